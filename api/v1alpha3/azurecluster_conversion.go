@@ -87,6 +87,8 @@ func (src *AzureCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint
 				}
 				dst.Spec.NetworkSpec.Subnets[i].SecurityGroup.SecurityRules = append(dst.Spec.NetworkSpec.Subnets[i].SecurityGroup.SecurityRules, restoredOutboundRules...)
 				dst.Spec.NetworkSpec.Subnets[i].NatGateway = restoredSubnet.NatGateway
+				dst.Spec.NetworkSpec.Subnets[i].PrivateEndpoints = restoredSubnet.PrivateEndpoints
+				dst.Spec.NetworkSpec.Subnets[i].SecurityGroup.DisabledDefaultRules = restoredSubnet.SecurityGroup.DisabledDefaultRules
 
 				break
 			}
@@ -94,10 +96,66 @@ func (src *AzureCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
+	dst.Status.ProvisioningDurations = restored.Status.ProvisioningDurations
 
 	// Restore list of virtual network peerings
 	dst.Spec.NetworkSpec.Vnet.Peerings = restored.Spec.NetworkSpec.Vnet.Peerings
 
+	// Restore DDoS Protection Plan of the virtual network
+	dst.Spec.NetworkSpec.Vnet.DDoSProtectionPlan = restored.Spec.NetworkSpec.Vnet.DDoSProtectionPlan
+
+	// Restore NSG flow logs settings
+	dst.Spec.NetworkSpec.FlowLogs = restored.Spec.NetworkSpec.FlowLogs
+
+	// Restore private link service of the API server
+	dst.Spec.NetworkSpec.APIServerPrivateLinkService = restored.Spec.NetworkSpec.APIServerPrivateLinkService
+
+	// Restore ExpressRoute gateway
+	dst.Spec.NetworkSpec.ExpressRouteGateway = restored.Spec.NetworkSpec.ExpressRouteGateway
+
+	// Restore network of the management cluster
+	dst.Spec.NetworkSpec.ManagementNetwork = restored.Spec.NetworkSpec.ManagementNetwork
+
+	// Restore Azure Firewall
+	dst.Spec.NetworkSpec.Firewall = restored.Spec.NetworkSpec.Firewall
+
+	// Restore retention of the public IPs
+	dst.Spec.NetworkSpec.RetainPublicIPsOnDelete = restored.Spec.NetworkSpec.RetainPublicIPsOnDelete
+	dst.Status.RetainedPublicIPs = restored.Status.RetainedPublicIPs
+
+	// Restore zones cordoned because of outages
+	dst.Status.CordonedZones = restored.Status.CordonedZones
+
+	// Restore existing private DNS zone
+	dst.Spec.NetworkSpec.PrivateDNSZone = restored.Spec.NetworkSpec.PrivateDNSZone
+
+	// Restore additional private DNS records
+	dst.Spec.NetworkSpec.PrivateDNSRecords = restored.Spec.NetworkSpec.PrivateDNSRecords
+	dst.Spec.NetworkSpec.APIServerPublicDNS = restored.Spec.NetworkSpec.APIServerPublicDNS
+
+	// Restore API version profile
+	dst.Spec.APIVersionProfile = restored.Spec.APIVersionProfile
+	dst.Spec.ForceDeleteVirtualMachines = restored.Spec.ForceDeleteVirtualMachines
+
+	// Restore API server access profile
+	dst.Spec.APIServerAccessProfile = restored.Spec.APIServerAccessProfile
+	dst.Spec.ConnectivityProfile = restored.Spec.ConnectivityProfile
+
+	// Restore baseline alerts
+	dst.Spec.Alerts = restored.Spec.Alerts
+
+	// Restore disk encryption sets
+	dst.Spec.DiskEncryptionSets = restored.Spec.DiskEncryptionSets
+	dst.Spec.ProximityPlacementGroup = restored.Spec.ProximityPlacementGroup
+
+	// Restore endpoints of a custom cloud
+	dst.Spec.AzureEnvironmentEndpoints = restored.Spec.AzureEnvironmentEndpoints
+
+	// Restore cluster-wide proxy configuration
+	dst.Spec.ProxyConfig = restored.Spec.ProxyConfig
+	dst.Spec.TrustedCAs = restored.Spec.TrustedCAs
+	dst.Spec.RegistryMirrors = restored.Spec.RegistryMirrors
+
 	return nil
 }
 
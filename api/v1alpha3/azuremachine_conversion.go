@@ -51,9 +51,40 @@ func (src *AzureMachine) ConvertTo(dstRaw conversion.Hub) error { // nolint
 		dst.Spec.Image.SharedGallery.SKU = restored.Spec.Image.SharedGallery.SKU
 	}
 
+	if restored.Spec.Image != nil && dst.Spec.Image != nil {
+		dst.Spec.Image.CommunityGallery = restored.Spec.Image.CommunityGallery
+		dst.Spec.Image.VHD = restored.Spec.Image.VHD
+	}
+
 	dst.Spec.SubnetName = restored.Spec.SubnetName
+	dst.Spec.UserData = restored.Spec.UserData
+	dst.Spec.ProximityPlacementGroupID = restored.Spec.ProximityPlacementGroupID
+	dst.Spec.CapacityReservationGroupID = restored.Spec.CapacityReservationGroupID
+	dst.Spec.LicenseType = restored.Spec.LicenseType
+	dst.Spec.PowerState = restored.Spec.PowerState
+	dst.Spec.ComputerNamePrefix = restored.Spec.ComputerNamePrefix
+
+	if restored.Spec.SpotVMOptions != nil && dst.Spec.SpotVMOptions != nil {
+		dst.Spec.SpotVMOptions.EvictionPolicy = restored.Spec.SpotVMOptions.EvictionPolicy
+	}
+
+	restoreSecurityProfile(dst.Spec.SecurityProfile, restored.Spec.SecurityProfile)
+	restoreManagedDiskParameters(dst.Spec.OSDisk.ManagedDisk, restored.Spec.OSDisk.ManagedDisk)
+	for i := range dst.Spec.DataDisks {
+		for _, disk := range restored.Spec.DataDisks {
+			if disk.NameSuffix == dst.Spec.DataDisks[i].NameSuffix {
+				dst.Spec.DataDisks[i].DeleteOption = disk.DeleteOption
+				dst.Spec.DataDisks[i].WriteAcceleratorEnabled = disk.WriteAcceleratorEnabled
+				dst.Spec.DataDisks[i].MaxShares = disk.MaxShares
+				restoreManagedDiskParameters(dst.Spec.DataDisks[i].ManagedDisk, disk.ManagedDisk)
+			}
+		}
+	}
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
+	dst.Status.CompletedPhase = restored.Status.CompletedPhase
+	dst.Status.PowerState = restored.Status.PowerState
+	dst.Status.Image = restored.Status.Image
 
 	return nil
 }
@@ -159,13 +190,67 @@ func Convert_v1beta1_OSDisk_To_v1alpha3_OSDisk(in *v1beta1.OSDisk, out *OSDisk,
 // Convert_v1alpha3_ManagedDisk_To_v1beta1_ManagedDiskParameters converts this ManagedDisk to the Hub version (v1beta1).
 func Convert_v1alpha3_ManagedDisk_To_v1beta1_ManagedDiskParameters(in *ManagedDisk, out *v1beta1.ManagedDiskParameters, s apiconversion.Scope) error { // nolint
 	out.StorageAccountType = in.StorageAccountType
-	out.DiskEncryptionSet = (*v1beta1.DiskEncryptionSetParameters)(in.DiskEncryptionSet)
+	if in.DiskEncryptionSet != nil {
+		out.DiskEncryptionSet = &v1beta1.DiskEncryptionSetParameters{ID: in.DiskEncryptionSet.ID}
+	}
 	return nil
 }
 
 // Convert_v1beta1_ManagedDiskParameters_To_v1alpha3_ManagedDisk converts from the Hub version (v1beta1) of the ManagedDiskParameters to this version.
 func Convert_v1beta1_ManagedDiskParameters_To_v1alpha3_ManagedDisk(in *v1beta1.ManagedDiskParameters, out *ManagedDisk, s apiconversion.Scope) error { // nolint
 	out.StorageAccountType = in.StorageAccountType
-	out.DiskEncryptionSet = (*DiskEncryptionSetParameters)(in.DiskEncryptionSet)
+	if in.DiskEncryptionSet != nil {
+		out.DiskEncryptionSet = &DiskEncryptionSetParameters{ID: in.DiskEncryptionSet.ID}
+	}
 	return nil
 }
+
+// Convert_v1beta1_SpotVMOptions_To_v1alpha3_SpotVMOptions converts from the Hub version (v1beta1) of the SpotVMOptions to this version.
+func Convert_v1beta1_SpotVMOptions_To_v1alpha3_SpotVMOptions(in *v1beta1.SpotVMOptions, out *SpotVMOptions, s apiconversion.Scope) error { // nolint
+	return autoConvert_v1beta1_SpotVMOptions_To_v1alpha3_SpotVMOptions(in, out, s)
+}
+
+// Convert_v1beta1_Image_To_v1alpha3_Image converts from the Hub version (v1beta1) of the Image to this version.
+func Convert_v1beta1_Image_To_v1alpha3_Image(in *v1beta1.Image, out *Image, s apiconversion.Scope) error { // nolint
+	return autoConvert_v1beta1_Image_To_v1alpha3_Image(in, out, s)
+}
+
+// Convert_v1beta1_DataDisk_To_v1alpha3_DataDisk converts from the Hub version (v1beta1) of the DataDisk to this version.
+func Convert_v1beta1_DataDisk_To_v1alpha3_DataDisk(in *v1beta1.DataDisk, out *DataDisk, s apiconversion.Scope) error { // nolint
+	return autoConvert_v1beta1_DataDisk_To_v1alpha3_DataDisk(in, out, s)
+}
+
+// Convert_v1beta1_DiskEncryptionSetParameters_To_v1alpha3_DiskEncryptionSetParameters converts from the Hub version (v1beta1) of the DiskEncryptionSetParameters to this version.
+func Convert_v1beta1_DiskEncryptionSetParameters_To_v1alpha3_DiskEncryptionSetParameters(in *v1beta1.DiskEncryptionSetParameters, out *DiskEncryptionSetParameters, s apiconversion.Scope) error { // nolint
+	return autoConvert_v1beta1_DiskEncryptionSetParameters_To_v1alpha3_DiskEncryptionSetParameters(in, out, s)
+}
+
+// restoreManagedDiskParameters restores the performance tier and the name of the disk encryption set of a managed disk,
+// which are not part of this version.
+func restoreManagedDiskParameters(dst, restored *v1beta1.ManagedDiskParameters) {
+	if dst == nil || restored == nil {
+		return
+	}
+	dst.PerformanceTier = restored.PerformanceTier
+	if restored.DiskEncryptionSet == nil || restored.DiskEncryptionSet.Name == "" {
+		return
+	}
+	if dst.DiskEncryptionSet == nil {
+		dst.DiskEncryptionSet = &v1beta1.DiskEncryptionSetParameters{}
+	}
+	dst.DiskEncryptionSet.Name = restored.DiskEncryptionSet.Name
+}
+
+// restoreSecurityProfile restores the trusted launch settings of a security profile, which are not part of this version.
+func restoreSecurityProfile(dst, restored *v1beta1.SecurityProfile) {
+	if dst == nil || restored == nil {
+		return
+	}
+	dst.SecurityType = restored.SecurityType
+	dst.UefiSettings = restored.UefiSettings
+}
+
+// Convert_v1beta1_SecurityProfile_To_v1alpha3_SecurityProfile converts from the Hub version (v1beta1) of the SecurityProfile to this version.
+func Convert_v1beta1_SecurityProfile_To_v1alpha3_SecurityProfile(in *v1beta1.SecurityProfile, out *SecurityProfile, s apiconversion.Scope) error { // nolint
+	return autoConvert_v1beta1_SecurityProfile_To_v1alpha3_SecurityProfile(in, out, s)
+}
@@ -51,7 +51,35 @@ func (src *AzureMachineTemplate) ConvertTo(dstRaw conversion.Hub) error { // nol
 		dst.Spec.Template.Spec.Image.SharedGallery.SKU = restored.Spec.Template.Spec.Image.SharedGallery.SKU
 	}
 
+	if restored.Spec.Template.Spec.Image != nil && dst.Spec.Template.Spec.Image != nil {
+		dst.Spec.Template.Spec.Image.CommunityGallery = restored.Spec.Template.Spec.Image.CommunityGallery
+		dst.Spec.Template.Spec.Image.VHD = restored.Spec.Template.Spec.Image.VHD
+	}
+
 	dst.Spec.Template.Spec.SubnetName = restored.Spec.Template.Spec.SubnetName
+	dst.Spec.Template.Spec.UserData = restored.Spec.Template.Spec.UserData
+	dst.Spec.Template.Spec.ProximityPlacementGroupID = restored.Spec.Template.Spec.ProximityPlacementGroupID
+	dst.Spec.Template.Spec.CapacityReservationGroupID = restored.Spec.Template.Spec.CapacityReservationGroupID
+	dst.Spec.Template.Spec.LicenseType = restored.Spec.Template.Spec.LicenseType
+	dst.Spec.Template.Spec.PowerState = restored.Spec.Template.Spec.PowerState
+	dst.Spec.Template.Spec.ComputerNamePrefix = restored.Spec.Template.Spec.ComputerNamePrefix
+
+	if restored.Spec.Template.Spec.SpotVMOptions != nil && dst.Spec.Template.Spec.SpotVMOptions != nil {
+		dst.Spec.Template.Spec.SpotVMOptions.EvictionPolicy = restored.Spec.Template.Spec.SpotVMOptions.EvictionPolicy
+	}
+
+	restoreSecurityProfile(dst.Spec.Template.Spec.SecurityProfile, restored.Spec.Template.Spec.SecurityProfile)
+	restoreManagedDiskParameters(dst.Spec.Template.Spec.OSDisk.ManagedDisk, restored.Spec.Template.Spec.OSDisk.ManagedDisk)
+	for i := range dst.Spec.Template.Spec.DataDisks {
+		for _, disk := range restored.Spec.Template.Spec.DataDisks {
+			if disk.NameSuffix == dst.Spec.Template.Spec.DataDisks[i].NameSuffix {
+				dst.Spec.Template.Spec.DataDisks[i].DeleteOption = disk.DeleteOption
+				dst.Spec.Template.Spec.DataDisks[i].WriteAcceleratorEnabled = disk.WriteAcceleratorEnabled
+				dst.Spec.Template.Spec.DataDisks[i].MaxShares = disk.MaxShares
+				restoreManagedDiskParameters(dst.Spec.Template.Spec.DataDisks[i].ManagedDisk, disk.ManagedDisk)
+			}
+		}
+	}
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
 
 	return nil
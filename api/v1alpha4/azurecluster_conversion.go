@@ -40,6 +40,83 @@ func (src *AzureCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	// Restore list of virtual network peerings
 	dst.Spec.NetworkSpec.Vnet.Peerings = restored.Spec.NetworkSpec.Vnet.Peerings
 
+	// Restore DDoS Protection Plan of the virtual network
+	dst.Spec.NetworkSpec.Vnet.DDoSProtectionPlan = restored.Spec.NetworkSpec.Vnet.DDoSProtectionPlan
+
+	// Restore NSG flow logs settings
+	dst.Spec.NetworkSpec.FlowLogs = restored.Spec.NetworkSpec.FlowLogs
+
+	// Restore private link service of the API server
+	dst.Spec.NetworkSpec.APIServerPrivateLinkService = restored.Spec.NetworkSpec.APIServerPrivateLinkService
+
+	// Restore ExpressRoute gateway
+	dst.Spec.NetworkSpec.ExpressRouteGateway = restored.Spec.NetworkSpec.ExpressRouteGateway
+
+	// Restore network of the management cluster
+	dst.Spec.NetworkSpec.ManagementNetwork = restored.Spec.NetworkSpec.ManagementNetwork
+
+	// Restore Azure Firewall
+	dst.Spec.NetworkSpec.Firewall = restored.Spec.NetworkSpec.Firewall
+
+	// Restore retention of the public IPs
+	dst.Spec.NetworkSpec.RetainPublicIPsOnDelete = restored.Spec.NetworkSpec.RetainPublicIPsOnDelete
+	dst.Status.RetainedPublicIPs = restored.Status.RetainedPublicIPs
+
+	// Restore zones cordoned because of outages
+	dst.Status.CordonedZones = restored.Status.CordonedZones
+
+	// Restore existing private DNS zone
+	dst.Spec.NetworkSpec.PrivateDNSZone = restored.Spec.NetworkSpec.PrivateDNSZone
+
+	// Restore additional private DNS records
+	dst.Spec.NetworkSpec.PrivateDNSRecords = restored.Spec.NetworkSpec.PrivateDNSRecords
+	dst.Spec.NetworkSpec.APIServerPublicDNS = restored.Spec.NetworkSpec.APIServerPublicDNS
+
+	// Restore API server access profile
+	dst.Spec.APIServerAccessProfile = restored.Spec.APIServerAccessProfile
+	dst.Spec.ConnectivityProfile = restored.Spec.ConnectivityProfile
+
+	// Restore baseline alerts
+	dst.Spec.Alerts = restored.Spec.Alerts
+
+	// Restore disk encryption sets
+	dst.Spec.DiskEncryptionSets = restored.Spec.DiskEncryptionSets
+	dst.Spec.ProximityPlacementGroup = restored.Spec.ProximityPlacementGroup
+
+	// Restore endpoints of a custom cloud
+	dst.Spec.AzureEnvironmentEndpoints = restored.Spec.AzureEnvironmentEndpoints
+
+	// Restore cluster-wide proxy configuration
+	dst.Spec.ProxyConfig = restored.Spec.ProxyConfig
+	dst.Spec.TrustedCAs = restored.Spec.TrustedCAs
+	dst.Spec.RegistryMirrors = restored.Spec.RegistryMirrors
+
+	// Restore provisioning durations
+	dst.Status.ProvisioningDurations = restored.Status.ProvisioningDurations
+
+	// Restore API version profile
+	dst.Spec.APIVersionProfile = restored.Spec.APIVersionProfile
+	dst.Spec.ForceDeleteVirtualMachines = restored.Spec.ForceDeleteVirtualMachines
+
+	// Restore private endpoints and disabled default security rules of the subnets
+	for _, restoredSubnet := range restored.Spec.NetworkSpec.Subnets {
+		for i, dstSubnet := range dst.Spec.NetworkSpec.Subnets {
+			if dstSubnet.Name == restoredSubnet.Name {
+				dst.Spec.NetworkSpec.Subnets[i].PrivateEndpoints = restoredSubnet.PrivateEndpoints
+				dst.Spec.NetworkSpec.Subnets[i].SecurityGroup.DisabledDefaultRules = restoredSubnet.SecurityGroup.DisabledDefaultRules
+				break
+			}
+		}
+	}
+	if restored.Spec.BastionSpec.AzureBastion != nil && dst.Spec.BastionSpec.AzureBastion != nil {
+		dst.Spec.BastionSpec.AzureBastion.Subnet.PrivateEndpoints = restored.Spec.BastionSpec.AzureBastion.Subnet.PrivateEndpoints
+		dst.Spec.BastionSpec.AzureBastion.Subnet.SecurityGroup.DisabledDefaultRules = restored.Spec.BastionSpec.AzureBastion.Subnet.SecurityGroup.DisabledDefaultRules
+		dst.Spec.BastionSpec.AzureBastion.Sku = restored.Spec.BastionSpec.AzureBastion.Sku
+		dst.Spec.BastionSpec.AzureBastion.ScaleUnits = restored.Spec.BastionSpec.AzureBastion.ScaleUnits
+		dst.Spec.BastionSpec.AzureBastion.EnableTunneling = restored.Spec.BastionSpec.AzureBastion.EnableTunneling
+		dst.Spec.BastionSpec.AzureBastion.EnableIPConnect = restored.Spec.BastionSpec.AzureBastion.EnableIPConnect
+	}
+
 	return nil
 }
 
@@ -70,11 +147,41 @@ func (dst *AzureClusterList) ConvertFrom(srcRaw conversion.Hub) error { // nolin
 	return Convert_v1beta1_AzureClusterList_To_v1alpha4_AzureClusterList(src, dst, nil)
 }
 
+// Convert_v1beta1_AzureClusterSpec_To_v1alpha4_AzureClusterSpec.
+func Convert_v1beta1_AzureClusterSpec_To_v1alpha4_AzureClusterSpec(in *infrav1beta1.AzureClusterSpec, out *AzureClusterSpec, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_AzureClusterSpec_To_v1alpha4_AzureClusterSpec(in, out, s)
+}
+
+// Convert_v1beta1_AzureClusterStatus_To_v1alpha4_AzureClusterStatus.
+func Convert_v1beta1_AzureClusterStatus_To_v1alpha4_AzureClusterStatus(in *infrav1beta1.AzureClusterStatus, out *AzureClusterStatus, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_AzureClusterStatus_To_v1alpha4_AzureClusterStatus(in, out, s)
+}
+
+// Convert_v1beta1_AzureBastion_To_v1alpha4_AzureBastion.
+func Convert_v1beta1_AzureBastion_To_v1alpha4_AzureBastion(in *infrav1beta1.AzureBastion, out *AzureBastion, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_AzureBastion_To_v1alpha4_AzureBastion(in, out, s)
+}
+
 // Convert_v1beta1_VnetSpec_To_v1alpha4_VnetSpec.
 func Convert_v1beta1_VnetSpec_To_v1alpha4_VnetSpec(in *infrav1beta1.VnetSpec, out *VnetSpec, s apiconversion.Scope) error { //nolint
 	return autoConvert_v1beta1_VnetSpec_To_v1alpha4_VnetSpec(in, out, s)
 }
 
+// Convert_v1beta1_NetworkSpec_To_v1alpha4_NetworkSpec.
+func Convert_v1beta1_NetworkSpec_To_v1alpha4_NetworkSpec(in *infrav1beta1.NetworkSpec, out *NetworkSpec, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_NetworkSpec_To_v1alpha4_NetworkSpec(in, out, s)
+}
+
+// Convert_v1beta1_SubnetSpec_To_v1alpha4_SubnetSpec.
+func Convert_v1beta1_SubnetSpec_To_v1alpha4_SubnetSpec(in *infrav1beta1.SubnetSpec, out *SubnetSpec, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_SubnetSpec_To_v1alpha4_SubnetSpec(in, out, s)
+}
+
+// Convert_v1beta1_SecurityGroup_To_v1alpha4_SecurityGroup.
+func Convert_v1beta1_SecurityGroup_To_v1alpha4_SecurityGroup(in *infrav1beta1.SecurityGroup, out *SecurityGroup, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_SecurityGroup_To_v1alpha4_SecurityGroup(in, out, s)
+}
+
 // Convert_v1alpha4_VnetSpec_To_v1beta1_VnetSpec is an autogenerated conversion function.
 func Convert_v1alpha4_VnetSpec_To_v1beta1_VnetSpec(in *VnetSpec, out *infrav1beta1.VnetSpec, s apiconversion.Scope) error {
 	return autoConvert_v1alpha4_VnetSpec_To_v1beta1_VnetSpec(in, out, s)
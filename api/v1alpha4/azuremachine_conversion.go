@@ -17,7 +17,9 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
@@ -25,13 +27,64 @@ import (
 func (src *AzureMachine) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	dst := dstRaw.(*v1beta1.AzureMachine)
 
-	return Convert_v1alpha4_AzureMachine_To_v1beta1_AzureMachine(src, dst, nil)
+	if err := Convert_v1alpha4_AzureMachine_To_v1beta1_AzureMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data from annotations
+	restored := &v1beta1.AzureMachine{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.UserData = restored.Spec.UserData
+	dst.Spec.ProximityPlacementGroupID = restored.Spec.ProximityPlacementGroupID
+	dst.Spec.CapacityReservationGroupID = restored.Spec.CapacityReservationGroupID
+	dst.Spec.LicenseType = restored.Spec.LicenseType
+	dst.Spec.PowerState = restored.Spec.PowerState
+	dst.Spec.ComputerNamePrefix = restored.Spec.ComputerNamePrefix
+
+	if restored.Spec.SpotVMOptions != nil && dst.Spec.SpotVMOptions != nil {
+		dst.Spec.SpotVMOptions.EvictionPolicy = restored.Spec.SpotVMOptions.EvictionPolicy
+	}
+
+	if restored.Spec.Image != nil && dst.Spec.Image != nil {
+		dst.Spec.Image.CommunityGallery = restored.Spec.Image.CommunityGallery
+		dst.Spec.Image.VHD = restored.Spec.Image.VHD
+	}
+
+	restoreSecurityProfile(dst.Spec.SecurityProfile, restored.Spec.SecurityProfile)
+	restoreManagedDiskParameters(dst.Spec.OSDisk.ManagedDisk, restored.Spec.OSDisk.ManagedDisk)
+	for i := range dst.Spec.DataDisks {
+		for _, disk := range restored.Spec.DataDisks {
+			if disk.NameSuffix == dst.Spec.DataDisks[i].NameSuffix {
+				dst.Spec.DataDisks[i].DeleteOption = disk.DeleteOption
+				dst.Spec.DataDisks[i].WriteAcceleratorEnabled = disk.WriteAcceleratorEnabled
+				dst.Spec.DataDisks[i].MaxShares = disk.MaxShares
+				restoreManagedDiskParameters(dst.Spec.DataDisks[i].ManagedDisk, disk.ManagedDisk)
+			}
+		}
+	}
+	dst.Status.CompletedPhase = restored.Status.CompletedPhase
+	dst.Status.PowerState = restored.Status.PowerState
+	dst.Status.Image = restored.Status.Image
+
+	return nil
 }
 
 // ConvertFrom converts from the Hub version (v1beta1) to this version.
 func (dst *AzureMachine) ConvertFrom(srcRaw conversion.Hub) error { // nolint
 	src := srcRaw.(*v1beta1.AzureMachine)
-	return Convert_v1beta1_AzureMachine_To_v1alpha4_AzureMachine(src, dst, nil)
+	if err := Convert_v1beta1_AzureMachine_To_v1alpha4_AzureMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion.
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // ConvertTo converts this AzureMachineList to the Hub version (v1beta1).
@@ -45,3 +98,68 @@ func (dst *AzureMachineList) ConvertFrom(srcRaw conversion.Hub) error { // nolin
 	src := srcRaw.(*v1beta1.AzureMachineList)
 	return Convert_v1beta1_AzureMachineList_To_v1alpha4_AzureMachineList(src, dst, nil)
 }
+
+// Convert_v1beta1_AzureMachineSpec_To_v1alpha4_AzureMachineSpec is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachineSpec_To_v1alpha4_AzureMachineSpec(in *v1beta1.AzureMachineSpec, out *AzureMachineSpec, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_AzureMachineSpec_To_v1alpha4_AzureMachineSpec(in, out, s)
+}
+
+// Convert_v1beta1_AzureMachineStatus_To_v1alpha4_AzureMachineStatus is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachineStatus_To_v1alpha4_AzureMachineStatus(in *v1beta1.AzureMachineStatus, out *AzureMachineStatus, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_AzureMachineStatus_To_v1alpha4_AzureMachineStatus(in, out, s)
+}
+
+// Convert_v1beta1_SpotVMOptions_To_v1alpha4_SpotVMOptions is an autogenerated conversion function.
+func Convert_v1beta1_SpotVMOptions_To_v1alpha4_SpotVMOptions(in *v1beta1.SpotVMOptions, out *SpotVMOptions, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_SpotVMOptions_To_v1alpha4_SpotVMOptions(in, out, s)
+}
+
+// Convert_v1beta1_Image_To_v1alpha4_Image converts from the Hub version (v1beta1) of the Image to this version.
+func Convert_v1beta1_Image_To_v1alpha4_Image(in *v1beta1.Image, out *Image, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_Image_To_v1alpha4_Image(in, out, s)
+}
+
+// Convert_v1beta1_DataDisk_To_v1alpha4_DataDisk is an autogenerated conversion function.
+func Convert_v1beta1_DataDisk_To_v1alpha4_DataDisk(in *v1beta1.DataDisk, out *DataDisk, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_DataDisk_To_v1alpha4_DataDisk(in, out, s)
+}
+
+// Convert_v1beta1_DiskEncryptionSetParameters_To_v1alpha4_DiskEncryptionSetParameters converts from the Hub version (v1beta1) of the DiskEncryptionSetParameters to this version.
+func Convert_v1beta1_DiskEncryptionSetParameters_To_v1alpha4_DiskEncryptionSetParameters(in *v1beta1.DiskEncryptionSetParameters, out *DiskEncryptionSetParameters, s apiconversion.Scope) error { // nolint
+	return autoConvert_v1beta1_DiskEncryptionSetParameters_To_v1alpha4_DiskEncryptionSetParameters(in, out, s)
+}
+
+// Convert_v1beta1_ManagedDiskParameters_To_v1alpha4_ManagedDiskParameters converts from the Hub version (v1beta1) of the ManagedDiskParameters to this version.
+func Convert_v1beta1_ManagedDiskParameters_To_v1alpha4_ManagedDiskParameters(in *v1beta1.ManagedDiskParameters, out *ManagedDiskParameters, s apiconversion.Scope) error { // nolint
+	return autoConvert_v1beta1_ManagedDiskParameters_To_v1alpha4_ManagedDiskParameters(in, out, s)
+}
+
+// restoreManagedDiskParameters restores the performance tier and the name of the disk encryption set of a managed disk,
+// which are not part of this version.
+func restoreManagedDiskParameters(dst, restored *v1beta1.ManagedDiskParameters) {
+	if dst == nil || restored == nil {
+		return
+	}
+	dst.PerformanceTier = restored.PerformanceTier
+	if restored.DiskEncryptionSet == nil || restored.DiskEncryptionSet.Name == "" {
+		return
+	}
+	if dst.DiskEncryptionSet == nil {
+		dst.DiskEncryptionSet = &v1beta1.DiskEncryptionSetParameters{}
+	}
+	dst.DiskEncryptionSet.Name = restored.DiskEncryptionSet.Name
+}
+
+// restoreSecurityProfile restores the trusted launch settings of a security profile, which are not part of this version.
+func restoreSecurityProfile(dst, restored *v1beta1.SecurityProfile) {
+	if dst == nil || restored == nil {
+		return
+	}
+	dst.SecurityType = restored.SecurityType
+	dst.UefiSettings = restored.UefiSettings
+}
+
+// Convert_v1beta1_SecurityProfile_To_v1alpha4_SecurityProfile converts from the Hub version (v1beta1) of the SecurityProfile to this version.
+func Convert_v1beta1_SecurityProfile_To_v1alpha4_SecurityProfile(in *v1beta1.SecurityProfile, out *SecurityProfile, s apiconversion.Scope) error { // nolint
+	return autoConvert_v1beta1_SecurityProfile_To_v1alpha4_SecurityProfile(in, out, s)
+}
@@ -35,28 +35,60 @@ const (
 	DefaultAzureBastionSubnetCIDR = "10.255.255.224/27"
 	// DefaultAzureBastionSubnetName is the default Subnet Name for AzureBastion.
 	DefaultAzureBastionSubnetName = "AzureBastionSubnet"
+	// DefaultExpressRouteGatewaySubnetCIDR is the default Subnet CIDR for the ExpressRoute gateway.
+	DefaultExpressRouteGatewaySubnetCIDR = "10.255.255.192/27"
+	// ExpressRouteGatewaySubnetName is the name Azure requires for the subnet of a virtual network gateway.
+	ExpressRouteGatewaySubnetName = "GatewaySubnet"
+	// DefaultAzureFirewallSubnetCIDR is the default Subnet CIDR for Azure Firewall.
+	DefaultAzureFirewallSubnetCIDR = "10.255.255.128/26"
+	// AzureFirewallSubnetName is the name Azure requires for the subnet of Azure Firewall.
+	AzureFirewallSubnetName = "AzureFirewallSubnet"
 	// DefaultInternalLBIPAddress is the default internal load balancer ip address.
 	DefaultInternalLBIPAddress = "10.0.0.100"
 	// DefaultOutboundRuleIdleTimeoutInMinutes is the default for IdleTimeoutInMinutes for the load balancer.
 	DefaultOutboundRuleIdleTimeoutInMinutes = 4
 	// DefaultAzureCloud is the public cloud that will be used by most users.
 	DefaultAzureCloud = "AzurePublicCloud"
+	// DefaultNetworkWatcherResourceGroup is the resource group of the Network Watchers Azure enables automatically.
+	DefaultNetworkWatcherResourceGroup = "NetworkWatcherRG"
+	// DefaultTrafficAnalyticsIntervalInMinutes is the default processing interval of Traffic Analytics.
+	DefaultTrafficAnalyticsIntervalInMinutes = 60
+	// DefaultPrivateLinkServiceNATIPCount is the default number of NAT IP addresses of a private link service.
+	DefaultPrivateLinkServiceNATIPCount = 1
+	// DefaultPrivateDNSZoneGroupName is the default name of the private DNS zone group of a private endpoint.
+	DefaultPrivateDNSZoneGroupName = "default"
+	// DefaultPublicDNSRecordTTL is the default time to live in seconds of the public DNS record of the API server.
+	DefaultPublicDNSRecordTTL = 300
+	// DefaultAlertSeverity is the default severity of the baseline alerts of a cluster, a warning.
+	DefaultAlertSeverity = 2
 )
 
 func (c *AzureCluster) setDefaults() {
 	c.setResourceGroupDefault()
 	c.setAzureEnvironmentDefault()
+	c.setAPIVersionProfileDefaults()
 	c.setNetworkSpecDefaults()
+	c.setAlertsDefaults()
+	c.setConnectivityProfileDefaults()
+	c.setDiskEncryptionSetsDefaults()
+	c.setProximityPlacementGroupDefaults()
 }
 
 func (c *AzureCluster) setNetworkSpecDefaults() {
 	c.setVnetDefaults()
 	c.setBastionDefaults()
+	c.setExpressRouteGatewayDefaults()
+	c.setFirewallDefaults()
 	c.setSubnetDefaults()
 	c.setVnetPeeringDefaults()
+	c.setManagementNetworkDefaults()
 	c.setAPIServerLBDefaults()
+	c.setAPIServerPrivateLinkServiceDefaults()
+	c.setAPIServerPublicDNSDefaults()
 	c.setNodeOutboundLBDefaults()
 	c.setControlPlaneOutboundLBDefaults()
+	c.setFlowLogsDefaults()
+	c.setPrivateEndpointDefaults()
 }
 
 func (c *AzureCluster) setResourceGroupDefault() {
@@ -71,6 +103,12 @@ func (c *AzureCluster) setAzureEnvironmentDefault() {
 	}
 }
 
+func (c *AzureCluster) setAPIVersionProfileDefaults() {
+	if c.Spec.APIVersionProfile != nil && c.Spec.APIVersionProfile.Name == "" {
+		c.Spec.APIVersionProfile.Name = APIVersionProfileLatest
+	}
+}
+
 func (c *AzureCluster) setVnetDefaults() {
 	if c.Spec.NetworkSpec.Vnet.ResourceGroup == "" {
 		c.Spec.NetworkSpec.Vnet.ResourceGroup = c.Spec.ResourceGroup
@@ -148,6 +186,37 @@ func (c *AzureCluster) setSubnetDefaults() {
 	}
 }
 
+func (c *AzureCluster) setFlowLogsDefaults() {
+	flowLogs := c.Spec.NetworkSpec.FlowLogs
+	if flowLogs == nil {
+		return
+	}
+	if flowLogs.NetworkWatcher == nil {
+		flowLogs.NetworkWatcher = &NetworkWatcherSpec{
+			Name:          generateNetworkWatcherName(c.Spec.Location),
+			ResourceGroup: DefaultNetworkWatcherResourceGroup,
+		}
+	}
+	if analytics := flowLogs.TrafficAnalytics; analytics != nil {
+		if analytics.WorkspaceRegion == "" {
+			analytics.WorkspaceRegion = c.Spec.Location
+		}
+		if analytics.IntervalInMinutes == nil {
+			analytics.IntervalInMinutes = pointer.Int32Ptr(DefaultTrafficAnalyticsIntervalInMinutes)
+		}
+	}
+}
+
+func (c *AzureCluster) setPrivateEndpointDefaults() {
+	for _, subnet := range c.Spec.NetworkSpec.Subnets {
+		for _, privateEndpoint := range subnet.PrivateEndpoints {
+			if group := privateEndpoint.PrivateDNSZoneGroup; group != nil && group.Name == "" {
+				group.Name = DefaultPrivateDNSZoneGroupName
+			}
+		}
+	}
+}
+
 func (c *AzureCluster) setVnetPeeringDefaults() {
 	for i, peering := range c.Spec.NetworkSpec.Vnet.Peerings {
 		if peering.ResourceGroup == "" {
@@ -156,11 +225,40 @@ func (c *AzureCluster) setVnetPeeringDefaults() {
 	}
 }
 
+func (c *AzureCluster) setManagementNetworkDefaults() {
+	if network := c.Spec.NetworkSpec.ManagementNetwork; network != nil && network.Vnet != nil && network.Vnet.ResourceGroup == "" {
+		network.Vnet.ResourceGroup = c.Spec.ResourceGroup
+	}
+}
+
 func setSecurityRuleDefaults(sg *SecurityGroup) {
+	usedPriorities := map[SecurityRuleDirection]map[int32]bool{
+		SecurityRuleDirectionInbound:  {},
+		SecurityRuleDirectionOutbound: {},
+	}
 	for i := range sg.SecurityRules {
 		if sg.SecurityRules[i].Direction == "" {
 			sg.SecurityRules[i].Direction = SecurityRuleDirectionInbound
 		}
+		// Rules of an unknown direction are left to the validation, which rejects them.
+		if sg.SecurityRules[i].Priority != 0 && usedPriorities[sg.SecurityRules[i].Direction] != nil {
+			usedPriorities[sg.SecurityRules[i].Direction][sg.SecurityRules[i].Priority] = true
+		}
+	}
+
+	// Rules without a priority get the lowest free one of their direction, skipping the band reserved to the default
+	// security rules so that they never conflict with them.
+	for i := range sg.SecurityRules {
+		rule := &sg.SecurityRules[i]
+		if rule.Priority != 0 || usedPriorities[rule.Direction] == nil {
+			continue
+		}
+		priority := int32(minRulePriority)
+		for usedPriorities[rule.Direction][priority] || (priority >= DefaultSecurityRulePriorityMin && priority <= DefaultSecurityRulePriorityMax) {
+			priority++
+		}
+		rule.Priority = priority
+		usedPriorities[rule.Direction][priority] = true
 	}
 }
 
@@ -205,6 +303,73 @@ func (c *AzureCluster) setAPIServerLBDefaults() {
 	}
 }
 
+func (c *AzureCluster) setAPIServerPrivateLinkServiceDefaults() {
+	pls := c.Spec.NetworkSpec.APIServerPrivateLinkService
+	if pls == nil {
+		return
+	}
+	if pls.Name == "" {
+		pls.Name = generatePrivateLinkServiceName(c.Spec.NetworkSpec.APIServerLB.Name)
+	}
+	if pls.NATIPCount == nil {
+		pls.NATIPCount = pointer.Int32Ptr(DefaultPrivateLinkServiceNATIPCount)
+	}
+}
+
+func (c *AzureCluster) setAPIServerPublicDNSDefaults() {
+	record := c.Spec.NetworkSpec.APIServerPublicDNS
+	if record == nil {
+		return
+	}
+	if record.Type == "" {
+		record.Type = PublicDNSRecordTypeCNAME
+	}
+	if record.TTL == nil {
+		record.TTL = pointer.Int64Ptr(DefaultPublicDNSRecordTTL)
+	}
+}
+
+func (c *AzureCluster) setAlertsDefaults() {
+	alerts := c.Spec.Alerts
+	if alerts == nil {
+		return
+	}
+	if len(alerts.Rules) == 0 {
+		alerts.Rules = []AlertRuleType{AlertRuleVMAvailability, AlertRuleLoadBalancerHealthProbe, AlertRuleNATGatewaySNAT}
+	}
+	if alerts.Severity == nil {
+		alerts.Severity = pointer.Int32Ptr(DefaultAlertSeverity)
+	}
+}
+
+func (c *AzureCluster) setConnectivityProfileDefaults() {
+	profile := c.Spec.ConnectivityProfile
+	if profile == nil {
+		return
+	}
+	for _, ports := range [][]ConnectivityPort{profile.Ingress, profile.Egress} {
+		for i := range ports {
+			if ports[i].Protocol == "" {
+				ports[i].Protocol = SecurityGroupProtocolTCP
+			}
+		}
+	}
+}
+
+func (c *AzureCluster) setDiskEncryptionSetsDefaults() {
+	for i, diskEncryptionSet := range c.Spec.DiskEncryptionSets {
+		if diskEncryptionSet.EncryptionType == "" {
+			c.Spec.DiskEncryptionSets[i].EncryptionType = DiskEncryptionSetTypeCustomerKey
+		}
+	}
+}
+
+func (c *AzureCluster) setProximityPlacementGroupDefaults() {
+	if c.Spec.ProximityPlacementGroup != nil && c.Spec.ProximityPlacementGroup.Name == "" {
+		c.Spec.ProximityPlacementGroup.Name = generateProximityPlacementGroupName(c.ObjectMeta.Name)
+	}
+}
+
 func (c *AzureCluster) setNodeOutboundLBDefaults() {
 	if c.Spec.NetworkSpec.NodeOutboundLB == nil {
 		if c.Spec.NetworkSpec.APIServerLB.Type == Internal {
@@ -323,6 +488,49 @@ func (c *AzureCluster) setBastionDefaults() {
 				c.Spec.BastionSpec.AzureBastion.PublicIP.Name = generateAzureBastionPublicIPName(c.ObjectMeta.Name)
 			}
 		}
+		if c.Spec.BastionSpec.AzureBastion.Sku == "" {
+			c.Spec.BastionSpec.AzureBastion.Sku = BastionHostSkuBasic
+		}
+	}
+}
+
+func (c *AzureCluster) setExpressRouteGatewayDefaults() {
+	gateway := c.Spec.NetworkSpec.ExpressRouteGateway
+	// An existing gateway is not created, so it doesn't need a subnet nor a public IP.
+	if gateway == nil || gateway.Name == "" {
+		return
+	}
+	if gateway.Sku == "" {
+		gateway.Sku = ExpressRouteGatewaySkuStandard
+	}
+	if gateway.Subnet.Name == "" {
+		gateway.Subnet.Name = ExpressRouteGatewaySubnetName
+	}
+	if len(gateway.Subnet.CIDRBlocks) == 0 {
+		gateway.Subnet.CIDRBlocks = []string{DefaultExpressRouteGatewaySubnetCIDR}
+	}
+	if gateway.PublicIP.Name == "" {
+		gateway.PublicIP.Name = generateExpressRouteGatewayPublicIPName(c.ObjectMeta.Name)
+	}
+}
+
+func (c *AzureCluster) setFirewallDefaults() {
+	firewall := c.Spec.NetworkSpec.Firewall
+	// An existing firewall is not created, so it doesn't need a subnet nor a public IP.
+	if firewall == nil || firewall.Name == "" {
+		return
+	}
+	if firewall.SkuTier == "" {
+		firewall.SkuTier = AzureFirewallSkuTierStandard
+	}
+	if firewall.Subnet.Name == "" {
+		firewall.Subnet.Name = AzureFirewallSubnetName
+	}
+	if len(firewall.Subnet.CIDRBlocks) == 0 {
+		firewall.Subnet.CIDRBlocks = []string{DefaultAzureFirewallSubnetCIDR}
+	}
+	if firewall.PublicIP.Name == "" {
+		firewall.PublicIP.Name = generateAzureFirewallPublicIPName(c.ObjectMeta.Name)
 	}
 }
 
@@ -341,6 +549,11 @@ func generateNodeSubnetName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "node-subnet")
 }
 
+// generateNetworkWatcherName generates the name of the Network Watcher Azure enables for a location.
+func generateNetworkWatcherName(location string) string {
+	return fmt.Sprintf("NetworkWatcher_%s", location)
+}
+
 // generateAzureBastionName generates an azure bastion name.
 func generateAzureBastionName(clusterName string) string {
 	return fmt.Sprintf("%s-azure-bastion", clusterName)
@@ -351,6 +564,16 @@ func generateAzureBastionPublicIPName(clusterName string) string {
 	return fmt.Sprintf("%s-azure-bastion-pip", clusterName)
 }
 
+// generateExpressRouteGatewayPublicIPName generates an ExpressRoute gateway public ip name.
+func generateExpressRouteGatewayPublicIPName(clusterName string) string {
+	return fmt.Sprintf("%s-ergw-pip", clusterName)
+}
+
+// generateAzureFirewallPublicIPName generates an Azure Firewall public ip name.
+func generateAzureFirewallPublicIPName(clusterName string) string {
+	return fmt.Sprintf("%s-azfw-pip", clusterName)
+}
+
 // generateControlPlaneSecurityGroupName generates a control plane security group name, based on the cluster name.
 func generateControlPlaneSecurityGroupName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "controlplane-nsg")
@@ -391,6 +614,11 @@ func generateFrontendIPConfigName(lbName string) string {
 	return fmt.Sprintf("%s-%s", lbName, "frontEnd")
 }
 
+// generatePrivateLinkServiceName generates the name of the private link service of a load balancer.
+func generatePrivateLinkServiceName(lbName string) string {
+	return fmt.Sprintf("%s-pls", lbName)
+}
+
 // generateNodeOutboundIPName generates a public IP name, based on the cluster name.
 func generateNodeOutboundIPName(clusterName string) string {
 	return fmt.Sprintf("pip-%s-node-outbound", clusterName)
@@ -406,6 +634,11 @@ func generateNatGatewayIPName(clusterName, subnetName string) string {
 	return fmt.Sprintf("pip-%s-%s-natgw", clusterName, subnetName)
 }
 
+// generateProximityPlacementGroupName generates a proximity placement group name, based on the cluster name.
+func generateProximityPlacementGroupName(clusterName string) string {
+	return fmt.Sprintf("%s-ppg", clusterName)
+}
+
 // withIndex appends the index as suffix to a generated name.
 func withIndex(name string, n int) string {
 	return fmt.Sprintf("%s-%d", name, n)
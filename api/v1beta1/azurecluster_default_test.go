@@ -18,12 +18,14 @@ package v1beta1
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/Azure/go-autorest/autorest/to"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 func TestResourceGroupDefault(t *testing.T) {
@@ -905,6 +907,58 @@ func TestAzureEnviromentDefault(t *testing.T) {
 	}
 }
 
+func TestAPIVersionProfileDefaults(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no profile": {
+			cluster: &AzureCluster{Spec: AzureClusterSpec{}},
+			output:  &AzureCluster{Spec: AzureClusterSpec{}},
+		},
+		"profile without name": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					APIVersionProfile: &APIVersionProfile{Overrides: map[string]string{"Microsoft.Network": "2018-11-01"}},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					APIVersionProfile: &APIVersionProfile{
+						Name:      APIVersionProfileLatest,
+						Overrides: map[string]string{"Microsoft.Network": "2018-11-01"},
+					},
+				},
+			},
+		},
+		"hybrid profile": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					APIVersionProfile: &APIVersionProfile{Name: APIVersionProfileHybrid20190301},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					APIVersionProfile: &APIVersionProfile{Name: APIVersionProfileHybrid20190301},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setAPIVersionProfileDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
 func TestNodeOutboundLBDefaults(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -1563,6 +1617,7 @@ func TestBastionDefault(t *testing.T) {
 							PublicIP: PublicIPSpec{
 								Name: "foo-azure-bastion-pip",
 							},
+							Sku: BastionHostSkuBasic,
 						},
 					},
 				},
@@ -1596,6 +1651,7 @@ func TestBastionDefault(t *testing.T) {
 							PublicIP: PublicIPSpec{
 								Name: "foo-azure-bastion-pip",
 							},
+							Sku: BastionHostSkuBasic,
 						},
 					},
 				},
@@ -1629,6 +1685,7 @@ func TestBastionDefault(t *testing.T) {
 							PublicIP: PublicIPSpec{
 								Name: "foo-azure-bastion-pip",
 							},
+							Sku: BastionHostSkuBasic,
 						},
 					},
 				},
@@ -1665,6 +1722,7 @@ func TestBastionDefault(t *testing.T) {
 							PublicIP: PublicIPSpec{
 								Name: "foo-azure-bastion-pip",
 							},
+							Sku: BastionHostSkuBasic,
 						},
 					},
 				},
@@ -1700,6 +1758,7 @@ func TestBastionDefault(t *testing.T) {
 							PublicIP: PublicIPSpec{
 								Name: "my-ultrafancy-pip-name",
 							},
+							Sku: BastionHostSkuBasic,
 						},
 					},
 				},
@@ -1720,3 +1779,902 @@ func TestBastionDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestExpressRouteGatewayDefaults(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no gateway": {
+			cluster: &AzureCluster{Spec: AzureClusterSpec{}},
+			output:  &AzureCluster{Spec: AzureClusterSpec{}},
+		},
+		"existing gateway": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						ExpressRouteGateway: &ExpressRouteGateway{ID: "/subscriptions/123/resourceGroups/hub/providers/Microsoft.Network/virtualNetworkGateways/my-ergw"},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						ExpressRouteGateway: &ExpressRouteGateway{ID: "/subscriptions/123/resourceGroups/hub/providers/Microsoft.Network/virtualNetworkGateways/my-ergw"},
+					},
+				},
+			},
+		},
+		"created gateway": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						ExpressRouteGateway: &ExpressRouteGateway{Name: "my-ergw"},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						ExpressRouteGateway: &ExpressRouteGateway{
+							Name: "my-ergw",
+							Sku:  ExpressRouteGatewaySkuStandard,
+							Subnet: SubnetSpec{
+								Name:       ExpressRouteGatewaySubnetName,
+								CIDRBlocks: []string{DefaultExpressRouteGatewaySubnetCIDR},
+							},
+							PublicIP: PublicIPSpec{Name: "foo-ergw-pip"},
+						},
+					},
+				},
+			},
+		},
+		"created gateway with custom settings": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						ExpressRouteGateway: &ExpressRouteGateway{
+							Name: "my-ergw",
+							Sku:  ExpressRouteGatewaySkuErGw1AZ,
+							Subnet: SubnetSpec{
+								Name:       ExpressRouteGatewaySubnetName,
+								CIDRBlocks: []string{"10.0.255.0/27"},
+							},
+							PublicIP: PublicIPSpec{Name: "my-ergw-pip"},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						ExpressRouteGateway: &ExpressRouteGateway{
+							Name: "my-ergw",
+							Sku:  ExpressRouteGatewaySkuErGw1AZ,
+							Subnet: SubnetSpec{
+								Name:       ExpressRouteGatewaySubnetName,
+								CIDRBlocks: []string{"10.0.255.0/27"},
+							},
+							PublicIP: PublicIPSpec{Name: "my-ergw-pip"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setExpressRouteGatewayDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestFirewallDefaults(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no firewall": {
+			cluster: &AzureCluster{Spec: AzureClusterSpec{}},
+			output:  &AzureCluster{Spec: AzureClusterSpec{}},
+		},
+		"existing firewall": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Firewall: &AzureFirewall{ID: "/subscriptions/123/resourceGroups/hub/providers/Microsoft.Network/azureFirewalls/my-azfw"},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Firewall: &AzureFirewall{ID: "/subscriptions/123/resourceGroups/hub/providers/Microsoft.Network/azureFirewalls/my-azfw"},
+					},
+				},
+			},
+		},
+		"created firewall": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Firewall: &AzureFirewall{Name: "my-azfw"},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Firewall: &AzureFirewall{
+							Name:    "my-azfw",
+							SkuTier: AzureFirewallSkuTierStandard,
+							Subnet: SubnetSpec{
+								Name:       AzureFirewallSubnetName,
+								CIDRBlocks: []string{DefaultAzureFirewallSubnetCIDR},
+							},
+							PublicIP: PublicIPSpec{Name: "foo-azfw-pip"},
+						},
+					},
+				},
+			},
+		},
+		"created firewall with custom settings": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Firewall: &AzureFirewall{
+							Name:    "my-azfw",
+							SkuTier: AzureFirewallSkuTierPremium,
+							Subnet: SubnetSpec{
+								Name:       AzureFirewallSubnetName,
+								CIDRBlocks: []string{"10.0.255.0/26"},
+							},
+							PublicIP: PublicIPSpec{Name: "my-azfw-pip"},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Firewall: &AzureFirewall{
+							Name:    "my-azfw",
+							SkuTier: AzureFirewallSkuTierPremium,
+							Subnet: SubnetSpec{
+								Name:       AzureFirewallSubnetName,
+								CIDRBlocks: []string{"10.0.255.0/26"},
+							},
+							PublicIP: PublicIPSpec{Name: "my-azfw-pip"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setFirewallDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestFlowLogsDefaults(t *testing.T) {
+	storageAccountID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/mystorage"
+	workspaceResourceID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-workspace"
+
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no flow logs set": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{Location: "westus2"},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{Location: "westus2"},
+			},
+		},
+		"flow logs with no settings": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					Location: "westus2",
+					NetworkSpec: NetworkSpec{
+						FlowLogs: &FlowLogsSpec{
+							StorageAccountID: storageAccountID,
+							TrafficAnalytics: &TrafficAnalyticsSpec{
+								WorkspaceResourceID: workspaceResourceID,
+								WorkspaceID:         "00000000-1111-2222-3333-444444444444",
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					Location: "westus2",
+					NetworkSpec: NetworkSpec{
+						FlowLogs: &FlowLogsSpec{
+							StorageAccountID: storageAccountID,
+							TrafficAnalytics: &TrafficAnalyticsSpec{
+								WorkspaceResourceID: workspaceResourceID,
+								WorkspaceID:         "00000000-1111-2222-3333-444444444444",
+								WorkspaceRegion:     "westus2",
+								IntervalInMinutes:   pointer.Int32Ptr(60),
+							},
+							NetworkWatcher: &NetworkWatcherSpec{
+								Name:          "NetworkWatcher_westus2",
+								ResourceGroup: "NetworkWatcherRG",
+							},
+						},
+					},
+				},
+			},
+		},
+		"flow logs with all settings": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					Location: "westus2",
+					NetworkSpec: NetworkSpec{
+						FlowLogs: &FlowLogsSpec{
+							StorageAccountID: storageAccountID,
+							TrafficAnalytics: &TrafficAnalyticsSpec{
+								WorkspaceResourceID: workspaceResourceID,
+								WorkspaceID:         "00000000-1111-2222-3333-444444444444",
+								WorkspaceRegion:     "eastus",
+								IntervalInMinutes:   pointer.Int32Ptr(10),
+							},
+							NetworkWatcher: &NetworkWatcherSpec{
+								Name:          "my-watcher",
+								ResourceGroup: "my-watcher-rg",
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					Location: "westus2",
+					NetworkSpec: NetworkSpec{
+						FlowLogs: &FlowLogsSpec{
+							StorageAccountID: storageAccountID,
+							TrafficAnalytics: &TrafficAnalyticsSpec{
+								WorkspaceResourceID: workspaceResourceID,
+								WorkspaceID:         "00000000-1111-2222-3333-444444444444",
+								WorkspaceRegion:     "eastus",
+								IntervalInMinutes:   pointer.Int32Ptr(10),
+							},
+							NetworkWatcher: &NetworkWatcherSpec{
+								Name:          "my-watcher",
+								ResourceGroup: "my-watcher-rg",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setFlowLogsDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestPrivateEndpointDefaults(t *testing.T) {
+	zoneID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/privateDnsZones/privatelink.azurecr.io"
+
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no private DNS zone group": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								Name:             "node-subnet",
+								PrivateEndpoints: []PrivateEndpointSpec{{Name: "my-pe"}},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								Name:             "node-subnet",
+								PrivateEndpoints: []PrivateEndpointSpec{{Name: "my-pe"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		"private DNS zone group name is defaulted": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								Name: "node-subnet",
+								PrivateEndpoints: []PrivateEndpointSpec{
+									{
+										Name:                "my-pe",
+										PrivateDNSZoneGroup: &PrivateDNSZoneGroupSpec{PrivateDNSZoneIDs: []string{zoneID}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								Name: "node-subnet",
+								PrivateEndpoints: []PrivateEndpointSpec{
+									{
+										Name: "my-pe",
+										PrivateDNSZoneGroup: &PrivateDNSZoneGroupSpec{
+											Name:              DefaultPrivateDNSZoneGroupName,
+											PrivateDNSZoneIDs: []string{zoneID},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"private DNS zone group name is not overridden": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								Name: "node-subnet",
+								PrivateEndpoints: []PrivateEndpointSpec{
+									{
+										Name: "my-pe",
+										PrivateDNSZoneGroup: &PrivateDNSZoneGroupSpec{
+											Name:              "my-zone-group",
+											PrivateDNSZoneIDs: []string{zoneID},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								Name: "node-subnet",
+								PrivateEndpoints: []PrivateEndpointSpec{
+									{
+										Name: "my-pe",
+										PrivateDNSZoneGroup: &PrivateDNSZoneGroupSpec{
+											Name:              "my-zone-group",
+											PrivateDNSZoneIDs: []string{zoneID},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setPrivateEndpointDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestAPIServerPrivateLinkServiceDefaults(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no private link service": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{Name: "my-cluster-internal-lb", Type: Internal},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{Name: "my-cluster-internal-lb", Type: Internal},
+					},
+				},
+			},
+		},
+		"private link service name and NAT IP count are defaulted": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB:                 LoadBalancerSpec{Name: "my-cluster-internal-lb", Type: Internal},
+						APIServerPrivateLinkService: &PrivateLinkServiceSpec{},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{Name: "my-cluster-internal-lb", Type: Internal},
+						APIServerPrivateLinkService: &PrivateLinkServiceSpec{
+							Name:       "my-cluster-internal-lb-pls",
+							NATIPCount: pointer.Int32Ptr(1),
+						},
+					},
+				},
+			},
+		},
+		"private link service settings are not overridden": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{Name: "my-cluster-internal-lb", Type: Internal},
+						APIServerPrivateLinkService: &PrivateLinkServiceSpec{
+							Name:       "my-pls",
+							NATIPCount: pointer.Int32Ptr(4),
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{Name: "my-cluster-internal-lb", Type: Internal},
+						APIServerPrivateLinkService: &PrivateLinkServiceSpec{
+							Name:       "my-pls",
+							NATIPCount: pointer.Int32Ptr(4),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setAPIServerPrivateLinkServiceDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestAPIServerPublicDNSDefaults(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no public DNS record": {
+			cluster: &AzureCluster{},
+			output:  &AzureCluster{},
+		},
+		"record type and TTL are defaulted": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerPublicDNS: &PublicDNSRecordSpec{Name: "api.cluster1"},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerPublicDNS: &PublicDNSRecordSpec{
+							Name: "api.cluster1",
+							Type: PublicDNSRecordTypeCNAME,
+							TTL:  pointer.Int64Ptr(300),
+						},
+					},
+				},
+			},
+		},
+		"record type and TTL are not overridden": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerPublicDNS: &PublicDNSRecordSpec{
+							Name: "api.cluster1",
+							Type: PublicDNSRecordTypeA,
+							TTL:  pointer.Int64Ptr(60),
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerPublicDNS: &PublicDNSRecordSpec{
+							Name: "api.cluster1",
+							Type: PublicDNSRecordTypeA,
+							TTL:  pointer.Int64Ptr(60),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setAPIServerPublicDNSDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestAlertsDefaults(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no alerts": {
+			cluster: &AzureCluster{},
+			output:  &AzureCluster{},
+		},
+		"rules and severity are defaulted": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					Alerts: &AlertsSpec{ActionGroupID: "my-action-group"},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					Alerts: &AlertsSpec{
+						ActionGroupID: "my-action-group",
+						Rules:         []AlertRuleType{AlertRuleVMAvailability, AlertRuleLoadBalancerHealthProbe, AlertRuleNATGatewaySNAT},
+						Severity:      pointer.Int32Ptr(2),
+					},
+				},
+			},
+		},
+		"rules and severity are not overridden": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					Alerts: &AlertsSpec{
+						ActionGroupID: "my-action-group",
+						Rules:         []AlertRuleType{AlertRuleNATGatewaySNAT},
+						Severity:      pointer.Int32Ptr(0),
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					Alerts: &AlertsSpec{
+						ActionGroupID: "my-action-group",
+						Rules:         []AlertRuleType{AlertRuleNATGatewaySNAT},
+						Severity:      pointer.Int32Ptr(0),
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setAlertsDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestConnectivityProfileDefaults(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no connectivity profile": {
+			cluster: &AzureCluster{},
+			output:  &AzureCluster{},
+		},
+		"protocol is defaulted": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					ConnectivityProfile: &ConnectivityProfile{
+						Ingress: []ConnectivityPort{{Name: "konnectivity", Port: 8132}},
+						Egress:  []ConnectivityPort{{Name: "metrics", Port: 9090}},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					ConnectivityProfile: &ConnectivityProfile{
+						Ingress: []ConnectivityPort{{Name: "konnectivity", Port: 8132, Protocol: SecurityGroupProtocolTCP}},
+						Egress:  []ConnectivityPort{{Name: "metrics", Port: 9090, Protocol: SecurityGroupProtocolTCP}},
+					},
+				},
+			},
+		},
+		"protocol is not overridden": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					ConnectivityProfile: &ConnectivityProfile{
+						Ingress: []ConnectivityPort{{Name: "tunnel", Port: 51820, Protocol: SecurityGroupProtocolUDP}},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					ConnectivityProfile: &ConnectivityProfile{
+						Ingress: []ConnectivityPort{{Name: "tunnel", Port: 51820, Protocol: SecurityGroupProtocolUDP}},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setConnectivityProfileDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestDiskEncryptionSetsDefaults(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no disk encryption sets": {
+			cluster: &AzureCluster{},
+			output:  &AzureCluster{},
+		},
+		"encryption type is defaulted": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					DiskEncryptionSets: []DiskEncryptionSetSpec{{Name: "my-des"}},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					DiskEncryptionSets: []DiskEncryptionSetSpec{{Name: "my-des", EncryptionType: DiskEncryptionSetTypeCustomerKey}},
+				},
+			},
+		},
+		"encryption type is not overridden": {
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					DiskEncryptionSets: []DiskEncryptionSetSpec{{Name: "my-des", EncryptionType: DiskEncryptionSetTypePlatformAndCustomerKeys}},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					DiskEncryptionSets: []DiskEncryptionSetSpec{{Name: "my-des", EncryptionType: DiskEncryptionSetTypePlatformAndCustomerKeys}},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setDiskEncryptionSetsDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestProximityPlacementGroupDefaults(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no proximity placement group": {
+			cluster: &AzureCluster{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
+			output:  &AzureCluster{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
+		},
+		"name is defaulted": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					ProximityPlacementGroup: &ProximityPlacementGroupSpec{},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					ProximityPlacementGroup: &ProximityPlacementGroupSpec{Name: "foo-ppg"},
+				},
+			},
+		},
+		"name is not overridden": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					ProximityPlacementGroup: &ProximityPlacementGroupSpec{Name: "my-ppg"},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: AzureClusterSpec{
+					ProximityPlacementGroup: &ProximityPlacementGroupSpec{Name: "my-ppg"},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setProximityPlacementGroupDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestSecurityRuleDefaults(t *testing.T) {
+	cases := map[string]struct {
+		sg     *SecurityGroup
+		output *SecurityGroup
+	}{
+		"no security rules": {
+			sg:     &SecurityGroup{Name: "my-sg"},
+			output: &SecurityGroup{Name: "my-sg"},
+		},
+		"direction and priority are defaulted": {
+			sg: &SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "first"},
+					{Name: "second"},
+				},
+			},
+			output: &SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "first", Direction: SecurityRuleDirectionInbound, Priority: 100},
+					{Name: "second", Direction: SecurityRuleDirectionInbound, Priority: 101},
+				},
+			},
+		},
+		"defaulted priorities skip the priorities used by rules of the same direction": {
+			sg: &SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "first"},
+					{Name: "second", Direction: SecurityRuleDirectionInbound, Priority: 100},
+					{Name: "third", Direction: SecurityRuleDirectionOutbound},
+				},
+			},
+			output: &SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "first", Direction: SecurityRuleDirectionInbound, Priority: 101},
+					{Name: "second", Direction: SecurityRuleDirectionInbound, Priority: 100},
+					{Name: "third", Direction: SecurityRuleDirectionOutbound, Priority: 100},
+				},
+			},
+		},
+		"rules of an unknown direction are left to the validation": {
+			sg: &SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "first", Direction: "inbound", Priority: 100},
+					{Name: "second", Direction: "inbound"},
+					{Name: "third"},
+				},
+			},
+			output: &SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "first", Direction: "inbound", Priority: 100},
+					{Name: "second", Direction: "inbound"},
+					{Name: "third", Direction: SecurityRuleDirectionInbound, Priority: 100},
+				},
+			},
+		},
+	}
+
+	// A security group where all the priorities below the band reserved to the default rules are used.
+	full := &SecurityGroup{}
+	for p := int32(minRulePriority); p < DefaultSecurityRulePriorityMin; p++ {
+		full.SecurityRules = append(full.SecurityRules, SecurityRule{Name: fmt.Sprintf("rule-%d", p), Direction: SecurityRuleDirectionInbound, Priority: p})
+	}
+	fullOutput := full.DeepCopy()
+	full.SecurityRules = append(full.SecurityRules, SecurityRule{Name: "next"})
+	fullOutput.SecurityRules = append(fullOutput.SecurityRules, SecurityRule{Name: "next", Direction: SecurityRuleDirectionInbound, Priority: DefaultSecurityRulePriorityMax + 1})
+	cases["defaulted priorities skip the band reserved to the default rules"] = struct {
+		sg     *SecurityGroup
+		output *SecurityGroup
+	}{sg: full, output: fullOutput}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			setSecurityRuleDefaults(c.sg)
+			if !reflect.DeepEqual(c.sg, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.sg, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
@@ -29,6 +29,14 @@ const (
 
 	// ClusterLabelNamespace indicates the namespace of the cluster.
 	ClusterLabelNamespace = "azurecluster.infrastructure.cluster.x-k8s.io/cluster-namespace"
+
+	// ExportTemplateAnnotation requests an export of the Azure resources owned by the cluster in its resource group as
+	// an ARM template, into the ConfigMap <cluster name>-arm-template. The template is exported again whenever the value
+	// of the annotation changes, e.g. to the current date.
+	ExportTemplateAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/export-template"
+
+	// TemplateExportedAnnotation records the value of ExportTemplateAnnotation the template was last exported for.
+	TemplateExportedAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/template-exported"
 )
 
 // AzureClusterSpec defines the desired state of AzureCluster.
@@ -67,6 +75,16 @@ type AzureClusterSpec struct {
 	// +optional
 	AzureEnvironment string `json:"azureEnvironment,omitempty"`
 
+	// AzureEnvironmentEndpoints are the endpoints of a cloud which is not one of the well-known AzureEnvironments,
+	// e.g. an Azure Stack Hub, for this cluster only. AzureEnvironment is then only used as the name of the cloud.
+	// +optional
+	AzureEnvironmentEndpoints *AzureEnvironmentEndpoints `json:"azureEnvironmentEndpoints,omitempty"`
+
+	// APIVersionProfile sets the ARM API versions used to manage the Azure resources of the cluster, for the clouds which
+	// only support older API versions such as Azure Stack Hub.
+	// +optional
+	APIVersionProfile *APIVersionProfile `json:"apiVersionProfile,omitempty"`
+
 	// BastionSpec encapsulates all things related to the Bastions in the cluster.
 	// +optional
 	BastionSpec BastionSpec `json:"bastionSpec,omitempty"`
@@ -78,6 +96,55 @@ type AzureClusterSpec struct {
 	// Note: All cloud provider config values can be customized by creating the secret beforehand. CloudProviderConfigOverrides is only used when the secret is managed by the Azure Provider.
 	// +optional
 	CloudProviderConfigOverrides *CloudProviderConfigOverrides `json:"cloudProviderConfigOverrides,omitempty"`
+
+	// ForceDeleteVirtualMachines force deletes the virtual machines and virtual machine scale sets when the cluster is
+	// deleted, which takes minutes rather than tens of minutes. The machines are deleted normally in the regions where
+	// force deletion is not available, and when the machines are deleted without deleting the cluster.
+	// +optional
+	ForceDeleteVirtualMachines bool `json:"forceDeleteVirtualMachines,omitempty"`
+
+	// APIServerAccessProfile restricts who can reach the API server through its public load balancer. The API server
+	// is reachable from the Internet if it is not set.
+	// +optional
+	APIServerAccessProfile *APIServerAccessProfile `json:"apiServerAccessProfile,omitempty"`
+
+	// ConnectivityProfile opens the ports of agents connecting the control plane of the cluster with management
+	// services, e.g. a konnectivity tunnel, so that they don't require editing the security group of the control plane
+	// subnet after the cluster is created.
+	// +optional
+	ConnectivityProfile *ConnectivityProfile `json:"connectivityProfile,omitempty"`
+
+	// Alerts creates baseline Azure Monitor metric alert rules for the infrastructure of the cluster, which notify an
+	// existing action group. The alert rules owned by the cluster are deleted when it is unset.
+	// +optional
+	Alerts *AlertsSpec `json:"alerts,omitempty"`
+
+	// DiskEncryptionSets are disk encryption sets created for the cluster, which machines can reference by name to
+	// encrypt their disks with a customer-managed key.
+	// +optional
+	DiskEncryptionSets []DiskEncryptionSetSpec `json:"diskEncryptionSets,omitempty"`
+
+	// ProximityPlacementGroup creates a proximity placement group for the cluster, in which its machines are placed
+	// unless they reference another one, to colocate them for low network latency. It can't be changed after creation.
+	// +optional
+	ProximityPlacementGroup *ProximityPlacementGroupSpec `json:"proximityPlacementGroup,omitempty"`
+
+	// ProxyConfig is the HTTP proxy the Linux machines of the cluster reach the Internet through. It is added to the
+	// cloud-config bootstrap data of the machines created after it is set.
+	// +optional
+	ProxyConfig *ProxyConfig `json:"proxyConfig,omitempty"`
+
+	// TrustedCAs references the CA certificates the Linux machines of the cluster trust in addition to the ones of the
+	// OS, e.g. for proxies which intercept the HTTPS requests or private registries with certificates signed by an
+	// internal CA. They are added to the cloud-config bootstrap data of the machines created after it is set.
+	// +optional
+	TrustedCAs *TrustedCASource `json:"trustedCAs,omitempty"`
+
+	// RegistryMirrors configure containerd on the Linux machines of the cluster to pull images from mirrors of
+	// registries, e.g. internal mirrors in air-gapped environments. They are added to the cloud-config bootstrap data
+	// of the machines created after they are set.
+	// +optional
+	RegistryMirrors []RegistryMirror `json:"registryMirrors,omitempty"`
 }
 
 // AzureClusterStatus defines the observed state of AzureCluster.
@@ -103,6 +170,64 @@ type AzureClusterStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// ProvisioningDurations summarizes how long the provisioning of the cluster took.
+	// +optional
+	ProvisioningDurations *ProvisioningDurations `json:"provisioningDurations,omitempty"`
+
+	// RetainedPublicIPs are the names of the public IPs which were left in place on purpose when the cluster was
+	// deleted, as requested by RetainPublicIPsOnDelete.
+	// +optional
+	RetainedPublicIPs []string `json:"retainedPublicIPs,omitempty"`
+
+	// CordonedZones are the availability zones impacted by an Azure service health event, in which new machines are not
+	// placed until the event is resolved when CAPZ picks their zone: control plane machines, machines without a failure
+	// domain and new scale sets spread across all zones. They are only reported when the controller cordons the zones
+	// impacted by outages.
+	// +optional
+	CordonedZones []CordonedZone `json:"cordonedZones,omitempty"`
+}
+
+// ProvisioningDurations summarizes how long the provisioning milestones of a cluster took to be reached, from the
+// creation of its AzureCluster. Each duration is recorded once, when its milestone is first reached.
+type ProvisioningDurations struct {
+	// NetworkReady is how long the network infrastructure of the cluster took to become ready.
+	// +optional
+	NetworkReady *metav1.Duration `json:"networkReady,omitempty"`
+
+	// ControlPlaneProvisioned is how long the control plane of the cluster took to be initialized.
+	// +optional
+	ControlPlaneProvisioned *metav1.Duration `json:"controlPlaneProvisioned,omitempty"`
+
+	// FirstNodeReady is how long the first node of the cluster took to become ready.
+	// +optional
+	FirstNodeReady *metav1.Duration `json:"firstNodeReady,omitempty"`
+
+	// Services is how long each Azure service of the cluster took to be reconciled successfully, keyed by service.
+	// +optional
+	Services map[string]metav1.Duration `json:"services,omitempty"`
+}
+
+// CordonedZone is an availability zone of a cluster impacted by an Azure service health event.
+type CordonedZone struct {
+	// Zone is the availability zone.
+	Zone string `json:"zone"`
+
+	// EventID is the correlation ID of the service health event impacting the zone.
+	// +optional
+	EventID string `json:"eventID,omitempty"`
+
+	// Title is the title of the service health event impacting the zone.
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Since is when the zone was cordoned.
+	Since metav1.Time `json:"since"`
+
+	// MachineDeployments are the MachineDeployments of the cluster whose machines are still placed in the zone by their
+	// failure domain, which CAPZ can't move to another zone.
+	// +optional
+	MachineDeployments []string `json:"machineDeployments,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -17,16 +17,21 @@ limitations under the License.
 package v1beta1
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"net"
+	"net/url"
 	"reflect"
 	"regexp"
+	"strings"
 
 	"k8s.io/utils/pointer"
 
 	valid "github.com/asaskevich/govalidator"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -42,6 +47,42 @@ const (
 	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules.
 	subnetRegex       = `^[-\w\._]+$`
 	loadBalancerRegex = `^[-\w\._]+$`
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules.
+	ddosProtectionPlanRegex   = `^[-\w\._]+$`
+	ddosProtectionPlanIDRegex = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/ddosProtectionPlans/[^/]+$`
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules.
+	virtualNetworkGatewayRegex   = `^[\w][-\w\._]{0,78}[\w_]$`
+	virtualNetworkGatewayIDRegex = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/virtualNetworkGateways/[^/]+$`
+	storageAccountIDRegex        = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Storage/storageAccounts/[^/]+$`
+	workspaceResourceIDRegex     = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.OperationalInsights/workspaces/[^/]+$`
+	workspaceIDRegex             = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules.
+	azureFirewallRegex         = `^[\w][-\w\._]{0,54}[\w_]$`
+	azureFirewallIDRegex       = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/azureFirewalls/[^/]+$`
+	azureFirewallPolicyIDRegex = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/firewallPolicies/[^/]+$`
+	// azureFirewallSubnetMaxPrefixLength is the longest prefix Azure allows for the subnet of Azure Firewall.
+	azureFirewallSubnetMaxPrefixLength = 26
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules.
+	privateEndpointRegex      = `^[\w][-\w\._]{0,78}[\w_]$`
+	privateLinkServiceIDRegex = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/[^/]+/[^/]+/[^/]+(/[^/]+/[^/]+)*$`
+	privateDNSZoneIDRegex     = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/privateDnsZones/[^/]+$`
+	publicDNSZoneIDRegex      = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/dnszones/[^/]+$`
+	actionGroupIDRegex        = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Insights/actionGroups/[^/]+$`
+	diskEncryptionSetRegex    = `^[-\w]{1,80}$`
+	connectivityPortNameRegex = `^[a-zA-Z0-9][-_.a-zA-Z0-9]{0,39}$`
+	keyVaultIDRegex           = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.KeyVault/vaults/[^/]+$`
+	keyVaultKeyURLRegex       = `^https://[^/]+/keys/[^/]+/[^/]+$`
+	privateLinkServiceRegex   = `^[\w][-\w\._]{0,78}[\w_]$`
+	subscriptionIDRegex       = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules.
+	proximityPlacementGroupRegex    = `^[a-zA-Z0-9]([-\w\.]{0,78}[\w])?$`
+	proximityPlacementGroupIDRegex  = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/proximityPlacementGroups/[^/]+$`
+	capacityReservationGroupIDRegex = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/capacityReservationGroups/[^/]+$`
+	// computer names can't be only digits, and can only contain letters, digits and hyphens on both Linux and Windows.
+	computerNamePrefixRegex = `^[a-zA-Z][a-zA-Z0-9-]*$`
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-providers-and-types.
+	resourceProviderTypeRegex = `^[a-zA-Z0-9]+(\.[a-zA-Z0-9]+)+(/[a-zA-Z0-9]+)*$`
+	apiVersionRegex           = `^[0-9]{4}-[0-9]{2}-[0-9]{2}(-preview)?$`
 	// MaxLoadBalancerOutboundIPs is the maximum number of outbound IPs in a Standard LoadBalancer frontend configuration.
 	MaxLoadBalancerOutboundIPs = 16
 	// MinLBIdleTimeoutInMinutes is the minimum number of minutes for the LB idle timeout.
@@ -84,6 +125,26 @@ func (c *AzureCluster) validateClusterSpec(old *AzureCluster) field.ErrorList {
 	allErrs = append(allErrs, validateCloudProviderConfigOverrides(c.Spec.CloudProviderConfigOverrides, oldCloudProviderConfigOverrides,
 		field.NewPath("spec").Child("cloudProviderConfigOverrides"))...)
 
+	allErrs = append(allErrs, validateAPIVersionProfile(c.Spec.APIVersionProfile, field.NewPath("spec").Child("apiVersionProfile"))...)
+
+	allErrs = append(allErrs, validateAzureBastion(c.Spec.BastionSpec.AzureBastion, field.NewPath("spec").Child("bastionSpec").Child("azureBastion"))...)
+
+	allErrs = append(allErrs, validateAPIServerAccessProfile(c.Spec.APIServerAccessProfile, c.Spec.NetworkSpec.APIServerLB,
+		field.NewPath("spec").Child("apiServerAccessProfile"))...)
+
+	allErrs = append(allErrs, validateConnectivityProfile(c.Spec.ConnectivityProfile, field.NewPath("spec").Child("connectivityProfile"))...)
+
+	allErrs = append(allErrs, validateAlerts(c.Spec.Alerts, field.NewPath("spec").Child("alerts"))...)
+
+	allErrs = append(allErrs, validateDiskEncryptionSets(c.Spec.DiskEncryptionSets, field.NewPath("spec").Child("diskEncryptionSets"))...)
+
+	allErrs = append(allErrs, validateProximityPlacementGroup(c.Spec.ProximityPlacementGroup, field.NewPath("spec").Child("proximityPlacementGroup"))...)
+
+	allErrs = append(allErrs, validateAzureEnvironmentEndpoints(c.Spec.AzureEnvironmentEndpoints, field.NewPath("spec").Child("azureEnvironmentEndpoints"))...)
+	allErrs = append(allErrs, ValidateProxyConfig(c.Spec.ProxyConfig, field.NewPath("spec").Child("proxyConfig"))...)
+	allErrs = append(allErrs, validateTrustedCAs(c.Spec.TrustedCAs, field.NewPath("spec").Child("trustedCAs"))...)
+	allErrs = append(allErrs, validateRegistryMirrors(c.Spec.RegistryMirrors, field.NewPath("spec").Child("registryMirrors"))...)
+
 	return allErrs
 }
 
@@ -124,6 +185,18 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 		allErrs = append(allErrs, validateVnetPeerings(networkSpec.Vnet.Peerings, fldPath.Child("peerings"))...)
 	}
 
+	allErrs = append(allErrs, validateDDoSProtectionPlan(networkSpec.Vnet.DDoSProtectionPlan, fldPath.Child("vnet").Child("ddosProtectionPlan"))...)
+
+	allErrs = append(allErrs, validateFlowLogs(networkSpec.FlowLogs, fldPath.Child("flowLogs"))...)
+
+	allErrs = append(allErrs, validateManagementNetwork(networkSpec.ManagementNetwork, fldPath.Child("managementNetwork"))...)
+
+	allErrs = append(allErrs, validateExpressRouteGateway(networkSpec.ExpressRouteGateway, networkSpec.Vnet, fldPath.Child("expressRouteGateway"))...)
+
+	allErrs = append(allErrs, validateFirewall(networkSpec.Firewall, networkSpec.Vnet, fldPath.Child("firewall"))...)
+
+	allErrs = append(allErrs, validateOutboundConflicts(networkSpec, fldPath)...)
+
 	var cidrBlocks []string
 	controlPlaneSubnet, err := networkSpec.GetControlPlaneSubnet()
 	if err != nil {
@@ -149,6 +222,14 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 
 	allErrs = append(allErrs, validatePrivateDNSZoneName(networkSpec, fldPath)...)
 
+	allErrs = append(allErrs, validatePrivateDNSZone(networkSpec, fldPath.Child("privateDNSZone"))...)
+
+	allErrs = append(allErrs, validatePrivateDNSRecords(networkSpec, fldPath.Child("privateDNSRecords"))...)
+
+	allErrs = append(allErrs, validateAPIServerPrivateLinkService(networkSpec, fldPath.Child("apiServerPrivateLinkService"))...)
+
+	allErrs = append(allErrs, validateAPIServerPublicDNS(networkSpec, fldPath.Child("apiServerPublicDNS"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -168,6 +249,7 @@ func validateResourceGroup(resourceGroup string, fldPath *field.Path) *field.Err
 func validateSubnets(subnets Subnets, vnet VnetSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	subnetNames := make(map[string]bool, len(subnets))
+	privateEndpointNames := make(map[string]bool)
 	requiredSubnetRoles := map[string]bool{
 		"control-plane": false,
 		"node":          false,
@@ -186,15 +268,9 @@ func validateSubnets(subnets Subnets, vnet VnetSpec, fldPath *field.Path) field.
 				requiredSubnetRoles[role] = true
 			}
 		}
-		for _, rule := range subnet.SecurityGroup.SecurityRules {
-			if err := validateSecurityRule(
-				rule,
-				fldPath.Index(i).Child("securityGroup").Child("securityRules").Index(i),
-			); err != nil {
-				allErrs = append(allErrs, err)
-			}
-		}
+		allErrs = append(allErrs, validateSecurityGroup(subnet.SecurityGroup, subnet.Role, fldPath.Index(i).Child("securityGroup"))...)
 		allErrs = append(allErrs, validateSubnetCIDR(subnet.CIDRBlocks, vnet.CIDRBlocks, fldPath.Index(i).Child("cidrBlocks"))...)
+		allErrs = append(allErrs, validatePrivateEndpoints(subnet.PrivateEndpoints, privateEndpointNames, fldPath.Index(i).Child("privateEndpoints"))...)
 	}
 	for k, v := range requiredSubnetRoles {
 		if !v {
@@ -214,6 +290,41 @@ func validateSubnetName(name string, fldPath *field.Path) *field.Error {
 	return nil
 }
 
+// validatePrivateEndpoints validates the private endpoints of a Subnet.
+// Private endpoints of all subnets are created in the same resource group, so their names must be unique across subnets.
+func validatePrivateEndpoints(privateEndpoints []PrivateEndpointSpec, names map[string]bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, privateEndpoint := range privateEndpoints {
+		if success, _ := regexp.MatchString(privateEndpointRegex, privateEndpoint.Name); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), privateEndpoint.Name,
+				fmt.Sprintf("name of private endpoint doesn't match regex %s", privateEndpointRegex)))
+		}
+		if names[privateEndpoint.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), privateEndpoint.Name))
+		}
+		names[privateEndpoint.Name] = true
+
+		if success, _ := regexp.MatchString(privateLinkServiceIDRegex, privateEndpoint.PrivateLinkServiceID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("privateLinkServiceID"), privateEndpoint.PrivateLinkServiceID,
+				fmt.Sprintf("privateLinkServiceID doesn't match regex %s", privateLinkServiceIDRegex)))
+		}
+
+		if group := privateEndpoint.PrivateDNSZoneGroup; group != nil {
+			if len(group.PrivateDNSZoneIDs) == 0 {
+				allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("privateDNSZoneGroup", "privateDNSZoneIDs"),
+					"at least one private DNS zone must be set"))
+			}
+			for j, zoneID := range group.PrivateDNSZoneIDs {
+				if success, _ := regexp.MatchString(privateDNSZoneIDRegex, zoneID); !success {
+					allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("privateDNSZoneGroup", "privateDNSZoneIDs").Index(j), zoneID,
+						fmt.Sprintf("privateDNSZoneID doesn't match regex %s", privateDNSZoneIDRegex)))
+				}
+			}
+		}
+	}
+	return allErrs
+}
+
 // validateSubnetCIDR validates the CIDR blocks of a Subnet.
 func validateSubnetCIDR(subnetCidrBlocks []string, vnetCidrBlocks []string, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -273,6 +384,177 @@ func validateVnetPeerings(peerings VnetPeerings, fldPath *field.Path) field.Erro
 	return allErrs
 }
 
+// validateDDoSProtectionPlan validates the DDoS Protection Plan of a virtual network.
+func validateDDoSProtectionPlan(plan *DDoSProtectionPlan, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if plan == nil {
+		return allErrs
+	}
+
+	switch {
+	case plan.ID == "" && plan.Name == "":
+		allErrs = append(allErrs, field.Required(fldPath, "one of id or name must be set"))
+	case plan.ID != "" && plan.Name != "":
+		allErrs = append(allErrs, field.Forbidden(fldPath, "id and name are mutually exclusive"))
+	case plan.ID != "":
+		if success, _ := regexp.MatchString(ddosProtectionPlanIDRegex, plan.ID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("id"), plan.ID,
+				fmt.Sprintf("id of DDoS Protection Plan doesn't match regex %s", ddosProtectionPlanIDRegex)))
+		}
+	default:
+		if success, _ := regexp.MatchString(ddosProtectionPlanRegex, plan.Name); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), plan.Name,
+				fmt.Sprintf("name of DDoS Protection Plan doesn't match regex %s", ddosProtectionPlanRegex)))
+		}
+	}
+	return allErrs
+}
+
+// validateManagementNetwork validates the network of the management cluster.
+func validateManagementNetwork(network *ManagementNetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if network == nil {
+		return allErrs
+	}
+
+	if len(network.CIDRBlocks) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("cidrBlocks"), "at least one CIDR block of the management network must be set"))
+	}
+	for i, cidr := range network.CIDRBlocks {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("cidrBlocks").Index(i), cidr, "invalid CIDR format"))
+		}
+	}
+	if network.Vnet != nil && network.Vnet.RemoteVnetName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("vnet", "remoteVnetName"), "name of the management virtual network must be set"))
+	}
+	return allErrs
+}
+
+// validateExpressRouteGateway validates the ExpressRoute gateway of a virtual network.
+func validateExpressRouteGateway(gateway *ExpressRouteGateway, vnet VnetSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if gateway == nil {
+		return allErrs
+	}
+
+	switch {
+	case gateway.ID == "" && gateway.Name == "":
+		allErrs = append(allErrs, field.Required(fldPath, "one of id or name must be set"))
+	case gateway.ID != "" && gateway.Name != "":
+		allErrs = append(allErrs, field.Forbidden(fldPath, "id and name are mutually exclusive"))
+	case gateway.ID != "":
+		if success, _ := regexp.MatchString(virtualNetworkGatewayIDRegex, gateway.ID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("id"), gateway.ID,
+				fmt.Sprintf("id of ExpressRoute gateway doesn't match regex %s", virtualNetworkGatewayIDRegex)))
+		}
+	default:
+		if success, _ := regexp.MatchString(virtualNetworkGatewayRegex, gateway.Name); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), gateway.Name,
+				fmt.Sprintf("name of ExpressRoute gateway doesn't match regex %s", virtualNetworkGatewayRegex)))
+		}
+		// Azure only deploys virtual network gateways in a dedicated subnet without a network security group.
+		if gateway.Subnet.Name != ExpressRouteGatewaySubnetName {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("subnet", "name"), gateway.Subnet.Name,
+				fmt.Sprintf("subnet of ExpressRoute gateway must be named %s", ExpressRouteGatewaySubnetName)))
+		}
+		if gateway.Subnet.SecurityGroup.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("subnet", "securityGroup"), "network security groups are not supported on the subnet of ExpressRoute gateway"))
+		}
+		allErrs = append(allErrs, validateSubnetCIDR(gateway.Subnet.CIDRBlocks, vnet.CIDRBlocks, fldPath.Child("subnet", "cidrBlocks"))...)
+	}
+	return allErrs
+}
+
+// validateFirewall validates the Azure Firewall the egress traffic of the nodes is routed through.
+func validateFirewall(firewall *AzureFirewall, vnet VnetSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if firewall == nil {
+		return allErrs
+	}
+
+	switch {
+	case firewall.ID == "" && firewall.Name == "":
+		allErrs = append(allErrs, field.Required(fldPath, "one of id or name must be set"))
+	case firewall.ID != "" && firewall.Name != "":
+		allErrs = append(allErrs, field.Forbidden(fldPath, "id and name are mutually exclusive"))
+	case firewall.ID != "":
+		if success, _ := regexp.MatchString(azureFirewallIDRegex, firewall.ID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("id"), firewall.ID,
+				fmt.Sprintf("id of Azure Firewall doesn't match regex %s", azureFirewallIDRegex)))
+		}
+		if firewall.PolicyID != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("policyID"), "the policy of an existing Azure Firewall is not managed"))
+		}
+	default:
+		if success, _ := regexp.MatchString(azureFirewallRegex, firewall.Name); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), firewall.Name,
+				fmt.Sprintf("name of Azure Firewall doesn't match regex %s", azureFirewallRegex)))
+		}
+		if firewall.PolicyID != "" {
+			if success, _ := regexp.MatchString(azureFirewallPolicyIDRegex, firewall.PolicyID); !success {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("policyID"), firewall.PolicyID,
+					fmt.Sprintf("id of firewall policy doesn't match regex %s", azureFirewallPolicyIDRegex)))
+			}
+		}
+		// Azure only deploys a firewall in a dedicated subnet of at least a /26, without a network security group.
+		if firewall.Subnet.Name != AzureFirewallSubnetName {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("subnet", "name"), firewall.Subnet.Name,
+				fmt.Sprintf("subnet of Azure Firewall must be named %s", AzureFirewallSubnetName)))
+		}
+		if firewall.Subnet.SecurityGroup.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("subnet", "securityGroup"), "network security groups are not supported on the subnet of Azure Firewall"))
+		}
+		for _, cidr := range firewall.Subnet.CIDRBlocks {
+			if _, subnet, err := net.ParseCIDR(cidr); err == nil {
+				if ones, _ := subnet.Mask.Size(); ones > azureFirewallSubnetMaxPrefixLength {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("subnet", "cidrBlocks"), cidr,
+						fmt.Sprintf("subnet of Azure Firewall must be at least a /%d", azureFirewallSubnetMaxPrefixLength)))
+				}
+			}
+		}
+		allErrs = append(allErrs, validateSubnetCIDR(firewall.Subnet.CIDRBlocks, vnet.CIDRBlocks, fldPath.Child("subnet", "cidrBlocks"))...)
+	}
+	return allErrs
+}
+
+// validateOutboundConflicts rejects outbound configurations which compete for the egress of the same machines. Azure
+// only uses one of them, so that the others are silently ignored or fail with errors unrelated to the cause.
+func validateOutboundConflicts(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allNodeSubnetsWithNatGateway := true
+	var nodeSubnets int
+	for i, subnet := range networkSpec.Subnets {
+		if !subnet.IsNatGatewayEnabled() {
+			if subnet.Role == SubnetNode {
+				nodeSubnets++
+				allNodeSubnetsWithNatGateway = false
+			}
+			continue
+		}
+
+		switch subnet.Role {
+		case SubnetControlPlane:
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("subnets").Index(i).Child("natGateway"),
+				fmt.Sprintf("control plane subnet %s can't have a NAT gateway: the egress of the control plane machines always goes through the outbound rules of the API server load balancer, or of the control plane outbound load balancer of a private cluster", subnet.Name)))
+		case SubnetNode:
+			nodeSubnets++
+			if networkSpec.Firewall != nil {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("subnets").Index(i).Child("natGateway"),
+					fmt.Sprintf("node subnet %s can't have a NAT gateway when the egress of the nodes is routed through an Azure Firewall: the default route to the firewall takes precedence over the NAT gateway, which would never be used. Remove either the NAT gateway or the firewall", subnet.Name)))
+			}
+		}
+	}
+
+	if networkSpec.NodeOutboundLB != nil && nodeSubnets > 0 && allNodeSubnetsWithNatGateway {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("nodeOutboundLB"),
+			"node outbound load balancer can't be set when all the node subnets have a NAT gateway: the NAT gateways take precedence over the outbound rules of the load balancer for all the nodes. Remove either the node outbound load balancer or the NAT gateway of a node subnet"))
+	}
+
+	return allErrs
+}
+
 // validateLoadBalancerName validates the Name of a Load Balancer.
 func validateLoadBalancerName(name string, fldPath *field.Path) *field.Error {
 	if success, _ := regexp.Match(loadBalancerRegex, []byte(name)); !success {
@@ -299,6 +581,41 @@ func validateInternalLBIPAddress(address string, cidrs []string, fldPath *field.
 		fmt.Sprintf("Internal LB IP address needs to be in control plane subnet range (%s)", cidrs))
 }
 
+// validateSecurityGroup validates the security rules of a SecurityGroup and the default rules it disables.
+func validateSecurityGroup(sg SecurityGroup, role SubnetRole, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	ruleNames := make(map[string]bool, len(sg.SecurityRules))
+	priorities := map[SecurityRuleDirection]map[int32]bool{
+		SecurityRuleDirectionInbound:  {},
+		SecurityRuleDirectionOutbound: {},
+	}
+	for i, rule := range sg.SecurityRules {
+		if err := validateSecurityRule(rule, fldPath.Child("securityRules").Index(i)); err != nil {
+			allErrs = append(allErrs, err)
+		}
+		if priorities[rule.Direction][rule.Priority] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("securityRules").Index(i).Child("priority"), rule.Priority))
+		}
+		if priorities[rule.Direction] != nil {
+			priorities[rule.Direction][rule.Priority] = true
+		}
+		ruleNames[rule.Name] = true
+	}
+
+	if len(sg.DisabledDefaultRules) > 0 && role != SubnetControlPlane {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("disabledDefaultRules"), "default security rules are only added to the control plane subnet"))
+	}
+	for i, name := range sg.DisabledDefaultRules {
+		if !sets.NewString(DefaultSecurityRuleNames...).Has(name) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("disabledDefaultRules").Index(i), name, DefaultSecurityRuleNames))
+		}
+		if ruleNames[name] {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("disabledDefaultRules").Index(i), name, "security rule is both declared and disabled"))
+		}
+	}
+	return allErrs
+}
+
 // validateSecurityRule validates a SecurityRule.
 func validateSecurityRule(rule SecurityRule, fldPath *field.Path) *field.Error {
 	if rule.Priority < minRulePriority || rule.Priority > maxRulePriority {
@@ -468,6 +785,95 @@ func validateControlPlaneOutboundLB(lb *LoadBalancerSpec, apiserverLB LoadBalanc
 	return allErrs
 }
 
+// validatePrivateDNSZone validates the reference to an existing private DNS zone.
+func validatePrivateDNSZone(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	zone := networkSpec.PrivateDNSZone
+	if zone == nil {
+		return nil
+	}
+	if networkSpec.APIServerLB.Type != Internal {
+		allErrs = append(allErrs, field.Invalid(fldPath, networkSpec.APIServerLB.Type,
+			"PrivateDNSZone is available only if APIServerLB.Type is Internal"))
+	}
+	if len(networkSpec.PrivateDNSZoneName) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "PrivateDNSZone cannot be set together with PrivateDNSZoneName"))
+	}
+	if success, _ := regexp.MatchString(privateDNSZoneIDRegex, zone.ID); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("id"), zone.ID,
+			fmt.Sprintf("privateDNSZoneID doesn't match regex %s", privateDNSZoneIDRegex)))
+	}
+
+	return allErrs
+}
+
+// validatePrivateDNSRecords validates the additional records of the private DNS zone.
+func validatePrivateDNSRecords(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(networkSpec.PrivateDNSRecords) == 0 {
+		return nil
+	}
+	if networkSpec.APIServerLB.Type != Internal {
+		allErrs = append(allErrs, field.Invalid(fldPath, networkSpec.APIServerLB.Type,
+			"PrivateDNSRecords are available only if APIServerLB.Type is Internal"))
+	}
+
+	// The API server record is "apiserver", or "apiserver.<cluster name>" in an existing private DNS zone.
+	hostnames := sets.NewString()
+	for i, record := range networkSpec.PrivateDNSRecords {
+		recordPath := fldPath.Index(i)
+		if !valid.IsDNSName(record.Hostname) {
+			allErrs = append(allErrs, field.Invalid(recordPath.Child("hostname"), record.Hostname,
+				"hostname can only contain alphanumeric characters, underscores and dashes, must end with an alphanumeric character"))
+		} else if record.Hostname == "apiserver" || strings.HasPrefix(record.Hostname, "apiserver.") {
+			allErrs = append(allErrs, field.Forbidden(recordPath.Child("hostname"), "hostname is reserved for the API server record"))
+		} else if hostnames.Has(record.Hostname) {
+			allErrs = append(allErrs, field.Duplicate(recordPath.Child("hostname"), record.Hostname))
+		}
+		hostnames.Insert(record.Hostname)
+
+		switch {
+		case record.IP == "" && record.CNAME == "":
+			allErrs = append(allErrs, field.Required(recordPath, "one of ip or cname must be set"))
+		case record.IP != "" && record.CNAME != "":
+			allErrs = append(allErrs, field.Forbidden(recordPath, "ip and cname cannot be set together"))
+		case record.IP != "" && net.ParseIP(record.IP) == nil:
+			allErrs = append(allErrs, field.Invalid(recordPath.Child("ip"), record.IP, "ip must be a valid IPv4 or IPv6 address"))
+		case record.CNAME != "" && !valid.IsDNSName(record.CNAME):
+			allErrs = append(allErrs, field.Invalid(recordPath.Child("cname"), record.CNAME, "cname must be a valid DNS name"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateAPIServerPublicDNS validates the record of a public API server in an existing DNS zone.
+func validateAPIServerPublicDNS(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	record := networkSpec.APIServerPublicDNS
+	if record == nil {
+		return nil
+	}
+	if networkSpec.APIServerLB.Type != Public {
+		allErrs = append(allErrs, field.Invalid(fldPath, networkSpec.APIServerLB.Type,
+			"APIServerPublicDNS is available only if APIServerLB.Type is Public"))
+	}
+	if success, _ := regexp.MatchString(publicDNSZoneIDRegex, record.ZoneID); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("zoneID"), record.ZoneID,
+			fmt.Sprintf("zoneID doesn't match regex %s", publicDNSZoneIDRegex)))
+	}
+	// The apex "@" of the zone is rejected: it cannot hold a CNAME record.
+	if !valid.IsDNSName(record.Name) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), record.Name,
+			"name can only contain alphanumeric characters, underscores and dashes, must end with an alphanumeric character"))
+	}
+
+	return allErrs
+}
+
 // validatePrivateDNSZoneName validate the PrivateDNSZoneName.
 func validatePrivateDNSZoneName(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -490,6 +896,40 @@ func validatePrivateDNSZoneName(networkSpec NetworkSpec, fldPath *field.Path) fi
 	return allErrs
 }
 
+// validateAPIServerPrivateLinkService validates the private link service of the API server load balancer.
+func validateAPIServerPrivateLinkService(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	pls := networkSpec.APIServerPrivateLinkService
+	if pls == nil {
+		return allErrs
+	}
+
+	if networkSpec.APIServerLB.Type != Internal {
+		allErrs = append(allErrs, field.Invalid(fldPath, networkSpec.APIServerLB.Type,
+			"APIServerPrivateLinkService is available only if APIServerLB.Type is Internal"))
+	}
+	if success, _ := regexp.MatchString(privateLinkServiceRegex, pls.Name); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), pls.Name,
+			fmt.Sprintf("name of private link service doesn't match regex %s", privateLinkServiceRegex)))
+	}
+	for i, subscriptionID := range pls.Visibility {
+		if subscriptionID == "*" {
+			continue
+		}
+		if success, _ := regexp.MatchString(subscriptionIDRegex, subscriptionID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("visibility").Index(i), subscriptionID,
+				fmt.Sprintf("visibility must be \"*\" or a subscription ID matching regex %s", subscriptionIDRegex)))
+		}
+	}
+	for i, subscriptionID := range pls.AutoApproval {
+		if success, _ := regexp.MatchString(subscriptionIDRegex, subscriptionID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("autoApproval").Index(i), subscriptionID,
+				fmt.Sprintf("autoApproval must be a subscription ID matching regex %s", subscriptionIDRegex)))
+		}
+	}
+	return allErrs
+}
+
 // validateCloudProviderConfigOverrides validates CloudProviderConfigOverrides.
 func validateCloudProviderConfigOverrides(old, new *CloudProviderConfigOverrides, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -498,3 +938,313 @@ func validateCloudProviderConfigOverrides(old, new *CloudProviderConfigOverrides
 	}
 	return allErrs
 }
+
+// validateAPIVersionProfile validates the overrides of an APIVersionProfile.
+func validateAPIVersionProfile(profile *APIVersionProfile, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if profile == nil {
+		return allErrs
+	}
+
+	for key, version := range profile.Overrides {
+		if success, _ := regexp.MatchString(resourceProviderTypeRegex, key); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("overrides"), key,
+				fmt.Sprintf("resource provider namespace or resource type doesn't match regex %s", resourceProviderTypeRegex)))
+		}
+		if success, _ := regexp.MatchString(apiVersionRegex, version); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("overrides").Key(key), version,
+				fmt.Sprintf("API version doesn't match regex %s", apiVersionRegex)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateAPIServerAccessProfile validates the source CIDR blocks allowed to reach a public API server.
+func validateAPIServerAccessProfile(profile *APIServerAccessProfile, apiServerLB LoadBalancerSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if profile == nil {
+		return allErrs
+	}
+
+	if apiServerLB.Type != Public {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "API server access profile is only supported with a public API server load balancer"))
+	}
+	if len(profile.AllowedCIDRs) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("allowedCIDRs"), "at least one allowed CIDR block must be set"))
+	}
+	for i, cidr := range profile.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allowedCIDRs").Index(i), cidr, "invalid CIDR format"))
+		}
+	}
+	return allErrs
+}
+
+// validateConnectivityProfile validates the ports of the agents connecting the control plane with management services.
+func validateConnectivityProfile(profile *ConnectivityProfile, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if profile == nil {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validateConnectivityPorts(profile.Ingress, fldPath.Child("ingress"))...)
+	allErrs = append(allErrs, validateConnectivityPorts(profile.Egress, fldPath.Child("egress"))...)
+	return allErrs
+}
+
+// validateConnectivityPorts validates the names, ports and CIDR blocks of the ingress or egress ports of a
+// connectivity profile. Ingress ports are forwarded by the API server load balancer, so a port and protocol can only
+// be used once.
+func validateConnectivityPorts(ports []ConnectivityPort, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]bool, len(ports))
+	usedPorts := make(map[string]bool, len(ports))
+	for i, port := range ports {
+		if success, _ := regexp.MatchString(connectivityPortNameRegex, port.Name); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), port.Name,
+				fmt.Sprintf("name doesn't match regex %s", connectivityPortNameRegex)))
+		}
+		if names[strings.ToLower(port.Name)] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), port.Name))
+		}
+		names[strings.ToLower(port.Name)] = true
+		if port.Port < 1 || port.Port > 65535 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("port"), port.Port, "port must be between 1 and 65535"))
+		}
+		if port.Protocol != SecurityGroupProtocolTCP && port.Protocol != SecurityGroupProtocolUDP {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i).Child("protocol"), port.Protocol,
+				[]string{string(SecurityGroupProtocolTCP), string(SecurityGroupProtocolUDP)}))
+		}
+		key := fmt.Sprintf("%s/%d", port.Protocol, port.Port)
+		if usedPorts[key] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("port"), port.Port))
+		}
+		usedPorts[key] = true
+		for j, cidr := range port.CIDRBlocks {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("cidrBlocks").Index(j), cidr, "invalid CIDR format"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateAlerts validates the action group of the baseline alerts of a cluster.
+func validateAlerts(alerts *AlertsSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if alerts == nil {
+		return allErrs
+	}
+
+	if success, _ := regexp.MatchString(actionGroupIDRegex, alerts.ActionGroupID); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("actionGroupID"), alerts.ActionGroupID,
+			fmt.Sprintf("actionGroupID doesn't match regex %s", actionGroupIDRegex)))
+	}
+	return allErrs
+}
+
+// validateDiskEncryptionSets validates the names, keys and key vaults of the disk encryption sets of a cluster.
+func validateDiskEncryptionSets(diskEncryptionSets []DiskEncryptionSetSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]bool, len(diskEncryptionSets))
+	for i, diskEncryptionSet := range diskEncryptionSets {
+		if success, _ := regexp.MatchString(diskEncryptionSetRegex, diskEncryptionSet.Name); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), diskEncryptionSet.Name,
+				fmt.Sprintf("name of disk encryption set doesn't match regex %s", diskEncryptionSetRegex)))
+		}
+		if names[diskEncryptionSet.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), diskEncryptionSet.Name))
+		}
+		names[diskEncryptionSet.Name] = true
+
+		if success, _ := regexp.MatchString(keyVaultKeyURLRegex, diskEncryptionSet.KeyURL); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("keyURL"), diskEncryptionSet.KeyURL,
+				"keyURL must be the URL of a versioned Key Vault key, e.g. https://myvault.vault.azure.net/keys/mykey/0123456789abcdef"))
+		}
+		if success, _ := regexp.MatchString(keyVaultIDRegex, diskEncryptionSet.KeyVaultID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("keyVaultID"), diskEncryptionSet.KeyVaultID,
+				fmt.Sprintf("keyVaultID doesn't match regex %s", keyVaultIDRegex)))
+		}
+	}
+	return allErrs
+}
+
+// validateProximityPlacementGroup validates the name of the proximity placement group of a cluster.
+func validateProximityPlacementGroup(proximityPlacementGroup *ProximityPlacementGroupSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if proximityPlacementGroup == nil {
+		return allErrs
+	}
+
+	if success, _ := regexp.MatchString(proximityPlacementGroupRegex, proximityPlacementGroup.Name); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), proximityPlacementGroup.Name,
+			fmt.Sprintf("name of proximity placement group doesn't match regex %s", proximityPlacementGroupRegex)))
+	}
+	return allErrs
+}
+
+// validateAzureEnvironmentEndpoints validates that the endpoints of a custom cloud are HTTPS URLs.
+func validateAzureEnvironmentEndpoints(endpoints *AzureEnvironmentEndpoints, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if endpoints == nil {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validateHTTPSEndpoint(endpoints.ResourceManagerEndpoint, fldPath.Child("resourceManagerEndpoint"))...)
+	allErrs = append(allErrs, validateHTTPSEndpoint(endpoints.ActiveDirectoryEndpoint, fldPath.Child("activeDirectoryEndpoint"))...)
+	if endpoints.TokenAudience != "" {
+		allErrs = append(allErrs, validateHTTPSEndpoint(endpoints.TokenAudience, fldPath.Child("tokenAudience"))...)
+	}
+	if endpoints.ResourceManagerVMDNSSuffix == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("resourceManagerVMDNSSuffix"), "DNS suffix of the public IPs is required"))
+	}
+	return allErrs
+}
+
+// ValidateProxyConfig validates the URLs of the proxies and the trusted CA certificate of a ProxyConfig.
+func ValidateProxyConfig(proxyConfig *ProxyConfig, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if proxyConfig == nil {
+		return allErrs
+	}
+
+	if proxyConfig.HTTPProxy == "" && proxyConfig.HTTPSProxy == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of httpProxy and httpsProxy is required"))
+	}
+	if proxyConfig.HTTPProxy != "" {
+		allErrs = append(allErrs, validateProxyURL(proxyConfig.HTTPProxy, fldPath.Child("httpProxy"))...)
+	}
+	if proxyConfig.HTTPSProxy != "" {
+		allErrs = append(allErrs, validateProxyURL(proxyConfig.HTTPSProxy, fldPath.Child("httpsProxy"))...)
+	}
+	for i, noProxy := range proxyConfig.NoProxy {
+		if noProxy == "" || strings.ContainsAny(noProxy, ", ") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("noProxy").Index(i), noProxy, "must be a single host, domain, IP address or CIDR"))
+		}
+	}
+	if proxyConfig.TrustedCA != "" {
+		block, _ := pem.Decode([]byte(proxyConfig.TrustedCA))
+		if block == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("trustedCA"), proxyConfig.TrustedCA, "must be a PEM encoded certificate"))
+		} else if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("trustedCA"), proxyConfig.TrustedCA, fmt.Sprintf("must be a PEM encoded certificate: %v", err)))
+		}
+	}
+	return allErrs
+}
+
+// validateTrustedCAs validates the secret reference and the registries of a TrustedCASource.
+func validateTrustedCAs(trustedCAs *TrustedCASource, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if trustedCAs == nil {
+		return allErrs
+	}
+
+	if trustedCAs.SecretName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("secretName"), "secretName is required"))
+	}
+	for i, registry := range trustedCAs.Registries {
+		if registry == "" || strings.ContainsAny(registry, "/ ") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("registries").Index(i), registry, "must be the host of a registry, optionally with a port, without scheme or path"))
+		}
+	}
+	return allErrs
+}
+
+// validateRegistryMirrors validates that the mirrored registries are hosts configured once, mirrored by absolute HTTP
+// or HTTPS URLs.
+func validateRegistryMirrors(mirrors []RegistryMirror, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	registries := make(map[string]bool, len(mirrors))
+	for i, mirror := range mirrors {
+		if mirror.Registry == "" || strings.ContainsAny(mirror.Registry, "/ ") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("registry"), mirror.Registry, "must be the host of a registry, optionally with a port, without scheme or path"))
+		} else if registries[mirror.Registry] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("registry"), mirror.Registry))
+		}
+		registries[mirror.Registry] = true
+
+		if len(mirror.Endpoints) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("endpoints"), "at least one mirror endpoint is required"))
+		}
+		for j, endpoint := range mirror.Endpoints {
+			allErrs = append(allErrs, validateProxyURL(endpoint, fldPath.Index(i).Child("endpoints").Index(j))...)
+		}
+	}
+	return allErrs
+}
+
+// validateProxyURL validates that the URL of a proxy is an absolute HTTP or HTTPS URL.
+func validateProxyURL(proxy string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	u, err := url.Parse(proxy)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath, proxy, "must be an absolute HTTP or HTTPS URL"))
+	}
+	return allErrs
+}
+
+// validateHTTPSEndpoint validates that an endpoint is an absolute HTTPS URL.
+func validateHTTPSEndpoint(endpoint string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath, endpoint, "must be an absolute HTTPS URL"))
+	}
+	return allErrs
+}
+
+// validateAzureBastion validates that the features of an AzureBastion are supported by its SKU.
+func validateAzureBastion(bastion *AzureBastion, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if bastion == nil || bastion.Sku == BastionHostSkuStandard {
+		return allErrs
+	}
+
+	if bastion.ScaleUnits != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("scaleUnits"), "scale units require the Standard SKU"))
+	}
+	if bastion.EnableTunneling {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("enableTunneling"), "tunneling requires the Standard SKU"))
+	}
+	if bastion.EnableIPConnect {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("enableIPConnect"), "IP-based connections require the Standard SKU"))
+	}
+
+	return allErrs
+}
+
+// validateFlowLogs validates the NSG flow logs settings.
+func validateFlowLogs(flowLogs *FlowLogsSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if flowLogs == nil {
+		return allErrs
+	}
+
+	if success, _ := regexp.MatchString(storageAccountIDRegex, flowLogs.StorageAccountID); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("storageAccountID"), flowLogs.StorageAccountID,
+			fmt.Sprintf("storageAccountID doesn't match regex %s", storageAccountIDRegex)))
+	}
+
+	if analytics := flowLogs.TrafficAnalytics; analytics != nil {
+		if success, _ := regexp.MatchString(workspaceResourceIDRegex, analytics.WorkspaceResourceID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("trafficAnalytics", "workspaceResourceID"), analytics.WorkspaceResourceID,
+				fmt.Sprintf("workspaceResourceID doesn't match regex %s", workspaceResourceIDRegex)))
+		}
+		if success, _ := regexp.MatchString(workspaceIDRegex, analytics.WorkspaceID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("trafficAnalytics", "workspaceID"), analytics.WorkspaceID,
+				fmt.Sprintf("workspaceID doesn't match regex %s", workspaceIDRegex)))
+		}
+	}
+
+	if watcher := flowLogs.NetworkWatcher; watcher != nil {
+		if watcher.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("networkWatcher", "name"), "name of the Network Watcher must be set"))
+		}
+		if err := validateResourceGroup(watcher.ResourceGroup, fldPath.Child("networkWatcher", "resourceGroup")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	return allErrs
+}
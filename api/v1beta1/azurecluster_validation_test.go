@@ -17,7 +17,17 @@ limitations under the License.
 package v1beta1
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/utils/pointer"
 
@@ -406,6 +416,1289 @@ func TestValidateVnetCIDR(t *testing.T) {
 	}
 }
 
+func TestValidateDDoSProtectionPlan(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		plan    *DDoSProtectionPlan
+		wantErr bool
+	}{
+		{
+			name:    "no plan",
+			plan:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "existing plan",
+			plan:    &DDoSProtectionPlan{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/ddosProtectionPlans/my-plan"},
+			wantErr: false,
+		},
+		{
+			name:    "managed plan",
+			plan:    &DDoSProtectionPlan{Name: "my-plan"},
+			wantErr: false,
+		},
+		{
+			name:    "empty plan",
+			plan:    &DDoSProtectionPlan{},
+			wantErr: true,
+		},
+		{
+			name:    "both id and name",
+			plan:    &DDoSProtectionPlan{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/ddosProtectionPlans/my-plan", Name: "my-plan"},
+			wantErr: true,
+		},
+		{
+			name:    "id of another resource type",
+			plan:    &DDoSProtectionPlan{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			plan:    &DDoSProtectionPlan{Name: "my plan"},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateDDoSProtectionPlan(testCase.plan, field.NewPath("vnet.ddosProtectionPlan"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateManagementNetwork(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		network *ManagementNetworkSpec
+		wantErr bool
+	}{
+		{
+			name:    "no management network",
+			network: nil,
+			wantErr: false,
+		},
+		{
+			name:    "cidr blocks only",
+			network: &ManagementNetworkSpec{CIDRBlocks: []string{"192.168.0.0/16"}},
+			wantErr: false,
+		},
+		{
+			name: "peered management network",
+			network: &ManagementNetworkSpec{
+				CIDRBlocks: []string{"192.168.0.0/16"},
+				Vnet:       &VnetPeeringSpec{RemoteVnetName: "mgmt-vnet"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "no cidr blocks",
+			network: &ManagementNetworkSpec{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid cidr block",
+			network: &ManagementNetworkSpec{CIDRBlocks: []string{"192.168.0.0"}},
+			wantErr: true,
+		},
+		{
+			name: "peering without virtual network name",
+			network: &ManagementNetworkSpec{
+				CIDRBlocks: []string{"192.168.0.0/16"},
+				Vnet:       &VnetPeeringSpec{ResourceGroup: "mgmt-rg"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateManagementNetwork(testCase.network, field.NewPath("managementNetwork"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateAPIServerAccessProfile(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		profile     *APIServerAccessProfile
+		apiServerLB LoadBalancerSpec
+		wantErr     bool
+	}{
+		{
+			name:        "no access profile",
+			profile:     nil,
+			apiServerLB: LoadBalancerSpec{Type: Internal},
+			wantErr:     false,
+		},
+		{
+			name:        "allowed cidr blocks",
+			profile:     &APIServerAccessProfile{AllowedCIDRs: []string{"203.0.113.0/24", "198.51.100.7/32"}},
+			apiServerLB: LoadBalancerSpec{Type: Public},
+			wantErr:     false,
+		},
+		{
+			name:        "no allowed cidr blocks",
+			profile:     &APIServerAccessProfile{},
+			apiServerLB: LoadBalancerSpec{Type: Public},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid cidr block",
+			profile:     &APIServerAccessProfile{AllowedCIDRs: []string{"203.0.113.0"}},
+			apiServerLB: LoadBalancerSpec{Type: Public},
+			wantErr:     true,
+		},
+		{
+			name:        "internal API server load balancer",
+			profile:     &APIServerAccessProfile{AllowedCIDRs: []string{"203.0.113.0/24"}},
+			apiServerLB: LoadBalancerSpec{Type: Internal},
+			wantErr:     true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateAPIServerAccessProfile(testCase.profile, testCase.apiServerLB, field.NewPath("apiServerAccessProfile"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateAPIServerPublicDNS(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		record      *PublicDNSRecordSpec
+		apiServerLB LoadBalancerSpec
+		wantErr     bool
+	}{
+		{
+			name:        "no public DNS record",
+			record:      nil,
+			apiServerLB: LoadBalancerSpec{Type: Internal},
+			wantErr:     false,
+		},
+		{
+			name:        "valid public DNS record",
+			record:      createValidPublicDNSRecord(),
+			apiServerLB: LoadBalancerSpec{Type: Public},
+			wantErr:     false,
+		},
+		{
+			name: "private DNS zone ID",
+			record: &PublicDNSRecordSpec{
+				ZoneID: "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/privateDnsZones/example.com",
+				Name:   "api.cluster1",
+			},
+			apiServerLB: LoadBalancerSpec{Type: Public},
+			wantErr:     true,
+		},
+		{
+			name: "apex of the zone",
+			record: &PublicDNSRecordSpec{
+				ZoneID: "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/dnszones/example.com",
+				Name:   "@",
+			},
+			apiServerLB: LoadBalancerSpec{Type: Public},
+			wantErr:     true,
+		},
+		{
+			name:        "internal API server load balancer",
+			record:      createValidPublicDNSRecord(),
+			apiServerLB: LoadBalancerSpec{Type: Internal},
+			wantErr:     true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			networkSpec := NetworkSpec{APIServerLB: testCase.apiServerLB, APIServerPublicDNS: testCase.record}
+			err := validateAPIServerPublicDNS(networkSpec, field.NewPath("apiServerPublicDNS"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+func TestValidateConnectivityProfile(t *testing.T) {
+	g := NewWithT(t)
+
+	konnectivity := ConnectivityPort{Name: "konnectivity", Port: 8132, Protocol: SecurityGroupProtocolTCP}
+	tests := []struct {
+		name    string
+		profile *ConnectivityProfile
+		wantErr bool
+	}{
+		{
+			name:    "no connectivity profile",
+			profile: nil,
+			wantErr: false,
+		},
+		{
+			name: "ingress and egress ports",
+			profile: &ConnectivityProfile{
+				Ingress: []ConnectivityPort{konnectivity},
+				Egress:  []ConnectivityPort{{Name: "metrics", Port: 9090, Protocol: SecurityGroupProtocolUDP, CIDRBlocks: []string{"10.10.0.0/16"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "invalid name",
+			profile: &ConnectivityProfile{Ingress: []ConnectivityPort{{Name: "-konnectivity", Port: 8132, Protocol: SecurityGroupProtocolTCP}}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate name",
+			profile: &ConnectivityProfile{Ingress: []ConnectivityPort{
+				konnectivity,
+				{Name: "Konnectivity", Port: 8133, Protocol: SecurityGroupProtocolTCP},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid port",
+			profile: &ConnectivityProfile{Egress: []ConnectivityPort{{Name: "metrics", Port: 65536, Protocol: SecurityGroupProtocolTCP}}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported protocol",
+			profile: &ConnectivityProfile{Egress: []ConnectivityPort{{Name: "metrics", Port: 9090, Protocol: SecurityGroupProtocolICMP}}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate port",
+			profile: &ConnectivityProfile{Ingress: []ConnectivityPort{
+				konnectivity,
+				{Name: "agent", Port: 8132, Protocol: SecurityGroupProtocolTCP},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "same port with another protocol",
+			profile: &ConnectivityProfile{Ingress: []ConnectivityPort{
+				konnectivity,
+				{Name: "agent", Port: 8132, Protocol: SecurityGroupProtocolUDP},
+			}},
+			wantErr: false,
+		},
+		{
+			name:    "invalid cidr block",
+			profile: &ConnectivityProfile{Ingress: []ConnectivityPort{{Name: "konnectivity", Port: 8132, Protocol: SecurityGroupProtocolTCP, CIDRBlocks: []string{"10.10.0.0"}}}},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateConnectivityProfile(testCase.profile, field.NewPath("connectivityProfile"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateAlerts(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		alerts  *AlertsSpec
+		wantErr bool
+	}{
+		{
+			name:    "no alerts",
+			alerts:  nil,
+			wantErr: false,
+		},
+		{
+			name: "valid action group ID",
+			alerts: &AlertsSpec{
+				ActionGroupID: "/subscriptions/123/resourceGroups/ops/providers/microsoft.insights/actionGroups/oncall",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid action group ID",
+			alerts: &AlertsSpec{
+				ActionGroupID: "/subscriptions/123/resourceGroups/ops/providers/Microsoft.Network/dnszones/example.com",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "missing action group ID",
+			alerts:  &AlertsSpec{},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateAlerts(testCase.alerts, field.NewPath("alerts"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateExpressRouteGateway(t *testing.T) {
+	g := NewWithT(t)
+
+	vnet := VnetSpec{CIDRBlocks: []string{DefaultVnetCIDR}}
+	gatewaySubnet := SubnetSpec{Name: ExpressRouteGatewaySubnetName, CIDRBlocks: []string{DefaultExpressRouteGatewaySubnetCIDR}}
+	tests := []struct {
+		name    string
+		gateway *ExpressRouteGateway
+		wantErr bool
+	}{
+		{
+			name:    "no gateway",
+			gateway: nil,
+			wantErr: false,
+		},
+		{
+			name:    "existing gateway",
+			gateway: &ExpressRouteGateway{ID: "/subscriptions/123/resourceGroups/hub/providers/Microsoft.Network/virtualNetworkGateways/my-ergw"},
+			wantErr: false,
+		},
+		{
+			name:    "managed gateway",
+			gateway: &ExpressRouteGateway{Name: "my-ergw", Subnet: gatewaySubnet},
+			wantErr: false,
+		},
+		{
+			name:    "empty gateway",
+			gateway: &ExpressRouteGateway{},
+			wantErr: true,
+		},
+		{
+			name:    "both id and name",
+			gateway: &ExpressRouteGateway{ID: "/subscriptions/123/resourceGroups/hub/providers/Microsoft.Network/virtualNetworkGateways/my-ergw", Name: "my-ergw", Subnet: gatewaySubnet},
+			wantErr: true,
+		},
+		{
+			name:    "id of another resource type",
+			gateway: &ExpressRouteGateway{ID: "/subscriptions/123/resourceGroups/hub/providers/Microsoft.Network/virtualNetworks/my-vnet"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			gateway: &ExpressRouteGateway{Name: "my ergw", Subnet: gatewaySubnet},
+			wantErr: true,
+		},
+		{
+			name:    "subnet not named GatewaySubnet",
+			gateway: &ExpressRouteGateway{Name: "my-ergw", Subnet: SubnetSpec{Name: "my-subnet", CIDRBlocks: []string{DefaultExpressRouteGatewaySubnetCIDR}}},
+			wantErr: true,
+		},
+		{
+			name: "subnet with a security group",
+			gateway: &ExpressRouteGateway{Name: "my-ergw", Subnet: SubnetSpec{
+				Name:          ExpressRouteGatewaySubnetName,
+				CIDRBlocks:    []string{DefaultExpressRouteGatewaySubnetCIDR},
+				SecurityGroup: SecurityGroup{Name: "my-nsg"},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "subnet outside of the vnet",
+			gateway: &ExpressRouteGateway{Name: "my-ergw", Subnet: SubnetSpec{Name: ExpressRouteGatewaySubnetName, CIDRBlocks: []string{"192.168.0.0/27"}}},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateExpressRouteGateway(testCase.gateway, vnet, field.NewPath("expressRouteGateway"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateFirewall(t *testing.T) {
+	g := NewWithT(t)
+
+	vnet := VnetSpec{CIDRBlocks: []string{DefaultVnetCIDR}}
+	firewallSubnet := SubnetSpec{Name: AzureFirewallSubnetName, CIDRBlocks: []string{DefaultAzureFirewallSubnetCIDR}}
+	tests := []struct {
+		name     string
+		firewall *AzureFirewall
+		wantErr  bool
+	}{
+		{
+			name:     "no firewall",
+			firewall: nil,
+			wantErr:  false,
+		},
+		{
+			name:     "existing firewall",
+			firewall: &AzureFirewall{ID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-azfw"},
+			wantErr:  false,
+		},
+		{
+			name: "created firewall",
+			firewall: &AzureFirewall{
+				Name:     "my-azfw",
+				PolicyID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/firewallPolicies/my-policy",
+				Subnet:   firewallSubnet,
+			},
+			wantErr: false,
+		},
+		{
+			name:     "empty firewall",
+			firewall: &AzureFirewall{},
+			wantErr:  true,
+		},
+		{
+			name:     "both id and name",
+			firewall: &AzureFirewall{ID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-azfw", Name: "my-azfw", Subnet: firewallSubnet},
+			wantErr:  true,
+		},
+		{
+			name:     "id of another resource type",
+			firewall: &AzureFirewall{ID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/virtualNetworkGateways/hub-ergw"},
+			wantErr:  true,
+		},
+		{
+			name: "policy of an existing firewall",
+			firewall: &AzureFirewall{
+				ID:       "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-azfw",
+				PolicyID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/firewallPolicies/my-policy",
+			},
+			wantErr: true,
+		},
+		{
+			name:     "invalid policy id",
+			firewall: &AzureFirewall{Name: "my-azfw", PolicyID: "my-policy", Subnet: firewallSubnet},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid name",
+			firewall: &AzureFirewall{Name: "my azfw", Subnet: firewallSubnet},
+			wantErr:  true,
+		},
+		{
+			name:     "subnet with another name",
+			firewall: &AzureFirewall{Name: "my-azfw", Subnet: SubnetSpec{Name: "my-subnet", CIDRBlocks: []string{DefaultAzureFirewallSubnetCIDR}}},
+			wantErr:  true,
+		},
+		{
+			name: "subnet with a security group",
+			firewall: &AzureFirewall{Name: "my-azfw", Subnet: SubnetSpec{
+				Name:          AzureFirewallSubnetName,
+				CIDRBlocks:    []string{DefaultAzureFirewallSubnetCIDR},
+				SecurityGroup: SecurityGroup{Name: "my-nsg"},
+			}},
+			wantErr: true,
+		},
+		{
+			name:     "subnet smaller than a /26",
+			firewall: &AzureFirewall{Name: "my-azfw", Subnet: SubnetSpec{Name: AzureFirewallSubnetName, CIDRBlocks: []string{"10.255.255.128/27"}}},
+			wantErr:  true,
+		},
+		{
+			name:     "subnet outside of the vnet",
+			firewall: &AzureFirewall{Name: "my-azfw", Subnet: SubnetSpec{Name: AzureFirewallSubnetName, CIDRBlocks: []string{"192.168.0.0/26"}}},
+			wantErr:  true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateFirewall(testCase.firewall, vnet, field.NewPath("firewall"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateAzureBastion(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		bastion *AzureBastion
+		wantErr bool
+	}{
+		{
+			name:    "no bastion",
+			bastion: nil,
+			wantErr: false,
+		},
+		{
+			name:    "basic sku",
+			bastion: &AzureBastion{Sku: BastionHostSkuBasic},
+			wantErr: false,
+		},
+		{
+			name: "standard sku with all the features",
+			bastion: &AzureBastion{
+				Sku:             BastionHostSkuStandard,
+				ScaleUnits:      pointer.Int32(10),
+				EnableTunneling: true,
+				EnableIPConnect: true,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "basic sku with scale units",
+			bastion: &AzureBastion{Sku: BastionHostSkuBasic, ScaleUnits: pointer.Int32(2)},
+			wantErr: true,
+		},
+		{
+			name:    "basic sku with tunneling",
+			bastion: &AzureBastion{Sku: BastionHostSkuBasic, EnableTunneling: true},
+			wantErr: true,
+		},
+		{
+			name:    "basic sku with ip connect",
+			bastion: &AzureBastion{Sku: BastionHostSkuBasic, EnableIPConnect: true},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateAzureBastion(testCase.bastion, field.NewPath("azureBastion"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateAPIVersionProfile(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		profile *APIVersionProfile
+		wantErr bool
+	}{
+		{
+			name:    "no profile",
+			profile: nil,
+			wantErr: false,
+		},
+		{
+			name: "valid overrides",
+			profile: &APIVersionProfile{
+				Name: APIVersionProfileHybrid20190301,
+				Overrides: map[string]string{
+					"Microsoft.Network":                          "2018-11-01",
+					"Microsoft.Network/networkWatchers/flowLogs": "2019-11-01",
+					"Microsoft.ContainerService":                 "2021-03-01-preview",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "resource type without namespace",
+			profile: &APIVersionProfile{Overrides: map[string]string{"loadBalancers": "2018-11-01"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid api version",
+			profile: &APIVersionProfile{Overrides: map[string]string{"Microsoft.Network": "v2018"}},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateAPIVersionProfile(testCase.profile, field.NewPath("apiVersionProfile"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateAzureEnvironmentEndpoints(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name      string
+		endpoints *AzureEnvironmentEndpoints
+		wantErr   bool
+	}{
+		{
+			name:      "no endpoints",
+			endpoints: nil,
+			wantErr:   false,
+		},
+		{
+			name: "valid endpoints",
+			endpoints: &AzureEnvironmentEndpoints{
+				ResourceManagerEndpoint:    "https://management.local.azurestack.external/",
+				ActiveDirectoryEndpoint:    "https://login.microsoftonline.com/",
+				ResourceManagerVMDNSSuffix: "cloudapp.local.azurestack.external",
+				TokenAudience:              "https://management.azurestack.example.com/1234",
+			},
+			wantErr: false,
+		},
+		{
+			name: "http resource manager endpoint",
+			endpoints: &AzureEnvironmentEndpoints{
+				ResourceManagerEndpoint:    "http://management.local.azurestack.external/",
+				ActiveDirectoryEndpoint:    "https://login.microsoftonline.com/",
+				ResourceManagerVMDNSSuffix: "cloudapp.local.azurestack.external",
+			},
+			wantErr: true,
+		},
+		{
+			name: "relative active directory endpoint",
+			endpoints: &AzureEnvironmentEndpoints{
+				ResourceManagerEndpoint:    "https://management.local.azurestack.external/",
+				ActiveDirectoryEndpoint:    "login.microsoftonline.com",
+				ResourceManagerVMDNSSuffix: "cloudapp.local.azurestack.external",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid token audience",
+			endpoints: &AzureEnvironmentEndpoints{
+				ResourceManagerEndpoint:    "https://management.local.azurestack.external/",
+				ActiveDirectoryEndpoint:    "https://login.microsoftonline.com/",
+				ResourceManagerVMDNSSuffix: "cloudapp.local.azurestack.external",
+				TokenAudience:              "management",
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing dns suffix",
+			endpoints: &AzureEnvironmentEndpoints{
+				ResourceManagerEndpoint: "https://management.local.azurestack.external/",
+				ActiveDirectoryEndpoint: "https://login.microsoftonline.com/",
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateAzureEnvironmentEndpoints(testCase.endpoints, field.NewPath("azureEnvironmentEndpoints"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateProxyConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	trustedCA := generateTestCertificate(g)
+
+	tests := []struct {
+		name        string
+		proxyConfig *ProxyConfig
+		wantErr     bool
+	}{
+		{
+			name:        "no proxy config",
+			proxyConfig: nil,
+			wantErr:     false,
+		},
+		{
+			name: "valid proxy config",
+			proxyConfig: &ProxyConfig{
+				HTTPProxy:  "http://proxy.example.com:3128",
+				HTTPSProxy: "https://proxy.example.com:3129",
+				NoProxy:    []string{"localhost", ".example.com", "10.0.0.0/8"},
+				TrustedCA:  trustedCA,
+			},
+			wantErr: false,
+		},
+		{
+			name:        "no proxy servers",
+			proxyConfig: &ProxyConfig{NoProxy: []string{"localhost"}},
+			wantErr:     true,
+		},
+		{
+			name:        "proxy without scheme",
+			proxyConfig: &ProxyConfig{HTTPProxy: "proxy.example.com:3128"},
+			wantErr:     true,
+		},
+		{
+			name:        "proxy with unsupported scheme",
+			proxyConfig: &ProxyConfig{HTTPSProxy: "socks5://proxy.example.com:1080"},
+			wantErr:     true,
+		},
+		{
+			name: "comma separated no proxy entry",
+			proxyConfig: &ProxyConfig{
+				HTTPProxy: "http://proxy.example.com:3128",
+				NoProxy:   []string{"localhost,127.0.0.1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty no proxy entry",
+			proxyConfig: &ProxyConfig{
+				HTTPProxy: "http://proxy.example.com:3128",
+				NoProxy:   []string{""},
+			},
+			wantErr: true,
+		},
+		{
+			name: "trusted CA is not PEM",
+			proxyConfig: &ProxyConfig{
+				HTTPProxy: "http://proxy.example.com:3128",
+				TrustedCA: "not a certificate",
+			},
+			wantErr: true,
+		},
+		{
+			name: "trusted CA is not a certificate",
+			proxyConfig: &ProxyConfig{
+				HTTPProxy: "http://proxy.example.com:3128",
+				TrustedCA: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")})),
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := ValidateProxyConfig(testCase.proxyConfig, field.NewPath("proxyConfig"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateTrustedCAs(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name       string
+		trustedCAs *TrustedCASource
+		wantErr    bool
+	}{
+		{
+			name:       "no trusted CAs",
+			trustedCAs: nil,
+			wantErr:    false,
+		},
+		{
+			name: "valid trusted CAs",
+			trustedCAs: &TrustedCASource{
+				SecretName: "my-cas",
+				Registries: []string{"registry.example.com", "registry.example.com:5000"},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "missing secret name",
+			trustedCAs: &TrustedCASource{Key: "ca.crt"},
+			wantErr:    true,
+		},
+		{
+			name: "registry with a scheme",
+			trustedCAs: &TrustedCASource{
+				SecretName: "my-cas",
+				Registries: []string{"https://registry.example.com"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty registry",
+			trustedCAs: &TrustedCASource{
+				SecretName: "my-cas",
+				Registries: []string{""},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateTrustedCAs(testCase.trustedCAs, field.NewPath("trustedCAs"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateRegistryMirrors(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		mirrors []RegistryMirror
+		wantErr bool
+	}{
+		{
+			name:    "no registry mirrors",
+			mirrors: nil,
+			wantErr: false,
+		},
+		{
+			name: "valid registry mirrors",
+			mirrors: []RegistryMirror{
+				{
+					Registry:              "docker.io",
+					Endpoints:             []string{"https://mirror.example.com:5000", "http://10.0.0.4:5000"},
+					CredentialsSecretName: "mirror-credentials",
+				},
+				{
+					Registry:  "registry.k8s.io",
+					Endpoints: []string{"https://mirror.example.com:5000/v2/registry.k8s.io"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "registry with a scheme",
+			mirrors: []RegistryMirror{{Registry: "https://docker.io", Endpoints: []string{"https://mirror.example.com"}}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate registry",
+			mirrors: []RegistryMirror{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}},
+				{Registry: "docker.io", Endpoints: []string{"https://other-mirror.example.com"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no endpoints",
+			mirrors: []RegistryMirror{{Registry: "docker.io"}},
+			wantErr: true,
+		},
+		{
+			name:    "endpoint without a scheme",
+			mirrors: []RegistryMirror{{Registry: "docker.io", Endpoints: []string{"mirror.example.com"}}},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateRegistryMirrors(testCase.mirrors, field.NewPath("registryMirrors"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+// generateTestCertificate returns a PEM encoded self-signed certificate.
+func generateTestCertificate(g *WithT) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "proxy-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	g.Expect(err).NotTo(HaveOccurred())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestValidateFlowLogs(t *testing.T) {
+	g := NewWithT(t)
+
+	storageAccountID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/mystorage"
+	workspaceResourceID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-workspace"
+
+	tests := []struct {
+		name     string
+		flowLogs *FlowLogsSpec
+		wantErr  bool
+	}{
+		{
+			name:     "no flow logs",
+			flowLogs: nil,
+			wantErr:  false,
+		},
+		{
+			name:     "storage account only",
+			flowLogs: &FlowLogsSpec{StorageAccountID: storageAccountID},
+			wantErr:  false,
+		},
+		{
+			name: "with traffic analytics and network watcher",
+			flowLogs: &FlowLogsSpec{
+				StorageAccountID: storageAccountID,
+				TrafficAnalytics: &TrafficAnalyticsSpec{
+					WorkspaceResourceID: workspaceResourceID,
+					WorkspaceID:         "00000000-1111-2222-3333-444444444444",
+				},
+				NetworkWatcher: &NetworkWatcherSpec{Name: "NetworkWatcher_westus2", ResourceGroup: "NetworkWatcherRG"},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "missing storage account",
+			flowLogs: &FlowLogsSpec{},
+			wantErr:  true,
+		},
+		{
+			name:     "id of another resource type",
+			flowLogs: &FlowLogsSpec{StorageAccountID: workspaceResourceID},
+			wantErr:  true,
+		},
+		{
+			name: "invalid workspace id",
+			flowLogs: &FlowLogsSpec{
+				StorageAccountID: storageAccountID,
+				TrafficAnalytics: &TrafficAnalyticsSpec{
+					WorkspaceResourceID: workspaceResourceID,
+					WorkspaceID:         "my-workspace",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "network watcher without name",
+			flowLogs: &FlowLogsSpec{
+				StorageAccountID: storageAccountID,
+				NetworkWatcher:   &NetworkWatcherSpec{ResourceGroup: "NetworkWatcherRG"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateFlowLogs(testCase.flowLogs, field.NewPath("flowLogs"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidatePrivateEndpoints(t *testing.T) {
+	g := NewWithT(t)
+
+	registryID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerRegistry/registries/myregistry"
+	zoneID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/privateDnsZones/privatelink.azurecr.io"
+
+	tests := []struct {
+		name             string
+		privateEndpoints []PrivateEndpointSpec
+		existingNames    []string
+		wantErr          bool
+	}{
+		{
+			name:             "no private endpoints",
+			privateEndpoints: nil,
+			wantErr:          false,
+		},
+		{
+			name: "valid private endpoint",
+			privateEndpoints: []PrivateEndpointSpec{
+				{
+					Name:                 "my-registry-pe",
+					PrivateLinkServiceID: registryID,
+					GroupIDs:             []string{"registry"},
+					PrivateDNSZoneGroup: &PrivateDNSZoneGroupSpec{
+						Name:              "default",
+						PrivateDNSZoneIDs: []string{zoneID},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid name",
+			privateEndpoints: []PrivateEndpointSpec{
+				{Name: "my-registry-pe.", PrivateLinkServiceID: registryID},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate name in the same subnet",
+			privateEndpoints: []PrivateEndpointSpec{
+				{Name: "my-registry-pe", PrivateLinkServiceID: registryID},
+				{Name: "my-registry-pe", PrivateLinkServiceID: registryID},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate name in another subnet",
+			privateEndpoints: []PrivateEndpointSpec{
+				{Name: "my-registry-pe", PrivateLinkServiceID: registryID},
+			},
+			existingNames: []string{"my-registry-pe"},
+			wantErr:       true,
+		},
+		{
+			name: "invalid private link service id",
+			privateEndpoints: []PrivateEndpointSpec{
+				{Name: "my-registry-pe", PrivateLinkServiceID: "myregistry"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "private DNS zone group without zones",
+			privateEndpoints: []PrivateEndpointSpec{
+				{
+					Name:                 "my-registry-pe",
+					PrivateLinkServiceID: registryID,
+					PrivateDNSZoneGroup:  &PrivateDNSZoneGroupSpec{Name: "default"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid private DNS zone id",
+			privateEndpoints: []PrivateEndpointSpec{
+				{
+					Name:                 "my-registry-pe",
+					PrivateLinkServiceID: registryID,
+					PrivateDNSZoneGroup: &PrivateDNSZoneGroupSpec{
+						Name:              "default",
+						PrivateDNSZoneIDs: []string{registryID},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			names := make(map[string]bool)
+			for _, name := range testCase.existingNames {
+				names[name] = true
+			}
+			err := validatePrivateEndpoints(testCase.privateEndpoints, names, field.NewPath("privateEndpoints"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateDiskEncryptionSets(t *testing.T) {
+	g := NewWithT(t)
+
+	keyURL := "https://my-kv.vault.azure.net/keys/my-key/0123456789abcdef"
+	keyVaultID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-kv"
+
+	tests := []struct {
+		name               string
+		diskEncryptionSets []DiskEncryptionSetSpec
+		wantErr            bool
+	}{
+		{
+			name:               "no disk encryption sets",
+			diskEncryptionSets: nil,
+			wantErr:            false,
+		},
+		{
+			name: "valid disk encryption set",
+			diskEncryptionSets: []DiskEncryptionSetSpec{
+				{Name: "my-des", KeyURL: keyURL, KeyVaultID: keyVaultID},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid name",
+			diskEncryptionSets: []DiskEncryptionSetSpec{
+				{Name: "my.des", KeyURL: keyURL, KeyVaultID: keyVaultID},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate name",
+			diskEncryptionSets: []DiskEncryptionSetSpec{
+				{Name: "my-des", KeyURL: keyURL, KeyVaultID: keyVaultID},
+				{Name: "my-des", KeyURL: keyURL, KeyVaultID: keyVaultID},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unversioned key URL",
+			diskEncryptionSets: []DiskEncryptionSetSpec{
+				{Name: "my-des", KeyURL: "https://my-kv.vault.azure.net/keys/my-key", KeyVaultID: keyVaultID},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid key vault id",
+			diskEncryptionSets: []DiskEncryptionSetSpec{
+				{Name: "my-des", KeyURL: keyURL, KeyVaultID: "my-kv"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateDiskEncryptionSets(testCase.diskEncryptionSets, field.NewPath("diskEncryptionSets"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateProximityPlacementGroup(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name                    string
+		proximityPlacementGroup *ProximityPlacementGroupSpec
+		wantErr                 bool
+	}{
+		{
+			name:                    "no proximity placement group",
+			proximityPlacementGroup: nil,
+			wantErr:                 false,
+		},
+		{
+			name:                    "valid name",
+			proximityPlacementGroup: &ProximityPlacementGroupSpec{Name: "my-cluster.ppg_1"},
+			wantErr:                 false,
+		},
+		{
+			name:                    "name ending with a period",
+			proximityPlacementGroup: &ProximityPlacementGroupSpec{Name: "my-ppg."},
+			wantErr:                 true,
+		},
+		{
+			name:                    "name too long",
+			proximityPlacementGroup: &ProximityPlacementGroupSpec{Name: strings.Repeat("a", 81)},
+			wantErr:                 true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateProximityPlacementGroup(testCase.proximityPlacementGroup, field.NewPath("proximityPlacementGroup"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateAPIServerPrivateLinkService(t *testing.T) {
+	g := NewWithT(t)
+
+	internalLB := LoadBalancerSpec{Type: Internal}
+
+	tests := []struct {
+		name        string
+		networkSpec NetworkSpec
+		wantErr     bool
+	}{
+		{
+			name:        "no private link service",
+			networkSpec: NetworkSpec{APIServerLB: LoadBalancerSpec{Type: Public}},
+			wantErr:     false,
+		},
+		{
+			name: "valid private link service",
+			networkSpec: NetworkSpec{
+				APIServerLB: internalLB,
+				APIServerPrivateLinkService: &PrivateLinkServiceSpec{
+					Name:         "my-cluster-internal-lb-pls",
+					Visibility:   []string{"*"},
+					AutoApproval: []string{"00000000-1111-2222-3333-444444444444"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "public API server load balancer",
+			networkSpec: NetworkSpec{
+				APIServerLB:                 LoadBalancerSpec{Type: Public},
+				APIServerPrivateLinkService: &PrivateLinkServiceSpec{Name: "my-cluster-public-lb-pls"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid name",
+			networkSpec: NetworkSpec{
+				APIServerLB:                 internalLB,
+				APIServerPrivateLinkService: &PrivateLinkServiceSpec{Name: "my-pls."},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid visibility",
+			networkSpec: NetworkSpec{
+				APIServerLB: internalLB,
+				APIServerPrivateLinkService: &PrivateLinkServiceSpec{
+					Name:       "my-cluster-internal-lb-pls",
+					Visibility: []string{"my-subscription"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "wildcard auto approval",
+			networkSpec: NetworkSpec{
+				APIServerLB: internalLB,
+				APIServerPrivateLinkService: &PrivateLinkServiceSpec{
+					Name:         "my-cluster-internal-lb-pls",
+					AutoApproval: []string{"*"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateAPIServerPrivateLinkService(testCase.networkSpec, field.NewPath("apiServerPrivateLinkService"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
 func TestSubnetsValid(t *testing.T) {
 	g := NewWithT(t)
 
@@ -657,6 +1950,80 @@ func TestValidateSecurityRule(t *testing.T) {
 	}
 }
 
+func TestValidateSecurityGroup(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		sg      SecurityGroup
+		role    SubnetRole
+		wantErr bool
+	}{
+		{
+			name: "valid security group",
+			sg: SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "allow_port_50000", Direction: SecurityRuleDirectionInbound, Priority: 100},
+					{Name: "deny_outbound", Direction: SecurityRuleDirectionOutbound, Priority: 100},
+				},
+				DisabledDefaultRules: []string{SecurityRuleAllowSSH},
+			},
+			role:    SubnetControlPlane,
+			wantErr: false,
+		},
+		{
+			name: "duplicate priority in the same direction",
+			sg: SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "allow_port_50000", Direction: SecurityRuleDirectionInbound, Priority: 100},
+					{Name: "allow_port_50001", Direction: SecurityRuleDirectionInbound, Priority: 100},
+				},
+			},
+			role:    SubnetNode,
+			wantErr: true,
+		},
+		{
+			name: "unknown disabled default rule",
+			sg: SecurityGroup{
+				DisabledDefaultRules: []string{"allow_rdp"},
+			},
+			role:    SubnetControlPlane,
+			wantErr: true,
+		},
+		{
+			name: "disabled default rule on a node subnet",
+			sg: SecurityGroup{
+				DisabledDefaultRules: []string{SecurityRuleAllowSSH},
+			},
+			role:    SubnetNode,
+			wantErr: true,
+		},
+		{
+			name: "default rule both declared and disabled",
+			sg: SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: SecurityRuleAllowSSH, Direction: SecurityRuleDirectionInbound, Priority: 100},
+				},
+				DisabledDefaultRules: []string{SecurityRuleAllowSSH},
+			},
+			role:    SubnetControlPlane,
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateSecurityGroup(testCase.sg, testCase.role, field.NewPath("spec").Child("networkSpec").Child("subnets").Index(0).Child("securityGroup"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
 func TestValidateAPIServerLB(t *testing.T) {
 	g := NewWithT(t)
 
@@ -921,6 +2288,302 @@ func TestPrivateDNSZoneName(t *testing.T) {
 	}
 }
 
+func TestPrivateDNSZone(t *testing.T) {
+	g := NewWithT(t)
+
+	zoneID := "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/privateDnsZones/good.dns.io"
+	testcases := []struct {
+		name        string
+		network     NetworkSpec
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name: "testValidPrivateDNSZone",
+			network: NetworkSpec{
+				PrivateDNSZone: &PrivateDNSZoneSpec{ID: zoneID},
+				APIServerLB:    createValidAPIServerInternalLB(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "testInvalidPrivateDNSZoneID",
+			network: NetworkSpec{
+				PrivateDNSZone: &PrivateDNSZoneSpec{ID: "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/dnsZones/good.dns.io"},
+				APIServerLB:    createValidAPIServerInternalLB(),
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.privateDNSZone.id",
+				BadValue: "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/dnsZones/good.dns.io",
+				Detail:   fmt.Sprintf("privateDNSZoneID doesn't match regex %s", privateDNSZoneIDRegex),
+			},
+			wantErr: true,
+		},
+		{
+			name: "testPrivateDNSZoneWithPrivateDNSZoneName",
+			network: NetworkSpec{
+				PrivateDNSZone:     &PrivateDNSZoneSpec{ID: zoneID},
+				PrivateDNSZoneName: "good.dns.io",
+				APIServerLB:        createValidAPIServerInternalLB(),
+			},
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "spec.networkSpec.privateDNSZone",
+				Detail: "PrivateDNSZone cannot be set together with PrivateDNSZoneName",
+			},
+			wantErr: true,
+		},
+		{
+			name: "testPrivateDNSZoneBadAPIServerLBType",
+			network: NetworkSpec{
+				PrivateDNSZone: &PrivateDNSZoneSpec{ID: zoneID},
+				APIServerLB: LoadBalancerSpec{
+					Name: "my-lb",
+					Type: Public,
+				},
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.privateDNSZone",
+				BadValue: "Public",
+				Detail:   "PrivateDNSZone is available only if APIServerLB.Type is Internal",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testcases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			err := validatePrivateDNSZone(test.network, field.NewPath("spec", "networkSpec", "privateDNSZone"))
+			if test.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(test.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidatePrivateDNSRecords(t *testing.T) {
+	g := NewWithT(t)
+
+	testcases := []struct {
+		name        string
+		network     NetworkSpec
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name: "valid A, AAAA and CNAME records",
+			network: NetworkSpec{
+				APIServerLB: createValidAPIServerInternalLB(),
+				PrivateDNSRecords: []PrivateDNSRecord{
+					{Hostname: "registry", IP: "10.0.3.4"},
+					{Hostname: "registry-v6", IP: "2001:1234:5678:9abd::5"},
+					{Hostname: "storage", CNAME: "mystorageaccount.privatelink.blob.core.windows.net"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "records of a public cluster",
+			network: NetworkSpec{
+				APIServerLB: LoadBalancerSpec{
+					Name: "my-lb",
+					Type: Public,
+				},
+				PrivateDNSRecords: []PrivateDNSRecord{{Hostname: "registry", IP: "10.0.3.4"}},
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.privateDNSRecords",
+				BadValue: "Public",
+				Detail:   "PrivateDNSRecords are available only if APIServerLB.Type is Internal",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid hostname",
+			network: NetworkSpec{
+				APIServerLB:       createValidAPIServerInternalLB(),
+				PrivateDNSRecords: []PrivateDNSRecord{{Hostname: "wrong@hostname", IP: "10.0.3.4"}},
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.privateDNSRecords[0].hostname",
+				BadValue: "wrong@hostname",
+				Detail:   "hostname can only contain alphanumeric characters, underscores and dashes, must end with an alphanumeric character",
+			},
+			wantErr: true,
+		},
+		{
+			name: "hostname of the API server record",
+			network: NetworkSpec{
+				APIServerLB:       createValidAPIServerInternalLB(),
+				PrivateDNSRecords: []PrivateDNSRecord{{Hostname: "apiserver.my-cluster", IP: "10.0.3.4"}},
+			},
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "spec.networkSpec.privateDNSRecords[0].hostname",
+				Detail: "hostname is reserved for the API server record",
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate hostnames",
+			network: NetworkSpec{
+				APIServerLB: createValidAPIServerInternalLB(),
+				PrivateDNSRecords: []PrivateDNSRecord{
+					{Hostname: "registry", IP: "10.0.3.4"},
+					{Hostname: "registry", CNAME: "registry.example.com"},
+				},
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueDuplicate",
+				Field:    "spec.networkSpec.privateDNSRecords[1].hostname",
+				BadValue: "registry",
+			},
+			wantErr: true,
+		},
+		{
+			name: "neither ip nor cname",
+			network: NetworkSpec{
+				APIServerLB:       createValidAPIServerInternalLB(),
+				PrivateDNSRecords: []PrivateDNSRecord{{Hostname: "registry"}},
+			},
+			expectedErr: field.Error{
+				Type:   "FieldValueRequired",
+				Field:  "spec.networkSpec.privateDNSRecords[0]",
+				Detail: "one of ip or cname must be set",
+			},
+			wantErr: true,
+		},
+		{
+			name: "both ip and cname",
+			network: NetworkSpec{
+				APIServerLB:       createValidAPIServerInternalLB(),
+				PrivateDNSRecords: []PrivateDNSRecord{{Hostname: "registry", IP: "10.0.3.4", CNAME: "registry.example.com"}},
+			},
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "spec.networkSpec.privateDNSRecords[0]",
+				Detail: "ip and cname cannot be set together",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid ip",
+			network: NetworkSpec{
+				APIServerLB:       createValidAPIServerInternalLB(),
+				PrivateDNSRecords: []PrivateDNSRecord{{Hostname: "registry", IP: "10.0.3"}},
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.privateDNSRecords[0].ip",
+				BadValue: "10.0.3",
+				Detail:   "ip must be a valid IPv4 or IPv6 address",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testcases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			err := validatePrivateDNSRecords(test.network, field.NewPath("spec", "networkSpec", "privateDNSRecords"))
+			if test.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(test.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateOutboundConflicts(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlaneSubnet := SubnetSpec{Name: "control-plane-subnet", Role: SubnetControlPlane}
+	nodeSubnet := SubnetSpec{Name: "node-subnet", Role: SubnetNode}
+	nodeSubnetWithNatGateway := SubnetSpec{Name: "node-subnet-natgw", Role: SubnetNode, NatGateway: NatGateway{Name: "node-natgw"}}
+	tests := []struct {
+		name        string
+		networkSpec NetworkSpec
+		wantErr     string
+	}{
+		{
+			name: "node outbound load balancer",
+			networkSpec: NetworkSpec{
+				Subnets:        Subnets{controlPlaneSubnet, nodeSubnet},
+				NodeOutboundLB: &LoadBalancerSpec{Name: "my-cluster"},
+			},
+		},
+		{
+			name: "NAT gateways on all node subnets",
+			networkSpec: NetworkSpec{
+				Subnets: Subnets{controlPlaneSubnet, nodeSubnetWithNatGateway},
+			},
+		},
+		{
+			name: "node outbound load balancer for the node subnets without NAT gateway",
+			networkSpec: NetworkSpec{
+				Subnets:        Subnets{controlPlaneSubnet, nodeSubnet, nodeSubnetWithNatGateway},
+				NodeOutboundLB: &LoadBalancerSpec{Name: "my-cluster"},
+			},
+		},
+		{
+			name: "firewall without NAT gateway",
+			networkSpec: NetworkSpec{
+				Subnets:        Subnets{controlPlaneSubnet, nodeSubnet},
+				NodeOutboundLB: &LoadBalancerSpec{Name: "my-cluster"},
+				Firewall:       &AzureFirewall{Name: "my-azfw"},
+			},
+		},
+		{
+			name: "NAT gateway on the control plane subnet",
+			networkSpec: NetworkSpec{
+				Subnets: Subnets{
+					{Name: "control-plane-subnet", Role: SubnetControlPlane, NatGateway: NatGateway{Name: "control-plane-natgw"}},
+					nodeSubnetWithNatGateway,
+				},
+			},
+			wantErr: "networkSpec.subnets[0].natGateway: Forbidden: control plane subnet control-plane-subnet can't have a NAT gateway",
+		},
+		{
+			name: "node outbound load balancer with NAT gateways on all node subnets",
+			networkSpec: NetworkSpec{
+				Subnets:        Subnets{controlPlaneSubnet, nodeSubnetWithNatGateway},
+				NodeOutboundLB: &LoadBalancerSpec{Name: "my-cluster"},
+			},
+			wantErr: "networkSpec.nodeOutboundLB: Forbidden: node outbound load balancer can't be set when all the node subnets have a NAT gateway",
+		},
+		{
+			name: "firewall with a NAT gateway on a node subnet",
+			networkSpec: NetworkSpec{
+				Subnets:        Subnets{controlPlaneSubnet, nodeSubnet, nodeSubnetWithNatGateway},
+				NodeOutboundLB: &LoadBalancerSpec{Name: "my-cluster"},
+				Firewall:       &AzureFirewall{Name: "my-azfw"},
+			},
+			wantErr: "networkSpec.subnets[2].natGateway: Forbidden: node subnet node-subnet-natgw can't have a NAT gateway when the egress of the nodes is routed through an Azure Firewall",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateOutboundConflicts(testCase.networkSpec, field.NewPath("networkSpec"))
+			if testCase.wantErr != "" {
+				g.Expect(errs).To(HaveLen(1))
+				g.Expect(errs[0].Error()).To(HavePrefix(testCase.wantErr))
+			} else {
+				g.Expect(errs).To(HaveLen(0))
+			}
+		})
+	}
+}
+
 func TestValidateNodeOutboundLB(t *testing.T) {
 	g := NewWithT(t)
 
@@ -1222,6 +2885,15 @@ func TestValidateCloudProviderConfigOverrides(t *testing.T) {
 	}
 }
 
+func createValidPublicDNSRecord() *PublicDNSRecordSpec {
+	return &PublicDNSRecordSpec{
+		ZoneID: "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/dnszones/example.com",
+		Name:   "api.cluster1",
+		Type:   PublicDNSRecordTypeCNAME,
+		TTL:    pointer.Int64(300),
+	}
+}
+
 func createValidCluster() *AzureCluster {
 	return &AzureCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -92,6 +92,13 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) error {
 		}
 	}
 
+	if !reflect.DeepEqual(c.Spec.AzureEnvironmentEndpoints, old.Spec.AzureEnvironmentEndpoints) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "azureEnvironmentEndpoints"),
+				c.Spec.AzureEnvironmentEndpoints, "field is immutable"),
+		)
+	}
+
 	if !reflect.DeepEqual(c.Spec.NetworkSpec.PrivateDNSZoneName, old.Spec.NetworkSpec.PrivateDNSZoneName) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "NetworkSpec", "PrivateDNSZoneName"),
@@ -99,11 +106,49 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
-	// Allow enabling azure bastion but avoid disabling it.
-	if old.Spec.BastionSpec.AzureBastion != nil && !reflect.DeepEqual(old.Spec.BastionSpec.AzureBastion, c.Spec.BastionSpec.AzureBastion) {
+	if !reflect.DeepEqual(c.Spec.NetworkSpec.PrivateDNSZone, old.Spec.NetworkSpec.PrivateDNSZone) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "NetworkSpec", "PrivateDNSZone"),
+				c.Spec.NetworkSpec.PrivateDNSZone, "field is immutable"),
+		)
+	}
+
+	// Allow publishing the API server in a DNS zone later, but only its TTL can be updated afterwards.
+	if oldRecord, record := old.Spec.NetworkSpec.APIServerPublicDNS, c.Spec.NetworkSpec.APIServerPublicDNS; oldRecord != nil {
+		if record == nil || record.ZoneID != oldRecord.ZoneID || record.Name != oldRecord.Name || record.Type != oldRecord.Type {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "NetworkSpec", "APIServerPublicDNS"),
+					record, "the zone, name and type of the API server DNS record are immutable"),
+			)
+		}
+	}
+
+	// Allow enabling azure bastion but avoid disabling it. Only the SKU and the features of azure bastion can be updated.
+	if oldBastion, bastion := old.Spec.BastionSpec.AzureBastion, c.Spec.BastionSpec.AzureBastion; oldBastion != nil {
+		switch {
+		case bastion == nil:
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "BastionSpec", "AzureBastion"),
+					bastion, "azure bastion cannot be removed from a cluster"),
+			)
+		case bastion.Name != oldBastion.Name || !reflect.DeepEqual(bastion.Subnet, oldBastion.Subnet) || !reflect.DeepEqual(bastion.PublicIP, oldBastion.PublicIP):
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "BastionSpec", "AzureBastion"),
+					bastion, "the name, subnet and public IP of azure bastion are immutable"),
+			)
+		case oldBastion.Sku == BastionHostSkuStandard && bastion.Sku != BastionHostSkuStandard:
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "BastionSpec", "AzureBastion", "Sku"),
+					bastion.Sku, "azure bastion cannot be downgraded to the Basic SKU"),
+			)
+		}
+	}
+
+	// Allow routing the egress through a firewall, but not changing or removing it as the routes would still point to it.
+	if old.Spec.NetworkSpec.Firewall != nil && !reflect.DeepEqual(c.Spec.NetworkSpec.Firewall, old.Spec.NetworkSpec.Firewall) {
 		allErrs = append(allErrs,
-			field.Invalid(field.NewPath("spec", "BastionSpec", "AzureBastion"),
-				c.Spec.BastionSpec.AzureBastion, "azure bastion cannot be removed from a cluster"),
+			field.Invalid(field.NewPath("spec", "networkSpec", "firewall"),
+				c.Spec.NetworkSpec.Firewall, "field is immutable"),
 		)
 	}
 
@@ -114,6 +159,14 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	// Machines can't be moved in or out of a proximity placement group without being recreated.
+	if !reflect.DeepEqual(c.Spec.ProximityPlacementGroup, old.Spec.ProximityPlacementGroup) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "proximityPlacementGroup"),
+				c.Spec.ProximityPlacementGroup, "field is immutable"),
+		)
+	}
+
 	if len(allErrs) == 0 {
 		return c.validateCluster(old)
 	}
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
 )
 
 func TestAzureCluster_ValidateCreate(t *testing.T) {
@@ -244,6 +245,24 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			}(),
 			wantErr: false,
 		},
+		{
+			name: "azurecluster azureEnvironmentEndpoints is immutable",
+			oldCluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					AzureEnvironmentEndpoints: &AzureEnvironmentEndpoints{
+						ResourceManagerEndpoint: "https://management.local.azurestack.external/",
+					},
+				},
+			},
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					AzureEnvironmentEndpoints: &AzureEnvironmentEndpoints{
+						ResourceManagerEndpoint: "https://management.other.azurestack.external/",
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "control plane outbound lb is immutable",
 			oldCluster: &AzureCluster{
@@ -262,6 +281,173 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:       "proximity placement group can't be added",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.ProximityPlacementGroup = &ProximityPlacementGroupSpec{Name: "my-ppg"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "proximity placement group is immutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.ProximityPlacementGroup = &ProximityPlacementGroupSpec{Name: "my-ppg"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.ProximityPlacementGroup = &ProximityPlacementGroupSpec{Name: "other-ppg"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azure firewall can be added",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Firewall = &AzureFirewall{ID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-azfw"}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azure firewall is immutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Firewall = &AzureFirewall{ID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-azfw"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Firewall = &AzureFirewall{ID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/other-azfw"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azure firewall can't be removed",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Firewall = &AzureFirewall{ID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-azfw"}
+				return cluster
+			}(),
+			cluster: createValidCluster(),
+			wantErr: true,
+		},
+		{
+			name:       "API server public DNS record can be added",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerPublicDNS = createValidPublicDNSRecord()
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "API server public DNS record TTL can be updated",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerPublicDNS = createValidPublicDNSRecord()
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerPublicDNS = createValidPublicDNSRecord()
+				cluster.Spec.NetworkSpec.APIServerPublicDNS.TTL = pointer.Int64(60)
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "API server public DNS record name is immutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerPublicDNS = createValidPublicDNSRecord()
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerPublicDNS = createValidPublicDNSRecord()
+				cluster.Spec.NetworkSpec.APIServerPublicDNS.Name = "api.cluster2"
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "API server public DNS record can't be removed",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerPublicDNS = createValidPublicDNSRecord()
+				return cluster
+			}(),
+			cluster: createValidCluster(),
+			wantErr: true,
+		},
+		{
+			name: "azure bastion can be upgraded to the standard sku",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BastionSpec.AzureBastion = &AzureBastion{Name: "my-bastion", Sku: BastionHostSkuBasic}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BastionSpec.AzureBastion = &AzureBastion{
+					Name:            "my-bastion",
+					Sku:             BastionHostSkuStandard,
+					ScaleUnits:      pointer.Int32(4),
+					EnableTunneling: true,
+				}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azure bastion can't be downgraded to the basic sku",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BastionSpec.AzureBastion = &AzureBastion{Name: "my-bastion", Sku: BastionHostSkuStandard}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BastionSpec.AzureBastion = &AzureBastion{Name: "my-bastion", Sku: BastionHostSkuBasic}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azure bastion name is immutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BastionSpec.AzureBastion = &AzureBastion{Name: "my-bastion", Sku: BastionHostSkuBasic}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BastionSpec.AzureBastion = &AzureBastion{Name: "my-other-bastion", Sku: BastionHostSkuBasic}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azure bastion can't be removed",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BastionSpec.AzureBastion = &AzureBastion{Name: "my-bastion", Sku: BastionHostSkuBasic}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				return createValidCluster()
+			}(),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
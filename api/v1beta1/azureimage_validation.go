@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"net/url"
+
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -40,6 +42,12 @@ func ValidateImage(image *Image, fldPath *field.Path) field.ErrorList {
 	if image.ID != nil {
 		allErrs = append(allErrs, validateSpecifcImage(image, fldPath)...)
 	}
+	if image.CommunityGallery != nil {
+		allErrs = append(allErrs, validateCommunityGalleryImage(image, fldPath)...)
+	}
+	if image.VHD != nil {
+		allErrs = append(allErrs, validateVHDImage(image, fldPath)...)
+	}
 
 	return allErrs
 }
@@ -68,8 +76,24 @@ func validateSingleDetailsOnly(image *Image, fldPath *field.Path) field.ErrorLis
 		}
 	}
 
+	if image.CommunityGallery != nil {
+		if imageDetailsFound {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("CommunityGallery"), "CommunityGallery cannot be used as an image ID, Marketplace or SharedGallery images has been specified"))
+		} else {
+			imageDetailsFound = true
+		}
+	}
+
+	if image.VHD != nil {
+		if imageDetailsFound {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("VHD"), "VHD cannot be used as an image ID, Marketplace, SharedGallery or CommunityGallery images has been specified"))
+		} else {
+			imageDetailsFound = true
+		}
+	}
+
 	if !imageDetailsFound {
-		allErrs = append(allErrs, field.Required(fldPath, "You must supply a ID, Marketplace or SharedGallery image details"))
+		allErrs = append(allErrs, field.Required(fldPath, "You must supply a ID, Marketplace, SharedGallery, CommunityGallery or VHD image details"))
 	}
 
 	return allErrs
@@ -97,6 +121,34 @@ func validateSharedGalleryImage(image *Image, fldPath *field.Path) field.ErrorLi
 	return allErrs
 }
 
+func validateCommunityGalleryImage(image *Image, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if image.CommunityGallery.Gallery == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Gallery"), "", "Gallery cannot be empty when specifying an AzureCommunityGalleryImage"))
+	}
+	if image.CommunityGallery.Name == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Name"), "", "Name cannot be empty when specifying an AzureCommunityGalleryImage"))
+	}
+	if image.CommunityGallery.Version == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Version"), "", "Version cannot be empty when specifying an AzureCommunityGalleryImage"))
+	}
+
+	return allErrs
+}
+
+func validateVHDImage(image *Image, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if image.VHD.URI == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("URI"), "", "URI cannot be empty when specifying an AzureVHDImage"))
+	} else if u, err := url.Parse(image.VHD.URI); err != nil || u.Scheme != "https" || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("URI"), image.VHD.URI, "URI must be the https URI of a VHD blob when specifying an AzureVHDImage"))
+	}
+
+	return allErrs
+}
+
 func validateMarketplaceImage(image *Image, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
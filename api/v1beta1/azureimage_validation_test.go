@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -131,6 +132,76 @@ func TestMarketPlaceImageValid(t *testing.T) {
 	}
 }
 
+func TestCommunityGalleryImageValid(t *testing.T) {
+	g := NewWithT(t)
+
+	testCases := map[string]struct {
+		image          *Image
+		expectedErrors int
+	}{
+		"AzureCommunityGalleryImage - fully specified": {
+			expectedErrors: 0,
+			image:          createTestCommunityGalleryImage("GALLERY9876", "IMAGENAME", "1.0.0"),
+		},
+		"AzureCommunityGalleryImage - missing gallery": {
+			expectedErrors: 1,
+			image:          createTestCommunityGalleryImage("", "IMAGENAME", "1.0.0"),
+		},
+		"AzureCommunityGalleryImage - missing image name": {
+			expectedErrors: 1,
+			image:          createTestCommunityGalleryImage("GALLERY9876", "", "1.0.0"),
+		},
+		"AzureCommunityGalleryImage - missing version": {
+			expectedErrors: 1,
+			image:          createTestCommunityGalleryImage("GALLERY9876", "IMAGENAME", ""),
+		},
+		"AzureCommunityGalleryImage - with an image ID": {
+			expectedErrors: 1,
+			image: &Image{
+				ID:               to.StringPtr("ID1234"),
+				CommunityGallery: createTestCommunityGalleryImage("GALLERY9876", "IMAGENAME", "1.0.0").CommunityGallery,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		g.Expect(ValidateImage(tc.image, field.NewPath("image"))).To(HaveLen(tc.expectedErrors))
+	}
+}
+
+func TestVHDImageValid(t *testing.T) {
+	g := NewWithT(t)
+
+	testCases := map[string]struct {
+		image          *Image
+		expectedErrors int
+	}{
+		"AzureVHDImage - with URI": {
+			expectedErrors: 0,
+			image:          createTestVHDImage("https://myaccount.blob.core.windows.net/vhds/image.vhd"),
+		},
+		"AzureVHDImage - missing URI": {
+			expectedErrors: 1,
+			image:          createTestVHDImage(""),
+		},
+		"AzureVHDImage - URI without https scheme": {
+			expectedErrors: 1,
+			image:          createTestVHDImage("myaccount.blob.core.windows.net/vhds/image.vhd"),
+		},
+		"AzureVHDImage - with a marketplace image": {
+			expectedErrors: 1,
+			image: &Image{
+				Marketplace: createTestMarketPlaceImage("PUB1234", "OFFER1234", "SKU1234", "1.0.0").Marketplace,
+				VHD:         createTestVHDImage("https://myaccount.blob.core.windows.net/vhds/image.vhd").VHD,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		g.Expect(ValidateImage(tc.image, field.NewPath("image"))).To(HaveLen(tc.expectedErrors))
+	}
+}
+
 func TestImageByIDValid(t *testing.T) {
 	g := NewWithT(t)
 
@@ -176,6 +247,24 @@ func createTestMarketPlaceImage(publisher, offer, sku, version string) *Image {
 	}
 }
 
+func createTestCommunityGalleryImage(gallery, name, version string) *Image {
+	return &Image{
+		CommunityGallery: &AzureCommunityGalleryImage{
+			Gallery: gallery,
+			Name:    name,
+			Version: version,
+		},
+	}
+}
+
+func createTestVHDImage(uri string) *Image {
+	return &Image{
+		VHD: &AzureVHDImage{
+			URI: uri,
+		},
+	}
+}
+
 func createTestImageByID(imageID string) *Image {
 	return &Image{
 		ID: &imageID,
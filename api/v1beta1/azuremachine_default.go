@@ -21,6 +21,7 @@ import (
 
 	"golang.org/x/crypto/ssh"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	utilSSH "sigs.k8s.io/cluster-api-provider-azure/util/ssh"
@@ -70,7 +71,23 @@ func (s *AzureMachineSpec) SetDataDisksDefaults() {
 			}
 		}
 		if disk.CachingType == "" {
-			s.DataDisks[i].CachingType = "ReadWrite"
+			switch {
+			case disk.WriteAcceleratorEnabled != nil && *disk.WriteAcceleratorEnabled:
+				// Write Accelerator doesn't support caching writes.
+				s.DataDisks[i].CachingType = "None"
+			case disk.IsShared():
+				// Host caching isn't supported on shared disks.
+				s.DataDisks[i].CachingType = "None"
+			default:
+				s.DataDisks[i].CachingType = "ReadWrite"
+			}
+		}
+		if disk.DeleteOption == "" {
+			s.DataDisks[i].DeleteOption = DiskDeleteOptionDelete
+			// shared disks outlive the machines they are attached to.
+			if disk.IsShared() {
+				s.DataDisks[i].DeleteOption = DiskDeleteOptionDetach
+			}
 		}
 	}
 }
@@ -84,6 +101,22 @@ func (s *AzureMachineSpec) SetIdentityDefaults() {
 	}
 }
 
+// SetDefaults sets the defaults for the SecurityProfile, which enable both secure boot and vTPM for trusted launch.
+func (p *SecurityProfile) SetDefaults() {
+	if p == nil || p.SecurityType != SecurityTypesTrustedLaunch {
+		return
+	}
+	if p.UefiSettings == nil {
+		p.UefiSettings = &UefiSettings{}
+	}
+	if p.UefiSettings.SecureBootEnabled == nil {
+		p.UefiSettings.SecureBootEnabled = pointer.BoolPtr(true)
+	}
+	if p.UefiSettings.VTpmEnabled == nil {
+		p.UefiSettings.VTpmEnabled = pointer.BoolPtr(true)
+	}
+}
+
 // SetDefaults sets to the defaults for the AzureMachineSpec.
 func (s *AzureMachineSpec) SetDefaults() {
 	err := s.SetDefaultSSHPublicKey()
@@ -93,4 +126,5 @@ func (s *AzureMachineSpec) SetDefaults() {
 	s.SetDefaultCachingType()
 	s.SetDataDisksDefaults()
 	s.SetIdentityDefaults()
+	s.SecurityProfile.SetDefaults()
 }
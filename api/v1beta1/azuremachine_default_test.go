@@ -79,6 +79,26 @@ func TestAzureMachineSpec_SetIdentityDefaults(t *testing.T) {
 	g.Expect(notSystemAssignedTest.machine.Spec.RoleAssignmentName).To(BeEmpty())
 }
 
+func TestSecurityProfile_SetDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	trustedLaunch := &SecurityProfile{SecurityType: SecurityTypesTrustedLaunch}
+	trustedLaunch.SetDefaults()
+	g.Expect(trustedLaunch.UefiSettings).To(Equal(&UefiSettings{SecureBootEnabled: to.BoolPtr(true), VTpmEnabled: to.BoolPtr(true)}))
+
+	secureBootDisabled := &SecurityProfile{SecurityType: SecurityTypesTrustedLaunch, UefiSettings: &UefiSettings{SecureBootEnabled: to.BoolPtr(false)}}
+	secureBootDisabled.SetDefaults()
+	g.Expect(secureBootDisabled.UefiSettings).To(Equal(&UefiSettings{SecureBootEnabled: to.BoolPtr(false), VTpmEnabled: to.BoolPtr(true)}))
+
+	encryptionAtHost := &SecurityProfile{EncryptionAtHost: to.BoolPtr(true)}
+	encryptionAtHost.SetDefaults()
+	g.Expect(encryptionAtHost.UefiSettings).To(BeNil())
+
+	var noSecurityProfile *SecurityProfile
+	noSecurityProfile.SetDefaults()
+	g.Expect(noSecurityProfile).To(BeNil())
+}
+
 func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -106,16 +126,18 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 			},
 			output: []DataDisk{
 				{
-					NameSuffix:  "testdisk1",
-					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(0),
-					CachingType: "ReadWrite",
+					NameSuffix:   "testdisk1",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(0),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDelete,
 				},
 				{
-					NameSuffix:  "testdisk2",
-					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(1),
-					CachingType: "ReadWrite",
+					NameSuffix:   "testdisk2",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(1),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDelete,
 				},
 			},
 		},
@@ -137,16 +159,18 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 			},
 			output: []DataDisk{
 				{
-					NameSuffix:  "testdisk1",
-					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(5),
-					CachingType: "ReadWrite",
+					NameSuffix:   "testdisk1",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(5),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDelete,
 				},
 				{
-					NameSuffix:  "testdisk2",
-					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(3),
-					CachingType: "ReadWrite",
+					NameSuffix:   "testdisk2",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(3),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDelete,
 				},
 			},
 		},
@@ -178,28 +202,32 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 			},
 			output: []DataDisk{
 				{
-					NameSuffix:  "testdisk1",
-					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(0),
-					CachingType: "ReadWrite",
+					NameSuffix:   "testdisk1",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(0),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDelete,
 				},
 				{
-					NameSuffix:  "testdisk2",
-					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(2),
-					CachingType: "ReadWrite",
+					NameSuffix:   "testdisk2",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(2),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDelete,
 				},
 				{
-					NameSuffix:  "testdisk3",
-					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(1),
-					CachingType: "ReadWrite",
+					NameSuffix:   "testdisk3",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(1),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDelete,
 				},
 				{
-					NameSuffix:  "testdisk4",
-					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(3),
-					CachingType: "ReadWrite",
+					NameSuffix:   "testdisk4",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(3),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDelete,
 				},
 			},
 		},
@@ -219,16 +247,81 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 			},
 			output: []DataDisk{
 				{
-					NameSuffix:  "testdisk1",
-					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(0),
-					CachingType: "ReadWrite",
+					NameSuffix:   "testdisk1",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(0),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDelete,
 				},
 				{
-					NameSuffix:  "testdisk2",
-					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(2),
-					CachingType: "ReadWrite",
+					NameSuffix:   "testdisk2",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(2),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDelete,
+				},
+			},
+		},
+		{
+			name: "CachingType unspecified with write accelerator",
+			disks: []DataDisk{
+				{
+					NameSuffix:              "testdisk1",
+					DiskSizeGB:              30,
+					Lun:                     to.Int32Ptr(0),
+					WriteAcceleratorEnabled: to.BoolPtr(true),
+				},
+			},
+			output: []DataDisk{
+				{
+					NameSuffix:              "testdisk1",
+					DiskSizeGB:              30,
+					Lun:                     to.Int32Ptr(0),
+					CachingType:             "None",
+					DeleteOption:            DiskDeleteOptionDelete,
+					WriteAcceleratorEnabled: to.BoolPtr(true),
+				},
+			},
+		},
+		{
+			name: "shared disk",
+			disks: []DataDisk{
+				{
+					NameSuffix: "testdisk1",
+					DiskSizeGB: 30,
+					Lun:        to.Int32Ptr(0),
+					MaxShares:  to.Int32Ptr(2),
+				},
+			},
+			output: []DataDisk{
+				{
+					NameSuffix:   "testdisk1",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(0),
+					CachingType:  "None",
+					DeleteOption: DiskDeleteOptionDetach,
+					MaxShares:    to.Int32Ptr(2),
+				},
+			},
+		},
+		{
+			name: "DeleteOption specified",
+			disks: []DataDisk{
+				{
+					NameSuffix:   "testdisk1",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(0),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDetach,
+				},
+			},
+			output: []DataDisk{
+				{
+					NameSuffix:   "testdisk1",
+					DiskSizeGB:   30,
+					Lun:          to.Int32Ptr(0),
+					CachingType:  "ReadWrite",
+					DeleteOption: DiskDeleteOptionDetach,
 				},
 			},
 		},
@@ -28,6 +28,22 @@ const (
 	// MachineFinalizer allows ReconcileAzureMachine to clean up Azure resources associated with AzureMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "azuremachine.infrastructure.cluster.x-k8s.io"
+
+	// NICPoolSizeAnnotation is the number of spare network interfaces to keep ready for the AzureMachines of a
+	// MachineDeployment. It is set in the template metadata of the AzureMachineTemplate and requires the NICPool
+	// feature gate.
+	NICPoolSizeAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/nic-pool-size"
+
+	// NICPoolClaimAnnotation records the name of the spare network interface claimed by an AzureMachine.
+	NICPoolClaimAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/nic-pool-claim"
+
+	// AvailabilityZoneAnnotation records the availability zone CAPZ picked for an AzureMachine without a failure domain,
+	// to keep its VM out of the cordoned zones of the cluster.
+	AvailabilityZoneAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/availability-zone"
+
+	// SkipImagePolicyAnnotation skips the verification of the gallery image version of an AzureMachine against the image
+	// policy of the manager when set to "true", e.g. to roll out a fix before the image is scanned.
+	SkipImagePolicyAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/skip-image-policy"
 )
 
 // AzureMachineSpec defines the desired state of AzureMachine.
@@ -114,15 +130,92 @@ type AzureMachineSpec struct {
 	// SubnetName selects the Subnet where the VM will be placed
 	// +optional
 	SubnetName string `json:"subnetName,omitempty"`
+
+	// ProximityPlacementGroupID is the Azure resource ID of an existing proximity placement group the VM is placed in.
+	// The VM is placed in the proximity placement group of the cluster if it is not set and the cluster has one.
+	// +optional
+	ProximityPlacementGroupID *string `json:"proximityPlacementGroupID,omitempty"`
+
+	// CapacityReservationGroupID is the Azure resource ID of a capacity reservation group the VM is allocated from,
+	// so that it consumes capacity reserved in advance. It can't be used with Spot VMs.
+	// +optional
+	CapacityReservationGroupID *string `json:"capacityReservationGroupID,omitempty"`
+
+	// ComputerNamePrefix is the prefix of the computer name, i.e. the hostname, of the VM. The computer name is
+	// "<prefix>-<zone>-<index>", or "<prefix>-<index>" without availability zone, where the index is the random suffix
+	// of the AzureMachine name. The prefix can be up to 7 characters long on Windows and 56 on Linux.
+	// The computer name is the VM name if it is not set.
+	// +optional
+	ComputerNamePrefix string `json:"computerNamePrefix,omitempty"`
+
+	// LicenseType is the type of the on-premises license the VM uses through Azure Hybrid Benefit, so that the software
+	// license isn't billed again. Windows_Server applies to Windows VMs, RHEL_BYOS and SLES_BYOS to Linux VMs.
+	// +kubebuilder:validation:Enum=Windows_Server;RHEL_BYOS;SLES_BYOS
+	// +optional
+	LicenseType LicenseType `json:"licenseType,omitempty"`
+
+	// UserData references a Secret holding the user data of the VM. The user data is available to the VM from the
+	// instance metadata service and, unlike the bootstrap data, can be changed without recreating the VM.
+	// +optional
+	UserData *UserDataSource `json:"userData,omitempty"`
+
+	// PowerState is the desired power state of the VM once it is provisioned. Deallocated VMs are stopped and release
+	// their compute resources, so that only their disks are billed. Hibernated VMs also save the content of their memory
+	// to the OS disk and resume from it when started again, which requires a VM size and an image supporting
+	// hibernation. The power state of the VM is left as is if it is not set.
+	// +kubebuilder:validation:Enum=Running;Deallocated;Hibernated
+	// +optional
+	PowerState VMPowerState `json:"powerState,omitempty"`
 }
 
 // SpotVMOptions defines the options relevant to running the Machine on Spot VMs.
 type SpotVMOptions struct {
-	// MaxPrice defines the maximum price the user is willing to pay for Spot VM instances
+	// MaxPrice defines the maximum price the user is willing to pay for Spot VM instances, in US dollars per hour.
+	// -1 caps the price at the on-demand price, which is also the behavior when it is not set.
 	// +optional
 	MaxPrice *resource.Quantity `json:"maxPrice,omitempty"`
+
+	// EvictionPolicy defines what happens to the VM when it is evicted. Deallocated VMs keep their disks and can be
+	// started again, deleted VMs don't incur any storage cost. Defaults to Deallocate.
+	// +kubebuilder:validation:Enum=Deallocate;Delete
+	// +optional
+	EvictionPolicy *SpotEvictionPolicy `json:"evictionPolicy,omitempty"`
 }
 
+// SpotEvictionPolicy defines the eviction policy of Spot VMs.
+type SpotEvictionPolicy string
+
+const (
+	// SpotEvictionPolicyDeallocate stops and deallocates the VM when it is evicted.
+	SpotEvictionPolicyDeallocate SpotEvictionPolicy = "Deallocate"
+	// SpotEvictionPolicyDelete deletes the VM and its disks when it is evicted.
+	SpotEvictionPolicyDelete SpotEvictionPolicy = "Delete"
+)
+
+// LicenseType defines the license type of a VM using Azure Hybrid Benefit.
+type LicenseType string
+
+const (
+	// LicenseTypeWindowsServer uses a Windows Server license with Software Assurance.
+	LicenseTypeWindowsServer LicenseType = "Windows_Server"
+	// LicenseTypeRHELBYOS uses a Red Hat Enterprise Linux subscription.
+	LicenseTypeRHELBYOS LicenseType = "RHEL_BYOS"
+	// LicenseTypeSLESBYOS uses a SUSE Linux Enterprise Server subscription.
+	LicenseTypeSLESBYOS LicenseType = "SLES_BYOS"
+)
+
+// VMPowerState defines the power state of a VM.
+type VMPowerState string
+
+const (
+	// VMPowerStateRunning is a VM which is started.
+	VMPowerStateRunning VMPowerState = "Running"
+	// VMPowerStateDeallocated is a VM which is stopped and doesn't hold compute resources.
+	VMPowerStateDeallocated VMPowerState = "Deallocated"
+	// VMPowerStateHibernated is a deallocated VM whose memory was saved to its OS disk.
+	VMPowerStateHibernated VMPowerState = "Hibernated"
+)
+
 // AzureMachineStatus defines the observed state of AzureMachine.
 type AzureMachineStatus struct {
 	// Ready is true when the provider resource is ready.
@@ -137,6 +230,17 @@ type AzureMachineStatus struct {
 	// +optional
 	VMState *ProvisioningState `json:"vmState,omitempty"`
 
+	// PowerState is the power state of the Azure virtual machine. It is only reported when the AzureMachine has a
+	// desired power state.
+	// +optional
+	PowerState VMPowerState `json:"powerState,omitempty"`
+
+	// Image is the image of the VM, resolved from the Kubernetes version of the machine when the AzureMachine doesn't
+	// specify one, or the shared gallery image of the AzureMachine with the version "latest" resolved to. It is kept
+	// for the lifetime of the machine so that its VM can be reproduced.
+	// +optional
+	Image *Image `json:"image,omitempty"`
+
 	// ErrorReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -183,13 +287,37 @@ type AzureMachineStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// CompletedPhase is the last provisioning phase the AzureMachine completed. Provisioning resumes from the next phase,
+	// and only the resources of the completed phases are reconciled again, not the steps needed to create them.
+	// +optional
+	CompletedPhase AzureMachinePhase `json:"completedPhase,omitempty"`
 }
 
+// AzureMachinePhase is a phase of the provisioning of an AzureMachine.
+// +kubebuilder:validation:Enum=NetworkInterfaces;VirtualMachine;Extensions;BootstrapVerified
+type AzureMachinePhase string
+
+const (
+	// AzureMachinePhaseNetworkInterfaces is completed when the public IPs, inbound NAT rules and network interfaces of
+	// the machine are created.
+	AzureMachinePhaseNetworkInterfaces AzureMachinePhase = "NetworkInterfaces"
+	// AzureMachinePhaseVirtualMachine is completed when the availability set, virtual machine and role assignments of
+	// the machine are created.
+	AzureMachinePhaseVirtualMachine AzureMachinePhase = "VirtualMachine"
+	// AzureMachinePhaseExtensions is completed when the VM extensions of the machine are created.
+	AzureMachinePhaseExtensions AzureMachinePhase = "Extensions"
+	// AzureMachinePhaseBootstrapVerified is completed when the bootstrap extension of the machine reports that the
+	// bootstrap data ran successfully. It is the last provisioning phase.
+	AzureMachinePhaseBootstrapVerified AzureMachinePhase = "BootstrapVerified"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason"
 // +kubebuilder:printcolumn:name="Message",type="string",priority=1,JSONPath=".status.conditions[?(@.type=='Ready')].message"
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.vmState",description="Azure VM provisioning state"
+// +kubebuilder:printcolumn:name="Phase",type="string",priority=1,JSONPath=".status.completedPhase",description="Last completed provisioning phase"
 // +kubebuilder:printcolumn:name="Cluster",type="string",priority=1,JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this AzureMachine belongs"
 // +kubebuilder:printcolumn:name="Machine",type="string",priority=1,JSONPath=".metadata.ownerReferences[?(@.kind==\"Machine\")].name",description="Machine object to which this AzureMachine belongs"
 // +kubebuilder:printcolumn:name="VM ID",type="string",priority=1,JSONPath=".spec.providerID",description="Azure VM ID"
@@ -19,11 +19,15 @@ package v1beta1
 import (
 	"encoding/base64"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 
 	"github.com/google/uuid"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"golang.org/x/crypto/ssh"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -55,6 +59,191 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateSpotVMOptions(spec.SpotVMOptions, spec.OSDisk, field.NewPath("spotVMOptions")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateSecurityProfile(spec.SecurityProfile, spec.Image, field.NewPath("securityProfile")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateProximityPlacementGroupID(spec.ProximityPlacementGroupID, field.NewPath("proximityPlacementGroupID")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateCapacityReservationGroupID(spec.CapacityReservationGroupID, spec.SpotVMOptions, field.NewPath("capacityReservationGroupID")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateLicenseType(spec.LicenseType, spec.OSDisk, field.NewPath("licenseType")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateWriteAccelerator(spec.VMSize, spec.DataDisks, field.NewPath("dataDisks")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	// The zone and the index of the machine are appended to the prefix, e.g. "-1-abcde".
+	maxComputerNamePrefixLength := 56
+	if spec.OSDisk.OSType == string(compute.OperatingSystemTypesWindows) {
+		maxComputerNamePrefixLength = 7
+	}
+	if errs := ValidateComputerNamePrefix(spec.ComputerNamePrefix, maxComputerNamePrefixLength, field.NewPath("computerNamePrefix")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	return allErrs
+}
+
+// ValidateComputerNamePrefix validates the prefix of computer names, which can't be longer than maxLength.
+func ValidateComputerNamePrefix(prefix string, maxLength int, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if prefix == "" {
+		return allErrs
+	}
+
+	if success, _ := regexp.MatchString(computerNamePrefixRegex, prefix); !success {
+		allErrs = append(allErrs, field.Invalid(fieldPath, prefix,
+			fmt.Sprintf("computerNamePrefix doesn't match regex %s", computerNamePrefixRegex)))
+	}
+	if len(prefix) > maxLength {
+		allErrs = append(allErrs, field.TooLong(fieldPath, prefix, maxLength))
+	}
+	return allErrs
+}
+
+// ValidateLicenseType validates that the license type matches the OS of the VM.
+func ValidateLicenseType(licenseType LicenseType, osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch licenseType {
+	case "":
+	case LicenseTypeWindowsServer:
+		if osDisk.OSType != string(compute.OperatingSystemTypesWindows) {
+			allErrs = append(allErrs, field.Invalid(fieldPath, licenseType, "license type is only supported by Windows VMs"))
+		}
+	case LicenseTypeRHELBYOS, LicenseTypeSLESBYOS:
+		if osDisk.OSType != string(compute.OperatingSystemTypesLinux) {
+			allErrs = append(allErrs, field.Invalid(fieldPath, licenseType, "license type is only supported by Linux VMs"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fieldPath, licenseType,
+			[]string{string(LicenseTypeWindowsServer), string(LicenseTypeRHELBYOS), string(LicenseTypeSLESBYOS)}))
+	}
+	return allErrs
+}
+
+// ValidateWriteAccelerator validates that the data disks with Write Accelerator enabled are attached to an M-series VM,
+// use Premium storage and don't cache writes.
+func ValidateWriteAccelerator(vmSize string, dataDisks []DataDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, disk := range dataDisks {
+		if disk.WriteAcceleratorEnabled == nil || !*disk.WriteAcceleratorEnabled {
+			continue
+		}
+		diskPath := fieldPath.Index(i)
+		if !strings.HasPrefix(strings.ToLower(vmSize), "standard_m") {
+			allErrs = append(allErrs, field.Invalid(diskPath.Child("writeAcceleratorEnabled"), vmSize, "write accelerator is only supported by M-series VM sizes"))
+		}
+		if disk.ManagedDisk == nil || (disk.ManagedDisk.StorageAccountType != string(compute.StorageAccountTypesPremiumLRS) &&
+			disk.ManagedDisk.StorageAccountType != string(compute.StorageAccountTypesPremiumZRS)) {
+			allErrs = append(allErrs, field.Required(diskPath.Child("managedDisk", "storageAccountType"),
+				fmt.Sprintf("write accelerator requires a storage account type of %s or %s", compute.StorageAccountTypesPremiumLRS, compute.StorageAccountTypesPremiumZRS)))
+		}
+		if disk.CachingType == string(compute.CachingTypesReadWrite) {
+			allErrs = append(allErrs, field.Invalid(diskPath.Child("cachingType"), disk.CachingType, "write accelerator requires a caching type of None or ReadOnly"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateProximityPlacementGroupID validates the Azure resource ID of a proximity placement group.
+func ValidateProximityPlacementGroupID(proximityPlacementGroupID *string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if proximityPlacementGroupID == nil {
+		return allErrs
+	}
+
+	if success, _ := regexp.MatchString(proximityPlacementGroupIDRegex, *proximityPlacementGroupID); !success {
+		allErrs = append(allErrs, field.Invalid(fieldPath, *proximityPlacementGroupID,
+			fmt.Sprintf("proximityPlacementGroupID doesn't match regex %s", proximityPlacementGroupIDRegex)))
+	}
+	return allErrs
+}
+
+// ValidateSecurityProfile validates the SecurityProfile, and that trusted launch is used with a compatible image.
+func ValidateSecurityProfile(securityProfile *SecurityProfile, image *Image, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if securityProfile == nil {
+		return allErrs
+	}
+
+	if securityProfile.SecurityType != SecurityTypesTrustedLaunch {
+		if securityProfile.UefiSettings != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("uefiSettings"), securityProfile.UefiSettings,
+				fmt.Sprintf("uefiSettings require securityType to be %s", SecurityTypesTrustedLaunch)))
+		}
+		return allErrs
+	}
+
+	// Trusted launch requires a Generation 2 image from a Shared Image Gallery or the Marketplace.
+	switch {
+	case image == nil:
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("securityType"), securityProfile.SecurityType,
+			"trusted launch requires a Generation 2 image, but the default images are Generation 1. Set the image explicitly"))
+	case image.ID != nil:
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("securityType"), securityProfile.SecurityType,
+			"trusted launch doesn't support managed images. Use a Generation 2 Shared Image Gallery or Marketplace image"))
+	}
+
+	return allErrs
+}
+
+// ValidateDiagnostics validates the Diagnostics, and that a storage account is referenced only, and always, when the
+// boot diagnostics are stored in a storage account managed by the user.
+func ValidateDiagnostics(diagnostics *Diagnostics, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if diagnostics == nil || diagnostics.Boot == nil {
+		return allErrs
+	}
+
+	boot := diagnostics.Boot
+	bootPath := fieldPath.Child("boot")
+	switch boot.StorageAccountType {
+	case UserManagedDiagnosticsStorage:
+		if boot.UserManaged == nil || boot.UserManaged.StorageAccountURI == "" {
+			allErrs = append(allErrs, field.Required(bootPath.Child("userManaged", "storageAccountURI"),
+				fmt.Sprintf("storageAccountURI is required when storageAccountType is %s", UserManagedDiagnosticsStorage)))
+		} else if !strings.HasPrefix(boot.UserManaged.StorageAccountURI, "https://") {
+			allErrs = append(allErrs, field.Invalid(bootPath.Child("userManaged", "storageAccountURI"), boot.UserManaged.StorageAccountURI,
+				"storageAccountURI must be the https blob endpoint of the storage account"))
+		}
+	case ManagedDiagnosticsStorage, DisabledDiagnosticsStorage:
+		if boot.UserManaged != nil {
+			allErrs = append(allErrs, field.Forbidden(bootPath.Child("userManaged"),
+				fmt.Sprintf("userManaged can only be set when storageAccountType is %s", UserManagedDiagnosticsStorage)))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(bootPath.Child("storageAccountType"), boot.StorageAccountType,
+			[]string{string(ManagedDiagnosticsStorage), string(UserManagedDiagnosticsStorage), string(DisabledDiagnosticsStorage)}))
+	}
+	return allErrs
+}
+
+// ValidateCapacityReservationGroupID validates the Azure resource ID of a capacity reservation group, which Spot VMs
+// can't be allocated from.
+func ValidateCapacityReservationGroupID(capacityReservationGroupID *string, spotVMOptions *SpotVMOptions, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if capacityReservationGroupID == nil {
+		return allErrs
+	}
+
+	if success, _ := regexp.MatchString(capacityReservationGroupIDRegex, *capacityReservationGroupID); !success {
+		allErrs = append(allErrs, field.Invalid(fieldPath, *capacityReservationGroupID,
+			fmt.Sprintf("capacityReservationGroupID doesn't match regex %s", capacityReservationGroupIDRegex)))
+	}
+	if spotVMOptions != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "capacity reservations can't be used with Spot VMs"))
+	}
 	return allErrs
 }
 
@@ -130,6 +319,8 @@ func ValidateDataDisks(dataDisks []DataDisk, fieldPath *field.Path) field.ErrorL
 			if errs := validateManagedDisk(disk.ManagedDisk, fieldPath.Child("managedDisk"), false); len(errs) > 0 {
 				allErrs = append(allErrs, errs...)
 			}
+			diskSizeGB := disk.DiskSizeGB
+			allErrs = append(allErrs, validatePerformanceTier(disk.ManagedDisk, &diskSizeGB, fieldPath.Child("managedDisk", "performanceTier"))...)
 		}
 
 		// validate that all LUNs are unique and between 0 and 63.
@@ -145,7 +336,74 @@ func ValidateDataDisks(dataDisks []DataDisk, fieldPath *field.Path) field.ErrorL
 
 		// validate cachingType
 		allErrs = append(allErrs, validateCachingType(disk.CachingType, fieldPath)...)
+
+		// validate maxShares
+		allErrs = append(allErrs, validateSharedDisk(disk, fieldPath)...)
+	}
+	return allErrs
+}
+
+// sharedDiskStorageAccountTypes are the storage account types of the managed disks which can be shared by multiple VMs.
+var sharedDiskStorageAccountTypes = []string{
+	string(compute.StorageAccountTypesPremiumLRS),
+	string(compute.StorageAccountTypesPremiumZRS),
+	string(compute.StorageAccountTypesStandardSSDLRS),
+	string(compute.StorageAccountTypesStandardSSDZRS),
+	string(compute.StorageAccountTypesUltraSSDLRS),
+}
+
+// validateSharedDisk validates that a data disk with maxShares greater than 1 can be shared by multiple VMs.
+func validateSharedDisk(disk DataDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if disk.MaxShares != nil && *disk.MaxShares < 1 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxShares"), *disk.MaxShares, "maxShares must be at least 1"))
+	}
+	if !disk.IsShared() {
+		return allErrs
+	}
+
+	storageAccountType := ""
+	if disk.ManagedDisk != nil {
+		storageAccountType = disk.ManagedDisk.StorageAccountType
+	}
+	supported := false
+	for _, t := range sharedDiskStorageAccountTypes {
+		if storageAccountType == t {
+			supported = true
+		}
+	}
+	if !supported {
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("managedDisk", "storageAccountType"), storageAccountType, sharedDiskStorageAccountTypes))
+	}
+	if disk.CachingType != "" && disk.CachingType != string(compute.CachingTypesNone) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("cachingType"), disk.CachingType, "shared disks require a caching type of None"))
+	}
+	if disk.WriteAcceleratorEnabled != nil && *disk.WriteAcceleratorEnabled {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("writeAcceleratorEnabled"), *disk.WriteAcceleratorEnabled, "write accelerator isn't supported on shared disks"))
+	}
+	if disk.DeleteOption == DiskDeleteOptionDelete {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("deleteOption"), disk.DeleteOption, "shared disks outlive the machines they are attached to, the delete option must be Detach"))
+	}
+	return allErrs
+}
+
+// ValidateSpotVMOptions validates the Spot VM options along with the OS disk they are used with.
+func ValidateSpotVMOptions(spotVMOptions *SpotVMOptions, osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spotVMOptions == nil {
+		return allErrs
+	}
+
+	if maxPrice := spotVMOptions.MaxPrice; maxPrice != nil && maxPrice.Sign() <= 0 && maxPrice.Cmp(resource.MustParse("-1")) != 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxPrice"), maxPrice.String(), "the maximum price must be greater than 0, or -1 to cap it at the on-demand price"))
+	}
+
+	// Azure can't deallocate VMs with an ephemeral OS disk, as the disk lives on the host the VM is evicted from.
+	if osDisk.DiffDiskSettings != nil && (spotVMOptions.EvictionPolicy == nil || *spotVMOptions.EvictionPolicy != SpotEvictionPolicyDelete) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("evictionPolicy"), spotVMOptions.EvictionPolicy,
+			fmt.Sprintf("the eviction policy must be %s when diffDiskSettings.option is 'Local'", SpotEvictionPolicyDelete)))
 	}
+
 	return allErrs
 }
 
@@ -169,6 +427,15 @@ func ValidateOSDisk(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
 		if errs := validateManagedDisk(osDisk.ManagedDisk, fieldPath.Child("managedDisk"), true); len(errs) > 0 {
 			allErrs = append(allErrs, errs...)
 		}
+		allErrs = append(allErrs, validatePerformanceTier(osDisk.ManagedDisk, osDisk.DiskSizeGB, fieldPath.Child("managedDisk", "performanceTier"))...)
+	}
+
+	if osDisk.DiffDiskSettings != nil && osDisk.ManagedDisk != nil && osDisk.ManagedDisk.PerformanceTier != "" {
+		allErrs = append(allErrs, field.Invalid(
+			fieldPath.Child("managedDisk").Child("performanceTier"),
+			osDisk.ManagedDisk.PerformanceTier,
+			"performanceTier is not supported when diffDiskSettings.option is 'Local'",
+		))
 	}
 
 	if osDisk.DiffDiskSettings != nil && osDisk.ManagedDisk != nil && osDisk.ManagedDisk.DiskEncryptionSet != nil {
@@ -179,6 +446,14 @@ func ValidateOSDisk(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
 		))
 	}
 
+	if osDisk.DiffDiskSettings != nil && osDisk.ManagedDisk.IsZoneRedundant() {
+		allErrs = append(allErrs, field.Invalid(
+			fieldPath.Child("managedDisk").Child("storageAccountType"),
+			osDisk.ManagedDisk.StorageAccountType,
+			"zone redundant storage account types are not supported when diffDiskSettings.option is 'Local'",
+		))
+	}
+
 	return allErrs
 }
 
@@ -188,22 +463,60 @@ func validateManagedDisk(m *ManagedDiskParameters, fieldPath *field.Path, isOSDi
 
 	if m != nil {
 		allErrs = append(allErrs, validateStorageAccountType(m.StorageAccountType, fieldPath.Child("StorageAccountType"), isOSDisk)...)
+		if m.DiskEncryptionSet != nil && m.DiskEncryptionSet.ID != "" && m.DiskEncryptionSet.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("diskEncryptionSet"), "id and name are mutually exclusive"))
+		}
 	}
 
 	return allErrs
 }
 
-// ValidateDataDisksUpdate validates updates to Data disks.
-func ValidateDataDisksUpdate(oldDataDisks, newDataDisks []DataDisk, fieldPath *field.Path) field.ErrorList {
+// diskPerformanceTiers are the performance tiers of Premium SSD disks, in increasing order, with the largest disk size
+// in GiB of which they are the baseline tier.
+var diskPerformanceTiers = []struct {
+	tier      string
+	maxSizeGB int32
+}{
+	{"P1", 4}, {"P2", 8}, {"P3", 16}, {"P4", 32}, {"P6", 64}, {"P10", 128}, {"P15", 256}, {"P20", 512},
+	{"P30", 1024}, {"P40", 2048}, {"P50", 4096}, {"P60", 8192}, {"P70", 16384}, {"P80", 32767},
+}
+
+// validatePerformanceTier validates that the performance tier of a managed disk is set on a Premium SSD disk and isn't
+// lower than the baseline tier of its size, when the size is known.
+func validatePerformanceTier(m *ManagedDiskParameters, diskSizeGB *int32, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
+	if m == nil || m.PerformanceTier == "" {
+		return allErrs
+	}
 
-	diskErrMsg := "adding/removing data disks after machine creation is not allowed"
-	fieldErrMsg := "modifying data disk's fields after machine creation is not allowed"
+	if m.StorageAccountType != string(compute.StorageAccountTypesPremiumLRS) && m.StorageAccountType != string(compute.StorageAccountTypesPremiumZRS) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, m.PerformanceTier,
+			fmt.Sprintf("performance tiers require a storage account type of %s or %s", compute.StorageAccountTypesPremiumLRS, compute.StorageAccountTypesPremiumZRS)))
+	}
 
-	if len(oldDataDisks) != len(newDataDisks) {
-		allErrs = append(allErrs, field.Invalid(fieldPath, newDataDisks, diskErrMsg))
+	tier := -1
+	for i, t := range diskPerformanceTiers {
+		if t.tier == m.PerformanceTier {
+			tier = i
+		}
+	}
+	if tier < 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath, m.PerformanceTier, "unknown performance tier"))
 		return allErrs
 	}
+	if diskSizeGB != nil && *diskSizeGB > diskPerformanceTiers[tier].maxSizeGB {
+		allErrs = append(allErrs, field.Invalid(fieldPath, m.PerformanceTier,
+			fmt.Sprintf("performance tier is lower than the baseline tier of a %d GiB disk", *diskSizeGB)))
+	}
+	return allErrs
+}
+
+// ValidateDataDisksUpdate validates updates to Data disks. Data disks can be added to or removed from a machine
+// after its creation, but the fields of the disks it already has can't be modified.
+func ValidateDataDisksUpdate(oldDataDisks, newDataDisks []DataDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldErrMsg := "modifying data disk's fields after machine creation is not allowed"
 
 	oldDisks := make(map[string]DataDisk)
 
@@ -228,14 +541,33 @@ func ValidateDataDisksUpdate(oldDataDisks, newDataDisks []DataDisk, fieldPath *f
 			if newDisk.CachingType != oldDisk.CachingType {
 				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("cachingType"), newDataDisks, fieldErrMsg))
 			}
-		} else {
-			allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("nameSuffix"), newDataDisks, diskErrMsg))
+
+			if dataDiskDeleteOption(newDisk) != dataDiskDeleteOption(oldDisk) {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("deleteOption"), newDataDisks, fieldErrMsg))
+			}
+
+			if !reflect.DeepEqual(newDisk.WriteAcceleratorEnabled, oldDisk.WriteAcceleratorEnabled) {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("writeAcceleratorEnabled"), newDataDisks, fieldErrMsg))
+			}
+
+			if !reflect.DeepEqual(newDisk.MaxShares, oldDisk.MaxShares) {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("maxShares"), newDataDisks, fieldErrMsg))
+			}
 		}
 	}
 
 	return allErrs
 }
 
+// dataDiskDeleteOption returns the delete option of a data disk, treating an unset option as its Delete default
+// so that disks created before the option existed compare equal to defaulted ones.
+func dataDiskDeleteOption(disk DataDisk) DiskDeleteOption {
+	if disk.DeleteOption == "" {
+		return DiskDeleteOptionDelete
+	}
+	return disk.DeleteOption
+}
+
 func validateManagedDisksUpdate(old, new *ManagedDiskParameters, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	fieldErrMsg := "changing managed disk options after machine creation is not allowed"
@@ -248,6 +580,9 @@ func validateManagedDisksUpdate(old, new *ManagedDiskParameters, fieldPath *fiel
 			if new.DiskEncryptionSet.ID != old.DiskEncryptionSet.ID {
 				allErrs = append(allErrs, field.Invalid(fieldPath.Child("diskEncryptionSet").Child("ID"), new, fieldErrMsg))
 			}
+			if new.DiskEncryptionSet.Name != old.DiskEncryptionSet.Name {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("diskEncryptionSet").Child("name"), new, fieldErrMsg))
+			}
 		} else if (new.DiskEncryptionSet != nil && old.DiskEncryptionSet == nil) || (new.DiskEncryptionSet == nil && old.DiskEncryptionSet != nil) {
 			allErrs = append(allErrs, field.Invalid(fieldPath.Child("diskEncryptionSet"), new, fieldErrMsg))
 		}
@@ -30,6 +30,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"golang.org/x/crypto/ssh"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -132,6 +133,93 @@ func TestAzureMachine_ValidateOSDisk(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "valid zone redundant os disk spec",
+			wantErr: false,
+			osDisk: OSDisk{
+				DiskSizeGB:  to.Int32Ptr(30),
+				CachingType: "None",
+				OSType:      "blah",
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: string(compute.StorageAccountTypesStandardSSDZRS),
+				},
+			},
+		},
+		{
+			name:    "zone redundant ephemeral os disk spec",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:  to.Int32Ptr(30),
+				CachingType: "None",
+				OSType:      "blah",
+				DiffDiskSettings: &DiffDiskSettings{
+					Option: string(compute.DiffDiskOptionsLocal),
+				},
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: string(compute.StorageAccountTypesPremiumZRS),
+				},
+			},
+		},
+		{
+			name:    "disk encryption set referenced by name",
+			wantErr: false,
+			osDisk: OSDisk{
+				DiskSizeGB:  to.Int32Ptr(30),
+				OSType:      "Linux",
+				CachingType: "None",
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: "Premium_LRS",
+					DiskEncryptionSet: &DiskEncryptionSetParameters{
+						Name: "my-des",
+					},
+				},
+			},
+		},
+		{
+			name:    "disk encryption set referenced by both id and name",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:  to.Int32Ptr(30),
+				OSType:      "Linux",
+				CachingType: "None",
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: "Premium_LRS",
+					DiskEncryptionSet: &DiskEncryptionSetParameters{
+						ID:   "disk-encryption-set",
+						Name: "my-des",
+					},
+				},
+			},
+		},
+		{
+			name:    "valid performance tier",
+			wantErr: false,
+			osDisk: OSDisk{
+				DiskSizeGB:  to.Int32Ptr(30),
+				OSType:      "Linux",
+				CachingType: "None",
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: "Premium_LRS",
+					PerformanceTier:    "P30",
+				},
+			},
+		},
+		{
+			name:    "performance tier with ephemeral os disk",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:  to.Int32Ptr(30),
+				OSType:      "Linux",
+				CachingType: "None",
+				DiffDiskSettings: &DiffDiskSettings{
+					Option: string(compute.DiffDiskOptionsLocal),
+				},
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: "Premium_LRS",
+					PerformanceTier:    "P30",
+				},
+			},
+		},
 	}
 	testcases = append(testcases, generateNegativeTestCases()...)
 
@@ -387,6 +475,100 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid shared disk",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					Lun:          to.Int32Ptr(0),
+					CachingType:  "None",
+					DeleteOption: DiskDeleteOptionDetach,
+					MaxShares:    to.Int32Ptr(2),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid maxShares",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					Lun:        to.Int32Ptr(0),
+					MaxShares:  to.Int32Ptr(0),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "shared disk with a storage account type that doesn't support sharing",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					Lun:          to.Int32Ptr(0),
+					CachingType:  "None",
+					DeleteOption: DiskDeleteOptionDetach,
+					MaxShares:    to.Int32Ptr(2),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "shared disk without a managed disk storage account type",
+			disks: []DataDisk{
+				{
+					NameSuffix:   "my_disk_1",
+					DiskSizeGB:   64,
+					Lun:          to.Int32Ptr(0),
+					CachingType:  "None",
+					DeleteOption: DiskDeleteOptionDetach,
+					MaxShares:    to.Int32Ptr(2),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "shared disk with host caching",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_ZRS",
+					},
+					Lun:          to.Int32Ptr(0),
+					CachingType:  "ReadOnly",
+					DeleteOption: DiskDeleteOptionDetach,
+					MaxShares:    to.Int32Ptr(2),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "shared disk deleted with the machine",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					Lun:          to.Int32Ptr(0),
+					CachingType:  "None",
+					DeleteOption: DiskDeleteOptionDelete,
+					MaxShares:    to.Int32Ptr(2),
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, test := range testcases {
@@ -456,147 +638,637 @@ func TestAzureMachine_ValidateSystemAssignedIdentity(t *testing.T) {
 	}
 }
 
-func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
+func TestAzureMachine_ValidateSpotVMOptions(t *testing.T) {
 	g := NewWithT(t)
 
+	deallocate := SpotEvictionPolicyDeallocate
+	deleteOnEviction := SpotEvictionPolicyDelete
+	ephemeralOSDisk := OSDisk{DiffDiskSettings: &DiffDiskSettings{Option: string(compute.DiffDiskOptionsLocal)}}
+
 	tests := []struct {
-		name     string
-		disks    []DataDisk
-		oldDisks []DataDisk
-		wantErr  bool
+		name          string
+		spotVMOptions *SpotVMOptions
+		osDisk        OSDisk
+		wantErr       bool
 	}{
 		{
-			name:     "valid nil data disks",
-			disks:    nil,
-			oldDisks: nil,
-			wantErr:  false,
+			name:          "no spot VM options",
+			spotVMOptions: nil,
+			osDisk:        ephemeralOSDisk,
+			wantErr:       false,
 		},
 		{
-			name:     "valid empty data disks",
-			disks:    []DataDisk{},
-			oldDisks: []DataDisk{},
-			wantErr:  false,
+			name:          "default spot VM options",
+			spotVMOptions: &SpotVMOptions{},
+			wantErr:       false,
 		},
 		{
-			name: "valid data disk updates",
-			disks: []DataDisk{
-				{
-					NameSuffix: "my_disk",
-					DiskSizeGB: 64,
-					Lun:        to.Int32Ptr(0),
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-				{
-					NameSuffix: "my_other_disk",
-					DiskSizeGB: 64,
-					Lun:        to.Int32Ptr(1),
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-			},
-			oldDisks: []DataDisk{
-				{
-					NameSuffix: "my_disk",
-					DiskSizeGB: 64,
-					Lun:        to.Int32Ptr(0),
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-				{
-					NameSuffix: "my_other_disk",
-					DiskSizeGB: 64,
-					Lun:        to.Int32Ptr(1),
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-			},
-			wantErr: false,
+			name:          "positive maximum price",
+			spotVMOptions: &SpotVMOptions{MaxPrice: resource.NewMilliQuantity(40, resource.DecimalSI)},
+			wantErr:       false,
 		},
 		{
-			name: "cannot update data disk fields after machine creation",
-			disks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 64,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-			},
-			oldDisks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 128,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-			},
-			wantErr: true,
+			name:          "maximum price capped at the on-demand price",
+			spotVMOptions: &SpotVMOptions{MaxPrice: resource.NewQuantity(-1, resource.DecimalSI)},
+			wantErr:       false,
 		},
 		{
-			name: "validate updates to optional fields",
-			disks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 128,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					Lun: to.Int32Ptr(0),
-				},
-			},
-			oldDisks: []DataDisk{
-				{
-					NameSuffix:  "my_disk_1",
-					DiskSizeGB:  128,
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-			},
-			wantErr: true,
+			name:          "zero maximum price",
+			spotVMOptions: &SpotVMOptions{MaxPrice: resource.NewQuantity(0, resource.DecimalSI)},
+			wantErr:       true,
 		},
 		{
-			name: "data disks cannot be added after machine creation",
-			disks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 64,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-			},
-			oldDisks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 64,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-				{
-					NameSuffix: "my_disk_2",
-					DiskSizeGB: 64,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-					},
+			name:          "negative maximum price",
+			spotVMOptions: &SpotVMOptions{MaxPrice: resource.NewMilliQuantity(-500, resource.DecimalSI)},
+			wantErr:       true,
+		},
+		{
+			name:          "deallocated on eviction with a managed OS disk",
+			spotVMOptions: &SpotVMOptions{EvictionPolicy: &deallocate},
+			wantErr:       false,
+		},
+		{
+			name:          "deleted on eviction with an ephemeral OS disk",
+			spotVMOptions: &SpotVMOptions{EvictionPolicy: &deleteOnEviction},
+			osDisk:        ephemeralOSDisk,
+			wantErr:       false,
+		},
+		{
+			name:          "deallocated on eviction with an ephemeral OS disk",
+			spotVMOptions: &SpotVMOptions{EvictionPolicy: &deallocate},
+			osDisk:        ephemeralOSDisk,
+			wantErr:       true,
+		},
+		{
+			name:          "default eviction policy with an ephemeral OS disk",
+			spotVMOptions: &SpotVMOptions{},
+			osDisk:        ephemeralOSDisk,
+			wantErr:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSpotVMOptions(tc.spotVMOptions, tc.osDisk, field.NewPath("spotVMOptions"))
+			if tc.wantErr {
+				g.Expect(err).ToNot(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateSecurityProfile(t *testing.T) {
+	g := NewWithT(t)
+
+	marketplaceImage := &Image{Marketplace: &AzureMarketplaceImage{Publisher: "Canonical", Offer: "0001-com-ubuntu-server-focal", SKU: "20_04-lts-gen2", Version: "latest"}}
+	tests := []struct {
+		name            string
+		securityProfile *SecurityProfile
+		image           *Image
+		wantErr         bool
+	}{
+		{
+			name:            "no security profile",
+			securityProfile: nil,
+			wantErr:         false,
+		},
+		{
+			name:            "trusted launch with a marketplace image",
+			securityProfile: &SecurityProfile{SecurityType: SecurityTypesTrustedLaunch, UefiSettings: &UefiSettings{SecureBootEnabled: to.BoolPtr(true)}},
+			image:           marketplaceImage,
+			wantErr:         false,
+		},
+		{
+			name:            "uefi settings without trusted launch",
+			securityProfile: &SecurityProfile{UefiSettings: &UefiSettings{SecureBootEnabled: to.BoolPtr(true)}},
+			image:           marketplaceImage,
+			wantErr:         true,
+		},
+		{
+			name:            "trusted launch with the default image",
+			securityProfile: &SecurityProfile{SecurityType: SecurityTypesTrustedLaunch},
+			image:           nil,
+			wantErr:         true,
+		},
+		{
+			name:            "trusted launch with a managed image",
+			securityProfile: &SecurityProfile{SecurityType: SecurityTypesTrustedLaunch},
+			image:           &Image{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/my-image")},
+			wantErr:         true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateSecurityProfile(test.securityProfile, test.image, field.NewPath("securityProfile"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateProximityPlacementGroupID(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name                      string
+		proximityPlacementGroupID *string
+		wantErr                   bool
+	}{
+		{
+			name:                      "no proximity placement group",
+			proximityPlacementGroupID: nil,
+			wantErr:                   false,
+		},
+		{
+			name:                      "valid proximity placement group ID",
+			proximityPlacementGroupID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"),
+			wantErr:                   false,
+		},
+		{
+			name:                      "proximity placement group name",
+			proximityPlacementGroupID: to.StringPtr("my-ppg"),
+			wantErr:                   true,
+		},
+		{
+			name:                      "ID of another resource type",
+			proximityPlacementGroupID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/availabilitySets/my-as"),
+			wantErr:                   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateProximityPlacementGroupID(test.proximityPlacementGroupID, field.NewPath("proximityPlacementGroupID"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateCapacityReservationGroupID(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name                       string
+		capacityReservationGroupID *string
+		spotVMOptions              *SpotVMOptions
+		wantErr                    bool
+	}{
+		{
+			name:                       "no capacity reservation group",
+			capacityReservationGroupID: nil,
+			wantErr:                    false,
+		},
+		{
+			name:                       "valid capacity reservation group ID",
+			capacityReservationGroupID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"),
+			wantErr:                    false,
+		},
+		{
+			name:                       "capacity reservation group name",
+			capacityReservationGroupID: to.StringPtr("my-crg"),
+			wantErr:                    true,
+		},
+		{
+			name:                       "ID of another resource type",
+			capacityReservationGroupID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"),
+			wantErr:                    true,
+		},
+		{
+			name:                       "capacity reservation group with Spot VM",
+			capacityReservationGroupID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"),
+			spotVMOptions:              &SpotVMOptions{},
+			wantErr:                    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateCapacityReservationGroupID(test.capacityReservationGroupID, test.spotVMOptions, field.NewPath("capacityReservationGroupID"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateComputerNamePrefix(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		prefix  string
+		wantErr bool
+	}{
+		{
+			name:    "no prefix",
+			prefix:  "",
+			wantErr: false,
+		},
+		{
+			name:    "valid prefix",
+			prefix:  "web-eu",
+			wantErr: false,
+		},
+		{
+			name:    "prefix starting with a digit",
+			prefix:  "1web",
+			wantErr: true,
+		},
+		{
+			name:    "prefix with an underscore",
+			prefix:  "web_eu",
+			wantErr: true,
+		},
+		{
+			name:    "prefix too long",
+			prefix:  "webservers",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateComputerNamePrefix(test.prefix, 9, field.NewPath("computerNamePrefix"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateLicenseType(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		licenseType LicenseType
+		osType      string
+		wantErr     bool
+	}{
+		{
+			name:        "no license type",
+			licenseType: "",
+			osType:      "Linux",
+			wantErr:     false,
+		},
+		{
+			name:        "windows server license on windows",
+			licenseType: LicenseTypeWindowsServer,
+			osType:      "Windows",
+			wantErr:     false,
+		},
+		{
+			name:        "rhel subscription on linux",
+			licenseType: LicenseTypeRHELBYOS,
+			osType:      "Linux",
+			wantErr:     false,
+		},
+		{
+			name:        "sles subscription on linux",
+			licenseType: LicenseTypeSLESBYOS,
+			osType:      "Linux",
+			wantErr:     false,
+		},
+		{
+			name:        "windows server license on linux",
+			licenseType: LicenseTypeWindowsServer,
+			osType:      "Linux",
+			wantErr:     true,
+		},
+		{
+			name:        "rhel subscription on windows",
+			licenseType: LicenseTypeRHELBYOS,
+			osType:      "Windows",
+			wantErr:     true,
+		},
+		{
+			name:        "unsupported license type",
+			licenseType: "Windows_Client",
+			osType:      "Windows",
+			wantErr:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateLicenseType(test.licenseType, OSDisk{OSType: test.osType}, field.NewPath("licenseType"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateWriteAccelerator(t *testing.T) {
+	g := NewWithT(t)
+
+	premiumDisk := func(cachingType string) DataDisk {
+		return DataDisk{
+			NameSuffix: "my_disk",
+			DiskSizeGB: 64,
+			Lun:        to.Int32Ptr(0),
+			ManagedDisk: &ManagedDiskParameters{
+				StorageAccountType: string(compute.StorageAccountTypesPremiumLRS),
+			},
+			CachingType:             cachingType,
+			WriteAcceleratorEnabled: to.BoolPtr(true),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		vmSize  string
+		disks   []DataDisk
+		wantErr bool
+	}{
+		{
+			name:    "write accelerator disabled on a non M-series VM",
+			vmSize:  "Standard_D4s_v3",
+			disks:   []DataDisk{{NameSuffix: "my_disk", DiskSizeGB: 64, Lun: to.Int32Ptr(0), WriteAcceleratorEnabled: to.BoolPtr(false)}},
+			wantErr: false,
+		},
+		{
+			name:    "write accelerator on an M-series VM",
+			vmSize:  "Standard_M32ms",
+			disks:   []DataDisk{premiumDisk("None")},
+			wantErr: false,
+		},
+		{
+			name:    "write accelerator with read only caching",
+			vmSize:  "Standard_M208ms_v2",
+			disks:   []DataDisk{premiumDisk("ReadOnly")},
+			wantErr: false,
+		},
+		{
+			name:    "write accelerator on a non M-series VM",
+			vmSize:  "Standard_D4s_v3",
+			disks:   []DataDisk{premiumDisk("None")},
+			wantErr: true,
+		},
+		{
+			name:    "write accelerator with read write caching",
+			vmSize:  "Standard_M32ms",
+			disks:   []DataDisk{premiumDisk("ReadWrite")},
+			wantErr: true,
+		},
+		{
+			name:   "write accelerator on a standard disk",
+			vmSize: "Standard_M32ms",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk",
+					DiskSizeGB: 64,
+					Lun:        to.Int32Ptr(0),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(compute.StorageAccountTypesStandardSSDLRS),
+					},
+					WriteAcceleratorEnabled: to.BoolPtr(true),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "write accelerator without a storage account type",
+			vmSize: "Standard_M32ms",
+			disks: []DataDisk{
+				{
+					NameSuffix:              "my_disk",
+					DiskSizeGB:              64,
+					Lun:                     to.Int32Ptr(0),
+					WriteAcceleratorEnabled: to.BoolPtr(true),
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateWriteAccelerator(test.vmSize, test.disks, field.NewPath("dataDisks"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidatePerformanceTier(t *testing.T) {
+	g := NewWithT(t)
+
+	dataDisk := func(storageAccountType string, diskSizeGB int32, tier string) DataDisk {
+		return DataDisk{
+			NameSuffix: "my_disk",
+			DiskSizeGB: diskSizeGB,
+			Lun:        to.Int32Ptr(0),
+			ManagedDisk: &ManagedDiskParameters{
+				StorageAccountType: storageAccountType,
+				PerformanceTier:    tier,
+			},
+			CachingType: "None",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		disks   []DataDisk
+		wantErr bool
+	}{
+		{
+			name:    "no performance tier",
+			disks:   []DataDisk{dataDisk(string(compute.StorageAccountTypesStandardLRS), 128, "")},
+			wantErr: false,
+		},
+		{
+			name:    "performance tier above the baseline",
+			disks:   []DataDisk{dataDisk(string(compute.StorageAccountTypesPremiumLRS), 128, "P30")},
+			wantErr: false,
+		},
+		{
+			name:    "performance tier equal to the baseline",
+			disks:   []DataDisk{dataDisk(string(compute.StorageAccountTypesPremiumZRS), 512, "P20")},
+			wantErr: false,
+		},
+		{
+			name:    "performance tier below the baseline",
+			disks:   []DataDisk{dataDisk(string(compute.StorageAccountTypesPremiumLRS), 512, "P10")},
+			wantErr: true,
+		},
+		{
+			name:    "performance tier on a standard disk",
+			disks:   []DataDisk{dataDisk(string(compute.StorageAccountTypesStandardSSDLRS), 128, "P30")},
+			wantErr: true,
+		},
+		{
+			name:    "unknown performance tier",
+			disks:   []DataDisk{dataDisk(string(compute.StorageAccountTypesPremiumLRS), 128, "P35")},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateDataDisks(test.disks, field.NewPath("dataDisks"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name     string
+		disks    []DataDisk
+		oldDisks []DataDisk
+		wantErr  bool
+	}{
+		{
+			name:     "valid nil data disks",
+			disks:    nil,
+			oldDisks: nil,
+			wantErr:  false,
+		},
+		{
+			name:     "valid empty data disks",
+			disks:    []DataDisk{},
+			oldDisks: []DataDisk{},
+			wantErr:  false,
+		},
+		{
+			name: "valid data disk updates",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk",
+					DiskSizeGB: 64,
+					Lun:        to.Int32Ptr(0),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+				{
+					NameSuffix: "my_other_disk",
+					DiskSizeGB: 64,
+					Lun:        to.Int32Ptr(1),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk",
+					DiskSizeGB: 64,
+					Lun:        to.Int32Ptr(0),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+				{
+					NameSuffix: "my_other_disk",
+					DiskSizeGB: 64,
+					Lun:        to.Int32Ptr(1),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cannot update data disk fields after machine creation",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					Lun:         to.Int32Ptr(0),
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 128,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					Lun:         to.Int32Ptr(0),
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validate updates to optional fields",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 128,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					Lun: to.Int32Ptr(0),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix:  "my_disk_1",
+					DiskSizeGB:  128,
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fields of the remaining data disks cannot be updated when removing data disks",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					Lun:         to.Int32Ptr(0),
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					Lun:         to.Int32Ptr(0),
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+				{
+					NameSuffix: "my_disk_2",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
 					Lun:         to.Int32Ptr(2),
 					CachingType: string(compute.PossibleCachingTypesValues()[0]),
 				},
@@ -604,7 +1276,7 @@ func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "data disks cannot be removed after machine creation",
+			name: "data disks can be added after machine creation",
 			disks: []DataDisk{
 				{
 					NameSuffix: "my_disk_1",
@@ -636,6 +1308,110 @@ func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
 					CachingType: string(compute.PossibleCachingTypesValues()[0]),
 				},
 			},
+			wantErr: false,
+		},
+		{
+			name: "data disks can be removed after machine creation",
+			disks: []DataDisk{
+				{
+					NameSuffix:  "my_disk_1",
+					DiskSizeGB:  64,
+					Lun:         to.Int32Ptr(0),
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix:  "my_disk_1",
+					DiskSizeGB:  64,
+					Lun:         to.Int32Ptr(0),
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+				{
+					NameSuffix:  "my_disk_2",
+					DiskSizeGB:  64,
+					Lun:         to.Int32Ptr(1),
+					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete option defaulting is not an update",
+			disks: []DataDisk{
+				{
+					NameSuffix:   "my_disk_1",
+					DiskSizeGB:   64,
+					Lun:          to.Int32Ptr(0),
+					DeleteOption: DiskDeleteOptionDelete,
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					Lun:        to.Int32Ptr(0),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cannot update the delete option after machine creation",
+			disks: []DataDisk{
+				{
+					NameSuffix:   "my_disk_1",
+					DiskSizeGB:   64,
+					Lun:          to.Int32Ptr(0),
+					DeleteOption: DiskDeleteOptionDetach,
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix:   "my_disk_1",
+					DiskSizeGB:   64,
+					Lun:          to.Int32Ptr(0),
+					DeleteOption: DiskDeleteOptionDelete,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cannot enable write accelerator after machine creation",
+			disks: []DataDisk{
+				{
+					NameSuffix:              "my_disk_1",
+					DiskSizeGB:              64,
+					Lun:                     to.Int32Ptr(0),
+					WriteAcceleratorEnabled: to.BoolPtr(true),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					Lun:        to.Int32Ptr(0),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cannot change maxShares after machine creation",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					Lun:        to.Int32Ptr(0),
+					MaxShares:  to.Int32Ptr(3),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					Lun:        to.Int32Ptr(0),
+					MaxShares:  to.Int32Ptr(2),
+				},
+			},
 			wantErr: true,
 		},
 	}
@@ -651,3 +1427,73 @@ func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureMachine_ValidateDiagnostics(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		diagnostics *Diagnostics
+		wantErr     bool
+	}{
+		{
+			name:        "no diagnostics",
+			diagnostics: nil,
+			wantErr:     false,
+		},
+		{
+			name:        "managed storage account",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{StorageAccountType: ManagedDiagnosticsStorage}},
+			wantErr:     false,
+		},
+		{
+			name:        "disabled",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{StorageAccountType: DisabledDiagnosticsStorage}},
+			wantErr:     false,
+		},
+		{
+			name: "user managed storage account",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{
+				StorageAccountType: UserManagedDiagnosticsStorage,
+				UserManaged:        &UserManagedBootDiagnostics{StorageAccountURI: "https://fake.blob.core.windows.net/"},
+			}},
+			wantErr: false,
+		},
+		{
+			name:        "user managed storage account without URI",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{StorageAccountType: UserManagedDiagnosticsStorage}},
+			wantErr:     true,
+		},
+		{
+			name: "user managed storage account with an http URI",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{
+				StorageAccountType: UserManagedDiagnosticsStorage,
+				UserManaged:        &UserManagedBootDiagnostics{StorageAccountURI: "http://fake.blob.core.windows.net/"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "managed storage account referencing a user managed storage account",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{
+				StorageAccountType: ManagedDiagnosticsStorage,
+				UserManaged:        &UserManagedBootDiagnostics{StorageAccountURI: "https://fake.blob.core.windows.net/"},
+			}},
+			wantErr: true,
+		},
+		{
+			name:        "unknown storage account type",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{StorageAccountType: "Premium"}},
+			wantErr:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateDiagnostics(test.diagnostics, field.NewPath("diagnostics"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
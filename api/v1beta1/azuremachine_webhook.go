@@ -35,6 +35,10 @@ func (m *AzureMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachine,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,versions=v1beta1,name=validation.azuremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachine,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,versions=v1beta1,name=default.azuremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// The image policy of new AzureMachines is enforced by a separate webhook which has access to Azure, see the imagepolicy package.
+// +kubebuilder:webhook:verbs=create,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachine-image,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,versions=v1beta1,name=imagepolicy.azuremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// The support of encryption at host by the VM size is validated by another webhook with access to Azure, see the encryptionathost package.
+// +kubebuilder:webhook:verbs=create,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachine-encryptionathost,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,versions=v1beta1,name=encryptionathost.azuremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 var _ webhook.Validator = &AzureMachine{}
 
@@ -80,18 +84,20 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
-	if !reflect.DeepEqual(m.Spec.OSDisk, old.Spec.OSDisk) {
+	// the performance tier is the only field of the OS disk which can be changed in place.
+	if !reflect.DeepEqual(osDiskWithoutPerformanceTier(m.Spec.OSDisk), osDiskWithoutPerformanceTier(old.Spec.OSDisk)) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "osDisk"),
 				m.Spec.OSDisk, "field is immutable"),
 		)
+	} else if !reflect.DeepEqual(m.Spec.OSDisk, old.Spec.OSDisk) {
+		allErrs = append(allErrs, ValidateOSDisk(m.Spec.OSDisk, field.NewPath("spec", "osDisk"))...)
 	}
 
 	if !reflect.DeepEqual(m.Spec.DataDisks, old.Spec.DataDisks) {
-		allErrs = append(allErrs,
-			field.Invalid(field.NewPath("spec", "dataDisks"),
-				m.Spec.DataDisks, "field is immutable"),
-		)
+		allErrs = append(allErrs, ValidateDataDisks(m.Spec.DataDisks, field.NewPath("spec", "dataDisks"))...)
+		allErrs = append(allErrs, ValidateDataDisksUpdate(old.Spec.DataDisks, m.Spec.DataDisks, field.NewPath("spec", "dataDisks"))...)
+		allErrs = append(allErrs, ValidateWriteAccelerator(m.Spec.VMSize, m.Spec.DataDisks, field.NewPath("spec", "dataDisks"))...)
 	}
 
 	if !reflect.DeepEqual(m.Spec.SSHPublicKey, old.Spec.SSHPublicKey) {
@@ -136,6 +142,34 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	if !reflect.DeepEqual(m.Spec.ProximityPlacementGroupID, old.Spec.ProximityPlacementGroupID) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "proximityPlacementGroupID"),
+				m.Spec.ProximityPlacementGroupID, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.CapacityReservationGroupID, old.Spec.CapacityReservationGroupID) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "capacityReservationGroupID"),
+				m.Spec.CapacityReservationGroupID, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.ComputerNamePrefix, old.Spec.ComputerNamePrefix) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "computerNamePrefix"),
+				m.Spec.ComputerNamePrefix, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.LicenseType, old.Spec.LicenseType) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "licenseType"),
+				m.Spec.LicenseType, "field is immutable"),
+		)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -147,6 +181,15 @@ func (m *AzureMachine) ValidateDelete() error {
 	return nil
 }
 
+// osDiskWithoutPerformanceTier returns a copy of the OS disk without its performance tier.
+func osDiskWithoutPerformanceTier(osDisk OSDisk) OSDisk {
+	result := *osDisk.DeepCopy()
+	if result.ManagedDisk != nil {
+		result.ManagedDisk.PerformanceTier = ""
+	}
+	return result
+}
+
 // Default implements webhookutil.defaulter so a webhook will be registered for the type.
 func (m *AzureMachine) Default() {
 	m.Spec.SetDefaults()
@@ -290,6 +290,64 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "validTest: azuremachine.spec.OSDisk performance tier can be changed",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "Linux",
+						DiskSizeGB: pointer.Int32Ptr(128),
+						ManagedDisk: &ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+						},
+						CachingType: "None",
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "Linux",
+						DiskSizeGB: pointer.Int32Ptr(128),
+						ManagedDisk: &ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+							PerformanceTier:    "P30",
+						},
+						CachingType: "None",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.OSDisk performance tier below the baseline",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "Linux",
+						DiskSizeGB: pointer.Int32Ptr(512),
+						ManagedDisk: &ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+						},
+						CachingType: "None",
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					OSDisk: OSDisk{
+						OSType:     "Linux",
+						DiskSizeGB: pointer.Int32Ptr(512),
+						ManagedDisk: &ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+							PerformanceTier:    "P10",
+						},
+						CachingType: "None",
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.DataDisks is immutable",
 			oldMachine: &AzureMachine{
@@ -510,6 +568,70 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.ProximityPlacementGroupID is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					ProximityPlacementGroupID: pointer.String("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"),
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.CapacityReservationGroupID is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					CapacityReservationGroupID: pointer.String("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.ComputerNamePrefix is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					ComputerNamePrefix: "web",
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					ComputerNamePrefix: "api",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.LicenseType is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					LicenseType: LicenseTypeRHELBYOS,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.PowerState is mutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					PowerState: VMPowerStateRunning,
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					PowerState: VMPowerStateHibernated,
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -42,12 +42,23 @@ const (
 	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
 	// WaitingForBootstrapDataReason used when machine is waiting for bootstrap data to be ready before proceeding.
 	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
+	// BootstrapTokenExpiringReason used when the kubeadm bootstrap token of the bootstrap data expires before a new VM
+	// would have time to join the cluster, and the machine is waiting for the bootstrap provider to refresh it.
+	BootstrapTokenExpiringReason = "BootstrapTokenExpiring"
 	// BootstrapSucceededCondition reports the result of the execution of the boostrap data on the machine.
 	BootstrapSucceededCondition = "BoostrapSucceeded"
 	// BootstrapInProgressReason is used to indicate the bootstrap data has not finished executing.
 	BootstrapInProgressReason = "BootstrapInProgress"
 	// BootstrapFailedReason is used to indicate the bootstrap process ran into an error.
 	BootstrapFailedReason = "BootstrapFailed"
+	// NetworkInterfacesReadyCondition reports on the public IPs, inbound NAT rules and network interfaces of the machine.
+	NetworkInterfacesReadyCondition clusterv1.ConditionType = "NetworkInterfacesReady"
+	// VMExtensionsReadyCondition reports on the creation of the VM extensions of the machine.
+	VMExtensionsReadyCondition clusterv1.ConditionType = "VMExtensionsReady"
+	// NICPoolReadyCondition reports on the refill of the pool of spare network interfaces the machine was claimed from.
+	NICPoolReadyCondition clusterv1.ConditionType = "NICPoolReady"
+	// NICPoolRefillFailedReason is used when the spare network interfaces of the pool could not be created or deleted.
+	NICPoolRefillFailedReason = "NICPoolRefillFailed"
 )
 
 // AzureMachinePool Conditions and Reasons.
@@ -74,6 +85,110 @@ const (
 	ScaleSetModelUpdatedCondition clusterv1.ConditionType = "ScaleSetModelUpdated"
 	// ScaleSetModelOutOfDateReason describes the machine pool model being out of date.
 	ScaleSetModelOutOfDateReason = "ScaleSetModelOutOfDate"
+
+	// SpotPlacementScoreReadyCondition reports on the Spot Placement Score of the machine pool before the scale set is created.
+	SpotPlacementScoreReadyCondition clusterv1.ConditionType = "SpotPlacementScoreReady"
+	// SpotPlacementScoreBelowThresholdReason describes a Spot Placement Score lower than the configured threshold.
+	SpotPlacementScoreBelowThresholdReason = "SpotPlacementScoreBelowThreshold"
+	// SpotPlacementScoreUnavailableReason describes a Spot Placement Score that could not be retrieved.
+	SpotPlacementScoreUnavailableReason = "SpotPlacementScoreUnavailable"
+)
+
+// AzureMachinePoolMachine Scheduled Event Conditions and Reasons.
+const (
+	// ScheduledEventDrainedCondition reports whether the node of an instance is cordoned and drained because Azure is
+	// about to evict it or to perform maintenance on it. It is only set while an event is reported for the instance.
+	ScheduledEventDrainedCondition clusterv1.ConditionType = "ScheduledEventDrained"
+	// SpotEvictionReason describes a Spot instance being evicted.
+	SpotEvictionReason = "SpotEviction"
+	// PlannedMaintenanceReason describes an instance about to be moved to another host for planned maintenance.
+	PlannedMaintenanceReason = "PlannedMaintenance"
+	// ScheduledEventDrainFailedReason describes a node which couldn't be drained ahead of a scheduled event.
+	ScheduledEventDrainFailedReason = "ScheduledEventDrainFailed"
+	// ScheduledEventsReportedCondition reports whether the nodes of an AzureMachinePool handling scheduled events report
+	// them with the node condition of its policy. It is only set while the pool handles scheduled events.
+	ScheduledEventsReportedCondition clusterv1.ConditionType = "ScheduledEventsReported"
+	// ScheduledEventsNodeConditionMissingReason describes a pool whose nodes don't report the node condition of its
+	// policy, usually because no agent polling the scheduled events runs on them.
+	ScheduledEventsNodeConditionMissingReason = "ScheduledEventsNodeConditionMissing"
+)
+
+// AzureMachinePoolMachine Remediation Conditions and Reasons.
+const (
+	// InstanceRemediatedCondition reports whether the failed instance of an AzureMachinePoolMachine was remediated in
+	// place. It is false from the start of the remediation until the machine is ready again, and only set when the pool
+	// remediates its failed instances in place.
+	InstanceRemediatedCondition clusterv1.ConditionType = "InstanceRemediated"
+	// InstanceReimagingReason describes a failed instance being reimaged.
+	InstanceReimagingReason = "InstanceReimaging"
+	// InstanceRedeployingReason describes a failed instance being redeployed to another host.
+	InstanceRedeployingReason = "InstanceRedeploying"
+)
+
+// AzureMachine Image Replication Conditions and Reasons.
+const (
+	// ImageReplicatedCondition reports whether the gallery image version of the machine is replicated into its location
+	// before the VM is created. It is only set when the controller checks the replication of gallery image versions.
+	ImageReplicatedCondition clusterv1.ConditionType = "ImageReplicated"
+	// ImageNotReplicatedReason describes a gallery image version which doesn't target the location of the machine.
+	ImageNotReplicatedReason = "ImageNotReplicated"
+	// ImageReplicatingReason describes a gallery image version being replicated into the location of the machine.
+	ImageReplicatingReason = "ImageReplicating"
+	// ImageReplicationFailedReason describes a gallery image version whose replication into the location of the machine
+	// failed, or couldn't be checked.
+	ImageReplicationFailedReason = "ImageReplicationFailed"
+)
+
+// AzureMachine Power State Conditions and Reasons.
+const (
+	// VMPowerStateReadyCondition reports whether the VM of the machine is in its desired power state. It is only set when
+	// the AzureMachine has a desired power state.
+	VMPowerStateReadyCondition clusterv1.ConditionType = "VMPowerStateReady"
+	// VMPowerStateChangingReason describes a VM being started, deallocated or hibernated.
+	VMPowerStateChangingReason = "VMPowerStateChanging"
+	// VMPowerStateFailedReason describes a VM whose power state couldn't be checked or changed.
+	VMPowerStateFailedReason = "VMPowerStateFailed"
+)
+
+// AzureCluster API Version Profile Conditions and Reasons.
+const (
+	// APIVersionProfileCompatibleCondition reports whether the API versions of the API version profile support the
+	// features used by the cluster.
+	APIVersionProfileCompatibleCondition clusterv1.ConditionType = "APIVersionProfileCompatible"
+	// UnsupportedCapabilitiesReason describes features which require newer API versions than the ones of the profile.
+	UnsupportedCapabilitiesReason = "UnsupportedCapabilities"
+)
+
+// AzureCluster Provisioning SLO Conditions and Reasons.
+const (
+	// ProvisioningSLOMetCondition reports whether the first node of the cluster became ready within the provisioning
+	// SLO of the controller. It is only set when the controller has a provisioning SLO.
+	ProvisioningSLOMetCondition clusterv1.ConditionType = "ProvisioningSLOMet"
+	// ProvisioningSLOExceededReason describes a cluster whose first node didn't become ready within the provisioning SLO.
+	ProvisioningSLOExceededReason = "ProvisioningSLOExceeded"
+)
+
+// AzureCluster Network Deployment Conditions and Reasons.
+const (
+	// NetworkDeployedCondition reports whether the initial network of the cluster was provisioned by a single ARM
+	// deployment. It is only set when the BulkProvisioning feature is enabled, and the network is reconciled resource by
+	// resource once it is true or the deployment failed.
+	NetworkDeployedCondition clusterv1.ConditionType = "NetworkDeployed"
+	// NetworkDeployingReason describes an ARM deployment of the initial network in progress.
+	NetworkDeployingReason = "NetworkDeploying"
+	// NetworkDeploymentFailedReason describes an ARM deployment of the initial network which failed, or couldn't be
+	// started.
+	NetworkDeploymentFailedReason = "NetworkDeploymentFailed"
+)
+
+// AzureCluster Resource Ownership Conditions and Reasons.
+const (
+	// ResourceOwnershipVerifiedCondition reports whether all the resources which look like they were created for the
+	// cluster are tagged as owned by it, so that they are deleted with the cluster.
+	ResourceOwnershipVerifiedCondition clusterv1.ConditionType = "ResourceOwnershipVerified"
+	// OwnershipTagMissingReason describes resources which look like they were created for the cluster but lost their
+	// ownership tag, and can't be adopted again safely.
+	OwnershipTagMissingReason = "OwnershipTagMissing"
 )
 
 // Azure Services Conditions and Reasons.
@@ -86,6 +201,12 @@ const (
 	VnetPeeringReadyCondition clusterv1.ConditionType = "VnetPeeringReady"
 	// SecurityGroupsReadyCondition means the security groups exist and are ready to be used.
 	SecurityGroupsReadyCondition clusterv1.ConditionType = "SecurityGroupsReady"
+	// FlowLogsReadyCondition means the NSG flow logs exist and are ready to be used.
+	FlowLogsReadyCondition clusterv1.ConditionType = "FlowLogsReady"
+	// PrivateEndpointsReadyCondition means the private endpoints exist and are ready to be used.
+	PrivateEndpointsReadyCondition clusterv1.ConditionType = "PrivateEndpointsReady"
+	// PrivateLinkServiceReadyCondition means the private link service of the API server exists and is ready to be used.
+	PrivateLinkServiceReadyCondition clusterv1.ConditionType = "PrivateLinkServiceReady"
 	// RouteTablesReadyCondition means the route tables exist and are ready to be used.
 	RouteTablesReadyCondition clusterv1.ConditionType = "RouteTablesReady"
 	// PublicIPsReadyCondition means the public IPs exist and are ready to be used.
@@ -108,6 +229,8 @@ const (
 	RoleAssignmentReadyCondition clusterv1.ConditionType = "RoleAssignmentReady"
 	// DisksReadyCondition means the disks exist and are ready to be used.
 	DisksReadyCondition clusterv1.ConditionType = "DisksReady"
+	// DiskEncryptionSetsReadyCondition means the disk encryption sets exist and have access to their key vaults.
+	DiskEncryptionSetsReadyCondition clusterv1.ConditionType = "DiskEncryptionSetsReady"
 
 	// CreatingReason means the resource is being created.
 	CreatingReason = "Creating"
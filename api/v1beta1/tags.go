@@ -116,6 +116,10 @@ const (
 	// dedicated to this cluster api provider implementation.
 	NameAzureClusterAPIRole = NameAzureProviderPrefix + "role"
 
+	// NameAzureProviderMachine is the tag name we use to record the name of the machine a data disk kept after the
+	// deletion of the machine belonged to.
+	NameAzureProviderMachine = NameAzureProviderPrefix + "machine"
+
 	// APIServerRole describes the value for the apiserver role.
 	APIServerRole = "apiserver"
 
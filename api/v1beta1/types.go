@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strings"
+
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
@@ -38,6 +40,8 @@ const (
 	PutFuture string = "PUT"
 	// DeleteFuture is a future that was derived from a DELETE request.
 	DeleteFuture string = "DELETE"
+	// PostFuture is a future that was derived from a POST request, e.g. an action on a virtual machine.
+	PostFuture string = "POST"
 )
 
 // Future contains the data needed for an Azure long-running operation to continue across reconcile loops.
@@ -87,6 +91,192 @@ type NetworkSpec struct {
 	// PrivateDNSZoneName defines the zone name for the Azure Private DNS.
 	// +optional
 	PrivateDNSZoneName string `json:"privateDNSZoneName,omitempty"`
+
+	// PrivateDNSZone references an existing Azure Private DNS zone used to resolve the API server of a private cluster,
+	// instead of the zone created for the cluster. The zone is never deleted: only the API server record and the
+	// virtual network links are managed. It cannot be set together with PrivateDNSZoneName.
+	// +optional
+	PrivateDNSZone *PrivateDNSZoneSpec `json:"privateDNSZone,omitempty"`
+
+	// PrivateDNSRecords are additional records of the private DNS zone of a private cluster, reconciled alongside the
+	// API server record, e.g. to resolve a private endpoint from the nodes.
+	// +optional
+	PrivateDNSRecords []PrivateDNSRecord `json:"privateDNSRecords,omitempty"`
+
+	// APIServerPublicDNS publishes the endpoint of a public API server as a record of an existing Azure DNS zone, so
+	// that the cluster can be reached through a friendly FQDN like "api.cluster1.example.com".
+	// +optional
+	APIServerPublicDNS *PublicDNSRecordSpec `json:"apiServerPublicDNS,omitempty"`
+
+	// FlowLogs enables NSG flow logs for the network security groups of the subnets of a managed virtual network.
+	// +optional
+	FlowLogs *FlowLogsSpec `json:"flowLogs,omitempty"`
+
+	// APIServerPrivateLinkService exposes an internal API server load balancer through an Azure Private Link Service,
+	// so that clients in other virtual networks or tenants can reach the API server through a private endpoint.
+	// +optional
+	APIServerPrivateLinkService *PrivateLinkServiceSpec `json:"apiServerPrivateLinkService,omitempty"`
+
+	// ExpressRouteGateway connects the virtual network to an ExpressRoute circuit through a virtual network gateway.
+	// +optional
+	ExpressRouteGateway *ExpressRouteGateway `json:"expressRouteGateway,omitempty"`
+
+	// ManagementNetwork is the network of the management cluster, which reaches a private API server through the
+	// network of the cluster rather than through a public endpoint.
+	// +optional
+	ManagementNetwork *ManagementNetworkSpec `json:"managementNetwork,omitempty"`
+
+	// Firewall routes all the egress traffic of the nodes through an Azure Firewall, which then filters it.
+	// +optional
+	Firewall *AzureFirewall `json:"firewall,omitempty"`
+
+	// RetainPublicIPsOnDelete keeps the public IPs of the load balancers and NAT gateways when the cluster is deleted,
+	// e.g. because external parties allow the egress traffic of the cluster from these IPs. The resource group of the
+	// cluster is then kept too, and a cluster created again with the same name reuses the public IPs.
+	// +optional
+	RetainPublicIPsOnDelete bool `json:"retainPublicIPsOnDelete,omitempty"`
+}
+
+// ManagementNetworkSpec defines the network of the management cluster.
+type ManagementNetworkSpec struct {
+	// CIDRBlocks are the address ranges of the management cluster. The security group of the control plane subnet
+	// allows them to reach the API server.
+	// +kubebuilder:validation:MinItems=1
+	CIDRBlocks []string `json:"cidrBlocks"`
+
+	// Vnet is the virtual network of the management cluster. When set, the virtual network of the cluster is peered
+	// with it and it is linked to the private DNS zone of the API server.
+	// +optional
+	Vnet *VnetPeeringSpec `json:"vnet,omitempty"`
+}
+
+// PrivateDNSZoneSpec references an existing Azure Private DNS zone.
+type PrivateDNSZoneSpec struct {
+	// ID is the Azure resource ID of the private DNS zone. The zone can be in another resource group or subscription
+	// than the cluster, as long as the cluster identity is allowed to manage its records and virtual network links.
+	ID string `json:"id"`
+
+	// SkipVirtualNetworkLinks disables the creation of the links between the zone and the virtual network of the
+	// cluster and its peerings, e.g. when the zone is already linked to a central DNS resolver.
+	// +optional
+	SkipVirtualNetworkLinks bool `json:"skipVirtualNetworkLinks,omitempty"`
+}
+
+// PrivateDNSRecord defines a record of the private DNS zone. Exactly one of IP and CNAME must be set.
+type PrivateDNSRecord struct {
+	// Hostname is the name of the record relative to the zone, e.g. "registry".
+	Hostname string `json:"hostname"`
+
+	// IP is the IPv4 or IPv6 address of an A or AAAA record.
+	// +optional
+	IP string `json:"ip,omitempty"`
+
+	// CNAME is the canonical name of a CNAME record.
+	// +optional
+	CNAME string `json:"cname,omitempty"`
+}
+
+// PublicDNSRecordType is the type of the record of a public API server in an Azure DNS zone.
+type PublicDNSRecordType string
+
+const (
+	// PublicDNSRecordTypeA is an A record to the address of the public IP of the API server.
+	PublicDNSRecordTypeA PublicDNSRecordType = "A"
+	// PublicDNSRecordTypeCNAME is a CNAME record to the FQDN of the public IP of the API server.
+	PublicDNSRecordTypeCNAME PublicDNSRecordType = "CNAME"
+)
+
+// PublicDNSRecordSpec defines a record of the API server in an existing Azure DNS zone.
+type PublicDNSRecordSpec struct {
+	// ZoneID is the Azure resource ID of the DNS zone, e.g. of the zone "example.com". The zone can be in another
+	// resource group or subscription than the cluster, as long as the cluster identity is allowed to manage its records.
+	ZoneID string `json:"zoneID"`
+
+	// Name is the name of the record relative to the zone, e.g. "api.cluster1".
+	Name string `json:"name"`
+
+	// Type is the type of the record. Defaults to CNAME.
+	// +kubebuilder:validation:Enum=A;CNAME
+	// +optional
+	Type PublicDNSRecordType `json:"type,omitempty"`
+
+	// TTL is the time to live of the record in seconds. Defaults to 300.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
+}
+
+// PrivateLinkServiceSpec configures an Azure Private Link Service bound to the frontend of a load balancer.
+type PrivateLinkServiceSpec struct {
+	// Name is the name of the private link service. Defaults to the name of the load balancer with the suffix "-pls".
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// NATIPCount is the number of private IP addresses of the control plane subnet the private link service translates
+	// the source address of the connections to. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=8
+	// +optional
+	NATIPCount *int32 `json:"natIPCount,omitempty"`
+
+	// Visibility is the list of subscription IDs allowed to create a private endpoint connecting to the private link
+	// service. Use "*" to allow all subscriptions. Only the subscription of the cluster is allowed if unset.
+	// +optional
+	Visibility []string `json:"visibility,omitempty"`
+
+	// AutoApproval is the list of subscription IDs whose private endpoint connections are approved automatically.
+	// Connections from other subscriptions must be approved manually.
+	// +optional
+	AutoApproval []string `json:"autoApproval,omitempty"`
+}
+
+// FlowLogsSpec configures the NSG flow logs written by an Azure Network Watcher.
+type FlowLogsSpec struct {
+	// StorageAccountID is the Azure resource ID of the storage account the flow logs are written to.
+	// The storage account must be in the same region as the cluster.
+	StorageAccountID string `json:"storageAccountID"`
+
+	// RetentionDays is the number of days flow logs are kept in the storage account. Flow logs are kept forever if unset.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=365
+	// +optional
+	RetentionDays int32 `json:"retentionDays,omitempty"`
+
+	// TrafficAnalytics sends the flow logs to a Log Analytics workspace for Traffic Analytics.
+	// +optional
+	TrafficAnalytics *TrafficAnalyticsSpec `json:"trafficAnalytics,omitempty"`
+
+	// NetworkWatcher is the Network Watcher writing the flow logs. It is created if it does not exist.
+	// Defaults to the Network Watcher Azure enables for the region of the cluster.
+	// +optional
+	NetworkWatcher *NetworkWatcherSpec `json:"networkWatcher,omitempty"`
+}
+
+// TrafficAnalyticsSpec configures Traffic Analytics for NSG flow logs.
+type TrafficAnalyticsSpec struct {
+	// WorkspaceResourceID is the Azure resource ID of the Log Analytics workspace.
+	WorkspaceResourceID string `json:"workspaceResourceID"`
+
+	// WorkspaceID is the workspace ID, a GUID, of the Log Analytics workspace.
+	WorkspaceID string `json:"workspaceID"`
+
+	// WorkspaceRegion is the region of the Log Analytics workspace. Defaults to the location of the cluster.
+	// +optional
+	WorkspaceRegion string `json:"workspaceRegion,omitempty"`
+
+	// IntervalInMinutes is how often flow logs are processed by Traffic Analytics. Defaults to 60.
+	// +kubebuilder:validation:Enum=10;60
+	// +optional
+	IntervalInMinutes *int32 `json:"intervalInMinutes,omitempty"`
+}
+
+// NetworkWatcherSpec references an Azure Network Watcher.
+type NetworkWatcherSpec struct {
+	// Name is the name of the Network Watcher.
+	Name string `json:"name"`
+
+	// ResourceGroup is the resource group of the Network Watcher.
+	ResourceGroup string `json:"resourceGroup"`
 }
 
 // VnetSpec configures an Azure virtual network.
@@ -115,6 +305,116 @@ type VnetSpec struct {
 	// Tags is a collection of tags describing the resource.
 	// +optional
 	Tags Tags `json:"tags,omitempty"`
+
+	// DDoSProtectionPlan enables DDoS protection on a managed virtual network using the referenced, or created,
+	// DDoS Protection Plan. It is ignored for virtual networks not managed by the AzureCluster.
+	// +optional
+	DDoSProtectionPlan *DDoSProtectionPlan `json:"ddosProtectionPlan,omitempty"`
+}
+
+// DDoSProtectionPlan defines the DDoS Protection Plan associated with a virtual network.
+// Exactly one of ID or Name must be set.
+type DDoSProtectionPlan struct {
+	// ID is the Azure resource ID of an existing DDoS Protection Plan. The plan is not managed by the AzureCluster.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name is the name of a DDoS Protection Plan to create in the resource group of the virtual network.
+	// The plan is deleted with the AzureCluster.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// ExpressRouteGatewaySku is the SKU of an ExpressRoute virtual network gateway.
+type ExpressRouteGatewaySku string
+
+const (
+	// ExpressRouteGatewaySkuStandard is the Standard SKU of ExpressRoute gateways.
+	ExpressRouteGatewaySkuStandard ExpressRouteGatewaySku = "Standard"
+	// ExpressRouteGatewaySkuHighPerformance is the HighPerformance SKU of ExpressRoute gateways.
+	ExpressRouteGatewaySkuHighPerformance ExpressRouteGatewaySku = "HighPerformance"
+	// ExpressRouteGatewaySkuUltraPerformance is the UltraPerformance SKU of ExpressRoute gateways.
+	ExpressRouteGatewaySkuUltraPerformance ExpressRouteGatewaySku = "UltraPerformance"
+	// ExpressRouteGatewaySkuErGw1AZ is the zone-redundant equivalent of the Standard SKU.
+	ExpressRouteGatewaySkuErGw1AZ ExpressRouteGatewaySku = "ErGw1AZ"
+	// ExpressRouteGatewaySkuErGw2AZ is the zone-redundant equivalent of the HighPerformance SKU.
+	ExpressRouteGatewaySkuErGw2AZ ExpressRouteGatewaySku = "ErGw2AZ"
+	// ExpressRouteGatewaySkuErGw3AZ is the zone-redundant equivalent of the UltraPerformance SKU.
+	ExpressRouteGatewaySkuErGw3AZ ExpressRouteGatewaySku = "ErGw3AZ"
+)
+
+// ExpressRouteGateway defines the ExpressRoute virtual network gateway of the virtual network.
+// Exactly one of ID or Name must be set.
+type ExpressRouteGateway struct {
+	// ID is the Azure resource ID of an existing ExpressRoute gateway, e.g. of a hub virtual network the cluster
+	// virtual network is peered with. The gateway is not managed by the AzureCluster.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name is the name of an ExpressRoute gateway to create in the resource group of the virtual network.
+	// The gateway is deleted with the AzureCluster.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Sku is the SKU of a created gateway. Defaults to Standard.
+	// +kubebuilder:validation:Enum=Standard;HighPerformance;UltraPerformance;ErGw1AZ;ErGw2AZ;ErGw3AZ
+	// +optional
+	Sku ExpressRouteGatewaySku `json:"sku,omitempty"`
+
+	// Subnet is the subnet of a created gateway, which Azure requires to be named GatewaySubnet.
+	// +optional
+	Subnet SubnetSpec `json:"subnet,omitempty"`
+
+	// PublicIP is the public IP of a created gateway, which Azure uses to manage the gateway.
+	// +optional
+	PublicIP PublicIPSpec `json:"publicIP,omitempty"`
+
+	// DisableBGPRoutePropagation stops the route tables of the cluster subnets from learning the routes advertised
+	// over ExpressRoute, e.g. when the traffic to the on-premises networks must go through a firewall.
+	// +optional
+	DisableBGPRoutePropagation bool `json:"disableBGPRoutePropagation,omitempty"`
+}
+
+// AzureFirewallSkuTier is the tier of an Azure Firewall.
+type AzureFirewallSkuTier string
+
+const (
+	// AzureFirewallSkuTierStandard is the Standard tier of Azure Firewall.
+	AzureFirewallSkuTierStandard AzureFirewallSkuTier = "Standard"
+	// AzureFirewallSkuTierPremium is the Premium tier of Azure Firewall, which adds TLS inspection and IDPS.
+	AzureFirewallSkuTierPremium AzureFirewallSkuTier = "Premium"
+)
+
+// AzureFirewall defines the Azure Firewall the egress traffic of the nodes is routed through.
+// Exactly one of ID or Name must be set.
+type AzureFirewall struct {
+	// ID is the Azure resource ID of an existing Azure Firewall in the subscription of the cluster, e.g. of a hub
+	// virtual network the cluster virtual network is peered with. The firewall is not managed by the AzureCluster.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name is the name of an Azure Firewall to create in the resource group of the virtual network.
+	// The firewall is deleted with the AzureCluster.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// SkuTier is the tier of a created firewall. Defaults to Standard.
+	// +kubebuilder:validation:Enum=Standard;Premium
+	// +optional
+	SkuTier AzureFirewallSkuTier `json:"skuTier,omitempty"`
+
+	// PolicyID is the Azure resource ID of an existing firewall policy to attach to a created firewall. Azure Firewall
+	// denies all the traffic it has no rule for, so the policy must allow the egress the nodes need to bootstrap.
+	// +optional
+	PolicyID string `json:"policyID,omitempty"`
+
+	// Subnet is the subnet of a created firewall, which Azure requires to be named AzureFirewallSubnet.
+	// +optional
+	Subnet SubnetSpec `json:"subnet,omitempty"`
+
+	// PublicIP is the public IP of a created firewall, which the egress traffic of the nodes is translated to.
+	// +optional
+	PublicIP PublicIPSpec `json:"publicIP,omitempty"`
 }
 
 // VnetPeeringSpec specifies an existing remote virtual network to peer with the AzureCluster's virtual network.
@@ -147,6 +447,11 @@ type SecurityGroup struct {
 	Name string `json:"name"`
 	// +optional
 	SecurityRules SecurityRules `json:"securityRules,omitempty"`
+	// DisabledDefaultRules are the names of the default security rules CAPZ must not add to the security group,
+	// e.g. "allow_ssh" to not allow SSH to the control plane nodes. Default rules are only added to the security group
+	// of the control plane subnet.
+	// +optional
+	DisabledDefaultRules []string `json:"disabledDefaultRules,omitempty"`
 	// +optional
 	Tags Tags `json:"tags,omitempty"`
 }
@@ -210,6 +515,7 @@ type SecurityRule struct {
 	// +kubebuilder:validation:Enum=Inbound;Outbound
 	Direction SecurityRuleDirection `json:"direction"`
 	// Priority is a number between 100 and 4096. Each rule should have a unique value for priority. Rules are processed in priority order, with lower numbers processed before higher numbers. Once traffic matches a rule, processing stops.
+	// Defaults to the lowest priority from 100 not used by another rule of the same direction in the security group, outside of the band 2200-2299 reserved to the default security rules.
 	// +optional
 	Priority int32 `json:"priority,omitempty"`
 	// SourcePorts specifies source port or range. Integer or range between 0 and 65535. Asterix '*' can also be used to match all ports.
@@ -229,6 +535,31 @@ type SecurityRule struct {
 // SecurityRules is a slice of Azure security rules for security groups.
 type SecurityRules []SecurityRule
 
+const (
+	// SecurityRuleAllowSSH is the name of the default security rule allowing SSH to the control plane nodes.
+	SecurityRuleAllowSSH = "allow_ssh"
+	// SecurityRuleAllowAPIServer is the name of the default security rule allowing traffic to the API server. When the
+	// API server access profile of the cluster is set, it is replaced by a rule per allowed CIDR, named with this prefix.
+	SecurityRuleAllowAPIServer = "allow_apiserver"
+	// SecurityRuleAllowManagementAPIServer is the name prefix of the security rules allowing traffic from the management
+	// network to the API server.
+	SecurityRuleAllowManagementAPIServer = "allow_management_apiserver"
+	// SecurityRuleAllowConnectivityIngress is the name prefix of the security rules allowing the ingress ports of the
+	// connectivity profile.
+	SecurityRuleAllowConnectivityIngress = "allow_connectivity_ingress"
+	// SecurityRuleAllowConnectivityEgress is the name prefix of the security rules allowing the egress ports of the
+	// connectivity profile.
+	SecurityRuleAllowConnectivityEgress = "allow_connectivity_egress"
+
+	// DefaultSecurityRulePriorityMin is the lowest priority of the band reserved to the default security rules.
+	DefaultSecurityRulePriorityMin = 2200
+	// DefaultSecurityRulePriorityMax is the highest priority of the band reserved to the default security rules.
+	DefaultSecurityRulePriorityMax = 2299
+)
+
+// DefaultSecurityRuleNames are the names of the default security rules of the control plane subnet.
+var DefaultSecurityRuleNames = []string{SecurityRuleAllowSSH, SecurityRuleAllowAPIServer}
+
 // LoadBalancerSpec defines an Azure load balancer.
 type LoadBalancerSpec struct {
 	// ID is the Azure resource ID of the load balancer.
@@ -314,8 +645,8 @@ const (
 )
 
 // Image defines information about the image to use for VM creation.
-// There are three ways to specify an image: by ID, Marketplace Image or SharedImageGallery
-// One of ID, SharedImage or Marketplace should be set.
+// There are five ways to specify an image: by ID, Marketplace Image, SharedImageGallery, CommunityGallery or VHD
+// One of ID, SharedImage, Marketplace, CommunityGallery or VHD should be set.
 type Image struct {
 	// ID specifies an image to use by ID
 	// +optional
@@ -328,6 +659,14 @@ type Image struct {
 	// Marketplace specifies an image to use from the Azure Marketplace
 	// +optional
 	Marketplace *AzureMarketplaceImage `json:"marketplace,omitempty"`
+
+	// CommunityGallery specifies an image to use from an Azure Compute Gallery shared with the community
+	// +optional
+	CommunityGallery *AzureCommunityGalleryImage `json:"communityGallery,omitempty"`
+
+	// VHD specifies an image to use from a VHD blob in a storage account
+	// +optional
+	VHD *AzureVHDImage `json:"vhd,omitempty"`
 }
 
 // AzureMarketplaceImage defines an image in the Azure Marketplace to use for VM creation.
@@ -397,6 +736,38 @@ type AzureSharedGalleryImage struct {
 	SKU *string `json:"sku,omitempty"`
 }
 
+// AzureCommunityGalleryImage defines an image in a community gallery to use for VM creation.
+// Community galleries are shared publicly, so their images can be used without copying them into a gallery of
+// the subscription.
+type AzureCommunityGalleryImage struct {
+	// Gallery is the public name of the community gallery that contains the image,
+	// e.g. ClusterAPI-f72ceb4f-5159-4c26-a0fe-2ea738f0d019
+	// +kubebuilder:validation:MinLength=1
+	Gallery string `json:"gallery"`
+	// Name is the name of the image
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Version specifies the version of the community gallery image. The allowed formats
+	// are Major.Minor.Build or 'latest'. Major, Minor, and Build are decimal numbers.
+	// Specify 'latest' to use the latest version of an image available at deploy time.
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+}
+
+// AzureVHDImage defines a generalized VHD blob to use for VM creation.
+// A managed image is created from the VHD in the resource group of the cluster, and shared by the machines of the
+// cluster using the same VHD.
+type AzureVHDImage struct {
+	// URI is the URI of the VHD blob, e.g. https://mystorageaccount.blob.core.windows.net/vhds/ubuntu-2004.vhd
+	// The storage account must be in the same location as the cluster.
+	// +kubebuilder:validation:MinLength=1
+	URI string `json:"uri"`
+	// HyperVGeneration is the HyperV generation of the VHD, V1 if not set.
+	// +kubebuilder:validation:Enum=V1;V2
+	// +optional
+	HyperVGeneration string `json:"hyperVGeneration,omitempty"`
+}
+
 // VMIdentity defines the identity of the virtual machine, if configured.
 // +kubebuilder:validation:Enum=None;SystemAssigned;UserAssigned
 type VMIdentity string
@@ -480,14 +851,60 @@ type DataDisk struct {
 	// +optional
 	// +kubebuilder:validation:Enum=None;ReadOnly;ReadWrite
 	CachingType string `json:"cachingType,omitempty"`
+	// DeleteOption specifies what happens to the data disk when the machine is deleted or the disk is removed
+	// from the machine. Delete removes the disk, Detach only detaches it and leaves it in the resource group.
+	// Defaults to Delete.
+	// +optional
+	DeleteOption DiskDeleteOption `json:"deleteOption,omitempty"`
+	// WriteAcceleratorEnabled specifies whether Write Accelerator is enabled on the data disk, to lower the latency
+	// of its writes. It requires an M-series VM size, Premium storage and a caching type of None or ReadOnly.
+	// +optional
+	WriteAcceleratorEnabled *bool `json:"writeAcceleratorEnabled,omitempty"`
+	// MaxShares is the maximum number of VMs which can attach the data disk at the same time. A value greater than 1
+	// makes it a shared disk, which is created once for the cluster and attached to all its machines with a data disk of
+	// the same name suffix, e.g. for the quorum and data disks of a failover cluster. Shared disks outlive the machines
+	// and require a storage account type which supports sharing.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxShares *int32 `json:"maxShares,omitempty"`
+}
+
+// IsShared returns true if the data disk can be attached to multiple VMs at the same time.
+func (d DataDisk) IsShared() bool {
+	return d.MaxShares != nil && *d.MaxShares > 1
 }
 
+// DiskDeleteOption defines what happens to a data disk when it is no longer used by a machine.
+// +kubebuilder:validation:Enum=Delete;Detach
+type DiskDeleteOption string
+
+const (
+	// DiskDeleteOptionDelete deletes the disk along with the machine.
+	DiskDeleteOptionDelete DiskDeleteOption = "Delete"
+	// DiskDeleteOptionDetach detaches the disk and keeps it when the machine is deleted.
+	DiskDeleteOptionDetach DiskDeleteOption = "Detach"
+)
+
 // ManagedDiskParameters defines the parameters of a managed disk.
 type ManagedDiskParameters struct {
+	// StorageAccountType is the storage account type of the managed disk, e.g. Premium_LRS. The zone redundant types
+	// Premium_ZRS and StandardSSD_ZRS replicate the disk synchronously across the availability zones of the location so
+	// that it survives a zone outage, and require a location with availability zones.
 	// +optional
 	StorageAccountType string `json:"storageAccountType,omitempty"`
 	// +optional
 	DiskEncryptionSet *DiskEncryptionSetParameters `json:"diskEncryptionSet,omitempty"`
+	// PerformanceTier is the performance tier of a Premium SSD disk, e.g. P30, which sets its IOPS and throughput
+	// independently of its size. It can't be lower than the baseline tier of the disk size, and can be changed on the
+	// disks of an existing machine. Not supported on AzureMachinePools.
+	// +kubebuilder:validation:Enum=P1;P2;P3;P4;P6;P10;P15;P20;P30;P40;P50;P60;P70;P80
+	// +optional
+	PerformanceTier string `json:"performanceTier,omitempty"`
+}
+
+// IsZoneRedundant returns true if the managed disk is replicated across the availability zones of its location.
+func (m *ManagedDiskParameters) IsZoneRedundant() bool {
+	return m != nil && strings.HasSuffix(m.StorageAccountType, "_ZRS")
 }
 
 // DiskEncryptionSetParameters defines disk encryption options.
@@ -495,6 +912,10 @@ type DiskEncryptionSetParameters struct {
 	// ID defines resourceID for diskEncryptionSet resource. It must be in the same subscription
 	// +optional
 	ID string `json:"id,omitempty"`
+
+	// Name is the name of a disk encryption set of the AzureCluster, created by the provider. Mutually exclusive with ID.
+	// +optional
+	Name string `json:"name,omitempty"`
 }
 
 // DiffDiskSettings describe ephemeral disk settings for the os disk.
@@ -544,6 +965,42 @@ type SubnetSpec struct {
 	// NatGateway associated with this subnet.
 	// +optional
 	NatGateway NatGateway `json:"natGateway,omitempty"`
+
+	// PrivateEndpoints defines the private endpoints that should be created in this subnet.
+	// Private endpoints are only created in managed virtual networks.
+	// +optional
+	PrivateEndpoints []PrivateEndpointSpec `json:"privateEndpoints,omitempty"`
+}
+
+// PrivateEndpointSpec configures an Azure Private Endpoint connecting the subnet to an Azure resource, e.g. a container
+// registry, a storage account or a key vault.
+type PrivateEndpointSpec struct {
+	// Name is the name of the private endpoint.
+	Name string `json:"name"`
+
+	// PrivateLinkServiceID is the Azure resource ID of the resource the private endpoint connects to.
+	PrivateLinkServiceID string `json:"privateLinkServiceID"`
+
+	// GroupIDs are the sub-resources of the target resource the private endpoint connects to, e.g. "registry" for a
+	// container registry or "blob" for a storage account.
+	// +optional
+	GroupIDs []string `json:"groupIDs,omitempty"`
+
+	// PrivateDNSZoneGroup registers the private IP address of the private endpoint in private DNS zones.
+	// +optional
+	PrivateDNSZoneGroup *PrivateDNSZoneGroupSpec `json:"privateDNSZoneGroup,omitempty"`
+}
+
+// PrivateDNSZoneGroupSpec configures the private DNS zone group of a private endpoint.
+type PrivateDNSZoneGroupSpec struct {
+	// Name is the name of the private DNS zone group. Defaults to "default".
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// PrivateDNSZoneIDs are the Azure resource IDs of the private DNS zones the private endpoint is registered in,
+	// e.g. the zone "privatelink.azurecr.io" for a container registry.
+	// +kubebuilder:validation:MinItems=1
+	PrivateDNSZoneIDs []string `json:"privateDNSZoneIDs"`
 }
 
 // GetControlPlaneSubnet returns the cluster control plane subnet.
@@ -587,6 +1044,94 @@ type SecurityProfile struct {
 	// set. Default is disabled.
 	// +optional
 	EncryptionAtHost *bool `json:"encryptionAtHost,omitempty"`
+
+	// SecurityType specifies the security type of the virtual machine. Set it to TrustedLaunch to enable the UEFI
+	// settings of the virtual machine. Trusted launch requires a Generation 2 image and VM size.
+	// +optional
+	SecurityType SecurityTypes `json:"securityType,omitempty"`
+
+	// UefiSettings specifies the security settings like secure boot and vTPM used while creating the virtual machine.
+	// Secure boot and vTPM are both enabled by default when securityType is TrustedLaunch.
+	// +optional
+	UefiSettings *UefiSettings `json:"uefiSettings,omitempty"`
+}
+
+// SecurityTypes represents the security type of a virtual machine.
+// +kubebuilder:validation:Enum=TrustedLaunch
+type SecurityTypes string
+
+const (
+	// SecurityTypesTrustedLaunch enables the UEFI settings of a virtual machine, such as secure boot and vTPM.
+	SecurityTypesTrustedLaunch SecurityTypes = "TrustedLaunch"
+)
+
+// UefiSettings specifies the security settings like secure boot and vTPM used while creating a trusted launch
+// virtual machine.
+type UefiSettings struct {
+	// SecureBootEnabled specifies whether secure boot should be enabled on the virtual machine.
+	// +optional
+	SecureBootEnabled *bool `json:"secureBootEnabled,omitempty"`
+
+	// VTpmEnabled specifies whether vTPM should be enabled on the virtual machine.
+	// +optional
+	VTpmEnabled *bool `json:"vTpmEnabled,omitempty"`
+}
+
+// UserDataSource is a reference to the user data of a virtual machine or virtual machine scale set. Unlike the
+// bootstrap data passed as custom data, the user data can be retrieved from the instance metadata service and is
+// updated in place when the Secret changes.
+type UserDataSource struct {
+	// SecretName is the name of the Secret holding the user data, in the namespace of the machine.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// Key is the key of the user data in the Secret. Defaults to "value".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// Diagnostics specifies the diagnostic settings of a virtual machine or virtual machine scale set.
+type Diagnostics struct {
+	// Boot configures the boot diagnostics, which capture the serial output and a screenshot of the virtual machine
+	// while it boots, and which the serial console requires. Boot diagnostics are enabled with a storage account
+	// managed by Azure if it isn't set.
+	// +optional
+	Boot *BootDiagnostics `json:"boot,omitempty"`
+}
+
+// BootDiagnostics specifies the boot diagnostics of a virtual machine.
+type BootDiagnostics struct {
+	// StorageAccountType determines whether the boot diagnostics are stored in a storage account managed by Azure
+	// (Managed) or by the user (UserManaged), or disabled (Disabled).
+	// +kubebuilder:validation:Enum=Managed;UserManaged;Disabled
+	StorageAccountType BootDiagnosticsStorageAccountType `json:"storageAccountType"`
+
+	// UserManaged references the storage account the boot diagnostics are stored in when StorageAccountType is
+	// UserManaged.
+	// +optional
+	UserManaged *UserManagedBootDiagnostics `json:"userManaged,omitempty"`
+}
+
+// BootDiagnosticsStorageAccountType is the type of the storage account of the boot diagnostics.
+type BootDiagnosticsStorageAccountType string
+
+const (
+	// ManagedDiagnosticsStorage stores the boot diagnostics in a storage account managed by Azure.
+	ManagedDiagnosticsStorage BootDiagnosticsStorageAccountType = "Managed"
+	// UserManagedDiagnosticsStorage stores the boot diagnostics in a storage account managed by the user.
+	UserManagedDiagnosticsStorage BootDiagnosticsStorageAccountType = "UserManaged"
+	// DisabledDiagnosticsStorage disables the boot diagnostics.
+	DisabledDiagnosticsStorage BootDiagnosticsStorageAccountType = "Disabled"
+)
+
+// UserManagedBootDiagnostics references the storage account managed by the user the boot diagnostics are stored in.
+type UserManagedBootDiagnostics struct {
+	// StorageAccountURI is the blob endpoint of the storage account, e.g. https://mystorageaccount.blob.core.windows.net/.
+	// The storage account must be in the same region and subscription as the virtual machine, and can't be a premium
+	// or zone-redundant storage account.
+	// +kubebuilder:validation:Pattern=`^https://`
+	// +kubebuilder:validation:MaxLength=1024
+	StorageAccountURI string `json:"storageAccountURI"`
 }
 
 // AddressRecord specifies a DNS record mapping a hostname to an IPV4 or IPv6 address.
@@ -678,12 +1223,156 @@ const (
 	AvailabilitySetRateLimit = "availabilitySetRateLimit"
 )
 
+// APIVersionProfileName is the name of a set of ARM API versions.
+type APIVersionProfileName string
+
+const (
+	// APIVersionProfileLatest uses the API versions of the Azure SDK.
+	APIVersionProfileLatest APIVersionProfileName = "latest"
+	// APIVersionProfileHybrid20190301 uses the API versions of the 2019-03-01-hybrid profile, supported by Azure Stack Hub.
+	APIVersionProfileHybrid20190301 APIVersionProfileName = "2019-03-01-hybrid"
+)
+
+// APIVersionProfile defines the ARM API versions used to manage the Azure resources.
+type APIVersionProfile struct {
+	// Name is the name of the profile of API versions.
+	// +kubebuilder:validation:Enum=latest;"2019-03-01-hybrid"
+	// +kubebuilder:default=latest
+	// +optional
+	Name APIVersionProfileName `json:"name,omitempty"`
+
+	// Overrides sets API versions which take precedence over the ones of the profile. The keys are either resource
+	// provider namespaces such as "Microsoft.Network", or resource types such as "Microsoft.Network/loadBalancers",
+	// and the values are API versions such as "2017-10-01".
+	// +optional
+	Overrides map[string]string `json:"overrides,omitempty"`
+}
+
+// APIServerAccessProfile defines who can reach the API server of a cluster.
+type APIServerAccessProfile struct {
+	// AllowedCIDRs are the source CIDR blocks allowed to reach the API server through its public load balancer. The
+	// API server remains reachable from the virtual network of the cluster.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=90
+	AllowedCIDRs []string `json:"allowedCIDRs"`
+}
+
+// ConnectivityProfile defines the ports of the agents connecting the control plane of a cluster with management
+// services.
+type ConnectivityProfile struct {
+	// Ingress are the ports of agents running on the control plane nodes which management services connect to, e.g.
+	// a konnectivity server. The security group of the control plane subnet allows them, and the API server load
+	// balancer forwards them to the control plane nodes.
+	// +optional
+	Ingress []ConnectivityPort `json:"ingress,omitempty"`
+
+	// Egress are the ports of management services which agents running on the control plane nodes connect to. The
+	// security group of the control plane subnet allows them.
+	// +optional
+	Egress []ConnectivityPort `json:"egress,omitempty"`
+}
+
+// ConnectivityPort defines a port of a management-plane agent.
+type ConnectivityPort struct {
+	// Name identifies the port in the names of its security and load balancing rules. It must be unique among the
+	// ingress or egress ports.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][-_.a-zA-Z0-9]{0,39}$`
+	Name string `json:"name"`
+
+	// Port is the port of the agent or the management service.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Protocol is the transport protocol of the port, "Tcp" or "Udp". Defaults to "Tcp".
+	// +kubebuilder:validation:Enum=Tcp;Udp
+	// +optional
+	Protocol SecurityGroupProtocol `json:"protocol,omitempty"`
+
+	// CIDRBlocks are the address ranges of the management services, i.e. the sources of ingress traffic or the
+	// destinations of egress traffic. Defaults to the CIDR blocks of the management network if it is set, or to any
+	// address.
+	// +optional
+	CIDRBlocks []string `json:"cidrBlocks,omitempty"`
+}
+
+// AlertRuleType is a baseline alert rule of the infrastructure of a cluster.
+// +kubebuilder:validation:Enum=VMAvailability;LoadBalancerHealthProbe;NATGatewaySNAT
+type AlertRuleType string
+
+const (
+	// AlertRuleVMAvailability fires when a virtual machine of the cluster is unavailable.
+	AlertRuleVMAvailability AlertRuleType = "VMAvailability"
+	// AlertRuleLoadBalancerHealthProbe fires when the health probe of the API server load balancer fails for a backend.
+	AlertRuleLoadBalancerHealthProbe AlertRuleType = "LoadBalancerHealthProbe"
+	// AlertRuleNATGatewaySNAT fires when SNAT connections fail on a NAT gateway of the cluster.
+	AlertRuleNATGatewaySNAT AlertRuleType = "NATGatewaySNAT"
+)
+
+// AlertsSpec defines the baseline Azure Monitor metric alert rules of a cluster.
+type AlertsSpec struct {
+	// ActionGroupID is the Azure resource ID of an existing action group, notified when an alert fires.
+	ActionGroupID string `json:"actionGroupID"`
+
+	// Rules are the alert rules to create. Defaults to all of them.
+	// +listType=set
+	// +optional
+	Rules []AlertRuleType `json:"rules,omitempty"`
+
+	// Severity is the severity of the alerts, from 0 (critical) to 4 (verbose). Defaults to 2 (warning).
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4
+	// +optional
+	Severity *int32 `json:"severity,omitempty"`
+}
+
+// DiskEncryptionSetSpec defines a disk encryption set created in the resource group of the cluster, which encrypts the
+// disks referencing it with a customer-managed key from Azure Key Vault.
+type DiskEncryptionSetSpec struct {
+	// Name is the name of the disk encryption set.
+	Name string `json:"name"`
+
+	// KeyURL is the URL of the Key Vault key encrypting the disks, including its version, e.g.
+	// https://myvault.vault.azure.net/keys/mykey/0123456789abcdef0123456789abcdef.
+	KeyURL string `json:"keyURL"`
+
+	// KeyVaultID is the Azure resource ID of the Key Vault holding the key. The managed identity of the disk encryption
+	// set is granted access to the key vault, with an access policy or a role assignment depending on its permission model.
+	KeyVaultID string `json:"keyVaultID"`
+
+	// EncryptionType is the type of encryption of the disks. Defaults to EncryptionAtRestWithCustomerKey.
+	// +optional
+	EncryptionType DiskEncryptionSetType `json:"encryptionType,omitempty"`
+}
+
+// DiskEncryptionSetType is the type of encryption of the disks of a disk encryption set.
+// +kubebuilder:validation:Enum=EncryptionAtRestWithCustomerKey;EncryptionAtRestWithPlatformAndCustomerKeys
+type DiskEncryptionSetType string
+
+const (
+	// DiskEncryptionSetTypeCustomerKey encrypts disks at rest with a customer-managed key.
+	DiskEncryptionSetTypeCustomerKey DiskEncryptionSetType = "EncryptionAtRestWithCustomerKey"
+	// DiskEncryptionSetTypePlatformAndCustomerKeys encrypts disks at rest twice, with a platform-managed key and a
+	// customer-managed key.
+	DiskEncryptionSetTypePlatformAndCustomerKeys DiskEncryptionSetType = "EncryptionAtRestWithPlatformAndCustomerKeys"
+)
+
 // BastionSpec specifies how the Bastion feature should be set up for the cluster.
 type BastionSpec struct {
 	// +optional
 	AzureBastion *AzureBastion `json:"azureBastion,omitempty"`
 }
 
+// BastionHostSkuName is the SKU of an Azure Bastion host.
+type BastionHostSkuName string
+
+const (
+	// BastionHostSkuBasic is the Basic SKU of Azure Bastion.
+	BastionHostSkuBasic BastionHostSkuName = "Basic"
+	// BastionHostSkuStandard is the Standard SKU of Azure Bastion, which supports scaling and native client connections.
+	BastionHostSkuStandard BastionHostSkuName = "Standard"
+)
+
 // AzureBastion specifies how the Azure Bastion cloud component should be configured.
 type AzureBastion struct {
 	// +optional
@@ -692,9 +1381,104 @@ type AzureBastion struct {
 	Subnet SubnetSpec `json:"subnet,omitempty"`
 	// +optional
 	PublicIP PublicIPSpec `json:"publicIP,omitempty"`
+	// Sku is the SKU of the bastion host. The Standard SKU is required for scale units, tunneling and IP-based
+	// connections, and can't be downgraded to the Basic SKU. Defaults to Basic.
+	// +kubebuilder:validation:Enum=Basic;Standard
+	// +optional
+	Sku BastionHostSkuName `json:"sku,omitempty"`
+	// ScaleUnits is the number of instances of the bastion host, each supporting about 20 concurrent sessions.
+	// Azure uses 2 scale units by default.
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=50
+	// +optional
+	ScaleUnits *int32 `json:"scaleUnits,omitempty"`
+	// EnableTunneling enables the connections with the native SSH and RDP clients, e.g. with "az network bastion ssh".
+	// +optional
+	EnableTunneling bool `json:"enableTunneling,omitempty"`
+	// EnableIPConnect enables the connections to the VMs by their private IP address.
+	// +optional
+	EnableIPConnect bool `json:"enableIPConnect,omitempty"`
+}
+
+// ProximityPlacementGroupSpec specifies a proximity placement group of a cluster.
+type ProximityPlacementGroupSpec struct {
+	// Name is the name of the proximity placement group. Defaults to <cluster name>-ppg.
+	// +optional
+	Name string `json:"name,omitempty"`
 }
 
 // IsTerminalProvisioningState returns true if the ProvisioningState is a terminal state for an Azure resource.
 func IsTerminalProvisioningState(state ProvisioningState) bool {
 	return state == Failed || state == Succeeded
 }
+
+// AzureEnvironmentEndpoints defines the endpoints of an Azure cloud.
+type AzureEnvironmentEndpoints struct {
+	// ResourceManagerEndpoint is the endpoint of Azure Resource Manager, e.g. "https://management.local.azurestack.external/".
+	ResourceManagerEndpoint string `json:"resourceManagerEndpoint"`
+
+	// ActiveDirectoryEndpoint is the endpoint of Azure Active Directory the identities authenticate with, e.g.
+	// "https://login.microsoftonline.com/".
+	ActiveDirectoryEndpoint string `json:"activeDirectoryEndpoint"`
+
+	// ResourceManagerVMDNSSuffix is the DNS suffix of the FQDNs of the public IPs, e.g. "cloudapp.local.azurestack.external".
+	ResourceManagerVMDNSSuffix string `json:"resourceManagerVMDNSSuffix"`
+
+	// TokenAudience is the audience of the tokens used to call Azure Resource Manager. Defaults to the
+	// ResourceManagerEndpoint.
+	// +optional
+	TokenAudience string `json:"tokenAudience,omitempty"`
+}
+
+// ProxyConfig defines the HTTP proxy the nodes of a cluster reach the Internet through.
+type ProxyConfig struct {
+	// HTTPProxy is the URL of the proxy of the HTTP requests, e.g. "http://proxy.example.com:3128".
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy of the HTTPS requests, e.g. "http://proxy.example.com:3128".
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy are the hosts, domains, IP addresses and CIDRs reached without the proxy, e.g. ".example.com".
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+
+	// TrustedCA is the PEM encoded certificate of the CA the nodes trust in addition to the ones of the OS, e.g. for
+	// a proxy which intercepts the HTTPS requests.
+	// +optional
+	TrustedCA string `json:"trustedCA,omitempty"`
+}
+
+// TrustedCASource is a reference to PEM encoded CA certificates which are added to the trust store of the nodes.
+type TrustedCASource struct {
+	// SecretName is the name of the Secret holding the CA certificates, in the namespace of the cluster.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// Key is the key of the CA certificates in the Secret. Defaults to "ca.crt".
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Registries are the hosts of the container registries, e.g. "registry.example.com:5000", whose certificates
+	// containerd verifies against the CA certificates.
+	// +optional
+	Registries []string `json:"registries,omitempty"`
+}
+
+// RegistryMirror configures containerd to pull the images of a registry from mirrors.
+type RegistryMirror struct {
+	// Registry is the host of the mirrored registry, optionally with a port, e.g. "docker.io" or "registry.k8s.io".
+	// +kubebuilder:validation:MinLength=1
+	Registry string `json:"registry"`
+
+	// Endpoints are the URLs of the mirrors, e.g. "https://mirror.example.com:5000", which are tried in order before
+	// the registry itself.
+	// +kubebuilder:validation:MinItems=1
+	Endpoints []string `json:"endpoints"`
+
+	// CredentialsSecretName is the name of a Secret in the namespace of the cluster, whose "username" and "password"
+	// keys hold the credentials used to pull from the mirrors.
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
@@ -28,6 +28,48 @@ import (
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerAccessProfile) DeepCopyInto(out *APIServerAccessProfile) {
+	*out = *in
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerAccessProfile.
+func (in *APIServerAccessProfile) DeepCopy() *APIServerAccessProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerAccessProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIVersionProfile) DeepCopyInto(out *APIVersionProfile) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIVersionProfile.
+func (in *APIVersionProfile) DeepCopy() *APIVersionProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(APIVersionProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddressRecord) DeepCopyInto(out *AddressRecord) {
 	*out = *in
@@ -43,6 +85,31 @@ func (in *AddressRecord) DeepCopy() *AddressRecord {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertsSpec) DeepCopyInto(out *AlertsSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]AlertRuleType, len(*in))
+		copy(*out, *in)
+	}
+	if in.Severity != nil {
+		in, out := &in.Severity, &out.Severity
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsSpec.
+func (in *AlertsSpec) DeepCopy() *AlertsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AllowedNamespaces) DeepCopyInto(out *AllowedNamespaces) {
 	*out = *in
@@ -73,6 +140,11 @@ func (in *AzureBastion) DeepCopyInto(out *AzureBastion) {
 	*out = *in
 	in.Subnet.DeepCopyInto(&out.Subnet)
 	out.PublicIP = in.PublicIP
+	if in.ScaleUnits != nil {
+		in, out := &in.ScaleUnits, &out.ScaleUnits
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureBastion.
@@ -263,12 +335,64 @@ func (in *AzureClusterSpec) DeepCopyInto(out *AzureClusterSpec) {
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.AzureEnvironmentEndpoints != nil {
+		in, out := &in.AzureEnvironmentEndpoints, &out.AzureEnvironmentEndpoints
+		*out = new(AzureEnvironmentEndpoints)
+		**out = **in
+	}
+	if in.APIVersionProfile != nil {
+		in, out := &in.APIVersionProfile, &out.APIVersionProfile
+		*out = new(APIVersionProfile)
+		(*in).DeepCopyInto(*out)
+	}
 	in.BastionSpec.DeepCopyInto(&out.BastionSpec)
 	if in.CloudProviderConfigOverrides != nil {
 		in, out := &in.CloudProviderConfigOverrides, &out.CloudProviderConfigOverrides
 		*out = new(CloudProviderConfigOverrides)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.APIServerAccessProfile != nil {
+		in, out := &in.APIServerAccessProfile, &out.APIServerAccessProfile
+		*out = new(APIServerAccessProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectivityProfile != nil {
+		in, out := &in.ConnectivityProfile, &out.ConnectivityProfile
+		*out = new(ConnectivityProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(AlertsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskEncryptionSets != nil {
+		in, out := &in.DiskEncryptionSets, &out.DiskEncryptionSets
+		*out = make([]DiskEncryptionSetSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProximityPlacementGroup != nil {
+		in, out := &in.ProximityPlacementGroup, &out.ProximityPlacementGroup
+		*out = new(ProximityPlacementGroupSpec)
+		**out = **in
+	}
+	if in.ProxyConfig != nil {
+		in, out := &in.ProxyConfig, &out.ProxyConfig
+		*out = new(ProxyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrustedCAs != nil {
+		in, out := &in.TrustedCAs, &out.TrustedCAs
+		*out = new(TrustedCASource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]RegistryMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterSpec.
@@ -303,6 +427,23 @@ func (in *AzureClusterStatus) DeepCopyInto(out *AzureClusterStatus) {
 		*out = make(Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.ProvisioningDurations != nil {
+		in, out := &in.ProvisioningDurations, &out.ProvisioningDurations
+		*out = new(ProvisioningDurations)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetainedPublicIPs != nil {
+		in, out := &in.RetainedPublicIPs, &out.RetainedPublicIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CordonedZones != nil {
+		in, out := &in.CordonedZones, &out.CordonedZones
+		*out = make([]CordonedZone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterStatus.
@@ -315,6 +456,53 @@ func (in *AzureClusterStatus) DeepCopy() *AzureClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureCommunityGalleryImage) DeepCopyInto(out *AzureCommunityGalleryImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureCommunityGalleryImage.
+func (in *AzureCommunityGalleryImage) DeepCopy() *AzureCommunityGalleryImage {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureCommunityGalleryImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureEnvironmentEndpoints) DeepCopyInto(out *AzureEnvironmentEndpoints) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureEnvironmentEndpoints.
+func (in *AzureEnvironmentEndpoints) DeepCopy() *AzureEnvironmentEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureEnvironmentEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureFirewall) DeepCopyInto(out *AzureFirewall) {
+	*out = *in
+	in.Subnet.DeepCopyInto(&out.Subnet)
+	out.PublicIP = in.PublicIP
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureFirewall.
+func (in *AzureFirewall) DeepCopy() *AzureFirewall {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureFirewall)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachine) DeepCopyInto(out *AzureMachine) {
 	*out = *in
@@ -427,6 +615,21 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		*out = new(SecurityProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ProximityPlacementGroupID != nil {
+		in, out := &in.ProximityPlacementGroupID, &out.ProximityPlacementGroupID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CapacityReservationGroupID != nil {
+		in, out := &in.CapacityReservationGroupID, &out.CapacityReservationGroupID
+		*out = new(string)
+		**out = **in
+	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(UserDataSource)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineSpec.
@@ -452,6 +655,11 @@ func (in *AzureMachineStatus) DeepCopyInto(out *AzureMachineStatus) {
 		*out = new(ProvisioningState)
 		**out = **in
 	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(Image)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(errors.MachineStatusError)
@@ -622,6 +830,21 @@ func (in *AzureSharedGalleryImage) DeepCopy() *AzureSharedGalleryImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureVHDImage) DeepCopyInto(out *AzureVHDImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureVHDImage.
+func (in *AzureVHDImage) DeepCopy() *AzureVHDImage {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureVHDImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackOffConfig) DeepCopyInto(out *BackOffConfig) {
 	*out = *in
@@ -667,6 +890,26 @@ func (in *BastionSpec) DeepCopy() *BastionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootDiagnostics) DeepCopyInto(out *BootDiagnostics) {
+	*out = *in
+	if in.UserManaged != nil {
+		in, out := &in.UserManaged, &out.UserManaged
+		*out = new(UserManagedBootDiagnostics)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootDiagnostics.
+func (in *BootDiagnostics) DeepCopy() *BootDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(BootDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildParams) DeepCopyInto(out *BuildParams) {
 	*out = *in
@@ -722,6 +965,91 @@ func (in *CloudProviderConfigOverrides) DeepCopy() *CloudProviderConfigOverrides
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityPort) DeepCopyInto(out *ConnectivityPort) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityPort.
+func (in *ConnectivityPort) DeepCopy() *ConnectivityPort {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityProfile) DeepCopyInto(out *ConnectivityProfile) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = make([]ConnectivityPort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]ConnectivityPort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityProfile.
+func (in *ConnectivityProfile) DeepCopy() *ConnectivityProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CordonedZone) DeepCopyInto(out *CordonedZone) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+	if in.MachineDeployments != nil {
+		in, out := &in.MachineDeployments, &out.MachineDeployments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CordonedZone.
+func (in *CordonedZone) DeepCopy() *CordonedZone {
+	if in == nil {
+		return nil
+	}
+	out := new(CordonedZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DDoSProtectionPlan) DeepCopyInto(out *DDoSProtectionPlan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DDoSProtectionPlan.
+func (in *DDoSProtectionPlan) DeepCopy() *DDoSProtectionPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(DDoSProtectionPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataDisk) DeepCopyInto(out *DataDisk) {
 	*out = *in
@@ -735,6 +1063,16 @@ func (in *DataDisk) DeepCopyInto(out *DataDisk) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.WriteAcceleratorEnabled != nil {
+		in, out := &in.WriteAcceleratorEnabled, &out.WriteAcceleratorEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxShares != nil {
+		in, out := &in.MaxShares, &out.MaxShares
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDisk.
@@ -747,6 +1085,26 @@ func (in *DataDisk) DeepCopy() *DataDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Diagnostics) DeepCopyInto(out *Diagnostics) {
+	*out = *in
+	if in.Boot != nil {
+		in, out := &in.Boot, &out.Boot
+		*out = new(BootDiagnostics)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Diagnostics.
+func (in *Diagnostics) DeepCopy() *Diagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(Diagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DiffDiskSettings) DeepCopyInto(out *DiffDiskSettings) {
 	*out = *in
@@ -778,51 +1136,108 @@ func (in *DiskEncryptionSetParameters) DeepCopy() *DiskEncryptionSetParameters {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FrontendIP) DeepCopyInto(out *FrontendIP) {
+func (in *DiskEncryptionSetSpec) DeepCopyInto(out *DiskEncryptionSetSpec) {
 	*out = *in
-	if in.PublicIP != nil {
-		in, out := &in.PublicIP, &out.PublicIP
-		*out = new(PublicIPSpec)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIP.
-func (in *FrontendIP) DeepCopy() *FrontendIP {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskEncryptionSetSpec.
+func (in *DiskEncryptionSetSpec) DeepCopy() *DiskEncryptionSetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FrontendIP)
+	out := new(DiskEncryptionSetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Future) DeepCopyInto(out *Future) {
+func (in *ExpressRouteGateway) DeepCopyInto(out *ExpressRouteGateway) {
 	*out = *in
+	in.Subnet.DeepCopyInto(&out.Subnet)
+	out.PublicIP = in.PublicIP
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Future.
-func (in *Future) DeepCopy() *Future {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExpressRouteGateway.
+func (in *ExpressRouteGateway) DeepCopy() *ExpressRouteGateway {
 	if in == nil {
 		return nil
 	}
-	out := new(Future)
+	out := new(ExpressRouteGateway)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in Futures) DeepCopyInto(out *Futures) {
-	{
-		in := &in
-		*out = make(Futures, len(*in))
-		copy(*out, *in)
+func (in *FlowLogsSpec) DeepCopyInto(out *FlowLogsSpec) {
+	*out = *in
+	if in.TrafficAnalytics != nil {
+		in, out := &in.TrafficAnalytics, &out.TrafficAnalytics
+		*out = new(TrafficAnalyticsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkWatcher != nil {
+		in, out := &in.NetworkWatcher, &out.NetworkWatcher
+		*out = new(NetworkWatcherSpec)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Futures.
-func (in Futures) DeepCopy() Futures {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowLogsSpec.
+func (in *FlowLogsSpec) DeepCopy() *FlowLogsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowLogsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FrontendIP) DeepCopyInto(out *FrontendIP) {
+	*out = *in
+	if in.PublicIP != nil {
+		in, out := &in.PublicIP, &out.PublicIP
+		*out = new(PublicIPSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIP.
+func (in *FrontendIP) DeepCopy() *FrontendIP {
+	if in == nil {
+		return nil
+	}
+	out := new(FrontendIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Future) DeepCopyInto(out *Future) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Future.
+func (in *Future) DeepCopy() *Future {
+	if in == nil {
+		return nil
+	}
+	out := new(Future)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in Futures) DeepCopyInto(out *Futures) {
+	{
+		in := &in
+		*out = make(Futures, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Futures.
+func (in Futures) DeepCopy() Futures {
 	if in == nil {
 		return nil
 	}
@@ -849,6 +1264,16 @@ func (in *Image) DeepCopyInto(out *Image) {
 		*out = new(AzureMarketplaceImage)
 		**out = **in
 	}
+	if in.CommunityGallery != nil {
+		in, out := &in.CommunityGallery, &out.CommunityGallery
+		*out = new(AzureCommunityGalleryImage)
+		**out = **in
+	}
+	if in.VHD != nil {
+		in, out := &in.VHD, &out.VHD
+		*out = new(AzureVHDImage)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
@@ -913,6 +1338,31 @@ func (in *ManagedDiskParameters) DeepCopy() *ManagedDiskParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementNetworkSpec) DeepCopyInto(out *ManagementNetworkSpec) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Vnet != nil {
+		in, out := &in.Vnet, &out.Vnet
+		*out = new(VnetPeeringSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementNetworkSpec.
+func (in *ManagementNetworkSpec) DeepCopy() *ManagementNetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementNetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NatGateway) DeepCopyInto(out *NatGateway) {
 	*out = *in
@@ -951,6 +1401,46 @@ func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 		*out = new(LoadBalancerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PrivateDNSZone != nil {
+		in, out := &in.PrivateDNSZone, &out.PrivateDNSZone
+		*out = new(PrivateDNSZoneSpec)
+		**out = **in
+	}
+	if in.PrivateDNSRecords != nil {
+		in, out := &in.PrivateDNSRecords, &out.PrivateDNSRecords
+		*out = make([]PrivateDNSRecord, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIServerPublicDNS != nil {
+		in, out := &in.APIServerPublicDNS, &out.APIServerPublicDNS
+		*out = new(PublicDNSRecordSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FlowLogs != nil {
+		in, out := &in.FlowLogs, &out.FlowLogs
+		*out = new(FlowLogsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIServerPrivateLinkService != nil {
+		in, out := &in.APIServerPrivateLinkService, &out.APIServerPrivateLinkService
+		*out = new(PrivateLinkServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpressRouteGateway != nil {
+		in, out := &in.ExpressRouteGateway, &out.ExpressRouteGateway
+		*out = new(ExpressRouteGateway)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagementNetwork != nil {
+		in, out := &in.ManagementNetwork, &out.ManagementNetwork
+		*out = new(ManagementNetworkSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(AzureFirewall)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
@@ -963,6 +1453,21 @@ func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkWatcherSpec) DeepCopyInto(out *NetworkWatcherSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkWatcherSpec.
+func (in *NetworkWatcherSpec) DeepCopy() *NetworkWatcherSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkWatcherSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OSDisk) DeepCopyInto(out *OSDisk) {
 	*out = *in
@@ -993,6 +1498,203 @@ func (in *OSDisk) DeepCopy() *OSDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateDNSRecord) DeepCopyInto(out *PrivateDNSRecord) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateDNSRecord.
+func (in *PrivateDNSRecord) DeepCopy() *PrivateDNSRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateDNSRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateDNSZoneGroupSpec) DeepCopyInto(out *PrivateDNSZoneGroupSpec) {
+	*out = *in
+	if in.PrivateDNSZoneIDs != nil {
+		in, out := &in.PrivateDNSZoneIDs, &out.PrivateDNSZoneIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateDNSZoneGroupSpec.
+func (in *PrivateDNSZoneGroupSpec) DeepCopy() *PrivateDNSZoneGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateDNSZoneGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateDNSZoneSpec) DeepCopyInto(out *PrivateDNSZoneSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateDNSZoneSpec.
+func (in *PrivateDNSZoneSpec) DeepCopy() *PrivateDNSZoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateDNSZoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateEndpointSpec) DeepCopyInto(out *PrivateEndpointSpec) {
+	*out = *in
+	if in.GroupIDs != nil {
+		in, out := &in.GroupIDs, &out.GroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrivateDNSZoneGroup != nil {
+		in, out := &in.PrivateDNSZoneGroup, &out.PrivateDNSZoneGroup
+		*out = new(PrivateDNSZoneGroupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateEndpointSpec.
+func (in *PrivateEndpointSpec) DeepCopy() *PrivateEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateLinkServiceSpec) DeepCopyInto(out *PrivateLinkServiceSpec) {
+	*out = *in
+	if in.NATIPCount != nil {
+		in, out := &in.NATIPCount, &out.NATIPCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Visibility != nil {
+		in, out := &in.Visibility, &out.Visibility
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoApproval != nil {
+		in, out := &in.AutoApproval, &out.AutoApproval
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateLinkServiceSpec.
+func (in *PrivateLinkServiceSpec) DeepCopy() *PrivateLinkServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateLinkServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningDurations) DeepCopyInto(out *ProvisioningDurations) {
+	*out = *in
+	if in.NetworkReady != nil {
+		in, out := &in.NetworkReady, &out.NetworkReady
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ControlPlaneProvisioned != nil {
+		in, out := &in.ControlPlaneProvisioned, &out.ControlPlaneProvisioned
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FirstNodeReady != nil {
+		in, out := &in.FirstNodeReady, &out.FirstNodeReady
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make(map[string]metav1.Duration, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningDurations.
+func (in *ProvisioningDurations) DeepCopy() *ProvisioningDurations {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningDurations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProximityPlacementGroupSpec) DeepCopyInto(out *ProximityPlacementGroupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProximityPlacementGroupSpec.
+func (in *ProximityPlacementGroupSpec) DeepCopy() *ProximityPlacementGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProximityPlacementGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyConfig) DeepCopyInto(out *ProxyConfig) {
+	*out = *in
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyConfig.
+func (in *ProxyConfig) DeepCopy() *ProxyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicDNSRecordSpec) DeepCopyInto(out *PublicDNSRecordSpec) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicDNSRecordSpec.
+func (in *PublicDNSRecordSpec) DeepCopy() *PublicDNSRecordSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicDNSRecordSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PublicIPSpec) DeepCopyInto(out *PublicIPSpec) {
 	*out = *in
@@ -1049,6 +1751,26 @@ func (in *RateLimitSpec) DeepCopy() *RateLimitSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteTable) DeepCopyInto(out *RouteTable) {
 	*out = *in
@@ -1074,6 +1796,11 @@ func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DisabledDefaultRules != nil {
+		in, out := &in.DisabledDefaultRules, &out.DisabledDefaultRules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(Tags, len(*in))
@@ -1101,6 +1828,11 @@ func (in *SecurityProfile) DeepCopyInto(out *SecurityProfile) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.UefiSettings != nil {
+		in, out := &in.UefiSettings, &out.UefiSettings
+		*out = new(UefiSettings)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityProfile.
@@ -1177,6 +1909,11 @@ func (in *SpotVMOptions) DeepCopyInto(out *SpotVMOptions) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.EvictionPolicy != nil {
+		in, out := &in.EvictionPolicy, &out.EvictionPolicy
+		*out = new(SpotEvictionPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotVMOptions.
@@ -1200,6 +1937,13 @@ func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 	in.SecurityGroup.DeepCopyInto(&out.SecurityGroup)
 	out.RouteTable = in.RouteTable
 	out.NatGateway = in.NatGateway
+	if in.PrivateEndpoints != nil {
+		in, out := &in.PrivateEndpoints, &out.PrivateEndpoints
+		*out = make([]PrivateEndpointSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetSpec.
@@ -1254,6 +1998,71 @@ func (in Tags) DeepCopy() Tags {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficAnalyticsSpec) DeepCopyInto(out *TrafficAnalyticsSpec) {
+	*out = *in
+	if in.IntervalInMinutes != nil {
+		in, out := &in.IntervalInMinutes, &out.IntervalInMinutes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficAnalyticsSpec.
+func (in *TrafficAnalyticsSpec) DeepCopy() *TrafficAnalyticsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficAnalyticsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedCASource) DeepCopyInto(out *TrustedCASource) {
+	*out = *in
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedCASource.
+func (in *TrustedCASource) DeepCopy() *TrustedCASource {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedCASource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UefiSettings) DeepCopyInto(out *UefiSettings) {
+	*out = *in
+	if in.SecureBootEnabled != nil {
+		in, out := &in.SecureBootEnabled, &out.SecureBootEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VTpmEnabled != nil {
+		in, out := &in.VTpmEnabled, &out.VTpmEnabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UefiSettings.
+func (in *UefiSettings) DeepCopy() *UefiSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(UefiSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UserAssignedIdentity) DeepCopyInto(out *UserAssignedIdentity) {
 	*out = *in
@@ -1269,6 +2078,36 @@ func (in *UserAssignedIdentity) DeepCopy() *UserAssignedIdentity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserDataSource) DeepCopyInto(out *UserDataSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserDataSource.
+func (in *UserDataSource) DeepCopy() *UserDataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(UserDataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserManagedBootDiagnostics) DeepCopyInto(out *UserManagedBootDiagnostics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserManagedBootDiagnostics.
+func (in *UserManagedBootDiagnostics) DeepCopy() *UserManagedBootDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(UserManagedBootDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VnetPeeringSpec) DeepCopyInto(out *VnetPeeringSpec) {
 	*out = *in
@@ -1323,6 +2162,11 @@ func (in *VnetSpec) DeepCopyInto(out *VnetSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.DDoSProtectionPlan != nil {
+		in, out := &in.DDoSProtectionPlan, &out.DDoSProtectionPlan
+		*out = new(DDoSProtectionPlan)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VnetSpec.
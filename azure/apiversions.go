@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// hybridAPIVersions are the API versions of the 2019-03-01-hybrid profile supported by Azure Stack Hub, as in
+// github.com/Azure/azure-sdk-for-go/profiles/2019-03-01.
+var hybridAPIVersions = map[string]string{
+	"microsoft.authorization":     "2015-07-01",
+	"microsoft.compute":           "2017-12-01",
+	"microsoft.compute/disks":     "2017-03-30",
+	"microsoft.compute/snapshots": "2017-03-30",
+	"microsoft.compute/skus":      "2017-09-01",
+	"microsoft.keyvault":          "2016-10-01",
+	"microsoft.network":           "2017-10-01",
+	"microsoft.network/dnszones":  "2016-04-01",
+	"microsoft.resources":         "2018-05-01",
+	"microsoft.storage":           "2017-10-01",
+}
+
+// Capability is an optional feature of a cluster which requires a minimum API version of a resource type.
+type Capability struct {
+	// Name is the name of the feature.
+	Name string
+	// ResourceType is the type of the resources the feature relies on.
+	ResourceType string
+	// MinAPIVersion is the first API version of the resource type.
+	MinAPIVersion string
+}
+
+var (
+	// BastionHostCapability is Azure Bastion.
+	BastionHostCapability = Capability{Name: "AzureBastion", ResourceType: "Microsoft.Network/bastionHosts", MinAPIVersion: "2019-04-01"}
+	// NatGatewayCapability is the NAT gateways of the subnets.
+	NatGatewayCapability = Capability{Name: "NatGateway", ResourceType: "Microsoft.Network/natGateways", MinAPIVersion: "2019-02-01"}
+	// PrivateEndpointCapability is the private endpoints of the subnets.
+	PrivateEndpointCapability = Capability{Name: "PrivateEndpoints", ResourceType: "Microsoft.Network/privateEndpoints", MinAPIVersion: "2019-04-01"}
+	// PrivateLinkServiceCapability is the private link service of the API server.
+	PrivateLinkServiceCapability = Capability{Name: "APIServerPrivateLinkService", ResourceType: "Microsoft.Network/privateLinkServices", MinAPIVersion: "2019-04-01"}
+	// FlowLogCapability is the NSG flow logs.
+	FlowLogCapability = Capability{Name: "FlowLogs", ResourceType: "Microsoft.Network/networkWatchers/flowLogs", MinAPIVersion: "2019-11-01"}
+	// DDoSProtectionPlanCapability is the DDoS protection plan of the virtual network.
+	DDoSProtectionPlanCapability = Capability{Name: "DDoSProtectionPlan", ResourceType: "Microsoft.Network/ddosProtectionPlans", MinAPIVersion: "2018-02-01"}
+	// PrivateDNSCapability is the private DNS zone of a private API server.
+	PrivateDNSCapability = Capability{Name: "PrivateDNS", ResourceType: "Microsoft.Network/privateDnsZones", MinAPIVersion: "2018-09-01"}
+)
+
+// IsSupported returns whether the API versions support the capability. The API versions of the Azure SDK support
+// all the capabilities.
+func (c Capability) IsSupported(apiVersions map[string]string) bool {
+	version, ok := ResourceTypeAPIVersion(apiVersions, c.ResourceType)
+	return !ok || version >= c.MinAPIVersion
+}
+
+// APIVersions returns the API versions of an API version profile, keyed by lowercase resource provider namespace or
+// resource type. It returns nil when the API versions of the Azure SDK are used.
+func APIVersions(profile *infrav1.APIVersionProfile) map[string]string {
+	if profile == nil {
+		return nil
+	}
+
+	versions := make(map[string]string)
+	if profile.Name == infrav1.APIVersionProfileHybrid20190301 {
+		for key, version := range hybridAPIVersions {
+			versions[key] = version
+		}
+	}
+	for key, version := range profile.Overrides {
+		versions[strings.ToLower(key)] = version
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	return versions
+}
+
+// ResourceTypeAPIVersion returns the API version of a resource type such as "Microsoft.Network/networkWatchers/flowLogs",
+// falling back to the API versions of its parent resource types and of its resource provider namespace.
+func ResourceTypeAPIVersion(apiVersions map[string]string, resourceType string) (string, bool) {
+	key := strings.ToLower(resourceType)
+	for {
+		if version, ok := apiVersions[key]; ok {
+			return version, true
+		}
+		i := strings.LastIndex(key, "/")
+		if i < 0 {
+			return "", false
+		}
+		key = key[:i]
+	}
+}
+
+// resourceTypeFromPath returns the resource type of the resource or collection an ARM request path refers to.
+func resourceTypeFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	namespace, types := "Microsoft.Resources", segments
+	// The last providers segment wins, as extension resources such as role assignments are nested in other resources.
+	for i := len(segments) - 2; i >= 0; i-- {
+		if strings.EqualFold(segments[i], "providers") {
+			namespace, types = segments[i+1], segments[i+2:]
+			break
+		}
+	}
+
+	resourceType := namespace
+	for i := 0; i < len(types); i += 2 {
+		resourceType += "/" + types[i]
+	}
+	return resourceType
+}
+
+// apiVersionAuthorizer authorizes the requests with the wrapped authorizer, and sets their API version.
+type apiVersionAuthorizer struct {
+	autorest.Authorizer
+	apiVersions map[string]string
+}
+
+// NewAPIVersionAuthorizer returns an authorizer which also sets the api-version of the requests to the API versions of
+// their resource type, so that the clients of all the services follow an API version profile.
+func NewAPIVersionAuthorizer(authorizer autorest.Authorizer, apiVersions map[string]string) autorest.Authorizer {
+	if len(apiVersions) == 0 {
+		return authorizer
+	}
+	return &apiVersionAuthorizer{
+		Authorizer:  authorizer,
+		apiVersions: apiVersions,
+	}
+}
+
+// WithAuthorization returns a PrepareDecorator which authorizes the request and sets its API version.
+func (a *apiVersionAuthorizer) WithAuthorization() autorest.PrepareDecorator {
+	return func(p autorest.Preparer) autorest.Preparer {
+		return autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			r, err := a.Authorizer.WithAuthorization()(p).Prepare(r)
+			if err != nil {
+				return r, err
+			}
+
+			query := r.URL.Query()
+			if query.Get("api-version") == "" {
+				return r, nil
+			}
+			if version, ok := ResourceTypeAPIVersion(a.apiVersions, resourceTypeFromPath(r.URL.Path)); ok {
+				query.Set("api-version", version)
+				r.URL.RawQuery = query.Encode()
+			}
+			return r, nil
+		})
+	}
+}
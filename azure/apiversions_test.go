@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestAPIVersions(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(APIVersions(nil)).To(BeNil())
+	g.Expect(APIVersions(&infrav1.APIVersionProfile{Name: infrav1.APIVersionProfileLatest})).To(BeNil())
+
+	versions := APIVersions(&infrav1.APIVersionProfile{
+		Name: infrav1.APIVersionProfileHybrid20190301,
+		Overrides: map[string]string{
+			"Microsoft.Network":               "2018-11-01",
+			"Microsoft.Network/loadBalancers": "2019-02-01",
+		},
+	})
+	g.Expect(versions).To(HaveKeyWithValue("microsoft.compute", "2017-12-01"))
+	g.Expect(versions).To(HaveKeyWithValue("microsoft.network", "2018-11-01"))
+	g.Expect(versions).To(HaveKeyWithValue("microsoft.network/loadbalancers", "2019-02-01"))
+	// The profile is not modified by the overrides.
+	g.Expect(hybridAPIVersions).To(HaveKeyWithValue("microsoft.network", "2017-10-01"))
+}
+
+func TestResourceTypeAPIVersion(t *testing.T) {
+	versions := map[string]string{
+		"microsoft.network":                 "2017-10-01",
+		"microsoft.network/networkwatchers": "2018-11-01",
+	}
+
+	tests := []struct {
+		resourceType string
+		version      string
+		found        bool
+	}{
+		{resourceType: "Microsoft.Network/networkWatchers/flowLogs", version: "2018-11-01", found: true},
+		{resourceType: "Microsoft.Network/networkWatchers", version: "2018-11-01", found: true},
+		{resourceType: "Microsoft.Network/loadBalancers", version: "2017-10-01", found: true},
+		{resourceType: "Microsoft.Compute/virtualMachines", found: false},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.resourceType, func(t *testing.T) {
+			g := NewWithT(t)
+			version, found := ResourceTypeAPIVersion(versions, tc.resourceType)
+			g.Expect(version).To(Equal(tc.version))
+			g.Expect(found).To(Equal(tc.found))
+		})
+	}
+}
+
+func TestResourceTypeFromPath(t *testing.T) {
+	tests := []struct {
+		path         string
+		resourceType string
+	}{
+		{
+			path:         "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+			resourceType: "Microsoft.Network/virtualNetworks",
+		},
+		{
+			path:         "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks",
+			resourceType: "Microsoft.Network/virtualNetworks",
+		},
+		{
+			path:         "/subscriptions/123/resourceGroups/NetworkWatcherRG/providers/Microsoft.Network/networkWatchers/my-watcher/flowLogs/my-flowlog",
+			resourceType: "Microsoft.Network/networkWatchers/flowLogs",
+		},
+		{
+			path:         "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm/providers/Microsoft.Authorization/roleAssignments/my-role",
+			resourceType: "Microsoft.Authorization/roleAssignments",
+		},
+		{
+			path:         "/subscriptions/123/providers/Microsoft.Compute/skus",
+			resourceType: "Microsoft.Compute/skus",
+		},
+		{
+			path:         "/subscriptions/123/resourcegroups/my-rg",
+			resourceType: "Microsoft.Resources/subscriptions/resourcegroups",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.path, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(resourceTypeFromPath(tc.path)).To(Equal(tc.resourceType))
+		})
+	}
+}
+
+func TestAPIVersionAuthorizer(t *testing.T) {
+	g := NewWithT(t)
+
+	authorizer := autorest.NewAPIKeyAuthorizerWithHeaders(map[string]interface{}{"Authorization": "Bearer token"})
+	g.Expect(NewAPIVersionAuthorizer(authorizer, nil)).To(BeIdenticalTo(authorizer))
+
+	versions := APIVersions(&infrav1.APIVersionProfile{Name: infrav1.APIVersionProfileHybrid20190301})
+	prepare := func(path string) *http.Request {
+		r, err := autorest.Prepare(&http.Request{},
+			autorest.AsGet(),
+			autorest.WithBaseURL("https://management.local.azurestack.external"),
+			autorest.WithPath(path),
+			autorest.WithQueryParameters(map[string]interface{}{"api-version": "2021-02-01", "$expand": "subnets"}),
+			NewAPIVersionAuthorizer(authorizer, versions).WithAuthorization())
+		g.Expect(err).NotTo(HaveOccurred())
+		return r
+	}
+
+	r := prepare("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet")
+	g.Expect(r.Header.Get("Authorization")).To(Equal("Bearer token"))
+	g.Expect(r.URL.Query().Get("api-version")).To(Equal("2017-10-01"))
+	g.Expect(r.URL.Query().Get("$expand")).To(Equal("subnets"))
+
+	// The requests to the resource providers without an API version in the profile are left untouched.
+	r = prepare("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerService/managedClusters/my-aks")
+	g.Expect(r.URL.Query().Get("api-version")).To(Equal("2021-02-01"))
+}
+
+func TestCapabilityIsSupported(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(BastionHostCapability.IsSupported(nil)).To(BeTrue())
+
+	hybrid := APIVersions(&infrav1.APIVersionProfile{Name: infrav1.APIVersionProfileHybrid20190301})
+	g.Expect(BastionHostCapability.IsSupported(hybrid)).To(BeFalse())
+	g.Expect(PrivateDNSCapability.IsSupported(hybrid)).To(BeFalse())
+
+	hybrid["microsoft.network/bastionhosts"] = "2019-04-01"
+	g.Expect(BastionHostCapability.IsSupported(hybrid)).To(BeTrue())
+}
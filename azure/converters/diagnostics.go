@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// DiagnosticsToSDK converts CAPZ Diagnostics to an Azure SDK DiagnosticsProfile. Boot diagnostics are enabled with a
+// storage account managed by Azure if they aren't configured.
+func DiagnosticsToSDK(diagnostics *infrav1.Diagnostics) *compute.DiagnosticsProfile {
+	bootDiagnostics := &compute.BootDiagnostics{
+		Enabled: to.BoolPtr(true),
+	}
+	if diagnostics != nil && diagnostics.Boot != nil {
+		switch diagnostics.Boot.StorageAccountType {
+		case infrav1.DisabledDiagnosticsStorage:
+			bootDiagnostics.Enabled = to.BoolPtr(false)
+		case infrav1.UserManagedDiagnosticsStorage:
+			if diagnostics.Boot.UserManaged != nil {
+				bootDiagnostics.StorageURI = to.StringPtr(diagnostics.Boot.UserManaged.StorageAccountURI)
+			}
+		}
+	}
+
+	return &compute.DiagnosticsProfile{
+		BootDiagnostics: bootDiagnostics,
+	}
+}
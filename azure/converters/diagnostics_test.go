@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestDiagnosticsToSDK(t *testing.T) {
+	tests := []struct {
+		name        string
+		diagnostics *infrav1.Diagnostics
+		want        *compute.BootDiagnostics
+	}{
+		{
+			name: "enabled by default",
+			want: &compute.BootDiagnostics{Enabled: to.BoolPtr(true)},
+		},
+		{
+			name:        "managed storage account",
+			diagnostics: &infrav1.Diagnostics{Boot: &infrav1.BootDiagnostics{StorageAccountType: infrav1.ManagedDiagnosticsStorage}},
+			want:        &compute.BootDiagnostics{Enabled: to.BoolPtr(true)},
+		},
+		{
+			name: "user managed storage account",
+			diagnostics: &infrav1.Diagnostics{Boot: &infrav1.BootDiagnostics{
+				StorageAccountType: infrav1.UserManagedDiagnosticsStorage,
+				UserManaged:        &infrav1.UserManagedBootDiagnostics{StorageAccountURI: "https://fake.blob.core.windows.net/"},
+			}},
+			want: &compute.BootDiagnostics{Enabled: to.BoolPtr(true), StorageURI: to.StringPtr("https://fake.blob.core.windows.net/")},
+		},
+		{
+			name:        "disabled",
+			diagnostics: &infrav1.Diagnostics{Boot: &infrav1.BootDiagnostics{StorageAccountType: infrav1.DisabledDiagnosticsStorage}},
+			want:        &compute.BootDiagnostics{Enabled: to.BoolPtr(false)},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(DiagnosticsToSDK(tt.diagnostics)).To(Equal(&compute.DiagnosticsProfile{BootDiagnostics: tt.want}))
+		})
+	}
+}
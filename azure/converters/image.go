@@ -37,10 +37,25 @@ func ImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
 	if image.SharedGallery != nil {
 		return sigImageToSDK(image)
 	}
+	if image.CommunityGallery != nil {
+		// the community gallery image ID is not part of the compute SDK, it is set by the virtual machines client.
+		return &compute.ImageReference{}, nil
+	}
 
 	return nil, errors.New("unable to convert image as no options set")
 }
 
+// CommunityGalleryImageID returns the ID of a community gallery image, or an empty string for other images.
+func CommunityGalleryImageID(image *infrav1.Image) string {
+	if image == nil || image.CommunityGallery == nil {
+		return ""
+	}
+	return fmt.Sprintf("/CommunityGalleries/%s/Images/%s/Versions/%s",
+		image.CommunityGallery.Gallery,
+		image.CommunityGallery.Name,
+		image.CommunityGallery.Version)
+}
+
 func mpImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
 	return &compute.ImageReference{
 		Publisher: &image.Marketplace.Publisher,
@@ -124,3 +124,44 @@ func Test_ImageToPlan(t *testing.T) {
 		})
 	}
 }
+
+func Test_CommunityGalleryImageID(t *testing.T) {
+	cases := []struct {
+		name   string
+		image  *infrav1.Image
+		expect string
+	}{
+		{
+			name: "Should return the ID of a community gallery image",
+			image: &infrav1.Image{
+				CommunityGallery: &infrav1.AzureCommunityGalleryImage{
+					Gallery: "fake-gallery-name",
+					Name:    "fake-image-name",
+					Version: "1.0.0",
+				},
+			},
+			expect: "/CommunityGalleries/fake-gallery-name/Images/fake-image-name/Versions/1.0.0",
+		},
+		{
+			name: "Should return an empty ID for an image ID",
+			image: &infrav1.Image{
+				ID: to.StringPtr("fake/image/id"),
+			},
+			expect: "",
+		},
+		{
+			name:   "Should return an empty ID for a nil image",
+			image:  nil,
+			expect: "",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+			g.Expect(CommunityGalleryImageID(c.image)).To(Equal(c.expect))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// SecurityProfileToSDK converts a CAPZ SecurityProfile to an Azure SDK SecurityProfile.
+func SecurityProfileToSDK(securityProfile *infrav1.SecurityProfile) *compute.SecurityProfile {
+	if securityProfile == nil {
+		return nil
+	}
+
+	sdkSecurityProfile := &compute.SecurityProfile{
+		EncryptionAtHost: securityProfile.EncryptionAtHost,
+	}
+	// UEFI settings are only enabled by Azure for trusted launch VMs.
+	if securityProfile.SecurityType == infrav1.SecurityTypesTrustedLaunch {
+		sdkSecurityProfile.SecurityType = compute.SecurityTypesTrustedLaunch
+		if securityProfile.UefiSettings != nil {
+			sdkSecurityProfile.UefiSettings = &compute.UefiSettings{
+				SecureBootEnabled: securityProfile.UefiSettings.SecureBootEnabled,
+				VTpmEnabled:       securityProfile.UefiSettings.VTpmEnabled,
+			}
+		}
+	}
+
+	return sdkSecurityProfile
+}
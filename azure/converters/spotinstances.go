@@ -40,5 +40,9 @@ func GetSpotVMOptions(spotVMOptions *infrav1.SpotVMOptions) (compute.VirtualMach
 			MaxPrice: &maxPrice,
 		}
 	}
-	return compute.VirtualMachinePriorityTypesSpot, compute.VirtualMachineEvictionPolicyTypesDeallocate, billingProfile, nil
+	evictionPolicy := compute.VirtualMachineEvictionPolicyTypesDeallocate
+	if spotVMOptions.EvictionPolicy != nil {
+		evictionPolicy = compute.VirtualMachineEvictionPolicyTypes(*spotVMOptions.EvictionPolicy)
+	}
+	return compute.VirtualMachinePriorityTypesSpot, evictionPolicy, billingProfile, nil
 }
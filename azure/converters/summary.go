@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// SDKToVNetSummary converts an Azure SDK VirtualNetwork to a VNetSummary.
+func SDKToVNetSummary(vnet network.VirtualNetwork, resourceGroup string) azure.VNetSummary {
+	summary := azure.VNetSummary{
+		ID:            to.String(vnet.ID),
+		Name:          to.String(vnet.Name),
+		ResourceGroup: resourceGroup,
+		Tags:          MapToTags(vnet.Tags),
+	}
+
+	props := vnet.VirtualNetworkPropertiesFormat
+	if props == nil {
+		return summary
+	}
+	summary.ProvisioningState = infrav1.ProvisioningState(props.ProvisioningState)
+	if props.AddressSpace != nil {
+		summary.CIDRs = to.StringSlice(props.AddressSpace.AddressPrefixes)
+	}
+	if props.Subnets != nil {
+		summary.Subnets = make([]azure.SubnetSummary, 0, len(*props.Subnets))
+		for _, subnet := range *props.Subnets {
+			summary.Subnets = append(summary.Subnets, SDKToSubnetSummary(subnet))
+		}
+	}
+	return summary
+}
+
+// SDKToSubnetSummary converts an Azure SDK Subnet to a SubnetSummary.
+func SDKToSubnetSummary(subnet network.Subnet) azure.SubnetSummary {
+	summary := azure.SubnetSummary{
+		ID:   to.String(subnet.ID),
+		Name: to.String(subnet.Name),
+	}
+
+	props := subnet.SubnetPropertiesFormat
+	if props == nil {
+		return summary
+	}
+	summary.ProvisioningState = infrav1.ProvisioningState(props.ProvisioningState)
+	switch {
+	case props.AddressPrefixes != nil:
+		summary.CIDRs = to.StringSlice(props.AddressPrefixes)
+	case props.AddressPrefix != nil:
+		summary.CIDRs = []string{to.String(props.AddressPrefix)}
+	}
+	if props.NetworkSecurityGroup != nil {
+		summary.SecurityGroupID = to.String(props.NetworkSecurityGroup.ID)
+	}
+	if props.RouteTable != nil {
+		summary.RouteTableID = to.String(props.RouteTable.ID)
+	}
+	if props.NatGateway != nil {
+		summary.NatGatewayID = to.String(props.NatGateway.ID)
+	}
+	return summary
+}
+
+// SDKToLoadBalancerSummary converts an Azure SDK LoadBalancer to a LoadBalancerSummary.
+func SDKToLoadBalancerSummary(lb network.LoadBalancer) azure.LoadBalancerSummary {
+	summary := azure.LoadBalancerSummary{
+		ID:   to.String(lb.ID),
+		Name: to.String(lb.Name),
+	}
+	if lb.Sku != nil {
+		summary.SKU = string(lb.Sku.Name)
+	}
+
+	props := lb.LoadBalancerPropertiesFormat
+	if props == nil {
+		return summary
+	}
+	summary.ProvisioningState = infrav1.ProvisioningState(props.ProvisioningState)
+	if props.FrontendIPConfigurations != nil {
+		summary.FrontendIPs = make([]azure.FrontendIPSummary, 0, len(*props.FrontendIPConfigurations))
+		for _, ipConfig := range *props.FrontendIPConfigurations {
+			frontendIP := azure.FrontendIPSummary{Name: to.String(ipConfig.Name)}
+			if ipConfig.FrontendIPConfigurationPropertiesFormat != nil {
+				frontendIP.PrivateIPAddress = to.String(ipConfig.PrivateIPAddress)
+				if ipConfig.PublicIPAddress != nil {
+					frontendIP.PublicIPID = to.String(ipConfig.PublicIPAddress.ID)
+				}
+			}
+			summary.FrontendIPs = append(summary.FrontendIPs, frontendIP)
+		}
+	}
+	if props.BackendAddressPools != nil {
+		summary.BackendPools = make([]string, 0, len(*props.BackendAddressPools))
+		for _, pool := range *props.BackendAddressPools {
+			summary.BackendPools = append(summary.BackendPools, to.String(pool.Name))
+		}
+	}
+	return summary
+}
+
+// SDKToPublicIPSummary converts an Azure SDK PublicIPAddress to a PublicIPSummary.
+func SDKToPublicIPSummary(ip network.PublicIPAddress) azure.PublicIPSummary {
+	summary := azure.PublicIPSummary{
+		ID:   to.String(ip.ID),
+		Name: to.String(ip.Name),
+	}
+
+	props := ip.PublicIPAddressPropertiesFormat
+	if props == nil {
+		return summary
+	}
+	summary.ProvisioningState = infrav1.ProvisioningState(props.ProvisioningState)
+	summary.IPAddress = to.String(props.IPAddress)
+	if props.DNSSettings != nil {
+		summary.FQDN = to.String(props.DNSSettings.Fqdn)
+	}
+	return summary
+}
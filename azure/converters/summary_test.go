@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters_test
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+func Test_SDKToVNetSummary(t *testing.T) {
+	cases := []struct {
+		Name   string
+		VNet   network.VirtualNetwork
+		Expect azure.VNetSummary
+	}{
+		{
+			Name: "ShouldPopulateWithData",
+			VNet: network.VirtualNetwork{
+				ID:   to.StringPtr("vnetID"),
+				Name: to.StringPtr("my-vnet"),
+				Tags: map[string]*string{"foo": to.StringPtr("bazz")},
+				VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+					ProvisioningState: network.ProvisioningStateSucceeded,
+					AddressSpace: &network.AddressSpace{
+						AddressPrefixes: &[]string{"10.0.0.0/8"},
+					},
+					Subnets: &[]network.Subnet{
+						{
+							ID:   to.StringPtr("subnetID"),
+							Name: to.StringPtr("my-subnet"),
+							SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+								ProvisioningState:    network.ProvisioningStateSucceeded,
+								AddressPrefix:        to.StringPtr("10.0.0.0/16"),
+								NetworkSecurityGroup: &network.SecurityGroup{ID: to.StringPtr("nsgID")},
+								RouteTable:           &network.RouteTable{ID: to.StringPtr("routeTableID")},
+								NatGateway:           &network.SubResource{ID: to.StringPtr("natGatewayID")},
+							},
+						},
+						{
+							ID:   to.StringPtr("dualStackSubnetID"),
+							Name: to.StringPtr("my-dual-stack-subnet"),
+							SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+								AddressPrefixes: &[]string{"10.1.0.0/16", "2001:1234:5678:9abd::/64"},
+							},
+						},
+					},
+				},
+			},
+			Expect: azure.VNetSummary{
+				ID:                "vnetID",
+				Name:              "my-vnet",
+				ResourceGroup:     "my-rg",
+				CIDRs:             []string{"10.0.0.0/8"},
+				Tags:              infrav1.Tags{"foo": "bazz"},
+				ProvisioningState: infrav1.Succeeded,
+				Subnets: []azure.SubnetSummary{
+					{
+						ID:                "subnetID",
+						Name:              "my-subnet",
+						CIDRs:             []string{"10.0.0.0/16"},
+						SecurityGroupID:   "nsgID",
+						RouteTableID:      "routeTableID",
+						NatGatewayID:      "natGatewayID",
+						ProvisioningState: infrav1.Succeeded,
+					},
+					{
+						ID:    "dualStackSubnetID",
+						Name:  "my-dual-stack-subnet",
+						CIDRs: []string{"10.1.0.0/16", "2001:1234:5678:9abd::/64"},
+					},
+				},
+			},
+		},
+		{
+			Name: "ShouldHandleMissingProperties",
+			VNet: network.VirtualNetwork{
+				ID:   to.StringPtr("vnetID"),
+				Name: to.StringPtr("my-vnet"),
+			},
+			Expect: azure.VNetSummary{
+				ID:            "vnetID",
+				Name:          "my-vnet",
+				ResourceGroup: "my-rg",
+				Tags:          infrav1.Tags{},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(converters.SDKToVNetSummary(c.VNet, "my-rg")).To(gomega.Equal(c.Expect))
+		})
+	}
+}
+
+func Test_SDKToLoadBalancerSummary(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	lb := network.LoadBalancer{
+		ID:   to.StringPtr("lbID"),
+		Name: to.StringPtr("my-lb"),
+		Sku:  &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+		LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+			ProvisioningState: network.ProvisioningStateSucceeded,
+			FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+				{
+					Name: to.StringPtr("public-frontend"),
+					FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &network.PublicIPAddress{ID: to.StringPtr("publicIPID")},
+					},
+				},
+				{
+					Name: to.StringPtr("private-frontend"),
+					FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+						PrivateIPAddress: to.StringPtr("10.0.0.100"),
+					},
+				},
+			},
+			BackendAddressPools: &[]network.BackendAddressPool{
+				{Name: to.StringPtr("my-pool")},
+			},
+		},
+	}
+
+	g.Expect(converters.SDKToLoadBalancerSummary(lb)).To(gomega.Equal(azure.LoadBalancerSummary{
+		ID:   "lbID",
+		Name: "my-lb",
+		SKU:  "Standard",
+		FrontendIPs: []azure.FrontendIPSummary{
+			{Name: "public-frontend", PublicIPID: "publicIPID"},
+			{Name: "private-frontend", PrivateIPAddress: "10.0.0.100"},
+		},
+		BackendPools:      []string{"my-pool"},
+		ProvisioningState: infrav1.Succeeded,
+	}))
+}
+
+func Test_SDKToPublicIPSummary(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ip := network.PublicIPAddress{
+		ID:   to.StringPtr("publicIPID"),
+		Name: to.StringPtr("my-publicip"),
+		PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+			ProvisioningState: network.ProvisioningStateSucceeded,
+			IPAddress:         to.StringPtr("20.1.2.3"),
+			DNSSettings: &network.PublicIPAddressDNSSettings{
+				Fqdn: to.StringPtr("my-cluster.westus2.cloudapp.azure.com"),
+			},
+		},
+	}
+
+	g.Expect(converters.SDKToPublicIPSummary(ip)).To(gomega.Equal(azure.PublicIPSummary{
+		ID:                "publicIPID",
+		Name:              "my-publicip",
+		IPAddress:         "20.1.2.3",
+		FQDN:              "my-cluster.westus2.cloudapp.azure.com",
+		ProvisioningState: infrav1.Succeeded,
+	}))
+}
@@ -44,6 +44,24 @@ func SDKToVMSS(sdkvmss compute.VirtualMachineScaleSet, sdkinstances []compute.Vi
 		vmss.Tags = MapToTags(sdkvmss.Tags)
 	}
 
+	if sdkvmss.VirtualMachineScaleSetProperties != nil {
+		vmss.OrchestrationMode = string(sdkvmss.OrchestrationMode)
+		if sdkvmss.ScaleInPolicy != nil && sdkvmss.ScaleInPolicy.Rules != nil && len(*sdkvmss.ScaleInPolicy.Rules) > 0 {
+			vmss.ScaleInRule = string((*sdkvmss.ScaleInPolicy.Rules)[0])
+		}
+		if sdkvmss.AutomaticRepairsPolicy != nil {
+			vmss.AutomaticRepairs = to.Bool(sdkvmss.AutomaticRepairsPolicy.Enabled)
+			vmss.AutomaticRepairsGracePeriod = to.String(sdkvmss.AutomaticRepairsPolicy.GracePeriod)
+		}
+	}
+
+	if sdkvmss.VirtualMachineScaleSetProperties != nil && sdkvmss.UpgradePolicy != nil {
+		vmss.UpgradeMode = string(sdkvmss.UpgradePolicy.Mode)
+		if sdkvmss.UpgradePolicy.AutomaticOSUpgradePolicy != nil {
+			vmss.AutomaticOSUpgrade = to.Bool(sdkvmss.UpgradePolicy.AutomaticOSUpgradePolicy.EnableAutomaticOSUpgrade)
+		}
+	}
+
 	if len(sdkinstances) > 0 {
 		vmss.Instances = make([]azure.VMSSVM, len(sdkinstances))
 		for i, vm := range sdkinstances {
@@ -58,9 +76,59 @@ func SDKToVMSS(sdkvmss compute.VirtualMachineScaleSet, sdkinstances []compute.Vi
 		vmss.Image = SDKImageToImage(imageRef, sdkvmss.Plan != nil)
 	}
 
+	if sdkvmss.VirtualMachineProfile != nil {
+		vmss.UserData = to.String(sdkvmss.VirtualMachineProfile.UserData)
+	}
+
+	if sdkvmss.VirtualMachineProfile != nil &&
+		sdkvmss.VirtualMachineProfile.StorageProfile != nil &&
+		sdkvmss.VirtualMachineProfile.StorageProfile.DataDisks != nil {
+		vmss.DataDisks = sdkDataDisksToVMSSDataDisks(*sdkvmss.VirtualMachineProfile.StorageProfile.DataDisks)
+	}
+
+	if sdkvmss.VirtualMachineProfile != nil &&
+		sdkvmss.VirtualMachineProfile.DiagnosticsProfile != nil &&
+		sdkvmss.VirtualMachineProfile.DiagnosticsProfile.BootDiagnostics != nil {
+		bootDiagnostics := sdkvmss.VirtualMachineProfile.DiagnosticsProfile.BootDiagnostics
+		vmss.BootDiagnostics = to.Bool(bootDiagnostics.Enabled)
+		vmss.BootDiagnosticsStorageURI = to.String(bootDiagnostics.StorageURI)
+	}
+
+	if sdkvmss.VirtualMachineProfile != nil &&
+		sdkvmss.VirtualMachineProfile.NetworkProfile != nil &&
+		sdkvmss.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations != nil {
+		for _, nic := range *sdkvmss.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations {
+			if nic.VirtualMachineScaleSetNetworkConfigurationProperties != nil && to.Bool(nic.Primary) {
+				vmss.AcceleratedNetworking = to.Bool(nic.EnableAcceleratedNetworking)
+			}
+		}
+	}
+
 	return vmss
 }
 
+// sdkDataDisksToVMSSDataDisks converts the data disks of the model of an Azure SDK VirtualMachineScaleSet.
+func sdkDataDisksToVMSSDataDisks(sdkDataDisks []compute.VirtualMachineScaleSetDataDisk) []azure.VMSSDataDisk {
+	if len(sdkDataDisks) == 0 {
+		return nil
+	}
+
+	dataDisks := make([]azure.VMSSDataDisk, len(sdkDataDisks))
+	for i, disk := range sdkDataDisks {
+		dataDisks[i] = azure.VMSSDataDisk{
+			Name:                    to.String(disk.Name),
+			Lun:                     to.Int32(disk.Lun),
+			DiskSizeGB:              to.Int32(disk.DiskSizeGB),
+			CachingType:             string(disk.Caching),
+			WriteAcceleratorEnabled: to.Bool(disk.WriteAcceleratorEnabled),
+		}
+		if disk.ManagedDisk != nil {
+			dataDisks[i].StorageAccountType = string(disk.ManagedDisk.StorageAccountType)
+		}
+	}
+	return dataDisks
+}
+
 // SDKToVMSSVM converts an Azure SDK VirtualMachineScaleSetVM into an infrav1exp.VMSSVM.
 func SDKToVMSSVM(sdkInstance compute.VirtualMachineScaleSetVM) *azure.VMSSVM {
 	instance := azure.VMSSVM{
@@ -91,6 +159,45 @@ func SDKToVMSSVM(sdkInstance compute.VirtualMachineScaleSetVM) *azure.VMSSVM {
 		instance.AvailabilityZone = to.StringSlice(sdkInstance.Zones)[0]
 	}
 
+	if policy := sdkInstance.ProtectionPolicy; policy != nil {
+		instance.ProtectFromScaleIn = to.Bool(policy.ProtectFromScaleIn)
+		instance.ProtectFromScaleSetActions = to.Bool(policy.ProtectFromScaleSetActions)
+	}
+
+	return &instance
+}
+
+// SDKVMToVMSSVM converts an Azure SDK VirtualMachine of a scale set in the Flexible orchestration mode into an
+// azure.VMSSVM. The instances of a Flexible scale set are standard VMs, which are identified by their name.
+func SDKVMToVMSSVM(sdkInstance compute.VirtualMachine) *azure.VMSSVM {
+	instance := azure.VMSSVM{
+		ID:         to.String(sdkInstance.ID),
+		InstanceID: to.String(sdkInstance.Name),
+	}
+
+	if sdkInstance.VirtualMachineProperties == nil {
+		return &instance
+	}
+
+	instance.State = infrav1.Creating
+	if sdkInstance.ProvisioningState != nil {
+		instance.State = infrav1.ProvisioningState(to.String(sdkInstance.ProvisioningState))
+	}
+
+	if sdkInstance.OsProfile != nil && sdkInstance.OsProfile.ComputerName != nil {
+		instance.Name = *sdkInstance.OsProfile.ComputerName
+	}
+
+	if sdkInstance.StorageProfile != nil && sdkInstance.StorageProfile.ImageReference != nil {
+		imageRef := sdkInstance.StorageProfile.ImageReference
+		instance.Image = SDKImageToImage(imageRef, sdkInstance.Plan != nil)
+	}
+
+	if sdkInstance.Zones != nil && len(*sdkInstance.Zones) > 0 {
+		// an instance should only have 1 zone, so we select the first item of the slice
+		instance.AvailabilityZone = to.StringSlice(sdkInstance.Zones)[0]
+	}
+
 	return &instance
 }
 
@@ -109,6 +109,101 @@ func Test_SDKToVMSS(t *testing.T) {
 				g.Expect(actual).To(gomega.Equal(&expected))
 			},
 		},
+		{
+			Name: "ShouldPopulateDataDisks",
+			SubjectFactory: func(g *gomega.GomegaWithT) (compute.VirtualMachineScaleSet, []compute.VirtualMachineScaleSetVM) {
+				return compute.VirtualMachineScaleSet{
+					VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
+						VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
+							StorageProfile: &compute.VirtualMachineScaleSetStorageProfile{
+								DataDisks: &[]compute.VirtualMachineScaleSetDataDisk{
+									{
+										Name:       to.StringPtr("vmssName_etcd"),
+										Lun:        to.Int32Ptr(0),
+										DiskSizeGB: to.Int32Ptr(256),
+										Caching:    compute.CachingTypesReadWrite,
+									},
+									{
+										Name:                    to.StringPtr("vmssName_ultra"),
+										Lun:                     to.Int32Ptr(1),
+										DiskSizeGB:              to.Int32Ptr(1024),
+										Caching:                 compute.CachingTypesNone,
+										WriteAcceleratorEnabled: to.BoolPtr(true),
+										ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
+											StorageAccountType: compute.StorageAccountTypesUltraSSDLRS,
+										},
+									},
+								},
+							},
+						},
+					},
+				}, nil
+			},
+			Expect: func(g *gomega.GomegaWithT, actual *azure.VMSS) {
+				g.Expect(actual.DataDisks).To(gomega.Equal([]azure.VMSSDataDisk{
+					{
+						Name:        "vmssName_etcd",
+						Lun:         0,
+						DiskSizeGB:  256,
+						CachingType: "ReadWrite",
+					},
+					{
+						Name:                    "vmssName_ultra",
+						Lun:                     1,
+						DiskSizeGB:              1024,
+						StorageAccountType:      "UltraSSD_LRS",
+						CachingType:             "None",
+						WriteAcceleratorEnabled: true,
+					},
+				}))
+			},
+		},
+		{
+			Name: "ShouldPopulateAcceleratedNetworking",
+			SubjectFactory: func(g *gomega.GomegaWithT) (compute.VirtualMachineScaleSet, []compute.VirtualMachineScaleSetVM) {
+				return compute.VirtualMachineScaleSet{
+					VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
+						VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
+							NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
+								NetworkInterfaceConfigurations: &[]compute.VirtualMachineScaleSetNetworkConfiguration{
+									{
+										Name: to.StringPtr("vmssName-netconfig"),
+										VirtualMachineScaleSetNetworkConfigurationProperties: &compute.VirtualMachineScaleSetNetworkConfigurationProperties{
+											Primary:                     to.BoolPtr(true),
+											EnableAcceleratedNetworking: to.BoolPtr(true),
+										},
+									},
+								},
+							},
+						},
+					},
+				}, nil
+			},
+			Expect: func(g *gomega.GomegaWithT, actual *azure.VMSS) {
+				g.Expect(actual.AcceleratedNetworking).To(gomega.BeTrue())
+			},
+		},
+		{
+			Name: "ShouldPopulateBootDiagnostics",
+			SubjectFactory: func(g *gomega.GomegaWithT) (compute.VirtualMachineScaleSet, []compute.VirtualMachineScaleSetVM) {
+				return compute.VirtualMachineScaleSet{
+					VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
+						VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
+							DiagnosticsProfile: &compute.DiagnosticsProfile{
+								BootDiagnostics: &compute.BootDiagnostics{
+									Enabled:    to.BoolPtr(true),
+									StorageURI: to.StringPtr("https://fake.blob.core.windows.net/"),
+								},
+							},
+						},
+					},
+				}, nil
+			},
+			Expect: func(g *gomega.GomegaWithT, actual *azure.VMSS) {
+				g.Expect(actual.BootDiagnostics).To(gomega.BeTrue())
+				g.Expect(actual.BootDiagnosticsStorageURI).To(gomega.Equal("https://fake.blob.core.windows.net/"))
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -122,3 +217,44 @@ func Test_SDKToVMSS(t *testing.T) {
 		})
 	}
 }
+
+func Test_SDKVMToVMSSVM(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vm := compute.VirtualMachine{
+		ID:    to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/vmssName_1a2b3c4d"),
+		Name:  to.StringPtr("vmssName_1a2b3c4d"),
+		Zones: to.StringSlicePtr([]string{"2"}),
+		VirtualMachineProperties: &compute.VirtualMachineProperties{
+			ProvisioningState: to.StringPtr(string(compute.ProvisioningState1Succeeded)),
+			OsProfile: &compute.OSProfile{
+				ComputerName: to.StringPtr("vmssname000001"),
+			},
+			VirtualMachineScaleSet: &compute.SubResource{
+				ID: to.StringPtr("vmssID"),
+			},
+		},
+	}
+	g.Expect(converters.SDKVMToVMSSVM(vm)).To(gomega.Equal(&azure.VMSSVM{
+		ID:               "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/vmssName_1a2b3c4d",
+		InstanceID:       "vmssName_1a2b3c4d",
+		Name:             "vmssname000001",
+		AvailabilityZone: "2",
+		State:            "Succeeded",
+	}))
+}
+
+func Test_SDKToVMSSVM_ProtectionPolicy(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	instance := compute.VirtualMachineScaleSetVM{
+		InstanceID: to.StringPtr("0"),
+		VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+			ProvisioningState: to.StringPtr(string(compute.ProvisioningState1Succeeded)),
+			ProtectionPolicy: &compute.VirtualMachineScaleSetVMProtectionPolicy{
+				ProtectFromScaleIn: to.BoolPtr(true),
+			},
+		},
+	}
+	subject := converters.SDKToVMSSVM(instance)
+	g.Expect(subject.ProtectFromScaleIn).To(gomega.BeTrue())
+	g.Expect(subject.ProtectFromScaleSetActions).To(gomega.BeFalse())
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package azure
 
 import (
+	"context"
+	"crypto/sha256"
 	"fmt"
 	"net/http"
 
@@ -25,6 +27,7 @@ import (
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/payloadlog"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	"sigs.k8s.io/cluster-api-provider-azure/version"
 )
@@ -45,6 +48,15 @@ const (
 	LatestVersion = "latest"
 )
 
+const (
+	// DefaultCommunityGalleryName is the public name of the community gallery of the reference images.
+	DefaultCommunityGalleryName = "ClusterAPI-f72ceb4f-5159-4c26-a0fe-2ea738f0d019"
+	// DefaultCommunityGalleryUbuntuImageName is the name of the Ubuntu reference image in the community gallery.
+	DefaultCommunityGalleryUbuntuImageName = "capi-ubun2-2004"
+	// DefaultCommunityGalleryWindowsImageName is the name of the Windows reference image in the community gallery.
+	DefaultCommunityGalleryWindowsImageName = "capi-win-2019-containerd"
+)
+
 const (
 	// LinuxOS is Linux OS value for OSDisk.OSType.
 	LinuxOS = "Linux"
@@ -60,6 +72,10 @@ const (
 const (
 	// PrivateAPIServerHostname will be used as the api server hostname for private clusters.
 	PrivateAPIServerHostname = "apiserver"
+	// PrivateDNSRecordOwnerKey is the metadata key of the private DNS record sets set to the name of the cluster owning them.
+	PrivateDNSRecordOwnerKey = "capz_cluster"
+	// PublicDNSRecordOwnerKey is the metadata key of the public DNS record set of the API server set to the name of the cluster owning it.
+	PublicDNSRecordOwnerKey = "capz_cluster"
 )
 
 const (
@@ -167,11 +183,28 @@ func GenerateDataDiskName(machineName, nameSuffix string) string {
 	return fmt.Sprintf("%s_%s", machineName, nameSuffix)
 }
 
+// GenerateSharedDataDiskName generates the name of a data disk shared by the machines of a cluster.
+func GenerateSharedDataDiskName(clusterName, nameSuffix string) string {
+	return fmt.Sprintf("%s_%s", clusterName, nameSuffix)
+}
+
+// GenerateVHDImageName generates the name of the managed image created from a VHD blob for the machines of a cluster.
+// The name includes a hash of the URI of the blob, so that machines using different VHDs get different images.
+func GenerateVHDImageName(clusterName, blobURI string) string {
+	hash := sha256.Sum256([]byte(blobURI))
+	return fmt.Sprintf("%s-vhd-%x", clusterName, hash[:5])
+}
+
 // GenerateVnetPeeringName generates the name for a peering between two vnets.
 func GenerateVnetPeeringName(sourceVnetName string, remoteVnetName string) string {
 	return fmt.Sprintf("%s-To-%s", sourceVnetName, remoteVnetName)
 }
 
+// GenerateFlowLogName generates the name of the flow log of a network security group.
+func GenerateFlowLogName(resourceGroup, nsgName string) string {
+	return fmt.Sprintf("%s-%s-flowlog", resourceGroup, nsgName)
+}
+
 // GenerateAvailabilitySetName generates the name of a availability set based on the cluster name and the node group.
 // node group identifies the set of nodes that belong to this availability set:
 // For control plane nodes, this will be `control-plane`.
@@ -180,6 +213,22 @@ func GenerateAvailabilitySetName(clusterName, nodeGroup string) string {
 	return fmt.Sprintf("%s_%s-as", clusterName, nodeGroup)
 }
 
+// GenerateExportedTemplateName generates the name of the ConfigMap holding the exported ARM template of a cluster.
+func GenerateExportedTemplateName(clusterName string) string {
+	return fmt.Sprintf("%s-arm-template", clusterName)
+}
+
+// GenerateNetworkDeploymentName generates the name of the ARM deployment provisioning the initial network of a cluster.
+func GenerateNetworkDeploymentName(clusterName string) string {
+	return fmt.Sprintf("%s-network", clusterName)
+}
+
+// GenerateImageTemplateName generates the name of the Azure Image Builder template of an AzureNodeImage. The namespace
+// is part of the name as templates of AzureNodeImages in different namespaces may share a resource group.
+func GenerateImageTemplateName(namespace, name string) string {
+	return fmt.Sprintf("%s-%s", namespace, name)
+}
+
 // WithIndex appends the index as suffix to a generated name.
 func WithIndex(name string, n int) string {
 	return fmt.Sprintf("%s-%d", name, n)
@@ -195,11 +244,36 @@ func VMID(subscriptionID, resourceGroup, vmName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines/%s", subscriptionID, resourceGroup, vmName)
 }
 
+// ImageID returns the azure resource ID for a given managed image.
+func ImageID(subscriptionID, resourceGroup, imageName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/images/%s", subscriptionID, resourceGroup, imageName)
+}
+
+// GalleryImageID returns the azure resource ID for a given image definition of a shared image gallery.
+func GalleryImageID(subscriptionID, resourceGroup, galleryName, imageName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/galleries/%s/images/%s", subscriptionID, resourceGroup, galleryName, imageName)
+}
+
+// GalleryImageVersionID returns the azure resource ID for a given image version of a shared image gallery.
+func GalleryImageVersionID(subscriptionID, resourceGroup, galleryName, imageName, version string) string {
+	return fmt.Sprintf("%s/versions/%s", GalleryImageID(subscriptionID, resourceGroup, galleryName, imageName), version)
+}
+
+// DiskID returns the azure resource ID for a given managed disk.
+func DiskID(subscriptionID, resourceGroup, diskName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/disks/%s", subscriptionID, resourceGroup, diskName)
+}
+
 // VNetID returns the azure resource ID for a given VNet.
 func VNetID(subscriptionID, resourceGroup, vnetName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s", subscriptionID, resourceGroup, vnetName)
 }
 
+// DDoSProtectionPlanID returns the azure resource ID for a given DDoS Protection Plan.
+func DDoSProtectionPlanID(subscriptionID, resourceGroup, planName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/ddosProtectionPlans/%s", subscriptionID, resourceGroup, planName)
+}
+
 // SubnetID returns the azure resource ID for a given subnet.
 func SubnetID(subscriptionID, resourceGroup, vnetName, subnetName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s/subnets/%s", subscriptionID, resourceGroup, vnetName, subnetName)
@@ -230,6 +304,11 @@ func NetworkInterfaceID(subscriptionID, resourceGroup, nicName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkInterfaces/%s", subscriptionID, resourceGroup, nicName)
 }
 
+// LoadBalancerID returns the azure resource ID for a given load balancer.
+func LoadBalancerID(subscriptionID, resourceGroup, loadBalancerName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers/%s", subscriptionID, resourceGroup, loadBalancerName)
+}
+
 // FrontendIPConfigID returns the azure resource ID for a given frontend IP config.
 func FrontendIPConfigID(subscriptionID, resourceGroup, loadBalancerName, configName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers/%s/frontendIPConfigurations/%s", subscriptionID, resourceGroup, loadBalancerName, configName)
@@ -255,6 +334,16 @@ func AvailabilitySetID(subscriptionID, resourceGroup, availabilitySetName string
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/availabilitySets/%s", subscriptionID, resourceGroup, availabilitySetName)
 }
 
+// DiskEncryptionSetID returns the azure resource ID for a given disk encryption set.
+func DiskEncryptionSetID(subscriptionID, resourceGroup, diskEncryptionSetName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/diskEncryptionSets/%s", subscriptionID, resourceGroup, diskEncryptionSetName)
+}
+
+// ProximityPlacementGroupID returns the azure resource ID for a given proximity placement group.
+func ProximityPlacementGroupID(subscriptionID, resourceGroup, proximityPlacementGroupName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/proximityPlacementGroups/%s", subscriptionID, resourceGroup, proximityPlacementGroupName)
+}
+
 // GetDefaultImageSKUID gets the SKU ID of the image to use for the provided version of Kubernetes.
 func getDefaultImageSKUID(k8sVersion, os, osVersion string) (string, error) {
 	version, err := semver.ParseTolerant(k8sVersion)
@@ -332,6 +421,67 @@ func GetDefaultWindowsImage(k8sVersion, runtime string) (*infrav1.Image, error)
 	return defaultImage, nil
 }
 
+// NewImageResolver returns the image resolver of the given source of default images.
+func NewImageResolver(source DefaultImageSource) (ImageResolver, error) {
+	switch source {
+	case DefaultImageSourceMarketplace:
+		return MarketplaceImageResolver{}, nil
+	case DefaultImageSourceCommunityGallery:
+		return CommunityGalleryImageResolver{}, nil
+	default:
+		return nil, errors.Errorf("unknown default image source %q", source)
+	}
+}
+
+// MarketplaceImageResolver resolves the reference images published in the Azure Marketplace. The resolved images use
+// the latest version of the SKU of the Kubernetes version.
+type MarketplaceImageResolver struct{}
+
+// ResolveImage returns the marketplace reference image of the Kubernetes version for the OS type.
+func (MarketplaceImageResolver) ResolveImage(_ context.Context, k8sVersion, osType, runtime string) (*infrav1.Image, error) {
+	if osType == WindowsOS {
+		return GetDefaultWindowsImage(k8sVersion, runtime)
+	}
+	return GetDefaultUbuntuImage(k8sVersion)
+}
+
+// CommunityGalleryImageResolver resolves the reference images published in the community gallery of Cluster API.
+// The versions of these images are the Kubernetes versions they ship, so the resolved images are pinned to an exact
+// image version.
+type CommunityGalleryImageResolver struct {
+	// Gallery is the public name of the community gallery. Defaults to DefaultCommunityGalleryName.
+	Gallery string
+}
+
+// ResolveImage returns the community gallery reference image of the Kubernetes version for the OS type.
+func (r CommunityGalleryImageResolver) ResolveImage(_ context.Context, k8sVersion, osType, runtime string) (*infrav1.Image, error) {
+	v, err := semver.ParseTolerant(k8sVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse Kubernetes version \"%s\"", k8sVersion)
+	}
+
+	name := DefaultCommunityGalleryUbuntuImageName
+	if osType == WindowsOS {
+		if runtime == "dockershim" {
+			return nil, errors.New("dockershim images are not published in the community gallery")
+		}
+		name = DefaultCommunityGalleryWindowsImageName
+	}
+
+	gallery := r.Gallery
+	if gallery == "" {
+		gallery = DefaultCommunityGalleryName
+	}
+
+	return &infrav1.Image{
+		CommunityGallery: &infrav1.AzureCommunityGalleryImage{
+			Gallery: gallery,
+			Name:    name,
+			Version: fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch),
+		},
+	}, nil
+}
+
 // GetBootstrappingVMExtension returns the CAPZ Bootstrapping VM extension.
 // The CAPZ Bootstrapping extension is a simple clone of https://github.com/Azure/custom-script-extension-linux for Linux or
 // https://docs.microsoft.com/en-us/azure/virtual-machines/extensions/custom-script-windows for Windows.
@@ -372,13 +522,14 @@ func UserAgent() string {
 	return fmt.Sprintf("cluster-api-provider-azure/%s", version.Get().String())
 }
 
-// SetAutoRestClientDefaults set authorizer and user agent for autorest client.
-func SetAutoRestClientDefaults(c *autorest.Client, auth autorest.Authorizer) {
+// SetAutoRestClientDefaults set authorizer and user agent for autorest client. The service is the name of the service
+// the client is used by, e.g. "natgateways", which its requests are logged under by the payload logging.
+func SetAutoRestClientDefaults(c *autorest.Client, auth autorest.Authorizer, service string) {
 	c.Authorizer = auth
 	// Wrap the original Sender on the autorest.Client c.
 	// The wrapped Sender should set the x-ms-correlation-request-id on the given
 	// request, then pass the new request to the underlying Sender.
-	c.Sender = autorest.DecorateSender(c.Sender, msCorrelationIDSendDecorator)
+	c.Sender = autorest.DecorateSender(c.Sender, msCorrelationIDSendDecorator, payloadlog.SendDecorator(service))
 	// The default number of retries is 3. This means the client will attempt to retry operation results like resource
 	// conflicts (HTTP 409). For a reconciling controller, this is undesirable behavior since if the controller runs
 	// into an error reconciling, the controller would be better off to end with an error and try again later.
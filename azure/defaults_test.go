@@ -27,6 +27,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -242,6 +243,86 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 	}
 }
 
+func TestCommunityGalleryImageResolver(t *testing.T) {
+	tests := []struct {
+		name          string
+		resolver      CommunityGalleryImageResolver
+		k8sVersion    string
+		osType        string
+		runtime       string
+		expectedImage *infrav1.Image
+		expectedErr   string
+	}{
+		{
+			name:       "Linux image",
+			k8sVersion: "v1.23.6",
+			osType:     LinuxOS,
+			expectedImage: &infrav1.Image{
+				CommunityGallery: &infrav1.AzureCommunityGalleryImage{
+					Gallery: DefaultCommunityGalleryName,
+					Name:    "capi-ubun2-2004",
+					Version: "1.23.6",
+				},
+			},
+		},
+		{
+			name:       "Windows image of a custom gallery",
+			resolver:   CommunityGalleryImageResolver{Gallery: "my-gallery"},
+			k8sVersion: "1.24.2",
+			osType:     WindowsOS,
+			expectedImage: &infrav1.Image{
+				CommunityGallery: &infrav1.AzureCommunityGalleryImage{
+					Gallery: "my-gallery",
+					Name:    "capi-win-2019-containerd",
+					Version: "1.24.2",
+				},
+			},
+		},
+		{
+			name:        "Windows dockershim image",
+			k8sVersion:  "v1.21.1",
+			osType:      WindowsOS,
+			runtime:     "dockershim",
+			expectedErr: "dockershim images are not published in the community gallery",
+		},
+		{
+			name:        "invalid Kubernetes version",
+			k8sVersion:  "latest",
+			osType:      LinuxOS,
+			expectedErr: "unable to parse Kubernetes version \"latest\"",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			image, err := test.resolver.ResolveImage(context.TODO(), test.k8sVersion, test.osType, test.runtime)
+			if test.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(test.expectedErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(image).To(Equal(test.expectedImage))
+		})
+	}
+}
+
+func TestNewImageResolver(t *testing.T) {
+	g := NewWithT(t)
+
+	resolver, err := NewImageResolver(DefaultImageSourceMarketplace)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resolver).To(Equal(MarketplaceImageResolver{}))
+
+	resolver, err = NewImageResolver(DefaultImageSourceCommunityGallery)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resolver).To(Equal(CommunityGalleryImageResolver{}))
+
+	_, err = NewImageResolver("SharedImageGallery")
+	g.Expect(err).To(MatchError("unknown default image source \"SharedImageGallery\""))
+}
+
 func TestMSCorrelationIDSendDecorator(t *testing.T) {
 	g := NewWithT(t)
 	const corrID tele.CorrID = "TestMSCorrelationIDSendDecoratorCorrID"
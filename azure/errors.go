@@ -25,6 +25,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/azure"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 )
 
 // ErrNotOwned is returned when a resource can't be deleted because it isn't owned.
@@ -41,8 +42,7 @@ func ResourceGroupNotFound(err error) bool {
 
 // ResourceNotFound parses the error to check if it's a resource not found error.
 func ResourceNotFound(err error) bool {
-	derr := autorest.DetailedError{}
-	return errors.As(err, &derr) && derr.StatusCode == 404
+	return azureerrors.IsNotFound(err)
 }
 
 // ResourceConflict parses the error to check if it's a resource conflict error (409).
@@ -51,6 +51,19 @@ func ResourceConflict(err error) bool {
 	return errors.As(err, &derr) && derr.StatusCode == 409
 }
 
+// PreconditionFailed parses the error to check if it's a precondition failed error (412), returned when the etag of
+// a conditional request no longer matches the resource.
+func PreconditionFailed(err error) bool {
+	derr := autorest.DetailedError{}
+	return errors.As(err, &derr) && derr.StatusCode == 412
+}
+
+// BadRequest parses the error to check if it's a bad request error (400).
+func BadRequest(err error) bool {
+	derr := autorest.DetailedError{}
+	return errors.As(err, &derr) && derr.StatusCode == 400
+}
+
 // VMDeletedError is returned when a virtual machine is deleted outside of capz.
 type VMDeletedError struct {
 	ProviderID string
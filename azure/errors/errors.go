@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errors defines the typed errors returned by the Azure service clients.
+//
+// Every typed error wraps the original Azure SDK error and returns its message unchanged, so callers can branch on
+// the type with errors.As or the Is* helpers while still having access to the full response through errors.Unwrap.
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+const (
+	codeResourceNotFound           = "ResourceNotFound"
+	codeResourceGroupNotFound      = "ResourceGroupNotFound"
+	codeAnotherOperationInProgress = "AnotherOperationInProgress"
+
+	// defaultRetryAfter is used when a throttled response does not have a Retry-After header.
+	defaultRetryAfter = 30 * time.Second
+)
+
+// NotFoundError is returned when an Azure resource, or the resource group holding it, does not exist.
+type NotFoundError struct {
+	Err error
+}
+
+// Error returns the message of the wrapped error.
+func (e NotFoundError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// ThrottledError is returned when Azure rejects a request because too many requests were made.
+type ThrottledError struct {
+	Err error
+	// RetryAfter is how long Azure asked to wait before retrying the request.
+	RetryAfter time.Duration
+}
+
+// Error returns the message of the wrapped error.
+func (e ThrottledError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e ThrottledError) Unwrap() error {
+	return e.Err
+}
+
+// TerminalError is returned when Azure rejects a request that cannot succeed by retrying it unchanged, e.g. an
+// invalid request or a missing permission.
+type TerminalError struct {
+	Err error
+	// Code is the error code returned by Azure, if any.
+	Code string
+}
+
+// Error returns the message of the wrapped error.
+func (e TerminalError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e TerminalError) Unwrap() error {
+	return e.Err
+}
+
+// RetryableError is returned when Azure rejects a request with an error classified as retryable by the configured
+// Classification, i.e. an error which is expected to succeed when the request is retried unchanged.
+type RetryableError struct {
+	Err error
+	// Code is the error code returned by Azure, if any.
+	Code string
+}
+
+// Error returns the message of the wrapped error.
+func (e RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// OperationInProgressError is returned when a long-running operation is not done yet, or when Azure rejects a request
+// because of another operation in progress on the same resource.
+type OperationInProgressError struct {
+	Err error
+}
+
+// Error returns the message of the wrapped error.
+func (e OperationInProgressError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e OperationInProgressError) Unwrap() error {
+	return e.Err
+}
+
+// Classification lists the Azure error codes and HTTP status codes classified as retryable or terminal on top of the
+// built-in classification, e.g. for the non-standard errors returned by sovereign clouds or Azure Stack Hub. Each
+// entry is either an error code, matched case-insensitively, or an HTTP status code such as 409.
+type Classification struct {
+	// Retryable lists the errors which are expected to succeed when the request is retried.
+	Retryable []string
+	// Terminal lists the errors which cannot succeed when the request is retried.
+	Terminal []string
+}
+
+var classification Classification
+
+// SetClassification sets the classification applied by FromAutorest to the errors that are neither not found errors
+// nor operations in progress. It takes precedence over the built-in classification of HTTP status codes.
+func SetClassification(c Classification) {
+	classification = c
+}
+
+// matches returns true if the error code or HTTP status code is one of the entries.
+func matches(entries []string, code string, statusCode int) bool {
+	for _, entry := range entries {
+		if (code != "" && strings.EqualFold(entry, code)) || (statusCode != 0 && entry == strconv.Itoa(statusCode)) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromAutorest returns err wrapped in the typed error matching the Azure response it carries.
+// Errors that are nil, already typed, or don't match any type are returned unchanged.
+func FromAutorest(err error) error {
+	if err == nil || isTyped(err) {
+		return err
+	}
+
+	if errors.As(err, &azure.AsyncOpIncompleteError{}) {
+		return OperationInProgressError{Err: err}
+	}
+
+	code := serviceErrorCode(err)
+	switch code {
+	case codeResourceNotFound, codeResourceGroupNotFound:
+		return NotFoundError{Err: err}
+	case codeAnotherOperationInProgress:
+		return OperationInProgressError{Err: err}
+	}
+
+	statusCode := responseStatusCode(err)
+	switch {
+	case matches(classification.Terminal, code, statusCode):
+		return TerminalError{Err: err, Code: code}
+	case matches(classification.Retryable, code, statusCode):
+		return RetryableError{Err: err, Code: code}
+	}
+
+	switch statusCode {
+	case http.StatusNotFound:
+		return NotFoundError{Err: err}
+	case http.StatusTooManyRequests:
+		retryAfter := defaultRetryAfter
+		derr := autorest.DetailedError{}
+		if errors.As(err, &derr) && derr.Response != nil {
+			retryAfter = autorest.GetRetryAfter(derr.Response, defaultRetryAfter)
+		}
+		return ThrottledError{Err: err, RetryAfter: retryAfter}
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusMethodNotAllowed,
+		http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return TerminalError{Err: err, Code: code}
+	}
+	return err
+}
+
+// Classify replaces the error pointed to by err with its typed error. It is meant to be deferred by the service
+// clients so that every error they return is typed.
+func Classify(err *error) {
+	*err = FromAutorest(*err)
+}
+
+// IsNotFound returns true if the error is a NotFoundError or an Azure response with a 404 status code.
+func IsNotFound(err error) bool {
+	return errors.As(FromAutorest(err), &NotFoundError{})
+}
+
+// IsThrottled returns true if the error is a ThrottledError or an Azure response with a 429 status code.
+func IsThrottled(err error) bool {
+	return errors.As(FromAutorest(err), &ThrottledError{})
+}
+
+// IsTerminal returns true if the error is a TerminalError or an Azure response that cannot succeed when retried.
+func IsTerminal(err error) bool {
+	return errors.As(FromAutorest(err), &TerminalError{})
+}
+
+// IsRetryable returns true if the error is a RetryableError or an Azure response classified as retryable.
+func IsRetryable(err error) bool {
+	return errors.As(FromAutorest(err), &RetryableError{})
+}
+
+// IsClassifiedTerminal returns true if the error is an Azure response classified as terminal by the configured
+// Classification. Unlike IsTerminal, it doesn't match the errors of the built-in classification.
+func IsClassifiedTerminal(err error) bool {
+	return IsTerminal(err) && matches(classification.Terminal, serviceErrorCode(err), responseStatusCode(err))
+}
+
+// IsOperationInProgress returns true if the error is an OperationInProgressError or an Azure response reporting an
+// operation in progress.
+func IsOperationInProgress(err error) bool {
+	return errors.As(FromAutorest(err), &OperationInProgressError{})
+}
+
+// isTyped returns true if the error already wraps one of the typed errors.
+func isTyped(err error) bool {
+	return errors.As(err, &NotFoundError{}) || errors.As(err, &ThrottledError{}) ||
+		errors.As(err, &TerminalError{}) || errors.As(err, &RetryableError{}) || errors.As(err, &OperationInProgressError{})
+}
+
+// serviceErrorCode returns the error code of the Azure service error wrapped by err, if any.
+func serviceErrorCode(err error) string {
+	rerr := &azure.RequestError{}
+	if errors.As(err, &rerr) && rerr.ServiceError != nil {
+		return rerr.ServiceError.Code
+	}
+	serr := &azure.ServiceError{}
+	if errors.As(err, &serr) {
+		return serr.Code
+	}
+	return ""
+}
+
+// responseStatusCode returns the HTTP status code of the Azure response wrapped by err, if any.
+func responseStatusCode(err error) int {
+	derr := autorest.DetailedError{}
+	if !errors.As(err, &derr) {
+		return 0
+	}
+	statusCode, _ := derr.StatusCode.(int)
+	return statusCode
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	. "github.com/onsi/gomega"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func responseError(statusCode int, header http.Header) error {
+	return autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: statusCode, Header: header}, "Failure")
+}
+
+func serviceError(statusCode int, code string) error {
+	return autorest.NewErrorWithError(&azure.RequestError{
+		DetailedError: autorest.DetailedError{StatusCode: statusCode},
+		ServiceError:  &azure.ServiceError{Code: code},
+	}, "", "", &http.Response{StatusCode: statusCode}, "Failure responding to request")
+}
+
+func TestFromAutorest(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		expect func(g *WithT, err error)
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(BeNil())
+			},
+		},
+		{
+			name: "not found",
+			err:  responseError(http.StatusNotFound, nil),
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(BeAssignableToTypeOf(NotFoundError{}))
+				g.Expect(IsNotFound(err)).To(BeTrue())
+			},
+		},
+		{
+			name: "resource group not found",
+			err:  serviceError(http.StatusNotFound, codeResourceGroupNotFound),
+			expect: func(g *WithT, err error) {
+				g.Expect(IsNotFound(err)).To(BeTrue())
+			},
+		},
+		{
+			name: "throttled with Retry-After header",
+			err:  responseError(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"10"}}),
+			expect: func(g *WithT, err error) {
+				g.Expect(IsThrottled(err)).To(BeTrue())
+				terr := ThrottledError{}
+				g.Expect(errors.As(err, &terr)).To(BeTrue())
+				g.Expect(terr.RetryAfter).To(Equal(10 * time.Second))
+			},
+		},
+		{
+			name: "throttled without Retry-After header",
+			err:  responseError(http.StatusTooManyRequests, http.Header{}),
+			expect: func(g *WithT, err error) {
+				terr := ThrottledError{}
+				g.Expect(errors.As(err, &terr)).To(BeTrue())
+				g.Expect(terr.RetryAfter).To(Equal(defaultRetryAfter))
+			},
+		},
+		{
+			name: "bad request",
+			err:  serviceError(http.StatusBadRequest, "InvalidParameter"),
+			expect: func(g *WithT, err error) {
+				g.Expect(IsTerminal(err)).To(BeTrue())
+				terr := TerminalError{}
+				g.Expect(errors.As(err, &terr)).To(BeTrue())
+				g.Expect(terr.Code).To(Equal("InvalidParameter"))
+			},
+		},
+		{
+			name: "another operation in progress",
+			err:  serviceError(http.StatusConflict, codeAnotherOperationInProgress),
+			expect: func(g *WithT, err error) {
+				g.Expect(IsOperationInProgress(err)).To(BeTrue())
+				g.Expect(IsTerminal(err)).To(BeFalse())
+			},
+		},
+		{
+			name: "long-running operation not done",
+			err:  azure.NewAsyncOpIncompleteError("network.VirtualNetworksCreateOrUpdateFuture"),
+			expect: func(g *WithT, err error) {
+				g.Expect(IsOperationInProgress(err)).To(BeTrue())
+			},
+		},
+		{
+			name: "server error is not typed",
+			err:  responseError(http.StatusInternalServerError, nil),
+			expect: func(g *WithT, err error) {
+				g.Expect(isTyped(err)).To(BeFalse())
+				g.Expect(IsNotFound(err)).To(BeFalse())
+				g.Expect(IsThrottled(err)).To(BeFalse())
+				g.Expect(IsTerminal(err)).To(BeFalse())
+				g.Expect(IsOperationInProgress(err)).To(BeFalse())
+			},
+		},
+		{
+			name: "wrapped error keeps its message and response",
+			err:  pkgerrors.Wrap(responseError(http.StatusNotFound, nil), "failed to get VNet my-vnet"),
+			expect: func(g *WithT, err error) {
+				g.Expect(IsNotFound(err)).To(BeTrue())
+				g.Expect(err).To(MatchError("failed to get VNet my-vnet: #: Failure: StatusCode=404"))
+				derr := autorest.DetailedError{}
+				g.Expect(errors.As(err, &derr)).To(BeTrue())
+			},
+		},
+		{
+			name: "typed error is not wrapped twice",
+			err:  NotFoundError{Err: responseError(http.StatusTooManyRequests, nil)},
+			expect: func(g *WithT, err error) {
+				g.Expect(IsThrottled(err)).To(BeFalse())
+				g.Expect(errors.Unwrap(err)).NotTo(BeAssignableToTypeOf(NotFoundError{}))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			tc.expect(g, FromAutorest(tc.err))
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	g := NewWithT(t)
+
+	get := func() (err error) {
+		defer Classify(&err)
+		return responseError(http.StatusNotFound, nil)
+	}
+	err := get()
+	g.Expect(err).To(BeAssignableToTypeOf(NotFoundError{}))
+	g.Expect(err).To(MatchError("#: Failure: StatusCode=404"))
+}
+
+func TestFromAutorestWithClassification(t *testing.T) {
+	SetClassification(Classification{
+		Retryable: []string{"SubscriptionNotReady", "400"},
+		Terminal:  []string{"quotaexceeded", "503"},
+	})
+	defer SetClassification(Classification{})
+
+	tests := []struct {
+		name   string
+		err    error
+		expect func(g *WithT, err error)
+	}{
+		{
+			name: "retryable error code",
+			err:  serviceError(http.StatusConflict, "SubscriptionNotReady"),
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(BeAssignableToTypeOf(RetryableError{}))
+				g.Expect(err.(RetryableError).Code).To(Equal("SubscriptionNotReady"))
+				g.Expect(IsRetryable(err)).To(BeTrue())
+				g.Expect(IsClassifiedTerminal(err)).To(BeFalse())
+			},
+		},
+		{
+			name: "retryable status code takes precedence over the built-in classification",
+			err:  responseError(http.StatusBadRequest, nil),
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(BeAssignableToTypeOf(RetryableError{}))
+				g.Expect(IsTerminal(err)).To(BeFalse())
+			},
+		},
+		{
+			name: "terminal error code is matched case-insensitively",
+			err:  serviceError(http.StatusConflict, "QuotaExceeded"),
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(BeAssignableToTypeOf(TerminalError{}))
+				g.Expect(IsClassifiedTerminal(err)).To(BeTrue())
+			},
+		},
+		{
+			name: "terminal status code",
+			err:  responseError(http.StatusServiceUnavailable, nil),
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(BeAssignableToTypeOf(TerminalError{}))
+				g.Expect(IsClassifiedTerminal(pkgerrors.Wrap(err, "failed to create resource"))).To(BeTrue())
+			},
+		},
+		{
+			name: "built-in terminal error isn't classified as terminal",
+			err:  responseError(http.StatusForbidden, nil),
+			expect: func(g *WithT, err error) {
+				g.Expect(IsTerminal(err)).To(BeTrue())
+				g.Expect(IsClassifiedTerminal(err)).To(BeFalse())
+			},
+		},
+		{
+			name: "not found errors are not reclassified",
+			err:  serviceError(http.StatusBadRequest, "ResourceGroupNotFound"),
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(BeAssignableToTypeOf(NotFoundError{}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			tt.expect(g, FromAutorest(tt.err))
+		})
+	}
+}
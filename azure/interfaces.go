@@ -32,6 +32,11 @@ type Reconciler interface {
 	Delete(ctx context.Context) error
 }
 
+// BootstrapVerifier is a Service which knows how to verify that the bootstrap data of a machine ran.
+type BootstrapVerifier interface {
+	VerifyBootstrap(ctx context.Context) error
+}
+
 // CredentialGetter is a Service which knows how to retrieve credentials for an Azure
 // resource in a resource group.
 type CredentialGetter interface {
@@ -39,6 +44,12 @@ type CredentialGetter interface {
 	GetCredentials(ctx context.Context, group string, cluster string) ([]byte, error)
 }
 
+// ImageResolver resolves the image of the VMs of machines which don't specify one.
+type ImageResolver interface {
+	// ResolveImage returns the image of the Kubernetes version for the OS type and container runtime.
+	ResolveImage(ctx context.Context, k8sVersion, osType, runtime string) (*infrav1.Image, error)
+}
+
 // Authorizer is an interface which can get the subscription ID, base URI, and authorizer for an Azure service.
 type Authorizer interface {
 	SubscriptionID() string
@@ -80,6 +91,9 @@ type ClusterDescriber interface {
 	AvailabilitySetEnabled() bool
 	CloudProviderConfigOverrides() *infrav1.CloudProviderConfigOverrides
 	FailureDomains() []string
+	ProxyConfig() *infrav1.ProxyConfig
+	TrustedCAs() *infrav1.TrustedCASource
+	RegistryMirrors() []infrav1.RegistryMirror
 }
 
 // AsyncStatusUpdater is an interface used to keep track of long running operations in Status that has Conditions and Futures.
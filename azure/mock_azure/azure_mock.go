@@ -81,6 +81,43 @@ func (mr *MockReconcilerMockRecorder) Reconcile(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockReconciler)(nil).Reconcile), ctx)
 }
 
+// MockBootstrapVerifier is a mock of BootstrapVerifier interface.
+type MockBootstrapVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockBootstrapVerifierMockRecorder
+}
+
+// MockBootstrapVerifierMockRecorder is the mock recorder for MockBootstrapVerifier.
+type MockBootstrapVerifierMockRecorder struct {
+	mock *MockBootstrapVerifier
+}
+
+// NewMockBootstrapVerifier creates a new mock instance.
+func NewMockBootstrapVerifier(ctrl *gomock.Controller) *MockBootstrapVerifier {
+	mock := &MockBootstrapVerifier{ctrl: ctrl}
+	mock.recorder = &MockBootstrapVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBootstrapVerifier) EXPECT() *MockBootstrapVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifyBootstrap mocks base method.
+func (m *MockBootstrapVerifier) VerifyBootstrap(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyBootstrap", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyBootstrap indicates an expected call of VerifyBootstrap.
+func (mr *MockBootstrapVerifierMockRecorder) VerifyBootstrap(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyBootstrap", reflect.TypeOf((*MockBootstrapVerifier)(nil).VerifyBootstrap), ctx)
+}
+
 // MockCredentialGetter is a mock of CredentialGetter interface.
 type MockCredentialGetter struct {
 	ctrl     *gomock.Controller
@@ -147,6 +184,44 @@ func (mr *MockCredentialGetterMockRecorder) Reconcile(ctx interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockCredentialGetter)(nil).Reconcile), ctx)
 }
 
+// MockImageResolver is a mock of ImageResolver interface.
+type MockImageResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageResolverMockRecorder
+}
+
+// MockImageResolverMockRecorder is the mock recorder for MockImageResolver.
+type MockImageResolverMockRecorder struct {
+	mock *MockImageResolver
+}
+
+// NewMockImageResolver creates a new mock instance.
+func NewMockImageResolver(ctrl *gomock.Controller) *MockImageResolver {
+	mock := &MockImageResolver{ctrl: ctrl}
+	mock.recorder = &MockImageResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageResolver) EXPECT() *MockImageResolverMockRecorder {
+	return m.recorder
+}
+
+// ResolveImage mocks base method.
+func (m *MockImageResolver) ResolveImage(ctx context.Context, k8sVersion, osType, runtime string) (*v1beta1.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveImage", ctx, k8sVersion, osType, runtime)
+	ret0, _ := ret[0].(*v1beta1.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveImage indicates an expected call of ResolveImage.
+func (mr *MockImageResolverMockRecorder) ResolveImage(ctx, k8sVersion, osType, runtime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveImage", reflect.TypeOf((*MockImageResolver)(nil).ResolveImage), ctx, k8sVersion, osType, runtime)
+}
+
 // MockAuthorizer is a mock of Authorizer interface.
 type MockAuthorizer struct {
 	ctrl     *gomock.Controller
@@ -704,6 +779,34 @@ func (mr *MockClusterDescriberMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockClusterDescriber)(nil).Location))
 }
 
+// ProxyConfig mocks base method.
+func (m *MockClusterDescriber) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockClusterDescriberMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockClusterDescriber)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockClusterDescriber) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockClusterDescriberMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockClusterDescriber)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockClusterDescriber) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -746,6 +849,20 @@ func (mr *MockClusterDescriberMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockClusterDescriber)(nil).TenantID))
 }
 
+// TrustedCAs mocks base method.
+func (m *MockClusterDescriber) TrustedCAs() *v1beta1.TrustedCASource {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrustedCAs")
+	ret0, _ := ret[0].(*v1beta1.TrustedCASource)
+	return ret0
+}
+
+// TrustedCAs indicates an expected call of TrustedCAs.
+func (mr *MockClusterDescriberMockRecorder) TrustedCAs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustedCAs", reflect.TypeOf((*MockClusterDescriber)(nil).TrustedCAs))
+}
+
 // MockAsyncStatusUpdater is a mock of AsyncStatusUpdater interface.
 type MockAsyncStatusUpdater struct {
 	ctrl     *gomock.Controller
@@ -1188,6 +1305,34 @@ func (mr *MockClusterScoperMockRecorder) OutboundPoolName(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundPoolName", reflect.TypeOf((*MockClusterScoper)(nil).OutboundPoolName), arg0)
 }
 
+// ProxyConfig mocks base method.
+func (m *MockClusterScoper) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockClusterScoperMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockClusterScoper)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockClusterScoper) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockClusterScoperMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockClusterScoper)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockClusterScoper) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -1270,6 +1415,20 @@ func (mr *MockClusterScoperMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockClusterScoper)(nil).TenantID))
 }
 
+// TrustedCAs mocks base method.
+func (m *MockClusterScoper) TrustedCAs() *v1beta1.TrustedCASource {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrustedCAs")
+	ret0, _ := ret[0].(*v1beta1.TrustedCASource)
+	return ret0
+}
+
+// TrustedCAs indicates an expected call of TrustedCAs.
+func (mr *MockClusterScoperMockRecorder) TrustedCAs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustedCAs", reflect.TypeOf((*MockClusterScoper)(nil).TrustedCAs))
+}
+
 // Vnet mocks base method.
 func (m *MockClusterScoper) Vnet() *v1beta1.VnetSpec {
 	m.ctrl.T.Helper()
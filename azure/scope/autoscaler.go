@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Annotations of a MachinePool read by cluster-autoscaler to build the template of its nodes when it is scaled to zero.
+const (
+	// AutoscalerCPUAnnotation is the number of CPUs of a node.
+	AutoscalerCPUAnnotation = "capacity.cluster-autoscaler.kubernetes.io/cpu"
+	// AutoscalerMemoryAnnotation is the memory of a node.
+	AutoscalerMemoryAnnotation = "capacity.cluster-autoscaler.kubernetes.io/memory"
+	// AutoscalerEphemeralDiskAnnotation is the ephemeral storage of a node.
+	AutoscalerEphemeralDiskAnnotation = "capacity.cluster-autoscaler.kubernetes.io/ephemeral-disk"
+	// AutoscalerGPUCountAnnotation is the number of GPUs of a node.
+	AutoscalerGPUCountAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-count"
+	// AutoscalerGPUTypeAnnotation is the resource name of the GPUs of a node.
+	AutoscalerGPUTypeAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-type"
+	// AutoscalerLabelsAnnotation is the comma separated list of the labels of a node, as key=value.
+	AutoscalerLabelsAnnotation = "capacity.cluster-autoscaler.kubernetes.io/labels"
+	// AutoscalerTaintsAnnotation is the comma separated list of the taints of a node, as key=value:effect.
+	AutoscalerTaintsAnnotation = "capacity.cluster-autoscaler.kubernetes.io/taints"
+
+	// nvidiaGPUResourceName is the resource name of the GPUs exposed by the NVIDIA device plugin.
+	nvidiaGPUResourceName = "nvidia.com/gpu"
+)
+
+// autoscalerAnnotations are the cluster-autoscaler annotations managed by CAPZ.
+var autoscalerAnnotations = []string{
+	AutoscalerCPUAnnotation,
+	AutoscalerMemoryAnnotation,
+	AutoscalerEphemeralDiskAnnotation,
+	AutoscalerGPUCountAnnotation,
+	AutoscalerGPUTypeAnnotation,
+	AutoscalerLabelsAnnotation,
+	AutoscalerTaintsAnnotation,
+}
+
+// UpdateAutoscalerCapacity annotates the MachinePool with the capacity, labels and taints of its nodes, so that
+// cluster-autoscaler can scale it from zero. The capacity comes from the SKU of the VM size, and the labels and taints
+// from the join configuration of the KubeadmConfig of the MachinePool, if any.
+func (m *MachinePoolScope) UpdateAutoscalerCapacity(ctx context.Context, sku resourceskus.SKU) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.UpdateAutoscalerCapacity")
+	defer done()
+
+	config, err := m.getKubeadmConfig(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the bootstrap config of the machine pool")
+	}
+	annotations := autoscalerCapacityAnnotations(sku, m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.Location(), config)
+
+	changed := false
+	for _, key := range autoscalerAnnotations {
+		value, ok := annotations[key]
+		current, exists := m.MachinePool.Annotations[key]
+		if ok != exists || value != current {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	helper, err := patch.NewHelper(m.MachinePool, m.client)
+	if err != nil {
+		return errors.Wrap(err, "failed to init patch helper")
+	}
+	if m.MachinePool.Annotations == nil {
+		m.MachinePool.Annotations = map[string]string{}
+	}
+	for _, key := range autoscalerAnnotations {
+		if value, ok := annotations[key]; ok {
+			m.MachinePool.Annotations[key] = value
+		} else {
+			delete(m.MachinePool.Annotations, key)
+		}
+	}
+	return helper.Patch(ctx, m.MachinePool)
+}
+
+// getKubeadmConfig returns the KubeadmConfig the nodes of the MachinePool are bootstrapped with, or nil if it is
+// bootstrapped by another provider. It is read as an unstructured object not to depend on the kubeadm bootstrap API.
+func (m *MachinePoolScope) getKubeadmConfig(ctx context.Context) (*unstructured.Unstructured, error) {
+	ref := m.MachinePool.Spec.Template.Spec.Bootstrap.ConfigRef
+	if ref == nil || ref.Kind != "KubeadmConfig" {
+		return nil, nil
+	}
+
+	config := &unstructured.Unstructured{}
+	config.SetAPIVersion(ref.APIVersion)
+	config.SetKind(ref.Kind)
+	key := client.ObjectKey{Namespace: m.MachinePool.Namespace, Name: ref.Name}
+	if err := m.client.Get(ctx, key, config); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return config, nil
+}
+
+// autoscalerCapacityAnnotations returns the cluster-autoscaler capacity annotations of the nodes of a VM size.
+func autoscalerCapacityAnnotations(sku resourceskus.SKU, osType, location string, kubeadmConfig *unstructured.Unstructured) map[string]string {
+	annotations := map[string]string{}
+	if cpus, ok := sku.GetCapability(resourceskus.VCPUs); ok {
+		annotations[AutoscalerCPUAnnotation] = cpus
+	}
+	if memory, ok := sku.GetCapability(resourceskus.MemoryGB); ok {
+		if gb, err := strconv.ParseFloat(memory, 64); err == nil {
+			annotations[AutoscalerMemoryAnnotation] = fmt.Sprintf("%dMi", int64(gb*1024))
+		}
+	}
+	if disk, ok := sku.GetCapability(resourceskus.MaxResourceVolumeMB); ok && disk != "0" {
+		annotations[AutoscalerEphemeralDiskAnnotation] = disk + "Mi"
+	}
+	if gpus, ok := sku.GetCapability(resourceskus.GPUs); ok && gpus != "0" {
+		annotations[AutoscalerGPUCountAnnotation] = gpus
+		annotations[AutoscalerGPUTypeAnnotation] = nvidiaGPUResourceName
+	}
+
+	os := "linux"
+	if osType == azure.WindowsOS {
+		os = "windows"
+	}
+	arch := "amd64"
+	if architecture, ok := sku.GetCapability(resourceskus.CPUArchitectureType); ok && strings.EqualFold(architecture, "Arm64") {
+		arch = "arm64"
+	}
+	labels := map[string]string{
+		corev1.LabelOSStable:           os,
+		corev1.LabelArchStable:         arch,
+		corev1.LabelInstanceTypeStable: to.String(sku.Name),
+		corev1.LabelTopologyRegion:     location,
+	}
+
+	var taints []string
+	if kubeadmConfig != nil {
+		nodeRegistration := []string{"spec", "joinConfiguration", "nodeRegistration"}
+		nodeLabels, _, _ := unstructured.NestedString(kubeadmConfig.Object, append(nodeRegistration, "kubeletExtraArgs", "node-labels")...)
+		for _, label := range strings.Split(nodeLabels, ",") {
+			if kv := strings.SplitN(strings.TrimSpace(label), "=", 2); len(kv) == 2 {
+				labels[kv[0]] = kv[1]
+			}
+		}
+		configTaints, _, _ := unstructured.NestedSlice(kubeadmConfig.Object, append(nodeRegistration, "taints")...)
+		for _, t := range configTaints {
+			taint, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key, _, _ := unstructured.NestedString(taint, "key")
+			value, _, _ := unstructured.NestedString(taint, "value")
+			effect, _, _ := unstructured.NestedString(taint, "effect")
+			taints = append(taints, fmt.Sprintf("%s=%s:%s", key, value, effect))
+		}
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	annotations[AutoscalerLabelsAnnotation] = strings.Join(pairs, ",")
+	if len(taints) > 0 {
+		annotations[AutoscalerTaintsAnnotation] = strings.Join(taints, ",")
+	}
+
+	return annotations
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+)
+
+func newTestSKU(name string, capabilities map[string]string) resourceskus.SKU {
+	skuCapabilities := []compute.ResourceSkuCapabilities{}
+	for key, value := range capabilities {
+		skuCapabilities = append(skuCapabilities, compute.ResourceSkuCapabilities{Name: to.StringPtr(key), Value: to.StringPtr(value)})
+	}
+	return resourceskus.SKU{Name: to.StringPtr(name), Capabilities: &skuCapabilities}
+}
+
+func TestAutoscalerCapacityAnnotations(t *testing.T) {
+	tests := []struct {
+		name          string
+		sku           resourceskus.SKU
+		osType        string
+		kubeadmConfig *unstructured.Unstructured
+		want          map[string]string
+	}{
+		{
+			name: "linux VM size",
+			sku: newTestSKU("Standard_D2s_v3", map[string]string{
+				resourceskus.VCPUs:               "2",
+				resourceskus.MemoryGB:            "8",
+				resourceskus.MaxResourceVolumeMB: "16384",
+				resourceskus.GPUs:                "0",
+			}),
+			osType: azure.LinuxOS,
+			want: map[string]string{
+				AutoscalerCPUAnnotation:           "2",
+				AutoscalerMemoryAnnotation:        "8192Mi",
+				AutoscalerEphemeralDiskAnnotation: "16384Mi",
+				AutoscalerLabelsAnnotation:        "kubernetes.io/arch=amd64,kubernetes.io/os=linux,node.kubernetes.io/instance-type=Standard_D2s_v3,topology.kubernetes.io/region=westus2",
+			},
+		},
+		{
+			name: "GPU VM size",
+			sku: newTestSKU("Standard_NC6s_v3", map[string]string{
+				resourceskus.VCPUs:    "6",
+				resourceskus.MemoryGB: "112",
+				resourceskus.GPUs:     "1",
+			}),
+			osType: azure.LinuxOS,
+			want: map[string]string{
+				AutoscalerCPUAnnotation:      "6",
+				AutoscalerMemoryAnnotation:   "114688Mi",
+				AutoscalerGPUCountAnnotation: "1",
+				AutoscalerGPUTypeAnnotation:  "nvidia.com/gpu",
+				AutoscalerLabelsAnnotation:   "kubernetes.io/arch=amd64,kubernetes.io/os=linux,node.kubernetes.io/instance-type=Standard_NC6s_v3,topology.kubernetes.io/region=westus2",
+			},
+		},
+		{
+			name: "Arm64 VM size with fractional memory",
+			sku: newTestSKU("Standard_D2ps_v5", map[string]string{
+				resourceskus.VCPUs:               "2",
+				resourceskus.MemoryGB:            "0.75",
+				resourceskus.CPUArchitectureType: "Arm64",
+			}),
+			osType: azure.LinuxOS,
+			want: map[string]string{
+				AutoscalerCPUAnnotation:    "2",
+				AutoscalerMemoryAnnotation: "768Mi",
+				AutoscalerLabelsAnnotation: "kubernetes.io/arch=arm64,kubernetes.io/os=linux,node.kubernetes.io/instance-type=Standard_D2ps_v5,topology.kubernetes.io/region=westus2",
+			},
+		},
+		{
+			name:   "windows VM size",
+			sku:    newTestSKU("Standard_D4s_v3", map[string]string{resourceskus.VCPUs: "4"}),
+			osType: azure.WindowsOS,
+			want: map[string]string{
+				AutoscalerCPUAnnotation:    "4",
+				AutoscalerLabelsAnnotation: "kubernetes.io/arch=amd64,kubernetes.io/os=windows,node.kubernetes.io/instance-type=Standard_D4s_v3,topology.kubernetes.io/region=westus2",
+			},
+		},
+		{
+			name:   "labels and taints of the kubeadm join configuration",
+			sku:    newTestSKU("Standard_D2s_v3", map[string]string{resourceskus.VCPUs: "2"}),
+			osType: azure.LinuxOS,
+			kubeadmConfig: &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"joinConfiguration": map[string]interface{}{
+						"nodeRegistration": map[string]interface{}{
+							"kubeletExtraArgs": map[string]interface{}{
+								"node-labels": "pool=workers, tier=batch",
+							},
+							"taints": []interface{}{
+								map[string]interface{}{"key": "dedicated", "value": "batch", "effect": "NoSchedule"},
+								map[string]interface{}{"key": "spot", "effect": "PreferNoSchedule"},
+							},
+						},
+					},
+				},
+			}},
+			want: map[string]string{
+				AutoscalerCPUAnnotation:    "2",
+				AutoscalerLabelsAnnotation: "kubernetes.io/arch=amd64,kubernetes.io/os=linux,node.kubernetes.io/instance-type=Standard_D2s_v3,pool=workers,tier=batch,topology.kubernetes.io/region=westus2",
+				AutoscalerTaintsAnnotation: "dedicated=batch:NoSchedule,spot=:PreferNoSchedule",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(autoscalerCapacityAnnotations(tt.sku, tt.osType, "westus2", tt.kubeadmConfig)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachinePoolScope_UpdateAutoscalerCapacity(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1exp.AddToScheme(scheme)).To(Succeed())
+
+	mp := &clusterv1exp.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mp1",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AutoscalerGPUCountAnnotation: "1",
+				"foo":                        "bar",
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mp).Build()
+	s := &MachinePoolScope{
+		client:           fakeClient,
+		MachinePool:      mp,
+		AzureMachinePool: &infrav1exp.AzureMachinePool{},
+		ClusterScoper: &ClusterScope{
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{Location: "westus2"},
+			},
+		},
+	}
+
+	sku := newTestSKU("Standard_D2s_v3", map[string]string{resourceskus.VCPUs: "2", resourceskus.MemoryGB: "8"})
+	g.Expect(s.UpdateAutoscalerCapacity(context.TODO(), sku)).To(Succeed())
+
+	got := &clusterv1exp.MachinePool{}
+	g.Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(mp), got)).To(Succeed())
+	g.Expect(got.Annotations).To(Equal(map[string]string{
+		AutoscalerCPUAnnotation:    "2",
+		AutoscalerMemoryAnnotation: "8192Mi",
+		AutoscalerLabelsAnnotation: "kubernetes.io/arch=amd64,kubernetes.io/os=linux,node.kubernetes.io/instance-type=Standard_D2s_v3,topology.kubernetes.io/region=westus2",
+		"foo":                      "bar",
+	}))
+}
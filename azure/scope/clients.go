@@ -23,10 +23,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
 // AzureClients contains all the Azure clients used by the scopes.
@@ -36,6 +40,8 @@ type AzureClients struct {
 	Authorizer                 autorest.Authorizer
 	ResourceManagerEndpoint    string
 	ResourceManagerVMDNSSuffix string
+
+	credentialsProvider CredentialsProvider
 }
 
 // CloudEnvironment returns the Azure environment the controller runs in.
@@ -64,16 +70,72 @@ func (c *AzureClients) SubscriptionID() string {
 	return c.Values[auth.SubscriptionID]
 }
 
-// HashKey returns a base64 url encoded sha256 hash for the Auth scope (Azure TenantID + CloudEnv + SubscriptionID +
-// ClientID).
+// HashKey returns a base64 url encoded sha256 hash for the Auth scope (Azure TenantID + CloudEnv + ResourceManagerEndpoint +
+// SubscriptionID + ClientID).
 func (c *AzureClients) HashKey() string {
 	hasher := sha256.New()
-	_, _ = hasher.Write([]byte(c.TenantID() + c.CloudEnvironment() + c.SubscriptionID() + c.ClientID()))
+	_, _ = hasher.Write([]byte(c.TenantID() + c.CloudEnvironment() + c.ResourceManagerEndpoint + c.SubscriptionID() + c.ClientID()))
 	return base64.URLEncoding.EncodeToString(hasher.Sum(nil))
 }
 
-func (c *AzureClients) setCredentials(subscriptionID, environmentName string) error {
-	settings, err := c.getSettingsFromEnvironment(environmentName)
+// tokenAudience returns the audience of the tokens used to call Azure Resource Manager.
+func (c *AzureClients) tokenAudience() string {
+	if c.Environment.TokenAudience != "" {
+		return c.Environment.TokenAudience
+	}
+	return c.ResourceManagerEndpoint
+}
+
+// GetAccessToken returns an access token for the given resource, such as the AAD server application of AKS, and when
+// it expires. The token is issued to the identity of the cluster when it references one, and to the credentials of the
+// controller environment otherwise.
+func (c *AzureClients) GetAccessToken(ctx context.Context, resource string) (string, time.Time, error) {
+	var spt *adal.ServicePrincipalToken
+	if c.credentialsProvider != nil {
+		authorizer, err := c.credentialsProvider.GetAuthorizer(ctx, resource, c.Environment.ActiveDirectoryEndpoint)
+		if err != nil {
+			return "", time.Time{}, errors.Wrap(err, "failed to get token from cluster identity")
+		}
+		bearerAuthorizer, ok := authorizer.(*autorest.BearerAuthorizer)
+		if !ok {
+			return "", time.Time{}, errors.Errorf("unexpected authorizer %T for cluster identity", authorizer)
+		}
+		spt, ok = bearerAuthorizer.TokenProvider().(*adal.ServicePrincipalToken)
+		if !ok {
+			return "", time.Time{}, errors.Errorf("unexpected token provider %T for cluster identity", bearerAuthorizer.TokenProvider())
+		}
+	} else {
+		settings := auth.EnvironmentSettings{
+			Environment: c.Environment,
+			Values:      map[string]string{},
+		}
+		for k, v := range c.Values {
+			settings.Values[k] = v
+		}
+		settings.Values[auth.Resource] = resource
+
+		if credentials, err := settings.GetClientCredentials(); err == nil {
+			spt, err = credentials.ServicePrincipalToken()
+			if err != nil {
+				return "", time.Time{}, errors.Wrap(err, "failed to get token from client credentials")
+			}
+		} else {
+			spt, err = settings.GetMSI().ServicePrincipalToken()
+			if err != nil {
+				return "", time.Time{}, errors.Wrap(err, "failed to get token from managed identity")
+			}
+		}
+	}
+
+	if err := spt.EnsureFreshWithContext(ctx); err != nil {
+		return "", time.Time{}, errors.Wrapf(err, "failed to refresh token for %s", resource)
+	}
+	token := spt.Token()
+	return token.AccessToken, token.Expires(), nil
+}
+
+func (c *AzureClients) setCredentials(subscriptionID, environmentName string, endpoints *infrav1.AzureEnvironmentEndpoints) error {
+	settings, err := c.getSettingsFromEnvironment(environmentName, endpoints)
 	if err != nil {
 		return err
 	}
@@ -99,12 +161,12 @@ func (c *AzureClients) setCredentials(subscriptionID, environmentName string) er
 	return err
 }
 
-func (c *AzureClients) setCredentialsWithProvider(ctx context.Context, subscriptionID, environmentName string, credentialsProvider CredentialsProvider) error {
+func (c *AzureClients) setCredentialsWithProvider(ctx context.Context, subscriptionID, environmentName string, endpoints *infrav1.AzureEnvironmentEndpoints, credentialsProvider CredentialsProvider) error {
 	if credentialsProvider == nil {
 		return fmt.Errorf("credentials provider cannot have an empty value")
 	}
 
-	settings, err := c.getSettingsFromEnvironment(environmentName)
+	settings, err := c.getSettingsFromEnvironment(environmentName, endpoints)
 	if err != nil {
 		return err
 	}
@@ -129,11 +191,14 @@ func (c *AzureClients) setCredentialsWithProvider(ctx context.Context, subscript
 	}
 	c.Values[auth.ClientSecret] = strings.TrimSuffix(clientSecret, "\n")
 
-	c.Authorizer, err = credentialsProvider.GetAuthorizer(ctx, c.ResourceManagerEndpoint, c.Environment.ActiveDirectoryEndpoint)
+	c.credentialsProvider = credentialsProvider
+	c.Authorizer, err = credentialsProvider.GetAuthorizer(ctx, c.tokenAudience(), c.Environment.ActiveDirectoryEndpoint)
 	return err
 }
 
-func (c *AzureClients) getSettingsFromEnvironment(environmentName string) (s auth.EnvironmentSettings, err error) {
+// getSettingsFromEnvironment returns the settings of the named Azure environment, or of the custom environment with the
+// given endpoints, and the credentials of the controller environment.
+func (c *AzureClients) getSettingsFromEnvironment(environmentName string, endpoints *infrav1.AzureEnvironmentEndpoints) (s auth.EnvironmentSettings, err error) {
 	s = auth.EnvironmentSettings{
 		Values: map[string]string{},
 	}
@@ -148,9 +213,22 @@ func (c *AzureClients) getSettingsFromEnvironment(environmentName string) (s aut
 	setValue(s, auth.Username)
 	setValue(s, auth.Password)
 	setValue(s, auth.Resource)
-	if v := s.Values[auth.EnvironmentName]; v == "" {
+	switch v := s.Values[auth.EnvironmentName]; {
+	case endpoints != nil:
+		s.Environment = azure.Environment{
+			Name:                       v,
+			ResourceManagerEndpoint:    endpoints.ResourceManagerEndpoint,
+			ActiveDirectoryEndpoint:    endpoints.ActiveDirectoryEndpoint,
+			ResourceManagerVMDNSSuffix: endpoints.ResourceManagerVMDNSSuffix,
+			TokenAudience:              endpoints.TokenAudience,
+		}
+		// The audience of the cluster takes precedence over the one of the controller environment.
+		if endpoints.TokenAudience != "" {
+			s.Values[auth.Resource] = endpoints.TokenAudience
+		}
+	case v == "":
 		s.Environment = azure.PublicCloud
-	} else {
+	default:
 		s.Environment, err = azure.EnvironmentFromName(v)
 	}
 	if s.Values[auth.Resource] == "" {
@@ -20,8 +20,10 @@ import (
 	"testing"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
 
 	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
 func TestGettingEnvironment(t *testing.T) {
@@ -71,7 +73,7 @@ func TestGettingEnvironment(t *testing.T) {
 			c := AzureClients{
 				Authorizer: autorest.NullAuthorizer{},
 			}
-			err := c.setCredentials("1234", test.azureEnv)
+			err := c.setCredentials("1234", test.azureEnv, nil)
 			if test.expectedError {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(err.Error()).To(ContainSubstring(test.expectedErrorMessage))
@@ -83,3 +85,44 @@ func TestGettingEnvironment(t *testing.T) {
 		})
 	}
 }
+
+func TestGettingCustomEnvironment(t *testing.T) {
+	tests := map[string]struct {
+		endpoints        *infrav1.AzureEnvironmentEndpoints
+		expectedAudience string
+	}{
+		"audience defaults to the resource manager endpoint": {
+			endpoints: &infrav1.AzureEnvironmentEndpoints{
+				ResourceManagerEndpoint:    "https://management.local.azurestack.external/",
+				ActiveDirectoryEndpoint:    "https://login.microsoftonline.com/",
+				ResourceManagerVMDNSSuffix: "cloudapp.local.azurestack.external",
+			},
+			expectedAudience: "https://management.local.azurestack.external/",
+		},
+		"audience is overridden": {
+			endpoints: &infrav1.AzureEnvironmentEndpoints{
+				ResourceManagerEndpoint:    "https://management.local.azurestack.external/",
+				ActiveDirectoryEndpoint:    "https://login.microsoftonline.com/",
+				ResourceManagerVMDNSSuffix: "cloudapp.local.azurestack.external",
+				TokenAudience:              "https://management.azurestack.example.com/1234",
+			},
+			expectedAudience: "https://management.azurestack.example.com/1234",
+		},
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := AzureClients{
+				Authorizer: autorest.NullAuthorizer{},
+			}
+			g.Expect(c.setCredentials("1234", "AzureStackCloud", test.endpoints)).To(Succeed())
+			g.Expect(c.CloudEnvironment()).To(Equal("AzureStackCloud"))
+			g.Expect(c.ResourceManagerEndpoint).To(Equal(test.endpoints.ResourceManagerEndpoint))
+			g.Expect(c.ResourceManagerVMDNSSuffix).To(Equal(test.endpoints.ResourceManagerVMDNSSuffix))
+			g.Expect(c.Environment.ActiveDirectoryEndpoint).To(Equal(test.endpoints.ActiveDirectoryEndpoint))
+			g.Expect(c.tokenAudience()).To(Equal(test.expectedAudience))
+			g.Expect(c.Values[auth.Resource]).To(Equal(test.expectedAudience))
+		})
+	}
+}
@@ -23,30 +23,51 @@ import (
 	"hash/fnv"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/net"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/diskencryptionsets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/flowlogs"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatelinkservices"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/runtimehooks"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// exportedTemplateKey is the key of the exported ARM template in its ConfigMap.
+const exportedTemplateKey = "template.json"
+
 // ClusterScopeParams defines the input parameters used to create a new Scope.
 type ClusterScopeParams struct {
 	AzureClients
 	Client       client.Client
 	Cluster      *clusterv1.Cluster
 	AzureCluster *infrav1.AzureCluster
+	// ProvisioningSLO is how long the first node of the cluster may take to become ready, no SLO is tracked if zero.
+	ProvisioningSLO time.Duration
+	// RuntimeHooks calls the runtime extension at the provisioning milestones of the cluster, if any is configured.
+	RuntimeHooks runtimehooks.Caller
+	// CordonImpactedZones cordons the availability zones impacted by an Azure service health event.
+	CordonImpactedZones bool
 }
 
 // NewClusterScope creates a new Scope from the supplied parameters.
@@ -63,7 +84,7 @@ func NewClusterScope(ctx context.Context, params ClusterScopeParams) (*ClusterSc
 	}
 
 	if params.AzureCluster.Spec.IdentityRef == nil {
-		err := params.AzureClients.setCredentials(params.AzureCluster.Spec.SubscriptionID, params.AzureCluster.Spec.AzureEnvironment)
+		err := params.AzureClients.setCredentials(params.AzureCluster.Spec.SubscriptionID, params.AzureCluster.Spec.AzureEnvironment, params.AzureCluster.Spec.AzureEnvironmentEndpoints)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to configure azure settings and credentials from environment")
 		}
@@ -72,23 +93,29 @@ func NewClusterScope(ctx context.Context, params ClusterScopeParams) (*ClusterSc
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to init credentials provider")
 		}
-		err = params.AzureClients.setCredentialsWithProvider(ctx, params.AzureCluster.Spec.SubscriptionID, params.AzureCluster.Spec.AzureEnvironment, credentialsProvider)
+		err = params.AzureClients.setCredentialsWithProvider(ctx, params.AzureCluster.Spec.SubscriptionID, params.AzureCluster.Spec.AzureEnvironment,
+			params.AzureCluster.Spec.AzureEnvironmentEndpoints, credentialsProvider)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to configure azure settings and credentials for Identity")
 		}
 	}
 
+	params.AzureClients.Authorizer = azure.NewAPIVersionAuthorizer(params.AzureClients.Authorizer, azure.APIVersions(params.AzureCluster.Spec.APIVersionProfile))
+
 	helper, err := patch.NewHelper(params.AzureCluster, params.Client)
 	if err != nil {
 		return nil, errors.Errorf("failed to init patch helper: %v", err)
 	}
 
 	return &ClusterScope{
-		Client:       params.Client,
-		AzureClients: params.AzureClients,
-		Cluster:      params.Cluster,
-		AzureCluster: params.AzureCluster,
-		patchHelper:  helper,
+		Client:          params.Client,
+		AzureClients:    params.AzureClients,
+		Cluster:         params.Cluster,
+		AzureCluster:    params.AzureCluster,
+		patchHelper:     helper,
+		provisioningSLO: params.ProvisioningSLO,
+		runtimeHooks:    params.RuntimeHooks,
+		cordonZones:     params.CordonImpactedZones,
 	}, nil
 }
 
@@ -100,6 +127,10 @@ type ClusterScope struct {
 	AzureClients
 	Cluster      *clusterv1.Cluster
 	AzureCluster *infrav1.AzureCluster
+
+	provisioningSLO time.Duration
+	runtimeHooks    runtimehooks.Caller
+	cordonZones     bool
 }
 
 // BaseURI returns the Azure ResourceManagerEndpoint.
@@ -125,9 +156,10 @@ func (s *ClusterScope) PublicIPSpecs() []azure.PublicIPSpec {
 		}
 	} else {
 		controlPlaneOutboundIPSpecs = []azure.PublicIPSpec{{
-			Name:    s.APIServerPublicIP().Name,
-			DNSName: s.APIServerPublicIP().DNSName,
-			IsIPv6:  false, // currently azure requires a ipv4 lb rule to enable ipv6
+			Name:           s.APIServerPublicIP().Name,
+			DNSName:        s.APIServerPublicIP().DNSName,
+			IsIPv6:         false, // currently azure requires a ipv4 lb rule to enable ipv6
+			RetainOnDelete: s.RetainPublicIPsOnDelete(),
 		}}
 	}
 	publicIPSpecs = append(publicIPSpecs, controlPlaneOutboundIPSpecs...)
@@ -143,8 +175,9 @@ func (s *ClusterScope) PublicIPSpecs() []azure.PublicIPSpec {
 	for _, subnet := range s.NodeSubnets() {
 		if subnet.IsNatGatewayEnabled() {
 			nodeNatGatewayIPSpecs = append(nodeNatGatewayIPSpecs, azure.PublicIPSpec{
-				Name:    subnet.NatGateway.NatGatewayIP.Name,
-				DNSName: subnet.NatGateway.NatGatewayIP.DNSName,
+				Name:           subnet.NatGateway.NatGatewayIP.Name,
+				DNSName:        subnet.NatGateway.NatGatewayIP.DNSName,
+				RetainOnDelete: s.RetainPublicIPsOnDelete(),
 			})
 		}
 		publicIPSpecs = append(publicIPSpecs, nodeNatGatewayIPSpecs...)
@@ -159,9 +192,39 @@ func (s *ClusterScope) PublicIPSpecs() []azure.PublicIPSpec {
 		publicIPSpecs = append(publicIPSpecs, azureBastionPublicIP)
 	}
 
+	if gateway := s.ExpressRouteGatewaySpec(); gateway != nil {
+		// public IP for the ExpressRoute gateway.
+		publicIPSpecs = append(publicIPSpecs, azure.PublicIPSpec{
+			Name: gateway.PublicIPName,
+		})
+	}
+
+	if firewall := s.AzureFirewallSpec(); firewall != nil && firewall.Managed {
+		// public IP for Azure Firewall.
+		publicIPSpecs = append(publicIPSpecs, azure.PublicIPSpec{
+			Name: firewall.PublicIPName,
+		})
+	}
+
 	return publicIPSpecs
 }
 
+// RetainPublicIPsOnDelete returns true if the public IPs of the load balancers and NAT gateways are kept when the
+// cluster is deleted.
+func (s *ClusterScope) RetainPublicIPsOnDelete() bool {
+	return s.AzureCluster.Spec.NetworkSpec.RetainPublicIPsOnDelete
+}
+
+// SetPublicIPRetained records in the status that a public IP was left in place on purpose.
+func (s *ClusterScope) SetPublicIPRetained(name string) {
+	for _, retained := range s.AzureCluster.Status.RetainedPublicIPs {
+		if retained == name {
+			return
+		}
+	}
+	s.AzureCluster.Status.RetainedPublicIPs = append(s.AzureCluster.Status.RetainedPublicIPs, name)
+}
+
 // LBSpecs returns the load balancer specs.
 func (s *ClusterScope) LBSpecs() []azure.LBSpec {
 	specs := []azure.LBSpec{
@@ -176,6 +239,7 @@ func (s *ClusterScope) LBSpecs() []azure.LBSpec {
 			Role:                 infrav1.APIServerRole,
 			BackendPoolName:      s.APIServerLBPoolName(s.APIServerLB().Name),
 			IdleTimeoutInMinutes: s.APIServerLB().IdleTimeoutInMinutes,
+			ForwardedPorts:       s.forwardedConnectivityPorts(),
 		},
 	}
 
@@ -208,12 +272,44 @@ func (s *ClusterScope) LBSpecs() []azure.LBSpec {
 	return specs
 }
 
+// forwardedConnectivityPorts returns the ingress ports of the connectivity profile which the API server load balancer
+// forwards to the control plane nodes. A port already used by the API server is already forwarded.
+func (s *ClusterScope) forwardedConnectivityPorts() []azure.LBPort {
+	profile := s.AzureCluster.Spec.ConnectivityProfile
+	if profile == nil {
+		return nil
+	}
+
+	var ports []azure.LBPort
+	for _, port := range profile.Ingress {
+		protocol := connectivityProtocol(port)
+		if port.Port == s.APIServerPort() && protocol == infrav1.SecurityGroupProtocolTCP {
+			continue
+		}
+		ports = append(ports, azure.LBPort{
+			Name:     port.Name,
+			Port:     port.Port,
+			Protocol: string(protocol),
+		})
+	}
+	return ports
+}
+
 // RouteTableSpecs returns the node route table.
 func (s *ClusterScope) RouteTableSpecs() []azure.RouteTableSpec {
+	var disableBGPRoutePropagation bool
+	if gateway := s.AzureCluster.Spec.NetworkSpec.ExpressRouteGateway; gateway != nil {
+		disableBGPRoutePropagation = gateway.DisableBGPRoutePropagation
+	}
+
 	var routetables []azure.RouteTableSpec
 	for _, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
 		if subnet.RouteTable.Name != "" {
-			routetables = append(routetables, azure.RouteTableSpec{Name: subnet.RouteTable.Name, Subnet: subnet})
+			routetables = append(routetables, azure.RouteTableSpec{
+				Name:                       subnet.RouteTable.Name,
+				Subnet:                     subnet,
+				DisableBGPRoutePropagation: disableBGPRoutePropagation,
+			})
 		}
 	}
 
@@ -240,25 +336,327 @@ func (s *ClusterScope) NatGatewaySpecs() []azure.NatGatewaySpec {
 	return natGateways
 }
 
+// SNATMetricsSpecs returns the outbound load balancers and NAT gateways whose SNAT metrics are collected, which is
+// only the case when the SNATMetrics feature gate is enabled.
+func (s *ClusterScope) SNATMetricsSpecs() []azure.SNATMetricsSpec {
+	if !feature.Gates.Enabled(feature.SNATMetrics) {
+		return nil
+	}
+
+	var specs []azure.SNATMetricsSpec
+	// The public API server load balancer holds the outbound rule of the control plane.
+	outboundLBs := []*infrav1.LoadBalancerSpec{s.ControlPlaneOutboundLB(), s.NodeOutboundLB()}
+	if !s.IsAPIServerPrivate() {
+		outboundLBs = append([]*infrav1.LoadBalancerSpec{s.APIServerLB()}, outboundLBs...)
+	}
+	for _, lb := range outboundLBs {
+		if lb == nil || lb.Name == "" {
+			continue
+		}
+		specs = append(specs, azure.SNATMetricsSpec{
+			Name:       lb.Name,
+			ResourceID: azure.LoadBalancerID(s.SubscriptionID(), s.ResourceGroup(), lb.Name),
+		})
+	}
+	for _, natGateway := range s.NatGatewaySpecs() {
+		specs = append(specs, azure.SNATMetricsSpec{
+			Name:       natGateway.Name,
+			ResourceID: azure.NatGatewayID(s.SubscriptionID(), s.ResourceGroup(), natGateway.Name),
+			NATGateway: true,
+		})
+	}
+
+	return specs
+}
+
+// AlertRuleSpecs returns the specs of the baseline metric alert rules of the cluster.
+func (s *ClusterScope) AlertRuleSpecs() []azure.AlertRuleSpec {
+	alerts := s.AzureCluster.Spec.Alerts
+	if alerts == nil {
+		return nil
+	}
+
+	var specs []azure.AlertRuleSpec
+	for _, rule := range alerts.Rules {
+		switch rule {
+		case infrav1.AlertRuleVMAvailability:
+			// A single rule covers all the virtual machines of the resource group of the cluster, even the ones
+			// created after the rule.
+			specs = append(specs, azure.AlertRuleSpec{
+				Name:               fmt.Sprintf("%s-vm-availability", s.ClusterName()),
+				Description:        fmt.Sprintf("A virtual machine of cluster %s is unavailable", s.ClusterName()),
+				Scopes:             []string{azure.ResourceGroupID(s.SubscriptionID(), s.ResourceGroup())},
+				TargetResourceType: "Microsoft.Compute/virtualMachines",
+				MetricName:         "VmAvailabilityMetric",
+				Aggregation:        "Average",
+				Operator:           "LessThan",
+				Threshold:          1,
+			})
+		case infrav1.AlertRuleLoadBalancerHealthProbe:
+			specs = append(specs, azure.AlertRuleSpec{
+				Name:               fmt.Sprintf("%s-health-probe", s.APIServerLBName()),
+				Description:        fmt.Sprintf("The health probe of the API server load balancer of cluster %s fails for a control plane node", s.ClusterName()),
+				Scopes:             []string{azure.LoadBalancerID(s.SubscriptionID(), s.ResourceGroup(), s.APIServerLBName())},
+				TargetResourceType: "Microsoft.Network/loadBalancers",
+				MetricName:         "DipAvailability",
+				Aggregation:        "Average",
+				Operator:           "LessThan",
+				Threshold:          100,
+			})
+		case infrav1.AlertRuleNATGatewaySNAT:
+			for _, natGateway := range s.NatGatewaySpecs() {
+				specs = append(specs, azure.AlertRuleSpec{
+					Name:               fmt.Sprintf("%s-snat-failed", natGateway.Name),
+					Description:        fmt.Sprintf("SNAT connections fail on NAT gateway %s of cluster %s", natGateway.Name, s.ClusterName()),
+					Scopes:             []string{azure.NatGatewayID(s.SubscriptionID(), s.ResourceGroup(), natGateway.Name)},
+					TargetResourceType: "Microsoft.Network/natGateways",
+					MetricName:         "SNATConnectionCount",
+					Aggregation:        "Total",
+					Operator:           "GreaterThan",
+					Threshold:          0,
+					DimensionName:      "ConnectionState",
+					DimensionValues:    []string{"Failed"},
+				})
+			}
+		}
+	}
+	for i := range specs {
+		specs[i].Severity = to.Int32(alerts.Severity)
+		specs[i].ActionGroupID = alerts.ActionGroupID
+	}
+
+	return specs
+}
+
 // NSGSpecs returns the security group specs.
 func (s *ClusterScope) NSGSpecs() []azure.NSGSpec {
 	nsgspecs := make([]azure.NSGSpec, len(s.AzureCluster.Spec.NetworkSpec.Subnets))
 	for i, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
+		securityRules := subnet.SecurityGroup.SecurityRules
+		if subnet.Role == infrav1.SubnetControlPlane {
+			defaultRules := append(s.defaultControlPlaneSecurityRules(), s.managementSecurityRules()...)
+			defaultRules = append(defaultRules, s.connectivitySecurityRules()...)
+			securityRules = mergeDefaultSecurityRules(securityRules, defaultRules, subnet.SecurityGroup.DisabledDefaultRules)
+		}
 		nsgspecs[i] = azure.NSGSpec{
 			Name:          subnet.SecurityGroup.Name,
-			SecurityRules: subnet.SecurityGroup.SecurityRules,
+			SecurityRules: securityRules,
 		}
 	}
 
 	return nsgspecs
 }
 
+// defaultControlPlaneSecurityRules returns the security rules CAPZ adds to the security group of the control plane subnet.
+// Note that this is not done in a webhook as it requires a valid Cluster object to exist to get the API Server port.
+func (s *ClusterScope) defaultControlPlaneSecurityRules() infrav1.SecurityRules {
+	return append(infrav1.SecurityRules{
+		{
+			Name:             infrav1.SecurityRuleAllowSSH,
+			Description:      "Allow SSH",
+			Priority:         infrav1.DefaultSecurityRulePriorityMin,
+			Protocol:         infrav1.SecurityGroupProtocolTCP,
+			Direction:        infrav1.SecurityRuleDirectionInbound,
+			Source:           to.StringPtr("*"),
+			SourcePorts:      to.StringPtr("*"),
+			Destination:      to.StringPtr("*"),
+			DestinationPorts: to.StringPtr("22"),
+		},
+	}, s.apiServerSecurityRules()...)
+}
+
+// apiServerSecurityRules returns the default security rules allowing traffic to the API server, either from anywhere
+// or from each of the CIDR blocks allowed by the API server access profile. The traffic from the virtual network is
+// allowed by the AllowVnetInBound rule of Azure in both cases.
+func (s *ClusterScope) apiServerSecurityRules() infrav1.SecurityRules {
+	profile := s.AzureCluster.Spec.APIServerAccessProfile
+	if profile == nil {
+		return infrav1.SecurityRules{
+			{
+				Name:             infrav1.SecurityRuleAllowAPIServer,
+				Description:      "Allow K8s API Server",
+				Priority:         infrav1.DefaultSecurityRulePriorityMin + 1,
+				Protocol:         infrav1.SecurityGroupProtocolTCP,
+				Direction:        infrav1.SecurityRuleDirectionInbound,
+				Source:           to.StringPtr("*"),
+				SourcePorts:      to.StringPtr("*"),
+				Destination:      to.StringPtr("*"),
+				DestinationPorts: to.StringPtr(strconv.Itoa(int(s.APIServerPort()))),
+			},
+		}
+	}
+
+	rules := make(infrav1.SecurityRules, len(profile.AllowedCIDRs))
+	for i, cidr := range profile.AllowedCIDRs {
+		rules[i] = infrav1.SecurityRule{
+			Name:             fmt.Sprintf("%s_%d", infrav1.SecurityRuleAllowAPIServer, i),
+			Description:      "Allow K8s API Server from an allowed CIDR block",
+			Priority:         infrav1.DefaultSecurityRulePriorityMin + 1 + int32(i),
+			Protocol:         infrav1.SecurityGroupProtocolTCP,
+			Direction:        infrav1.SecurityRuleDirectionInbound,
+			Source:           to.StringPtr(cidr),
+			SourcePorts:      to.StringPtr("*"),
+			Destination:      to.StringPtr("*"),
+			DestinationPorts: to.StringPtr(strconv.Itoa(int(s.APIServerPort()))),
+		}
+	}
+	return rules
+}
+
+// managementSecurityRules returns the security rules allowing the management network to reach the API server, which
+// are merged with the default security rules of the control plane subnet.
+func (s *ClusterScope) managementSecurityRules() infrav1.SecurityRules {
+	management := s.AzureCluster.Spec.NetworkSpec.ManagementNetwork
+	if management == nil {
+		return nil
+	}
+
+	rules := make(infrav1.SecurityRules, len(management.CIDRBlocks))
+	for i, cidr := range management.CIDRBlocks {
+		rules[i] = infrav1.SecurityRule{
+			Name:             fmt.Sprintf("%s_%d", infrav1.SecurityRuleAllowManagementAPIServer, i),
+			Description:      "Allow K8s API Server from the management cluster",
+			Priority:         infrav1.DefaultSecurityRulePriorityMin + 2 + int32(i),
+			Protocol:         infrav1.SecurityGroupProtocolTCP,
+			Direction:        infrav1.SecurityRuleDirectionInbound,
+			Source:           to.StringPtr(cidr),
+			SourcePorts:      to.StringPtr("*"),
+			Destination:      to.StringPtr("*"),
+			DestinationPorts: to.StringPtr(strconv.Itoa(int(s.APIServerPort()))),
+		}
+	}
+	return rules
+}
+
+// connectivitySecurityRules returns the security rules allowing the ports of the connectivity profile, from or to
+// each of their CIDR blocks, which are merged with the default security rules of the control plane subnet.
+func (s *ClusterScope) connectivitySecurityRules() infrav1.SecurityRules {
+	profile := s.AzureCluster.Spec.ConnectivityProfile
+	if profile == nil {
+		return nil
+	}
+
+	var rules infrav1.SecurityRules
+	for _, port := range profile.Ingress {
+		for i, cidr := range s.connectivityCIDRBlocks(port) {
+			rules = append(rules, infrav1.SecurityRule{
+				Name:             fmt.Sprintf("%s_%s_%d", infrav1.SecurityRuleAllowConnectivityIngress, port.Name, i),
+				Description:      "Allow the ingress port of a management agent",
+				Priority:         infrav1.DefaultSecurityRulePriorityMin + 2 + int32(len(rules)),
+				Protocol:         connectivityProtocol(port),
+				Direction:        infrav1.SecurityRuleDirectionInbound,
+				Source:           to.StringPtr(cidr),
+				SourcePorts:      to.StringPtr("*"),
+				Destination:      to.StringPtr("*"),
+				DestinationPorts: to.StringPtr(strconv.Itoa(int(port.Port))),
+			})
+		}
+	}
+	egress := 0
+	for _, port := range profile.Egress {
+		for i, cidr := range s.connectivityCIDRBlocks(port) {
+			rules = append(rules, infrav1.SecurityRule{
+				Name:             fmt.Sprintf("%s_%s_%d", infrav1.SecurityRuleAllowConnectivityEgress, port.Name, i),
+				Description:      "Allow the egress port of a management service",
+				Priority:         infrav1.DefaultSecurityRulePriorityMin + int32(egress),
+				Protocol:         connectivityProtocol(port),
+				Direction:        infrav1.SecurityRuleDirectionOutbound,
+				Source:           to.StringPtr("*"),
+				SourcePorts:      to.StringPtr("*"),
+				Destination:      to.StringPtr(cidr),
+				DestinationPorts: to.StringPtr(strconv.Itoa(int(port.Port))),
+			})
+			egress++
+		}
+	}
+	return rules
+}
+
+// connectivityCIDRBlocks returns the CIDR blocks of a port of the connectivity profile, defaulting to the CIDR blocks
+// of the management network, or to any address.
+func (s *ClusterScope) connectivityCIDRBlocks(port infrav1.ConnectivityPort) []string {
+	if len(port.CIDRBlocks) > 0 {
+		return port.CIDRBlocks
+	}
+	if management := s.AzureCluster.Spec.NetworkSpec.ManagementNetwork; management != nil && len(management.CIDRBlocks) > 0 {
+		return management.CIDRBlocks
+	}
+	return []string{"*"}
+}
+
+// connectivityProtocol returns the protocol of a port of the connectivity profile, TCP if it is not set.
+func connectivityProtocol(port infrav1.ConnectivityPort) infrav1.SecurityGroupProtocol {
+	if port.Protocol == "" {
+		return infrav1.SecurityGroupProtocolTCP
+	}
+	return port.Protocol
+}
+
+// mergeDefaultSecurityRules returns the given security rules followed by the default rules which are neither disabled
+// nor overridden by a rule with the same name. A default rule whose priority is already used by a rule of the same
+// direction is moved to the next free priority of the band reserved to the default rules, and dropped if there is none.
+func mergeDefaultSecurityRules(rules, defaults infrav1.SecurityRules, disabled []string) infrav1.SecurityRules {
+	merged := make(infrav1.SecurityRules, 0, len(rules)+len(defaults))
+	merged = append(merged, rules...)
+
+	skip := make(map[string]bool, len(rules)+len(disabled))
+	usedPriorities := map[infrav1.SecurityRuleDirection]map[int32]bool{
+		infrav1.SecurityRuleDirectionInbound:  {},
+		infrav1.SecurityRuleDirectionOutbound: {},
+	}
+	for _, rule := range rules {
+		skip[strings.ToLower(rule.Name)] = true
+		if usedPriorities[rule.Direction] != nil {
+			usedPriorities[rule.Direction][rule.Priority] = true
+		}
+	}
+	for _, name := range disabled {
+		skip[strings.ToLower(name)] = true
+	}
+
+	for _, rule := range defaults {
+		if skip[strings.ToLower(rule.Name)] || skip[baseDefaultRuleName(rule.Name)] {
+			continue
+		}
+		for usedPriorities[rule.Direction][rule.Priority] {
+			rule.Priority++
+		}
+		if rule.Priority > infrav1.DefaultSecurityRulePriorityMax {
+			continue
+		}
+		usedPriorities[rule.Direction][rule.Priority] = true
+		merged = append(merged, rule)
+	}
+
+	return merged
+}
+
+// baseDefaultRuleName returns the lowercase name of the default rule a rule derives from. The rules allowing the CIDR
+// blocks of the API server access profile derive from the allow_apiserver rule, so that disabling or overriding it
+// applies to all of them.
+func baseDefaultRuleName(name string) string {
+	name = strings.ToLower(name)
+	if suffix := strings.TrimPrefix(name, infrav1.SecurityRuleAllowAPIServer+"_"); suffix != name {
+		if _, err := strconv.Atoi(suffix); err == nil {
+			return infrav1.SecurityRuleAllowAPIServer
+		}
+	}
+	return name
+}
+
 // SubnetSpecs returns the subnets specs.
 func (s *ClusterScope) SubnetSpecs() []azure.SubnetSpec {
 	numberOfSubnets := len(s.AzureCluster.Spec.NetworkSpec.Subnets)
 	if s.AzureCluster.Spec.BastionSpec.AzureBastion != nil {
 		numberOfSubnets++
 	}
+	if s.ExpressRouteGatewaySpec() != nil {
+		numberOfSubnets++
+	}
+	firewall := s.AzureFirewallSpec()
+	if firewall != nil && firewall.Managed {
+		numberOfSubnets++
+	}
 
 	subnetSpecs := make([]azure.SubnetSpec, 0, numberOfSubnets)
 	for _, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
@@ -270,6 +668,9 @@ func (s *ClusterScope) SubnetSpecs() []azure.SubnetSpec {
 			RouteTableName:    subnet.RouteTable.Name,
 			Role:              subnet.Role,
 			NatGatewayName:    subnet.NatGateway.Name,
+			PrivateEndpoints:  len(subnet.PrivateEndpoints) > 0,
+			// the NAT IPs of the private link service of the API server are allocated in the control plane subnet
+			PrivateLinkService: subnet.Role == infrav1.SubnetControlPlane && s.APIServerPrivateLinkService() != nil,
 		}
 		subnetSpecs = append(subnetSpecs, subnetSpec)
 	}
@@ -286,6 +687,28 @@ func (s *ClusterScope) SubnetSpecs() []azure.SubnetSpec {
 		})
 	}
 
+	if s.ExpressRouteGatewaySpec() != nil {
+		gatewaySubnet := s.AzureCluster.Spec.NetworkSpec.ExpressRouteGateway.Subnet
+		subnetSpecs = append(subnetSpecs, azure.SubnetSpec{
+			Name:           gatewaySubnet.Name,
+			CIDRs:          gatewaySubnet.CIDRBlocks,
+			VNetName:       s.Vnet().Name,
+			RouteTableName: gatewaySubnet.RouteTable.Name,
+			Role:           gatewaySubnet.Role,
+		})
+	}
+
+	if firewall != nil && firewall.Managed {
+		firewallSubnet := s.AzureCluster.Spec.NetworkSpec.Firewall.Subnet
+		subnetSpecs = append(subnetSpecs, azure.SubnetSpec{
+			Name:           firewallSubnet.Name,
+			CIDRs:          firewallSubnet.CIDRBlocks,
+			VNetName:       s.Vnet().Name,
+			RouteTableName: firewallSubnet.RouteTable.Name,
+			Role:           firewallSubnet.Role,
+		})
+	}
+
 	return subnetSpecs
 }
 
@@ -296,13 +719,31 @@ func (s *ClusterScope) GroupSpec() azure.ResourceSpecGetter {
 		Location:       s.Location(),
 		ClusterName:    s.ClusterName(),
 		AdditionalTags: s.AdditionalTags(),
+		ExistingOnly:   feature.Gates.Enabled(feature.ResourceGroupScoped),
+	}
+}
+
+// vnetPeerings returns the remote virtual networks the virtual network is peered with, including the virtual network
+// of the management cluster unless it is already listed.
+func (s *ClusterScope) vnetPeerings() infrav1.VnetPeerings {
+	peerings := s.Vnet().Peerings
+	management := s.AzureCluster.Spec.NetworkSpec.ManagementNetwork
+	if management == nil || management.Vnet == nil {
+		return peerings
+	}
+	for _, peering := range peerings {
+		if strings.EqualFold(peering.ResourceGroup, management.Vnet.ResourceGroup) && strings.EqualFold(peering.RemoteVnetName, management.Vnet.RemoteVnetName) {
+			return peerings
+		}
 	}
+	return append(peerings[:len(peerings):len(peerings)], *management.Vnet)
 }
 
 // VnetPeeringSpecs returns the virtual network peering specs.
 func (s *ClusterScope) VnetPeeringSpecs() []azure.ResourceSpecGetter {
-	peeringSpecs := make([]azure.ResourceSpecGetter, 2*len(s.Vnet().Peerings))
-	for i, peering := range s.Vnet().Peerings {
+	peerings := s.vnetPeerings()
+	peeringSpecs := make([]azure.ResourceSpecGetter, 2*len(peerings))
+	for i, peering := range peerings {
 		forwardPeering := &vnetpeerings.VnetPeeringSpec{
 			PeeringName:         azure.GenerateVnetPeeringName(s.Vnet().Name, peering.RemoteVnetName),
 			SourceVnetName:      s.Vnet().Name,
@@ -326,62 +767,327 @@ func (s *ClusterScope) VnetPeeringSpecs() []azure.ResourceSpecGetter {
 	return peeringSpecs
 }
 
+// FlowLogSpecs returns the flow log specs of the network security groups.
+func (s *ClusterScope) FlowLogSpecs() []azure.ResourceSpecGetter {
+	flowLogs := s.AzureCluster.Spec.NetworkSpec.FlowLogs
+	if flowLogs == nil || flowLogs.NetworkWatcher == nil {
+		return nil
+	}
+
+	// Subnets may share a network security group, which only needs one flow log.
+	seen := make(map[string]bool)
+	var flowLogSpecs []azure.ResourceSpecGetter
+	for _, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
+		nsgName := subnet.SecurityGroup.Name
+		if nsgName == "" || seen[nsgName] {
+			continue
+		}
+		seen[nsgName] = true
+		flowLogSpecs = append(flowLogSpecs, &flowlogs.FlowLogSpec{
+			Name:                        azure.GenerateFlowLogName(s.ResourceGroup(), nsgName),
+			NetworkWatcherName:          flowLogs.NetworkWatcher.Name,
+			NetworkWatcherResourceGroup: flowLogs.NetworkWatcher.ResourceGroup,
+			Location:                    s.Location(),
+			TargetNSGID:                 azure.SecurityGroupID(s.SubscriptionID(), s.ResourceGroup(), nsgName),
+			StorageAccountID:            flowLogs.StorageAccountID,
+			RetentionDays:               flowLogs.RetentionDays,
+			TrafficAnalytics:            flowLogs.TrafficAnalytics,
+			ClusterName:                 s.ClusterName(),
+			AdditionalTags:              s.AdditionalTags(),
+		})
+	}
+
+	return flowLogSpecs
+}
+
+// PrivateEndpointSpecs returns the private endpoint specs of the subnets.
+func (s *ClusterScope) PrivateEndpointSpecs() []azure.ResourceSpecGetter {
+	var privateEndpointSpecs []azure.ResourceSpecGetter
+	for _, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
+		for _, privateEndpoint := range subnet.PrivateEndpoints {
+			privateEndpointSpecs = append(privateEndpointSpecs, &privateendpoints.PrivateEndpointSpec{
+				Name:                 privateEndpoint.Name,
+				ResourceGroup:        s.ResourceGroup(),
+				Location:             s.Location(),
+				SubnetID:             azure.SubnetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name, subnet.Name),
+				PrivateLinkServiceID: privateEndpoint.PrivateLinkServiceID,
+				GroupIDs:             privateEndpoint.GroupIDs,
+				PrivateDNSZoneGroup:  privateEndpoint.PrivateDNSZoneGroup,
+				ClusterName:          s.ClusterName(),
+				AdditionalTags:       s.AdditionalTags(),
+			})
+		}
+	}
+
+	return privateEndpointSpecs
+}
+
+// DiskEncryptionSetSpecs returns the specs of the disk encryption sets of the cluster.
+func (s *ClusterScope) DiskEncryptionSetSpecs() []azure.ResourceSpecGetter {
+	specs := make([]azure.ResourceSpecGetter, 0, len(s.AzureCluster.Spec.DiskEncryptionSets))
+	for _, diskEncryptionSet := range s.AzureCluster.Spec.DiskEncryptionSets {
+		specs = append(specs, &diskencryptionsets.DiskEncryptionSetSpec{
+			Name:           diskEncryptionSet.Name,
+			ResourceGroup:  s.ResourceGroup(),
+			Location:       s.Location(),
+			KeyURL:         diskEncryptionSet.KeyURL,
+			KeyVaultID:     diskEncryptionSet.KeyVaultID,
+			EncryptionType: diskEncryptionSet.EncryptionType,
+			ClusterName:    s.ClusterName(),
+			AdditionalTags: s.AdditionalTags(),
+		})
+	}
+
+	return specs
+}
+
+// PrivateLinkServiceSpecs returns the spec of the private link service of the API server load balancer, if any.
+func (s *ClusterScope) PrivateLinkServiceSpecs() []azure.ResourceSpecGetter {
+	privateLinkService := s.APIServerPrivateLinkService()
+	if privateLinkService == nil {
+		return nil
+	}
+
+	frontendIPConfigIDs := make([]string, 0, len(s.APIServerLB().FrontendIPs))
+	for _, frontendIP := range s.APIServerLB().FrontendIPs {
+		frontendIPConfigIDs = append(frontendIPConfigIDs, azure.FrontendIPConfigID(s.SubscriptionID(), s.ResourceGroup(), s.APIServerLBName(), frontendIP.Name))
+	}
+
+	return []azure.ResourceSpecGetter{
+		&privatelinkservices.PrivateLinkServiceSpec{
+			Name:                privateLinkService.Name,
+			ResourceGroup:       s.ResourceGroup(),
+			Location:            s.Location(),
+			FrontendIPConfigIDs: frontendIPConfigIDs,
+			SubnetID:            azure.SubnetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name, s.ControlPlaneSubnet().Name),
+			NATIPCount:          to.Int32(privateLinkService.NATIPCount),
+			Visibility:          privateLinkService.Visibility,
+			AutoApproval:        privateLinkService.AutoApproval,
+			ClusterName:         s.ClusterName(),
+			AdditionalTags:      s.AdditionalTags(),
+		},
+	}
+}
+
 // VNetSpec returns the virtual network spec.
 func (s *ClusterScope) VNetSpec() azure.VNetSpec {
-	return azure.VNetSpec{
+	spec := azure.VNetSpec{
 		ResourceGroup: s.Vnet().ResourceGroup,
 		Name:          s.Vnet().Name,
 		CIDRs:         s.Vnet().CIDRBlocks,
 	}
+	if plan := s.Vnet().DDoSProtectionPlan; plan != nil {
+		spec.DDoSProtectionPlanID = plan.ID
+		if plan.Name != "" {
+			spec.DDoSProtectionPlanID = azure.DDoSProtectionPlanID(s.SubscriptionID(), s.Vnet().ResourceGroup, plan.Name)
+			spec.DDoSProtectionPlanName = plan.Name
+		}
+	}
+	return spec
+}
+
+// NetworkDeploymentSpec returns the ARM deployment provisioning the virtual network of the cluster along with its
+// security groups, route tables and subnets, which is only the case when the BulkProvisioning feature gate is enabled
+// and the virtual network of the cluster wasn't provisioned yet. Subnets with a NAT gateway are left to be created once
+// their NAT gateway exists.
+func (s *ClusterScope) NetworkDeploymentSpec() *azure.NetworkDeploymentSpec {
+	if !feature.Gates.Enabled(feature.BulkProvisioning) || s.Vnet().ID != "" || s.Vnet().ResourceGroup != s.ResourceGroup() {
+		return nil
+	}
+	// a DDoS protection plan created by capz has to exist before the virtual network protected by it
+	if plan := s.Vnet().DDoSProtectionPlan; plan != nil && plan.Name != "" {
+		return nil
+	}
+	if condition := conditions.Get(s.AzureCluster, infrav1.NetworkDeployedCondition); condition != nil && condition.Reason != infrav1.NetworkDeployingReason {
+		return nil
+	}
+
+	spec := &azure.NetworkDeploymentSpec{
+		Name:           azure.GenerateNetworkDeploymentName(s.ClusterName()),
+		ResourceGroup:  s.ResourceGroup(),
+		VNet:           s.VNetSpec(),
+		SecurityGroups: s.NSGSpecs(),
+		RouteTables:    s.RouteTableSpecs(),
+	}
+	for _, subnet := range s.SubnetSpecs() {
+		if subnet.NatGatewayName == "" {
+			spec.Subnets = append(spec.Subnets, subnet)
+		}
+	}
+	return spec
+}
+
+// UpdateNetworkDeploymentStatus reports the state of the ARM deployment of the initial network in a condition.
+func (s *ClusterScope) UpdateNetworkDeploymentStatus(reason string, err error) {
+	if err == nil {
+		conditions.MarkTrue(s.AzureCluster, infrav1.NetworkDeployedCondition)
+		return
+	}
+	severity := clusterv1.ConditionSeverityInfo
+	if reason == infrav1.NetworkDeploymentFailedReason {
+		severity = clusterv1.ConditionSeverityWarning
+	}
+	conditions.MarkFalse(s.AzureCluster, infrav1.NetworkDeployedCondition, reason, severity, "%s", err.Error())
 }
 
 // PrivateDNSSpec returns the private dns zone spec.
 func (s *ClusterScope) PrivateDNSSpec() *azure.PrivateDNSSpec {
 	var specs *azure.PrivateDNSSpec
 	if s.IsAPIServerPrivate() {
-		links := make([]azure.PrivateDNSLinkSpec, 1+len(s.Vnet().Peerings))
-		links[0] = azure.PrivateDNSLinkSpec{
-			VNetName:          s.Vnet().Name,
-			VNetResourceGroup: s.Vnet().ResourceGroup,
-			LinkName:          azure.GenerateVNetLinkName(s.Vnet().Name),
-		}
-		for i, peering := range s.Vnet().Peerings {
-			links[i+1] = azure.PrivateDNSLinkSpec{
-				VNetName:          peering.RemoteVnetName,
-				VNetResourceGroup: peering.ResourceGroup,
-				LinkName:          azure.GenerateVNetLinkName(peering.RemoteVnetName),
+		var links []azure.PrivateDNSLinkSpec
+		existingZone := s.AzureCluster.Spec.NetworkSpec.PrivateDNSZone
+		if existingZone == nil || !existingZone.SkipVirtualNetworkLinks {
+			peerings := s.vnetPeerings()
+			links = make([]azure.PrivateDNSLinkSpec, 1+len(peerings))
+			links[0] = azure.PrivateDNSLinkSpec{
+				VNetName:          s.Vnet().Name,
+				VNetResourceGroup: s.Vnet().ResourceGroup,
+				LinkName:          azure.GenerateVNetLinkName(s.Vnet().Name),
+			}
+			for i, peering := range peerings {
+				links[i+1] = azure.PrivateDNSLinkSpec{
+					VNetName:          peering.RemoteVnetName,
+					VNetResourceGroup: peering.ResourceGroup,
+					LinkName:          azure.GenerateVNetLinkName(peering.RemoteVnetName),
+				}
 			}
 		}
 		specs = &azure.PrivateDNSSpec{
-			ZoneName: s.GetPrivateDNSZoneName(),
-			Links:    links,
+			ZoneName:       s.GetPrivateDNSZoneName(),
+			ResourceGroup:  s.ResourceGroup(),
+			SubscriptionID: s.SubscriptionID(),
+			Links:          links,
 			Records: []infrav1.AddressRecord{
 				{
-					Hostname: azure.PrivateAPIServerHostname,
+					Hostname: s.privateAPIServerHostname(),
 					IP:       s.APIServerPrivateIP(),
 				},
 			},
 		}
+		for _, record := range s.AzureCluster.Spec.NetworkSpec.PrivateDNSRecords {
+			if record.CNAME != "" {
+				specs.CNAMERecords = append(specs.CNAMERecords, azure.PrivateDNSCNAMERecordSpec{
+					Hostname: record.Hostname,
+					CNAME:    record.CNAME,
+				})
+				continue
+			}
+			specs.Records = append(specs.Records, infrav1.AddressRecord{
+				Hostname: record.Hostname,
+				IP:       record.IP,
+			})
+		}
+		if existingZone != nil {
+			// The ID is validated by the webhook, so parsing it only fails on clusters created before the validation.
+			if resource, err := azureautorest.ParseResourceID(existingZone.ID); err == nil {
+				specs.ResourceGroup = resource.ResourceGroup
+				specs.SubscriptionID = resource.SubscriptionID
+			}
+			specs.ExistingZone = true
+		}
 	}
 
 	return specs
 }
 
+// PublicDNSSpec returns the spec of the record of a public API server in an existing DNS zone.
+func (s *ClusterScope) PublicDNSSpec() *azure.PublicDNSSpec {
+	record := s.AzureCluster.Spec.NetworkSpec.APIServerPublicDNS
+	if record == nil || s.IsAPIServerPrivate() {
+		return nil
+	}
+	// The ID is validated by the webhook.
+	zone, err := azureautorest.ParseResourceID(record.ZoneID)
+	if err != nil {
+		return nil
+	}
+	return &azure.PublicDNSSpec{
+		ZoneName:       zone.ResourceName,
+		ResourceGroup:  zone.ResourceGroup,
+		SubscriptionID: zone.SubscriptionID,
+		RecordName:     record.Name,
+		RecordType:     record.Type,
+		TTL:            to.Int64(record.TTL),
+		CNAME:          s.APIServerPublicIP().DNSName,
+		PublicIPID:     azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), s.APIServerPublicIP().Name),
+	}
+}
+
 // BastionSpec returns the bastion spec.
 func (s *ClusterScope) BastionSpec() azure.BastionSpec {
 	var ret azure.BastionSpec
 	if s.AzureCluster.Spec.BastionSpec.AzureBastion != nil {
 		ret.AzureBastion = &azure.AzureBastionSpec{
-			Name:         s.AzureCluster.Spec.BastionSpec.AzureBastion.Name,
-			SubnetSpec:   s.AzureCluster.Spec.BastionSpec.AzureBastion.Subnet,
-			PublicIPName: s.AzureCluster.Spec.BastionSpec.AzureBastion.PublicIP.Name,
-			VNetName:     s.Vnet().Name,
+			Name:            s.AzureCluster.Spec.BastionSpec.AzureBastion.Name,
+			SubnetSpec:      s.AzureCluster.Spec.BastionSpec.AzureBastion.Subnet,
+			PublicIPName:    s.AzureCluster.Spec.BastionSpec.AzureBastion.PublicIP.Name,
+			VNetName:        s.Vnet().Name,
+			Sku:             s.AzureCluster.Spec.BastionSpec.AzureBastion.Sku,
+			ScaleUnits:      s.AzureCluster.Spec.BastionSpec.AzureBastion.ScaleUnits,
+			EnableTunneling: s.AzureCluster.Spec.BastionSpec.AzureBastion.EnableTunneling,
+			EnableIPConnect: s.AzureCluster.Spec.BastionSpec.AzureBastion.EnableIPConnect,
 		}
 	}
 
 	return ret
 }
 
+// ExpressRouteGatewaySpec returns the spec of the ExpressRoute gateway to create in the virtual network, or nil if
+// there is none or it references an existing gateway.
+func (s *ClusterScope) ExpressRouteGatewaySpec() *azure.ExpressRouteGatewaySpec {
+	gateway := s.AzureCluster.Spec.NetworkSpec.ExpressRouteGateway
+	if gateway == nil || gateway.Name == "" {
+		return nil
+	}
+
+	return &azure.ExpressRouteGatewaySpec{
+		Name:          gateway.Name,
+		ResourceGroup: s.Vnet().ResourceGroup,
+		Sku:           gateway.Sku,
+		VNetName:      s.Vnet().Name,
+		SubnetName:    gateway.Subnet.Name,
+		PublicIPName:  gateway.PublicIP.Name,
+	}
+}
+
+// AzureFirewallSpec returns the spec of the Azure Firewall the egress of the nodes is routed through, or nil if there
+// is none.
+func (s *ClusterScope) AzureFirewallSpec() *azure.AzureFirewallSpec {
+	firewall := s.AzureCluster.Spec.NetworkSpec.Firewall
+	if firewall == nil {
+		return nil
+	}
+
+	spec := &azure.AzureFirewallSpec{
+		Name:          firewall.Name,
+		ResourceGroup: s.Vnet().ResourceGroup,
+		Managed:       firewall.Name != "",
+		SkuTier:       firewall.SkuTier,
+		PolicyID:      firewall.PolicyID,
+		VNetName:      s.Vnet().Name,
+		SubnetName:    firewall.Subnet.Name,
+		PublicIPName:  firewall.PublicIP.Name,
+	}
+	if !spec.Managed {
+		// The ID is validated by the webhook, so parsing it only fails on clusters created before the validation.
+		resource, err := azureautorest.ParseResourceID(firewall.ID)
+		if err != nil {
+			return nil
+		}
+		spec.Name = resource.ResourceName
+		spec.ResourceGroup = resource.ResourceGroup
+	}
+
+	seen := make(map[string]bool)
+	for _, subnet := range s.NodeSubnets() {
+		if subnet.RouteTable.Name != "" && !seen[subnet.RouteTable.Name] {
+			seen[subnet.RouteTable.Name] = true
+			spec.RouteTableNames = append(spec.RouteTableNames, subnet.RouteTable.Name)
+		}
+	}
+	return spec
+}
+
 // Vnet returns the cluster Vnet.
 func (s *ClusterScope) Vnet() *infrav1.VnetSpec {
 	return &s.AzureCluster.Spec.NetworkSpec.Vnet
@@ -457,6 +1163,11 @@ func (s *ClusterScope) APIServerLB() *infrav1.LoadBalancerSpec {
 	return &s.AzureCluster.Spec.NetworkSpec.APIServerLB
 }
 
+// APIServerPrivateLinkService returns the private link service of the cluster API Server load balancer.
+func (s *ClusterScope) APIServerPrivateLinkService() *infrav1.PrivateLinkServiceSpec {
+	return s.AzureCluster.Spec.NetworkSpec.APIServerPrivateLinkService
+}
+
 // NodeOutboundLB returns the cluster node outbound load balancer.
 func (s *ClusterScope) NodeOutboundLB() *infrav1.LoadBalancerSpec {
 	return s.AzureCluster.Spec.NetworkSpec.NodeOutboundLB
@@ -489,12 +1200,24 @@ func (s *ClusterScope) APIServerPrivateIP() string {
 
 // GetPrivateDNSZoneName returns the Private DNS Zone from the spec or generate it from cluster name.
 func (s *ClusterScope) GetPrivateDNSZoneName() string {
+	if zone := s.AzureCluster.Spec.NetworkSpec.PrivateDNSZone; zone != nil {
+		return zone.ID[strings.LastIndex(zone.ID, "/")+1:]
+	}
 	if len(s.AzureCluster.Spec.NetworkSpec.PrivateDNSZoneName) > 0 {
 		return s.AzureCluster.Spec.NetworkSpec.PrivateDNSZoneName
 	}
 	return azure.GeneratePrivateDNSZoneName(s.ClusterName())
 }
 
+// privateAPIServerHostname returns the name of the API server record in the private DNS zone.
+// Existing zones may be shared by several clusters, so the record name includes the cluster name.
+func (s *ClusterScope) privateAPIServerHostname() string {
+	if s.AzureCluster.Spec.NetworkSpec.PrivateDNSZone != nil {
+		return fmt.Sprintf("%s.%s", azure.PrivateAPIServerHostname, s.ClusterName())
+	}
+	return azure.PrivateAPIServerHostname
+}
+
 // APIServerLBPoolName returns the API Server LB backend pool name.
 func (s *ClusterScope) APIServerLBPoolName(loadBalancerName string) string {
 	return azure.GenerateBackendAddressPoolName(loadBalancerName)
@@ -555,11 +1278,75 @@ func (s *ClusterScope) AvailabilitySetEnabled() bool {
 	return len(s.AzureCluster.Status.FailureDomains) == 0
 }
 
+// ProximityPlacementGroup returns the name of the proximity placement group of the cluster, if it has one.
+func (s *ClusterScope) ProximityPlacementGroup() (string, bool) {
+	if s.AzureCluster.Spec.ProximityPlacementGroup == nil {
+		return "", false
+	}
+	return s.AzureCluster.Spec.ProximityPlacementGroup.Name, true
+}
+
+// ProximityPlacementGroupID returns the ID of the proximity placement group of the cluster, in which machines are
+// placed by default, or an empty string if the cluster has none.
+func (s *ClusterScope) ProximityPlacementGroupID() string {
+	name, ok := s.ProximityPlacementGroup()
+	if !ok {
+		return ""
+	}
+	return azure.ProximityPlacementGroupID(s.SubscriptionID(), s.ResourceGroup(), name)
+}
+
 // CloudProviderConfigOverrides returns the cloud provider config overrides for the cluster.
 func (s *ClusterScope) CloudProviderConfigOverrides() *infrav1.CloudProviderConfigOverrides {
 	return s.AzureCluster.Spec.CloudProviderConfigOverrides
 }
 
+// defaultNoProxy are the destinations which must always bypass the cluster-wide proxy: the local host, the Azure
+// Instance Metadata Service, the Azure platform's virtual public IP, and in-cluster service names.
+var defaultNoProxy = []string{"localhost", "127.0.0.1", "169.254.169.254", "168.63.129.16", ".svc", ".cluster.local"}
+
+// ProxyConfig returns the cluster-wide proxy configuration for machines, or nil if no proxy is configured.
+// The cluster's vnet, pod and service CIDRs and the default no-proxy destinations are appended to the user-specified
+// no-proxy list.
+func (s *ClusterScope) ProxyConfig() *infrav1.ProxyConfig {
+	if s.AzureCluster.Spec.ProxyConfig == nil {
+		return nil
+	}
+	proxyConfig := s.AzureCluster.Spec.ProxyConfig.DeepCopy()
+
+	noProxy := append([]string{}, proxyConfig.NoProxy...)
+	noProxy = append(noProxy, defaultNoProxy...)
+	noProxy = append(noProxy, s.Vnet().CIDRBlocks...)
+	if clusterNetwork := s.Cluster.Spec.ClusterNetwork; clusterNetwork != nil {
+		if clusterNetwork.Pods != nil {
+			noProxy = append(noProxy, clusterNetwork.Pods.CIDRBlocks...)
+		}
+		if clusterNetwork.Services != nil {
+			noProxy = append(noProxy, clusterNetwork.Services.CIDRBlocks...)
+		}
+	}
+
+	seen := make(map[string]bool, len(noProxy))
+	proxyConfig.NoProxy = nil
+	for _, entry := range noProxy {
+		if !seen[entry] {
+			seen[entry] = true
+			proxyConfig.NoProxy = append(proxyConfig.NoProxy, entry)
+		}
+	}
+	return proxyConfig
+}
+
+// TrustedCAs returns the reference to the additional CA certificates trusted by the machines, or nil if there are none.
+func (s *ClusterScope) TrustedCAs() *infrav1.TrustedCASource {
+	return s.AzureCluster.Spec.TrustedCAs
+}
+
+// RegistryMirrors returns the mirrors of container registries the machines pull images from.
+func (s *ClusterScope) RegistryMirrors() []infrav1.RegistryMirror {
+	return s.AzureCluster.Spec.RegistryMirrors
+}
+
 // GenerateFQDN generates a fully qualified domain name, based on a hash, cluster name and cluster location.
 func (s *ClusterScope) GenerateFQDN(ipName string) string {
 	h := fnv.New32a()
@@ -589,6 +1376,60 @@ func (s *ClusterScope) ListOptionsLabelSelector() client.ListOption {
 	})
 }
 
+// UnsupportedCapabilities returns the names of the features used by the cluster which the API versions of its API
+// version profile don't support.
+func (s *ClusterScope) UnsupportedCapabilities() []string {
+	networkSpec := s.AzureCluster.Spec.NetworkSpec
+	var natGateway, privateEndpoints bool
+	for _, subnet := range networkSpec.Subnets {
+		natGateway = natGateway || subnet.IsNatGatewayEnabled()
+		privateEndpoints = privateEndpoints || len(subnet.PrivateEndpoints) > 0
+	}
+
+	capabilities := []struct {
+		capability azure.Capability
+		used       bool
+	}{
+		{capability: azure.BastionHostCapability, used: s.AzureCluster.Spec.BastionSpec.AzureBastion != nil},
+		{capability: azure.NatGatewayCapability, used: natGateway},
+		{capability: azure.PrivateEndpointCapability, used: privateEndpoints},
+		{capability: azure.PrivateLinkServiceCapability, used: networkSpec.APIServerPrivateLinkService != nil},
+		{capability: azure.FlowLogCapability, used: networkSpec.FlowLogs != nil},
+		{capability: azure.DDoSProtectionPlanCapability, used: networkSpec.Vnet.DDoSProtectionPlan != nil},
+		{capability: azure.PrivateDNSCapability, used: s.IsAPIServerPrivate()},
+	}
+
+	apiVersions := azure.APIVersions(s.AzureCluster.Spec.APIVersionProfile)
+	var unsupported []string
+	for _, c := range capabilities {
+		if c.used && !c.capability.IsSupported(apiVersions) {
+			unsupported = append(unsupported, c.capability.Name)
+		}
+	}
+	return unsupported
+}
+
+// ForceDeleteVirtualMachines returns whether the virtual machines and virtual machine scale sets should be force
+// deleted, which is only the case when the whole cluster is being deleted.
+func (s *ClusterScope) ForceDeleteVirtualMachines() bool {
+	return s.AzureCluster.Spec.ForceDeleteVirtualMachines && !s.Cluster.DeletionTimestamp.IsZero()
+}
+
+// SetAPIVersionProfileCondition reports whether the API version profile supports the features used by the cluster.
+func (s *ClusterScope) SetAPIVersionProfileCondition() {
+	if s.AzureCluster.Spec.APIVersionProfile == nil {
+		conditions.Delete(s.AzureCluster, infrav1.APIVersionProfileCompatibleCondition)
+		return
+	}
+
+	if unsupported := s.UnsupportedCapabilities(); len(unsupported) > 0 {
+		conditions.MarkFalse(s.AzureCluster, infrav1.APIVersionProfileCompatibleCondition, infrav1.UnsupportedCapabilitiesReason,
+			clusterv1.ConditionSeverityWarning, "the API versions of the profile don't support %s", strings.Join(unsupported, ", "))
+		return
+	}
+	conditions.MarkTrue(s.AzureCluster, infrav1.APIVersionProfileCompatibleCondition)
+}
+
 // PatchObject persists the cluster configuration and status.
 func (s *ClusterScope) PatchObject(ctx context.Context) error {
 	conditions.SetSummary(s.AzureCluster,
@@ -596,7 +1437,11 @@ func (s *ClusterScope) PatchObject(ctx context.Context) error {
 			infrav1.ResourceGroupReadyCondition,
 			infrav1.NetworkInfrastructureReadyCondition,
 			infrav1.VnetPeeringReadyCondition,
+			infrav1.FlowLogsReadyCondition,
+			infrav1.PrivateEndpointsReadyCondition,
+			infrav1.PrivateLinkServiceReadyCondition,
 			infrav1.DisksReadyCondition,
+			infrav1.DiskEncryptionSetsReadyCondition,
 		),
 	)
 
@@ -608,7 +1453,15 @@ func (s *ClusterScope) PatchObject(ctx context.Context) error {
 			infrav1.ResourceGroupReadyCondition,
 			infrav1.NetworkInfrastructureReadyCondition,
 			infrav1.VnetPeeringReadyCondition,
+			infrav1.FlowLogsReadyCondition,
+			infrav1.PrivateEndpointsReadyCondition,
+			infrav1.PrivateLinkServiceReadyCondition,
 			infrav1.DisksReadyCondition,
+			infrav1.DiskEncryptionSetsReadyCondition,
+			infrav1.APIVersionProfileCompatibleCondition,
+			infrav1.ProvisioningSLOMetCondition,
+			infrav1.ResourceOwnershipVerifiedCondition,
+			infrav1.NetworkDeployedCondition,
 		}})
 }
 
@@ -637,7 +1490,7 @@ func (s *ClusterScope) APIServerPort() int32 {
 // APIServerHost returns the hostname used to reach the API server.
 func (s *ClusterScope) APIServerHost() string {
 	if s.IsAPIServerPrivate() {
-		return azure.GeneratePrivateFQDN(s.GetPrivateDNSZoneName())
+		return fmt.Sprintf("%s.%s", s.privateAPIServerHostname(), s.GetPrivateDNSZoneName())
 	}
 	return s.APIServerPublicIP().DNSName
 }
@@ -650,6 +1503,93 @@ func (s *ClusterScope) SetFailureDomain(id string, spec clusterv1.FailureDomainS
 	s.AzureCluster.Status.FailureDomains[id] = spec
 }
 
+// CordonImpactedZones returns whether the availability zones impacted by an Azure service health event are cordoned.
+func (s *ClusterScope) CordonImpactedZones() bool {
+	return s.cordonZones
+}
+
+// CordonedZones returns the availability zones of the cluster which are cordoned.
+func (s *ClusterScope) CordonedZones() []infrav1.CordonedZone {
+	return s.AzureCluster.Status.CordonedZones
+}
+
+// SetCordonedZones sets the availability zones of the cluster which are cordoned.
+func (s *ClusterScope) SetCordonedZones(zones []infrav1.CordonedZone) {
+	s.AzureCluster.Status.CordonedZones = zones
+}
+
+// CordonedZoneNames returns the names of the availability zones of the cluster which are cordoned.
+func (s *ClusterScope) CordonedZoneNames() []string {
+	var zones []string
+	for _, cordoned := range s.AzureCluster.Status.CordonedZones {
+		zones = append(zones, cordoned.Zone)
+	}
+	return zones
+}
+
+// ZonalMachineDeployments returns the names of the MachineDeployments of the cluster which aren't being deleted, keyed
+// by the failure domain their machines are placed in.
+func (s *ClusterScope) ZonalMachineDeployments(ctx context.Context) (map[string][]string, error) {
+	mds := &clusterv1.MachineDeploymentList{}
+	if err := s.Client.List(ctx, mds, client.InNamespace(s.Namespace()), client.MatchingLabels{clusterv1.ClusterLabelName: s.ClusterName()}); err != nil {
+		return nil, errors.Wrap(err, "failed to list MachineDeployments")
+	}
+
+	zonal := map[string][]string{}
+	for _, md := range mds.Items {
+		if !md.DeletionTimestamp.IsZero() || md.Spec.Template.Spec.FailureDomain == nil {
+			continue
+		}
+		zone := *md.Spec.Template.Spec.FailureDomain
+		zonal[zone] = append(zonal[zone], md.Name)
+	}
+	return zonal, nil
+}
+
+// IsZoneCordoned returns whether new machines must not be placed in the availability zone.
+func (s *ClusterScope) IsZoneCordoned(zone string) bool {
+	for _, cordoned := range s.AzureCluster.Status.CordonedZones {
+		if cordoned.Zone == zone {
+			return true
+		}
+	}
+	return false
+}
+
+// NICPoolsEnabled returns whether the machines of the MachineDeployments can use network interface pools.
+func (s *ClusterScope) NICPoolsEnabled() bool {
+	return feature.Gates.Enabled(feature.NICPool)
+}
+
+// ActiveNICPools returns the names of the network interface pools in use, those of the MachineDeployments of the
+// cluster which aren't being deleted and whose AzureMachineTemplate sets a pool size.
+func (s *ClusterScope) ActiveNICPools(ctx context.Context) (map[string]bool, error) {
+	mds := &clusterv1.MachineDeploymentList{}
+	if err := s.Client.List(ctx, mds, client.InNamespace(s.Namespace()), client.MatchingLabels{clusterv1.ClusterLabelName: s.ClusterName()}); err != nil {
+		return nil, errors.Wrap(err, "failed to list MachineDeployments")
+	}
+
+	active := map[string]bool{}
+	for _, md := range mds.Items {
+		ref := md.Spec.Template.Spec.InfrastructureRef
+		if !md.DeletionTimestamp.IsZero() || ref.Kind != "AzureMachineTemplate" {
+			continue
+		}
+		template := &infrav1.AzureMachineTemplate{}
+		err := s.Client.Get(ctx, client.ObjectKey{Namespace: md.Namespace, Name: ref.Name}, template)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get AzureMachineTemplate %s", ref.Name)
+		}
+		if size, ok := nicPoolSize(template.Spec.Template.ObjectMeta.Annotations); ok && size > 0 {
+			active[md.Name] = true
+		}
+	}
+	return active, nil
+}
+
 // FailureDomains returns the failure domains for the cluster.
 func (s *ClusterScope) FailureDomains() []string {
 	fds := make([]string, len(s.AzureCluster.Status.FailureDomains))
@@ -661,39 +1601,6 @@ func (s *ClusterScope) FailureDomains() []string {
 	return fds
 }
 
-// SetControlPlaneSecurityRules sets the default security rules of the control plane subnet.
-// Note that this is not done in a webhook as it requires a valid Cluster object to exist to get the API Server port.
-func (s *ClusterScope) SetControlPlaneSecurityRules() {
-	if s.ControlPlaneSubnet().SecurityGroup.SecurityRules == nil {
-		subnet := s.ControlPlaneSubnet()
-		subnet.SecurityGroup.SecurityRules = infrav1.SecurityRules{
-			infrav1.SecurityRule{
-				Name:             "allow_ssh",
-				Description:      "Allow SSH",
-				Priority:         2200,
-				Protocol:         infrav1.SecurityGroupProtocolTCP,
-				Direction:        infrav1.SecurityRuleDirectionInbound,
-				Source:           to.StringPtr("*"),
-				SourcePorts:      to.StringPtr("*"),
-				Destination:      to.StringPtr("*"),
-				DestinationPorts: to.StringPtr("22"),
-			},
-			infrav1.SecurityRule{
-				Name:             "allow_apiserver",
-				Description:      "Allow K8s API Server",
-				Priority:         2201,
-				Protocol:         infrav1.SecurityGroupProtocolTCP,
-				Direction:        infrav1.SecurityRuleDirectionInbound,
-				Source:           to.StringPtr("*"),
-				SourcePorts:      to.StringPtr("*"),
-				Destination:      to.StringPtr("*"),
-				DestinationPorts: to.StringPtr(strconv.Itoa(int(s.APIServerPort()))),
-			},
-		}
-		s.AzureCluster.Spec.NetworkSpec.UpdateControlPlaneSubnet(subnet)
-	}
-}
-
 // SetDNSName sets the API Server public IP DNS name.
 // Note: this logic exists only for purposes of ensuring backwards compatibility for old clusters created without an APIServerLB, and should be removed in the future.
 func (s *ClusterScope) SetDNSName() {
@@ -733,12 +1640,14 @@ func (s *ClusterScope) getOutboundLBPublicIPSpecs(outboundLB *infrav1.LoadBalanc
 		// do nothing
 	} else if *loadBalancerNodeOutboundIPs == 1 {
 		outboundIPSpecs = append(outboundIPSpecs, azure.PublicIPSpec{
-			Name: generateOutboundIPName(s.ClusterName()),
+			Name:           generateOutboundIPName(s.ClusterName()),
+			RetainOnDelete: s.RetainPublicIPsOnDelete(),
 		})
 	} else {
 		for i := 0; i < int(*loadBalancerNodeOutboundIPs); i++ {
 			outboundIPSpecs = append(outboundIPSpecs, azure.PublicIPSpec{
-				Name: azure.WithIndex(generateOutboundIPName(s.ClusterName()), i+1),
+				Name:           azure.WithIndex(generateOutboundIPName(s.ClusterName()), i+1),
+				RetainOnDelete: s.RetainPublicIPsOnDelete(),
 			})
 		}
 	}
@@ -849,3 +1758,61 @@ func (s *ClusterScope) TagsSpecs() []azure.TagsSpec {
 		},
 	}
 }
+
+// OwnershipSpecs returns the specs of the resources whose ownership tag is verified for the AzureCluster.
+// The virtual network is recorded as owned once its tags were observed in a previous reconcile.
+func (s *ClusterScope) OwnershipSpecs() []azure.OwnershipSpec {
+	return []azure.OwnershipSpec{
+		{
+			Name:  s.ResourceGroup(),
+			Scope: azure.ResourceGroupID(s.SubscriptionID(), s.ResourceGroup()),
+		},
+		{
+			Name:  s.Vnet().Name,
+			Scope: azure.VNetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name),
+			Owned: s.Vnet().ID != "" && s.Vnet().Tags.HasOwned(s.ClusterName()),
+		},
+	}
+}
+
+// SetResourceOwnershipCondition reports the resources which look like they were created for the cluster but are not
+// tagged as owned by it, and would therefore be left behind when the cluster is deleted.
+func (s *ClusterScope) SetResourceOwnershipCondition(unowned []string) {
+	if len(unowned) > 0 {
+		conditions.MarkFalse(s.AzureCluster, infrav1.ResourceOwnershipVerifiedCondition, infrav1.OwnershipTagMissingReason,
+			clusterv1.ConditionSeverityWarning, "%s not tagged as owned by the cluster and won't be deleted with it", strings.Join(unowned, ", "))
+		return
+	}
+	conditions.MarkTrue(s.AzureCluster, infrav1.ResourceOwnershipVerifiedCondition)
+}
+
+// TemplateExportRequested returns true if the export annotation of the AzureCluster changed since the ARM template
+// of the cluster was last exported.
+func (s *ClusterScope) TemplateExportRequested() bool {
+	request, ok := s.AzureCluster.GetAnnotations()[infrav1.ExportTemplateAnnotation]
+	return ok && request != s.AzureCluster.GetAnnotations()[infrav1.TemplateExportedAnnotation]
+}
+
+// SetExportedTemplate stores the exported ARM template of the cluster in a ConfigMap owned by the AzureCluster, and
+// records the export request it answers.
+func (s *ClusterScope) SetExportedTemplate(ctx context.Context, template []byte) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      azure.GenerateExportedTemplateName(s.ClusterName()),
+			Namespace: s.Namespace(),
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, s.Client, configMap, func() error {
+		configMap.Labels = map[string]string{clusterv1.ClusterLabelName: s.ClusterName()}
+		configMap.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(s.AzureCluster, infrav1.GroupVersion.WithKind("AzureCluster")),
+		}
+		configMap.Data = map[string]string{exportedTemplateKey: string(template)}
+		return nil
+	}); err != nil {
+		return errors.Wrapf(err, "failed to write ConfigMap %s", configMap.Name)
+	}
+
+	s.SetAnnotation(infrav1.TemplateExportedAnnotation, s.AzureCluster.GetAnnotations()[infrav1.ExportTemplateAnnotation])
+	return nil
+}
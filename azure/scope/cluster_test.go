@@ -18,16 +18,25 @@ package scope
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/go-autorest/autorest/to"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -96,6 +105,23 @@ func TestAPIServerHost(t *testing.T) {
 			},
 			want: "apiserver.example.private",
 		},
+		{
+			name: "private apiserver (existing private dns zone)",
+			azureCluster: infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					SubscriptionID: fakeSubscriptionID,
+					NetworkSpec: infrav1.NetworkSpec{
+						PrivateDNSZone: &infrav1.PrivateDNSZoneSpec{
+							ID: "/subscriptions/456/resourceGroups/dns-rg/providers/Microsoft.Network/privateDnsZones/contoso.internal",
+						},
+						APIServerLB: infrav1.LoadBalancerSpec{
+							Type: infrav1.Internal,
+						},
+					},
+				},
+			},
+			want: "apiserver.my-cluster.contoso.internal",
+		},
 	}
 
 	for _, tc := range tests {
@@ -141,175 +167,375 @@ func TestAPIServerHost(t *testing.T) {
 	}
 }
 
-func TestGettingSecurityRules(t *testing.T) {
-	g := NewWithT(t)
-	scheme := runtime.NewScheme()
-	_ = clusterv1.AddToScheme(scheme)
-	_ = infrav1.AddToScheme(scheme)
+func TestPrivateDNSSpec(t *testing.T) {
+	zoneID := "/subscriptions/456/resourceGroups/dns-rg/providers/Microsoft.Network/privateDnsZones/contoso.internal"
 
-	cluster := &clusterv1.Cluster{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "my-cluster",
-			Namespace: "default",
+	tests := []struct {
+		name              string
+		privateDNSZone    *infrav1.PrivateDNSZoneSpec
+		records           []infrav1.PrivateDNSRecord
+		managementNetwork *infrav1.ManagementNetworkSpec
+		want              *azure.PrivateDNSSpec
+	}{
+		{
+			name: "private dns zone of the cluster",
+			want: &azure.PrivateDNSSpec{
+				ZoneName:       "my-cluster.capz.io",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				Links: []azure.PrivateDNSLinkSpec{
+					{
+						VNetName:          "my-vnet",
+						VNetResourceGroup: "my-rg",
+						LinkName:          "my-vnet-link",
+					},
+				},
+				Records: []infrav1.AddressRecord{
+					{
+						Hostname: "apiserver",
+						IP:       "10.0.0.100",
+					},
+				},
+			},
 		},
-	}
-	cluster.Default()
-
-	azureCluster := &infrav1.AzureCluster{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "my-azure-cluster",
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: "cluster.x-k8s.io/v1beta1",
-					Kind:       "Cluster",
-					Name:       "my-cluster",
+		{
+			name:           "existing private dns zone",
+			privateDNSZone: &infrav1.PrivateDNSZoneSpec{ID: zoneID},
+			want: &azure.PrivateDNSSpec{
+				ZoneName:       "contoso.internal",
+				ResourceGroup:  "dns-rg",
+				SubscriptionID: "456",
+				ExistingZone:   true,
+				Links: []azure.PrivateDNSLinkSpec{
+					{
+						VNetName:          "my-vnet",
+						VNetResourceGroup: "my-rg",
+						LinkName:          "my-vnet-link",
+					},
+				},
+				Records: []infrav1.AddressRecord{
+					{
+						Hostname: "apiserver.my-cluster",
+						IP:       "10.0.0.100",
+					},
 				},
 			},
 		},
-		Spec: infrav1.AzureClusterSpec{
-			SubscriptionID: "123",
-			NetworkSpec: infrav1.NetworkSpec{
-				Subnets: infrav1.Subnets{
+		{
+			name: "additional records",
+			records: []infrav1.PrivateDNSRecord{
+				{Hostname: "registry", IP: "10.0.3.4"},
+				{Hostname: "storage", CNAME: "mystorageaccount.privatelink.blob.core.windows.net"},
+			},
+			want: &azure.PrivateDNSSpec{
+				ZoneName:       "my-cluster.capz.io",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				Links: []azure.PrivateDNSLinkSpec{
 					{
-						Name: "node",
-						Role: infrav1.SubnetNode,
+						VNetName:          "my-vnet",
+						VNetResourceGroup: "my-rg",
+						LinkName:          "my-vnet-link",
+					},
+				},
+				Records: []infrav1.AddressRecord{
+					{
+						Hostname: "apiserver",
+						IP:       "10.0.0.100",
+					},
+					{
+						Hostname: "registry",
+						IP:       "10.0.3.4",
+					},
+				},
+				CNAMERecords: []azure.PrivateDNSCNAMERecordSpec{
+					{
+						Hostname: "storage",
+						CNAME:    "mystorageaccount.privatelink.blob.core.windows.net",
+					},
+				},
+			},
+		},
+		{
+			name: "management virtual network",
+			managementNetwork: &infrav1.ManagementNetworkSpec{
+				CIDRBlocks: []string{"192.168.0.0/16"},
+				Vnet:       &infrav1.VnetPeeringSpec{ResourceGroup: "mgmt-rg", RemoteVnetName: "mgmt-vnet"},
+			},
+			want: &azure.PrivateDNSSpec{
+				ZoneName:       "my-cluster.capz.io",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				Links: []azure.PrivateDNSLinkSpec{
+					{
+						VNetName:          "my-vnet",
+						VNetResourceGroup: "my-rg",
+						LinkName:          "my-vnet-link",
+					},
+					{
+						VNetName:          "mgmt-vnet",
+						VNetResourceGroup: "mgmt-rg",
+						LinkName:          "mgmt-vnet-link",
+					},
+				},
+				Records: []infrav1.AddressRecord{
+					{
+						Hostname: "apiserver",
+						IP:       "10.0.0.100",
+					},
+				},
+			},
+		},
+		{
+			name:           "existing private dns zone without virtual network links",
+			privateDNSZone: &infrav1.PrivateDNSZoneSpec{ID: zoneID, SkipVirtualNetworkLinks: true},
+			want: &azure.PrivateDNSSpec{
+				ZoneName:       "contoso.internal",
+				ResourceGroup:  "dns-rg",
+				SubscriptionID: "456",
+				ExistingZone:   true,
+				Records: []infrav1.AddressRecord{
+					{
+						Hostname: "apiserver.my-cluster",
+						IP:       "10.0.0.100",
 					},
 				},
 			},
 		},
 	}
-	azureCluster.Default()
 
-	initObjects := []runtime.Object{cluster, azureCluster}
-	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = clusterv1.AddToScheme(scheme)
+			_ = infrav1.AddToScheme(scheme)
 
-	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-		AzureClients: AzureClients{
-			Authorizer: autorest.NullAuthorizer{},
-		},
-		Cluster:      cluster,
-		AzureCluster: azureCluster,
-		Client:       fakeClient,
-	})
-	g.Expect(err).NotTo(HaveOccurred())
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+			}
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+				Spec: infrav1.AzureClusterSpec{
+					SubscriptionID: "123",
+					ResourceGroup:  "my-rg",
+					NetworkSpec: infrav1.NetworkSpec{
+						Vnet: infrav1.VnetSpec{
+							Name:          "my-vnet",
+							ResourceGroup: "my-rg",
+						},
+						PrivateDNSZone:    tc.privateDNSZone,
+						PrivateDNSRecords: tc.records,
+						ManagementNetwork: tc.managementNetwork,
+						APIServerLB: infrav1.LoadBalancerSpec{
+							Type: infrav1.Internal,
+							FrontendIPs: []infrav1.FrontendIP{
+								{
+									Name:             "my-frontend",
+									PrivateIPAddress: "10.0.0.100",
+								},
+							},
+						},
+					},
+				},
+			}
 
-	clusterScope.SetControlPlaneSecurityRules()
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster, azureCluster).Build()
+			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+				AzureClients: AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+				Client:       fakeClient,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
 
-	subnet, err := clusterScope.AzureCluster.Spec.NetworkSpec.GetControlPlaneSubnet()
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(len(subnet.SecurityGroup.SecurityRules)).To(Equal(2))
+			g.Expect(clusterScope.PrivateDNSSpec()).To(Equal(tc.want))
+		})
+	}
 }
 
-func TestOutboundLBName(t *testing.T) {
+func TestPublicDNSSpec(t *testing.T) {
+	zoneID := "/subscriptions/456/resourceGroups/dns-rg/providers/Microsoft.Network/dnszones/example.com"
+
 	tests := []struct {
-		clusterName            string
-		name                   string
-		role                   string
-		apiServerLB            *infrav1.LoadBalancerSpec
-		controlPlaneOutboundLB *infrav1.LoadBalancerSpec
-		nodeOutboundLB         *infrav1.LoadBalancerSpec
-		expected               string
+		name        string
+		record      *infrav1.PublicDNSRecordSpec
+		apiServerLB infrav1.LoadBalancerSpec
+		want        *azure.PublicDNSSpec
 	}{
 		{
-			clusterName: "my-cluster",
-			name:        "public cluster node outbound lb",
-			role:        "node",
-			expected:    "my-cluster",
+			name:        "no public dns record",
+			apiServerLB: infrav1.LoadBalancerSpec{Type: infrav1.Public},
+			want:        nil,
 		},
 		{
-			clusterName: "my-cluster",
-			name:        "public cluster control plane outbound lb",
-			role:        "control-plane",
-			expected:    "my-cluster-public-lb",
+			name: "cname record",
+			record: &infrav1.PublicDNSRecordSpec{
+				ZoneID: zoneID,
+				Name:   "api.cluster1",
+				Type:   infrav1.PublicDNSRecordTypeCNAME,
+				TTL:    to.Int64Ptr(300),
+			},
+			apiServerLB: infrav1.LoadBalancerSpec{Type: infrav1.Public},
+			want: &azure.PublicDNSSpec{
+				ZoneName:       "example.com",
+				ResourceGroup:  "dns-rg",
+				SubscriptionID: "456",
+				RecordName:     "api.cluster1",
+				RecordType:     infrav1.PublicDNSRecordTypeCNAME,
+				TTL:            300,
+				CNAME:          "my-cluster-api.eastus.cloudapp.azure.com",
+				PublicIPID:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-api",
+			},
 		},
 		{
-			clusterName:    "my-cluster",
-			name:           "private cluster with node outbound lb",
-			role:           "node",
-			nodeOutboundLB: &infrav1.LoadBalancerSpec{},
-			apiServerLB:    &infrav1.LoadBalancerSpec{Type: "Internal"},
-			expected:       "my-cluster",
+			name: "internal API server load balancer",
+			record: &infrav1.PublicDNSRecordSpec{
+				ZoneID: zoneID,
+				Name:   "api.cluster1",
+				Type:   infrav1.PublicDNSRecordTypeA,
+				TTL:    to.Int64Ptr(300),
+			},
+			apiServerLB: infrav1.LoadBalancerSpec{Type: infrav1.Internal},
+			want:        nil,
 		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = clusterv1.AddToScheme(scheme)
+			_ = infrav1.AddToScheme(scheme)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+			}
+			tc.apiServerLB.FrontendIPs = []infrav1.FrontendIP{
+				{
+					Name: "my-frontend",
+					PublicIP: &infrav1.PublicIPSpec{
+						Name:    "pip-my-cluster-api",
+						DNSName: "my-cluster-api.eastus.cloudapp.azure.com",
+					},
+				},
+			}
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+				Spec: infrav1.AzureClusterSpec{
+					SubscriptionID: "123",
+					ResourceGroup:  "my-rg",
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerLB:        tc.apiServerLB,
+						APIServerPublicDNS: tc.record,
+					},
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster, azureCluster).Build()
+			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+				AzureClients: AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+				Client:       fakeClient,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(clusterScope.PublicDNSSpec()).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestOwnershipSpecs(t *testing.T) {
+	tests := []struct {
+		name string
+		vnet infrav1.VnetSpec
+		want []azure.OwnershipSpec
+	}{
 		{
-			clusterName: "my-cluster",
-			name:        "private cluster without node outbound lb",
-			role:        "node",
-			apiServerLB: &infrav1.LoadBalancerSpec{Type: "Internal"},
-			expected:    "",
+			name: "virtual network not observed yet",
+			vnet: infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "my-vnet"},
+			want: []azure.OwnershipSpec{
+				{Name: "my-rg", Scope: "/subscriptions/123/resourceGroups/my-rg"},
+				{Name: "my-vnet", Scope: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"},
+			},
 		},
 		{
-			clusterName:            "my-cluster",
-			name:                   "private cluster with control plane outbound lb",
-			role:                   "control-plane",
-			controlPlaneOutboundLB: &infrav1.LoadBalancerSpec{Name: "cp-outbound-lb"},
-			apiServerLB:            &infrav1.LoadBalancerSpec{Type: "Internal"},
-			expected:               "cp-outbound-lb",
+			name: "virtual network recorded as owned",
+			vnet: infrav1.VnetSpec{
+				ResourceGroup: "my-rg",
+				Name:          "my-vnet",
+				ID:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+				Tags: infrav1.Tags{
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+				},
+			},
+			want: []azure.OwnershipSpec{
+				{Name: "my-rg", Scope: "/subscriptions/123/resourceGroups/my-rg"},
+				{Name: "my-vnet", Scope: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", Owned: true},
+			},
 		},
 		{
-			clusterName: "my-cluster",
-			name:        "private cluster without control plane outbound lb",
-			role:        "control-plane",
-			apiServerLB: &infrav1.LoadBalancerSpec{Type: "Internal"},
-			expected:    "",
+			name: "custom virtual network",
+			vnet: infrav1.VnetSpec{
+				ResourceGroup: "network-rg",
+				Name:          "custom-vnet",
+				ID:            "/subscriptions/123/resourceGroups/network-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet",
+			},
+			want: []azure.OwnershipSpec{
+				{Name: "my-rg", Scope: "/subscriptions/123/resourceGroups/my-rg"},
+				{Name: "custom-vnet", Scope: "/subscriptions/123/resourceGroups/network-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet"},
+			},
 		},
 	}
 
 	for _, tc := range tests {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			g := NewWithT(t)
 			scheme := runtime.NewScheme()
-			_ = infrav1.AddToScheme(scheme)
 			_ = clusterv1.AddToScheme(scheme)
+			_ = infrav1.AddToScheme(scheme)
 
 			cluster := &clusterv1.Cluster{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      tc.clusterName,
+					Name:      "my-cluster",
 					Namespace: "default",
 				},
 			}
-			cluster.Default()
-
 			azureCluster := &infrav1.AzureCluster{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: tc.clusterName,
-					OwnerReferences: []metav1.OwnerReference{
-						{
-							APIVersion: "cluster.x-k8s.io/v1beta1",
-							Kind:       "Cluster",
-							Name:       "my-cluster",
-						},
-					},
+					Name:      "my-cluster",
+					Namespace: "default",
 				},
 				Spec: infrav1.AzureClusterSpec{
 					SubscriptionID: "123",
+					ResourceGroup:  "my-rg",
 					NetworkSpec: infrav1.NetworkSpec{
-						Subnets: infrav1.Subnets{
-							{
-								Name: "node",
-								Role: infrav1.SubnetNode,
-							},
-						},
+						Vnet: tc.vnet,
 					},
 				},
 			}
 
-			if tc.apiServerLB != nil {
-				azureCluster.Spec.NetworkSpec.APIServerLB = *tc.apiServerLB
-			}
-
-			if tc.controlPlaneOutboundLB != nil {
-				azureCluster.Spec.NetworkSpec.ControlPlaneOutboundLB = tc.controlPlaneOutboundLB
-			}
-
-			if tc.nodeOutboundLB != nil {
-				azureCluster.Spec.NetworkSpec.NodeOutboundLB = tc.nodeOutboundLB
-			}
-
-			azureCluster.Default()
-
-			initObjects := []runtime.Object{cluster, azureCluster}
-			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
-
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster, azureCluster).Build()
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
 				AzureClients: AzureClients{
 					Authorizer: autorest.NullAuthorizer{},
@@ -319,8 +545,1262 @@ func TestOutboundLBName(t *testing.T) {
 				Client:       fakeClient,
 			})
 			g.Expect(err).NotTo(HaveOccurred())
-			got := clusterScope.OutboundLBName(tc.role)
-			g.Expect(tc.expected).Should(Equal(got))
+
+			g.Expect(clusterScope.OwnershipSpecs()).To(Equal(tc.want))
 		})
 	}
 }
+
+func TestSNATMetricsSpecs(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		network infrav1.NetworkSpec
+		want    []azure.SNATMetricsSpec
+	}{
+		{
+			name:    "feature gate disabled",
+			enabled: false,
+			network: infrav1.NetworkSpec{
+				APIServerLB:    infrav1.LoadBalancerSpec{Name: "my-cluster-public-lb", Type: infrav1.Public},
+				NodeOutboundLB: &infrav1.LoadBalancerSpec{Name: "my-cluster"},
+			},
+			want: nil,
+		},
+		{
+			name:    "public API server and node outbound load balancers",
+			enabled: true,
+			network: infrav1.NetworkSpec{
+				APIServerLB:    infrav1.LoadBalancerSpec{Name: "my-cluster-public-lb", Type: infrav1.Public},
+				NodeOutboundLB: &infrav1.LoadBalancerSpec{Name: "my-cluster"},
+			},
+			want: []azure.SNATMetricsSpec{
+				{Name: "my-cluster-public-lb", ResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster-public-lb"},
+				{Name: "my-cluster", ResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster"},
+			},
+		},
+		{
+			name:    "private API server with a control plane outbound load balancer and a NAT gateway",
+			enabled: true,
+			network: infrav1.NetworkSpec{
+				APIServerLB:            infrav1.LoadBalancerSpec{Name: "my-cluster-internal-lb", Type: infrav1.Internal},
+				ControlPlaneOutboundLB: &infrav1.LoadBalancerSpec{Name: "my-cluster-outbound-lb"},
+				Subnets: infrav1.Subnets{
+					{
+						Role: infrav1.SubnetNode,
+						Name: "node-subnet",
+						NatGateway: infrav1.NatGateway{
+							Name: "node-natgw",
+							NatGatewayIP: infrav1.PublicIPSpec{
+								Name: "node-natgw-ip",
+							},
+						},
+					},
+				},
+			},
+			want: []azure.SNATMetricsSpec{
+				{Name: "my-cluster-outbound-lb", ResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster-outbound-lb"},
+				{Name: "node-natgw", ResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/node-natgw", NATGateway: true},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, feature.SNATMetrics, tc.enabled)()
+
+			clusterScope := &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: auth.EnvironmentSettings{
+						Values: map[string]string{auth.SubscriptionID: "123"},
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						NetworkSpec:   tc.network,
+					},
+				},
+			}
+
+			g.Expect(clusterScope.SNATMetricsSpecs()).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestAlertRuleSpecs(t *testing.T) {
+	actionGroupID := "/subscriptions/123/resourceGroups/ops/providers/Microsoft.Insights/actionGroups/oncall"
+	vmAvailability := azure.AlertRuleSpec{
+		Name:               "my-cluster-vm-availability",
+		Description:        "A virtual machine of cluster my-cluster is unavailable",
+		Severity:           2,
+		Scopes:             []string{"/subscriptions/123/resourceGroups/my-rg"},
+		TargetResourceType: "Microsoft.Compute/virtualMachines",
+		MetricName:         "VmAvailabilityMetric",
+		Aggregation:        "Average",
+		Operator:           "LessThan",
+		Threshold:          1,
+		ActionGroupID:      actionGroupID,
+	}
+	healthProbe := azure.AlertRuleSpec{
+		Name:               "my-cluster-public-lb-health-probe",
+		Description:        "The health probe of the API server load balancer of cluster my-cluster fails for a control plane node",
+		Severity:           2,
+		Scopes:             []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster-public-lb"},
+		TargetResourceType: "Microsoft.Network/loadBalancers",
+		MetricName:         "DipAvailability",
+		Aggregation:        "Average",
+		Operator:           "LessThan",
+		Threshold:          100,
+		ActionGroupID:      actionGroupID,
+	}
+	natGatewaySNAT := azure.AlertRuleSpec{
+		Name:               "node-natgw-snat-failed",
+		Description:        "SNAT connections fail on NAT gateway node-natgw of cluster my-cluster",
+		Severity:           0,
+		Scopes:             []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/node-natgw"},
+		TargetResourceType: "Microsoft.Network/natGateways",
+		MetricName:         "SNATConnectionCount",
+		Aggregation:        "Total",
+		Operator:           "GreaterThan",
+		Threshold:          0,
+		DimensionName:      "ConnectionState",
+		DimensionValues:    []string{"Failed"},
+		ActionGroupID:      actionGroupID,
+	}
+	network := infrav1.NetworkSpec{
+		APIServerLB: infrav1.LoadBalancerSpec{Name: "my-cluster-public-lb", Type: infrav1.Public},
+		Subnets: infrav1.Subnets{
+			{
+				Role: infrav1.SubnetNode,
+				Name: "node-subnet",
+				NatGateway: infrav1.NatGateway{
+					Name: "node-natgw",
+					NatGatewayIP: infrav1.PublicIPSpec{
+						Name: "node-natgw-ip",
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		alerts *infrav1.AlertsSpec
+		want   []azure.AlertRuleSpec
+	}{
+		{
+			name:   "no alerts",
+			alerts: nil,
+			want:   nil,
+		},
+		{
+			name: "VM availability and health probe alert rules",
+			alerts: &infrav1.AlertsSpec{
+				ActionGroupID: actionGroupID,
+				Rules:         []infrav1.AlertRuleType{infrav1.AlertRuleVMAvailability, infrav1.AlertRuleLoadBalancerHealthProbe},
+				Severity:      to.Int32Ptr(2),
+			},
+			want: []azure.AlertRuleSpec{vmAvailability, healthProbe},
+		},
+		{
+			name: "NAT gateway alert rules",
+			alerts: &infrav1.AlertsSpec{
+				ActionGroupID: actionGroupID,
+				Rules:         []infrav1.AlertRuleType{infrav1.AlertRuleNATGatewaySNAT},
+				Severity:      to.Int32Ptr(0),
+			},
+			want: []azure.AlertRuleSpec{natGatewaySNAT},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterScope := &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: auth.EnvironmentSettings{
+						Values: map[string]string{auth.SubscriptionID: "123"},
+					},
+				},
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						NetworkSpec:   network,
+						Alerts:        tc.alerts,
+					},
+				},
+			}
+
+			g.Expect(clusterScope.AlertRuleSpecs()).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestNSGSpecs(t *testing.T) {
+	allowSSH := infrav1.SecurityRule{
+		Name:             "allow_ssh",
+		Description:      "Allow SSH",
+		Priority:         2200,
+		Protocol:         infrav1.SecurityGroupProtocolTCP,
+		Direction:        infrav1.SecurityRuleDirectionInbound,
+		Source:           to.StringPtr("*"),
+		SourcePorts:      to.StringPtr("*"),
+		Destination:      to.StringPtr("*"),
+		DestinationPorts: to.StringPtr("22"),
+	}
+	allowAPIServer := infrav1.SecurityRule{
+		Name:             "allow_apiserver",
+		Description:      "Allow K8s API Server",
+		Priority:         2201,
+		Protocol:         infrav1.SecurityGroupProtocolTCP,
+		Direction:        infrav1.SecurityRuleDirectionInbound,
+		Source:           to.StringPtr("*"),
+		SourcePorts:      to.StringPtr("*"),
+		Destination:      to.StringPtr("*"),
+		DestinationPorts: to.StringPtr("6443"),
+	}
+	customRule := infrav1.SecurityRule{
+		Name:             "allow_port_50000",
+		Description:      "allow port 50000",
+		Priority:         2200,
+		Protocol:         infrav1.SecurityGroupProtocolTCP,
+		Direction:        infrav1.SecurityRuleDirectionInbound,
+		Source:           to.StringPtr("*"),
+		SourcePorts:      to.StringPtr("*"),
+		Destination:      to.StringPtr("*"),
+		DestinationPorts: to.StringPtr("50000"),
+	}
+	restrictedSSH := allowSSH
+	restrictedSSH.Source = to.StringPtr("10.0.0.0/8")
+	allowManagementAPIServer := func(index int, cidr string) infrav1.SecurityRule {
+		return infrav1.SecurityRule{
+			Name:             fmt.Sprintf("allow_management_apiserver_%d", index),
+			Description:      "Allow K8s API Server from the management cluster",
+			Priority:         2202 + int32(index),
+			Protocol:         infrav1.SecurityGroupProtocolTCP,
+			Direction:        infrav1.SecurityRuleDirectionInbound,
+			Source:           to.StringPtr(cidr),
+			SourcePorts:      to.StringPtr("*"),
+			Destination:      to.StringPtr("*"),
+			DestinationPorts: to.StringPtr("6443"),
+		}
+	}
+	allowAPIServerFrom := func(index int, cidr string) infrav1.SecurityRule {
+		return infrav1.SecurityRule{
+			Name:             fmt.Sprintf("allow_apiserver_%d", index),
+			Description:      "Allow K8s API Server from an allowed CIDR block",
+			Priority:         2201 + int32(index),
+			Protocol:         infrav1.SecurityGroupProtocolTCP,
+			Direction:        infrav1.SecurityRuleDirectionInbound,
+			Source:           to.StringPtr(cidr),
+			SourcePorts:      to.StringPtr("*"),
+			Destination:      to.StringPtr("*"),
+			DestinationPorts: to.StringPtr("6443"),
+		}
+	}
+	allowConnectivityIngress := func(name string, index int, cidr string, port string, priority int32) infrav1.SecurityRule {
+		return infrav1.SecurityRule{
+			Name:             fmt.Sprintf("allow_connectivity_ingress_%s_%d", name, index),
+			Description:      "Allow the ingress port of a management agent",
+			Priority:         priority,
+			Protocol:         infrav1.SecurityGroupProtocolTCP,
+			Direction:        infrav1.SecurityRuleDirectionInbound,
+			Source:           to.StringPtr(cidr),
+			SourcePorts:      to.StringPtr("*"),
+			Destination:      to.StringPtr("*"),
+			DestinationPorts: to.StringPtr(port),
+		}
+	}
+	allowConnectivityEgress := func(name string, index int, cidr string, port string, priority int32) infrav1.SecurityRule {
+		return infrav1.SecurityRule{
+			Name:             fmt.Sprintf("allow_connectivity_egress_%s_%d", name, index),
+			Description:      "Allow the egress port of a management service",
+			Priority:         priority,
+			Protocol:         infrav1.SecurityGroupProtocolTCP,
+			Direction:        infrav1.SecurityRuleDirectionOutbound,
+			Source:           to.StringPtr("*"),
+			SourcePorts:      to.StringPtr("*"),
+			Destination:      to.StringPtr(cidr),
+			DestinationPorts: to.StringPtr(port),
+		}
+	}
+
+	withPriority := func(rule infrav1.SecurityRule, priority int32) infrav1.SecurityRule {
+		rule.Priority = priority
+		return rule
+	}
+
+	tests := []struct {
+		name              string
+		securityGroup     infrav1.SecurityGroup
+		managementNetwork *infrav1.ManagementNetworkSpec
+		accessProfile     *infrav1.APIServerAccessProfile
+		connectivity      *infrav1.ConnectivityProfile
+		want              infrav1.SecurityRules
+	}{
+		{
+			name:          "default rules are added to the control plane security group",
+			securityGroup: infrav1.SecurityGroup{Name: "cp-nsg"},
+			want:          infrav1.SecurityRules{allowSSH, allowAPIServer},
+		},
+		{
+			name: "default rules are added after the custom rules and moved out of their priorities",
+			securityGroup: infrav1.SecurityGroup{
+				Name:          "cp-nsg",
+				SecurityRules: infrav1.SecurityRules{customRule},
+			},
+			want: infrav1.SecurityRules{customRule, withPriority(allowSSH, 2201), withPriority(allowAPIServer, 2202)},
+		},
+		{
+			name: "custom rule with the name of a default rule overrides it",
+			securityGroup: infrav1.SecurityGroup{
+				Name:          "cp-nsg",
+				SecurityRules: infrav1.SecurityRules{restrictedSSH},
+			},
+			want: infrav1.SecurityRules{restrictedSSH, allowAPIServer},
+		},
+		{
+			name: "disabled default rules are not added",
+			securityGroup: infrav1.SecurityGroup{
+				Name:                 "cp-nsg",
+				DisabledDefaultRules: []string{infrav1.SecurityRuleAllowSSH},
+			},
+			want: infrav1.SecurityRules{allowAPIServer},
+		},
+		{
+			name: "management network is allowed to reach the API server",
+			securityGroup: infrav1.SecurityGroup{
+				Name:                 "cp-nsg",
+				DisabledDefaultRules: []string{infrav1.SecurityRuleAllowAPIServer},
+			},
+			managementNetwork: &infrav1.ManagementNetworkSpec{CIDRBlocks: []string{"192.168.0.0/16", "172.16.0.0/12"}},
+			want: infrav1.SecurityRules{
+				allowSSH,
+				allowManagementAPIServer(0, "192.168.0.0/16"),
+				allowManagementAPIServer(1, "172.16.0.0/12"),
+			},
+		},
+		{
+			name:          "API server is only reachable from the allowed CIDR blocks",
+			securityGroup: infrav1.SecurityGroup{Name: "cp-nsg"},
+			accessProfile: &infrav1.APIServerAccessProfile{AllowedCIDRs: []string{"203.0.113.0/24", "198.51.100.7/32"}},
+			want: infrav1.SecurityRules{
+				allowSSH,
+				allowAPIServerFrom(0, "203.0.113.0/24"),
+				allowAPIServerFrom(1, "198.51.100.7/32"),
+			},
+		},
+		{
+			name:              "allowed CIDR blocks are moved out of the priorities of the management network rules",
+			securityGroup:     infrav1.SecurityGroup{Name: "cp-nsg"},
+			managementNetwork: &infrav1.ManagementNetworkSpec{CIDRBlocks: []string{"192.168.0.0/16"}},
+			accessProfile:     &infrav1.APIServerAccessProfile{AllowedCIDRs: []string{"203.0.113.0/24", "198.51.100.7/32"}},
+			want: infrav1.SecurityRules{
+				allowSSH,
+				allowAPIServerFrom(0, "203.0.113.0/24"),
+				allowAPIServerFrom(1, "198.51.100.7/32"),
+				withPriority(allowManagementAPIServer(0, "192.168.0.0/16"), 2203),
+			},
+		},
+		{
+			name: "disabling the API server rule disables the rules of the allowed CIDR blocks",
+			securityGroup: infrav1.SecurityGroup{
+				Name:                 "cp-nsg",
+				DisabledDefaultRules: []string{infrav1.SecurityRuleAllowAPIServer},
+			},
+			accessProfile: &infrav1.APIServerAccessProfile{AllowedCIDRs: []string{"203.0.113.0/24", "198.51.100.7/32"}},
+			want:          infrav1.SecurityRules{allowSSH},
+		},
+		{
+			name:          "connectivity ports are allowed from and to any address",
+			securityGroup: infrav1.SecurityGroup{Name: "cp-nsg"},
+			connectivity: &infrav1.ConnectivityProfile{
+				Ingress: []infrav1.ConnectivityPort{{Name: "konnectivity", Port: 8132, Protocol: infrav1.SecurityGroupProtocolTCP}},
+				Egress:  []infrav1.ConnectivityPort{{Name: "metrics", Port: 9090, Protocol: infrav1.SecurityGroupProtocolTCP, CIDRBlocks: []string{"10.10.0.0/16"}}},
+			},
+			want: infrav1.SecurityRules{
+				allowSSH,
+				allowAPIServer,
+				allowConnectivityIngress("konnectivity", 0, "*", "8132", 2202),
+				allowConnectivityEgress("metrics", 0, "10.10.0.0/16", "9090", 2200),
+			},
+		},
+		{
+			name:              "connectivity ports default to the management network and are moved out of its priorities",
+			securityGroup:     infrav1.SecurityGroup{Name: "cp-nsg"},
+			managementNetwork: &infrav1.ManagementNetworkSpec{CIDRBlocks: []string{"192.168.0.0/16", "172.16.0.0/12"}},
+			connectivity: &infrav1.ConnectivityProfile{
+				Ingress: []infrav1.ConnectivityPort{{Name: "konnectivity", Port: 8132, Protocol: infrav1.SecurityGroupProtocolTCP}},
+				Egress:  []infrav1.ConnectivityPort{{Name: "metrics", Port: 9090, Protocol: infrav1.SecurityGroupProtocolTCP}},
+			},
+			want: infrav1.SecurityRules{
+				allowSSH,
+				allowAPIServer,
+				allowManagementAPIServer(0, "192.168.0.0/16"),
+				allowManagementAPIServer(1, "172.16.0.0/12"),
+				allowConnectivityIngress("konnectivity", 0, "192.168.0.0/16", "8132", 2204),
+				allowConnectivityIngress("konnectivity", 1, "172.16.0.0/12", "8132", 2205),
+				allowConnectivityEgress("metrics", 0, "192.168.0.0/16", "9090", 2200),
+				allowConnectivityEgress("metrics", 1, "172.16.0.0/12", "9090", 2201),
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = clusterv1.AddToScheme(scheme)
+			_ = infrav1.AddToScheme(scheme)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+			}
+			cluster.Default()
+
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-azure-cluster",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "cluster.x-k8s.io/v1beta1",
+							Kind:       "Cluster",
+							Name:       "my-cluster",
+						},
+					},
+				},
+				Spec: infrav1.AzureClusterSpec{
+					SubscriptionID: "123",
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{
+								Name:          "control-plane",
+								Role:          infrav1.SubnetControlPlane,
+								SecurityGroup: tc.securityGroup,
+							},
+							{
+								Name:          "node",
+								Role:          infrav1.SubnetNode,
+								SecurityGroup: infrav1.SecurityGroup{Name: "node-nsg"},
+							},
+						},
+						ManagementNetwork: tc.managementNetwork,
+					},
+					APIServerAccessProfile: tc.accessProfile,
+					ConnectivityProfile:    tc.connectivity,
+				},
+			}
+
+			initObjects := []runtime.Object{cluster, azureCluster}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+				AzureClients: AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+				Client:       fakeClient,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(clusterScope.NSGSpecs()).To(Equal([]azure.NSGSpec{
+				{Name: "cp-nsg", SecurityRules: tc.want},
+				{Name: "node-nsg"},
+			}))
+		})
+	}
+}
+
+func TestOutboundLBName(t *testing.T) {
+	tests := []struct {
+		clusterName            string
+		name                   string
+		role                   string
+		apiServerLB            *infrav1.LoadBalancerSpec
+		controlPlaneOutboundLB *infrav1.LoadBalancerSpec
+		nodeOutboundLB         *infrav1.LoadBalancerSpec
+		expected               string
+	}{
+		{
+			clusterName: "my-cluster",
+			name:        "public cluster node outbound lb",
+			role:        "node",
+			expected:    "my-cluster",
+		},
+		{
+			clusterName: "my-cluster",
+			name:        "public cluster control plane outbound lb",
+			role:        "control-plane",
+			expected:    "my-cluster-public-lb",
+		},
+		{
+			clusterName:    "my-cluster",
+			name:           "private cluster with node outbound lb",
+			role:           "node",
+			nodeOutboundLB: &infrav1.LoadBalancerSpec{},
+			apiServerLB:    &infrav1.LoadBalancerSpec{Type: "Internal"},
+			expected:       "my-cluster",
+		},
+		{
+			clusterName: "my-cluster",
+			name:        "private cluster without node outbound lb",
+			role:        "node",
+			apiServerLB: &infrav1.LoadBalancerSpec{Type: "Internal"},
+			expected:    "",
+		},
+		{
+			clusterName:            "my-cluster",
+			name:                   "private cluster with control plane outbound lb",
+			role:                   "control-plane",
+			controlPlaneOutboundLB: &infrav1.LoadBalancerSpec{Name: "cp-outbound-lb"},
+			apiServerLB:            &infrav1.LoadBalancerSpec{Type: "Internal"},
+			expected:               "cp-outbound-lb",
+		},
+		{
+			clusterName: "my-cluster",
+			name:        "private cluster without control plane outbound lb",
+			role:        "control-plane",
+			apiServerLB: &infrav1.LoadBalancerSpec{Type: "Internal"},
+			expected:    "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			_ = clusterv1.AddToScheme(scheme)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tc.clusterName,
+					Namespace: "default",
+				},
+			}
+			cluster.Default()
+
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: tc.clusterName,
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "cluster.x-k8s.io/v1beta1",
+							Kind:       "Cluster",
+							Name:       "my-cluster",
+						},
+					},
+				},
+				Spec: infrav1.AzureClusterSpec{
+					SubscriptionID: "123",
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{
+								Name: "node",
+								Role: infrav1.SubnetNode,
+							},
+						},
+					},
+				},
+			}
+
+			if tc.apiServerLB != nil {
+				azureCluster.Spec.NetworkSpec.APIServerLB = *tc.apiServerLB
+			}
+
+			if tc.controlPlaneOutboundLB != nil {
+				azureCluster.Spec.NetworkSpec.ControlPlaneOutboundLB = tc.controlPlaneOutboundLB
+			}
+
+			if tc.nodeOutboundLB != nil {
+				azureCluster.Spec.NetworkSpec.NodeOutboundLB = tc.nodeOutboundLB
+			}
+
+			azureCluster.Default()
+
+			initObjects := []runtime.Object{cluster, azureCluster}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+				AzureClients: AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+				Client:       fakeClient,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			got := clusterScope.OutboundLBName(tc.role)
+			g.Expect(tc.expected).Should(Equal(got))
+		})
+	}
+}
+
+func TestSetAPIVersionProfileCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		profile    *infrav1.APIVersionProfile
+		bastion    *infrav1.AzureBastion
+		apiServer  infrav1.LBType
+		wantStatus corev1.ConditionStatus
+		wantMsg    string
+	}{
+		{
+			name:    "no profile",
+			bastion: &infrav1.AzureBastion{},
+		},
+		{
+			name:       "latest profile",
+			profile:    &infrav1.APIVersionProfile{Name: infrav1.APIVersionProfileLatest},
+			bastion:    &infrav1.AzureBastion{},
+			apiServer:  infrav1.Internal,
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name:       "hybrid profile without optional features",
+			profile:    &infrav1.APIVersionProfile{Name: infrav1.APIVersionProfileHybrid20190301},
+			apiServer:  infrav1.Public,
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name:       "hybrid profile with bastion and private api server",
+			profile:    &infrav1.APIVersionProfile{Name: infrav1.APIVersionProfileHybrid20190301},
+			bastion:    &infrav1.AzureBastion{},
+			apiServer:  infrav1.Internal,
+			wantStatus: corev1.ConditionFalse,
+			wantMsg:    "the API versions of the profile don't support AzureBastion, PrivateDNS",
+		},
+		{
+			name: "hybrid profile with overrides",
+			profile: &infrav1.APIVersionProfile{
+				Name:      infrav1.APIVersionProfileHybrid20190301,
+				Overrides: map[string]string{"Microsoft.Network/bastionHosts": "2019-04-01"},
+			},
+			bastion:    &infrav1.AzureBastion{},
+			apiServer:  infrav1.Public,
+			wantStatus: corev1.ConditionTrue,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ClusterScope{
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						APIVersionProfile: tc.profile,
+						BastionSpec:       infrav1.BastionSpec{AzureBastion: tc.bastion},
+						NetworkSpec: infrav1.NetworkSpec{
+							APIServerLB: infrav1.LoadBalancerSpec{Type: tc.apiServer},
+						},
+					},
+				},
+			}
+
+			s.SetAPIVersionProfileCondition()
+			condition := conditions.Get(s.AzureCluster, infrav1.APIVersionProfileCompatibleCondition)
+			if tc.wantStatus == "" {
+				g.Expect(condition).To(BeNil())
+				return
+			}
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.wantStatus))
+			g.Expect(condition.Message).To(Equal(tc.wantMsg))
+		})
+	}
+}
+
+func TestForceDeleteVirtualMachines(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name              string
+		forceDelete       bool
+		deletionTimestamp *metav1.Time
+		want              bool
+	}{
+		{
+			name:              "not enabled",
+			forceDelete:       false,
+			deletionTimestamp: &now,
+			want:              false,
+		},
+		{
+			name:        "enabled but cluster not deleted",
+			forceDelete: true,
+			want:        false,
+		},
+		{
+			name:              "enabled and cluster deleted",
+			forceDelete:       true,
+			deletionTimestamp: &now,
+			want:              true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: tc.deletionTimestamp},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{ForceDeleteVirtualMachines: tc.forceDelete},
+				},
+			}
+			g.Expect(s.ForceDeleteVirtualMachines()).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestExpressRouteGateway(t *testing.T) {
+	gatewaySubnet := infrav1.SubnetSpec{Name: infrav1.ExpressRouteGatewaySubnetName, CIDRBlocks: []string{infrav1.DefaultExpressRouteGatewaySubnetCIDR}}
+	nodeSubnet := infrav1.SubnetSpec{Name: "node-subnet", Role: infrav1.SubnetNode, RouteTable: infrav1.RouteTable{Name: "node-routetable"}}
+	tests := []struct {
+		name                       string
+		gateway                    *infrav1.ExpressRouteGateway
+		wantSpec                   *azure.ExpressRouteGatewaySpec
+		wantSubnets                []string
+		disableBGPRoutePropagation bool
+	}{
+		{
+			name:        "no gateway",
+			wantSubnets: []string{"node-subnet"},
+		},
+		{
+			name:                       "existing gateway",
+			gateway:                    &infrav1.ExpressRouteGateway{ID: "/subscriptions/123/resourceGroups/hub/providers/Microsoft.Network/virtualNetworkGateways/my-ergw", DisableBGPRoutePropagation: true},
+			wantSubnets:                []string{"node-subnet"},
+			disableBGPRoutePropagation: true,
+		},
+		{
+			name: "created gateway",
+			gateway: &infrav1.ExpressRouteGateway{
+				Name:     "my-ergw",
+				Sku:      infrav1.ExpressRouteGatewaySkuStandard,
+				Subnet:   gatewaySubnet,
+				PublicIP: infrav1.PublicIPSpec{Name: "my-ergw-pip"},
+			},
+			wantSpec: &azure.ExpressRouteGatewaySpec{
+				Name:          "my-ergw",
+				ResourceGroup: "my-vnet-rg",
+				Sku:           infrav1.ExpressRouteGatewaySkuStandard,
+				VNetName:      "my-vnet",
+				SubnetName:    infrav1.ExpressRouteGatewaySubnetName,
+				PublicIPName:  "my-ergw-pip",
+			},
+			wantSubnets: []string{"node-subnet", infrav1.ExpressRouteGatewaySubnetName},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ClusterScope{
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet:                infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-vnet-rg"},
+							Subnets:             infrav1.Subnets{nodeSubnet},
+							ExpressRouteGateway: tc.gateway,
+						},
+					},
+				},
+			}
+			g.Expect(s.ExpressRouteGatewaySpec()).To(Equal(tc.wantSpec))
+
+			var subnets []string
+			for _, subnet := range s.SubnetSpecs() {
+				subnets = append(subnets, subnet.Name)
+			}
+			g.Expect(subnets).To(Equal(tc.wantSubnets))
+
+			routeTables := s.RouteTableSpecs()
+			g.Expect(routeTables).To(HaveLen(1))
+			g.Expect(routeTables[0].DisableBGPRoutePropagation).To(Equal(tc.disableBGPRoutePropagation))
+		})
+	}
+}
+
+func TestAzureFirewall(t *testing.T) {
+	firewallSubnet := infrav1.SubnetSpec{Name: infrav1.AzureFirewallSubnetName, CIDRBlocks: []string{infrav1.DefaultAzureFirewallSubnetCIDR}}
+	nodeSubnets := infrav1.Subnets{
+		{Name: "node-subnet", Role: infrav1.SubnetNode, RouteTable: infrav1.RouteTable{Name: "node-routetable"}},
+		{Name: "other-node-subnet", Role: infrav1.SubnetNode, RouteTable: infrav1.RouteTable{Name: "node-routetable"}},
+		{Name: "cp-subnet", Role: infrav1.SubnetControlPlane, RouteTable: infrav1.RouteTable{Name: "cp-routetable"}},
+	}
+	tests := []struct {
+		name          string
+		firewall      *infrav1.AzureFirewall
+		wantSpec      *azure.AzureFirewallSpec
+		wantSubnets   []string
+		wantPublicIPs []string
+	}{
+		{
+			name:        "no firewall",
+			wantSubnets: []string{"node-subnet", "other-node-subnet", "cp-subnet"},
+		},
+		{
+			name:     "existing firewall",
+			firewall: &infrav1.AzureFirewall{ID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/azureFirewalls/hub-azfw"},
+			wantSpec: &azure.AzureFirewallSpec{
+				Name:            "hub-azfw",
+				ResourceGroup:   "hub-rg",
+				VNetName:        "my-vnet",
+				RouteTableNames: []string{"node-routetable"},
+			},
+			wantSubnets: []string{"node-subnet", "other-node-subnet", "cp-subnet"},
+		},
+		{
+			name: "created firewall",
+			firewall: &infrav1.AzureFirewall{
+				Name:     "my-azfw",
+				SkuTier:  infrav1.AzureFirewallSkuTierStandard,
+				PolicyID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/firewallPolicies/my-policy",
+				Subnet:   firewallSubnet,
+				PublicIP: infrav1.PublicIPSpec{Name: "my-azfw-pip"},
+			},
+			wantSpec: &azure.AzureFirewallSpec{
+				Name:            "my-azfw",
+				ResourceGroup:   "my-vnet-rg",
+				Managed:         true,
+				SkuTier:         infrav1.AzureFirewallSkuTierStandard,
+				PolicyID:        "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/firewallPolicies/my-policy",
+				VNetName:        "my-vnet",
+				SubnetName:      infrav1.AzureFirewallSubnetName,
+				PublicIPName:    "my-azfw-pip",
+				RouteTableNames: []string{"node-routetable"},
+			},
+			wantSubnets:   []string{"node-subnet", "other-node-subnet", "cp-subnet", infrav1.AzureFirewallSubnetName},
+			wantPublicIPs: []string{"my-azfw-pip"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ClusterScope{
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet:     infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-vnet-rg"},
+							Subnets:  nodeSubnets,
+							Firewall: tc.firewall,
+							// a private API server without outbound load balancer has no other public IP
+							APIServerLB: infrav1.LoadBalancerSpec{Type: infrav1.Internal},
+						},
+					},
+				},
+			}
+			g.Expect(s.AzureFirewallSpec()).To(Equal(tc.wantSpec))
+
+			var subnets []string
+			for _, subnet := range s.SubnetSpecs() {
+				subnets = append(subnets, subnet.Name)
+			}
+			g.Expect(subnets).To(Equal(tc.wantSubnets))
+
+			var publicIPs []string
+			for _, publicIP := range s.PublicIPSpecs() {
+				publicIPs = append(publicIPs, publicIP.Name)
+			}
+			g.Expect(publicIPs).To(Equal(tc.wantPublicIPs))
+		})
+	}
+}
+
+func TestVnetPeeringsWithManagementNetwork(t *testing.T) {
+	peering := infrav1.VnetPeeringSpec{ResourceGroup: "other-rg", RemoteVnetName: "other-vnet"}
+	management := infrav1.VnetPeeringSpec{ResourceGroup: "mgmt-rg", RemoteVnetName: "mgmt-vnet"}
+	tests := []struct {
+		name              string
+		peerings          infrav1.VnetPeerings
+		managementNetwork *infrav1.ManagementNetworkSpec
+		want              infrav1.VnetPeerings
+	}{
+		{
+			name:     "no management network",
+			peerings: infrav1.VnetPeerings{peering},
+			want:     infrav1.VnetPeerings{peering},
+		},
+		{
+			name:              "management network without virtual network",
+			peerings:          infrav1.VnetPeerings{peering},
+			managementNetwork: &infrav1.ManagementNetworkSpec{CIDRBlocks: []string{"192.168.0.0/16"}},
+			want:              infrav1.VnetPeerings{peering},
+		},
+		{
+			name:              "management virtual network is peered",
+			peerings:          infrav1.VnetPeerings{peering},
+			managementNetwork: &infrav1.ManagementNetworkSpec{CIDRBlocks: []string{"192.168.0.0/16"}, Vnet: &management},
+			want:              infrav1.VnetPeerings{peering, management},
+		},
+		{
+			name:              "management virtual network is already peered",
+			peerings:          infrav1.VnetPeerings{peering, {ResourceGroup: "MGMT-RG", RemoteVnetName: "mgmt-vnet"}},
+			managementNetwork: &infrav1.ManagementNetworkSpec{CIDRBlocks: []string{"192.168.0.0/16"}, Vnet: &management},
+			want:              infrav1.VnetPeerings{peering, {ResourceGroup: "MGMT-RG", RemoteVnetName: "mgmt-vnet"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ClusterScope{
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet:              infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg", Peerings: tc.peerings},
+							ManagementNetwork: tc.managementNetwork,
+						},
+					},
+				},
+			}
+			g.Expect(s.vnetPeerings()).To(Equal(tc.want))
+			g.Expect(s.VnetPeeringSpecs()).To(HaveLen(2 * len(tc.want)))
+		})
+	}
+}
+
+func TestTemplateExportRequested(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{
+			name: "no export requested",
+			want: false,
+		},
+		{
+			name:        "first export requested",
+			annotations: map[string]string{infrav1.ExportTemplateAnnotation: "2022-01-20"},
+			want:        true,
+		},
+		{
+			name: "template already exported",
+			annotations: map[string]string{
+				infrav1.ExportTemplateAnnotation:   "2022-01-20",
+				infrav1.TemplateExportedAnnotation: "2022-01-20",
+			},
+			want: false,
+		},
+		{
+			name: "new export requested",
+			annotations: map[string]string{
+				infrav1.ExportTemplateAnnotation:   "2022-01-21",
+				infrav1.TemplateExportedAnnotation: "2022-01-20",
+			},
+			want: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ClusterScope{
+				AzureCluster: &infrav1.AzureCluster{
+					ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				},
+			}
+			g.Expect(s.TemplateExportRequested()).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestSetExportedTemplate(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-arm-template", Namespace: "default"},
+		Data:       map[string]string{"template.json": "{}"},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(existing).Build()
+	s := &ClusterScope{
+		Client: fakeClient,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "my-cluster",
+				Namespace:   "default",
+				UID:         "1234",
+				Annotations: map[string]string{infrav1.ExportTemplateAnnotation: "2022-01-21"},
+			},
+		},
+	}
+
+	g.Expect(s.SetExportedTemplate(context.TODO(), []byte(`{"contentVersion": "1.0.0.0"}`))).To(Succeed())
+	g.Expect(s.TemplateExportRequested()).To(BeFalse())
+
+	configMap := &corev1.ConfigMap{}
+	g.Expect(fakeClient.Get(context.TODO(), client.ObjectKey{Name: "my-cluster-arm-template", Namespace: "default"}, configMap)).To(Succeed())
+	g.Expect(configMap.Data).To(Equal(map[string]string{"template.json": `{"contentVersion": "1.0.0.0"}`}))
+	g.Expect(configMap.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "my-cluster"))
+	g.Expect(configMap.OwnerReferences).To(HaveLen(1))
+	g.Expect(configMap.OwnerReferences[0].Kind).To(Equal("AzureCluster"))
+	g.Expect(configMap.OwnerReferences[0].Name).To(Equal("my-cluster"))
+}
+
+func TestRetainPublicIPsOnDelete(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &ClusterScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					APIServerLB: infrav1.LoadBalancerSpec{
+						Type: infrav1.Public,
+						FrontendIPs: []infrav1.FrontendIP{
+							{PublicIP: &infrav1.PublicIPSpec{Name: "my-apiserver-pip"}},
+						},
+					},
+					NodeOutboundLB:          &infrav1.LoadBalancerSpec{FrontendIPsCount: to.Int32Ptr(1)},
+					RetainPublicIPsOnDelete: true,
+				},
+				BastionSpec: infrav1.BastionSpec{
+					AzureBastion: &infrav1.AzureBastion{PublicIP: infrav1.PublicIPSpec{Name: "my-bastion-pip"}},
+				},
+			},
+		},
+	}
+
+	retained := map[string]bool{}
+	for _, publicIP := range s.PublicIPSpecs() {
+		retained[publicIP.Name] = publicIP.RetainOnDelete
+	}
+	g.Expect(retained).To(Equal(map[string]bool{
+		"my-apiserver-pip":             true,
+		"pip-my-cluster-node-outbound": true,
+		"my-bastion-pip":               false,
+	}))
+
+	s.SetPublicIPRetained("my-apiserver-pip")
+	s.SetPublicIPRetained("my-apiserver-pip")
+	g.Expect(s.AzureCluster.Status.RetainedPublicIPs).To(Equal([]string{"my-apiserver-pip"}))
+}
+
+func TestProxyConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		proxyConfig *infrav1.ProxyConfig
+		want        *infrav1.ProxyConfig
+	}{
+		{
+			name:        "no proxy configured",
+			proxyConfig: nil,
+			want:        nil,
+		},
+		{
+			name: "default and cluster no-proxy entries are appended",
+			proxyConfig: &infrav1.ProxyConfig{
+				HTTPProxy: "http://proxy.example.com:3128",
+				NoProxy:   []string{"example.org", "localhost"},
+			},
+			want: &infrav1.ProxyConfig{
+				HTTPProxy: "http://proxy.example.com:3128",
+				NoProxy: []string{
+					"example.org", "localhost", "127.0.0.1", "169.254.169.254", "168.63.129.16", ".svc", ".cluster.local",
+					"10.0.0.0/8", "192.168.0.0/16", "10.128.0.0/12",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					Spec: clusterv1.ClusterSpec{
+						ClusterNetwork: &clusterv1.ClusterNetwork{
+							Pods:     &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+							Services: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.128.0.0/12"}},
+						},
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{CIDRBlocks: []string{"10.0.0.0/8"}},
+						},
+						ProxyConfig: tc.proxyConfig,
+					},
+				},
+			}
+			g.Expect(s.ProxyConfig()).To(Equal(tc.want))
+			if tc.proxyConfig != nil {
+				g.Expect(s.AzureCluster.Spec.ProxyConfig.NoProxy).To(Equal([]string{"example.org", "localhost"}))
+			}
+		})
+	}
+}
+
+func TestActiveNICPools(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+
+	machineDeployment := func(name, template string, deleted bool) *clusterv1.MachineDeployment {
+		md := &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				ClusterName: "my-cluster",
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						ClusterName:       "my-cluster",
+						InfrastructureRef: corev1.ObjectReference{Kind: "AzureMachineTemplate", Name: template},
+					},
+				},
+			},
+		}
+		if deleted {
+			now := metav1.Now()
+			md.DeletionTimestamp = &now
+			md.Finalizers = []string{"test"}
+		}
+		return md
+	}
+	machineTemplate := func(name, poolSize string) *infrav1.AzureMachineTemplate {
+		template := &infrav1.AzureMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+		if poolSize != "" {
+			template.Spec.Template.ObjectMeta.Annotations = map[string]string{infrav1.NICPoolSizeAnnotation: poolSize}
+		}
+		return template
+	}
+
+	other := machineDeployment("md-other-cluster", "pool-template", false)
+	other.Labels[clusterv1.ClusterLabelName] = "other-cluster"
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(
+		machineDeployment("md-pool", "pool-template", false),
+		machineDeployment("md-no-pool", "no-pool-template", false),
+		machineDeployment("md-empty-pool", "empty-pool-template", false),
+		machineDeployment("md-deleted", "pool-template", true),
+		machineDeployment("md-template-deleted", "deleted-template", false),
+		other,
+		machineTemplate("pool-template", "3"),
+		machineTemplate("no-pool-template", ""),
+		machineTemplate("empty-pool-template", "0"),
+	).Build()
+
+	s := &ClusterScope{
+		Client: fakeClient,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		},
+	}
+
+	active, err := s.ActiveNICPools(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(active).To(Equal(map[string]bool{"md-pool": true}))
+}
+
+func TestZonalMachineDeployments(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+
+	machineDeployment := func(name, cluster string, failureDomain *string, deleted bool) *clusterv1.MachineDeployment {
+		md := &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterLabelName: cluster},
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				ClusterName: cluster,
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						ClusterName:   cluster,
+						FailureDomain: failureDomain,
+					},
+				},
+			},
+		}
+		if deleted {
+			now := metav1.Now()
+			md.DeletionTimestamp = &now
+			md.Finalizers = []string{"test"}
+		}
+		return md
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(
+		machineDeployment("md-1a", "my-cluster", to.StringPtr("1"), false),
+		machineDeployment("md-1b", "my-cluster", to.StringPtr("1"), false),
+		machineDeployment("md-2", "my-cluster", to.StringPtr("2"), false),
+		machineDeployment("md-any", "my-cluster", nil, false),
+		machineDeployment("md-deleted", "my-cluster", to.StringPtr("2"), true),
+		machineDeployment("md-other-cluster", "other-cluster", to.StringPtr("1"), false),
+	).Build()
+
+	s := &ClusterScope{
+		Client: fakeClient,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Status: infrav1.AzureClusterStatus{
+				CordonedZones: []infrav1.CordonedZone{{Zone: "1"}, {Zone: "3"}},
+			},
+		},
+	}
+
+	zonal, err := s.ZonalMachineDeployments(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(zonal).To(HaveLen(2))
+	g.Expect(zonal["1"]).To(ConsistOf("md-1a", "md-1b"))
+	g.Expect(zonal["2"]).To(ConsistOf("md-2"))
+	g.Expect(s.CordonedZoneNames()).To(Equal([]string{"1", "3"}))
+}
+
+func TestClusterScope_ProximityPlacementGroupID(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{auth.SubscriptionID: "123"},
+			},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+			},
+		},
+	}
+	g.Expect(s.ProximityPlacementGroupID()).To(BeEmpty())
+
+	s.AzureCluster.Spec.ProximityPlacementGroup = &infrav1.ProximityPlacementGroupSpec{Name: "my-cluster-ppg"}
+	g.Expect(s.ProximityPlacementGroupID()).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-cluster-ppg"))
+}
@@ -18,6 +18,8 @@ package scope
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"reflect"
 
@@ -44,7 +46,7 @@ const azureSecretKey = "clientSecret"
 
 // CredentialsProvider defines the behavior for azure identity based credential providers.
 type CredentialsProvider interface {
-	GetAuthorizer(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string) (autorest.Authorizer, error)
+	GetAuthorizer(ctx context.Context, tokenAudience, activeDirectoryEndpoint string) (autorest.Authorizer, error)
 	GetClientID() string
 	GetClientSecret(ctx context.Context) (string, error)
 	GetTenantID() string
@@ -68,8 +70,15 @@ type ManagedControlPlaneCredentialsProvider struct {
 	AzureManagedControlPlane *infrav1exp.AzureManagedControlPlane
 }
 
+// NodeImageCredentialsProvider wraps AzureCredentialsProvider with AzureNodeImage.
+type NodeImageCredentialsProvider struct {
+	AzureCredentialsProvider
+	AzureNodeImage *infrav1exp.AzureNodeImage
+}
+
 var _ CredentialsProvider = (*AzureClusterCredentialsProvider)(nil)
 var _ CredentialsProvider = (*ManagedControlPlaneCredentialsProvider)(nil)
+var _ CredentialsProvider = (*NodeImageCredentialsProvider)(nil)
 
 // NewAzureClusterCredentialsProvider creates a new AzureClusterCredentialsProvider from the supplied inputs.
 func NewAzureClusterCredentialsProvider(ctx context.Context, kubeClient client.Client, azureCluster *infrav1.AzureCluster) (*AzureClusterCredentialsProvider, error) {
@@ -103,8 +112,8 @@ func NewAzureClusterCredentialsProvider(ctx context.Context, kubeClient client.C
 }
 
 // GetAuthorizer returns an Azure authorizer based on the provided azure identity. It delegates to AzureCredentialsProvider with AzureCluster metadata.
-func (p *AzureClusterCredentialsProvider) GetAuthorizer(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string) (autorest.Authorizer, error) {
-	return p.AzureCredentialsProvider.GetAuthorizer(ctx, resourceManagerEndpoint, activeDirectoryEndpoint, p.AzureCluster.ObjectMeta)
+func (p *AzureClusterCredentialsProvider) GetAuthorizer(ctx context.Context, tokenAudience, activeDirectoryEndpoint string) (autorest.Authorizer, error) {
+	return p.AzureCredentialsProvider.GetAuthorizer(ctx, tokenAudience, activeDirectoryEndpoint, p.AzureCluster.ObjectMeta)
 }
 
 // NewManagedControlPlaneCredentialsProvider creates a new ManagedControlPlaneCredentialsProvider from the supplied inputs.
@@ -139,43 +148,97 @@ func NewManagedControlPlaneCredentialsProvider(ctx context.Context, kubeClient c
 }
 
 // GetAuthorizer returns an Azure authorizer based on the provided azure identity. It delegates to AzureCredentialsProvider with AzureManagedControlPlane metadata.
-func (p *ManagedControlPlaneCredentialsProvider) GetAuthorizer(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string) (autorest.Authorizer, error) {
-	return p.AzureCredentialsProvider.GetAuthorizer(ctx, resourceManagerEndpoint, activeDirectoryEndpoint, p.AzureManagedControlPlane.ObjectMeta)
+func (p *ManagedControlPlaneCredentialsProvider) GetAuthorizer(ctx context.Context, tokenAudience, activeDirectoryEndpoint string) (autorest.Authorizer, error) {
+	return p.AzureCredentialsProvider.GetAuthorizer(ctx, tokenAudience, activeDirectoryEndpoint, p.AzureManagedControlPlane.ObjectMeta)
+}
+
+// NewNodeImageCredentialsProvider creates a new NodeImageCredentialsProvider from the supplied inputs.
+func NewNodeImageCredentialsProvider(ctx context.Context, kubeClient client.Client, nodeImage *infrav1exp.AzureNodeImage) (*NodeImageCredentialsProvider, error) {
+	if nodeImage.Spec.IdentityRef == nil {
+		return nil, errors.New("failed to generate new NodeImageCredentialsProvider from empty identityName")
+	}
+
+	ref := nodeImage.Spec.IdentityRef
+	// if the namespace isn't specified then assume it's in the same namespace as the AzureNodeImage
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = nodeImage.Namespace
+	}
+	identity := &infrav1.AzureClusterIdentity{}
+	key := client.ObjectKey{Name: ref.Name, Namespace: namespace}
+	if err := kubeClient.Get(ctx, key, identity); err != nil {
+		return nil, errors.Errorf("failed to retrieve AzureClusterIdentity external object %q/%q: %v", key.Namespace, key.Name, err)
+	}
+
+	if identity.Spec.Type != infrav1.ServicePrincipal {
+		return nil, errors.New("AzureClusterIdentity is not of type Service Principal")
+	}
+
+	return &NodeImageCredentialsProvider{
+		AzureCredentialsProvider{
+			Client:   kubeClient,
+			Identity: identity,
+		},
+		nodeImage,
+	}, nil
+}
+
+// GetAuthorizer returns an Azure authorizer based on the provided azure identity. It delegates to AzureCredentialsProvider with AzureNodeImage metadata.
+func (p *NodeImageCredentialsProvider) GetAuthorizer(ctx context.Context, tokenAudience, activeDirectoryEndpoint string) (autorest.Authorizer, error) {
+	return p.AzureCredentialsProvider.GetAuthorizer(ctx, tokenAudience, activeDirectoryEndpoint, p.AzureNodeImage.ObjectMeta)
 }
 
 // GetAuthorizer returns an Azure authorizer based on the provided azure identity and cluster metadata.
-func (p *AzureCredentialsProvider) GetAuthorizer(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string, clusterMeta metav1.ObjectMeta) (autorest.Authorizer, error) {
+// The token of the identity is shared with the other clusters using the same identity.
+func (p *AzureCredentialsProvider) GetAuthorizer(ctx context.Context, tokenAudience, activeDirectoryEndpoint string, clusterMeta metav1.ObjectMeta) (autorest.Authorizer, error) {
+	identityName := fmt.Sprintf("%s/%s", p.Identity.Namespace, p.Identity.Name)
 	var spt *adal.ServicePrincipalToken
 	switch p.Identity.Spec.Type {
 	case infrav1.ServicePrincipal:
-		if err := createAzureIdentityWithBindings(ctx, p.Identity, resourceManagerEndpoint, activeDirectoryEndpoint, clusterMeta, p.Client); err != nil {
+		if err := createAzureIdentityWithBindings(ctx, p.Identity, tokenAudience, activeDirectoryEndpoint, clusterMeta, p.Client); err != nil {
 			return nil, err
 		}
 
-		msiEndpoint, err := adal.GetMSIVMEndpoint()
-		if err != nil {
-			return nil, errors.Errorf("failed to get MSI endpoint: %v", err)
-		}
-
-		spt, err = adal.NewServicePrincipalTokenFromMSIWithUserAssignedID(msiEndpoint, resourceManagerEndpoint, p.Identity.Spec.ClientID)
-		if err != nil {
-			return nil, errors.Errorf("failed to get token from service principal identity: %v", err)
-		}
-
-	case infrav1.ManualServicePrincipal:
-		oauthConfig, err := adal.NewOAuthConfig(activeDirectoryEndpoint, p.GetTenantID())
+		key := p.tokenCacheKey(tokenAudience, activeDirectoryEndpoint, "")
+		var err error
+		spt, err = identityTokens.getOrCreate(key, identityName, func() (*adal.ServicePrincipalToken, error) {
+			msiEndpoint, err := adal.GetMSIVMEndpoint()
+			if err != nil {
+				return nil, errors.Errorf("failed to get MSI endpoint: %v", err)
+			}
+
+			spt, err := adal.NewServicePrincipalTokenFromMSIWithUserAssignedID(msiEndpoint, tokenAudience, p.Identity.Spec.ClientID)
+			if err != nil {
+				return nil, errors.Errorf("failed to get token from service principal identity: %v", err)
+			}
+			return spt, nil
+		})
 		if err != nil {
 			return nil, err
 		}
 
+	case infrav1.ManualServicePrincipal:
 		clientSecret, err := p.GetClientSecret(ctx)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get client secret")
 		}
 
-		spt, err = adal.NewServicePrincipalToken(*oauthConfig, p.Identity.Spec.ClientID, clientSecret, resourceManagerEndpoint)
+		// The secret is part of the key so that a rotated secret gets a new token.
+		key := p.tokenCacheKey(tokenAudience, activeDirectoryEndpoint, clientSecret)
+		spt, err = identityTokens.getOrCreate(key, identityName, func() (*adal.ServicePrincipalToken, error) {
+			oauthConfig, err := adal.NewOAuthConfig(activeDirectoryEndpoint, p.GetTenantID())
+			if err != nil {
+				return nil, err
+			}
+
+			spt, err := adal.NewServicePrincipalToken(*oauthConfig, p.Identity.Spec.ClientID, clientSecret, tokenAudience)
+			if err != nil {
+				return nil, errors.Errorf("failed to get token from service principal identity: %v", err)
+			}
+			return spt, nil
+		})
 		if err != nil {
-			return nil, errors.Errorf("failed to get token from service principal identity: %v", err)
+			return nil, err
 		}
 
 	default:
@@ -185,6 +248,14 @@ func (p *AzureCredentialsProvider) GetAuthorizer(ctx context.Context, resourceMa
 	return autorest.NewBearerAuthorizer(spt), nil
 }
 
+// tokenCacheKey returns the key of the token of the identity in the token cache.
+func (p *AzureCredentialsProvider) tokenCacheKey(tokenAudience, activeDirectoryEndpoint, clientSecret string) string {
+	hasher := sha256.New()
+	_, _ = hasher.Write([]byte(p.Identity.Namespace + p.Identity.Name + string(p.Identity.Spec.Type) + p.Identity.Spec.TenantID +
+		p.Identity.Spec.ClientID + tokenAudience + activeDirectoryEndpoint + clientSecret))
+	return base64.URLEncoding.EncodeToString(hasher.Sum(nil))
+}
+
 // GetClientID returns the Client ID associated with the AzureCredentialsProvider's Identity.
 func (p *AzureCredentialsProvider) GetClientID() string {
 	return p.Identity.Spec.ClientID
@@ -212,7 +283,7 @@ func (p *AzureCredentialsProvider) GetTenantID() string {
 	return p.Identity.Spec.TenantID
 }
 
-func createAzureIdentityWithBindings(ctx context.Context, azureIdentity *infrav1.AzureClusterIdentity, resourceManagerEndpoint, activeDirectoryEndpoint string, clusterMeta metav1.ObjectMeta,
+func createAzureIdentityWithBindings(ctx context.Context, azureIdentity *infrav1.AzureClusterIdentity, tokenAudience, activeDirectoryEndpoint string, clusterMeta metav1.ObjectMeta,
 	kubeClient client.Client) error {
 	azureIdentityType, err := getAzureIdentityType(azureIdentity)
 	if err != nil {
@@ -247,7 +318,7 @@ func createAzureIdentityWithBindings(ctx context.Context, azureIdentity *infrav1
 			ClientID:       azureIdentity.Spec.ClientID,
 			ClientPassword: azureIdentity.Spec.ClientSecret,
 			ResourceID:     azureIdentity.Spec.ResourceID,
-			ADResourceID:   resourceManagerEndpoint,
+			ADResourceID:   tokenAudience,
 			ADEndpoint:     activeDirectoryEndpoint,
 		},
 	}
@@ -20,6 +20,10 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,13 +41,29 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/galleryimageversions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vhdimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/runtimehooks"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cloudinit"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+const (
+	// bootstrapFormatKey is the key of the format of the bootstrap data in its secret.
+	bootstrapFormatKey = "format"
+	// ignitionFormat is the format of Ignition bootstrap data.
+	ignitionFormat = "ignition"
+)
+
+// galleryImageVersionIDRegex matches the resource IDs of the versions of the images of shared image galleries.
+var galleryImageVersionIDRegex = regexp.MustCompile(`(?i)^/subscriptions/([^/]+)/resourceGroups/([^/]+)/providers/Microsoft\.Compute/galleries/([^/]+)/images/([^/]+)/versions/([^/]+)$`)
+
 // MachineScopeParams defines the input parameters used to create a new MachineScope.
 type MachineScopeParams struct {
 	Client       client.Client
@@ -51,6 +71,25 @@ type MachineScopeParams struct {
 	Machine      *clusterv1.Machine
 	AzureMachine *infrav1.AzureMachine
 	Cache        *MachineCache
+	// ForceDelete force deletes the VM when the AzureMachine is deleted.
+	ForceDelete bool
+	// ImageReplication is how the replication of the gallery image version of the machine into its location is handled
+	// before its VM is created.
+	ImageReplication azure.ImageReplicationMode
+	// AcceptMarketplaceTerms accepts the marketplace terms of the purchase plan of the image of the machine before its
+	// VM is created.
+	AcceptMarketplaceTerms bool
+	// ImageResolver resolves the image of the machine if it doesn't specify one. Defaults to the marketplace reference
+	// images.
+	ImageResolver azure.ImageResolver
+	// RuntimeHooks calls the runtime extension at the provisioning milestones of the machine, if any is configured.
+	RuntimeHooks runtimehooks.Caller
+	// CordonedZones are the availability zones of the cluster in which new machines without a failure domain must not
+	// be placed.
+	CordonedZones []string
+	// ProximityPlacementGroupID is the ID of the proximity placement group of the cluster, in which the machine is
+	// placed unless it sets its own.
+	ProximityPlacementGroupID string
 }
 
 // NewMachineScope creates a new MachineScope from the supplied parameters.
@@ -71,14 +110,22 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 		return nil, errors.Wrap(err, "failed to init patch helper")
 	}
 
-	return &MachineScope{
-		client:        params.Client,
-		Machine:       params.Machine,
-		AzureMachine:  params.AzureMachine,
-		patchHelper:   helper,
-		ClusterScoper: params.ClusterScope,
-		cache:         params.Cache,
-	}, nil
+	ms := &MachineScope{
+		client:           params.Client,
+		Machine:          params.Machine,
+		AzureMachine:     params.AzureMachine,
+		patchHelper:      helper,
+		ClusterScoper:    params.ClusterScope,
+		cache:            params.Cache,
+		forceDelete:      params.ForceDelete,
+		imageReplication: params.ImageReplication,
+		acceptTerms:      params.AcceptMarketplaceTerms,
+		imageResolver:    params.ImageResolver,
+		runtimeHooks:     params.RuntimeHooks,
+		clusterPPGID:     params.ProximityPlacementGroupID,
+	}
+	ms.avoidCordonedZones(params.CordonedZones)
+	return ms, nil
 }
 
 // MachineScope defines a scope defined around a machine and its cluster.
@@ -90,13 +137,27 @@ type MachineScope struct {
 	Machine      *clusterv1.Machine
 	AzureMachine *infrav1.AzureMachine
 	cache        *MachineCache
+	forceDelete  bool
+	// imageReplication is how the replication of the gallery image version is handled before the VM is created.
+	imageReplication azure.ImageReplicationMode
+	// acceptTerms accepts the marketplace terms of the image before the VM is created.
+	acceptTerms   bool
+	imageResolver azure.ImageResolver
+	runtimeHooks  runtimehooks.Caller
+	// clusterPPGID is the ID of the proximity placement group of the cluster, if it has one.
+	clusterPPGID string
+	// galleryImageVersions lists the versions of the gallery image of the machine to resolve its latest version.
+	// Defaults to a client of the identity of the cluster.
+	galleryImageVersions galleryimageversions.Client
 }
 
 // MachineCache stores common machine information so we don't have to hit the API multiple times within the same reconcile loop.
 type MachineCache struct {
 	BootstrapData string
+	UserData      string
 	VMImage       *infrav1.Image
 	VMSKU         resourceskus.SKU
+	LocationZones []string
 }
 
 // InitMachineCache sets cached information about the machine to be used in the scope.
@@ -113,6 +174,11 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 			return err
 		}
 
+		m.cache.UserData, err = m.GetUserData(ctx)
+		if err != nil {
+			return err
+		}
+
 		m.cache.VMImage, err = m.GetVMImage(ctx)
 		if err != nil {
 			return err
@@ -126,6 +192,10 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 		if err != nil {
 			return azure.WithTerminalError(errors.Wrapf(err, "failed to get SKU %s in compute api", m.AzureMachine.Spec.VMSize))
 		}
+		m.cache.LocationZones, err = skuCache.GetZones(ctx, m.Location())
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the zones for location %s", m.Location())
+		}
 	}
 
 	return nil
@@ -134,29 +204,37 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 // VMSpec returns the VM spec.
 func (m *MachineScope) VMSpec() azure.ResourceSpecGetter {
 	spec := &virtualmachines.VMSpec{
-		Name:                   m.Name(),
-		Location:               m.Location(),
-		ResourceGroup:          m.ResourceGroup(),
-		ClusterName:            m.ClusterName(),
-		Role:                   m.Role(),
-		NICIDs:                 m.NICIDs(),
-		SSHKeyData:             m.AzureMachine.Spec.SSHPublicKey,
-		Size:                   m.AzureMachine.Spec.VMSize,
-		OSDisk:                 m.AzureMachine.Spec.OSDisk,
-		DataDisks:              m.AzureMachine.Spec.DataDisks,
-		AvailabilitySetID:      m.AvailabilitySetID(),
-		Zone:                   m.AvailabilityZone(),
-		Identity:               m.AzureMachine.Spec.Identity,
-		UserAssignedIdentities: m.AzureMachine.Spec.UserAssignedIdentities,
-		SpotVMOptions:          m.AzureMachine.Spec.SpotVMOptions,
-		SecurityProfile:        m.AzureMachine.Spec.SecurityProfile,
-		AdditionalTags:         m.AdditionalTags(),
-		ProviderID:             m.ProviderID(),
+		Name:                       m.Name(),
+		Location:                   m.Location(),
+		ResourceGroup:              m.ResourceGroup(),
+		SubscriptionID:             m.SubscriptionID(),
+		ClusterName:                m.ClusterName(),
+		Role:                       m.Role(),
+		NICIDs:                     m.NICIDs(),
+		SSHKeyData:                 m.AzureMachine.Spec.SSHPublicKey,
+		Size:                       m.AzureMachine.Spec.VMSize,
+		OSDisk:                     withDiskEncryptionSetID(m.AzureMachine.Spec.OSDisk, m.SubscriptionID(), m.ResourceGroup()),
+		DataDisks:                  withDiskEncryptionSetIDs(m.AzureMachine.Spec.DataDisks, m.SubscriptionID(), m.ResourceGroup()),
+		AvailabilitySetID:          m.AvailabilitySetID(),
+		ProximityPlacementGroupID:  m.ProximityPlacementGroupID(),
+		CapacityReservationGroupID: to.String(m.AzureMachine.Spec.CapacityReservationGroupID),
+		LicenseType:                string(m.AzureMachine.Spec.LicenseType),
+		ComputerName:               m.ComputerName(),
+		Zone:                       m.AvailabilityZone(),
+		Identity:                   m.AzureMachine.Spec.Identity,
+		UserAssignedIdentities:     m.AzureMachine.Spec.UserAssignedIdentities,
+		SpotVMOptions:              m.AzureMachine.Spec.SpotVMOptions,
+		SecurityProfile:            m.AzureMachine.Spec.SecurityProfile,
+		AdditionalTags:             m.AdditionalTags(),
+		ProviderID:                 m.ProviderID(),
+		ForceDelete:                m.forceDelete,
 	}
 	if m.cache != nil {
 		spec.SKU = m.cache.VMSKU
-		spec.Image = m.cache.VMImage
+		spec.LocationZones = m.cache.LocationZones
+		spec.Image = m.vmImage(m.cache.VMImage)
 		spec.BootstrapData = m.cache.BootstrapData
+		spec.UserData = m.cache.UserData
 	}
 	return spec
 }
@@ -183,6 +261,9 @@ func (m *MachineScope) PublicIPSpecs() []azure.PublicIPSpec {
 	return spec
 }
 
+// SetPublicIPRetained does nothing, as the public IPs of machines are never retained.
+func (m *MachineScope) SetPublicIPRetained(name string) {}
+
 // InboundNatSpecs returns the inbound NAT specs.
 func (m *MachineScope) InboundNatSpecs() []azure.InboundNatSpec {
 	if m.Role() == infrav1.ControlPlane {
@@ -231,9 +312,56 @@ func (m *MachineScope) NICSpecs() []azure.NICSpec {
 		spec.PublicIPName = azure.GenerateNodePublicIPName(m.Name())
 	}
 
+	if name, ok := m.AzureMachine.Annotations[infrav1.NICPoolClaimAnnotation]; ok {
+		spec.Name = name
+	}
+
+	if poolName, poolSize, ok := m.nicPool(); ok && spec.PublicIPName == "" {
+		spec.PoolName = poolName
+		spec.PoolSize = poolSize
+	}
+
 	return []azure.NICSpec{spec}
 }
 
+// nicPool returns the name and size of the spare network interface pool of the machine's MachineDeployment.
+func (m *MachineScope) nicPool() (string, int, bool) {
+	if !feature.Gates.Enabled(feature.NICPool) || m.Role() != infrav1.Node {
+		return "", 0, false
+	}
+	mdName, ok := m.Machine.Labels[clusterv1.MachineDeploymentLabelName]
+	if !ok {
+		return "", 0, false
+	}
+	// without a valid pool size, the pool of the MachineDeployment is drained.
+	size, _ := nicPoolSize(m.AzureMachine.Annotations)
+	return mdName, size, true
+}
+
+// nicPoolSize returns the size of the network interface pool set in the annotations, and false if it isn't set or is
+// invalid.
+func nicPoolSize(annotations map[string]string) (int, bool) {
+	size, err := strconv.Atoi(annotations[infrav1.NICPoolSizeAnnotation])
+	if err != nil || size < 0 {
+		return 0, false
+	}
+	return size, true
+}
+
+// SetNICPoolClaim records the spare network interface claimed by the AzureMachine.
+func (m *MachineScope) SetNICPoolClaim(nicName string) {
+	m.SetAnnotation(infrav1.NICPoolClaimAnnotation, nicName)
+}
+
+// UpdateNICPoolStatus reports the result of the last refill of the network interface pool of the machine.
+func (m *MachineScope) UpdateNICPoolStatus(err error) {
+	if err == nil {
+		conditions.MarkTrue(m.AzureMachine, infrav1.NICPoolReadyCondition)
+		return
+	}
+	conditions.MarkFalse(m.AzureMachine, infrav1.NICPoolReadyCondition, infrav1.NICPoolRefillFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
+}
+
 // NICIDs returns the NIC resource IDs.
 func (m *MachineScope) NICIDs() []string {
 	nicspecs := m.NICSpecs()
@@ -246,17 +374,85 @@ func (m *MachineScope) NICIDs() []string {
 
 // DiskSpecs returns the disk specs.
 func (m *MachineScope) DiskSpecs() []azure.ResourceSpecGetter {
-	diskSpecs := make([]azure.ResourceSpecGetter, 1+len(m.AzureMachine.Spec.DataDisks))
-	diskSpecs[0] = &disks.DiskSpec{
-		Name:          azure.GenerateOSDiskName(m.Name()),
-		ResourceGroup: m.ResourceGroup(),
+	diskSpecs := []azure.ResourceSpecGetter{
+		&disks.DiskSpec{
+			Name:          azure.GenerateOSDiskName(m.Name()),
+			ResourceGroup: m.ResourceGroup(),
+		},
+	}
+
+	for _, dd := range m.AzureMachine.Spec.DataDisks {
+		// data disks with the Detach delete option, such as shared disks, outlive the machine.
+		if dd.DeleteOption == infrav1.DiskDeleteOptionDetach || dd.IsShared() {
+			continue
+		}
+		diskSpecs = append(diskSpecs, &disks.DiskSpec{
+			Name:          azure.GenerateDataDiskName(m.Name(), dd.NameSuffix),
+			ResourceGroup: m.ResourceGroup(),
+		})
+	}
+	return diskSpecs
+}
+
+// SharedDiskSpecs returns the specs of the data disks shared by the machines of the cluster.
+func (m *MachineScope) SharedDiskSpecs() []azure.ResourceSpecGetter {
+	var diskSpecs []azure.ResourceSpecGetter
+	for _, dd := range withDiskEncryptionSetIDs(m.AzureMachine.Spec.DataDisks, m.SubscriptionID(), m.ResourceGroup()) {
+		if !dd.IsShared() {
+			continue
+		}
+		spec := &disks.SharedDiskSpec{
+			Name:           azure.GenerateSharedDataDiskName(m.ClusterName(), dd.NameSuffix),
+			ResourceGroup:  m.ResourceGroup(),
+			Location:       m.Location(),
+			Zone:           m.AvailabilityZone(),
+			SizeGB:         dd.DiskSizeGB,
+			MaxShares:      *dd.MaxShares,
+			ClusterName:    m.ClusterName(),
+			AdditionalTags: m.AdditionalTags(),
+		}
+		if dd.ManagedDisk != nil {
+			spec.StorageAccountType = dd.ManagedDisk.StorageAccountType
+			spec.PerformanceTier = dd.ManagedDisk.PerformanceTier
+			if dd.ManagedDisk.DiskEncryptionSet != nil {
+				spec.DiskEncryptionSetID = dd.ManagedDisk.DiskEncryptionSet.ID
+			}
+		}
+		diskSpecs = append(diskSpecs, spec)
 	}
+	return diskSpecs
+}
 
-	for i, dd := range m.AzureMachine.Spec.DataDisks {
-		diskSpecs[i+1] = &disks.DiskSpec{
+// DiskUpdateSpecs returns the specs of the disks of the machine to update once they exist, shared disks excluded: the
+// disks with a performance tier, and the data disks kept after the deletion of the machine, which are tagged with the
+// name of the machine so that they can be found and attached to another machine.
+func (m *MachineScope) DiskUpdateSpecs() []azure.ResourceSpecGetter {
+	var diskSpecs []azure.ResourceSpecGetter
+	if osDisk := m.AzureMachine.Spec.OSDisk; osDisk.ManagedDisk != nil && osDisk.ManagedDisk.PerformanceTier != "" {
+		diskSpecs = append(diskSpecs, &disks.DiskSpec{
+			Name:            azure.GenerateOSDiskName(m.Name()),
+			ResourceGroup:   m.ResourceGroup(),
+			PerformanceTier: osDisk.ManagedDisk.PerformanceTier,
+		})
+	}
+	for _, dd := range m.AzureMachine.Spec.DataDisks {
+		if dd.IsShared() {
+			continue
+		}
+		spec := &disks.DiskSpec{
 			Name:          azure.GenerateDataDiskName(m.Name(), dd.NameSuffix),
 			ResourceGroup: m.ResourceGroup(),
 		}
+		if dd.ManagedDisk != nil {
+			spec.PerformanceTier = dd.ManagedDisk.PerformanceTier
+		}
+		if dd.DeleteOption == infrav1.DiskDeleteOptionDetach {
+			spec.MachineName = m.Name()
+		}
+		if spec.PerformanceTier == "" && spec.MachineName == "" {
+			continue
+		}
+		diskSpecs = append(diskSpecs, spec)
 	}
 	return diskSpecs
 }
@@ -269,12 +465,22 @@ func (m *MachineScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
 				MachineName:  m.Name(),
 				Name:         m.AzureMachine.Spec.RoleAssignmentName,
 				ResourceType: azure.VirtualMachine,
+				Scope:        m.roleAssignmentScope(),
 			},
 		}
 	}
 	return []azure.RoleAssignmentSpec{}
 }
 
+// roleAssignmentScope returns the scope of the role assignment of the system-assigned identity: the resource group of
+// the cluster when capz is only allowed to manage its resource groups, otherwise the subscription.
+func (m *MachineScope) roleAssignmentScope() string {
+	if feature.Gates.Enabled(feature.ResourceGroupScoped) {
+		return azure.ResourceGroupID(m.SubscriptionID(), m.ResourceGroup())
+	}
+	return ""
+}
+
 // VMExtensionSpecs returns the vm extension specs.
 func (m *MachineScope) VMExtensionSpecs() []azure.ExtensionSpec {
 	var extensionSpecs = []azure.ExtensionSpec{}
@@ -300,9 +506,10 @@ func (m *MachineScope) Subnet() infrav1.SubnetSpec {
 
 // AvailabilityZone returns the AzureMachine Availability Zone.
 // Priority for selecting the AZ is
-//   1) Machine.Spec.FailureDomain
-//   2) AzureMachine.Spec.FailureDomain (This is to support deprecated AZ)
-//   3) No AZ
+//  1. Machine.Spec.FailureDomain
+//  2. AzureMachine.Spec.FailureDomain (This is to support deprecated AZ)
+//  3. The zone picked to avoid the cordoned zones of the cluster, see avoidCordonedZones
+//  4. No AZ
 func (m *MachineScope) AvailabilityZone() string {
 	if m.Machine.Spec.FailureDomain != nil {
 		return *m.Machine.Spec.FailureDomain
@@ -311,10 +518,43 @@ func (m *MachineScope) AvailabilityZone() string {
 	if m.AzureMachine.Spec.FailureDomain != nil {
 		return *m.AzureMachine.Spec.FailureDomain
 	}
+	if zone, ok := m.AzureMachine.Annotations[infrav1.AvailabilityZoneAnnotation]; ok {
+		return zone
+	}
 
 	return ""
 }
 
+// avoidCordonedZones pins a machine without a failure domain whose VM isn't created yet to an availability zone of the
+// cluster which isn't cordoned, rather than letting Azure place its VM in any zone. The zone is picked from the name of
+// the machine, to spread the machines across the remaining zones, and recorded in the AvailabilityZoneAnnotation so
+// that it doesn't change once the zones are uncordoned.
+func (m *MachineScope) avoidCordonedZones(cordoned []string) {
+	if len(cordoned) == 0 || m.AvailabilityZone() != "" || m.AzureMachine.Spec.ProviderID != nil ||
+		!m.AzureMachine.DeletionTimestamp.IsZero() || conditions.Has(m.AzureMachine, infrav1.VMRunningCondition) {
+		return
+	}
+
+	isCordoned := make(map[string]bool, len(cordoned))
+	for _, zone := range cordoned {
+		isCordoned[zone] = true
+	}
+	var zones []string
+	for _, zone := range m.FailureDomains() {
+		if !isCordoned[zone] {
+			zones = append(zones, zone)
+		}
+	}
+	if len(zones) == 0 {
+		return
+	}
+	sort.Strings(zones)
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(m.AzureMachine.Name))
+	m.SetAnnotation(infrav1.AvailabilityZoneAnnotation, zones[h.Sum32()%uint32(len(zones))])
+}
+
 // Name returns the AzureMachine name.
 func (m *MachineScope) Name() string {
 	if id := m.GetVMID(); id != "" {
@@ -327,6 +567,25 @@ func (m *MachineScope) Name() string {
 	return m.AzureMachine.Name
 }
 
+// ComputerName returns the computer name of the VM, i.e. "<prefix>-<zone>-<index>" with a computer name prefix, where
+// the index is the random suffix of the AzureMachine name, and the VM name otherwise.
+func (m *MachineScope) ComputerName() string {
+	prefix := m.AzureMachine.Spec.ComputerNamePrefix
+	if prefix == "" {
+		return m.Name()
+	}
+	parts := []string{prefix}
+	if zone := m.AvailabilityZone(); zone != "" {
+		parts = append(parts, zone)
+	}
+	index := m.AzureMachine.Name
+	if len(index) > 5 {
+		index = index[len(index)-5:]
+	}
+	parts = append(parts, strings.TrimPrefix(index, "-"))
+	return strings.Join(parts, "-")
+}
+
 // Namespace returns the namespace name.
 func (m *MachineScope) Namespace() string {
 	return m.AzureMachine.Namespace
@@ -395,6 +654,15 @@ func (m *MachineScope) AvailabilitySetID() string {
 	return asID
 }
 
+// ProximityPlacementGroupID returns the ID of the proximity placement group of this machine, which defaults to the one
+// of the cluster, or "" if the machine isn't placed in a proximity placement group.
+func (m *MachineScope) ProximityPlacementGroupID() string {
+	if m.AzureMachine.Spec.ProximityPlacementGroupID != nil {
+		return *m.AzureMachine.Spec.ProximityPlacementGroupID
+	}
+	return m.clusterPPGID
+}
+
 // SetProviderID sets the AzureMachine providerID in spec.
 func (m *MachineScope) SetProviderID(v string) {
 	m.AzureMachine.Spec.ProviderID = to.StringPtr(v)
@@ -456,6 +724,16 @@ func (m *MachineScope) SetBootstrapConditions(ctx context.Context, provisioningS
 	}
 }
 
+// CompletedPhase returns the last provisioning phase the AzureMachine completed.
+func (m *MachineScope) CompletedPhase() infrav1.AzureMachinePhase {
+	return m.AzureMachine.Status.CompletedPhase
+}
+
+// SetCompletedPhase sets the last provisioning phase the AzureMachine completed.
+func (m *MachineScope) SetCompletedPhase(phase infrav1.AzureMachinePhase) {
+	m.AzureMachine.Status.CompletedPhase = phase
+}
+
 // SetAnnotation sets a key value annotation on the AzureMachine.
 func (m *MachineScope) SetAnnotation(key, value string) {
 	if m.AzureMachine.Annotations == nil {
@@ -500,10 +778,16 @@ func (m *MachineScope) SetAddresses(addrs []corev1.NodeAddress) {
 func (m *MachineScope) PatchObject(ctx context.Context) error {
 	conditions.SetSummary(m.AzureMachine,
 		conditions.WithConditions(
+			infrav1.NetworkInterfacesReadyCondition,
 			infrav1.VMRunningCondition,
+			infrav1.VMExtensionsReadyCondition,
+			infrav1.BootstrapSucceededCondition,
 		),
 		conditions.WithStepCounterIfOnly(
+			infrav1.NetworkInterfacesReadyCondition,
 			infrav1.VMRunningCondition,
+			infrav1.VMExtensionsReadyCondition,
+			infrav1.BootstrapSucceededCondition,
 		),
 	)
 
@@ -512,7 +796,12 @@ func (m *MachineScope) PatchObject(ctx context.Context) error {
 		m.AzureMachine,
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
+			infrav1.NetworkInterfacesReadyCondition,
 			infrav1.VMRunningCondition,
+			infrav1.VMExtensionsReadyCondition,
+			infrav1.BootstrapSucceededCondition,
+			infrav1.VMPowerStateReadyCondition,
+			infrav1.NICPoolReadyCondition,
 		}})
 }
 
@@ -535,6 +824,37 @@ func (m *MachineScope) AdditionalTags() infrav1.Tags {
 	return tags
 }
 
+// withDiskEncryptionSetID returns a copy of the OS disk in which a disk encryption set of the cluster referenced by name
+// is referenced by ID.
+func withDiskEncryptionSetID(osDisk infrav1.OSDisk, subscriptionID, resourceGroup string) infrav1.OSDisk {
+	resolved := osDisk.DeepCopy()
+	resolveDiskEncryptionSetID(resolved.ManagedDisk, subscriptionID, resourceGroup)
+	return *resolved
+}
+
+// withDiskEncryptionSetIDs returns a copy of the data disks in which the disk encryption sets of the cluster referenced by
+// name are referenced by ID.
+func withDiskEncryptionSetIDs(dataDisks []infrav1.DataDisk, subscriptionID, resourceGroup string) []infrav1.DataDisk {
+	if dataDisks == nil {
+		return nil
+	}
+	resolved := make([]infrav1.DataDisk, len(dataDisks))
+	for i := range dataDisks {
+		dataDisks[i].DeepCopyInto(&resolved[i])
+		resolveDiskEncryptionSetID(resolved[i].ManagedDisk, subscriptionID, resourceGroup)
+	}
+	return resolved
+}
+
+// resolveDiskEncryptionSetID sets the ID of a disk encryption set of the cluster referenced by name, which lives in the
+// resource group of the cluster.
+func resolveDiskEncryptionSetID(managedDisk *infrav1.ManagedDiskParameters, subscriptionID, resourceGroup string) {
+	if managedDisk == nil || managedDisk.DiskEncryptionSet == nil || managedDisk.DiskEncryptionSet.Name == "" {
+		return
+	}
+	managedDisk.DiskEncryptionSet.ID = azure.DiskEncryptionSetID(subscriptionID, resourceGroup, managedDisk.DiskEncryptionSet.Name)
+}
+
 // GetBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName.
 func (m *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetBootstrapData")
@@ -553,27 +873,405 @@ func (m *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
 	if !ok {
 		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
+	if isIgnition(secret) {
+		// Ignition reads its config from the custom data of the VM, which is delivered as is.
+		if err := validateIgnitionBootstrap(m.AzureMachine.Spec.OSDisk.OSType, m); err != nil {
+			return "", azure.WithTerminalError(errors.Wrapf(err, "invalid Ignition bootstrap data for AzureMachine %s/%s", m.Namespace(), m.Name()))
+		}
+		return base64.StdEncoding.EncodeToString(value), nil
+	}
+	if m.AzureMachine.Spec.OSDisk.OSType != azure.WindowsOS {
+		injected, err := cloudinit.InjectProxyConfig(value, m.ProxyConfig())
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to inject proxy configuration into bootstrap data for AzureMachine %s/%s", m.Namespace(), m.Name())
+		}
+		value = injected
+
+		if trustedCAs := m.TrustedCAs(); trustedCAs != nil {
+			caCerts, err := getTrustedCAs(ctx, m.client, m.Namespace(), trustedCAs)
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to retrieve trusted CA certificates for AzureMachine %s/%s", m.Namespace(), m.Name())
+			}
+			injected, err = cloudinit.InjectTrustedCAs(value, caCerts, withoutMirroredRegistries(trustedCAs.Registries, m.RegistryMirrors()))
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to inject trusted CA certificates into bootstrap data for AzureMachine %s/%s", m.Namespace(), m.Name())
+			}
+			value = injected
+		}
+
+		if mirrors := m.RegistryMirrors(); len(mirrors) > 0 {
+			credentials, err := getRegistryCredentials(ctx, m.client, m.Namespace(), mirrors)
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to retrieve registry mirror credentials for AzureMachine %s/%s", m.Namespace(), m.Name())
+			}
+			injected, err = cloudinit.InjectRegistryMirrors(value, mirrors, credentials)
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to inject registry mirrors into bootstrap data for AzureMachine %s/%s", m.Namespace(), m.Name())
+			}
+			value = injected
+		}
+	}
 	return base64.StdEncoding.EncodeToString(value), nil
 }
 
-// GetVMImage returns the image from the machine configuration, or a default one.
+// isIgnition reports whether a bootstrap data secret holds an Ignition config, as used by Flatcar Container Linux,
+// rather than cloud-init data. The format is read from the secret's format key, falling back to parsing the data for
+// bootstrap providers which don't set it.
+func isIgnition(secret *corev1.Secret) bool {
+	if format, ok := secret.Data[bootstrapFormatKey]; ok {
+		return string(format) == ignitionFormat
+	}
+	var config struct {
+		Ignition *struct {
+			Version string `json:"version"`
+		} `json:"ignition"`
+	}
+	if err := json.Unmarshal(secret.Data["value"], &config); err != nil {
+		return false
+	}
+	return config.Ignition != nil && config.Ignition.Version != ""
+}
+
+// bootstrapAmender exposes the cluster configuration which is added to cloud-init bootstrap data.
+type bootstrapAmender interface {
+	ProxyConfig() *infrav1.ProxyConfig
+	TrustedCAs() *infrav1.TrustedCASource
+	RegistryMirrors() []infrav1.RegistryMirror
+}
+
+// validateIgnitionBootstrap returns an error if Ignition bootstrap data can't be used by a machine. Ignition is only
+// available on Linux, and the proxy, trusted CAs and registry mirrors of the cluster can only be added to cloud-init
+// bootstrap data.
+func validateIgnitionBootstrap(osType string, amender bootstrapAmender) error {
+	if osType == azure.WindowsOS {
+		return errors.New("bootstrap data in Ignition format is not supported on Windows")
+	}
+	var unsupported []string
+	if amender.ProxyConfig() != nil {
+		unsupported = append(unsupported, "proxy")
+	}
+	if amender.TrustedCAs() != nil {
+		unsupported = append(unsupported, "trustedCAs")
+	}
+	if len(amender.RegistryMirrors()) > 0 {
+		unsupported = append(unsupported, "registryMirrors")
+	}
+	if len(unsupported) > 0 {
+		return errors.Errorf("the %s configuration of the cluster can't be added to Ignition bootstrap data, configure it in the Ignition config instead", strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// getTrustedCAs returns the CA certificates referenced by a TrustedCASource from its Secret in the given namespace.
+func getTrustedCAs(ctx context.Context, c client.Client, namespace string, source *infrav1.TrustedCASource) ([]byte, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: namespace, Name: source.SecretName}
+	if err := c.Get(ctx, key, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve trusted CAs secret %s/%s", namespace, source.SecretName)
+	}
+
+	dataKey := source.Key
+	if dataKey == "" {
+		dataKey = "ca.crt"
+	}
+	value, ok := secret.Data[dataKey]
+	if !ok {
+		return nil, errors.Errorf("error retrieving trusted CAs: secret %s key is missing", dataKey)
+	}
+	return value, nil
+}
+
+// withoutMirroredRegistries returns the registries which aren't mirrored, since the host configuration of a mirrored
+// registry is replaced by the one of its mirrors.
+func withoutMirroredRegistries(registries []string, mirrors []infrav1.RegistryMirror) []string {
+	mirrored := make(map[string]bool, len(mirrors))
+	for _, mirror := range mirrors {
+		mirrored[mirror.Registry] = true
+	}
+	var result []string
+	for _, registry := range registries {
+		if !mirrored[registry] {
+			result = append(result, registry)
+		}
+	}
+	return result
+}
+
+// getRegistryCredentials returns the credentials of the registry mirrors, keyed by mirrored registry, from their
+// Secrets in the given namespace.
+func getRegistryCredentials(ctx context.Context, c client.Client, namespace string, mirrors []infrav1.RegistryMirror) (map[string]cloudinit.RegistryCredentials, error) {
+	credentials := map[string]cloudinit.RegistryCredentials{}
+	for _, mirror := range mirrors {
+		if mirror.CredentialsSecretName == "" {
+			continue
+		}
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: namespace, Name: mirror.CredentialsSecretName}
+		if err := c.Get(ctx, key, secret); err != nil {
+			return nil, errors.Wrapf(err, "failed to retrieve registry mirror credentials secret %s/%s", namespace, mirror.CredentialsSecretName)
+		}
+		username, ok := secret.Data[corev1.BasicAuthUsernameKey]
+		if !ok {
+			return nil, errors.Errorf("error retrieving registry mirror credentials: secret %s key is missing", corev1.BasicAuthUsernameKey)
+		}
+		password, ok := secret.Data[corev1.BasicAuthPasswordKey]
+		if !ok {
+			return nil, errors.Errorf("error retrieving registry mirror credentials: secret %s key is missing", corev1.BasicAuthPasswordKey)
+		}
+		credentials[mirror.Registry] = cloudinit.RegistryCredentials{Username: string(username), Password: string(password)}
+	}
+	return credentials, nil
+}
+
+// GetUserData returns the user data from the secret in the AzureMachine's userData, or an empty string if it has no
+// user data.
+func (m *MachineScope) GetUserData(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetUserData")
+	defer done()
+
+	userData := m.AzureMachine.Spec.UserData
+	if userData == nil {
+		return "", nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: m.Namespace(), Name: userData.SecretName}
+	if err := m.client.Get(ctx, key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve user data secret for AzureMachine %s/%s", m.Namespace(), m.Name())
+	}
+
+	dataKey := userData.Key
+	if dataKey == "" {
+		dataKey = "value"
+	}
+	value, ok := secret.Data[dataKey]
+	if !ok {
+		return "", errors.Errorf("error retrieving user data: secret %s key is missing", dataKey)
+	}
+	return base64.StdEncoding.EncodeToString(value), nil
+}
+
+// GetVMImage returns the image from the machine configuration, or a default one. The default image is resolved once
+// and saved to the AzureMachine status, so the VM of the machine keeps being created from the same image.
 func (m *MachineScope) GetVMImage(ctx context.Context) (*infrav1.Image, error) {
-	_, log, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetVMImage")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetVMImage")
 	defer done()
 
 	// Use custom Marketplace image, Image ID or a Shared Image Gallery image if provided
-	if m.AzureMachine.Spec.Image != nil {
-		return m.AzureMachine.Spec.Image, nil
+	if image := m.AzureMachine.Spec.Image; image != nil {
+		if !isLatestSharedGalleryImage(image) {
+			return image, nil
+		}
+		return m.pinSharedGalleryImage(ctx, image)
+	}
+
+	if m.AzureMachine.Status.Image != nil {
+		return m.AzureMachine.Status.Image, nil
+	}
+
+	osType := m.AzureMachine.Spec.OSDisk.OSType
+	runtime := m.AzureMachine.Annotations["runtime"]
+	log.Info("No image specified for machine, resolving default image", "machine", m.AzureMachine.GetName(), "osType", osType, "runtime", runtime)
+	resolver := m.imageResolver
+	if resolver == nil {
+		resolver = azure.MarketplaceImageResolver{}
+	}
+	image, err := resolver.ResolveImage(ctx, to.String(m.Machine.Spec.Version), osType, runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	m.AzureMachine.Status.Image = image
+	return image, nil
+}
+
+// pinSharedGalleryImage returns the shared gallery image of the machine with the version "latest" resolves to in its
+// location. The version is resolved once before the VM is created and saved to the AzureMachine status, so the VM of
+// the machine keeps being created from the same image version, and the version it runs is known.
+func (m *MachineScope) pinSharedGalleryImage(ctx context.Context, image *infrav1.Image) (*infrav1.Image, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.pinSharedGalleryImage")
+	defer done()
+
+	if pinned := m.AzureMachine.Status.Image; pinned != nil && pinned.SharedGallery != nil && sameGalleryImage(*pinned.SharedGallery, *image.SharedGallery) {
+		return pinned, nil
+	}
+	// The version of an existing VM can't be resolved anymore, as newer versions may have been published since.
+	if m.ProviderID() != "" {
+		return image, nil
+	}
+
+	versions := m.galleryImageVersions
+	if versions == nil {
+		versions = galleryimageversions.NewClient(m)
 	}
+	version, err := galleryimageversions.LatestVersion(ctx, versions, *image.SharedGallery, m.Location())
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Resolved latest version of shared gallery image", "gallery", image.SharedGallery.Gallery, "image", image.SharedGallery.Name, "version", version)
+
+	pinned := image.DeepCopy()
+	pinned.SharedGallery.Version = version
+	m.AzureMachine.Status.Image = pinned
+	return pinned, nil
+}
+
+// isLatestSharedGalleryImage reports whether the image is a shared gallery image with "latest" as version.
+func isLatestSharedGalleryImage(image *infrav1.Image) bool {
+	return image.SharedGallery != nil && strings.EqualFold(image.SharedGallery.Version, azure.LatestVersion)
+}
+
+// sameGalleryImage reports whether two shared gallery images refer to the same image definition.
+func sameGalleryImage(a, b infrav1.AzureSharedGalleryImage) bool {
+	return a.SubscriptionID == b.SubscriptionID && strings.EqualFold(a.ResourceGroup, b.ResourceGroup) &&
+		strings.EqualFold(a.Gallery, b.Gallery) && strings.EqualFold(a.Name, b.Name)
+}
+
+// VHDImageSpec returns the spec of the managed image the VM of the machine is created from when its image is a VHD
+// blob, or nil for other images.
+func (m *MachineScope) VHDImageSpec() azure.ResourceSpecGetter {
+	image := m.AzureMachine.Spec.Image
+	if image == nil || image.VHD == nil {
+		return nil
+	}
+	return &vhdimages.VHDImageSpec{
+		Name:             azure.GenerateVHDImageName(m.ClusterName(), image.VHD.URI),
+		ResourceGroup:    m.ResourceGroup(),
+		Location:         m.Location(),
+		BlobURI:          image.VHD.URI,
+		OSType:           m.AzureMachine.Spec.OSDisk.OSType,
+		HyperVGeneration: image.VHD.HyperVGeneration,
+		ClusterName:      m.ClusterName(),
+		AdditionalTags:   m.AdditionalTags(),
+	}
+}
+
+// vmImage returns the image to create the VM from: VHD blobs are replaced with the managed image created from them.
+func (m *MachineScope) vmImage(image *infrav1.Image) *infrav1.Image {
+	if image == nil || image.VHD == nil {
+		return image
+	}
+	return &infrav1.Image{
+		ID: to.StringPtr(azure.ImageID(m.SubscriptionID(), m.ResourceGroup(), azure.GenerateVHDImageName(m.ClusterName(), image.VHD.URI))),
+	}
+}
 
-	if m.AzureMachine.Spec.OSDisk.OSType == azure.WindowsOS {
-		runtime := m.AzureMachine.Annotations["runtime"]
-		log.Info("No image specified for machine, using default Windows Image", "machine", m.AzureMachine.GetName(), "runtime", runtime)
-		return azure.GetDefaultWindowsImage(to.String(m.Machine.Spec.Version), runtime)
+// ImageReplicationSpec returns the spec of the replication of the gallery image version of the machine into its
+// location. It returns nil if the controller doesn't check image replication, if the machine doesn't use a specific
+// version of a gallery image, or once its VM exists, as the replication is only checked before the VM is created.
+func (m *MachineScope) ImageReplicationSpec() *azure.ImageReplicationSpec {
+	if m.imageReplication == azure.ImageReplicationDisabled || m.ProviderID() != "" {
+		return nil
 	}
 
-	log.Info("No image specified for machine, using default Linux Image", "machine", m.AzureMachine.GetName())
-	return azure.GetDefaultUbuntuImage(to.String(m.Machine.Spec.Version))
+	var spec *azure.ImageReplicationSpec
+	image := m.AzureMachine.Spec.Image
+	if image != nil && isLatestSharedGalleryImage(image) && m.AzureMachine.Status.Image != nil {
+		image = m.AzureMachine.Status.Image
+	}
+	switch {
+	case image == nil:
+		return nil
+	case image.SharedGallery != nil:
+		spec = &azure.ImageReplicationSpec{
+			SubscriptionID: image.SharedGallery.SubscriptionID,
+			ResourceGroup:  image.SharedGallery.ResourceGroup,
+			Gallery:        image.SharedGallery.Gallery,
+			Image:          image.SharedGallery.Name,
+			Version:        image.SharedGallery.Version,
+		}
+	case image.ID != nil:
+		match := galleryImageVersionIDRegex.FindStringSubmatch(*image.ID)
+		if match == nil {
+			return nil
+		}
+		spec = &azure.ImageReplicationSpec{
+			SubscriptionID: match[1],
+			ResourceGroup:  match[2],
+			Gallery:        match[3],
+			Image:          match[4],
+			Version:        match[5],
+		}
+	default:
+		return nil
+	}
+	// The version Azure resolves "latest" to is only known when the VM is created.
+	if strings.EqualFold(spec.Version, "latest") {
+		return nil
+	}
+	spec.Location = m.Location()
+	spec.Replicate = m.imageReplication == azure.ImageReplicationReplicate
+	return spec
+}
+
+// MarketplaceTermsSpec returns the spec of the marketplace terms of the purchase plan of the image of the machine. It
+// returns nil if the controller doesn't accept marketplace terms, if the image has no purchase plan, or once its VM
+// exists, as the terms only need to be accepted before the VM is created.
+func (m *MachineScope) MarketplaceTermsSpec() *azure.MarketplaceTermsSpec {
+	if !m.acceptTerms || m.ProviderID() != "" || m.AzureMachine.Spec.Image == nil {
+		return nil
+	}
+	return marketplaceTermsSpec(m.AzureMachine.Spec.Image)
+}
+
+// marketplaceTermsSpec returns the spec of the marketplace terms of the purchase plan of an image, or nil if the image
+// has no purchase plan.
+func marketplaceTermsSpec(image *infrav1.Image) *azure.MarketplaceTermsSpec {
+	plan := converters.ImageToPlan(image)
+	if plan == nil || to.String(plan.Publisher) == "" || to.String(plan.Product) == "" || to.String(plan.Name) == "" {
+		return nil
+	}
+	return &azure.MarketplaceTermsSpec{
+		Publisher: to.String(plan.Publisher),
+		Offer:     to.String(plan.Product),
+		Plan:      to.String(plan.Name),
+	}
+}
+
+// UpdateImageReplicationStatus updates the ImageReplicated condition on the AzureMachine status.
+func (m *MachineScope) UpdateImageReplicationStatus(reason string, err error) {
+	if err == nil {
+		conditions.MarkTrue(m.AzureMachine, infrav1.ImageReplicatedCondition)
+		return
+	}
+	severity := clusterv1.ConditionSeverityInfo
+	switch reason {
+	case infrav1.ImageNotReplicatedReason:
+		severity = clusterv1.ConditionSeverityWarning
+	case infrav1.ImageReplicationFailedReason:
+		severity = clusterv1.ConditionSeverityError
+	}
+	conditions.MarkFalse(m.AzureMachine, infrav1.ImageReplicatedCondition, reason, severity, "%s", err.Error())
+}
+
+// VMPowerStateSpec returns the spec of the power state of the VM of the machine, or nil if the machine doesn't have a
+// desired power state.
+func (m *MachineScope) VMPowerStateSpec() *azure.VMPowerStateSpec {
+	if m.AzureMachine.Spec.PowerState == "" {
+		return nil
+	}
+	return &azure.VMPowerStateSpec{
+		Name:          m.Name(),
+		ResourceGroup: m.ResourceGroup(),
+		PowerState:    m.AzureMachine.Spec.PowerState,
+	}
+}
+
+// SetVMPowerState sets the power state of the VM in the AzureMachine status.
+func (m *MachineScope) SetVMPowerState(state infrav1.VMPowerState) {
+	m.AzureMachine.Status.PowerState = state
+}
+
+// UpdateVMPowerStateStatus updates the VMPowerStateReady condition on the AzureMachine status.
+func (m *MachineScope) UpdateVMPowerStateStatus(reason string, err error) {
+	if err == nil {
+		conditions.MarkTrue(m.AzureMachine, infrav1.VMPowerStateReadyCondition)
+		return
+	}
+	severity := clusterv1.ConditionSeverityInfo
+	if reason == infrav1.VMPowerStateFailedReason {
+		severity = clusterv1.ConditionSeverityError
+	}
+	conditions.MarkFalse(m.AzureMachine, infrav1.VMPowerStateReadyCondition, reason, severity, "%s", err.Error())
 }
 
 // SetSubnetName defaults the AzureMachine subnet name to the name of one the subnets with the machine role when there is only one of them.
@@ -18,20 +18,33 @@ package scope
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	autorestazure "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/galleryimageversions/mock_galleryimageversions"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vhdimages"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cloudinit"
 )
 
 func TestMachineScope_Name(t *testing.T) {
@@ -141,6 +154,69 @@ func TestMachineScope_Name(t *testing.T) {
 	}
 }
 
+func TestMachineScope_ComputerName(t *testing.T) {
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         string
+	}{
+		{
+			name: "without prefix, use the VM name",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-abcde",
+					},
+				},
+			},
+			want: "machine-abcde",
+		},
+		{
+			name: "with prefix and zone",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{
+						FailureDomain: to.StringPtr("2"),
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-abcde",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						ComputerNamePrefix: "web",
+					},
+				},
+			},
+			want: "web-2-abcde",
+		},
+		{
+			name: "with prefix and without zone",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-abcde",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						ComputerNamePrefix: "web",
+					},
+				},
+			},
+			want: "web-abcde",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.machineScope.ComputerName()
+			if got != tt.want {
+				t.Errorf("MachineScope.ComputerName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMachineScope_GetVMID(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -391,6 +467,46 @@ func TestMachineScope_RoleAssignmentSpecs(t *testing.T) {
 	}
 }
 
+func TestMachineScope_RoleAssignmentSpecsResourceGroupScoped(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, feature.ResourceGroupScoped, true)()
+
+	machineScope := MachineScope{
+		Machine: &clusterv1.Machine{},
+		AzureMachine: &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "machine-name",
+			},
+			Spec: infrav1.AzureMachineSpec{
+				Identity:           infrav1.VMIdentitySystemAssigned,
+				RoleAssignmentName: "azure-role-assignment-name",
+			},
+		},
+		ClusterScoper: &ClusterScope{
+			AzureClients: AzureClients{
+				EnvironmentSettings: auth.EnvironmentSettings{
+					Values: map[string]string{auth.SubscriptionID: "123"},
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+				},
+			},
+		},
+	}
+	want := []azure.RoleAssignmentSpec{
+		{
+			MachineName:  "machine-name",
+			Name:         "azure-role-assignment-name",
+			ResourceType: azure.VirtualMachine,
+			Scope:        "/subscriptions/123/resourceGroups/my-rg",
+		},
+	}
+	if got := machineScope.RoleAssignmentSpecs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RoleAssignmentSpecs() = %v, want %v", got, want)
+	}
+}
+
 func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -742,6 +858,97 @@ func TestMachineScope_AvailabilityZone(t *testing.T) {
 	}
 }
 
+func TestMachineScope_AvoidCordonedZones(t *testing.T) {
+	clusterScope := &ClusterScope{
+		AzureCluster: &infrav1.AzureCluster{
+			Status: infrav1.AzureClusterStatus{
+				FailureDomains: clusterv1.FailureDomains{"1": {}, "2": {}, "3": {}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		machine      *clusterv1.Machine
+		azureMachine *infrav1.AzureMachine
+		cordoned     []string
+		want         []string
+	}{
+		{
+			name:         "machine without failure domain is placed in any zone when none is cordoned",
+			machine:      &clusterv1.Machine{},
+			azureMachine: &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Name: "machine-name"}},
+			want:         []string{""},
+		},
+		{
+			name:         "machine without failure domain is pinned to a zone which isn't cordoned",
+			machine:      &clusterv1.Machine{},
+			azureMachine: &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Name: "machine-name"}},
+			cordoned:     []string{"1", "3"},
+			want:         []string{"2"},
+		},
+		{
+			name:         "machine is placed in any zone when every zone is cordoned",
+			machine:      &clusterv1.Machine{},
+			azureMachine: &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Name: "machine-name"}},
+			cordoned:     []string{"1", "2", "3"},
+			want:         []string{""},
+		},
+		{
+			name:         "machine with a failure domain keeps it",
+			machine:      &clusterv1.Machine{Spec: clusterv1.MachineSpec{FailureDomain: pointer.String("1")}},
+			azureMachine: &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Name: "machine-name"}},
+			cordoned:     []string{"1"},
+			want:         []string{"1"},
+		},
+		{
+			name:    "machine keeps the zone it was pinned to",
+			machine: &clusterv1.Machine{},
+			azureMachine: &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{
+				Name:        "machine-name",
+				Annotations: map[string]string{infrav1.AvailabilityZoneAnnotation: "1"},
+			}},
+			cordoned: []string{"1"},
+			want:     []string{"1"},
+		},
+		{
+			name:    "machine whose VM exists isn't pinned",
+			machine: &clusterv1.Machine{},
+			azureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine-name"},
+				Spec:       infrav1.AzureMachineSpec{ProviderID: pointer.String("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name")},
+			},
+			cordoned: []string{"1"},
+			want:     []string{""},
+		},
+		{
+			name:         "machines are spread across the zones which aren't cordoned",
+			machine:      &clusterv1.Machine{},
+			azureMachine: &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Name: "machine-name"}},
+			cordoned:     []string{"2"},
+			want:         []string{"1", "3"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := &MachineScope{
+				ClusterScoper: clusterScope,
+				Machine:       tt.machine,
+				AzureMachine:  tt.azureMachine,
+			}
+			m.avoidCordonedZones(tt.cordoned)
+			g.Expect(tt.want).To(ContainElement(m.AvailabilityZone()))
+
+			// The zone doesn't change once the zones are uncordoned.
+			zone := m.AvailabilityZone()
+			m.avoidCordonedZones(nil)
+			g.Expect(m.AvailabilityZone()).To(Equal(zone))
+		})
+	}
+}
+
 func TestMachineScope_Namespace(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -781,104 +988,523 @@ func TestMachineScope_Namespace(t *testing.T) {
 	}
 }
 
-func TestMachineScope_IsControlPlane(t *testing.T) {
+func TestMachineScope_GetUserData(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-user-data",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"value":  []byte("fake-user-data"),
+			"custom": []byte("custom-user-data"),
+		},
+	}
+
 	tests := []struct {
-		name         string
-		machineScope MachineScope
-		want         bool
+		name    string
+		source  *infrav1.UserDataSource
+		want    string
+		wantErr bool
 	}{
 		{
-			name: "returns false when machine is not control plane",
-			machineScope: MachineScope{
-				Machine: &clusterv1.Machine{},
+			name:   "returns no user data if not specified",
+			source: nil,
+			want:   "",
+		},
+		{
+			name:   "returns the value key of the secret by default",
+			source: &infrav1.UserDataSource{SecretName: "my-user-data"},
+			want:   "ZmFrZS11c2VyLWRhdGE=",
+		},
+		{
+			name:   "returns the key of the secret",
+			source: &infrav1.UserDataSource{SecretName: "my-user-data", Key: "custom"},
+			want:   "Y3VzdG9tLXVzZXItZGF0YQ==",
+		},
+		{
+			name:    "fails if the key is missing from the secret",
+			source:  &infrav1.UserDataSource{SecretName: "my-user-data", Key: "missing"},
+			wantErr: true,
+		},
+		{
+			name:    "fails if the secret is missing",
+			source:  &infrav1.UserDataSource{SecretName: "missing"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(secret).Build(),
 				AzureMachine: &infrav1.AzureMachine{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "machine-name",
+						Name:      "machine-name",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						UserData: tt.source,
 					},
 				},
+			}
+			got, err := machineScope.GetUserData(context.TODO())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_GetBootstrapDataTrustedCAs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	caCerts := "-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----\n"
+	bootstrapSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bootstrap-data",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"value": []byte("#cloud-config\nruncmd:\n- kubeadm join\n"),
+		},
+	}
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cas",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"ca.crt": []byte(caCerts),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		trustedCAs *infrav1.TrustedCASource
+		osType     string
+		expect     func(g *WithT, bootstrapData string)
+		wantErr    bool
+	}{
+		{
+			name:       "bootstrap data is unchanged without trusted CAs",
+			trustedCAs: nil,
+			osType:     azure.LinuxOS,
+			expect: func(g *WithT, bootstrapData string) {
+				g.Expect(bootstrapData).To(Equal("#cloud-config\nruncmd:\n- kubeadm join\n"))
 			},
-			want: false,
 		},
 		{
-			name: "returns true when machine is control plane",
-			machineScope: MachineScope{
-				Machine: &clusterv1.Machine{
-					ObjectMeta: metav1.ObjectMeta{
-						Labels: map[string]string{
-							clusterv1.MachineControlPlaneLabelName: "",
+			name:       "trusted CAs are added to the bootstrap data",
+			trustedCAs: &infrav1.TrustedCASource{SecretName: "my-cas"},
+			osType:     azure.LinuxOS,
+			expect: func(g *WithT, bootstrapData string) {
+				g.Expect(bootstrapData).To(ContainSubstring(cloudinit.TrustedCAsPath))
+				g.Expect(bootstrapData).To(ContainSubstring("update-ca-certificates"))
+			},
+		},
+		{
+			name:       "trusted CAs are not added to the bootstrap data of Windows machines",
+			trustedCAs: &infrav1.TrustedCASource{SecretName: "my-cas"},
+			osType:     azure.WindowsOS,
+			expect: func(g *WithT, bootstrapData string) {
+				g.Expect(bootstrapData).To(Equal("#cloud-config\nruncmd:\n- kubeadm join\n"))
+			},
+		},
+		{
+			name:       "fails if the key is missing from the secret",
+			trustedCAs: &infrav1.TrustedCASource{SecretName: "my-cas", Key: "missing"},
+			osType:     azure.LinuxOS,
+			wantErr:    true,
+		},
+		{
+			name:       "fails if the secret is missing",
+			trustedCAs: &infrav1.TrustedCASource{SecretName: "missing"},
+			osType:     azure.LinuxOS,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(bootstrapSecret, caSecret).Build(),
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							TrustedCAs: tt.trustedCAs,
 						},
 					},
 				},
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{
+						Bootstrap: clusterv1.Bootstrap{DataSecretName: to.StringPtr("bootstrap-data")},
+					},
+				},
 				AzureMachine: &infrav1.AzureMachine{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "machine-name",
+						Name:      "machine-name",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{OSType: tt.osType},
 					},
 				},
-			},
-			want: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.machineScope.IsControlPlane(); got != tt.want {
-				t.Errorf("IsControlPlane() = %v, want %v", got, tt.want)
 			}
+			got, err := machineScope.GetBootstrapData(context.TODO())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			bootstrapData, err := base64.StdEncoding.DecodeString(got)
+			g.Expect(err).NotTo(HaveOccurred())
+			tt.expect(g, string(bootstrapData))
 		})
 	}
 }
 
-func TestMachineScope_Role(t *testing.T) {
+func TestMachineScope_GetBootstrapDataRegistryMirrors(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	bootstrapSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bootstrap-data",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"value": []byte("#cloud-config\nruncmd:\n- kubeadm join\n"),
+		},
+	}
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cas",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"ca.crt": []byte("-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----\n"),
+		},
+	}
+	credentialsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mirror-credentials",
+			Namespace: "default",
+		},
+		Type: corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("puller"),
+			corev1.BasicAuthPasswordKey: []byte("s3cr3t"),
+		},
+	}
+
 	tests := []struct {
-		name         string
-		machineScope MachineScope
-		want         string
+		name       string
+		mirrors    []infrav1.RegistryMirror
+		trustedCAs *infrav1.TrustedCASource
+		expect     func(g *WithT, bootstrapData string)
+		wantErr    bool
 	}{
 		{
-			name: "returns node when machine is worker",
-			machineScope: MachineScope{
-				Machine: &clusterv1.Machine{},
-				AzureMachine: &infrav1.AzureMachine{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "machine-name",
-					},
-				},
+			name:    "bootstrap data is unchanged without registry mirrors",
+			mirrors: nil,
+			expect: func(g *WithT, bootstrapData string) {
+				g.Expect(bootstrapData).To(Equal("#cloud-config\nruncmd:\n- kubeadm join\n"))
 			},
-			want: infrav1.Node,
 		},
 		{
-			name: "returns control-plane when machine is control plane",
-			machineScope: MachineScope{
-				Machine: &clusterv1.Machine{
-					ObjectMeta: metav1.ObjectMeta{
-						Labels: map[string]string{
-							clusterv1.MachineControlPlaneLabelName: "",
+			name: "registry mirrors and their credentials are added to the bootstrap data",
+			mirrors: []infrav1.RegistryMirror{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}, CredentialsSecretName: "mirror-credentials"},
+			},
+			expect: func(g *WithT, bootstrapData string) {
+				g.Expect(bootstrapData).To(ContainSubstring("/etc/containerd/certs.d/docker.io/hosts.toml"))
+				g.Expect(bootstrapData).To(ContainSubstring(cloudinit.ContainerdConfigPath))
+				g.Expect(bootstrapData).To(ContainSubstring("s3cr3t"))
+			},
+		},
+		{
+			name: "mirrored registries keep the host configuration of their mirrors",
+			mirrors: []infrav1.RegistryMirror{
+				{Registry: "registry.example.com", Endpoints: []string{"https://mirror.example.com"}},
+			},
+			trustedCAs: &infrav1.TrustedCASource{SecretName: "my-cas", Registries: []string{"registry.example.com"}},
+			expect: func(g *WithT, bootstrapData string) {
+				g.Expect(strings.Count(bootstrapData, "/etc/containerd/certs.d/registry.example.com/hosts.toml")).To(Equal(1))
+				g.Expect(bootstrapData).To(ContainSubstring(cloudinit.TrustedCAsPath))
+			},
+		},
+		{
+			name: "fails if the credentials secret is missing",
+			mirrors: []infrav1.RegistryMirror{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}, CredentialsSecretName: "missing"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(bootstrapSecret, caSecret, credentialsSecret).Build(),
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							TrustedCAs:      tt.trustedCAs,
+							RegistryMirrors: tt.mirrors,
 						},
 					},
 				},
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{
+						Bootstrap: clusterv1.Bootstrap{DataSecretName: to.StringPtr("bootstrap-data")},
+					},
+				},
 				AzureMachine: &infrav1.AzureMachine{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "machine-name",
+						Name:      "machine-name",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{OSType: azure.LinuxOS},
 					},
 				},
-			},
-			want: infrav1.ControlPlane,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.machineScope.Role(); got != tt.want {
-				t.Errorf("Role() = %v, want %v", got, tt.want)
 			}
+			got, err := machineScope.GetBootstrapData(context.TODO())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			bootstrapData, err := base64.StdEncoding.DecodeString(got)
+			g.Expect(err).NotTo(HaveOccurred())
+			tt.expect(g, string(bootstrapData))
 		})
 	}
 }
 
-func TestMachineScope_AvailabilitySet(t *testing.T) {
+func TestMachineScope_GetBootstrapDataIgnition(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	ignitionConfig := `{"ignition":{"version":"3.3.0"},"storage":{"files":[]}}`
+
 	tests := []struct {
-		name                         string
-		machineScope                 MachineScope
-		wantAvailabilitySetName      string
-		wantAvailabilitySetExistence bool
+		name        string
+		data        map[string][]byte
+		proxyConfig *infrav1.ProxyConfig
+		osType      string
+		expect      func(g *WithT, bootstrapData string)
+		wantErr     bool
+	}{
+		{
+			name: "Ignition bootstrap data is delivered unchanged",
+			data: map[string][]byte{
+				"value":  []byte(ignitionConfig),
+				"format": []byte("ignition"),
+			},
+			osType: azure.LinuxOS,
+			expect: func(g *WithT, bootstrapData string) {
+				g.Expect(bootstrapData).To(Equal(ignitionConfig))
+			},
+		},
+		{
+			name: "Ignition bootstrap data is detected without a format",
+			data: map[string][]byte{
+				"value": []byte(ignitionConfig),
+			},
+			osType: azure.LinuxOS,
+			expect: func(g *WithT, bootstrapData string) {
+				g.Expect(bootstrapData).To(Equal(ignitionConfig))
+			},
+		},
+		{
+			name: "cloud-config bootstrap data is amended",
+			data: map[string][]byte{
+				"value":  []byte("#cloud-config\nruncmd:\n- kubeadm join\n"),
+				"format": []byte("cloud-config"),
+			},
+			proxyConfig: &infrav1.ProxyConfig{HTTPProxy: "http://proxy.example.com:3128"},
+			osType:      azure.LinuxOS,
+			expect: func(g *WithT, bootstrapData string) {
+				g.Expect(bootstrapData).To(ContainSubstring(cloudinit.EnvironmentPath))
+			},
+		},
+		{
+			name: "fails if the proxy can't be added to Ignition bootstrap data",
+			data: map[string][]byte{
+				"value":  []byte(ignitionConfig),
+				"format": []byte("ignition"),
+			},
+			proxyConfig: &infrav1.ProxyConfig{HTTPProxy: "http://proxy.example.com:3128"},
+			osType:      azure.LinuxOS,
+			wantErr:     true,
+		},
+		{
+			name: "fails for Ignition bootstrap data on Windows",
+			data: map[string][]byte{
+				"value":  []byte(ignitionConfig),
+				"format": []byte("ignition"),
+			},
+			osType:  azure.WindowsOS,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			bootstrapSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bootstrap-data",
+					Namespace: "default",
+				},
+				Data: tt.data,
+			}
+			machineScope := MachineScope{
+				client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(bootstrapSecret).Build(),
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ProxyConfig: tt.proxyConfig,
+						},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{
+						Bootstrap: clusterv1.Bootstrap{DataSecretName: to.StringPtr("bootstrap-data")},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "machine-name",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{OSType: tt.osType},
+					},
+				},
+			}
+			got, err := machineScope.GetBootstrapData(context.TODO())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+				g.Expect(reconcileError.IsTerminal()).To(BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			bootstrapData, err := base64.StdEncoding.DecodeString(got)
+			g.Expect(err).NotTo(HaveOccurred())
+			tt.expect(g, string(bootstrapData))
+		})
+	}
+}
+
+func TestMachineScope_IsControlPlane(t *testing.T) {
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         bool
+	}{
+		{
+			name: "returns false when machine is not control plane",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "returns true when machine is control plane",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							clusterv1.MachineControlPlaneLabelName: "",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.machineScope.IsControlPlane(); got != tt.want {
+				t.Errorf("IsControlPlane() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMachineScope_Role(t *testing.T) {
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         string
+	}{
+		{
+			name: "returns node when machine is worker",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+			},
+			want: infrav1.Node,
+		},
+		{
+			name: "returns control-plane when machine is control plane",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							clusterv1.MachineControlPlaneLabelName: "",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+			},
+			want: infrav1.ControlPlane,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.machineScope.Role(); got != tt.want {
+				t.Errorf("Role() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMachineScope_AvailabilitySet(t *testing.T) {
+	tests := []struct {
+		name                         string
+		machineScope                 MachineScope
+		wantAvailabilitySetName      string
+		wantAvailabilitySetExistence bool
 	}{
 		{
 			name: "returns empty and false if availability set is not enabled",
@@ -1040,6 +1666,51 @@ func TestMachineScope_AvailabilitySet(t *testing.T) {
 	}
 }
 
+func TestMachineScope_ProximityPlacementGroupID(t *testing.T) {
+	clusterPPGID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-cluster-ppg"
+
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         string
+	}{
+		{
+			name: "returns empty if neither the machine nor the cluster have a proximity placement group",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{},
+			},
+			want: "",
+		},
+		{
+			name: "returns the proximity placement group of the cluster",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{},
+				clusterPPGID: clusterPPGID,
+			},
+			want: clusterPPGID,
+		},
+		{
+			name: "returns the proximity placement group of the machine over the one of the cluster",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						ProximityPlacementGroupID: to.StringPtr("/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Compute/proximityPlacementGroups/other-ppg"),
+					},
+				},
+				clusterPPGID: clusterPPGID,
+			},
+			want: "/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Compute/proximityPlacementGroups/other-ppg",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.machineScope.ProximityPlacementGroupID(); got != tt.want {
+				t.Errorf("ProximityPlacementGroupID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMachineScope_VMState(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1280,6 +1951,61 @@ func TestMachineScope_GetVMImage(t *testing.T) {
 			}(),
 			wantErr: false,
 		},
+		{
+			name: "if no image is specified, returns the image resolved by the image resolver",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: clusterv1.MachineSpec{
+						Version: pointer.String("v1.23.6"),
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+				imageResolver: azure.CommunityGalleryImageResolver{},
+			},
+			want: &infrav1.Image{
+				CommunityGallery: &infrav1.AzureCommunityGalleryImage{
+					Gallery: azure.DefaultCommunityGalleryName,
+					Name:    azure.DefaultCommunityGalleryUbuntuImageName,
+					Version: "1.23.6",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "if no image is specified, returns the image previously resolved in the AzureMachine status",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: clusterv1.MachineSpec{
+						Version: pointer.String("v1.23.6"),
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Status: infrav1.AzureMachineStatus{
+						Image: &infrav1.Image{
+							ID: pointer.StringPtr("1"),
+						},
+					},
+				},
+				imageResolver: azure.CommunityGalleryImageResolver{},
+			},
+			want: &infrav1.Image{
+				ID: pointer.StringPtr("1"),
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1295,32 +2021,122 @@ func TestMachineScope_GetVMImage(t *testing.T) {
 			if !reflect.DeepEqual(gotImage, tt.want) {
 				t.Errorf("GetVMImage(), gotImage = %v, wantImage %v", gotImage, tt.want)
 			}
+			if tt.machineScope.AzureMachine.Spec.Image == nil && !reflect.DeepEqual(tt.machineScope.AzureMachine.Status.Image, tt.want) {
+				t.Errorf("GetVMImage(), gotStatusImage = %v, wantStatusImage %v", tt.machineScope.AzureMachine.Status.Image, tt.want)
+			}
 		})
 	}
 }
 
-func TestMachineScope_NICSpecs(t *testing.T) {
+func TestMachineScope_GetVMImageLatestSharedGallery(t *testing.T) {
+	galleryImage := func(version string) *infrav1.Image {
+		return &infrav1.Image{
+			SharedGallery: &infrav1.AzureSharedGalleryImage{
+				SubscriptionID: "123",
+				ResourceGroup:  "my-rg",
+				Gallery:        "my-gallery",
+				Name:           "my-image",
+				Version:        version,
+			},
+		}
+	}
+	imageVersion := compute.GalleryImageVersion{
+		Name: to.StringPtr("1.2.0"),
+		GalleryImageVersionProperties: &compute.GalleryImageVersionProperties{
+			ProvisioningState: compute.ProvisioningState3Succeeded,
+			PublishingProfile: &compute.GalleryImageVersionPublishingProfile{
+				TargetRegions: &[]compute.TargetRegion{{Name: to.StringPtr("West US 2")}},
+			},
+		},
+	}
+
 	tests := []struct {
-		name         string
-		machineScope MachineScope
-		want         []azure.NICSpec
+		name        string
+		providerID  *string
+		statusImage *infrav1.Image
+		expect      func(m *mock_galleryimageversions.MockClientMockRecorder)
+		want        *infrav1.Image
 	}{
 		{
-			name: "Node Machine with no nat gateway and no public IP address",
-			machineScope: MachineScope{
+			name: "latest version is resolved and pinned in the status",
+			expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.List(gomock.Any(), "123", "my-rg", "my-gallery", "my-image").Return([]compute.GalleryImageVersion{imageVersion}, nil)
+			},
+			want: galleryImage("1.2.0"),
+		},
+		{
+			name:        "pinned version is kept",
+			statusImage: galleryImage("1.1.0"),
+			want:        galleryImage("1.1.0"),
+		},
+		{
+			name:       "latest version of an existing VM is not resolved",
+			providerID: to.StringPtr("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm"),
+			want:       galleryImage("latest"),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_galleryimageversions.NewMockClient(mockCtrl)
+			if tc.expect != nil {
+				tc.expect(clientMock.EXPECT())
+			}
+
+			machineScope := MachineScope{
 				ClusterScoper: &ClusterScope{
-					Cluster: &clusterv1.Cluster{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      "cluster",
-							Namespace: "default",
-						},
-					},
 					AzureCluster: &infrav1.AzureCluster{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      "cluster",
-							Namespace: "default",
-							OwnerReferences: []metav1.OwnerReference{
-								{
+						Spec: infrav1.AzureClusterSpec{Location: "westus2"},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						ProviderID: tc.providerID,
+						Image:      galleryImage("latest"),
+					},
+					Status: infrav1.AzureMachineStatus{
+						Image: tc.statusImage,
+					},
+				},
+				galleryImageVersions: clientMock,
+			}
+
+			got, err := machineScope.GetVMImage(context.TODO())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tc.want))
+			if tc.providerID == nil {
+				g.Expect(machineScope.AzureMachine.Status.Image).To(Equal(tc.want))
+			}
+			g.Expect(machineScope.AzureMachine.Spec.Image).To(Equal(galleryImage("latest")))
+		})
+	}
+}
+
+func TestMachineScope_NICSpecs(t *testing.T) {
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         []azure.NICSpec
+	}{
+		{
+			name: "Node Machine with no nat gateway and no public IP address",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+							OwnerReferences: []metav1.OwnerReference{
+								{
 									APIVersion: "cluster.x-k8s.io/v1beta1",
 									Kind:       "Cluster",
 									Name:       "cluster",
@@ -1722,6 +2538,105 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 	}
 }
 
+func TestMachineScope_NICSpecsPool(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, feature.NICPool, true)()
+
+	newMachineScope := func(labels, annotations map[string]string) MachineScope {
+		return MachineScope{
+			ClusterScoper: &ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster",
+						Namespace: "default",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{
+								Name:          "vnet1",
+								ResourceGroup: "rg1",
+							},
+							Subnets: []infrav1.SubnetSpec{
+								{
+									Role: infrav1.SubnetNode,
+									Name: "subnet1",
+								},
+							},
+							NodeOutboundLB: &infrav1.LoadBalancerSpec{
+								Name: "outbound-lb",
+							},
+						},
+					},
+				},
+			},
+			AzureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "machine",
+					Annotations: annotations,
+				},
+				Spec: infrav1.AzureMachineSpec{
+					SubnetName: "subnet1",
+				},
+			},
+			Machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "machine",
+					Labels: labels,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		wantName     string
+		wantPoolName string
+		wantPoolSize int
+	}{
+		{
+			name:         "Machine without a MachineDeployment does not use a pool",
+			machineScope: newMachineScope(nil, map[string]string{infrav1.NICPoolSizeAnnotation: "3"}),
+			wantName:     "machine-nic",
+		},
+		{
+			name:         "Machine without a pool size drains the pool",
+			machineScope: newMachineScope(map[string]string{clusterv1.MachineDeploymentLabelName: "md-0"}, nil),
+			wantName:     "machine-nic",
+			wantPoolName: "md-0",
+			wantPoolSize: 0,
+		},
+		{
+			name:         "Machine of a MachineDeployment with a pool size uses the pool",
+			machineScope: newMachineScope(map[string]string{clusterv1.MachineDeploymentLabelName: "md-0"}, map[string]string{infrav1.NICPoolSizeAnnotation: "3"}),
+			wantName:     "machine-nic",
+			wantPoolName: "md-0",
+			wantPoolSize: 3,
+		},
+		{
+			name: "Machine with a claimed network interface uses its name",
+			machineScope: newMachineScope(map[string]string{clusterv1.MachineDeploymentLabelName: "md-0"}, map[string]string{
+				infrav1.NICPoolSizeAnnotation:  "3",
+				infrav1.NICPoolClaimAnnotation: "md-0-pool-abcde-nic",
+			}),
+			wantName:     "md-0-pool-abcde-nic",
+			wantPoolName: "md-0",
+			wantPoolSize: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			nicSpecs := tt.machineScope.NICSpecs()
+			g.Expect(nicSpecs).To(HaveLen(1))
+			g.Expect(nicSpecs[0].Name).To(Equal(tt.wantName))
+			g.Expect(nicSpecs[0].PoolName).To(Equal(tt.wantPoolName))
+			g.Expect(nicSpecs[0].PoolSize).To(Equal(tt.wantPoolSize))
+		})
+	}
+}
+
 func TestDiskSpecs(t *testing.T) {
 	testcases := []struct {
 		name         string
@@ -1877,6 +2792,61 @@ func TestDiskSpecs(t *testing.T) {
 					ResourceGroup: "my-rg",
 				},
 			},
+		}, {
+			name: "data disks kept on machine deletion",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-azure-machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							DiskSizeGB: to.Int32Ptr(30),
+							OSType:     "Linux",
+						},
+						DataDisks: []infrav1.DataDisk{
+							{
+								NameSuffix:   "etcddisk",
+								DeleteOption: infrav1.DiskDeleteOptionDelete,
+							},
+							{
+								NameSuffix:   "datadisk",
+								DeleteOption: infrav1.DiskDeleteOptionDetach,
+							},
+						},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&disks.DiskSpec{
+					Name:          "my-azure-machine_OSDisk",
+					ResourceGroup: "my-rg",
+				},
+				&disks.DiskSpec{
+					Name:          "my-azure-machine_etcddisk",
+					ResourceGroup: "my-rg",
+				},
+			},
 		},
 	}
 
@@ -1891,3 +2861,490 @@ func TestDiskSpecs(t *testing.T) {
 		})
 	}
 }
+
+func TestSharedDiskSpecs(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope := MachineScope{
+		ClusterScoper: &ClusterScope{
+			AzureClients: AzureClients{
+				EnvironmentSettings: auth.EnvironmentSettings{
+					Values: map[string]string{auth.SubscriptionID: "123"},
+				},
+			},
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+			},
+		},
+		AzureMachine: &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-azure-machine",
+			},
+			Spec: infrav1.AzureMachineSpec{
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "etcddisk",
+					},
+					{
+						NameSuffix: "quorum",
+						DiskSizeGB: 32,
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+							DiskEncryptionSet:  &infrav1.DiskEncryptionSetParameters{Name: "my-des"},
+						},
+						MaxShares: to.Int32Ptr(3),
+					},
+				},
+			},
+		},
+		Machine: &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "machine",
+			},
+			Spec: clusterv1.MachineSpec{
+				FailureDomain: to.StringPtr("2"),
+			},
+		},
+	}
+
+	g.Expect(machineScope.SharedDiskSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&disks.SharedDiskSpec{
+			Name:                "cluster_quorum",
+			ResourceGroup:       "my-rg",
+			Location:            "westus",
+			Zone:                "2",
+			SizeGB:              32,
+			StorageAccountType:  "Premium_LRS",
+			MaxShares:           3,
+			DiskEncryptionSetID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des",
+			ClusterName:         "cluster",
+			AdditionalTags:      infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
+		},
+	}))
+}
+
+func TestDiskUpdateSpecs(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope := MachineScope{
+		ClusterScoper: &ClusterScope{
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+				},
+			},
+		},
+		AzureMachine: &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-azure-machine",
+			},
+			Spec: infrav1.AzureMachineSpec{
+				OSDisk: infrav1.OSDisk{
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+						PerformanceTier:    "P30",
+					},
+				},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "etcddisk",
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+							PerformanceTier:    "P40",
+						},
+					},
+					{
+						NameSuffix: "untiered",
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+						},
+					},
+					{
+						NameSuffix:   "kept",
+						DeleteOption: infrav1.DiskDeleteOptionDetach,
+					},
+					{
+						NameSuffix: "quorum",
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+							PerformanceTier:    "P30",
+						},
+						MaxShares: to.Int32Ptr(3),
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(machineScope.DiskUpdateSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&disks.DiskSpec{
+			Name:            "my-azure-machine_OSDisk",
+			ResourceGroup:   "my-rg",
+			PerformanceTier: "P30",
+		},
+		&disks.DiskSpec{
+			Name:            "my-azure-machine_etcddisk",
+			ResourceGroup:   "my-rg",
+			PerformanceTier: "P40",
+		},
+		&disks.DiskSpec{
+			Name:          "my-azure-machine_kept",
+			ResourceGroup: "my-rg",
+			MachineName:   "my-azure-machine",
+		},
+	}))
+}
+
+func TestMachineScope_ImageReplicationSpec(t *testing.T) {
+	sharedGalleryImage := &infrav1.Image{
+		SharedGallery: &infrav1.AzureSharedGalleryImage{
+			SubscriptionID: "123",
+			ResourceGroup:  "my-rg",
+			Gallery:        "my-gallery",
+			Name:           "my-image",
+			Version:        "1.0.0",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		mode        azure.ImageReplicationMode
+		image       *infrav1.Image
+		statusImage *infrav1.Image
+		providerID  *string
+		want        *azure.ImageReplicationSpec
+	}{
+		{
+			name:  "replication isn't checked when disabled",
+			mode:  azure.ImageReplicationDisabled,
+			image: sharedGalleryImage,
+			want:  nil,
+		},
+		{
+			name:  "shared gallery image",
+			mode:  azure.ImageReplicationVerify,
+			image: sharedGalleryImage,
+			want: &azure.ImageReplicationSpec{
+				SubscriptionID: "123",
+				ResourceGroup:  "my-rg",
+				Gallery:        "my-gallery",
+				Image:          "my-image",
+				Version:        "1.0.0",
+				Location:       "westus2",
+			},
+		},
+		{
+			name:  "gallery image version ID",
+			mode:  azure.ImageReplicationReplicate,
+			image: &infrav1.Image{ID: to.StringPtr("/subscriptions/456/resourceGroups/other-rg/providers/Microsoft.Compute/galleries/other-gallery/images/other-image/versions/2.0.0")},
+			want: &azure.ImageReplicationSpec{
+				SubscriptionID: "456",
+				ResourceGroup:  "other-rg",
+				Gallery:        "other-gallery",
+				Image:          "other-image",
+				Version:        "2.0.0",
+				Location:       "westus2",
+				Replicate:      true,
+			},
+		},
+		{
+			name:  "managed image ID",
+			mode:  azure.ImageReplicationVerify,
+			image: &infrav1.Image{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/my-image")},
+			want:  nil,
+		},
+		{
+			name: "latest version",
+			mode: azure.ImageReplicationVerify,
+			image: &infrav1.Image{
+				SharedGallery: &infrav1.AzureSharedGalleryImage{
+					SubscriptionID: "123",
+					ResourceGroup:  "my-rg",
+					Gallery:        "my-gallery",
+					Name:           "my-image",
+					Version:        "latest",
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "pinned latest version",
+			mode: azure.ImageReplicationVerify,
+			image: &infrav1.Image{
+				SharedGallery: &infrav1.AzureSharedGalleryImage{
+					SubscriptionID: "123",
+					ResourceGroup:  "my-rg",
+					Gallery:        "my-gallery",
+					Name:           "my-image",
+					Version:        "latest",
+				},
+			},
+			statusImage: sharedGalleryImage,
+			want: &azure.ImageReplicationSpec{
+				SubscriptionID: "123",
+				ResourceGroup:  "my-rg",
+				Gallery:        "my-gallery",
+				Image:          "my-image",
+				Version:        "1.0.0",
+				Location:       "westus2",
+			},
+		},
+		{
+			name:  "marketplace image",
+			mode:  azure.ImageReplicationVerify,
+			image: &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{Publisher: "cncf-upstream", Offer: "capi", SKU: "k8s-1dot22dot1-ubuntu-2004", Version: "latest"}},
+			want:  nil,
+		},
+		{
+			name:       "replication isn't checked once the VM exists",
+			mode:       azure.ImageReplicationVerify,
+			image:      sharedGalleryImage,
+			providerID: to.StringPtr("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm"),
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							Location: "westus2",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						Image:      tt.image,
+						ProviderID: tt.providerID,
+					},
+					Status: infrav1.AzureMachineStatus{
+						Image: tt.statusImage,
+					},
+				},
+				imageReplication: tt.mode,
+			}
+			g.Expect(machineScope.ImageReplicationSpec()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_MarketplaceTermsSpec(t *testing.T) {
+	thirdPartyImage := &infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			Publisher:       "my-publisher",
+			Offer:           "my-offer",
+			SKU:             "my-sku",
+			Version:         "1.0.0",
+			ThirdPartyImage: true,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		acceptTerms bool
+		image       *infrav1.Image
+		providerID  *string
+		want        *azure.MarketplaceTermsSpec
+	}{
+		{
+			name:        "terms aren't accepted when disabled",
+			acceptTerms: false,
+			image:       thirdPartyImage,
+			want:        nil,
+		},
+		{
+			name:        "third party marketplace image",
+			acceptTerms: true,
+			image:       thirdPartyImage,
+			want: &azure.MarketplaceTermsSpec{
+				Publisher: "my-publisher",
+				Offer:     "my-offer",
+				Plan:      "my-sku",
+			},
+		},
+		{
+			name:        "shared gallery image with a purchase plan",
+			acceptTerms: true,
+			image: &infrav1.Image{
+				SharedGallery: &infrav1.AzureSharedGalleryImage{
+					SubscriptionID: "123",
+					ResourceGroup:  "my-rg",
+					Gallery:        "my-gallery",
+					Name:           "my-image",
+					Version:        "1.0.0",
+					Publisher:      to.StringPtr("my-publisher"),
+					Offer:          to.StringPtr("my-offer"),
+					SKU:            to.StringPtr("my-sku"),
+				},
+			},
+			want: &azure.MarketplaceTermsSpec{
+				Publisher: "my-publisher",
+				Offer:     "my-offer",
+				Plan:      "my-sku",
+			},
+		},
+		{
+			name:        "first party marketplace image",
+			acceptTerms: true,
+			image:       &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{Publisher: "cncf-upstream", Offer: "capi", SKU: "k8s-1dot22dot1-ubuntu-2004", Version: "latest"}},
+			want:        nil,
+		},
+		{
+			name:        "default image",
+			acceptTerms: true,
+			image:       nil,
+			want:        nil,
+		},
+		{
+			name:        "terms aren't accepted once the VM exists",
+			acceptTerms: true,
+			image:       thirdPartyImage,
+			providerID:  to.StringPtr("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm"),
+			want:        nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						Image:      tt.image,
+						ProviderID: tt.providerID,
+					},
+				},
+				acceptTerms: tt.acceptTerms,
+			}
+			g.Expect(machineScope.MarketplaceTermsSpec()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_VHDImageSpec(t *testing.T) {
+	vhdImage := &infrav1.Image{
+		VHD: &infrav1.AzureVHDImage{
+			URI:              "https://myaccount.blob.core.windows.net/vhds/image.vhd",
+			HyperVGeneration: "V2",
+		},
+	}
+	imageName := azure.GenerateVHDImageName("my-cluster", "https://myaccount.blob.core.windows.net/vhds/image.vhd")
+
+	tests := []struct {
+		name          string
+		image         *infrav1.Image
+		wantSpec      azure.ResourceSpecGetter
+		wantImageByID *infrav1.Image
+	}{
+		{
+			name:  "VHD image",
+			image: vhdImage,
+			wantSpec: &vhdimages.VHDImageSpec{
+				Name:             imageName,
+				ResourceGroup:    "my-rg",
+				Location:         "westus",
+				BlobURI:          "https://myaccount.blob.core.windows.net/vhds/image.vhd",
+				OSType:           "Linux",
+				HyperVGeneration: "V2",
+				ClusterName:      "my-cluster",
+				AdditionalTags:   infrav1.Tags{"kubernetes.io_cluster_my-cluster": "owned"},
+			},
+			wantImageByID: &infrav1.Image{
+				ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/" + imageName),
+			},
+		},
+		{
+			name:          "marketplace image",
+			image:         &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{Publisher: "cncf-upstream", Offer: "capi", SKU: "k8s-1dot22dot1-ubuntu-2004", Version: "latest"}},
+			wantSpec:      nil,
+			wantImageByID: &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{Publisher: "cncf-upstream", Offer: "capi", SKU: "k8s-1dot22dot1-ubuntu-2004", Version: "latest"}},
+		},
+		{
+			name:          "default image",
+			image:         nil,
+			wantSpec:      nil,
+			wantImageByID: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Values: map[string]string{auth.SubscriptionID: "123"},
+						},
+					},
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							Location:      "westus",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						Image:  tt.image,
+						OSDisk: infrav1.OSDisk{OSType: "Linux"},
+					},
+				},
+			}
+			if tt.wantSpec == nil {
+				g.Expect(machineScope.VHDImageSpec()).To(BeNil())
+			} else {
+				g.Expect(machineScope.VHDImageSpec()).To(Equal(tt.wantSpec))
+			}
+			if tt.wantImageByID == nil {
+				g.Expect(machineScope.vmImage(tt.image)).To(BeNil())
+			} else {
+				g.Expect(machineScope.vmImage(tt.image)).To(Equal(tt.wantImageByID))
+			}
+		})
+	}
+}
+
+func TestWithDiskEncryptionSetIDs(t *testing.T) {
+	g := NewWithT(t)
+
+	dataDisks := []infrav1.DataDisk{
+		{
+			NameSuffix: "by-name",
+			ManagedDisk: &infrav1.ManagedDiskParameters{
+				DiskEncryptionSet: &infrav1.DiskEncryptionSetParameters{Name: "my-des"},
+			},
+		},
+		{
+			NameSuffix: "by-id",
+			ManagedDisk: &infrav1.ManagedDiskParameters{
+				DiskEncryptionSet: &infrav1.DiskEncryptionSetParameters{ID: "/subscriptions/456/resourceGroups/other-rg/providers/Microsoft.Compute/diskEncryptionSets/other-des"},
+			},
+		},
+		{
+			NameSuffix: "unencrypted",
+		},
+	}
+
+	resolved := withDiskEncryptionSetIDs(dataDisks, "123", "my-rg")
+	g.Expect(resolved[0].ManagedDisk.DiskEncryptionSet.ID).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des"))
+	g.Expect(resolved[1].ManagedDisk.DiskEncryptionSet.ID).To(Equal("/subscriptions/456/resourceGroups/other-rg/providers/Microsoft.Compute/diskEncryptionSets/other-des"))
+	g.Expect(resolved[2].ManagedDisk).To(BeNil())
+	// the spec of the machine is left untouched
+	g.Expect(dataDisks[0].ManagedDisk.DiskEncryptionSet.ID).To(BeEmpty())
+	g.Expect(withDiskEncryptionSetIDs(nil, "123", "my-rg")).To(BeNil())
+}
@@ -19,9 +19,11 @@ package scope
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"strings"
 	"time"
 
+	"sigs.k8s.io/cluster-api-provider-azure/util/cloudinit"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 
 	"github.com/Azure/go-autorest/autorest/to"
@@ -42,15 +44,12 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	machinepool "sigs.k8s.io/cluster-api-provider-azure/azure/scope/strategies/machinepool_deployments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-// ScalesetsServiceName is the name of the scalesets service.
-// TODO: move this to scalesets.go once we remove the usage in this package,
-// added here to avoid a circular dependency.
-const ScalesetsServiceName = "scalesets"
-
 type (
 	// MachinePoolScopeParams defines the input parameters used to create a new MachinePoolScope.
 	MachinePoolScopeParams struct {
@@ -58,6 +57,18 @@ type (
 		MachinePool      *capiv1exp.MachinePool
 		AzureMachinePool *infrav1exp.AzureMachinePool
 		ClusterScope     azure.ClusterScoper
+		// ForceDelete force deletes the VMSS when the AzureMachinePool is deleted.
+		ForceDelete bool
+		// AcceptMarketplaceTerms accepts the marketplace terms of the purchase plan of the image of the machine pool.
+		AcceptMarketplaceTerms bool
+		// CordonedZones are the availability zones of the cluster a new scale set spread across all zones isn't placed in.
+		CordonedZones []string
+		// ProximityPlacementGroupID is the ID of the proximity placement group of the cluster, in which the scale set is
+		// placed unless it sets its own.
+		ProximityPlacementGroupID string
+
+		// workloadNodeLister is only used for testing purposes and provides a way for mocking requests to the workload cluster
+		workloadNodeLister nodeLister
 	}
 
 	// MachinePoolScope defines a scope defined around a machine pool and its cluster.
@@ -68,6 +79,13 @@ type (
 		client           client.Client
 		patchHelper      *patch.Helper
 		vmssState        *azure.VMSS
+		forceDelete      bool
+		acceptTerms      bool
+		cordonedZones    []string
+		clusterPPGID     string
+
+		// workloadNodeLister is only used for testing purposes and provides a way for mocking requests to the workload cluster
+		workloadNodeLister nodeLister
 	}
 
 	// NodeStatus represents the status of a Kubernetes node.
@@ -98,11 +116,16 @@ func NewMachinePoolScope(params MachinePoolScopeParams) (*MachinePoolScope, erro
 	}
 
 	return &MachinePoolScope{
-		client:           params.Client,
-		MachinePool:      params.MachinePool,
-		AzureMachinePool: params.AzureMachinePool,
-		patchHelper:      helper,
-		ClusterScoper:    params.ClusterScope,
+		client:             params.Client,
+		MachinePool:        params.MachinePool,
+		AzureMachinePool:   params.AzureMachinePool,
+		patchHelper:        helper,
+		ClusterScoper:      params.ClusterScope,
+		forceDelete:        params.ForceDelete,
+		acceptTerms:        params.AcceptMarketplaceTerms,
+		cordonedZones:      params.CordonedZones,
+		clusterPPGID:       params.ProximityPlacementGroupID,
+		workloadNodeLister: params.workloadNodeLister,
 	}, nil
 }
 
@@ -113,20 +136,234 @@ func (m *MachinePoolScope) ScaleSetSpec() azure.ScaleSetSpec {
 		Size:                         m.AzureMachinePool.Spec.Template.VMSize,
 		Capacity:                     int64(to.Int32(m.MachinePool.Spec.Replicas)),
 		SSHKeyData:                   m.AzureMachinePool.Spec.Template.SSHPublicKey,
-		OSDisk:                       m.AzureMachinePool.Spec.Template.OSDisk,
-		DataDisks:                    m.AzureMachinePool.Spec.Template.DataDisks,
+		OSDisk:                       withDiskEncryptionSetID(m.AzureMachinePool.Spec.Template.OSDisk, m.SubscriptionID(), m.ResourceGroup()),
+		DataDisks:                    withDiskEncryptionSetIDs(m.AzureMachinePool.Spec.Template.DataDisks, m.SubscriptionID(), m.ResourceGroup()),
 		SubnetName:                   m.AzureMachinePool.Spec.Template.SubnetName,
 		VNetName:                     m.Vnet().Name,
 		VNetResourceGroup:            m.Vnet().ResourceGroup,
 		PublicLBName:                 m.OutboundLBName(infrav1.Node),
 		PublicLBAddressPoolName:      azure.GenerateOutboundBackendAddressPoolName(m.OutboundLBName(infrav1.Node)),
 		AcceleratedNetworking:        m.AzureMachinePool.Spec.Template.AcceleratedNetworking,
+		Diagnostics:                  m.AzureMachinePool.Spec.Template.Diagnostics,
 		Identity:                     m.AzureMachinePool.Spec.Identity,
 		UserAssignedIdentities:       m.AzureMachinePool.Spec.UserAssignedIdentities,
 		SecurityProfile:              m.AzureMachinePool.Spec.Template.SecurityProfile,
 		SpotVMOptions:                m.AzureMachinePool.Spec.Template.SpotVMOptions,
-		FailureDomains:               m.MachinePool.Spec.FailureDomains,
+		FailureDomains:               m.FailureDomains(),
+		AllZones:                     m.AzureMachinePool.Spec.ZonePolicy != nil && m.AzureMachinePool.Spec.ZonePolicy.AllZones,
+		ExcludedZones:                m.cordonedZones,
+		ZoneBalance:                  m.zoneBalance(),
+		PlatformFaultDomainCount:     m.platformFaultDomainCount(),
+		ProximityPlacementGroupID:    m.ProximityPlacementGroupID(),
+		CapacityReservationGroupID:   to.String(m.AzureMachinePool.Spec.Template.CapacityReservationGroupID),
+		ComputerNamePrefix:           m.AzureMachinePool.Spec.Template.ComputerNamePrefix,
 		TerminateNotificationTimeout: m.AzureMachinePool.Spec.Template.TerminateNotificationTimeout,
+		UpgradePolicy:                m.scaleSetUpgradePolicy(),
+		AutomaticRepairsPolicy:       m.scaleSetAutomaticRepairsPolicy(),
+		PriorityMixPolicy:            m.scaleSetPriorityMixPolicy(),
+		OrchestrationMode:            string(m.OrchestrationMode()),
+		ScaleInRule:                  m.scaleInRule(),
+		ScaleUpBatchSize:             m.scaleUpBatchSize(),
+		ForceDelete:                  m.forceDelete,
+	}
+}
+
+// ProximityPlacementGroupID returns the ID of the proximity placement group of the scale set, which defaults to the one
+// of the cluster, or "" if the scale set isn't placed in a proximity placement group.
+func (m *MachinePoolScope) ProximityPlacementGroupID() string {
+	if m.AzureMachinePool.Spec.Template.ProximityPlacementGroupID != nil {
+		return *m.AzureMachinePool.Spec.Template.ProximityPlacementGroupID
+	}
+	return m.clusterPPGID
+}
+
+// FailureDomains returns the availability zones the scale set is pinned to, which default to the failure domains of the
+// MachinePool.
+func (m *MachinePoolScope) FailureDomains() []string {
+	if policy := m.AzureMachinePool.Spec.ZonePolicy; policy != nil && len(policy.Zones) > 0 {
+		return policy.Zones
+	}
+	return m.MachinePool.Spec.FailureDomains
+}
+
+// zoneBalance returns whether the instances are strictly balanced across the zones of the scale set, or nil to leave it
+// to Azure. Scale sets spread across all zones are balanced unless told otherwise.
+func (m *MachinePoolScope) zoneBalance() *bool {
+	policy := m.AzureMachinePool.Spec.ZonePolicy
+	if policy == nil {
+		return nil
+	}
+	if policy.ZoneBalance == nil && policy.AllZones {
+		return to.BoolPtr(true)
+	}
+	return policy.ZoneBalance
+}
+
+// platformFaultDomainCount returns the number of fault domains of the scale set, or nil to leave it to Azure.
+func (m *MachinePoolScope) platformFaultDomainCount() *int32 {
+	if m.AzureMachinePool.Spec.ZonePolicy == nil {
+		return nil
+	}
+	return m.AzureMachinePool.Spec.ZonePolicy.PlatformFaultDomainCount
+}
+
+// OrchestrationMode returns the orchestration mode of the scale set, which defaults to Uniform.
+func (m *MachinePoolScope) OrchestrationMode() infrav1exp.OrchestrationModeType {
+	if m.AzureMachinePool.Spec.OrchestrationMode == "" {
+		return infrav1exp.UniformOrchestrationMode
+	}
+	return m.AzureMachinePool.Spec.OrchestrationMode
+}
+
+// scaleInRule returns the rule Azure follows to choose the instances removed when the scale set is scaled in, or "" if
+// no scale-in policy is specified.
+func (m *MachinePoolScope) scaleInRule() string {
+	if m.AzureMachinePool.Spec.ScaleInPolicy == nil {
+		return ""
+	}
+	return string(m.AzureMachinePool.Spec.ScaleInPolicy.Rule)
+}
+
+// ForceDeleteInstances returns whether the instances removed from the scale set are force deleted.
+func (m *MachinePoolScope) ForceDeleteInstances() bool {
+	return m.AzureMachinePool.Spec.ScaleInPolicy != nil && m.AzureMachinePool.Spec.ScaleInPolicy.ForceDeletion
+}
+
+// RemediationStrategy returns how the failed instances of the scale set are remediated.
+func (m *MachinePoolScope) RemediationStrategy() infrav1exp.RemediationStrategyType {
+	if strategy := m.AzureMachinePool.Spec.RemediationStrategy; strategy != nil && strategy.Type != "" {
+		return strategy.Type
+	}
+	return infrav1exp.DeleteRemediationStrategyType
+}
+
+// remediatesInPlace returns whether the failed machine is reimaged or redeployed in place by its own controller rather
+// than deleted. A machine is remediated in place once, until it is ready again.
+func (m *MachinePoolScope) remediatesInPlace(machine *infrav1exp.AzureMachinePoolMachine) bool {
+	if m.RemediationStrategy() == infrav1exp.DeleteRemediationStrategyType {
+		return false
+	}
+	for _, future := range machine.Status.LongRunningOperationStates {
+		if future.Type == infrav1.PostFuture {
+			// the remediation is running
+			return true
+		}
+	}
+	return !conditions.IsFalse(machine, infrav1.InstanceRemediatedCondition)
+}
+
+// scaleUpBatchSize returns the maximum number of instances to add to the scale set at once.
+func (m *MachinePoolScope) scaleUpBatchSize() int64 {
+	if m.AzureMachinePool.Spec.ScaleUpBatchSize == nil {
+		return infrav1exp.DefaultScaleUpBatchSize
+	}
+	return int64(*m.AzureMachinePool.Spec.ScaleUpBatchSize)
+}
+
+// scaleSetUpgradePolicy returns the upgrade policy of the scale set, or nil if none is specified.
+func (m *MachinePoolScope) scaleSetUpgradePolicy() *azure.ScaleSetUpgradePolicy {
+	policy := m.AzureMachinePool.Spec.UpgradePolicy
+	if policy == nil {
+		return nil
+	}
+
+	spec := &azure.ScaleSetUpgradePolicy{
+		Mode:               string(policy.Mode),
+		AutomaticOSUpgrade: policy.AutomaticOSUpgrade,
+	}
+	if rolling := policy.RollingUpgrade; rolling != nil {
+		spec.MaxBatchInstancePercent = rolling.MaxBatchInstancePercent
+		spec.MaxUnhealthyInstancePercent = rolling.MaxUnhealthyInstancePercent
+		spec.MaxUnhealthyUpgradedInstancePercent = rolling.MaxUnhealthyUpgradedInstancePercent
+		if rolling.PauseTimeBetweenBatches != nil {
+			spec.PauseTimeBetweenBatches = to.StringPtr(fmt.Sprintf("PT%dS", int64(rolling.PauseTimeBetweenBatches.Seconds())))
+		}
+	}
+	if probe := policy.HealthProbe; probe != nil {
+		spec.HealthProbe = &azure.ScaleSetHealthProbe{
+			Protocol:    string(probe.Protocol),
+			Port:        probe.Port,
+			RequestPath: probe.RequestPath,
+		}
+	}
+	return spec
+}
+
+// scaleSetAutomaticRepairsPolicy returns the automatic repairs policy of the scale set, or nil if none is specified.
+func (m *MachinePoolScope) scaleSetAutomaticRepairsPolicy() *azure.ScaleSetAutomaticRepairsPolicy {
+	policy := m.AzureMachinePool.Spec.AutomaticRepairsPolicy
+	if policy == nil {
+		return nil
+	}
+
+	spec := &azure.ScaleSetAutomaticRepairsPolicy{
+		Enabled: policy.Enabled,
+	}
+	if policy.GracePeriod != nil {
+		spec.GracePeriod = to.StringPtr(fmt.Sprintf("PT%dM", int64(policy.GracePeriod.Minutes())))
+	}
+	return spec
+}
+
+// scaleSetPriorityMixPolicy returns the priority mix policy of the scale set, or nil if none is specified.
+func (m *MachinePoolScope) scaleSetPriorityMixPolicy() *azure.ScaleSetPriorityMixPolicy {
+	policy := m.AzureMachinePool.Spec.PriorityMixPolicy
+	if policy == nil {
+		return nil
+	}
+
+	return &azure.ScaleSetPriorityMixPolicy{
+		BaseRegularPriorityCount:           policy.BaseRegularPriorityCount,
+		RegularPriorityPercentageAboveBase: policy.RegularPriorityPercentageAboveBase,
+	}
+}
+
+// azureManagesUpgrades returns true if Azure upgrades the instances of the scale set to its latest model in place,
+// instead of CAPZ replacing them following the deployment strategy.
+func (m *MachinePoolScope) azureManagesUpgrades() bool {
+	policy := m.AzureMachinePool.Spec.UpgradePolicy
+	return policy != nil && (policy.Mode == infrav1exp.UpgradeModeAutomatic || policy.Mode == infrav1exp.UpgradeModeRolling)
+}
+
+// SpotPlacementScoreSpec returns the Spot Placement Score lookup spec. It returns nil if the machine pool does not use
+// Spot VMs or once creation of the scale set has started, as the score is only checked before the scale set is created.
+func (m *MachinePoolScope) SpotPlacementScoreSpec() *azure.SpotPlacementScoreSpec {
+	if m.AzureMachinePool.Spec.Template.SpotVMOptions == nil || m.ProviderID() != "" {
+		return nil
+	}
+	if m.GetLongRunningOperationState(m.Name(), scalesets.ServiceName) != nil {
+		return nil
+	}
+
+	spec := &azure.SpotPlacementScoreSpec{
+		Location: m.Location(),
+		Size:     m.AzureMachinePool.Spec.Template.VMSize,
+		Zones:    m.FailureDomains(),
+		Count:    m.DesiredReplicas(),
+	}
+	if spec.Count < 1 {
+		spec.Count = 1
+	}
+	if m.AzureMachinePool.Spec.SpotPlacementScoreThreshold != nil {
+		spec.Threshold = string(*m.AzureMachinePool.Spec.SpotPlacementScoreThreshold)
+	}
+	return spec
+}
+
+// UpdateSpotPlacementScoreStatus updates the SpotPlacementScoreReady condition on the AzureMachinePool status.
+func (m *MachinePoolScope) UpdateSpotPlacementScoreStatus(score string, err error) {
+	var reconcileError azure.ReconcileError
+	switch {
+	case err == nil:
+		conditions.Set(m.AzureMachinePool, &clusterv1.Condition{
+			Type:    infrav1.SpotPlacementScoreReadyCondition,
+			Status:  corev1.ConditionTrue,
+			Message: fmt.Sprintf("spot placement score is %s", score),
+		})
+	case errors.As(err, &reconcileError) && reconcileError.IsTransient():
+		conditions.MarkFalse(m.AzureMachinePool, infrav1.SpotPlacementScoreReadyCondition, infrav1.SpotPlacementScoreBelowThresholdReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
+	default:
+		conditions.MarkFalse(m.AzureMachinePool, infrav1.SpotPlacementScoreReadyCondition, infrav1.SpotPlacementScoreUnavailableReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
 	}
 }
 
@@ -187,8 +424,13 @@ func (m MachinePoolScope) DesiredReplicas() int32 {
 	return to.Int32(m.MachinePool.Spec.Replicas)
 }
 
-// MaxSurge returns the number of machines to surge, or 0 if the deployment strategy does not support surge.
+// MaxSurge returns the number of machines to surge, or 0 if the deployment strategy does not support surge or if Azure
+// upgrades the instances in place.
 func (m MachinePoolScope) MaxSurge() (int, error) {
+	if m.azureManagesUpgrades() {
+		return 0, nil
+	}
+
 	if surger, ok := m.getDeploymentStrategy().(machinepool.Surger); ok {
 		surgeCount, err := surger.Surge(int(m.DesiredReplicas()))
 		if err != nil {
@@ -297,7 +539,7 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 		return nil
 	}
 
-	if futures.Has(m.AzureMachinePool, m.Name(), ScalesetsServiceName) {
+	if futures.Has(m.AzureMachinePool, m.Name(), scalesets.ServiceName) {
 		log.V(4).Info("exiting early due an in-progress long running operation on the ScaleSet")
 		// exit early to be less greedy about delete
 		return nil
@@ -309,8 +551,37 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 		return nil
 	}
 
+	machines := existingMachinesByProviderID
+	if m.azureManagesUpgrades() {
+		// Azure brings the instances up to date itself, they must not be replaced because they run an older model.
+		machines = make(map[string]infrav1exp.AzureMachinePoolMachine, len(existingMachinesByProviderID))
+		for key, machine := range existingMachinesByProviderID {
+			machine.Status.LatestModelApplied = true
+			machines[key] = machine
+		}
+	}
+
+	// the failed machines remediated in place by their own controller are left out of the deletion
+	for key, machine := range machines {
+		machine := machine
+		if machine.Status.ProvisioningState != nil && *machine.Status.ProvisioningState == infrav1.Failed && m.remediatesInPlace(&machine) {
+			delete(machines, key)
+		}
+	}
+
+	// the machines whose instance is protected, possibly through the annotation of their node, are never selected
+	for key, machine := range machines {
+		if _, ok := machine.Annotations[infrav1exp.InstanceProtectionAnnotation]; ok {
+			continue
+		}
+		if instance := azureMachinesByProviderID[key]; instance.ProtectFromScaleIn || instance.ProtectFromScaleSetActions {
+			machine.Annotations = protectionAnnotations(machine.Annotations, instance)
+			machines[key] = machine
+		}
+	}
+
 	// select machines to delete to lower the replica count
-	toDelete, err := deleteSelector.SelectMachinesToDelete(ctx, m.DesiredReplicas(), existingMachinesByProviderID)
+	toDelete, err := deleteSelector.SelectMachinesToDelete(ctx, m.DesiredReplicas(), machines)
 	if err != nil {
 		return errors.Wrap(err, "failed selecting AzureMachinePoolMachine(s) to delete")
 	}
@@ -327,6 +598,144 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 	return nil
 }
 
+// ScheduledEventsPollInterval returns how often the nodes of the pool are checked for scheduled events, or 0 if the pool
+// doesn't handle them.
+func (m *MachinePoolScope) ScheduledEventsPollInterval() time.Duration {
+	policy := m.AzureMachinePool.Spec.ScheduledEventsPolicy
+	if policy == nil || !policy.Enabled {
+		return 0
+	}
+	if policy.PollInterval == nil {
+		return infrav1exp.DefaultScheduledEventsPollInterval
+	}
+	return policy.PollInterval.Duration
+}
+
+// AnnotateScheduledEvents sets the ScheduledEventAnnotation of the AzureMachinePoolMachines whose node reports an event
+// Azure scheduled for its instance, so that they drain their node ahead of the event, and removes it once the event is
+// over. The events are read from a condition of the nodes, set by an agent polling the Scheduled Events of the Instance
+// Metadata Service, since Azure doesn't report them to the control plane before they start. The
+// ScheduledEventsReported condition warns when no node of the pool reports the condition, i.e. the agent isn't
+// deployed. It is a no-op unless the pool handles scheduled events.
+func (m *MachinePoolScope) AnnotateScheduledEvents(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.AnnotateScheduledEvents")
+	defer done()
+
+	if m.ScheduledEventsPollInterval() == 0 {
+		conditions.Delete(m.AzureMachinePool, infrav1.ScheduledEventsReportedCondition)
+		return nil
+	}
+
+	machines, err := m.getMachinePoolMachines(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get machine pool machines")
+	}
+	if len(machines) == 0 {
+		return nil
+	}
+
+	lister := m.workloadNodeLister
+	if lister == nil {
+		lister = newWorkloadClusterProxy(m.client, client.ObjectKey{Namespace: m.AzureMachinePool.Namespace, Name: m.ClusterName()})
+	}
+	nodes, err := lister.ListNodes(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the nodes of the workload cluster")
+	}
+
+	conditionType := m.AzureMachinePool.Spec.ScheduledEventsPolicy.NodeConditionType
+	if conditionType == "" {
+		conditionType = infrav1exp.DefaultScheduledEventsNodeConditionType
+	}
+	providerIDs := make(map[string]bool, len(machines))
+	for _, machine := range machines {
+		providerIDs[machine.Spec.ProviderID] = true
+	}
+	reported := false
+	eventsByProviderID := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		if event := scheduledEvent(node, corev1.NodeConditionType(conditionType)); event != "" {
+			eventsByProviderID[node.Spec.ProviderID] = event
+		}
+		if providerIDs[node.Spec.ProviderID] && hasNodeCondition(node, corev1.NodeConditionType(conditionType)) {
+			reported = true
+		}
+	}
+	if reported {
+		conditions.MarkTrue(m.AzureMachinePool, infrav1.ScheduledEventsReportedCondition)
+	} else {
+		conditions.MarkFalse(m.AzureMachinePool, infrav1.ScheduledEventsReportedCondition, infrav1.ScheduledEventsNodeConditionMissingReason, clusterv1.ConditionSeverityWarning,
+			"no node of the pool reports the %s condition, check that an agent reporting scheduled events runs on the nodes", conditionType)
+	}
+
+	for _, machine := range machines {
+		machine := machine
+		event := eventsByProviderID[machine.Spec.ProviderID]
+		if machine.Annotations[infrav1exp.ScheduledEventAnnotation] == event {
+			continue
+		}
+
+		patch := client.MergeFrom(machine.DeepCopy())
+		if event == "" {
+			delete(machine.Annotations, infrav1exp.ScheduledEventAnnotation)
+		} else {
+			if machine.Annotations == nil {
+				machine.Annotations = map[string]string{}
+			}
+			machine.Annotations[infrav1exp.ScheduledEventAnnotation] = event
+		}
+		log.Info("updating the scheduled event of AzureMachinePoolMachine", "name", machine.Name, "event", event)
+		if err := m.client.Patch(ctx, &machine, patch); err != nil {
+			return errors.Wrapf(err, "failed to update the scheduled event of AzureMachinePoolMachine %s", machine.Name)
+		}
+	}
+
+	return nil
+}
+
+// scheduledEvent returns the type of the event reported by the condition of the node, which is its reason, or an empty
+// string if no event requiring to drain the node is reported.
+func scheduledEvent(node corev1.Node, conditionType corev1.NodeConditionType) string {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type != conditionType || condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Reason {
+		case infrav1exp.ScheduledEventFreeze:
+			return ""
+		case "":
+			return "Unknown"
+		default:
+			return condition.Reason
+		}
+	}
+	return ""
+}
+
+// hasNodeCondition returns whether the node reports a condition of the given type, whatever its status.
+func hasNodeCondition(node corev1.Node, conditionType corev1.NodeConditionType) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == conditionType {
+			return true
+		}
+	}
+	return false
+}
+
+// protectionAnnotations returns a copy of the annotations of a machine with the InstanceProtectionAnnotation matching
+// the protection policy of its instance.
+func protectionAnnotations(annotations map[string]string, instance azure.VMSSVM) map[string]string {
+	protected := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		protected[k] = v
+	}
+	protected[infrav1exp.InstanceProtectionAnnotation] = infrav1exp.InstanceProtectionScaleIn
+	if instance.ProtectFromScaleSetActions {
+		protected[infrav1exp.InstanceProtectionAnnotation] = infrav1exp.InstanceProtectionScaleSetActions
+	}
+	return protected
+}
+
 func (m *MachinePoolScope) createMachine(ctx context.Context, machine azure.VMSSVM) error {
 	if machine.InstanceID == "" {
 		return errors.New("machine.InstanceID must not be empty")
@@ -336,9 +745,15 @@ func (m *MachinePoolScope) createMachine(ctx context.Context, machine azure.VMSS
 		return errors.New("machine.Name must not be empty")
 	}
 
+	name := strings.Join([]string{m.AzureMachinePool.Name, machine.InstanceID}, "-")
+	if m.OrchestrationMode() == infrav1exp.FlexibleOrchestrationMode {
+		// the instances of a Flexible scale set are VMs named after the scale set, e.g. "pool0_1a2b3c4d".
+		name = strings.ToLower(strings.ReplaceAll(machine.InstanceID, "_", "-"))
+	}
+
 	ampm := infrav1exp.AzureMachinePoolMachine{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      strings.Join([]string{m.AzureMachinePool.Name, machine.InstanceID}, "-"),
+			Name:      name,
 			Namespace: m.AzureMachinePool.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
 				{
@@ -494,15 +909,22 @@ func (m *MachinePoolScope) PatchObject(ctx context.Context) error {
 	return m.patchHelper.Patch(ctx, m.AzureMachinePool)
 }
 
-// Close the MachineScope by updating the machine spec, machine status.
+// Close the MachineScope by updating the machine spec, machine status. A transient error returned when the deletion of
+// AzureMachinePoolMachines must wait, e.g. for the BatchSettleTime of a rolling update, doesn't prevent the update and
+// is returned after it, so that the caller can requeue.
 func (m *MachinePoolScope) Close(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.Close")
 	defer done()
 
+	var transientErr error
 	if m.vmssState != nil {
 		if err := m.applyAzureMachinePoolMachines(ctx); err != nil {
-			log.Error(err, "failed to apply changes to the AzureMachinePoolMachines")
-			return errors.Wrap(err, "failed to apply changes to AzureMachinePoolMachines")
+			var reconcileError azure.ReconcileError
+			if !errors.As(err, &reconcileError) || !reconcileError.IsTransient() {
+				log.Error(err, "failed to apply changes to the AzureMachinePoolMachines")
+				return errors.Wrap(err, "failed to apply changes to AzureMachinePoolMachines")
+			}
+			transientErr = err
 		}
 
 		m.setProvisioningStateAndConditions(m.vmssState.State)
@@ -511,7 +933,10 @@ func (m *MachinePoolScope) Close(ctx context.Context) error {
 		}
 	}
 
-	return m.patchHelper.Patch(ctx, m.AzureMachinePool)
+	if err := m.patchHelper.Patch(ctx, m.AzureMachinePool); err != nil {
+		return err
+	}
+	return transientErr
 }
 
 // GetBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName.
@@ -533,9 +958,84 @@ func (m *MachinePoolScope) GetBootstrapData(ctx context.Context) (string, error)
 	if !ok {
 		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
+	if isIgnition(secret) {
+		// Ignition reads its config from the custom data of the VMSS instances, which is delivered as is.
+		if err := validateIgnitionBootstrap(m.AzureMachinePool.Spec.Template.OSDisk.OSType, m); err != nil {
+			return "", azure.WithTerminalError(errors.Wrapf(err, "invalid Ignition bootstrap data for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name()))
+		}
+		return base64.StdEncoding.EncodeToString(value), nil
+	}
+	if m.AzureMachinePool.Spec.Template.OSDisk.OSType != azure.WindowsOS {
+		injected, err := cloudinit.InjectProxyConfig(value, m.ProxyConfig())
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to inject proxy configuration into bootstrap data for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
+		}
+		value = injected
+
+		if trustedCAs := m.TrustedCAs(); trustedCAs != nil {
+			caCerts, err := getTrustedCAs(ctx, m.client, m.AzureMachinePool.Namespace, trustedCAs)
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to retrieve trusted CA certificates for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
+			}
+			injected, err = cloudinit.InjectTrustedCAs(value, caCerts, withoutMirroredRegistries(trustedCAs.Registries, m.RegistryMirrors()))
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to inject trusted CA certificates into bootstrap data for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
+			}
+			value = injected
+		}
+
+		if mirrors := m.RegistryMirrors(); len(mirrors) > 0 {
+			credentials, err := getRegistryCredentials(ctx, m.client, m.AzureMachinePool.Namespace, mirrors)
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to retrieve registry mirror credentials for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
+			}
+			injected, err = cloudinit.InjectRegistryMirrors(value, mirrors, credentials)
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to inject registry mirrors into bootstrap data for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
+			}
+			value = injected
+		}
+	}
 	return base64.StdEncoding.EncodeToString(value), nil
 }
 
+// GetUserData returns the user data from the secret in the AzureMachinePool's template.userData, or an empty string if
+// it has no user data.
+func (m *MachinePoolScope) GetUserData(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.GetUserData")
+	defer done()
+
+	userData := m.AzureMachinePool.Spec.Template.UserData
+	if userData == nil {
+		return "", nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: m.AzureMachinePool.Namespace, Name: userData.SecretName}
+	if err := m.client.Get(ctx, key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve user data secret for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
+	}
+
+	dataKey := userData.Key
+	if dataKey == "" {
+		dataKey = "value"
+	}
+	value, ok := secret.Data[dataKey]
+	if !ok {
+		return "", errors.Errorf("error retrieving user data: secret %s key is missing", dataKey)
+	}
+	return base64.StdEncoding.EncodeToString(value), nil
+}
+
+// MarketplaceTermsSpec returns the spec of the marketplace terms of the purchase plan of the image of the machine pool.
+// It returns nil if the controller doesn't accept marketplace terms or if the image has no purchase plan. Unlike for
+// machines, the terms are checked on every reconciliation as the image of the scale set can be updated.
+func (m *MachinePoolScope) MarketplaceTermsSpec() *azure.MarketplaceTermsSpec {
+	if !m.acceptTerms || m.AzureMachinePool.Spec.Template.Image == nil {
+		return nil
+	}
+	return marketplaceTermsSpec(m.AzureMachinePool.Spec.Template.Image)
+}
+
 // GetVMImage picks an image from the machine configuration, or uses a default one.
 func (m *MachinePoolScope) GetVMImage(ctx context.Context) (*infrav1.Image, error) {
 	_, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.GetVMImage")
@@ -578,12 +1078,22 @@ func (m *MachinePoolScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
 				MachineName:  m.Name(),
 				Name:         m.AzureMachinePool.Spec.RoleAssignmentName,
 				ResourceType: azure.VirtualMachineScaleSet,
+				Scope:        m.roleAssignmentScope(),
 			},
 		}
 	}
 	return []azure.RoleAssignmentSpec{}
 }
 
+// roleAssignmentScope returns the scope of the role assignment of the system-assigned identity: the resource group of
+// the cluster when capz is only allowed to manage its resource groups, otherwise the subscription.
+func (m *MachinePoolScope) roleAssignmentScope() string {
+	if feature.Gates.Enabled(feature.ResourceGroupScoped) {
+		return azure.ResourceGroupID(m.SubscriptionID(), m.ResourceGroup())
+	}
+	return ""
+}
+
 // VMSSExtensionSpecs returns the vmss extension specs.
 func (m *MachinePoolScope) VMSSExtensionSpecs() []azure.ExtensionSpec {
 	var extensionSpecs = []azure.ExtensionSpec{}
@@ -21,22 +21,27 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	autorestazure "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	mock_scope "sigs.k8s.io/cluster-api-provider-azure/azure/scope/mocks"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	gomock2 "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -217,6 +222,19 @@ func TestMachinePoolScope_MaxSurge(t *testing.T) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
 		},
+		{
+			Name: "surge should be 0 when Azure upgrades the instances",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
+				mp.Spec.Replicas = to.Int32Ptr(3)
+				amp.Spec.UpgradePolicy = &infrav1exp.UpgradePolicy{
+					Mode: infrav1exp.UpgradeModeRolling,
+				}
+			},
+			Verify: func(g *WithT, surge int, err error) {
+				g.Expect(surge).To(Equal(0))
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -620,6 +638,173 @@ func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 	}
 }
 
+func TestMachinePoolScope_AnnotateScheduledEvents(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+
+	nodeWithCondition := func(providerID string, status corev1.ConditionStatus, reason string) corev1.Node {
+		return corev1.Node{
+			Spec: corev1.NodeSpec{
+				ProviderID: providerID,
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:   infrav1exp.DefaultScheduledEventsNodeConditionType,
+						Status: status,
+						Reason: reason,
+					},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		Name   string
+		Policy *infrav1exp.ScheduledEventsPolicy
+		Setup  func(mockNodeLister *mock_scope.MocknodeLister, machines []infrav1exp.AzureMachinePoolMachine)
+		Err    string
+		Verify func(g *WithT, amp *infrav1exp.AzureMachinePool, machines map[string]infrav1exp.AzureMachinePoolMachine)
+	}{
+		{
+			Name:   "should not list the nodes if the pool doesn't handle scheduled events",
+			Policy: nil,
+			Setup:  func(mockNodeLister *mock_scope.MocknodeLister, machines []infrav1exp.AzureMachinePoolMachine) {},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, machines map[string]infrav1exp.AzureMachinePoolMachine) {
+				g.Expect(machines["ampm0"].Annotations).NotTo(HaveKey(infrav1exp.ScheduledEventAnnotation))
+				g.Expect(conditions.Has(amp, infrav1.ScheduledEventsReportedCondition)).To(BeFalse())
+			},
+		},
+		{
+			Name:   "should annotate the machines whose node reports a scheduled event",
+			Policy: &infrav1exp.ScheduledEventsPolicy{Enabled: true},
+			Setup: func(mockNodeLister *mock_scope.MocknodeLister, machines []infrav1exp.AzureMachinePoolMachine) {
+				mockNodeLister.EXPECT().ListNodes(gomock2.AContext()).Return([]corev1.Node{
+					nodeWithCondition("/foo/ampm0", corev1.ConditionTrue, infrav1exp.ScheduledEventPreempt),
+					nodeWithCondition("/foo/ampm1", corev1.ConditionFalse, ""),
+					nodeWithCondition("/foo/ampm2", corev1.ConditionTrue, infrav1exp.ScheduledEventFreeze),
+				}, nil)
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, machines map[string]infrav1exp.AzureMachinePoolMachine) {
+				g.Expect(machines["ampm0"].Annotations).To(HaveKeyWithValue(infrav1exp.ScheduledEventAnnotation, infrav1exp.ScheduledEventPreempt))
+				g.Expect(machines["ampm1"].Annotations).NotTo(HaveKey(infrav1exp.ScheduledEventAnnotation))
+				g.Expect(machines["ampm2"].Annotations).NotTo(HaveKey(infrav1exp.ScheduledEventAnnotation))
+				g.Expect(conditions.IsTrue(amp, infrav1.ScheduledEventsReportedCondition)).To(BeTrue())
+			},
+		},
+		{
+			Name:   "should warn if no node of the pool reports the node condition",
+			Policy: &infrav1exp.ScheduledEventsPolicy{Enabled: true},
+			Setup: func(mockNodeLister *mock_scope.MocknodeLister, machines []infrav1exp.AzureMachinePoolMachine) {
+				mockNodeLister.EXPECT().ListNodes(gomock2.AContext()).Return([]corev1.Node{
+					{Spec: corev1.NodeSpec{ProviderID: "/foo/ampm0"}},
+					nodeWithCondition("/foo/other-pool", corev1.ConditionFalse, ""),
+				}, nil)
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, machines map[string]infrav1exp.AzureMachinePoolMachine) {
+				g.Expect(conditions.IsFalse(amp, infrav1.ScheduledEventsReportedCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(amp, infrav1.ScheduledEventsReportedCondition)).To(Equal(infrav1.ScheduledEventsNodeConditionMissingReason))
+				g.Expect(*conditions.GetSeverity(amp, infrav1.ScheduledEventsReportedCondition)).To(Equal(clusterv1.ConditionSeverityWarning))
+			},
+		},
+		{
+			Name:   "should remove the annotation once the event is over",
+			Policy: &infrav1exp.ScheduledEventsPolicy{Enabled: true},
+			Setup: func(mockNodeLister *mock_scope.MocknodeLister, machines []infrav1exp.AzureMachinePoolMachine) {
+				machines[0].Annotations = map[string]string{infrav1exp.ScheduledEventAnnotation: "Redeploy"}
+				mockNodeLister.EXPECT().ListNodes(gomock2.AContext()).Return([]corev1.Node{
+					nodeWithCondition("/foo/ampm0", corev1.ConditionFalse, ""),
+				}, nil)
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, machines map[string]infrav1exp.AzureMachinePoolMachine) {
+				g.Expect(machines["ampm0"].Annotations).NotTo(HaveKey(infrav1exp.ScheduledEventAnnotation))
+			},
+		},
+		{
+			Name:   "should read the events from the node condition of the policy",
+			Policy: &infrav1exp.ScheduledEventsPolicy{Enabled: true, NodeConditionType: "MaintenanceScheduled"},
+			Setup: func(mockNodeLister *mock_scope.MocknodeLister, machines []infrav1exp.AzureMachinePoolMachine) {
+				node := nodeWithCondition("/foo/ampm1", corev1.ConditionTrue, "Reboot")
+				node.Status.Conditions[0].Type = "MaintenanceScheduled"
+				mockNodeLister.EXPECT().ListNodes(gomock2.AContext()).Return([]corev1.Node{
+					nodeWithCondition("/foo/ampm0", corev1.ConditionTrue, "Reboot"),
+					node,
+				}, nil)
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, machines map[string]infrav1exp.AzureMachinePoolMachine) {
+				g.Expect(machines["ampm0"].Annotations).NotTo(HaveKey(infrav1exp.ScheduledEventAnnotation))
+				g.Expect(machines["ampm1"].Annotations).To(HaveKeyWithValue(infrav1exp.ScheduledEventAnnotation, "Reboot"))
+			},
+		},
+		{
+			Name:   "if ListNodes fails with an error, an error will be returned",
+			Policy: &infrav1exp.ScheduledEventsPolicy{Enabled: true},
+			Setup: func(mockNodeLister *mock_scope.MocknodeLister, machines []infrav1exp.AzureMachinePoolMachine) {
+				mockNodeLister.EXPECT().ListNodes(gomock2.AContext()).Return(nil, errors.New("boom"))
+			},
+			Err: "failed to list the nodes of the workload cluster: boom",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var (
+				g              = NewWithT(t)
+				mockCtrl       = gomock.NewController(t)
+				mockNodeLister = mock_scope.NewMocknodeLister(mockCtrl)
+				cb             = fake.NewClientBuilder().WithScheme(scheme)
+				cluster        = &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				}
+				amp = &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "amp1",
+						Namespace: "default",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						ScheduledEventsPolicy: c.Policy,
+					},
+				}
+			)
+			defer mockCtrl.Finish()
+
+			machines := getReadyAzureMachinePoolMachines(3)
+			c.Setup(mockNodeLister, machines)
+			for _, machine := range machines {
+				obj := machine
+				cb.WithObjects(&obj)
+			}
+			s := &MachinePoolScope{
+				client: cb.Build(),
+				ClusterScoper: &ClusterScope{
+					Cluster: cluster,
+				},
+				AzureMachinePool:   amp,
+				workloadNodeLister: mockNodeLister,
+			}
+
+			err := s.AnnotateScheduledEvents(context.TODO())
+			if c.Err != "" {
+				g.Expect(err).To(MatchError(c.Err))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			ampml := &infrav1exp.AzureMachinePoolMachineList{}
+			g.Expect(s.client.List(context.TODO(), ampml)).To(Succeed())
+			byName := make(map[string]infrav1exp.AzureMachinePoolMachine, len(ampml.Items))
+			for _, machine := range ampml.Items {
+				byName[machine.Name] = machine
+			}
+			c.Verify(g, s.AzureMachinePool, byName)
+		})
+	}
+}
+
 func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -856,3 +1041,541 @@ func getReadyAzureMachinePoolMachines(count int32) []infrav1exp.AzureMachinePool
 
 	return machines
 }
+
+func TestMachinePoolScope_SpotPlacementScoreSpec(t *testing.T) {
+	threshold := infrav1exp.SpotPlacementScoreMedium
+	clusterScope := &ClusterScope{
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				Location: "westus2",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		machinePoolScope MachinePoolScope
+		want             *azure.SpotPlacementScoreSpec
+	}{
+		{
+			name: "returns nil without spot VM options",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: &clusterv1exp.MachinePool{},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machinepool-name",
+					},
+				},
+				ClusterScoper: clusterScope,
+			},
+			want: nil,
+		},
+		{
+			name: "returns nil once the scale set exists",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: &clusterv1exp.MachinePool{},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machinepool-name",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						ProviderID: "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/machinepool-name",
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							SpotVMOptions: &infrav1.SpotVMOptions{},
+						},
+					},
+				},
+				ClusterScoper: clusterScope,
+			},
+			want: nil,
+		},
+		{
+			name: "returns spec with zones and threshold",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: &clusterv1exp.MachinePool{
+					Spec: clusterv1exp.MachinePoolSpec{
+						Replicas:       to.Int32Ptr(3),
+						FailureDomains: []string{"1", "2"},
+					},
+				},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machinepool-name",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						SpotPlacementScoreThreshold: &threshold,
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							VMSize:        "Standard_D2s_v3",
+							SpotVMOptions: &infrav1.SpotVMOptions{},
+						},
+					},
+				},
+				ClusterScoper: clusterScope,
+			},
+			want: &azure.SpotPlacementScoreSpec{
+				Location:  "westus2",
+				Size:      "Standard_D2s_v3",
+				Zones:     []string{"1", "2"},
+				Count:     3,
+				Threshold: "Medium",
+			},
+		},
+		{
+			name: "requests at least one instance",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: &clusterv1exp.MachinePool{
+					Spec: clusterv1exp.MachinePoolSpec{
+						Replicas: to.Int32Ptr(0),
+					},
+				},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machinepool-name",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							VMSize:        "Standard_D2s_v3",
+							SpotVMOptions: &infrav1.SpotVMOptions{},
+						},
+					},
+				},
+				ClusterScoper: clusterScope,
+			},
+			want: &azure.SpotPlacementScoreSpec{
+				Location: "westus2",
+				Size:     "Standard_D2s_v3",
+				Count:    1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.machinePoolScope.SpotPlacementScoreSpec(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SpotPlacementScoreSpec() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMachinePoolScope_ScaleSetUpgradePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *infrav1exp.UpgradePolicy
+		want   *azure.ScaleSetUpgradePolicy
+	}{
+		{
+			name:   "no upgrade policy",
+			policy: nil,
+			want:   nil,
+		},
+		{
+			name: "rolling upgrade policy with a health probe",
+			policy: &infrav1exp.UpgradePolicy{
+				Mode: infrav1exp.UpgradeModeRolling,
+				RollingUpgrade: &infrav1exp.RollingUpgradePolicy{
+					MaxBatchInstancePercent: to.Int32Ptr(50),
+					PauseTimeBetweenBatches: &metav1.Duration{Duration: 90 * time.Second},
+				},
+				AutomaticOSUpgrade: true,
+				HealthProbe: &infrav1exp.HealthProbe{
+					Protocol:    infrav1exp.HealthProbeProtocolHTTP,
+					Port:        10248,
+					RequestPath: "/healthz",
+				},
+			},
+			want: &azure.ScaleSetUpgradePolicy{
+				Mode:                    "Rolling",
+				AutomaticOSUpgrade:      true,
+				MaxBatchInstancePercent: to.Int32Ptr(50),
+				PauseTimeBetweenBatches: to.StringPtr("PT90S"),
+				HealthProbe: &azure.ScaleSetHealthProbe{
+					Protocol:    "http",
+					Port:        10248,
+					RequestPath: "/healthz",
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{UpgradePolicy: tc.policy},
+				},
+			}
+			g.Expect(s.scaleSetUpgradePolicy()).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestMachinePoolScope_ScaleUpBatchSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchSize *int32
+		want      int64
+	}{
+		{
+			name:      "defaults the batch size",
+			batchSize: nil,
+			want:      infrav1exp.DefaultScaleUpBatchSize,
+		},
+		{
+			name:      "batch size of the machine pool",
+			batchSize: to.Int32Ptr(20),
+			want:      20,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{ScaleUpBatchSize: tc.batchSize},
+				},
+			}
+			g.Expect(s.scaleUpBatchSize()).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestMachinePoolScope_OrchestrationMode(t *testing.T) {
+	tests := []struct {
+		name string
+		mode infrav1exp.OrchestrationModeType
+		want infrav1exp.OrchestrationModeType
+	}{
+		{
+			name: "defaults to the Uniform mode",
+			mode: "",
+			want: infrav1exp.UniformOrchestrationMode,
+		},
+		{
+			name: "orchestration mode of the machine pool",
+			mode: infrav1exp.FlexibleOrchestrationMode,
+			want: infrav1exp.FlexibleOrchestrationMode,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{OrchestrationMode: tc.mode},
+				},
+			}
+			g.Expect(s.OrchestrationMode()).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestMachinePoolScope_createMachine(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+
+	tests := []struct {
+		name     string
+		mode     infrav1exp.OrchestrationModeType
+		instance azure.VMSSVM
+		want     string
+	}{
+		{
+			name: "machine of a Uniform scale set is named after its instance ID",
+			mode: infrav1exp.UniformOrchestrationMode,
+			instance: azure.VMSSVM{
+				ID:         "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/amp1/virtualMachines/3",
+				InstanceID: "3",
+				Name:       "amp1000003",
+			},
+			want: "amp1-3",
+		},
+		{
+			name: "machine of a Flexible scale set is named after its VM",
+			mode: infrav1exp.FlexibleOrchestrationMode,
+			instance: azure.VMSSVM{
+				ID:         "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/amp1_1A2B3C4D",
+				InstanceID: "amp1_1A2B3C4D",
+				Name:       "amp1000003",
+			},
+			want: "amp1-1a2b3c4d",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := fake.NewClientBuilder().WithScheme(scheme).Build()
+			s := &MachinePoolScope{
+				client: c,
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+				},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "amp1", Namespace: "default"},
+					Spec:       infrav1exp.AzureMachinePoolSpec{OrchestrationMode: tc.mode},
+				},
+			}
+			g.Expect(s.createMachine(context.TODO(), tc.instance)).To(Succeed())
+
+			ampm := &infrav1exp.AzureMachinePoolMachine{}
+			g.Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: tc.want}, ampm)).To(Succeed())
+			g.Expect(ampm.Spec.InstanceID).To(Equal(tc.instance.InstanceID))
+			g.Expect(ampm.Spec.ProviderID).To(Equal(tc.instance.ProviderID()))
+		})
+	}
+}
+
+func TestMachinePoolScope_ScaleSetAutomaticRepairsPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *infrav1exp.AutomaticRepairsPolicy
+		want   *azure.ScaleSetAutomaticRepairsPolicy
+	}{
+		{
+			name:   "no automatic repairs policy",
+			policy: nil,
+			want:   nil,
+		},
+		{
+			name:   "automatic repairs with the default grace period",
+			policy: &infrav1exp.AutomaticRepairsPolicy{Enabled: true},
+			want:   &azure.ScaleSetAutomaticRepairsPolicy{Enabled: true},
+		},
+		{
+			name: "automatic repairs with a grace period",
+			policy: &infrav1exp.AutomaticRepairsPolicy{
+				Enabled:     true,
+				GracePeriod: &metav1.Duration{Duration: 45*time.Minute + 30*time.Second},
+			},
+			want: &azure.ScaleSetAutomaticRepairsPolicy{
+				Enabled:     true,
+				GracePeriod: to.StringPtr("PT45M"),
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{AutomaticRepairsPolicy: tc.policy},
+				},
+			}
+			g.Expect(s.scaleSetAutomaticRepairsPolicy()).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestMachinePoolScope_ScaleSetPriorityMixPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *infrav1exp.PriorityMixPolicy
+		want   *azure.ScaleSetPriorityMixPolicy
+	}{
+		{
+			name:   "no priority mix policy",
+			policy: nil,
+			want:   nil,
+		},
+		{
+			name: "priority mix policy of the machine pool",
+			policy: &infrav1exp.PriorityMixPolicy{
+				BaseRegularPriorityCount:           to.Int32Ptr(2),
+				RegularPriorityPercentageAboveBase: to.Int32Ptr(25),
+			},
+			want: &azure.ScaleSetPriorityMixPolicy{
+				BaseRegularPriorityCount:           to.Int32Ptr(2),
+				RegularPriorityPercentageAboveBase: to.Int32Ptr(25),
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{PriorityMixPolicy: tc.policy},
+				},
+			}
+			g.Expect(s.scaleSetPriorityMixPolicy()).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestMachinePoolScope_ScaleInPolicy(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          *infrav1exp.ScaleInPolicy
+		wantRule        string
+		wantForceDelete bool
+	}{
+		{
+			name:            "no scale-in policy",
+			policy:          nil,
+			wantRule:        "",
+			wantForceDelete: false,
+		},
+		{
+			name: "scale-in policy of the machine pool",
+			policy: &infrav1exp.ScaleInPolicy{
+				Rule:          infrav1exp.ScaleInRuleOldestVM,
+				ForceDeletion: true,
+			},
+			wantRule:        "OldestVM",
+			wantForceDelete: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{ScaleInPolicy: tc.policy},
+				},
+			}
+			g.Expect(s.scaleInRule()).To(Equal(tc.wantRule))
+			g.Expect(s.ForceDeleteInstances()).To(Equal(tc.wantForceDelete))
+		})
+	}
+}
+
+func TestMachinePoolScope_ZonePolicy(t *testing.T) {
+	tests := []struct {
+		name                         string
+		policy                       *infrav1exp.ZonePolicy
+		failureDomains               []string
+		wantFailureDomains           []string
+		wantZoneBalance              *bool
+		wantPlatformFaultDomainCount *int32
+	}{
+		{
+			name:               "failure domains of the machine pool without zone policy",
+			policy:             nil,
+			failureDomains:     []string{"1", "2"},
+			wantFailureDomains: []string{"1", "2"},
+		},
+		{
+			name:                         "zones of the zone policy take precedence",
+			policy:                       &infrav1exp.ZonePolicy{Zones: []string{"3"}, PlatformFaultDomainCount: to.Int32Ptr(2)},
+			failureDomains:               []string{"1", "2"},
+			wantFailureDomains:           []string{"3"},
+			wantPlatformFaultDomainCount: to.Int32Ptr(2),
+		},
+		{
+			name:            "scale set spread across all zones is balanced",
+			policy:          &infrav1exp.ZonePolicy{AllZones: true},
+			wantZoneBalance: to.BoolPtr(true),
+		},
+		{
+			name:            "scale set spread across all zones without zone balance",
+			policy:          &infrav1exp.ZonePolicy{AllZones: true, ZoneBalance: to.BoolPtr(false)},
+			wantZoneBalance: to.BoolPtr(false),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolScope{
+				MachinePool: &clusterv1exp.MachinePool{
+					Spec: clusterv1exp.MachinePoolSpec{FailureDomains: tc.failureDomains},
+				},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{ZonePolicy: tc.policy},
+				},
+			}
+			g.Expect(s.FailureDomains()).To(Equal(tc.wantFailureDomains))
+			g.Expect(s.zoneBalance()).To(Equal(tc.wantZoneBalance))
+			g.Expect(s.platformFaultDomainCount()).To(Equal(tc.wantPlatformFaultDomainCount))
+		})
+	}
+}
+
+func TestMachinePoolScope_remediatesInPlace(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy *infrav1exp.RemediationStrategy
+		machine  infrav1exp.AzureMachinePoolMachine
+		want     bool
+	}{
+		{
+			name:     "failed machines are deleted by default",
+			strategy: nil,
+			want:     false,
+		},
+		{
+			name:     "failed machines are reimaged",
+			strategy: &infrav1exp.RemediationStrategy{Type: infrav1exp.ReimageRemediationStrategyType},
+			want:     true,
+		},
+		{
+			name:     "machines being remediated are not deleted",
+			strategy: &infrav1exp.RemediationStrategy{Type: infrav1exp.RedeployRemediationStrategyType},
+			machine: infrav1exp.AzureMachinePoolMachine{
+				Status: infrav1exp.AzureMachinePoolMachineStatus{
+					Conditions:                 clusterv1.Conditions{{Type: infrav1.InstanceRemediatedCondition, Status: corev1.ConditionFalse}},
+					LongRunningOperationStates: infrav1.Futures{{Type: infrav1.PostFuture, ServiceName: "scalesetvms"}},
+				},
+			},
+			want: true,
+		},
+		{
+			name:     "machines which failed again after being remediated are deleted",
+			strategy: &infrav1exp.RemediationStrategy{Type: infrav1exp.RedeployRemediationStrategyType},
+			machine: infrav1exp.AzureMachinePoolMachine{
+				Status: infrav1exp.AzureMachinePoolMachineStatus{
+					Conditions: clusterv1.Conditions{{Type: infrav1.InstanceRemediatedCondition, Status: corev1.ConditionFalse}},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{RemediationStrategy: tc.strategy},
+				},
+			}
+			g.Expect(s.remediatesInPlace(&tc.machine)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestMachinePoolScope_protectionAnnotations(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance azure.VMSSVM
+		want     string
+	}{
+		{
+			name:     "instance protected from scale in",
+			instance: azure.VMSSVM{ProtectFromScaleIn: true},
+			want:     infrav1exp.InstanceProtectionScaleIn,
+		},
+		{
+			name:     "instance protected from scale set actions",
+			instance: azure.VMSSVM{ProtectFromScaleIn: true, ProtectFromScaleSetActions: true},
+			want:     infrav1exp.InstanceProtectionScaleSetActions,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			annotations := map[string]string{"foo": "bar"}
+			got := protectionAnnotations(annotations, tc.instance)
+			g.Expect(got).To(Equal(map[string]string{"foo": "bar", infrav1exp.InstanceProtectionAnnotation: tc.want}))
+			g.Expect(annotations).To(HaveLen(1))
+		})
+	}
+}
@@ -56,6 +56,10 @@ type (
 		GetNodeByObjectReference(ctx context.Context, nodeRef corev1.ObjectReference) (*corev1.Node, error)
 	}
 
+	nodeLister interface {
+		ListNodes(ctx context.Context) ([]corev1.Node, error)
+	}
+
 	workloadClusterProxy struct {
 		Client  client.Client
 		Cluster client.ObjectKey
@@ -164,6 +168,16 @@ func (s *MachinePoolMachineScope) ScaleSetName() string {
 	return s.MachinePoolScope.Name()
 }
 
+// OrchestrationMode is the orchestration mode of the VMSS.
+func (s *MachinePoolMachineScope) OrchestrationMode() infrav1exp.OrchestrationModeType {
+	return s.MachinePoolScope.OrchestrationMode()
+}
+
+// ForceDelete returns whether the instance is force deleted when it is removed from the VMSS.
+func (s *MachinePoolMachineScope) ForceDelete() bool {
+	return s.MachinePoolScope.ForceDeleteInstances()
+}
+
 // SetLongRunningOperationState will set the future on the AzureMachinePoolMachine status to allow the resource to continue
 // in the next reconciliation.
 func (s *MachinePoolMachineScope) SetLongRunningOperationState(future *infrav1.Future) {
@@ -227,6 +241,50 @@ func (s *MachinePoolMachineScope) SetVMSSVM(instance *azure.VMSSVM) {
 	s.instance = instance
 }
 
+// InPlaceRemediation returns how the failed instance is reimaged or redeployed in place, or "" if it isn't failed or is
+// deleted instead, following the remediation strategy of the pool or because it was already remediated in place since it
+// was last ready.
+func (s *MachinePoolMachineScope) InPlaceRemediation() infrav1exp.RemediationStrategyType {
+	if s.instance == nil || s.instance.State != infrav1.Failed || !s.MachinePoolScope.remediatesInPlace(s.AzureMachinePoolMachine) {
+		return ""
+	}
+	return s.MachinePoolScope.RemediationStrategy()
+}
+
+// SetInPlaceRemediationStarted records the start of the in place remediation of the failed instance.
+func (s *MachinePoolMachineScope) SetInPlaceRemediationStarted(strategy infrav1exp.RemediationStrategyType) {
+	reason := infrav1.InstanceRedeployingReason
+	if strategy == infrav1exp.ReimageRemediationStrategyType {
+		reason = infrav1.InstanceReimagingReason
+	}
+	conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.InstanceRemediatedCondition, reason, clusterv1.ConditionSeverityWarning, "Remediating the failed instance with %s", strategy)
+}
+
+// InstanceProtection returns the protection of the instance requested by the InstanceProtectionAnnotation of the
+// AzureMachinePoolMachine or, if it doesn't have it, of its node, or "" if the instance isn't protected.
+func (s *MachinePoolMachineScope) InstanceProtection(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolMachineScope.InstanceProtection")
+	defer done()
+
+	protection, ok := s.AzureMachinePoolMachine.Annotations[infrav1exp.InstanceProtectionAnnotation]
+	if !ok && s.AzureMachinePoolMachine.Status.NodeRef != nil {
+		node, err := s.getNode(ctx)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get the node of the instance")
+		}
+		if node != nil {
+			protection = node.Annotations[infrav1exp.InstanceProtectionAnnotation]
+		}
+	}
+
+	switch protection {
+	case "", infrav1exp.InstanceProtectionScaleIn, infrav1exp.InstanceProtectionScaleSetActions:
+		return protection, nil
+	default:
+		return "", errors.Errorf("annotation %s must be %s or %s, got %q", infrav1exp.InstanceProtectionAnnotation, infrav1exp.InstanceProtectionScaleIn, infrav1exp.InstanceProtectionScaleSetActions, protection)
+	}
+}
+
 // ProvisioningState returns the AzureMachinePoolMachine provisioning state.
 func (s *MachinePoolMachineScope) ProvisioningState() infrav1.ProvisioningState {
 	if s.AzureMachinePoolMachine.Status.ProvisioningState != nil {
@@ -314,6 +372,11 @@ func (s *MachinePoolMachineScope) UpdateStatus(ctx context.Context) error {
 		s.AzureMachinePoolMachine.Status.ProvisioningState = &s.instance.State
 	}
 
+	// a machine remediated in place can be remediated in place again once it is ready
+	if s.IsReady() && conditions.IsFalse(s.AzureMachinePoolMachine, infrav1.InstanceRemediatedCondition) {
+		conditions.MarkTrue(s.AzureMachinePoolMachine, infrav1.InstanceRemediatedCondition)
+	}
+
 	return nil
 }
 
@@ -384,6 +447,40 @@ func (s *MachinePoolMachineScope) CordonAndDrain(ctx context.Context) error {
 	return nil
 }
 
+// WaitForVolumeDetach waits for the volumes of the drained Kubernetes node associated with this
+// AzureMachinePoolMachine to be detached before its instance is deleted, unless the NodeVolumeDetachTimeout of the
+// AzureMachinePool has expired.
+func (s *MachinePoolMachineScope) WaitForVolumeDetach(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.MachinePoolMachineScope.WaitForVolumeDetach",
+	)
+	defer done()
+
+	// only wait for the volumes of the nodes which were drained, as the pods of the others may still use them.
+	if !conditions.IsTrue(s.AzureMachinePoolMachine, clusterv1.DrainingSucceededCondition) || s.nodeVolumeDetachTimeoutExceeded() {
+		return nil
+	}
+
+	node, err := s.getNode(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to find node")
+	}
+	if node == nil || len(node.Status.VolumesAttached) == 0 {
+		conditions.MarkTrue(s.AzureMachinePoolMachine, clusterv1.VolumeDetachSucceededCondition)
+		return nil
+	}
+
+	// The VolumeDetachSucceededCondition never exists before waiting for the volumes for the first time, so its
+	// transition time can be used to record when waiting started.
+	if conditions.Get(s.AzureMachinePoolMachine, clusterv1.VolumeDetachSucceededCondition) == nil {
+		conditions.MarkFalse(s.AzureMachinePoolMachine, clusterv1.VolumeDetachSucceededCondition, clusterv1.WaitingForVolumeDetachReason, clusterv1.ConditionSeverityInfo, "Waiting for node volumes to be detached")
+	}
+
+	log.V(4).Info("Waiting for node volumes to be detached", "node", node.Name, "volumes", len(node.Status.VolumesAttached))
+	return azure.WithTransientError(errors.Errorf("waiting for %d volumes to be detached from node %s", len(node.Status.VolumesAttached), node.Name), 20*time.Second)
+}
+
 func (s *MachinePoolMachineScope) drainNode(ctx context.Context, node *corev1.Node) error {
 	ctx, log, done := tele.StartSpanWithLogger(
 		ctx,
@@ -448,6 +545,114 @@ func (s *MachinePoolMachineScope) drainNode(ctx context.Context, node *corev1.No
 	return nil
 }
 
+// HandleScheduledEvents cordons and drains the node of the instance while the AzureMachinePool controller reports an
+// event Azure scheduled for it, such as a Spot eviction or a planned maintenance, with the ScheduledEventAnnotation, and
+// uncordons the node once the event is over. It is a no-op unless the pool handles scheduled events.
+func (s *MachinePoolMachineScope) HandleScheduledEvents(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.MachinePoolMachineScope.HandleScheduledEvents",
+	)
+	defer done()
+
+	if s.MachinePoolScope.ScheduledEventsPollInterval() == 0 {
+		return nil
+	}
+
+	event := s.AzureMachinePoolMachine.Annotations[infrav1exp.ScheduledEventAnnotation]
+	if event == "" && !conditions.Has(s.AzureMachinePoolMachine, infrav1.ScheduledEventDrainedCondition) {
+		return nil
+	}
+	if _, exists := s.AzureMachinePoolMachine.ObjectMeta.Annotations[clusterv1.ExcludeNodeDrainingAnnotation]; exists {
+		return nil
+	}
+
+	node, err := s.getNode(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to find node")
+	}
+
+	if event == "" {
+		// the event is over, the node can run workloads again
+		if node != nil {
+			log.Info("Uncordoning node after scheduled event", "node", node.Name)
+			if err := s.uncordonNode(ctx, node); err != nil {
+				return err
+			}
+		}
+		conditions.Delete(s.AzureMachinePoolMachine, infrav1.ScheduledEventDrainedCondition)
+		return nil
+	}
+
+	if conditions.IsTrue(s.AzureMachinePoolMachine, infrav1.ScheduledEventDrainedCondition) {
+		return nil
+	}
+
+	reason := infrav1.PlannedMaintenanceReason
+	if event == infrav1exp.ScheduledEventPreempt {
+		reason = infrav1.SpotEvictionReason
+	}
+	conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.ScheduledEventDrainedCondition, reason, clusterv1.ConditionSeverityInfo, "Draining the node before the %s event", event)
+	if node == nil {
+		return nil
+	}
+
+	log.Info("Draining node for scheduled event", "node", node.Name, "event", event)
+	if err := s.drainNode(ctx, node); err != nil {
+		conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.ScheduledEventDrainedCondition, infrav1.ScheduledEventDrainFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+
+	conditions.MarkTrue(s.AzureMachinePoolMachine, infrav1.ScheduledEventDrainedCondition)
+	return nil
+}
+
+// getNode returns the node of the instance, or nil if it doesn't exist.
+func (s *MachinePoolMachineScope) getNode(ctx context.Context) (*corev1.Node, error) {
+	var (
+		nodeRef = s.AzureMachinePoolMachine.Status.NodeRef
+		node    *corev1.Node
+		err     error
+	)
+	if nodeRef == nil || nodeRef.Name == "" {
+		node, err = s.workloadNodeGetter.GetNodeByProviderID(ctx, s.ProviderID())
+	} else {
+		node, err = s.workloadNodeGetter.GetNodeByObjectReference(ctx, *nodeRef)
+	}
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return node, err
+}
+
+func (s *MachinePoolMachineScope) uncordonNode(ctx context.Context, node *corev1.Node) error {
+	restConfig, err := remote.RESTConfig(ctx, MachinePoolMachineScopeName, s.client, client.ObjectKey{
+		Name:      s.ClusterName(),
+		Namespace: s.AzureMachinePoolMachine.Namespace,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create a remote client")
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a remote client")
+	}
+
+	drainer := &kubedrain.Helper{
+		Client: kubeClient,
+		Ctx:    ctx,
+		Out:    writer{klog.Info},
+		ErrOut: writer{klog.Error},
+	}
+	if err := kubedrain.RunCordonOrUncordon(drainer, node, false); err != nil {
+		// Machine will be re-reconciled after an uncordon failure.
+		return azure.WithTransientError(errors.Errorf("unable to uncordon node %s: %v", node.Name, err), 20*time.Second)
+	}
+
+	return nil
+}
+
 // isNodeDrainAllowed checks to see the node is excluded from draining or if the NodeDrainTimeout has expired.
 func (s *MachinePoolMachineScope) isNodeDrainAllowed() bool {
 	if _, exists := s.AzureMachinePoolMachine.ObjectMeta.Annotations[clusterv1.ExcludeNodeDrainingAnnotation]; exists {
@@ -481,6 +686,23 @@ func (s *MachinePoolMachineScope) nodeDrainTimeoutExceeded() bool {
 	return diff.Seconds() >= s.AzureMachinePool.Spec.NodeDrainTimeout.Seconds()
 }
 
+// nodeVolumeDetachTimeoutExceeded will check to see if the AzureMachinePool's NodeVolumeDetachTimeout is exceeded for
+// the AzureMachinePoolMachine.
+func (s *MachinePoolMachineScope) nodeVolumeDetachTimeoutExceeded() bool {
+	pool := s.AzureMachinePool
+	if pool == nil || pool.Spec.NodeVolumeDetachTimeout == nil || pool.Spec.NodeVolumeDetachTimeout.Seconds() <= 0 {
+		return false
+	}
+
+	// if the volume detach succeeded condition does not exist
+	if conditions.Get(s.AzureMachinePoolMachine, clusterv1.VolumeDetachSucceededCondition) == nil {
+		return false
+	}
+
+	firstTimeWait := conditions.GetLastTransitionTime(s.AzureMachinePoolMachine, clusterv1.VolumeDetachSucceededCondition)
+	return time.Since(firstTimeWait.Time) >= pool.Spec.NodeVolumeDetachTimeout.Duration
+}
+
 func (s *MachinePoolMachineScope) hasLatestModelApplied(ctx context.Context) (bool, error) {
 	ctx, _, done := tele.StartSpanWithLogger(
 		ctx,
@@ -513,6 +735,35 @@ func newWorkloadClusterProxy(c client.Client, cluster client.ObjectKey) *workloa
 	}
 }
 
+// ListNodes lists the nodes of the workload cluster.
+func (np *workloadClusterProxy) ListNodes(ctx context.Context) ([]corev1.Node, error) {
+	ctx, _, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.workloadClusterProxy.ListNodes",
+	)
+	defer done()
+
+	workloadClient, err := getWorkloadClient(ctx, np.Client, np.Cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the workload cluster client")
+	}
+
+	var (
+		nodes    []corev1.Node
+		nodeList corev1.NodeList
+	)
+	for {
+		if err := workloadClient.List(ctx, &nodeList, client.Continue(nodeList.Continue)); err != nil {
+			return nil, errors.Wrap(err, "failed to list nodes")
+		}
+		nodes = append(nodes, nodeList.Items...)
+
+		if nodeList.Continue == "" {
+			return nodes, nil
+		}
+	}
+}
+
 // GetNodeByObjectReference will fetch a *corev1.Node via a node object reference.
 func (np *workloadClusterProxy) GetNodeByObjectReference(ctx context.Context, nodeRef corev1.ObjectReference) (*corev1.Node, error) {
 	workloadClient, err := getWorkloadClient(ctx, np.Client, np.Cluster)
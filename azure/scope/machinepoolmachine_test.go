@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
@@ -34,6 +35,7 @@ import (
 	gomock2 "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -240,6 +242,17 @@ func TestMachineScope_UpdateStatus(t *testing.T) {
 				}))
 			},
 		},
+		{
+			Name: "should mark the instance remediated once the AMPM is ready",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) (*azure.VMSSVM, *infrav1.AzureMachinePoolMachine) {
+				conditions.MarkFalse(ampm, v1beta1.InstanceRemediatedCondition, v1beta1.InstanceReimagingReason, clusterv1.ConditionSeverityWarning, "")
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(getReadyNode(), nil)
+				return &azure.VMSSVM{State: v1beta1.Succeeded}, ampm
+			},
+			Verify: func(g *WithT, scope *MachinePoolMachineScope) {
+				g.Expect(conditions.IsTrue(scope.AzureMachinePoolMachine, v1beta1.InstanceRemediatedCondition)).To(BeTrue())
+			},
+		},
 		{
 			Name: "instance information with latest model populates the AMPM status",
 			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) (*azure.VMSSVM, *infrav1.AzureMachinePoolMachine) {
@@ -409,6 +422,300 @@ func TestMachinePoolMachineScope_CordonAndDrain(t *testing.T) {
 	}
 }
 
+func TestMachinePoolMachineScope_WaitForVolumeDetach(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	var (
+		clusterScope = ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-foo",
+				},
+			},
+		}
+		nodeWithVolumes = func() *corev1.Node {
+			node := getReadyNode()
+			node.Status.VolumesAttached = []corev1.AttachedVolume{
+				{Name: "kubernetes.io/csi/disk.csi.azure.com^disk1"},
+			}
+			return node
+		}
+	)
+
+	cases := []struct {
+		Name    string
+		Timeout *metav1.Duration
+		Setup   func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine)
+		Err     string
+		Verify  func(g *WithT, ampm *infrav1.AzureMachinePoolMachine)
+	}{
+		{
+			Name:  "should not wait for the volumes of a node which wasn't drained",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) {},
+			Verify: func(g *WithT, ampm *infrav1.AzureMachinePoolMachine) {
+				g.Expect(conditions.Has(ampm, clusterv1.VolumeDetachSucceededCondition)).To(BeFalse())
+			},
+		},
+		{
+			Name: "should succeed once the volumes of the node are detached",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) {
+				conditions.MarkTrue(ampm, clusterv1.DrainingSucceededCondition)
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(getReadyNode(), nil)
+			},
+			Verify: func(g *WithT, ampm *infrav1.AzureMachinePoolMachine) {
+				g.Expect(conditions.IsTrue(ampm, clusterv1.VolumeDetachSucceededCondition)).To(BeTrue())
+			},
+		},
+		{
+			Name: "should requeue while volumes are attached to the node",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) {
+				conditions.MarkTrue(ampm, clusterv1.DrainingSucceededCondition)
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nodeWithVolumes(), nil)
+			},
+			Err: "waiting for 1 volumes to be detached from node node1. Object will be requeued after 20s",
+			Verify: func(g *WithT, ampm *infrav1.AzureMachinePoolMachine) {
+				g.Expect(conditions.GetReason(ampm, clusterv1.VolumeDetachSucceededCondition)).To(Equal(clusterv1.WaitingForVolumeDetachReason))
+			},
+		},
+		{
+			Name:    "should stop waiting for the volumes once the timeout is exceeded",
+			Timeout: &metav1.Duration{Duration: time.Minute},
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) {
+				conditions.MarkTrue(ampm, clusterv1.DrainingSucceededCondition)
+				conditions.Set(ampm, &clusterv1.Condition{
+					Type:               clusterv1.VolumeDetachSucceededCondition,
+					Status:             corev1.ConditionFalse,
+					Reason:             clusterv1.WaitingForVolumeDetachReason,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+				})
+			},
+		},
+		{
+			Name:    "should keep waiting for the volumes until the timeout is exceeded",
+			Timeout: &metav1.Duration{Duration: time.Hour},
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) {
+				conditions.MarkTrue(ampm, clusterv1.DrainingSucceededCondition)
+				conditions.Set(ampm, &clusterv1.Condition{
+					Type:               clusterv1.VolumeDetachSucceededCondition,
+					Status:             corev1.ConditionFalse,
+					Reason:             clusterv1.WaitingForVolumeDetachReason,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+				})
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nodeWithVolumes(), nil)
+			},
+			Err: "waiting for 1 volumes to be detached from node node1. Object will be requeued after 20s",
+		},
+		{
+			Name: "if GetNodeByProviderID fails with an error, an error will be returned",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) {
+				conditions.MarkTrue(ampm, clusterv1.DrainingSucceededCondition)
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nil, errors.New("boom"))
+			},
+			Err: "failed to find node: boom",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var (
+				controller = gomock.NewController(t)
+				mockClient = mock_scope.NewMocknodeGetter(controller)
+				g          = NewWithT(t)
+				params     = MachinePoolMachineScopeParams{
+					Client:       fake.NewClientBuilder().WithScheme(scheme).Build(),
+					ClusterScope: &clusterScope,
+					MachinePool: &capiv1exp.MachinePool{
+						Spec: capiv1exp.MachinePoolSpec{
+							Template: clusterv1.MachineTemplateSpec{
+								Spec: clusterv1.MachineSpec{
+									Version: to.StringPtr("v1.19.11"),
+								},
+							},
+						},
+					},
+					AzureMachinePool: &infrav1.AzureMachinePool{
+						Spec: infrav1.AzureMachinePoolSpec{
+							NodeVolumeDetachTimeout: c.Timeout,
+						},
+					},
+				}
+			)
+
+			defer controller.Finish()
+
+			ampm := &infrav1.AzureMachinePoolMachine{
+				Spec: infrav1.AzureMachinePoolMachineSpec{
+					ProviderID: FakeProviderID,
+				},
+			}
+			c.Setup(mockClient, ampm)
+			params.AzureMachinePoolMachine = ampm
+			s, err := NewMachinePoolMachineScope(params)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).ToNot(BeNil())
+			s.workloadNodeGetter = mockClient
+
+			err = s.WaitForVolumeDetach(context.TODO())
+			if c.Err == "" {
+				g.Expect(err).To(Succeed())
+			} else {
+				g.Expect(err).To(MatchError(c.Err))
+			}
+
+			if c.Verify != nil {
+				c.Verify(g, ampm)
+			}
+		})
+	}
+}
+
+func TestMachinePoolMachineScope_HandleScheduledEvents(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	var (
+		clusterScope = ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-foo",
+				},
+			},
+		}
+	)
+
+	cases := []struct {
+		Name   string
+		Policy *infrav1.ScheduledEventsPolicy
+		Setup  func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) string
+		Err    string
+		Verify func(g *WithT, ampm *infrav1.AzureMachinePoolMachine)
+	}{
+		{
+			Name:   "should ignore scheduled events if the pool doesn't handle them",
+			Policy: nil,
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) string {
+				return infrav1.ScheduledEventPreempt
+			},
+			Verify: func(g *WithT, ampm *infrav1.AzureMachinePoolMachine) {
+				g.Expect(conditions.Has(ampm, v1beta1.ScheduledEventDrainedCondition)).To(BeFalse())
+			},
+		},
+		{
+			Name:   "should do nothing without scheduled event",
+			Policy: &infrav1.ScheduledEventsPolicy{Enabled: true},
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) string {
+				return ""
+			},
+			Verify: func(g *WithT, ampm *infrav1.AzureMachinePoolMachine) {
+				g.Expect(conditions.Has(ampm, v1beta1.ScheduledEventDrainedCondition)).To(BeFalse())
+			},
+		},
+		{
+			Name:   "should report a spot eviction if the node does not exist",
+			Policy: &infrav1.ScheduledEventsPolicy{Enabled: true},
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) string {
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nil, nil)
+				return infrav1.ScheduledEventPreempt
+			},
+			Verify: func(g *WithT, ampm *infrav1.AzureMachinePoolMachine) {
+				g.Expect(conditions.IsFalse(ampm, v1beta1.ScheduledEventDrainedCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(ampm, v1beta1.ScheduledEventDrainedCondition)).To(Equal(v1beta1.SpotEvictionReason))
+			},
+		},
+		{
+			Name:   "should not drain again an already drained node",
+			Policy: &infrav1.ScheduledEventsPolicy{Enabled: true},
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) string {
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(getReadyNode(), nil)
+				conditions.MarkTrue(ampm, v1beta1.ScheduledEventDrainedCondition)
+				return "Redeploy"
+			},
+			Verify: func(g *WithT, ampm *infrav1.AzureMachinePoolMachine) {
+				g.Expect(conditions.IsTrue(ampm, v1beta1.ScheduledEventDrainedCondition)).To(BeTrue())
+			},
+		},
+		{
+			Name:   "should clear the condition once the event is over",
+			Policy: &infrav1.ScheduledEventsPolicy{Enabled: true},
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) string {
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nil, nil)
+				conditions.MarkTrue(ampm, v1beta1.ScheduledEventDrainedCondition)
+				return ""
+			},
+			Verify: func(g *WithT, ampm *infrav1.AzureMachinePoolMachine) {
+				g.Expect(conditions.Has(ampm, v1beta1.ScheduledEventDrainedCondition)).To(BeFalse())
+			},
+		},
+		{
+			Name:   "if GetNodeByProviderID fails with an error, an error will be returned",
+			Policy: &infrav1.ScheduledEventsPolicy{Enabled: true},
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) string {
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nil, errors.New("boom"))
+				return "Redeploy"
+			},
+			Err: "failed to find node: boom",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var (
+				controller = gomock.NewController(t)
+				mockClient = mock_scope.NewMocknodeGetter(controller)
+				g          = NewWithT(t)
+				params     = MachinePoolMachineScopeParams{
+					Client:       fake.NewClientBuilder().WithScheme(scheme).Build(),
+					ClusterScope: &clusterScope,
+					MachinePool: &capiv1exp.MachinePool{
+						Spec: capiv1exp.MachinePoolSpec{
+							Template: clusterv1.MachineTemplateSpec{
+								Spec: clusterv1.MachineSpec{
+									Version: to.StringPtr("v1.19.11"),
+								},
+							},
+						},
+					},
+					AzureMachinePool: &infrav1.AzureMachinePool{
+						Spec: infrav1.AzureMachinePoolSpec{
+							ScheduledEventsPolicy: c.Policy,
+						},
+					},
+				}
+			)
+
+			defer controller.Finish()
+
+			ampm := &infrav1.AzureMachinePoolMachine{
+				Spec: infrav1.AzureMachinePoolMachineSpec{
+					ProviderID: FakeProviderID,
+				},
+			}
+			if event := c.Setup(mockClient, ampm); event != "" {
+				ampm.Annotations = map[string]string{infrav1.ScheduledEventAnnotation: event}
+			}
+			params.AzureMachinePoolMachine = ampm
+			s, err := NewMachinePoolMachineScope(params)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).ToNot(BeNil())
+			s.workloadNodeGetter = mockClient
+
+			err = s.HandleScheduledEvents(context.TODO())
+			if c.Err == "" {
+				g.Expect(err).To(Succeed())
+			} else {
+				g.Expect(err).To(MatchError(c.Err))
+			}
+
+			if c.Verify != nil {
+				c.Verify(g, ampm)
+			}
+		})
+	}
+}
+
 func getReadyNode() *corev1.Node {
 	return &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -446,3 +753,172 @@ func getNotReadyNode() *corev1.Node {
 		},
 	}
 }
+
+func TestMachinePoolMachineScope_InPlaceRemediation(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Strategy *infrav1.RemediationStrategy
+		Instance *azure.VMSSVM
+		Setup    func(ampm *infrav1.AzureMachinePoolMachine)
+		Want     infrav1.RemediationStrategyType
+	}{
+		{
+			Name:     "should delete failed instances by default",
+			Instance: &azure.VMSSVM{State: v1beta1.Failed},
+			Want:     "",
+		},
+		{
+			Name:     "should reimage a failed instance",
+			Strategy: &infrav1.RemediationStrategy{Type: infrav1.ReimageRemediationStrategyType},
+			Instance: &azure.VMSSVM{State: v1beta1.Failed},
+			Want:     infrav1.ReimageRemediationStrategyType,
+		},
+		{
+			Name:     "should redeploy a failed instance remediated in place before it was last ready",
+			Strategy: &infrav1.RemediationStrategy{Type: infrav1.RedeployRemediationStrategyType},
+			Instance: &azure.VMSSVM{State: v1beta1.Failed},
+			Setup: func(ampm *infrav1.AzureMachinePoolMachine) {
+				conditions.MarkTrue(ampm, v1beta1.InstanceRemediatedCondition)
+			},
+			Want: infrav1.RedeployRemediationStrategyType,
+		},
+		{
+			Name:     "should not remediate a healthy instance",
+			Strategy: &infrav1.RemediationStrategy{Type: infrav1.ReimageRemediationStrategyType},
+			Instance: &azure.VMSSVM{State: v1beta1.Succeeded},
+			Want:     "",
+		},
+		{
+			Name:     "should not remediate an instance in place twice until it is ready",
+			Strategy: &infrav1.RemediationStrategy{Type: infrav1.ReimageRemediationStrategyType},
+			Instance: &azure.VMSSVM{State: v1beta1.Failed},
+			Setup: func(ampm *infrav1.AzureMachinePoolMachine) {
+				conditions.MarkFalse(ampm, v1beta1.InstanceRemediatedCondition, v1beta1.InstanceReimagingReason, clusterv1.ConditionSeverityWarning, "")
+			},
+			Want: "",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &infrav1.AzureMachinePool{
+				Spec: infrav1.AzureMachinePoolSpec{RemediationStrategy: c.Strategy},
+			}
+			ampm := &infrav1.AzureMachinePoolMachine{}
+			if c.Setup != nil {
+				c.Setup(ampm)
+			}
+			s := &MachinePoolMachineScope{
+				AzureMachinePool:        amp,
+				AzureMachinePoolMachine: ampm,
+				MachinePoolScope:        &MachinePoolScope{AzureMachinePool: amp},
+				instance:                c.Instance,
+			}
+			g.Expect(s.InPlaceRemediation()).To(Equal(c.Want))
+
+			if c.Want != "" {
+				s.SetInPlaceRemediationStarted(c.Want)
+				g.Expect(conditions.IsFalse(ampm, v1beta1.InstanceRemediatedCondition)).To(BeTrue())
+				g.Expect(s.InPlaceRemediation()).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestMachinePoolMachineScope_InstanceProtection(t *testing.T) {
+	nodeRef := &corev1.ObjectReference{Name: "node1"}
+	protectedNode := func(protection string) *corev1.Node {
+		node := getReadyNode()
+		node.Annotations = map[string]string{infrav1.InstanceProtectionAnnotation: protection}
+		return node
+	}
+
+	cases := []struct {
+		Name        string
+		Annotations map[string]string
+		NodeRef     *corev1.ObjectReference
+		Setup       func(mockNodeGetter *mock_scope.MocknodeGetter)
+		Want        string
+		Err         string
+	}{
+		{
+			Name:  "should not protect an instance without annotation and node",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter) {},
+		},
+		{
+			Name:        "should use the annotation of the AzureMachinePoolMachine",
+			Annotations: map[string]string{infrav1.InstanceProtectionAnnotation: infrav1.InstanceProtectionScaleSetActions},
+			NodeRef:     nodeRef,
+			Setup:       func(mockNodeGetter *mock_scope.MocknodeGetter) {},
+			Want:        infrav1.InstanceProtectionScaleSetActions,
+		},
+		{
+			Name:    "should use the annotation of the node",
+			NodeRef: nodeRef,
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter) {
+				mockNodeGetter.EXPECT().GetNodeByObjectReference(gomock2.AContext(), *nodeRef).Return(protectedNode(infrav1.InstanceProtectionScaleIn), nil)
+			},
+			Want: infrav1.InstanceProtectionScaleIn,
+		},
+		{
+			Name:    "should not protect an instance whose node isn't annotated",
+			NodeRef: nodeRef,
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter) {
+				mockNodeGetter.EXPECT().GetNodeByObjectReference(gomock2.AContext(), *nodeRef).Return(getReadyNode(), nil)
+			},
+		},
+		{
+			Name:    "should fail on an invalid protection",
+			NodeRef: nodeRef,
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter) {
+				mockNodeGetter.EXPECT().GetNodeByObjectReference(gomock2.AContext(), *nodeRef).Return(protectedNode("Always"), nil)
+			},
+			Err: `annotation azuremachinepoolmachine.infrastructure.cluster.x-k8s.io/instance-protection must be ScaleIn or ScaleSetActions, got "Always"`,
+		},
+		{
+			Name:    "if GetNodeByObjectReference fails with an error, an error will be returned",
+			NodeRef: nodeRef,
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter) {
+				mockNodeGetter.EXPECT().GetNodeByObjectReference(gomock2.AContext(), *nodeRef).Return(nil, errors.New("boom"))
+			},
+			Err: "failed to get the node of the instance: boom",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var (
+				controller     = gomock.NewController(t)
+				mockNodeGetter = mock_scope.NewMocknodeGetter(controller)
+				g              = NewWithT(t)
+			)
+			defer controller.Finish()
+
+			c.Setup(mockNodeGetter)
+			s := &MachinePoolMachineScope{
+				AzureMachinePoolMachine: &infrav1.AzureMachinePoolMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: c.Annotations,
+					},
+					Spec: infrav1.AzureMachinePoolMachineSpec{
+						ProviderID: FakeProviderID,
+					},
+					Status: infrav1.AzureMachinePoolMachineStatus{
+						NodeRef: c.NodeRef,
+					},
+				},
+				workloadNodeGetter: mockNodeGetter,
+			}
+
+			got, err := s.InstanceProtection(context.TODO())
+			if c.Err == "" {
+				g.Expect(err).To(Succeed())
+				g.Expect(got).To(Equal(c.Want))
+			} else {
+				g.Expect(err).To(MatchError(c.Err))
+			}
+		})
+	}
+}
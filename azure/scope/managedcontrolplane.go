@@ -23,9 +23,11 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -71,7 +73,7 @@ func NewManagedControlPlaneScope(ctx context.Context, params ManagedControlPlane
 	}
 
 	if params.ControlPlane.Spec.IdentityRef == nil {
-		if err := params.AzureClients.setCredentials(params.ControlPlane.Spec.SubscriptionID, ""); err != nil {
+		if err := params.AzureClients.setCredentials(params.ControlPlane.Spec.SubscriptionID, "", nil); err != nil {
 			return nil, errors.Wrap(err, "failed to create Azure session")
 		}
 	} else {
@@ -80,7 +82,7 @@ func NewManagedControlPlaneScope(ctx context.Context, params ManagedControlPlane
 			return nil, errors.Wrap(err, "failed to init credentials provider")
 		}
 
-		if err := params.AzureClients.setCredentialsWithProvider(ctx, params.ControlPlane.Spec.SubscriptionID, "", credentialsProvider); err != nil {
+		if err := params.AzureClients.setCredentialsWithProvider(ctx, params.ControlPlane.Spec.SubscriptionID, "", nil, credentialsProvider); err != nil {
 			return nil, errors.Wrap(err, "failed to configure azure settings and credentials for Identity")
 		}
 	}
@@ -107,6 +109,8 @@ type ManagedControlPlaneScope struct {
 	Client         client.Client
 	patchHelper    *patch.Helper
 	kubeConfigData []byte
+	// kubeConfigTokenExpiry is when the token of the kubeconfig expires, if it does.
+	kubeConfigTokenExpiry time.Time
 
 	AzureClients
 	Cluster          *clusterv1.Cluster
@@ -116,6 +120,8 @@ type ManagedControlPlaneScope struct {
 	PatchTarget      client.Object
 
 	AllNodePools []infrav1exp.AzureManagedMachinePool
+
+	vnet *infrav1.VnetSpec
 }
 
 // ResourceGroup returns the managed control plane's resource group.
@@ -192,13 +198,35 @@ func (s *ManagedControlPlaneScope) Close(ctx context.Context) error {
 	return s.PatchObject(ctx)
 }
 
-// Vnet returns the cluster Vnet.
+// Vnet returns the cluster Vnet. It is built from the spec on first use, and then holds the state of the existing
+// virtual network set by the virtual networks service.
 func (s *ManagedControlPlaneScope) Vnet() *infrav1.VnetSpec {
-	return &infrav1.VnetSpec{
-		ResourceGroup: s.ControlPlane.Spec.ResourceGroupName,
-		Name:          s.ControlPlane.Spec.VirtualNetwork.Name,
-		CIDRBlocks:    []string{s.ControlPlane.Spec.VirtualNetwork.CIDRBlock},
+	if s.vnet == nil {
+		s.vnet = &infrav1.VnetSpec{
+			ResourceGroup: s.vnetResourceGroup(),
+			Name:          s.ControlPlane.Spec.VirtualNetwork.Name,
+			CIDRBlocks:    []string{s.ControlPlane.Spec.VirtualNetwork.CIDRBlock},
+		}
+	}
+	return s.vnet
+}
+
+// vnetResourceGroup returns the resource group of the cluster Vnet.
+func (s *ManagedControlPlaneScope) vnetResourceGroup() string {
+	if s.ControlPlane.Spec.VirtualNetwork.ResourceGroup != "" {
+		return s.ControlPlane.Spec.VirtualNetwork.ResourceGroup
+	}
+	return s.ControlPlane.Spec.ResourceGroupName
+}
+
+// nodeSubnetID returns the ID of the subnet of the cluster Vnet with the given name, or of the cluster node subnet if
+// it is nil.
+func (s *ManagedControlPlaneScope) nodeSubnetID(subnetName *string) string {
+	name := s.ControlPlane.Spec.VirtualNetwork.Subnet.Name
+	if subnetName != nil {
+		name = *subnetName
 	}
+	return azure.SubnetID(s.ControlPlane.Spec.SubscriptionID, s.vnetResourceGroup(), s.ControlPlane.Spec.VirtualNetwork.Name, name)
 }
 
 // GroupSpec returns the resource group spec.
@@ -220,6 +248,36 @@ func (s *ManagedControlPlaneScope) VNetSpec() azure.VNetSpec {
 	}
 }
 
+// azureBuiltInNetworkContributorID is the ID of the Network Contributor Azure built-in role.
+const azureBuiltInNetworkContributorID = "4d97b98b-1d4f-4787-a291-c67834d212e7"
+
+const (
+	// kubeConfigTokenRefreshWindow is how long before it expires the token of a kubeconfig is refreshed. It matches the
+	// refresh window of the tokens of the Azure SDK, which only issues a new token within it.
+	kubeConfigTokenRefreshWindow = 5 * time.Minute
+	// minKubeConfigRefreshAfter is the shortest delay before refreshing the token of a kubeconfig.
+	minKubeConfigRefreshAfter = 30 * time.Second
+)
+
+// RoleAssignmentSpecs returns the role assignment specs. When the cluster uses an existing virtual network, the
+// identity of the managed cluster is granted the Network Contributor role on it, so that AKS can manage the load
+// balancers and IP addresses of the cluster in the subnets of its node pools.
+func (s *ManagedControlPlaneScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
+	if s.IsVnetManaged() {
+		return nil
+	}
+	vnetID := azure.VNetID(s.ControlPlane.Spec.SubscriptionID, s.vnetResourceGroup(), s.ControlPlane.Spec.VirtualNetwork.Name)
+	return []azure.RoleAssignmentSpec{
+		{
+			MachineName:      s.ControlPlane.Name,
+			Name:             uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.ToLower(vnetID)+"/"+string(s.ControlPlane.UID))).String(),
+			ResourceType:     azure.ManagedCluster,
+			Scope:            vnetID,
+			RoleDefinitionID: azureBuiltInNetworkContributorID,
+		},
+	}
+}
+
 // ControlPlaneRouteTable returns the cluster controlplane routetable.
 func (s *ManagedControlPlaneScope) ControlPlaneRouteTable() infrav1.RouteTable {
 	return infrav1.RouteTable{}
@@ -299,7 +357,7 @@ func (s *ManagedControlPlaneScope) IsIPv6Enabled() bool {
 
 // IsVnetManaged returns true if the vnet is managed.
 func (s *ManagedControlPlaneScope) IsVnetManaged() bool {
-	return true
+	return s.Vnet().IsManaged(s.ClusterName())
 }
 
 // APIServerLBName returns the API Server LB name.
@@ -345,6 +403,21 @@ func (s *ManagedControlPlaneScope) FailureDomains() []string {
 	return []string{}
 }
 
+// ProxyConfig returns the HTTP proxy configuration for the managed cluster.
+func (s *ManagedControlPlaneScope) ProxyConfig() *infrav1.ProxyConfig {
+	return s.ControlPlane.Spec.ProxyConfig
+}
+
+// TrustedCAs is a no-op for managed clusters, whose nodes have no bootstrap data.
+func (s *ManagedControlPlaneScope) TrustedCAs() *infrav1.TrustedCASource {
+	return nil
+}
+
+// RegistryMirrors is a no-op for managed clusters, whose nodes have no bootstrap data.
+func (s *ManagedControlPlaneScope) RegistryMirrors() []infrav1.RegistryMirror {
+	return nil
+}
+
 // ManagedClusterSpec returns the managed cluster spec.
 func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpec, error) {
 	decodedSSHPublicKey, err := base64.StdEncoding.DecodeString(s.ControlPlane.Spec.SSHPublicKey)
@@ -361,20 +434,23 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpe
 		Version:               strings.TrimPrefix(s.ControlPlane.Spec.Version, "v"),
 		SSHPublicKey:          string(decodedSSHPublicKey),
 		DNSServiceIP:          s.ControlPlane.Spec.DNSServiceIP,
-		VnetSubnetID: azure.SubnetID(
-			s.ControlPlane.Spec.SubscriptionID,
-			s.ControlPlane.Spec.ResourceGroupName,
-			s.ControlPlane.Spec.VirtualNetwork.Name,
-			s.ControlPlane.Spec.VirtualNetwork.Subnet.Name,
-		),
+		VnetSubnetID:          s.nodeSubnetID(nil),
+		Stopped:               s.ControlPlane.Spec.Stopped,
+		DisableLocalAccounts:  s.ControlPlane.Spec.DisableLocalAccounts,
 	}
 
 	if s.ControlPlane.Spec.NetworkPlugin != nil {
 		managedClusterSpec.NetworkPlugin = *s.ControlPlane.Spec.NetworkPlugin
 	}
+	if s.ControlPlane.Spec.NetworkPluginMode != nil {
+		managedClusterSpec.NetworkPluginMode = *s.ControlPlane.Spec.NetworkPluginMode
+	}
 	if s.ControlPlane.Spec.NetworkPolicy != nil {
 		managedClusterSpec.NetworkPolicy = *s.ControlPlane.Spec.NetworkPolicy
 	}
+	if s.ControlPlane.Spec.NetworkDataplane != nil {
+		managedClusterSpec.NetworkDataplane = *s.ControlPlane.Spec.NetworkDataplane
+	}
 	if s.ControlPlane.Spec.LoadBalancerSKU != nil {
 		managedClusterSpec.LoadBalancerSKU = *s.ControlPlane.Spec.LoadBalancerSKU
 	}
@@ -449,6 +525,53 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpe
 		}
 	}
 
+	if s.ControlPlane.Spec.ProxyConfig != nil {
+		managedClusterSpec.HTTPProxyConfig = &azure.HTTPProxyConfig{
+			HTTPProxy:  s.ControlPlane.Spec.ProxyConfig.HTTPProxy,
+			HTTPSProxy: s.ControlPlane.Spec.ProxyConfig.HTTPSProxy,
+			NoProxy:    s.ControlPlane.Spec.ProxyConfig.NoProxy,
+			TrustedCA:  s.ControlPlane.Spec.ProxyConfig.TrustedCA,
+		}
+	}
+
+	if profile := s.ControlPlane.Spec.AutoScalerProfile; profile != nil {
+		managedClusterSpec.AutoScalerProfile = &azure.AutoScalerProfile{
+			BalanceSimilarNodeGroups:      profile.BalanceSimilarNodeGroups,
+			Expander:                      (*string)(profile.Expander),
+			MaxEmptyBulkDelete:            profile.MaxEmptyBulkDelete,
+			MaxGracefulTerminationSec:     profile.MaxGracefulTerminationSec,
+			MaxNodeProvisionTime:          profile.MaxNodeProvisionTime,
+			MaxTotalUnreadyPercentage:     profile.MaxTotalUnreadyPercentage,
+			NewPodScaleUpDelay:            profile.NewPodScaleUpDelay,
+			OkTotalUnreadyCount:           profile.OkTotalUnreadyCount,
+			ScanInterval:                  profile.ScanInterval,
+			ScaleDownDelayAfterAdd:        profile.ScaleDownDelayAfterAdd,
+			ScaleDownDelayAfterDelete:     profile.ScaleDownDelayAfterDelete,
+			ScaleDownDelayAfterFailure:    profile.ScaleDownDelayAfterFailure,
+			ScaleDownUnneededTime:         profile.ScaleDownUnneededTime,
+			ScaleDownUnreadyTime:          profile.ScaleDownUnreadyTime,
+			ScaleDownUtilizationThreshold: profile.ScaleDownUtilizationThreshold,
+			SkipNodesWithLocalStorage:     profile.SkipNodesWithLocalStorage,
+			SkipNodesWithSystemPods:       profile.SkipNodesWithSystemPods,
+		}
+	}
+
+	if s.ControlPlane.Spec.OIDCIssuerProfile != nil {
+		managedClusterSpec.OIDCIssuerEnabled = to.BoolPtr(s.ControlPlane.Spec.OIDCIssuerProfile.Enabled)
+	}
+
+	if securityProfile := s.ControlPlane.Spec.SecurityProfile; securityProfile != nil && securityProfile.WorkloadIdentity != nil {
+		managedClusterSpec.WorkloadIdentityEnabled = to.BoolPtr(securityProfile.WorkloadIdentity.Enabled)
+	}
+
+	for _, profile := range s.ControlPlane.Spec.AddonProfiles {
+		managedClusterSpec.AddonProfiles = append(managedClusterSpec.AddonProfiles, azure.AddonProfile{
+			Name:    profile.Name,
+			Enabled: profile.Enabled,
+			Config:  profile.Config,
+		})
+	}
+
 	return managedClusterSpec, nil
 }
 
@@ -457,19 +580,8 @@ func (s *ManagedControlPlaneScope) GetAgentPoolSpecs(ctx context.Context) ([]azu
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.ManagedControlPlaneScope.GetAgentPoolSpecs")
 	defer done()
 
-	if len(s.AllNodePools) == 0 {
-		opt1 := client.InNamespace(s.ControlPlane.Namespace)
-		opt2 := client.MatchingLabels(map[string]string{
-			clusterv1.ClusterLabelName: s.Cluster.Name,
-		})
-
-		ammpList := &infrav1exp.AzureManagedMachinePoolList{}
-
-		if err := s.Client.List(ctx, ammpList, opt1, opt2); err != nil {
-			return nil, err
-		}
-
-		s.AllNodePools = ammpList.Items
+	if err := s.listNodePools(ctx); err != nil {
+		return nil, err
 	}
 
 	var (
@@ -498,9 +610,13 @@ func (s *ManagedControlPlaneScope) GetAgentPoolSpecs(ctx context.Context) ([]azu
 			SKU:               pool.Spec.SKU,
 			Replicas:          1,
 			OSDiskSizeGB:      0,
+			VnetSubnetID:      s.nodeSubnetID(pool.Spec.SubnetName),
 			Mode:              pool.Spec.Mode,
 			AvailabilityZones: pool.Spec.AvailabilityZones,
+			NodeLabels:        nodeLabels(pool.Spec.NodeLabels),
+			NodeTaints:        nodeTaints(pool.Spec.Taints),
 		}
+		setSpotOptions(&ammp, pool.Spec)
 
 		// Set optional values
 		if pool.Spec.OSDiskSizeGB != nil {
@@ -521,6 +637,94 @@ func (s *ManagedControlPlaneScope) GetAgentPoolSpecs(ctx context.Context) ([]azu
 	return ammps, nil
 }
 
+// listNodePools lists the AzureManagedMachinePools of the cluster into AllNodePools, unless they were already listed.
+func (s *ManagedControlPlaneScope) listNodePools(ctx context.Context) error {
+	if len(s.AllNodePools) != 0 {
+		return nil
+	}
+
+	opt1 := client.InNamespace(s.ControlPlane.Namespace)
+	opt2 := client.MatchingLabels(map[string]string{
+		clusterv1.ClusterLabelName: s.Cluster.Name,
+	})
+
+	ammpList := &infrav1exp.AzureManagedMachinePoolList{}
+
+	if err := s.Client.List(ctx, ammpList, opt1, opt2); err != nil {
+		return err
+	}
+
+	s.AllNodePools = ammpList.Items
+	return nil
+}
+
+// SetSubnetUtilization reports how many nodes the node pools of the cluster hold in its subnet, and how many nodes and
+// pods the subnet can hold, in the status of the AzureManagedControlPlane. The node pools in their own subnets are not
+// counted.
+func (s *ManagedControlPlaneScope) SetSubnetUtilization(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ManagedControlPlaneScope.SetSubnetUtilization")
+	defer done()
+
+	maxNodes, maxPods, err := s.ControlPlane.SubnetCapacity()
+	if err != nil {
+		return errors.Wrap(err, "failed to compute the capacity of the subnet")
+	}
+	if err := s.listNodePools(ctx); err != nil {
+		return errors.Wrap(err, "failed to list node pools")
+	}
+
+	var nodes int32
+	for _, pool := range s.AllNodePools {
+		if pool.Spec.SubnetName != nil && *pool.Spec.SubnetName != s.ControlPlane.Spec.VirtualNetwork.Subnet.Name {
+			continue
+		}
+		nodes += pool.Status.Replicas
+	}
+	s.ControlPlane.Status.SubnetUtilization = &infrav1exp.SubnetUtilization{
+		Nodes:    nodes,
+		MaxNodes: maxNodes,
+		MaxPods:  maxPods,
+	}
+	return nil
+}
+
+// nodeLabels converts the node labels of an AzureManagedMachinePool to the ones of an agent pool.
+func nodeLabels(labels map[string]string) map[string]*string {
+	if len(labels) == 0 {
+		return nil
+	}
+	nodeLabels := make(map[string]*string, len(labels))
+	for key, value := range labels {
+		nodeLabels[key] = to.StringPtr(value)
+	}
+	return nodeLabels
+}
+
+// nodeTaints converts the taints of an AzureManagedMachinePool to the key=value:effect form of an agent pool.
+func nodeTaints(taints []infrav1exp.Taint) []string {
+	if len(taints) == 0 {
+		return nil
+	}
+	nodeTaints := make([]string, 0, len(taints))
+	for _, taint := range taints {
+		nodeTaints = append(nodeTaints, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+	}
+	return nodeTaints
+}
+
+// setSpotOptions sets the priority, eviction policy and maximum price of an agent pool from an AzureManagedMachinePool.
+func setSpotOptions(agentPoolSpec *azure.AgentPoolSpec, spec infrav1exp.AzureManagedMachinePoolSpec) {
+	if spec.ScaleSetPriority != nil {
+		agentPoolSpec.ScaleSetPriority = string(*spec.ScaleSetPriority)
+	}
+	if spec.ScaleSetEvictionPolicy != nil {
+		agentPoolSpec.ScaleSetEvictionPolicy = string(*spec.ScaleSetEvictionPolicy)
+	}
+	if spec.SpotMaxPrice != nil {
+		agentPoolSpec.SpotMaxPrice = to.Float64Ptr(spec.SpotMaxPrice.AsApproximateFloat64())
+	}
+}
+
 // AgentPoolSpec returns an azure.AgentPoolSpec for currently reconciled AzureManagedMachinePool.
 func (s *ManagedControlPlaneScope) AgentPoolSpec() azure.AgentPoolSpec {
 	var normalizedVersion *string
@@ -535,21 +739,20 @@ func (s *ManagedControlPlaneScope) AgentPoolSpec() azure.AgentPoolSpec {
 	}
 
 	agentPoolSpec := azure.AgentPoolSpec{
-		Name:          to.String(s.InfraMachinePool.Spec.Name),
-		ResourceGroup: s.ControlPlane.Spec.ResourceGroupName,
-		Cluster:       s.ControlPlane.Name,
-		SKU:           s.InfraMachinePool.Spec.SKU,
-		Replicas:      replicas,
-		Version:       normalizedVersion,
-		VnetSubnetID: azure.SubnetID(
-			s.ControlPlane.Spec.SubscriptionID,
-			s.ControlPlane.Spec.ResourceGroupName,
-			s.ControlPlane.Spec.VirtualNetwork.Name,
-			s.ControlPlane.Spec.VirtualNetwork.Subnet.Name,
-		),
-		Mode:              s.InfraMachinePool.Spec.Mode,
-		AvailabilityZones: s.InfraMachinePool.Spec.AvailabilityZones,
-	}
+		Name:                    to.String(s.InfraMachinePool.Spec.Name),
+		ResourceGroup:           s.ControlPlane.Spec.ResourceGroupName,
+		Cluster:                 s.ControlPlane.Name,
+		SKU:                     s.InfraMachinePool.Spec.SKU,
+		Replicas:                replicas,
+		Version:                 normalizedVersion,
+		VnetSubnetID:            s.nodeSubnetID(s.InfraMachinePool.Spec.SubnetName),
+		Mode:                    s.InfraMachinePool.Spec.Mode,
+		AvailabilityZones:       s.InfraMachinePool.Spec.AvailabilityZones,
+		EnableArtifactStreaming: s.InfraMachinePool.Spec.EnableArtifactStreaming,
+		NodeLabels:              nodeLabels(s.InfraMachinePool.Spec.NodeLabels),
+		NodeTaints:              nodeTaints(s.InfraMachinePool.Spec.Taints),
+	}
+	setSpotOptions(&agentPoolSpec, s.InfraMachinePool.Spec)
 
 	if s.InfraMachinePool.Spec.OSDiskSizeGB != nil {
 		agentPoolSpec.OSDiskSizeGB = *s.InfraMachinePool.Spec.OSDiskSizeGB
@@ -584,6 +787,16 @@ func (s *ManagedControlPlaneScope) SetControlPlaneEndpoint(endpoint clusterv1.AP
 	s.ControlPlane.Spec.ControlPlaneEndpoint = endpoint
 }
 
+// SetPowerState sets the power state of the managed cluster in the status of the control plane.
+func (s *ManagedControlPlaneScope) SetPowerState(powerState string) {
+	s.ControlPlane.Status.PowerState = powerState
+}
+
+// SetOIDCIssuerURL sets the URL of the OIDC issuer of the managed cluster in the status of the control plane.
+func (s *ManagedControlPlaneScope) SetOIDCIssuerURL(url string) {
+	s.ControlPlane.Status.OIDCIssuerURL = url
+}
+
 // MakeEmptyKubeConfigSecret creates an empty secret object that is used for storing kubeconfig secret data.
 func (s *ManagedControlPlaneScope) MakeEmptyKubeConfigSecret() corev1.Secret {
 	return corev1.Secret{
@@ -607,6 +820,24 @@ func (s *ManagedControlPlaneScope) SetKubeConfigData(kubeConfigData []byte) {
 	s.kubeConfigData = kubeConfigData
 }
 
+// SetKubeConfigTokenExpiry sets when the token of the kubeconfig expires.
+func (s *ManagedControlPlaneScope) SetKubeConfigTokenExpiry(expiresOn time.Time) {
+	s.kubeConfigTokenExpiry = expiresOn
+}
+
+// KubeConfigRefreshAfter returns how long until the token of the kubeconfig must be refreshed, or 0 if the kubeconfig
+// doesn't expire. The token is refreshed a few minutes before it expires, when a new token can be issued.
+func (s *ManagedControlPlaneScope) KubeConfigRefreshAfter() time.Duration {
+	if s.kubeConfigTokenExpiry.IsZero() {
+		return 0
+	}
+	refreshAfter := time.Until(s.kubeConfigTokenExpiry) - kubeConfigTokenRefreshWindow
+	if refreshAfter < minKubeConfigRefreshAfter {
+		return minKubeConfigRefreshAfter
+	}
+	return refreshAfter
+}
+
 // SetLongRunningOperationState will set the future on the AzureManagedControlPlane status to allow the resource to continue
 // in the next reconciliation.
 func (s *ManagedControlPlaneScope) SetLongRunningOperationState(future *infrav1.Future) {
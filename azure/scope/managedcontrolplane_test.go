@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -130,6 +131,57 @@ func TestManagedControlPlaneScope_Autoscaling(t *testing.T) {
 	}
 }
 
+func TestManagedControlPlaneScope_SetSubnetUtilization(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	pool0 := getAzureMachinePool("pool0", infrav1.NodePoolModeSystem)
+	pool0.Status.Replicas = 3
+	pool1 := getAzureMachinePoolWithScaling("pool1", 2, 10)
+	pool1.Status.Replicas = 2
+	pool2 := getAzureMachinePool("pool2", infrav1.NodePoolModeUser)
+	pool2.Spec.SubnetName = to.StringPtr("pool2-subnet")
+	pool2.Status.Replicas = 4
+	controlPlane := &infrav1.AzureManagedControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureManagedControlPlaneSpec{
+			SubscriptionID: "00000000-0000-0000-0000-000000000000",
+			NetworkPlugin:  to.StringPtr(infrav1.NetworkPluginAzure),
+			VirtualNetwork: infrav1.ManagedControlPlaneVirtualNetwork{
+				Subnet: infrav1.ManagedControlPlaneSubnet{CIDRBlock: "10.240.0.0/24"},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pool0, pool1, pool2, controlPlane).Build()
+
+	s, err := NewManagedControlPlaneScope(context.TODO(), ManagedControlPlaneScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Client: fakeClient,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: controlPlane,
+		PatchTarget:  controlPlane,
+	})
+	g.Expect(err).To(Succeed())
+	g.Expect(s.SetSubnetUtilization(context.TODO())).To(Succeed())
+	g.Expect(controlPlane.Status.SubnetUtilization).To(Equal(&infrav1.SubnetUtilization{
+		Nodes:    5,
+		MaxNodes: 8,
+		MaxPods:  to.Int32Ptr(240),
+	}))
+}
+
 func getAzureMachinePool(name string, mode infrav1.NodePoolMode) *infrav1.AzureManagedMachinePool {
 	return &infrav1.AzureManagedMachinePool{
 		ObjectMeta: metav1.ObjectMeta{
@@ -177,3 +229,100 @@ func getMachinePool(name string) *capiv1exp.MachinePool {
 		},
 	}
 }
+
+func TestManagedControlPlaneScope_VirtualNetwork(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := &infrav1.AzureManagedControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: "default",
+			UID:       "a5d5b6e1-1f63-4d4b-8e9e-36c3a1c4b0f2",
+		},
+		Spec: infrav1.AzureManagedControlPlaneSpec{
+			SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+			ResourceGroupName: "cluster-rg",
+			VirtualNetwork: infrav1.ManagedControlPlaneVirtualNetwork{
+				Name:          "my-vnet",
+				CIDRBlock:     "10.0.0.0/8",
+				ResourceGroup: "network-rg",
+				Subnet: infrav1.ManagedControlPlaneSubnet{
+					Name:      "my-subnet",
+					CIDRBlock: "10.240.0.0/16",
+				},
+			},
+		},
+	}
+	pool := getAzureMachinePool("pool1", infrav1.NodePoolModeUser)
+	pool.Spec.SubnetName = to.StringPtr("pool1-subnet")
+	s := &ManagedControlPlaneScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+		},
+		ControlPlane:     controlPlane,
+		MachinePool:      getMachinePool("pool1"),
+		InfraMachinePool: pool,
+	}
+
+	g.Expect(s.Vnet().ResourceGroup).To(Equal("network-rg"))
+	g.Expect(s.AgentPoolSpec().VnetSubnetID).To(Equal("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/network-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/pool1-subnet"))
+
+	// The virtual network is managed until the virtual networks service finds an existing one not owned by the cluster.
+	g.Expect(s.IsVnetManaged()).To(BeTrue())
+	g.Expect(s.RoleAssignmentSpecs()).To(BeEmpty())
+
+	s.Vnet().ID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/network-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"
+	g.Expect(s.IsVnetManaged()).To(BeFalse())
+	g.Expect(s.RoleAssignmentSpecs()).To(Equal([]azure.RoleAssignmentSpec{
+		{
+			MachineName:      "cluster1",
+			Name:             s.RoleAssignmentSpecs()[0].Name,
+			ResourceType:     azure.ManagedCluster,
+			Scope:            "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/network-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+			RoleDefinitionID: "4d97b98b-1d4f-4787-a291-c67834d212e7",
+		},
+	}))
+	g.Expect(s.RoleAssignmentSpecs()[0].Name).To(MatchRegexp("^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$"))
+}
+
+func TestManagedControlPlaneScope_KubeConfigRefreshAfter(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Expiry   time.Time
+		Expected func(g *WithT, refreshAfter time.Duration)
+	}{
+		{
+			Name:   "should not refresh a kubeconfig without token",
+			Expiry: time.Time{},
+			Expected: func(g *WithT, refreshAfter time.Duration) {
+				g.Expect(refreshAfter).To(BeZero())
+			},
+		},
+		{
+			Name:   "should refresh the token a few minutes before it expires",
+			Expiry: time.Now().Add(time.Hour),
+			Expected: func(g *WithT, refreshAfter time.Duration) {
+				g.Expect(refreshAfter).To(BeNumerically("~", 55*time.Minute, time.Minute))
+			},
+		},
+		{
+			Name:   "should refresh an expiring token shortly",
+			Expiry: time.Now().Add(time.Minute),
+			Expected: func(g *WithT, refreshAfter time.Duration) {
+				g.Expect(refreshAfter).To(Equal(30 * time.Second))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ManagedControlPlaneScope{}
+			s.SetKubeConfigTokenExpiry(c.Expiry)
+			c.Expected(g, s.KubeConfigRefreshAfter())
+		})
+	}
+}
@@ -80,3 +80,41 @@ func (mr *MocknodeGetterMockRecorder) GetNodeByProviderID(ctx, providerID interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeByProviderID", reflect.TypeOf((*MocknodeGetter)(nil).GetNodeByProviderID), ctx, providerID)
 }
+
+// MocknodeLister is a mock of nodeLister interface.
+type MocknodeLister struct {
+	ctrl     *gomock.Controller
+	recorder *MocknodeListerMockRecorder
+}
+
+// MocknodeListerMockRecorder is the mock recorder for MocknodeLister.
+type MocknodeListerMockRecorder struct {
+	mock *MocknodeLister
+}
+
+// NewMocknodeLister creates a new mock instance.
+func NewMocknodeLister(ctrl *gomock.Controller) *MocknodeLister {
+	mock := &MocknodeLister{ctrl: ctrl}
+	mock.recorder = &MocknodeListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocknodeLister) EXPECT() *MocknodeListerMockRecorder {
+	return m.recorder
+}
+
+// ListNodes mocks base method.
+func (m *MocknodeLister) ListNodes(ctx context.Context) ([]v1.Node, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNodes", ctx)
+	ret0, _ := ret[0].([]v1.Node)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNodes indicates an expected call of ListNodes.
+func (mr *MocknodeListerMockRecorder) ListNodes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodes", reflect.TypeOf((*MocknodeLister)(nil).ListNodes), ctx)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/virtualmachineimagebuilder/mgmt/2020-02-14/virtualmachineimagebuilder"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagebuilder"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// NodeImageScopeParams defines the input parameters used to create a new NodeImageScope.
+type NodeImageScopeParams struct {
+	AzureClients
+	Client    client.Client
+	NodeImage *infrav1exp.AzureNodeImage
+}
+
+// NewNodeImageScope creates a new NodeImageScope from the supplied parameters.
+// This is meant to be called for each reconcile iteration.
+func NewNodeImageScope(ctx context.Context, params NodeImageScopeParams) (*NodeImageScope, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.NewNodeImageScope")
+	defer done()
+
+	if params.NodeImage == nil {
+		return nil, errors.New("failed to generate new scope from nil AzureNodeImage")
+	}
+
+	if params.NodeImage.Spec.IdentityRef == nil {
+		if err := params.AzureClients.setCredentials(params.NodeImage.Spec.SubscriptionID, "", nil); err != nil {
+			return nil, errors.Wrap(err, "failed to create Azure session")
+		}
+	} else {
+		credentialsProvider, err := NewNodeImageCredentialsProvider(ctx, params.Client, params.NodeImage)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to init credentials provider")
+		}
+
+		if err := params.AzureClients.setCredentialsWithProvider(ctx, params.NodeImage.Spec.SubscriptionID, "", nil, credentialsProvider); err != nil {
+			return nil, errors.Wrap(err, "failed to configure azure settings and credentials for Identity")
+		}
+	}
+
+	helper, err := patch.NewHelper(params.NodeImage, params.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init patch helper")
+	}
+
+	return &NodeImageScope{
+		Client:       params.Client,
+		AzureClients: params.AzureClients,
+		NodeImage:    params.NodeImage,
+		patchHelper:  helper,
+	}, nil
+}
+
+// NodeImageScope defines the basic context for an actuator to operate upon.
+type NodeImageScope struct {
+	Client      client.Client
+	patchHelper *patch.Helper
+
+	AzureClients
+	NodeImage *infrav1exp.AzureNodeImage
+}
+
+// SubscriptionID returns the subscription of the image template and the gallery.
+func (s *NodeImageScope) SubscriptionID() string {
+	return s.AzureClients.SubscriptionID()
+}
+
+// BaseURI returns the Azure ResourceManagerEndpoint.
+func (s *NodeImageScope) BaseURI() string {
+	return s.AzureClients.ResourceManagerEndpoint
+}
+
+// Authorizer returns the Azure client Authorizer.
+func (s *NodeImageScope) Authorizer() autorest.Authorizer {
+	return s.AzureClients.Authorizer
+}
+
+// ImageTemplateSpec returns the spec of the Azure Image Builder template building the node image.
+func (s *NodeImageScope) ImageTemplateSpec() azure.ResourceSpecGetter {
+	spec := s.NodeImage.Spec
+	return &imagebuilder.ImageTemplateSpec{
+		Name:                  azure.GenerateImageTemplateName(s.NodeImage.Namespace, s.NodeImage.Name),
+		ResourceGroup:         spec.ResourceGroup,
+		Location:              spec.Location,
+		SubscriptionID:        s.SubscriptionID(),
+		BuildIdentityID:       spec.BuildIdentityID,
+		Source:                spec.Source,
+		Customizations:        spec.Customizations,
+		Distribution:          spec.Distribution,
+		VMSize:                spec.VMSize,
+		OSDiskSizeGB:          spec.OSDiskSizeGB,
+		BuildTimeoutInMinutes: spec.BuildTimeoutInMinutes,
+		AdditionalTags:        spec.AdditionalTags,
+	}
+}
+
+// RunState returns the state of the build of the node image.
+func (s *NodeImageScope) RunState() string {
+	return s.NodeImage.Status.RunState
+}
+
+// SetRunStatus sets the state of the build of the node image, and reports it in the ImageBuilt condition.
+func (s *NodeImageScope) SetRunStatus(state, message string) {
+	s.NodeImage.Status.RunState = state
+	s.NodeImage.Status.RunMessage = message
+
+	switch virtualmachineimagebuilder.RunState(state) {
+	case virtualmachineimagebuilder.RunStateSucceeded:
+		conditions.MarkTrue(s.NodeImage, infrav1exp.ImageBuiltCondition)
+	case virtualmachineimagebuilder.RunStateRunning, virtualmachineimagebuilder.RunStateCanceling:
+		conditions.MarkFalse(s.NodeImage, infrav1exp.ImageBuiltCondition, infrav1exp.ImageBuildingReason, clusterv1.ConditionSeverityInfo, "%s", message)
+	default:
+		conditions.MarkFalse(s.NodeImage, infrav1exp.ImageBuiltCondition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "build %s: %s", state, message)
+	}
+}
+
+// SetImage sets the gallery image version the node image was published as.
+func (s *NodeImageScope) SetImage(image *infrav1.Image) {
+	s.NodeImage.Status.Image = image
+}
+
+// SetLongRunningOperationState will set the future on the AzureNodeImage status to allow the resource to continue
+// in the next reconciliation.
+func (s *NodeImageScope) SetLongRunningOperationState(future *infrav1.Future) {
+	futures.Set(s.NodeImage, future)
+}
+
+// GetLongRunningOperationState will get the future on the AzureNodeImage status.
+func (s *NodeImageScope) GetLongRunningOperationState(name, service string) *infrav1.Future {
+	return futures.Get(s.NodeImage, name, service)
+}
+
+// DeleteLongRunningOperationState will delete the future from the AzureNodeImage status.
+func (s *NodeImageScope) DeleteLongRunningOperationState(name, service string) {
+	futures.Delete(s.NodeImage, name, service)
+}
+
+// UpdateDeleteStatus updates a condition on the AzureNodeImage status after a DELETE operation.
+func (s *NodeImageScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
+	switch {
+	case err == nil:
+		conditions.MarkFalse(s.NodeImage, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
+	case azure.IsOperationNotDoneError(err):
+		conditions.MarkFalse(s.NodeImage, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting", service)
+	default:
+		conditions.MarkFalse(s.NodeImage, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
+	}
+}
+
+// UpdatePutStatus updates a condition on the AzureNodeImage status after a PUT operation.
+func (s *NodeImageScope) UpdatePutStatus(condition clusterv1.ConditionType, service string, err error) {
+	switch {
+	case err == nil:
+		conditions.MarkTrue(s.NodeImage, condition)
+	case azure.IsOperationNotDoneError(err):
+		conditions.MarkFalse(s.NodeImage, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating", service)
+	default:
+		conditions.MarkFalse(s.NodeImage, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
+	}
+}
+
+// UpdatePatchStatus updates a condition on the AzureNodeImage status after a PATCH operation.
+func (s *NodeImageScope) UpdatePatchStatus(condition clusterv1.ConditionType, service string, err error) {
+	switch {
+	case err == nil:
+		conditions.MarkTrue(s.NodeImage, condition)
+	case azure.IsOperationNotDoneError(err):
+		conditions.MarkFalse(s.NodeImage, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating", service)
+	default:
+		conditions.MarkFalse(s.NodeImage, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
+	}
+}
+
+// PatchObject persists the AzureNodeImage spec and status.
+func (s *NodeImageScope) PatchObject(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.NodeImageScope.PatchObject")
+	defer done()
+
+	conditions.SetSummary(s.NodeImage)
+
+	return s.patchHelper.Patch(
+		ctx,
+		s.NodeImage,
+		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.ReadyCondition,
+			infrav1exp.ImageBuiltCondition,
+		}})
+}
+
+// Close closes the current scope persisting the AzureNodeImage spec and status.
+func (s *NodeImageScope) Close(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.NodeImageScope.Close")
+	defer done()
+
+	return s.PatchObject(ctx)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagebuilder"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+)
+
+func TestNodeImageScope_ImageTemplateSpec(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &NodeImageScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		NodeImage: &infrav1exp.AzureNodeImage{
+			ObjectMeta: metav1.ObjectMeta{Name: "ubuntu", Namespace: "default"},
+			Spec: infrav1exp.AzureNodeImageSpec{
+				ResourceGroup:   "my-rg",
+				Location:        "westus2",
+				BuildIdentityID: "aib-identity",
+				Distribution: infrav1exp.NodeImageDistribution{
+					ResourceGroup: "gallery-rg",
+					Gallery:       "my_gallery",
+					Name:          "capi-ubuntu-2004",
+				},
+			},
+		},
+	}
+
+	g.Expect(s.ImageTemplateSpec()).To(Equal(&imagebuilder.ImageTemplateSpec{
+		Name:            "default-ubuntu",
+		ResourceGroup:   "my-rg",
+		Location:        "westus2",
+		SubscriptionID:  "123",
+		BuildIdentityID: "aib-identity",
+		Distribution: infrav1exp.NodeImageDistribution{
+			ResourceGroup: "gallery-rg",
+			Gallery:       "my_gallery",
+			Name:          "capi-ubuntu-2004",
+		},
+	}))
+}
+
+func TestNodeImageScope_SetRunStatus(t *testing.T) {
+	tests := []struct {
+		state          string
+		message        string
+		expectedStatus corev1.ConditionStatus
+		expectedReason string
+	}{
+		{state: "Running", message: "", expectedStatus: "False", expectedReason: infrav1exp.ImageBuildingReason},
+		{state: "Succeeded", message: "", expectedStatus: "True", expectedReason: ""},
+		{state: "Failed", message: "customization failed", expectedStatus: "False", expectedReason: "Failed"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.state, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &NodeImageScope{NodeImage: &infrav1exp.AzureNodeImage{}}
+
+			s.SetRunStatus(tc.state, tc.message)
+			g.Expect(s.RunState()).To(Equal(tc.state))
+			g.Expect(s.NodeImage.Status.RunMessage).To(Equal(tc.message))
+			condition := conditions.Get(s.NodeImage, infrav1exp.ImageBuiltCondition)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.expectedStatus))
+			g.Expect(condition.Reason).To(Equal(tc.expectedReason))
+			if tc.expectedReason == "Failed" {
+				g.Expect(condition.Severity).To(Equal(clusterv1.ConditionSeverityError))
+			}
+		})
+	}
+}
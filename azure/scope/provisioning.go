@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	milestoneNetworkReady            = "network_ready"
+	milestoneControlPlaneProvisioned = "control_plane_provisioned"
+	milestoneFirstNodeReady          = "first_node_ready"
+)
+
+var (
+	// provisioningBuckets range from 30 seconds to a bit over 4 hours.
+	provisioningBuckets = prometheus.ExponentialBuckets(30, 2, 10)
+
+	clusterProvisioningDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "capz_cluster_provisioning_duration_seconds",
+			Help:    "Duration from the creation of the clusters until they reached their provisioning milestones.",
+			Buckets: provisioningBuckets,
+		},
+		[]string{"milestone"},
+	)
+	clusterServiceProvisioningDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "capz_cluster_service_provisioning_duration_seconds",
+			Help:    "Duration from the creation of the clusters until their Azure services were first reconciled successfully.",
+			Buckets: provisioningBuckets,
+		},
+		[]string{"service"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(clusterProvisioningDuration, clusterServiceProvisioningDuration)
+}
+
+// provisioningDurations returns the provisioning durations of the cluster, initializing them if needed.
+func (s *ClusterScope) provisioningDurations() *infrav1.ProvisioningDurations {
+	if s.AzureCluster.Status.ProvisioningDurations == nil {
+		s.AzureCluster.Status.ProvisioningDurations = &infrav1.ProvisioningDurations{}
+	}
+	return s.AzureCluster.Status.ProvisioningDurations
+}
+
+// sinceCreation returns how long after the creation of the AzureCluster t is.
+func (s *ClusterScope) sinceCreation(t time.Time) metav1.Duration {
+	return metav1.Duration{Duration: t.Sub(s.AzureCluster.CreationTimestamp.Time).Round(time.Second)}
+}
+
+// SetServiceProvisioned records how long the given service took to be reconciled successfully for the first time.
+func (s *ClusterScope) SetServiceProvisioned(service string) {
+	durations := s.provisioningDurations()
+	if _, ok := durations.Services[service]; ok {
+		return
+	}
+	if durations.Services == nil {
+		durations.Services = map[string]metav1.Duration{}
+	}
+	duration := s.sinceCreation(time.Now())
+	durations.Services[service] = duration
+	clusterServiceProvisioningDuration.WithLabelValues(service).Observe(duration.Seconds())
+}
+
+// ServiceProvisioned returns true if the given service was already reconciled successfully.
+func (s *ClusterScope) ServiceProvisioned(service string) bool {
+	if s.AzureCluster.Status.ProvisioningDurations == nil {
+		return false
+	}
+	_, ok := s.AzureCluster.Status.ProvisioningDurations.Services[service]
+	return ok
+}
+
+// UpdateProvisioningDurations records the provisioning milestones the cluster reached since the last reconcile and
+// sets the ProvisioningSLOMet condition accordingly. The milestones are timed from the last transition time of their
+// conditions, so they are accurate even if they are recorded in a later reconcile.
+func (s *ClusterScope) UpdateProvisioningDurations(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ClusterScope.UpdateProvisioningDurations")
+	defer done()
+
+	durations := s.provisioningDurations()
+
+	if durations.NetworkReady == nil && conditions.IsTrue(s.AzureCluster, infrav1.NetworkInfrastructureReadyCondition) {
+		durations.NetworkReady = s.recordMilestone(milestoneNetworkReady, conditions.GetLastTransitionTime(s.AzureCluster, infrav1.NetworkInfrastructureReadyCondition))
+	}
+
+	if durations.ControlPlaneProvisioned == nil && conditions.IsTrue(s.Cluster, clusterv1.ControlPlaneInitializedCondition) {
+		durations.ControlPlaneProvisioned = s.recordMilestone(milestoneControlPlaneProvisioned, conditions.GetLastTransitionTime(s.Cluster, clusterv1.ControlPlaneInitializedCondition))
+	}
+
+	if durations.FirstNodeReady == nil {
+		firstNodeReady, err := s.firstNodeReadyTime(ctx)
+		if err != nil {
+			return err
+		}
+		if firstNodeReady != nil {
+			durations.FirstNodeReady = s.recordMilestone(milestoneFirstNodeReady, firstNodeReady)
+		}
+	}
+
+	s.setProvisioningSLOCondition()
+	return nil
+}
+
+// recordMilestone returns how long the cluster took to reach a milestone at the given time and observes it.
+func (s *ClusterScope) recordMilestone(milestone string, reachedAt *metav1.Time) *metav1.Duration {
+	if reachedAt == nil {
+		return nil
+	}
+	duration := s.sinceCreation(reachedAt.Time)
+	clusterProvisioningDuration.WithLabelValues(milestone).Observe(duration.Seconds())
+	return &duration
+}
+
+// firstNodeReadyTime returns when the first node of the cluster became healthy, or nil if none did yet.
+func (s *ClusterScope) firstNodeReadyTime(ctx context.Context) (*metav1.Time, error) {
+	machines := &clusterv1.MachineList{}
+	if err := s.Client.List(ctx, machines, client.InNamespace(s.Namespace()), client.MatchingLabels{clusterv1.ClusterLabelName: s.ClusterName()}); err != nil {
+		return nil, errors.Wrap(err, "failed to list the machines of the cluster")
+	}
+
+	var first *metav1.Time
+	for i := range machines.Items {
+		machine := &machines.Items[i]
+		if !conditions.IsTrue(machine, clusterv1.MachineNodeHealthyCondition) {
+			continue
+		}
+		if healthy := conditions.GetLastTransitionTime(machine, clusterv1.MachineNodeHealthyCondition); healthy != nil && (first == nil || healthy.Before(first)) {
+			first = healthy
+		}
+	}
+	return first, nil
+}
+
+// setProvisioningSLOCondition sets the ProvisioningSLOMet condition once it is known whether the first node of the
+// cluster became ready within the provisioning SLO, or removes it if there is no SLO.
+func (s *ClusterScope) setProvisioningSLOCondition() {
+	if s.provisioningSLO <= 0 {
+		conditions.Delete(s.AzureCluster, infrav1.ProvisioningSLOMetCondition)
+		return
+	}
+
+	if firstNodeReady := s.provisioningDurations().FirstNodeReady; firstNodeReady != nil {
+		if firstNodeReady.Duration <= s.provisioningSLO {
+			conditions.MarkTrue(s.AzureCluster, infrav1.ProvisioningSLOMetCondition)
+			return
+		}
+		conditions.MarkFalse(s.AzureCluster, infrav1.ProvisioningSLOMetCondition, infrav1.ProvisioningSLOExceededReason,
+			clusterv1.ConditionSeverityWarning, "the first node became ready after %s, exceeding the provisioning SLO of %s", firstNodeReady.Duration, s.provisioningSLO)
+		return
+	}
+
+	if elapsed := s.sinceCreation(time.Now()); elapsed.Duration > s.provisioningSLO {
+		conditions.MarkFalse(s.AzureCluster, infrav1.ProvisioningSLOMetCondition, infrav1.ProvisioningSLOExceededReason,
+			clusterv1.ConditionSeverityWarning, "no node became ready within the provisioning SLO of %s", s.provisioningSLO)
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestSetServiceProvisioned(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &ClusterScope{
+		AzureCluster: &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Minute))},
+		},
+	}
+
+	s.SetServiceProvisioned("groups")
+	first := s.AzureCluster.Status.ProvisioningDurations.Services["groups"]
+	g.Expect(first.Duration).To(BeNumerically("~", 5*time.Minute, time.Minute))
+
+	// Only the first successful reconcile of a service is recorded.
+	s.AzureCluster.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	s.SetServiceProvisioned("groups")
+	s.SetServiceProvisioned("virtualnetworks")
+	g.Expect(s.AzureCluster.Status.ProvisioningDurations.Services).To(Equal(map[string]metav1.Duration{
+		"groups":          first,
+		"virtualnetworks": s.AzureCluster.Status.ProvisioningDurations.Services["virtualnetworks"],
+	}))
+	g.Expect(s.AzureCluster.Status.ProvisioningDurations.Services["virtualnetworks"].Duration).To(BeNumerically("~", time.Hour, time.Minute))
+}
+
+func TestServiceProvisioned(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &ClusterScope{AzureCluster: &infrav1.AzureCluster{}}
+	g.Expect(s.ServiceProvisioned("loadbalancers")).To(BeFalse())
+
+	s.SetServiceProvisioned("loadbalancers")
+	g.Expect(s.ServiceProvisioned("loadbalancers")).To(BeTrue())
+	g.Expect(s.ServiceProvisioned("virtualnetworks")).To(BeFalse())
+}
+
+func TestUpdateProvisioningDurations(t *testing.T) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) metav1.Time {
+		return metav1.NewTime(created.Add(d))
+	}
+	machine := func(name string, healthy *metav1.Time) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+			},
+		}
+		if healthy != nil {
+			m.Status.Conditions = clusterv1.Conditions{{Type: clusterv1.MachineNodeHealthyCondition, Status: "True", LastTransitionTime: *healthy}}
+		}
+		return m
+	}
+	ptr := func(t metav1.Time) *metav1.Time {
+		return &t
+	}
+
+	tests := []struct {
+		name                 string
+		slo                  time.Duration
+		networkReady         *metav1.Time
+		controlPlaneReady    *metav1.Time
+		machines             []*clusterv1.Machine
+		existing             *infrav1.ProvisioningDurations
+		expected             *infrav1.ProvisioningDurations
+		expectedSLOCondition *clusterv1.Condition
+	}{
+		{
+			name:     "no milestone reached",
+			expected: &infrav1.ProvisioningDurations{},
+		},
+		{
+			name:              "all milestones reached",
+			networkReady:      ptr(at(2 * time.Minute)),
+			controlPlaneReady: ptr(at(6 * time.Minute)),
+			machines: []*clusterv1.Machine{
+				machine("not-healthy", nil),
+				machine("second", ptr(at(12*time.Minute))),
+				machine("first", ptr(at(9*time.Minute))),
+			},
+			expected: &infrav1.ProvisioningDurations{
+				NetworkReady:            &metav1.Duration{Duration: 2 * time.Minute},
+				ControlPlaneProvisioned: &metav1.Duration{Duration: 6 * time.Minute},
+				FirstNodeReady:          &metav1.Duration{Duration: 9 * time.Minute},
+			},
+		},
+		{
+			name:         "recorded milestones are kept",
+			networkReady: ptr(at(20 * time.Minute)),
+			existing: &infrav1.ProvisioningDurations{
+				NetworkReady: &metav1.Duration{Duration: 2 * time.Minute},
+			},
+			expected: &infrav1.ProvisioningDurations{
+				NetworkReady: &metav1.Duration{Duration: 2 * time.Minute},
+			},
+		},
+		{
+			name:         "machines of other clusters are ignored",
+			networkReady: ptr(at(2 * time.Minute)),
+			machines: []*clusterv1.Machine{
+				func() *clusterv1.Machine {
+					m := machine("other", ptr(at(3*time.Minute)))
+					m.Labels[clusterv1.ClusterLabelName] = "other-cluster"
+					return m
+				}(),
+			},
+			expected: &infrav1.ProvisioningDurations{
+				NetworkReady: &metav1.Duration{Duration: 2 * time.Minute},
+			},
+		},
+		{
+			name:     "SLO met",
+			slo:      10 * time.Minute,
+			machines: []*clusterv1.Machine{machine("first", ptr(at(9*time.Minute)))},
+			expected: &infrav1.ProvisioningDurations{
+				FirstNodeReady: &metav1.Duration{Duration: 9 * time.Minute},
+			},
+			expectedSLOCondition: conditions.TrueCondition(infrav1.ProvisioningSLOMetCondition),
+		},
+		{
+			name:     "SLO exceeded",
+			slo:      5 * time.Minute,
+			machines: []*clusterv1.Machine{machine("first", ptr(at(9*time.Minute)))},
+			expected: &infrav1.ProvisioningDurations{
+				FirstNodeReady: &metav1.Duration{Duration: 9 * time.Minute},
+			},
+			expectedSLOCondition: conditions.FalseCondition(infrav1.ProvisioningSLOMetCondition, infrav1.ProvisioningSLOExceededReason,
+				clusterv1.ConditionSeverityWarning, "the first node became ready after 9m0s, exceeding the provisioning SLO of 5m0s"),
+		},
+		{
+			name:     "SLO exceeded without ready node",
+			slo:      5 * time.Minute,
+			expected: &infrav1.ProvisioningDurations{},
+			expectedSLOCondition: conditions.FalseCondition(infrav1.ProvisioningSLOMetCondition, infrav1.ProvisioningSLOExceededReason,
+				clusterv1.ConditionSeverityWarning, "no node became ready within the provisioning SLO of 5m0s"),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			_ = clusterv1.AddToScheme(scheme)
+			_ = infrav1.AddToScheme(scheme)
+
+			initObjects := []runtime.Object{}
+			for _, m := range tc.machines {
+				initObjects = append(initObjects, m)
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+			}
+			if tc.controlPlaneReady != nil {
+				cluster.Status.Conditions = clusterv1.Conditions{{Type: clusterv1.ControlPlaneInitializedCondition, Status: "True", LastTransitionTime: *tc.controlPlaneReady}}
+			}
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+				Status:     infrav1.AzureClusterStatus{ProvisioningDurations: tc.existing},
+			}
+			if tc.networkReady != nil {
+				azureCluster.Status.Conditions = clusterv1.Conditions{{Type: infrav1.NetworkInfrastructureReadyCondition, Status: "True", LastTransitionTime: *tc.networkReady}}
+			}
+
+			s := &ClusterScope{
+				Client:          fakeClient,
+				Cluster:         cluster,
+				AzureCluster:    azureCluster,
+				provisioningSLO: tc.slo,
+			}
+			g.Expect(s.UpdateProvisioningDurations(context.TODO())).To(Succeed())
+			g.Expect(azureCluster.Status.ProvisioningDurations).To(Equal(tc.expected))
+
+			condition := conditions.Get(azureCluster, infrav1.ProvisioningSLOMetCondition)
+			if tc.expectedSLOCondition == nil {
+				g.Expect(condition).To(BeNil())
+			} else {
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Status).To(Equal(tc.expectedSLOCondition.Status))
+				g.Expect(condition.Reason).To(Equal(tc.expectedSLOCondition.Reason))
+				g.Expect(condition.Message).To(Equal(tc.expectedSLOCondition.Message))
+			}
+		})
+	}
+}
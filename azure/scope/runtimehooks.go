@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/runtimehooks"
+)
+
+// CallRuntimeHook calls a hook of the runtime extension for the cluster. It is a no-op if no runtime extension is
+// configured.
+func (s *ClusterScope) CallRuntimeHook(ctx context.Context, hook runtimehooks.Hook) error {
+	if s.runtimeHooks == nil {
+		return nil
+	}
+	req := runtimehooks.Request{
+		Hook:    hook,
+		Cluster: runtimehooks.ObjectReference{Namespace: s.Namespace(), Name: s.ClusterName()},
+	}
+	if hook == runtimehooks.AfterControlPlaneLBReady {
+		req.ControlPlaneEndpoint = &clusterv1.APIEndpoint{Host: s.APIServerHost(), Port: s.APIServerPort()}
+	}
+	return s.runtimeHooks.Call(ctx, req)
+}
+
+// CallRuntimeHook calls a hook of the runtime extension for the machine. It is a no-op if no runtime extension is
+// configured.
+func (m *MachineScope) CallRuntimeHook(ctx context.Context, hook runtimehooks.Hook) error {
+	if m.runtimeHooks == nil {
+		return nil
+	}
+	return m.runtimeHooks.Call(ctx, runtimehooks.Request{
+		Hook:       hook,
+		Cluster:    runtimehooks.ObjectReference{Namespace: m.Namespace(), Name: m.ClusterName()},
+		Machine:    &runtimehooks.ObjectReference{Namespace: m.Namespace(), Name: m.Name()},
+		ProviderID: m.ProviderID(),
+	})
+}
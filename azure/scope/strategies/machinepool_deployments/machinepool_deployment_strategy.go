@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -102,6 +103,27 @@ func (rollingUpdateStrategy *rollingUpdateStrategy) maxUnavailable(desiredReplic
 	return 0, nil
 }
 
+// settleTimeRemaining calculates how long to wait before deleting the next batch of machines without the latest model.
+// The BatchSettleTime starts when the newest ready machine with the latest model was created.
+func (rollingUpdateStrategy *rollingUpdateStrategy) settleTimeRemaining(readyMachines []infrav1exp.AzureMachinePoolMachine) time.Duration {
+	if rollingUpdateStrategy.BatchSettleTime == nil || rollingUpdateStrategy.BatchSettleTime.Duration <= 0 {
+		return 0
+	}
+
+	var newest time.Time
+	for _, v := range readyMachines {
+		if v.Status.LatestModelApplied && v.ObjectMeta.CreationTimestamp.After(newest) {
+			newest = v.ObjectMeta.CreationTimestamp.Time
+		}
+	}
+
+	if newest.IsZero() {
+		return 0
+	}
+
+	return time.Until(newest.Add(rollingUpdateStrategy.BatchSettleTime.Duration))
+}
+
 // SelectMachinesToDelete selects the machines to delete based on the machine state, desired replica count, and
 // the DeletePolicy.
 func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx context.Context, desiredReplicaCount int32, machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) ([]infrav1exp.AzureMachinePoolMachine, error) {
@@ -169,8 +191,32 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 		return []infrav1exp.AzureMachinePoolMachine{}, nil
 	}
 
+	// give the latest batch of machines time to settle before removing more machines without the latest model, and
+	// requeue once it has
+	var settleRemaining time.Duration
+	if len(machinesWithoutLatestModel) > 0 {
+		settleRemaining = rollingUpdateStrategy.settleTimeRemaining(readyMachines)
+	}
+	errSettling := func() error {
+		return azure.WithTransientError(errors.New("waiting for the latest batch of machines to settle before deleting machines without the latest model"), settleRemaining)
+	}
+
 	// we have too many machines, let's choose the oldest to remove
 	if overProvisionCount > 0 {
+		// while the latest batch settles, only the machines beyond the surge of the rollout are removed, as they are
+		// removed by a scale down rather than replaced
+		if settleRemaining > 0 {
+			surge, err := rollingUpdateStrategy.Surge(int(desiredReplicaCount))
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to calculate surge")
+			}
+			log.Info("waiting for the latest batch to settle", "remaining", settleRemaining.String(), "overProvisionCount", overProvisionCount, "surge", surge, "machinesWithoutLatestModel", getProviderIDs(machinesWithoutLatestModel))
+			overProvisionCount -= surge
+			if overProvisionCount <= 0 {
+				return nil, errSettling()
+			}
+		}
+
 		var toDelete []infrav1exp.AzureMachinePoolMachine
 		log.Info("over-provisioned", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "machinesWithoutLatestModel", getProviderIDs(machinesWithoutLatestModel))
 		// we are over-provisioned try to remove old models
@@ -179,7 +225,9 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 				return toDelete, nil
 			}
 
-			toDelete = append(toDelete, v)
+			if !isProtected(v) {
+				toDelete = append(toDelete, v)
+			}
 		}
 
 		log.Info("over-provisioned ready", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "readyMachines", getProviderIDs(readyMachines))
@@ -189,7 +237,9 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 				return toDelete, nil
 			}
 
-			toDelete = append(toDelete, v)
+			if !isProtected(v) {
+				toDelete = append(toDelete, v)
+			}
 		}
 
 		return toDelete, nil
@@ -200,6 +250,11 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 		return []infrav1exp.AzureMachinePoolMachine{}, nil
 	}
 
+	if settleRemaining > 0 {
+		log.Info("waiting for the latest batch to settle", "remaining", settleRemaining.String(), "machinesWithoutLatestModel", getProviderIDs(machinesWithoutLatestModel))
+		return nil, errSettling()
+	}
+
 	if disruptionBudget <= 0 {
 		log.Info("exit early since disruption budget is less than or equal to zero", "disruptionBudget", disruptionBudget, "desiredReplicaCount", desiredReplicaCount, "maxUnavailable", maxUnavailable, "readyMachines", getProviderIDs(readyMachines), "readyMachinesCount", len(readyMachines))
 		return []infrav1exp.AzureMachinePoolMachine{}, nil
@@ -212,7 +267,7 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 			return toDelete, nil
 		}
 
-		if !v.Status.LatestModelApplied {
+		if !v.Status.LatestModelApplied && !isProtected(v) {
 			toDelete = append(toDelete, v)
 		}
 	}
@@ -268,6 +323,13 @@ func getMachinesWithoutLatestModel(machinesByProviderID map[string]infrav1exp.Az
 	return machinesWithLatestModel
 }
 
+// isProtected returns whether the instance of the machine is protected, in which case it is never deleted to scale in or
+// to roll out the latest model.
+func isProtected(machine infrav1exp.AzureMachinePoolMachine) bool {
+	_, ok := machine.Annotations[infrav1exp.InstanceProtectionAnnotation]
+	return ok
+}
+
 func orderByNewest(machines []infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
 	sort.Slice(machines, func(i, j int) bool {
 		return machines[i].ObjectMeta.CreationTimestamp.After(machines[j].ObjectMeta.CreationTimestamp.Time)
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// tokenRefreshWithin is how long before their expiry the cached tokens are refreshed when they are used.
+	tokenRefreshWithin = 10 * time.Minute
+	// tokenRefreshInterval is how often the cached tokens are checked for a proactive refresh.
+	tokenRefreshInterval = time.Minute
+	// tokenIdleTimeout is how long a cached token is kept without being used.
+	tokenIdleTimeout = time.Hour
+)
+
+var (
+	tokenRefreshDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "capz_identity_token_refresh_duration_seconds",
+			Help: "Duration of the token requests of the identities.",
+		},
+		[]string{"identity", "result"},
+	)
+	tokenRefreshFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "capz_identity_token_refresh_failures_total",
+			Help: "Number of failed token requests of the identities.",
+		},
+		[]string{"identity"},
+	)
+
+	// identityTokens caches the tokens of the identities across the reconciles of all the clusters.
+	identityTokens = newTokenCache(nil, time.Now)
+)
+
+func init() {
+	metrics.Registry.MustRegister(tokenRefreshDuration, tokenRefreshFailures)
+}
+
+// tokenCache shares a single token per identity, so that a new token is only requested when the cached one is about
+// to expire rather than on every reconcile.
+type tokenCache struct {
+	sync.Mutex
+	// sender sends the token requests, the default adal sender is used if nil.
+	sender adal.Sender
+	now    func() time.Time
+	tokens map[string]*cachedToken
+}
+
+// cachedToken is a token of the cache.
+type cachedToken struct {
+	*adal.ServicePrincipalToken
+	identity string
+	lastUsed time.Time
+}
+
+func newTokenCache(sender adal.Sender, now func() time.Time) *tokenCache {
+	return &tokenCache{
+		sender: sender,
+		now:    now,
+		tokens: make(map[string]*cachedToken),
+	}
+}
+
+// getOrCreate returns the cached token for the key, or caches the token created by newToken.
+func (c *tokenCache) getOrCreate(key, identity string, newToken func() (*adal.ServicePrincipalToken, error)) (*adal.ServicePrincipalToken, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if t, ok := c.tokens[key]; ok {
+		t.lastUsed = c.now()
+		return t.ServicePrincipalToken, nil
+	}
+
+	spt, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	spt.SetRefreshWithin(tokenRefreshWithin)
+	sender := c.sender
+	if sender == nil {
+		// The default sender can't be created at init, as it depends on whether tracing is enabled.
+		sender = adal.CreateSender()
+	}
+	spt.SetSender(adal.DecorateSender(sender, withRefreshMetrics(identity)))
+	c.tokens[key] = &cachedToken{
+		ServicePrincipalToken: spt,
+		identity:              identity,
+		lastUsed:              c.now(),
+	}
+	return spt, nil
+}
+
+// refreshExpiring refreshes the tokens expiring before the next check, and evicts the tokens which were not used for a while.
+func (c *tokenCache) refreshExpiring(ctx context.Context) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.tokenCache.refreshExpiring")
+	defer done()
+
+	var expiring []*cachedToken
+	c.Lock()
+	for key, t := range c.tokens {
+		if c.now().Sub(t.lastUsed) > tokenIdleTimeout {
+			delete(c.tokens, key)
+			continue
+		}
+		if t.Token().WillExpireIn(tokenRefreshWithin + tokenRefreshInterval) {
+			expiring = append(expiring, t)
+		}
+	}
+	c.Unlock()
+
+	// Refresh outside of the lock so that reconciles can still get the other tokens.
+	for _, t := range expiring {
+		if err := t.RefreshWithContext(ctx); err != nil {
+			log.Error(err, "failed to refresh identity token", "identity", t.identity)
+		}
+	}
+}
+
+// withRefreshMetrics records the duration and the failures of the token requests of an identity.
+func withRefreshMetrics(identity string) adal.SendDecorator {
+	return func(s adal.Sender) adal.Sender {
+		return adal.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := s.Do(r)
+
+			result := "success"
+			if err != nil || resp.StatusCode >= http.StatusBadRequest {
+				result = "failure"
+				tokenRefreshFailures.WithLabelValues(identity).Inc()
+			}
+			tokenRefreshDuration.WithLabelValues(identity, result).Observe(time.Since(start).Seconds())
+			return resp, err
+		})
+	}
+}
+
+// RefreshIdentityTokens refreshes the cached identity tokens ahead of their expiry until the context is done, so that
+// reconciles don't wait for a token request.
+func RefreshIdentityTokens(ctx context.Context) error {
+	wait.UntilWithContext(ctx, identityTokens.refreshExpiring, tokenRefreshInterval)
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/adal"
+	. "github.com/onsi/gomega"
+)
+
+func newTestToken() (*adal.ServicePrincipalToken, error) {
+	oauthConfig, err := adal.NewOAuthConfig("https://login.microsoftonline.com/", "my-tenant")
+	if err != nil {
+		return nil, err
+	}
+	return adal.NewServicePrincipalToken(*oauthConfig, "my-client", "my-secret", "https://management.azure.com/")
+}
+
+// tokenSender answers the token requests with tokens expiring in an hour and counts them.
+func tokenSender(requests *int) adal.Sender {
+	return adal.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		*requests++
+		body := fmt.Sprintf(`{"access_token":"token-%d","expires_in":"3600","expires_on":"%d","token_type":"Bearer"}`,
+			*requests, time.Now().Add(time.Hour).Unix())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Request:    r,
+		}, nil
+	})
+}
+
+func TestTokenCacheGetOrCreate(t *testing.T) {
+	g := NewWithT(t)
+
+	var created int
+	newToken := func() (*adal.ServicePrincipalToken, error) {
+		created++
+		return newTestToken()
+	}
+	cache := newTokenCache(tokenSender(new(int)), time.Now)
+
+	first, err := cache.getOrCreate("key", "default/my-identity", newToken)
+	g.Expect(err).NotTo(HaveOccurred())
+	second, err := cache.getOrCreate("key", "default/my-identity", newToken)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(second).To(BeIdenticalTo(first))
+	g.Expect(created).To(Equal(1))
+
+	other, err := cache.getOrCreate("other-key", "default/my-identity", newToken)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(other).NotTo(BeIdenticalTo(first))
+	g.Expect(created).To(Equal(2))
+
+	_, err = cache.getOrCreate("failing-key", "default/my-identity", func() (*adal.ServicePrincipalToken, error) {
+		return nil, fmt.Errorf("no token")
+	})
+	g.Expect(err).To(MatchError("no token"))
+	g.Expect(cache.tokens).NotTo(HaveKey("failing-key"))
+}
+
+func TestTokenCacheRefreshExpiring(t *testing.T) {
+	g := NewWithT(t)
+
+	var requests int
+	now := time.Now()
+	cache := newTokenCache(tokenSender(&requests), func() time.Time { return now })
+
+	spt, err := cache.getOrCreate("key", "default/my-identity", newTestToken)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The token was never requested, so it is refreshed ahead of its first use.
+	cache.refreshExpiring(context.TODO())
+	g.Expect(requests).To(Equal(1))
+	g.Expect(spt.OAuthToken()).To(Equal("token-1"))
+
+	// A fresh token is not refreshed again.
+	cache.refreshExpiring(context.TODO())
+	g.Expect(requests).To(Equal(1))
+
+	// The token is evicted once it was not used for a while.
+	now = now.Add(tokenIdleTimeout + time.Minute)
+	cache.refreshExpiring(context.TODO())
+	g.Expect(requests).To(Equal(1))
+	g.Expect(cache.tokens).To(BeEmpty())
+}
@@ -19,6 +19,7 @@ package agentpools
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
@@ -30,6 +31,13 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+const (
+	// azureSystemNodeLabelPrefix is the prefix of the node labels set by AKS.
+	azureSystemNodeLabelPrefix = "kubernetes.azure.com/"
+	// spotNodeTaint is the taint AKS adds to the nodes of Spot agent pools.
+	spotNodeTaint = "kubernetes.azure.com/scalesetpriority=spot:NoSchedule"
+)
+
 // ManagedMachinePoolScope defines the scope interface for a managed machine pool.
 type ManagedMachinePoolScope interface {
 	azure.ClusterDescriber
@@ -67,20 +75,27 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	profile := containerservice.AgentPool{
 		ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
-			VMSize:              &agentPoolSpec.SKU,
-			OsType:              containerservice.OSTypeLinux,
-			OsDiskSizeGB:        &agentPoolSpec.OSDiskSizeGB,
-			Count:               &agentPoolSpec.Replicas,
-			Type:                containerservice.AgentPoolTypeVirtualMachineScaleSets,
-			OrchestratorVersion: agentPoolSpec.Version,
-			VnetSubnetID:        &agentPoolSpec.VnetSubnetID,
-			Mode:                containerservice.AgentPoolMode(agentPoolSpec.Mode),
-			EnableAutoScaling:   agentPoolSpec.EnableAutoScaling,
-			MaxCount:            agentPoolSpec.MaxCount,
-			MinCount:            agentPoolSpec.MinCount,
-			AvailabilityZones:   &agentPoolSpec.AvailabilityZones,
+			VMSize:                 &agentPoolSpec.SKU,
+			OsType:                 containerservice.OSTypeLinux,
+			OsDiskSizeGB:           &agentPoolSpec.OSDiskSizeGB,
+			Count:                  &agentPoolSpec.Replicas,
+			Type:                   containerservice.AgentPoolTypeVirtualMachineScaleSets,
+			OrchestratorVersion:    agentPoolSpec.Version,
+			VnetSubnetID:           &agentPoolSpec.VnetSubnetID,
+			Mode:                   containerservice.AgentPoolMode(agentPoolSpec.Mode),
+			EnableAutoScaling:      agentPoolSpec.EnableAutoScaling,
+			MaxCount:               agentPoolSpec.MaxCount,
+			MinCount:               agentPoolSpec.MinCount,
+			AvailabilityZones:      &agentPoolSpec.AvailabilityZones,
+			NodeLabels:             agentPoolSpec.NodeLabels,
+			ScaleSetPriority:       containerservice.ScaleSetPriority(agentPoolSpec.ScaleSetPriority),
+			ScaleSetEvictionPolicy: containerservice.ScaleSetEvictionPolicy(agentPoolSpec.ScaleSetEvictionPolicy),
+			SpotMaxPrice:           agentPoolSpec.SpotMaxPrice,
 		},
 	}
+	if nodeTaints := nodeTaints(agentPoolSpec); len(nodeTaints) > 0 {
+		profile.NodeTaints = &nodeTaints
+	}
 
 	existingPool, err := s.Client.Get(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name)
 	if err != nil && !azure.ResourceNotFound(err) {
@@ -107,6 +122,9 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			return azure.WithTransientError(errors.New(msg), 20*time.Second)
 		}
 
+		// Keep the labels AKS sets on the nodes, such as the priority of Spot node pools, which can't be removed.
+		profile.NodeLabels = mergeSystemNodeLabels(profile.NodeLabels, existingPool.NodeLabels)
+
 		// Normalize individual agent pools to diff in case we need to update
 		existingProfile := containerservice.AgentPool{
 			ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
@@ -116,8 +134,13 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				EnableAutoScaling:   existingPool.EnableAutoScaling,
 				MinCount:            existingPool.MinCount,
 				MaxCount:            existingPool.MaxCount,
+				NodeLabels:          existingPool.NodeLabels,
 			},
 		}
+		// AKS returns an empty map for agent pools without labels.
+		if len(existingProfile.NodeLabels) == 0 {
+			existingProfile.NodeLabels = nil
+		}
 
 		normalizedProfile := containerservice.AgentPool{
 			ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
@@ -127,8 +150,12 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				EnableAutoScaling:   profile.EnableAutoScaling,
 				MinCount:            profile.MinCount,
 				MaxCount:            profile.MaxCount,
+				NodeLabels:          profile.NodeLabels,
 			},
 		}
+		if len(normalizedProfile.NodeLabels) == 0 {
+			normalizedProfile.NodeLabels = nil
+		}
 
 		// Diff and check if we require an update
 		diff := cmp.Diff(normalizedProfile, existingProfile)
@@ -143,9 +170,64 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 	}
 
+	if agentPoolSpec.EnableArtifactStreaming != nil {
+		if err := s.reconcileArtifactStreaming(ctx, agentPoolSpec); err != nil {
+			return errors.Wrap(err, "failed to reconcile artifact streaming")
+		}
+	}
+
 	return nil
 }
 
+// nodeTaints returns the taints of the nodes of an agent pool, including the taint AKS adds to the nodes of Spot agent
+// pools, which can't be removed.
+func nodeTaints(agentPoolSpec azure.AgentPoolSpec) []string {
+	if agentPoolSpec.ScaleSetPriority != string(containerservice.ScaleSetPrioritySpot) {
+		return agentPoolSpec.NodeTaints
+	}
+	for _, taint := range agentPoolSpec.NodeTaints {
+		if taint == spotNodeTaint {
+			return agentPoolSpec.NodeTaints
+		}
+	}
+	return append(append([]string{}, agentPoolSpec.NodeTaints...), spotNodeTaint)
+}
+
+// mergeSystemNodeLabels adds the labels prefixed with kubernetes.azure.com, which are set by AKS, of the existing
+// agent pool to the desired labels.
+func mergeSystemNodeLabels(desired, existing map[string]*string) map[string]*string {
+	merged := map[string]*string{}
+	for key, value := range existing {
+		if strings.HasPrefix(key, azureSystemNodeLabelPrefix) {
+			merged[key] = value
+		}
+	}
+	for key, value := range desired {
+		merged[key] = value
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// reconcileArtifactStreaming enables or disables artifact streaming in place on the agent pool.
+func (s *Service) reconcileArtifactStreaming(ctx context.Context, agentPoolSpec azure.AgentPoolSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "agentpools.Service.reconcileArtifactStreaming")
+	defer done()
+
+	enabled, err := s.Client.GetArtifactStreaming(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name)
+	if err != nil {
+		return errors.Wrap(err, "failed to get artifact streaming")
+	}
+	if enabled == *agentPoolSpec.EnableArtifactStreaming {
+		return nil
+	}
+
+	log.V(2).Info("updating artifact streaming", "agentPool", agentPoolSpec.Name, "enabled", *agentPoolSpec.EnableArtifactStreaming)
+	return s.Client.SetArtifactStreaming(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name, *agentPoolSpec.EnableArtifactStreaming)
+}
+
 // Delete deletes the virtual network with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(
@@ -19,6 +19,7 @@ package agentpools
 import (
 	"context"
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
@@ -26,6 +27,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
@@ -132,6 +134,7 @@ func TestReconcile(t *testing.T) {
 	testcases := []struct {
 		name           string
 		agentPoolsSpec azure.AgentPoolSpec
+		taints         []infraexpv1.Taint
 		expectedError  string
 		expect         func(m *mock_agentpools.MockClientMockRecorder)
 	}{
@@ -249,6 +252,236 @@ func TestReconcile(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			name: "can enable artifact streaming on an Agent Pool",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:                    "my-agent-pool",
+				ResourceGroup:           "my-rg",
+				Cluster:                 "my-cluster",
+				SKU:                     "Standard_D2s_v3",
+				Version:                 to.StringPtr("9.99.9999"),
+				Replicas:                2,
+				OSDiskSizeGB:            100,
+				EnableArtifactStreaming: to.BoolPtr(true),
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Int32Ptr(2),
+						OsDiskSizeGB:        to.Int32Ptr(100),
+						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:              containerservice.OSTypeLinux,
+						OrchestratorVersion: to.StringPtr("9.99.9999"),
+						ProvisioningState:   to.StringPtr("Succeeded"),
+						VnetSubnetID:        to.StringPtr(""),
+					},
+				}, nil)
+				m.GetArtifactStreaming(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(false, nil)
+				m.SetArtifactStreaming(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", true).Return(nil)
+			},
+		},
+		{
+			name: "artifact streaming already enabled on an Agent Pool",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:                    "my-agent-pool",
+				ResourceGroup:           "my-rg",
+				Cluster:                 "my-cluster",
+				SKU:                     "Standard_D2s_v3",
+				Version:                 to.StringPtr("9.99.9999"),
+				Replicas:                2,
+				OSDiskSizeGB:            100,
+				EnableArtifactStreaming: to.BoolPtr(true),
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Int32Ptr(2),
+						OsDiskSizeGB:        to.Int32Ptr(100),
+						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:              containerservice.OSTypeLinux,
+						OrchestratorVersion: to.StringPtr("9.99.9999"),
+						ProvisioningState:   to.StringPtr("Succeeded"),
+						VnetSubnetID:        to.StringPtr(""),
+					},
+				}, nil)
+				m.GetArtifactStreaming(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(true, nil)
+			},
+		},
+		{
+			name: "fail to enable artifact streaming on an Agent Pool",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:                    "my-agent-pool",
+				ResourceGroup:           "my-rg",
+				Cluster:                 "my-cluster",
+				SKU:                     "Standard_D2s_v3",
+				Version:                 to.StringPtr("9.99.9999"),
+				Replicas:                2,
+				OSDiskSizeGB:            100,
+				EnableArtifactStreaming: to.BoolPtr(true),
+			},
+			expectedError: "failed to reconcile artifact streaming: #: Bad Request: StatusCode=400",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Int32Ptr(2),
+						OsDiskSizeGB:        to.Int32Ptr(100),
+						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:              containerservice.OSTypeLinux,
+						OrchestratorVersion: to.StringPtr("9.99.9999"),
+						ProvisioningState:   to.StringPtr("Succeeded"),
+						VnetSubnetID:        to.StringPtr(""),
+					},
+				}, nil)
+				m.GetArtifactStreaming(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(false, nil)
+				m.SetArtifactStreaming(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", true).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 400}, "Bad Request"))
+			},
+		},
+		{
+			name: "no update needed on Agent Pool with the same node labels",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.StringPtr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+				NodeLabels:    map[string]*string{"workload": to.StringPtr("batch")},
+			},
+			taints:        []infraexpv1.Taint{{Key: "dedicated", Value: "batch", Effect: infraexpv1.TaintEffectNoSchedule}},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Int32Ptr(2),
+						OsDiskSizeGB:        to.Int32Ptr(100),
+						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:              containerservice.OSTypeLinux,
+						OrchestratorVersion: to.StringPtr("9.99.9999"),
+						ProvisioningState:   to.StringPtr("Succeeded"),
+						VnetSubnetID:        to.StringPtr(""),
+						NodeLabels:          map[string]*string{"workload": to.StringPtr("batch")},
+						NodeTaints:          &[]string{"dedicated=batch:NoSchedule"},
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "can update node labels of an Agent Pool in place",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.StringPtr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+				NodeLabels:    map[string]*string{"workload": to.StringPtr("batch")},
+			},
+			taints:        []infraexpv1.Taint{{Key: "dedicated", Value: "batch", Effect: infraexpv1.TaintEffectNoSchedule}},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Int32Ptr(2),
+						OsDiskSizeGB:        to.Int32Ptr(100),
+						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:              containerservice.OSTypeLinux,
+						OrchestratorVersion: to.StringPtr("9.99.9999"),
+						ProvisioningState:   to.StringPtr("Succeeded"),
+						VnetSubnetID:        to.StringPtr(""),
+						NodeLabels:          map[string]*string{"workload": to.StringPtr("web")},
+						NodeTaints:          &[]string{"dedicated=batch:NoSchedule"},
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).DoAndReturn(
+					func(_ context.Context, _, _, _ string, agentPool containerservice.AgentPool) error {
+						if !reflect.DeepEqual(agentPool.NodeLabels, map[string]*string{"workload": to.StringPtr("batch")}) {
+							return errors.Errorf("unexpected node labels %v", agentPool.NodeLabels)
+						}
+						if !reflect.DeepEqual(agentPool.NodeTaints, &[]string{"dedicated=batch:NoSchedule"}) {
+							return errors.Errorf("unexpected node taints %v", agentPool.NodeTaints)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name: "no update needed on Spot Agent Pool with the labels and taint set by AKS",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:             "my-agent-pool",
+				ResourceGroup:    "my-rg",
+				Cluster:          "my-cluster",
+				SKU:              "Standard_D2s_v3",
+				Version:          to.StringPtr("9.99.9999"),
+				Replicas:         2,
+				OSDiskSizeGB:     100,
+				ScaleSetPriority: "Spot",
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Int32Ptr(2),
+						OsDiskSizeGB:        to.Int32Ptr(100),
+						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:              containerservice.OSTypeLinux,
+						OrchestratorVersion: to.StringPtr("9.99.9999"),
+						ProvisioningState:   to.StringPtr("Succeeded"),
+						VnetSubnetID:        to.StringPtr(""),
+						NodeLabels:          map[string]*string{"kubernetes.azure.com/scalesetpriority": to.StringPtr("spot")},
+						NodeTaints:          &[]string{"kubernetes.azure.com/scalesetpriority=spot:NoSchedule"},
+						ScaleSetPriority:    containerservice.ScaleSetPrioritySpot,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "can update node labels of a Spot Agent Pool keeping the labels and taint set by AKS",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:             "my-agent-pool",
+				ResourceGroup:    "my-rg",
+				Cluster:          "my-cluster",
+				SKU:              "Standard_D2s_v3",
+				Version:          to.StringPtr("9.99.9999"),
+				Replicas:         2,
+				OSDiskSizeGB:     100,
+				NodeLabels:       map[string]*string{"workload": to.StringPtr("batch")},
+				ScaleSetPriority: "Spot",
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Int32Ptr(2),
+						OsDiskSizeGB:        to.Int32Ptr(100),
+						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:              containerservice.OSTypeLinux,
+						OrchestratorVersion: to.StringPtr("9.99.9999"),
+						ProvisioningState:   to.StringPtr("Succeeded"),
+						VnetSubnetID:        to.StringPtr(""),
+						NodeLabels:          map[string]*string{"kubernetes.azure.com/scalesetpriority": to.StringPtr("spot")},
+						NodeTaints:          &[]string{"kubernetes.azure.com/scalesetpriority=spot:NoSchedule"},
+						ScaleSetPriority:    containerservice.ScaleSetPrioritySpot,
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).DoAndReturn(
+					func(_ context.Context, _, _, _ string, agentPool containerservice.AgentPool) error {
+						if !reflect.DeepEqual(agentPool.NodeLabels, map[string]*string{"workload": to.StringPtr("batch"), "kubernetes.azure.com/scalesetpriority": to.StringPtr("spot")}) {
+							return errors.Errorf("unexpected node labels %v", agentPool.NodeLabels)
+						}
+						if !reflect.DeepEqual(agentPool.NodeTaints, &[]string{"kubernetes.azure.com/scalesetpriority=spot:NoSchedule"}) {
+							return errors.Errorf("unexpected node taints %v", agentPool.NodeTaints)
+						}
+						if agentPool.ScaleSetPriority != containerservice.ScaleSetPrioritySpot {
+							return errors.Errorf("unexpected scale set priority %s", agentPool.ScaleSetPriority)
+						}
+						return nil
+					})
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -262,6 +495,15 @@ func TestReconcile(t *testing.T) {
 
 			replicas := tc.agentPoolsSpec.Replicas
 			osDiskSizeGB := tc.agentPoolsSpec.OSDiskSizeGB
+			nodeLabels := map[string]string{}
+			for key, value := range tc.agentPoolsSpec.NodeLabels {
+				nodeLabels[key] = *value
+			}
+			var scaleSetPriority *infraexpv1.ScaleSetPriority
+			if tc.agentPoolsSpec.ScaleSetPriority != "" {
+				priority := infraexpv1.ScaleSetPriority(tc.agentPoolsSpec.ScaleSetPriority)
+				scaleSetPriority = &priority
+			}
 
 			agentpoolsMock := mock_agentpools.NewMockClient(mockCtrl)
 			machinePoolScope := &scope.ManagedControlPlaneScope{
@@ -288,9 +530,13 @@ func TestReconcile(t *testing.T) {
 						Name: tc.agentPoolsSpec.Name,
 					},
 					Spec: infraexpv1.AzureManagedMachinePoolSpec{
-						Name:         &tc.agentPoolsSpec.Name,
-						SKU:          tc.agentPoolsSpec.SKU,
-						OSDiskSizeGB: &osDiskSizeGB,
+						Name:                    &tc.agentPoolsSpec.Name,
+						SKU:                     tc.agentPoolsSpec.SKU,
+						OSDiskSizeGB:            &osDiskSizeGB,
+						EnableArtifactStreaming: tc.agentPoolsSpec.EnableArtifactStreaming,
+						NodeLabels:              nodeLabels,
+						Taints:                  tc.taints,
+						ScaleSetPriority:        scaleSetPriority,
 					},
 				},
 			}
@@ -18,20 +18,29 @@ package agentpools
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/pkg/errors"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// artifactStreamingAPIVersion is the first AKS API version exposing the artifact streaming of agent pools, which is not
+// part of the containerservice SDK package used by the rest of the provider.
+const artifactStreamingAPIVersion = "2023-08-02-preview"
+
 // Client wraps go-sdk.
 type Client interface {
 	Get(context.Context, string, string, string) (containerservice.AgentPool, error)
 	CreateOrUpdate(context.Context, string, string, string, containerservice.AgentPool) error
 	Delete(context.Context, string, string, string) error
+	GetArtifactStreaming(context.Context, string, string, string) (bool, error)
+	SetArtifactStreaming(context.Context, string, string, string, bool) error
 }
 
 // AzureClient contains the Azure go-sdk Client.
@@ -50,22 +59,24 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newAgentPoolsClient creates a new agent pool client from subscription ID.
 func newAgentPoolsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) containerservice.AgentPoolsClient {
 	agentPoolsClient := containerservice.NewAgentPoolsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&agentPoolsClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&agentPoolsClient.Client, authorizer, "agentpools")
 	return agentPoolsClient
 }
 
 // Get gets an agent pool.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, cluster, name string) (containerservice.AgentPool, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, cluster, name string) (_ containerservice.AgentPool, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "agentpools.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.agentpools.Get(ctx, resourceGroupName, cluster, name)
 }
 
 // CreateOrUpdate creates or updates an agent pool.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, cluster, name string, properties containerservice.AgentPool) error {
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, cluster, name string, properties containerservice.AgentPool) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "agentpools.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.agentpools.CreateOrUpdate(ctx, resourceGroupName, cluster, name, properties)
 	if err != nil {
@@ -79,9 +90,10 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, cl
 }
 
 // Delete deletes an agent pool.
-func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, cluster, name string) error {
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, cluster, name string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "agentpools.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.agentpools.Delete(ctx, resourceGroupName, cluster, name)
 	if err != nil {
@@ -93,3 +105,98 @@ func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, cluster, n
 	_, err = future.Result(ac.agentpools)
 	return err
 }
+
+// GetArtifactStreaming returns whether artifact streaming is enabled on an agent pool.
+func (ac *AzureClient) GetArtifactStreaming(ctx context.Context, resourceGroupName, cluster, name string) (_ bool, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "agentpools.AzureClient.GetArtifactStreaming")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	agentPool, err := ac.getRaw(ctx, resourceGroupName, cluster, name)
+	if err != nil {
+		return false, err
+	}
+	properties, _ := agentPool["properties"].(map[string]interface{})
+	profile, _ := properties["artifactStreamingProfile"].(map[string]interface{})
+	enabled, _ := profile["enabled"].(bool)
+	return enabled, nil
+}
+
+// SetArtifactStreaming enables or disables artifact streaming on an existing agent pool. As the agent pool has to be
+// updated as a whole, the other properties are sent back as AKS returns them.
+func (ac *AzureClient) SetArtifactStreaming(ctx context.Context, resourceGroupName, cluster, name string, enabled bool) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "agentpools.AzureClient.SetArtifactStreaming")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	agentPool, err := ac.getRaw(ctx, resourceGroupName, cluster, name)
+	if err != nil {
+		return err
+	}
+	properties, ok := agentPool["properties"].(map[string]interface{})
+	if !ok {
+		properties = map[string]interface{}{}
+		agentPool["properties"] = properties
+	}
+	properties["artifactStreamingProfile"] = map[string]interface{}{"enabled": enabled}
+
+	req, err := ac.preparer(ctx, resourceGroupName, cluster, name, autorest.AsPut(), autorest.WithJSON(agentPool))
+	if err != nil {
+		return autorest.NewErrorWithError(err, "agentpools.AzureClient", "SetArtifactStreaming", nil, "Failure preparing request")
+	}
+	resp, err := ac.agentpools.Send(req, azureautorest.DoRetryWithRegistration(ac.agentpools.Client))
+	if err != nil {
+		return autorest.NewErrorWithError(err, "agentpools.AzureClient", "SetArtifactStreaming", resp, "Failure sending request")
+	}
+	future, err := azureautorest.NewFutureFromResponse(resp)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin operation")
+	}
+	if err := future.WaitForCompletionRef(ctx, ac.agentpools.Client); err != nil {
+		return errors.Wrap(err, "failed to end operation")
+	}
+	return nil
+}
+
+// getRaw gets an agent pool with the artifact streaming API version, as raw JSON.
+func (ac *AzureClient) getRaw(ctx context.Context, resourceGroupName, cluster, name string) (map[string]interface{}, error) {
+	req, err := ac.preparer(ctx, resourceGroupName, cluster, name, autorest.AsGet())
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "agentpools.AzureClient", "getRaw", nil, "Failure preparing request")
+	}
+	resp, err := ac.agentpools.Send(req, azureautorest.DoRetryWithRegistration(ac.agentpools.Client))
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "agentpools.AzureClient", "getRaw", resp, "Failure sending request")
+	}
+	agentPool := map[string]interface{}{}
+	err = autorest.Respond(
+		resp,
+		azureautorest.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(&agentPool),
+		autorest.ByClosing())
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "agentpools.AzureClient", "getRaw", resp, "Failure responding to request")
+	}
+	return agentPool, nil
+}
+
+// preparer prepares a request on an agent pool with the artifact streaming API version.
+func (ac *AzureClient) preparer(ctx context.Context, resourceGroupName, cluster, name string, decorators ...autorest.PrepareDecorator) (*http.Request, error) {
+	pathParameters := map[string]interface{}{
+		"agentPoolName":     autorest.Encode("path", name),
+		"resourceGroupName": autorest.Encode("path", resourceGroupName),
+		"resourceName":      autorest.Encode("path", cluster),
+		"subscriptionId":    autorest.Encode("path", ac.agentpools.SubscriptionID),
+	}
+	queryParameters := map[string]interface{}{
+		"api-version": artifactStreamingAPIVersion,
+	}
+
+	decorators = append([]autorest.PrepareDecorator{
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.WithBaseURL(ac.agentpools.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.ContainerService/managedClusters/{resourceName}/agentPools/{agentPoolName}", pathParameters),
+		autorest.WithQueryParameters(queryParameters),
+	}, decorators...)
+	return autorest.CreatePreparer(decorators...).Prepare((&http.Request{}).WithContext(ctx))
+}
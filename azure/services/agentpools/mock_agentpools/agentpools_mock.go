@@ -93,3 +93,32 @@ func (mr *MockClientMockRecorder) Get(arg0, arg1, arg2, arg3 interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), arg0, arg1, arg2, arg3)
 }
+
+// GetArtifactStreaming mocks base method.
+func (m *MockClient) GetArtifactStreaming(arg0 context.Context, arg1, arg2, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtifactStreaming", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtifactStreaming indicates an expected call of GetArtifactStreaming.
+func (mr *MockClientMockRecorder) GetArtifactStreaming(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtifactStreaming", reflect.TypeOf((*MockClient)(nil).GetArtifactStreaming), arg0, arg1, arg2, arg3)
+}
+
+// SetArtifactStreaming mocks base method.
+func (m *MockClient) SetArtifactStreaming(arg0 context.Context, arg1, arg2, arg3 string, arg4 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetArtifactStreaming", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetArtifactStreaming indicates an expected call of SetArtifactStreaming.
+func (mr *MockClientMockRecorder) SetArtifactStreaming(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArtifactStreaming", reflect.TypeOf((*MockClient)(nil).SetArtifactStreaming), arg0, arg1, arg2, arg3, arg4)
+}
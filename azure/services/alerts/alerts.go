@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerts
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// evaluationFrequency is how often the alert rules are evaluated.
+	evaluationFrequency = "PT1M"
+	// windowSize is the period over which the metrics of the alert rules are aggregated.
+	windowSize = "PT5M"
+)
+
+// AlertsScope defines the scope interface for an alerts service.
+type AlertsScope interface {
+	azure.ClusterDescriber
+	AlertRuleSpecs() []azure.AlertRuleSpec
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope AlertsScope
+	client
+}
+
+// New creates a new alerts service.
+func New(scope AlertsScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile creates or updates the metric alert rules of the cluster, and deletes the ones which are not desired anymore.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "alerts.Service.Reconcile")
+	defer done()
+
+	desired := make(map[string]bool)
+	for _, ruleSpec := range s.Scope.AlertRuleSpecs() {
+		desired[ruleSpec.Name] = true
+		log.V(2).Info("creating metric alert rule", "alert rule", ruleSpec.Name)
+		if err := s.client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), ruleSpec.Name, s.metricAlert(ruleSpec)); err != nil {
+			return errors.Wrapf(err, "failed to create metric alert rule %s in resource group %s", ruleSpec.Name, s.Scope.ResourceGroup())
+		}
+		log.V(2).Info("successfully created metric alert rule", "alert rule", ruleSpec.Name)
+	}
+
+	return s.deleteOwned(ctx, desired)
+}
+
+// Delete deletes the metric alert rules owned by the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "alerts.Service.Delete")
+	defer done()
+
+	return s.deleteOwned(ctx, nil)
+}
+
+// deleteOwned deletes the metric alert rules owned by the cluster which are not in keep.
+func (s *Service) deleteOwned(ctx context.Context, keep map[string]bool) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "alerts.Service.deleteOwned")
+	defer done()
+
+	rules, err := s.client.List(ctx, s.Scope.ResourceGroup())
+	if azure.ResourceNotFound(err) {
+		// the resource group is already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to list metric alert rules in resource group %s", s.Scope.ResourceGroup())
+	}
+
+	for _, rule := range rules {
+		name := to.String(rule.Name)
+		if keep[name] || !converters.MapToTags(rule.Tags).HasOwned(s.Scope.ClusterName()) {
+			continue
+		}
+		log.V(2).Info("deleting metric alert rule", "alert rule", name)
+		if err := s.client.Delete(ctx, s.Scope.ResourceGroup(), name); err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete metric alert rule %s in resource group %s", name, s.Scope.ResourceGroup())
+		}
+		log.V(2).Info("successfully deleted metric alert rule", "alert rule", name)
+	}
+	return nil
+}
+
+// metricAlert returns the metric alert rule of a spec.
+func (s *Service) metricAlert(ruleSpec azure.AlertRuleSpec) insights.MetricAlertResource {
+	criterion := insights.MetricCriteria{
+		Name:            to.StringPtr(ruleSpec.MetricName),
+		MetricName:      to.StringPtr(ruleSpec.MetricName),
+		MetricNamespace: to.StringPtr(ruleSpec.TargetResourceType),
+		TimeAggregation: ruleSpec.Aggregation,
+		Operator:        insights.Operator(ruleSpec.Operator),
+		Threshold:       to.Float64Ptr(ruleSpec.Threshold),
+		CriterionType:   insights.CriterionTypeStaticThresholdCriterion,
+	}
+	if ruleSpec.DimensionName != "" {
+		values := ruleSpec.DimensionValues
+		criterion.Dimensions = &[]insights.MetricDimension{
+			{
+				Name:     to.StringPtr(ruleSpec.DimensionName),
+				Operator: to.StringPtr("Include"),
+				Values:   &values,
+			},
+		}
+	}
+
+	scopes := ruleSpec.Scopes
+	return insights.MetricAlertResource{
+		// Metric alert rules are global resources.
+		Location: to.StringPtr("global"),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.Scope.ClusterName(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(ruleSpec.Name),
+			Role:        to.StringPtr(infrav1.CommonRole),
+			Additional:  s.Scope.AdditionalTags(),
+		})),
+		MetricAlertProperties: &insights.MetricAlertProperties{
+			Description:          to.StringPtr(ruleSpec.Description),
+			Severity:             to.Int32Ptr(ruleSpec.Severity),
+			Enabled:              to.BoolPtr(true),
+			Scopes:               &scopes,
+			EvaluationFrequency:  to.StringPtr(evaluationFrequency),
+			WindowSize:           to.StringPtr(windowSize),
+			TargetResourceType:   to.StringPtr(ruleSpec.TargetResourceType),
+			TargetResourceRegion: to.StringPtr(s.Scope.Location()),
+			Criteria: insights.MetricAlertMultipleResourceMultipleMetricCriteria{
+				AllOf:     &[]insights.BasicMultiMetricCriteria{criterion},
+				OdataType: insights.OdataTypeMicrosoftAzureMonitorMultipleResourceMultipleMetricCriteria,
+			},
+			AutoMitigate: to.BoolPtr(true),
+			Actions: &[]insights.MetricAlertAction{
+				{ActionGroupID: to.StringPtr(ruleSpec.ActionGroupID)},
+			},
+		},
+	}
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/alerts/mock_alerts"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeVMAvailabilitySpec = azure.AlertRuleSpec{
+		Name:               "my-cluster-vm-availability",
+		Description:        "A virtual machine of cluster my-cluster is unavailable",
+		Severity:           2,
+		Scopes:             []string{"/subscriptions/123/resourceGroups/my-rg"},
+		TargetResourceType: "Microsoft.Compute/virtualMachines",
+		MetricName:         "VmAvailabilityMetric",
+		Aggregation:        "Average",
+		Operator:           "LessThan",
+		Threshold:          1,
+		ActionGroupID:      "/subscriptions/123/resourceGroups/ops/providers/Microsoft.Insights/actionGroups/oncall",
+	}
+	fakeNATGatewaySpec = azure.AlertRuleSpec{
+		Name:               "my-natgateway-snat-failed",
+		Description:        "SNAT connections fail on NAT gateway my-natgateway of cluster my-cluster",
+		Severity:           2,
+		Scopes:             []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-natgateway"},
+		TargetResourceType: "Microsoft.Network/natGateways",
+		MetricName:         "SNATConnectionCount",
+		Aggregation:        "Total",
+		Operator:           "GreaterThan",
+		Threshold:          0,
+		DimensionName:      "ConnectionState",
+		DimensionValues:    []string{"Failed"},
+		ActionGroupID:      "/subscriptions/123/resourceGroups/ops/providers/Microsoft.Insights/actionGroups/oncall",
+	}
+	ownedTags = map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+	}
+	notFound    = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not found")
+	internalErr = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func TestReconcileAlerts(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_alerts.MockAlertsScopeMockRecorder, m *mock_alerts.MockclientMockRecorder)
+	}{
+		{
+			name:          "create the alert rules",
+			expectedError: "",
+			expect: func(s *mock_alerts.MockAlertsScopeMockRecorder, m *mock_alerts.MockclientMockRecorder) {
+				s.AlertRuleSpecs().Return([]azure.AlertRuleSpec{fakeVMAvailabilitySpec, fakeNATGatewaySpec})
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster-vm-availability", gomock.Any()).
+					Do(func(_ context.Context, _, _ string, rule insights.MetricAlertResource) {
+						g := NewWithT(t)
+						g.Expect(rule.Location).To(Equal(to.StringPtr("global")))
+						g.Expect(rule.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", to.StringPtr("owned")))
+						g.Expect(*rule.Scopes).To(Equal([]string{"/subscriptions/123/resourceGroups/my-rg"}))
+						g.Expect(rule.TargetResourceType).To(Equal(to.StringPtr("Microsoft.Compute/virtualMachines")))
+						g.Expect(rule.TargetResourceRegion).To(Equal(to.StringPtr("westus")))
+						g.Expect(rule.Severity).To(Equal(to.Int32Ptr(2)))
+						g.Expect(*rule.Actions).To(ConsistOf(insights.MetricAlertAction{ActionGroupID: to.StringPtr(fakeVMAvailabilitySpec.ActionGroupID)}))
+						criteria, ok := rule.Criteria.AsMetricAlertMultipleResourceMultipleMetricCriteria()
+						g.Expect(ok).To(BeTrue())
+						g.Expect(*criteria.AllOf).To(HaveLen(1))
+						criterion, ok := (*criteria.AllOf)[0].AsMetricCriteria()
+						g.Expect(ok).To(BeTrue())
+						g.Expect(criterion.MetricName).To(Equal(to.StringPtr("VmAvailabilityMetric")))
+						g.Expect(criterion.Operator).To(Equal(insights.OperatorLessThan))
+						g.Expect(criterion.Threshold).To(Equal(to.Float64Ptr(1)))
+						g.Expect(criterion.Dimensions).To(BeNil())
+					})
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-natgateway-snat-failed", gomock.Any()).
+					Do(func(_ context.Context, _, _ string, rule insights.MetricAlertResource) {
+						g := NewWithT(t)
+						criteria, _ := rule.Criteria.AsMetricAlertMultipleResourceMultipleMetricCriteria()
+						criterion, _ := (*criteria.AllOf)[0].AsMetricCriteria()
+						g.Expect(criterion.TimeAggregation).To(Equal("Total"))
+						g.Expect(*criterion.Dimensions).To(ConsistOf(insights.MetricDimension{
+							Name:     to.StringPtr("ConnectionState"),
+							Operator: to.StringPtr("Include"),
+							Values:   &[]string{"Failed"},
+						}))
+					})
+				m.List(gomockinternal.AContext(), "my-rg").Return([]insights.MetricAlertResource{
+					{Name: to.StringPtr("my-cluster-vm-availability"), Tags: ownedTags},
+					{Name: to.StringPtr("my-natgateway-snat-failed"), Tags: ownedTags},
+				}, nil)
+			},
+		},
+		{
+			name:          "delete the owned alert rules which are not desired anymore",
+			expectedError: "",
+			expect: func(s *mock_alerts.MockAlertsScopeMockRecorder, m *mock_alerts.MockclientMockRecorder) {
+				s.AlertRuleSpecs().Return([]azure.AlertRuleSpec{fakeVMAvailabilitySpec})
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster-vm-availability", gomock.Any())
+				m.List(gomockinternal.AContext(), "my-rg").Return([]insights.MetricAlertResource{
+					{Name: to.StringPtr("my-cluster-vm-availability"), Tags: ownedTags},
+					{Name: to.StringPtr("my-natgateway-snat-failed"), Tags: ownedTags},
+					{Name: to.StringPtr("user-rule")},
+				}, nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-natgateway-snat-failed")
+			},
+		},
+		{
+			name:          "no alert rules",
+			expectedError: "",
+			expect: func(s *mock_alerts.MockAlertsScopeMockRecorder, m *mock_alerts.MockclientMockRecorder) {
+				s.AlertRuleSpecs().Return(nil)
+				m.List(gomockinternal.AContext(), "my-rg").Return(nil, nil)
+			},
+		},
+		{
+			name:          "fail to create an alert rule",
+			expectedError: "failed to create metric alert rule my-cluster-vm-availability in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_alerts.MockAlertsScopeMockRecorder, m *mock_alerts.MockclientMockRecorder) {
+				s.AlertRuleSpecs().Return([]azure.AlertRuleSpec{fakeVMAvailabilitySpec})
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster-vm-availability", gomock.Any()).Return(internalErr)
+			},
+		},
+		{
+			name:          "fail to list the alert rules",
+			expectedError: "failed to list metric alert rules in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_alerts.MockAlertsScopeMockRecorder, m *mock_alerts.MockclientMockRecorder) {
+				s.AlertRuleSpecs().Return(nil)
+				m.List(gomockinternal.AContext(), "my-rg").Return(nil, internalErr)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_alerts.NewMockAlertsScope(mockCtrl)
+			clientMock := mock_alerts.NewMockclient(mockCtrl)
+
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			scopeMock.EXPECT().Location().AnyTimes().Return("westus")
+			scopeMock.EXPECT().AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteAlerts(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(m *mock_alerts.MockclientMockRecorder)
+	}{
+		{
+			name:          "delete the owned alert rules",
+			expectedError: "",
+			expect: func(m *mock_alerts.MockclientMockRecorder) {
+				m.List(gomockinternal.AContext(), "my-rg").Return([]insights.MetricAlertResource{
+					{Name: to.StringPtr("my-cluster-vm-availability"), Tags: ownedTags},
+					{Name: to.StringPtr("user-rule")},
+				}, nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-cluster-vm-availability")
+			},
+		},
+		{
+			name:          "resource group already deleted",
+			expectedError: "",
+			expect: func(m *mock_alerts.MockclientMockRecorder) {
+				m.List(gomockinternal.AContext(), "my-rg").Return(nil, notFound)
+			},
+		},
+		{
+			name:          "alert rule already deleted",
+			expectedError: "",
+			expect: func(m *mock_alerts.MockclientMockRecorder) {
+				m.List(gomockinternal.AContext(), "my-rg").Return([]insights.MetricAlertResource{
+					{Name: to.StringPtr("my-cluster-vm-availability"), Tags: ownedTags},
+				}, nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-cluster-vm-availability").Return(notFound)
+			},
+		},
+		{
+			name:          "fail to delete an alert rule",
+			expectedError: "failed to delete metric alert rule my-cluster-vm-availability in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_alerts.MockclientMockRecorder) {
+				m.List(gomockinternal.AContext(), "my-rg").Return([]insights.MetricAlertResource{
+					{Name: to.StringPtr("my-cluster-vm-availability"), Tags: ownedTags},
+				}, nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-cluster-vm-availability").Return(internalErr)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_alerts.NewMockAlertsScope(mockCtrl)
+			clientMock := mock_alerts.NewMockclient(mockCtrl)
+
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			tc.expect(clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerts
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	List(context.Context, string) ([]insights.MetricAlertResource, error)
+	CreateOrUpdate(context.Context, string, string, insights.MetricAlertResource) error
+	Delete(context.Context, string, string) error
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	metricalerts insights.MetricAlertsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new metric alerts client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newMetricAlertsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c}
+}
+
+// newMetricAlertsClient creates a new metric alerts client from subscription ID.
+func newMetricAlertsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) insights.MetricAlertsClient {
+	metricAlertsClient := insights.NewMetricAlertsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&metricAlertsClient.Client, authorizer, "alerts")
+	return metricAlertsClient
+}
+
+// List lists the metric alert rules of a resource group.
+func (ac *azureClient) List(ctx context.Context, resourceGroupName string) (_ []insights.MetricAlertResource, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "alerts.AzureClient.List")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	collection, err := ac.metricalerts.ListByResourceGroup(ctx, resourceGroupName)
+	if err != nil || collection.Value == nil {
+		return nil, err
+	}
+	return *collection.Value, nil
+}
+
+// CreateOrUpdate creates or updates a metric alert rule.
+func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, ruleName string, rule insights.MetricAlertResource) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "alerts.AzureClient.CreateOrUpdate")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.metricalerts.CreateOrUpdate(ctx, resourceGroupName, ruleName, rule)
+	return err
+}
+
+// Delete deletes a metric alert rule.
+func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, ruleName string) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "alerts.AzureClient.Delete")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.metricalerts.Delete(ctx, resourceGroupName, ruleName)
+	return err
+}
@@ -26,6 +26,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -64,10 +65,11 @@ func processOngoingOperation(ctx context.Context, scope FutureScope, client Futu
 	// Resource has been created/deleted/updated.
 	log.V(2).Info("long running operation has completed", "service", serviceName, "resource", resourceName)
 	result, err := client.Result(ctx, sdkFuture, future.Type)
-	if err == nil {
+	// Retryable failures restart the operation instead of getting the result of the failed operation again.
+	if err == nil || azureerrors.IsRetryable(err) {
 		scope.DeleteLongRunningOperationState(resourceName, serviceName)
 	}
-	return result, err
+	return result, withClassification(err)
 }
 
 // CreateResource implements the logic for creating a resource Asynchronously.
@@ -95,7 +97,7 @@ func CreateResource(ctx context.Context, scope FutureScope, client Creator, spec
 		scope.SetLongRunningOperationState(future)
 		return nil, azure.WithTransientError(azure.NewOperationNotDoneError(future), retryAfter(sdkFuture))
 	} else if err != nil {
-		return nil, errors.Wrapf(err, "failed to create resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+		return nil, withClassification(errors.Wrapf(err, "failed to create resource %s/%s (service: %s)", rgName, resourceName, serviceName))
 	}
 
 	log.V(2).Info("successfully created resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
@@ -132,7 +134,7 @@ func DeleteResource(ctx context.Context, scope FutureScope, client Deleter, spec
 			// already deleted
 			return nil
 		}
-		return errors.Wrapf(err, "failed to delete resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+		return withClassification(errors.Wrapf(err, "failed to delete resource %s/%s (service: %s)", rgName, resourceName, serviceName))
 	}
 
 	log.V(2).Info("successfully deleted resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
@@ -148,3 +150,18 @@ func retryAfter(sdkFuture azureautorest.FutureAPI) time.Duration {
 	}
 	return retryAfter
 }
+
+// withClassification wraps the errors classified as terminal by the configured error classification in a terminal
+// ReconcileError, and the errors classified as retryable in a transient ReconcileError. Other errors are returned
+// unchanged and retried with the backoff of the controller.
+func withClassification(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case azureerrors.IsClassifiedTerminal(err):
+		return azure.WithTerminalError(err)
+	case azureerrors.IsRetryable(err):
+		return azure.WithTransientError(err, reconciler.DefaultReconcilerRequeue)
+	}
+	return err
+}
@@ -29,6 +29,8 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
@@ -315,3 +317,75 @@ func TestDeleteResource(t *testing.T) {
 		})
 	}
 }
+
+// TestProcessOngoingOperationWithClassification tests that the configured error classification is applied to the
+// results of long running operations.
+func TestProcessOngoingOperationWithClassification(t *testing.T) {
+	azureerrors.SetClassification(azureerrors.Classification{
+		Retryable: []string{"SubscriptionNotReady"},
+		Terminal:  []string{"QuotaExceeded"},
+	})
+	defer azureerrors.SetClassification(azureerrors.Classification{})
+
+	serviceError := func(code string) error {
+		return autorest.NewErrorWithError(&azureautorest.ServiceError{Code: code}, "", "", &http.Response{StatusCode: http.StatusConflict}, "Failure")
+	}
+
+	testcases := []struct {
+		name   string
+		err    error
+		expect func(s *mock_async.MockFutureScopeMockRecorder)
+		verify func(g *WithT, err error)
+	}{
+		{
+			name: "retryable failure restarts the operation",
+			err:  serviceError("SubscriptionNotReady"),
+			expect: func(s *mock_async.MockFutureScopeMockRecorder) {
+				s.DeleteLongRunningOperationState("test-resource", "test-service")
+			},
+			verify: func(g *WithT, err error) {
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+				g.Expect(reconcileError.IsTransient()).To(BeTrue())
+			},
+		},
+		{
+			name:   "terminal failure is not retried",
+			err:    serviceError("QuotaExceeded"),
+			expect: func(s *mock_async.MockFutureScopeMockRecorder) {},
+			verify: func(g *WithT, err error) {
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+				g.Expect(reconcileError.IsTerminal()).To(BeTrue())
+			},
+		},
+		{
+			name:   "unclassified failure is returned unchanged",
+			err:    fakeError,
+			expect: func(s *mock_async.MockFutureScopeMockRecorder) {},
+			verify: func(g *WithT, err error) {
+				g.Expect(err).To(Equal(fakeError))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+			clientMock := mock_async.NewMockFutureHandler(mockCtrl)
+
+			scopeMock.EXPECT().GetLongRunningOperationState("test-resource", "test-service").Return(&validCreateFuture)
+			clientMock.EXPECT().IsDone(gomockinternal.AContext(), gomock.AssignableToTypeOf(&azureautorest.Future{})).Return(true, nil)
+			clientMock.EXPECT().Result(gomockinternal.AContext(), gomock.AssignableToTypeOf(&azureautorest.Future{}), infrav1.PutFuture).Return(nil, azureerrors.FromAutorest(tc.err))
+			tc.expect(scopeMock.EXPECT())
+
+			_, err := processOngoingOperation(context.TODO(), scopeMock, clientMock, "test-resource", "test-service")
+			tc.verify(g, err)
+		})
+	}
+}
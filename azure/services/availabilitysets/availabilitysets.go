@@ -34,6 +34,7 @@ import (
 type AvailabilitySetScope interface {
 	azure.ClusterDescriber
 	AvailabilitySet() (string, bool)
+	ProximityPlacementGroupID() string
 }
 
 // Service provides operations on Azure resources.
@@ -98,6 +99,9 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		})),
 		Location: to.StringPtr(s.Scope.Location()),
 	}
+	if ppgID := s.Scope.ProximityPlacementGroupID(); ppgID != "" {
+		asParams.ProximityPlacementGroup = &compute.SubResource{ID: to.StringPtr(ppgID)}
+	}
 
 	_, err = s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), availabilitySetName, asParams)
 	if err != nil {
@@ -48,6 +48,7 @@ func TestReconcileAvailabilitySets(t *testing.T) {
 				s.ClusterName().Return("cl-name")
 				s.AdditionalTags().Return(map[string]string{})
 				s.Location().Return("test-location")
+				s.ProximityPlacementGroupID().Return("")
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "as-name",
 					compute.AvailabilitySet{
 						Sku: &compute.Sku{Name: to.StringPtr("Aligned")},
@@ -76,6 +77,47 @@ func TestReconcileAvailabilitySets(t *testing.T) {
 				svc.resourceSKUCache = resourceSkusCache
 			},
 		},
+		{
+			name:          "create or update availability set in a proximity placement group",
+			expectedError: "",
+			expect: func(s *mock_availabilitysets.MockAvailabilitySetScopeMockRecorder, m *mock_availabilitysets.MockClientMockRecorder) {
+				s.AvailabilitySet().Return("as-name", true)
+				s.ResourceGroup().Return("my-rg")
+				s.ClusterName().Return("cl-name")
+				s.AdditionalTags().Return(map[string]string{})
+				s.Location().Return("test-location")
+				s.ProximityPlacementGroupID().Return("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg")
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "as-name",
+					compute.AvailabilitySet{
+						Sku: &compute.Sku{Name: to.StringPtr("Aligned")},
+						AvailabilitySetProperties: &compute.AvailabilitySetProperties{
+							PlatformFaultDomainCount: pointer.Int32Ptr(3),
+							ProximityPlacementGroup: &compute.SubResource{
+								ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"),
+							},
+						},
+						Tags: map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_cl-name": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role": to.StringPtr("common"), "Name": to.StringPtr("as-name")},
+						Location: to.StringPtr("test-location"),
+					}).Return(compute.AvailabilitySet{}, nil)
+			},
+			setupSKUs: func(svc *Service) {
+				skus := []compute.ResourceSku{
+					{
+						Name: to.StringPtr("Aligned"),
+						Kind: to.StringPtr(string(resourceskus.AvailabilitySets)),
+						Capabilities: &[]compute.ResourceSkuCapabilities{
+							{
+								Name:  to.StringPtr(resourceskus.MaximumPlatformFaultDomainCount),
+								Value: to.StringPtr("3"),
+							},
+						},
+					},
+				}
+				resourceSkusCache := resourceskus.NewStaticCache(skus, "")
+				svc.resourceSKUCache = resourceSkusCache
+			},
+		},
 		{
 			name:          "noop if the machine does not need to be assigned an availability set (machines without a deployment)",
 			expectedError: "",
@@ -94,6 +136,7 @@ func TestReconcileAvailabilitySets(t *testing.T) {
 				s.ClusterName().Return("cl-name")
 				s.AdditionalTags().Return(map[string]string{})
 				s.Location().Return("test-location")
+				s.ProximityPlacementGroupID().Return("")
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "as-name",
 					gomock.AssignableToTypeOf(compute.AvailabilitySet{})).Return(compute.AvailabilitySet{}, errors.New("something went wrong"))
 			},
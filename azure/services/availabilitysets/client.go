@@ -22,6 +22,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -49,31 +50,34 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newAvailabilitySetsClient creates a new AvailabilitySets Client from subscription ID.
 func newAvailabilitySetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.AvailabilitySetsClient {
 	asClient := compute.NewAvailabilitySetsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&asClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&asClient.Client, authorizer, "availabilitysets")
 	return asClient
 }
 
 // Get gets an availability set.
-func (a *AzureClient) Get(ctx context.Context, resourceGroup, availabilitySetsName string) (compute.AvailabilitySet, error) {
+func (a *AzureClient) Get(ctx context.Context, resourceGroup, availabilitySetsName string) (_ compute.AvailabilitySet, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "availabilitysets.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return a.availabilitySets.Get(ctx, resourceGroup, availabilitySetsName)
 }
 
 // CreateOrUpdate creates or updates an availability set.
 func (a *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroup string, availabilitySetsName string,
-	params compute.AvailabilitySet) (compute.AvailabilitySet, error) {
+	params compute.AvailabilitySet) (_ compute.AvailabilitySet, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "availabilitysets.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return a.availabilitySets.CreateOrUpdate(ctx, resourceGroup, availabilitySetsName, params)
 }
 
 // Delete deletes an availability set.
-func (a *AzureClient) Delete(ctx context.Context, resourceGroup, availabilitySetsName string) error {
+func (a *AzureClient) Delete(ctx context.Context, resourceGroup, availabilitySetsName string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "availabilitysets.AzureClient.Delete")
 	defer done()
-	_, err := a.availabilitySets.Delete(ctx, resourceGroup, availabilitySetsName)
+	defer azureerrors.Classify(&err)
+	_, err = a.availabilitySets.Delete(ctx, resourceGroup, availabilitySetsName)
 	return err
 }
@@ -234,6 +234,48 @@ func (mr *MockAvailabilitySetScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockAvailabilitySetScope)(nil).Location))
 }
 
+// ProximityPlacementGroupID mocks base method.
+func (m *MockAvailabilitySetScope) ProximityPlacementGroupID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProximityPlacementGroupID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ProximityPlacementGroupID indicates an expected call of ProximityPlacementGroupID.
+func (mr *MockAvailabilitySetScopeMockRecorder) ProximityPlacementGroupID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProximityPlacementGroupID", reflect.TypeOf((*MockAvailabilitySetScope)(nil).ProximityPlacementGroupID))
+}
+
+// ProxyConfig mocks base method.
+func (m *MockAvailabilitySetScope) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockAvailabilitySetScopeMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockAvailabilitySetScope)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockAvailabilitySetScope) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockAvailabilitySetScopeMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockAvailabilitySetScope)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockAvailabilitySetScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -275,3 +317,17 @@ func (mr *MockAvailabilitySetScopeMockRecorder) TenantID() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockAvailabilitySetScope)(nil).TenantID))
 }
+
+// TrustedCAs mocks base method.
+func (m *MockAvailabilitySetScope) TrustedCAs() *v1beta1.TrustedCASource {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrustedCAs")
+	ret0, _ := ret[0].(*v1beta1.TrustedCASource)
+	return ret0
+}
+
+// TrustedCAs indicates an expected call of TrustedCAs.
+func (mr *MockAvailabilitySetScopeMockRecorder) TrustedCAs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustedCAs", reflect.TypeOf((*MockAvailabilitySetScope)(nil).TrustedCAs))
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azurefirewalls
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// DefaultRouteName is the name of the route of the node route tables which sends the egress to the firewall.
+	DefaultRouteName = "default-to-firewall"
+	// defaultRouteAddressPrefix matches all the destinations which no more specific route matches.
+	defaultRouteAddressPrefix = "0.0.0.0/0"
+)
+
+// AzureFirewallScope defines the scope interface for an Azure Firewall service.
+type AzureFirewallScope interface {
+	azure.ClusterDescriber
+	Vnet() *infrav1.VnetSpec
+	AzureFirewallSpec() *azure.AzureFirewallSpec
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope AzureFirewallScope
+	client
+	subnetsClient   subnets.Client
+	publicIPsClient publicips.Client
+}
+
+// New creates a new service.
+func New(scope AzureFirewallScope) *Service {
+	return &Service{
+		Scope:           scope,
+		client:          newClient(scope),
+		subnetsClient:   subnets.NewClient(scope),
+		publicIPsClient: publicips.NewClient(scope),
+	}
+}
+
+// Reconcile creates the Azure Firewall if it doesn't exist, and routes the egress of the node subnets through it.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "azurefirewalls.Service.Reconcile")
+	defer done()
+
+	firewallSpec := s.Scope.AzureFirewallSpec()
+	if firewallSpec == nil {
+		return nil
+	}
+
+	firewall, err := s.client.Get(ctx, firewallSpec.ResourceGroup, firewallSpec.Name)
+	switch {
+	case err == nil:
+		// the tier and the IP configuration of a firewall are not updated, as it may require recreating it
+	case !azure.ResourceNotFound(err):
+		return errors.Wrapf(err, "failed to get Azure Firewall %s", firewallSpec.Name)
+	case !firewallSpec.Managed:
+		return errors.Wrapf(err, "failed to get existing Azure Firewall %s in resource group %s", firewallSpec.Name, firewallSpec.ResourceGroup)
+	default:
+		if firewall, err = s.createFirewall(ctx, firewallSpec); err != nil {
+			return err
+		}
+	}
+
+	privateIP := privateIPAddress(firewall)
+	if privateIP == "" {
+		return errors.Errorf("Azure Firewall %s has no private IP address yet", firewallSpec.Name)
+	}
+
+	if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
+		log.V(4).Info("Skipping default routes to Azure Firewall in custom vnet mode")
+		return nil
+	}
+	for _, routeTableName := range firewallSpec.RouteTableNames {
+		if err := s.reconcileDefaultRoute(ctx, routeTableName, privateIP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createFirewall creates the Azure Firewall in its subnet, and returns it with the private IP address it was allocated.
+func (s *Service) createFirewall(ctx context.Context, firewallSpec *azure.AzureFirewallSpec) (network.AzureFirewall, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "azurefirewalls.Service.createFirewall")
+	defer done()
+
+	publicIP, err := s.publicIPsClient.Get(ctx, s.Scope.ResourceGroup(), firewallSpec.PublicIPName)
+	if err != nil {
+		return network.AzureFirewall{}, errors.Wrap(err, "failed to get public IP for Azure Firewall")
+	}
+
+	subnet, err := s.subnetsClient.Get(ctx, firewallSpec.ResourceGroup, firewallSpec.VNetName, firewallSpec.SubnetName)
+	if err != nil {
+		return network.AzureFirewall{}, errors.Wrap(err, "failed to get subnet for Azure Firewall")
+	}
+
+	var policy *network.SubResource
+	if firewallSpec.PolicyID != "" {
+		policy = &network.SubResource{ID: to.StringPtr(firewallSpec.PolicyID)}
+	}
+
+	log.V(2).Info("creating Azure Firewall", "firewall", firewallSpec.Name)
+	firewall, err := s.client.CreateOrUpdate(ctx, firewallSpec.ResourceGroup, firewallSpec.Name, network.AzureFirewall{
+		Location: to.StringPtr(s.Scope.Location()),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.Scope.ClusterName(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(firewallSpec.Name),
+			Role:        to.StringPtr(infrav1.CommonRole),
+			Additional:  s.Scope.AdditionalTags(),
+		})),
+		AzureFirewallPropertiesFormat: &network.AzureFirewallPropertiesFormat{
+			Sku: &network.AzureFirewallSku{
+				Name: network.AzureFirewallSkuNameAZFWVNet,
+				Tier: network.AzureFirewallSkuTier(firewallSpec.SkuTier),
+			},
+			IPConfigurations: &[]network.AzureFirewallIPConfiguration{
+				{
+					Name: to.StringPtr(fmt.Sprintf("%s-ipconfig", firewallSpec.Name)),
+					AzureFirewallIPConfigurationPropertiesFormat: &network.AzureFirewallIPConfigurationPropertiesFormat{
+						Subnet:          &network.SubResource{ID: subnet.ID},
+						PublicIPAddress: &network.SubResource{ID: publicIP.ID},
+					},
+				},
+			},
+			FirewallPolicy: policy,
+		},
+	})
+	if err != nil {
+		return network.AzureFirewall{}, errors.Wrapf(err, "failed to create Azure Firewall %s in resource group %s", firewallSpec.Name, firewallSpec.ResourceGroup)
+	}
+
+	log.V(2).Info("successfully created Azure Firewall", "firewall", firewallSpec.Name)
+	return firewall, nil
+}
+
+// reconcileDefaultRoute points the default route of a node route table to the private IP address of the firewall.
+func (s *Service) reconcileDefaultRoute(ctx context.Context, routeTableName, privateIP string) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "azurefirewalls.Service.reconcileDefaultRoute")
+	defer done()
+
+	route, err := s.client.GetRoute(ctx, s.Scope.ResourceGroup(), routeTableName, DefaultRouteName)
+	switch {
+	case err == nil:
+		if props := route.RoutePropertiesFormat; props != nil && to.String(props.AddressPrefix) == defaultRouteAddressPrefix &&
+			props.NextHopType == network.RouteNextHopTypeVirtualAppliance && to.String(props.NextHopIPAddress) == privateIP {
+			return nil
+		}
+	case !azure.ResourceNotFound(err):
+		return errors.Wrapf(err, "failed to get default route of route table %s", routeTableName)
+	}
+
+	log.V(2).Info("routing egress through Azure Firewall", "route table", routeTableName, "next hop", privateIP)
+	err = s.client.CreateOrUpdateRoute(ctx, s.Scope.ResourceGroup(), routeTableName, DefaultRouteName, network.Route{
+		RoutePropertiesFormat: &network.RoutePropertiesFormat{
+			AddressPrefix:    to.StringPtr(defaultRouteAddressPrefix),
+			NextHopType:      network.RouteNextHopTypeVirtualAppliance,
+			NextHopIPAddress: to.StringPtr(privateIP),
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create default route of route table %s in resource group %s", routeTableName, s.Scope.ResourceGroup())
+	}
+
+	log.V(2).Info("successfully routed egress through Azure Firewall", "route table", routeTableName)
+	return nil
+}
+
+// Delete deletes the Azure Firewall if it is owned by the cluster. The default routes are deleted with the route tables.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "azurefirewalls.Service.Delete")
+	defer done()
+
+	firewallSpec := s.Scope.AzureFirewallSpec()
+	if firewallSpec == nil || !firewallSpec.Managed {
+		return nil
+	}
+
+	firewall, err := s.client.Get(ctx, firewallSpec.ResourceGroup, firewallSpec.Name)
+	if azure.ResourceGroupNotFound(err) || azure.ResourceNotFound(err) {
+		// firewall does not exist, there is nothing to delete
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get Azure Firewall %s", firewallSpec.Name)
+	}
+
+	if !converters.MapToTags(firewall.Tags).HasOwned(s.Scope.ClusterName()) {
+		log.V(4).Info("Skipping deletion of unmanaged Azure Firewall", "firewall", firewallSpec.Name)
+		return nil
+	}
+
+	log.V(2).Info("deleting Azure Firewall", "firewall", firewallSpec.Name)
+	err = s.client.Delete(ctx, firewallSpec.ResourceGroup, firewallSpec.Name)
+	if err != nil && !azure.ResourceGroupNotFound(err) && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete Azure Firewall %s in resource group %s", firewallSpec.Name, firewallSpec.ResourceGroup)
+	}
+
+	log.V(2).Info("successfully deleted Azure Firewall", "firewall", firewallSpec.Name)
+	return nil
+}
+
+// privateIPAddress returns the private IP address of a firewall, which the node route tables use as next hop.
+func privateIPAddress(firewall network.AzureFirewall) string {
+	if firewall.AzureFirewallPropertiesFormat == nil || firewall.IPConfigurations == nil {
+		return ""
+	}
+	for _, ipConfig := range *firewall.IPConfigurations {
+		if ipConfig.AzureFirewallIPConfigurationPropertiesFormat != nil && to.String(ipConfig.PrivateIPAddress) != "" {
+			return to.String(ipConfig.PrivateIPAddress)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,396 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azurefirewalls
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/azurefirewalls/mock_azurefirewalls"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips/mock_publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets/mock_subnets"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeFirewallSpec = azure.AzureFirewallSpec{
+		Name:            "my-azfw",
+		ResourceGroup:   "my-vnet-rg",
+		Managed:         true,
+		SkuTier:         infrav1.AzureFirewallSkuTierPremium,
+		PolicyID:        "my-policy-id",
+		VNetName:        "my-vnet",
+		SubnetName:      "AzureFirewallSubnet",
+		PublicIPName:    "my-azfw-pip",
+		RouteTableNames: []string{"my-node-routetable"},
+	}
+	fakeExistingFirewallSpec = azure.AzureFirewallSpec{
+		Name:            "hub-azfw",
+		ResourceGroup:   "hub-rg",
+		RouteTableNames: []string{"my-node-routetable"},
+	}
+	fakeFirewall = network.AzureFirewall{
+		Name: to.StringPtr("my-azfw"),
+		AzureFirewallPropertiesFormat: &network.AzureFirewallPropertiesFormat{
+			IPConfigurations: &[]network.AzureFirewallIPConfiguration{
+				{
+					AzureFirewallIPConfigurationPropertiesFormat: &network.AzureFirewallIPConfigurationPropertiesFormat{
+						PrivateIPAddress: to.StringPtr("10.255.255.132"),
+					},
+				},
+			},
+		},
+	}
+	fakeDefaultRoute = network.Route{
+		RoutePropertiesFormat: &network.RoutePropertiesFormat{
+			AddressPrefix:    to.StringPtr("0.0.0.0/0"),
+			NextHopType:      network.RouteNextHopTypeVirtualAppliance,
+			NextHopIPAddress: to.StringPtr("10.255.255.132"),
+		},
+	}
+	notFoundError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found")
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+)
+
+func TestReconcileAzureFirewalls(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+			m *mock_azurefirewalls.MockclientMockRecorder,
+			mSubnet *mock_subnets.MockClientMockRecorder,
+			mPublicIP *mock_publicips.MockClientMockRecorder)
+	}{
+		{
+			name:          "no firewall",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+				m *mock_azurefirewalls.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.AzureFirewallSpec().Return(nil)
+			},
+		},
+		{
+			name:          "firewall and default route already exist",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+				m *mock_azurefirewalls.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewallSpec)
+				s.Vnet().Return(&infrav1.VnetSpec{Name: "my-vnet"})
+				s.ClusterName().Return("my-cluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-azfw").Return(fakeFirewall, nil)
+				m.GetRoute(gomockinternal.AContext(), "my-rg", "my-node-routetable", "default-to-firewall").Return(fakeDefaultRoute, nil)
+			},
+		},
+		{
+			name:          "create firewall and default route",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+				m *mock_azurefirewalls.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewallSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().Return("westus")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().Return(infrav1.Tags{})
+				s.Vnet().Return(&infrav1.VnetSpec{Name: "my-vnet"})
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-azfw").Return(network.AzureFirewall{}, notFoundError)
+				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-azfw-pip").Return(network.PublicIPAddress{ID: to.StringPtr("my-azfw-pip-id")}, nil)
+				mSubnet.Get(gomockinternal.AContext(), "my-vnet-rg", "my-vnet", "AzureFirewallSubnet").Return(network.Subnet{ID: to.StringPtr("my-firewall-subnet-id")}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-vnet-rg", "my-azfw", gomockinternal.DiffEq(network.AzureFirewall{
+					Location: to.StringPtr("westus"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-azfw"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr("common"),
+					},
+					AzureFirewallPropertiesFormat: &network.AzureFirewallPropertiesFormat{
+						Sku: &network.AzureFirewallSku{
+							Name: network.AzureFirewallSkuNameAZFWVNet,
+							Tier: network.AzureFirewallSkuTierPremium,
+						},
+						IPConfigurations: &[]network.AzureFirewallIPConfiguration{
+							{
+								Name: to.StringPtr("my-azfw-ipconfig"),
+								AzureFirewallIPConfigurationPropertiesFormat: &network.AzureFirewallIPConfigurationPropertiesFormat{
+									Subnet:          &network.SubResource{ID: to.StringPtr("my-firewall-subnet-id")},
+									PublicIPAddress: &network.SubResource{ID: to.StringPtr("my-azfw-pip-id")},
+								},
+							},
+						},
+						FirewallPolicy: &network.SubResource{ID: to.StringPtr("my-policy-id")},
+					},
+				})).Return(fakeFirewall, nil)
+				m.GetRoute(gomockinternal.AContext(), "my-rg", "my-node-routetable", "default-to-firewall").Return(network.Route{}, notFoundError)
+				m.CreateOrUpdateRoute(gomockinternal.AContext(), "my-rg", "my-node-routetable", "default-to-firewall", gomockinternal.DiffEq(fakeDefaultRoute))
+			},
+		},
+		{
+			name:          "update outdated default route to existing firewall",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+				m *mock_azurefirewalls.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeExistingFirewallSpec)
+				s.Vnet().Return(&infrav1.VnetSpec{Name: "my-vnet"})
+				s.ClusterName().Return("my-cluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "hub-rg", "hub-azfw").Return(fakeFirewall, nil)
+				m.GetRoute(gomockinternal.AContext(), "my-rg", "my-node-routetable", "default-to-firewall").Return(network.Route{
+					RoutePropertiesFormat: &network.RoutePropertiesFormat{
+						AddressPrefix:    to.StringPtr("0.0.0.0/0"),
+						NextHopType:      network.RouteNextHopTypeVirtualAppliance,
+						NextHopIPAddress: to.StringPtr("10.255.255.196"),
+					},
+				}, nil)
+				m.CreateOrUpdateRoute(gomockinternal.AContext(), "my-rg", "my-node-routetable", "default-to-firewall", gomockinternal.DiffEq(fakeDefaultRoute))
+			},
+		},
+		{
+			name:          "skip default routes in custom vnet mode",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+				m *mock_azurefirewalls.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeExistingFirewallSpec)
+				s.Vnet().Return(&infrav1.VnetSpec{ID: "my-vnet-id", Name: "my-vnet"})
+				s.ClusterName().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "hub-rg", "hub-azfw").Return(fakeFirewall, nil)
+			},
+		},
+		{
+			name:          "existing firewall not found",
+			expectedError: "failed to get existing Azure Firewall hub-azfw in resource group hub-rg: #: Not Found: StatusCode=404",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+				m *mock_azurefirewalls.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeExistingFirewallSpec)
+				m.Get(gomockinternal.AContext(), "hub-rg", "hub-azfw").Return(network.AzureFirewall{}, notFoundError)
+			},
+		},
+		{
+			name:          "firewall without private IP address",
+			expectedError: "Azure Firewall hub-azfw has no private IP address yet",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+				m *mock_azurefirewalls.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeExistingFirewallSpec)
+				m.Get(gomockinternal.AContext(), "hub-rg", "hub-azfw").Return(network.AzureFirewall{Name: to.StringPtr("hub-azfw")}, nil)
+			},
+		},
+		{
+			name:          "fail to get firewall",
+			expectedError: "failed to get Azure Firewall my-azfw: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+				m *mock_azurefirewalls.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewallSpec)
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-azfw").Return(network.AzureFirewall{}, internalError)
+			},
+		},
+		{
+			name:          "fail to get public IP",
+			expectedError: "failed to get public IP for Azure Firewall: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+				m *mock_azurefirewalls.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewallSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-azfw").Return(network.AzureFirewall{}, notFoundError)
+				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-azfw-pip").Return(network.PublicIPAddress{}, internalError)
+			},
+		},
+		{
+			name:          "fail to create firewall",
+			expectedError: "failed to create Azure Firewall my-azfw in resource group my-vnet-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+				m *mock_azurefirewalls.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewallSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().Return("westus")
+				s.ClusterName().Return("my-cluster")
+				s.AdditionalTags().Return(infrav1.Tags{})
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-azfw").Return(network.AzureFirewall{}, notFoundError)
+				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-azfw-pip").Return(network.PublicIPAddress{ID: to.StringPtr("my-azfw-pip-id")}, nil)
+				mSubnet.Get(gomockinternal.AContext(), "my-vnet-rg", "my-vnet", "AzureFirewallSubnet").Return(network.Subnet{ID: to.StringPtr("my-firewall-subnet-id")}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-vnet-rg", "my-azfw", gomock.AssignableToTypeOf(network.AzureFirewall{})).Return(network.AzureFirewall{}, internalError)
+			},
+		},
+		{
+			name:          "fail to create default route",
+			expectedError: "failed to create default route of route table my-node-routetable in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder,
+				m *mock_azurefirewalls.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewallSpec)
+				s.Vnet().Return(&infrav1.VnetSpec{Name: "my-vnet"})
+				s.ClusterName().Return("my-cluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-azfw").Return(fakeFirewall, nil)
+				m.GetRoute(gomockinternal.AContext(), "my-rg", "my-node-routetable", "default-to-firewall").Return(network.Route{}, notFoundError)
+				m.CreateOrUpdateRoute(gomockinternal.AContext(), "my-rg", "my-node-routetable", "default-to-firewall", gomock.AssignableToTypeOf(network.Route{})).Return(internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_azurefirewalls.NewMockAzureFirewallScope(mockCtrl)
+			clientMock := mock_azurefirewalls.NewMockclient(mockCtrl)
+			subnetMock := mock_subnets.NewMockClient(mockCtrl)
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(),
+				subnetMock.EXPECT(), publicIPsMock.EXPECT())
+
+			s := &Service{
+				Scope:           scopeMock,
+				client:          clientMock,
+				subnetsClient:   subnetMock,
+				publicIPsClient: publicIPsMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteAzureFirewalls(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, m *mock_azurefirewalls.MockclientMockRecorder)
+	}{
+		{
+			name:          "no firewall to delete",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, m *mock_azurefirewalls.MockclientMockRecorder) {
+				s.AzureFirewallSpec().Return(nil)
+			},
+		},
+		{
+			name:          "existing firewall is not deleted",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, m *mock_azurefirewalls.MockclientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeExistingFirewallSpec)
+			},
+		},
+		{
+			name:          "firewall already deleted",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, m *mock_azurefirewalls.MockclientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewallSpec)
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-azfw").Return(network.AzureFirewall{}, notFoundError)
+			},
+		},
+		{
+			name:          "skip unmanaged firewall",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, m *mock_azurefirewalls.MockclientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewallSpec)
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-azfw").Return(network.AzureFirewall{Name: to.StringPtr("my-azfw")}, nil)
+			},
+		},
+		{
+			name:          "delete managed firewall",
+			expectedError: "",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, m *mock_azurefirewalls.MockclientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewallSpec)
+				s.ClusterName().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-azfw").Return(network.AzureFirewall{
+					Name: to.StringPtr("my-azfw"),
+					Tags: map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned")},
+				}, nil)
+				m.Delete(gomockinternal.AContext(), "my-vnet-rg", "my-azfw")
+			},
+		},
+		{
+			name:          "fail to delete firewall",
+			expectedError: "failed to delete Azure Firewall my-azfw in resource group my-vnet-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_azurefirewalls.MockAzureFirewallScopeMockRecorder, m *mock_azurefirewalls.MockclientMockRecorder) {
+				s.AzureFirewallSpec().Return(&fakeFirewallSpec)
+				s.ClusterName().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-azfw").Return(network.AzureFirewall{
+					Name: to.StringPtr("my-azfw"),
+					Tags: map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned")},
+				}, nil)
+				m.Delete(gomockinternal.AContext(), "my-vnet-rg", "my-azfw").Return(internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_azurefirewalls.NewMockAzureFirewallScope(mockCtrl)
+			clientMock := mock_azurefirewalls.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azurefirewalls
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, string, string) (network.AzureFirewall, error)
+	CreateOrUpdate(context.Context, string, string, network.AzureFirewall) (network.AzureFirewall, error)
+	Delete(context.Context, string, string) error
+	GetRoute(context.Context, string, string, string) (network.Route, error)
+	CreateOrUpdateRoute(context.Context, string, string, string, network.Route) error
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	azurefirewalls network.AzureFirewallsClient
+	routes         network.RoutesClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new Azure Firewall client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	return &azureClient{
+		azurefirewalls: newAzureFirewallsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		routes:         newRoutesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newAzureFirewallsClient creates a new Azure Firewalls client from subscription ID.
+func newAzureFirewallsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.AzureFirewallsClient {
+	firewallsClient := network.NewAzureFirewallsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&firewallsClient.Client, authorizer, "azurefirewalls")
+	return firewallsClient
+}
+
+// newRoutesClient creates a new routes client from subscription ID.
+func newRoutesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.RoutesClient {
+	routesClient := network.NewRoutesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&routesClient.Client, authorizer, "azurefirewalls")
+	return routesClient
+}
+
+// Get gets the specified Azure Firewall.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, firewallName string) (_ network.AzureFirewall, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "azurefirewalls.AzureClient.Get")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.azurefirewalls.Get(ctx, resourceGroupName, firewallName)
+}
+
+// CreateOrUpdate creates or updates an Azure Firewall in the specified resource group, and returns it with the private
+// IP address it was allocated.
+func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, firewallName string, firewall network.AzureFirewall) (_ network.AzureFirewall, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "azurefirewalls.AzureClient.CreateOrUpdate")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.azurefirewalls.CreateOrUpdate(ctx, resourceGroupName, firewallName, firewall)
+	if err != nil {
+		return network.AzureFirewall{}, err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.azurefirewalls.Client)
+	if err != nil {
+		return network.AzureFirewall{}, err
+	}
+	return future.Result(ac.azurefirewalls)
+}
+
+// Delete deletes the specified Azure Firewall.
+func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, firewallName string) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "azurefirewalls.AzureClient.Delete")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.azurefirewalls.Delete(ctx, resourceGroupName, firewallName)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.azurefirewalls.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.azurefirewalls)
+	return err
+}
+
+// GetRoute gets the specified route of a route table.
+func (ac *azureClient) GetRoute(ctx context.Context, resourceGroupName, routeTableName, routeName string) (_ network.Route, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "azurefirewalls.AzureClient.GetRoute")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.routes.Get(ctx, resourceGroupName, routeTableName, routeName)
+}
+
+// CreateOrUpdateRoute creates or updates a route of a route table.
+func (ac *azureClient) CreateOrUpdateRoute(ctx context.Context, resourceGroupName, routeTableName, routeName string, route network.Route) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "azurefirewalls.AzureClient.CreateOrUpdateRoute")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.routes.CreateOrUpdate(ctx, resourceGroupName, routeTableName, routeName, route)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.routes.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.routes)
+	return err
+}
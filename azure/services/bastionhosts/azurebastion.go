@@ -21,7 +21,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-03-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -49,6 +49,10 @@ func (s *Service) ensureAzureBastion(ctx context.Context, azureBastionSpec azure
 
 	log.V(2).Info("creating bastion host", "bastion", azureBastionSpec.Name)
 	bastionHostIPConfigName := fmt.Sprintf("%s-%s", azureBastionSpec.Name, "bastionIP")
+	sku := network.BastionHostSkuNameBasic
+	if azureBastionSpec.Sku != "" {
+		sku = network.BastionHostSkuName(azureBastionSpec.Sku)
+	}
 	err = s.client.CreateOrUpdate(
 		ctx,
 		s.Scope.ResourceGroup(),
@@ -62,8 +66,14 @@ func (s *Service) ensureAzureBastion(ctx context.Context, azureBastionSpec azure
 				Name:        to.StringPtr(azureBastionSpec.Name),
 				Role:        to.StringPtr("Bastion"),
 			})),
+			Sku: &network.Sku{
+				Name: sku,
+			},
 			BastionHostPropertiesFormat: &network.BastionHostPropertiesFormat{
-				DNSName: to.StringPtr(fmt.Sprintf("%s-bastion", strings.ToLower(azureBastionSpec.Name))),
+				DNSName:         to.StringPtr(fmt.Sprintf("%s-bastion", strings.ToLower(azureBastionSpec.Name))),
+				ScaleUnits:      azureBastionSpec.ScaleUnits,
+				EnableTunneling: to.BoolPtr(azureBastionSpec.EnableTunneling),
+				EnableIPConnect: to.BoolPtr(azureBastionSpec.EnableIPConnect),
 				IPConfigurations: &[]network.BastionHostIPConfiguration{
 					{
 						Name: to.StringPtr(bastionHostIPConfigName),
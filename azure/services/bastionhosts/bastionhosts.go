@@ -29,7 +29,6 @@ import (
 // BastionScope defines the scope interface for a bastion host service.
 type BastionScope interface {
 	azure.ClusterDescriber
-	azure.NetworkDescriber
 	BastionSpec() azure.BastionSpec
 }
 
@@ -21,8 +21,10 @@ import (
 	"net/http"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	networkv20210201 "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-03-01/network"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -66,7 +68,7 @@ func TestReconcileBastionHosts(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
-				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(networkv20210201.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
 		{
@@ -88,9 +90,9 @@ func TestReconcileBastionHosts(t *testing.T) {
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				gomock.InOrder(
-					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil),
+					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(networkv20210201.PublicIPAddress{}, nil),
 					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").
-						Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")),
+						Return(networkv20210201.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")),
 				)
 			},
 		},
@@ -115,8 +117,8 @@ func TestReconcileBastionHosts(t *testing.T) {
 				s.Location().AnyTimes().Return("fake-location")
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				gomock.InOrder(
-					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil),
-					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
+					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(networkv20210201.PublicIPAddress{}, nil),
+					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(networkv20210201.Subnet{}, nil),
 					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-bastion", gomock.AssignableToTypeOf(network.BastionHost{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")),
 				)
 			},
@@ -142,12 +144,69 @@ func TestReconcileBastionHosts(t *testing.T) {
 				s.Location().AnyTimes().Return("fake-location")
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				gomock.InOrder(
-					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil),
-					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
+					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(networkv20210201.PublicIPAddress{}, nil),
+					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(networkv20210201.Subnet{}, nil),
 					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-bastion", gomock.AssignableToTypeOf(network.BastionHost{})),
 				)
 			},
 		},
+		{
+			name:          "standard bastion successfully created",
+			expectedError: "",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
+				m *mock_bastionhosts.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.BastionSpec().Return(azure.BastionSpec{
+					AzureBastion: &azure.AzureBastionSpec{
+						Name:     "my-bastion",
+						VNetName: "my-vnet",
+						SubnetSpec: v1beta1.SubnetSpec{
+							Name: "my-subnet",
+						},
+						PublicIPName:    "my-publicip",
+						Sku:             v1beta1.BastionHostSkuStandard,
+						ScaleUnits:      to.Int32Ptr(4),
+						EnableTunneling: true,
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("fake-location")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				gomock.InOrder(
+					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(networkv20210201.PublicIPAddress{ID: to.StringPtr("my-publicip-id")}, nil),
+					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(networkv20210201.Subnet{ID: to.StringPtr("my-subnet-id")}, nil),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-bastion", gomockinternal.DiffEq(network.BastionHost{
+						Name:     to.StringPtr("my-bastion"),
+						Location: to.StringPtr("fake-location"),
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("Bastion"),
+							"Name": to.StringPtr("my-bastion"),
+						},
+						Sku: &network.Sku{
+							Name: network.BastionHostSkuNameStandard,
+						},
+						BastionHostPropertiesFormat: &network.BastionHostPropertiesFormat{
+							DNSName:         to.StringPtr("my-bastion-bastion"),
+							ScaleUnits:      to.Int32Ptr(4),
+							EnableTunneling: to.BoolPtr(true),
+							EnableIPConnect: to.BoolPtr(false),
+							IPConfigurations: &[]network.BastionHostIPConfiguration{
+								{
+									Name: to.StringPtr("my-bastion-bastionIP"),
+									BastionHostIPConfigurationPropertiesFormat: &network.BastionHostIPConfigurationPropertiesFormat{
+										Subnet:                    &network.SubResource{ID: to.StringPtr("my-subnet-id")},
+										PublicIPAddress:           &network.SubResource{ID: to.StringPtr("my-publicip-id")},
+										PrivateIPAllocationMethod: network.IPAllocationMethodDynamic,
+									},
+								},
+							},
+						},
+					})),
+				)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
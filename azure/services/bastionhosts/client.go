@@ -19,10 +19,12 @@ package bastionhosts
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	// Bastion hosts use a newer API version than the other network services for the features of the Standard SKU.
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-03-01/network"
 	"github.com/Azure/go-autorest/autorest"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -49,22 +51,24 @@ func newClient(auth azure.Authorizer) *azureClient {
 // newBastionHostsClient creates a new bastion host client from subscription ID.
 func newBastionHostsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.BastionHostsClient {
 	bastionClient := network.NewBastionHostsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&bastionClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&bastionClient.Client, authorizer, "bastionhosts")
 	return bastionClient
 }
 
 // Get gets information about the specified bastion host.
-func (ac *azureClient) Get(ctx context.Context, resourceGroupName, bastionName string) (network.BastionHost, error) {
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, bastionName string) (_ network.BastionHost, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "bastionhosts.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.interfaces.Get(ctx, resourceGroupName, bastionName)
 }
 
 // CreateOrUpdate creates or updates a bastion host.
-func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, bastionName string, bastionHost network.BastionHost) error {
+func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, bastionName string, bastionHost network.BastionHost) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "bastionhosts.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.interfaces.CreateOrUpdate(ctx, resourceGroupName, bastionName, bastionHost)
 	if err != nil {
@@ -79,9 +83,10 @@ func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName str
 }
 
 // Delete deletes the specified network interface.
-func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, bastionName string) error {
+func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, bastionName string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "bastionhosts.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.interfaces.Delete(ctx, resourceGroupName, bastionName)
 	if err != nil {
@@ -24,7 +24,7 @@ import (
 	context "context"
 	reflect "reflect"
 
-	network "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	network "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-03-01/network"
 	gomock "github.com/golang/mock/gomock"
 )
 
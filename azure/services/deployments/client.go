@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployments
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, string, string) (resources.DeploymentExtended, error)
+	CreateOrUpdate(context.Context, string, string, resources.Deployment) error
+	GetVirtualNetwork(context.Context, string, string) (network.VirtualNetwork, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	deployments     resources.DeploymentsClient
+	virtualnetworks network.VirtualNetworksClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new deployments client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	return &azureClient{
+		deployments:     newDeploymentsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		virtualnetworks: newVirtualNetworksClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newDeploymentsClient creates a new deployments client from subscription ID.
+func newDeploymentsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resources.DeploymentsClient {
+	deploymentsClient := resources.NewDeploymentsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&deploymentsClient.Client, authorizer, "deployments")
+	return deploymentsClient
+}
+
+// newVirtualNetworksClient creates a new virtual networks client from subscription ID.
+func newVirtualNetworksClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.VirtualNetworksClient {
+	vnetsClient := network.NewVirtualNetworksClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&vnetsClient.Client, authorizer, "deployments")
+	return vnetsClient
+}
+
+// Get gets a deployment of a resource group.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, deploymentName string) (_ resources.DeploymentExtended, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "deployments.AzureClient.Get")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.deployments.Get(ctx, resourceGroupName, deploymentName)
+}
+
+// CreateOrUpdate starts a deployment in a resource group. It doesn't wait for the deployment, whose progress is
+// reported by its provisioning state.
+func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, deploymentName string, deployment resources.Deployment) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "deployments.AzureClient.CreateOrUpdate")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.deployments.CreateOrUpdate(ctx, resourceGroupName, deploymentName, deployment)
+	return err
+}
+
+// GetVirtualNetwork gets a virtual network.
+func (ac *azureClient) GetVirtualNetwork(ctx context.Context, resourceGroupName, vnetName string) (_ network.VirtualNetwork, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "deployments.AzureClient.GetVirtualNetwork")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.virtualnetworks.Get(ctx, resourceGroupName, vnetName, "")
+}
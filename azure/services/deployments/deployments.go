@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployments
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// deployingRequeue is how long to wait between checks of a deployment in progress.
+const deployingRequeue = 15 * time.Second
+
+// NetworkDeploymentScope defines the scope interface for a network deployment service.
+type NetworkDeploymentScope interface {
+	azure.ClusterDescriber
+	NetworkDeploymentSpec() *azure.NetworkDeploymentSpec
+	UpdateNetworkDeploymentStatus(reason string, err error)
+}
+
+// Service provisions the initial network of a cluster with a single ARM deployment, instead of the sequential requests
+// of the network services which reconcile the deployed resources afterwards.
+type Service struct {
+	Scope NetworkDeploymentScope
+	client
+}
+
+// New creates a new network deployment service.
+func New(scope NetworkDeploymentScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile starts the deployment of the initial network of the cluster and waits for it. A deployment in progress
+// returns a transient error so that the network services only run once it is over. A failed deployment is reported in
+// a condition, and the network services create the resources it didn't.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "deployments.Service.Reconcile")
+	defer done()
+
+	spec := s.Scope.NetworkDeploymentSpec()
+	if spec == nil {
+		return nil
+	}
+
+	deployment, err := s.client.Get(ctx, spec.ResourceGroup, spec.Name)
+	switch {
+	case err != nil && !azure.ResourceNotFound(err):
+		err = errors.Wrapf(err, "failed to get deployment %s", spec.Name)
+		s.Scope.UpdateNetworkDeploymentStatus(infrav1.NetworkDeploymentFailedReason, err)
+		return err
+
+	case err != nil:
+		// a virtual network which already exists is left to the network services, it wasn't created by a deployment.
+		if _, err := s.client.GetVirtualNetwork(ctx, spec.ResourceGroup, spec.VNet.Name); err == nil {
+			log.V(2).Info("virtual network already exists, skipping deployment", "vnet", spec.VNet.Name)
+			return nil
+		} else if !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to get virtual network %s", spec.VNet.Name)
+		}
+
+		template, err := networkTemplate(s.Scope, *spec)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to generate template of deployment %s", spec.Name)
+			s.Scope.UpdateNetworkDeploymentStatus(infrav1.NetworkDeploymentFailedReason, err)
+			return nil
+		}
+
+		log.V(2).Info("starting deployment", "deployment", spec.Name, "resource group", spec.ResourceGroup)
+		if err := s.client.CreateOrUpdate(ctx, spec.ResourceGroup, spec.Name, resources.Deployment{
+			Properties: &resources.DeploymentProperties{
+				Template: template,
+				Mode:     resources.Incremental,
+			},
+		}); err != nil {
+			err = errors.Wrapf(err, "failed to start deployment %s", spec.Name)
+			s.Scope.UpdateNetworkDeploymentStatus(infrav1.NetworkDeploymentFailedReason, err)
+			return nil
+		}
+		err = azure.WithTransientError(errors.Errorf("deploying network of cluster %s", s.Scope.ClusterName()), deployingRequeue)
+		s.Scope.UpdateNetworkDeploymentStatus(infrav1.NetworkDeployingReason, err)
+		return err
+	}
+
+	var state string
+	if deployment.Properties != nil {
+		state = to.String(deployment.Properties.ProvisioningState)
+	}
+	switch infrav1.ProvisioningState(state) {
+	case infrav1.Succeeded:
+		log.V(2).Info("successfully deployed network", "deployment", spec.Name)
+		s.Scope.UpdateNetworkDeploymentStatus("", nil)
+		return nil
+	case infrav1.Failed, infrav1.Canceled:
+		// the network services create the resources which weren't deployed.
+		err := errors.Errorf("deployment %s is %s: %s", spec.Name, state, deploymentError(deployment))
+		log.Info("failed to deploy network, falling back to per-resource reconciliation", "deployment", spec.Name, "error", err.Error())
+		s.Scope.UpdateNetworkDeploymentStatus(infrav1.NetworkDeploymentFailedReason, err)
+		return nil
+	default:
+		err := azure.WithTransientError(errors.Errorf("deploying network of cluster %s", s.Scope.ClusterName()), deployingRequeue)
+		s.Scope.UpdateNetworkDeploymentStatus(infrav1.NetworkDeployingReason, err)
+		return err
+	}
+}
+
+// Delete is a no-op, the deployed resources are deleted by the network services.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// deploymentError returns the error reported by a deployment.
+func deploymentError(deployment resources.DeploymentExtended) string {
+	if deployment.Properties == nil || deployment.Properties.Error == nil {
+		return "unknown error"
+	}
+	return to.String(deployment.Properties.Error.Message)
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployments
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/deployments/mock_deployments"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeSpec = azure.NetworkDeploymentSpec{
+		Name:          "my-cluster-network",
+		ResourceGroup: "my-rg",
+		VNet: azure.VNetSpec{
+			ResourceGroup: "my-rg",
+			Name:          "my-vnet",
+			CIDRs:         []string{"10.0.0.0/8"},
+		},
+		SecurityGroups: []azure.NSGSpec{{Name: "my-nsg"}},
+		RouteTables:    []azure.RouteTableSpec{{Name: "my-rt"}},
+		Subnets: []azure.SubnetSpec{
+			{Name: "cp-subnet", CIDRs: []string{"10.0.0.0/16"}, VNetName: "my-vnet", SecurityGroupName: "my-nsg"},
+			{Name: "node-subnet", CIDRs: []string{"10.1.0.0/16"}, VNetName: "my-vnet", SecurityGroupName: "my-nsg", RouteTableName: "my-rt"},
+		},
+	}
+	notFoundErr = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not Found")
+	internalErr = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func deploymentWithState(state string) resources.DeploymentExtended {
+	return resources.DeploymentExtended{
+		Properties: &resources.DeploymentPropertiesExtended{
+			ProvisioningState: to.StringPtr(state),
+		},
+	}
+}
+
+func TestReconcileNetworkDeployment(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_deployments.MockNetworkDeploymentScopeMockRecorder, m *mock_deployments.MockclientMockRecorder)
+	}{
+		{
+			name:          "no deployment",
+			expectedError: "",
+			expect: func(s *mock_deployments.MockNetworkDeploymentScopeMockRecorder, m *mock_deployments.MockclientMockRecorder) {
+				s.NetworkDeploymentSpec().Return(nil)
+			},
+		},
+		{
+			name:          "start the deployment",
+			expectedError: "deploying network of cluster my-cluster. Object will be requeued after 15s",
+			expect: func(s *mock_deployments.MockNetworkDeploymentScopeMockRecorder, m *mock_deployments.MockclientMockRecorder) {
+				s.NetworkDeploymentSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster-network").Return(resources.DeploymentExtended{}, notFoundErr)
+				m.GetVirtualNetwork(gomockinternal.AContext(), "my-rg", "my-vnet").Return(network.VirtualNetwork{}, notFoundErr)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster-network", gomock.Any())
+				s.UpdateNetworkDeploymentStatus(infrav1.NetworkDeployingReason, gomock.Any())
+			},
+		},
+		{
+			name:          "skip the deployment of an existing virtual network",
+			expectedError: "",
+			expect: func(s *mock_deployments.MockNetworkDeploymentScopeMockRecorder, m *mock_deployments.MockclientMockRecorder) {
+				s.NetworkDeploymentSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster-network").Return(resources.DeploymentExtended{}, notFoundErr)
+				m.GetVirtualNetwork(gomockinternal.AContext(), "my-rg", "my-vnet").Return(network.VirtualNetwork{}, nil)
+			},
+		},
+		{
+			name:          "fall back to the network services when the deployment can't be started",
+			expectedError: "",
+			expect: func(s *mock_deployments.MockNetworkDeploymentScopeMockRecorder, m *mock_deployments.MockclientMockRecorder) {
+				s.NetworkDeploymentSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster-network").Return(resources.DeploymentExtended{}, notFoundErr)
+				m.GetVirtualNetwork(gomockinternal.AContext(), "my-rg", "my-vnet").Return(network.VirtualNetwork{}, notFoundErr)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster-network", gomock.Any()).Return(internalErr)
+				s.UpdateNetworkDeploymentStatus(infrav1.NetworkDeploymentFailedReason, gomock.Any())
+			},
+		},
+		{
+			name:          "wait for the deployment in progress",
+			expectedError: "deploying network of cluster my-cluster. Object will be requeued after 15s",
+			expect: func(s *mock_deployments.MockNetworkDeploymentScopeMockRecorder, m *mock_deployments.MockclientMockRecorder) {
+				s.NetworkDeploymentSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster-network").Return(deploymentWithState("Running"), nil)
+				s.UpdateNetworkDeploymentStatus(infrav1.NetworkDeployingReason, gomock.Any())
+			},
+		},
+		{
+			name:          "deployment succeeded",
+			expectedError: "",
+			expect: func(s *mock_deployments.MockNetworkDeploymentScopeMockRecorder, m *mock_deployments.MockclientMockRecorder) {
+				s.NetworkDeploymentSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster-network").Return(deploymentWithState("Succeeded"), nil)
+				s.UpdateNetworkDeploymentStatus("", nil)
+			},
+		},
+		{
+			name:          "fall back to the network services when the deployment failed",
+			expectedError: "",
+			expect: func(s *mock_deployments.MockNetworkDeploymentScopeMockRecorder, m *mock_deployments.MockclientMockRecorder) {
+				s.NetworkDeploymentSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster-network").Return(deploymentWithState("Failed"), nil)
+				s.UpdateNetworkDeploymentStatus(infrav1.NetworkDeploymentFailedReason, gomock.Any())
+			},
+		},
+		{
+			name:          "fail to get deployment",
+			expectedError: "failed to get deployment my-cluster-network: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_deployments.MockNetworkDeploymentScopeMockRecorder, m *mock_deployments.MockclientMockRecorder) {
+				s.NetworkDeploymentSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster-network").Return(resources.DeploymentExtended{}, internalErr)
+				s.UpdateNetworkDeploymentStatus(infrav1.NetworkDeploymentFailedReason, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_deployments.NewMockNetworkDeploymentScope(mockCtrl)
+			clientMock := mock_deployments.NewMockclient(mockCtrl)
+
+			scopeMock.EXPECT().SubscriptionID().AnyTimes().Return("123")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			scopeMock.EXPECT().Location().AnyTimes().Return("westus")
+			scopeMock.EXPECT().AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestNetworkTemplate(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_deployments.NewMockNetworkDeploymentScope(mockCtrl)
+	scopeMock.EXPECT().SubscriptionID().AnyTimes().Return("123")
+	scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+	scopeMock.EXPECT().Location().AnyTimes().Return("westus")
+	scopeMock.EXPECT().AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+
+	template, err := networkTemplate(scopeMock, fakeSpec)
+	g.Expect(err).NotTo(HaveOccurred())
+	resources := template["resources"].([]interface{})
+	g.Expect(resources).To(HaveLen(5))
+
+	names := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		names = append(names, resource.(map[string]interface{})["name"].(string))
+	}
+	g.Expect(names).To(Equal([]string{"my-vnet", "my-nsg", "my-rt", "my-vnet/cp-subnet", "my-vnet/node-subnet"}))
+
+	// subnets are deployed one after the other, once their security group and route table exist.
+	g.Expect(resources[4].(map[string]interface{})["dependsOn"]).To(Equal([]string{
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/cp-subnet",
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-nsg",
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-rt",
+	}))
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployments
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
+)
+
+const (
+	// deploymentTemplateSchema is the schema of the templates of resource group deployments.
+	deploymentTemplateSchema = "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#"
+	// networkAPIVersion is the API version of the network resources of the templates, the one used by the services
+	// reconciling them afterwards.
+	networkAPIVersion = "2021-02-01"
+)
+
+// networkTemplate generates the ARM template of the network of a deployment spec. Its resources have the same
+// properties as the ones the network services create, so that the services find them up to date once deployed.
+func networkTemplate(scope NetworkDeploymentScope, spec azure.NetworkDeploymentSpec) (map[string]interface{}, error) {
+	var resources []interface{}
+	add := func(resourceType, name string, body interface{}, dependsOn []string) error {
+		resource, err := templateResource(resourceType, name, body, dependsOn)
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate template resource %s", name)
+		}
+		resources = append(resources, resource)
+		return nil
+	}
+
+	vnet := virtualnetworks.Parameters(spec.VNet, scope.ClusterName(), scope.Location(), scope.AdditionalTags())
+	if err := add("Microsoft.Network/virtualNetworks", spec.VNet.Name, vnet, nil); err != nil {
+		return nil, err
+	}
+
+	for _, nsgSpec := range spec.SecurityGroups {
+		securityRules := make([]network.SecurityRule, 0, len(nsgSpec.SecurityRules))
+		for _, rule := range nsgSpec.SecurityRules {
+			securityRules = append(securityRules, converters.SecurityRuleToSDK(rule))
+		}
+		nsg := network.SecurityGroup{
+			Location: to.StringPtr(scope.Location()),
+			SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+				SecurityRules: &securityRules,
+			},
+		}
+		if err := add("Microsoft.Network/networkSecurityGroups", nsgSpec.Name, nsg, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, routeTableSpec := range spec.RouteTables {
+		routeTable := network.RouteTable{
+			Location: to.StringPtr(scope.Location()),
+			RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+				DisableBgpRoutePropagation: to.BoolPtr(routeTableSpec.DisableBGPRoutePropagation),
+			},
+		}
+		if err := add("Microsoft.Network/routeTables", routeTableSpec.Name, routeTable, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// The subnets of a virtual network can't be updated concurrently, so each subnet waits for the previous one.
+	previousSubnetID := azure.VNetID(scope.SubscriptionID(), spec.ResourceGroup, spec.VNet.Name)
+	for _, subnetSpec := range spec.Subnets {
+		dependsOn := []string{previousSubnetID}
+		if subnetSpec.SecurityGroupName != "" {
+			dependsOn = append(dependsOn, azure.SecurityGroupID(scope.SubscriptionID(), spec.ResourceGroup, subnetSpec.SecurityGroupName))
+		}
+		if subnetSpec.RouteTableName != "" {
+			dependsOn = append(dependsOn, azure.RouteTableID(scope.SubscriptionID(), spec.ResourceGroup, subnetSpec.RouteTableName))
+		}
+		subnet := subnets.Parameters(scope.SubscriptionID(), spec.ResourceGroup, subnetSpec)
+		name := fmt.Sprintf("%s/%s", spec.VNet.Name, subnetSpec.Name)
+		if err := add("Microsoft.Network/virtualNetworks/subnets", name, subnet, dependsOn); err != nil {
+			return nil, err
+		}
+		previousSubnetID = azure.SubnetID(scope.SubscriptionID(), spec.ResourceGroup, spec.VNet.Name, subnetSpec.Name)
+	}
+
+	return map[string]interface{}{
+		"$schema":        deploymentTemplateSchema,
+		"contentVersion": "1.0.0.0",
+		"resources":      resources,
+	}, nil
+}
+
+// templateResource returns the template resource of an SDK resource, whose JSON representation is the one of the
+// properties of the resource in templates.
+func templateResource(resourceType, name string, body interface{}, dependsOn []string) (map[string]interface{}, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	resource := map[string]interface{}{}
+	if err := json.Unmarshal(b, &resource); err != nil {
+		return nil, err
+	}
+	resource["type"] = resourceType
+	resource["apiVersion"] = networkAPIVersion
+	resource["name"] = name
+	if len(dependsOn) > 0 {
+		resource["dependsOn"] = dependsOn
+	}
+	return resource, nil
+}
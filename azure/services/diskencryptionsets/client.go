@@ -0,0 +1,258 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskencryptionsets
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/mgmt/2019-09-01/keyvault"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	Get(context.Context, string, string) (compute.DiskEncryptionSet, error)
+	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter) (interface{}, azureautorest.FutureAPI, error)
+	DeleteAsync(context.Context, azure.ResourceSpecGetter) (azureautorest.FutureAPI, error)
+	IsDone(context.Context, azureautorest.FutureAPI) (bool, error)
+	Result(context.Context, azureautorest.FutureAPI, string) (interface{}, error)
+	GetVault(context.Context, string, string) (keyvault.Vault, error)
+	UpdateAccessPolicy(context.Context, string, string, keyvault.AccessPolicyUpdateKind, keyvault.VaultAccessPolicyParameters) error
+	CreateRoleAssignment(context.Context, string, string, authorization.RoleAssignmentCreateParameters) error
+	DeleteRoleAssignment(context.Context, string, string) error
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	diskencryptionsets compute.DiskEncryptionSetsClient
+	vaults             keyvault.VaultsClient
+	roleassignments    authorization.RoleAssignmentsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new disk encryption sets client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		diskencryptionsets: newDiskEncryptionSetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		vaults:             newVaultsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		roleassignments:    newRoleAssignmentsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newDiskEncryptionSetsClient creates a new disk encryption sets client from subscription ID.
+func newDiskEncryptionSetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.DiskEncryptionSetsClient {
+	diskEncryptionSetsClient := compute.NewDiskEncryptionSetsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&diskEncryptionSetsClient.Client, authorizer, serviceName)
+	return diskEncryptionSetsClient
+}
+
+// newVaultsClient creates a new key vaults client from subscription ID.
+func newVaultsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) keyvault.VaultsClient {
+	vaultsClient := keyvault.NewVaultsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&vaultsClient.Client, authorizer, serviceName)
+	return vaultsClient
+}
+
+// newRoleAssignmentsClient creates a new role assignments client from subscription ID.
+func newRoleAssignmentsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) authorization.RoleAssignmentsClient {
+	roleAssignmentsClient := authorization.NewRoleAssignmentsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&roleAssignmentsClient.Client, authorizer, serviceName)
+	return roleAssignmentsClient
+}
+
+// Get gets the specified disk encryption set by the disk encryption set name and resource group.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, diskEncryptionSetName string) (_ compute.DiskEncryptionSet, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "diskencryptionsets.AzureClient.Get")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	return ac.diskencryptionsets.Get(ctx, resourceGroupName, diskEncryptionSetName)
+}
+
+// CreateOrUpdateAsync creates or updates a disk encryption set asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ interface{}, _ azureautorest.FutureAPI, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "diskencryptionsets.AzureClient.CreateOrUpdateAsync")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	var existingDiskEncryptionSet interface{}
+
+	if existing, err := ac.Get(ctx, spec.ResourceGroupName(), spec.ResourceName()); err != nil && !azure.ResourceNotFound(err) {
+		return nil, nil, errors.Wrapf(err, "failed to get disk encryption set %s in %s", spec.ResourceName(), spec.ResourceGroupName())
+	} else if err == nil {
+		existingDiskEncryptionSet = existing
+	}
+
+	params, err := spec.Parameters(existingDiskEncryptionSet)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get desired parameters for disk encryption set %s", spec.ResourceName())
+	}
+
+	diskEncryptionSet, ok := params.(compute.DiskEncryptionSet)
+	if !ok {
+		if params == nil {
+			// nothing to do here.
+			return existingDiskEncryptionSet, nil, nil
+		}
+		return nil, nil, errors.Errorf("%T is not a compute.DiskEncryptionSet", params)
+	}
+
+	future, err := ac.diskencryptionsets.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), diskEncryptionSet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = future.WaitForCompletionRef(ctx, ac.diskencryptionsets.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, &future, err
+	}
+
+	result, err := future.Result(ac.diskencryptionsets)
+	// if the operation completed, return a nil future
+	return result, nil, err
+}
+
+// DeleteAsync deletes a disk encryption set asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ azureautorest.FutureAPI, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "diskencryptionsets.AzureClient.Delete")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.diskencryptionsets.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = future.WaitForCompletionRef(ctx, ac.diskencryptionsets.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return &future, err
+	}
+	_, err = future.Result(ac.diskencryptionsets)
+	// if the operation completed, return a nil future.
+	return nil, err
+}
+
+// IsDone returns true if the long-running operation has completed.
+func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (_ bool, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "diskencryptionsets.AzureClient.IsDone")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	done, err := future.DoneWithContext(ctx, ac.diskencryptionsets)
+	if err != nil {
+		return false, errors.Wrap(err, "failed checking if the operation was complete")
+	}
+
+	return done, nil
+}
+
+// Result fetches the result of a long-running operation future.
+func (ac *AzureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (_ interface{}, err error) {
+	defer azureerrors.Classify(&err)
+
+	if futureData == nil {
+		return nil, errors.Errorf("cannot get result from nil future")
+	}
+	var result func(client compute.DiskEncryptionSetsClient) (diskEncryptionSet compute.DiskEncryptionSet, err error)
+
+	switch futureType {
+	case infrav1.PutFuture:
+		var future *compute.DiskEncryptionSetsCreateOrUpdateFuture
+		jsonData, err := futureData.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal future")
+		}
+		if err := json.Unmarshal(jsonData, &future); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal future data")
+		}
+		result = (*future).Result
+
+	case infrav1.DeleteFuture:
+		// Delete does not return a result disk encryption set
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("unknown future type %q", futureType)
+	}
+
+	return result(ac.diskencryptionsets)
+}
+
+// GetVault gets the specified key vault by the key vault name and resource group.
+func (ac *AzureClient) GetVault(ctx context.Context, resourceGroupName, vaultName string) (_ keyvault.Vault, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "diskencryptionsets.AzureClient.GetVault")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	return ac.vaults.Get(ctx, resourceGroupName, vaultName)
+}
+
+// UpdateAccessPolicy adds, replaces or removes access policies of a key vault.
+func (ac *AzureClient) UpdateAccessPolicy(ctx context.Context, resourceGroupName, vaultName string, kind keyvault.AccessPolicyUpdateKind, parameters keyvault.VaultAccessPolicyParameters) (err error) {
+	ctx, span := tele.Tracer().Start(ctx, "diskencryptionsets.AzureClient.UpdateAccessPolicy")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.vaults.UpdateAccessPolicy(ctx, resourceGroupName, vaultName, kind, parameters)
+	return err
+}
+
+// CreateRoleAssignment creates a role assignment at the given scope.
+func (ac *AzureClient) CreateRoleAssignment(ctx context.Context, scope, roleAssignmentName string, parameters authorization.RoleAssignmentCreateParameters) (err error) {
+	ctx, span := tele.Tracer().Start(ctx, "diskencryptionsets.AzureClient.CreateRoleAssignment")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.roleassignments.Create(ctx, scope, roleAssignmentName, parameters)
+	return err
+}
+
+// DeleteRoleAssignment deletes a role assignment at the given scope.
+func (ac *AzureClient) DeleteRoleAssignment(ctx context.Context, scope, roleAssignmentName string) (err error) {
+	ctx, span := tele.Tracer().Start(ctx, "diskencryptionsets.AzureClient.DeleteRoleAssignment")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.roleassignments.Delete(ctx, scope, roleAssignmentName)
+	return err
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskencryptionsets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/mgmt/2019-09-01/keyvault"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	serviceName = "diskencryptionsets"
+	// keyVaultCryptoServiceEncryptionUserID is the ID of the "Key Vault Crypto Service Encryption User" built-in role,
+	// which allows to wrap and unwrap keys of key vaults using the Azure RBAC permission model.
+	keyVaultCryptoServiceEncryptionUserID = "e147488a-f6f5-4113-8e2d-b22465e65bf6"
+)
+
+// keyPermissions are the key permissions granted to a disk encryption set by the access policy of a key vault.
+var keyPermissions = []keyvault.KeyPermissions{keyvault.KeyPermissionsGet, keyvault.KeyPermissionsWrapKey, keyvault.KeyPermissionsUnwrapKey}
+
+// DiskEncryptionSetScope defines the scope interface for a disk encryption sets service.
+type DiskEncryptionSetScope interface {
+	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
+	DiskEncryptionSetSpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope DiskEncryptionSetScope
+	Client
+}
+
+// New creates a new service.
+func New(scope DiskEncryptionSetScope) *Service {
+	return &Service{
+		Scope:  scope,
+		Client: NewClient(scope),
+	}
+}
+
+// Reconcile gets/creates/updates the disk encryption sets and grants them access to their key vaults.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "diskencryptionsets.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.DiskEncryptionSetSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// We go through the list of DiskEncryptionSetSpecs to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one
+	// order of precedence is: error creating -> creating in progress -> created (no error)
+	var result error
+	for _, diskEncryptionSetSpec := range specs {
+		diskEncryptionSet, err := async.CreateResource(ctx, s.Scope, s.Client, diskEncryptionSetSpec, serviceName)
+		if err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+			continue
+		}
+		if err := s.grantKeyVaultAccess(ctx, diskEncryptionSetSpec, diskEncryptionSet); err != nil {
+			result = err
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, result)
+	return result
+}
+
+// Delete revokes the access of the disk encryption sets to their key vaults and deletes them.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "diskencryptionsets.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.DiskEncryptionSetSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// We go through the list of DiskEncryptionSetSpecs to delete each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one
+	// order of precedence is: error deleting -> deleting in progress -> deleted (no error)
+	var result error
+	for _, diskEncryptionSetSpec := range specs {
+		// the access to the key vault is granted outside of the resource group of the cluster, so it is revoked
+		// explicitly while the disk encryption set still exists
+		if diskEncryptionSet, err := s.Client.Get(ctx, diskEncryptionSetSpec.ResourceGroupName(), diskEncryptionSetSpec.ResourceName()); err == nil {
+			if err := s.revokeKeyVaultAccess(ctx, diskEncryptionSetSpec, diskEncryptionSet); err != nil {
+				result = err
+				continue
+			}
+		} else if !azure.ResourceNotFound(err) {
+			result = errors.Wrapf(err, "failed to get disk encryption set %s", diskEncryptionSetSpec.ResourceName())
+			continue
+		}
+
+		if err := async.DeleteResource(ctx, s.Scope, s.Client, diskEncryptionSetSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, result)
+	return result
+}
+
+// grantKeyVaultAccess grants the managed identity of the disk encryption set access to the key of its key vault, with
+// an access policy or a role assignment depending on the permission model of the key vault.
+func (s *Service) grantKeyVaultAccess(ctx context.Context, spec azure.ResourceSpecGetter, result interface{}) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "diskencryptionsets.Service.grantKeyVaultAccess")
+	defer done()
+
+	diskEncryptionSetSpec, ok := spec.(*DiskEncryptionSetSpec)
+	if !ok {
+		return errors.Errorf("%T is not a *DiskEncryptionSetSpec", spec)
+	}
+	diskEncryptionSet, ok := result.(compute.DiskEncryptionSet)
+	if !ok {
+		return errors.Errorf("%T is not a compute.DiskEncryptionSet", result)
+	}
+	principalID := principalID(diskEncryptionSet)
+	if principalID == "" {
+		return errors.Errorf("disk encryption set %s has no managed identity", diskEncryptionSetSpec.Name)
+	}
+
+	vaultResource, vault, err := s.getVault(ctx, diskEncryptionSetSpec.KeyVaultID)
+	if err != nil {
+		return err
+	}
+
+	if to.Bool(vault.Properties.EnableRbacAuthorization) {
+		log.V(2).Info("assigning key vault role to disk encryption set", "disk encryption set", diskEncryptionSetSpec.Name, "key vault", vaultResource.ResourceName)
+		err := s.Client.CreateRoleAssignment(ctx, diskEncryptionSetSpec.KeyVaultID, roleAssignmentName(diskEncryptionSetSpec.KeyVaultID, principalID),
+			authorization.RoleAssignmentCreateParameters{
+				Properties: &authorization.RoleAssignmentProperties{
+					RoleDefinitionID: to.StringPtr(fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", vaultResource.SubscriptionID, keyVaultCryptoServiceEncryptionUserID)),
+					PrincipalID:      to.StringPtr(principalID),
+				},
+			})
+		// the role may already be assigned to the disk encryption set by someone else, under another name
+		if err != nil && !azure.ResourceConflict(err) {
+			return errors.Wrapf(err, "failed to assign key vault %s role to disk encryption set %s", vaultResource.ResourceName, diskEncryptionSetSpec.Name)
+		}
+		return nil
+	}
+
+	if hasAccessPolicy(vault, principalID) {
+		return nil
+	}
+	log.V(2).Info("adding key vault access policy for disk encryption set", "disk encryption set", diskEncryptionSetSpec.Name, "key vault", vaultResource.ResourceName)
+	if err := s.Client.UpdateAccessPolicy(ctx, vaultResource.ResourceGroup, vaultResource.ResourceName, keyvault.Add, accessPolicy(vault, principalID)); err != nil {
+		return errors.Wrapf(err, "failed to add key vault %s access policy for disk encryption set %s", vaultResource.ResourceName, diskEncryptionSetSpec.Name)
+	}
+	return nil
+}
+
+// revokeKeyVaultAccess removes the access policy or the role assignment granting the managed identity of the disk
+// encryption set access to its key vault.
+func (s *Service) revokeKeyVaultAccess(ctx context.Context, spec azure.ResourceSpecGetter, diskEncryptionSet compute.DiskEncryptionSet) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "diskencryptionsets.Service.revokeKeyVaultAccess")
+	defer done()
+
+	diskEncryptionSetSpec, ok := spec.(*DiskEncryptionSetSpec)
+	if !ok {
+		return errors.Errorf("%T is not a *DiskEncryptionSetSpec", spec)
+	}
+	principalID := principalID(diskEncryptionSet)
+	if principalID == "" {
+		return nil
+	}
+
+	vaultResource, vault, err := s.getVault(ctx, diskEncryptionSetSpec.KeyVaultID)
+	if azure.ResourceNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if to.Bool(vault.Properties.EnableRbacAuthorization) {
+		log.V(2).Info("deleting key vault role assignment of disk encryption set", "disk encryption set", diskEncryptionSetSpec.Name, "key vault", vaultResource.ResourceName)
+		err := s.Client.DeleteRoleAssignment(ctx, diskEncryptionSetSpec.KeyVaultID, roleAssignmentName(diskEncryptionSetSpec.KeyVaultID, principalID))
+		if err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete key vault %s role assignment of disk encryption set %s", vaultResource.ResourceName, diskEncryptionSetSpec.Name)
+		}
+		return nil
+	}
+
+	if !hasAccessPolicy(vault, principalID) {
+		return nil
+	}
+	log.V(2).Info("removing key vault access policy of disk encryption set", "disk encryption set", diskEncryptionSetSpec.Name, "key vault", vaultResource.ResourceName)
+	if err := s.Client.UpdateAccessPolicy(ctx, vaultResource.ResourceGroup, vaultResource.ResourceName, keyvault.Remove, accessPolicy(vault, principalID)); err != nil {
+		return errors.Wrapf(err, "failed to remove key vault %s access policy of disk encryption set %s", vaultResource.ResourceName, diskEncryptionSetSpec.Name)
+	}
+	return nil
+}
+
+// getVault gets the key vault with the given resource ID.
+func (s *Service) getVault(ctx context.Context, keyVaultID string) (azureautorest.Resource, keyvault.Vault, error) {
+	vaultResource, err := azureautorest.ParseResourceID(keyVaultID)
+	if err != nil {
+		return azureautorest.Resource{}, keyvault.Vault{}, errors.Wrapf(err, "failed to parse key vault ID %s", keyVaultID)
+	}
+	vault, err := s.Client.GetVault(ctx, vaultResource.ResourceGroup, vaultResource.ResourceName)
+	if err != nil {
+		return vaultResource, keyvault.Vault{}, errors.Wrapf(err, "failed to get key vault %s", vaultResource.ResourceName)
+	}
+	if vault.Properties == nil {
+		return vaultResource, keyvault.Vault{}, errors.Errorf("key vault %s has no properties", vaultResource.ResourceName)
+	}
+	return vaultResource, vault, nil
+}
+
+// principalID returns the object ID of the managed identity of the disk encryption set.
+func principalID(diskEncryptionSet compute.DiskEncryptionSet) string {
+	if diskEncryptionSet.Identity == nil {
+		return ""
+	}
+	return to.String(diskEncryptionSet.Identity.PrincipalID)
+}
+
+// roleAssignmentName returns a stable name for the role assignment of the principal on the key vault, so it can be
+// found again to be deleted.
+func roleAssignmentName(keyVaultID, principalID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.ToLower(keyVaultID)+"/"+principalID)).String()
+}
+
+// hasAccessPolicy returns true if the key vault has an access policy for the principal.
+func hasAccessPolicy(vault keyvault.Vault, principalID string) bool {
+	if vault.Properties.AccessPolicies == nil {
+		return false
+	}
+	for _, policy := range *vault.Properties.AccessPolicies {
+		if strings.EqualFold(to.String(policy.ObjectID), principalID) {
+			return true
+		}
+	}
+	return false
+}
+
+// accessPolicy returns the access policy of the key vault granting the principal access to its keys.
+func accessPolicy(vault keyvault.Vault, principalID string) keyvault.VaultAccessPolicyParameters {
+	return keyvault.VaultAccessPolicyParameters{
+		Properties: &keyvault.VaultAccessPolicyProperties{
+			AccessPolicies: &[]keyvault.AccessPolicyEntry{
+				{
+					TenantID: vault.Properties.TenantID,
+					ObjectID: to.StringPtr(principalID),
+					Permissions: &keyvault.Permissions{
+						Keys: &keyPermissions,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskencryptionsets
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/mgmt/2019-09-01/keyvault"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/diskencryptionsets/mock_diskencryptionsets"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/golden"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeDiskEncryptionSetSpec = DiskEncryptionSetSpec{
+		Name:           "my-des",
+		ResourceGroup:  "my-rg",
+		Location:       "westus2",
+		KeyURL:         "https://my-kv.vault.azure.net/keys/my-key/0123456789abcdef",
+		KeyVaultID:     "/subscriptions/123/resourceGroups/my-kv-rg/providers/Microsoft.KeyVault/vaults/my-kv",
+		EncryptionType: infrav1.DiskEncryptionSetTypeCustomerKey,
+		ClusterName:    "my-cluster",
+	}
+	fakeDiskEncryptionSetSpecs = []azure.ResourceSpecGetter{&fakeDiskEncryptionSetSpec}
+	fakeDiskEncryptionSet      = compute.DiskEncryptionSet{
+		Name:     to.StringPtr("my-des"),
+		Identity: &compute.EncryptionSetIdentity{PrincipalID: to.StringPtr("my-principal")},
+	}
+	fakeRoleAssignmentName = uuid.NewSHA1(uuid.NameSpaceURL, []byte("/subscriptions/123/resourcegroups/my-kv-rg/providers/microsoft.keyvault/vaults/my-kv/my-principal")).String()
+	fakeRoleAssignment     = authorization.RoleAssignmentCreateParameters{
+		Properties: &authorization.RoleAssignmentProperties{
+			RoleDefinitionID: to.StringPtr("/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/e147488a-f6f5-4113-8e2d-b22465e65bf6"),
+			PrincipalID:      to.StringPtr("my-principal"),
+		},
+	}
+	fakeRBACVault = keyvault.Vault{
+		Properties: &keyvault.VaultProperties{EnableRbacAuthorization: to.BoolPtr(true)},
+	}
+	fakeAccessPolicyVault = keyvault.Vault{
+		Properties: &keyvault.VaultProperties{},
+	}
+	fakeAccessPolicy = keyvault.VaultAccessPolicyParameters{
+		Properties: &keyvault.VaultAccessPolicyProperties{
+			AccessPolicies: &[]keyvault.AccessPolicyEntry{
+				{
+					ObjectID: to.StringPtr("my-principal"),
+					Permissions: &keyvault.Permissions{
+						Keys: &[]keyvault.KeyPermissions{keyvault.KeyPermissionsGet, keyvault.KeyPermissionsWrapKey, keyvault.KeyPermissionsUnwrapKey},
+					},
+				},
+			},
+		},
+	}
+	fakeGrantedVault = keyvault.Vault{
+		Properties: &keyvault.VaultProperties{
+			AccessPolicies: fakeAccessPolicy.Properties.AccessPolicies,
+		},
+	}
+	notFound      = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+	conflict      = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 409}, "Conflict")
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+)
+
+func TestReconcileDiskEncryptionSets(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder)
+	}{
+		{
+			name:          "noop if no disk encryption sets are specified",
+			expectedError: "",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "create disk encryption set and assign key vault role",
+			expectedError: "",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				s.GetLongRunningOperationState("my-des", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeDiskEncryptionSetSpec).Return(fakeDiskEncryptionSet, nil, nil)
+				m.GetVault(gomockinternal.AContext(), "my-kv-rg", "my-kv").Return(fakeRBACVault, nil)
+				m.CreateRoleAssignment(gomockinternal.AContext(), fakeDiskEncryptionSetSpec.KeyVaultID, fakeRoleAssignmentName, fakeRoleAssignment)
+				s.UpdatePutStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "key vault role is already assigned",
+			expectedError: "",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				s.GetLongRunningOperationState("my-des", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeDiskEncryptionSetSpec).Return(fakeDiskEncryptionSet, nil, nil)
+				m.GetVault(gomockinternal.AContext(), "my-kv-rg", "my-kv").Return(fakeRBACVault, nil)
+				m.CreateRoleAssignment(gomockinternal.AContext(), fakeDiskEncryptionSetSpec.KeyVaultID, fakeRoleAssignmentName, fakeRoleAssignment).Return(conflict)
+				s.UpdatePutStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "create disk encryption set and add key vault access policy",
+			expectedError: "",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				s.GetLongRunningOperationState("my-des", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeDiskEncryptionSetSpec).Return(fakeDiskEncryptionSet, nil, nil)
+				m.GetVault(gomockinternal.AContext(), "my-kv-rg", "my-kv").Return(fakeAccessPolicyVault, nil)
+				m.UpdateAccessPolicy(gomockinternal.AContext(), "my-kv-rg", "my-kv", keyvault.Add, fakeAccessPolicy)
+				s.UpdatePutStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "key vault access policy already exists",
+			expectedError: "",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				s.GetLongRunningOperationState("my-des", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeDiskEncryptionSetSpec).Return(fakeDiskEncryptionSet, nil, nil)
+				m.GetVault(gomockinternal.AContext(), "my-kv-rg", "my-kv").Return(fakeGrantedVault, nil)
+				s.UpdatePutStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to create a disk encryption set",
+			expectedError: "failed to create resource my-rg/my-des (service: diskencryptionsets): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				s.GetLongRunningOperationState("my-des", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeDiskEncryptionSetSpec).Return(nil, nil, internalError)
+				s.UpdatePutStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "fail to get the key vault",
+			expectedError: "failed to get key vault my-kv: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				s.GetLongRunningOperationState("my-des", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeDiskEncryptionSetSpec).Return(fakeDiskEncryptionSet, nil, nil)
+				m.GetVault(gomockinternal.AContext(), "my-kv-rg", "my-kv").Return(keyvault.Vault{}, internalError)
+				s.UpdatePutStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "fail to assign the key vault role",
+			expectedError: "failed to assign key vault my-kv role to disk encryption set my-des: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				s.GetLongRunningOperationState("my-des", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeDiskEncryptionSetSpec).Return(fakeDiskEncryptionSet, nil, nil)
+				m.GetVault(gomockinternal.AContext(), "my-kv-rg", "my-kv").Return(fakeRBACVault, nil)
+				m.CreateRoleAssignment(gomockinternal.AContext(), fakeDiskEncryptionSetSpec.KeyVaultID, fakeRoleAssignmentName, fakeRoleAssignment).Return(internalError)
+				s.UpdatePutStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_diskencryptionsets.NewMockDiskEncryptionSetScope(mockCtrl)
+			clientMock := mock_diskencryptionsets.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteDiskEncryptionSets(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder)
+	}{
+		{
+			name:          "noop if no disk encryption sets are specified",
+			expectedError: "",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "delete key vault role assignment and disk encryption set",
+			expectedError: "",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-des").Return(fakeDiskEncryptionSet, nil)
+				m.GetVault(gomockinternal.AContext(), "my-kv-rg", "my-kv").Return(fakeRBACVault, nil)
+				m.DeleteRoleAssignment(gomockinternal.AContext(), fakeDiskEncryptionSetSpec.KeyVaultID, fakeRoleAssignmentName)
+				s.GetLongRunningOperationState("my-des", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeDiskEncryptionSetSpec).Return(nil, nil)
+				s.UpdateDeleteStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "remove key vault access policy and delete disk encryption set",
+			expectedError: "",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-des").Return(fakeDiskEncryptionSet, nil)
+				m.GetVault(gomockinternal.AContext(), "my-kv-rg", "my-kv").Return(fakeGrantedVault, nil)
+				m.UpdateAccessPolicy(gomockinternal.AContext(), "my-kv-rg", "my-kv", keyvault.Remove, fakeAccessPolicy)
+				s.GetLongRunningOperationState("my-des", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeDiskEncryptionSetSpec).Return(nil, nil)
+				s.UpdateDeleteStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "disk encryption set is already deleted",
+			expectedError: "",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-des").Return(compute.DiskEncryptionSet{}, notFound)
+				s.GetLongRunningOperationState("my-des", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeDiskEncryptionSetSpec).Return(nil, notFound)
+				s.UpdateDeleteStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete the key vault role assignment",
+			expectedError: "failed to delete key vault my-kv role assignment of disk encryption set my-des: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-des").Return(fakeDiskEncryptionSet, nil)
+				m.GetVault(gomockinternal.AContext(), "my-kv-rg", "my-kv").Return(fakeRBACVault, nil)
+				m.DeleteRoleAssignment(gomockinternal.AContext(), fakeDiskEncryptionSetSpec.KeyVaultID, fakeRoleAssignmentName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "fail to delete the disk encryption set",
+			expectedError: "failed to delete resource my-rg/my-des (service: diskencryptionsets): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_diskencryptionsets.MockDiskEncryptionSetScopeMockRecorder, m *mock_diskencryptionsets.MockClientMockRecorder) {
+				s.DiskEncryptionSetSpecs().Return(fakeDiskEncryptionSetSpecs)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-des").Return(compute.DiskEncryptionSet{}, notFound)
+				s.GetLongRunningOperationState("my-des", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeDiskEncryptionSetSpec).Return(nil, internalError)
+				s.UpdateDeleteStatus(infrav1.DiskEncryptionSetsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_diskencryptionsets.NewMockDiskEncryptionSetScope(mockCtrl)
+			clientMock := mock_diskencryptionsets.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDiskEncryptionSetPayload(t *testing.T) {
+	g := NewWithT(t)
+
+	result, err := fakeDiskEncryptionSetSpec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	golden.Assert(t, "diskencryptionset", result)
+}
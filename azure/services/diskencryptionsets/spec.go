@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskencryptionsets
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// DiskEncryptionSetSpec defines the specification for a disk encryption set.
+type DiskEncryptionSetSpec struct {
+	Name           string
+	ResourceGroup  string
+	Location       string
+	KeyURL         string
+	KeyVaultID     string
+	EncryptionType infrav1.DiskEncryptionSetType
+	ClusterName    string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the disk encryption set.
+func (s *DiskEncryptionSetSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *DiskEncryptionSetSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for disk encryption sets.
+func (s *DiskEncryptionSetSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the disk encryption set.
+func (s *DiskEncryptionSetSpec) Parameters(existing interface{}) (interface{}, error) {
+	if existing != nil {
+		existingSet, ok := existing.(compute.DiskEncryptionSet)
+		if !ok {
+			return nil, errors.Errorf("%T is not a compute.DiskEncryptionSet", existing)
+		}
+		if existingSet.EncryptionSetProperties != nil && isUpToDate(*existingSet.EncryptionSetProperties, s) {
+			// disk encryption set is already configured as desired
+			return nil, nil
+		}
+	}
+
+	return compute.DiskEncryptionSet{
+		Location: to.StringPtr(s.Location),
+		// the managed identity of the disk encryption set is granted access to the key vault
+		Identity: &compute.EncryptionSetIdentity{
+			Type: compute.DiskEncryptionSetIdentityTypeSystemAssigned,
+		},
+		EncryptionSetProperties: &compute.EncryptionSetProperties{
+			EncryptionType: compute.DiskEncryptionSetType(s.EncryptionType),
+			ActiveKey: &compute.KeyForDiskEncryptionSet{
+				SourceVault: &compute.SourceVault{ID: to.StringPtr(s.KeyVaultID)},
+				KeyURL:      to.StringPtr(s.KeyURL),
+			},
+		},
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+	}, nil
+}
+
+// isUpToDate returns true if the existing disk encryption set uses the desired key with the desired encryption type.
+func isUpToDate(existing compute.EncryptionSetProperties, spec *DiskEncryptionSetSpec) bool {
+	if existing.ActiveKey == nil || existing.ActiveKey.SourceVault == nil {
+		return false
+	}
+	return string(existing.EncryptionType) == string(spec.EncryptionType) &&
+		to.String(existing.ActiveKey.KeyURL) == spec.KeyURL &&
+		strings.EqualFold(to.String(existing.ActiveKey.SourceVault.ID), spec.KeyVaultID)
+}
@@ -18,19 +18,24 @@ package disks
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
 	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/pkg/errors"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // client wraps go-sdk.
 type client interface {
+	Get(context.Context, string, string) (compute.Disk, error)
+	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter) (interface{}, azureautorest.FutureAPI, error)
 	DeleteAsync(context.Context, azure.ResourceSpecGetter) (azureautorest.FutureAPI, error)
 	Result(context.Context, azureautorest.FutureAPI, string) (interface{}, error)
 	IsDone(context.Context, azureautorest.FutureAPI) (bool, error)
@@ -52,16 +57,76 @@ func newClient(auth azure.Authorizer) *azureClient {
 // NewDisksClient creates a new disks Client from subscription ID.
 func NewDisksClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.DisksClient {
 	disksClient := compute.NewDisksClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&disksClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&disksClient.Client, authorizer, serviceName)
 	return disksClient
 }
 
-// DeleteAsync deletes a route table asynchronously. DeleteAsync sends a DELETE
+// Get gets the specified disk by the disk name and resource group.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, diskName string) (_ compute.Disk, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.Get")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.disks.Get(ctx, resourceGroupName, diskName)
+}
+
+// CreateOrUpdateAsync creates or updates a disk asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ interface{}, _ azureautorest.FutureAPI, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.CreateOrUpdateAsync")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	var existingDisk interface{}
+
+	if existing, err := ac.Get(ctx, spec.ResourceGroupName(), spec.ResourceName()); err != nil && !azure.ResourceNotFound(err) {
+		return nil, nil, errors.Wrapf(err, "failed to get disk %s in %s", spec.ResourceName(), spec.ResourceGroupName())
+	} else if err == nil {
+		existingDisk = existing
+	}
+
+	params, err := spec.Parameters(existingDisk)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get desired parameters for disk %s", spec.ResourceName())
+	}
+
+	disk, ok := params.(compute.Disk)
+	if !ok {
+		if params == nil {
+			// nothing to do here.
+			return existingDisk, nil, nil
+		}
+		return nil, nil, errors.Errorf("%T is not a compute.Disk", params)
+	}
+
+	future, err := ac.disks.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), disk)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = future.WaitForCompletionRef(ctx, ac.disks.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, &future, err
+	}
+
+	result, err := future.Result(ac.disks)
+	// if the operation completed, return a nil future
+	return result, nil, err
+}
+
+// DeleteAsync deletes a disk asynchronously. DeleteAsync sends a DELETE
 // request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
 // progress of the operation.
-func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (azureautorest.FutureAPI, error) {
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ azureautorest.FutureAPI, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.DeleteAsync")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.disks.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
 	if err != nil {
@@ -83,14 +148,39 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 }
 
 // Result fetches the result of a long-running operation future.
-func (ac *azureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (interface{}, error) {
-	return nil, nil
+func (ac *azureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (_ interface{}, err error) {
+	defer azureerrors.Classify(&err)
+
+	if futureData == nil {
+		return nil, errors.Errorf("cannot get result from nil future")
+	}
+
+	switch futureType {
+	case infrav1.PutFuture:
+		var future *compute.DisksCreateOrUpdateFuture
+		jsonData, err := futureData.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal future")
+		}
+		if err := json.Unmarshal(jsonData, &future); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal future data")
+		}
+		return future.Result(ac.disks)
+
+	case infrav1.DeleteFuture:
+		// Delete does not return a result disk
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("unknown future type %q", futureType)
+	}
 }
 
 // IsDone returns true if the long-running operation has completed.
-func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (bool, error) {
+func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (_ bool, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "natgateways.Service.IsDone")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	isDone, err := future.DoneWithContext(ctx, ac.disks)
 	if err != nil {
@@ -33,6 +33,8 @@ type DiskScope interface {
 	azure.ClusterDescriber
 	azure.AsyncStatusUpdater
 	DiskSpecs() []azure.ResourceSpecGetter
+	SharedDiskSpecs() []azure.ResourceSpecGetter
+	DiskUpdateSpecs() []azure.ResourceSpecGetter
 }
 
 // Service provides operations on Azure resources.
@@ -49,12 +51,26 @@ func New(scope DiskScope) *Service {
 	}
 }
 
-// Reconcile on disk is currently no-op. OS disks should only be deleted and will create with the VM automatically.
+// Reconcile creates the data disks shared by the machines of the cluster, which are attached to the VM. Other disks
+// are created with the VM automatically, their performance tiers and machine tags are updated once they exist.
 func (s *Service) Reconcile(ctx context.Context) error {
-	_, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.Reconcile")
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.Reconcile")
 	defer done()
 
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
 	// DisksReadyCondition is set in the VM service.
+	for _, diskSpec := range s.Scope.SharedDiskSpecs() {
+		if _, err := async.CreateResource(ctx, s.Scope, s.client, diskSpec, serviceName); err != nil {
+			return err
+		}
+	}
+	for _, diskSpec := range s.Scope.DiskUpdateSpecs() {
+		if _, err := async.CreateResource(ctx, s.Scope, s.client, diskSpec, serviceName); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
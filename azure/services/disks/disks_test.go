@@ -48,10 +48,126 @@ var (
 		&diskSpec2,
 	}
 
+	sharedDiskSpec = SharedDiskSpec{
+		Name:               "my-cluster_quorum",
+		ResourceGroup:      "my-group",
+		Location:           "westus",
+		SizeGB:             32,
+		StorageAccountType: "Premium_LRS",
+		MaxShares:          2,
+		ClusterName:        "my-cluster",
+	}
+
+	fakeSharedDiskSpecs = []azure.ResourceSpecGetter{
+		&sharedDiskSpec,
+	}
+
+	diskTierSpec = DiskSpec{
+		Name:            "my-disk-1",
+		ResourceGroup:   "my-group",
+		PerformanceTier: "P30",
+	}
+
+	fakeDiskUpdateSpecs = []azure.ResourceSpecGetter{
+		&diskTierSpec,
+	}
+
 	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
 	notFoundError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found")
 )
 
+func TestReconcileDisks(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_disks.MockDiskScopeMockRecorder, m *mock_disks.MockclientMockRecorder)
+	}{
+		{
+			name:          "noop if no shared disks or performance tiers are specified",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, m *mock_disks.MockclientMockRecorder) {
+				s.SharedDiskSpecs().Return(nil)
+				s.DiskUpdateSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "create the shared disks",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, m *mock_disks.MockclientMockRecorder) {
+				s.SharedDiskSpecs().Return(fakeSharedDiskSpecs)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("my-cluster_quorum", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &sharedDiskSpec).Return(nil, nil, nil),
+				)
+				s.DiskUpdateSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "update the performance tiers of the disks",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, m *mock_disks.MockclientMockRecorder) {
+				s.SharedDiskSpecs().Return(nil)
+				s.DiskUpdateSpecs().Return(fakeDiskUpdateSpecs)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("my-disk-1", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &diskTierSpec).Return(nil, nil, nil),
+				)
+			},
+		},
+		{
+			name:          "error while trying to update the performance tier of a disk",
+			expectedError: "failed to create resource my-group/my-disk-1 (service: disks): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, m *mock_disks.MockclientMockRecorder) {
+				s.SharedDiskSpecs().Return(nil)
+				s.DiskUpdateSpecs().Return(fakeDiskUpdateSpecs)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("my-disk-1", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &diskTierSpec).Return(nil, nil, internalError),
+				)
+			},
+		},
+		{
+			name:          "error while trying to create the shared disk",
+			expectedError: "failed to create resource my-group/my-cluster_quorum (service: disks): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, m *mock_disks.MockclientMockRecorder) {
+				s.SharedDiskSpecs().Return(fakeSharedDiskSpecs)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("my-cluster_quorum", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &sharedDiskSpec).Return(nil, nil, internalError),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_disks.NewMockDiskScope(mockCtrl)
+			clientMock := mock_disks.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestDeleteDisk(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -16,10 +16,40 @@ limitations under the License.
 
 package disks
 
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
 // DiskSpec defines the specification for a disk.
 type DiskSpec struct {
-	Name          string
-	ResourceGroup string
+	Name            string
+	ResourceGroup   string
+	PerformanceTier string
+	// MachineName is the name of the machine the disk is tagged with, for data disks kept after the deletion of the
+	// machine.
+	MachineName string
+}
+
+// SharedDiskSpec defines the specification for a data disk shared by the machines of a cluster.
+type SharedDiskSpec struct {
+	Name                string
+	ResourceGroup       string
+	Location            string
+	Zone                string
+	SizeGB              int32
+	StorageAccountType  string
+	MaxShares           int32
+	DiskEncryptionSetID string
+	PerformanceTier     string
+	ClusterName         string
+	AdditionalTags      infrav1.Tags
 }
 
 // ResourceName returns the name of the disk.
@@ -37,7 +67,101 @@ func (s *DiskSpec) OwnerResourceName() string {
 	return ""
 }
 
-// Parameters is a no-op for disks.
+// Parameters returns the parameters to update the performance tier and the machine tag of an existing disk. Disks are
+// created with their VM, so it is a no-op for disks which don't exist yet.
 func (s *DiskSpec) Parameters(existing interface{}) (interface{}, error) {
-	return nil, nil
+	if existing == nil {
+		return nil, nil
+	}
+	existingDisk, ok := existing.(compute.Disk)
+	if !ok {
+		return nil, errors.Errorf("%T is not a compute.Disk", existing)
+	}
+
+	updated := false
+	if disk, ok := withPerformanceTier(existingDisk, s.PerformanceTier).(compute.Disk); ok {
+		existingDisk, updated = disk, true
+	}
+	if s.MachineName != "" && to.String(existingDisk.Tags[infrav1.NameAzureProviderMachine]) != s.MachineName {
+		tags := make(map[string]*string, len(existingDisk.Tags)+1)
+		for k, v := range existingDisk.Tags {
+			tags[k] = v
+		}
+		tags[infrav1.NameAzureProviderMachine] = to.StringPtr(s.MachineName)
+		existingDisk.Tags, updated = tags, true
+	}
+	if !updated {
+		return nil, nil
+	}
+	return existingDisk, nil
+}
+
+// withPerformanceTier returns the existing disk updated with the given performance tier, or nil if it already has it.
+func withPerformanceTier(existing compute.Disk, tier string) interface{} {
+	if tier == "" || existing.DiskProperties == nil || to.String(existing.Tier) == tier {
+		return nil
+	}
+	existing.Tier = to.StringPtr(tier)
+	return existing
+}
+
+// ResourceName returns the name of the shared disk.
+func (s *SharedDiskSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *SharedDiskSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for shared disks.
+func (s *SharedDiskSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the shared disk. Only the performance tier of an existing shared disk is
+// updated as it may already be attached to other machines.
+func (s *SharedDiskSpec) Parameters(existing interface{}) (interface{}, error) {
+	if existing != nil {
+		existingDisk, ok := existing.(compute.Disk)
+		if !ok {
+			return nil, errors.Errorf("%T is not a compute.Disk", existing)
+		}
+		return withPerformanceTier(existingDisk, s.PerformanceTier), nil
+	}
+
+	disk := compute.Disk{
+		Location: to.StringPtr(s.Location),
+		Sku: &compute.DiskSku{
+			Name: compute.DiskStorageAccountTypes(s.StorageAccountType),
+		},
+		DiskProperties: &compute.DiskProperties{
+			CreationData: &compute.CreationData{
+				CreateOption: compute.DiskCreateOptionEmpty,
+			},
+			DiskSizeGB: to.Int32Ptr(s.SizeGB),
+			MaxShares:  to.Int32Ptr(s.MaxShares),
+		},
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+	}
+	if s.PerformanceTier != "" {
+		disk.Tier = to.StringPtr(s.PerformanceTier)
+	}
+	if s.DiskEncryptionSetID != "" {
+		// the encryption type is inferred from the disk encryption set.
+		disk.Encryption = &compute.Encryption{
+			DiskEncryptionSetID: to.StringPtr(s.DiskEncryptionSetID),
+		}
+	}
+	// locally redundant disks can only be attached to VMs in their zone, zone redundant disks are not zonal.
+	if s.Zone != "" && strings.HasSuffix(s.StorageAccountType, "_LRS") {
+		disk.Zones = &[]string{s.Zone}
+	}
+	return disk, nil
 }
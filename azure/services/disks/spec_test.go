@@ -0,0 +1,285 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disks
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/golden"
+)
+
+func TestDiskSpec_Parameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          *DiskSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name: "disk does not exist yet",
+			spec: &diskTierSpec,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:          "existing is not a disk",
+			spec:          &diskTierSpec,
+			existing:      "foo",
+			expectedError: "string is not a compute.Disk",
+		},
+		{
+			name: "disk already has the performance tier",
+			spec: &diskTierSpec,
+			existing: compute.Disk{
+				Name:           to.StringPtr("my-disk-1"),
+				DiskProperties: &compute.DiskProperties{Tier: to.StringPtr("P30")},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "disk without a performance tier is left untouched",
+			spec: &DiskSpec{Name: "my-disk-1", ResourceGroup: "my-group"},
+			existing: compute.Disk{
+				Name:           to.StringPtr("my-disk-1"),
+				DiskProperties: &compute.DiskProperties{Tier: to.StringPtr("P10")},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "performance tier of the disk is updated",
+			spec: &diskTierSpec,
+			existing: compute.Disk{
+				Name: to.StringPtr("my-disk-1"),
+				DiskProperties: &compute.DiskProperties{
+					DiskSizeGB: to.Int32Ptr(128),
+					Tier:       to.StringPtr("P10"),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(compute.Disk{
+					Name: to.StringPtr("my-disk-1"),
+					DiskProperties: &compute.DiskProperties{
+						DiskSizeGB: to.Int32Ptr(128),
+						Tier:       to.StringPtr("P30"),
+					},
+				}))
+			},
+		},
+		{
+			name: "data disk kept after the deletion of the machine is tagged with the machine name",
+			spec: &DiskSpec{Name: "my-disk-1", ResourceGroup: "my-group", MachineName: "my-machine"},
+			existing: compute.Disk{
+				Name:           to.StringPtr("my-disk-1"),
+				DiskProperties: &compute.DiskProperties{Tier: to.StringPtr("P10")},
+				Tags:           map[string]*string{"foo": to.StringPtr("bar")},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(compute.Disk{
+					Name:           to.StringPtr("my-disk-1"),
+					DiskProperties: &compute.DiskProperties{Tier: to.StringPtr("P10")},
+					Tags: map[string]*string{
+						"foo":                            to.StringPtr("bar"),
+						infrav1.NameAzureProviderMachine: to.StringPtr("my-machine"),
+					},
+				}))
+			},
+		},
+		{
+			name: "data disk already tagged with the machine name is left untouched",
+			spec: &DiskSpec{Name: "my-disk-1", ResourceGroup: "my-group", MachineName: "my-machine"},
+			existing: compute.Disk{
+				Name:           to.StringPtr("my-disk-1"),
+				DiskProperties: &compute.DiskProperties{Tier: to.StringPtr("P10")},
+				Tags:           map[string]*string{infrav1.NameAzureProviderMachine: to.StringPtr("my-machine")},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				tc.expect(g, result)
+			}
+		})
+	}
+}
+
+func TestSharedDiskSpec_Parameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          *SharedDiskSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name: "shared disk already exists",
+			spec: &sharedDiskSpec,
+			existing: compute.Disk{
+				Name: to.StringPtr("my-cluster_quorum"),
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "performance tier of the existing shared disk is updated",
+			spec: &SharedDiskSpec{
+				Name:            "my-cluster_quorum",
+				ResourceGroup:   "my-group",
+				PerformanceTier: "P30",
+			},
+			existing: compute.Disk{
+				Name:           to.StringPtr("my-cluster_quorum"),
+				DiskProperties: &compute.DiskProperties{Tier: to.StringPtr("P4")},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result.(compute.Disk).Tier).To(Equal(to.StringPtr("P30")))
+			},
+		},
+		{
+			name:          "existing is not a disk",
+			spec:          &sharedDiskSpec,
+			existing:      "foo",
+			expectedError: "string is not a compute.Disk",
+		},
+		{
+			name: "shared disk does not exist",
+			spec: &sharedDiskSpec,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.Disk{}))
+				disk := result.(compute.Disk)
+				g.Expect(disk.Location).To(Equal(to.StringPtr("westus")))
+				g.Expect(disk.Sku.Name).To(Equal(compute.DiskStorageAccountTypesPremiumLRS))
+				g.Expect(disk.CreationData.CreateOption).To(Equal(compute.DiskCreateOptionEmpty))
+				g.Expect(disk.DiskSizeGB).To(Equal(to.Int32Ptr(32)))
+				g.Expect(disk.MaxShares).To(Equal(to.Int32Ptr(2)))
+				g.Expect(disk.Zones).To(BeNil())
+				g.Expect(disk.Encryption).To(BeNil())
+				g.Expect(disk.Tier).To(BeNil())
+				g.Expect(disk.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", to.StringPtr("owned")))
+			},
+		},
+		{
+			name: "locally redundant shared disk is created in the zone of the machine",
+			spec: &SharedDiskSpec{
+				Name:               "my-cluster_quorum",
+				ResourceGroup:      "my-group",
+				Location:           "westus",
+				Zone:               "2",
+				SizeGB:             32,
+				StorageAccountType: "Premium_LRS",
+				MaxShares:          2,
+				ClusterName:        "my-cluster",
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result.(compute.Disk).Zones).To(Equal(&[]string{"2"}))
+			},
+		},
+		{
+			name: "zone redundant shared disk is not zonal",
+			spec: &SharedDiskSpec{
+				Name:               "my-cluster_quorum",
+				ResourceGroup:      "my-group",
+				Location:           "westus",
+				Zone:               "2",
+				SizeGB:             32,
+				StorageAccountType: "Premium_ZRS",
+				MaxShares:          2,
+				ClusterName:        "my-cluster",
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result.(compute.Disk).Zones).To(BeNil())
+			},
+		},
+		{
+			name: "shared disk encrypted with a disk encryption set",
+			spec: &SharedDiskSpec{
+				Name:                "my-cluster_quorum",
+				ResourceGroup:       "my-group",
+				Location:            "westus",
+				SizeGB:              32,
+				StorageAccountType:  "Premium_LRS",
+				MaxShares:           2,
+				DiskEncryptionSetID: "my-des-id",
+				ClusterName:         "my-cluster",
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result.(compute.Disk).Encryption).To(Equal(&compute.Encryption{DiskEncryptionSetID: to.StringPtr("my-des-id")}))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				tc.expect(g, result)
+			}
+		})
+	}
+}
+
+func TestDiskPayload(t *testing.T) {
+	g := NewWithT(t)
+
+	result, err := diskTierSpec.Parameters(compute.Disk{
+		Name:           to.StringPtr("my-disk-1"),
+		DiskProperties: &compute.DiskProperties{Tier: to.StringPtr("P10")},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	golden.Assert(t, "disk", result)
+}
+
+func TestSharedDiskPayload(t *testing.T) {
+	g := NewWithT(t)
+
+	result, err := sharedDiskSpec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	golden.Assert(t, "shareddisk", result)
+}
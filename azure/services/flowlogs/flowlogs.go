@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flowlogs
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "flowlogs"
+
+// FlowLogScope defines the scope interface for a flow logs service.
+type FlowLogScope interface {
+	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
+	IsVnetManaged() bool
+	FlowLogSpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope FlowLogScope
+	Client
+}
+
+// New creates a new service.
+func New(scope FlowLogScope) *Service {
+	return &Service{
+		Scope:  scope,
+		Client: NewClient(scope),
+	}
+}
+
+// Reconcile gets/creates/updates the flow logs of the network security groups.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "flowlogs.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.FlowLogSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	if !s.Scope.IsVnetManaged() {
+		log.V(4).Info("Skipping flow logs reconcile in custom VNet mode")
+		return nil
+	}
+
+	if err := s.reconcileNetworkWatcher(ctx, specs[0]); err != nil {
+		s.Scope.UpdatePutStatus(infrav1.FlowLogsReadyCondition, serviceName, err)
+		return err
+	}
+
+	// We go through the list of FlowLogSpecs to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one
+	// order of precedence is: error creating -> creating in progress -> created (no error)
+	var result error
+	for _, flowLogSpec := range specs {
+		if _, err := async.CreateResource(ctx, s.Scope, s.Client, flowLogSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.FlowLogsReadyCondition, serviceName, result)
+	return result
+}
+
+// Delete deletes the flow logs of the network security groups.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "flowlogs.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.FlowLogSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	if !s.Scope.IsVnetManaged() {
+		log.V(4).Info("Skipping flow logs delete in custom VNet mode")
+		return nil
+	}
+
+	// We go through the list of FlowLogSpecs to delete each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one
+	// order of precedence is: error deleting -> deleting in progress -> deleted (no error)
+	var result error
+	for _, flowLogSpec := range specs {
+		if err := async.DeleteResource(ctx, s.Scope, s.Client, flowLogSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.FlowLogsReadyCondition, serviceName, result)
+	return result
+}
+
+// reconcileNetworkWatcher creates the Network Watcher writing the flow logs if it doesn't exist.
+// The Network Watcher is never deleted, as Azure allows a single Network Watcher per region which is shared with
+// other workloads of the subscription.
+func (s *Service) reconcileNetworkWatcher(ctx context.Context, spec azure.ResourceSpecGetter) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "flowlogs.Service.reconcileNetworkWatcher")
+	defer done()
+
+	rgName, watcherName := spec.ResourceGroupName(), spec.OwnerResourceName()
+	if _, err := s.Client.GetNetworkWatcher(ctx, rgName, watcherName); err == nil {
+		return nil
+	} else if !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get network watcher %s in %s", watcherName, rgName)
+	}
+
+	log.V(2).Info("creating network watcher", "network watcher", watcherName, "resourceGroup", rgName)
+	watcher := network.Watcher{
+		Location: to.StringPtr(s.Scope.Location()),
+	}
+	if err := s.Client.CreateOrUpdateNetworkWatcher(ctx, rgName, watcherName, watcher); err != nil {
+		return errors.Wrapf(err, "failed to create network watcher %s in %s", watcherName, rgName)
+	}
+	log.V(2).Info("successfully created network watcher", "network watcher", watcherName, "resourceGroup", rgName)
+	return nil
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flowlogs
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/flowlogs/mock_flowlogs"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/golden"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeControlPlaneFlowLog = FlowLogSpec{
+		Name:                        "my-rg-controlplane-nsg-flowlog",
+		NetworkWatcherName:          "NetworkWatcher_westus2",
+		NetworkWatcherResourceGroup: "NetworkWatcherRG",
+		Location:                    "westus2",
+		TargetNSGID:                 "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/controlplane-nsg",
+		StorageAccountID:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/mystorage",
+		ClusterName:                 "my-cluster",
+	}
+	fakeNodeFlowLog = FlowLogSpec{
+		Name:                        "my-rg-node-nsg-flowlog",
+		NetworkWatcherName:          "NetworkWatcher_westus2",
+		NetworkWatcherResourceGroup: "NetworkWatcherRG",
+		Location:                    "westus2",
+		TargetNSGID:                 "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/node-nsg",
+		StorageAccountID:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/mystorage",
+		ClusterName:                 "my-cluster",
+	}
+	fakeFlowLogSpecs = []azure.ResourceSpecGetter{&fakeControlPlaneFlowLog, &fakeNodeFlowLog}
+	notFound         = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+	internalError    = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+)
+
+func TestReconcileFlowLogs(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_flowlogs.MockFlowLogScopeMockRecorder, m *mock_flowlogs.MockClientMockRecorder)
+	}{
+		{
+			name:          "noop if flow logs are not enabled",
+			expectedError: "",
+			expect: func(s *mock_flowlogs.MockFlowLogScopeMockRecorder, m *mock_flowlogs.MockClientMockRecorder) {
+				s.FlowLogSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "noop in custom VNet mode",
+			expectedError: "",
+			expect: func(s *mock_flowlogs.MockFlowLogScopeMockRecorder, m *mock_flowlogs.MockClientMockRecorder) {
+				s.FlowLogSpecs().Return(fakeFlowLogSpecs)
+				s.IsVnetManaged().Return(false)
+			},
+		},
+		{
+			name:          "create flow logs with an existing network watcher",
+			expectedError: "",
+			expect: func(s *mock_flowlogs.MockFlowLogScopeMockRecorder, m *mock_flowlogs.MockClientMockRecorder) {
+				s.FlowLogSpecs().Return(fakeFlowLogSpecs)
+				s.IsVnetManaged().Return(true)
+				m.GetNetworkWatcher(gomockinternal.AContext(), "NetworkWatcherRG", "NetworkWatcher_westus2").Return(network.Watcher{}, nil)
+				s.GetLongRunningOperationState("my-rg-controlplane-nsg-flowlog", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeControlPlaneFlowLog).Return(nil, nil, nil)
+				s.GetLongRunningOperationState("my-rg-node-nsg-flowlog", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeNodeFlowLog).Return(nil, nil, nil)
+				s.UpdatePutStatus(infrav1.FlowLogsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "create the network watcher if it doesn't exist",
+			expectedError: "",
+			expect: func(s *mock_flowlogs.MockFlowLogScopeMockRecorder, m *mock_flowlogs.MockClientMockRecorder) {
+				s.FlowLogSpecs().Return(fakeFlowLogSpecs[:1])
+				s.IsVnetManaged().Return(true)
+				s.Location().Return("westus2")
+				m.GetNetworkWatcher(gomockinternal.AContext(), "NetworkWatcherRG", "NetworkWatcher_westus2").Return(network.Watcher{}, notFound)
+				m.CreateOrUpdateNetworkWatcher(gomockinternal.AContext(), "NetworkWatcherRG", "NetworkWatcher_westus2", network.Watcher{Location: to.StringPtr("westus2")})
+				s.GetLongRunningOperationState("my-rg-controlplane-nsg-flowlog", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeControlPlaneFlowLog).Return(nil, nil, nil)
+				s.UpdatePutStatus(infrav1.FlowLogsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to get the network watcher",
+			expectedError: "failed to get network watcher NetworkWatcher_westus2 in NetworkWatcherRG: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_flowlogs.MockFlowLogScopeMockRecorder, m *mock_flowlogs.MockClientMockRecorder) {
+				s.FlowLogSpecs().Return(fakeFlowLogSpecs[:1])
+				s.IsVnetManaged().Return(true)
+				m.GetNetworkWatcher(gomockinternal.AContext(), "NetworkWatcherRG", "NetworkWatcher_westus2").Return(network.Watcher{}, internalError)
+				s.UpdatePutStatus(infrav1.FlowLogsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "fail to create a flow log",
+			expectedError: "failed to create resource NetworkWatcherRG/my-rg-node-nsg-flowlog (service: flowlogs): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_flowlogs.MockFlowLogScopeMockRecorder, m *mock_flowlogs.MockClientMockRecorder) {
+				s.FlowLogSpecs().Return(fakeFlowLogSpecs)
+				s.IsVnetManaged().Return(true)
+				m.GetNetworkWatcher(gomockinternal.AContext(), "NetworkWatcherRG", "NetworkWatcher_westus2").Return(network.Watcher{}, nil)
+				s.GetLongRunningOperationState("my-rg-controlplane-nsg-flowlog", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeControlPlaneFlowLog).Return(nil, nil, nil)
+				s.GetLongRunningOperationState("my-rg-node-nsg-flowlog", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeNodeFlowLog).Return(nil, nil, internalError)
+				s.UpdatePutStatus(infrav1.FlowLogsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_flowlogs.NewMockFlowLogScope(mockCtrl)
+			clientMock := mock_flowlogs.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteFlowLogs(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_flowlogs.MockFlowLogScopeMockRecorder, m *mock_flowlogs.MockClientMockRecorder)
+	}{
+		{
+			name:          "noop if flow logs are not enabled",
+			expectedError: "",
+			expect: func(s *mock_flowlogs.MockFlowLogScopeMockRecorder, m *mock_flowlogs.MockClientMockRecorder) {
+				s.FlowLogSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "delete flow logs",
+			expectedError: "",
+			expect: func(s *mock_flowlogs.MockFlowLogScopeMockRecorder, m *mock_flowlogs.MockClientMockRecorder) {
+				s.FlowLogSpecs().Return(fakeFlowLogSpecs)
+				s.IsVnetManaged().Return(true)
+				s.GetLongRunningOperationState("my-rg-controlplane-nsg-flowlog", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeControlPlaneFlowLog).Return(nil, nil)
+				s.GetLongRunningOperationState("my-rg-node-nsg-flowlog", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeNodeFlowLog).Return(nil, notFound)
+				s.UpdateDeleteStatus(infrav1.FlowLogsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete a flow log",
+			expectedError: "failed to delete resource NetworkWatcherRG/my-rg-controlplane-nsg-flowlog (service: flowlogs): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_flowlogs.MockFlowLogScopeMockRecorder, m *mock_flowlogs.MockClientMockRecorder) {
+				s.FlowLogSpecs().Return(fakeFlowLogSpecs)
+				s.IsVnetManaged().Return(true)
+				s.GetLongRunningOperationState("my-rg-controlplane-nsg-flowlog", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeControlPlaneFlowLog).Return(nil, internalError)
+				s.GetLongRunningOperationState("my-rg-node-nsg-flowlog", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeNodeFlowLog).Return(nil, nil)
+				s.UpdateDeleteStatus(infrav1.FlowLogsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_flowlogs.NewMockFlowLogScope(mockCtrl)
+			clientMock := mock_flowlogs.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestFlowLogSpecParameters(t *testing.T) {
+	spec := FlowLogSpec{
+		Name:             "my-rg-node-nsg-flowlog",
+		Location:         "westus2",
+		TargetNSGID:      fakeNodeFlowLog.TargetNSGID,
+		StorageAccountID: fakeNodeFlowLog.StorageAccountID,
+		RetentionDays:    30,
+		TrafficAnalytics: &infrav1.TrafficAnalyticsSpec{
+			WorkspaceResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-workspace",
+			WorkspaceID:         "00000000-1111-2222-3333-444444444444",
+			WorkspaceRegion:     "westus2",
+			IntervalInMinutes:   to.Int32Ptr(10),
+		},
+		ClusterName: "my-cluster",
+	}
+
+	testcases := []struct {
+		name     string
+		existing interface{}
+		expect   func(g *WithT, result interface{})
+	}{
+		{
+			name:     "new flow log",
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.FlowLog{}))
+				flowLog := result.(network.FlowLog)
+				g.Expect(flowLog.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", to.StringPtr("owned")))
+				g.Expect(to.String(flowLog.StorageID)).To(Equal(spec.StorageAccountID))
+				g.Expect(to.Int32(flowLog.RetentionPolicy.Days)).To(Equal(int32(30)))
+				g.Expect(to.Bool(flowLog.FlowAnalyticsConfiguration.NetworkWatcherFlowAnalyticsConfiguration.Enabled)).To(BeTrue())
+			},
+		},
+		{
+			name: "existing flow log is up to date",
+			existing: func() network.FlowLog {
+				params, _ := spec.Parameters(nil)
+				return params.(network.FlowLog)
+			}(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "existing flow log writes to another storage account",
+			existing: network.FlowLog{
+				Tags: map[string]*string{"foo": to.StringPtr("bar")},
+				FlowLogPropertiesFormat: &network.FlowLogPropertiesFormat{
+					TargetResourceID: to.StringPtr(spec.TargetNSGID),
+					StorageID:        to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/other"),
+					Enabled:          to.BoolPtr(true),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.FlowLog{}))
+				flowLog := result.(network.FlowLog)
+				g.Expect(flowLog.Tags).To(Equal(map[string]*string{"foo": to.StringPtr("bar")}))
+				g.Expect(to.String(flowLog.StorageID)).To(Equal(spec.StorageAccountID))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			result, err := spec.Parameters(tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, result)
+		})
+	}
+}
+
+func TestFlowLogPayload(t *testing.T) {
+	g := NewWithT(t)
+
+	result, err := fakeControlPlaneFlowLog.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	golden.Assert(t, "flowlog", result)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flowlogs
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// flowLogFormatVersion is the version of the JSON flow log format. Version 2 adds flow state and byte counters.
+const flowLogFormatVersion = 2
+
+// FlowLogSpec defines the specification for the flow log of a network security group.
+type FlowLogSpec struct {
+	Name                        string
+	NetworkWatcherName          string
+	NetworkWatcherResourceGroup string
+	Location                    string
+	TargetNSGID                 string
+	StorageAccountID            string
+	RetentionDays               int32
+	TrafficAnalytics            *infrav1.TrafficAnalyticsSpec
+	ClusterName                 string
+	AdditionalTags              infrav1.Tags
+}
+
+// ResourceName returns the name of the flow log.
+func (s *FlowLogSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the Network Watcher.
+func (s *FlowLogSpec) ResourceGroupName() string {
+	return s.NetworkWatcherResourceGroup
+}
+
+// OwnerResourceName returns the name of the Network Watcher owning the flow log.
+func (s *FlowLogSpec) OwnerResourceName() string {
+	return s.NetworkWatcherName
+}
+
+// Parameters returns the parameters for the flow log.
+func (s *FlowLogSpec) Parameters(existing interface{}) (interface{}, error) {
+	desired := network.FlowLogPropertiesFormat{
+		TargetResourceID: to.StringPtr(s.TargetNSGID),
+		StorageID:        to.StringPtr(s.StorageAccountID),
+		Enabled:          to.BoolPtr(true),
+		RetentionPolicy: &network.RetentionPolicyParameters{
+			Days:    to.Int32Ptr(s.RetentionDays),
+			Enabled: to.BoolPtr(s.RetentionDays > 0),
+		},
+		Format: &network.FlowLogFormatParameters{
+			Type:    network.FlowLogFormatTypeJSON,
+			Version: to.Int32Ptr(flowLogFormatVersion),
+		},
+		FlowAnalyticsConfiguration: &network.TrafficAnalyticsProperties{
+			NetworkWatcherFlowAnalyticsConfiguration: s.trafficAnalyticsConfiguration(),
+		},
+	}
+
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.ClusterName,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        to.StringPtr(s.Name),
+		Additional:  s.AdditionalTags,
+	})
+
+	if existing != nil {
+		existingFlowLog, ok := existing.(network.FlowLog)
+		if !ok {
+			return nil, errors.Errorf("%T is not a network.FlowLog", existing)
+		}
+		if existingFlowLog.FlowLogPropertiesFormat != nil && isUpToDate(*existingFlowLog.FlowLogPropertiesFormat, desired) {
+			// flow log is already configured as desired
+			return nil, nil
+		}
+		// keep the tags of the existing flow log, e.g. if it was created by the user
+		if existingFlowLog.Tags != nil {
+			tags = converters.MapToTags(existingFlowLog.Tags)
+		}
+	}
+
+	return network.FlowLog{
+		Location:                to.StringPtr(s.Location),
+		Tags:                    converters.TagsToMap(tags),
+		FlowLogPropertiesFormat: &desired,
+	}, nil
+}
+
+// trafficAnalyticsConfiguration returns the Traffic Analytics configuration of the flow log.
+func (s *FlowLogSpec) trafficAnalyticsConfiguration() *network.TrafficAnalyticsConfigurationProperties {
+	if s.TrafficAnalytics == nil {
+		return &network.TrafficAnalyticsConfigurationProperties{
+			Enabled: to.BoolPtr(false),
+		}
+	}
+	return &network.TrafficAnalyticsConfigurationProperties{
+		Enabled:                  to.BoolPtr(true),
+		WorkspaceID:              to.StringPtr(s.TrafficAnalytics.WorkspaceID),
+		WorkspaceRegion:          to.StringPtr(s.TrafficAnalytics.WorkspaceRegion),
+		WorkspaceResourceID:      to.StringPtr(s.TrafficAnalytics.WorkspaceResourceID),
+		TrafficAnalyticsInterval: s.TrafficAnalytics.IntervalInMinutes,
+	}
+}
+
+// isUpToDate returns true if the existing flow log writes to the desired storage account and Log Analytics workspace
+// with the desired retention.
+func isUpToDate(existing, desired network.FlowLogPropertiesFormat) bool {
+	if !to.Bool(existing.Enabled) ||
+		!strings.EqualFold(to.String(existing.TargetResourceID), to.String(desired.TargetResourceID)) ||
+		!strings.EqualFold(to.String(existing.StorageID), to.String(desired.StorageID)) {
+		return false
+	}
+
+	if existing.RetentionPolicy == nil ||
+		to.Bool(existing.RetentionPolicy.Enabled) != to.Bool(desired.RetentionPolicy.Enabled) ||
+		(to.Bool(desired.RetentionPolicy.Enabled) && to.Int32(existing.RetentionPolicy.Days) != to.Int32(desired.RetentionPolicy.Days)) {
+		return false
+	}
+
+	var have network.TrafficAnalyticsConfigurationProperties
+	if existing.FlowAnalyticsConfiguration != nil && existing.FlowAnalyticsConfiguration.NetworkWatcherFlowAnalyticsConfiguration != nil {
+		have = *existing.FlowAnalyticsConfiguration.NetworkWatcherFlowAnalyticsConfiguration
+	}
+	want := desired.FlowAnalyticsConfiguration.NetworkWatcherFlowAnalyticsConfiguration
+	if to.Bool(have.Enabled) != to.Bool(want.Enabled) {
+		return false
+	}
+	if !to.Bool(want.Enabled) {
+		return true
+	}
+	return strings.EqualFold(to.String(have.WorkspaceResourceID), to.String(want.WorkspaceResourceID)) &&
+		strings.EqualFold(to.String(have.WorkspaceID), to.String(want.WorkspaceID)) &&
+		strings.EqualFold(to.String(have.WorkspaceRegion), to.String(want.WorkspaceRegion)) &&
+		to.Int32(have.TrafficAnalyticsInterval) == to.Int32(want.TrafficAnalyticsInterval)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package galleryimageversions
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	List(ctx context.Context, subscriptionID, resourceGroup, gallery, image string) ([]compute.GalleryImageVersion, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	baseURI    string
+	authorizer autorest.Authorizer
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new gallery image versions client. The galleries can be in other subscriptions than the one of
+// the cluster, so the clients of the subscriptions are created on demand.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		baseURI:    auth.BaseURI(),
+		authorizer: auth.Authorizer(),
+	}
+}
+
+// newGalleryImageVersionsClient creates a new gallery image versions client from subscription ID.
+func newGalleryImageVersionsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.GalleryImageVersionsClient {
+	c := compute.NewGalleryImageVersionsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer, "galleryimageversions")
+	return c
+}
+
+// List returns all the versions of a gallery image.
+func (ac *AzureClient) List(ctx context.Context, subscriptionID, resourceGroup, gallery, image string) (_ []compute.GalleryImageVersion, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "galleryimageversions.AzureClient.List")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	versionsClient := newGalleryImageVersionsClient(subscriptionID, ac.baseURI, ac.authorizer)
+	iter, err := versionsClient.ListByGalleryImageComplete(ctx, resourceGroup, gallery, image)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list gallery image versions")
+	}
+
+	var versions []compute.GalleryImageVersion
+	for iter.NotDone() {
+		versions = append(versions, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return versions, errors.Wrap(err, "could not iterate gallery image versions")
+		}
+	}
+
+	return versions, nil
+}
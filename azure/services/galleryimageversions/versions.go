@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package galleryimageversions
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// noVersionRequeue is how long to wait for a version of a gallery image to be published into a location.
+const noVersionRequeue = 5 * time.Minute
+
+// LatestVersion returns the version Azure deploys a shared gallery image with "latest" as version from in the location,
+// i.e. the highest version of the image which is provisioned, replicated into the location, and not excluded from
+// latest.
+func LatestVersion(ctx context.Context, c Client, image infrav1.AzureSharedGalleryImage, location string) (string, error) {
+	versions, err := c.List(ctx, image.SubscriptionID, image.ResourceGroup, image.Gallery, image.Name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list the versions of image %s/%s", image.Gallery, image.Name)
+	}
+
+	var latest string
+	var latestParts []int
+	for _, version := range versions {
+		if version.Name == nil || !deployable(version, location) {
+			continue
+		}
+		parts, ok := parseVersion(*version.Name)
+		if !ok {
+			continue
+		}
+		if latestParts == nil || compareVersions(parts, latestParts) > 0 {
+			latest, latestParts = *version.Name, parts
+		}
+	}
+	if latest == "" {
+		return "", azure.WithTransientError(errors.Errorf("no version of image %s/%s is available in location %s", image.Gallery, image.Name, location), noVersionRequeue)
+	}
+	return latest, nil
+}
+
+// deployable reports whether VMs deploying the latest version of an image in the location can use the image version.
+func deployable(version compute.GalleryImageVersion, location string) bool {
+	props := version.GalleryImageVersionProperties
+	if props == nil || props.ProvisioningState != compute.ProvisioningState3Succeeded || props.PublishingProfile == nil {
+		return false
+	}
+	if to.Bool(props.PublishingProfile.ExcludeFromLatest) || props.PublishingProfile.TargetRegions == nil {
+		return false
+	}
+	for _, region := range *props.PublishingProfile.TargetRegions {
+		// The galleries API returns the display names of the regions, e.g. "West US 2" for "westus2".
+		if region.Name != nil && strings.EqualFold(strings.ReplaceAll(*region.Name, " ", ""), strings.ReplaceAll(location, " ", "")) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseVersion parses a gallery image version in the Major.Minor.Patch format.
+func parseVersion(version string) ([]int, bool) {
+	fields := strings.Split(version, ".")
+	if len(fields) != 3 {
+		return nil, false
+	}
+	parts := make([]int, len(fields))
+	for i, field := range fields {
+		part, err := strconv.Atoi(field)
+		if err != nil || part < 0 {
+			return nil, false
+		}
+		parts[i] = part
+	}
+	return parts, true
+}
+
+// compareVersions compares two parsed gallery image versions.
+func compareVersions(a, b []int) int {
+	for i := range a {
+		switch {
+		case a[i] > b[i]:
+			return 1
+		case a[i] < b[i]:
+			return -1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package galleryimageversions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/galleryimageversions/mock_galleryimageversions"
+)
+
+func TestLatestVersion(t *testing.T) {
+	version := func(name string, excluded bool, state compute.ProvisioningState3, regions ...string) compute.GalleryImageVersion {
+		var targetRegions []compute.TargetRegion
+		for _, region := range regions {
+			targetRegions = append(targetRegions, compute.TargetRegion{Name: to.StringPtr(region)})
+		}
+		return compute.GalleryImageVersion{
+			Name: to.StringPtr(name),
+			GalleryImageVersionProperties: &compute.GalleryImageVersionProperties{
+				ProvisioningState: state,
+				PublishingProfile: &compute.GalleryImageVersionPublishingProfile{
+					ExcludeFromLatest: to.BoolPtr(excluded),
+					TargetRegions:     &targetRegions,
+				},
+			},
+		}
+	}
+	succeeded := compute.ProvisioningState3Succeeded
+
+	tests := []struct {
+		name          string
+		versions      []compute.GalleryImageVersion
+		want          string
+		expectedError string
+	}{
+		{
+			name: "highest version is compared numerically",
+			versions: []compute.GalleryImageVersion{
+				version("1.9.0", false, succeeded, "West US 2"),
+				version("1.10.0", false, succeeded, "West US 2"),
+				version("1.2.3", false, succeeded, "West US 2"),
+			},
+			want: "1.10.0",
+		},
+		{
+			name: "versions excluded from latest, not provisioned or not replicated into the location are skipped",
+			versions: []compute.GalleryImageVersion{
+				version("1.0.0", false, succeeded, "West US 2", "East US"),
+				version("1.1.0", true, succeeded, "West US 2"),
+				version("1.2.0", false, compute.ProvisioningState3Creating, "West US 2"),
+				version("1.3.0", false, succeeded, "East US"),
+			},
+			want: "1.0.0",
+		},
+		{
+			name: "no available version",
+			versions: []compute.GalleryImageVersion{
+				version("1.3.0", false, succeeded, "East US"),
+			},
+			expectedError: "no version of image my-gallery/my-image is available in location westus2",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_galleryimageversions.NewMockClient(mockCtrl)
+			clientMock.EXPECT().List(gomock.Any(), "123", "my-rg", "my-gallery", "my-image").Return(tc.versions, nil)
+
+			image := infrav1.AzureSharedGalleryImage{
+				SubscriptionID: "123",
+				ResourceGroup:  "my-rg",
+				Gallery:        "my-gallery",
+				Name:           "my-image",
+				Version:        azure.LatestVersion,
+			}
+			got, err := LatestVersion(context.TODO(), clientMock, image, "westus2")
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError) && reconcileError.IsTransient()).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(got).To(Equal(tc.want))
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -56,23 +57,25 @@ func newClient(auth azure.Authorizer) *azureClient {
 // newGroupsClient creates a new groups client from subscription ID.
 func newGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resources.GroupsClient {
 	groupsClient := resources.NewGroupsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&groupsClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&groupsClient.Client, authorizer, "groups")
 	return groupsClient
 }
 
 // Get gets a resource group.
-func (ac *azureClient) Get(ctx context.Context, name string) (resources.Group, error) {
+func (ac *azureClient) Get(ctx context.Context, name string) (_ resources.Group, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.groups.Get(ctx, name)
 }
 
 // CreateOrUpdateAsync creates or updates a resource group.
 // Creating a resource group is not a long running operation, so we don't ever return a future.
-func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (interface{}, azureautorest.FutureAPI, error) {
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ interface{}, _ azureautorest.FutureAPI, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	group, err := ac.resourceGroupParams(ctx, spec)
 	if err != nil {
@@ -91,9 +94,10 @@ func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 // progress of the operation.
 //
 // NOTE: When you delete a resource group, all of its resources are also deleted.
-func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (azureautorest.FutureAPI, error) {
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ azureautorest.FutureAPI, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.groups.Delete(ctx, spec.ResourceName())
 	if err != nil {
@@ -115,9 +119,10 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 }
 
 // IsDone returns true if the long-running operation has completed.
-func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (bool, error) {
+func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (_ bool, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureClient.IsDone")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	isDone, err := future.DoneWithContext(ctx, ac.groups)
 	if err != nil {
@@ -128,15 +133,18 @@ func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAP
 }
 
 // Result fetches the result of a long-running operation future.
-func (ac *azureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (interface{}, error) {
+func (ac *azureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (_ interface{}, err error) {
+	defer azureerrors.Classify(&err)
+
 	// Result is a no-op for resource groups as only Delete operations return a future.
 	return nil, nil
 }
 
 // resourceGroupParams returns the desired resource group parameters from the given spec.
-func (ac *azureClient) resourceGroupParams(ctx context.Context, spec azure.ResourceSpecGetter) (*resources.Group, error) {
+func (ac *azureClient) resourceGroupParams(ctx context.Context, spec azure.ResourceSpecGetter) (_ *resources.Group, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureClient.resourceGroupParams")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	var params interface{}
 
@@ -19,6 +19,7 @@ package groups
 import (
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 )
@@ -29,6 +30,8 @@ type GroupSpec struct {
 	Location       string
 	ClusterName    string
 	AdditionalTags infrav1.Tags
+	// ExistingOnly is true when the group must be created beforehand, as capz is not allowed to create it.
+	ExistingOnly bool
 }
 
 // ResourceName returns the name of the group.
@@ -54,6 +57,9 @@ func (s *GroupSpec) Parameters(existing interface{}) (interface{}, error) {
 		// Note that rg tags are updated separately using tags service.
 		return nil, nil
 	}
+	if s.ExistingOnly {
+		return nil, errors.Errorf("resource group %s does not exist and must be created beforehand", s.Name)
+	}
 	return resources.Group{
 		Location: to.StringPtr(s.Location),
 		// We create only CAPZ default tags. User defined additional tags
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groups
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/golden"
+)
+
+func TestParameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          GroupSpec
+		existing      interface{}
+		expectNil     bool
+		expectedError string
+	}{
+		{
+			name:      "group is created",
+			spec:      fakeGroupSpec,
+			existing:  nil,
+			expectNil: false,
+		},
+		{
+			name:      "existing group is not updated",
+			spec:      fakeGroupSpec,
+			existing:  sampleBYOGroup,
+			expectNil: true,
+		},
+		{
+			name: "existing group is not updated when it must be created beforehand",
+			spec: GroupSpec{
+				Name:         "test-group",
+				Location:     "test-location",
+				ClusterName:  "test-cluster",
+				ExistingOnly: true,
+			},
+			existing:  sampleBYOGroup,
+			expectNil: true,
+		},
+		{
+			name: "missing group is not created when it must be created beforehand",
+			spec: GroupSpec{
+				Name:         "test-group",
+				Location:     "test-location",
+				ClusterName:  "test-cluster",
+				ExistingOnly: true,
+			},
+			existing:      nil,
+			expectedError: "resource group test-group does not exist and must be created beforehand",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expectNil {
+				g.Expect(result).To(BeNil())
+			} else {
+				g.Expect(result).To(BeAssignableToTypeOf(resources.Group{}))
+			}
+		})
+	}
+}
+
+func TestGroupPayload(t *testing.T) {
+	g := NewWithT(t)
+
+	result, err := fakeGroupSpec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	golden.Assert(t, "group", result)
+}
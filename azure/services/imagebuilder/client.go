@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagebuilder
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/services/virtualmachineimagebuilder/mgmt/2020-02-14/virtualmachineimagebuilder"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, string, string) (virtualmachineimagebuilder.ImageTemplate, error)
+	Run(context.Context, string, string) error
+	GetRunOutput(context.Context, string, string, string) (virtualmachineimagebuilder.RunOutput, error)
+	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter) (interface{}, azureautorest.FutureAPI, error)
+	DeleteAsync(context.Context, azure.ResourceSpecGetter) (azureautorest.FutureAPI, error)
+	Result(context.Context, azureautorest.FutureAPI, string) (interface{}, error)
+	IsDone(context.Context, azureautorest.FutureAPI) (bool, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	templates virtualmachineimagebuilder.VirtualMachineImageTemplatesClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new image templates client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newImageTemplatesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c}
+}
+
+// newImageTemplatesClient creates a new image templates client from subscription ID.
+func newImageTemplatesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) virtualmachineimagebuilder.VirtualMachineImageTemplatesClient {
+	templatesClient := virtualmachineimagebuilder.NewVirtualMachineImageTemplatesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&templatesClient.Client, authorizer, serviceName)
+	return templatesClient
+}
+
+// Get gets the specified image template by the template name and resource group.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, templateName string) (_ virtualmachineimagebuilder.ImageTemplate, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagebuilder.AzureClient.Get")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.templates.Get(ctx, resourceGroupName, templateName)
+}
+
+// Run starts building the image of an image template. It doesn't wait for the build to complete, as it takes a long
+// time: its progress is reported by the last run status of the template.
+func (ac *azureClient) Run(ctx context.Context, resourceGroupName, templateName string) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagebuilder.AzureClient.Run")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.templates.Run(ctx, resourceGroupName, templateName)
+	return err
+}
+
+// GetRunOutput gets the artifact published by the last run of an image template.
+func (ac *azureClient) GetRunOutput(ctx context.Context, resourceGroupName, templateName, runOutputName string) (_ virtualmachineimagebuilder.RunOutput, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagebuilder.AzureClient.GetRunOutput")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.templates.GetRunOutput(ctx, resourceGroupName, templateName, runOutputName)
+}
+
+// CreateOrUpdateAsync creates an image template asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ interface{}, _ azureautorest.FutureAPI, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagebuilder.AzureClient.CreateOrUpdateAsync")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	var existingTemplate interface{}
+
+	if existing, err := ac.Get(ctx, spec.ResourceGroupName(), spec.ResourceName()); err != nil && !azure.ResourceNotFound(err) {
+		return nil, nil, errors.Wrapf(err, "failed to get image template %s in %s", spec.ResourceName(), spec.ResourceGroupName())
+	} else if err == nil {
+		existingTemplate = existing
+	}
+
+	params, err := spec.Parameters(existingTemplate)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get desired parameters for image template %s", spec.ResourceName())
+	}
+
+	template, ok := params.(virtualmachineimagebuilder.ImageTemplate)
+	if !ok {
+		if params == nil {
+			// nothing to do here.
+			return existingTemplate, nil, nil
+		}
+		return nil, nil, errors.Errorf("%T is not a virtualmachineimagebuilder.ImageTemplate", params)
+	}
+
+	future, err := ac.templates.CreateOrUpdate(ctx, template, spec.ResourceGroupName(), spec.ResourceName())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = future.WaitForCompletionRef(ctx, ac.templates.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, &future, err
+	}
+
+	result, err := future.Result(ac.templates)
+	// if the operation completed, return a nil future
+	return result, nil, err
+}
+
+// DeleteAsync deletes an image template asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ azureautorest.FutureAPI, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagebuilder.AzureClient.DeleteAsync")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.templates.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = future.WaitForCompletionRef(ctx, ac.templates.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return &future, err
+	}
+	_, err = future.Result(ac.templates)
+	// if the operation completed, return a nil future.
+	return nil, err
+}
+
+// Result fetches the result of a long-running operation future.
+func (ac *azureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (_ interface{}, err error) {
+	defer azureerrors.Classify(&err)
+
+	if futureData == nil {
+		return nil, errors.Errorf("cannot get result from nil future")
+	}
+
+	switch futureType {
+	case infrav1.PutFuture:
+		var future *virtualmachineimagebuilder.VirtualMachineImageTemplatesCreateOrUpdateFuture
+		jsonData, err := futureData.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal future")
+		}
+		if err := json.Unmarshal(jsonData, &future); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal future data")
+		}
+		return future.Result(ac.templates)
+
+	case infrav1.DeleteFuture:
+		// Delete does not return a result image template
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("unknown future type %q", futureType)
+	}
+}
+
+// IsDone returns true if the long-running operation has completed.
+func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (_ bool, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagebuilder.AzureClient.IsDone")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	isDone, err := future.DoneWithContext(ctx, ac.templates)
+	if err != nil {
+		return false, errors.Wrap(err, "failed checking if the operation was complete")
+	}
+
+	return isDone, nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagebuilder
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/virtualmachineimagebuilder/mgmt/2020-02-14/virtualmachineimagebuilder"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	serviceName = "imagebuilder"
+
+	// buildRequeue is how often the progress of a build is checked. Builds take from several minutes to hours.
+	buildRequeue = 2 * time.Minute
+)
+
+// ImageBuilderScope defines the scope interface for an image builder service.
+type ImageBuilderScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	ImageTemplateSpec() azure.ResourceSpecGetter
+	RunState() string
+	SetRunStatus(state, message string)
+	SetImage(image *infrav1.Image)
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope ImageBuilderScope
+	client
+}
+
+// New creates a new image builder service.
+func New(scope ImageBuilderScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile creates the image template of the node image, starts building the image once and reports the progress of
+// the build. When the build succeeds, the image version it published to the gallery is set as the image of the scope.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagebuilder.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec, ok := s.Scope.ImageTemplateSpec().(*ImageTemplateSpec)
+	if !ok {
+		return errors.Errorf("%T is not a *ImageTemplateSpec", s.Scope.ImageTemplateSpec())
+	}
+	if _, err := async.CreateResource(ctx, s.Scope, s.client, spec, serviceName); err != nil {
+		s.Scope.UpdatePutStatus(infrav1exp.ImageBuiltCondition, serviceName, err)
+		return err
+	}
+
+	template, err := s.client.Get(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	if err != nil {
+		return errors.Wrapf(err, "failed to get image template %s", spec.ResourceName())
+	}
+
+	var lastRun *virtualmachineimagebuilder.ImageTemplateLastRunStatus
+	if template.ImageTemplateProperties != nil {
+		lastRun = template.LastRunStatus
+	}
+	if lastRun == nil {
+		if s.Scope.RunState() != "" {
+			// The build was started, but Azure Image Builder doesn't report it yet.
+			return azure.WithTransientError(errors.Errorf("waiting for the build of image template %s to be reported", spec.ResourceName()), buildRequeue)
+		}
+		if err := s.client.Run(ctx, spec.ResourceGroupName(), spec.ResourceName()); err != nil {
+			return errors.Wrapf(err, "failed to start building image template %s", spec.ResourceName())
+		}
+		s.Scope.SetRunStatus(string(virtualmachineimagebuilder.RunStateRunning), "build started")
+		return azure.WithTransientError(errors.Errorf("started building image template %s", spec.ResourceName()), buildRequeue)
+	}
+
+	state, message := string(lastRun.RunState), to.String(lastRun.Message)
+	switch lastRun.RunState {
+	case virtualmachineimagebuilder.RunStateRunning, virtualmachineimagebuilder.RunStateCanceling:
+		s.Scope.SetRunStatus(state, message)
+		return azure.WithTransientError(errors.Errorf("image template %s is still building (%s)", spec.ResourceName(), lastRun.RunSubState), buildRequeue)
+	case virtualmachineimagebuilder.RunStateSucceeded:
+		output, err := s.client.GetRunOutput(ctx, spec.ResourceGroupName(), spec.ResourceName(), spec.RunOutputName())
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the output of image template %s", spec.ResourceName())
+		}
+		if output.RunOutputProperties == nil || output.ArtifactID == nil {
+			return errors.Errorf("image template %s has no output artifact", spec.ResourceName())
+		}
+		image, err := galleryImage(spec, *output.ArtifactID)
+		if err != nil {
+			return err
+		}
+		s.Scope.SetImage(image)
+		s.Scope.SetRunStatus(state, message)
+		return nil
+	default:
+		// Failed, partially succeeded and canceled builds aren't retried, a new AzureNodeImage must be created.
+		s.Scope.SetRunStatus(state, message)
+		return azure.WithTerminalError(errors.Errorf("build of image template %s %s: %s", spec.ResourceName(), strings.ToLower(state), message))
+	}
+}
+
+// Delete deletes the image template of the node image. The image version it published is kept, as machines may still
+// use it.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagebuilder.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	err := async.DeleteResource(ctx, s.Scope, s.client, s.Scope.ImageTemplateSpec(), serviceName)
+	s.Scope.UpdateDeleteStatus(infrav1exp.ImageBuiltCondition, serviceName, err)
+	return err
+}
+
+// galleryImage returns the shared gallery image of the image version published by an image template.
+func galleryImage(spec *ImageTemplateSpec, artifactID string) (*infrav1.Image, error) {
+	imageID := azure.GalleryImageID(spec.SubscriptionID, spec.Distribution.ResourceGroup, spec.Distribution.Gallery, spec.Distribution.Name)
+	if !strings.HasPrefix(strings.ToLower(artifactID), strings.ToLower(imageID)+"/versions/") {
+		return nil, errors.Errorf("output artifact %s of image template %s is not a version of %s", artifactID, spec.ResourceName(), imageID)
+	}
+	image := &infrav1.AzureSharedGalleryImage{
+		SubscriptionID: spec.SubscriptionID,
+		ResourceGroup:  spec.Distribution.ResourceGroup,
+		Gallery:        spec.Distribution.Gallery,
+		Name:           spec.Distribution.Name,
+		Version:        path.Base(artifactID),
+	}
+	// VMs created from images built from third party images need the plan of the base image.
+	if source := spec.Source.Marketplace; source != nil && source.ThirdPartyImage {
+		image.Publisher = to.StringPtr(source.Publisher)
+		image.Offer = to.StringPtr(source.Offer)
+		image.SKU = to.StringPtr(source.SKU)
+	}
+	return &infrav1.Image{SharedGallery: image}, nil
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagebuilder
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/virtualmachineimagebuilder/mgmt/2020-02-14/virtualmachineimagebuilder"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagebuilder/mock_imagebuilder"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeImageTemplateSpec = ImageTemplateSpec{
+		Name:            "default-ubuntu",
+		ResourceGroup:   "my-rg",
+		Location:        "westus2",
+		SubscriptionID:  "123",
+		BuildIdentityID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/aib",
+		Source: infrav1exp.NodeImageSource{
+			Marketplace: &infrav1.AzureMarketplaceImage{
+				Publisher: "cncf-upstream",
+				Offer:     "capi",
+				SKU:       "ubuntu-2004-gen1",
+				Version:   "latest",
+			},
+		},
+		Distribution: infrav1exp.NodeImageDistribution{
+			ResourceGroup: "gallery-rg",
+			Gallery:       "my_gallery",
+			Name:          "capi-ubuntu-2004",
+		},
+	}
+
+	fakeArtifactID = "/subscriptions/123/resourceGroups/gallery-rg/providers/Microsoft.Compute/galleries/my_gallery/images/capi-ubuntu-2004/versions/0.24763.13016"
+
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func templateWithLastRun(state virtualmachineimagebuilder.RunState, message string) virtualmachineimagebuilder.ImageTemplate {
+	return virtualmachineimagebuilder.ImageTemplate{
+		ImageTemplateProperties: &virtualmachineimagebuilder.ImageTemplateProperties{
+			LastRunStatus: &virtualmachineimagebuilder.ImageTemplateLastRunStatus{
+				RunState:    state,
+				RunSubState: virtualmachineimagebuilder.RunSubStateCustomizing,
+				Message:     to.StringPtr(message),
+			},
+		},
+	}
+}
+
+func TestReconcileImageBuilder(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		transient     bool
+		expect        func(s *mock_imagebuilder.MockImageBuilderScopeMockRecorder, m *mock_imagebuilder.MockclientMockRecorder)
+	}{
+		{
+			name:          "create the template and start the build",
+			expectedError: "started building image template default-ubuntu",
+			transient:     true,
+			expect: func(s *mock_imagebuilder.MockImageBuilderScopeMockRecorder, m *mock_imagebuilder.MockclientMockRecorder) {
+				s.ImageTemplateSpec().Return(&fakeImageTemplateSpec)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("default-ubuntu", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeImageTemplateSpec).Return(nil, nil, nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "default-ubuntu").Return(virtualmachineimagebuilder.ImageTemplate{ImageTemplateProperties: &virtualmachineimagebuilder.ImageTemplateProperties{}}, nil),
+					s.RunState().Return(""),
+					m.Run(gomockinternal.AContext(), "my-rg", "default-ubuntu").Return(nil),
+					s.SetRunStatus("Running", "build started"),
+				)
+			},
+		},
+		{
+			name:          "wait for a started build to be reported",
+			expectedError: "waiting for the build of image template default-ubuntu to be reported",
+			transient:     true,
+			expect: func(s *mock_imagebuilder.MockImageBuilderScopeMockRecorder, m *mock_imagebuilder.MockclientMockRecorder) {
+				s.ImageTemplateSpec().Return(&fakeImageTemplateSpec)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("default-ubuntu", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeImageTemplateSpec).Return(nil, nil, nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "default-ubuntu").Return(virtualmachineimagebuilder.ImageTemplate{}, nil),
+					s.RunState().Return("Running"),
+				)
+			},
+		},
+		{
+			name:          "report a running build",
+			expectedError: "image template default-ubuntu is still building (Customizing)",
+			transient:     true,
+			expect: func(s *mock_imagebuilder.MockImageBuilderScopeMockRecorder, m *mock_imagebuilder.MockclientMockRecorder) {
+				s.ImageTemplateSpec().Return(&fakeImageTemplateSpec)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("default-ubuntu", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeImageTemplateSpec).Return(nil, nil, nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "default-ubuntu").Return(templateWithLastRun(virtualmachineimagebuilder.RunStateRunning, ""), nil),
+					s.SetRunStatus("Running", ""),
+				)
+			},
+		},
+		{
+			name:          "publish the image of a successful build",
+			expectedError: "",
+			expect: func(s *mock_imagebuilder.MockImageBuilderScopeMockRecorder, m *mock_imagebuilder.MockclientMockRecorder) {
+				s.ImageTemplateSpec().Return(&fakeImageTemplateSpec)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("default-ubuntu", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeImageTemplateSpec).Return(nil, nil, nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "default-ubuntu").Return(templateWithLastRun(virtualmachineimagebuilder.RunStateSucceeded, ""), nil),
+					m.GetRunOutput(gomockinternal.AContext(), "my-rg", "default-ubuntu", "default-ubuntu").Return(virtualmachineimagebuilder.RunOutput{
+						RunOutputProperties: &virtualmachineimagebuilder.RunOutputProperties{ArtifactID: to.StringPtr(fakeArtifactID)},
+					}, nil),
+					s.SetImage(&infrav1.Image{
+						SharedGallery: &infrav1.AzureSharedGalleryImage{
+							SubscriptionID: "123",
+							ResourceGroup:  "gallery-rg",
+							Gallery:        "my_gallery",
+							Name:           "capi-ubuntu-2004",
+							Version:        "0.24763.13016",
+						},
+					}),
+					s.SetRunStatus("Succeeded", ""),
+				)
+			},
+		},
+		{
+			name:          "output artifact of a successful build is not in the gallery",
+			expectedError: "output artifact /subscriptions/123/resourceGroups/gallery-rg/providers/Microsoft.Compute/images/capi-ubuntu-2004 of image template default-ubuntu is not a version of /subscriptions/123/resourceGroups/gallery-rg/providers/Microsoft.Compute/galleries/my_gallery/images/capi-ubuntu-2004",
+			expect: func(s *mock_imagebuilder.MockImageBuilderScopeMockRecorder, m *mock_imagebuilder.MockclientMockRecorder) {
+				s.ImageTemplateSpec().Return(&fakeImageTemplateSpec)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("default-ubuntu", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeImageTemplateSpec).Return(nil, nil, nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "default-ubuntu").Return(templateWithLastRun(virtualmachineimagebuilder.RunStateSucceeded, ""), nil),
+					m.GetRunOutput(gomockinternal.AContext(), "my-rg", "default-ubuntu", "default-ubuntu").Return(virtualmachineimagebuilder.RunOutput{
+						RunOutputProperties: &virtualmachineimagebuilder.RunOutputProperties{ArtifactID: to.StringPtr("/subscriptions/123/resourceGroups/gallery-rg/providers/Microsoft.Compute/images/capi-ubuntu-2004")},
+					}, nil),
+				)
+			},
+		},
+		{
+			name:          "failed build",
+			expectedError: "build of image template default-ubuntu failed: customization script failed",
+			expect: func(s *mock_imagebuilder.MockImageBuilderScopeMockRecorder, m *mock_imagebuilder.MockclientMockRecorder) {
+				s.ImageTemplateSpec().Return(&fakeImageTemplateSpec)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("default-ubuntu", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeImageTemplateSpec).Return(nil, nil, nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "default-ubuntu").Return(templateWithLastRun(virtualmachineimagebuilder.RunStateFailed, "customization script failed"), nil),
+					s.SetRunStatus("Failed", "customization script failed"),
+				)
+			},
+		},
+		{
+			name:          "error while trying to create the template",
+			expectedError: "failed to create resource my-rg/default-ubuntu (service: imagebuilder): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_imagebuilder.MockImageBuilderScopeMockRecorder, m *mock_imagebuilder.MockclientMockRecorder) {
+				s.ImageTemplateSpec().Return(&fakeImageTemplateSpec)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("default-ubuntu", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeImageTemplateSpec).Return(nil, nil, internalError),
+					s.UpdatePutStatus(infrav1exp.ImageBuiltCondition, serviceName, gomockinternal.ErrStrEq("failed to create resource my-rg/default-ubuntu (service: imagebuilder): #: Internal Server Error: StatusCode=500")),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_imagebuilder.NewMockImageBuilderScope(mockCtrl)
+			clientMock := mock_imagebuilder.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError) && reconcileError.IsTransient()).To(Equal(tc.transient))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteImageBuilder(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_imagebuilder.NewMockImageBuilderScope(mockCtrl)
+	clientMock := mock_imagebuilder.NewMockclient(mockCtrl)
+
+	s := scopeMock.EXPECT()
+	m := clientMock.EXPECT()
+	s.ImageTemplateSpec().Return(&fakeImageTemplateSpec)
+	gomock.InOrder(
+		s.GetLongRunningOperationState("default-ubuntu", serviceName),
+		m.DeleteAsync(gomockinternal.AContext(), &fakeImageTemplateSpec).Return(nil, nil),
+		s.UpdateDeleteStatus(infrav1exp.ImageBuiltCondition, serviceName, nil),
+	)
+
+	err := (&Service{Scope: scopeMock, client: clientMock}).Delete(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+}
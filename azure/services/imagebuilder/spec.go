@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagebuilder
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/virtualmachineimagebuilder/mgmt/2020-02-14/virtualmachineimagebuilder"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+)
+
+// ImageTemplateSpec defines the specification for an Azure Image Builder template building a node image and
+// publishing it to a gallery.
+type ImageTemplateSpec struct {
+	Name                  string
+	ResourceGroup         string
+	Location              string
+	SubscriptionID        string
+	BuildIdentityID       string
+	Source                infrav1exp.NodeImageSource
+	Customizations        []infrav1exp.NodeImageCustomization
+	Distribution          infrav1exp.NodeImageDistribution
+	VMSize                string
+	OSDiskSizeGB          *int32
+	BuildTimeoutInMinutes *int32
+	AdditionalTags        infrav1.Tags
+}
+
+// ResourceName returns the name of the image template.
+func (s *ImageTemplateSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *ImageTemplateSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for image templates.
+func (s *ImageTemplateSpec) OwnerResourceName() string {
+	return ""
+}
+
+// RunOutputName returns the name of the run output holding the image version published by the template.
+func (s *ImageTemplateSpec) RunOutputName() string {
+	return s.Name
+}
+
+// Parameters returns the parameters for the image template. Image templates can't be updated, so it is a no-op for an
+// existing template.
+func (s *ImageTemplateSpec) Parameters(existing interface{}) (interface{}, error) {
+	if existing != nil {
+		if _, ok := existing.(virtualmachineimagebuilder.ImageTemplate); !ok {
+			return nil, errors.Errorf("%T is not a virtualmachineimagebuilder.ImageTemplate", existing)
+		}
+		return nil, nil
+	}
+
+	source, err := s.source()
+	if err != nil {
+		return nil, err
+	}
+
+	replicationRegions := s.Distribution.ReplicationRegions
+	if len(replicationRegions) == 0 {
+		replicationRegions = []string{s.Location}
+	}
+	tags := converters.TagsToMap(s.AdditionalTags)
+	distributor := virtualmachineimagebuilder.ImageTemplateSharedImageDistributor{
+		GalleryImageID:     to.StringPtr(azure.GalleryImageID(s.SubscriptionID, s.Distribution.ResourceGroup, s.Distribution.Gallery, s.Distribution.Name)),
+		ReplicationRegions: &replicationRegions,
+		ExcludeFromLatest:  to.BoolPtr(s.Distribution.ExcludeFromLatest),
+		RunOutputName:      to.StringPtr(s.RunOutputName()),
+		ArtifactTags:       tags,
+		Type:               virtualmachineimagebuilder.TypeBasicImageTemplateDistributorTypeSharedImage,
+	}
+
+	template := virtualmachineimagebuilder.ImageTemplate{
+		Location: to.StringPtr(s.Location),
+		Identity: &virtualmachineimagebuilder.ImageTemplateIdentity{
+			Type: virtualmachineimagebuilder.ResourceIdentityTypeUserAssigned,
+			UserAssignedIdentities: map[string]*virtualmachineimagebuilder.ImageTemplateIdentityUserAssignedIdentitiesValue{
+				s.BuildIdentityID: {},
+			},
+		},
+		ImageTemplateProperties: &virtualmachineimagebuilder.ImageTemplateProperties{
+			Source:                source,
+			Distribute:            &[]virtualmachineimagebuilder.BasicImageTemplateDistributor{distributor},
+			BuildTimeoutInMinutes: s.BuildTimeoutInMinutes,
+		},
+		Tags: tags,
+	}
+	if len(s.Customizations) > 0 {
+		customizers := make([]virtualmachineimagebuilder.BasicImageTemplateCustomizer, 0, len(s.Customizations))
+		for _, customization := range s.Customizations {
+			customizer, err := customizer(customization)
+			if err != nil {
+				return nil, err
+			}
+			customizers = append(customizers, customizer)
+		}
+		template.Customize = &customizers
+	}
+	if s.VMSize != "" || s.OSDiskSizeGB != nil {
+		template.VMProfile = &virtualmachineimagebuilder.ImageTemplateVMProfile{
+			OsDiskSizeGB: s.OSDiskSizeGB,
+		}
+		if s.VMSize != "" {
+			template.VMProfile.VMSize = to.StringPtr(s.VMSize)
+		}
+	}
+
+	return template, nil
+}
+
+// source returns the source of the image template from the base image of the node image.
+func (s *ImageTemplateSpec) source() (virtualmachineimagebuilder.BasicImageTemplateSource, error) {
+	switch {
+	case s.Source.Marketplace != nil:
+		image := s.Source.Marketplace
+		source := virtualmachineimagebuilder.ImageTemplatePlatformImageSource{
+			Publisher: to.StringPtr(image.Publisher),
+			Offer:     to.StringPtr(image.Offer),
+			Sku:       to.StringPtr(image.SKU),
+			Version:   to.StringPtr(image.Version),
+			Type:      virtualmachineimagebuilder.TypePlatformImage,
+		}
+		if image.ThirdPartyImage {
+			source.PlanInfo = &virtualmachineimagebuilder.PlatformImagePurchasePlan{
+				PlanName:      to.StringPtr(image.SKU),
+				PlanProduct:   to.StringPtr(image.Offer),
+				PlanPublisher: to.StringPtr(image.Publisher),
+			}
+		}
+		return source, nil
+	case s.Source.SharedGallery != nil:
+		image := s.Source.SharedGallery
+		return virtualmachineimagebuilder.ImageTemplateSharedImageVersionSource{
+			ImageVersionID: to.StringPtr(azure.GalleryImageVersionID(image.SubscriptionID, image.ResourceGroup, image.Gallery, image.Name, image.Version)),
+			Type:           virtualmachineimagebuilder.TypeSharedImageVersion,
+		}, nil
+	default:
+		return nil, errors.New("node image has no source image")
+	}
+}
+
+// customizer returns the image template customizer of a customization step of the node image.
+func customizer(customization infrav1exp.NodeImageCustomization) (virtualmachineimagebuilder.BasicImageTemplateCustomizer, error) {
+	switch {
+	case customization.Shell != nil:
+		script := customization.Shell
+		return virtualmachineimagebuilder.ImageTemplateShellCustomizer{
+			Name:           to.StringPtr(customization.Name),
+			Inline:         inline(script.Inline),
+			ScriptURI:      stringPtrOrNil(script.ScriptURI),
+			Sha256Checksum: stringPtrOrNil(script.SHA256Checksum),
+			Type:           virtualmachineimagebuilder.TypeBasicImageTemplateCustomizerTypeShell,
+		}, nil
+	case customization.PowerShell != nil:
+		script := customization.PowerShell
+		return virtualmachineimagebuilder.ImageTemplatePowerShellCustomizer{
+			Name:           to.StringPtr(customization.Name),
+			Inline:         inline(script.Inline),
+			ScriptURI:      stringPtrOrNil(script.ScriptURI),
+			Sha256Checksum: stringPtrOrNil(script.SHA256Checksum),
+			Type:           virtualmachineimagebuilder.TypeBasicImageTemplateCustomizerTypePowerShell,
+		}, nil
+	case customization.File != nil:
+		file := customization.File
+		return virtualmachineimagebuilder.ImageTemplateFileCustomizer{
+			Name:           to.StringPtr(customization.Name),
+			SourceURI:      to.StringPtr(file.SourceURI),
+			Destination:    to.StringPtr(file.Destination),
+			Sha256Checksum: stringPtrOrNil(file.SHA256Checksum),
+			Type:           virtualmachineimagebuilder.TypeBasicImageTemplateCustomizerTypeFile,
+		}, nil
+	default:
+		return nil, errors.Errorf("customization %s has no step", customization.Name)
+	}
+}
+
+func inline(commands []string) *[]string {
+	if len(commands) == 0 {
+		return nil
+	}
+	return &commands
+}
+
+func stringPtrOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return to.StringPtr(s)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagebuilder
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/virtualmachineimagebuilder/mgmt/2020-02-14/virtualmachineimagebuilder"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/golden"
+)
+
+func TestImageTemplateSpec_Parameters(t *testing.T) {
+	customizedSpec := fakeImageTemplateSpec
+	customizedSpec.Source = infrav1exp.NodeImageSource{
+		SharedGallery: &infrav1.AzureSharedGalleryImage{
+			SubscriptionID: "456",
+			ResourceGroup:  "base-rg",
+			Gallery:        "base_gallery",
+			Name:           "base",
+			Version:        "1.0.0",
+		},
+	}
+	customizedSpec.Customizations = []infrav1exp.NodeImageCustomization{
+		{Name: "packages", Shell: &infrav1exp.ScriptCustomization{Inline: []string{"sudo apt-get install -y jq"}}},
+		{Name: "config", File: &infrav1exp.FileCustomization{SourceURI: "https://example.com/config", Destination: "/tmp/config"}},
+	}
+	customizedSpec.Distribution.ReplicationRegions = []string{"eastus", "westeurope"}
+	customizedSpec.VMSize = "Standard_D4s_v3"
+	customizedSpec.AdditionalTags = infrav1.Tags{"team": "platform"}
+
+	testcases := []struct {
+		name          string
+		spec          *ImageTemplateSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name:     "template already exists",
+			spec:     &fakeImageTemplateSpec,
+			existing: virtualmachineimagebuilder.ImageTemplate{Name: to.StringPtr("default-ubuntu")},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:          "existing is not a template",
+			spec:          &fakeImageTemplateSpec,
+			existing:      "foo",
+			expectedError: "string is not a virtualmachineimagebuilder.ImageTemplate",
+		},
+		{
+			name: "template from a marketplace image",
+			spec: &fakeImageTemplateSpec,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(virtualmachineimagebuilder.ImageTemplate{}))
+				template := result.(virtualmachineimagebuilder.ImageTemplate)
+				g.Expect(template.Location).To(Equal(to.StringPtr("westus2")))
+				g.Expect(template.Identity.Type).To(Equal(virtualmachineimagebuilder.ResourceIdentityTypeUserAssigned))
+				g.Expect(template.Identity.UserAssignedIdentities).To(HaveKey(fakeImageTemplateSpec.BuildIdentityID))
+				g.Expect(template.Source).To(Equal(virtualmachineimagebuilder.ImageTemplatePlatformImageSource{
+					Publisher: to.StringPtr("cncf-upstream"),
+					Offer:     to.StringPtr("capi"),
+					Sku:       to.StringPtr("ubuntu-2004-gen1"),
+					Version:   to.StringPtr("latest"),
+					Type:      virtualmachineimagebuilder.TypePlatformImage,
+				}))
+				g.Expect(template.Customize).To(BeNil())
+				g.Expect(template.VMProfile).To(BeNil())
+				g.Expect(*template.Distribute).To(ConsistOf(virtualmachineimagebuilder.ImageTemplateSharedImageDistributor{
+					GalleryImageID:     to.StringPtr("/subscriptions/123/resourceGroups/gallery-rg/providers/Microsoft.Compute/galleries/my_gallery/images/capi-ubuntu-2004"),
+					ReplicationRegions: &[]string{"westus2"},
+					ExcludeFromLatest:  to.BoolPtr(false),
+					RunOutputName:      to.StringPtr("default-ubuntu"),
+					ArtifactTags:       map[string]*string{},
+					Type:               virtualmachineimagebuilder.TypeBasicImageTemplateDistributorTypeSharedImage,
+				}))
+			},
+		},
+		{
+			name: "customized template from a shared gallery image",
+			spec: &customizedSpec,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(virtualmachineimagebuilder.ImageTemplate{}))
+				template := result.(virtualmachineimagebuilder.ImageTemplate)
+				g.Expect(template.Source).To(Equal(virtualmachineimagebuilder.ImageTemplateSharedImageVersionSource{
+					ImageVersionID: to.StringPtr("/subscriptions/456/resourceGroups/base-rg/providers/Microsoft.Compute/galleries/base_gallery/images/base/versions/1.0.0"),
+					Type:           virtualmachineimagebuilder.TypeSharedImageVersion,
+				}))
+				g.Expect(*template.Customize).To(Equal([]virtualmachineimagebuilder.BasicImageTemplateCustomizer{
+					virtualmachineimagebuilder.ImageTemplateShellCustomizer{
+						Name:   to.StringPtr("packages"),
+						Inline: &[]string{"sudo apt-get install -y jq"},
+						Type:   virtualmachineimagebuilder.TypeBasicImageTemplateCustomizerTypeShell,
+					},
+					virtualmachineimagebuilder.ImageTemplateFileCustomizer{
+						Name:        to.StringPtr("config"),
+						SourceURI:   to.StringPtr("https://example.com/config"),
+						Destination: to.StringPtr("/tmp/config"),
+						Type:        virtualmachineimagebuilder.TypeBasicImageTemplateCustomizerTypeFile,
+					},
+				}))
+				g.Expect(template.VMProfile).To(Equal(&virtualmachineimagebuilder.ImageTemplateVMProfile{VMSize: to.StringPtr("Standard_D4s_v3")}))
+				g.Expect(template.Tags).To(HaveKeyWithValue("team", to.StringPtr("platform")))
+				distributor := (*template.Distribute)[0].(virtualmachineimagebuilder.ImageTemplateSharedImageDistributor)
+				g.Expect(*distributor.ReplicationRegions).To(Equal([]string{"eastus", "westeurope"}))
+				g.Expect(distributor.ArtifactTags).To(HaveKeyWithValue("team", to.StringPtr("platform")))
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				tc.expect(g, result)
+			}
+		})
+	}
+}
+
+func TestImageTemplatePayload(t *testing.T) {
+	g := NewWithT(t)
+
+	result, err := fakeImageTemplateSpec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	golden.Assert(t, "imagetemplate", result)
+}
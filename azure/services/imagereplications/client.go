@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagereplications
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, azure.ImageReplicationSpec) (compute.GalleryImageVersion, error)
+	AddTargetRegion(context.Context, azure.ImageReplicationSpec, compute.GalleryImageVersion) error
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	baseURI    string
+	authorizer autorest.Authorizer
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new gallery image versions client. The galleries can be in other subscriptions than the one of
+// the cluster, so the clients of the subscriptions are created on demand.
+func newClient(auth azure.Authorizer) *azureClient {
+	return &azureClient{
+		baseURI:    auth.BaseURI(),
+		authorizer: auth.Authorizer(),
+	}
+}
+
+// newGalleryImageVersionsClient creates a new gallery image versions client from subscription ID.
+func newGalleryImageVersionsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.GalleryImageVersionsClient {
+	versionsClient := compute.NewGalleryImageVersionsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&versionsClient.Client, authorizer, "imagereplications")
+	return versionsClient
+}
+
+// Get gets the gallery image version of the spec, with its replication status.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ImageReplicationSpec) (_ compute.GalleryImageVersion, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagereplications.AzureClient.Get")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	versions := newGalleryImageVersionsClient(spec.SubscriptionID, ac.baseURI, ac.authorizer)
+	return versions.Get(ctx, spec.ResourceGroup, spec.Gallery, spec.Image, spec.Version, compute.ReplicationStatusTypesReplicationStatus)
+}
+
+// AddTargetRegion adds the location of the spec to the target regions of the gallery image version. It doesn't wait
+// for the update, whose progress is reported by the replication status of the image version.
+func (ac *azureClient) AddTargetRegion(ctx context.Context, spec azure.ImageReplicationSpec, version compute.GalleryImageVersion) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagereplications.AzureClient.AddTargetRegion")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	var targetRegions []compute.TargetRegion
+	if version.GalleryImageVersionProperties != nil && version.PublishingProfile != nil && version.PublishingProfile.TargetRegions != nil {
+		targetRegions = append(targetRegions, *version.PublishingProfile.TargetRegions...)
+	}
+	targetRegions = append(targetRegions, compute.TargetRegion{Name: to.StringPtr(spec.Location)})
+
+	update := compute.GalleryImageVersionUpdate{
+		GalleryImageVersionProperties: &compute.GalleryImageVersionProperties{
+			PublishingProfile: &compute.GalleryImageVersionPublishingProfile{
+				TargetRegions: &targetRegions,
+			},
+		},
+	}
+	versions := newGalleryImageVersionsClient(spec.SubscriptionID, ac.baseURI, ac.authorizer)
+	_, err = versions.Update(ctx, spec.ResourceGroup, spec.Gallery, spec.Image, spec.Version, update)
+	return err
+}
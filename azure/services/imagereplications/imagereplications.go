@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagereplications
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// notReplicatedRequeue is how long to wait for an image version to be added to the location of the machine.
+	notReplicatedRequeue = 5 * time.Minute
+	// replicatingRequeue is how long to wait between checks of a replication in progress.
+	replicatingRequeue = time.Minute
+)
+
+// ImageReplicationScope defines the scope interface for an image replications service.
+type ImageReplicationScope interface {
+	azure.Authorizer
+	ImageReplicationSpec() *azure.ImageReplicationSpec
+	UpdateImageReplicationStatus(reason string, err error)
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope ImageReplicationScope
+	client
+}
+
+// New creates a new image replications service.
+func New(scope ImageReplicationScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile checks that the gallery image version of the machine is replicated into its location and reports it in a
+// condition. A replication which is missing or in progress returns a transient error so that the VM is only created
+// once the image version is available in its location. When requested, the location is added to the target regions of
+// the image version.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "imagereplications.Service.Reconcile")
+	defer done()
+
+	spec := s.Scope.ImageReplicationSpec()
+	if spec == nil {
+		return nil
+	}
+	ref := fmt.Sprintf("%s/%s/%s", spec.Gallery, spec.Image, spec.Version)
+
+	version, err := s.client.Get(ctx, *spec)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get image version %s", ref)
+		s.Scope.UpdateImageReplicationStatus(infrav1.ImageReplicationFailedReason, err)
+		return err
+	}
+
+	if !targetsLocation(version, spec.Location) {
+		if !spec.Replicate {
+			err := azure.WithTransientError(errors.Errorf("image version %s is not replicated into location %s, add it to its target regions", ref, spec.Location), notReplicatedRequeue)
+			s.Scope.UpdateImageReplicationStatus(infrav1.ImageNotReplicatedReason, err)
+			return err
+		}
+
+		log.V(2).Info("adding location to the target regions of the image version", "imageVersion", ref, "location", spec.Location)
+		if err := s.client.AddTargetRegion(ctx, *spec, version); err != nil {
+			err = errors.Wrapf(err, "failed to replicate image version %s into location %s", ref, spec.Location)
+			s.Scope.UpdateImageReplicationStatus(infrav1.ImageReplicationFailedReason, err)
+			return err
+		}
+		err := azure.WithTransientError(errors.Errorf("replicating image version %s into location %s", ref, spec.Location), replicatingRequeue)
+		s.Scope.UpdateImageReplicationStatus(infrav1.ImageReplicatingReason, err)
+		return err
+	}
+
+	switch state, details := replicationState(version, spec.Location); state {
+	case compute.ReplicationStateCompleted:
+		s.Scope.UpdateImageReplicationStatus("", nil)
+		return nil
+	case compute.ReplicationStateFailed:
+		err := errors.Errorf("replication of image version %s into location %s failed: %s", ref, spec.Location, details)
+		s.Scope.UpdateImageReplicationStatus(infrav1.ImageReplicationFailedReason, err)
+		return err
+	default:
+		err := azure.WithTransientError(errors.Errorf("replicating image version %s into location %s", ref, spec.Location), replicatingRequeue)
+		s.Scope.UpdateImageReplicationStatus(infrav1.ImageReplicatingReason, err)
+		return err
+	}
+}
+
+// Delete is a no-op as the replication of the image version is not owned by the machine.
+func (s *Service) Delete(_ context.Context) error {
+	return nil
+}
+
+// targetsLocation reports whether the location is one of the target regions of the image version.
+func targetsLocation(version compute.GalleryImageVersion, location string) bool {
+	if version.GalleryImageVersionProperties == nil || version.PublishingProfile == nil || version.PublishingProfile.TargetRegions == nil {
+		return false
+	}
+	for _, region := range *version.PublishingProfile.TargetRegions {
+		if region.Name != nil && sameLocation(*region.Name, location) {
+			return true
+		}
+	}
+	return false
+}
+
+// replicationState returns the state of the replication of the image version into the location, with its details.
+// An image version without replication status for the location is considered replicated once it's provisioned.
+func replicationState(version compute.GalleryImageVersion, location string) (compute.ReplicationState, string) {
+	if version.GalleryImageVersionProperties == nil {
+		return compute.ReplicationStateUnknown, ""
+	}
+	if version.ReplicationStatus != nil && version.ReplicationStatus.Summary != nil {
+		for _, status := range *version.ReplicationStatus.Summary {
+			if status.Region != nil && sameLocation(*status.Region, location) {
+				details := ""
+				if status.Details != nil {
+					details = *status.Details
+				}
+				return status.State, details
+			}
+		}
+	}
+	if version.ProvisioningState == compute.ProvisioningState3Succeeded {
+		return compute.ReplicationStateCompleted, ""
+	}
+	return compute.ReplicationStateUnknown, ""
+}
+
+// sameLocation compares a location name, e.g. "westus2", with the display names returned by the galleries API, e.g.
+// "West US 2".
+func sameLocation(name, location string) bool {
+	return strings.EqualFold(strings.ReplaceAll(name, " ", ""), strings.ReplaceAll(location, " ", ""))
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagereplications
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagereplications/mock_imagereplications"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var fakeSpec = azure.ImageReplicationSpec{
+	SubscriptionID: "123",
+	ResourceGroup:  "my-rg",
+	Gallery:        "my-gallery",
+	Image:          "my-image",
+	Version:        "1.0.0",
+	Location:       "westus2",
+}
+
+// imageVersion returns a gallery image version targeting the regions, with the replication states keyed by region.
+func imageVersion(regions []string, states map[string]compute.ReplicationState) compute.GalleryImageVersion {
+	targetRegions := []compute.TargetRegion{}
+	for _, region := range regions {
+		targetRegions = append(targetRegions, compute.TargetRegion{Name: to.StringPtr(region)})
+	}
+	summary := []compute.RegionalReplicationStatus{}
+	for region, state := range states {
+		summary = append(summary, compute.RegionalReplicationStatus{Region: to.StringPtr(region), State: state, Details: to.StringPtr("details")})
+	}
+	return compute.GalleryImageVersion{
+		GalleryImageVersionProperties: &compute.GalleryImageVersionProperties{
+			PublishingProfile: &compute.GalleryImageVersionPublishingProfile{TargetRegions: &targetRegions},
+			ProvisioningState: compute.ProvisioningState3Succeeded,
+			ReplicationStatus: &compute.ReplicationStatus{Summary: &summary},
+		},
+	}
+}
+
+func TestReconcileImageReplications(t *testing.T) {
+	replicateSpec := fakeSpec
+	replicateSpec.Replicate = true
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_imagereplications.MockImageReplicationScopeMockRecorder, m *mock_imagereplications.MockclientMockRecorder)
+	}{
+		{
+			name:          "noop if no spec is returned",
+			expectedError: "",
+			expect: func(s *mock_imagereplications.MockImageReplicationScopeMockRecorder, m *mock_imagereplications.MockclientMockRecorder) {
+				s.ImageReplicationSpec().Return(nil)
+			},
+		},
+		{
+			name:          "image version replicated into the location",
+			expectedError: "",
+			expect: func(s *mock_imagereplications.MockImageReplicationScopeMockRecorder, m *mock_imagereplications.MockclientMockRecorder) {
+				s.ImageReplicationSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), fakeSpec).Return(imageVersion([]string{"East US", "West US 2"}, map[string]compute.ReplicationState{
+					"East US":   compute.ReplicationStateReplicating,
+					"West US 2": compute.ReplicationStateCompleted,
+				}), nil)
+				s.UpdateImageReplicationStatus("", nil)
+			},
+		},
+		{
+			name:          "image version without replication status is replicated once provisioned",
+			expectedError: "",
+			expect: func(s *mock_imagereplications.MockImageReplicationScopeMockRecorder, m *mock_imagereplications.MockclientMockRecorder) {
+				s.ImageReplicationSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), fakeSpec).Return(imageVersion([]string{"westus2"}, nil), nil)
+				s.UpdateImageReplicationStatus("", nil)
+			},
+		},
+		{
+			name:          "replication in progress blocks creation",
+			expectedError: "replicating image version my-gallery/my-image/1.0.0 into location westus2. Object will be requeued after 1m0s",
+			expect: func(s *mock_imagereplications.MockImageReplicationScopeMockRecorder, m *mock_imagereplications.MockclientMockRecorder) {
+				s.ImageReplicationSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), fakeSpec).Return(imageVersion([]string{"West US 2"}, map[string]compute.ReplicationState{
+					"West US 2": compute.ReplicationStateReplicating,
+				}), nil)
+				s.UpdateImageReplicationStatus(infrav1.ImageReplicatingReason, gomock.Any())
+			},
+		},
+		{
+			name:          "failed replication is reported",
+			expectedError: "replication of image version my-gallery/my-image/1.0.0 into location westus2 failed: details",
+			expect: func(s *mock_imagereplications.MockImageReplicationScopeMockRecorder, m *mock_imagereplications.MockclientMockRecorder) {
+				s.ImageReplicationSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), fakeSpec).Return(imageVersion([]string{"West US 2"}, map[string]compute.ReplicationState{
+					"West US 2": compute.ReplicationStateFailed,
+				}), nil)
+				s.UpdateImageReplicationStatus(infrav1.ImageReplicationFailedReason, gomock.Any())
+			},
+		},
+		{
+			name:          "image version not targeting the location blocks creation",
+			expectedError: "image version my-gallery/my-image/1.0.0 is not replicated into location westus2, add it to its target regions. Object will be requeued after 5m0s",
+			expect: func(s *mock_imagereplications.MockImageReplicationScopeMockRecorder, m *mock_imagereplications.MockclientMockRecorder) {
+				s.ImageReplicationSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), fakeSpec).Return(imageVersion([]string{"East US"}, nil), nil)
+				s.UpdateImageReplicationStatus(infrav1.ImageNotReplicatedReason, gomock.Any())
+			},
+		},
+		{
+			name:          "location is added to the target regions when replication is requested",
+			expectedError: "replicating image version my-gallery/my-image/1.0.0 into location westus2. Object will be requeued after 1m0s",
+			expect: func(s *mock_imagereplications.MockImageReplicationScopeMockRecorder, m *mock_imagereplications.MockclientMockRecorder) {
+				version := imageVersion([]string{"East US"}, nil)
+				s.ImageReplicationSpec().Return(&replicateSpec)
+				m.Get(gomockinternal.AContext(), replicateSpec).Return(version, nil)
+				m.AddTargetRegion(gomockinternal.AContext(), replicateSpec, version).Return(nil)
+				s.UpdateImageReplicationStatus(infrav1.ImageReplicatingReason, gomock.Any())
+			},
+		},
+		{
+			name:          "error adding the location to the target regions",
+			expectedError: "failed to replicate image version my-gallery/my-image/1.0.0 into location westus2: #: Forbidden: StatusCode=403",
+			expect: func(s *mock_imagereplications.MockImageReplicationScopeMockRecorder, m *mock_imagereplications.MockclientMockRecorder) {
+				version := imageVersion([]string{"East US"}, nil)
+				s.ImageReplicationSpec().Return(&replicateSpec)
+				m.Get(gomockinternal.AContext(), replicateSpec).Return(version, nil)
+				m.AddTargetRegion(gomockinternal.AContext(), replicateSpec, version).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+				s.UpdateImageReplicationStatus(infrav1.ImageReplicationFailedReason, gomock.Any())
+			},
+		},
+		{
+			name:          "error getting the image version",
+			expectedError: "failed to get image version my-gallery/my-image/1.0.0: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_imagereplications.MockImageReplicationScopeMockRecorder, m *mock_imagereplications.MockclientMockRecorder) {
+				s.ImageReplicationSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), fakeSpec).Return(compute.GalleryImageVersion{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				s.UpdateImageReplicationStatus(infrav1.ImageReplicationFailedReason, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_imagereplications.NewMockImageReplicationScope(mockCtrl)
+			clientMock := mock_imagereplications.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
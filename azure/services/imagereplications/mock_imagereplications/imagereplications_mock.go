@@ -0,0 +1,190 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../imagereplications.go
+
+// Package mock_imagereplications is a generated GoMock package.
+package mock_imagereplications
+
+import (
+	reflect "reflect"
+
+	autorest "github.com/Azure/go-autorest/autorest"
+	gomock "github.com/golang/mock/gomock"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockImageReplicationScope is a mock of ImageReplicationScope interface.
+type MockImageReplicationScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageReplicationScopeMockRecorder
+}
+
+// MockImageReplicationScopeMockRecorder is the mock recorder for MockImageReplicationScope.
+type MockImageReplicationScopeMockRecorder struct {
+	mock *MockImageReplicationScope
+}
+
+// NewMockImageReplicationScope creates a new mock instance.
+func NewMockImageReplicationScope(ctrl *gomock.Controller) *MockImageReplicationScope {
+	mock := &MockImageReplicationScope{ctrl: ctrl}
+	mock.recorder = &MockImageReplicationScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageReplicationScope) EXPECT() *MockImageReplicationScopeMockRecorder {
+	return m.recorder
+}
+
+// Authorizer mocks base method.
+func (m *MockImageReplicationScope) Authorizer() autorest.Authorizer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorizer")
+	ret0, _ := ret[0].(autorest.Authorizer)
+	return ret0
+}
+
+// Authorizer indicates an expected call of Authorizer.
+func (mr *MockImageReplicationScopeMockRecorder) Authorizer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockImageReplicationScope)(nil).Authorizer))
+}
+
+// BaseURI mocks base method.
+func (m *MockImageReplicationScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockImageReplicationScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockImageReplicationScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockImageReplicationScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockImageReplicationScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockImageReplicationScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockImageReplicationScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockImageReplicationScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockImageReplicationScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockImageReplicationScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockImageReplicationScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockImageReplicationScope)(nil).CloudEnvironment))
+}
+
+// HashKey mocks base method.
+func (m *MockImageReplicationScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockImageReplicationScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockImageReplicationScope)(nil).HashKey))
+}
+
+// ImageReplicationSpec mocks base method.
+func (m *MockImageReplicationScope) ImageReplicationSpec() *azure.ImageReplicationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageReplicationSpec")
+	ret0, _ := ret[0].(*azure.ImageReplicationSpec)
+	return ret0
+}
+
+// ImageReplicationSpec indicates an expected call of ImageReplicationSpec.
+func (mr *MockImageReplicationScopeMockRecorder) ImageReplicationSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageReplicationSpec", reflect.TypeOf((*MockImageReplicationScope)(nil).ImageReplicationSpec))
+}
+
+// SubscriptionID mocks base method.
+func (m *MockImageReplicationScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockImageReplicationScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockImageReplicationScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockImageReplicationScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockImageReplicationScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockImageReplicationScope)(nil).TenantID))
+}
+
+// UpdateImageReplicationStatus mocks base method.
+func (m *MockImageReplicationScope) UpdateImageReplicationStatus(reason string, err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateImageReplicationStatus", reason, err)
+}
+
+// UpdateImageReplicationStatus indicates an expected call of UpdateImageReplicationStatus.
+func (mr *MockImageReplicationScopeMockRecorder) UpdateImageReplicationStatus(reason, err interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImageReplicationStatus", reflect.TypeOf((*MockImageReplicationScope)(nil).UpdateImageReplicationStatus), reason, err)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -49,22 +50,24 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newLoadbalancersClient creates a new load balancer client from subscription ID.
 func newLoadBalancersClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.LoadBalancersClient {
 	loadBalancersClient := network.NewLoadBalancersClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&loadBalancersClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&loadBalancersClient.Client, authorizer, "loadbalancers")
 	return loadBalancersClient
 }
 
 // Get gets the specified load balancer.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, lbName string) (network.LoadBalancer, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, lbName string) (_ network.LoadBalancer, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "loadbalancers.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.loadbalancers.Get(ctx, resourceGroupName, lbName, "")
 }
 
 // CreateOrUpdate creates or updates a load balancer.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, lbName string, lb network.LoadBalancer) error {
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, lbName string, lb network.LoadBalancer) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "loadbalancers.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	var etag string
 	if lb.Etag != nil {
@@ -97,9 +100,10 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName str
 }
 
 // Delete deletes the specified load balancer.
-func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, lbName string) error {
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, lbName string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "loadbalancers.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.loadbalancers.Delete(ctx, resourceGroupName, lbName)
 	if err != nil {
@@ -18,6 +18,8 @@ package loadbalancers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -32,13 +34,15 @@ import (
 const (
 	tcpProbe    = "TCPProbe"
 	lbRuleHTTPS = "LBRuleHTTPS"
-	outboundNAT = "OutboundNATAllProtocols"
+	// lbRuleForwardedPrefix is the name prefix of the rules of the ports forwarded to the control plane nodes.
+	lbRuleForwardedPrefix = "LBRuleForwarded"
+	outboundNAT           = "OutboundNATAllProtocols"
 )
 
 // LBScope defines the scope interface for a load balancer service.
 type LBScope interface {
 	azure.ClusterDescriber
-	azure.NetworkDescriber
+	Vnet() *infrav1.VnetSpec
 	LBSpecs() []azure.LBSpec
 }
 
@@ -192,6 +196,25 @@ func (s *Service) Delete(ctx context.Context) error {
 	return nil
 }
 
+// Describe returns a summary of the existing load balancers. Load balancers that don't exist are skipped.
+func (s *Service) Describe(ctx context.Context) ([]azure.LoadBalancerSummary, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "loadbalancers.Service.Describe")
+	defer done()
+
+	var summaries []azure.LoadBalancerSummary
+	for _, lbSpec := range s.Scope.LBSpecs() {
+		existing, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), lbSpec.Name)
+		if azure.ResourceNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get load balancer %s in %s", lbSpec.Name, s.Scope.ResourceGroup())
+		}
+		summaries = append(summaries, converters.SDKToLoadBalancerSummary(existing))
+	}
+	return summaries, nil
+}
+
 func (s *Service) getFrontendIPConfigs(lbSpec azure.LBSpec) ([]network.FrontendIPConfiguration, []network.SubResource) {
 	frontendIPConfigurations := make([]network.FrontendIPConfiguration, 0)
 	frontendIDs := make([]network.SubResource, 0)
@@ -250,31 +273,46 @@ func (s *Service) getLoadBalancingRules(lbSpec azure.LBSpec, frontendIDs []netwo
 		if len(frontendIDs) != 0 {
 			frontendIPConfig = frontendIDs[0]
 		}
-		return []network.LoadBalancingRule{
-			{
-				Name: to.StringPtr(lbRuleHTTPS),
-				LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
-					DisableOutboundSnat:     to.BoolPtr(true),
-					Protocol:                network.TransportProtocolTCP,
-					FrontendPort:            to.Int32Ptr(lbSpec.APIServerPort),
-					BackendPort:             to.Int32Ptr(lbSpec.APIServerPort),
-					IdleTimeoutInMinutes:    lbSpec.IdleTimeoutInMinutes,
-					EnableFloatingIP:        to.BoolPtr(false),
-					LoadDistribution:        network.LoadDistributionDefault,
-					FrontendIPConfiguration: &frontendIPConfig,
-					BackendAddressPool: &network.SubResource{
-						ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), lbSpec.Name, lbSpec.BackendPoolName)),
-					},
-					Probe: &network.SubResource{
-						ID: to.StringPtr(azure.ProbeID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), lbSpec.Name, tcpProbe)),
-					},
-				},
-			},
+		rules := []network.LoadBalancingRule{
+			s.controlPlaneLBRule(lbSpec, lbRuleHTTPS, network.TransportProtocolTCP, lbSpec.APIServerPort, frontendIPConfig),
 		}
+		// The forwarded ports are served by agents running alongside the API server, so they share its health probe.
+		for _, port := range lbSpec.ForwardedPorts {
+			protocol := network.TransportProtocolTCP
+			if strings.EqualFold(port.Protocol, string(network.TransportProtocolUDP)) {
+				protocol = network.TransportProtocolUDP
+			}
+			rules = append(rules, s.controlPlaneLBRule(lbSpec, fmt.Sprintf("%s-%s", lbRuleForwardedPrefix, port.Name), protocol, port.Port, frontendIPConfig))
+		}
+		return rules
 	}
 	return []network.LoadBalancingRule{}
 }
 
+// controlPlaneLBRule returns a rule of the API server load balancer forwarding a port to the same port of the control
+// plane nodes.
+func (s *Service) controlPlaneLBRule(lbSpec azure.LBSpec, name string, protocol network.TransportProtocol, port int32, frontendIPConfig network.SubResource) network.LoadBalancingRule {
+	return network.LoadBalancingRule{
+		Name: to.StringPtr(name),
+		LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
+			DisableOutboundSnat:     to.BoolPtr(true),
+			Protocol:                protocol,
+			FrontendPort:            to.Int32Ptr(port),
+			BackendPort:             to.Int32Ptr(port),
+			IdleTimeoutInMinutes:    lbSpec.IdleTimeoutInMinutes,
+			EnableFloatingIP:        to.BoolPtr(false),
+			LoadDistribution:        network.LoadDistributionDefault,
+			FrontendIPConfiguration: &frontendIPConfig,
+			BackendAddressPool: &network.SubResource{
+				ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), lbSpec.Name, lbSpec.BackendPoolName)),
+			},
+			Probe: &network.SubResource{
+				ID: to.StringPtr(azure.ProbeID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), lbSpec.Name, tcpProbe)),
+			},
+		},
+	}
+}
+
 func (s *Service) getBackendAddressPools(lbSpec azure.LBSpec) []network.BackendAddressPool {
 	return []network.BackendAddressPool{
 		{
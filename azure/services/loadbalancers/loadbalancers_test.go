@@ -85,6 +85,46 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publiclb", gomockinternal.DiffEq(newDefaultPublicAPIServerLB())).Return(nil))
 			},
 		},
+		{
+			name:          "create public apiserver LB forwarding additional ports",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder) {
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:                 "my-publiclb",
+						Role:                 infrav1.APIServerRole,
+						Type:                 infrav1.Public,
+						SKU:                  infrav1.SKUStandard,
+						SubnetName:           "my-cp-subnet",
+						BackendPoolName:      "my-publiclb-backendPool",
+						IdleTimeoutInMinutes: to.Int32Ptr(4),
+						FrontendIPConfigs: []infrav1.FrontendIP{
+							{
+								Name: "my-publiclb-frontEnd",
+								PublicIP: &infrav1.PublicIPSpec{
+									Name:    "my-publicip",
+									DNSName: "my-cluster.12345.mydomain.com",
+								},
+							},
+						},
+						APIServerPort: 6443,
+						ForwardedPorts: []azure.LBPort{
+							{Name: "konnectivity", Port: 8132, Protocol: "Tcp"},
+							{Name: "tunnel", Port: 51820, Protocol: "Udp"},
+						},
+					},
+				})
+				setupDefaultLBExpectations(s)
+				lb := newDefaultPublicAPIServerLB()
+				httpsRule := (*lb.LoadBalancingRules)[0]
+				konnectivityRule := forwardedPortRule(httpsRule, "LBRuleForwarded-konnectivity", network.TransportProtocolTCP, 8132)
+				tunnelRule := forwardedPortRule(httpsRule, "LBRuleForwarded-tunnel", network.TransportProtocolUDP, 51820)
+				lb.LoadBalancingRules = &[]network.LoadBalancingRule{httpsRule, konnectivityRule, tunnelRule}
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publiclb", gomockinternal.DiffEq(lb)).Return(nil))
+			},
+		},
 		{
 			name:          "create internal apiserver LB",
 			expectedError: "",
@@ -483,6 +523,17 @@ func newDefaultPublicAPIServerLB() network.LoadBalancer {
 	}
 }
 
+func forwardedPortRule(httpsRule network.LoadBalancingRule, name string, protocol network.TransportProtocol, port int32) network.LoadBalancingRule {
+	props := *httpsRule.LoadBalancingRulePropertiesFormat
+	props.Protocol = protocol
+	props.FrontendPort = to.Int32Ptr(port)
+	props.BackendPort = to.Int32Ptr(port)
+	return network.LoadBalancingRule{
+		Name:                              to.StringPtr(name),
+		LoadBalancingRulePropertiesFormat: &props,
+	}
+}
+
 func newDefaultInternalAPIServerLB() network.LoadBalancer {
 	return network.LoadBalancer{
 		Tags: map[string]*string{
@@ -18,21 +18,36 @@ package managedclusters
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/pkg/errors"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// previewAPIVersion is the AKS API version used for the settings of managed clusters which are not part of the
+// containerservice SDK package used by the rest of the provider: the OIDC issuer, workload identity, and the mode and
+// dataplane of the network plugin.
+const previewAPIVersion = "2023-08-02-preview"
+
 // Client wraps go-sdk.
 type Client interface {
 	Get(context.Context, string, string) (containerservice.ManagedCluster, error)
 	GetCredentials(context.Context, string, string) ([]byte, error)
+	GetUserCredentials(context.Context, string, string) ([]byte, error)
 	CreateOrUpdate(context.Context, string, string, containerservice.ManagedCluster) (containerservice.ManagedCluster, error)
+	CreateWithNetworkMode(context.Context, string, string, containerservice.ManagedCluster, string, string) (containerservice.ManagedCluster, error)
 	Delete(context.Context, string, string) error
+	Start(context.Context, string, string) error
+	Stop(context.Context, string, string) error
+	GetWorkloadIdentity(context.Context, string, string) (azure.WorkloadIdentityProfile, error)
+	SetWorkloadIdentity(context.Context, string, string, azure.WorkloadIdentityProfile) error
 }
 
 // AzureClient contains the Azure go-sdk Client.
@@ -52,19 +67,22 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newManagedClustersClient creates a new managed clusters client from subscription ID.
 func newManagedClustersClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) containerservice.ManagedClustersClient {
 	managedClustersClient := containerservice.NewManagedClustersClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&managedClustersClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&managedClustersClient.Client, authorizer, "managedclusters")
 	return managedClustersClient
 }
 
 // Get gets a managed cluster.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, name string) (containerservice.ManagedCluster, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, name string) (_ containerservice.ManagedCluster, err error) {
+	defer azureerrors.Classify(&err)
+
 	return ac.managedclusters.Get(ctx, resourceGroupName, name)
 }
 
 // GetCredentials fetches the admin kubeconfig for a managed cluster.
-func (ac *AzureClient) GetCredentials(ctx context.Context, resourceGroupName, name string) ([]byte, error) {
+func (ac *AzureClient) GetCredentials(ctx context.Context, resourceGroupName, name string) (_ []byte, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.GetCredentials")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	credentialList, err := ac.managedclusters.ListClusterAdminCredentials(ctx, resourceGroupName, name, "")
 	if err != nil {
@@ -78,10 +96,30 @@ func (ac *AzureClient) GetCredentials(ctx context.Context, resourceGroupName, na
 	return *(*credentialList.Kubeconfigs)[0].Value, nil
 }
 
+// GetUserCredentials fetches the user kubeconfig for a managed cluster, which authenticates with AAD. It is the only
+// kubeconfig available when the local accounts of the cluster are disabled.
+func (ac *AzureClient) GetUserCredentials(ctx context.Context, resourceGroupName, name string) (_ []byte, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.GetUserCredentials")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	credentialList, err := ac.managedclusters.ListClusterUserCredentials(ctx, resourceGroupName, name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if credentialList.Kubeconfigs == nil || len(*credentialList.Kubeconfigs) < 1 {
+		return nil, errors.New("no user kubeconfigs available for the managed cluster")
+	}
+
+	return *(*credentialList.Kubeconfigs)[0].Value, nil
+}
+
 // CreateOrUpdate creates or updates a managed cluster.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, cluster containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, cluster containerservice.ManagedCluster) (_ containerservice.ManagedCluster, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.managedclusters.CreateOrUpdate(ctx, resourceGroupName, name, cluster)
 	if err != nil {
@@ -94,10 +132,61 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, na
 	return managedCluster, err
 }
 
+// CreateWithNetworkMode creates a managed cluster with the mode and dataplane of its network plugin, which can only be
+// set at creation.
+func (ac *AzureClient) CreateWithNetworkMode(ctx context.Context, resourceGroupName, name string, cluster containerservice.ManagedCluster, networkPluginMode, networkDataplane string) (_ containerservice.ManagedCluster, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.CreateWithNetworkMode")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	body, err := json.Marshal(cluster)
+	if err != nil {
+		return containerservice.ManagedCluster{}, errors.Wrap(err, "failed to marshal managed cluster")
+	}
+	managedCluster := map[string]interface{}{}
+	if err := json.Unmarshal(body, &managedCluster); err != nil {
+		return containerservice.ManagedCluster{}, errors.Wrap(err, "failed to unmarshal managed cluster")
+	}
+	properties, ok := managedCluster["properties"].(map[string]interface{})
+	if !ok {
+		properties = map[string]interface{}{}
+		managedCluster["properties"] = properties
+	}
+	networkProfile, ok := properties["networkProfile"].(map[string]interface{})
+	if !ok {
+		networkProfile = map[string]interface{}{}
+		properties["networkProfile"] = networkProfile
+	}
+	if networkPluginMode != "" {
+		networkProfile["networkPluginMode"] = networkPluginMode
+	}
+	if networkDataplane != "" {
+		networkProfile["networkDataplane"] = networkDataplane
+	}
+
+	req, err := ac.preparer(ctx, resourceGroupName, name, autorest.AsPut(), autorest.WithJSON(managedCluster))
+	if err != nil {
+		return containerservice.ManagedCluster{}, autorest.NewErrorWithError(err, "managedclusters.AzureClient", "CreateWithNetworkMode", nil, "Failure preparing request")
+	}
+	resp, err := ac.managedclusters.Send(req, azureautorest.DoRetryWithRegistration(ac.managedclusters.Client))
+	if err != nil {
+		return containerservice.ManagedCluster{}, autorest.NewErrorWithError(err, "managedclusters.AzureClient", "CreateWithNetworkMode", resp, "Failure sending request")
+	}
+	future, err := azureautorest.NewFutureFromResponse(resp)
+	if err != nil {
+		return containerservice.ManagedCluster{}, errors.Wrap(err, "failed to begin operation")
+	}
+	if err := future.WaitForCompletionRef(ctx, ac.managedclusters.Client); err != nil {
+		return containerservice.ManagedCluster{}, errors.Wrap(err, "failed to end operation")
+	}
+	return ac.managedclusters.Get(ctx, resourceGroupName, name)
+}
+
 // Delete deletes a managed cluster.
-func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, name string) error {
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, name string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.managedclusters.Delete(ctx, resourceGroupName, name)
 	if err != nil {
@@ -112,3 +201,143 @@ func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, name strin
 	_, err = future.Result(ac.managedclusters)
 	return err
 }
+
+// Start starts a stopped managed cluster.
+func (ac *AzureClient) Start(ctx context.Context, resourceGroupName, name string) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.Start")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.managedclusters.Start(ctx, resourceGroupName, name)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin operation")
+	}
+	if err := future.WaitForCompletionRef(ctx, ac.managedclusters.Client); err != nil {
+		return errors.Wrap(err, "failed to end operation")
+	}
+	_, err = future.Result(ac.managedclusters)
+	return err
+}
+
+// Stop stops a running managed cluster, deallocating its control plane and nodes.
+func (ac *AzureClient) Stop(ctx context.Context, resourceGroupName, name string) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.Stop")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.managedclusters.Stop(ctx, resourceGroupName, name)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin operation")
+	}
+	if err := future.WaitForCompletionRef(ctx, ac.managedclusters.Client); err != nil {
+		return errors.Wrap(err, "failed to end operation")
+	}
+	_, err = future.Result(ac.managedclusters)
+	return err
+}
+
+// GetWorkloadIdentity returns the OIDC issuer and workload identity settings of a managed cluster.
+func (ac *AzureClient) GetWorkloadIdentity(ctx context.Context, resourceGroupName, name string) (_ azure.WorkloadIdentityProfile, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.GetWorkloadIdentity")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	managedCluster, err := ac.getRaw(ctx, resourceGroupName, name)
+	if err != nil {
+		return azure.WorkloadIdentityProfile{}, err
+	}
+	properties, _ := managedCluster["properties"].(map[string]interface{})
+	oidcIssuerProfile, _ := properties["oidcIssuerProfile"].(map[string]interface{})
+	securityProfile, _ := properties["securityProfile"].(map[string]interface{})
+	workloadIdentity, _ := securityProfile["workloadIdentity"].(map[string]interface{})
+
+	profile := azure.WorkloadIdentityProfile{}
+	profile.OIDCIssuerEnabled, _ = oidcIssuerProfile["enabled"].(bool)
+	profile.OIDCIssuerURL, _ = oidcIssuerProfile["issuerURL"].(string)
+	profile.WorkloadIdentityEnabled, _ = workloadIdentity["enabled"].(bool)
+	return profile, nil
+}
+
+// SetWorkloadIdentity enables or disables the OIDC issuer and workload identity of an existing managed cluster. As the
+// managed cluster has to be updated as a whole, the other properties are sent back as AKS returns them.
+func (ac *AzureClient) SetWorkloadIdentity(ctx context.Context, resourceGroupName, name string, profile azure.WorkloadIdentityProfile) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.SetWorkloadIdentity")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	managedCluster, err := ac.getRaw(ctx, resourceGroupName, name)
+	if err != nil {
+		return err
+	}
+	properties, ok := managedCluster["properties"].(map[string]interface{})
+	if !ok {
+		properties = map[string]interface{}{}
+		managedCluster["properties"] = properties
+	}
+	properties["oidcIssuerProfile"] = map[string]interface{}{"enabled": profile.OIDCIssuerEnabled}
+	securityProfile, ok := properties["securityProfile"].(map[string]interface{})
+	if !ok {
+		securityProfile = map[string]interface{}{}
+		properties["securityProfile"] = securityProfile
+	}
+	securityProfile["workloadIdentity"] = map[string]interface{}{"enabled": profile.WorkloadIdentityEnabled}
+
+	req, err := ac.preparer(ctx, resourceGroupName, name, autorest.AsPut(), autorest.WithJSON(managedCluster))
+	if err != nil {
+		return autorest.NewErrorWithError(err, "managedclusters.AzureClient", "SetWorkloadIdentity", nil, "Failure preparing request")
+	}
+	resp, err := ac.managedclusters.Send(req, azureautorest.DoRetryWithRegistration(ac.managedclusters.Client))
+	if err != nil {
+		return autorest.NewErrorWithError(err, "managedclusters.AzureClient", "SetWorkloadIdentity", resp, "Failure sending request")
+	}
+	future, err := azureautorest.NewFutureFromResponse(resp)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin operation")
+	}
+	if err := future.WaitForCompletionRef(ctx, ac.managedclusters.Client); err != nil {
+		return errors.Wrap(err, "failed to end operation")
+	}
+	return nil
+}
+
+// getRaw gets a managed cluster with the preview API version, as raw JSON.
+func (ac *AzureClient) getRaw(ctx context.Context, resourceGroupName, name string) (map[string]interface{}, error) {
+	req, err := ac.preparer(ctx, resourceGroupName, name, autorest.AsGet())
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "managedclusters.AzureClient", "getRaw", nil, "Failure preparing request")
+	}
+	resp, err := ac.managedclusters.Send(req, azureautorest.DoRetryWithRegistration(ac.managedclusters.Client))
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "managedclusters.AzureClient", "getRaw", resp, "Failure sending request")
+	}
+	managedCluster := map[string]interface{}{}
+	err = autorest.Respond(
+		resp,
+		azureautorest.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(&managedCluster),
+		autorest.ByClosing())
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "managedclusters.AzureClient", "getRaw", resp, "Failure responding to request")
+	}
+	return managedCluster, nil
+}
+
+// preparer prepares a request on a managed cluster with the preview API version.
+func (ac *AzureClient) preparer(ctx context.Context, resourceGroupName, name string, decorators ...autorest.PrepareDecorator) (*http.Request, error) {
+	pathParameters := map[string]interface{}{
+		"resourceGroupName": autorest.Encode("path", resourceGroupName),
+		"resourceName":      autorest.Encode("path", name),
+		"subscriptionId":    autorest.Encode("path", ac.managedclusters.SubscriptionID),
+	}
+	queryParameters := map[string]interface{}{
+		"api-version": previewAPIVersion,
+	}
+
+	decorators = append([]autorest.PrepareDecorator{
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.WithBaseURL(ac.managedclusters.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.ContainerService/managedClusters/{resourceName}", pathParameters),
+		autorest.WithQueryParameters(queryParameters),
+	}, decorators...)
+	return autorest.CreatePreparer(decorators...).Prepare((&http.Request{}).WithContext(ctx))
+}
@@ -18,6 +18,7 @@ package managedclusters
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"net"
 	"time"
@@ -29,6 +30,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/yaml"
 
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -40,15 +42,26 @@ var (
 	managedIdentity = "msi"
 )
 
+// aksAADServerAppID is the ID of the AAD server application of the AKS clusters with managed AAD, which is the audience
+// of the tokens their API server accepts.
+const aksAADServerAppID = "6dae42f8-4368-4678-94ff-3960e28e3630"
+
+// standardSKUTier is the tier of managed clusters with a financially backed SLA, named Paid in older AKS API versions.
+const standardSKUTier = "Standard"
+
 // ManagedClusterScope defines the scope interface for a managed cluster.
 type ManagedClusterScope interface {
 	azure.ClusterDescriber
 	ManagedClusterSpec() (azure.ManagedClusterSpec, error)
 	GetAgentPoolSpecs(ctx context.Context) ([]azure.AgentPoolSpec, error)
 	SetControlPlaneEndpoint(clusterv1.APIEndpoint)
+	SetOIDCIssuerURL(string)
+	SetPowerState(string)
 	MakeEmptyKubeConfigSecret() corev1.Secret
 	GetKubeConfigData() []byte
 	SetKubeConfigData([]byte)
+	SetKubeConfigTokenExpiry(time.Time)
+	GetAccessToken(ctx context.Context, resource string) (string, time.Time, error)
 }
 
 // Service provides operations on azure resources.
@@ -65,6 +78,176 @@ func convertToResourceReferences(resources []string) *[]containerservice.Resourc
 	return &resourceReferences
 }
 
+// convertToHTTPProxyConfig converts the proxy configuration to its AKS representation, which expects the trusted CA
+// bundle to be base64 encoded.
+func convertToHTTPProxyConfig(proxyConfig *azure.HTTPProxyConfig) *containerservice.ManagedClusterHTTPProxyConfig {
+	httpProxyConfig := &containerservice.ManagedClusterHTTPProxyConfig{}
+	if proxyConfig.HTTPProxy != "" {
+		httpProxyConfig.HTTPProxy = to.StringPtr(proxyConfig.HTTPProxy)
+	}
+	if proxyConfig.HTTPSProxy != "" {
+		httpProxyConfig.HTTPSProxy = to.StringPtr(proxyConfig.HTTPSProxy)
+	}
+	if len(proxyConfig.NoProxy) > 0 {
+		httpProxyConfig.NoProxy = &proxyConfig.NoProxy
+	}
+	if proxyConfig.TrustedCA != "" {
+		httpProxyConfig.TrustedCa = to.StringPtr(base64.StdEncoding.EncodeToString([]byte(proxyConfig.TrustedCA)))
+	}
+	return httpProxyConfig
+}
+
+// convertToAutoScalerProfile converts the cluster autoscaler profile to its AKS representation.
+func convertToAutoScalerProfile(profile *azure.AutoScalerProfile) *containerservice.ManagedClusterPropertiesAutoScalerProfile {
+	autoScalerProfile := &containerservice.ManagedClusterPropertiesAutoScalerProfile{
+		BalanceSimilarNodeGroups:      profile.BalanceSimilarNodeGroups,
+		MaxEmptyBulkDelete:            profile.MaxEmptyBulkDelete,
+		MaxGracefulTerminationSec:     profile.MaxGracefulTerminationSec,
+		MaxNodeProvisionTime:          profile.MaxNodeProvisionTime,
+		MaxTotalUnreadyPercentage:     profile.MaxTotalUnreadyPercentage,
+		NewPodScaleUpDelay:            profile.NewPodScaleUpDelay,
+		OkTotalUnreadyCount:           profile.OkTotalUnreadyCount,
+		ScanInterval:                  profile.ScanInterval,
+		ScaleDownDelayAfterAdd:        profile.ScaleDownDelayAfterAdd,
+		ScaleDownDelayAfterDelete:     profile.ScaleDownDelayAfterDelete,
+		ScaleDownDelayAfterFailure:    profile.ScaleDownDelayAfterFailure,
+		ScaleDownUnneededTime:         profile.ScaleDownUnneededTime,
+		ScaleDownUnreadyTime:          profile.ScaleDownUnreadyTime,
+		ScaleDownUtilizationThreshold: profile.ScaleDownUtilizationThreshold,
+		SkipNodesWithLocalStorage:     profile.SkipNodesWithLocalStorage,
+		SkipNodesWithSystemPods:       profile.SkipNodesWithSystemPods,
+	}
+	if profile.Expander != nil {
+		autoScalerProfile.Expander = containerservice.Expander(*profile.Expander)
+	}
+	return autoScalerProfile
+}
+
+// mergeAutoScalerProfile fills the settings of the desired cluster autoscaler profile that are not set with the ones
+// of the existing cluster, so that updating the cluster doesn't reset them to their AKS defaults.
+func mergeAutoScalerProfile(desired, existing *containerservice.ManagedClusterPropertiesAutoScalerProfile) *containerservice.ManagedClusterPropertiesAutoScalerProfile {
+	orDefault := func(value, defaultValue *string) *string {
+		if value != nil {
+			return value
+		}
+		return defaultValue
+	}
+	merged := &containerservice.ManagedClusterPropertiesAutoScalerProfile{
+		BalanceSimilarNodeGroups:      orDefault(desired.BalanceSimilarNodeGroups, existing.BalanceSimilarNodeGroups),
+		MaxEmptyBulkDelete:            orDefault(desired.MaxEmptyBulkDelete, existing.MaxEmptyBulkDelete),
+		MaxGracefulTerminationSec:     orDefault(desired.MaxGracefulTerminationSec, existing.MaxGracefulTerminationSec),
+		MaxNodeProvisionTime:          orDefault(desired.MaxNodeProvisionTime, existing.MaxNodeProvisionTime),
+		MaxTotalUnreadyPercentage:     orDefault(desired.MaxTotalUnreadyPercentage, existing.MaxTotalUnreadyPercentage),
+		NewPodScaleUpDelay:            orDefault(desired.NewPodScaleUpDelay, existing.NewPodScaleUpDelay),
+		OkTotalUnreadyCount:           orDefault(desired.OkTotalUnreadyCount, existing.OkTotalUnreadyCount),
+		ScanInterval:                  orDefault(desired.ScanInterval, existing.ScanInterval),
+		ScaleDownDelayAfterAdd:        orDefault(desired.ScaleDownDelayAfterAdd, existing.ScaleDownDelayAfterAdd),
+		ScaleDownDelayAfterDelete:     orDefault(desired.ScaleDownDelayAfterDelete, existing.ScaleDownDelayAfterDelete),
+		ScaleDownDelayAfterFailure:    orDefault(desired.ScaleDownDelayAfterFailure, existing.ScaleDownDelayAfterFailure),
+		ScaleDownUnneededTime:         orDefault(desired.ScaleDownUnneededTime, existing.ScaleDownUnneededTime),
+		ScaleDownUnreadyTime:          orDefault(desired.ScaleDownUnreadyTime, existing.ScaleDownUnreadyTime),
+		ScaleDownUtilizationThreshold: orDefault(desired.ScaleDownUtilizationThreshold, existing.ScaleDownUtilizationThreshold),
+		SkipNodesWithLocalStorage:     orDefault(desired.SkipNodesWithLocalStorage, existing.SkipNodesWithLocalStorage),
+		SkipNodesWithSystemPods:       orDefault(desired.SkipNodesWithSystemPods, existing.SkipNodesWithSystemPods),
+		Expander:                      desired.Expander,
+	}
+	if merged.Expander == "" {
+		merged.Expander = existing.Expander
+	}
+	return merged
+}
+
+// convertToSKUTier converts the tier of the spec to a tier of the containerservice API version, in which the Standard
+// tier is still named Paid.
+func convertToSKUTier(tier string) containerservice.ManagedClusterSKUTier {
+	if tier == standardSKUTier {
+		return containerservice.ManagedClusterSKUTierPaid
+	}
+	return containerservice.ManagedClusterSKUTier(tier)
+}
+
+// convertToAddonProfiles converts the add-on profiles of the spec to the add-on profiles of a managed cluster, keyed
+// by the name of the add-on.
+func convertToAddonProfiles(profiles []azure.AddonProfile) map[string]*containerservice.ManagedClusterAddonProfile {
+	addonProfiles := map[string]*containerservice.ManagedClusterAddonProfile{}
+	for _, profile := range profiles {
+		addonProfile := &containerservice.ManagedClusterAddonProfile{
+			Enabled: to.BoolPtr(profile.Enabled),
+		}
+		if len(profile.Config) > 0 {
+			addonProfile.Config = *to.StringMapPtr(profile.Config)
+		}
+		addonProfiles[profile.Name] = addonProfile
+	}
+	return addonProfiles
+}
+
+// mergeAddonProfiles adds the add-ons of the existing cluster that are not desired to the desired add-on profiles,
+// and fills the settings of the desired add-ons with the ones of the existing cluster, so that updating the cluster
+// doesn't disable or reset them. The identities AKS reports for the add-ons are left out, as they are read-only.
+func mergeAddonProfiles(desired, existing map[string]*containerservice.ManagedClusterAddonProfile) map[string]*containerservice.ManagedClusterAddonProfile {
+	merged := normalizeAddonProfiles(existing)
+	for name, profile := range desired {
+		config := map[string]*string{}
+		if existingProfile, ok := merged[name]; ok {
+			for key, value := range existingProfile.Config {
+				config[key] = value
+			}
+		}
+		for key, value := range profile.Config {
+			config[key] = value
+		}
+		mergedProfile := &containerservice.ManagedClusterAddonProfile{Enabled: profile.Enabled}
+		if len(config) > 0 {
+			mergedProfile.Config = config
+		}
+		merged[name] = mergedProfile
+	}
+	return merged
+}
+
+// normalizeAddonProfiles returns the add-on profiles with only the settings CAPZ manages.
+func normalizeAddonProfiles(profiles map[string]*containerservice.ManagedClusterAddonProfile) map[string]*containerservice.ManagedClusterAddonProfile {
+	normalized := map[string]*containerservice.ManagedClusterAddonProfile{}
+	for name, profile := range profiles {
+		if profile == nil {
+			continue
+		}
+		normalizedProfile := &containerservice.ManagedClusterAddonProfile{Enabled: profile.Enabled}
+		if len(profile.Config) > 0 {
+			normalizedProfile.Config = profile.Config
+		}
+		normalized[name] = normalizedProfile
+	}
+	return normalized
+}
+
+// normalizeAPIServerAccessProfile returns the API server access profile with only its authorized IP ranges, the only
+// settings which can be updated, or nil when it has none so that a missing and an empty list of ranges are equal.
+func normalizeAPIServerAccessProfile(profile *containerservice.ManagedClusterAPIServerAccessProfile) *containerservice.ManagedClusterAPIServerAccessProfile {
+	if profile == nil || profile.AuthorizedIPRanges == nil || len(*profile.AuthorizedIPRanges) == 0 {
+		return nil
+	}
+	return &containerservice.ManagedClusterAPIServerAccessProfile{
+		AuthorizedIPRanges: profile.AuthorizedIPRanges,
+	}
+}
+
+// mergeAPIServerAccessProfile returns the API server access profile to update the managed cluster with. The authorized
+// IP ranges removed from the spec are set to an empty list so that AKS clears them, and the other settings of the
+// existing profile are kept when the spec has none.
+func mergeAPIServerAccessProfile(desired, existing *containerservice.ManagedClusterAPIServerAccessProfile) *containerservice.ManagedClusterAPIServerAccessProfile {
+	if normalizeAPIServerAccessProfile(existing) == nil || normalizeAPIServerAccessProfile(desired) != nil {
+		return desired
+	}
+	merged := *existing
+	if desired != nil {
+		merged = *desired
+	}
+	merged.AuthorizedIPRanges = &[]string{}
+	return &merged
+}
+
 func computeDiffOfNormalizedClusters(managedCluster containerservice.ManagedCluster, existingMC containerservice.ManagedCluster) string {
 	// Normalize properties for the desired (CR spec) and existing managed
 	// cluster, so that we check only those fields that were specified in
@@ -106,16 +289,19 @@ func computeDiffOfNormalizedClusters(managedCluster containerservice.ManagedClus
 		existingMCPropertiesNormalized.NetworkProfile.LoadBalancerProfile = existingMC.NetworkProfile.LoadBalancerProfile
 	}
 
-	if managedCluster.APIServerAccessProfile != nil {
-		propertiesNormalized.APIServerAccessProfile = &containerservice.ManagedClusterAPIServerAccessProfile{
-			AuthorizedIPRanges: managedCluster.APIServerAccessProfile.AuthorizedIPRanges,
-		}
+	propertiesNormalized.APIServerAccessProfile = normalizeAPIServerAccessProfile(managedCluster.APIServerAccessProfile)
+	existingMCPropertiesNormalized.APIServerAccessProfile = normalizeAPIServerAccessProfile(existingMC.APIServerAccessProfile)
+
+	// The autoscaler profile of the existing cluster is only compared when one is desired, as AKS sets a default one.
+	if managedCluster.AutoScalerProfile != nil {
+		propertiesNormalized.AutoScalerProfile = managedCluster.AutoScalerProfile
+		existingMCPropertiesNormalized.AutoScalerProfile = existingMC.AutoScalerProfile
 	}
 
-	if existingMC.APIServerAccessProfile != nil {
-		existingMCPropertiesNormalized.APIServerAccessProfile = &containerservice.ManagedClusterAPIServerAccessProfile{
-			AuthorizedIPRanges: existingMC.APIServerAccessProfile.AuthorizedIPRanges,
-		}
+	// The add-on profiles of the existing cluster are only compared when some are desired, as AKS may enable some.
+	if managedCluster.AddonProfiles != nil {
+		propertiesNormalized.AddonProfiles = normalizeAddonProfiles(managedCluster.AddonProfiles)
+		existingMCPropertiesNormalized.AddonProfiles = normalizeAddonProfiles(existingMC.AddonProfiles)
 	}
 
 	clusterNormalized := &containerservice.ManagedCluster{
@@ -232,15 +418,26 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	for i := range managedClusterSpec.AgentPools {
 		pool := managedClusterSpec.AgentPools[i]
+		vnetSubnetID := managedClusterSpec.VnetSubnetID
+		if pool.VnetSubnetID != "" {
+			vnetSubnetID = pool.VnetSubnetID
+		}
 		profile := containerservice.ManagedClusterAgentPoolProfile{
-			Name:              &pool.Name,
-			VMSize:            &pool.SKU,
-			OsDiskSizeGB:      &pool.OSDiskSizeGB,
-			Count:             &pool.Replicas,
-			Type:              containerservice.AgentPoolTypeVirtualMachineScaleSets,
-			VnetSubnetID:      &managedClusterSpec.VnetSubnetID,
-			Mode:              containerservice.AgentPoolMode(pool.Mode),
-			AvailabilityZones: &pool.AvailabilityZones,
+			Name:                   &pool.Name,
+			VMSize:                 &pool.SKU,
+			OsDiskSizeGB:           &pool.OSDiskSizeGB,
+			Count:                  &pool.Replicas,
+			Type:                   containerservice.AgentPoolTypeVirtualMachineScaleSets,
+			VnetSubnetID:           &vnetSubnetID,
+			Mode:                   containerservice.AgentPoolMode(pool.Mode),
+			AvailabilityZones:      &pool.AvailabilityZones,
+			NodeLabels:             pool.NodeLabels,
+			ScaleSetPriority:       containerservice.ScaleSetPriority(pool.ScaleSetPriority),
+			ScaleSetEvictionPolicy: containerservice.ScaleSetEvictionPolicy(pool.ScaleSetEvictionPolicy),
+			SpotMaxPrice:           pool.SpotMaxPrice,
+		}
+		if len(pool.NodeTaints) > 0 {
+			profile.NodeTaints = &pool.NodeTaints
 		}
 		*managedCluster.AgentPoolProfiles = append(*managedCluster.AgentPoolProfiles, profile)
 	}
@@ -253,8 +450,12 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 	}
 
+	if managedClusterSpec.DisableLocalAccounts != nil {
+		managedCluster.DisableLocalAccounts = managedClusterSpec.DisableLocalAccounts
+	}
+
 	if managedClusterSpec.SKU != nil {
-		tierName := containerservice.ManagedClusterSKUTier(managedClusterSpec.SKU.Tier)
+		tierName := convertToSKUTier(managedClusterSpec.SKU.Tier)
 		managedCluster.Sku = &containerservice.ManagedClusterSKU{
 			Name: containerservice.ManagedClusterSKUNameBasic,
 			Tier: tierName,
@@ -290,8 +491,24 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 	}
 
+	if managedClusterSpec.HTTPProxyConfig != nil {
+		managedCluster.HTTPProxyConfig = convertToHTTPProxyConfig(managedClusterSpec.HTTPProxyConfig)
+	}
+
+	if managedClusterSpec.AutoScalerProfile != nil {
+		managedCluster.AutoScalerProfile = convertToAutoScalerProfile(managedClusterSpec.AutoScalerProfile)
+	}
+
+	if len(managedClusterSpec.AddonProfiles) > 0 {
+		managedCluster.AddonProfiles = convertToAddonProfiles(managedClusterSpec.AddonProfiles)
+	}
+
 	if isCreate {
-		managedCluster, err = s.Client.CreateOrUpdate(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name, managedCluster)
+		if managedClusterSpec.NetworkPluginMode != "" || managedClusterSpec.NetworkDataplane != "" {
+			managedCluster, err = s.Client.CreateWithNetworkMode(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name, managedCluster, managedClusterSpec.NetworkPluginMode, managedClusterSpec.NetworkDataplane)
+		} else {
+			managedCluster, err = s.Client.CreateOrUpdate(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name, managedCluster)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to create managed cluster, %w", err)
 		}
@@ -303,6 +520,18 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			return azure.WithTransientError(errors.New(msg), 20*time.Second)
 		}
 
+		// A stopped cluster can't be updated, so it is left as is while it is to stay stopped, and started otherwise.
+		if existingMC.PowerState != nil && existingMC.PowerState.Code == containerservice.CodeStopped {
+			if managedClusterSpec.Stopped {
+				s.Scope.SetPowerState(string(containerservice.CodeStopped))
+				return nil
+			}
+			klog.V(2).Infof("Starting managed cluster %s", managedClusterSpec.Name)
+			if err := s.Client.Start(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name); err != nil {
+				return errors.Wrap(err, "failed to start managed cluster")
+			}
+		}
+
 		// Normalize the LoadBalancerProfile so the diff below doesn't get thrown off by AKS added properties.
 		if managedCluster.NetworkProfile.LoadBalancerProfile == nil {
 			// If our LoadBalancerProfile generated by the spec is nil, then don't worry about what AKS has added.
@@ -313,6 +542,16 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			existingMC.NetworkProfile.LoadBalancerProfile.EffectiveOutboundIPs = nil
 		}
 
+		if managedCluster.AutoScalerProfile != nil && existingMC.AutoScalerProfile != nil {
+			managedCluster.AutoScalerProfile = mergeAutoScalerProfile(managedCluster.AutoScalerProfile, existingMC.AutoScalerProfile)
+		}
+
+		managedCluster.APIServerAccessProfile = mergeAPIServerAccessProfile(managedCluster.APIServerAccessProfile, existingMC.APIServerAccessProfile)
+
+		if managedCluster.AddonProfiles != nil {
+			managedCluster.AddonProfiles = mergeAddonProfiles(managedCluster.AddonProfiles, existingMC.AddonProfiles)
+		}
+
 		diff := computeDiffOfNormalizedClusters(managedCluster, existingMC)
 		if diff != "" {
 			klog.V(2).Infof("Update required (+new -old):\n%s", diff)
@@ -323,6 +562,20 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 	}
 
+	if err := s.reconcileWorkloadIdentity(ctx, managedClusterSpec); err != nil {
+		return errors.Wrap(err, "failed to reconcile the OIDC issuer and workload identity of managed cluster")
+	}
+
+	powerState := containerservice.CodeRunning
+	if managedClusterSpec.Stopped {
+		klog.V(2).Infof("Stopping managed cluster %s", managedClusterSpec.Name)
+		if err := s.Client.Stop(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name); err != nil {
+			return errors.Wrap(err, "failed to stop managed cluster")
+		}
+		powerState = containerservice.CodeStopped
+	}
+	s.Scope.SetPowerState(string(powerState))
+
 	// Update control plane endpoint.
 	if managedCluster.ManagedClusterProperties != nil && managedCluster.ManagedClusterProperties.Fqdn != nil {
 		endpoint := clusterv1.APIEndpoint{
@@ -334,15 +587,170 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	// Update kubeconfig data
 	// Always fetch credentials in case of rotation
-	kubeConfigData, err := s.Client.GetCredentials(ctx, s.Scope.ResourceGroup(), s.Scope.ClusterName())
+	kubeConfigData, err := s.getKubeConfigData(ctx, managedClusterSpec)
 	if err != nil {
-		return errors.Wrap(err, "failed to get credentials for managed cluster")
+		return err
 	}
 	s.Scope.SetKubeConfigData(kubeConfigData)
 
 	return nil
 }
 
+// getKubeConfigData returns the admin kubeconfig of the managed cluster. When its local accounts are disabled, it
+// returns the user kubeconfig instead, authenticated with an AAD token of the identity of the cluster, and reports when
+// the token expires so that it is refreshed in time.
+func (s *Service) getKubeConfigData(ctx context.Context, managedClusterSpec azure.ManagedClusterSpec) ([]byte, error) {
+	if managedClusterSpec.DisableLocalAccounts == nil || !*managedClusterSpec.DisableLocalAccounts {
+		kubeConfigData, err := s.Client.GetCredentials(ctx, s.Scope.ResourceGroup(), s.Scope.ClusterName())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get credentials for managed cluster")
+		}
+		return kubeConfigData, nil
+	}
+
+	kubeConfigData, err := s.Client.GetUserCredentials(ctx, s.Scope.ResourceGroup(), s.Scope.ClusterName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user credentials for managed cluster")
+	}
+	token, expiresOn, err := s.Scope.GetAccessToken(ctx, aksAADServerAppID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get AAD token for managed cluster")
+	}
+	s.Scope.SetKubeConfigTokenExpiry(expiresOn)
+	return withBearerToken(kubeConfigData, token)
+}
+
+// withBearerToken replaces the AAD auth provider or exec plugin of the users of a kubeconfig with a bearer token, so
+// that it can be used without client-side AAD tooling. The token expires after about an hour, so the original
+// credentials are kept as a kubelogin exec plugin in a "-kubelogin" user and context next to each user and context,
+// for the clients which can't reload the kubeconfig when it is refreshed.
+func withBearerToken(kubeConfigData []byte, token string) ([]byte, error) {
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal(kubeConfigData, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse user kubeconfig")
+	}
+
+	users, _ := config["users"].([]interface{})
+	replaced := map[string]bool{}
+	var kubeloginUsers []interface{}
+	for _, u := range users {
+		user, ok := u.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := user["name"].(string)
+		if exec := kubeloginExec(user["user"]); exec != nil && name != "" {
+			kubeloginUsers = append(kubeloginUsers, map[string]interface{}{
+				"name": name + kubeloginSuffix,
+				"user": map[string]interface{}{"exec": exec},
+			})
+			replaced[name] = true
+		}
+		user["user"] = map[string]interface{}{"token": token}
+	}
+	config["users"] = append(users, kubeloginUsers...)
+
+	contexts, _ := config["contexts"].([]interface{})
+	var kubeloginContexts []interface{}
+	for _, c := range contexts {
+		kubeContext, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := kubeContext["name"].(string)
+		spec, _ := kubeContext["context"].(map[string]interface{})
+		user, _ := spec["user"].(string)
+		if name == "" || !replaced[user] {
+			continue
+		}
+		kubeloginSpec := map[string]interface{}{}
+		for k, v := range spec {
+			kubeloginSpec[k] = v
+		}
+		kubeloginSpec["user"] = user + kubeloginSuffix
+		kubeloginContexts = append(kubeloginContexts, map[string]interface{}{
+			"name":    name + kubeloginSuffix,
+			"context": kubeloginSpec,
+		})
+	}
+	if len(kubeloginContexts) > 0 {
+		config["contexts"] = append(contexts, kubeloginContexts...)
+	}
+
+	return yaml.Marshal(config)
+}
+
+// kubeloginSuffix is the suffix of the names of the users and contexts of a kubeconfig authenticated with kubelogin.
+const kubeloginSuffix = "-kubelogin"
+
+// kubeloginExec returns the kubelogin exec plugin authenticating the user of a kubeconfig: its exec plugin if it has
+// one, or the one kubelogin converts its AAD auth provider to, or nil if it has neither.
+func kubeloginExec(u interface{}) map[string]interface{} {
+	user, _ := u.(map[string]interface{})
+	if exec, ok := user["exec"].(map[string]interface{}); ok {
+		return exec
+	}
+	authProvider, _ := user["auth-provider"].(map[string]interface{})
+	if name, _ := authProvider["name"].(string); name != "azure" {
+		return nil
+	}
+	config, _ := authProvider["config"].(map[string]interface{})
+	args := []interface{}{"get-token", "--login", "devicecode"}
+	for _, arg := range []struct{ flag, key string }{
+		{"--environment", "environment"},
+		{"--server-id", "apiserver-id"},
+		{"--client-id", "client-id"},
+		{"--tenant-id", "tenant-id"},
+	} {
+		if value, _ := config[arg.key].(string); value != "" {
+			args = append(args, arg.flag, value)
+		}
+	}
+	return map[string]interface{}{
+		"apiVersion":         "client.authentication.k8s.io/v1beta1",
+		"command":            "kubelogin",
+		"args":               args,
+		"provideClusterInfo": false,
+	}
+}
+
+// reconcileWorkloadIdentity enables or disables the OIDC issuer and workload identity of the managed cluster in place,
+// and reports the URL of its OIDC issuer.
+func (s *Service) reconcileWorkloadIdentity(ctx context.Context, managedClusterSpec azure.ManagedClusterSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "managedclusters.Service.reconcileWorkloadIdentity")
+	defer done()
+
+	if managedClusterSpec.OIDCIssuerEnabled == nil && managedClusterSpec.WorkloadIdentityEnabled == nil {
+		return nil
+	}
+
+	existing, err := s.Client.GetWorkloadIdentity(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the OIDC issuer and workload identity of managed cluster")
+	}
+
+	desired := existing
+	if managedClusterSpec.OIDCIssuerEnabled != nil {
+		desired.OIDCIssuerEnabled = *managedClusterSpec.OIDCIssuerEnabled
+	}
+	if managedClusterSpec.WorkloadIdentityEnabled != nil {
+		desired.WorkloadIdentityEnabled = *managedClusterSpec.WorkloadIdentityEnabled
+	}
+	if desired.OIDCIssuerEnabled != existing.OIDCIssuerEnabled || desired.WorkloadIdentityEnabled != existing.WorkloadIdentityEnabled {
+		log.V(2).Info("updating OIDC issuer and workload identity", "oidcIssuer", desired.OIDCIssuerEnabled, "workloadIdentity", desired.WorkloadIdentityEnabled)
+		if err := s.Client.SetWorkloadIdentity(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name, desired); err != nil {
+			return errors.Wrap(err, "failed to update the OIDC issuer and workload identity of managed cluster")
+		}
+		// The URL of the OIDC issuer is assigned by AKS when it is enabled.
+		if existing, err = s.Client.GetWorkloadIdentity(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name); err != nil {
+			return errors.Wrap(err, "failed to get the OIDC issuer and workload identity of managed cluster")
+		}
+	}
+
+	s.Scope.SetOIDCIssuerURL(existing.OIDCIssuerURL)
+	return nil
+}
+
 // Delete deletes the virtual network with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.Service.Delete")
@@ -18,13 +18,17 @@ package managedclusters
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/utils/pointer"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -61,6 +65,7 @@ func TestReconcile(t *testing.T) {
 					ResourceGroupName: "my-rg",
 				}, nil)
 				s.SetControlPlaneEndpoint(gomock.Any()).Times(1)
+				s.SetPowerState("Running")
 				s.SetKubeConfigData(gomock.Any()).Times(1)
 			},
 		},
@@ -138,9 +143,622 @@ func TestReconcile(t *testing.T) {
 						OSDiskSizeGB: 0,
 					},
 				}, nil)
+				s.SetPowerState("Running")
 				s.SetKubeConfigData(gomock.Any()).Times(1)
 			},
 		},
+		{
+			name:          "no managedcluster exists with the overlay network plugin mode and the cilium network dataplane",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.CreateWithNetworkMode(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any(), "overlay", "cilium").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					NetworkPlugin:     "azure",
+					NetworkPluginMode: "overlay",
+					NetworkPolicy:     "cilium",
+					NetworkDataplane:  "cilium",
+					PodCIDR:           "192.168.0.0/16",
+				}, nil)
+				s.GetAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "running managedcluster is stopped",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+					PowerState:        &containerservice.PowerState{Code: containerservice.CodeRunning},
+				}}, nil)
+				m.Stop(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(nil)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					Stopped:           true,
+				}, nil)
+				s.SetPowerState("Stopped")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "stopped managedcluster is not updated while it is to stay stopped",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+					PowerState:        &containerservice.PowerState{Code: containerservice.CodeStopped},
+				}}, nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					Stopped:           true,
+				}, nil)
+				s.SetPowerState("Stopped")
+			},
+		},
+		{
+			name:          "stopped managedcluster is started",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+					PowerState:        &containerservice.PowerState{Code: containerservice.CodeStopped},
+				}}, nil)
+				m.Start(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(nil)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					Stopped:           false,
+				}, nil)
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "fail to start stopped managedcluster",
+			expectedError: "failed to start managed cluster: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+					PowerState:        &containerservice.PowerState{Code: containerservice.CodeStopped},
+				}}, nil)
+				m.Start(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error"))
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					Stopped:           false,
+				}, nil)
+			},
+		},
+		{
+			name:          "autoscaler profile matching the existing one with AKS defaults is not updated",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+					AutoScalerProfile: &containerservice.ManagedClusterPropertiesAutoScalerProfile{
+						Expander:           containerservice.ExpanderRandom,
+						ScanInterval:       pointer.String("20s"),
+						MaxEmptyBulkDelete: pointer.String("10"),
+					},
+				}}, nil)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					AutoScalerProfile: &azure.AutoScalerProfile{
+						ScanInterval: pointer.String("20s"),
+					},
+				}, nil)
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "autoscaler profile change is updated in place keeping the existing settings",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+					AutoScalerProfile: &containerservice.ManagedClusterPropertiesAutoScalerProfile{
+						Expander:           containerservice.ExpanderRandom,
+						ScanInterval:       pointer.String("10s"),
+						MaxEmptyBulkDelete: pointer.String("10"),
+					},
+				}}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).DoAndReturn(
+					func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if diff := cmp.Diff(&containerservice.ManagedClusterPropertiesAutoScalerProfile{
+							Expander:           containerservice.ExpanderLeastWaste,
+							ScanInterval:       pointer.String("10s"),
+							MaxEmptyBulkDelete: pointer.String("10"),
+						}, mc.AutoScalerProfile); diff != "" {
+							return containerservice.ManagedCluster{}, errors.New(diff)
+						}
+						return mc, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					AutoScalerProfile: &azure.AutoScalerProfile{
+						Expander: pointer.String("least-waste"),
+					},
+				}, nil)
+				s.GetAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "authorized IP ranges change is updated in place",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+					APIServerAccessProfile: &containerservice.ManagedClusterAPIServerAccessProfile{
+						AuthorizedIPRanges:   &[]string{"12.34.56.78/32"},
+						EnablePrivateCluster: pointer.Bool(false),
+					},
+				}}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).DoAndReturn(
+					func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if diff := cmp.Diff(&[]string{"12.34.56.78/32", "98.76.54.0/24"}, mc.APIServerAccessProfile.AuthorizedIPRanges); diff != "" {
+							return containerservice.ManagedCluster{}, errors.New(diff)
+						}
+						return mc, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					APIServerAccessProfile: &azure.APIServerAccessProfile{
+						AuthorizedIPRanges: []string{"12.34.56.78/32", "98.76.54.0/24"},
+					},
+				}, nil)
+				s.GetAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "authorized IP ranges removed from the spec are cleared",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+					APIServerAccessProfile: &containerservice.ManagedClusterAPIServerAccessProfile{
+						AuthorizedIPRanges:   &[]string{"12.34.56.78/32"},
+						EnablePrivateCluster: pointer.Bool(false),
+					},
+				}}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).DoAndReturn(
+					func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if diff := cmp.Diff(&containerservice.ManagedClusterAPIServerAccessProfile{
+							AuthorizedIPRanges:   &[]string{},
+							EnablePrivateCluster: pointer.Bool(false),
+						}, mc.APIServerAccessProfile); diff != "" {
+							return containerservice.ManagedCluster{}, errors.New(diff)
+						}
+						return mc, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+				}, nil)
+				s.GetAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "no authorized IP ranges matching the existing cluster without any are not updated",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+					APIServerAccessProfile: &containerservice.ManagedClusterAPIServerAccessProfile{
+						EnablePrivateCluster: pointer.Bool(true),
+					},
+				}}, nil)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					APIServerAccessProfile: &azure.APIServerAccessProfile{
+						EnablePrivateCluster: pointer.Bool(true),
+					},
+				}, nil)
+				s.GetAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "SKU tier change is updated in place with the tier name of the API version",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{
+					Sku: &containerservice.ManagedClusterSKU{
+						Name: containerservice.ManagedClusterSKUNameBasic,
+						Tier: containerservice.ManagedClusterSKUTierFree,
+					},
+					ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+						Fqdn:              pointer.String("my-managedcluster-fqdn"),
+						ProvisioningState: pointer.String("Succeeded"),
+						KubernetesVersion: pointer.String("v1.22.6"),
+						NetworkProfile:    &containerservice.NetworkProfile{},
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).DoAndReturn(
+					func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if diff := cmp.Diff(&containerservice.ManagedClusterSKU{
+							Name: containerservice.ManagedClusterSKUNameBasic,
+							Tier: containerservice.ManagedClusterSKUTierPaid,
+						}, mc.Sku); diff != "" {
+							return containerservice.ManagedCluster{}, errors.New(diff)
+						}
+						return mc, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					SKU:               &azure.SKU{Tier: "Standard"},
+				}, nil)
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "standard SKU tier matching the existing paid tier is not updated",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{
+					Sku: &containerservice.ManagedClusterSKU{
+						Name: containerservice.ManagedClusterSKUNameBasic,
+						Tier: containerservice.ManagedClusterSKUTierPaid,
+					},
+					ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+						Fqdn:              pointer.String("my-managedcluster-fqdn"),
+						ProvisioningState: pointer.String("Succeeded"),
+						KubernetesVersion: pointer.String("v1.22.6"),
+						NetworkProfile:    &containerservice.NetworkProfile{},
+					},
+				}, nil)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					SKU:               &azure.SKU{Tier: "Standard"},
+				}, nil)
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "addon profiles matching the existing ones are not updated",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+					AddonProfiles: map[string]*containerservice.ManagedClusterAddonProfile{
+						"omsagent": {
+							Enabled: pointer.Bool(true),
+							Config: map[string]*string{
+								"logAnalyticsWorkspaceResourceID": pointer.String("my-workspace"),
+								"useAADAuth":                      pointer.String("true"),
+							},
+							Identity: &containerservice.ManagedClusterAddonProfileIdentity{ClientID: pointer.String("my-client-id")},
+						},
+						"azurepolicy": {
+							Enabled: pointer.Bool(true),
+						},
+					},
+				}}, nil)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					AddonProfiles: []azure.AddonProfile{
+						{
+							Name:    "omsagent",
+							Enabled: true,
+							Config:  map[string]string{"logAnalyticsWorkspaceResourceID": "my-workspace"},
+						},
+						{
+							Name:    "azurepolicy",
+							Enabled: true,
+						},
+					},
+				}, nil)
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "addon profile change is updated in place keeping the existing add-ons and settings",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+					AddonProfiles: map[string]*containerservice.ManagedClusterAddonProfile{
+						"omsagent": {
+							Enabled: pointer.Bool(true),
+							Config: map[string]*string{
+								"logAnalyticsWorkspaceResourceID": pointer.String("my-workspace"),
+								"useAADAuth":                      pointer.String("true"),
+							},
+							Identity: &containerservice.ManagedClusterAddonProfileIdentity{ClientID: pointer.String("my-client-id")},
+						},
+						"azurepolicy": {
+							Enabled: pointer.Bool(true),
+						},
+					},
+				}}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).DoAndReturn(
+					func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if diff := cmp.Diff(map[string]*containerservice.ManagedClusterAddonProfile{
+							"omsagent": {
+								Enabled: pointer.Bool(true),
+								Config: map[string]*string{
+									"logAnalyticsWorkspaceResourceID": pointer.String("my-workspace"),
+									"useAADAuth":                      pointer.String("true"),
+								},
+							},
+							"azurepolicy": {
+								Enabled: pointer.Bool(true),
+							},
+							"azureKeyvaultSecretsProvider": {
+								Enabled: pointer.Bool(true),
+								Config:  map[string]*string{"enableSecretRotation": pointer.String("true")},
+							},
+						}, mc.AddonProfiles); diff != "" {
+							return containerservice.ManagedCluster{}, errors.New(diff)
+						}
+						return mc, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					AddonProfiles: []azure.AddonProfile{
+						{
+							Name:    "omsagent",
+							Enabled: true,
+							Config:  map[string]string{"logAnalyticsWorkspaceResourceID": "my-workspace"},
+						},
+						{
+							Name:    "azureKeyvaultSecretsProvider",
+							Enabled: true,
+							Config:  map[string]string{"enableSecretRotation": "true"},
+						},
+					},
+				}, nil)
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "OIDC issuer and workload identity are enabled in place and the issuer URL is reported",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+				}}, nil)
+				gomock.InOrder(
+					m.GetWorkloadIdentity(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(azure.WorkloadIdentityProfile{}, nil),
+					m.SetWorkloadIdentity(gomockinternal.AContext(), "my-rg", "my-managedcluster", azure.WorkloadIdentityProfile{
+						OIDCIssuerEnabled:       true,
+						WorkloadIdentityEnabled: true,
+					}).Return(nil),
+					m.GetWorkloadIdentity(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(azure.WorkloadIdentityProfile{
+						OIDCIssuerEnabled:       true,
+						OIDCIssuerURL:           "https://oidc.example.com/my-managedcluster/",
+						WorkloadIdentityEnabled: true,
+					}, nil),
+				)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:                    "my-managedcluster",
+					ResourceGroupName:       "my-rg",
+					Version:                 "v1.22.6",
+					OIDCIssuerEnabled:       pointer.Bool(true),
+					WorkloadIdentityEnabled: pointer.Bool(true),
+				}, nil)
+				s.SetOIDCIssuerURL("https://oidc.example.com/my-managedcluster/")
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "OIDC issuer matching the existing one is not updated",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+				}}, nil)
+				m.GetWorkloadIdentity(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(azure.WorkloadIdentityProfile{
+					OIDCIssuerEnabled: true,
+					OIDCIssuerURL:     "https://oidc.example.com/my-managedcluster/",
+				}, nil)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					OIDCIssuerEnabled: pointer.Bool(true),
+				}, nil)
+				s.SetOIDCIssuerURL("https://oidc.example.com/my-managedcluster/")
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "fail to enable the OIDC issuer",
+			expectedError: "failed to reconcile the OIDC issuer and workload identity of managed cluster: failed to update the OIDC issuer and workload identity of managed cluster: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:              pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState: pointer.String("Succeeded"),
+					KubernetesVersion: pointer.String("v1.22.6"),
+					NetworkProfile:    &containerservice.NetworkProfile{},
+				}}, nil)
+				m.GetWorkloadIdentity(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(azure.WorkloadIdentityProfile{}, nil)
+				m.SetWorkloadIdentity(gomockinternal.AContext(), "my-rg", "my-managedcluster", azure.WorkloadIdentityProfile{OIDCIssuerEnabled: true}).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error"))
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "v1.22.6",
+					OIDCIssuerEnabled: pointer.Bool(true),
+				}, nil)
+			},
+		},
+		{
+			name:          "user kubeconfig authenticated with an AAD token is stored when local accounts are disabled",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:                 pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState:    pointer.String("Succeeded"),
+					KubernetesVersion:    pointer.String("v1.22.6"),
+					NetworkProfile:       &containerservice.NetworkProfile{},
+					DisableLocalAccounts: pointer.Bool(true),
+				}}, nil)
+				m.GetUserCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return([]byte(userKubeConfig), nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:                 "my-managedcluster",
+					ResourceGroupName:    "my-rg",
+					Version:              "v1.22.6",
+					DisableLocalAccounts: pointer.Bool(true),
+				}, nil)
+				s.GetAccessToken(gomockinternal.AContext(), "6dae42f8-4368-4678-94ff-3960e28e3630").Return("my-token", time.Date(2021, 1, 1, 1, 0, 0, 0, time.UTC), nil)
+				s.SetKubeConfigTokenExpiry(time.Date(2021, 1, 1, 1, 0, 0, 0, time.UTC))
+				s.SetPowerState("Running")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "fail to get an AAD token when local accounts are disabled",
+			expectedError: "failed to get AAD token for managed cluster: no identity",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+					Fqdn:                 pointer.String("my-managedcluster-fqdn"),
+					ProvisioningState:    pointer.String("Succeeded"),
+					KubernetesVersion:    pointer.String("v1.22.6"),
+					NetworkProfile:       &containerservice.NetworkProfile{},
+					DisableLocalAccounts: pointer.Bool(true),
+				}}, nil)
+				m.GetUserCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return([]byte(userKubeConfig), nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:                 "my-managedcluster",
+					ResourceGroupName:    "my-rg",
+					Version:              "v1.22.6",
+					DisableLocalAccounts: pointer.Bool(true),
+				}, nil)
+				s.GetAccessToken(gomockinternal.AContext(), "6dae42f8-4368-4678-94ff-3960e28e3630").Return("", time.Time{}, errors.New("no identity"))
+				s.SetPowerState("Running")
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -170,3 +788,183 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertToHTTPProxyConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		proxyConfig *azure.HTTPProxyConfig
+		want        *containerservice.ManagedClusterHTTPProxyConfig
+	}{
+		{
+			name:        "empty proxy config",
+			proxyConfig: &azure.HTTPProxyConfig{},
+			want:        &containerservice.ManagedClusterHTTPProxyConfig{},
+		},
+		{
+			name: "all fields set",
+			proxyConfig: &azure.HTTPProxyConfig{
+				HTTPProxy:  "http://proxy.example.com:3128",
+				HTTPSProxy: "http://proxy.example.com:3129",
+				NoProxy:    []string{"localhost", "10.0.0.0/8"},
+				TrustedCA:  "-----BEGIN CERTIFICATE-----\n",
+			},
+			want: &containerservice.ManagedClusterHTTPProxyConfig{
+				HTTPProxy:  pointer.String("http://proxy.example.com:3128"),
+				HTTPSProxy: pointer.String("http://proxy.example.com:3129"),
+				NoProxy:    &[]string{"localhost", "10.0.0.0/8"},
+				TrustedCa:  pointer.String("LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCg=="),
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(convertToHTTPProxyConfig(tc.proxyConfig)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestConvertToAutoScalerProfile(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(convertToAutoScalerProfile(&azure.AutoScalerProfile{
+		BalanceSimilarNodeGroups:      pointer.String("true"),
+		Expander:                      pointer.String("most-pods"),
+		ScaleDownUtilizationThreshold: pointer.String("0.6"),
+	})).To(Equal(&containerservice.ManagedClusterPropertiesAutoScalerProfile{
+		BalanceSimilarNodeGroups:      pointer.String("true"),
+		Expander:                      containerservice.ExpanderMostPods,
+		ScaleDownUtilizationThreshold: pointer.String("0.6"),
+	}))
+}
+
+// userKubeConfig is a user kubeconfig of an AKS cluster with managed AAD.
+const userKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    certificate-authority-data: Y2E=
+    server: https://my-managedcluster-fqdn:443
+  name: my-managedcluster
+contexts:
+- context:
+    cluster: my-managedcluster
+    user: clusterUser_my-rg_my-managedcluster
+  name: my-managedcluster
+current-context: my-managedcluster
+users:
+- name: clusterUser_my-rg_my-managedcluster
+  user:
+    auth-provider:
+      name: azure
+      config:
+        apiserver-id: 6dae42f8-4368-4678-94ff-3960e28e3630
+        client-id: 80faf920-1908-4b52-b5ef-a8e7bedfc67a
+        config-mode: "1"
+        environment: AzurePublicCloud
+        tenant-id: 00000000-0000-0000-0000-000000000000
+`
+
+func TestWithBearerToken(t *testing.T) {
+	g := NewWithT(t)
+
+	kubeConfigData, err := withBearerToken([]byte(userKubeConfig), "my-token")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	config, err := clientcmd.Load(kubeConfigData)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(config.AuthInfos).To(HaveKey("clusterUser_my-rg_my-managedcluster"))
+	authInfo := config.AuthInfos["clusterUser_my-rg_my-managedcluster"]
+	g.Expect(authInfo.Token).To(Equal("my-token"))
+	g.Expect(authInfo.AuthProvider).To(BeNil())
+	g.Expect(authInfo.Exec).To(BeNil())
+	g.Expect(config.Clusters["my-managedcluster"].Server).To(Equal("https://my-managedcluster-fqdn:443"))
+	g.Expect(config.CurrentContext).To(Equal("my-managedcluster"))
+
+	// The AAD auth provider is kept as a kubelogin exec plugin in its own user and context.
+	g.Expect(config.AuthInfos).To(HaveKey("clusterUser_my-rg_my-managedcluster-kubelogin"))
+	exec := config.AuthInfos["clusterUser_my-rg_my-managedcluster-kubelogin"].Exec
+	g.Expect(exec).NotTo(BeNil())
+	g.Expect(exec.Command).To(Equal("kubelogin"))
+	g.Expect(exec.Args).To(Equal([]string{
+		"get-token", "--login", "devicecode",
+		"--environment", "AzurePublicCloud",
+		"--server-id", "6dae42f8-4368-4678-94ff-3960e28e3630",
+		"--client-id", "80faf920-1908-4b52-b5ef-a8e7bedfc67a",
+		"--tenant-id", "00000000-0000-0000-0000-000000000000",
+	}))
+	g.Expect(config.Contexts).To(HaveKey("my-managedcluster-kubelogin"))
+	g.Expect(config.Contexts["my-managedcluster-kubelogin"].Cluster).To(Equal("my-managedcluster"))
+	g.Expect(config.Contexts["my-managedcluster-kubelogin"].AuthInfo).To(Equal("clusterUser_my-rg_my-managedcluster-kubelogin"))
+
+	_, err = withBearerToken([]byte("not a kubeconfig"), "my-token")
+	g.Expect(err).To(HaveOccurred())
+}
+
+const multiUserKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://my-managedcluster-fqdn:443
+  name: my-managedcluster
+contexts:
+- context:
+    cluster: my-managedcluster
+    user: clusterUser_my-rg_my-managedcluster
+  name: my-managedcluster
+- context:
+    cluster: my-managedcluster
+    user: other-user
+  name: other-context
+current-context: my-managedcluster
+users:
+- name: clusterUser_my-rg_my-managedcluster
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: kubelogin
+      args:
+      - get-token
+      - --server-id
+      - 6dae42f8-4368-4678-94ff-3960e28e3630
+- name: other-user
+  user:
+    auth-provider:
+      name: azure
+      config:
+        apiserver-id: 6dae42f8-4368-4678-94ff-3960e28e3630
+- name: static-user
+  user:
+    username: admin
+    password: secret
+`
+
+func TestWithBearerTokenReplacesEveryUser(t *testing.T) {
+	g := NewWithT(t)
+
+	kubeConfigData, err := withBearerToken([]byte(multiUserKubeConfig), "my-token")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	config, err := clientcmd.Load(kubeConfigData)
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, name := range []string{"clusterUser_my-rg_my-managedcluster", "other-user", "static-user"} {
+		g.Expect(config.AuthInfos).To(HaveKey(name))
+		authInfo := config.AuthInfos[name]
+		g.Expect(authInfo.Token).To(Equal("my-token"), "user %s", name)
+		g.Expect(authInfo.Exec).To(BeNil(), "user %s", name)
+		g.Expect(authInfo.AuthProvider).To(BeNil(), "user %s", name)
+		g.Expect(authInfo.Username).To(BeEmpty(), "user %s", name)
+		g.Expect(authInfo.Password).To(BeEmpty(), "user %s", name)
+	}
+
+	// Only the users authenticated with AAD get a kubelogin user and context.
+	g.Expect(config.AuthInfos).To(HaveLen(5))
+	g.Expect(config.AuthInfos["clusterUser_my-rg_my-managedcluster-kubelogin"].Exec.Args).To(Equal([]string{
+		"get-token", "--server-id", "6dae42f8-4368-4678-94ff-3960e28e3630",
+	}))
+	g.Expect(config.AuthInfos["other-user-kubelogin"].Exec.Command).To(Equal("kubelogin"))
+	g.Expect(config.AuthInfos).NotTo(HaveKey("static-user-kubelogin"))
+	g.Expect(config.Contexts).To(HaveLen(4))
+	g.Expect(config.Contexts["my-managedcluster-kubelogin"].AuthInfo).To(Equal("clusterUser_my-rg_my-managedcluster-kubelogin"))
+	g.Expect(config.Contexts["other-context-kubelogin"].AuthInfo).To(Equal("other-user-kubelogin"))
+}
@@ -26,6 +26,7 @@ import (
 
 	containerservice "github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
 	gomock "github.com/golang/mock/gomock"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
 )
 
 // MockClient is a mock of Client interface.
@@ -66,6 +67,21 @@ func (mr *MockClientMockRecorder) CreateOrUpdate(arg0, arg1, arg2, arg3 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdate", reflect.TypeOf((*MockClient)(nil).CreateOrUpdate), arg0, arg1, arg2, arg3)
 }
 
+// CreateWithNetworkMode mocks base method.
+func (m *MockClient) CreateWithNetworkMode(arg0 context.Context, arg1, arg2 string, arg3 containerservice.ManagedCluster, arg4, arg5 string) (containerservice.ManagedCluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithNetworkMode", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(containerservice.ManagedCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithNetworkMode indicates an expected call of CreateWithNetworkMode.
+func (mr *MockClientMockRecorder) CreateWithNetworkMode(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithNetworkMode", reflect.TypeOf((*MockClient)(nil).CreateWithNetworkMode), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
 // Delete mocks base method.
 func (m *MockClient) Delete(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -109,3 +125,75 @@ func (mr *MockClientMockRecorder) GetCredentials(arg0, arg1, arg2 interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredentials", reflect.TypeOf((*MockClient)(nil).GetCredentials), arg0, arg1, arg2)
 }
+
+// GetUserCredentials mocks base method.
+func (m *MockClient) GetUserCredentials(arg0 context.Context, arg1, arg2 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCredentials", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCredentials indicates an expected call of GetUserCredentials.
+func (mr *MockClientMockRecorder) GetUserCredentials(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCredentials", reflect.TypeOf((*MockClient)(nil).GetUserCredentials), arg0, arg1, arg2)
+}
+
+// GetWorkloadIdentity mocks base method.
+func (m *MockClient) GetWorkloadIdentity(arg0 context.Context, arg1, arg2 string) (azure.WorkloadIdentityProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkloadIdentity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(azure.WorkloadIdentityProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkloadIdentity indicates an expected call of GetWorkloadIdentity.
+func (mr *MockClientMockRecorder) GetWorkloadIdentity(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkloadIdentity", reflect.TypeOf((*MockClient)(nil).GetWorkloadIdentity), arg0, arg1, arg2)
+}
+
+// SetWorkloadIdentity mocks base method.
+func (m *MockClient) SetWorkloadIdentity(arg0 context.Context, arg1, arg2 string, arg3 azure.WorkloadIdentityProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWorkloadIdentity", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWorkloadIdentity indicates an expected call of SetWorkloadIdentity.
+func (mr *MockClientMockRecorder) SetWorkloadIdentity(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWorkloadIdentity", reflect.TypeOf((*MockClient)(nil).SetWorkloadIdentity), arg0, arg1, arg2, arg3)
+}
+
+// Start mocks base method.
+func (m *MockClient) Start(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockClientMockRecorder) Start(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockClient)(nil).Start), arg0, arg1, arg2)
+}
+
+// Stop mocks base method.
+func (m *MockClient) Stop(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockClientMockRecorder) Stop(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockClient)(nil).Stop), arg0, arg1, arg2)
+}
@@ -23,6 +23,7 @@ package mock_managedclusters
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	autorest "github.com/Azure/go-autorest/autorest"
 	gomock "github.com/golang/mock/gomock"
@@ -195,6 +196,22 @@ func (mr *MockManagedClusterScopeMockRecorder) FailureDomains() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockManagedClusterScope)(nil).FailureDomains))
 }
 
+// GetAccessToken mocks base method.
+func (m *MockManagedClusterScope) GetAccessToken(ctx context.Context, resource string) (string, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessToken", ctx, resource)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAccessToken indicates an expected call of GetAccessToken.
+func (mr *MockManagedClusterScopeMockRecorder) GetAccessToken(ctx, resource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessToken", reflect.TypeOf((*MockManagedClusterScope)(nil).GetAccessToken), ctx, resource)
+}
+
 // GetAgentPoolSpecs mocks base method.
 func (m *MockManagedClusterScope) GetAgentPoolSpecs(ctx context.Context) ([]azure.AgentPoolSpec, error) {
 	m.ctrl.T.Helper()
@@ -281,6 +298,34 @@ func (mr *MockManagedClusterScopeMockRecorder) ManagedClusterSpec() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedClusterSpec", reflect.TypeOf((*MockManagedClusterScope)(nil).ManagedClusterSpec))
 }
 
+// ProxyConfig mocks base method.
+func (m *MockManagedClusterScope) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockManagedClusterScopeMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockManagedClusterScope)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockManagedClusterScope) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockManagedClusterScopeMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockManagedClusterScope)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockManagedClusterScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -319,6 +364,42 @@ func (mr *MockManagedClusterScopeMockRecorder) SetKubeConfigData(arg0 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKubeConfigData", reflect.TypeOf((*MockManagedClusterScope)(nil).SetKubeConfigData), arg0)
 }
 
+// SetKubeConfigTokenExpiry mocks base method.
+func (m *MockManagedClusterScope) SetKubeConfigTokenExpiry(arg0 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetKubeConfigTokenExpiry", arg0)
+}
+
+// SetKubeConfigTokenExpiry indicates an expected call of SetKubeConfigTokenExpiry.
+func (mr *MockManagedClusterScopeMockRecorder) SetKubeConfigTokenExpiry(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKubeConfigTokenExpiry", reflect.TypeOf((*MockManagedClusterScope)(nil).SetKubeConfigTokenExpiry), arg0)
+}
+
+// SetOIDCIssuerURL mocks base method.
+func (m *MockManagedClusterScope) SetOIDCIssuerURL(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetOIDCIssuerURL", arg0)
+}
+
+// SetOIDCIssuerURL indicates an expected call of SetOIDCIssuerURL.
+func (mr *MockManagedClusterScopeMockRecorder) SetOIDCIssuerURL(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOIDCIssuerURL", reflect.TypeOf((*MockManagedClusterScope)(nil).SetOIDCIssuerURL), arg0)
+}
+
+// SetPowerState mocks base method.
+func (m *MockManagedClusterScope) SetPowerState(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPowerState", arg0)
+}
+
+// SetPowerState indicates an expected call of SetPowerState.
+func (mr *MockManagedClusterScopeMockRecorder) SetPowerState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPowerState", reflect.TypeOf((*MockManagedClusterScope)(nil).SetPowerState), arg0)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockManagedClusterScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -346,3 +427,17 @@ func (mr *MockManagedClusterScopeMockRecorder) TenantID() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockManagedClusterScope)(nil).TenantID))
 }
+
+// TrustedCAs mocks base method.
+func (m *MockManagedClusterScope) TrustedCAs() *v1beta1.TrustedCASource {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrustedCAs")
+	ret0, _ := ret[0].(*v1beta1.TrustedCASource)
+	return ret0
+}
+
+// TrustedCAs indicates an expected call of TrustedCAs.
+func (mr *MockManagedClusterScopeMockRecorder) TrustedCAs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustedCAs", reflect.TypeOf((*MockManagedClusterScope)(nil).TrustedCAs))
+}
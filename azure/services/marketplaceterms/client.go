@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package marketplaceterms
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/marketplaceordering/mgmt/2015-06-01/marketplaceordering"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, azure.MarketplaceTermsSpec) (marketplaceordering.AgreementTerms, error)
+	Accept(context.Context, azure.MarketplaceTermsSpec, marketplaceordering.AgreementTerms) error
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	agreements marketplaceordering.MarketplaceAgreementsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new marketplace agreements client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	return &azureClient{
+		agreements: newMarketplaceAgreementsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newMarketplaceAgreementsClient creates a new marketplace agreements client from subscription ID.
+func newMarketplaceAgreementsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) marketplaceordering.MarketplaceAgreementsClient {
+	agreementsClient := marketplaceordering.NewMarketplaceAgreementsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&agreementsClient.Client, authorizer, "marketplaceterms")
+	return agreementsClient
+}
+
+// Get gets the marketplace terms of the plan of the spec for the subscription.
+func (ac *azureClient) Get(ctx context.Context, spec azure.MarketplaceTermsSpec) (_ marketplaceordering.AgreementTerms, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "marketplaceterms.AzureClient.Get")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.agreements.Get(ctx, spec.Publisher, spec.Offer, spec.Plan)
+}
+
+// Accept accepts the marketplace terms of the plan of the spec for the subscription, like
+// `az vm image terms accept` does.
+func (ac *azureClient) Accept(ctx context.Context, spec azure.MarketplaceTermsSpec, terms marketplaceordering.AgreementTerms) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "marketplaceterms.AzureClient.Accept")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	if terms.AgreementProperties == nil {
+		terms.AgreementProperties = &marketplaceordering.AgreementProperties{}
+	}
+	terms.Accepted = to.BoolPtr(true)
+	_, err = ac.agreements.Create(ctx, spec.Publisher, spec.Offer, spec.Plan, terms)
+	return err
+}
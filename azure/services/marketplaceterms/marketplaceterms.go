@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package marketplaceterms
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/marketplaceordering/mgmt/2015-06-01/marketplaceordering"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// MarketplaceTermsScope defines the scope interface for a marketplace terms service.
+type MarketplaceTermsScope interface {
+	azure.Authorizer
+	MarketplaceTermsSpec() *azure.MarketplaceTermsSpec
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope MarketplaceTermsScope
+	client
+}
+
+// New creates a new marketplace terms service.
+func New(scope MarketplaceTermsScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile accepts the marketplace terms of the plan of the image of the machines for the subscription, unless they
+// were already accepted, so that VMs can be created from images with a purchase plan.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "marketplaceterms.Service.Reconcile")
+	defer done()
+
+	spec := s.Scope.MarketplaceTermsSpec()
+	if spec == nil {
+		return nil
+	}
+
+	terms, err := s.client.Get(ctx, *spec)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get marketplace terms of plan %s/%s/%s", spec.Publisher, spec.Offer, spec.Plan)
+	}
+	if accepted(terms) {
+		return nil
+	}
+
+	log.V(2).Info("accepting marketplace terms", "publisher", spec.Publisher, "offer", spec.Offer, "plan", spec.Plan)
+	if err := s.client.Accept(ctx, *spec, terms); err != nil {
+		return errors.Wrapf(err, "failed to accept marketplace terms of plan %s/%s/%s", spec.Publisher, spec.Offer, spec.Plan)
+	}
+	return nil
+}
+
+// Delete is a no-op as the marketplace terms are accepted for the whole subscription.
+func (s *Service) Delete(_ context.Context) error {
+	return nil
+}
+
+// accepted reports whether the marketplace terms are accepted.
+func accepted(terms marketplaceordering.AgreementTerms) bool {
+	return terms.AgreementProperties != nil && to.Bool(terms.Accepted)
+}
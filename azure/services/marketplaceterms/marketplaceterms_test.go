@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package marketplaceterms
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/marketplaceordering/mgmt/2015-06-01/marketplaceordering"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/marketplaceterms/mock_marketplaceterms"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeSpec = azure.MarketplaceTermsSpec{
+		Publisher: "my-publisher",
+		Offer:     "my-offer",
+		Plan:      "my-plan",
+	}
+	internalErr = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func fakeTerms(accepted bool) marketplaceordering.AgreementTerms {
+	return marketplaceordering.AgreementTerms{
+		AgreementProperties: &marketplaceordering.AgreementProperties{
+			Publisher: to.StringPtr("my-publisher"),
+			Product:   to.StringPtr("my-offer"),
+			Plan:      to.StringPtr("my-plan"),
+			Accepted:  to.BoolPtr(accepted),
+		},
+	}
+}
+
+func TestReconcileMarketplaceTerms(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_marketplaceterms.MockMarketplaceTermsScopeMockRecorder, m *mock_marketplaceterms.MockclientMockRecorder)
+	}{
+		{
+			name:          "image without purchase plan",
+			expectedError: "",
+			expect: func(s *mock_marketplaceterms.MockMarketplaceTermsScopeMockRecorder, m *mock_marketplaceterms.MockclientMockRecorder) {
+				s.MarketplaceTermsSpec().Return(nil)
+			},
+		},
+		{
+			name:          "terms already accepted",
+			expectedError: "",
+			expect: func(s *mock_marketplaceterms.MockMarketplaceTermsScopeMockRecorder, m *mock_marketplaceterms.MockclientMockRecorder) {
+				s.MarketplaceTermsSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), fakeSpec).Return(fakeTerms(true), nil)
+			},
+		},
+		{
+			name:          "accept terms",
+			expectedError: "",
+			expect: func(s *mock_marketplaceterms.MockMarketplaceTermsScopeMockRecorder, m *mock_marketplaceterms.MockclientMockRecorder) {
+				s.MarketplaceTermsSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), fakeSpec).Return(fakeTerms(false), nil)
+				m.Accept(gomockinternal.AContext(), fakeSpec, fakeTerms(false))
+			},
+		},
+		{
+			name:          "fail to get terms",
+			expectedError: "failed to get marketplace terms of plan my-publisher/my-offer/my-plan: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_marketplaceterms.MockMarketplaceTermsScopeMockRecorder, m *mock_marketplaceterms.MockclientMockRecorder) {
+				s.MarketplaceTermsSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), fakeSpec).Return(marketplaceordering.AgreementTerms{}, internalErr)
+			},
+		},
+		{
+			name:          "fail to accept terms",
+			expectedError: "failed to accept marketplace terms of plan my-publisher/my-offer/my-plan: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_marketplaceterms.MockMarketplaceTermsScopeMockRecorder, m *mock_marketplaceterms.MockclientMockRecorder) {
+				s.MarketplaceTermsSpec().Return(&fakeSpec)
+				m.Get(gomockinternal.AContext(), fakeSpec).Return(fakeTerms(false), nil)
+				m.Accept(gomockinternal.AContext(), fakeSpec, fakeTerms(false)).Return(internalErr)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_marketplaceterms.NewMockMarketplaceTermsScope(mockCtrl)
+			clientMock := mock_marketplaceterms.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
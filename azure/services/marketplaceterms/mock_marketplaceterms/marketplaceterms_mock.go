@@ -0,0 +1,178 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../marketplaceterms.go
+
+// Package mock_marketplaceterms is a generated GoMock package.
+package mock_marketplaceterms
+
+import (
+	reflect "reflect"
+
+	autorest "github.com/Azure/go-autorest/autorest"
+	gomock "github.com/golang/mock/gomock"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockMarketplaceTermsScope is a mock of MarketplaceTermsScope interface.
+type MockMarketplaceTermsScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockMarketplaceTermsScopeMockRecorder
+}
+
+// MockMarketplaceTermsScopeMockRecorder is the mock recorder for MockMarketplaceTermsScope.
+type MockMarketplaceTermsScopeMockRecorder struct {
+	mock *MockMarketplaceTermsScope
+}
+
+// NewMockMarketplaceTermsScope creates a new mock instance.
+func NewMockMarketplaceTermsScope(ctrl *gomock.Controller) *MockMarketplaceTermsScope {
+	mock := &MockMarketplaceTermsScope{ctrl: ctrl}
+	mock.recorder = &MockMarketplaceTermsScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMarketplaceTermsScope) EXPECT() *MockMarketplaceTermsScopeMockRecorder {
+	return m.recorder
+}
+
+// Authorizer mocks base method.
+func (m *MockMarketplaceTermsScope) Authorizer() autorest.Authorizer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorizer")
+	ret0, _ := ret[0].(autorest.Authorizer)
+	return ret0
+}
+
+// Authorizer indicates an expected call of Authorizer.
+func (mr *MockMarketplaceTermsScopeMockRecorder) Authorizer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockMarketplaceTermsScope)(nil).Authorizer))
+}
+
+// BaseURI mocks base method.
+func (m *MockMarketplaceTermsScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockMarketplaceTermsScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockMarketplaceTermsScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockMarketplaceTermsScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockMarketplaceTermsScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockMarketplaceTermsScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockMarketplaceTermsScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockMarketplaceTermsScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockMarketplaceTermsScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockMarketplaceTermsScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockMarketplaceTermsScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockMarketplaceTermsScope)(nil).CloudEnvironment))
+}
+
+// HashKey mocks base method.
+func (m *MockMarketplaceTermsScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockMarketplaceTermsScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockMarketplaceTermsScope)(nil).HashKey))
+}
+
+// MarketplaceTermsSpec mocks base method.
+func (m *MockMarketplaceTermsScope) MarketplaceTermsSpec() *azure.MarketplaceTermsSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarketplaceTermsSpec")
+	ret0, _ := ret[0].(*azure.MarketplaceTermsSpec)
+	return ret0
+}
+
+// MarketplaceTermsSpec indicates an expected call of MarketplaceTermsSpec.
+func (mr *MockMarketplaceTermsScopeMockRecorder) MarketplaceTermsSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarketplaceTermsSpec", reflect.TypeOf((*MockMarketplaceTermsScope)(nil).MarketplaceTermsSpec))
+}
+
+// SubscriptionID mocks base method.
+func (m *MockMarketplaceTermsScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockMarketplaceTermsScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockMarketplaceTermsScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockMarketplaceTermsScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockMarketplaceTermsScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockMarketplaceTermsScope)(nil).TenantID))
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -49,22 +50,24 @@ func newClient(auth azure.Authorizer) *azureClient {
 // netNatGatewaysClient creates a new nat gateways client from subscription ID.
 func netNatGatewaysClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.NatGatewaysClient {
 	natGatewaysClient := network.NewNatGatewaysClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&natGatewaysClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&natGatewaysClient.Client, authorizer, "natgateways")
 	return natGatewaysClient
 }
 
 // Get gets the specified nat gateway.
-func (ac *azureClient) Get(ctx context.Context, resourceGroupName, natGatewayName string) (network.NatGateway, error) {
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, natGatewayName string) (_ network.NatGateway, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "natgateways.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.natgateways.Get(ctx, resourceGroupName, natGatewayName, "")
 }
 
 // CreateOrUpdate create or updates a nat gateway in a specified resource group.
-func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, natGatewayName string, natGateway network.NatGateway) error {
+func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, natGatewayName string, natGateway network.NatGateway) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "natgateways.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.natgateways.CreateOrUpdate(ctx, resourceGroupName, natGatewayName, natGateway)
 	if err != nil {
@@ -79,9 +82,10 @@ func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName str
 }
 
 // Delete deletes the specified nat gateway.
-func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, natGatewayName string) error {
+func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, natGatewayName string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "natgateways.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.natgateways.Delete(ctx, resourceGroupName, natGatewayName)
 	if err != nil {
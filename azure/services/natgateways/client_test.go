@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natgateways
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/vcr"
+)
+
+// newRecordedClient creates a client sending its requests through a recorder of the cassette.
+func newRecordedClient(t *testing.T, cassette string) *azureClient {
+	r := vcr.New(t, cassette)
+	c := newClient(r)
+	r.Apply(&c.natgateways.Client)
+	return c
+}
+
+func TestClientGet(t *testing.T) {
+	g := NewWithT(t)
+	c := newRecordedClient(t, "get")
+
+	natGateway, err := c.Get(context.TODO(), "my-rg", "my-natgateway")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(natGateway.Name).To(Equal(to.StringPtr("my-natgateway")))
+	g.Expect(natGateway.PublicIPAddresses).To(Equal(&[]network.SubResource{
+		{ID: to.StringPtr("/subscriptions/" + c.natgateways.SubscriptionID + "/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-natgateway-ip")},
+	}))
+}
+
+func TestClientGetNotFound(t *testing.T) {
+	g := NewWithT(t)
+	c := newRecordedClient(t, "get-not-found")
+
+	_, err := c.Get(context.TODO(), "my-rg", "my-natgateway")
+	g.Expect(azureerrors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestClientCreateOrUpdate(t *testing.T) {
+	g := NewWithT(t)
+	c := newRecordedClient(t, "create-or-update")
+
+	err := c.CreateOrUpdate(context.TODO(), "my-rg", "my-natgateway", network.NatGateway{
+		Sku:      &network.NatGatewaySku{Name: network.Standard},
+		Location: to.StringPtr("westus"),
+		NatGatewayPropertiesFormat: &network.NatGatewayPropertiesFormat{
+			PublicIPAddresses: &[]network.SubResource{
+				{ID: to.StringPtr("/subscriptions/" + c.natgateways.SubscriptionID + "/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-natgateway-ip")},
+			},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestClientDelete(t *testing.T) {
+	g := NewWithT(t)
+	c := newRecordedClient(t, "delete")
+
+	g.Expect(c.Delete(context.TODO(), "my-rg", "my-natgateway")).To(Succeed())
+}
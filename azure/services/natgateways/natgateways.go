@@ -31,7 +31,9 @@ import (
 
 // NatGatewayScope defines the scope interface for nat gateway service.
 type NatGatewayScope interface {
-	azure.ClusterScoper
+	azure.ClusterDescriber
+	Vnet() *infrav1.VnetSpec
+	SetSubnet(infrav1.SubnetSpec)
 	NatGatewaySpecs() []azure.NatGatewaySpec
 }
 
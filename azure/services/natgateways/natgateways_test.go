@@ -30,6 +30,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways/mock_natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/golden"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 )
@@ -291,6 +292,43 @@ func TestReconcileNatGateways(t *testing.T) {
 	}
 }
 
+func TestNatGatewayPayload(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_natgateways.NewMockNatGatewayScope(mockCtrl)
+	clientMock := mock_natgateways.NewMockclient(mockCtrl)
+
+	var payload network.NatGateway
+	s, m := scopeMock.EXPECT(), clientMock.EXPECT()
+	s.Vnet().Return(&infrav1.VnetSpec{Name: "my-vnet"})
+	s.ClusterName()
+	s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
+		{
+			Name:         "my-node-natgateway",
+			Subnet:       infrav1.SubnetSpec{Name: "node-subnet", Role: infrav1.SubnetNode},
+			NatGatewayIP: infrav1.PublicIPSpec{Name: "pip-node-subnet"},
+		},
+	})
+	s.SubscriptionID().AnyTimes().Return("123")
+	s.ResourceGroup().AnyTimes().Return("my-rg")
+	s.Location().Return("westus")
+	m.Get(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Return(network.NatGateway{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+	m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-node-natgateway", gomock.AssignableToTypeOf(network.NatGateway{})).
+		DoAndReturn(func(_ context.Context, _, _ string, natGateway network.NatGateway) error {
+			payload = natGateway
+			return nil
+		})
+	s.SetSubnet(gomock.Any())
+
+	svc := &Service{
+		Scope:  scopeMock,
+		client: clientMock,
+	}
+	g.Expect(svc.Reconcile(context.TODO())).To(Succeed())
+	golden.Assert(t, "natgateway", payload)
+}
+
 func TestDeleteNatGateway(t *testing.T) {
 	testcases := []struct {
 		name          string
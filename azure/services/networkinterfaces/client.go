@@ -18,11 +18,14 @@ package networkinterfaces
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -31,6 +34,8 @@ type Client interface {
 	Get(context.Context, string, string) (network.Interface, error)
 	CreateOrUpdate(context.Context, string, string, network.Interface) error
 	Delete(context.Context, string, string) error
+	DeleteIfMatch(context.Context, string, string, string) error
+	List(context.Context, string) ([]network.Interface, error)
 }
 
 // AzureClient contains the Azure go-sdk Client.
@@ -49,25 +54,42 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newInterfacesClient creates a new network interfaces client from subscription ID.
 func newInterfacesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.InterfacesClient {
 	nicClient := network.NewInterfacesClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&nicClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&nicClient.Client, authorizer, "networkinterfaces")
 	return nicClient
 }
 
 // Get gets information about the specified network interface.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, nicName string) (network.Interface, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, nicName string) (_ network.Interface, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.interfaces.Get(ctx, resourceGroupName, nicName, "")
 }
 
-// CreateOrUpdate creates or updates a network interface.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, nicName string, nic network.Interface) error {
+// CreateOrUpdate creates or updates a network interface. If the network interface has an etag, the update fails with a
+// precondition failed error if the network interface was changed since it was read.
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, nicName string, nic network.Interface) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
-	future, err := ac.interfaces.CreateOrUpdate(ctx, resourceGroupName, nicName, nic)
+	var etag string
+	if nic.Etag != nil {
+		etag = *nic.Etag
+	}
+	req, err := ac.interfaces.CreateOrUpdatePreparer(ctx, resourceGroupName, nicName, nic)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "network.InterfacesClient", "CreateOrUpdate", nil, "Failure preparing request")
+		return err
+	}
+	if etag != "" {
+		req.Header.Add("If-Match", etag)
+	}
+
+	future, err := ac.interfaces.CreateOrUpdateSender(req)
 	if err != nil {
+		err = autorest.NewErrorWithError(err, "network.InterfacesClient", "CreateOrUpdate", future.Response(), "Failure sending request")
 		return err
 	}
 	err = future.WaitForCompletionRef(ctx, ac.interfaces.Client)
@@ -79,9 +101,10 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName str
 }
 
 // Delete deletes the specified network interface.
-func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, nicName string) error {
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, nicName string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.interfaces.Delete(ctx, resourceGroupName, nicName)
 	if err != nil {
@@ -94,3 +117,53 @@ func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, nicName st
 	_, err = future.Result(ac.interfaces)
 	return err
 }
+
+// DeleteIfMatch deletes the specified network interface, failing with a precondition failed error if the network
+// interface was changed since the given etag was read.
+func (ac *AzureClient) DeleteIfMatch(ctx context.Context, resourceGroupName, nicName, etag string) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.AzureClient.DeleteIfMatch")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	req, err := ac.interfaces.DeletePreparer(ctx, resourceGroupName, nicName)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "network.InterfacesClient", "Delete", nil, "Failure preparing request")
+		return err
+	}
+	if etag != "" {
+		req.Header.Add("If-Match", etag)
+	}
+
+	future, err := ac.interfaces.DeleteSender(req)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "network.InterfacesClient", "Delete", future.Response(), "Failure sending request")
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.interfaces.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.interfaces)
+	return err
+}
+
+// List returns all network interfaces in a resource group.
+func (ac *AzureClient) List(ctx context.Context, resourceGroupName string) (_ []network.Interface, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.AzureClient.List")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	itr, err := ac.interfaces.ListComplete(ctx, resourceGroupName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list network interfaces in the resource group")
+	}
+
+	var nics []network.Interface
+	for ; itr.NotDone(); err = itr.NextWithContext(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate network interfaces [%w]", err)
+		}
+		nics = append(nics, itr.Value())
+	}
+	return nics, nil
+}
@@ -234,6 +234,34 @@ func (mr *MockNICScopeMockRecorder) NICSpecs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NICSpecs", reflect.TypeOf((*MockNICScope)(nil).NICSpecs))
 }
 
+// ProxyConfig mocks base method.
+func (m *MockNICScope) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockNICScopeMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockNICScope)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockNICScope) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockNICScopeMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockNICScope)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockNICScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -248,6 +276,18 @@ func (mr *MockNICScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockNICScope)(nil).ResourceGroup))
 }
 
+// SetNICPoolClaim mocks base method.
+func (m *MockNICScope) SetNICPoolClaim(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetNICPoolClaim", arg0)
+}
+
+// SetNICPoolClaim indicates an expected call of SetNICPoolClaim.
+func (mr *MockNICScopeMockRecorder) SetNICPoolClaim(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNICPoolClaim", reflect.TypeOf((*MockNICScope)(nil).SetNICPoolClaim), arg0)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockNICScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -275,3 +315,29 @@ func (mr *MockNICScopeMockRecorder) TenantID() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockNICScope)(nil).TenantID))
 }
+
+// TrustedCAs mocks base method.
+func (m *MockNICScope) TrustedCAs() *v1beta1.TrustedCASource {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrustedCAs")
+	ret0, _ := ret[0].(*v1beta1.TrustedCASource)
+	return ret0
+}
+
+// TrustedCAs indicates an expected call of TrustedCAs.
+func (mr *MockNICScopeMockRecorder) TrustedCAs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustedCAs", reflect.TypeOf((*MockNICScope)(nil).TrustedCAs))
+}
+
+// UpdateNICPoolStatus mocks base method.
+func (m *MockNICScope) UpdateNICPoolStatus(arg0 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateNICPoolStatus", arg0)
+}
+
+// UpdateNICPoolStatus indicates an expected call of UpdateNICPoolStatus.
+func (mr *MockNICScopeMockRecorder) UpdateNICPoolStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNICPoolStatus", reflect.TypeOf((*MockNICScope)(nil).UpdateNICPoolStatus), arg0)
+}
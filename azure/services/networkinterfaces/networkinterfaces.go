@@ -32,6 +32,8 @@ import (
 type NICScope interface {
 	azure.ClusterDescriber
 	NICSpecs() []azure.NICSpec
+	SetNICPoolClaim(string)
+	UpdateNICPoolStatus(error)
 }
 
 // Service provides operations on Azure resources.
@@ -61,95 +63,36 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		case err != nil && !azure.ResourceNotFound(err):
 			return errors.Wrapf(err, "failed to fetch network interface %s", nicSpec.Name)
 		case err == nil:
-			// network interface already exists, do nothing
-			continue
-		default:
-			nicConfig := &network.InterfaceIPConfigurationPropertiesFormat{}
-
-			subnet := &network.Subnet{
-				ID: to.StringPtr(azure.SubnetID(s.Scope.SubscriptionID(), nicSpec.VNetResourceGroup, nicSpec.VNetName, nicSpec.SubnetName)),
-			}
-			nicConfig.Subnet = subnet
-
-			nicConfig.PrivateIPAllocationMethod = network.IPAllocationMethodDynamic
-			if nicSpec.StaticIPAddress != "" {
-				nicConfig.PrivateIPAllocationMethod = network.IPAllocationMethodStatic
-				nicConfig.PrivateIPAddress = to.StringPtr(nicSpec.StaticIPAddress)
-			}
-
-			backendAddressPools := []network.BackendAddressPool{}
-			if nicSpec.PublicLBName != "" {
-				if nicSpec.PublicLBAddressPoolName != "" {
-					backendAddressPools = append(backendAddressPools,
-						network.BackendAddressPool{
-							ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.PublicLBName, nicSpec.PublicLBAddressPoolName)),
-						})
-				}
-				if nicSpec.PublicLBNATRuleName != "" {
-					nicConfig.LoadBalancerInboundNatRules = &[]network.InboundNatRule{
-						{
-							ID: to.StringPtr(azure.NATRuleID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.PublicLBName, nicSpec.PublicLBNATRuleName)),
-						},
-					}
-				}
-			}
-			if nicSpec.InternalLBName != "" && nicSpec.InternalLBAddressPoolName != "" {
-				backendAddressPools = append(backendAddressPools,
-					network.BackendAddressPool{
-						ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.InternalLBName, nicSpec.InternalLBAddressPoolName)),
-					})
-			}
-			nicConfig.LoadBalancerBackendAddressPools = &backendAddressPools
-
-			if nicSpec.PublicIPName != "" {
-				nicConfig.PublicIPAddress = &network.PublicIPAddress{
-					ID: to.StringPtr(azure.PublicIPID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.PublicIPName)),
+			// network interface already exists, only keep the pool of spare network interfaces filled. A failed refill
+			// is reported in a condition rather than returned, so that it doesn't block the reconciliation of the machine.
+			if nicSpec.PoolName != "" {
+				err := s.refillPool(ctx, nicSpec)
+				if err != nil {
+					err = errors.Wrapf(err, "failed to refill network interface pool %s", nicSpec.PoolName)
+					log.Error(err, "failed to refill network interface pool", "pool", nicSpec.PoolName)
 				}
+				s.Scope.UpdateNICPoolStatus(err)
 			}
-
-			if nicSpec.AcceleratedNetworking == nil {
-				// set accelerated networking to the capability of the VMSize
-				sku, err := s.resourceSKUCache.Get(ctx, nicSpec.VMSize, resourceskus.VirtualMachines)
+			continue
+		default:
+			if nicSpec.PoolName != "" {
+				claimed, err := s.claimPoolNIC(ctx, nicSpec)
 				if err != nil {
-					return azure.WithTerminalError(errors.Wrapf(err, "failed to get SKU %s in compute api", nicSpec.VMSize))
+					return errors.Wrapf(err, "failed to claim network interface from pool %s", nicSpec.PoolName)
 				}
-
-				accelNet := sku.HasCapability(resourceskus.AcceleratedNetworking)
-				nicSpec.AcceleratedNetworking = &accelNet
-			}
-
-			ipConfigurations := []network.InterfaceIPConfiguration{
-				{
-					Name:                                     to.StringPtr("pipConfig"),
-					InterfaceIPConfigurationPropertiesFormat: nicConfig,
-				},
-			}
-
-			if nicSpec.IPv6Enabled {
-				ipv6Config := network.InterfaceIPConfiguration{
-					Name: to.StringPtr("ipConfigv6"),
-					InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-						PrivateIPAddressVersion: "IPv6",
-						Primary:                 to.BoolPtr(false),
-						Subnet:                  &network.Subnet{ID: subnet.ID},
-					},
+				if claimed != "" {
+					s.Scope.SetNICPoolClaim(claimed)
+					log.V(2).Info("successfully claimed network interface from pool", "network interface", claimed, "pool", nicSpec.PoolName)
+					continue
 				}
-
-				ipConfigurations = append(ipConfigurations, ipv6Config)
 			}
 
-			err = s.Client.CreateOrUpdate(ctx,
-				s.Scope.ResourceGroup(),
-				nicSpec.Name,
-				network.Interface{
-					Location: to.StringPtr(s.Scope.Location()),
-					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
-						EnableAcceleratedNetworking: nicSpec.AcceleratedNetworking,
-						IPConfigurations:            &ipConfigurations,
-						EnableIPForwarding:          to.BoolPtr(nicSpec.EnableIPForwarding),
-					},
-				})
+			nic, err := s.parameters(ctx, nicSpec)
+			if err != nil {
+				return err
+			}
 
+			err = s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), nicSpec.Name, nic)
 			if err != nil {
 				return errors.Wrapf(err, "failed to create network interface %s in resource group %s", nicSpec.Name, s.Scope.ResourceGroup())
 			}
@@ -174,3 +117,89 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 	return nil
 }
+
+// parameters returns the network interface to create for the spec.
+func (s *Service) parameters(ctx context.Context, nicSpec azure.NICSpec) (network.Interface, error) {
+	nicConfig := &network.InterfaceIPConfigurationPropertiesFormat{}
+
+	subnet := &network.Subnet{
+		ID: to.StringPtr(azure.SubnetID(s.Scope.SubscriptionID(), nicSpec.VNetResourceGroup, nicSpec.VNetName, nicSpec.SubnetName)),
+	}
+	nicConfig.Subnet = subnet
+
+	nicConfig.PrivateIPAllocationMethod = network.IPAllocationMethodDynamic
+	if nicSpec.StaticIPAddress != "" {
+		nicConfig.PrivateIPAllocationMethod = network.IPAllocationMethodStatic
+		nicConfig.PrivateIPAddress = to.StringPtr(nicSpec.StaticIPAddress)
+	}
+
+	backendAddressPools := []network.BackendAddressPool{}
+	if nicSpec.PublicLBName != "" {
+		if nicSpec.PublicLBAddressPoolName != "" {
+			backendAddressPools = append(backendAddressPools,
+				network.BackendAddressPool{
+					ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.PublicLBName, nicSpec.PublicLBAddressPoolName)),
+				})
+		}
+		if nicSpec.PublicLBNATRuleName != "" {
+			nicConfig.LoadBalancerInboundNatRules = &[]network.InboundNatRule{
+				{
+					ID: to.StringPtr(azure.NATRuleID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.PublicLBName, nicSpec.PublicLBNATRuleName)),
+				},
+			}
+		}
+	}
+	if nicSpec.InternalLBName != "" && nicSpec.InternalLBAddressPoolName != "" {
+		backendAddressPools = append(backendAddressPools,
+			network.BackendAddressPool{
+				ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.InternalLBName, nicSpec.InternalLBAddressPoolName)),
+			})
+	}
+	nicConfig.LoadBalancerBackendAddressPools = &backendAddressPools
+
+	if nicSpec.PublicIPName != "" {
+		nicConfig.PublicIPAddress = &network.PublicIPAddress{
+			ID: to.StringPtr(azure.PublicIPID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.PublicIPName)),
+		}
+	}
+
+	if nicSpec.AcceleratedNetworking == nil {
+		// set accelerated networking to the capability of the VMSize
+		sku, err := s.resourceSKUCache.Get(ctx, nicSpec.VMSize, resourceskus.VirtualMachines)
+		if err != nil {
+			return network.Interface{}, azure.WithTerminalError(errors.Wrapf(err, "failed to get SKU %s in compute api", nicSpec.VMSize))
+		}
+
+		accelNet := sku.HasCapability(resourceskus.AcceleratedNetworking)
+		nicSpec.AcceleratedNetworking = &accelNet
+	}
+
+	ipConfigurations := []network.InterfaceIPConfiguration{
+		{
+			Name:                                     to.StringPtr("pipConfig"),
+			InterfaceIPConfigurationPropertiesFormat: nicConfig,
+		},
+	}
+
+	if nicSpec.IPv6Enabled {
+		ipv6Config := network.InterfaceIPConfiguration{
+			Name: to.StringPtr("ipConfigv6"),
+			InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+				PrivateIPAddressVersion: "IPv6",
+				Primary:                 to.BoolPtr(false),
+				Subnet:                  &network.Subnet{ID: subnet.ID},
+			},
+		}
+
+		ipConfigurations = append(ipConfigurations, ipv6Config)
+	}
+
+	return network.Interface{
+		Location: to.StringPtr(s.Scope.Location()),
+		InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+			EnableAcceleratedNetworking: nicSpec.AcceleratedNetworking,
+			IPConfigurations:            &ipConfigurations,
+			EnableIPForwarding:          to.BoolPtr(nicSpec.EnableIPForwarding),
+		},
+	}, nil
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkinterfaces
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/generators"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// poolTagKey is the tag holding the name of the pool a spare network interface belongs to.
+	poolTagKey = infrav1.NameAzureProviderPrefix + "nic-pool"
+
+	// poolRefillPeriod is the minimum time between two refills of the same pool.
+	poolRefillPeriod = time.Minute
+)
+
+// nicPool serializes claims and refills of the spare network interfaces of a pool within the controller, and rate
+// limits its refills. Claims are atomic across controllers because they are conditional on the etag of the spare
+// network interface, see claimPoolNIC.
+type nicPool struct {
+	sync.Mutex
+	lastRefill time.Time
+}
+
+var (
+	poolsMu sync.Mutex
+	pools   = map[string]*nicPool{}
+)
+
+// getPool returns the nicPool for the given key, creating it if needed.
+func getPool(key string) *nicPool {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	if _, ok := pools[key]; !ok {
+		pools[key] = &nicPool{}
+	}
+	return pools[key]
+}
+
+// claimPoolNIC takes a spare network interface matching the spec out of the pool and returns its name.
+// It returns an empty name if no spare network interface is available.
+func (s *Service) claimPoolNIC(ctx context.Context, nicSpec azure.NICSpec) (string, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.Service.claimPoolNIC")
+	defer done()
+
+	if nicSpec.PoolSize == 0 {
+		return "", nil
+	}
+
+	pool := getPool(s.poolKey(nicSpec))
+	pool.Lock()
+	defer pool.Unlock()
+
+	desired, err := s.parameters(ctx, nicSpec)
+	if err != nil {
+		return "", err
+	}
+
+	spares, _, err := s.spareNICs(ctx, nicSpec.PoolName)
+	if err != nil {
+		return "", err
+	}
+
+	for _, nic := range spares {
+		if !matchesPoolNIC(nic, desired) {
+			continue
+		}
+		// Removing the pool tag takes the network interface out of the pool. The update is conditional on the etag
+		// of the listed network interface, so that a spare is never claimed by two machines, even if they are
+		// reconciled by different controllers.
+		delete(nic.Tags, poolTagKey)
+		err := s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), to.String(nic.Name), nic)
+		switch {
+		case azure.PreconditionFailed(err) || azure.ResourceNotFound(err):
+			log.V(2).Info("spare network interface was claimed or deleted concurrently", "network interface", to.String(nic.Name), "pool", nicSpec.PoolName)
+			continue
+		case err != nil:
+			return "", errors.Wrapf(err, "failed to update network interface %s", to.String(nic.Name))
+		}
+		return to.String(nic.Name), nil
+	}
+	return "", nil
+}
+
+// refillPool creates or deletes spare network interfaces until the pool holds exactly PoolSize network interfaces
+// matching the spec. Spare network interfaces that no longer match the spec are deleted.
+func (s *Service) refillPool(ctx context.Context, nicSpec azure.NICSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.Service.refillPool")
+	defer done()
+
+	pool := getPool(s.poolKey(nicSpec))
+	pool.Lock()
+	defer pool.Unlock()
+
+	if time.Since(pool.lastRefill) < poolRefillPeriod {
+		return nil
+	}
+
+	desired, err := s.parameters(ctx, nicSpec)
+	if err != nil {
+		return err
+	}
+
+	spares, taken, err := s.spareNICs(ctx, nicSpec.PoolName)
+	if err != nil {
+		return err
+	}
+
+	ready := 0
+	for _, nic := range spares {
+		if ready < nicSpec.PoolSize && matchesPoolNIC(nic, desired) {
+			ready++
+			continue
+		}
+		if err := deleteSpareNIC(ctx, s.Client, s.Scope.ResourceGroup(), nic); err != nil {
+			return err
+		}
+		log.V(2).Info("deleted spare network interface", "network interface", to.String(nic.Name), "pool", nicSpec.PoolName)
+	}
+
+	for ; ready < nicSpec.PoolSize; ready++ {
+		name := poolNICName(nicSpec.PoolName, taken)
+		taken[name] = true
+		desired.Tags = converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.Scope.ClusterName(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(name),
+			Additional:  infrav1.Tags{poolTagKey: nicSpec.PoolName},
+		}))
+		if err := s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), name, desired); err != nil {
+			return errors.Wrapf(err, "failed to create spare network interface %s", name)
+		}
+		log.V(2).Info("created spare network interface", "network interface", name, "pool", nicSpec.PoolName)
+	}
+
+	pool.lastRefill = time.Now()
+	return nil
+}
+
+// spareNICs returns the unattached network interfaces of the pool, and the names of all the network interfaces of
+// the resource group.
+func (s *Service) spareNICs(ctx context.Context, poolName string) ([]network.Interface, map[string]bool, error) {
+	nics, err := s.Client.List(ctx, s.Scope.ResourceGroup())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var spares []network.Interface
+	taken := make(map[string]bool, len(nics))
+	for _, nic := range nics {
+		taken[to.String(nic.Name)] = true
+		if pool, ok := spareNICPool(nic, s.Scope.ClusterName()); ok && pool == poolName {
+			spares = append(spares, nic)
+		}
+	}
+	return spares, taken, nil
+}
+
+// spareNICPool returns the pool of a spare network interface of the cluster, and false if the network interface is
+// not an unattached spare of the cluster.
+func spareNICPool(nic network.Interface, clusterName string) (string, bool) {
+	tags := converters.MapToTags(nic.Tags)
+	pool, ok := tags[poolTagKey]
+	if !ok || !tags.HasOwned(clusterName) {
+		return "", false
+	}
+	if nic.InterfacePropertiesFormat == nil || nic.VirtualMachine != nil {
+		return "", false
+	}
+	return pool, true
+}
+
+// deleteSpareNIC deletes a spare network interface unless it was claimed since it was listed.
+func deleteSpareNIC(ctx context.Context, client Client, resourceGroup string, nic network.Interface) error {
+	err := client.DeleteIfMatch(ctx, resourceGroup, to.String(nic.Name), to.String(nic.Etag))
+	if err != nil && !azure.ResourceNotFound(err) && !azure.PreconditionFailed(err) {
+		return errors.Wrapf(err, "failed to delete spare network interface %s", to.String(nic.Name))
+	}
+	return nil
+}
+
+// poolNICName returns the name of a new spare network interface of the pool which is not taken by another network
+// interface of the resource group. Names are reproducible in deterministic mode, see generators.SetDeterministic.
+func poolNICName(poolName string, taken map[string]bool) string {
+	for i := 0; ; i++ {
+		name := azure.GenerateNICName(fmt.Sprintf("%s-pool-%s", poolName, generators.RandomString(5, fmt.Sprintf("%s/%d", poolName, i))))
+		if !taken[name] {
+			return name
+		}
+	}
+}
+
+// poolKey identifies a pool across clusters sharing a resource group.
+func (s *Service) poolKey(nicSpec azure.NICSpec) string {
+	return strings.Join([]string{s.Scope.ResourceGroup(), s.Scope.ClusterName(), nicSpec.PoolName}, "/")
+}
+
+// matchesPoolNIC returns true if a spare network interface has the properties of the desired network interface built
+// from the spec: its location, accelerated networking and IP forwarding settings, and for each IP configuration its
+// subnet, IP version, private and public IP addresses, load balancer backend pools and inbound NAT rules.
+func matchesPoolNIC(nic, desired network.Interface) bool {
+	if nic.InterfacePropertiesFormat == nil || !strings.EqualFold(to.String(nic.Location), to.String(desired.Location)) {
+		return false
+	}
+	if to.Bool(nic.EnableAcceleratedNetworking) != to.Bool(desired.EnableAcceleratedNetworking) ||
+		to.Bool(nic.EnableIPForwarding) != to.Bool(desired.EnableIPForwarding) {
+		return false
+	}
+
+	var have, want []network.InterfaceIPConfiguration
+	if nic.IPConfigurations != nil {
+		have = *nic.IPConfigurations
+	}
+	if desired.IPConfigurations != nil {
+		want = *desired.IPConfigurations
+	}
+	if len(have) != len(want) {
+		return false
+	}
+	for i := range want {
+		if !matchesIPConfiguration(have[i], want[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesIPConfiguration returns true if an IP configuration of a spare network interface matches the desired one.
+// Unset IP versions and allocation methods take the Azure defaults, IPv4 and dynamic.
+func matchesIPConfiguration(have, want network.InterfaceIPConfiguration) bool {
+	if !strings.EqualFold(to.String(have.Name), to.String(want.Name)) {
+		return false
+	}
+	h, w := have.InterfaceIPConfigurationPropertiesFormat, want.InterfaceIPConfigurationPropertiesFormat
+	if h == nil || w == nil {
+		return h == w
+	}
+
+	version := func(v network.IPVersion) network.IPVersion {
+		if v == "" {
+			return network.IPVersionIPv4
+		}
+		return v
+	}
+	allocation := func(m network.IPAllocationMethod) network.IPAllocationMethod {
+		if m == "" {
+			return network.IPAllocationMethodDynamic
+		}
+		return m
+	}
+	if version(h.PrivateIPAddressVersion) != version(w.PrivateIPAddressVersion) ||
+		allocation(h.PrivateIPAllocationMethod) != allocation(w.PrivateIPAllocationMethod) {
+		return false
+	}
+	if allocation(w.PrivateIPAllocationMethod) == network.IPAllocationMethodStatic && to.String(h.PrivateIPAddress) != to.String(w.PrivateIPAddress) {
+		return false
+	}
+
+	var haveSubnet, wantSubnet, haveIP, wantIP string
+	if h.Subnet != nil {
+		haveSubnet = to.String(h.Subnet.ID)
+	}
+	if w.Subnet != nil {
+		wantSubnet = to.String(w.Subnet.ID)
+	}
+	if h.PublicIPAddress != nil {
+		haveIP = to.String(h.PublicIPAddress.ID)
+	}
+	if w.PublicIPAddress != nil {
+		wantIP = to.String(w.PublicIPAddress.ID)
+	}
+	if !strings.EqualFold(haveSubnet, wantSubnet) || !strings.EqualFold(haveIP, wantIP) {
+		return false
+	}
+
+	return sameIDs(backendPoolIDs(h.LoadBalancerBackendAddressPools), backendPoolIDs(w.LoadBalancerBackendAddressPools)) &&
+		sameIDs(natRuleIDs(h.LoadBalancerInboundNatRules), natRuleIDs(w.LoadBalancerInboundNatRules))
+}
+
+// backendPoolIDs returns the resource IDs of the backend address pools.
+func backendPoolIDs(pools *[]network.BackendAddressPool) []string {
+	var ids []string
+	if pools != nil {
+		for _, p := range *pools {
+			ids = append(ids, to.String(p.ID))
+		}
+	}
+	return ids
+}
+
+// natRuleIDs returns the resource IDs of the inbound NAT rules.
+func natRuleIDs(rules *[]network.InboundNatRule) []string {
+	var ids []string
+	if rules != nil {
+		for _, r := range *rules {
+			ids = append(ids, to.String(r.ID))
+		}
+	}
+	return ids
+}
+
+// sameIDs returns true if both lists hold the same resource IDs, ignoring order and case.
+func sameIDs(a, b []string) bool {
+	set := func(ids []string) map[string]bool {
+		m := map[string]bool{}
+		for _, id := range ids {
+			m[strings.ToLower(id)] = true
+		}
+		return m
+	}
+
+	have, want := set(a), set(b)
+	if len(have) != len(want) {
+		return false
+	}
+	for id := range want {
+		if !have[id] {
+			return false
+		}
+	}
+	return true
+}
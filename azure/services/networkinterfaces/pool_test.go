@@ -0,0 +1,441 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkinterfaces
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces/mock_networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const (
+	poolSubnetID      = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet"
+	poolBackendPoolID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/backendAddressPools/my-pool"
+)
+
+func poolNICSpec(poolName string, poolSize int) azure.NICSpec {
+	return azure.NICSpec{
+		Name:                    "azure-test1-nic",
+		MachineName:             "azure-test1",
+		SubnetName:              "my-subnet",
+		VNetName:                "my-vnet",
+		VNetResourceGroup:       "my-rg",
+		PublicLBName:            "my-public-lb",
+		PublicLBAddressPoolName: "my-pool",
+		VMSize:                  "Standard_D2v2",
+		PoolName:                poolName,
+		PoolSize:                poolSize,
+	}
+}
+
+func spareNIC(name, poolName, subnetID string, attached bool) network.Interface {
+	nic := network.Interface{
+		Name:     to.StringPtr(name),
+		Etag:     to.StringPtr("etag-" + name),
+		Location: to.StringPtr("fake-location"),
+		Tags: map[string]*string{
+			poolTagKey: to.StringPtr(poolName),
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+		},
+		InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+			EnableAcceleratedNetworking: to.BoolPtr(true),
+			EnableIPForwarding:          to.BoolPtr(false),
+			IPConfigurations: &[]network.InterfaceIPConfiguration{
+				{
+					Name: to.StringPtr("pipConfig"),
+					InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+						Primary:                         to.BoolPtr(true),
+						PrivateIPAddressVersion:         network.IPVersionIPv4,
+						PrivateIPAllocationMethod:       network.IPAllocationMethodDynamic,
+						PrivateIPAddress:                to.StringPtr("10.1.0.4"),
+						Subnet:                          &network.Subnet{ID: to.StringPtr(subnetID)},
+						LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{{ID: to.StringPtr(poolBackendPoolID)}},
+					},
+				},
+			},
+		},
+	}
+	if attached {
+		nic.VirtualMachine = &network.SubResource{ID: to.StringPtr("some-vm")}
+	}
+	return nic
+}
+
+func TestReconcileNetworkInterfacePool(t *testing.T) {
+	notFound := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_networkinterfaces.MockNICScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder)
+	}{
+		{
+			name:          "claims a spare network interface from the pool",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{poolNICSpec("md-claim", 2)})
+				m.Get(gomockinternal.AContext(), "my-rg", "azure-test1-nic").Return(network.Interface{}, notFound)
+				m.List(gomockinternal.AContext(), "my-rg").Return([]network.Interface{
+					spareNIC("md-claim-pool-attached-nic", "md-claim", poolSubnetID, true),
+					spareNIC("md-other-pool-abcde-nic", "md-other", poolSubnetID, false),
+					spareNIC("md-claim-pool-abcde-nic", "md-claim", poolSubnetID, false),
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "md-claim-pool-abcde-nic", gomock.AssignableToTypeOf(network.Interface{})).
+					Do(func(_ context.Context, _, _ string, nic network.Interface) {
+						if _, ok := nic.Tags[poolTagKey]; ok {
+							t.Errorf("claimed network interface should not keep the pool tag")
+						}
+						if to.String(nic.Etag) != "etag-md-claim-pool-abcde-nic" {
+							t.Errorf("claim should be conditional on the etag of the spare network interface")
+						}
+					})
+				s.SetNICPoolClaim("md-claim-pool-abcde-nic")
+			},
+		},
+		{
+			name:          "creates the network interface if the pool has no matching spare",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{poolNICSpec("md-empty", 2)})
+				m.Get(gomockinternal.AContext(), "my-rg", "azure-test1-nic").Return(network.Interface{}, notFound)
+				m.List(gomockinternal.AContext(), "my-rg").Return([]network.Interface{
+					spareNIC("md-empty-pool-stale-nic", "md-empty", "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/old-subnet", false),
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "azure-test1-nic", gomock.AssignableToTypeOf(network.Interface{}))
+			},
+		},
+		{
+			name:          "refills the pool and deletes stale spares",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{poolNICSpec("md-refill", 2)})
+				m.Get(gomockinternal.AContext(), "my-rg", "azure-test1-nic")
+				m.List(gomockinternal.AContext(), "my-rg").Return([]network.Interface{
+					spareNIC("md-refill-pool-ready-nic", "md-refill", poolSubnetID, false),
+					spareNIC("md-refill-pool-stale-nic", "md-refill", "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/old-subnet", false),
+				}, nil)
+				m.DeleteIfMatch(gomockinternal.AContext(), "my-rg", "md-refill-pool-stale-nic", "etag-md-refill-pool-stale-nic")
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", gomock.Any(), gomock.AssignableToTypeOf(network.Interface{})).
+					Do(func(_ context.Context, _, _ string, nic network.Interface) {
+						if to.String(nic.Tags[poolTagKey]) != "md-refill" {
+							t.Errorf("spare network interface should have the pool tag")
+						}
+					})
+				s.UpdateNICPoolStatus(nil)
+			},
+		},
+		{
+			name:          "shrinks the pool",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{poolNICSpec("md-shrink", 0)})
+				m.Get(gomockinternal.AContext(), "my-rg", "azure-test1-nic")
+				m.List(gomockinternal.AContext(), "my-rg").Return([]network.Interface{
+					spareNIC("md-shrink-pool-ready-nic", "md-shrink", poolSubnetID, false),
+				}, nil)
+				m.DeleteIfMatch(gomockinternal.AContext(), "my-rg", "md-shrink-pool-ready-nic", "etag-md-shrink-pool-ready-nic")
+				s.UpdateNICPoolStatus(nil)
+			},
+		},
+		{
+			name:          "claims the next spare if a spare was claimed concurrently",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{poolNICSpec("md-race", 2)})
+				m.Get(gomockinternal.AContext(), "my-rg", "azure-test1-nic").Return(network.Interface{}, notFound)
+				m.List(gomockinternal.AContext(), "my-rg").Return([]network.Interface{
+					spareNIC("md-race-pool-first-nic", "md-race", poolSubnetID, false),
+					spareNIC("md-race-pool-second-nic", "md-race", poolSubnetID, false),
+				}, nil)
+				gomock.InOrder(
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "md-race-pool-first-nic", gomock.AssignableToTypeOf(network.Interface{})).
+						Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 412}, "Precondition failed")),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "md-race-pool-second-nic", gomock.AssignableToTypeOf(network.Interface{})),
+				)
+				s.SetNICPoolClaim("md-race-pool-second-nic")
+			},
+		},
+		{
+			name:          "does not claim from a drained pool",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{poolNICSpec("md-drained", 0)})
+				m.Get(gomockinternal.AContext(), "my-rg", "azure-test1-nic").Return(network.Interface{}, notFound)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "azure-test1-nic", gomock.AssignableToTypeOf(network.Interface{}))
+			},
+		},
+		{
+			name:          "reports a failed refill without failing the reconcile",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{poolNICSpec("md-refill-error", 1)})
+				m.Get(gomockinternal.AContext(), "my-rg", "azure-test1-nic")
+				m.List(gomockinternal.AContext(), "my-rg").Return(nil, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", gomock.Any(), gomock.AssignableToTypeOf(network.Interface{})).
+					Return(autorest.NewError("", "", "quota exceeded"))
+				s.UpdateNICPoolStatus(gomock.Not(gomock.Nil()))
+			},
+		},
+		{
+			name:          "claim fails if spares cannot be listed",
+			expectedError: "failed to claim network interface from pool md-list-error: #: failed to list: StatusCode=0",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{poolNICSpec("md-list-error", 1)})
+				m.Get(gomockinternal.AContext(), "my-rg", "azure-test1-nic").Return(network.Interface{}, notFound)
+				m.List(gomockinternal.AContext(), "my-rg").Return(nil, autorest.NewError("", "", "failed to list"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_networkinterfaces.NewMockNICScope(mockCtrl)
+			clientMock := mock_networkinterfaces.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().SubscriptionID().AnyTimes().Return("123")
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			scopeMock.EXPECT().Location().AnyTimes().Return("fake-location")
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+				resourceSKUCache: resourceskus.NewStaticCache([]compute.ResourceSku{
+					{
+						Name: to.StringPtr("Standard_D2v2"),
+						Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
+						Locations: &[]string{
+							"fake-location",
+						},
+						Capabilities: &[]compute.ResourceSkuCapabilities{
+							{
+								Name:  to.StringPtr(resourceskus.AcceleratedNetworking),
+								Value: to.StringPtr(string(resourceskus.CapabilitySupported)),
+							},
+						},
+					},
+				}, ""),
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestMatchesPoolNIC(t *testing.T) {
+	desired := func(mutate func(*network.Interface)) network.Interface {
+		nic := spareNIC("desired", "md-0", poolSubnetID, false)
+		nic.Etag, nic.Tags = nil, nil
+		(*nic.IPConfigurations)[0].Primary = nil
+		(*nic.IPConfigurations)[0].PrivateIPAddressVersion = ""
+		(*nic.IPConfigurations)[0].PrivateIPAddress = nil
+		if mutate != nil {
+			mutate(&nic)
+		}
+		return nic
+	}
+	ipv6 := func(nic *network.Interface) {
+		configs := append(*nic.IPConfigurations, network.InterfaceIPConfiguration{
+			Name: to.StringPtr("ipConfigv6"),
+			InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+				PrivateIPAddressVersion: network.IPVersionIPv6,
+				Subnet:                  &network.Subnet{ID: to.StringPtr(poolSubnetID)},
+			},
+		})
+		nic.IPConfigurations = &configs
+	}
+
+	testcases := []struct {
+		name    string
+		desired network.Interface
+		want    bool
+	}{
+		{
+			name:    "matches the same network interface",
+			desired: desired(nil),
+			want:    true,
+		},
+		{
+			name: "matches resource IDs regardless of case",
+			desired: desired(func(nic *network.Interface) {
+				(*nic.IPConfigurations)[0].Subnet.ID = to.StringPtr(strings.ToUpper(poolSubnetID))
+			}),
+			want: true,
+		},
+		{
+			name:    "does not match a network interface without the IPv6 configuration",
+			desired: desired(ipv6),
+			want:    false,
+		},
+		{
+			name: "does not match a network interface without IP forwarding",
+			desired: desired(func(nic *network.Interface) {
+				nic.EnableIPForwarding = to.BoolPtr(true)
+			}),
+			want: false,
+		},
+		{
+			name: "does not match a network interface without the inbound NAT rule",
+			desired: desired(func(nic *network.Interface) {
+				(*nic.IPConfigurations)[0].LoadBalancerInboundNatRules = &[]network.InboundNatRule{{ID: to.StringPtr("my-nat-rule")}}
+			}),
+			want: false,
+		},
+		{
+			name: "does not match a network interface with other backend pools",
+			desired: desired(func(nic *network.Interface) {
+				(*nic.IPConfigurations)[0].LoadBalancerBackendAddressPools = &[]network.BackendAddressPool{{ID: to.StringPtr("my-internal-pool")}}
+			}),
+			want: false,
+		},
+		{
+			name: "does not match a network interface with another static IP address",
+			desired: desired(func(nic *network.Interface) {
+				(*nic.IPConfigurations)[0].PrivateIPAllocationMethod = network.IPAllocationMethodStatic
+				(*nic.IPConfigurations)[0].PrivateIPAddress = to.StringPtr("10.1.0.5")
+			}),
+			want: false,
+		},
+		{
+			name: "does not match a network interface in another location",
+			desired: desired(func(nic *network.Interface) {
+				nic.Location = to.StringPtr("other-location")
+			}),
+			want: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(matchesPoolNIC(spareNIC("spare", "md-0", poolSubnetID, false), tc.desired)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestReconcilePoolService(t *testing.T) {
+	testcases := []struct {
+		name          string
+		delete        bool
+		expectedError string
+		expect        func(s *mock_networkinterfaces.MockPoolScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder)
+	}{
+		{
+			name:          "deletes the spares of the pools no longer in use",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockPoolScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				s.NICPoolsEnabled().Return(true)
+				s.ActiveNICPools(gomockinternal.AContext()).Return(map[string]bool{"md-active": true}, nil)
+				m.List(gomockinternal.AContext(), "my-rg").Return([]network.Interface{
+					spareNIC("md-active-pool-abcde-nic", "md-active", poolSubnetID, false),
+					spareNIC("md-deleted-pool-attached-nic", "md-deleted", poolSubnetID, true),
+					spareNIC("md-deleted-pool-abcde-nic", "md-deleted", poolSubnetID, false),
+					{Name: to.StringPtr("machine-nic"), InterfacePropertiesFormat: &network.InterfacePropertiesFormat{}},
+				}, nil)
+				m.DeleteIfMatch(gomockinternal.AContext(), "my-rg", "md-deleted-pool-abcde-nic", "etag-md-deleted-pool-abcde-nic")
+			},
+		},
+		{
+			name:          "does nothing if network interface pools are disabled",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockPoolScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				s.NICPoolsEnabled().Return(false)
+			},
+		},
+		{
+			name:          "fails if the pools in use cannot be listed",
+			expectedError: "failed to list the network interface pools in use: #: failed to list MachineDeployments",
+			expect: func(s *mock_networkinterfaces.MockPoolScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				s.NICPoolsEnabled().Return(true)
+				s.ActiveNICPools(gomockinternal.AContext()).Return(nil, autorest.NewError("", "", "failed to list MachineDeployments"))
+			},
+		},
+		{
+			name:          "deletes the spares of all the pools when the cluster is deleted",
+			delete:        true,
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockPoolScopeMockRecorder, m *mock_networkinterfaces.MockClientMockRecorder) {
+				m.List(gomockinternal.AContext(), "my-rg").Return([]network.Interface{
+					spareNIC("md-0-pool-abcde-nic", "md-0", poolSubnetID, false),
+					spareNIC("md-1-pool-abcde-nic", "md-1", poolSubnetID, false),
+				}, nil)
+				m.DeleteIfMatch(gomockinternal.AContext(), "my-rg", "md-0-pool-abcde-nic", "etag-md-0-pool-abcde-nic")
+				m.DeleteIfMatch(gomockinternal.AContext(), "my-rg", "md-1-pool-abcde-nic", "etag-md-1-pool-abcde-nic").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 412}, "Precondition failed"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_networkinterfaces.NewMockPoolScope(mockCtrl)
+			clientMock := mock_networkinterfaces.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &PoolService{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			var err error
+			if tc.delete {
+				err = s.Delete(context.TODO())
+			} else {
+				err = s.Reconcile(context.TODO())
+			}
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkinterfaces
+
+import (
+	"context"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// PoolScope defines the scope interface for the cleanup of the network interface pools of a cluster.
+type PoolScope interface {
+	azure.ClusterDescriber
+	NICPoolsEnabled() bool
+	ActiveNICPools(context.Context) (map[string]bool, error)
+}
+
+// PoolService deletes the spare network interfaces of the pools of a cluster which are no longer used.
+type PoolService struct {
+	Scope PoolScope
+	Client
+}
+
+// NewPoolService creates a new pool service.
+func NewPoolService(scope PoolScope) *PoolService {
+	return &PoolService{
+		Scope:  scope,
+		Client: NewClient(scope),
+	}
+}
+
+// Reconcile deletes the spare network interfaces of the pools whose MachineDeployment was deleted or no longer sets
+// a pool size.
+func (s *PoolService) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.PoolService.Reconcile")
+	defer done()
+
+	if !s.Scope.NICPoolsEnabled() {
+		return nil
+	}
+
+	active, err := s.Scope.ActiveNICPools(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the network interface pools in use")
+	}
+	return s.deleteSpares(ctx, func(pool string) bool { return !active[pool] })
+}
+
+// Delete deletes the spare network interfaces of all the pools of the cluster.
+func (s *PoolService) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.PoolService.Delete")
+	defer done()
+
+	return s.deleteSpares(ctx, func(string) bool { return true })
+}
+
+// deleteSpares deletes the spare network interfaces of the cluster whose pool is selected.
+func (s *PoolService) deleteSpares(ctx context.Context, selected func(pool string) bool) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.PoolService.deleteSpares")
+	defer done()
+
+	nics, err := s.Client.List(ctx, s.Scope.ResourceGroup())
+	if err != nil {
+		if azure.ResourceGroupNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to list spare network interfaces")
+	}
+
+	for _, nic := range nics {
+		pool, ok := spareNICPool(nic, s.Scope.ClusterName())
+		if !ok || !selected(pool) {
+			continue
+		}
+		if err := deleteSpareNIC(ctx, s.Client, s.Scope.ResourceGroup(), nic); err != nil {
+			return err
+		}
+		log.V(2).Info("deleted spare network interface of unused pool", "network interface", to.String(nic.Name), "pool", pool)
+	}
+	return nil
+}
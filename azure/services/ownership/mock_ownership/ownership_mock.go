@@ -0,0 +1,204 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../ownership.go
+
+// Package mock_ownership is a generated GoMock package.
+package mock_ownership
+
+import (
+	reflect "reflect"
+
+	autorest "github.com/Azure/go-autorest/autorest"
+	gomock "github.com/golang/mock/gomock"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockOwnershipScope is a mock of OwnershipScope interface.
+type MockOwnershipScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockOwnershipScopeMockRecorder
+}
+
+// MockOwnershipScopeMockRecorder is the mock recorder for MockOwnershipScope.
+type MockOwnershipScopeMockRecorder struct {
+	mock *MockOwnershipScope
+}
+
+// NewMockOwnershipScope creates a new mock instance.
+func NewMockOwnershipScope(ctrl *gomock.Controller) *MockOwnershipScope {
+	mock := &MockOwnershipScope{ctrl: ctrl}
+	mock.recorder = &MockOwnershipScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOwnershipScope) EXPECT() *MockOwnershipScopeMockRecorder {
+	return m.recorder
+}
+
+// Authorizer mocks base method.
+func (m *MockOwnershipScope) Authorizer() autorest.Authorizer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorizer")
+	ret0, _ := ret[0].(autorest.Authorizer)
+	return ret0
+}
+
+// Authorizer indicates an expected call of Authorizer.
+func (mr *MockOwnershipScopeMockRecorder) Authorizer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockOwnershipScope)(nil).Authorizer))
+}
+
+// BaseURI mocks base method.
+func (m *MockOwnershipScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockOwnershipScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockOwnershipScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockOwnershipScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockOwnershipScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockOwnershipScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockOwnershipScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockOwnershipScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockOwnershipScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockOwnershipScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockOwnershipScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockOwnershipScope)(nil).CloudEnvironment))
+}
+
+// ClusterName mocks base method.
+func (m *MockOwnershipScope) ClusterName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClusterName indicates an expected call of ClusterName.
+func (mr *MockOwnershipScopeMockRecorder) ClusterName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockOwnershipScope)(nil).ClusterName))
+}
+
+// HashKey mocks base method.
+func (m *MockOwnershipScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockOwnershipScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockOwnershipScope)(nil).HashKey))
+}
+
+// OwnershipSpecs mocks base method.
+func (m *MockOwnershipScope) OwnershipSpecs() []azure.OwnershipSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OwnershipSpecs")
+	ret0, _ := ret[0].([]azure.OwnershipSpec)
+	return ret0
+}
+
+// OwnershipSpecs indicates an expected call of OwnershipSpecs.
+func (mr *MockOwnershipScopeMockRecorder) OwnershipSpecs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OwnershipSpecs", reflect.TypeOf((*MockOwnershipScope)(nil).OwnershipSpecs))
+}
+
+// SetResourceOwnershipCondition mocks base method.
+func (m *MockOwnershipScope) SetResourceOwnershipCondition(arg0 []string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetResourceOwnershipCondition", arg0)
+}
+
+// SetResourceOwnershipCondition indicates an expected call of SetResourceOwnershipCondition.
+func (mr *MockOwnershipScopeMockRecorder) SetResourceOwnershipCondition(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResourceOwnershipCondition", reflect.TypeOf((*MockOwnershipScope)(nil).SetResourceOwnershipCondition), arg0)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockOwnershipScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockOwnershipScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockOwnershipScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockOwnershipScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockOwnershipScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockOwnershipScope)(nil).TenantID))
+}
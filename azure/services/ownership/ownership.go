@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ownership
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// OwnershipScope defines the scope interface for an ownership service.
+type OwnershipScope interface {
+	azure.Authorizer
+	ClusterName() string
+	OwnershipSpecs() []azure.OwnershipSpec
+	SetResourceOwnershipCondition([]string)
+}
+
+// Service verifies that the resources of a cluster are still tagged as owned by it, as resources which lost their
+// ownership tag, e.g. after a partial restore, are left behind when the cluster is deleted.
+type Service struct {
+	Scope OwnershipScope
+	client
+}
+
+// New creates a new service.
+func New(scope OwnershipScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile restores the ownership tag of the resources recorded as owned by the cluster, and reports the resources
+// which look like they were created for the cluster but can't be adopted again safely.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "ownership.Service.Reconcile")
+	defer done()
+
+	var unowned []string
+	for _, ownershipSpec := range s.Scope.OwnershipSpecs() {
+		existingTags, err := s.client.GetAtScope(ctx, ownershipSpec.Scope)
+		if azure.ResourceNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get tags of %s", ownershipSpec.Name)
+		}
+		tags := infrav1.Tags{}
+		if existingTags.Properties != nil {
+			tags = converters.MapToTags(existingTags.Properties.Tags)
+		}
+
+		switch {
+		case tags.HasOwned(s.Scope.ClusterName()), hasClusterTag(tags):
+			// the resource is either owned by the cluster, or was tagged deliberately for another lifecycle or cluster.
+			continue
+
+		case ownershipSpec.Owned:
+			log.V(2).Info("restoring missing ownership tag", "resource", ownershipSpec.Name)
+			ownedTag := map[string]*string{
+				infrav1.ClusterTagKey(s.Scope.ClusterName()): to.StringPtr(string(infrav1.ResourceLifecycleOwned)),
+			}
+			if _, err := s.client.UpdateAtScope(ctx, ownershipSpec.Scope, resources.TagsPatchResource{Operation: "Merge", Properties: &resources.Tags{Tags: ownedTag}}); err != nil {
+				return errors.Wrapf(err, "failed to restore the ownership tag of %s", ownershipSpec.Name)
+			}
+
+		case hasCommonTags(tags, ownershipSpec.Name):
+			log.V(2).Info("resource looks like it was created for the cluster but isn't tagged as owned by it", "resource", ownershipSpec.Name)
+			unowned = append(unowned, ownershipSpec.Name)
+		}
+	}
+
+	s.Scope.SetResourceOwnershipCondition(unowned)
+	return nil
+}
+
+// Delete restores the ownership tag of the resources recorded as owned by the cluster, so that they are deleted with
+// the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "ownership.Service.Delete")
+	defer done()
+
+	return s.Reconcile(ctx)
+}
+
+// hasClusterTag returns true if the tags contain a cluster tag of capz, whatever the cluster and the lifecycle.
+func hasClusterTag(tags infrav1.Tags) bool {
+	for key := range tags {
+		if strings.HasPrefix(key, infrav1.NameAzureProviderOwned) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCommonTags returns true if the tags contain the name and role tags capz sets on the common resources it creates.
+func hasCommonTags(tags infrav1.Tags, name string) bool {
+	return tags.GetRole() == infrav1.CommonRole && tags["Name"] == name
+}
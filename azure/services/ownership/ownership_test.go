@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ownership
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/ownership/mock_ownership"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	groupSpec = azure.OwnershipSpec{
+		Name:  "my-rg",
+		Scope: "/subscriptions/123/resourceGroups/my-rg",
+	}
+	vnetSpec = azure.OwnershipSpec{
+		Name:  "my-vnet",
+		Scope: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+		Owned: true,
+	}
+)
+
+func TestReconcileOwnership(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_ownership.MockOwnershipScopeMockRecorder, m *mock_ownership.MockclientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "resources owned by the cluster",
+			expectedError: "",
+			expect: func(s *mock_ownership.MockOwnershipScopeMockRecorder, m *mock_ownership.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.OwnershipSpecs().Return([]azure.OwnershipSpec{groupSpec, vnetSpec})
+				m.GetAtScope(gomockinternal.AContext(), groupSpec.Scope).Return(resources.TagsResource{Properties: &resources.Tags{
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+					},
+				}}, nil)
+				m.GetAtScope(gomockinternal.AContext(), vnetSpec.Scope).Return(resources.TagsResource{Properties: &resources.Tags{
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+					},
+				}}, nil)
+				s.SetResourceOwnershipCondition(nil)
+			},
+		},
+		{
+			name:          "restore the ownership tag of a resource recorded as owned",
+			expectedError: "",
+			expect: func(s *mock_ownership.MockOwnershipScopeMockRecorder, m *mock_ownership.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.OwnershipSpecs().Return([]azure.OwnershipSpec{vnetSpec})
+				m.GetAtScope(gomockinternal.AContext(), vnetSpec.Scope).Return(resources.TagsResource{Properties: &resources.Tags{
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-vnet"),
+					},
+				}}, nil)
+				m.UpdateAtScope(gomockinternal.AContext(), vnetSpec.Scope, resources.TagsPatchResource{
+					Operation: "Merge",
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						},
+					},
+				})
+				s.SetResourceOwnershipCondition(nil)
+			},
+		},
+		{
+			name:          "report a resource created for the cluster which lost its ownership tag",
+			expectedError: "",
+			expect: func(s *mock_ownership.MockOwnershipScopeMockRecorder, m *mock_ownership.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.OwnershipSpecs().Return([]azure.OwnershipSpec{groupSpec})
+				m.GetAtScope(gomockinternal.AContext(), groupSpec.Scope).Return(resources.TagsResource{Properties: &resources.Tags{
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-rg"),
+						"sigs.k8s.io_cluster-api-provider-azure_role": to.StringPtr("common"),
+					},
+				}}, nil)
+				s.SetResourceOwnershipCondition([]string{"my-rg"})
+			},
+		},
+		{
+			name:          "ignore resources brought by the user",
+			expectedError: "",
+			expect: func(s *mock_ownership.MockOwnershipScopeMockRecorder, m *mock_ownership.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.OwnershipSpecs().Return([]azure.OwnershipSpec{groupSpec})
+				m.GetAtScope(gomockinternal.AContext(), groupSpec.Scope).Return(resources.TagsResource{Properties: &resources.Tags{
+					Tags: map[string]*string{
+						"team": to.StringPtr("platform"),
+					},
+				}}, nil)
+				s.SetResourceOwnershipCondition(nil)
+			},
+		},
+		{
+			name:          "ignore resources tagged for another cluster",
+			expectedError: "",
+			expect: func(s *mock_ownership.MockOwnershipScopeMockRecorder, m *mock_ownership.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.OwnershipSpecs().Return([]azure.OwnershipSpec{vnetSpec})
+				m.GetAtScope(gomockinternal.AContext(), vnetSpec.Scope).Return(resources.TagsResource{Properties: &resources.Tags{
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-vnet"),
+						"sigs.k8s.io_cluster-api-provider-azure_role":                  to.StringPtr("common"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_other-cluster": to.StringPtr("owned"),
+					},
+				}}, nil)
+				s.SetResourceOwnershipCondition(nil)
+			},
+		},
+		{
+			name:          "skip resources which don't exist",
+			expectedError: "",
+			expect: func(s *mock_ownership.MockOwnershipScopeMockRecorder, m *mock_ownership.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.OwnershipSpecs().Return([]azure.OwnershipSpec{vnetSpec})
+				m.GetAtScope(gomockinternal.AContext(), vnetSpec.Scope).Return(resources.TagsResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				s.SetResourceOwnershipCondition(nil)
+			},
+		},
+		{
+			name:          "error getting existing tags",
+			expectedError: "failed to get tags of my-vnet: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_ownership.MockOwnershipScopeMockRecorder, m *mock_ownership.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.OwnershipSpecs().Return([]azure.OwnershipSpec{vnetSpec})
+				m.GetAtScope(gomockinternal.AContext(), vnetSpec.Scope).Return(resources.TagsResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "error restoring the ownership tag",
+			expectedError: "failed to restore the ownership tag of my-vnet: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_ownership.MockOwnershipScopeMockRecorder, m *mock_ownership.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.OwnershipSpecs().Return([]azure.OwnershipSpec{vnetSpec})
+				m.GetAtScope(gomockinternal.AContext(), vnetSpec.Scope).Return(resources.TagsResource{}, nil)
+				m.UpdateAtScope(gomockinternal.AContext(), vnetSpec.Scope, gomock.Any()).Return(resources.TagsResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_ownership.NewMockOwnershipScope(mockCtrl)
+			clientMock := mock_ownership.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
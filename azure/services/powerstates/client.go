@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package powerstates
+
+import (
+	"context"
+
+	// hibernation requires a newer API version than the one the VMs are created with.
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-07-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, string, string) (compute.VirtualMachine, error)
+	Start(context.Context, string, string) error
+	Deallocate(context.Context, string, string, bool) error
+	EnableHibernation(context.Context, string, string) error
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	virtualmachines compute.VirtualMachinesClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new VM power state client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	return &azureClient{
+		virtualmachines: newVirtualMachinesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newVirtualMachinesClient creates a new VM client from subscription ID.
+func newVirtualMachinesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachinesClient {
+	vmClient := compute.NewVirtualMachinesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&vmClient.Client, authorizer, "powerstates")
+	return vmClient
+}
+
+// Get gets a virtual machine along with its instance view, which reports its power state.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmName string) (_ compute.VirtualMachine, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "powerstates.AzureClient.Get")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.virtualmachines.Get(ctx, resourceGroupName, vmName, compute.InstanceViewTypesInstanceView)
+}
+
+// Start starts a virtual machine. It doesn't wait for the VM, whose progress is reported by its power state.
+func (ac *azureClient) Start(ctx context.Context, resourceGroupName, vmName string) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "powerstates.AzureClient.Start")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.virtualmachines.Start(ctx, resourceGroupName, vmName)
+	return err
+}
+
+// Deallocate stops a virtual machine and releases its compute resources, saving its memory to the OS disk when
+// hibernating. It doesn't wait for the VM, whose progress is reported by its power state.
+func (ac *azureClient) Deallocate(ctx context.Context, resourceGroupName, vmName string, hibernate bool) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "powerstates.AzureClient.Deallocate")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.virtualmachines.Deallocate(ctx, resourceGroupName, vmName, to.BoolPtr(hibernate))
+	return err
+}
+
+// EnableHibernation enables the hibernation of a deallocated virtual machine. It doesn't wait for the update, whose
+// progress is reported by the provisioning state of the VM.
+func (ac *azureClient) EnableHibernation(ctx context.Context, resourceGroupName, vmName string) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "powerstates.AzureClient.EnableHibernation")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.virtualmachines.Update(ctx, resourceGroupName, vmName, compute.VirtualMachineUpdate{
+		VirtualMachineProperties: &compute.VirtualMachineProperties{
+			AdditionalCapabilities: &compute.AdditionalCapabilities{
+				HibernationEnabled: to.BoolPtr(true),
+			},
+		},
+	})
+	return err
+}
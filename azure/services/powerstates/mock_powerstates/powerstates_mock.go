@@ -0,0 +1,203 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../powerstates.go
+
+// Package mock_powerstates is a generated GoMock package.
+package mock_powerstates
+
+import (
+	reflect "reflect"
+
+	autorest "github.com/Azure/go-autorest/autorest"
+	gomock "github.com/golang/mock/gomock"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockVMPowerStateScope is a mock of VMPowerStateScope interface.
+type MockVMPowerStateScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockVMPowerStateScopeMockRecorder
+}
+
+// MockVMPowerStateScopeMockRecorder is the mock recorder for MockVMPowerStateScope.
+type MockVMPowerStateScopeMockRecorder struct {
+	mock *MockVMPowerStateScope
+}
+
+// NewMockVMPowerStateScope creates a new mock instance.
+func NewMockVMPowerStateScope(ctrl *gomock.Controller) *MockVMPowerStateScope {
+	mock := &MockVMPowerStateScope{ctrl: ctrl}
+	mock.recorder = &MockVMPowerStateScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVMPowerStateScope) EXPECT() *MockVMPowerStateScopeMockRecorder {
+	return m.recorder
+}
+
+// Authorizer mocks base method.
+func (m *MockVMPowerStateScope) Authorizer() autorest.Authorizer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorizer")
+	ret0, _ := ret[0].(autorest.Authorizer)
+	return ret0
+}
+
+// Authorizer indicates an expected call of Authorizer.
+func (mr *MockVMPowerStateScopeMockRecorder) Authorizer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockVMPowerStateScope)(nil).Authorizer))
+}
+
+// BaseURI mocks base method.
+func (m *MockVMPowerStateScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockVMPowerStateScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockVMPowerStateScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockVMPowerStateScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockVMPowerStateScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockVMPowerStateScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockVMPowerStateScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockVMPowerStateScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockVMPowerStateScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockVMPowerStateScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockVMPowerStateScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockVMPowerStateScope)(nil).CloudEnvironment))
+}
+
+// HashKey mocks base method.
+func (m *MockVMPowerStateScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockVMPowerStateScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockVMPowerStateScope)(nil).HashKey))
+}
+
+// SetVMPowerState mocks base method.
+func (m *MockVMPowerStateScope) SetVMPowerState(arg0 v1beta1.VMPowerState) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetVMPowerState", arg0)
+}
+
+// SetVMPowerState indicates an expected call of SetVMPowerState.
+func (mr *MockVMPowerStateScopeMockRecorder) SetVMPowerState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVMPowerState", reflect.TypeOf((*MockVMPowerStateScope)(nil).SetVMPowerState), arg0)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockVMPowerStateScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockVMPowerStateScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockVMPowerStateScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockVMPowerStateScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockVMPowerStateScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockVMPowerStateScope)(nil).TenantID))
+}
+
+// UpdateVMPowerStateStatus mocks base method.
+func (m *MockVMPowerStateScope) UpdateVMPowerStateStatus(reason string, err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateVMPowerStateStatus", reason, err)
+}
+
+// UpdateVMPowerStateStatus indicates an expected call of UpdateVMPowerStateStatus.
+func (mr *MockVMPowerStateScopeMockRecorder) UpdateVMPowerStateStatus(reason, err interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVMPowerStateStatus", reflect.TypeOf((*MockVMPowerStateScope)(nil).UpdateVMPowerStateStatus), reason, err)
+}
+
+// VMPowerStateSpec mocks base method.
+func (m *MockVMPowerStateScope) VMPowerStateSpec() *azure.VMPowerStateSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VMPowerStateSpec")
+	ret0, _ := ret[0].(*azure.VMPowerStateSpec)
+	return ret0
+}
+
+// VMPowerStateSpec indicates an expected call of VMPowerStateSpec.
+func (mr *MockVMPowerStateScopeMockRecorder) VMPowerStateSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMPowerStateSpec", reflect.TypeOf((*MockVMPowerStateScope)(nil).VMPowerStateSpec))
+}
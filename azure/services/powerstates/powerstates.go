@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package powerstates
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-07-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// changingRequeue is how long to wait between checks of a VM whose power state is changing.
+const changingRequeue = 30 * time.Second
+
+// VMPowerStateScope defines the scope interface for a VM power states service.
+type VMPowerStateScope interface {
+	azure.Authorizer
+	VMPowerStateSpec() *azure.VMPowerStateSpec
+	SetVMPowerState(infrav1.VMPowerState)
+	UpdateVMPowerStateStatus(reason string, err error)
+}
+
+// Service starts, deallocates and hibernates the VM of a machine according to its desired power state.
+type Service struct {
+	Scope VMPowerStateScope
+	client
+}
+
+// New creates a new VM power states service.
+func New(scope VMPowerStateScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile brings the VM of the machine one step closer to its desired power state, and reports it in a condition. A
+// VM whose power state is changing returns a transient error until it reaches the desired power state.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "powerstates.Service.Reconcile")
+	defer done()
+
+	spec := s.Scope.VMPowerStateSpec()
+	if spec == nil {
+		s.Scope.SetVMPowerState("")
+		return nil
+	}
+
+	vm, err := s.client.Get(ctx, spec.ResourceGroup, spec.Name)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get power state of virtual machine %s", spec.Name)
+		s.Scope.UpdateVMPowerStateStatus(infrav1.VMPowerStateFailedReason, err)
+		return err
+	}
+
+	current, changing := powerState(vm)
+	s.Scope.SetVMPowerState(current)
+	changingErr := azure.WithTransientError(errors.Errorf("changing power state of virtual machine %s to %s", spec.Name, spec.PowerState), changingRequeue)
+	if current == spec.PowerState && !changing {
+		s.Scope.UpdateVMPowerStateStatus("", nil)
+		return nil
+	}
+	if changing {
+		s.Scope.UpdateVMPowerStateStatus(infrav1.VMPowerStateChangingReason, changingErr)
+		return changingErr
+	}
+
+	switch {
+	case current == infrav1.VMPowerStateDeallocated && spec.PowerState == infrav1.VMPowerStateHibernated && !hibernationEnabled(vm):
+		log.V(2).Info("enabling hibernation of virtual machine", "vm", spec.Name)
+		err = errors.Wrapf(s.client.EnableHibernation(ctx, spec.ResourceGroup, spec.Name), "failed to enable hibernation of virtual machine %s", spec.Name)
+	case spec.PowerState == infrav1.VMPowerStateRunning || current == infrav1.VMPowerStateDeallocated || current == infrav1.VMPowerStateHibernated:
+		// a deallocated VM is started before it is hibernated, and a hibernated one before it is deallocated.
+		log.V(2).Info("starting virtual machine", "vm", spec.Name)
+		err = errors.Wrapf(s.client.Start(ctx, spec.ResourceGroup, spec.Name), "failed to start virtual machine %s", spec.Name)
+	default:
+		// hibernation can only be enabled on a deallocated VM, so a VM without it is deallocated first.
+		hibernate := spec.PowerState == infrav1.VMPowerStateHibernated && hibernationEnabled(vm)
+		log.V(2).Info("deallocating virtual machine", "vm", spec.Name, "hibernate", hibernate)
+		err = errors.Wrapf(s.client.Deallocate(ctx, spec.ResourceGroup, spec.Name, hibernate), "failed to deallocate virtual machine %s", spec.Name)
+	}
+	if err != nil {
+		s.Scope.UpdateVMPowerStateStatus(infrav1.VMPowerStateFailedReason, err)
+		return err
+	}
+	s.Scope.UpdateVMPowerStateStatus(infrav1.VMPowerStateChangingReason, changingErr)
+	return changingErr
+}
+
+// Delete is a no-op, the VM is deleted by the virtual machines service.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// powerState returns the power state of a VM from its instance view, and whether it is changing. A VM which is neither
+// running nor deallocated, e.g. stopped from within its OS, has an empty power state.
+func powerState(vm compute.VirtualMachine) (infrav1.VMPowerState, bool) {
+	if vm.VirtualMachineProperties == nil || vm.InstanceView == nil || vm.InstanceView.Statuses == nil {
+		return "", false
+	}
+	var state infrav1.VMPowerState
+	changing, hibernated := false, false
+	for _, status := range *vm.InstanceView.Statuses {
+		switch to.String(status.Code) {
+		case "PowerState/running":
+			state = infrav1.VMPowerStateRunning
+		case "PowerState/deallocated":
+			state = infrav1.VMPowerStateDeallocated
+		case "HibernationState/Hibernated":
+			hibernated = true
+		case "PowerState/starting", "PowerState/stopping", "PowerState/deallocating", "ProvisioningState/updating":
+			changing = true
+		}
+	}
+	if hibernated && state == infrav1.VMPowerStateDeallocated {
+		state = infrav1.VMPowerStateHibernated
+	}
+	return state, changing
+}
+
+// hibernationEnabled returns whether a VM can be hibernated.
+func hibernationEnabled(vm compute.VirtualMachine) bool {
+	return vm.VirtualMachineProperties != nil && vm.AdditionalCapabilities != nil && to.Bool(vm.AdditionalCapabilities.HibernationEnabled)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package powerstates
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-07-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/powerstates/mock_powerstates"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var internalErr = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+
+func fakeSpec(state infrav1.VMPowerState) *azure.VMPowerStateSpec {
+	return &azure.VMPowerStateSpec{
+		Name:          "my-vm",
+		ResourceGroup: "my-rg",
+		PowerState:    state,
+	}
+}
+
+func fakeVM(hibernationEnabled bool, codes ...string) compute.VirtualMachine {
+	statuses := []compute.InstanceViewStatus{{Code: to.StringPtr("ProvisioningState/succeeded")}}
+	for _, code := range codes {
+		statuses = append(statuses, compute.InstanceViewStatus{Code: to.StringPtr(code)})
+	}
+	return compute.VirtualMachine{
+		VirtualMachineProperties: &compute.VirtualMachineProperties{
+			AdditionalCapabilities: &compute.AdditionalCapabilities{
+				HibernationEnabled: to.BoolPtr(hibernationEnabled),
+			},
+			InstanceView: &compute.VirtualMachineInstanceView{
+				Statuses: &statuses,
+			},
+		},
+	}
+}
+
+func TestReconcilePowerStates(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder)
+	}{
+		{
+			name:          "no desired power state",
+			expectedError: "",
+			expect: func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder) {
+				s.VMPowerStateSpec().Return(nil)
+				s.SetVMPowerState(infrav1.VMPowerState(""))
+			},
+		},
+		{
+			name:          "vm is in its desired power state",
+			expectedError: "",
+			expect: func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder) {
+				s.VMPowerStateSpec().Return(fakeSpec(infrav1.VMPowerStateDeallocated))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(false, "PowerState/deallocated"), nil)
+				s.SetVMPowerState(infrav1.VMPowerStateDeallocated)
+				s.UpdateVMPowerStateStatus("", nil)
+			},
+		},
+		{
+			name:          "deallocate a running vm",
+			expectedError: "changing power state of virtual machine my-vm to Deallocated. Object will be requeued after 30s",
+			expect: func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder) {
+				s.VMPowerStateSpec().Return(fakeSpec(infrav1.VMPowerStateDeallocated))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(false, "PowerState/running"), nil)
+				s.SetVMPowerState(infrav1.VMPowerStateRunning)
+				m.Deallocate(gomockinternal.AContext(), "my-rg", "my-vm", false)
+				s.UpdateVMPowerStateStatus(infrav1.VMPowerStateChangingReason, gomock.Any())
+			},
+		},
+		{
+			name:          "start a deallocated vm",
+			expectedError: "changing power state of virtual machine my-vm to Running. Object will be requeued after 30s",
+			expect: func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder) {
+				s.VMPowerStateSpec().Return(fakeSpec(infrav1.VMPowerStateRunning))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(false, "PowerState/deallocated"), nil)
+				s.SetVMPowerState(infrav1.VMPowerStateDeallocated)
+				m.Start(gomockinternal.AContext(), "my-rg", "my-vm")
+				s.UpdateVMPowerStateStatus(infrav1.VMPowerStateChangingReason, gomock.Any())
+			},
+		},
+		{
+			name:          "start a hibernated vm",
+			expectedError: "changing power state of virtual machine my-vm to Running. Object will be requeued after 30s",
+			expect: func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder) {
+				s.VMPowerStateSpec().Return(fakeSpec(infrav1.VMPowerStateRunning))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(true, "PowerState/deallocated", "HibernationState/Hibernated"), nil)
+				s.SetVMPowerState(infrav1.VMPowerStateHibernated)
+				m.Start(gomockinternal.AContext(), "my-rg", "my-vm")
+				s.UpdateVMPowerStateStatus(infrav1.VMPowerStateChangingReason, gomock.Any())
+			},
+		},
+		{
+			name:          "hibernate a running vm",
+			expectedError: "changing power state of virtual machine my-vm to Hibernated. Object will be requeued after 30s",
+			expect: func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder) {
+				s.VMPowerStateSpec().Return(fakeSpec(infrav1.VMPowerStateHibernated))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(true, "PowerState/running"), nil)
+				s.SetVMPowerState(infrav1.VMPowerStateRunning)
+				m.Deallocate(gomockinternal.AContext(), "my-rg", "my-vm", true)
+				s.UpdateVMPowerStateStatus(infrav1.VMPowerStateChangingReason, gomock.Any())
+			},
+		},
+		{
+			name:          "deallocate a running vm without hibernation before hibernating it",
+			expectedError: "changing power state of virtual machine my-vm to Hibernated. Object will be requeued after 30s",
+			expect: func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder) {
+				s.VMPowerStateSpec().Return(fakeSpec(infrav1.VMPowerStateHibernated))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(false, "PowerState/running"), nil)
+				s.SetVMPowerState(infrav1.VMPowerStateRunning)
+				m.Deallocate(gomockinternal.AContext(), "my-rg", "my-vm", false)
+				s.UpdateVMPowerStateStatus(infrav1.VMPowerStateChangingReason, gomock.Any())
+			},
+		},
+		{
+			name:          "enable hibernation of a deallocated vm",
+			expectedError: "changing power state of virtual machine my-vm to Hibernated. Object will be requeued after 30s",
+			expect: func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder) {
+				s.VMPowerStateSpec().Return(fakeSpec(infrav1.VMPowerStateHibernated))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(false, "PowerState/deallocated"), nil)
+				s.SetVMPowerState(infrav1.VMPowerStateDeallocated)
+				m.EnableHibernation(gomockinternal.AContext(), "my-rg", "my-vm")
+				s.UpdateVMPowerStateStatus(infrav1.VMPowerStateChangingReason, gomock.Any())
+			},
+		},
+		{
+			name:          "wait for a vm whose power state is changing",
+			expectedError: "changing power state of virtual machine my-vm to Deallocated. Object will be requeued after 30s",
+			expect: func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder) {
+				s.VMPowerStateSpec().Return(fakeSpec(infrav1.VMPowerStateDeallocated))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(false, "PowerState/deallocating"), nil)
+				s.SetVMPowerState(infrav1.VMPowerState(""))
+				s.UpdateVMPowerStateStatus(infrav1.VMPowerStateChangingReason, gomock.Any())
+			},
+		},
+		{
+			name:          "fail to get vm",
+			expectedError: "failed to get power state of virtual machine my-vm: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder) {
+				s.VMPowerStateSpec().Return(fakeSpec(infrav1.VMPowerStateDeallocated))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(compute.VirtualMachine{}, internalErr)
+				s.UpdateVMPowerStateStatus(infrav1.VMPowerStateFailedReason, gomock.Any())
+			},
+		},
+		{
+			name:          "fail to deallocate vm",
+			expectedError: "failed to deallocate virtual machine my-vm: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_powerstates.MockVMPowerStateScopeMockRecorder, m *mock_powerstates.MockclientMockRecorder) {
+				s.VMPowerStateSpec().Return(fakeSpec(infrav1.VMPowerStateDeallocated))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(false, "PowerState/running"), nil)
+				s.SetVMPowerState(infrav1.VMPowerStateRunning)
+				m.Deallocate(gomockinternal.AContext(), "my-rg", "my-vm", false).Return(internalErr)
+				s.UpdateVMPowerStateStatus(infrav1.VMPowerStateFailedReason, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_powerstates.NewMockVMPowerStateScope(mockCtrl)
+			clientMock := mock_powerstates.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -18,11 +18,14 @@ package privatedns
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -34,6 +37,7 @@ type client interface {
 	DeleteLink(context.Context, string, string, string) error
 	CreateOrUpdateRecordSet(context.Context, string, string, privatedns.RecordType, string, privatedns.RecordSet) error
 	DeleteRecordSet(context.Context, string, string, privatedns.RecordType, string) error
+	ListRecordSets(context.Context, string, string) ([]privatedns.RecordSet, error)
 }
 
 // AzureClient contains the Azure go-sdk Client.
@@ -56,28 +60,29 @@ func newClient(auth azure.Authorizer) *azureClient {
 // newPrivateZonesClient creates a new private zones client from subscription ID.
 func newPrivateZonesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) privatedns.PrivateZonesClient {
 	zonesClient := privatedns.NewPrivateZonesClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&zonesClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&zonesClient.Client, authorizer, "privatedns")
 	return zonesClient
 }
 
 // newVirtualNetworkLinksClient creates a new virtual networks link client from subscription ID.
 func newVirtualNetworkLinksClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) privatedns.VirtualNetworkLinksClient {
 	linksClient := privatedns.NewVirtualNetworkLinksClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&linksClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&linksClient.Client, authorizer, "privatedns")
 	return linksClient
 }
 
 // newRecordSetsClient creates a new record sets client from subscription ID.
 func newRecordSetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) privatedns.RecordSetsClient {
 	recordsClient := privatedns.NewRecordSetsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&recordsClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&recordsClient.Client, authorizer, "privatedns")
 	return recordsClient
 }
 
 // CreateOrUpdateZone creates or updates a private zone.
-func (ac *azureClient) CreateOrUpdateZone(ctx context.Context, resourceGroupName string, zoneName string, zone privatedns.PrivateZone) error {
+func (ac *azureClient) CreateOrUpdateZone(ctx context.Context, resourceGroupName string, zoneName string, zone privatedns.PrivateZone) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "privatedns.AzureClient.CreateOrUpdateZone")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.privatezones.CreateOrUpdate(ctx, resourceGroupName, zoneName, zone, "", "")
 	if err != nil {
@@ -92,9 +97,10 @@ func (ac *azureClient) CreateOrUpdateZone(ctx context.Context, resourceGroupName
 }
 
 // DeleteZone deletes the private zone.
-func (ac *azureClient) DeleteZone(ctx context.Context, resourceGroupName, name string) error {
+func (ac *azureClient) DeleteZone(ctx context.Context, resourceGroupName, name string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "privatedns.AzureClient.DeleteZone")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.privatezones.Delete(ctx, resourceGroupName, name, "")
 	if err != nil {
@@ -109,9 +115,10 @@ func (ac *azureClient) DeleteZone(ctx context.Context, resourceGroupName, name s
 }
 
 // CreateOrUpdateLink creates or updates a virtual network link to the specified Private DNS zone.
-func (ac *azureClient) CreateOrUpdateLink(ctx context.Context, resourceGroupName, privateZoneName, name string, link privatedns.VirtualNetworkLink) error {
+func (ac *azureClient) CreateOrUpdateLink(ctx context.Context, resourceGroupName, privateZoneName, name string, link privatedns.VirtualNetworkLink) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "privatedns.AzureClient.CreateOrUpdateLink")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.vnetlinks.CreateOrUpdate(ctx, resourceGroupName, privateZoneName, name, link, "", "")
 	if err != nil {
@@ -126,9 +133,10 @@ func (ac *azureClient) CreateOrUpdateLink(ctx context.Context, resourceGroupName
 }
 
 // DeleteLink deletes a virtual network link to the specified Private DNS zone.
-func (ac *azureClient) DeleteLink(ctx context.Context, resourceGroupName, privateZoneName, name string) error {
+func (ac *azureClient) DeleteLink(ctx context.Context, resourceGroupName, privateZoneName, name string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "privatedns.AzureClient.DeleteLink")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.vnetlinks.Delete(ctx, resourceGroupName, privateZoneName, name, "")
 	if err != nil {
@@ -143,19 +151,42 @@ func (ac *azureClient) DeleteLink(ctx context.Context, resourceGroupName, privat
 }
 
 // CreateOrUpdateRecordSet creates or updates a record set within the specified Private DNS zone.
-func (ac *azureClient) CreateOrUpdateRecordSet(ctx context.Context, resourceGroupName string, privateZoneName string, recordType privatedns.RecordType, name string, set privatedns.RecordSet) error {
+func (ac *azureClient) CreateOrUpdateRecordSet(ctx context.Context, resourceGroupName string, privateZoneName string, recordType privatedns.RecordType, name string, set privatedns.RecordSet) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "privatedns.AzureClient.CreateOrUpdateRecordSet")
 	defer done()
+	defer azureerrors.Classify(&err)
 
-	_, err := ac.recordsets.CreateOrUpdate(ctx, resourceGroupName, privateZoneName, recordType, name, set, "", "")
+	_, err = ac.recordsets.CreateOrUpdate(ctx, resourceGroupName, privateZoneName, recordType, name, set, "", "")
 	return err
 }
 
 // DeleteRecordSet deletes a record set within the specified Private DNS zone.
-func (ac *azureClient) DeleteRecordSet(ctx context.Context, resourceGroupName string, privateZoneName string, recordType privatedns.RecordType, name string) error {
+func (ac *azureClient) DeleteRecordSet(ctx context.Context, resourceGroupName string, privateZoneName string, recordType privatedns.RecordType, name string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "privatedns.AzureClient.DeleteRecordSet")
 	defer done()
+	defer azureerrors.Classify(&err)
 
-	_, err := ac.recordsets.Delete(ctx, resourceGroupName, privateZoneName, recordType, name, "")
+	_, err = ac.recordsets.Delete(ctx, resourceGroupName, privateZoneName, recordType, name, "")
 	return err
 }
+
+// ListRecordSets lists all the record sets within the specified Private DNS zone.
+func (ac *azureClient) ListRecordSets(ctx context.Context, resourceGroupName string, privateZoneName string) (_ []privatedns.RecordSet, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "privatedns.AzureClient.ListRecordSets")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	itr, err := ac.recordsets.ListComplete(ctx, resourceGroupName, privateZoneName, nil, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list record sets in the private DNS zone")
+	}
+
+	var sets []privatedns.RecordSet
+	for ; itr.NotDone(); err = itr.NextWithContext(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate record sets [%w]", err)
+		}
+		sets = append(sets, itr.Value())
+	}
+	return sets, nil
+}
@@ -134,3 +134,18 @@ func (mr *MockclientMockRecorder) DeleteZone(arg0, arg1, arg2 interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteZone", reflect.TypeOf((*Mockclient)(nil).DeleteZone), arg0, arg1, arg2)
 }
+
+// ListRecordSets mocks base method.
+func (m *Mockclient) ListRecordSets(arg0 context.Context, arg1, arg2 string) ([]privatedns.RecordSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecordSets", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]privatedns.RecordSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecordSets indicates an expected call of ListRecordSets.
+func (mr *MockclientMockRecorder) ListRecordSets(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecordSets", reflect.TypeOf((*Mockclient)(nil).ListRecordSets), arg0, arg1, arg2)
+}
@@ -18,6 +18,7 @@ package privatedns
 
 import (
 	"context"
+	"path"
 
 	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -38,6 +39,8 @@ type Scope interface {
 type Service struct {
 	Scope Scope
 	client
+	// newZoneClient creates a client for a private DNS zone in another subscription than the cluster.
+	newZoneClient func(subscriptionID string) client
 }
 
 // New creates a new private dns service.
@@ -45,9 +48,31 @@ func New(scope Scope) *Service {
 	return &Service{
 		Scope:  scope,
 		client: newClient(scope),
+		newZoneClient: func(subscriptionID string) client {
+			return newClient(subscriptionAuthorizer{Scope: scope, subscriptionID: subscriptionID})
+		},
 	}
 }
 
+// subscriptionAuthorizer overrides the subscription of the scope authorizer.
+type subscriptionAuthorizer struct {
+	Scope
+	subscriptionID string
+}
+
+// SubscriptionID returns the overridden subscription ID.
+func (a subscriptionAuthorizer) SubscriptionID() string {
+	return a.subscriptionID
+}
+
+// zoneClient returns the client for the subscription of the private DNS zone.
+func (s *Service) zoneClient(zoneSpec *azure.PrivateDNSSpec) client {
+	if zoneSpec.SubscriptionID == "" || zoneSpec.SubscriptionID == s.Scope.SubscriptionID() {
+		return s.client
+	}
+	return s.newZoneClient(zoneSpec.SubscriptionID)
+}
+
 // Reconcile creates or updates the private zone, links it to the vnet, and creates DNS records.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "privatedns.Service.Reconcile")
@@ -55,13 +80,16 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	zoneSpec := s.Scope.PrivateDNSSpec()
 	if zoneSpec != nil {
-		// Create the private DNS zone.
-		log.V(2).Info("creating private DNS zone", "private dns zone", zoneSpec.ZoneName)
-		err := s.client.CreateOrUpdateZone(ctx, s.Scope.ResourceGroup(), zoneSpec.ZoneName, privatedns.PrivateZone{Location: to.StringPtr(azure.Global)})
-		if err != nil {
-			return errors.Wrapf(err, "failed to create private DNS zone %s", zoneSpec.ZoneName)
+		zoneClient := s.zoneClient(zoneSpec)
+		if !zoneSpec.ExistingZone {
+			// Create the private DNS zone.
+			log.V(2).Info("creating private DNS zone", "private dns zone", zoneSpec.ZoneName)
+			err := zoneClient.CreateOrUpdateZone(ctx, zoneSpec.ResourceGroup, zoneSpec.ZoneName, privatedns.PrivateZone{Location: to.StringPtr(azure.Global)})
+			if err != nil {
+				return errors.Wrapf(err, "failed to create private DNS zone %s", zoneSpec.ZoneName)
+			}
+			log.V(2).Info("successfully created private DNS zone", "private dns zone", zoneSpec.ZoneName)
 		}
-		log.V(2).Info("successfully created private DNS zone", "private dns zone", zoneSpec.ZoneName)
 
 		for _, linkSpec := range zoneSpec.Links {
 			// Link each virtual network.
@@ -75,19 +103,27 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				},
 				Location: to.StringPtr(azure.Global),
 			}
-			err = s.client.CreateOrUpdateLink(ctx, s.Scope.ResourceGroup(), zoneSpec.ZoneName, linkSpec.LinkName, link)
+			err := zoneClient.CreateOrUpdateLink(ctx, zoneSpec.ResourceGroup, zoneSpec.ZoneName, linkSpec.LinkName, link)
 			if err != nil {
 				return errors.Wrapf(err, "failed to create virtual network link %s", linkSpec.LinkName)
 			}
 			log.V(2).Info("successfully created virtual network link", "virtual network", linkSpec.VNetName, "private dns zone", zoneSpec.ZoneName)
 		}
 
+		// Delete the records of the cluster which are no longer in the spec before creating the new ones, in case
+		// the record type of a hostname changed.
+		if err := s.deleteStaleRecordSets(ctx, zoneClient, zoneSpec); err != nil {
+			return err
+		}
+
 		// Create the record(s).
+		owner := map[string]*string{azure.PrivateDNSRecordOwnerKey: to.StringPtr(s.Scope.ClusterName())}
 		for _, record := range zoneSpec.Records {
 			log.V(2).Info("creating record set", "private dns zone", zoneSpec.ZoneName, "record", record.Hostname)
 			set := privatedns.RecordSet{
 				RecordSetProperties: &privatedns.RecordSetProperties{
-					TTL: to.Int64Ptr(300),
+					Metadata: owner,
+					TTL:      to.Int64Ptr(300),
 				},
 			}
 			recordType := converters.GetRecordType(record.IP)
@@ -100,7 +136,25 @@ func (s *Service) Reconcile(ctx context.Context) error {
 					Ipv6Address: &record.IP,
 				}}
 			}
-			err := s.client.CreateOrUpdateRecordSet(ctx, s.Scope.ResourceGroup(), zoneSpec.ZoneName, recordType, record.Hostname, set)
+			err := zoneClient.CreateOrUpdateRecordSet(ctx, zoneSpec.ResourceGroup, zoneSpec.ZoneName, recordType, record.Hostname, set)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create record %s in private DNS zone %s", record.Hostname, zoneSpec.ZoneName)
+			}
+			log.V(2).Info("successfully created record set", "private dns zone", zoneSpec.ZoneName, "record", record.Hostname)
+		}
+
+		for _, record := range zoneSpec.CNAMERecords {
+			log.V(2).Info("creating record set", "private dns zone", zoneSpec.ZoneName, "record", record.Hostname)
+			set := privatedns.RecordSet{
+				RecordSetProperties: &privatedns.RecordSetProperties{
+					Metadata: owner,
+					TTL:      to.Int64Ptr(300),
+					CnameRecord: &privatedns.CnameRecord{
+						Cname: to.StringPtr(record.CNAME),
+					},
+				},
+			}
+			err := zoneClient.CreateOrUpdateRecordSet(ctx, zoneSpec.ResourceGroup, zoneSpec.ZoneName, privatedns.CNAME, record.Hostname, set)
 			if err != nil {
 				return errors.Wrapf(err, "failed to create record %s in private DNS zone %s", record.Hostname, zoneSpec.ZoneName)
 			}
@@ -110,33 +164,98 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
-// Delete deletes the private zone.
+// Delete deletes the private zone, or only the records and links of the cluster when the zone is not owned by the cluster.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "privatedns.Service.Delete")
 	defer done()
 
 	zoneSpec := s.Scope.PrivateDNSSpec()
 	if zoneSpec != nil {
+		zoneClient := s.zoneClient(zoneSpec)
+		if zoneSpec.ExistingZone {
+			for _, record := range zoneSpec.Records {
+				// Remove each record of the cluster.
+				log.V(2).Info("deleting record set", "private dns zone", zoneSpec.ZoneName, "record", record.Hostname)
+				err := zoneClient.DeleteRecordSet(ctx, zoneSpec.ResourceGroup, zoneSpec.ZoneName, converters.GetRecordType(record.IP), record.Hostname)
+				if err != nil && !azure.ResourceNotFound(err) {
+					return errors.Wrapf(err, "failed to delete record %s in private DNS zone %s in resource group %s", record.Hostname, zoneSpec.ZoneName, zoneSpec.ResourceGroup)
+				}
+			}
+			for _, record := range zoneSpec.CNAMERecords {
+				log.V(2).Info("deleting record set", "private dns zone", zoneSpec.ZoneName, "record", record.Hostname)
+				err := zoneClient.DeleteRecordSet(ctx, zoneSpec.ResourceGroup, zoneSpec.ZoneName, privatedns.CNAME, record.Hostname)
+				if err != nil && !azure.ResourceNotFound(err) {
+					return errors.Wrapf(err, "failed to delete record %s in private DNS zone %s in resource group %s", record.Hostname, zoneSpec.ZoneName, zoneSpec.ResourceGroup)
+				}
+			}
+		}
+
 		for _, linkSpec := range zoneSpec.Links {
 			// Remove each virtual network link.
 			log.V(2).Info("removing virtual network link", "virtual network", linkSpec.VNetName, "private dns zone", zoneSpec.ZoneName)
-			err := s.client.DeleteLink(ctx, s.Scope.ResourceGroup(), zoneSpec.ZoneName, linkSpec.LinkName)
+			err := zoneClient.DeleteLink(ctx, zoneSpec.ResourceGroup, zoneSpec.ZoneName, linkSpec.LinkName)
 			if err != nil && !azure.ResourceNotFound(err) {
-				return errors.Wrapf(err, "failed to delete virtual network link %s with zone %s in resource group %s", linkSpec.VNetName, zoneSpec.ZoneName, s.Scope.ResourceGroup())
+				return errors.Wrapf(err, "failed to delete virtual network link %s with zone %s in resource group %s", linkSpec.VNetName, zoneSpec.ZoneName, zoneSpec.ResourceGroup)
 			}
 		}
 
+		if zoneSpec.ExistingZone {
+			// The zone is not owned by the cluster.
+			return nil
+		}
+
 		// Delete the private DNS zone, which also deletes all records.
 		log.V(2).Info("deleting private dns zone", "private dns zone", zoneSpec.ZoneName)
-		err := s.client.DeleteZone(ctx, s.Scope.ResourceGroup(), zoneSpec.ZoneName)
+		err := zoneClient.DeleteZone(ctx, zoneSpec.ResourceGroup, zoneSpec.ZoneName)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
 			return nil
 		}
 		if err != nil && !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to delete private dns zone %s in resource group %s", zoneSpec.ZoneName, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to delete private dns zone %s in resource group %s", zoneSpec.ZoneName, zoneSpec.ResourceGroup)
 		}
 		log.V(2).Info("successfully deleted private dns zone", "private dns zone", zoneSpec.ZoneName)
 	}
 	return nil
 }
+
+// deleteStaleRecordSets deletes the record sets owned by the cluster which are not in the spec anymore.
+func (s *Service) deleteStaleRecordSets(ctx context.Context, zoneClient client, zoneSpec *azure.PrivateDNSSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "privatedns.Service.deleteStaleRecordSets")
+	defer done()
+
+	desired := make(map[string]bool)
+	for _, record := range zoneSpec.Records {
+		desired[recordSetKey(converters.GetRecordType(record.IP), record.Hostname)] = true
+	}
+	for _, record := range zoneSpec.CNAMERecords {
+		desired[recordSetKey(privatedns.CNAME, record.Hostname)] = true
+	}
+
+	sets, err := zoneClient.ListRecordSets(ctx, zoneSpec.ResourceGroup, zoneSpec.ZoneName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list records in private DNS zone %s", zoneSpec.ZoneName)
+	}
+	for _, set := range sets {
+		if set.RecordSetProperties == nil || to.String(set.Metadata[azure.PrivateDNSRecordOwnerKey]) != s.Scope.ClusterName() {
+			continue
+		}
+		// The type of a record set is e.g. "Microsoft.Network/privateDnsZones/A".
+		recordType := privatedns.RecordType(path.Base(to.String(set.Type)))
+		hostname := to.String(set.Name)
+		if desired[recordSetKey(recordType, hostname)] {
+			continue
+		}
+		log.V(2).Info("deleting stale record set", "private dns zone", zoneSpec.ZoneName, "record", hostname)
+		err := zoneClient.DeleteRecordSet(ctx, zoneSpec.ResourceGroup, zoneSpec.ZoneName, recordType, hostname)
+		if err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete record %s in private DNS zone %s", hostname, zoneSpec.ZoneName)
+		}
+	}
+	return nil
+}
+
+// recordSetKey identifies a record set by its type and name.
+func recordSetKey(recordType privatedns.RecordType, hostname string) string {
+	return string(recordType) + "/" + hostname
+}
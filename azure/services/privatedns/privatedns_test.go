@@ -50,7 +50,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet",
@@ -77,9 +78,12 @@ func TestReconcilePrivateDNS(t *testing.T) {
 					},
 					Location: to.StringPtr(azure.Global),
 				})
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.ListRecordSets(gomockinternal.AContext(), "my-rg", "my-dns-zone")
 				m.CreateOrUpdateRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.A, "hostname-1", privatedns.RecordSet{
 					RecordSetProperties: &privatedns.RecordSetProperties{
-						TTL: to.Int64Ptr(300),
+						Metadata: map[string]*string{azure.PrivateDNSRecordOwnerKey: to.StringPtr("my-cluster")},
+						TTL:      to.Int64Ptr(300),
 						ARecords: &[]privatedns.ARecord{
 							{
 								Ipv4Address: to.StringPtr("10.0.0.8"),
@@ -94,7 +98,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet-1",
@@ -135,9 +140,12 @@ func TestReconcilePrivateDNS(t *testing.T) {
 					},
 					Location: to.StringPtr(azure.Global),
 				})
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.ListRecordSets(gomockinternal.AContext(), "my-rg", "my-dns-zone")
 				m.CreateOrUpdateRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.A, "hostname-1", privatedns.RecordSet{
 					RecordSetProperties: &privatedns.RecordSetProperties{
-						TTL: to.Int64Ptr(300),
+						Metadata: map[string]*string{azure.PrivateDNSRecordOwnerKey: to.StringPtr("my-cluster")},
+						TTL:      to.Int64Ptr(300),
 						ARecords: &[]privatedns.ARecord{
 							{
 								Ipv4Address: to.StringPtr("10.0.0.8"),
@@ -152,7 +160,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet",
@@ -179,9 +188,12 @@ func TestReconcilePrivateDNS(t *testing.T) {
 					},
 					Location: to.StringPtr(azure.Global),
 				})
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.ListRecordSets(gomockinternal.AContext(), "my-rg", "my-dns-zone")
 				m.CreateOrUpdateRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.AAAA, "hostname-2", privatedns.RecordSet{
 					RecordSetProperties: &privatedns.RecordSetProperties{
-						TTL: to.Int64Ptr(300),
+						Metadata: map[string]*string{azure.PrivateDNSRecordOwnerKey: to.StringPtr("my-cluster")},
+						TTL:      to.Int64Ptr(300),
 						AaaaRecords: &[]privatedns.AaaaRecord{
 							{
 								Ipv6Address: to.StringPtr("2603:1030:805:2::b"),
@@ -196,7 +208,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet-1",
@@ -237,9 +250,12 @@ func TestReconcilePrivateDNS(t *testing.T) {
 					},
 					Location: to.StringPtr(azure.Global),
 				})
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.ListRecordSets(gomockinternal.AContext(), "my-rg", "my-dns-zone")
 				m.CreateOrUpdateRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.AAAA, "hostname-2", privatedns.RecordSet{
 					RecordSetProperties: &privatedns.RecordSetProperties{
-						TTL: to.Int64Ptr(300),
+						Metadata: map[string]*string{azure.PrivateDNSRecordOwnerKey: to.StringPtr("my-cluster")},
+						TTL:      to.Int64Ptr(300),
 						AaaaRecords: &[]privatedns.AaaaRecord{
 							{
 								Ipv6Address: to.StringPtr("2603:1030:805:2::b"),
@@ -249,12 +265,69 @@ func TestReconcilePrivateDNS(t *testing.T) {
 				})
 			},
 		},
+		{
+			name:          "create cname record and delete stale records successfully",
+			expectedError: "",
+			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
+				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
+					CNAMERecords: []azure.PrivateDNSCNAMERecordSpec{
+						{
+							Hostname: "storage",
+							CNAME:    "mystorageaccount.privatelink.blob.core.windows.net",
+						},
+					},
+				})
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.CreateOrUpdateZone(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.PrivateZone{Location: to.StringPtr(azure.Global)})
+				m.ListRecordSets(gomockinternal.AContext(), "my-rg", "my-dns-zone").Return([]privatedns.RecordSet{
+					{
+						Name: to.StringPtr("storage"),
+						Type: to.StringPtr("Microsoft.Network/privateDnsZones/CNAME"),
+						RecordSetProperties: &privatedns.RecordSetProperties{
+							Metadata: map[string]*string{azure.PrivateDNSRecordOwnerKey: to.StringPtr("my-cluster")},
+						},
+					},
+					{
+						Name: to.StringPtr("registry"),
+						Type: to.StringPtr("Microsoft.Network/privateDnsZones/A"),
+						RecordSetProperties: &privatedns.RecordSetProperties{
+							Metadata: map[string]*string{azure.PrivateDNSRecordOwnerKey: to.StringPtr("my-cluster")},
+						},
+					},
+					{
+						Name: to.StringPtr("other-cluster-registry"),
+						Type: to.StringPtr("Microsoft.Network/privateDnsZones/A"),
+						RecordSetProperties: &privatedns.RecordSetProperties{
+							Metadata: map[string]*string{azure.PrivateDNSRecordOwnerKey: to.StringPtr("other-cluster")},
+						},
+					},
+					{
+						Name:                to.StringPtr("@"),
+						Type:                to.StringPtr("Microsoft.Network/privateDnsZones/SOA"),
+						RecordSetProperties: &privatedns.RecordSetProperties{},
+					},
+				}, nil)
+				m.DeleteRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.A, "registry")
+				m.CreateOrUpdateRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.CNAME, "storage", privatedns.RecordSet{
+					RecordSetProperties: &privatedns.RecordSetProperties{
+						Metadata: map[string]*string{azure.PrivateDNSRecordOwnerKey: to.StringPtr("my-cluster")},
+						TTL:      to.Int64Ptr(300),
+						CnameRecord: &privatedns.CnameRecord{
+							Cname: to.StringPtr("mystorageaccount.privatelink.blob.core.windows.net"),
+						},
+					},
+				})
+			},
+		},
 		{
 			name:          "link creation fails",
 			expectedError: "failed to create virtual network link my-link: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet",
@@ -288,7 +361,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 			expectedError: "failed to create virtual network link my-link-2: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet-1",
@@ -385,7 +459,8 @@ func TestDeletePrivateDNS(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet",
@@ -410,7 +485,8 @@ func TestDeletePrivateDNS(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet-1",
@@ -447,7 +523,8 @@ func TestDeletePrivateDNS(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet",
@@ -473,7 +550,8 @@ func TestDeletePrivateDNS(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet-1",
@@ -511,7 +589,8 @@ func TestDeletePrivateDNS(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet",
@@ -538,7 +617,8 @@ func TestDeletePrivateDNS(t *testing.T) {
 			expectedError: "failed to delete virtual network link my-vnet with zone my-dns-zone in resource group my-rg: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet",
@@ -563,7 +643,8 @@ func TestDeletePrivateDNS(t *testing.T) {
 			expectedError: "failed to delete virtual network link my-vnet-2 with zone my-dns-zone in resource group my-rg: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet-1",
@@ -599,7 +680,8 @@ func TestDeletePrivateDNS(t *testing.T) {
 			expectedError: "failed to delete private dns zone my-dns-zone in resource group my-rg: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet",
@@ -625,7 +707,8 @@ func TestDeletePrivateDNS(t *testing.T) {
 			expectedError: "failed to delete private dns zone my-dns-zone in resource group my-rg: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
 				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
-					ZoneName: "my-dns-zone",
+					ZoneName:      "my-dns-zone",
+					ResourceGroup: "my-rg",
 					Links: []azure.PrivateDNSLinkSpec{
 						{
 							VNetName:          "my-vnet-1",
@@ -688,3 +771,95 @@ func TestDeletePrivateDNS(t *testing.T) {
 		})
 	}
 }
+
+func TestExistingPrivateDNSZone(t *testing.T) {
+	zoneSpec := &azure.PrivateDNSSpec{
+		ZoneName:       "contoso.internal",
+		ResourceGroup:  "dns-rg",
+		SubscriptionID: "456",
+		ExistingZone:   true,
+		Links: []azure.PrivateDNSLinkSpec{
+			{
+				VNetName:          "my-vnet",
+				VNetResourceGroup: "vnet-rg",
+				LinkName:          "my-link",
+			},
+		},
+		Records: []infrav1.AddressRecord{
+			{
+				Hostname: "apiserver.my-cluster",
+				IP:       "10.0.0.8",
+			},
+		},
+	}
+
+	t.Run("reconcile only creates links and records in the zone subscription", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		scopeMock := mock_privatedns.NewMockScope(mockCtrl)
+		clientMock := mock_privatedns.NewMockclient(mockCtrl)
+		zoneClientMock := mock_privatedns.NewMockclient(mockCtrl)
+
+		scopeMock.EXPECT().PrivateDNSSpec().Return(zoneSpec)
+		scopeMock.EXPECT().SubscriptionID().AnyTimes().Return("123")
+		zoneClientMock.EXPECT().CreateOrUpdateLink(gomockinternal.AContext(), "dns-rg", "contoso.internal", "my-link", privatedns.VirtualNetworkLink{
+			VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
+				VirtualNetwork: &privatedns.SubResource{
+					ID: to.StringPtr("/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"),
+				},
+				RegistrationEnabled: to.BoolPtr(false),
+			},
+			Location: to.StringPtr(azure.Global),
+		})
+		scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+		zoneClientMock.EXPECT().ListRecordSets(gomockinternal.AContext(), "dns-rg", "contoso.internal")
+		zoneClientMock.EXPECT().CreateOrUpdateRecordSet(gomockinternal.AContext(), "dns-rg", "contoso.internal", privatedns.A, "apiserver.my-cluster", privatedns.RecordSet{
+			RecordSetProperties: &privatedns.RecordSetProperties{
+				Metadata: map[string]*string{azure.PrivateDNSRecordOwnerKey: to.StringPtr("my-cluster")},
+				TTL:      to.Int64Ptr(300),
+				ARecords: &[]privatedns.ARecord{
+					{
+						Ipv4Address: to.StringPtr("10.0.0.8"),
+					},
+				},
+			},
+		})
+
+		s := &Service{
+			Scope:  scopeMock,
+			client: clientMock,
+			newZoneClient: func(subscriptionID string) client {
+				g.Expect(subscriptionID).To(Equal("456"))
+				return zoneClientMock
+			},
+		}
+		g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+	})
+
+	t.Run("delete only removes records and links", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		scopeMock := mock_privatedns.NewMockScope(mockCtrl)
+		clientMock := mock_privatedns.NewMockclient(mockCtrl)
+		zoneClientMock := mock_privatedns.NewMockclient(mockCtrl)
+
+		scopeMock.EXPECT().PrivateDNSSpec().Return(zoneSpec)
+		scopeMock.EXPECT().SubscriptionID().AnyTimes().Return("123")
+		gomock.InOrder(
+			zoneClientMock.EXPECT().DeleteRecordSet(gomockinternal.AContext(), "dns-rg", "contoso.internal", privatedns.A, "apiserver.my-cluster"),
+			zoneClientMock.EXPECT().DeleteLink(gomockinternal.AContext(), "dns-rg", "contoso.internal", "my-link").
+				Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
+		)
+
+		s := &Service{
+			Scope:  scopeMock,
+			client: clientMock,
+			newZoneClient: func(subscriptionID string) client {
+				return zoneClientMock
+			},
+		}
+		g.Expect(s.Delete(context.TODO())).To(Succeed())
+	})
+}
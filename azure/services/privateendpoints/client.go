@@ -0,0 +1,233 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoints
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	Get(context.Context, string, string) (network.PrivateEndpoint, error)
+	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter) (interface{}, azureautorest.FutureAPI, error)
+	DeleteAsync(context.Context, azure.ResourceSpecGetter) (azureautorest.FutureAPI, error)
+	IsDone(context.Context, azureautorest.FutureAPI) (bool, error)
+	Result(context.Context, azureautorest.FutureAPI, string) (interface{}, error)
+	GetPrivateDNSZoneGroup(context.Context, string, string, string) (network.PrivateDNSZoneGroup, error)
+	CreateOrUpdatePrivateDNSZoneGroup(context.Context, string, string, string, network.PrivateDNSZoneGroup) error
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	privateendpoints     network.PrivateEndpointsClient
+	privatednszonegroups network.PrivateDNSZoneGroupsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new private endpoints client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		privateendpoints:     newPrivateEndpointsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		privatednszonegroups: newPrivateDNSZoneGroupsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newPrivateEndpointsClient creates a new private endpoints client from subscription ID.
+func newPrivateEndpointsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PrivateEndpointsClient {
+	privateEndpointsClient := network.NewPrivateEndpointsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&privateEndpointsClient.Client, authorizer, serviceName)
+	return privateEndpointsClient
+}
+
+// newPrivateDNSZoneGroupsClient creates a new private DNS zone groups client from subscription ID.
+func newPrivateDNSZoneGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PrivateDNSZoneGroupsClient {
+	privateDNSZoneGroupsClient := network.NewPrivateDNSZoneGroupsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&privateDNSZoneGroupsClient.Client, authorizer, serviceName)
+	return privateDNSZoneGroupsClient
+}
+
+// Get gets the specified private endpoint by the private endpoint name and resource group.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, privateEndpointName string) (_ network.PrivateEndpoint, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "privateendpoints.AzureClient.Get")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	return ac.privateendpoints.Get(ctx, resourceGroupName, privateEndpointName, "")
+}
+
+// CreateOrUpdateAsync creates or updates a private endpoint asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ interface{}, _ azureautorest.FutureAPI, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "privateendpoints.AzureClient.CreateOrUpdateAsync")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	var existingPrivateEndpoint interface{}
+
+	if existing, err := ac.Get(ctx, spec.ResourceGroupName(), spec.ResourceName()); err != nil && !azure.ResourceNotFound(err) {
+		return nil, nil, errors.Wrapf(err, "failed to get private endpoint %s in %s", spec.ResourceName(), spec.ResourceGroupName())
+	} else if err == nil {
+		existingPrivateEndpoint = existing
+	}
+
+	params, err := spec.Parameters(existingPrivateEndpoint)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get desired parameters for private endpoint %s", spec.ResourceName())
+	}
+
+	privateEndpoint, ok := params.(network.PrivateEndpoint)
+	if !ok {
+		if params == nil {
+			// nothing to do here.
+			return existingPrivateEndpoint, nil, nil
+		}
+		return nil, nil, errors.Errorf("%T is not a network.PrivateEndpoint", params)
+	}
+
+	future, err := ac.privateendpoints.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), privateEndpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = future.WaitForCompletionRef(ctx, ac.privateendpoints.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, &future, err
+	}
+
+	result, err := future.Result(ac.privateendpoints)
+	// if the operation completed, return a nil future
+	return result, nil, err
+}
+
+// DeleteAsync deletes a private endpoint asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ azureautorest.FutureAPI, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "privateendpoints.AzureClient.Delete")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.privateendpoints.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = future.WaitForCompletionRef(ctx, ac.privateendpoints.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return &future, err
+	}
+	_, err = future.Result(ac.privateendpoints)
+	// if the operation completed, return a nil future.
+	return nil, err
+}
+
+// IsDone returns true if the long-running operation has completed.
+func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (_ bool, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "privateendpoints.AzureClient.IsDone")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	done, err := future.DoneWithContext(ctx, ac.privateendpoints)
+	if err != nil {
+		return false, errors.Wrap(err, "failed checking if the operation was complete")
+	}
+
+	return done, nil
+}
+
+// Result fetches the result of a long-running operation future.
+func (ac *AzureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (_ interface{}, err error) {
+	defer azureerrors.Classify(&err)
+
+	if futureData == nil {
+		return nil, errors.Errorf("cannot get result from nil future")
+	}
+	var result func(client network.PrivateEndpointsClient) (privateEndpoint network.PrivateEndpoint, err error)
+
+	switch futureType {
+	case infrav1.PutFuture:
+		var future *network.PrivateEndpointsCreateOrUpdateFuture
+		jsonData, err := futureData.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal future")
+		}
+		if err := json.Unmarshal(jsonData, &future); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal future data")
+		}
+		result = (*future).Result
+
+	case infrav1.DeleteFuture:
+		// Delete does not return a result private endpoint
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("unknown future type %q", futureType)
+	}
+
+	return result(ac.privateendpoints)
+}
+
+// GetPrivateDNSZoneGroup gets the specified private DNS zone group by name, private endpoint, and resource group.
+func (ac *AzureClient) GetPrivateDNSZoneGroup(ctx context.Context, resourceGroupName, privateEndpointName, privateDNSZoneGroupName string) (_ network.PrivateDNSZoneGroup, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "privateendpoints.AzureClient.GetPrivateDNSZoneGroup")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	return ac.privatednszonegroups.Get(ctx, resourceGroupName, privateEndpointName, privateDNSZoneGroupName)
+}
+
+// CreateOrUpdatePrivateDNSZoneGroup creates or updates the private DNS zone group of a private endpoint.
+func (ac *AzureClient) CreateOrUpdatePrivateDNSZoneGroup(ctx context.Context, resourceGroupName, privateEndpointName, privateDNSZoneGroupName string, group network.PrivateDNSZoneGroup) (err error) {
+	ctx, span := tele.Tracer().Start(ctx, "privateendpoints.AzureClient.CreateOrUpdatePrivateDNSZoneGroup")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.privatednszonegroups.CreateOrUpdate(ctx, resourceGroupName, privateEndpointName, privateDNSZoneGroupName, group)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.privatednszonegroups.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.privatednszonegroups)
+	return err
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoints
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "privateendpoints"
+
+// PrivateEndpointScope defines the scope interface for a private endpoints service.
+type PrivateEndpointScope interface {
+	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
+	IsVnetManaged() bool
+	PrivateEndpointSpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope PrivateEndpointScope
+	Client
+}
+
+// New creates a new service.
+func New(scope PrivateEndpointScope) *Service {
+	return &Service{
+		Scope:  scope,
+		Client: NewClient(scope),
+	}
+}
+
+// Reconcile gets/creates/updates the private endpoints of the subnets.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "privateendpoints.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.PrivateEndpointSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	if !s.Scope.IsVnetManaged() {
+		log.V(4).Info("Skipping private endpoints reconcile in custom VNet mode")
+		return nil
+	}
+
+	// We go through the list of PrivateEndpointSpecs to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one
+	// order of precedence is: error creating -> creating in progress -> created (no error)
+	var result error
+	for _, privateEndpointSpec := range specs {
+		if _, err := async.CreateResource(ctx, s.Scope, s.Client, privateEndpointSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+			continue
+		}
+		if err := s.reconcilePrivateDNSZoneGroup(ctx, privateEndpointSpec); err != nil {
+			result = err
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.PrivateEndpointsReadyCondition, serviceName, result)
+	return result
+}
+
+// Delete deletes the private endpoints of the subnets.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "privateendpoints.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.PrivateEndpointSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	if !s.Scope.IsVnetManaged() {
+		log.V(4).Info("Skipping private endpoints delete in custom VNet mode")
+		return nil
+	}
+
+	// We go through the list of PrivateEndpointSpecs to delete each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one
+	// order of precedence is: error deleting -> deleting in progress -> deleted (no error)
+	var result error
+	for _, privateEndpointSpec := range specs {
+		if err := async.DeleteResource(ctx, s.Scope, s.Client, privateEndpointSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.PrivateEndpointsReadyCondition, serviceName, result)
+	return result
+}
+
+// reconcilePrivateDNSZoneGroup registers the private endpoint in the private DNS zones of its spec.
+// The private DNS zone group is deleted by Azure along with the private endpoint.
+func (s *Service) reconcilePrivateDNSZoneGroup(ctx context.Context, spec azure.ResourceSpecGetter) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "privateendpoints.Service.reconcilePrivateDNSZoneGroup")
+	defer done()
+
+	privateEndpointSpec, ok := spec.(*PrivateEndpointSpec)
+	if !ok || privateEndpointSpec.PrivateDNSZoneGroup == nil {
+		return nil
+	}
+
+	rgName, endpointName, groupName := spec.ResourceGroupName(), spec.ResourceName(), privateEndpointSpec.PrivateDNSZoneGroup.Name
+	var existing *network.PrivateDNSZoneGroup
+	if group, err := s.Client.GetPrivateDNSZoneGroup(ctx, rgName, endpointName, groupName); err == nil {
+		existing = &group
+	} else if !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get private DNS zone group %s of private endpoint %s", groupName, endpointName)
+	}
+
+	group := privateEndpointSpec.PrivateDNSZoneGroupParameters(existing)
+	if group == nil {
+		return nil
+	}
+
+	log.V(2).Info("creating private DNS zone group", "private DNS zone group", groupName, "private endpoint", endpointName)
+	if err := s.Client.CreateOrUpdatePrivateDNSZoneGroup(ctx, rgName, endpointName, groupName, *group); err != nil {
+		return errors.Wrapf(err, "failed to create private DNS zone group %s of private endpoint %s", groupName, endpointName)
+	}
+	log.V(2).Info("successfully created private DNS zone group", "private DNS zone group", groupName, "private endpoint", endpointName)
+	return nil
+}
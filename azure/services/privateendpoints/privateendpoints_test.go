@@ -0,0 +1,342 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoints
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints/mock_privateendpoints"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/golden"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeRegistryPrivateEndpoint = PrivateEndpointSpec{
+		Name:                 "my-registry-pe",
+		ResourceGroup:        "my-rg",
+		Location:             "westus2",
+		SubnetID:             "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/node-subnet",
+		PrivateLinkServiceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerRegistry/registries/myregistry",
+		GroupIDs:             []string{"registry"},
+		PrivateDNSZoneGroup: &infrav1.PrivateDNSZoneGroupSpec{
+			Name:              "default",
+			PrivateDNSZoneIDs: []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/privateDnsZones/privatelink.azurecr.io"},
+		},
+		ClusterName: "my-cluster",
+	}
+	fakeStoragePrivateEndpoint = PrivateEndpointSpec{
+		Name:                 "my-storage-pe",
+		ResourceGroup:        "my-rg",
+		Location:             "westus2",
+		SubnetID:             "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/node-subnet",
+		PrivateLinkServiceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/mystorage",
+		GroupIDs:             []string{"blob"},
+		ClusterName:          "my-cluster",
+	}
+	fakePrivateEndpointSpecs = []azure.ResourceSpecGetter{&fakeRegistryPrivateEndpoint, &fakeStoragePrivateEndpoint}
+	fakePrivateDNSZoneGroup  = fakeRegistryPrivateEndpoint.PrivateDNSZoneGroupParameters(nil)
+	notFound                 = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+	internalError            = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+)
+
+func TestReconcilePrivateEndpoints(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder)
+	}{
+		{
+			name:          "noop if no private endpoints are specified",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "noop in custom VNet mode",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.IsVnetManaged().Return(false)
+			},
+		},
+		{
+			name:          "create private endpoints and private DNS zone group",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.IsVnetManaged().Return(true)
+				s.GetLongRunningOperationState("my-registry-pe", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeRegistryPrivateEndpoint).Return(nil, nil, nil)
+				m.GetPrivateDNSZoneGroup(gomockinternal.AContext(), "my-rg", "my-registry-pe", "default").Return(network.PrivateDNSZoneGroup{}, notFound)
+				m.CreateOrUpdatePrivateDNSZoneGroup(gomockinternal.AContext(), "my-rg", "my-registry-pe", "default", *fakePrivateDNSZoneGroup)
+				s.GetLongRunningOperationState("my-storage-pe", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeStoragePrivateEndpoint).Return(nil, nil, nil)
+				s.UpdatePutStatus(infrav1.PrivateEndpointsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "private DNS zone group is up to date",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs[:1])
+				s.IsVnetManaged().Return(true)
+				s.GetLongRunningOperationState("my-registry-pe", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeRegistryPrivateEndpoint).Return(nil, nil, nil)
+				m.GetPrivateDNSZoneGroup(gomockinternal.AContext(), "my-rg", "my-registry-pe", "default").Return(*fakePrivateDNSZoneGroup, nil)
+				s.UpdatePutStatus(infrav1.PrivateEndpointsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to create a private endpoint",
+			expectedError: "failed to create resource my-rg/my-storage-pe (service: privateendpoints): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.IsVnetManaged().Return(true)
+				s.GetLongRunningOperationState("my-registry-pe", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeRegistryPrivateEndpoint).Return(nil, nil, nil)
+				m.GetPrivateDNSZoneGroup(gomockinternal.AContext(), "my-rg", "my-registry-pe", "default").Return(*fakePrivateDNSZoneGroup, nil)
+				s.GetLongRunningOperationState("my-storage-pe", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeStoragePrivateEndpoint).Return(nil, nil, internalError)
+				s.UpdatePutStatus(infrav1.PrivateEndpointsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "fail to create a private DNS zone group",
+			expectedError: "failed to create private DNS zone group default of private endpoint my-registry-pe: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs[:1])
+				s.IsVnetManaged().Return(true)
+				s.GetLongRunningOperationState("my-registry-pe", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeRegistryPrivateEndpoint).Return(nil, nil, nil)
+				m.GetPrivateDNSZoneGroup(gomockinternal.AContext(), "my-rg", "my-registry-pe", "default").Return(network.PrivateDNSZoneGroup{}, notFound)
+				m.CreateOrUpdatePrivateDNSZoneGroup(gomockinternal.AContext(), "my-rg", "my-registry-pe", "default", *fakePrivateDNSZoneGroup).Return(internalError)
+				s.UpdatePutStatus(infrav1.PrivateEndpointsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privateendpoints.NewMockPrivateEndpointScope(mockCtrl)
+			clientMock := mock_privateendpoints.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeletePrivateEndpoints(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder)
+	}{
+		{
+			name:          "noop if no private endpoints are specified",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "delete private endpoints",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.IsVnetManaged().Return(true)
+				s.GetLongRunningOperationState("my-registry-pe", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeRegistryPrivateEndpoint).Return(nil, nil)
+				s.GetLongRunningOperationState("my-storage-pe", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeStoragePrivateEndpoint).Return(nil, notFound)
+				s.UpdateDeleteStatus(infrav1.PrivateEndpointsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete a private endpoint",
+			expectedError: "failed to delete resource my-rg/my-registry-pe (service: privateendpoints): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.IsVnetManaged().Return(true)
+				s.GetLongRunningOperationState("my-registry-pe", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeRegistryPrivateEndpoint).Return(nil, internalError)
+				s.GetLongRunningOperationState("my-storage-pe", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakeStoragePrivateEndpoint).Return(nil, nil)
+				s.UpdateDeleteStatus(infrav1.PrivateEndpointsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privateendpoints.NewMockPrivateEndpointScope(mockCtrl)
+			clientMock := mock_privateendpoints.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestPrivateEndpointSpecParameters(t *testing.T) {
+	spec := fakeRegistryPrivateEndpoint
+
+	testcases := []struct {
+		name          string
+		existing      interface{}
+		expectedError string
+		expect        func(g *WithT, result interface{})
+	}{
+		{
+			name:     "new private endpoint",
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.PrivateEndpoint{}))
+				privateEndpoint := result.(network.PrivateEndpoint)
+				g.Expect(privateEndpoint.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", to.StringPtr("owned")))
+				g.Expect(to.String(privateEndpoint.Subnet.ID)).To(Equal(spec.SubnetID))
+				g.Expect(*privateEndpoint.PrivateLinkServiceConnections).To(HaveLen(1))
+				connection := (*privateEndpoint.PrivateLinkServiceConnections)[0]
+				g.Expect(to.String(connection.PrivateLinkServiceID)).To(Equal(spec.PrivateLinkServiceID))
+				g.Expect(to.StringSlice(connection.GroupIds)).To(Equal([]string{"registry"}))
+			},
+		},
+		{
+			name: "existing private endpoint is up to date",
+			existing: network.PrivateEndpoint{
+				PrivateEndpointProperties: &network.PrivateEndpointProperties{
+					Subnet: &network.Subnet{ID: to.StringPtr(spec.SubnetID)},
+					ManualPrivateLinkServiceConnections: &[]network.PrivateLinkServiceConnection{
+						{
+							PrivateLinkServiceConnectionProperties: &network.PrivateLinkServiceConnectionProperties{
+								PrivateLinkServiceID: to.StringPtr("/subscriptions/123/resourceGroups/MY-RG/providers/Microsoft.ContainerRegistry/registries/myregistry"),
+								GroupIds:             to.StringSlicePtr([]string{"Registry"}),
+							},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "existing private endpoint connects to another resource",
+			existing: network.PrivateEndpoint{
+				PrivateEndpointProperties: &network.PrivateEndpointProperties{
+					Subnet: &network.Subnet{ID: to.StringPtr(spec.SubnetID)},
+					PrivateLinkServiceConnections: &[]network.PrivateLinkServiceConnection{
+						{
+							PrivateLinkServiceConnectionProperties: &network.PrivateLinkServiceConnectionProperties{
+								PrivateLinkServiceID: to.StringPtr(fakeStoragePrivateEndpoint.PrivateLinkServiceID),
+								GroupIds:             to.StringSlicePtr([]string{"blob"}),
+							},
+						},
+					},
+				},
+			},
+			expectedError: "private endpoint my-registry-pe already exists with a different subnet or private link service connection",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			result, err := spec.Parameters(tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, result)
+		})
+	}
+}
+
+func TestPrivateDNSZoneGroupParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	group := fakeRegistryPrivateEndpoint.PrivateDNSZoneGroupParameters(nil)
+	g.Expect(to.String(group.Name)).To(Equal("default"))
+	g.Expect(*group.PrivateDNSZoneConfigs).To(HaveLen(1))
+	config := (*group.PrivateDNSZoneConfigs)[0]
+	g.Expect(to.String(config.Name)).To(Equal("privatelink-azurecr-io"))
+	g.Expect(to.String(config.PrivateDNSZoneID)).To(Equal(fakeRegistryPrivateEndpoint.PrivateDNSZoneGroup.PrivateDNSZoneIDs[0]))
+
+	g.Expect(fakeRegistryPrivateEndpoint.PrivateDNSZoneGroupParameters(group)).To(BeNil())
+	g.Expect(fakeRegistryPrivateEndpoint.PrivateDNSZoneGroupParameters(&network.PrivateDNSZoneGroup{
+		PrivateDNSZoneGroupPropertiesFormat: &network.PrivateDNSZoneGroupPropertiesFormat{
+			PrivateDNSZoneConfigs: &[]network.PrivateDNSZoneConfig{},
+		},
+	})).To(Equal(group))
+	g.Expect(fakeStoragePrivateEndpoint.PrivateDNSZoneGroupParameters(nil)).To(BeNil())
+}
+
+func TestPrivateEndpointPayload(t *testing.T) {
+	g := NewWithT(t)
+
+	result, err := fakeRegistryPrivateEndpoint.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	golden.Assert(t, "privateendpoint", result)
+}
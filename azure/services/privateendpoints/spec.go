@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoints
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// PrivateEndpointSpec defines the specification for a private endpoint.
+type PrivateEndpointSpec struct {
+	Name                 string
+	ResourceGroup        string
+	Location             string
+	SubnetID             string
+	PrivateLinkServiceID string
+	GroupIDs             []string
+	PrivateDNSZoneGroup  *infrav1.PrivateDNSZoneGroupSpec
+	ClusterName          string
+	AdditionalTags       infrav1.Tags
+}
+
+// ResourceName returns the name of the private endpoint.
+func (s *PrivateEndpointSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *PrivateEndpointSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for private endpoints.
+func (s *PrivateEndpointSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the private endpoint.
+func (s *PrivateEndpointSpec) Parameters(existing interface{}) (interface{}, error) {
+	if existing != nil {
+		existingEndpoint, ok := existing.(network.PrivateEndpoint)
+		if !ok {
+			return nil, errors.Errorf("%T is not a network.PrivateEndpoint", existing)
+		}
+		// the subnet and the connection of a private endpoint can't be changed once it is created
+		if existingEndpoint.PrivateEndpointProperties != nil && isUpToDate(*existingEndpoint.PrivateEndpointProperties, s) {
+			// private endpoint is already configured as desired
+			return nil, nil
+		}
+		return nil, errors.Errorf("private endpoint %s already exists with a different subnet or private link service connection", s.Name)
+	}
+
+	return network.PrivateEndpoint{
+		Name:     to.StringPtr(s.Name),
+		Location: to.StringPtr(s.Location),
+		PrivateEndpointProperties: &network.PrivateEndpointProperties{
+			Subnet: &network.Subnet{ID: to.StringPtr(s.SubnetID)},
+			PrivateLinkServiceConnections: &[]network.PrivateLinkServiceConnection{
+				{
+					Name: to.StringPtr(s.Name),
+					PrivateLinkServiceConnectionProperties: &network.PrivateLinkServiceConnectionProperties{
+						PrivateLinkServiceID: to.StringPtr(s.PrivateLinkServiceID),
+						GroupIds:             to.StringSlicePtr(s.GroupIDs),
+					},
+				},
+			},
+		},
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+	}, nil
+}
+
+// PrivateDNSZoneGroupParameters returns the parameters for the private DNS zone group of the private endpoint,
+// or nil if the existing private DNS zone group registers the private endpoint in the desired private DNS zones.
+func (s *PrivateEndpointSpec) PrivateDNSZoneGroupParameters(existing *network.PrivateDNSZoneGroup) *network.PrivateDNSZoneGroup {
+	if s.PrivateDNSZoneGroup == nil {
+		return nil
+	}
+
+	desiredZoneIDs := normalize(s.PrivateDNSZoneGroup.PrivateDNSZoneIDs)
+	if existing != nil && existing.PrivateDNSZoneGroupPropertiesFormat != nil && existing.PrivateDNSZoneConfigs != nil {
+		existingZoneIDs := make([]string, 0, len(*existing.PrivateDNSZoneConfigs))
+		for _, config := range *existing.PrivateDNSZoneConfigs {
+			if config.PrivateDNSZonePropertiesFormat != nil {
+				existingZoneIDs = append(existingZoneIDs, to.String(config.PrivateDNSZoneID))
+			}
+		}
+		if equal(normalize(existingZoneIDs), desiredZoneIDs) {
+			return nil
+		}
+	}
+
+	configs := make([]network.PrivateDNSZoneConfig, 0, len(s.PrivateDNSZoneGroup.PrivateDNSZoneIDs))
+	for _, zoneID := range s.PrivateDNSZoneGroup.PrivateDNSZoneIDs {
+		configs = append(configs, network.PrivateDNSZoneConfig{
+			// the name of a private DNS zone configuration must be unique within the group and can't contain dots
+			Name: to.StringPtr(strings.ReplaceAll(zoneID[strings.LastIndex(zoneID, "/")+1:], ".", "-")),
+			PrivateDNSZonePropertiesFormat: &network.PrivateDNSZonePropertiesFormat{
+				PrivateDNSZoneID: to.StringPtr(zoneID),
+			},
+		})
+	}
+	return &network.PrivateDNSZoneGroup{
+		Name: to.StringPtr(s.PrivateDNSZoneGroup.Name),
+		PrivateDNSZoneGroupPropertiesFormat: &network.PrivateDNSZoneGroupPropertiesFormat{
+			PrivateDNSZoneConfigs: &configs,
+		},
+	}
+}
+
+// isUpToDate returns true if the existing private endpoint is in the desired subnet and connects to the desired
+// sub-resources of the desired resource.
+func isUpToDate(existing network.PrivateEndpointProperties, spec *PrivateEndpointSpec) bool {
+	if existing.Subnet == nil || !strings.EqualFold(to.String(existing.Subnet.ID), spec.SubnetID) {
+		return false
+	}
+
+	// connections which need a manual approval are kept in a separate list by Azure
+	var connections []network.PrivateLinkServiceConnection
+	if existing.PrivateLinkServiceConnections != nil {
+		connections = append(connections, *existing.PrivateLinkServiceConnections...)
+	}
+	if existing.ManualPrivateLinkServiceConnections != nil {
+		connections = append(connections, *existing.ManualPrivateLinkServiceConnections...)
+	}
+	for _, connection := range connections {
+		if connection.PrivateLinkServiceConnectionProperties == nil {
+			continue
+		}
+		if strings.EqualFold(to.String(connection.PrivateLinkServiceID), spec.PrivateLinkServiceID) &&
+			equal(normalize(to.StringSlice(connection.GroupIds)), normalize(spec.GroupIDs)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize returns a sorted, lower-cased copy of the resource IDs or group IDs, which are case-insensitive in Azure.
+func normalize(ids []string) []string {
+	normalized := make([]string, 0, len(ids))
+	for _, id := range ids {
+		normalized = append(normalized, strings.ToLower(id))
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privatelinkservices
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	Get(context.Context, string, string) (network.PrivateLinkService, error)
+	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter) (interface{}, azureautorest.FutureAPI, error)
+	DeleteAsync(context.Context, azure.ResourceSpecGetter) (azureautorest.FutureAPI, error)
+	IsDone(context.Context, azureautorest.FutureAPI) (bool, error)
+	Result(context.Context, azureautorest.FutureAPI, string) (interface{}, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	privatelinkservices network.PrivateLinkServicesClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new private link services client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		privatelinkservices: newPrivateLinkServicesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newPrivateLinkServicesClient creates a new private link services client from subscription ID.
+func newPrivateLinkServicesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PrivateLinkServicesClient {
+	privateLinkServicesClient := network.NewPrivateLinkServicesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&privateLinkServicesClient.Client, authorizer, serviceName)
+	return privateLinkServicesClient
+}
+
+// Get gets the specified private link service by the private link service name and resource group.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, privateLinkServiceName string) (_ network.PrivateLinkService, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "privatelinkservices.AzureClient.Get")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	return ac.privatelinkservices.Get(ctx, resourceGroupName, privateLinkServiceName, "")
+}
+
+// CreateOrUpdateAsync creates or updates a private link service asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ interface{}, _ azureautorest.FutureAPI, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "privatelinkservices.AzureClient.CreateOrUpdateAsync")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	var existingPrivateLinkService interface{}
+
+	if existing, err := ac.Get(ctx, spec.ResourceGroupName(), spec.ResourceName()); err != nil && !azure.ResourceNotFound(err) {
+		return nil, nil, errors.Wrapf(err, "failed to get private link service %s in %s", spec.ResourceName(), spec.ResourceGroupName())
+	} else if err == nil {
+		existingPrivateLinkService = existing
+	}
+
+	params, err := spec.Parameters(existingPrivateLinkService)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get desired parameters for private link service %s", spec.ResourceName())
+	}
+
+	privateLinkService, ok := params.(network.PrivateLinkService)
+	if !ok {
+		if params == nil {
+			// nothing to do here.
+			return existingPrivateLinkService, nil, nil
+		}
+		return nil, nil, errors.Errorf("%T is not a network.PrivateLinkService", params)
+	}
+
+	future, err := ac.privatelinkservices.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), privateLinkService)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = future.WaitForCompletionRef(ctx, ac.privatelinkservices.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, &future, err
+	}
+
+	result, err := future.Result(ac.privatelinkservices)
+	// if the operation completed, return a nil future
+	return result, nil, err
+}
+
+// DeleteAsync deletes a private link service asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ azureautorest.FutureAPI, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "privatelinkservices.AzureClient.Delete")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.privatelinkservices.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = future.WaitForCompletionRef(ctx, ac.privatelinkservices.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return &future, err
+	}
+	_, err = future.Result(ac.privatelinkservices)
+	// if the operation completed, return a nil future.
+	return nil, err
+}
+
+// IsDone returns true if the long-running operation has completed.
+func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (_ bool, err error) {
+	ctx, span := tele.Tracer().Start(ctx, "privatelinkservices.AzureClient.IsDone")
+	defer span.End()
+	defer azureerrors.Classify(&err)
+
+	done, err := future.DoneWithContext(ctx, ac.privatelinkservices)
+	if err != nil {
+		return false, errors.Wrap(err, "failed checking if the operation was complete")
+	}
+
+	return done, nil
+}
+
+// Result fetches the result of a long-running operation future.
+func (ac *AzureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (_ interface{}, err error) {
+	defer azureerrors.Classify(&err)
+
+	if futureData == nil {
+		return nil, errors.Errorf("cannot get result from nil future")
+	}
+	var result func(client network.PrivateLinkServicesClient) (privateLinkService network.PrivateLinkService, err error)
+
+	switch futureType {
+	case infrav1.PutFuture:
+		var future *network.PrivateLinkServicesCreateOrUpdateFuture
+		jsonData, err := futureData.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal future")
+		}
+		if err := json.Unmarshal(jsonData, &future); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal future data")
+		}
+		result = (*future).Result
+
+	case infrav1.DeleteFuture:
+		// Delete does not return a result private link service
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("unknown future type %q", futureType)
+	}
+
+	return result(ac.privatelinkservices)
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privatelinkservices
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatelinkservices/mock_privatelinkservices"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/golden"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakePrivateLinkService = PrivateLinkServiceSpec{
+		Name:                "my-cluster-internal-lb-pls",
+		ResourceGroup:       "my-rg",
+		Location:            "westus2",
+		FrontendIPConfigIDs: []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster-internal-lb/frontendIPConfigurations/my-cluster-internal-lb-frontEnd"},
+		SubnetID:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/control-plane-subnet",
+		NATIPCount:          2,
+		Visibility:          []string{"00000000-0000-0000-0000-000000000000"},
+		AutoApproval:        []string{"00000000-0000-0000-0000-000000000000"},
+		ClusterName:         "my-cluster",
+	}
+	fakePrivateLinkServiceSpecs = []azure.ResourceSpecGetter{&fakePrivateLinkService}
+	notFound                    = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+	internalError               = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+)
+
+func TestReconcilePrivateLinkServices(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder)
+	}{
+		{
+			name:          "noop if no private link service is specified",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder) {
+				s.PrivateLinkServiceSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "create private link service",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder) {
+				s.PrivateLinkServiceSpecs().Return(fakePrivateLinkServiceSpecs)
+				s.GetLongRunningOperationState("my-cluster-internal-lb-pls", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakePrivateLinkService).Return(nil, nil, nil)
+				s.UpdatePutStatus(infrav1.PrivateLinkServiceReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to create private link service",
+			expectedError: "failed to create resource my-rg/my-cluster-internal-lb-pls (service: privatelinkservices): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder) {
+				s.PrivateLinkServiceSpecs().Return(fakePrivateLinkServiceSpecs)
+				s.GetLongRunningOperationState("my-cluster-internal-lb-pls", serviceName).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakePrivateLinkService).Return(nil, nil, internalError)
+				s.UpdatePutStatus(infrav1.PrivateLinkServiceReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privatelinkservices.NewMockPrivateLinkServiceScope(mockCtrl)
+			clientMock := mock_privatelinkservices.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeletePrivateLinkServices(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder)
+	}{
+		{
+			name:          "noop if no private link service is specified",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder) {
+				s.PrivateLinkServiceSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "private link service already deleted",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder) {
+				s.PrivateLinkServiceSpecs().Return(fakePrivateLinkServiceSpecs)
+				s.GetLongRunningOperationState("my-cluster-internal-lb-pls", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakePrivateLinkService).Return(nil, notFound)
+				s.UpdateDeleteStatus(infrav1.PrivateLinkServiceReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete private link service",
+			expectedError: "failed to delete resource my-rg/my-cluster-internal-lb-pls (service: privatelinkservices): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder) {
+				s.PrivateLinkServiceSpecs().Return(fakePrivateLinkServiceSpecs)
+				s.GetLongRunningOperationState("my-cluster-internal-lb-pls", serviceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), &fakePrivateLinkService).Return(nil, internalError)
+				s.UpdateDeleteStatus(infrav1.PrivateLinkServiceReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privatelinkservices.NewMockPrivateLinkServiceScope(mockCtrl)
+			clientMock := mock_privatelinkservices.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestPrivateLinkServiceSpecParameters(t *testing.T) {
+	spec := fakePrivateLinkService
+	desired, err := spec.Parameters(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name     string
+		existing interface{}
+		expect   func(g *WithT, result interface{})
+	}{
+		{
+			name:     "new private link service",
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(network.PrivateLinkService{}))
+				privateLinkService := result.(network.PrivateLinkService)
+				g.Expect(privateLinkService.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", to.StringPtr("owned")))
+				g.Expect(*privateLinkService.LoadBalancerFrontendIPConfigurations).To(HaveLen(1))
+				g.Expect(to.String((*privateLinkService.LoadBalancerFrontendIPConfigurations)[0].ID)).To(Equal(spec.FrontendIPConfigIDs[0]))
+				g.Expect(*privateLinkService.IPConfigurations).To(HaveLen(2))
+				g.Expect(to.Bool((*privateLinkService.IPConfigurations)[0].Primary)).To(BeTrue())
+				g.Expect(to.Bool((*privateLinkService.IPConfigurations)[1].Primary)).To(BeFalse())
+				g.Expect(to.String((*privateLinkService.IPConfigurations)[1].Subnet.ID)).To(Equal(spec.SubnetID))
+				g.Expect(to.StringSlice(privateLinkService.Visibility.Subscriptions)).To(Equal(spec.Visibility))
+				g.Expect(to.StringSlice(privateLinkService.AutoApproval.Subscriptions)).To(Equal(spec.AutoApproval))
+			},
+		},
+		{
+			name:     "existing private link service is up to date",
+			existing: desired,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "existing private link service has fewer NAT IPs",
+			existing: network.PrivateLinkService{
+				PrivateLinkServiceProperties: &network.PrivateLinkServiceProperties{
+					LoadBalancerFrontendIPConfigurations: desired.(network.PrivateLinkService).LoadBalancerFrontendIPConfigurations,
+					IPConfigurations:                     &[]network.PrivateLinkServiceIPConfiguration{(*desired.(network.PrivateLinkService).IPConfigurations)[0]},
+					Visibility:                           desired.(network.PrivateLinkService).Visibility,
+					AutoApproval:                         desired.(network.PrivateLinkService).AutoApproval,
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(desired))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			result, err := spec.Parameters(tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, result)
+		})
+	}
+}
+
+func TestPrivateLinkServicePayload(t *testing.T) {
+	g := NewWithT(t)
+
+	result, err := fakePrivateLinkService.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	golden.Assert(t, "privatelinkservice", result)
+}
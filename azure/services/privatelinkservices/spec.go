@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privatelinkservices
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// PrivateLinkServiceSpec defines the specification for a private link service.
+type PrivateLinkServiceSpec struct {
+	Name                string
+	ResourceGroup       string
+	Location            string
+	FrontendIPConfigIDs []string
+	SubnetID            string
+	NATIPCount          int32
+	Visibility          []string
+	AutoApproval        []string
+	ClusterName         string
+	AdditionalTags      infrav1.Tags
+}
+
+// ResourceName returns the name of the private link service.
+func (s *PrivateLinkServiceSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *PrivateLinkServiceSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for private link services.
+func (s *PrivateLinkServiceSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the private link service.
+func (s *PrivateLinkServiceSpec) Parameters(existing interface{}) (interface{}, error) {
+	if existing != nil {
+		existingService, ok := existing.(network.PrivateLinkService)
+		if !ok {
+			return nil, errors.Errorf("%T is not a network.PrivateLinkService", existing)
+		}
+		if existingService.PrivateLinkServiceProperties != nil && isUpToDate(*existingService.PrivateLinkServiceProperties, s) {
+			// private link service is already configured as desired
+			return nil, nil
+		}
+	}
+
+	frontendIPConfigs := make([]network.FrontendIPConfiguration, 0, len(s.FrontendIPConfigIDs))
+	for _, id := range s.FrontendIPConfigIDs {
+		frontendIPConfigs = append(frontendIPConfigs, network.FrontendIPConfiguration{ID: to.StringPtr(id)})
+	}
+
+	ipConfigs := make([]network.PrivateLinkServiceIPConfiguration, 0, s.NATIPCount)
+	for i := int32(0); i < s.NATIPCount; i++ {
+		ipConfigs = append(ipConfigs, network.PrivateLinkServiceIPConfiguration{
+			Name: to.StringPtr(fmt.Sprintf("%s-nat-ipconfig-%d", s.Name, i)),
+			PrivateLinkServiceIPConfigurationProperties: &network.PrivateLinkServiceIPConfigurationProperties{
+				PrivateIPAllocationMethod: network.IPAllocationMethodDynamic,
+				Subnet:                    &network.Subnet{ID: to.StringPtr(s.SubnetID)},
+				// the first NAT IP configuration of a private link service must be the primary one
+				Primary: to.BoolPtr(i == 0),
+			},
+		})
+	}
+
+	return network.PrivateLinkService{
+		Name:     to.StringPtr(s.Name),
+		Location: to.StringPtr(s.Location),
+		PrivateLinkServiceProperties: &network.PrivateLinkServiceProperties{
+			LoadBalancerFrontendIPConfigurations: &frontendIPConfigs,
+			IPConfigurations:                     &ipConfigs,
+			Visibility: &network.PrivateLinkServicePropertiesVisibility{
+				Subscriptions: to.StringSlicePtr(s.Visibility),
+			},
+			AutoApproval: &network.PrivateLinkServicePropertiesAutoApproval{
+				Subscriptions: to.StringSlicePtr(s.AutoApproval),
+			},
+		},
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+	}, nil
+}
+
+// isUpToDate returns true if the existing private link service exposes the desired load balancer frontends
+// with the desired number of NAT IPs, visibility and auto-approval.
+func isUpToDate(existing network.PrivateLinkServiceProperties, spec *PrivateLinkServiceSpec) bool {
+	var frontendIPConfigIDs []string
+	if existing.LoadBalancerFrontendIPConfigurations != nil {
+		for _, config := range *existing.LoadBalancerFrontendIPConfigurations {
+			frontendIPConfigIDs = append(frontendIPConfigIDs, to.String(config.ID))
+		}
+	}
+	if !equal(normalize(frontendIPConfigIDs), normalize(spec.FrontendIPConfigIDs)) {
+		return false
+	}
+
+	if existing.IPConfigurations == nil || len(*existing.IPConfigurations) != int(spec.NATIPCount) {
+		return false
+	}
+	for _, config := range *existing.IPConfigurations {
+		if config.PrivateLinkServiceIPConfigurationProperties == nil || config.Subnet == nil ||
+			!strings.EqualFold(to.String(config.Subnet.ID), spec.SubnetID) {
+			return false
+		}
+	}
+
+	var visibility, autoApproval []string
+	if existing.Visibility != nil {
+		visibility = to.StringSlice(existing.Visibility.Subscriptions)
+	}
+	if existing.AutoApproval != nil {
+		autoApproval = to.StringSlice(existing.AutoApproval.Subscriptions)
+	}
+	return equal(normalize(visibility), normalize(spec.Visibility)) && equal(normalize(autoApproval), normalize(spec.AutoApproval))
+}
+
+// normalize returns a sorted, lower-cased copy of the resource IDs or subscription IDs, which are case-insensitive in Azure.
+func normalize(ids []string) []string {
+	normalized := make([]string, 0, len(ids))
+	for _, id := range ids {
+		normalized = append(normalized, strings.ToLower(id))
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proximityplacementgroups
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, string, string) (compute.ProximityPlacementGroup, error)
+	CreateOrUpdate(context.Context, string, string, compute.ProximityPlacementGroup) error
+	Delete(context.Context, string, string) error
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	proximityplacementgroups compute.ProximityPlacementGroupsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new proximity placement groups client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newProximityPlacementGroupsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c}
+}
+
+// newProximityPlacementGroupsClient creates a new proximity placement groups client from subscription ID.
+func newProximityPlacementGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.ProximityPlacementGroupsClient {
+	proximityPlacementGroupsClient := compute.NewProximityPlacementGroupsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&proximityPlacementGroupsClient.Client, authorizer, "proximityplacementgroups")
+	return proximityPlacementGroupsClient
+}
+
+// Get gets a proximity placement group.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, proximityPlacementGroupName string) (_ compute.ProximityPlacementGroup, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "proximityplacementgroups.AzureClient.Get")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.proximityplacementgroups.Get(ctx, resourceGroupName, proximityPlacementGroupName, "")
+}
+
+// CreateOrUpdate creates or updates a proximity placement group.
+func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, proximityPlacementGroupName string, proximityPlacementGroup compute.ProximityPlacementGroup) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "proximityplacementgroups.AzureClient.CreateOrUpdate")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.proximityplacementgroups.CreateOrUpdate(ctx, resourceGroupName, proximityPlacementGroupName, proximityPlacementGroup)
+	return err
+}
+
+// Delete deletes a proximity placement group.
+func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, proximityPlacementGroupName string) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "proximityplacementgroups.AzureClient.Delete")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.proximityplacementgroups.Delete(ctx, resourceGroupName, proximityPlacementGroupName)
+	return err
+}
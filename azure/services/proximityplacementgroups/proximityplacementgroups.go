@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proximityplacementgroups
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ProximityPlacementGroupScope defines the scope interface for a proximity placement groups service.
+type ProximityPlacementGroupScope interface {
+	azure.ClusterDescriber
+	ProximityPlacementGroup() (string, bool)
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope ProximityPlacementGroupScope
+	client
+}
+
+// New creates a new proximity placement groups service.
+func New(scope ProximityPlacementGroupScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile creates or updates the proximity placement group of the cluster.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "proximityplacementgroups.Service.Reconcile")
+	defer done()
+
+	name, ok := s.Scope.ProximityPlacementGroup()
+	if !ok {
+		return nil
+	}
+
+	log.V(2).Info("creating proximity placement group", "proximity placement group", name)
+	proximityPlacementGroup := compute.ProximityPlacementGroup{
+		Location: to.StringPtr(s.Scope.Location()),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.Scope.ClusterName(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(name),
+			Role:        to.StringPtr(infrav1.CommonRole),
+			Additional:  s.Scope.AdditionalTags(),
+		})),
+		ProximityPlacementGroupProperties: &compute.ProximityPlacementGroupProperties{
+			ProximityPlacementGroupType: compute.ProximityPlacementGroupTypeStandard,
+		},
+	}
+	if err := s.client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), name, proximityPlacementGroup); err != nil {
+		return errors.Wrapf(err, "failed to create proximity placement group %s in resource group %s", name, s.Scope.ResourceGroup())
+	}
+
+	log.V(2).Info("successfully created proximity placement group", "proximity placement group", name)
+	return nil
+}
+
+// Delete deletes the proximity placement group of the cluster if it is owned by the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "proximityplacementgroups.Service.Delete")
+	defer done()
+
+	name, ok := s.Scope.ProximityPlacementGroup()
+	if !ok {
+		return nil
+	}
+
+	existing, err := s.client.Get(ctx, s.Scope.ResourceGroup(), name)
+	if azure.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get proximity placement group %s in resource group %s", name, s.Scope.ResourceGroup())
+	}
+
+	if !converters.MapToTags(existing.Tags).HasOwned(s.Scope.ClusterName()) {
+		log.V(2).Info("skipping deletion of unowned proximity placement group", "proximity placement group", name)
+		return nil
+	}
+
+	log.V(2).Info("deleting proximity placement group", "proximity placement group", name)
+	if err := s.client.Delete(ctx, s.Scope.ResourceGroup(), name); err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete proximity placement group %s in resource group %s", name, s.Scope.ResourceGroup())
+	}
+
+	log.V(2).Info("successfully deleted proximity placement group", "proximity placement group", name)
+	return nil
+}
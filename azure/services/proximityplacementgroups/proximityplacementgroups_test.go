@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proximityplacementgroups
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/proximityplacementgroups/mock_proximityplacementgroups"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	ownedTags = map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+	}
+	notFound    = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not found")
+	internalErr = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func TestReconcileProximityPlacementGroups(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockclientMockRecorder)
+	}{
+		{
+			name:          "create the proximity placement group",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockclientMockRecorder) {
+				s.ProximityPlacementGroup().Return("my-cluster-ppg", true)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster-ppg", gomock.Any()).
+					Do(func(_ context.Context, _, _ string, ppg compute.ProximityPlacementGroup) {
+						g := NewWithT(t)
+						g.Expect(ppg.Location).To(Equal(to.StringPtr("westus")))
+						g.Expect(ppg.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", to.StringPtr("owned")))
+						g.Expect(ppg.ProximityPlacementGroupType).To(Equal(compute.ProximityPlacementGroupTypeStandard))
+					})
+			},
+		},
+		{
+			name:          "no proximity placement group",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockclientMockRecorder) {
+				s.ProximityPlacementGroup().Return("", false)
+			},
+		},
+		{
+			name:          "fail to create the proximity placement group",
+			expectedError: "failed to create proximity placement group my-cluster-ppg in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockclientMockRecorder) {
+				s.ProximityPlacementGroup().Return("my-cluster-ppg", true)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster-ppg", gomock.Any()).Return(internalErr)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_proximityplacementgroups.NewMockProximityPlacementGroupScope(mockCtrl)
+			clientMock := mock_proximityplacementgroups.NewMockclient(mockCtrl)
+
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			scopeMock.EXPECT().Location().AnyTimes().Return("westus")
+			scopeMock.EXPECT().AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteProximityPlacementGroups(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockclientMockRecorder)
+	}{
+		{
+			name:          "delete the owned proximity placement group",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockclientMockRecorder) {
+				s.ProximityPlacementGroup().Return("my-cluster-ppg", true)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster-ppg").Return(compute.ProximityPlacementGroup{Tags: ownedTags}, nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-cluster-ppg")
+			},
+		},
+		{
+			name:          "no proximity placement group",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockclientMockRecorder) {
+				s.ProximityPlacementGroup().Return("", false)
+			},
+		},
+		{
+			name:          "proximity placement group already deleted",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockclientMockRecorder) {
+				s.ProximityPlacementGroup().Return("my-cluster-ppg", true)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster-ppg").Return(compute.ProximityPlacementGroup{}, notFound)
+			},
+		},
+		{
+			name:          "skip the unowned proximity placement group",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockclientMockRecorder) {
+				s.ProximityPlacementGroup().Return("my-cluster-ppg", true)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster-ppg").Return(compute.ProximityPlacementGroup{}, nil)
+			},
+		},
+		{
+			name:          "fail to delete the proximity placement group",
+			expectedError: "failed to delete proximity placement group my-cluster-ppg in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockclientMockRecorder) {
+				s.ProximityPlacementGroup().Return("my-cluster-ppg", true)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster-ppg").Return(compute.ProximityPlacementGroup{Tags: ownedTags}, nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-cluster-ppg").Return(internalErr)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_proximityplacementgroups.NewMockProximityPlacementGroupScope(mockCtrl)
+			clientMock := mock_proximityplacementgroups.NewMockclient(mockCtrl)
+
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publicdns
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type client interface {
+	GetRecordSet(context.Context, string, string, string, dns.RecordType) (dns.RecordSet, error)
+	CreateOrUpdateRecordSet(context.Context, string, string, string, dns.RecordType, dns.RecordSet) error
+	DeleteRecordSet(context.Context, string, string, string, dns.RecordType) error
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	recordsets dns.RecordSetsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new DNS client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newRecordSetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c}
+}
+
+// newRecordSetsClient creates a new record sets client from subscription ID.
+func newRecordSetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) dns.RecordSetsClient {
+	recordsClient := dns.NewRecordSetsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&recordsClient.Client, authorizer, "publicdns")
+	return recordsClient
+}
+
+// GetRecordSet gets a record set within the specified DNS zone.
+func (ac *azureClient) GetRecordSet(ctx context.Context, resourceGroupName string, zoneName string, name string, recordType dns.RecordType) (_ dns.RecordSet, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicdns.AzureClient.GetRecordSet")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.recordsets.Get(ctx, resourceGroupName, zoneName, name, recordType)
+}
+
+// CreateOrUpdateRecordSet creates or updates a record set within the specified DNS zone.
+func (ac *azureClient) CreateOrUpdateRecordSet(ctx context.Context, resourceGroupName string, zoneName string, name string, recordType dns.RecordType, set dns.RecordSet) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicdns.AzureClient.CreateOrUpdateRecordSet")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.recordsets.CreateOrUpdate(ctx, resourceGroupName, zoneName, name, recordType, set, "", "")
+	return err
+}
+
+// DeleteRecordSet deletes a record set within the specified DNS zone.
+func (ac *azureClient) DeleteRecordSet(ctx context.Context, resourceGroupName string, zoneName string, name string, recordType dns.RecordType) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicdns.AzureClient.DeleteRecordSet")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	_, err = ac.recordsets.Delete(ctx, resourceGroupName, zoneName, name, recordType, "")
+	return err
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publicdns
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Scope defines the scope interface for a public dns service.
+type Scope interface {
+	azure.ClusterDescriber
+	PublicDNSSpec() *azure.PublicDNSSpec
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope Scope
+	client
+	// newZoneClient creates a client for a DNS zone in another subscription than the cluster.
+	newZoneClient func(subscriptionID string) client
+}
+
+// New creates a new public dns service.
+func New(scope Scope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+		newZoneClient: func(subscriptionID string) client {
+			return newClient(subscriptionAuthorizer{Scope: scope, subscriptionID: subscriptionID})
+		},
+	}
+}
+
+// subscriptionAuthorizer overrides the subscription of the scope authorizer.
+type subscriptionAuthorizer struct {
+	Scope
+	subscriptionID string
+}
+
+// SubscriptionID returns the overridden subscription ID.
+func (a subscriptionAuthorizer) SubscriptionID() string {
+	return a.subscriptionID
+}
+
+// zoneClient returns the client for the subscription of the DNS zone.
+func (s *Service) zoneClient(recordSpec *azure.PublicDNSSpec) client {
+	if recordSpec.SubscriptionID == "" || recordSpec.SubscriptionID == s.Scope.SubscriptionID() {
+		return s.client
+	}
+	return s.newZoneClient(recordSpec.SubscriptionID)
+}
+
+// Reconcile creates or updates the record of the API server in the DNS zone.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "publicdns.Service.Reconcile")
+	defer done()
+
+	recordSpec := s.Scope.PublicDNSSpec()
+	if recordSpec == nil {
+		return nil
+	}
+	zoneClient := s.zoneClient(recordSpec)
+	recordType := dns.RecordType(recordSpec.RecordType)
+
+	// Never take over a record of the zone which was not created for the cluster.
+	existing, err := zoneClient.GetRecordSet(ctx, recordSpec.ResourceGroup, recordSpec.ZoneName, recordSpec.RecordName, recordType)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get record %s in DNS zone %s", recordSpec.RecordName, recordSpec.ZoneName)
+	}
+	if err == nil && !s.isOwned(existing) {
+		return errors.Errorf("record %s in DNS zone %s is not owned by the cluster", recordSpec.RecordName, recordSpec.ZoneName)
+	}
+
+	log.V(2).Info("creating record set", "dns zone", recordSpec.ZoneName, "record", recordSpec.RecordName)
+	set := dns.RecordSet{
+		RecordSetProperties: &dns.RecordSetProperties{
+			Metadata: map[string]*string{azure.PublicDNSRecordOwnerKey: to.StringPtr(s.Scope.ClusterName())},
+			TTL:      to.Int64Ptr(recordSpec.TTL),
+		},
+	}
+	if recordType == dns.A {
+		// An alias record follows the address of the public IP, even if it changes.
+		set.RecordSetProperties.TargetResource = &dns.SubResource{ID: to.StringPtr(recordSpec.PublicIPID)}
+	} else {
+		set.RecordSetProperties.CnameRecord = &dns.CnameRecord{Cname: to.StringPtr(recordSpec.CNAME)}
+	}
+	err = zoneClient.CreateOrUpdateRecordSet(ctx, recordSpec.ResourceGroup, recordSpec.ZoneName, recordSpec.RecordName, recordType, set)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create record %s in DNS zone %s", recordSpec.RecordName, recordSpec.ZoneName)
+	}
+	log.V(2).Info("successfully created record set", "dns zone", recordSpec.ZoneName, "record", recordSpec.RecordName)
+	return nil
+}
+
+// Delete deletes the record of the API server from the DNS zone, if it is owned by the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "publicdns.Service.Delete")
+	defer done()
+
+	recordSpec := s.Scope.PublicDNSSpec()
+	if recordSpec == nil {
+		return nil
+	}
+	zoneClient := s.zoneClient(recordSpec)
+	recordType := dns.RecordType(recordSpec.RecordType)
+
+	existing, err := zoneClient.GetRecordSet(ctx, recordSpec.ResourceGroup, recordSpec.ZoneName, recordSpec.RecordName, recordType)
+	if azure.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get record %s in DNS zone %s", recordSpec.RecordName, recordSpec.ZoneName)
+	}
+	if !s.isOwned(existing) {
+		log.V(2).Info("skipping deletion of record set not owned by the cluster", "dns zone", recordSpec.ZoneName, "record", recordSpec.RecordName)
+		return nil
+	}
+
+	log.V(2).Info("deleting record set", "dns zone", recordSpec.ZoneName, "record", recordSpec.RecordName)
+	err = zoneClient.DeleteRecordSet(ctx, recordSpec.ResourceGroup, recordSpec.ZoneName, recordSpec.RecordName, recordType)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete record %s in DNS zone %s", recordSpec.RecordName, recordSpec.ZoneName)
+	}
+	log.V(2).Info("successfully deleted record set", "dns zone", recordSpec.ZoneName, "record", recordSpec.RecordName)
+	return nil
+}
+
+// isOwned returns true if the record set was created for the cluster.
+func (s *Service) isOwned(set dns.RecordSet) bool {
+	return set.RecordSetProperties != nil && to.String(set.Metadata[azure.PublicDNSRecordOwnerKey]) == s.Scope.ClusterName()
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publicdns
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicdns/mock_publicdns"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	cnameRecordSpec = azure.PublicDNSSpec{
+		ZoneName:      "example.com",
+		ResourceGroup: "dns-rg",
+		RecordName:    "api.cluster1",
+		RecordType:    infrav1.PublicDNSRecordTypeCNAME,
+		TTL:           300,
+		CNAME:         "my-cluster-api.eastus.cloudapp.azure.com",
+		PublicIPID:    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-api",
+	}
+	aRecordSpec = azure.PublicDNSSpec{
+		ZoneName:      "example.com",
+		ResourceGroup: "dns-rg",
+		RecordName:    "api.cluster1",
+		RecordType:    infrav1.PublicDNSRecordTypeA,
+		TTL:           60,
+		CNAME:         "my-cluster-api.eastus.cloudapp.azure.com",
+		PublicIPID:    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-api",
+	}
+	notFound    = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+	internalErr = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+	owner       = map[string]*string{azure.PublicDNSRecordOwnerKey: to.StringPtr("my-cluster")}
+)
+
+func TestReconcilePublicDNS(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder)
+	}{
+		{
+			name:          "no public dns record",
+			expectedError: "",
+			expect: func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder) {
+				s.PublicDNSSpec().Return(nil)
+			},
+		},
+		{
+			name:          "create cname record successfully",
+			expectedError: "",
+			expect: func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder) {
+				s.PublicDNSSpec().Return(&cnameRecordSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME).Return(dns.RecordSet{}, notFound)
+				m.CreateOrUpdateRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME, dns.RecordSet{
+					RecordSetProperties: &dns.RecordSetProperties{
+						Metadata: owner,
+						TTL:      to.Int64Ptr(300),
+						CnameRecord: &dns.CnameRecord{
+							Cname: to.StringPtr("my-cluster-api.eastus.cloudapp.azure.com"),
+						},
+					},
+				})
+			},
+		},
+		{
+			name:          "update a record owned by the cluster successfully",
+			expectedError: "",
+			expect: func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder) {
+				s.PublicDNSSpec().Return(&aRecordSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.A).Return(dns.RecordSet{
+					RecordSetProperties: &dns.RecordSetProperties{Metadata: owner},
+				}, nil)
+				m.CreateOrUpdateRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.A, dns.RecordSet{
+					RecordSetProperties: &dns.RecordSetProperties{
+						Metadata: owner,
+						TTL:      to.Int64Ptr(60),
+						TargetResource: &dns.SubResource{
+							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-api"),
+						},
+					},
+				})
+			},
+		},
+		{
+			name:          "record not owned by the cluster",
+			expectedError: "record api.cluster1 in DNS zone example.com is not owned by the cluster",
+			expect: func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder) {
+				s.PublicDNSSpec().Return(&cnameRecordSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME).Return(dns.RecordSet{
+					RecordSetProperties: &dns.RecordSetProperties{
+						Metadata: map[string]*string{azure.PublicDNSRecordOwnerKey: to.StringPtr("other-cluster")},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "record creation fails",
+			expectedError: "failed to create record api.cluster1 in DNS zone example.com: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder) {
+				s.PublicDNSSpec().Return(&cnameRecordSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME).Return(dns.RecordSet{}, notFound)
+				m.CreateOrUpdateRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME, gomock.Any()).Return(internalErr)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_publicdns.NewMockScope(mockCtrl)
+			clientMock := mock_publicdns.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeletePublicDNS(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder)
+	}{
+		{
+			name:          "no public dns record",
+			expectedError: "",
+			expect: func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder) {
+				s.PublicDNSSpec().Return(nil)
+			},
+		},
+		{
+			name:          "delete the record of the cluster successfully",
+			expectedError: "",
+			expect: func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder) {
+				s.PublicDNSSpec().Return(&cnameRecordSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				gomock.InOrder(
+					m.GetRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME).Return(dns.RecordSet{
+						RecordSetProperties: &dns.RecordSetProperties{Metadata: owner},
+					}, nil),
+					m.DeleteRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME),
+				)
+			},
+		},
+		{
+			name:          "record already deleted",
+			expectedError: "",
+			expect: func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder) {
+				s.PublicDNSSpec().Return(&cnameRecordSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				m.GetRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME).Return(dns.RecordSet{}, notFound)
+			},
+		},
+		{
+			name:          "record not owned by the cluster is kept",
+			expectedError: "",
+			expect: func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder) {
+				s.PublicDNSSpec().Return(&cnameRecordSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME).Return(dns.RecordSet{
+					RecordSetProperties: &dns.RecordSetProperties{},
+				}, nil)
+			},
+		},
+		{
+			name:          "record deletion fails",
+			expectedError: "failed to delete record api.cluster1 in DNS zone example.com: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_publicdns.MockScopeMockRecorder, m *mock_publicdns.MockclientMockRecorder) {
+				s.PublicDNSSpec().Return(&cnameRecordSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				gomock.InOrder(
+					m.GetRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME).Return(dns.RecordSet{
+						RecordSetProperties: &dns.RecordSetProperties{Metadata: owner},
+					}, nil),
+					m.DeleteRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME).Return(internalErr),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_publicdns.NewMockScope(mockCtrl)
+			clientMock := mock_publicdns.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestPublicDNSInOtherSubscription(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_publicdns.NewMockScope(mockCtrl)
+	clientMock := mock_publicdns.NewMockclient(mockCtrl)
+	zoneClientMock := mock_publicdns.NewMockclient(mockCtrl)
+
+	recordSpec := cnameRecordSpec
+	recordSpec.SubscriptionID = "456"
+	scopeMock.EXPECT().PublicDNSSpec().Return(&recordSpec)
+	scopeMock.EXPECT().SubscriptionID().AnyTimes().Return("123")
+	scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+	zoneClientMock.EXPECT().GetRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME).Return(dns.RecordSet{}, notFound)
+	zoneClientMock.EXPECT().CreateOrUpdateRecordSet(gomockinternal.AContext(), "dns-rg", "example.com", "api.cluster1", dns.CNAME, gomock.Any())
+
+	s := &Service{
+		Scope:  scopeMock,
+		client: clientMock,
+		newZoneClient: func(subscriptionID string) client {
+			g.Expect(subscriptionID).To(Equal("456"))
+			return zoneClientMock
+		},
+	}
+	g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -49,22 +50,24 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newPublicIPAddressesClient creates a new public IP client from subscription ID.
 func newPublicIPAddressesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PublicIPAddressesClient {
 	publicIPsClient := network.NewPublicIPAddressesClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&publicIPsClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&publicIPsClient.Client, authorizer, "publicips")
 	return publicIPsClient
 }
 
 // Get gets the specified public IP address in a specified resource group.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, ipName string) (network.PublicIPAddress, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, ipName string) (_ network.PublicIPAddress, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicips.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.publicips.Get(ctx, resourceGroupName, ipName, "")
 }
 
 // CreateOrUpdate creates or updates a static or dynamic public IP address.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, ipName string, ip network.PublicIPAddress) error {
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, ipName string, ip network.PublicIPAddress) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicips.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.publicips.CreateOrUpdate(ctx, resourceGroupName, ipName, ip)
 	if err != nil {
@@ -79,9 +82,10 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName str
 }
 
 // Delete deletes the specified public IP address.
-func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, ipName string) error {
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, ipName string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicips.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.publicips.Delete(ctx, resourceGroupName, ipName)
 	if err != nil {
@@ -33,6 +33,7 @@ import (
 type PublicIPScope interface {
 	azure.ClusterDescriber
 	PublicIPSpecs() []azure.PublicIPSpec
+	SetPublicIPRetained(name string)
 }
 
 // Service provides operations on Azure resources.
@@ -121,6 +122,12 @@ func (s *Service) Delete(ctx context.Context) error {
 			continue
 		}
 
+		if ip.RetainOnDelete {
+			log.V(2).Info("Retaining public IP", "public ip", ip.Name)
+			s.Scope.SetPublicIPRetained(ip.Name)
+			continue
+		}
+
 		log.V(2).Info("deleting public IP", "public ip", ip.Name)
 		err = s.Client.Delete(ctx, s.Scope.ResourceGroup(), ip.Name)
 		if err != nil && azure.ResourceNotFound(err) {
@@ -136,6 +143,25 @@ func (s *Service) Delete(ctx context.Context) error {
 	return nil
 }
 
+// Describe returns a summary of the existing public IPs. Public IPs that don't exist are skipped.
+func (s *Service) Describe(ctx context.Context) ([]azure.PublicIPSummary, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicips.Service.Describe")
+	defer done()
+
+	var summaries []azure.PublicIPSummary
+	for _, ip := range s.Scope.PublicIPSpecs() {
+		existing, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), ip.Name)
+		if azure.ResourceNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get public IP %s in %s", ip.Name, s.Scope.ResourceGroup())
+		}
+		summaries = append(summaries, converters.SDKToPublicIPSummary(existing))
+	}
+	return summaries, nil
+}
+
 // isIPManaged returns true if the IP has an owned tag with the cluster name as value,
 // meaning that the IP's lifecycle is managed.
 func (s *Service) isIPManaged(ctx context.Context, ipName string) (bool, error) {
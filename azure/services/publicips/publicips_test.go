@@ -277,6 +277,37 @@ func TestDeletePublicIP(t *testing.T) {
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
+		{
+			name:          "retain public ip",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:           "my-publicip",
+						RetainOnDelete: true,
+					},
+					{
+						Name: "my-publicip-2",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
+					Name: to.StringPtr("my-publicip"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+				}, nil)
+				s.SetPublicIPRetained("my-publicip")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip-2").Return(network.PublicIPAddress{
+					Name: to.StringPtr("my-publicip-2"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+				}, nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-publicip-2")
+			},
+		},
 		{
 			name:          "skip unmanaged public ip deletion",
 			expectedError: "",
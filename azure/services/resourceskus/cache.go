@@ -18,7 +18,9 @@ package resourceskus
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 	"sync"
@@ -61,6 +63,8 @@ var (
 	_           Client = &AzureClient{}
 	doOnce      sync.Once
 	clientCache Cacher
+	// staticData are the resource SKUs of all locations loaded by LoadStaticData.
+	staticData []compute.ResourceSku
 )
 
 // newCache instantiates a cache and initializes its contents.
@@ -82,6 +86,10 @@ func GetCache(auth azure.Authorizer, location string) (*Cache, error) {
 		return nil, errors.Wrap(err, "failed creating LRU cache for resourceSKUs cache")
 	}
 
+	if staticData != nil {
+		return NewStaticCache(staticDataForLocation(location), location), nil
+	}
+
 	key := location + "_" + auth.HashKey()
 	c, ok := clientCache.Get(key)
 	if ok {
@@ -93,7 +101,7 @@ func GetCache(auth azure.Authorizer, location string) (*Cache, error) {
 	return c.(*Cache), nil
 }
 
-// NewStaticCache initializes a cache with data and no ability to refresh. Used for testing.
+// NewStaticCache initializes a cache with data and no ability to refresh. Used for testing and static resource SKUs.
 func NewStaticCache(data []compute.ResourceSku, location string) *Cache {
 	return &Cache{
 		data:     data,
@@ -101,6 +109,43 @@ func NewStaticCache(data []compute.ResourceSku, location string) *Cache {
 	}
 }
 
+// LoadStaticData loads the resource SKUs from a JSON file, e.g. the output of "az vm list-skus --output json".
+// Caches are then initialized with the resource SKUs of their location from this file instead of listing them from
+// Azure, which requires permissions on the whole subscription.
+func LoadStaticData(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read resource SKUs file %s", path)
+	}
+	var data []compute.ResourceSku
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return errors.Wrapf(err, "failed to parse resource SKUs file %s", path)
+	}
+	if data == nil {
+		data = []compute.ResourceSku{}
+	}
+	staticData = data
+	return nil
+}
+
+// staticDataForLocation returns the static resource SKUs available in a location. The result is never nil, so that
+// a cache initialized with it never refreshes.
+func staticDataForLocation(location string) []compute.ResourceSku {
+	data := []compute.ResourceSku{}
+	for _, sku := range staticData {
+		if sku.Locations == nil {
+			continue
+		}
+		for _, skuLocation := range *sku.Locations {
+			if strings.EqualFold(skuLocation, location) {
+				data = append(data, sku)
+				break
+			}
+		}
+	}
+	return data
+}
+
 func (c *Cache) refresh(ctx context.Context, location string) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "resourceskus.Cache.refresh")
 	defer done()
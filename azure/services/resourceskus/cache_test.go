@@ -18,6 +18,8 @@ package resourceskus
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
@@ -394,3 +396,41 @@ func TestCacheGetZonesWithVMSize(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadStaticData(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "skus.json")
+	skus := `[
+  {"name": "Standard_D2s_v3", "resourceType": "virtualMachines", "locations": ["eastus"], "locationInfo": [{"location": "eastus", "zones": ["1", "2"]}]},
+  {"name": "Standard_D4s_v3", "resourceType": "virtualMachines", "locations": ["westeurope"], "locationInfo": [{"location": "westeurope", "zones": ["3"]}]}
+]`
+	if err := os.WriteFile(path, []byte(skus), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { staticData = nil }()
+
+	if err := LoadStaticData(path); err != nil {
+		t.Fatalf("expected static data to load, but got error %s", err)
+	}
+
+	cache, err := GetCache(nil, "EastUS")
+	if err != nil {
+		t.Fatalf("expected cache to be created, but got error %s", err)
+	}
+	if _, err := cache.Get(context.Background(), "Standard_D2s_v3", VirtualMachines); err != nil {
+		t.Fatalf("expected sku of the location to be found, but got error %s", err)
+	}
+	if _, err := cache.Get(context.Background(), "Standard_D4s_v3", VirtualMachines); err == nil {
+		t.Fatal("expected sku of another location not to be found")
+	}
+	zones, err := cache.GetZones(context.Background(), "eastus")
+	if err != nil {
+		t.Fatalf("expected zones to be found, but got error %s", err)
+	}
+	if diff := cmp.Diff([]string{"1", "2"}, zones); diff != "" {
+		t.Fatalf("unexpected zones (-want +got):\n%s", diff)
+	}
+
+	if err := LoadStaticData(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected a missing file to fail to load")
+	}
+}
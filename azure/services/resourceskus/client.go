@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -49,14 +50,15 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newResourceSkusClient creates a new Resource SKUs client from subscription ID.
 func newResourceSkusClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.ResourceSkusClient {
 	c := compute.NewResourceSkusClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer, "resourceskus")
 	return c
 }
 
 // List returns all Resource SKUs available to the subscription.
-func (ac *AzureClient) List(ctx context.Context, filter string) ([]compute.ResourceSku, error) {
+func (ac *AzureClient) List(ctx context.Context, filter string) (_ []compute.ResourceSku, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "resourceskus.AzureClient.List")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	iter, err := ac.skus.ListComplete(ctx, filter)
 	if err != nil {
@@ -68,8 +68,49 @@ const (
 	MaximumPlatformFaultDomainCount = "MaximumPlatformFaultDomainCount"
 	// UltraSSDAvailable identifies the capability for the support of UltraSSD data disks.
 	UltraSSDAvailable = "UltraSSDAvailable"
+	// TrustedLaunchDisabled identifies the capability for the lack of support of trusted launch.
+	TrustedLaunchDisabled = "TrustedLaunchDisabled"
+	// HyperVGenerations identifies the comma separated list of Hyper-V generations supported by a VM size, e.g. "V1,V2".
+	HyperVGenerations = "HyperVGenerations"
+	// MaxWriteAcceleratorDisksAllowed identifies the capability for the maximum number of data disks with Write
+	// Accelerator enabled, which is only exposed by the VM sizes supporting Write Accelerator.
+	MaxWriteAcceleratorDisksAllowed = "MaxWriteAcceleratorDisksAllowed"
+	// GPUs identifies the capability for the number of GPUs, which is only exposed by the VM sizes with GPUs.
+	GPUs = "GPUs"
+	// MaxResourceVolumeMB identifies the capability for the size of the temporary disk.
+	MaxResourceVolumeMB = "MaxResourceVolumeMB"
+	// CPUArchitectureType identifies the capability for the CPU architecture, "x64" or "Arm64".
+	CPUArchitectureType = "CpuArchitectureType"
+	// CachedDiskBytes identifies the capability for the size of the cache disk, in bytes.
+	CachedDiskBytes = "CachedDiskBytes"
 )
 
+// HasEphemeralOSDiskCapacity returns true if an ephemeral OS disk of the given size fits where Azure places it by
+// default: the cache disk of the VM size if it has one, or else its temporary disk.
+func (s SKU) HasEphemeralOSDiskCapacity(diskSizeGB int32) (bool, error) {
+	if cached, ok := s.GetCapability(CachedDiskBytes); ok && cached != "0" {
+		return s.HasCapabilityWithCapacity(CachedDiskBytes, int64(diskSizeGB)<<30)
+	}
+	return s.HasCapabilityWithCapacity(MaxResourceVolumeMB, int64(diskSizeGB)<<10)
+}
+
+// SupportsTrustedLaunch returns true if the VM size supports trusted launch, which requires Hyper-V generation 2.
+func (s SKU) SupportsTrustedLaunch() bool {
+	if s.HasCapability(TrustedLaunchDisabled) {
+		return false
+	}
+	generations, ok := s.GetCapability(HyperVGenerations)
+	if !ok {
+		return false
+	}
+	for _, generation := range strings.Split(generations, ",") {
+		if strings.EqualFold(strings.TrimSpace(generation), "V2") {
+			return true
+		}
+	}
+	return false
+}
+
 // HasCapability return true for a capability which can be either
 // supported or not. Examples include "EphemeralOSDiskSupported",
 // "UltraSSDAvavailable" "EncryptionAtHostSupported",
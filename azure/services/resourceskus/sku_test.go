@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceskus
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+func TestSupportsTrustedLaunch(t *testing.T) {
+	cases := map[string]struct {
+		capabilities []compute.ResourceSkuCapabilities
+		want         bool
+	}{
+		"generation 2": {
+			capabilities: []compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(HyperVGenerations), Value: to.StringPtr("V1,V2")},
+			},
+			want: true,
+		},
+		"generation 1 only": {
+			capabilities: []compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(HyperVGenerations), Value: to.StringPtr("V1")},
+			},
+			want: false,
+		},
+		"trusted launch disabled": {
+			capabilities: []compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(HyperVGenerations), Value: to.StringPtr("V1,V2")},
+				{Name: to.StringPtr(TrustedLaunchDisabled), Value: to.StringPtr("True")},
+			},
+			want: false,
+		},
+		"unknown generations": {
+			capabilities: nil,
+			want:         false,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			sku := SKU{Capabilities: &tc.capabilities}
+			if got := sku.SupportsTrustedLaunch(); got != tc.want {
+				t.Errorf("SupportsTrustedLaunch() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHasEphemeralOSDiskCapacity(t *testing.T) {
+	cases := map[string]struct {
+		capabilities []compute.ResourceSkuCapabilities
+		diskSizeGB   int32
+		want         bool
+	}{
+		"fits in the cache disk": {
+			capabilities: []compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(CachedDiskBytes), Value: to.StringPtr("53687091200")},
+				{Name: to.StringPtr(MaxResourceVolumeMB), Value: to.StringPtr("16384")},
+			},
+			diskSizeGB: 30,
+			want:       true,
+		},
+		"larger than the cache disk": {
+			capabilities: []compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(CachedDiskBytes), Value: to.StringPtr("53687091200")},
+				{Name: to.StringPtr(MaxResourceVolumeMB), Value: to.StringPtr("204800")},
+			},
+			diskSizeGB: 128,
+			want:       false,
+		},
+		"fits in the temporary disk without a cache disk": {
+			capabilities: []compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(CachedDiskBytes), Value: to.StringPtr("0")},
+				{Name: to.StringPtr(MaxResourceVolumeMB), Value: to.StringPtr("153600")},
+			},
+			diskSizeGB: 128,
+			want:       true,
+		},
+		"larger than the temporary disk": {
+			capabilities: []compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(MaxResourceVolumeMB), Value: to.StringPtr("16384")},
+			},
+			diskSizeGB: 30,
+			want:       false,
+		},
+		"neither cache nor temporary disk": {
+			capabilities: nil,
+			diskSizeGB:   30,
+			want:         false,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			sku := SKU{Capabilities: &tc.capabilities}
+			got, err := sku.HasEphemeralOSDiskCapacity(tc.diskSizeGB)
+			if err != nil {
+				t.Fatalf("HasEphemeralOSDiskCapacity() returned an unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("HasEphemeralOSDiskCapacity() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -47,7 +48,7 @@ func newClient(auth azure.Authorizer) *azureClient {
 // newRoleAssignmentClient creates a role assignments client from subscription ID.
 func newRoleAssignmentClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) authorization.RoleAssignmentsClient {
 	roleClient := authorization.NewRoleAssignmentsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&roleClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&roleClient.Client, authorizer, "roleassignments")
 	return roleClient
 }
 
@@ -60,9 +61,10 @@ func newRoleAssignmentClient(subscriptionID string, baseURI string, authorizer a
 // for a resource.
 // roleAssignmentName - the name of the role assignment to create. It can be any valid GUID.
 // parameters - parameters for the role assignment.
-func (ac *azureClient) Create(ctx context.Context, scope string, roleAssignmentName string, parameters authorization.RoleAssignmentCreateParameters) (authorization.RoleAssignment, error) {
+func (ac *azureClient) Create(ctx context.Context, scope string, roleAssignmentName string, parameters authorization.RoleAssignmentCreateParameters) (_ authorization.RoleAssignment, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.AzureClient.Create")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.roleassignments.Create(ctx, scope, roleAssignmentName, parameters)
 }
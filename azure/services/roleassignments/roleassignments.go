@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -44,6 +45,7 @@ type Service struct {
 	client
 	virtualMachinesClient        virtualmachines.Client
 	virtualMachineScaleSetClient scalesets.Client
+	managedClustersClient        managedclusters.Client
 }
 
 // New creates a new service.
@@ -53,6 +55,7 @@ func New(scope RoleAssignmentScope) *Service {
 		client:                       newClient(scope),
 		virtualMachinesClient:        virtualmachines.NewClient(scope),
 		virtualMachineScaleSetClient: scalesets.NewClient(scope),
+		managedClustersClient:        managedclusters.NewClient(scope),
 	}
 }
 
@@ -67,9 +70,11 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			return s.reconcileVM(ctx, roleSpec)
 		case azure.VirtualMachineScaleSet:
 			return s.reconcileVMSS(ctx, roleSpec)
+		case azure.ManagedCluster:
+			return s.reconcileManagedCluster(ctx, roleSpec)
 		default:
-			return errors.Errorf("unexpected resource type %q. Expected one of [%s, %s]", roleSpec.ResourceType,
-				azure.VirtualMachine, azure.VirtualMachineScaleSet)
+			return errors.Errorf("unexpected resource type %q. Expected one of [%s, %s, %s]", roleSpec.ResourceType,
+				azure.VirtualMachine, azure.VirtualMachineScaleSet, azure.ManagedCluster)
 		}
 	}
 	return nil
@@ -84,7 +89,7 @@ func (s *Service) reconcileVM(ctx context.Context, roleSpec azure.RoleAssignment
 		return errors.Wrap(err, "cannot get VM to assign role to system assigned identity")
 	}
 
-	err = s.assignRole(ctx, roleSpec.Name, resultVM.Identity.PrincipalID)
+	err = s.assignRole(ctx, roleSpec, resultVM.Identity.PrincipalID)
 	if err != nil {
 		return errors.Wrap(err, "cannot assign role to VM system assigned identity")
 	}
@@ -103,7 +108,7 @@ func (s *Service) reconcileVMSS(ctx context.Context, roleSpec azure.RoleAssignme
 		return errors.Wrap(err, "cannot get VMSS to assign role to system assigned identity")
 	}
 
-	err = s.assignRole(ctx, roleSpec.Name, resultVMSS.Identity.PrincipalID)
+	err = s.assignRole(ctx, roleSpec, resultVMSS.Identity.PrincipalID)
 	if err != nil {
 		return errors.Wrap(err, "cannot assign role to VMSS system assigned identity")
 	}
@@ -113,20 +118,49 @@ func (s *Service) reconcileVMSS(ctx context.Context, roleSpec azure.RoleAssignme
 	return nil
 }
 
-func (s *Service) assignRole(ctx context.Context, roleAssignmentName string, principalID *string) error {
+func (s *Service) reconcileManagedCluster(ctx context.Context, roleSpec azure.RoleAssignmentSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.reconcileManagedCluster")
+	defer done()
+
+	managedCluster, err := s.managedClustersClient.Get(ctx, s.Scope.ResourceGroup(), roleSpec.MachineName)
+	if err != nil {
+		return errors.Wrap(err, "cannot get managed cluster to assign role to system assigned identity")
+	}
+	if managedCluster.Identity == nil {
+		return errors.Errorf("managed cluster %s has no system assigned identity", roleSpec.MachineName)
+	}
+
+	err = s.assignRole(ctx, roleSpec, managedCluster.Identity.PrincipalID)
+	if err != nil {
+		return errors.Wrap(err, "cannot assign role to managed cluster system assigned identity")
+	}
+
+	log.V(2).Info("successfully created role assignment for generated Identity for managed cluster", "managed cluster", roleSpec.MachineName)
+
+	return nil
+}
+
+func (s *Service) assignRole(ctx context.Context, roleSpec azure.RoleAssignmentSpec, principalID *string) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.assignRole")
 	defer done()
 
-	scope := fmt.Sprintf("/subscriptions/%s/", s.Scope.SubscriptionID())
+	scope := roleSpec.Scope
+	if scope == "" {
+		scope = fmt.Sprintf("/subscriptions/%s/", s.Scope.SubscriptionID())
+	}
 	// Azure built-in roles https://docs.microsoft.com/en-us/azure/role-based-access-control/built-in-roles
-	contributorRoleDefinitionID := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", s.Scope.SubscriptionID(), azureBuiltInContributorID)
+	roleID := roleSpec.RoleDefinitionID
+	if roleID == "" {
+		roleID = azureBuiltInContributorID
+	}
+	roleDefinitionID := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", s.Scope.SubscriptionID(), roleID)
 	params := authorization.RoleAssignmentCreateParameters{
 		Properties: &authorization.RoleAssignmentProperties{
-			RoleDefinitionID: to.StringPtr(contributorRoleDefinitionID),
+			RoleDefinitionID: to.StringPtr(roleDefinitionID),
 			PrincipalID:      principalID,
 		},
 	}
-	_, err := s.client.Create(ctx, scope, roleAssignmentName, params)
+	_, err := s.client.Create(ctx, scope, roleSpec.Name, params)
 	return err
 }
 
@@ -23,11 +23,13 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters/mock_managedclusters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments/mock_roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets/mock_scalesets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines/mock_virtualmachines"
@@ -65,6 +67,27 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 				}))
 			},
 		},
+		{
+			name:          "create a role assignment scoped to the resource group",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, v *mock_virtualmachines.MockClientMockRecorder) {
+				s.SubscriptionID().AnyTimes().Return("12345")
+				s.ResourceGroup().Return("my-rg")
+				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+					{
+						MachineName:  "test-vm",
+						ResourceType: azure.VirtualMachine,
+						Scope:        "/subscriptions/12345/resourceGroups/my-rg",
+					},
+				})
+				v.Get(gomockinternal.AContext(), "my-rg", "test-vm").Return(compute.VirtualMachine{
+					Identity: &compute.VirtualMachineIdentity{
+						PrincipalID: to.StringPtr("000"),
+					},
+				}, nil)
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/resourceGroups/my-rg", gomock.AssignableToTypeOf("uuid"), gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{}))
+			},
+		},
 		{
 			name:          "error getting VM",
 			expectedError: "cannot get VM to assign role to system assigned identity: #: Internal Server Error: StatusCode=500",
@@ -228,3 +251,106 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileRoleAssignmentsManagedCluster(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, mc *mock_managedclusters.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "create a role assignment on the virtual network",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, mc *mock_managedclusters.MockClientMockRecorder) {
+				s.SubscriptionID().AnyTimes().Return("12345")
+				s.ResourceGroup().Return("my-rg")
+				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+					{
+						MachineName:      "test-aks",
+						Name:             "role-assignment-name",
+						ResourceType:     azure.ManagedCluster,
+						Scope:            "/subscriptions/12345/resourceGroups/network-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+						RoleDefinitionID: "4d97b98b-1d4f-4787-a291-c67834d212e7",
+					},
+				})
+				mc.Get(gomockinternal.AContext(), "my-rg", "test-aks").Return(containerservice.ManagedCluster{
+					Identity: &containerservice.ManagedClusterIdentity{
+						PrincipalID: to.StringPtr("000"),
+					},
+				}, nil)
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/resourceGroups/network-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", "role-assignment-name", authorization.RoleAssignmentCreateParameters{
+					Properties: &authorization.RoleAssignmentProperties{
+						RoleDefinitionID: to.StringPtr("/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/4d97b98b-1d4f-4787-a291-c67834d212e7"),
+						PrincipalID:      to.StringPtr("000"),
+					},
+				})
+			},
+		},
+		{
+			name:          "error getting managed cluster",
+			expectedError: "cannot get managed cluster to assign role to system assigned identity: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, mc *mock_managedclusters.MockClientMockRecorder) {
+				s.SubscriptionID().AnyTimes().Return("12345")
+				s.ResourceGroup().Return("my-rg")
+				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+					{
+						MachineName:  "test-aks",
+						ResourceType: azure.ManagedCluster,
+					},
+				})
+				mc.Get(gomockinternal.AContext(), "my-rg", "test-aks").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "managed cluster without identity",
+			expectedError: "managed cluster test-aks has no system assigned identity",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, mc *mock_managedclusters.MockClientMockRecorder) {
+				s.SubscriptionID().AnyTimes().Return("12345")
+				s.ResourceGroup().Return("my-rg")
+				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+					{
+						MachineName:  "test-aks",
+						ResourceType: azure.ManagedCluster,
+					},
+				})
+				mc.Get(gomockinternal.AContext(), "my-rg", "test-aks").Return(containerservice.ManagedCluster{}, nil)
+			},
+		},
+		{
+			name:          "no role assignment for managed virtual networks",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, mc *mock_managedclusters.MockClientMockRecorder) {
+				s.RoleAssignmentSpecs().Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+			clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+			managedClustersMock := mock_managedclusters.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), managedClustersMock.EXPECT())
+
+			s := &Service{
+				Scope:                 scopeMock,
+				client:                clientMock,
+				managedClustersClient: managedClustersMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -49,22 +50,24 @@ func newClient(auth azure.Authorizer) *azureClient {
 // newRouteTablesClient creates a new route tables client from subscription ID.
 func newRouteTablesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.RouteTablesClient {
 	routeTablesClient := network.NewRouteTablesClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&routeTablesClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&routeTablesClient.Client, authorizer, "routetables")
 	return routeTablesClient
 }
 
 // Get gets the specified route table.
-func (ac *azureClient) Get(ctx context.Context, resourceGroupName, rtName string) (network.RouteTable, error) {
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, rtName string) (_ network.RouteTable, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "routetables.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.routetables.Get(ctx, resourceGroupName, rtName, "")
 }
 
 // CreateOrUpdate create or updates a route table in a specified resource group.
-func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, rtName string, rt network.RouteTable) error {
+func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, rtName string, rt network.RouteTable) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "routetables.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.routetables.CreateOrUpdate(ctx, resourceGroupName, rtName, rt)
 	if err != nil {
@@ -79,9 +82,10 @@ func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName str
 }
 
 // Delete deletes the specified route table.
-func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, rtName string) error {
+func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, rtName string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "routetables.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.routetables.Delete(ctx, resourceGroupName, rtName)
 	if err != nil {
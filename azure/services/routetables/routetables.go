@@ -23,15 +23,16 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
-	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // RouteTableScope defines the scope interface for route table service.
 type RouteTableScope interface {
 	azure.ClusterDescriber
-	azure.NetworkDescriber
+	Vnet() *infrav1.VnetSpec
+	SetSubnet(infrav1.SubnetSpec)
 	RouteTableSpecs() []azure.RouteTableSpec
 }
 
@@ -42,7 +43,7 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope *scope.ClusterScope) *Service {
+func New(scope RouteTableScope) *Service {
 	return &Service{
 		Scope:  scope,
 		client: newClient(scope),
@@ -67,7 +68,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			}
 
 			// route table already exists
-			// currently don't support specifying your own routes via spec
+			// currently don't support specifying your own routes via spec, only the route propagation is kept in sync
+			if err := s.reconcileBGPRoutePropagation(ctx, routeTableSpec, existingRouteTable); err != nil {
+				return err
+			}
 			routeTableSpec.Subnet.RouteTable.Name = to.String(existingRouteTable.Name)
 			routeTableSpec.Subnet.RouteTable.ID = to.String(existingRouteTable.ID)
 			s.Scope.SetSubnet(routeTableSpec.Subnet)
@@ -81,8 +85,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			s.Scope.ResourceGroup(),
 			routeTableSpec.Name,
 			network.RouteTable{
-				Location:                   to.StringPtr(s.Scope.Location()),
-				RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{},
+				Location: to.StringPtr(s.Scope.Location()),
+				RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+					DisableBgpRoutePropagation: to.BoolPtr(routeTableSpec.DisableBGPRoutePropagation),
+				},
 			},
 		)
 		if err != nil {
@@ -93,6 +99,28 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// reconcileBGPRoutePropagation updates an existing route table if its propagation of the routes learned over BGP, e.g.
+// from an ExpressRoute gateway, doesn't match the spec.
+func (s *Service) reconcileBGPRoutePropagation(ctx context.Context, routeTableSpec azure.RouteTableSpec, existing network.RouteTable) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "routetables.Service.reconcileBGPRoutePropagation")
+	defer done()
+
+	if existing.RouteTablePropertiesFormat == nil {
+		existing.RouteTablePropertiesFormat = &network.RouteTablePropertiesFormat{}
+	}
+	if to.Bool(existing.DisableBgpRoutePropagation) == routeTableSpec.DisableBGPRoutePropagation {
+		return nil
+	}
+
+	log.V(2).Info("updating BGP route propagation of route table", "route table", routeTableSpec.Name, "disabled", routeTableSpec.DisableBGPRoutePropagation)
+	existing.DisableBgpRoutePropagation = to.BoolPtr(routeTableSpec.DisableBGPRoutePropagation)
+	if err := s.client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), routeTableSpec.Name, existing); err != nil {
+		return errors.Wrapf(err, "failed to update BGP route propagation of route table %s in resource group %s", routeTableSpec.Name, s.Scope.ResourceGroup())
+	}
+	log.V(2).Info("successfully updated BGP route propagation of route table", "route table", routeTableSpec.Name)
+	return nil
+}
+
 // Delete deletes the route table with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "routetables.Service.Delete")
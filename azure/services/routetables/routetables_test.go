@@ -90,7 +90,6 @@ func TestReconcileRouteTables(t *testing.T) {
 						},
 					},
 				})
-				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				s.Location().Return("westus")
@@ -129,8 +128,6 @@ func TestReconcileRouteTables(t *testing.T) {
 						},
 					},
 				})
-				s.ControlPlaneSubnet().AnyTimes().Return(infrav1.SubnetSpec{Name: "control-plane-subnet", Role: infrav1.SubnetControlPlane})
-				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{
 					Name: to.StringPtr("my-cp-routetable"),
@@ -159,6 +156,52 @@ func TestReconcileRouteTables(t *testing.T) {
 				}).Times(1)
 			},
 		},
+		{
+			name: "update the BGP route propagation of an existing route table",
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "",
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder) {
+				s.Vnet().Return(&infrav1.VnetSpec{
+					Name: "my-vnet",
+				})
+				s.ClusterName()
+				s.RouteTableSpecs().Return([]azure.RouteTableSpec{{
+					Name: "my-node-routetable",
+					Subnet: infrav1.SubnetSpec{
+						Name: "node-subnet",
+						Role: infrav1.SubnetNode,
+					},
+					DisableBGPRoutePropagation: true,
+				}})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-node-routetable").Return(network.RouteTable{
+					Name: to.StringPtr("my-node-routetable"),
+					ID:   to.StringPtr("2"),
+					RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+						DisableBgpRoutePropagation: to.BoolPtr(false),
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-node-routetable", network.RouteTable{
+					Name: to.StringPtr("my-node-routetable"),
+					ID:   to.StringPtr("2"),
+					RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+						DisableBgpRoutePropagation: to.BoolPtr(true),
+					},
+				})
+				s.SetSubnet(infrav1.SubnetSpec{
+					Name: "node-subnet",
+					Role: infrav1.SubnetNode,
+					RouteTable: infrav1.RouteTable{
+						ID:   "2",
+						Name: "my-node-routetable",
+					},
+				})
+			},
+		},
 		{
 			name: "fail when getting existing route table",
 			tags: infrav1.Tags{
@@ -179,8 +222,6 @@ func TestReconcileRouteTables(t *testing.T) {
 						Role: infrav1.SubnetControlPlane,
 					},
 				}})
-				s.ControlPlaneSubnet().AnyTimes().Return(infrav1.SubnetSpec{})
-				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-routetable"})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 				m.CreateOrUpdate(gomockinternal.AContext(), gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(network.RouteTable{})).Times(0)
@@ -206,8 +247,6 @@ func TestReconcileRouteTables(t *testing.T) {
 						Role: infrav1.SubnetControlPlane,
 					},
 				}})
-				s.ControlPlaneSubnet().AnyTimes().Return(infrav1.SubnetSpec{})
-				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				s.Location().Return("westus")
@@ -296,7 +335,6 @@ func TestDeleteRouteTable(t *testing.T) {
 						},
 					},
 				})
-				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
 				s.ResourceGroup().Return("my-rg")
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-cp-routetable")
 				s.ResourceGroup().Return("my-rg")
@@ -332,7 +370,6 @@ func TestDeleteRouteTable(t *testing.T) {
 						},
 					},
 				})
-				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
 				s.ResourceGroup().Return("my-rg")
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
 				s.ResourceGroup().Return("my-rg")
@@ -359,7 +396,6 @@ func TestDeleteRouteTable(t *testing.T) {
 						Role: infrav1.SubnetControlPlane,
 					},
 				}})
-				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
@@ -21,6 +21,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
@@ -32,21 +34,27 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
-	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// priorityMixAPIVersion is a compute API version exposing the priority mix policy of scale sets, which is not part of
+// the compute SDK package used by the rest of the provider.
+const priorityMixAPIVersion = "2022-08-01"
+
 // Client wraps go-sdk.
 type Client interface {
 	List(context.Context, string) ([]compute.VirtualMachineScaleSet, error)
 	ListInstances(context.Context, string, string) ([]compute.VirtualMachineScaleSetVM, error)
+	ListFlexibleInstances(context.Context, string, string) ([]compute.VirtualMachine, error)
 	Get(context.Context, string, string) (compute.VirtualMachineScaleSet, error)
 	CreateOrUpdateAsync(context.Context, string, string, compute.VirtualMachineScaleSet) (*infrav1.Future, error)
+	CreateOrUpdateWithPriorityMixAsync(context.Context, string, string, compute.VirtualMachineScaleSet, azure.ScaleSetPriorityMixPolicy) (*infrav1.Future, error)
 	UpdateAsync(context.Context, string, string, compute.VirtualMachineScaleSetUpdate) (*infrav1.Future, error)
 	GetResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachineScaleSet, error)
 	UpdateInstances(context.Context, string, string, []string) error
-	DeleteAsync(context.Context, string, string) (*infrav1.Future, error)
+	DeleteAsync(context.Context, string, string, bool) (*infrav1.Future, error)
 }
 
 type (
@@ -54,6 +62,7 @@ type (
 	AzureClient struct {
 		scalesetvms compute.VirtualMachineScaleSetVMsClient
 		scalesets   compute.VirtualMachineScaleSetsClient
+		vms         compute.VirtualMachinesClient
 	}
 
 	genericScaleSetFuture interface {
@@ -78,27 +87,36 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 	return &AzureClient{
 		scalesetvms: newVirtualMachineScaleSetVMsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
 		scalesets:   newVirtualMachineScaleSetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		vms:         newVirtualMachinesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
 	}
 }
 
 // newVirtualMachineScaleSetVMsClient creates a new vmss VM client from subscription ID.
 func newVirtualMachineScaleSetVMsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineScaleSetVMsClient {
 	c := compute.NewVirtualMachineScaleSetVMsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer, "scalesets")
 	return c
 }
 
 // newVirtualMachineScaleSetsClient creates a new vmss client from subscription ID.
 func newVirtualMachineScaleSetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineScaleSetsClient {
 	c := compute.NewVirtualMachineScaleSetsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer, "scalesets")
+	return c
+}
+
+// newVirtualMachinesClient creates a new VM client from subscription ID.
+func newVirtualMachinesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachinesClient {
+	c := compute.NewVirtualMachinesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer, "scalesets")
 	return c
 }
 
 // ListInstances retrieves information about the model views of a virtual machine scale set.
-func (ac *AzureClient) ListInstances(ctx context.Context, resourceGroupName, vmssName string) ([]compute.VirtualMachineScaleSetVM, error) {
+func (ac *AzureClient) ListInstances(ctx context.Context, resourceGroupName, vmssName string) (_ []compute.VirtualMachineScaleSetVM, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.ListInstances")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	itr, err := ac.scalesetvms.ListComplete(ctx, resourceGroupName, vmssName, "", "", "")
 	if err != nil {
@@ -116,10 +134,38 @@ func (ac *AzureClient) ListInstances(ctx context.Context, resourceGroupName, vms
 	return instances, nil
 }
 
+// ListFlexibleInstances retrieves the VMs of a virtual machine scale set in the Flexible orchestration mode. Those are
+// standard VMs which aren't returned by the scale set VMs API, they are found among the VMs of the resource group by
+// the scale set they reference.
+func (ac *AzureClient) ListFlexibleInstances(ctx context.Context, resourceGroupName, vmssID string) (_ []compute.VirtualMachine, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.ListFlexibleInstances")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	itr, err := ac.vms.ListComplete(ctx, resourceGroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []compute.VirtualMachine
+	for ; itr.NotDone(); err = itr.NextWithContext(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate vms [%w]", err)
+		}
+		vm := itr.Value()
+		if vm.VirtualMachineProperties == nil || vm.VirtualMachineScaleSet == nil || !strings.EqualFold(to.String(vm.VirtualMachineScaleSet.ID), vmssID) {
+			continue
+		}
+		instances = append(instances, vm)
+	}
+	return instances, nil
+}
+
 // List returns all scale sets in a resource group.
-func (ac *AzureClient) List(ctx context.Context, resourceGroupName string) ([]compute.VirtualMachineScaleSet, error) {
+func (ac *AzureClient) List(ctx context.Context, resourceGroupName string) (_ []compute.VirtualMachineScaleSet, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.List")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	itr, err := ac.scalesets.ListComplete(ctx, resourceGroupName)
 	if err != nil {
@@ -138,32 +184,96 @@ func (ac *AzureClient) List(ctx context.Context, resourceGroupName string) ([]co
 }
 
 // Get retrieves information about the model view of a virtual machine scale set.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vmssName string) (compute.VirtualMachineScaleSet, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vmssName string) (_ compute.VirtualMachineScaleSet, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
-	return ac.scalesets.Get(ctx, resourceGroupName, vmssName, "")
+	return ac.scalesets.Get(ctx, resourceGroupName, vmssName, compute.ExpandTypesForGetVMScaleSetsUserData)
 }
 
 // CreateOrUpdateAsync the operation to create or update a virtual machine scale set without waiting for the operation
 // to complete.
-func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vmssName string, vmss compute.VirtualMachineScaleSet) (*infrav1.Future, error) {
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vmssName string, vmss compute.VirtualMachineScaleSet) (_ *infrav1.Future, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.CreateOrUpdateAsync")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.scalesets.CreateOrUpdate(ctx, resourceGroupName, vmssName, vmss)
 	if err != nil {
 		return nil, err
 	}
 
+	return ac.waitForCreateOrUpdate(ctx, resourceGroupName, vmssName, future)
+}
+
+// CreateOrUpdateWithPriorityMixAsync creates or updates a virtual machine scale set blending regular and Spot VMs
+// without waiting for the operation to complete. The PUT request is sent with the priority mix API version, as the
+// priority mix policy can't be expressed with the compute SDK.
+func (ac *AzureClient) CreateOrUpdateWithPriorityMixAsync(ctx context.Context, resourceGroupName, vmssName string, vmss compute.VirtualMachineScaleSet, policy azure.ScaleSetPriorityMixPolicy) (_ *infrav1.Future, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.CreateOrUpdateWithPriorityMixAsync")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	data, err := json.Marshal(vmss)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal scale set")
+	}
+	parameters := map[string]interface{}{}
+	if err := json.Unmarshal(data, &parameters); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal scale set")
+	}
+	properties, ok := parameters["properties"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("scale set has no properties")
+	}
+	priorityMixPolicy := map[string]interface{}{}
+	if policy.BaseRegularPriorityCount != nil {
+		priorityMixPolicy["baseRegularPriorityCount"] = *policy.BaseRegularPriorityCount
+	}
+	if policy.RegularPriorityPercentageAboveBase != nil {
+		priorityMixPolicy["regularPriorityPercentageAboveBase"] = *policy.RegularPriorityPercentageAboveBase
+	}
+	properties["priorityMixPolicy"] = priorityMixPolicy
+
+	pathParameters := map[string]interface{}{
+		"resourceGroupName": autorest.Encode("path", resourceGroupName),
+		"subscriptionId":    autorest.Encode("path", ac.scalesets.SubscriptionID),
+		"vmScaleSetName":    autorest.Encode("path", vmssName),
+	}
+	queryParameters := map[string]interface{}{
+		"api-version": priorityMixAPIVersion,
+	}
+	req, err := autorest.CreatePreparer(
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.AsPut(),
+		autorest.WithBaseURL(ac.scalesets.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Compute/virtualMachineScaleSets/{vmScaleSetName}", pathParameters),
+		autorest.WithJSON(parameters),
+		autorest.WithQueryParameters(queryParameters),
+	).Prepare((&http.Request{}).WithContext(ctx))
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "scalesets.AzureClient", "CreateOrUpdate", nil, "Failure preparing request")
+	}
+	future, err := ac.scalesets.CreateOrUpdateSender(req)
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "scalesets.AzureClient", "CreateOrUpdate", future.Response(), "Failure sending request")
+	}
+
+	return ac.waitForCreateOrUpdate(ctx, resourceGroupName, vmssName, future)
+}
+
+// waitForCreateOrUpdate waits for the creation or update of a scale set to complete, and returns the future of the
+// operation if it didn't complete in time.
+func (ac *AzureClient) waitForCreateOrUpdate(ctx context.Context, resourceGroupName, vmssName string, future compute.VirtualMachineScaleSetsCreateOrUpdateFuture) (*infrav1.Future, error) {
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
 	defer cancel()
 
-	err = future.WaitForCompletionRef(ctx, ac.scalesets.Client)
+	err := future.WaitForCompletionRef(ctx, ac.scalesets.Client)
 	if err != nil {
 		// if an error occurs, return the future.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return converters.SDKToFuture(&future, infrav1.PutFuture, scope.ScalesetsServiceName, vmssName, resourceGroupName)
+		return converters.SDKToFuture(&future, infrav1.PutFuture, ServiceName, vmssName, resourceGroupName)
 	}
 
 	// todo: this returns the result VMSS, we should use it
@@ -180,9 +290,10 @@ func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupNam
 // Parameters:
 //   resourceGroupName - the name of the resource group.
 //   vmssName - the name of the VM scale set to create or update. parameters - the scale set object.
-func (ac *AzureClient) UpdateAsync(ctx context.Context, resourceGroupName, vmssName string, parameters compute.VirtualMachineScaleSetUpdate) (*infrav1.Future, error) {
+func (ac *AzureClient) UpdateAsync(ctx context.Context, resourceGroupName, vmssName string, parameters compute.VirtualMachineScaleSetUpdate) (_ *infrav1.Future, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.UpdateAsync")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.scalesets.Update(ctx, resourceGroupName, vmssName, parameters)
 	if err != nil {
@@ -196,7 +307,7 @@ func (ac *AzureClient) UpdateAsync(ctx context.Context, resourceGroupName, vmssN
 	if err != nil {
 		// if an error occurs, return the future.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return converters.SDKToFuture(&future, infrav1.PatchFuture, scope.ScalesetsServiceName, vmssName, resourceGroupName)
+		return converters.SDKToFuture(&future, infrav1.PatchFuture, ServiceName, vmssName, resourceGroupName)
 	}
 	// todo: this returns the result VMSS, we should use it
 	_, err = future.Result(ac.scalesets)
@@ -206,7 +317,9 @@ func (ac *AzureClient) UpdateAsync(ctx context.Context, resourceGroupName, vmssN
 }
 
 // GetResultIfDone fetches the result of a long-running operation future if it is done.
-func (ac *AzureClient) GetResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachineScaleSet, error) {
+func (ac *AzureClient) GetResultIfDone(ctx context.Context, future *infrav1.Future) (_ compute.VirtualMachineScaleSet, err error) {
+	defer azureerrors.Classify(&err)
+
 	var genericFuture genericScaleSetFuture
 	futureData, err := base64.URLEncoding.DecodeString(future.Data)
 	if err != nil {
@@ -265,9 +378,10 @@ func (ac *AzureClient) GetResultIfDone(ctx context.Context, future *infrav1.Futu
 }
 
 // UpdateInstances update instances of a VM scale set.
-func (ac *AzureClient) UpdateInstances(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string) error {
+func (ac *AzureClient) UpdateInstances(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.UpdateInstances")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	params := compute.VirtualMachineScaleSetVMInstanceRequiredIDs{
 		InstanceIds: &instanceIDs,
@@ -291,11 +405,17 @@ func (ac *AzureClient) UpdateInstances(ctx context.Context, resourceGroupName, v
 // Parameters:
 //   resourceGroupName - the name of the resource group.
 //   vmssName - the name of the VM scale set to create or update. parameters - the scale set object.
-func (ac *AzureClient) DeleteAsync(ctx context.Context, resourceGroupName, vmssName string) (*infrav1.Future, error) {
+//   forceDelete - whether to force delete the VM scale set, falling back to a normal deletion where it isn't available.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, resourceGroupName, vmssName string, forceDelete bool) (_ *infrav1.Future, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.DeleteAsync")
 	defer done()
+	defer azureerrors.Classify(&err)
 
-	future, err := ac.scalesets.Delete(ctx, resourceGroupName, vmssName, to.BoolPtr(false))
+	future, err := ac.scalesets.Delete(ctx, resourceGroupName, vmssName, to.BoolPtr(forceDelete))
+	if err != nil && forceDelete && azure.BadRequest(err) {
+		// force deletion is not available in all the regions, so fall back to a normal deletion.
+		future, err = ac.scalesets.Delete(ctx, resourceGroupName, vmssName, to.BoolPtr(false))
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed deleting vmss named %q", vmssName)
 	}
@@ -307,7 +427,7 @@ func (ac *AzureClient) DeleteAsync(ctx context.Context, resourceGroupName, vmssN
 	if err != nil {
 		// if an error occurs, return the future.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return converters.SDKToFuture(&future, infrav1.DeleteFuture, scope.ScalesetsServiceName, vmssName, resourceGroupName)
+		return converters.SDKToFuture(&future, infrav1.DeleteFuture, ServiceName, vmssName, resourceGroupName)
 	}
 	_, err = future.Result(ac.scalesets)
 
@@ -28,6 +28,7 @@ import (
 	autorest "github.com/Azure/go-autorest/autorest"
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
 )
 
 // MockClient is a mock of Client interface.
@@ -68,19 +69,34 @@ func (mr *MockClientMockRecorder) CreateOrUpdateAsync(arg0, arg1, arg2, arg3 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateAsync", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateAsync), arg0, arg1, arg2, arg3)
 }
 
+// CreateOrUpdateWithPriorityMixAsync mocks base method.
+func (m *MockClient) CreateOrUpdateWithPriorityMixAsync(arg0 context.Context, arg1, arg2 string, arg3 compute.VirtualMachineScaleSet, arg4 azure.ScaleSetPriorityMixPolicy) (*v1beta1.Future, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateWithPriorityMixAsync", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrUpdateWithPriorityMixAsync indicates an expected call of CreateOrUpdateWithPriorityMixAsync.
+func (mr *MockClientMockRecorder) CreateOrUpdateWithPriorityMixAsync(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateWithPriorityMixAsync", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateWithPriorityMixAsync), arg0, arg1, arg2, arg3, arg4)
+}
+
 // DeleteAsync mocks base method.
-func (m *MockClient) DeleteAsync(arg0 context.Context, arg1, arg2 string) (*v1beta1.Future, error) {
+func (m *MockClient) DeleteAsync(arg0 context.Context, arg1, arg2 string, arg3 bool) (*v1beta1.Future, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAsync", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "DeleteAsync", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*v1beta1.Future)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteAsync indicates an expected call of DeleteAsync.
-func (mr *MockClientMockRecorder) DeleteAsync(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) DeleteAsync(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAsync", reflect.TypeOf((*MockClient)(nil).DeleteAsync), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAsync", reflect.TypeOf((*MockClient)(nil).DeleteAsync), arg0, arg1, arg2, arg3)
 }
 
 // Get mocks base method.
@@ -128,6 +144,21 @@ func (mr *MockClientMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockClient)(nil).List), arg0, arg1)
 }
 
+// ListFlexibleInstances mocks base method.
+func (m *MockClient) ListFlexibleInstances(arg0 context.Context, arg1, arg2 string) ([]compute.VirtualMachine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFlexibleInstances", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]compute.VirtualMachine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFlexibleInstances indicates an expected call of ListFlexibleInstances.
+func (mr *MockClientMockRecorder) ListFlexibleInstances(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFlexibleInstances", reflect.TypeOf((*MockClient)(nil).ListFlexibleInstances), arg0, arg1, arg2)
+}
+
 // ListInstances mocks base method.
 func (m *MockClient) ListInstances(arg0 context.Context, arg1, arg2 string) ([]compute.VirtualMachineScaleSetVM, error) {
 	m.ctrl.T.Helper()
@@ -28,22 +28,26 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
-	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/util/generators"
 	"sigs.k8s.io/cluster-api-provider-azure/util/slice"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// ServiceName is the name of the scalesets service.
+const ServiceName = "scalesets"
+
 type (
 	// ScaleSetScope defines the scope interface for a scale sets service.
 	ScaleSetScope interface {
 		azure.ClusterDescriber
 		azure.AsyncStatusUpdater
 		GetBootstrapData(context.Context) (string, error)
+		GetUserData(context.Context) (string, error)
 		GetVMImage(context.Context) (*infrav1.Image, error)
 		SaveVMImageToStatus(*infrav1.Image)
 		MaxSurge() (int, error)
+		ProximityPlacementGroupID() string
 		ScaleSetSpec() azure.ScaleSetSpec
 		VMSSExtensionSpecs() []azure.ExtensionSpec
 		SetAnnotation(string, string)
@@ -84,7 +88,7 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 
 	// check if there is an ongoing long running operation
 	var (
-		future      = s.Scope.GetLongRunningOperationState(s.Scope.ScaleSetSpec().Name, scope.ScalesetsServiceName)
+		future      = s.Scope.GetLongRunningOperationState(s.Scope.ScaleSetSpec().Name, ServiceName)
 		fetchedVMSS *azure.VMSS
 	)
 
@@ -139,7 +143,7 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 	}
 
 	// if we get to here, we have completed any long running VMSS operations (creates / updates)
-	s.Scope.DeleteLongRunningOperationState(s.Scope.ScaleSetSpec().Name, scope.ScalesetsServiceName)
+	s.Scope.DeleteLongRunningOperationState(s.Scope.ScaleSetSpec().Name, ServiceName)
 	return nil
 }
 
@@ -166,7 +170,7 @@ func (s *Service) Delete(ctx context.Context) error {
 	}()
 
 	// check if there is an ongoing long running operation
-	future := s.Scope.GetLongRunningOperationState(vmssSpec.Name, scope.ScalesetsServiceName)
+	future := s.Scope.GetLongRunningOperationState(vmssSpec.Name, ServiceName)
 	if future != nil {
 		// if the operation is not complete this will return an error
 		_, err := s.GetResultIfDone(ctx, future)
@@ -175,13 +179,13 @@ func (s *Service) Delete(ctx context.Context) error {
 		}
 
 		// ScaleSet has been deleted
-		s.Scope.DeleteLongRunningOperationState(vmssSpec.Name, scope.ScalesetsServiceName)
+		s.Scope.DeleteLongRunningOperationState(vmssSpec.Name, ServiceName)
 		return nil
 	}
 
 	// no long running delete operation is active, so delete the ScaleSet
 	log.V(2).Info("deleting VMSS", "scale set", vmssSpec.Name)
-	future, err = s.Client.DeleteAsync(ctx, s.Scope.ResourceGroup(), vmssSpec.Name)
+	future, err = s.Client.DeleteAsync(ctx, s.Scope.ResourceGroup(), vmssSpec.Name, vmssSpec.ForceDelete)
 	if err != nil {
 		if azure.ResourceNotFound(err) {
 			// already deleted
@@ -199,7 +203,7 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 
 	// future is either nil, or the result of the future is complete
-	s.Scope.DeleteLongRunningOperationState(vmssSpec.Name, scope.ScalesetsServiceName)
+	s.Scope.DeleteLongRunningOperationState(vmssSpec.Name, ServiceName)
 	return nil
 }
 
@@ -214,7 +218,17 @@ func (s *Service) createVMSS(ctx context.Context) (*infrav1.Future, error) {
 		return nil, errors.Wrap(err, "failed building VMSS from spec")
 	}
 
-	future, err := s.Client.CreateOrUpdateAsync(ctx, s.Scope.ResourceGroup(), spec.Name, vmss)
+	if capacity := scaleUpBatch(0, spec.Capacity, spec.ScaleUpBatchSize); capacity != spec.Capacity {
+		log.V(2).Info("creating VMSS with the first batch of instances", "scale set", spec.Name, "capacity", capacity, "desiredCapacity", spec.Capacity)
+		vmss.Sku.Capacity = to.Int64Ptr(capacity)
+	}
+
+	var future *infrav1.Future
+	if spec.PriorityMixPolicy != nil {
+		future, err = s.Client.CreateOrUpdateWithPriorityMixAsync(ctx, s.Scope.ResourceGroup(), spec.Name, vmss, *spec.PriorityMixPolicy)
+	} else {
+		future, err = s.Client.CreateOrUpdateAsync(ctx, s.Scope.ResourceGroup(), spec.Name, vmss)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot create VMSS")
 	}
@@ -246,6 +260,16 @@ func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS)
 	}
 
 	hasModelChanges := hasModelModifyingDifferences(infraVMSS, vmss)
+	// The user data is updated in place, so unlike the other model changes it doesn't surge the capacity.
+	hasUserDataChanges := infraVMSS.UserData != to.String(vmss.VirtualMachineProfile.UserData)
+	if hasUserDataChanges && patch.VirtualMachineProfile.UserData == nil {
+		// an empty user data, rather than none, clears the user data of the model.
+		patch.VirtualMachineProfile.UserData = to.StringPtr("")
+	}
+	// The scale-in policy doesn't apply to the existing instances either.
+	hasScaleInPolicyChanges := spec.ScaleInRule != "" && infraVMSS.ScaleInRule != spec.ScaleInRule
+	// Nor does the automatic repairs policy, which only needs the health extension of the instances.
+	hasAutomaticRepairsPolicyChanges := hasAutomaticRepairsPolicyDifferences(infraVMSS, spec.AutomaticRepairsPolicy)
 	if maxSurge > 0 && (hasModelChanges || !infraVMSS.HasEnoughLatestModelOrNotMixedModel()) {
 		// surge capacity with the intention of lowering during instance reconciliation
 		surge := spec.Capacity + int64(maxSurge)
@@ -253,9 +277,17 @@ func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS)
 		patch.Sku.Capacity = to.Int64Ptr(surge)
 	}
 
+	// Large scale ups are split into batches, each of them getting the bootstrap data current when it starts. The next
+	// batch is added once the instances of the previous one are created, as the machine pool is requeued until the
+	// scale set has the desired number of instances.
+	if capacity := scaleUpBatch(infraVMSS.Capacity, *patch.Sku.Capacity, spec.ScaleUpBatchSize); capacity != *patch.Sku.Capacity {
+		log.V(2).Info("scaling up VMSS in batches", "scale set", spec.Name, "capacity", capacity, "desiredCapacity", *patch.Sku.Capacity)
+		patch.Sku.Capacity = to.Int64Ptr(capacity)
+	}
+
 	// If there are no model changes and no increase in the replica count, do not update the VMSS.
 	// Decreases in replica count is handled by deleting AzureMachinePoolMachine instances in the MachinePoolScope
-	if *patch.Sku.Capacity <= infraVMSS.Capacity && !hasModelChanges {
+	if *patch.Sku.Capacity <= infraVMSS.Capacity && !hasModelChanges && !hasUserDataChanges && !hasScaleInPolicyChanges && !hasAutomaticRepairsPolicyChanges {
 		log.V(4).Info("nothing to update on vmss", "scale set", spec.Name, "newReplicas", *patch.Sku.Capacity, "oldReplicas", infraVMSS.Capacity, "hasChanges", hasModelChanges)
 		return nil, nil
 	}
@@ -274,6 +306,27 @@ func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS)
 	return future, err
 }
 
+// scaleUpBatch returns the capacity to scale a scale set up to from its current capacity, adding at most batchSize
+// instances at once. A batch size of 0 doesn't limit the scale up.
+func scaleUpBatch(current, desired, batchSize int64) int64 {
+	if batchSize > 0 && desired-current > batchSize {
+		return current + batchSize
+	}
+	return desired
+}
+
+// hasAutomaticRepairsPolicyDifferences returns true if the automatic repairs policy of the scale set differs from the
+// desired one. A policy left unspecified is not managed.
+func hasAutomaticRepairsPolicyDifferences(infraVMSS *azure.VMSS, policy *azure.ScaleSetAutomaticRepairsPolicy) bool {
+	if policy == nil {
+		return false
+	}
+	if infraVMSS.AutomaticRepairs != policy.Enabled {
+		return true
+	}
+	return policy.GracePeriod != nil && infraVMSS.AutomaticRepairsGracePeriod != *policy.GracePeriod
+}
+
 func hasModelModifyingDifferences(infraVMSS *azure.VMSS, vmss compute.VirtualMachineScaleSet) bool {
 	other := converters.SDKToVMSS(vmss, []compute.VirtualMachineScaleSetVM{})
 	return infraVMSS.HasModelChanges(*other)
@@ -315,17 +368,33 @@ func (s *Service) validateSpec(ctx context.Context) error {
 		return azure.WithTerminalError(fmt.Errorf("vm size %s does not support ephemeral os. select a different vm size or disable ephemeral os", spec.Size))
 	}
 
-	if spec.SecurityProfile != nil && !sku.HasCapability(resourceskus.EncryptionAtHost) {
+	// check that the ephemeral OS disk fits in the cache or temporary disk of the vm size
+	if spec.OSDisk.DiffDiskSettings != nil && spec.OSDisk.DiskSizeGB != nil {
+		fits, err := sku.HasEphemeralOSDiskCapacity(*spec.OSDisk.DiskSizeGB)
+		if err != nil {
+			return azure.WithTerminalError(errors.Wrap(err, "failed to validate the ephemeral os disk capacity"))
+		}
+		if !fits {
+			return azure.WithTerminalError(fmt.Errorf("vm size %s does not have enough cache or temporary disk space for an ephemeral os disk of %d GB. select a larger vm size or a smaller os disk", spec.Size, *spec.OSDisk.DiskSizeGB))
+		}
+	}
+
+	if to.Bool(spec.AcceleratedNetworking) && !sku.HasCapability(resourceskus.AcceleratedNetworking) {
+		return azure.WithTerminalError(fmt.Errorf("vm size %s does not support accelerated networking. select a different vm size or disable accelerated networking", spec.Size))
+	}
+
+	if spec.SecurityProfile != nil && to.Bool(spec.SecurityProfile.EncryptionAtHost) && !sku.HasCapability(resourceskus.EncryptionAtHost) {
 		return azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM type %s", spec.Size))
 	}
 
+	location := s.Scope.Location()
+	zones, err := s.resourceSKUCache.GetZones(ctx, location)
+	if err != nil {
+		return azure.WithTerminalError(errors.Wrapf(err, "failed to get the zones for location %s", location))
+	}
+
 	// check the support for ultra disks based on location and vm size
 	for _, disks := range spec.DataDisks {
-		location := s.Scope.Location()
-		zones, err := s.resourceSKUCache.GetZones(ctx, location)
-		if err != nil {
-			return azure.WithTerminalError(errors.Wrapf(err, "failed to get the zones for location %s", location))
-		}
 
 		for _, zone := range zones {
 			if disks.ManagedDisk != nil && disks.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) && !sku.HasLocationCapability(resourceskus.UltraSSDAvailable, location, zone) {
@@ -334,6 +403,36 @@ func (s *Service) validateSpec(ctx context.Context) error {
 		}
 	}
 
+	// check that zone redundant disks can be replicated across the availability zones of the location
+	if len(zones) == 0 {
+		managedDisks := []*infrav1.ManagedDiskParameters{spec.OSDisk.ManagedDisk}
+		for _, disk := range spec.DataDisks {
+			managedDisks = append(managedDisks, disk.ManagedDisk)
+		}
+		for _, managedDisk := range managedDisks {
+			if managedDisk.IsZoneRedundant() {
+				return azure.WithTerminalError(fmt.Errorf("location %s does not have availability zones to replicate disks with storage account type %s across. select a locally redundant storage account type", location, managedDisk.StorageAccountType))
+			}
+		}
+	}
+
+	// check the support for write accelerator based on vm size
+	var writeAcceleratorDisks int64
+	for _, disk := range spec.DataDisks {
+		if to.Bool(disk.WriteAcceleratorEnabled) {
+			writeAcceleratorDisks++
+		}
+	}
+	if writeAcceleratorDisks > 0 {
+		writeAcceleratorCapability, err := sku.HasCapabilityWithCapacity(resourceskus.MaxWriteAcceleratorDisksAllowed, writeAcceleratorDisks)
+		if err != nil {
+			return azure.WithTerminalError(errors.Wrap(err, "failed to validate the write accelerator capability"))
+		}
+		if !writeAcceleratorCapability {
+			return azure.WithTerminalError(fmt.Errorf("vm size %s does not support write accelerator on %d data disks. select a different vm size or disable write accelerator", spec.Size, writeAcceleratorDisks))
+		}
+	}
+
 	// Checking if selected availability zones are available selected VM type in location
 	azsInLocation, err := s.resourceSKUCache.GetZonesWithVMSize(ctx, spec.Size, s.Scope.Location())
 	if err != nil {
@@ -365,6 +464,9 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 	}
 
 	extensions := s.generateExtensions()
+	if healthExtension := getHealthExtension(vmssSpec); healthExtension != nil {
+		extensions = append(extensions, *healthExtension)
+	}
 
 	storageProfile, err := s.generateStorageProfile(ctx, vmssSpec, sku)
 	if err != nil {
@@ -397,6 +499,11 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 		return compute.VirtualMachineScaleSet{}, err
 	}
 
+	userData, err := s.Scope.GetUserData(ctx)
+	if err != nil {
+		return compute.VirtualMachineScaleSet{}, errors.Wrap(err, "failed to get user data")
+	}
+
 	vmss := compute.VirtualMachineScaleSet{
 		Location: to.StringPtr(s.Scope.Location()),
 		Sku: &compute.Sku{
@@ -408,19 +515,13 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 		Plan:  s.generateImagePlan(ctx),
 		VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
 			SinglePlacementGroup: to.BoolPtr(false),
-			UpgradePolicy: &compute.UpgradePolicy{
-				Mode: compute.UpgradeModeManual,
-			},
-			Overprovision: to.BoolPtr(false),
+			UpgradePolicy:        getUpgradePolicy(vmssSpec),
+			Overprovision:        to.BoolPtr(false),
 			VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
-				OsProfile:       osProfile,
-				StorageProfile:  storageProfile,
-				SecurityProfile: securityProfile,
-				DiagnosticsProfile: &compute.DiagnosticsProfile{
-					BootDiagnostics: &compute.BootDiagnostics{
-						Enabled: to.BoolPtr(true),
-					},
-				},
+				OsProfile:          osProfile,
+				StorageProfile:     storageProfile,
+				SecurityProfile:    securityProfile,
+				DiagnosticsProfile: converters.DiagnosticsToSDK(vmssSpec.Diagnostics),
 				NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
 					NetworkInterfaceConfigurations: &[]compute.VirtualMachineScaleSetNetworkConfiguration{
 						{
@@ -456,6 +557,62 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 		},
 	}
 
+	if policy := vmssSpec.AutomaticRepairsPolicy; policy != nil {
+		vmss.AutomaticRepairsPolicy = &compute.AutomaticRepairsPolicy{
+			Enabled:     to.BoolPtr(policy.Enabled),
+			GracePeriod: policy.GracePeriod,
+		}
+	}
+
+	if vmssSpec.ScaleInRule != "" {
+		vmss.ScaleInPolicy = &compute.ScaleInPolicy{
+			Rules: &[]compute.VirtualMachineScaleSetScaleInRules{compute.VirtualMachineScaleSetScaleInRules(vmssSpec.ScaleInRule)},
+		}
+	}
+
+	if vmssSpec.OrchestrationMode == string(compute.OrchestrationModeFlexible) {
+		// Flexible scale sets spread their VMs across fault domains at best effort and manage them as standard VMs: the
+		// placement group, overprovisioning and upgrade policy settings of Uniform scale sets don't apply to them, and
+		// their network interfaces are created as standalone resources by the newer network API.
+		vmss.OrchestrationMode = compute.OrchestrationModeFlexible
+		vmss.PlatformFaultDomainCount = to.Int32Ptr(1)
+		vmss.SinglePlacementGroup = nil
+		vmss.Overprovision = nil
+		vmss.UpgradePolicy = nil
+		vmss.VirtualMachineProfile.NetworkProfile.NetworkAPIVersion = compute.NetworkAPIVersionTwoZeroTwoZeroHyphenMinusOneOneHyphenMinusZeroOne
+	}
+
+	if vmssSpec.AllZones {
+		zones, err := s.resourceSKUCache.GetZonesWithVMSize(ctx, vmssSpec.Size, s.Scope.Location())
+		if err != nil {
+			return compute.VirtualMachineScaleSet{}, errors.Wrapf(err, "failed to get zones for VM type %s in location %s", vmssSpec.Size, s.Scope.Location())
+		}
+		vmss.Zones = to.StringSlicePtr(withoutZones(zones, vmssSpec.ExcludedZones))
+	}
+
+	// Azure rejects zone balance for scale sets which aren't spread across several zones
+	if vmssSpec.ZoneBalance != nil && vmss.Zones != nil && len(*vmss.Zones) > 1 {
+		vmss.ZoneBalance = vmssSpec.ZoneBalance
+	}
+
+	if vmssSpec.PlatformFaultDomainCount != nil {
+		vmss.PlatformFaultDomainCount = vmssSpec.PlatformFaultDomainCount
+	}
+
+	if vmssSpec.ProximityPlacementGroupID != "" {
+		vmss.VirtualMachineScaleSetProperties.ProximityPlacementGroup = &compute.SubResource{
+			ID: to.StringPtr(vmssSpec.ProximityPlacementGroupID),
+		}
+	}
+
+	if vmssSpec.CapacityReservationGroupID != "" {
+		vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.CapacityReservation = &compute.CapacityReservationProfile{
+			CapacityReservationGroup: &compute.SubResource{
+				ID: to.StringPtr(vmssSpec.CapacityReservationGroupID),
+			},
+		}
+	}
+
 	// Assign Identity to VMSS
 	if vmssSpec.Identity == infrav1.VMIdentitySystemAssigned {
 		vmss.Identity = &compute.VirtualMachineScaleSetIdentity{
@@ -480,6 +637,10 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 		}
 	}
 
+	if userData != "" {
+		vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.UserData = to.StringPtr(userData)
+	}
+
 	if vmssSpec.TerminateNotificationTimeout != nil {
 		vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.ScheduledEventsProfile = &compute.ScheduledEventsProfile{
 			TerminateNotificationProfile: &compute.TerminateNotificationProfile{
@@ -511,12 +672,7 @@ func (s *Service) getVirtualMachineScaleSet(ctx context.Context, vmssName string
 		return nil, errors.Wrap(err, "failed to get existing vmss")
 	}
 
-	vmssInstances, err := s.Client.ListInstances(ctx, s.Scope.ResourceGroup(), vmssName)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to list instances")
-	}
-
-	return converters.SDKToVMSS(vmss, vmssInstances), nil
+	return s.withInstances(ctx, s.Scope.ResourceGroup(), vmssName, vmss)
 }
 
 // getVirtualMachineScaleSetIfDone gets a Virtual Machine Scale Set and its instances from Azure if the future is completed.
@@ -529,7 +685,29 @@ func (s *Service) getVirtualMachineScaleSetIfDone(ctx context.Context, future *i
 		return nil, errors.Wrap(err, "failed to get result from future")
 	}
 
-	vmssInstances, err := s.Client.ListInstances(ctx, future.ResourceGroup, future.Name)
+	return s.withInstances(ctx, future.ResourceGroup, future.Name, vmss)
+}
+
+// withInstances converts a Virtual Machine Scale Set along with its instances. The instances of a scale set in the
+// Flexible orchestration mode are standard VMs, which the scale set VMs API doesn't return.
+func (s *Service) withInstances(ctx context.Context, resourceGroup, vmssName string, vmss compute.VirtualMachineScaleSet) (*azure.VMSS, error) {
+	if vmss.VirtualMachineScaleSetProperties != nil && vmss.OrchestrationMode == compute.OrchestrationModeFlexible {
+		vms, err := s.Client.ListFlexibleInstances(ctx, resourceGroup, to.String(vmss.ID))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list instances")
+		}
+
+		result := converters.SDKToVMSS(vmss, nil)
+		if len(vms) > 0 {
+			result.Instances = make([]azure.VMSSVM, len(vms))
+			for i, vm := range vms {
+				result.Instances[i] = *converters.SDKVMToVMSSVM(vm)
+			}
+		}
+		return result, nil
+	}
+
+	vmssInstances, err := s.Client.ListInstances(ctx, resourceGroup, vmssName)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to list instances")
 	}
@@ -555,6 +733,66 @@ func (s *Service) generateExtensions() []compute.VirtualMachineScaleSetExtension
 	return extensions
 }
 
+// getUpgradePolicy returns the upgrade policy of the scale set, which defaults to the Manual mode.
+func getUpgradePolicy(vmssSpec azure.ScaleSetSpec) *compute.UpgradePolicy {
+	upgradePolicy := &compute.UpgradePolicy{
+		Mode: compute.UpgradeModeManual,
+	}
+	policy := vmssSpec.UpgradePolicy
+	if policy == nil {
+		return upgradePolicy
+	}
+
+	if policy.Mode != "" {
+		upgradePolicy.Mode = compute.UpgradeMode(policy.Mode)
+	}
+	if upgradePolicy.Mode == compute.UpgradeModeRolling {
+		upgradePolicy.RollingUpgradePolicy = &compute.RollingUpgradePolicy{
+			MaxBatchInstancePercent:             policy.MaxBatchInstancePercent,
+			MaxUnhealthyInstancePercent:         policy.MaxUnhealthyInstancePercent,
+			MaxUnhealthyUpgradedInstancePercent: policy.MaxUnhealthyUpgradedInstancePercent,
+			PauseTimeBetweenBatches:             policy.PauseTimeBetweenBatches,
+		}
+	}
+	if policy.AutomaticOSUpgrade {
+		upgradePolicy.AutomaticOSUpgradePolicy = &compute.AutomaticOSUpgradePolicy{
+			EnableAutomaticOSUpgrade: to.BoolPtr(true),
+		}
+	}
+	return upgradePolicy
+}
+
+// getHealthExtension returns the Application Health extension reporting the health of the instances to Azure during
+// upgrades, or nil if the scale set has no health probe.
+func getHealthExtension(vmssSpec azure.ScaleSetSpec) *compute.VirtualMachineScaleSetExtension {
+	if vmssSpec.UpgradePolicy == nil || vmssSpec.UpgradePolicy.HealthProbe == nil {
+		return nil
+	}
+	probe := vmssSpec.UpgradePolicy.HealthProbe
+
+	extensionType := "ApplicationHealthLinux"
+	if vmssSpec.OSDisk.OSType == azure.WindowsOS {
+		extensionType = "ApplicationHealthWindows"
+	}
+	settings := map[string]interface{}{
+		"protocol": probe.Protocol,
+		"port":     probe.Port,
+	}
+	if probe.RequestPath != "" {
+		settings["requestPath"] = probe.RequestPath
+	}
+
+	return &compute.VirtualMachineScaleSetExtension{
+		Name: to.StringPtr(extensionType),
+		VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+			Publisher:          to.StringPtr("Microsoft.ManagedServices"),
+			Type:               to.StringPtr(extensionType),
+			TypeHandlerVersion: to.StringPtr("1.0"),
+			Settings:           settings,
+		},
+	}
+}
+
 // generateStorageProfile generates a pointer to a compute.VirtualMachineScaleSetStorageProfile which can utilized for VM creation.
 func (s *Service) generateStorageProfile(ctx context.Context, vmssSpec azure.ScaleSetSpec, sku resourceskus.SKU) (*compute.VirtualMachineScaleSetStorageProfile, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.generateStorageProfile")
@@ -564,6 +802,7 @@ func (s *Service) generateStorageProfile(ctx context.Context, vmssSpec azure.Sca
 		OsDisk: &compute.VirtualMachineScaleSetOSDisk{
 			OsType:       compute.OperatingSystemTypes(vmssSpec.OSDisk.OSType),
 			CreateOption: compute.DiskCreateOptionTypesFromImage,
+			Caching:      compute.CachingTypes(vmssSpec.OSDisk.CachingType),
 			DiskSizeGB:   vmssSpec.OSDisk.DiskSizeGB,
 		},
 	}
@@ -592,10 +831,12 @@ func (s *Service) generateStorageProfile(ctx context.Context, vmssSpec azure.Sca
 	dataDisks := make([]compute.VirtualMachineScaleSetDataDisk, len(vmssSpec.DataDisks))
 	for i, disk := range vmssSpec.DataDisks {
 		dataDisks[i] = compute.VirtualMachineScaleSetDataDisk{
-			CreateOption: compute.DiskCreateOptionTypesEmpty,
-			DiskSizeGB:   to.Int32Ptr(disk.DiskSizeGB),
-			Lun:          disk.Lun,
-			Name:         to.StringPtr(azure.GenerateDataDiskName(vmssSpec.Name, disk.NameSuffix)),
+			CreateOption:            compute.DiskCreateOptionTypesEmpty,
+			Caching:                 compute.CachingTypes(disk.CachingType),
+			DiskSizeGB:              to.Int32Ptr(disk.DiskSizeGB),
+			Lun:                     disk.Lun,
+			Name:                    to.StringPtr(azure.GenerateDataDiskName(vmssSpec.Name, disk.NameSuffix)),
+			WriteAcceleratorEnabled: disk.WriteAcceleratorEnabled,
 		}
 
 		if disk.ManagedDisk != nil {
@@ -637,8 +878,12 @@ func (s *Service) generateOSProfile(ctx context.Context, vmssSpec azure.ScaleSet
 		return nil, errors.Wrap(err, "failed to retrieve bootstrap data")
 	}
 
+	computerNamePrefix := vmssSpec.Name
+	if vmssSpec.ComputerNamePrefix != "" {
+		computerNamePrefix = vmssSpec.ComputerNamePrefix
+	}
 	osProfile := &compute.VirtualMachineScaleSetOSProfile{
-		ComputerNamePrefix: to.StringPtr(vmssSpec.Name),
+		ComputerNamePrefix: to.StringPtr(computerNamePrefix),
 		AdminUsername:      to.StringPtr(azure.DefaultUserName),
 		CustomData:         to.StringPtr(bootstrapData),
 	}
@@ -727,11 +972,35 @@ func getSecurityProfile(vmssSpec azure.ScaleSetSpec, sku resourceskus.SKU) (*com
 		return nil, nil
 	}
 
-	if !sku.HasCapability(resourceskus.EncryptionAtHost) {
+	if to.Bool(vmssSpec.SecurityProfile.EncryptionAtHost) && !sku.HasCapability(resourceskus.EncryptionAtHost) {
 		return nil, azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM type %s", vmssSpec.Size))
 	}
 
-	return &compute.SecurityProfile{
-		EncryptionAtHost: to.BoolPtr(*vmssSpec.SecurityProfile.EncryptionAtHost),
-	}, nil
+	if vmssSpec.SecurityProfile.SecurityType == infrav1.SecurityTypesTrustedLaunch && !sku.SupportsTrustedLaunch() {
+		return nil, azure.WithTerminalError(errors.Errorf("trusted launch is not supported for VM type %s", vmssSpec.Size))
+	}
+
+	return converters.SecurityProfileToSDK(vmssSpec.SecurityProfile), nil
+}
+
+// withoutZones returns the zones which are not excluded. All the zones are returned if they are all excluded, as the
+// scale set couldn't be placed otherwise.
+func withoutZones(zones, excluded []string) []string {
+	if len(excluded) == 0 {
+		return zones
+	}
+	isExcluded := make(map[string]bool, len(excluded))
+	for _, zone := range excluded {
+		isExcluded[zone] = true
+	}
+	var remaining []string
+	for _, zone := range zones {
+		if !isExcluded[zone] {
+			remaining = append(remaining, zone)
+		}
+	}
+	if len(remaining) == 0 {
+		return zones
+	}
+	return remaining
 }
@@ -27,20 +27,14 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/resource"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
-	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets/mock_scalesets"
-	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
-	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
-	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 const (
@@ -55,42 +49,15 @@ func init() {
 
 func TestNewService(t *testing.T) {
 	g := NewGomegaWithT(t)
-	scheme := runtime.NewScheme()
-	_ = clusterv1.AddToScheme(scheme)
-	_ = infrav1.AddToScheme(scheme)
-	_ = infrav1exp.AddToScheme(scheme)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
 
-	cluster := &clusterv1.Cluster{
-		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
-	}
-	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
-	s, err := scope.NewClusterScope(context.Background(), scope.ClusterScopeParams{
-		AzureClients: scope.AzureClients{
-			Authorizer: autorest.NullAuthorizer{},
-		},
-		Client:  client,
-		Cluster: cluster,
-		AzureCluster: &infrav1.AzureCluster{
-			Spec: infrav1.AzureClusterSpec{
-				Location: "test-location",
-				ResourceGroup:  "my-rg",
-				SubscriptionID: "123",
-				NetworkSpec: infrav1.NetworkSpec{
-					Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
-				},
-			},
-		},
-	})
-	g.Expect(err).NotTo(HaveOccurred())
+	scopeMock := mock_scalesets.NewMockScaleSetScope(mockCtrl)
+	scopeMock.EXPECT().SubscriptionID().AnyTimes().Return("123")
+	scopeMock.EXPECT().BaseURI().AnyTimes().Return("https://localhost/")
+	scopeMock.EXPECT().Authorizer().AnyTimes().Return(autorest.NullAuthorizer{})
 
-	mps, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
-		Client:           client,
-		MachinePool:      new(clusterv1exp.MachinePool),
-		AzureMachinePool: new(infrav1exp.AzureMachinePool),
-		ClusterScope:     s,
-	})
-	g.Expect(err).NotTo(HaveOccurred())
-	actual := NewService(mps, resourceskus.NewStaticCache(nil, ""))
+	actual := NewService(scopeMock, resourceskus.NewStaticCache(nil, ""))
 	g.Expect(actual).ToNot(BeNil())
 }
 
@@ -164,6 +131,54 @@ func TestGetExistingVMSS(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			name:     "get existing flexible vmss",
+			vmssName: "my-vmss",
+			result: &azure.VMSS{
+				ID:                "my-id",
+				Name:              "my-vmss",
+				State:             "Succeeded",
+				Sku:               "Standard_D2",
+				Capacity:          int64(1),
+				OrchestrationMode: "Flexible",
+				Instances: []azure.VMSSVM{
+					{
+						ID:         "my-vm-id",
+						InstanceID: "my-vmss_1a2b3c4d",
+						Name:       "instance-000001",
+						State:      "Succeeded",
+					},
+				},
+			},
+			expectedError: "",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(compute.VirtualMachineScaleSet{
+					ID:   to.StringPtr("my-id"),
+					Name: to.StringPtr("my-vmss"),
+					Sku: &compute.Sku{
+						Capacity: to.Int64Ptr(1),
+						Name:     to.StringPtr("Standard_D2"),
+					},
+					VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
+						OrchestrationMode: compute.OrchestrationModeFlexible,
+						ProvisioningState: to.StringPtr("Succeeded"),
+					},
+				}, nil)
+				m.ListFlexibleInstances(gomockinternal.AContext(), "my-rg", "my-id").Return([]compute.VirtualMachine{
+					{
+						ID:   to.StringPtr("my-vm-id"),
+						Name: to.StringPtr("my-vmss_1a2b3c4d"),
+						VirtualMachineProperties: &compute.VirtualMachineProperties{
+							ProvisioningState: to.StringPtr("Succeeded"),
+							OsProfile: &compute.OSProfile{
+								ComputerName: to.StringPtr("instance-000001"),
+							},
+						},
+					},
+				}, nil)
+			},
+		},
 		{
 			name:          "list instances fails",
 			vmssName:      "my-vmss",
@@ -262,11 +277,19 @@ func TestReconcileVMSS(t *testing.T) {
 			expectedError: "",
 			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				defaultSpec := newDefaultVMSSSpec()
+				defaultSpec.DataDisks = append(defaultSpec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
 				s.ScaleSetSpec().Return(defaultSpec).AnyTimes()
 				createdVMSS := newDefaultVMSS("VM_SIZE")
 				instances := newDefaultInstances()
 				_ = setupDefaultVMSSInProgressOperationDoneExpectations(s, m, createdVMSS, instances)
-				s.DeleteLongRunningOperationState(defaultSpec.Name, scope.ScalesetsServiceName)
+				s.DeleteLongRunningOperationState(defaultSpec.Name, ServiceName)
 			},
 		},
 		{
@@ -274,11 +297,19 @@ func TestReconcileVMSS(t *testing.T) {
 			expectedError: "",
 			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				defaultSpec := newWindowsVMSSSpec()
+				defaultSpec.DataDisks = append(defaultSpec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
 				s.ScaleSetSpec().Return(defaultSpec).AnyTimes()
 				createdVMSS := newDefaultWindowsVMSS()
 				instances := newDefaultInstances()
 				_ = setupDefaultVMSSInProgressOperationDoneExpectations(s, m, createdVMSS, instances)
-				s.DeleteLongRunningOperationState(defaultSpec.Name, scope.ScalesetsServiceName)
+				s.DeleteLongRunningOperationState(defaultSpec.Name, ServiceName)
 			},
 		},
 		{
@@ -386,6 +417,30 @@ func TestReconcileVMSS(t *testing.T) {
 				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
 			},
 		},
+		{
+			name:          "should start creating a vmss with user data",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				s.GetUserData(gomockinternal.AContext()).Return("ZmFrZS11c2VyLWRhdGE=", nil)
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE")
+				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.VirtualMachineProfile.UserData = to.StringPtr("ZmFrZS11c2VyLWRhdGE=")
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
+			},
+		},
 		{
 			name:          "can start creating a vmss with user assigned identity",
 			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
@@ -439,6 +494,183 @@ func TestReconcileVMSS(t *testing.T) {
 				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE_EAH"), putFuture)
 			},
 		},
+		{
+			name:          "should start creating a vmss in a proximity placement group",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				spec.ProximityPlacementGroupID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE")
+				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.VirtualMachineScaleSetProperties.ProximityPlacementGroup = &compute.SubResource{
+					ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg"),
+				}
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
+			},
+		},
+		{
+			name:          "should start creating a vmss storing boot diagnostics in a user managed storage account",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				spec.Diagnostics = &infrav1.Diagnostics{
+					Boot: &infrav1.BootDiagnostics{
+						StorageAccountType: infrav1.UserManagedDiagnosticsStorage,
+						UserManaged:        &infrav1.UserManagedBootDiagnostics{StorageAccountURI: "https://fake.blob.core.windows.net/"},
+					},
+				}
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE")
+				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.VirtualMachineProfile.DiagnosticsProfile.BootDiagnostics.StorageURI = to.StringPtr("https://fake.blob.core.windows.net/")
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
+			},
+		},
+		{
+			name:          "should start creating a vmss in the Flexible orchestration mode",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				spec.OrchestrationMode = "Flexible"
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE")
+				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.OrchestrationMode = compute.OrchestrationModeFlexible
+				vmss.PlatformFaultDomainCount = to.Int32Ptr(1)
+				vmss.SinglePlacementGroup = nil
+				vmss.Overprovision = nil
+				vmss.UpgradePolicy = nil
+				vmss.VirtualMachineProfile.NetworkProfile.NetworkAPIVersion = compute.NetworkAPIVersionTwoZeroTwoZeroHyphenMinusOneOneHyphenMinusZeroOne
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
+			},
+		},
+		{
+			name:          "should start creating a vmss with a mix of regular and spot vms",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				spec.OrchestrationMode = "Flexible"
+				spec.SpotVMOptions = &infrav1.SpotVMOptions{}
+				spec.PriorityMixPolicy = &azure.ScaleSetPriorityMixPolicy{
+					BaseRegularPriorityCount:           to.Int32Ptr(2),
+					RegularPriorityPercentageAboveBase: to.Int32Ptr(25),
+				}
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE")
+				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.Priority = compute.VirtualMachinePriorityTypesSpot
+				vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.EvictionPolicy = compute.VirtualMachineEvictionPolicyTypesDeallocate
+				vmss.OrchestrationMode = compute.OrchestrationModeFlexible
+				vmss.PlatformFaultDomainCount = to.Int32Ptr(1)
+				vmss.SinglePlacementGroup = nil
+				vmss.Overprovision = nil
+				vmss.UpgradePolicy = nil
+				vmss.VirtualMachineProfile.NetworkProfile.NetworkAPIVersion = compute.NetworkAPIVersionTwoZeroTwoZeroHyphenMinusOneOneHyphenMinusZeroOne
+				m.CreateOrUpdateWithPriorityMixAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss), *spec.PriorityMixPolicy).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
+			},
+		},
+		{
+			name:          "should start creating a vmss spread across all zones",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				spec.FailureDomains = nil
+				spec.AllZones = true
+				spec.ZoneBalance = to.BoolPtr(true)
+				spec.PlatformFaultDomainCount = to.Int32Ptr(2)
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE")
+				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.Zones = &[]string{"1", "3"}
+				vmss.ZoneBalance = to.BoolPtr(true)
+				vmss.PlatformFaultDomainCount = to.Int32Ptr(2)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
+			},
+		},
+		{
+			name:          "should start creating a vmss from a capacity reservation group",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				spec.CapacityReservationGroupID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE")
+				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.CapacityReservation = &compute.CapacityReservationProfile{
+					CapacityReservationGroup: &compute.SubResource{
+						ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"),
+					},
+				}
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
+			},
+		},
 		{
 			name:          "creating a vmss with encryption at host enabled for unsupported VM type fails",
 			expectedError: "reconcile error that cannot be recovered occurred: encryption at host is not supported for VM type VM_SIZE. Object will not be requeued",
@@ -493,6 +725,173 @@ func TestReconcileVMSS(t *testing.T) {
 				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
 			},
 		},
+		{
+			name:          "should scale up a scale set in batches",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PATCH on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.Capacity = 10
+				spec.ScaleUpBatchSize = 3
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+
+				setupDefaultVMSSUpdateExpectations(s)
+				existingVMSS := newDefaultExistingVMSS("VM_SIZE")
+				existingVMSS.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				existingVMSS.Sku.Capacity = to.Int64Ptr(2)
+				instances := newDefaultInstances()
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(existingVMSS, nil)
+				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+
+				clone := newDefaultExistingVMSS("VM_SIZE")
+				clone.Sku.Capacity = to.Int64Ptr(5)
+				clone.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+
+				patchVMSS, err := getVMSSUpdateFromVMSS(clone)
+				g.Expect(err).NotTo(HaveOccurred())
+				patchVMSS.VirtualMachineProfile.StorageProfile.ImageReference.Version = to.StringPtr("2.0")
+				patchVMSS.VirtualMachineProfile.NetworkProfile = nil
+				m.UpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(patchVMSS)).
+					Return(patchFuture, nil)
+				s.SetLongRunningOperationState(patchFuture)
+				m.GetResultIfDone(gomockinternal.AContext(), patchFuture).Return(compute.VirtualMachineScaleSet{}, azure.NewOperationNotDoneError(patchFuture))
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(clone, nil)
+				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+			},
+		},
+		{
+			name:          "should update the user data of an existing scale set without surging",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PATCH on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				s.GetUserData(gomockinternal.AContext()).Return("ZmFrZS11c2VyLWRhdGE=", nil)
+				setupDefaultVMSSExpectations(s)
+				s.SetProviderID(azure.ProviderIDPrefix + "vmss-id")
+				s.GetLongRunningOperationState(defaultVMSSName, ServiceName).Return(nil)
+				s.MaxSurge().Return(1, nil)
+				s.SetVMSSState(gomock.Any())
+				existingVMSS := newDefaultExistingVMSS("VM_SIZE")
+				existingVMSS.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				existingVMSS.VirtualMachineProfile.UserData = to.StringPtr("b2xkLXVzZXItZGF0YQ==")
+				instances := newDefaultInstances()
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(existingVMSS, nil)
+				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+
+				clone := newDefaultExistingVMSS("VM_SIZE")
+				clone.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				clone.VirtualMachineProfile.UserData = to.StringPtr("ZmFrZS11c2VyLWRhdGE=")
+				patchVMSS, err := getVMSSUpdateFromVMSS(clone)
+				g.Expect(err).NotTo(HaveOccurred())
+				m.UpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(patchVMSS)).
+					Return(patchFuture, nil)
+				s.SetLongRunningOperationState(patchFuture)
+				m.GetResultIfDone(gomockinternal.AContext(), patchFuture).Return(compute.VirtualMachineScaleSet{}, azure.NewOperationNotDoneError(patchFuture))
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(clone, nil)
+				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+			},
+		},
+		{
+			name:          "should update the scale-in policy of an existing scale set without surging",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PATCH on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				spec.ScaleInRule = "OldestVM"
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSExpectations(s)
+				s.SetProviderID(azure.ProviderIDPrefix + "vmss-id")
+				s.GetLongRunningOperationState(defaultVMSSName, ServiceName).Return(nil)
+				s.MaxSurge().Return(1, nil)
+				s.SetVMSSState(gomock.Any())
+				existingVMSS := newDefaultExistingVMSS("VM_SIZE")
+				existingVMSS.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				instances := newDefaultInstances()
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(existingVMSS, nil)
+				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+
+				clone := newDefaultExistingVMSS("VM_SIZE")
+				clone.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				clone.ScaleInPolicy = &compute.ScaleInPolicy{
+					Rules: &[]compute.VirtualMachineScaleSetScaleInRules{compute.VirtualMachineScaleSetScaleInRulesOldestVM},
+				}
+				patchVMSS, err := getVMSSUpdateFromVMSS(clone)
+				g.Expect(err).NotTo(HaveOccurred())
+				m.UpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(patchVMSS)).
+					Return(patchFuture, nil)
+				s.SetLongRunningOperationState(patchFuture)
+				m.GetResultIfDone(gomockinternal.AContext(), patchFuture).Return(compute.VirtualMachineScaleSet{}, azure.NewOperationNotDoneError(patchFuture))
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(clone, nil)
+				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+			},
+		},
+		{
+			name:          "should enable automatic repairs on an existing scale set without surging",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PATCH on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				spec.AutomaticRepairsPolicy = &azure.ScaleSetAutomaticRepairsPolicy{
+					Enabled:     true,
+					GracePeriod: to.StringPtr("PT45M"),
+				}
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSExpectations(s)
+				s.SetProviderID(azure.ProviderIDPrefix + "vmss-id")
+				s.GetLongRunningOperationState(defaultVMSSName, ServiceName).Return(nil)
+				s.MaxSurge().Return(1, nil)
+				s.SetVMSSState(gomock.Any())
+				existingVMSS := newDefaultExistingVMSS("VM_SIZE")
+				existingVMSS.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				instances := newDefaultInstances()
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(existingVMSS, nil)
+				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+
+				clone := newDefaultExistingVMSS("VM_SIZE")
+				clone.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				clone.AutomaticRepairsPolicy = &compute.AutomaticRepairsPolicy{
+					Enabled:     to.BoolPtr(true),
+					GracePeriod: to.StringPtr("PT45M"),
+				}
+				patchVMSS, err := getVMSSUpdateFromVMSS(clone)
+				g.Expect(err).NotTo(HaveOccurred())
+				m.UpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(patchVMSS)).
+					Return(patchFuture, nil)
+				s.SetLongRunningOperationState(patchFuture)
+				m.GetResultIfDone(gomockinternal.AContext(), patchFuture).Return(compute.VirtualMachineScaleSet{}, azure.NewOperationNotDoneError(patchFuture))
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(clone, nil)
+				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+			},
+		},
 		{
 			name:          "less than 2 vCPUs",
 			expectedError: "reconcile error that cannot be recovered occurred: vm size should be bigger or equal to at least 2 vCPUs. Object will not be requeued",
@@ -562,6 +961,54 @@ func TestReconcileVMSS(t *testing.T) {
 				s.Location().AnyTimes().Return("test-location")
 			},
 		},
+		{
+			name:          "fail to create a vmss with zone redundant disks in a location without availability zones",
+			expectedError: "reconcile error that cannot be recovered occurred: location test-location-without-zones does not have availability zones to replicate disks with storage account type StandardSSD_ZRS across. select a locally redundant storage account type. Object will not be requeued",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ScaleSetSpec().Return(azure.ScaleSetSpec{
+					Name:       defaultVMSSName,
+					Size:       "VM_SIZE",
+					Capacity:   2,
+					SSHKeyData: "ZmFrZXNzaGtleQo=",
+					OSDisk: infrav1.OSDisk{
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "StandardSSD_ZRS",
+						},
+					},
+				})
+				s.Location().AnyTimes().Return("test-location-without-zones")
+			},
+		},
+		{
+			name:          "fail to create a vmss with an ephemeral os disk larger than the cache disk",
+			expectedError: "reconcile error that cannot be recovered occurred: vm size VM_SIZE does not have enough cache or temporary disk space for an ephemeral os disk of 120 GB. select a larger vm size or a smaller os disk. Object will not be requeued",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ScaleSetSpec().Return(azure.ScaleSetSpec{
+					Name:       defaultVMSSName,
+					Size:       "VM_SIZE",
+					Capacity:   2,
+					SSHKeyData: "ZmFrZXNzaGtleQo=",
+					OSDisk: infrav1.OSDisk{
+						DiskSizeGB:       to.Int32Ptr(120),
+						CachingType:      "ReadOnly",
+						DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"},
+					},
+				})
+			},
+		},
+		{
+			name:          "fail to create a vmss with accelerated networking on a vm size which does not support it",
+			expectedError: "reconcile error that cannot be recovered occurred: vm size VM_SIZE does not support accelerated networking. select a different vm size or disable accelerated networking. Object will not be requeued",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ScaleSetSpec().Return(azure.ScaleSetSpec{
+					Name:                  defaultVMSSName,
+					Size:                  "VM_SIZE",
+					Capacity:              2,
+					SSHKeyData:            "ZmFrZXNzaGtleQo=",
+					AcceleratedNetworking: to.BoolPtr(true),
+				})
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -616,11 +1063,11 @@ func TestDeleteVMSS(t *testing.T) {
 				}).AnyTimes()
 				s.ResourceGroup().AnyTimes().Return("my-existing-rg")
 				future := &infrav1.Future{}
-				s.GetLongRunningOperationState("my-existing-vmss", scope.ScalesetsServiceName).Return(future)
+				s.GetLongRunningOperationState("my-existing-vmss", ServiceName).Return(future)
 				m.GetResultIfDone(gomockinternal.AContext(), future).Return(compute.VirtualMachineScaleSet{}, nil)
 				m.Get(gomockinternal.AContext(), "my-existing-rg", "my-existing-vmss").
 					Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				s.DeleteLongRunningOperationState("my-existing-vmss", scope.ScalesetsServiceName)
+				s.DeleteLongRunningOperationState("my-existing-vmss", ServiceName)
 			},
 		},
 		{
@@ -633,13 +1080,32 @@ func TestDeleteVMSS(t *testing.T) {
 					Capacity: 3,
 				}).AnyTimes()
 				s.ResourceGroup().AnyTimes().Return(resourceGroup)
-				s.GetLongRunningOperationState(name, scope.ScalesetsServiceName).Return(nil)
-				m.DeleteAsync(gomockinternal.AContext(), resourceGroup, name).
+				s.GetLongRunningOperationState(name, ServiceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), resourceGroup, name, false).
 					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.Get(gomockinternal.AContext(), resourceGroup, name).
 					Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 			},
 		},
+		{
+			name:          "force delete a vmss",
+			expectedError: "",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ScaleSetSpec().Return(azure.ScaleSetSpec{
+					Name:        name,
+					Size:        "VM_SIZE",
+					Capacity:    3,
+					ForceDelete: true,
+				}).AnyTimes()
+				s.ResourceGroup().AnyTimes().Return(resourceGroup)
+				s.GetLongRunningOperationState(name, ServiceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), resourceGroup, name, true).Return(nil, nil)
+				s.SetLongRunningOperationState(nil)
+				s.DeleteLongRunningOperationState(name, ServiceName)
+				m.Get(gomockinternal.AContext(), resourceGroup, name).
+					Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
 		{
 			name:          "vmss deletion fails",
 			expectedError: "failed to delete VMSS my-vmss in resource group my-rg: #: Internal Server Error: StatusCode=500",
@@ -650,8 +1116,8 @@ func TestDeleteVMSS(t *testing.T) {
 					Capacity: 3,
 				}).AnyTimes()
 				s.ResourceGroup().AnyTimes().Return(resourceGroup)
-				s.GetLongRunningOperationState(name, scope.ScalesetsServiceName).Return(nil)
-				m.DeleteAsync(gomockinternal.AContext(), resourceGroup, name).
+				s.GetLongRunningOperationState(name, ServiceName).Return(nil)
+				m.DeleteAsync(gomockinternal.AContext(), resourceGroup, name, false).
 					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 				m.Get(gomockinternal.AContext(), resourceGroup, name).
 					Return(newDefaultVMSS("VM_SIZE"), nil)
@@ -728,6 +1194,14 @@ func getFakeSkus() []compute.ResourceSku {
 					Name:  to.StringPtr(resourceskus.MemoryGB),
 					Value: to.StringPtr("4"),
 				},
+				{
+					Name:  to.StringPtr(resourceskus.EphemeralOSDisk),
+					Value: to.StringPtr(string(resourceskus.CapabilitySupported)),
+				},
+				{
+					Name:  to.StringPtr(resourceskus.CachedDiskBytes),
+					Value: to.StringPtr("53687091200"),
+				},
 			},
 		},
 		{
@@ -1204,7 +1678,7 @@ func setupDefaultVMSSInProgressOperationDoneExpectations(s *mock_scalesets.MockS
 		Name:          defaultVMSSName,
 		Data:          "",
 	}
-	s.GetLongRunningOperationState(defaultVMSSName, scope.ScalesetsServiceName).Return(future)
+	s.GetLongRunningOperationState(defaultVMSSName, ServiceName).Return(future)
 	m.GetResultIfDone(gomockinternal.AContext(), future).Return(createdVMSS, nil).AnyTimes()
 	m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil).AnyTimes()
 	s.MaxSurge().Return(1, nil)
@@ -1215,7 +1689,7 @@ func setupDefaultVMSSInProgressOperationDoneExpectations(s *mock_scalesets.MockS
 
 func setupDefaultVMSSStartCreatingExpectations(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 	setupDefaultVMSSExpectations(s)
-	s.GetLongRunningOperationState(defaultVMSSName, scope.ScalesetsServiceName).Return(nil)
+	s.GetLongRunningOperationState(defaultVMSSName, ServiceName).Return(nil)
 	m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).
 		Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 }
@@ -1264,6 +1738,7 @@ func setupVMSSExpectationsWithoutVMImage(s *mock_scalesets.MockScaleSetScopeMock
 	s.Location().AnyTimes().Return("test-location")
 	s.ClusterName().Return("my-cluster")
 	s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
+	s.GetUserData(gomockinternal.AContext()).Return("", nil).AnyTimes()
 	s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
 		{
 			Name:      "someExtension",
@@ -1280,7 +1755,199 @@ func setupVMSSExpectationsWithoutVMImage(s *mock_scalesets.MockScaleSetScopeMock
 func setupDefaultVMSSUpdateExpectations(s *mock_scalesets.MockScaleSetScopeMockRecorder) {
 	setupUpdateVMSSExpectations(s)
 	s.SetProviderID(azure.ProviderIDPrefix + "vmss-id")
-	s.GetLongRunningOperationState(defaultVMSSName, scope.ScalesetsServiceName).Return(nil)
+	s.GetLongRunningOperationState(defaultVMSSName, ServiceName).Return(nil)
 	s.MaxSurge().Return(1, nil)
 	s.SetVMSSState(gomock.Any())
 }
+
+func TestScaleUpBatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   int64
+		desired   int64
+		batchSize int64
+		want      int64
+	}{
+		{
+			name:      "scale up smaller than the batch size",
+			current:   2,
+			desired:   5,
+			batchSize: 3,
+			want:      5,
+		},
+		{
+			name:      "scale up larger than the batch size",
+			current:   2,
+			desired:   500,
+			batchSize: 100,
+			want:      102,
+		},
+		{
+			name:      "scale down",
+			current:   500,
+			desired:   2,
+			batchSize: 100,
+			want:      2,
+		},
+		{
+			name:      "no batch size",
+			current:   0,
+			desired:   500,
+			batchSize: 0,
+			want:      500,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(scaleUpBatch(tc.current, tc.desired, tc.batchSize)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestGetUpgradePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *azure.ScaleSetUpgradePolicy
+		want   *compute.UpgradePolicy
+	}{
+		{
+			name:   "defaults to the Manual mode",
+			policy: nil,
+			want:   &compute.UpgradePolicy{Mode: compute.UpgradeModeManual},
+		},
+		{
+			name: "Automatic mode with automatic OS upgrade",
+			policy: &azure.ScaleSetUpgradePolicy{
+				Mode:                    "Automatic",
+				AutomaticOSUpgrade:      true,
+				MaxBatchInstancePercent: to.Int32Ptr(50),
+			},
+			want: &compute.UpgradePolicy{
+				Mode: compute.UpgradeModeAutomatic,
+				AutomaticOSUpgradePolicy: &compute.AutomaticOSUpgradePolicy{
+					EnableAutomaticOSUpgrade: to.BoolPtr(true),
+				},
+			},
+		},
+		{
+			name: "Rolling mode",
+			policy: &azure.ScaleSetUpgradePolicy{
+				Mode:                    "Rolling",
+				MaxBatchInstancePercent: to.Int32Ptr(50),
+				PauseTimeBetweenBatches: to.StringPtr("PT30S"),
+			},
+			want: &compute.UpgradePolicy{
+				Mode: compute.UpgradeModeRolling,
+				RollingUpgradePolicy: &compute.RollingUpgradePolicy{
+					MaxBatchInstancePercent: to.Int32Ptr(50),
+					PauseTimeBetweenBatches: to.StringPtr("PT30S"),
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(getUpgradePolicy(azure.ScaleSetSpec{UpgradePolicy: tc.policy})).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestWithoutZones(t *testing.T) {
+	tests := []struct {
+		name     string
+		excluded []string
+		want     []string
+	}{
+		{
+			name: "no zone is excluded",
+			want: []string{"1", "2", "3"},
+		},
+		{
+			name:     "excluded zones are removed",
+			excluded: []string{"2", "4"},
+			want:     []string{"1", "3"},
+		},
+		{
+			name:     "all zones are kept when they are all excluded",
+			excluded: []string{"1", "2", "3"},
+			want:     []string{"1", "2", "3"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(withoutZones([]string{"1", "2", "3"}, tc.excluded)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestGetHealthExtension(t *testing.T) {
+	tests := []struct {
+		name string
+		spec azure.ScaleSetSpec
+		want *compute.VirtualMachineScaleSetExtension
+	}{
+		{
+			name: "no health probe",
+			spec: azure.ScaleSetSpec{UpgradePolicy: &azure.ScaleSetUpgradePolicy{Mode: "Manual"}},
+			want: nil,
+		},
+		{
+			name: "http health probe on Linux",
+			spec: azure.ScaleSetSpec{
+				OSDisk: infrav1.OSDisk{OSType: azure.LinuxOS},
+				UpgradePolicy: &azure.ScaleSetUpgradePolicy{
+					Mode:        "Rolling",
+					HealthProbe: &azure.ScaleSetHealthProbe{Protocol: "http", Port: 10248, RequestPath: "/healthz"},
+				},
+			},
+			want: &compute.VirtualMachineScaleSetExtension{
+				Name: to.StringPtr("ApplicationHealthLinux"),
+				VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+					Publisher:          to.StringPtr("Microsoft.ManagedServices"),
+					Type:               to.StringPtr("ApplicationHealthLinux"),
+					TypeHandlerVersion: to.StringPtr("1.0"),
+					Settings: map[string]interface{}{
+						"protocol":    "http",
+						"port":        int32(10248),
+						"requestPath": "/healthz",
+					},
+				},
+			},
+		},
+		{
+			name: "tcp health probe on Windows",
+			spec: azure.ScaleSetSpec{
+				OSDisk: infrav1.OSDisk{OSType: azure.WindowsOS},
+				UpgradePolicy: &azure.ScaleSetUpgradePolicy{
+					Mode:        "Rolling",
+					HealthProbe: &azure.ScaleSetHealthProbe{Protocol: "tcp", Port: 10250},
+				},
+			},
+			want: &compute.VirtualMachineScaleSetExtension{
+				Name: to.StringPtr("ApplicationHealthWindows"),
+				VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+					Publisher:          to.StringPtr("Microsoft.ManagedServices"),
+					Type:               to.StringPtr("ApplicationHealthWindows"),
+					TypeHandlerVersion: to.StringPtr("1.0"),
+					Settings: map[string]interface{}{
+						"protocol": "tcp",
+						"port":     int32(10250),
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(getHealthExtension(tc.spec)).To(Equal(tc.want))
+		})
+	}
+}
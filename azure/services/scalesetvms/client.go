@@ -30,6 +30,8 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -37,13 +39,20 @@ import (
 type client interface {
 	Get(context.Context, string, string, string) (compute.VirtualMachineScaleSetVM, error)
 	GetResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachineScaleSetVM, error)
-	DeleteAsync(context.Context, string, string, string) (*infrav1.Future, error)
+	DeleteAsync(context.Context, string, string, string, bool) (*infrav1.Future, error)
+	GetVM(context.Context, string, string) (compute.VirtualMachine, error)
+	DeleteVMAsync(context.Context, string, string, bool) (*infrav1.Future, error)
+	ReimageAsync(context.Context, string, string, string) (*infrav1.Future, error)
+	RedeployAsync(context.Context, string, string, string) (*infrav1.Future, error)
+	RedeployVMAsync(context.Context, string, string) (*infrav1.Future, error)
+	UpdateProtectionPolicy(context.Context, string, string, string, compute.VirtualMachineScaleSetVMProtectionPolicy) error
 }
 
 type (
 	// azureClient contains the Azure go-sdk Client.
 	azureClient struct {
 		scalesetvms compute.VirtualMachineScaleSetVMsClient
+		vms         compute.VirtualMachinesClient
 	}
 
 	genericScaleSetVMFuture interface {
@@ -54,14 +63,20 @@ type (
 	deleteFutureAdapter struct {
 		compute.VirtualMachineScaleSetVMsDeleteFuture
 	}
+
+	postFutureAdapter struct {
+		compute.VirtualMachineScaleSetVMsRedeployFuture
+	}
 )
 
 var _ client = &azureClient{}
 
 // newClient creates a new VMSS client from subscription ID.
 func newClient(auth azure.Authorizer) *azureClient {
+	subscriptionID, baseURI, authorizer := auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()
 	return &azureClient{
-		scalesetvms: newVirtualMachineScaleSetVMsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		scalesetvms: newVirtualMachineScaleSetVMsClient(subscriptionID, baseURI, authorizer),
+		vms:         newVirtualMachinesClient(subscriptionID, baseURI, authorizer),
 	}
 }
 
@@ -74,16 +89,28 @@ func newVirtualMachineScaleSetVMsClient(subscriptionID string, baseURI string, a
 	return c
 }
 
+// newVirtualMachinesClient creates a new VM client from subscription ID.
+func newVirtualMachinesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachinesClient {
+	c := compute.NewVirtualMachinesClientWithBaseURI(baseURI, subscriptionID)
+	c.Authorizer = authorizer
+	c.RetryAttempts = 1
+	_ = c.AddToUserAgent(azure.UserAgent()) // intentionally ignore error as it doesn't matter
+	return c
+}
+
 // Get retrieves the Virtual Machine Scale Set Virtual Machine.
-func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmssName, instanceID string) (compute.VirtualMachineScaleSetVM, error) {
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmssName, instanceID string) (_ compute.VirtualMachineScaleSetVM, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.scalesetvms.Get(ctx, resourceGroupName, vmssName, instanceID, "")
 }
 
 // GetResultIfDone fetches the result of a long-running operation future if it is done.
-func (ac *azureClient) GetResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachineScaleSetVM, error) {
+func (ac *azureClient) GetResultIfDone(ctx context.Context, future *infrav1.Future) (_ compute.VirtualMachineScaleSetVM, err error) {
+	defer azureerrors.Classify(&err)
+
 	ctx, _, spanDone := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.GetResultIfDone")
 	defer spanDone()
 
@@ -103,6 +130,16 @@ func (ac *azureClient) GetResultIfDone(ctx context.Context, future *infrav1.Futu
 		genericFuture = &deleteFutureAdapter{
 			VirtualMachineScaleSetVMsDeleteFuture: future,
 		}
+	case infrav1.PostFuture:
+		// the reimage and redeploy operations don't have a result, only their completion matters.
+		var future compute.VirtualMachineScaleSetVMsRedeployFuture
+		if err := json.Unmarshal(futureData, &future); err != nil {
+			return compute.VirtualMachineScaleSetVM{}, errors.Wrap(err, "failed to unmarshal future data")
+		}
+
+		genericFuture = &postFutureAdapter{
+			VirtualMachineScaleSetVMsRedeployFuture: future,
+		}
 	default:
 		return compute.VirtualMachineScaleSetVM{}, errors.Errorf("unknown furture type %q", future.Type)
 	}
@@ -132,11 +169,17 @@ func (ac *azureClient) GetResultIfDone(ctx context.Context, future *infrav1.Futu
 //   resourceGroupName - the name of the resource group.
 //   vmssName - the name of the VM scale set to create or update. parameters - the scale set object.
 //   instanceID - the ID of the VM scale set VM.
-func (ac *azureClient) DeleteAsync(ctx context.Context, resourceGroupName, vmssName, instanceID string) (*infrav1.Future, error) {
+//   forceDelete - whether to force delete the VM scale set VM, falling back to a normal deletion where it isn't available.
+func (ac *azureClient) DeleteAsync(ctx context.Context, resourceGroupName, vmssName, instanceID string, forceDelete bool) (_ *infrav1.Future, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.DeleteAsync")
 	defer done()
+	defer azureerrors.Classify(&err)
 
-	future, err := ac.scalesetvms.Delete(ctx, resourceGroupName, vmssName, instanceID, to.BoolPtr(false))
+	future, err := ac.scalesetvms.Delete(ctx, resourceGroupName, vmssName, instanceID, to.BoolPtr(forceDelete))
+	if err != nil && forceDelete && azure.BadRequest(err) {
+		// force deletion is not available in all the regions, so fall back to a normal deletion.
+		future, err = ac.scalesetvms.Delete(ctx, resourceGroupName, vmssName, instanceID, to.BoolPtr(false))
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed deleting vmss named %q", vmssName)
 	}
@@ -144,6 +187,120 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, resourceGroupName, vmssN
 	return converters.SDKToFuture(&future, infrav1.DeleteFuture, serviceName, instanceID, resourceGroupName)
 }
 
+// GetVM retrieves the Virtual Machine of a Virtual Machine Scale Set in the Flexible orchestration mode.
+func (ac *azureClient) GetVM(ctx context.Context, resourceGroupName, vmName string) (_ compute.VirtualMachine, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.GetVM")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.vms.Get(ctx, resourceGroupName, vmName, "")
+}
+
+// DeleteVMAsync is the operation to delete the Virtual Machine of a Virtual Machine Scale Set in the Flexible
+// orchestration mode asynchronously. The returned future is polled by GetResultIfDone like the one of a scale set
+// instance, as only its completion matters. Force deletion falls back to a normal deletion where it isn't available.
+func (ac *azureClient) DeleteVMAsync(ctx context.Context, resourceGroupName, vmName string, forceDelete bool) (_ *infrav1.Future, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.DeleteVMAsync")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.vms.Delete(ctx, resourceGroupName, vmName, to.BoolPtr(forceDelete))
+	if err != nil && forceDelete && azure.BadRequest(err) {
+		future, err = ac.vms.Delete(ctx, resourceGroupName, vmName, to.BoolPtr(false))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed deleting vm named %q", vmName)
+	}
+
+	return converters.SDKToFuture(&future, infrav1.DeleteFuture, serviceName, vmName, resourceGroupName)
+}
+
+// ReimageAsync is the operation to reimage a virtual machine scale set instance asynchronously, restoring its OS disk
+// from the image of the scale set model. The returned future is polled by GetResultIfDone.
+func (ac *azureClient) ReimageAsync(ctx context.Context, resourceGroupName, vmssName, instanceID string) (_ *infrav1.Future, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.ReimageAsync")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.scalesetvms.Reimage(ctx, resourceGroupName, vmssName, instanceID, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reimaging vmss named %q", vmssName)
+	}
+
+	return converters.SDKToFuture(&future, infrav1.PostFuture, serviceName, instanceID, resourceGroupName)
+}
+
+// RedeployAsync is the operation to redeploy a virtual machine scale set instance to another host asynchronously. The
+// returned future is polled by GetResultIfDone.
+func (ac *azureClient) RedeployAsync(ctx context.Context, resourceGroupName, vmssName, instanceID string) (_ *infrav1.Future, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.RedeployAsync")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.scalesetvms.Redeploy(ctx, resourceGroupName, vmssName, instanceID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed redeploying vmss named %q", vmssName)
+	}
+
+	return converters.SDKToFuture(&future, infrav1.PostFuture, serviceName, instanceID, resourceGroupName)
+}
+
+// RedeployVMAsync is the operation to redeploy the Virtual Machine of a Virtual Machine Scale Set in the Flexible
+// orchestration mode to another host asynchronously. The returned future is polled by GetResultIfDone like the one of
+// a scale set instance.
+func (ac *azureClient) RedeployVMAsync(ctx context.Context, resourceGroupName, vmName string) (_ *infrav1.Future, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.RedeployVMAsync")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.vms.Redeploy(ctx, resourceGroupName, vmName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed redeploying vm named %q", vmName)
+	}
+
+	return converters.SDKToFuture(&future, infrav1.PostFuture, serviceName, vmName, resourceGroupName)
+}
+
+// UpdateProtectionPolicy sets the protection policy of a virtual machine scale set instance, and waits for the update
+// to complete.
+func (ac *azureClient) UpdateProtectionPolicy(ctx context.Context, resourceGroupName, vmssName, instanceID string, policy compute.VirtualMachineScaleSetVMProtectionPolicy) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.UpdateProtectionPolicy")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	instance, err := ac.scalesetvms.Get(ctx, resourceGroupName, vmssName, instanceID, "")
+	if err != nil {
+		return errors.Wrapf(err, "failed getting instance %s of vmss named %q", instanceID, vmssName)
+	}
+	if instance.VirtualMachineScaleSetVMProperties == nil {
+		return errors.Errorf("instance %s of vmss named %q has no properties", instanceID, vmssName)
+	}
+	instance.ProtectionPolicy = &policy
+	// the extensions of the instance are managed by the scale set model, and can't be sent back.
+	instance.Resources = nil
+
+	future, err := ac.scalesetvms.Update(ctx, resourceGroupName, vmssName, instanceID, instance)
+	if err != nil {
+		return errors.Wrapf(err, "failed updating instance %s of vmss named %q", instanceID, vmssName)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	if err := future.WaitForCompletionRef(ctx, ac.scalesetvms.Client); err != nil {
+		return errors.Wrapf(err, "failed waiting for the update of instance %s of vmss named %q", instanceID, vmssName)
+	}
+	_, err = future.Result(ac.scalesetvms)
+	return err
+}
+
+// Result wraps the reimage or redeploy result so that we can treat it generically. The only thing we care about is if
+// the operation was successful. If it wasn't, an error will be returned.
+func (pa *postFutureAdapter) Result(client compute.VirtualMachineScaleSetVMsClient) (compute.VirtualMachineScaleSetVM, error) {
+	_, err := pa.VirtualMachineScaleSetVMsRedeployFuture.Result(client)
+	return compute.VirtualMachineScaleSetVM{}, err
+}
+
 // Result wraps the delete result so that we can treat it generically. The only thing we care about is if the delete
 // was successful. If it wasn't, an error will be returned.
 func (da *deleteFutureAdapter) Result(client compute.VirtualMachineScaleSetVMsClient) (compute.VirtualMachineScaleSetVM, error) {
@@ -54,18 +54,33 @@ func (m *Mockclient) EXPECT() *MockclientMockRecorder {
 }
 
 // DeleteAsync mocks base method.
-func (m *Mockclient) DeleteAsync(arg0 context.Context, arg1, arg2, arg3 string) (*v1beta1.Future, error) {
+func (m *Mockclient) DeleteAsync(arg0 context.Context, arg1, arg2, arg3 string, arg4 bool) (*v1beta1.Future, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAsync", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "DeleteAsync", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(*v1beta1.Future)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteAsync indicates an expected call of DeleteAsync.
-func (mr *MockclientMockRecorder) DeleteAsync(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockclientMockRecorder) DeleteAsync(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAsync", reflect.TypeOf((*Mockclient)(nil).DeleteAsync), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAsync", reflect.TypeOf((*Mockclient)(nil).DeleteAsync), arg0, arg1, arg2, arg3, arg4)
+}
+
+// DeleteVMAsync mocks base method.
+func (m *Mockclient) DeleteVMAsync(arg0 context.Context, arg1, arg2 string, arg3 bool) (*v1beta1.Future, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVMAsync", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVMAsync indicates an expected call of DeleteVMAsync.
+func (mr *MockclientMockRecorder) DeleteVMAsync(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVMAsync", reflect.TypeOf((*Mockclient)(nil).DeleteVMAsync), arg0, arg1, arg2, arg3)
 }
 
 // Get mocks base method.
@@ -98,6 +113,80 @@ func (mr *MockclientMockRecorder) GetResultIfDone(ctx, future interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResultIfDone", reflect.TypeOf((*Mockclient)(nil).GetResultIfDone), ctx, future)
 }
 
+// GetVM mocks base method.
+func (m *Mockclient) GetVM(arg0 context.Context, arg1, arg2 string) (compute.VirtualMachine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVM", arg0, arg1, arg2)
+	ret0, _ := ret[0].(compute.VirtualMachine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVM indicates an expected call of GetVM.
+func (mr *MockclientMockRecorder) GetVM(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVM", reflect.TypeOf((*Mockclient)(nil).GetVM), arg0, arg1, arg2)
+}
+
+// RedeployAsync mocks base method.
+func (m *Mockclient) RedeployAsync(arg0 context.Context, arg1, arg2, arg3 string) (*v1beta1.Future, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeployAsync", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeployAsync indicates an expected call of RedeployAsync.
+func (mr *MockclientMockRecorder) RedeployAsync(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeployAsync", reflect.TypeOf((*Mockclient)(nil).RedeployAsync), arg0, arg1, arg2, arg3)
+}
+
+// RedeployVMAsync mocks base method.
+func (m *Mockclient) RedeployVMAsync(arg0 context.Context, arg1, arg2 string) (*v1beta1.Future, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeployVMAsync", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeployVMAsync indicates an expected call of RedeployVMAsync.
+func (mr *MockclientMockRecorder) RedeployVMAsync(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeployVMAsync", reflect.TypeOf((*Mockclient)(nil).RedeployVMAsync), arg0, arg1, arg2)
+}
+
+// ReimageAsync mocks base method.
+func (m *Mockclient) ReimageAsync(arg0 context.Context, arg1, arg2, arg3 string) (*v1beta1.Future, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReimageAsync", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReimageAsync indicates an expected call of ReimageAsync.
+func (mr *MockclientMockRecorder) ReimageAsync(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReimageAsync", reflect.TypeOf((*Mockclient)(nil).ReimageAsync), arg0, arg1, arg2, arg3)
+}
+
+// UpdateProtectionPolicy mocks base method.
+func (m *Mockclient) UpdateProtectionPolicy(arg0 context.Context, arg1, arg2, arg3 string, arg4 compute.VirtualMachineScaleSetVMProtectionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProtectionPolicy", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProtectionPolicy indicates an expected call of UpdateProtectionPolicy.
+func (mr *MockclientMockRecorder) UpdateProtectionPolicy(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProtectionPolicy", reflect.TypeOf((*Mockclient)(nil).UpdateProtectionPolicy), arg0, arg1, arg2, arg3, arg4)
+}
+
 // MockgenericScaleSetVMFuture is a mock of genericScaleSetVMFuture interface.
 type MockgenericScaleSetVMFuture struct {
 	ctrl     *gomock.Controller
@@ -21,13 +21,15 @@ limitations under the License.
 package mock_scalesetvms
 
 import (
+	context "context"
 	reflect "reflect"
 
 	autorest "github.com/Azure/go-autorest/autorest"
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
-	v1beta10 "sigs.k8s.io/cluster-api/api/v1beta1"
+	v1beta10 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	v1beta11 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // MockScaleSetVMScope is a mock of ScaleSetVMScope interface.
@@ -205,6 +207,20 @@ func (mr *MockScaleSetVMScopeMockRecorder) FailureDomains() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockScaleSetVMScope)(nil).FailureDomains))
 }
 
+// ForceDelete mocks base method.
+func (m *MockScaleSetVMScope) ForceDelete() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceDelete")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ForceDelete indicates an expected call of ForceDelete.
+func (mr *MockScaleSetVMScopeMockRecorder) ForceDelete() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceDelete", reflect.TypeOf((*MockScaleSetVMScope)(nil).ForceDelete))
+}
+
 // GetLongRunningOperationState mocks base method.
 func (m *MockScaleSetVMScope) GetLongRunningOperationState(arg0, arg1 string) *v1beta1.Future {
 	m.ctrl.T.Helper()
@@ -233,6 +249,20 @@ func (mr *MockScaleSetVMScopeMockRecorder) HashKey() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockScaleSetVMScope)(nil).HashKey))
 }
 
+// InPlaceRemediation mocks base method.
+func (m *MockScaleSetVMScope) InPlaceRemediation() v1beta10.RemediationStrategyType {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InPlaceRemediation")
+	ret0, _ := ret[0].(v1beta10.RemediationStrategyType)
+	return ret0
+}
+
+// InPlaceRemediation indicates an expected call of InPlaceRemediation.
+func (mr *MockScaleSetVMScopeMockRecorder) InPlaceRemediation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InPlaceRemediation", reflect.TypeOf((*MockScaleSetVMScope)(nil).InPlaceRemediation))
+}
+
 // InstanceID mocks base method.
 func (m *MockScaleSetVMScope) InstanceID() string {
 	m.ctrl.T.Helper()
@@ -247,6 +277,21 @@ func (mr *MockScaleSetVMScopeMockRecorder) InstanceID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceID", reflect.TypeOf((*MockScaleSetVMScope)(nil).InstanceID))
 }
 
+// InstanceProtection mocks base method.
+func (m *MockScaleSetVMScope) InstanceProtection(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstanceProtection", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstanceProtection indicates an expected call of InstanceProtection.
+func (mr *MockScaleSetVMScopeMockRecorder) InstanceProtection(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceProtection", reflect.TypeOf((*MockScaleSetVMScope)(nil).InstanceProtection), ctx)
+}
+
 // Location mocks base method.
 func (m *MockScaleSetVMScope) Location() string {
 	m.ctrl.T.Helper()
@@ -261,6 +306,48 @@ func (mr *MockScaleSetVMScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockScaleSetVMScope)(nil).Location))
 }
 
+// OrchestrationMode mocks base method.
+func (m *MockScaleSetVMScope) OrchestrationMode() v1beta10.OrchestrationModeType {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrchestrationMode")
+	ret0, _ := ret[0].(v1beta10.OrchestrationModeType)
+	return ret0
+}
+
+// OrchestrationMode indicates an expected call of OrchestrationMode.
+func (mr *MockScaleSetVMScopeMockRecorder) OrchestrationMode() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrchestrationMode", reflect.TypeOf((*MockScaleSetVMScope)(nil).OrchestrationMode))
+}
+
+// ProxyConfig mocks base method.
+func (m *MockScaleSetVMScope) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockScaleSetVMScopeMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockScaleSetVMScope)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockScaleSetVMScope) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockScaleSetVMScopeMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockScaleSetVMScope)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockScaleSetVMScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -289,6 +376,18 @@ func (mr *MockScaleSetVMScopeMockRecorder) ScaleSetName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleSetName", reflect.TypeOf((*MockScaleSetVMScope)(nil).ScaleSetName))
 }
 
+// SetInPlaceRemediationStarted mocks base method.
+func (m *MockScaleSetVMScope) SetInPlaceRemediationStarted(strategy v1beta10.RemediationStrategyType) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetInPlaceRemediationStarted", strategy)
+}
+
+// SetInPlaceRemediationStarted indicates an expected call of SetInPlaceRemediationStarted.
+func (mr *MockScaleSetVMScopeMockRecorder) SetInPlaceRemediationStarted(strategy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInPlaceRemediationStarted", reflect.TypeOf((*MockScaleSetVMScope)(nil).SetInPlaceRemediationStarted), strategy)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockScaleSetVMScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -341,8 +440,22 @@ func (mr *MockScaleSetVMScopeMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockScaleSetVMScope)(nil).TenantID))
 }
 
+// TrustedCAs mocks base method.
+func (m *MockScaleSetVMScope) TrustedCAs() *v1beta1.TrustedCASource {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrustedCAs")
+	ret0, _ := ret[0].(*v1beta1.TrustedCASource)
+	return ret0
+}
+
+// TrustedCAs indicates an expected call of TrustedCAs.
+func (mr *MockScaleSetVMScopeMockRecorder) TrustedCAs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustedCAs", reflect.TypeOf((*MockScaleSetVMScope)(nil).TrustedCAs))
+}
+
 // UpdateDeleteStatus mocks base method.
-func (m *MockScaleSetVMScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+func (m *MockScaleSetVMScope) UpdateDeleteStatus(arg0 v1beta11.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "UpdateDeleteStatus", arg0, arg1, arg2)
 }
@@ -354,7 +467,7 @@ func (mr *MockScaleSetVMScopeMockRecorder) UpdateDeleteStatus(arg0, arg1, arg2 i
 }
 
 // UpdatePatchStatus mocks base method.
-func (m *MockScaleSetVMScope) UpdatePatchStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+func (m *MockScaleSetVMScope) UpdatePatchStatus(arg0 v1beta11.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "UpdatePatchStatus", arg0, arg1, arg2)
 }
@@ -366,7 +479,7 @@ func (mr *MockScaleSetVMScopeMockRecorder) UpdatePatchStatus(arg0, arg1, arg2 in
 }
 
 // UpdatePutStatus mocks base method.
-func (m *MockScaleSetVMScope) UpdatePutStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+func (m *MockScaleSetVMScope) UpdatePutStatus(arg0 v1beta11.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "UpdatePutStatus", arg0, arg1, arg2)
 }
@@ -20,11 +20,14 @@ import (
 	"context"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -37,7 +40,12 @@ type (
 		azure.AsyncStatusUpdater
 		InstanceID() string
 		ScaleSetName() string
+		OrchestrationMode() infrav1exp.OrchestrationModeType
+		ForceDelete() bool
 		SetVMSSVM(vmssvm *azure.VMSSVM)
+		InPlaceRemediation() infrav1exp.RemediationStrategyType
+		SetInPlaceRemediationStarted(strategy infrav1exp.RemediationStrategyType)
+		InstanceProtection(ctx context.Context) (string, error)
 	}
 
 	// Service provides operations on Azure resources.
@@ -67,7 +75,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	)
 
 	// fetch the latest data about the instance -- model mutations are handled by the AzureMachinePoolReconciler
-	instance, err := s.Client.Get(ctx, resourceGroup, vmssName, instanceID)
+	instance, err := s.getInstance(ctx, resourceGroup, vmssName, instanceID)
 	if err != nil {
 		if azure.ResourceNotFound(err) {
 			return azure.WithTransientError(errors.New("instance does not exist yet"), 30*time.Second)
@@ -75,7 +83,124 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed getting instance")
 	}
 
-	s.Scope.SetVMSSVM(converters.SDKToVMSSVM(instance))
+	s.Scope.SetVMSSVM(instance)
+	return s.reconcileProtectionPolicy(ctx, instance)
+}
+
+// reconcileProtectionPolicy updates the protection policy of the instance to match its InstanceProtectionAnnotation.
+// The instances of a scale set in the Flexible orchestration mode can't be protected, and the policy of an instance
+// can't be updated until it is provisioned.
+func (s *Service) reconcileProtectionPolicy(ctx context.Context, instance *azure.VMSSVM) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.reconcileProtectionPolicy")
+	defer done()
+
+	if s.Scope.OrchestrationMode() == infrav1exp.FlexibleOrchestrationMode || instance.State != infrav1.Succeeded {
+		return nil
+	}
+
+	protection, err := s.Scope.InstanceProtection(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the protection of the instance")
+	}
+
+	policy := compute.VirtualMachineScaleSetVMProtectionPolicy{
+		ProtectFromScaleIn:         to.BoolPtr(protection != ""),
+		ProtectFromScaleSetActions: to.BoolPtr(protection == infrav1exp.InstanceProtectionScaleSetActions),
+	}
+	if *policy.ProtectFromScaleIn == instance.ProtectFromScaleIn && *policy.ProtectFromScaleSetActions == instance.ProtectFromScaleSetActions {
+		return nil
+	}
+
+	log.Info("updating the protection policy of the instance", "protection", protection)
+	if err := s.Client.UpdateProtectionPolicy(ctx, s.Scope.ResourceGroup(), s.Scope.ScaleSetName(), s.Scope.InstanceID(), policy); err != nil {
+		return errors.Wrap(err, "failed to update the protection policy of the instance")
+	}
+	instance.ProtectFromScaleIn = *policy.ProtectFromScaleIn
+	instance.ProtectFromScaleSetActions = *policy.ProtectFromScaleSetActions
+	return nil
+}
+
+// getInstance gets the instance of the scale set. The instances of a scale set in the Flexible orchestration mode are
+// standard VMs, which are identified by their name.
+func (s *Service) getInstance(ctx context.Context, resourceGroup, vmssName, instanceID string) (*azure.VMSSVM, error) {
+	if s.Scope.OrchestrationMode() == infrav1exp.FlexibleOrchestrationMode {
+		vm, err := s.Client.GetVM(ctx, resourceGroup, instanceID)
+		if err != nil {
+			return nil, err
+		}
+		return converters.SDKVMToVMSSVM(vm), nil
+	}
+
+	instance, err := s.Client.Get(ctx, resourceGroup, vmssName, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return converters.SDKToVMSSVM(instance), nil
+}
+
+// Remediate reimages or redeploys the failed instance in place, following the remediation strategy of the pool, and
+// waits for the operation to complete. It is a no-op unless the instance is remediated in place.
+func (s *Service) Remediate(ctx context.Context) error {
+	var (
+		resourceGroup = s.Scope.ResourceGroup()
+		vmssName      = s.Scope.ScaleSetName()
+		instanceID    = s.Scope.InstanceID()
+	)
+
+	ctx, log, done := tele.StartSpanWithLogger(
+		ctx,
+		"scalesetvms.Service.Remediate",
+		tele.KVP("resourceGroup", resourceGroup),
+		tele.KVP("scaleset", vmssName),
+		tele.KVP("instanceID", instanceID),
+	)
+	defer done()
+
+	future := s.Scope.GetLongRunningOperationState(instanceID, serviceName)
+	if future == nil {
+		strategy := s.Scope.InPlaceRemediation()
+		if strategy == "" {
+			return nil
+		}
+
+		var err error
+		switch {
+		case strategy == infrav1exp.ReimageRemediationStrategyType:
+			future, err = s.Client.ReimageAsync(ctx, resourceGroup, vmssName, instanceID)
+		case s.Scope.OrchestrationMode() == infrav1exp.FlexibleOrchestrationMode:
+			future, err = s.Client.RedeployVMAsync(ctx, resourceGroup, instanceID)
+		default:
+			future, err = s.Client.RedeployAsync(ctx, resourceGroup, vmssName, instanceID)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to remediate instance %s/%s", vmssName, instanceID)
+		}
+
+		log.Info("remediating failed instance in place", "strategy", strategy)
+		s.Scope.SetLongRunningOperationState(future)
+		s.Scope.SetInPlaceRemediationStarted(strategy)
+	} else if future.Type != infrav1.PostFuture {
+		return nil
+	}
+
+	log.V(4).Info("checking if the instance is done being remediated")
+	if _, err := s.Client.GetResultIfDone(ctx, future); err != nil {
+		if !azure.IsOperationNotDoneError(err) {
+			// the remediation failed, the instance is deleted if it is still failed.
+			s.Scope.DeleteLongRunningOperationState(instanceID, serviceName)
+		}
+		return errors.Wrap(err, "failed to get result of long running operation")
+	}
+
+	log.V(4).Info("successfully remediated the instance")
+	s.Scope.DeleteLongRunningOperationState(instanceID, serviceName)
+
+	// fetch the state of the instance following its remediation
+	instance, err := s.getInstance(ctx, resourceGroup, vmssName, instanceID)
+	if err != nil {
+		return errors.Wrap(err, "failed getting instance")
+	}
+	s.Scope.SetVMSSVM(instance)
 	return nil
 }
 
@@ -97,14 +222,20 @@ func (s *Service) Delete(ctx context.Context) error {
 	defer done()
 
 	defer func() {
-		if instance, err := s.Client.Get(ctx, resourceGroup, vmssName, instanceID); err == nil && instance.VirtualMachineScaleSetVMProperties != nil {
-			log.V(4).Info("updating vmss vm state", "state", instance.ProvisioningState)
-			s.Scope.SetVMSSVM(converters.SDKToVMSSVM(instance))
+		if instance, err := s.getInstance(ctx, resourceGroup, vmssName, instanceID); err == nil && instance.State != "" {
+			log.V(4).Info("updating vmss vm state", "state", instance.State)
+			s.Scope.SetVMSSVM(instance)
 		}
 	}()
 
 	log.V(4).Info("entering delete")
 	future := s.Scope.GetLongRunningOperationState(instanceID, serviceName)
+	if future != nil && future.Type == infrav1.PostFuture {
+		// the deletion of the instance supersedes its remediation.
+		log.V(4).Info("abandoning the remediation of the instance")
+		s.Scope.DeleteLongRunningOperationState(instanceID, serviceName)
+		future = nil
+	}
 	if future != nil {
 		if future.Type != infrav1.DeleteFuture {
 			return azure.WithTransientError(errors.New("attempting to delete, non-delete operation in progress"), 30*time.Second)
@@ -123,7 +254,12 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 
 	// since the future was nil, there is no ongoing activity; start deleting the instance
-	future, err := s.Client.DeleteAsync(ctx, resourceGroup, vmssName, instanceID)
+	var err error
+	if s.Scope.OrchestrationMode() == infrav1exp.FlexibleOrchestrationMode {
+		future, err = s.Client.DeleteVMAsync(ctx, resourceGroup, instanceID, s.Scope.ForceDelete())
+	} else {
+		future, err = s.Client.DeleteAsync(ctx, resourceGroup, vmssName, instanceID, s.Scope.ForceDelete())
+	}
 	if err != nil {
 		if azure.ResourceNotFound(err) {
 			// already deleted
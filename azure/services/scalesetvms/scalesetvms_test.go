@@ -108,6 +108,104 @@ func TestService_Reconcile(t *testing.T) {
 				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
 			},
 		},
+		{
+			Name: "should reconcile the VM of a Flexible scale set successfully",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("scaleset_1a2b3c4d")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1exp.FlexibleOrchestrationMode).AnyTimes()
+				vm := compute.VirtualMachine{
+					Name: to.StringPtr("scaleset_1a2b3c4d"),
+				}
+				m.GetVM(gomock2.AContext(), "rg", "scaleset_1a2b3c4d").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKVMToVMSSVM(vm))
+			},
+		},
+		{
+			Name: "should protect the instance following its annotation",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg").Times(2)
+				s.InstanceID().Return("0").Times(2)
+				s.ScaleSetName().Return("scaleset").Times(2)
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: to.StringPtr("0"),
+					VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+						ProvisioningState: to.StringPtr("Succeeded"),
+					},
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(gomock.Any())
+				s.InstanceProtection(gomock2.AContext()).Return(infrav1exp.InstanceProtectionScaleIn, nil)
+				m.UpdateProtectionPolicy(gomock2.AContext(), "rg", "scaleset", "0", compute.VirtualMachineScaleSetVMProtectionPolicy{
+					ProtectFromScaleIn:         to.BoolPtr(true),
+					ProtectFromScaleSetActions: to.BoolPtr(false),
+				}).Return(nil)
+			},
+		},
+		{
+			Name: "should not update the protection policy of an instance already protected",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: to.StringPtr("0"),
+					VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+						ProvisioningState: to.StringPtr("Succeeded"),
+						ProtectionPolicy: &compute.VirtualMachineScaleSetVMProtectionPolicy{
+							ProtectFromScaleIn:         to.BoolPtr(true),
+							ProtectFromScaleSetActions: to.BoolPtr(true),
+						},
+					},
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				s.InstanceProtection(gomock2.AContext()).Return(infrav1exp.InstanceProtectionScaleSetActions, nil)
+			},
+		},
+		{
+			Name: "should remove the protection of an instance which is no longer annotated",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg").Times(2)
+				s.InstanceID().Return("0").Times(2)
+				s.ScaleSetName().Return("scaleset").Times(2)
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: to.StringPtr("0"),
+					VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+						ProvisioningState: to.StringPtr("Succeeded"),
+						ProtectionPolicy: &compute.VirtualMachineScaleSetVMProtectionPolicy{
+							ProtectFromScaleIn: to.BoolPtr(true),
+						},
+					},
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(gomock.Any())
+				s.InstanceProtection(gomock2.AContext()).Return("", nil)
+				m.UpdateProtectionPolicy(gomock2.AContext(), "rg", "scaleset", "0", compute.VirtualMachineScaleSetVMProtectionPolicy{
+					ProtectFromScaleIn:         to.BoolPtr(false),
+					ProtectFromScaleSetActions: to.BoolPtr(false),
+				}).Return(nil)
+			},
+		},
+		{
+			Name: "if the protection of the instance can't be read, then should respond with error",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: to.StringPtr("0"),
+					VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+						ProvisioningState: to.StringPtr("Succeeded"),
+					},
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				s.InstanceProtection(gomock2.AContext()).Return("", errors.New("boom"))
+			},
+			Err: errors.Wrap(errors.New("boom"), "failed to get the protection of the instance"),
+		},
 		{
 			Name: "if 404, then should respond with transient error",
 			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
@@ -148,6 +246,7 @@ func TestService_Reconcile(t *testing.T) {
 			service := NewService(scopeMock)
 			service.Client = clientMock
 			c.Setup(scopeMock.EXPECT(), clientMock.EXPECT())
+			scopeMock.EXPECT().OrchestrationMode().Return(infrav1exp.UniformOrchestrationMode).AnyTimes()
 
 			if err := service.Reconcile(context.TODO()); c.Err == nil {
 				g.Expect(err).To(Succeed())
@@ -179,7 +278,7 @@ func TestService_Delete(t *testing.T) {
 				future := &infrav1.Future{
 					Type: infrav1.DeleteFuture,
 				}
-				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(future, nil)
+				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0", false).Return(future, nil)
 				s.SetLongRunningOperationState(future)
 				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second))
 				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
@@ -211,7 +310,7 @@ func TestService_Delete(t *testing.T) {
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
 				s.GetLongRunningOperationState("0", serviceName).Return(nil)
-				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(nil, autorest404)
+				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0", false).Return(nil, autorest404)
 				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
 			},
 		},
@@ -222,7 +321,7 @@ func TestService_Delete(t *testing.T) {
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
 				s.GetLongRunningOperationState("0", serviceName).Return(nil)
-				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(nil, errors.New("boom"))
+				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0", false).Return(nil, errors.New("boom"))
 				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
 			},
 			Err: errors.Wrap(errors.New("boom"), "failed to delete instance scaleset/0"),
@@ -242,6 +341,61 @@ func TestService_Delete(t *testing.T) {
 			},
 			Err: errors.Wrap(errors.New("boom"), "failed to get result of long running operation"),
 		},
+		{
+			Name: "should delete the instance when it is being remediated",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.GetLongRunningOperationState("0", serviceName).Return(&infrav1.Future{
+					Type: infrav1.PostFuture,
+				})
+				s.DeleteLongRunningOperationState("0", serviceName).Times(2)
+				future := &infrav1.Future{
+					Type: infrav1.DeleteFuture,
+				}
+				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0", false).Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, nil)
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, autorest404)
+			},
+		},
+		{
+			Name: "should force delete the instance when the scale-in policy requires it",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.ForceDelete().Return(true).AnyTimes()
+				s.GetLongRunningOperationState("0", serviceName).Return(nil)
+				future := &infrav1.Future{
+					Type: infrav1.DeleteFuture,
+				}
+				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0", true).Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, nil)
+				s.DeleteLongRunningOperationState("0", serviceName)
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, autorest404)
+			},
+		},
+		{
+			Name: "should start deleting the VM of a Flexible scale set successfully if no long running operation is active",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("scaleset_1a2b3c4d")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1exp.FlexibleOrchestrationMode).AnyTimes()
+				s.GetLongRunningOperationState("scaleset_1a2b3c4d", serviceName).Return(nil)
+				future := &infrav1.Future{
+					Type: infrav1.DeleteFuture,
+				}
+				m.DeleteVMAsync(gomock2.AContext(), "rg", "scaleset_1a2b3c4d", false).Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, nil)
+				s.DeleteLongRunningOperationState("scaleset_1a2b3c4d", serviceName)
+				m.GetVM(gomock2.AContext(), "rg", "scaleset_1a2b3c4d").Return(compute.VirtualMachine{}, autorest404)
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -261,6 +415,8 @@ func TestService_Delete(t *testing.T) {
 			service := NewService(scopeMock)
 			service.Client = clientMock
 			c.Setup(scopeMock.EXPECT(), clientMock.EXPECT())
+			scopeMock.EXPECT().OrchestrationMode().Return(infrav1exp.UniformOrchestrationMode).AnyTimes()
+			scopeMock.EXPECT().ForceDelete().Return(false).AnyTimes()
 
 			if err := service.Delete(context.TODO()); c.Err == nil {
 				g.Expect(err).To(Succeed())
@@ -274,3 +430,127 @@ func TestService_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Remediate(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Setup      func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder)
+		Err        error
+		CheckIsErr bool
+	}{
+		{
+			Name: "should do nothing if the instance is not remediated in place",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.GetLongRunningOperationState("0", serviceName).Return(nil)
+				s.InPlaceRemediation().Return(infrav1exp.RemediationStrategyType(""))
+			},
+		},
+		{
+			Name: "should start reimaging the failed instance",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.GetLongRunningOperationState("0", serviceName).Return(nil)
+				s.InPlaceRemediation().Return(infrav1exp.ReimageRemediationStrategyType)
+				future := &infrav1.Future{
+					Type: infrav1.PostFuture,
+				}
+				m.ReimageAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				s.SetInPlaceRemediationStarted(infrav1exp.ReimageRemediationStrategyType)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second))
+			},
+			CheckIsErr: true,
+			Err: errors.Wrap(azure.WithTransientError(azure.NewOperationNotDoneError(&infrav1.Future{
+				Type: infrav1.PostFuture,
+			}), 15*time.Second), "failed to get result of long running operation"),
+		},
+		{
+			Name: "should redeploy the failed instance",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.GetLongRunningOperationState("0", serviceName).Return(nil)
+				s.InPlaceRemediation().Return(infrav1exp.RedeployRemediationStrategyType)
+				future := &infrav1.Future{
+					Type: infrav1.PostFuture,
+				}
+				m.RedeployAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				s.SetInPlaceRemediationStarted(infrav1exp.RedeployRemediationStrategyType)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, nil)
+				s.DeleteLongRunningOperationState("0", serviceName)
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{InstanceID: to.StringPtr("0")}, nil)
+				s.SetVMSSVM(&azure.VMSSVM{InstanceID: "0"})
+			},
+		},
+		{
+			Name: "should redeploy the failed VM of a Flexible scale set",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.OrchestrationMode().Return(infrav1exp.FlexibleOrchestrationMode).AnyTimes()
+				s.GetLongRunningOperationState("0", serviceName).Return(nil)
+				s.InPlaceRemediation().Return(infrav1exp.RedeployRemediationStrategyType)
+				future := &infrav1.Future{
+					Type: infrav1.PostFuture,
+				}
+				m.RedeployVMAsync(gomock2.AContext(), "rg", "0").Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				s.SetInPlaceRemediationStarted(infrav1exp.RedeployRemediationStrategyType)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, nil)
+				s.DeleteLongRunningOperationState("0", serviceName)
+				m.GetVM(gomock2.AContext(), "rg", "0").Return(compute.VirtualMachine{Name: to.StringPtr("0")}, nil)
+				s.SetVMSSVM(&azure.VMSSVM{InstanceID: "0"})
+			},
+		},
+		{
+			Name: "should forget the remediation of the instance when it failed",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				future := &infrav1.Future{
+					Type: infrav1.PostFuture,
+				}
+				s.GetLongRunningOperationState("0", serviceName).Return(future)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, errors.New("boom"))
+				s.DeleteLongRunningOperationState("0", serviceName)
+			},
+			Err: errors.Wrap(errors.New("boom"), "failed to get result of long running operation"),
+		},
+		{
+			Name: "should do nothing while another operation is running on the instance",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.GetLongRunningOperationState("0", serviceName).Return(&infrav1.Future{
+					Type: infrav1.DeleteFuture,
+				})
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var (
+				g          = NewWithT(t)
+				mockCtrl   = gomock.NewController(t)
+				scopeMock  = mock_scalesetvms.NewMockScaleSetVMScope(mockCtrl)
+				clientMock = mock_scalesetvms.NewMockclient(mockCtrl)
+			)
+			defer mockCtrl.Finish()
+
+			scopeMock.EXPECT().SubscriptionID().Return("subID")
+			scopeMock.EXPECT().BaseURI().Return("https://localhost/")
+			scopeMock.EXPECT().Authorizer().Return(nil)
+
+			service := NewService(scopeMock)
+			service.Client = clientMock
+			scopeMock.EXPECT().ResourceGroup().Return("rg")
+			scopeMock.EXPECT().InstanceID().Return("0")
+			scopeMock.EXPECT().ScaleSetName().Return("scaleset")
+			c.Setup(scopeMock.EXPECT(), clientMock.EXPECT())
+			scopeMock.EXPECT().OrchestrationMode().Return(infrav1exp.UniformOrchestrationMode).AnyTimes()
+
+			if err := service.Remediate(context.TODO()); c.Err == nil {
+				g.Expect(err).To(Succeed())
+			} else {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(c.Err.Error()))
+				if c.CheckIsErr {
+					g.Expect(errors.Is(err, c.Err)).To(BeTrue())
+				}
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -49,22 +50,24 @@ func newClient(auth azure.Authorizer) *azureClient {
 // newSecurityGroupsClient creates a new security groups client from subscription ID.
 func newSecurityGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.SecurityGroupsClient {
 	securityGroupsClient := network.NewSecurityGroupsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&securityGroupsClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&securityGroupsClient.Client, authorizer, "securitygroups")
 	return securityGroupsClient
 }
 
 // Get gets the specified network security group.
-func (ac *azureClient) Get(ctx context.Context, resourceGroupName, sgName string) (network.SecurityGroup, error) {
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, sgName string) (_ network.SecurityGroup, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "securitygroups.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.securitygroups.Get(ctx, resourceGroupName, sgName, "")
 }
 
 // CreateOrUpdate creates or updates a network security group in the specified resource group.
-func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, sgName string, sg network.SecurityGroup) error {
+func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, sgName string, sg network.SecurityGroup) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "securitygroups.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	var etag string
 	if sg.Etag != nil {
@@ -94,9 +97,10 @@ func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName str
 }
 
 // Delete deletes the specified network security group.
-func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, sgName string) error {
+func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, sgName string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "securitygroups.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.securitygroups.Delete(ctx, resourceGroupName, sgName)
 	if err != nil {
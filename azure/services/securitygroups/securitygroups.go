@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -31,7 +32,7 @@ import (
 // NSGScope defines the scope interface for a security groups service.
 type NSGScope interface {
 	azure.ClusterDescriber
-	azure.NetworkDescriber
+	IsVnetManaged() bool
 	NSGSpecs() []azure.NSGSpec
 }
 
@@ -71,19 +72,11 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			// security group already exists
 			// We append the existing NSG etag to the header to ensure we only apply the updates if the NSG has not been modified.
 			etag = existingNSG.Etag
-			// Check if the expected rules are present
-			update := false
-			securityRules = *existingNSG.SecurityRules
-			for _, rule := range nsgSpec.SecurityRules {
-				sdkRule := converters.SecurityRuleToSDK(rule)
-				if !ruleExists(securityRules, sdkRule) {
-					update = true
-					securityRules = append(securityRules, sdkRule)
-				}
-			}
+			// Only the rules declared in the spec and the default rules are reconciled, any other rule is left untouched.
+			var update bool
+			securityRules, update = mergeSecurityRules(existingNSG.SecurityRules, nsgSpec.SecurityRules)
 			if !update {
-				// Skip update for NSG as the required default rules are present
-				log.V(2).Info("security group exists and no default rules are missing, skipping update", "security group", nsgSpec.Name)
+				log.V(2).Info("security group exists and its security rules are up to date, skipping update", "security group", nsgSpec.Name)
 				continue
 			}
 		default:
@@ -109,27 +102,80 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
-func ruleExists(rules []network.SecurityRule, rule network.SecurityRule) bool {
-	for _, existingRule := range rules {
-		if !strings.EqualFold(to.String(existingRule.Name), to.String(rule.Name)) {
-			continue
-		}
-		if !strings.EqualFold(to.String(existingRule.DestinationPortRange), to.String(rule.DestinationPortRange)) {
+// mergeSecurityRules returns the rules of an existing security group updated with the desired rules, and whether they
+// changed. An existing rule is replaced by the desired rule with the same name if they differ, and dropped if it is a
+// default rule which is no longer desired, e.g. because it was disabled. Missing desired rules are appended.
+func mergeSecurityRules(existing *[]network.SecurityRule, desired infrav1.SecurityRules) ([]network.SecurityRule, bool) {
+	desiredRules := make(map[string]network.SecurityRule, len(desired))
+	for _, rule := range desired {
+		desiredRules[strings.ToLower(rule.Name)] = converters.SecurityRuleToSDK(rule)
+	}
+
+	var existingRules []network.SecurityRule
+	if existing != nil {
+		existingRules = *existing
+	}
+	merged := make([]network.SecurityRule, 0, len(existingRules)+len(desired))
+	found := make(map[string]bool, len(desired))
+	changed := false
+	for _, rule := range existingRules {
+		name := strings.ToLower(to.String(rule.Name))
+		if desiredRule, ok := desiredRules[name]; ok {
+			found[name] = true
+			if !ruleEqual(rule, desiredRule) {
+				rule = desiredRule
+				changed = true
+			}
+		} else if isDefaultRule(rule) {
+			changed = true
 			continue
 		}
-		if existingRule.Protocol != network.SecurityRuleProtocolTCP &&
-			existingRule.Access != network.SecurityRuleAccessAllow &&
-			existingRule.Direction != network.SecurityRuleDirectionInbound {
-			continue
+		merged = append(merged, rule)
+	}
+	for _, rule := range desired {
+		if !found[strings.ToLower(rule.Name)] {
+			merged = append(merged, converters.SecurityRuleToSDK(rule))
+			changed = true
 		}
-		if !strings.EqualFold(to.String(existingRule.SourcePortRange), "*") &&
-			!strings.EqualFold(to.String(existingRule.SourceAddressPrefix), "*") &&
-			!strings.EqualFold(to.String(existingRule.DestinationAddressPrefix), "*") {
-			continue
+	}
+
+	return merged, changed
+}
+
+// isDefaultRule returns true if the rule is one of the default rules CAPZ adds to the control plane security group,
+// including the rules allowing the management network and the CIDR blocks of the API server access profile to reach
+// the API server.
+func isDefaultRule(rule network.SecurityRule) bool {
+	if rule.SecurityRulePropertiesFormat == nil || rule.Priority == nil {
+		return false
+	}
+	if *rule.Priority < infrav1.DefaultSecurityRulePriorityMin || *rule.Priority > infrav1.DefaultSecurityRulePriorityMax {
+		return false
+	}
+	for _, name := range infrav1.DefaultSecurityRuleNames {
+		if strings.EqualFold(to.String(rule.Name), name) {
+			return true
 		}
-		return true
 	}
-	return false
+	name := strings.ToLower(to.String(rule.Name))
+	return strings.HasPrefix(name, infrav1.SecurityRuleAllowManagementAPIServer+"_") ||
+		strings.HasPrefix(name, infrav1.SecurityRuleAllowAPIServer+"_")
+}
+
+// ruleEqual returns true if the existing rule has the same properties as the desired one.
+func ruleEqual(existing, desired network.SecurityRule) bool {
+	if existing.SecurityRulePropertiesFormat == nil {
+		return false
+	}
+	return existing.Protocol == desired.Protocol &&
+		existing.Direction == desired.Direction &&
+		existing.Access == desired.Access &&
+		to.Int32(existing.Priority) == to.Int32(desired.Priority) &&
+		to.String(existing.Description) == to.String(desired.Description) &&
+		strings.EqualFold(to.String(existing.SourceAddressPrefix), to.String(desired.SourceAddressPrefix)) &&
+		strings.EqualFold(to.String(existing.SourcePortRange), to.String(desired.SourcePortRange)) &&
+		strings.EqualFold(to.String(existing.DestinationAddressPrefix), to.String(desired.DestinationAddressPrefix)) &&
+		strings.EqualFold(to.String(existing.DestinationPortRange), to.String(desired.DestinationPortRange))
 }
 
 // Delete deletes the network security group with the provided name.
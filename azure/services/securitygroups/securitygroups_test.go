@@ -220,6 +220,288 @@ func TestReconcileSecurityGroups(t *testing.T) {
 					Name: to.StringPtr("nsg-two"),
 				}, nil)
 			},
+		}, {
+			name: "security group exists with outdated, disabled and stale default rules",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, m *mock_securitygroups.MockclientMockRecorder) {
+				s.NSGSpecs().Return([]azure.NSGSpec{
+					{
+						Name: "nsg-one",
+						SecurityRules: infrav1.SecurityRules{
+							{
+								Name:             "first-rule",
+								Description:      "a test rule",
+								Protocol:         infrav1.SecurityGroupProtocolTCP,
+								Priority:         400,
+								SourcePorts:      to.StringPtr("*"),
+								DestinationPorts: to.StringPtr("443"),
+								Source:           to.StringPtr("10.0.0.0/8"),
+								Destination:      to.StringPtr("*"),
+								Direction:        infrav1.SecurityRuleDirectionInbound,
+							},
+							{
+								Name:             "allow_apiserver",
+								Description:      "Allow K8s API Server",
+								Protocol:         infrav1.SecurityGroupProtocolTCP,
+								Priority:         2201,
+								SourcePorts:      to.StringPtr("*"),
+								DestinationPorts: to.StringPtr("6443"),
+								Source:           to.StringPtr("*"),
+								Destination:      to.StringPtr("*"),
+								Direction:        infrav1.SecurityRuleDirectionInbound,
+							},
+						},
+					},
+				})
+				s.IsVnetManaged().Return(true)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "nsg-one").Return(network.SecurityGroup{
+					SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+						SecurityRules: &[]network.SecurityRule{
+							{
+								SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+									Description:              to.StringPtr("Allow SSH"),
+									SourcePortRange:          to.StringPtr("*"),
+									DestinationPortRange:     to.StringPtr("22"),
+									SourceAddressPrefix:      to.StringPtr("*"),
+									DestinationAddressPrefix: to.StringPtr("*"),
+									Protocol:                 network.SecurityRuleProtocolTCP,
+									Direction:                network.SecurityRuleDirectionInbound,
+									Access:                   network.SecurityRuleAccessAllow,
+									Priority:                 to.Int32Ptr(2200),
+								},
+								Name: to.StringPtr("allow_ssh"),
+							},
+							{
+								SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+									Description:              to.StringPtr("Allow K8s API Server from the management cluster"),
+									SourcePortRange:          to.StringPtr("*"),
+									DestinationPortRange:     to.StringPtr("6443"),
+									SourceAddressPrefix:      to.StringPtr("192.168.0.0/16"),
+									DestinationAddressPrefix: to.StringPtr("*"),
+									Protocol:                 network.SecurityRuleProtocolTCP,
+									Direction:                network.SecurityRuleDirectionInbound,
+									Access:                   network.SecurityRuleAccessAllow,
+									Priority:                 to.Int32Ptr(2203),
+								},
+								Name: to.StringPtr("allow_management_apiserver_1"),
+							},
+							{
+								SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+									Description:              to.StringPtr("a test rule"),
+									SourcePortRange:          to.StringPtr("*"),
+									DestinationPortRange:     to.StringPtr("443"),
+									SourceAddressPrefix:      to.StringPtr("*"),
+									DestinationAddressPrefix: to.StringPtr("*"),
+									Protocol:                 network.SecurityRuleProtocolTCP,
+									Direction:                network.SecurityRuleDirectionInbound,
+									Access:                   network.SecurityRuleAccessAllow,
+									Priority:                 to.Int32Ptr(400),
+								},
+								Name: to.StringPtr("first-rule"),
+							},
+							{
+								SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+									Description:              to.StringPtr("added outside of CAPZ"),
+									SourcePortRange:          to.StringPtr("*"),
+									DestinationPortRange:     to.StringPtr("*"),
+									SourceAddressPrefix:      to.StringPtr("*"),
+									DestinationAddressPrefix: to.StringPtr("*"),
+									Protocol:                 network.SecurityRuleProtocolAsterisk,
+									Direction:                network.SecurityRuleDirectionOutbound,
+									Access:                   network.SecurityRuleAccessDeny,
+									Priority:                 to.Int32Ptr(2250),
+								},
+								Name: to.StringPtr("foo-rule"),
+							},
+						},
+					},
+					Etag: to.StringPtr("test-etag"),
+					ID:   to.StringPtr("fake/nsg/id"),
+					Name: to.StringPtr("nsg-one"),
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "nsg-one", gomockinternal.DiffEq(network.SecurityGroup{
+					SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+						SecurityRules: &[]network.SecurityRule{
+							{
+								SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+									Description:              to.StringPtr("a test rule"),
+									SourcePortRange:          to.StringPtr("*"),
+									DestinationPortRange:     to.StringPtr("443"),
+									SourceAddressPrefix:      to.StringPtr("10.0.0.0/8"),
+									DestinationAddressPrefix: to.StringPtr("*"),
+									Protocol:                 network.SecurityRuleProtocolTCP,
+									Direction:                network.SecurityRuleDirectionInbound,
+									Access:                   network.SecurityRuleAccessAllow,
+									Priority:                 to.Int32Ptr(400),
+								},
+								Name: to.StringPtr("first-rule"),
+							},
+							{
+								SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+									Description:              to.StringPtr("added outside of CAPZ"),
+									SourcePortRange:          to.StringPtr("*"),
+									DestinationPortRange:     to.StringPtr("*"),
+									SourceAddressPrefix:      to.StringPtr("*"),
+									DestinationAddressPrefix: to.StringPtr("*"),
+									Protocol:                 network.SecurityRuleProtocolAsterisk,
+									Direction:                network.SecurityRuleDirectionOutbound,
+									Access:                   network.SecurityRuleAccessDeny,
+									Priority:                 to.Int32Ptr(2250),
+								},
+								Name: to.StringPtr("foo-rule"),
+							},
+							{
+								SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+									Description:              to.StringPtr("Allow K8s API Server"),
+									SourcePortRange:          to.StringPtr("*"),
+									DestinationPortRange:     to.StringPtr("6443"),
+									SourceAddressPrefix:      to.StringPtr("*"),
+									DestinationAddressPrefix: to.StringPtr("*"),
+									Protocol:                 network.SecurityRuleProtocolTCP,
+									Direction:                network.SecurityRuleDirectionInbound,
+									Access:                   network.SecurityRuleAccessAllow,
+									Priority:                 to.Int32Ptr(2201),
+								},
+								Name: to.StringPtr("allow_apiserver"),
+							},
+						},
+					},
+					Etag:     to.StringPtr("test-etag"),
+					Location: to.StringPtr("test-location"),
+				}))
+			},
+		}, {
+			name: "rules of the allowed CIDR blocks are removed when the API server is reachable from anywhere",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, m *mock_securitygroups.MockclientMockRecorder) {
+				s.NSGSpecs().Return([]azure.NSGSpec{
+					{
+						Name: "nsg-one",
+						SecurityRules: infrav1.SecurityRules{
+							{
+								Name:             "allow_apiserver",
+								Description:      "Allow K8s API Server",
+								Protocol:         infrav1.SecurityGroupProtocolTCP,
+								Priority:         2201,
+								SourcePorts:      to.StringPtr("*"),
+								DestinationPorts: to.StringPtr("6443"),
+								Source:           to.StringPtr("*"),
+								Destination:      to.StringPtr("*"),
+								Direction:        infrav1.SecurityRuleDirectionInbound,
+							},
+						},
+					},
+				})
+				s.IsVnetManaged().Return(true)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "nsg-one").Return(network.SecurityGroup{
+					SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+						SecurityRules: &[]network.SecurityRule{
+							{
+								SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+									Description:              to.StringPtr("Allow K8s API Server from an allowed CIDR block"),
+									SourcePortRange:          to.StringPtr("*"),
+									DestinationPortRange:     to.StringPtr("6443"),
+									SourceAddressPrefix:      to.StringPtr("203.0.113.0/24"),
+									DestinationAddressPrefix: to.StringPtr("*"),
+									Protocol:                 network.SecurityRuleProtocolTCP,
+									Direction:                network.SecurityRuleDirectionInbound,
+									Access:                   network.SecurityRuleAccessAllow,
+									Priority:                 to.Int32Ptr(2201),
+								},
+								Name: to.StringPtr("allow_apiserver_0"),
+							},
+							{
+								SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+									Description:              to.StringPtr("Allow K8s API Server from an allowed CIDR block"),
+									SourcePortRange:          to.StringPtr("*"),
+									DestinationPortRange:     to.StringPtr("6443"),
+									SourceAddressPrefix:      to.StringPtr("198.51.100.7/32"),
+									DestinationAddressPrefix: to.StringPtr("*"),
+									Protocol:                 network.SecurityRuleProtocolTCP,
+									Direction:                network.SecurityRuleDirectionInbound,
+									Access:                   network.SecurityRuleAccessAllow,
+									Priority:                 to.Int32Ptr(2202),
+								},
+								Name: to.StringPtr("allow_apiserver_1"),
+							},
+						},
+					},
+					Etag: to.StringPtr("test-etag"),
+					ID:   to.StringPtr("fake/nsg/id"),
+					Name: to.StringPtr("nsg-one"),
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "nsg-one", gomockinternal.DiffEq(network.SecurityGroup{
+					SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+						SecurityRules: &[]network.SecurityRule{
+							{
+								SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+									Description:              to.StringPtr("Allow K8s API Server"),
+									SourcePortRange:          to.StringPtr("*"),
+									DestinationPortRange:     to.StringPtr("6443"),
+									SourceAddressPrefix:      to.StringPtr("*"),
+									DestinationAddressPrefix: to.StringPtr("*"),
+									Protocol:                 network.SecurityRuleProtocolTCP,
+									Direction:                network.SecurityRuleDirectionInbound,
+									Access:                   network.SecurityRuleAccessAllow,
+									Priority:                 to.Int32Ptr(2201),
+								},
+								Name: to.StringPtr("allow_apiserver"),
+							},
+						},
+					},
+					Etag:     to.StringPtr("test-etag"),
+					Location: to.StringPtr("test-location"),
+				}))
+			},
+		}, {
+			name: "security group exists and is up to date",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, m *mock_securitygroups.MockclientMockRecorder) {
+				s.NSGSpecs().Return([]azure.NSGSpec{
+					{
+						Name: "nsg-one",
+						SecurityRules: infrav1.SecurityRules{
+							{
+								Name:             "allow_ssh",
+								Description:      "Allow SSH",
+								Protocol:         infrav1.SecurityGroupProtocolTCP,
+								Priority:         2200,
+								SourcePorts:      to.StringPtr("*"),
+								DestinationPorts: to.StringPtr("22"),
+								Source:           to.StringPtr("*"),
+								Destination:      to.StringPtr("*"),
+								Direction:        infrav1.SecurityRuleDirectionInbound,
+							},
+						},
+					},
+				})
+				s.IsVnetManaged().Return(true)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "nsg-one").Return(network.SecurityGroup{
+					SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+						SecurityRules: &[]network.SecurityRule{
+							{
+								SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+									Description:              to.StringPtr("Allow SSH"),
+									SourcePortRange:          to.StringPtr("*"),
+									DestinationPortRange:     to.StringPtr("22"),
+									SourceAddressPrefix:      to.StringPtr("*"),
+									DestinationAddressPrefix: to.StringPtr("*"),
+									Protocol:                 network.SecurityRuleProtocolTCP,
+									Direction:                network.SecurityRuleDirectionInbound,
+									Access:                   network.SecurityRuleAccessAllow,
+									Priority:                 to.Int32Ptr(2200),
+								},
+								Name: to.StringPtr("allow_ssh"),
+							},
+						},
+					},
+					Etag: to.StringPtr("test-etag"),
+					ID:   to.StringPtr("fake/nsg/id"),
+					Name: to.StringPtr("nsg-one"),
+				}, nil)
+			},
 		}, {
 			name: "skipping network security group reconcile in custom VNet mode",
 			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, m *mock_securitygroups.MockclientMockRecorder) {
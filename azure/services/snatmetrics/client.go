@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snatmetrics
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	ListMetrics(ctx context.Context, resourceID, timespan, interval, metricName, aggregation, filter string) (insights.Response, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	metrics insights.MetricsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new metrics client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newMetricsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c}
+}
+
+// newMetricsClient creates a new metrics client from subscription ID.
+func newMetricsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) insights.MetricsClient {
+	metricsClient := insights.NewMetricsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&metricsClient.Client, authorizer, "snatmetrics")
+	return metricsClient
+}
+
+// ListMetrics lists the data points of a metric of a resource over the timespan, split by the dimensions of the filter.
+func (ac *azureClient) ListMetrics(ctx context.Context, resourceID, timespan, interval, metricName, aggregation, filter string) (_ insights.Response, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "snatmetrics.AzureClient.ListMetrics")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.metrics.List(ctx, resourceID, timespan, &interval, metricName, aggregation, nil, "", filter, insights.Data, "")
+}
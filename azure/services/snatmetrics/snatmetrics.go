@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snatmetrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// metricsInterval is the granularity of the collected metrics, only the most recent interval is exported.
+	metricsInterval = 5 * time.Minute
+	// metricsTimespan is how far back the metrics are queried, to account for the latency of Azure Monitor.
+	metricsTimespan = 2 * metricsInterval
+)
+
+var (
+	snatAllocatedPorts = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "capz_cluster_snat_allocated_ports",
+			Help: "Highest number of SNAT ports allocated to a backend instance of the outbound load balancers of the clusters.",
+		},
+		[]string{"namespace", "cluster", "resource"},
+	)
+	snatUsedPorts = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "capz_cluster_snat_used_ports",
+			Help: "Highest number of SNAT ports used by a backend instance of the outbound load balancers of the clusters.",
+		},
+		[]string{"namespace", "cluster", "resource"},
+	)
+	snatFailedConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "capz_cluster_snat_failed_connections",
+			Help: "Number of failed SNAT connections of the outbound load balancers and NAT gateways of the clusters over the last 5 minutes.",
+		},
+		[]string{"namespace", "cluster", "resource"},
+	)
+	snatDroppedPackets = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "capz_cluster_snat_dropped_packets",
+			Help: "Number of packets dropped by the NAT gateways of the clusters over the last 5 minutes.",
+		},
+		[]string{"namespace", "cluster", "resource"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(snatAllocatedPorts, snatUsedPorts, snatFailedConnections, snatDroppedPackets)
+}
+
+// SNATMetricsScope defines the scope interface for a SNAT metrics service.
+type SNATMetricsScope interface {
+	azure.Authorizer
+	ClusterName() string
+	Namespace() string
+	SNATMetricsSpecs() []azure.SNATMetricsSpec
+}
+
+// Service collects the SNAT metrics of the outbound load balancers and NAT gateways of a cluster from Azure Monitor,
+// so that SNAT port exhaustion can be diagnosed from the management cluster.
+type Service struct {
+	Scope SNATMetricsScope
+	client
+}
+
+// New creates a new service.
+func New(scope SNATMetricsScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// snatMetric defines a metric queried from Azure Monitor and the gauge it is exported to.
+type snatMetric struct {
+	name        string
+	aggregation string
+	filter      string
+	gauge       *prometheus.GaugeVec
+}
+
+var (
+	loadBalancerMetrics = []snatMetric{
+		{name: "AllocatedSnatPorts", aggregation: "Average", filter: "BackendIPAddress eq '*'", gauge: snatAllocatedPorts},
+		{name: "UsedSnatPorts", aggregation: "Average", filter: "BackendIPAddress eq '*'", gauge: snatUsedPorts},
+		{name: "SnatConnectionCount", aggregation: "Total", filter: "ConnectionState eq 'Failed'", gauge: snatFailedConnections},
+	}
+	natGatewayMetrics = []snatMetric{
+		{name: "SNATConnectionCount", aggregation: "Total", filter: "ConnectionState eq 'Failed'", gauge: snatFailedConnections},
+		{name: "PacketDropCount", aggregation: "Total", gauge: snatDroppedPackets},
+	}
+)
+
+// Reconcile exports the SNAT metrics of the outbound load balancers and NAT gateways of the cluster. The metrics are
+// informational, so failing to collect them doesn't fail the reconcile.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "snatmetrics.Service.Reconcile")
+	defer done()
+
+	end := time.Now().UTC()
+	timespan := fmt.Sprintf("%s/%s", end.Add(-metricsTimespan).Format(time.RFC3339), end.Format(time.RFC3339))
+	for _, spec := range s.Scope.SNATMetricsSpecs() {
+		for _, metric := range metricsFor(spec) {
+			value, err := s.collect(ctx, spec, metric, timespan)
+			if err != nil {
+				log.Error(err, "failed to collect SNAT metric", "resource", spec.Name, "metric", metric.name)
+				continue
+			}
+			metric.gauge.WithLabelValues(s.Scope.Namespace(), s.Scope.ClusterName(), spec.Name).Set(value)
+		}
+	}
+
+	return nil
+}
+
+// Delete stops exporting the SNAT metrics of the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "snatmetrics.Service.Delete")
+	defer done()
+
+	for _, spec := range s.Scope.SNATMetricsSpecs() {
+		for _, metric := range metricsFor(spec) {
+			metric.gauge.DeleteLabelValues(s.Scope.Namespace(), s.Scope.ClusterName(), spec.Name)
+		}
+	}
+
+	return nil
+}
+
+// collect returns the most recent value of the metric, which is the highest one across the time series of the
+// dimensions of the filter.
+func (s *Service) collect(ctx context.Context, spec azure.SNATMetricsSpec, metric snatMetric, timespan string) (float64, error) {
+	interval := fmt.Sprintf("PT%dM", int(metricsInterval.Minutes()))
+	response, err := s.client.ListMetrics(ctx, spec.ResourceID, timespan, interval, metric.name, metric.aggregation, metric.filter)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to list metric %s of %s", metric.name, spec.Name)
+	}
+
+	var value float64
+	if response.Value == nil {
+		return value, nil
+	}
+	for _, m := range *response.Value {
+		if m.Timeseries == nil {
+			continue
+		}
+		for _, series := range *m.Timeseries {
+			if latest, ok := latestValue(series, metric.aggregation); ok && latest > value {
+				value = latest
+			}
+		}
+	}
+	return value, nil
+}
+
+// metricsFor returns the SNAT metrics of the kind of resource of the spec.
+func metricsFor(spec azure.SNATMetricsSpec) []snatMetric {
+	if spec.NATGateway {
+		return natGatewayMetrics
+	}
+	return loadBalancerMetrics
+}
+
+// latestValue returns the value of the most recent data point of the time series which has a value for the aggregation.
+func latestValue(series insights.TimeSeriesElement, aggregation string) (float64, bool) {
+	if series.Data == nil {
+		return 0, false
+	}
+	data := *series.Data
+	for i := len(data) - 1; i >= 0; i-- {
+		var value *float64
+		switch aggregation {
+		case "Average":
+			value = data[i].Average
+		case "Total":
+			value = data[i].Total
+		}
+		if value != nil {
+			return *value, true
+		}
+	}
+	return 0, false
+}
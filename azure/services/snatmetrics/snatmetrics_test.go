@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snatmetrics
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2019-06-01/insights"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/snatmetrics/mock_snatmetrics"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeLBSpec = azure.SNATMetricsSpec{
+		Name:       "my-cluster-outbound-lb",
+		ResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster-outbound-lb",
+	}
+	fakeNATGatewaySpec = azure.SNATMetricsSpec{
+		Name:       "my-natgateway",
+		ResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-natgateway",
+		NATGateway: true,
+	}
+)
+
+// metricsResponse returns a response holding a time series per list of data points.
+func metricsResponse(series ...[]insights.MetricValue) insights.Response {
+	var timeseries []insights.TimeSeriesElement
+	for i := range series {
+		timeseries = append(timeseries, insights.TimeSeriesElement{Data: &series[i]})
+	}
+	return insights.Response{Value: &[]insights.Metric{{Timeseries: &timeseries}}}
+}
+
+func TestReconcileSNATMetrics(t *testing.T) {
+	testcases := []struct {
+		name   string
+		expect func(s *mock_snatmetrics.MockSNATMetricsScopeMockRecorder, m *mock_snatmetrics.MockclientMockRecorder)
+		verify func(g *WithT, cluster string)
+	}{
+		{
+			name: "export the metrics of an outbound load balancer",
+			expect: func(s *mock_snatmetrics.MockSNATMetricsScopeMockRecorder, m *mock_snatmetrics.MockclientMockRecorder) {
+				s.SNATMetricsSpecs().Return([]azure.SNATMetricsSpec{fakeLBSpec})
+				m.ListMetrics(gomockinternal.AContext(), fakeLBSpec.ResourceID, gomock.Any(), "PT5M", "AllocatedSnatPorts", "Average", "BackendIPAddress eq '*'").Return(metricsResponse(
+					[]insights.MetricValue{{Average: to.Float64Ptr(1024)}},
+					[]insights.MetricValue{{Average: to.Float64Ptr(1024)}},
+				), nil)
+				m.ListMetrics(gomockinternal.AContext(), fakeLBSpec.ResourceID, gomock.Any(), "PT5M", "UsedSnatPorts", "Average", "BackendIPAddress eq '*'").Return(metricsResponse(
+					[]insights.MetricValue{{Average: to.Float64Ptr(12)}, {Average: to.Float64Ptr(640)}},
+					[]insights.MetricValue{{Average: to.Float64Ptr(1000)}, {Average: nil}},
+				), nil)
+				m.ListMetrics(gomockinternal.AContext(), fakeLBSpec.ResourceID, gomock.Any(), "PT5M", "SnatConnectionCount", "Total", "ConnectionState eq 'Failed'").Return(metricsResponse(
+					[]insights.MetricValue{{Total: to.Float64Ptr(3)}, {Total: to.Float64Ptr(7)}},
+				), nil)
+			},
+			verify: func(g *WithT, cluster string) {
+				g.Expect(testutil.ToFloat64(snatAllocatedPorts.WithLabelValues("default", cluster, fakeLBSpec.Name))).To(Equal(1024.0))
+				g.Expect(testutil.ToFloat64(snatUsedPorts.WithLabelValues("default", cluster, fakeLBSpec.Name))).To(Equal(1000.0))
+				g.Expect(testutil.ToFloat64(snatFailedConnections.WithLabelValues("default", cluster, fakeLBSpec.Name))).To(Equal(7.0))
+			},
+		},
+		{
+			name: "export the metrics of a NAT gateway",
+			expect: func(s *mock_snatmetrics.MockSNATMetricsScopeMockRecorder, m *mock_snatmetrics.MockclientMockRecorder) {
+				s.SNATMetricsSpecs().Return([]azure.SNATMetricsSpec{fakeNATGatewaySpec})
+				m.ListMetrics(gomockinternal.AContext(), fakeNATGatewaySpec.ResourceID, gomock.Any(), "PT5M", "SNATConnectionCount", "Total", "ConnectionState eq 'Failed'").Return(insights.Response{}, nil)
+				m.ListMetrics(gomockinternal.AContext(), fakeNATGatewaySpec.ResourceID, gomock.Any(), "PT5M", "PacketDropCount", "Total", "").Return(metricsResponse(
+					[]insights.MetricValue{{Total: to.Float64Ptr(42)}},
+				), nil)
+			},
+			verify: func(g *WithT, cluster string) {
+				g.Expect(testutil.ToFloat64(snatFailedConnections.WithLabelValues("default", cluster, fakeNATGatewaySpec.Name))).To(Equal(0.0))
+				g.Expect(testutil.ToFloat64(snatDroppedPackets.WithLabelValues("default", cluster, fakeNATGatewaySpec.Name))).To(Equal(42.0))
+			},
+		},
+		{
+			name: "collection failures don't fail the reconcile",
+			expect: func(s *mock_snatmetrics.MockSNATMetricsScopeMockRecorder, m *mock_snatmetrics.MockclientMockRecorder) {
+				s.SNATMetricsSpecs().Return([]azure.SNATMetricsSpec{fakeNATGatewaySpec})
+				m.ListMetrics(gomockinternal.AContext(), fakeNATGatewaySpec.ResourceID, gomock.Any(), "PT5M", "SNATConnectionCount", "Total", "ConnectionState eq 'Failed'").
+					Return(insights.Response{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+				m.ListMetrics(gomockinternal.AContext(), fakeNATGatewaySpec.ResourceID, gomock.Any(), "PT5M", "PacketDropCount", "Total", "").Return(metricsResponse(
+					[]insights.MetricValue{{Total: to.Float64Ptr(1)}},
+				), nil)
+			},
+			verify: func(g *WithT, cluster string) {
+				g.Expect(snatFailedConnections.DeleteLabelValues("default", cluster, fakeNATGatewaySpec.Name)).To(BeFalse())
+				g.Expect(testutil.ToFloat64(snatDroppedPackets.WithLabelValues("default", cluster, fakeNATGatewaySpec.Name))).To(Equal(1.0))
+			},
+		},
+		{
+			name: "no outbound resources",
+			expect: func(s *mock_snatmetrics.MockSNATMetricsScopeMockRecorder, m *mock_snatmetrics.MockclientMockRecorder) {
+				s.SNATMetricsSpecs().Return(nil)
+			},
+			verify: func(g *WithT, cluster string) {},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_snatmetrics.NewMockSNATMetricsScope(mockCtrl)
+			clientMock := mock_snatmetrics.NewMockclient(mockCtrl)
+
+			// the gauges are shared by the test cases, so each of them uses its own cluster.
+			cluster := tc.name
+			scopeMock.EXPECT().Namespace().AnyTimes().Return("default")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return(cluster)
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+			tc.verify(g, cluster)
+		})
+	}
+}
+
+func TestDeleteSNATMetrics(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_snatmetrics.NewMockSNATMetricsScope(mockCtrl)
+	clientMock := mock_snatmetrics.NewMockclient(mockCtrl)
+
+	cluster := "deleted-cluster"
+	snatUsedPorts.WithLabelValues("default", cluster, fakeLBSpec.Name).Set(10)
+	snatDroppedPackets.WithLabelValues("default", cluster, fakeNATGatewaySpec.Name).Set(10)
+
+	scopeMock.EXPECT().Namespace().AnyTimes().Return("default")
+	scopeMock.EXPECT().ClusterName().AnyTimes().Return(cluster)
+	scopeMock.EXPECT().SNATMetricsSpecs().Return([]azure.SNATMetricsSpec{fakeLBSpec, fakeNATGatewaySpec})
+
+	s := &Service{
+		Scope:  scopeMock,
+		client: clientMock,
+	}
+
+	g.Expect(s.Delete(context.TODO())).To(Succeed())
+	g.Expect(snatUsedPorts.DeleteLabelValues("default", cluster, fakeLBSpec.Name)).To(BeFalse())
+	g.Expect(snatDroppedPackets.DeleteLabelValues("default", cluster, fakeNATGatewaySpec.Name)).To(BeFalse())
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotplacementscores
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// apiVersion is the Compute API version serving the Spot Placement Score API.
+// The operation is not part of the compute SDK package used by the rest of the provider.
+const apiVersion = "2025-06-05"
+
+// resourceSize is a VM size to get a Spot Placement Score for.
+type resourceSize struct {
+	SKU *string `json:"sku,omitempty"`
+}
+
+// generateInput is the request body of a Spot Placement Score request.
+type generateInput struct {
+	DesiredLocations  *[]string       `json:"desiredLocations,omitempty"`
+	DesiredSizes      *[]resourceSize `json:"desiredSizes,omitempty"`
+	DesiredCount      *int32          `json:"desiredCount,omitempty"`
+	AvailabilityZones *bool           `json:"availabilityZones,omitempty"`
+}
+
+// placementScore is the Spot Placement Score of a VM size in a region or availability zone.
+type placementScore struct {
+	SKU              *string `json:"sku,omitempty"`
+	Region           *string `json:"region,omitempty"`
+	AvailabilityZone *string `json:"availabilityZone,omitempty"`
+	Score            *string `json:"score,omitempty"`
+	IsQuotaAvailable *bool   `json:"isQuotaAvailable,omitempty"`
+}
+
+// generateResult is the response of a Spot Placement Score request.
+type generateResult struct {
+	autorest.Response `json:"-"`
+	PlacementScores   *[]placementScore `json:"placementScores,omitempty"`
+}
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, azure.SpotPlacementScoreSpec) (map[string]string, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	compute.BaseClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new Spot Placement Score client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newSpotPlacementScoresClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c}
+}
+
+// newSpotPlacementScoresClient creates a new compute base client from subscription ID.
+func newSpotPlacementScoresClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.BaseClient {
+	baseClient := compute.NewWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&baseClient.Client, authorizer, "spotplacementscores")
+	return baseClient
+}
+
+// Get returns the Spot Placement Scores of the VM size in the spec, keyed by availability zone. The regional score is
+// keyed by the empty string when no zones are requested.
+func (ac *azureClient) Get(ctx context.Context, spec azure.SpotPlacementScoreSpec) (_ map[string]string, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "spotplacementscores.AzureClient.Get")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	input := generateInput{
+		DesiredLocations:  &[]string{spec.Location},
+		DesiredSizes:      &[]resourceSize{{SKU: to.StringPtr(spec.Size)}},
+		DesiredCount:      to.Int32Ptr(spec.Count),
+		AvailabilityZones: to.BoolPtr(len(spec.Zones) > 0),
+	}
+
+	req, err := ac.generatePreparer(ctx, spec.Location, input)
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "spotplacementscores.AzureClient", "Get", nil, "Failure preparing request")
+	}
+
+	resp, err := ac.Send(req, azureautorest.DoRetryWithRegistration(ac.Client))
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "spotplacementscores.AzureClient", "Get", resp, "Failure sending request")
+	}
+
+	result, err := generateResponder(resp)
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "spotplacementscores.AzureClient", "Get", resp, "Failure responding to request")
+	}
+
+	scores := make(map[string]string)
+	if result.PlacementScores == nil {
+		return scores, nil
+	}
+	for _, score := range *result.PlacementScores {
+		if strings.EqualFold(to.String(score.SKU), spec.Size) && strings.EqualFold(to.String(score.Region), spec.Location) {
+			scores[to.String(score.AvailabilityZone)] = to.String(score.Score)
+		}
+	}
+	return scores, nil
+}
+
+// generatePreparer prepares the Generate request.
+func (ac *azureClient) generatePreparer(ctx context.Context, location string, input generateInput) (_ *http.Request, err error) {
+	defer azureerrors.Classify(&err)
+
+	pathParameters := map[string]interface{}{
+		"location":       autorest.Encode("path", location),
+		"subscriptionId": autorest.Encode("path", ac.SubscriptionID),
+	}
+	queryParameters := map[string]interface{}{
+		"api-version": apiVersion,
+	}
+
+	preparer := autorest.CreatePreparer(
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.AsPost(),
+		autorest.WithBaseURL(ac.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/providers/Microsoft.Compute/locations/{location}/placementScores/spot/generate", pathParameters),
+		autorest.WithJSON(input),
+		autorest.WithQueryParameters(queryParameters))
+	return preparer.Prepare((&http.Request{}).WithContext(ctx))
+}
+
+// generateResponder handles the response to the Generate request. It always closes the http.Response Body.
+func generateResponder(resp *http.Response) (result generateResult, err error) {
+	err = autorest.Respond(
+		resp,
+		azureautorest.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(&result),
+		autorest.ByClosing())
+	result.Response = autorest.Response{Response: resp}
+	return
+}
@@ -0,0 +1,190 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../spotplacementscores.go
+
+// Package mock_spotplacementscores is a generated GoMock package.
+package mock_spotplacementscores
+
+import (
+	reflect "reflect"
+
+	autorest "github.com/Azure/go-autorest/autorest"
+	gomock "github.com/golang/mock/gomock"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockSpotPlacementScoreScope is a mock of SpotPlacementScoreScope interface.
+type MockSpotPlacementScoreScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotPlacementScoreScopeMockRecorder
+}
+
+// MockSpotPlacementScoreScopeMockRecorder is the mock recorder for MockSpotPlacementScoreScope.
+type MockSpotPlacementScoreScopeMockRecorder struct {
+	mock *MockSpotPlacementScoreScope
+}
+
+// NewMockSpotPlacementScoreScope creates a new mock instance.
+func NewMockSpotPlacementScoreScope(ctrl *gomock.Controller) *MockSpotPlacementScoreScope {
+	mock := &MockSpotPlacementScoreScope{ctrl: ctrl}
+	mock.recorder = &MockSpotPlacementScoreScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpotPlacementScoreScope) EXPECT() *MockSpotPlacementScoreScopeMockRecorder {
+	return m.recorder
+}
+
+// Authorizer mocks base method.
+func (m *MockSpotPlacementScoreScope) Authorizer() autorest.Authorizer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorizer")
+	ret0, _ := ret[0].(autorest.Authorizer)
+	return ret0
+}
+
+// Authorizer indicates an expected call of Authorizer.
+func (mr *MockSpotPlacementScoreScopeMockRecorder) Authorizer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockSpotPlacementScoreScope)(nil).Authorizer))
+}
+
+// BaseURI mocks base method.
+func (m *MockSpotPlacementScoreScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockSpotPlacementScoreScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockSpotPlacementScoreScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockSpotPlacementScoreScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockSpotPlacementScoreScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockSpotPlacementScoreScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockSpotPlacementScoreScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockSpotPlacementScoreScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockSpotPlacementScoreScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockSpotPlacementScoreScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockSpotPlacementScoreScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockSpotPlacementScoreScope)(nil).CloudEnvironment))
+}
+
+// HashKey mocks base method.
+func (m *MockSpotPlacementScoreScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockSpotPlacementScoreScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockSpotPlacementScoreScope)(nil).HashKey))
+}
+
+// SpotPlacementScoreSpec mocks base method.
+func (m *MockSpotPlacementScoreScope) SpotPlacementScoreSpec() *azure.SpotPlacementScoreSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpotPlacementScoreSpec")
+	ret0, _ := ret[0].(*azure.SpotPlacementScoreSpec)
+	return ret0
+}
+
+// SpotPlacementScoreSpec indicates an expected call of SpotPlacementScoreSpec.
+func (mr *MockSpotPlacementScoreScopeMockRecorder) SpotPlacementScoreSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpotPlacementScoreSpec", reflect.TypeOf((*MockSpotPlacementScoreScope)(nil).SpotPlacementScoreSpec))
+}
+
+// SubscriptionID mocks base method.
+func (m *MockSpotPlacementScoreScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockSpotPlacementScoreScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockSpotPlacementScoreScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockSpotPlacementScoreScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockSpotPlacementScoreScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockSpotPlacementScoreScope)(nil).TenantID))
+}
+
+// UpdateSpotPlacementScoreStatus mocks base method.
+func (m *MockSpotPlacementScoreScope) UpdateSpotPlacementScoreStatus(score string, err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateSpotPlacementScoreStatus", score, err)
+}
+
+// UpdateSpotPlacementScoreStatus indicates an expected call of UpdateSpotPlacementScoreStatus.
+func (mr *MockSpotPlacementScoreScopeMockRecorder) UpdateSpotPlacementScoreStatus(score, err interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpotPlacementScoreStatus", reflect.TypeOf((*MockSpotPlacementScoreScope)(nil).UpdateSpotPlacementScoreStatus), score, err)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotplacementscores
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// restrictedSKUNotAvailable is the score returned when the VM size is not offered to the subscription in the location.
+const restrictedSKUNotAvailable = "RestrictedSkuNotAvailable"
+
+// scoreRanks orders the Spot Placement Scores that can be compared against a threshold.
+// Any other score, e.g. "DataNotFoundOrStale", is treated as unavailable.
+var scoreRanks = map[string]int{
+	restrictedSKUNotAvailable: 0,
+	"Low":                     1,
+	"Medium":                  2,
+	"High":                    3,
+}
+
+// SpotPlacementScoreScope defines the scope interface for a spot placement scores service.
+type SpotPlacementScoreScope interface {
+	azure.Authorizer
+	SpotPlacementScoreSpec() *azure.SpotPlacementScoreSpec
+	UpdateSpotPlacementScoreStatus(score string, err error)
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope SpotPlacementScoreScope
+	client
+}
+
+// New creates a new spot placement scores service.
+func New(scope SpotPlacementScoreScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile gets the Spot Placement Score for the requested VM size and zones and reports it in a condition.
+// A score lower than the threshold returns a transient error so that creation is retried later. A score that
+// cannot be retrieved is reported but does not block creation.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "spotplacementscores.Service.Reconcile")
+	defer done()
+
+	spec := s.Scope.SpotPlacementScoreSpec()
+	if spec == nil {
+		return nil
+	}
+
+	scores, err := s.client.Get(ctx, *spec)
+	if err != nil {
+		log.V(2).Info("unable to get spot placement score, continuing", "size", spec.Size, "location", spec.Location, "error", err.Error())
+		s.Scope.UpdateSpotPlacementScoreStatus("", errors.Wrap(err, "failed to get spot placement score"))
+		return nil
+	}
+
+	score, ok := lowestScore(spec, scores)
+	if !ok {
+		s.Scope.UpdateSpotPlacementScoreStatus(score, errors.Errorf("no spot placement score available for size %s in location %s", spec.Size, spec.Location))
+		return nil
+	}
+
+	if threshold, ok := scoreRanks[spec.Threshold]; ok && scoreRanks[score] < threshold {
+		err := azure.WithTransientError(errors.Errorf("spot placement score %s for size %s in location %s is below the threshold %s", score, spec.Size, spec.Location, spec.Threshold), 5*time.Minute)
+		s.Scope.UpdateSpotPlacementScoreStatus(score, err)
+		return err
+	}
+
+	s.Scope.UpdateSpotPlacementScoreStatus(score, nil)
+	return nil
+}
+
+// Delete is a no-op as the Spot Placement Score is not an Azure resource.
+func (s *Service) Delete(_ context.Context) error {
+	return nil
+}
+
+// lowestScore returns the lowest known score across the requested zones, or the regional score if no zones were
+// requested. It returns false if none of the matching scores can be ranked.
+func lowestScore(spec *azure.SpotPlacementScoreSpec, scores map[string]string) (string, bool) {
+	zones := spec.Zones
+	if len(zones) == 0 {
+		zones = []string{""}
+	}
+
+	lowest, found := "", false
+	for _, zone := range zones {
+		score, ok := scores[zone]
+		if !ok {
+			continue
+		}
+		rank, ok := scoreRanks[score]
+		if !ok {
+			if !found {
+				lowest = score
+			}
+			continue
+		}
+		if !found || rank < scoreRanks[lowest] {
+			lowest, found = score, true
+		}
+	}
+	return lowest, found
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotplacementscores
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/spotplacementscores/mock_spotplacementscores"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func TestReconcileSpotPlacementScores(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_spotplacementscores.MockSpotPlacementScoreScopeMockRecorder, m *mock_spotplacementscores.MockclientMockRecorder)
+	}{
+		{
+			name:          "noop if no spec is returned",
+			expectedError: "",
+			expect: func(s *mock_spotplacementscores.MockSpotPlacementScoreScopeMockRecorder, m *mock_spotplacementscores.MockclientMockRecorder) {
+				s.SpotPlacementScoreSpec().Return(nil)
+			},
+		},
+		{
+			name:          "regional score without threshold",
+			expectedError: "",
+			expect: func(s *mock_spotplacementscores.MockSpotPlacementScoreScopeMockRecorder, m *mock_spotplacementscores.MockclientMockRecorder) {
+				s.SpotPlacementScoreSpec().Return(&azure.SpotPlacementScoreSpec{
+					Location: "westus2",
+					Size:     "Standard_D2s_v3",
+					Count:    3,
+				})
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(map[string]string{"": "Low"}, nil)
+				s.UpdateSpotPlacementScoreStatus("Low", nil)
+			},
+		},
+		{
+			name:          "lowest score of the requested zones meets the threshold",
+			expectedError: "",
+			expect: func(s *mock_spotplacementscores.MockSpotPlacementScoreScopeMockRecorder, m *mock_spotplacementscores.MockclientMockRecorder) {
+				s.SpotPlacementScoreSpec().Return(&azure.SpotPlacementScoreSpec{
+					Location:  "westus2",
+					Size:      "Standard_D2s_v3",
+					Zones:     []string{"1", "2"},
+					Count:     3,
+					Threshold: "Medium",
+				})
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(map[string]string{"1": "High", "2": "Medium", "3": "Low"}, nil)
+				s.UpdateSpotPlacementScoreStatus("Medium", nil)
+			},
+		},
+		{
+			name:          "score below the threshold blocks creation",
+			expectedError: "spot placement score Low for size Standard_D2s_v3 in location westus2 is below the threshold High. Object will be requeued after 5m0s",
+			expect: func(s *mock_spotplacementscores.MockSpotPlacementScoreScopeMockRecorder, m *mock_spotplacementscores.MockclientMockRecorder) {
+				s.SpotPlacementScoreSpec().Return(&azure.SpotPlacementScoreSpec{
+					Location:  "westus2",
+					Size:      "Standard_D2s_v3",
+					Count:     3,
+					Threshold: "High",
+				})
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(map[string]string{"": "Low"}, nil)
+				s.UpdateSpotPlacementScoreStatus("Low", gomock.Any())
+			},
+		},
+		{
+			name:          "restricted size blocks creation",
+			expectedError: "spot placement score RestrictedSkuNotAvailable for size Standard_D2s_v3 in location westus2 is below the threshold Low. Object will be requeued after 5m0s",
+			expect: func(s *mock_spotplacementscores.MockSpotPlacementScoreScopeMockRecorder, m *mock_spotplacementscores.MockclientMockRecorder) {
+				s.SpotPlacementScoreSpec().Return(&azure.SpotPlacementScoreSpec{
+					Location:  "westus2",
+					Size:      "Standard_D2s_v3",
+					Count:     3,
+					Threshold: "Low",
+				})
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(map[string]string{"": "RestrictedSkuNotAvailable"}, nil)
+				s.UpdateSpotPlacementScoreStatus("RestrictedSkuNotAvailable", gomock.Any())
+			},
+		},
+		{
+			name:          "unknown score does not block creation",
+			expectedError: "",
+			expect: func(s *mock_spotplacementscores.MockSpotPlacementScoreScopeMockRecorder, m *mock_spotplacementscores.MockclientMockRecorder) {
+				s.SpotPlacementScoreSpec().Return(&azure.SpotPlacementScoreSpec{
+					Location:  "westus2",
+					Size:      "Standard_D2s_v3",
+					Count:     3,
+					Threshold: "High",
+				})
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(map[string]string{"": "DataNotFoundOrStale"}, nil)
+				s.UpdateSpotPlacementScoreStatus("DataNotFoundOrStale", gomock.Any())
+			},
+		},
+		{
+			name:          "error getting the score does not block creation",
+			expectedError: "",
+			expect: func(s *mock_spotplacementscores.MockSpotPlacementScoreScopeMockRecorder, m *mock_spotplacementscores.MockclientMockRecorder) {
+				s.SpotPlacementScoreSpec().Return(&azure.SpotPlacementScoreSpec{
+					Location:  "westus2",
+					Size:      "Standard_D2s_v3",
+					Count:     3,
+					Threshold: "High",
+				})
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				s.UpdateSpotPlacementScoreStatus("", gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_spotplacementscores.NewMockSpotPlacementScoreScope(mockCtrl)
+			clientMock := mock_spotplacementscores.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -49,22 +50,24 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newSubnetsClient creates a new subnets client from subscription ID.
 func newSubnetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.SubnetsClient {
 	subnetsClient := network.NewSubnetsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&subnetsClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&subnetsClient.Client, authorizer, "subnets")
 	return subnetsClient
 }
 
 // Get gets the specified subnet by virtual network and resource group.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vnetName, snName string) (network.Subnet, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vnetName, snName string) (_ network.Subnet, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "subnets.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.subnets.Get(ctx, resourceGroupName, vnetName, snName, "")
 }
 
 // CreateOrUpdate creates or updates a subnet in the specified virtual network.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vnetName, snName string, sn network.Subnet) error {
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vnetName, snName string, sn network.Subnet) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "subnets.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.subnets.CreateOrUpdate(ctx, resourceGroupName, vnetName, snName, sn)
 	if err != nil {
@@ -79,9 +82,10 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vn
 }
 
 // Delete deletes the specified subnet.
-func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vnetName, snName string) error {
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vnetName, snName string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "subnets.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.subnets.Delete(ctx, resourceGroupName, vnetName, snName)
 	if err != nil {
@@ -30,7 +30,11 @@ import (
 
 // SubnetScope defines the scope interface for a subnet service.
 type SubnetScope interface {
-	azure.ClusterScoper
+	azure.ClusterDescriber
+	Vnet() *infrav1.VnetSpec
+	IsVnetManaged() bool
+	Subnet(string) infrav1.SubnetSpec
+	SetSubnet(infrav1.SubnetSpec)
 	SubnetSpecs() []azure.SubnetSpec
 }
 
@@ -67,45 +71,13 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			return fmt.Errorf("vnet was provided but subnet %s is missing", subnetSpec.Name)
 
 		default:
-
-			subnetProperties := network.SubnetPropertiesFormat{
-				AddressPrefixes: &subnetSpec.CIDRs,
-			}
-
-			// workaround needed to avoid SubscriptionNotRegisteredForFeature for feature Microsoft.Network/AllowMultipleAddressPrefixesOnSubnet.
-			if len(subnetSpec.CIDRs) == 1 {
-				subnetProperties = network.SubnetPropertiesFormat{
-					AddressPrefix: &subnetSpec.CIDRs[0],
-				}
-			}
-
-			if subnetSpec.RouteTableName != "" {
-				subnetProperties.RouteTable = &network.RouteTable{
-					ID: to.StringPtr(azure.RouteTableID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), subnetSpec.RouteTableName)),
-				}
-			}
-
-			if subnetSpec.NatGatewayName != "" {
-				subnetProperties.NatGateway = &network.SubResource{
-					ID: to.StringPtr(azure.NatGatewayID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), subnetSpec.NatGatewayName)),
-				}
-			}
-
-			if subnetSpec.SecurityGroupName != "" {
-				subnetProperties.NetworkSecurityGroup = &network.SecurityGroup{
-					ID: to.StringPtr(azure.SecurityGroupID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), subnetSpec.SecurityGroupName)),
-				}
-			}
-
 			log.V(2).Info("creating subnet in vnet", "subnet", subnetSpec.Name, "vnet", subnetSpec.VNetName)
 			err = s.Client.CreateOrUpdate(
 				ctx,
 				s.Scope.Vnet().ResourceGroup,
 				subnetSpec.VNetName,
 				subnetSpec.Name,
-				network.Subnet{
-					SubnetPropertiesFormat: &subnetProperties,
-				},
+				Parameters(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), subnetSpec),
 			)
 			if err != nil {
 				return errors.Wrapf(err, "failed to create subnet %s in resource group %s", subnetSpec.Name, s.Scope.Vnet().ResourceGroup)
@@ -165,3 +137,50 @@ func (s *Service) getExisting(ctx context.Context, rgName string, spec azure.Sub
 
 	return &subnetSpec, nil
 }
+
+// Parameters returns the parameters of a new subnet. The route table, NAT gateway and security group of the subnet are
+// in the given resource group.
+func Parameters(subscriptionID, resourceGroup string, subnetSpec azure.SubnetSpec) network.Subnet {
+	subnetProperties := network.SubnetPropertiesFormat{
+		AddressPrefixes: &subnetSpec.CIDRs,
+	}
+
+	// workaround needed to avoid SubscriptionNotRegisteredForFeature for feature Microsoft.Network/AllowMultipleAddressPrefixesOnSubnet.
+	if len(subnetSpec.CIDRs) == 1 {
+		subnetProperties = network.SubnetPropertiesFormat{
+			AddressPrefix: &subnetSpec.CIDRs[0],
+		}
+	}
+
+	if subnetSpec.RouteTableName != "" {
+		subnetProperties.RouteTable = &network.RouteTable{
+			ID: to.StringPtr(azure.RouteTableID(subscriptionID, resourceGroup, subnetSpec.RouteTableName)),
+		}
+	}
+
+	if subnetSpec.NatGatewayName != "" {
+		subnetProperties.NatGateway = &network.SubResource{
+			ID: to.StringPtr(azure.NatGatewayID(subscriptionID, resourceGroup, subnetSpec.NatGatewayName)),
+		}
+	}
+
+	if subnetSpec.SecurityGroupName != "" {
+		subnetProperties.NetworkSecurityGroup = &network.SecurityGroup{
+			ID: to.StringPtr(azure.SecurityGroupID(subscriptionID, resourceGroup, subnetSpec.SecurityGroupName)),
+		}
+	}
+
+	if subnetSpec.PrivateEndpoints {
+		// network policies must be disabled for private endpoints to be created in the subnet
+		subnetProperties.PrivateEndpointNetworkPolicies = network.VirtualNetworkPrivateEndpointNetworkPoliciesDisabled
+	}
+
+	if subnetSpec.PrivateLinkService {
+		// network policies must be disabled for a private link service to use the subnet for its NAT IPs
+		subnetProperties.PrivateLinkServiceNetworkPolicies = network.VirtualNetworkPrivateLinkServiceNetworkPoliciesDisabled
+	}
+
+	return network.Subnet{
+		SubnetPropertiesFormat: &subnetProperties,
+	}
+}
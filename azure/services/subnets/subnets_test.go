@@ -56,7 +56,6 @@ func TestReconcileSubnets(t *testing.T) {
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.IsIPv6Enabled().AnyTimes().Return(false)
 				s.IsVnetManaged().Return(true)
 				m.Get(gomockinternal.AContext(), "", "my-vnet", "my-subnet").
 					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
@@ -69,6 +68,66 @@ func TestReconcileSubnets(t *testing.T) {
 				}))
 			},
 		},
+		{
+			name:          "subnet with private endpoints does not exist",
+			expectedError: "",
+			expect: func(s *mock_subnets.MockSubnetScopeMockRecorder, m *mock_subnets.MockClientMockRecorder) {
+				s.SubnetSpecs().Return([]azure.SubnetSpec{
+					{
+						Name:              "my-subnet",
+						CIDRs:             []string{"10.0.0.0/16"},
+						VNetName:          "my-vnet",
+						SecurityGroupName: "my-sg",
+						Role:              infrav1.SubnetNode,
+						PrivateEndpoints:  true,
+					},
+				})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"})
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.IsVnetManaged().Return(true)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").
+					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet", gomockinternal.DiffEq(network.Subnet{
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						AddressPrefix:                  to.StringPtr("10.0.0.0/16"),
+						NetworkSecurityGroup:           &network.SecurityGroup{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
+						PrivateEndpointNetworkPolicies: network.VirtualNetworkPrivateEndpointNetworkPoliciesDisabled,
+					},
+				}))
+			},
+		},
+		{
+			name:          "control plane subnet with private link service does not exist",
+			expectedError: "",
+			expect: func(s *mock_subnets.MockSubnetScopeMockRecorder, m *mock_subnets.MockClientMockRecorder) {
+				s.SubnetSpecs().Return([]azure.SubnetSpec{
+					{
+						Name:               "my-subnet",
+						CIDRs:              []string{"10.0.0.0/16"},
+						VNetName:           "my-vnet",
+						SecurityGroupName:  "my-sg",
+						Role:               infrav1.SubnetControlPlane,
+						PrivateLinkService: true,
+					},
+				})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"})
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.IsVnetManaged().Return(true)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").
+					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet", gomockinternal.DiffEq(network.Subnet{
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						AddressPrefix:                     to.StringPtr("10.0.0.0/16"),
+						NetworkSecurityGroup:              &network.SecurityGroup{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
+						PrivateLinkServiceNetworkPolicies: network.VirtualNetworkPrivateLinkServiceNetworkPoliciesDisabled,
+					},
+				}))
+			},
+		},
 		{
 			name:          "subnet ipv6 does not exist",
 			expectedError: "",
@@ -120,7 +179,6 @@ func TestReconcileSubnets(t *testing.T) {
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.IsIPv6Enabled().AnyTimes().Return(false)
 				s.IsVnetManaged().Return(true)
 				m.Get(gomockinternal.AContext(), "", "my-vnet", "my-subnet").
 					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
@@ -296,7 +354,6 @@ func TestReconcileSubnets(t *testing.T) {
 				})
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet"})
 				s.ClusterName().AnyTimes().Return("fake-cluster")
-				s.IsIPv6Enabled().AnyTimes().Return(true)
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "", "my-vnet", "my-ipv6-subnet").
@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -48,23 +49,25 @@ func newClient(auth azure.Authorizer) *azureClient {
 // newTagsClient creates a new tags client from subscription ID.
 func newTagsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resources.TagsClient {
 	tagsClient := resources.NewTagsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&tagsClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&tagsClient.Client, authorizer, "tags")
 	return tagsClient
 }
 
 // GetAtScope sends the get at scope request.
-func (ac *azureClient) GetAtScope(ctx context.Context, scope string) (resources.TagsResource, error) {
+func (ac *azureClient) GetAtScope(ctx context.Context, scope string) (_ resources.TagsResource, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "tags.AzureClient.GetAtScope")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.tags.GetAtScope(ctx, scope)
 }
 
 // UpdateAtScope this operation allows replacing, merging or selectively deleting tags on the specified resource or
 // subscription.
-func (ac *azureClient) UpdateAtScope(ctx context.Context, scope string, parameters resources.TagsPatchResource) (resources.TagsResource, error) {
+func (ac *azureClient) UpdateAtScope(ctx context.Context, scope string, parameters resources.TagsPatchResource) (_ resources.TagsResource, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "tags.AzureClient.UpdateAtScope")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.tags.UpdateAtScope(ctx, scope, parameters)
 }
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// exportOptions parameterizes the names of the exported resources, with the current names as default values.
+const exportOptions = "IncludeParameterDefaultValue"
+
+// client wraps go-sdk.
+type client interface {
+	ListByResourceGroup(context.Context, string) ([]resources.GenericResourceExpanded, error)
+	ExportTemplate(context.Context, string, []string) (resources.GroupExportResult, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	resources resources.Client
+	groups    resources.GroupsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new template export client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	return &azureClient{
+		resources: newResourcesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		groups:    newGroupsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newResourcesClient creates a new resources client from subscription ID.
+func newResourcesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resources.Client {
+	resourcesClient := resources.NewClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&resourcesClient.Client, authorizer, "templates")
+	return resourcesClient
+}
+
+// newGroupsClient creates a new groups client from subscription ID.
+func newGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resources.GroupsClient {
+	groupsClient := resources.NewGroupsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&groupsClient.Client, authorizer, "templates")
+	return groupsClient
+}
+
+// ListByResourceGroup lists the top-level resources of a resource group.
+func (ac *azureClient) ListByResourceGroup(ctx context.Context, resourceGroupName string) (_ []resources.GenericResourceExpanded, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "templates.AzureClient.ListByResourceGroup")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	var result []resources.GenericResourceExpanded
+	iter, err := ac.resources.ListByResourceGroupComplete(ctx, resourceGroupName, "", "", nil)
+	for ; err == nil && iter.NotDone(); err = iter.NextWithContext(ctx) {
+		result = append(result, iter.Value())
+	}
+	return result, err
+}
+
+// ExportTemplate exports the resources of a resource group as an ARM template.
+func (ac *azureClient) ExportTemplate(ctx context.Context, resourceGroupName string, resourceIDs []string) (_ resources.GroupExportResult, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "templates.AzureClient.ExportTemplate")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	exportOpts := exportOptions
+	future, err := ac.groups.ExportTemplate(ctx, resourceGroupName, resources.ExportTemplateRequest{
+		ResourcesProperty: &resourceIDs,
+		Options:           &exportOpts,
+	})
+	if err != nil {
+		return resources.GroupExportResult{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	if err := future.WaitForCompletionRef(ctx, ac.groups.Client); err != nil {
+		return resources.GroupExportResult{}, err
+	}
+	return future.Result(ac.groups)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+
+// Package mock_templates is a generated GoMock package.
+package mock_templates
+
+import (
+	context "context"
+	reflect "reflect"
+
+	resources "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// Mockclient is a mock of client interface.
+type Mockclient struct {
+	ctrl     *gomock.Controller
+	recorder *MockclientMockRecorder
+}
+
+// MockclientMockRecorder is the mock recorder for Mockclient.
+type MockclientMockRecorder struct {
+	mock *Mockclient
+}
+
+// NewMockclient creates a new mock instance.
+func NewMockclient(ctrl *gomock.Controller) *Mockclient {
+	mock := &Mockclient{ctrl: ctrl}
+	mock.recorder = &MockclientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockclient) EXPECT() *MockclientMockRecorder {
+	return m.recorder
+}
+
+// ExportTemplate mocks base method.
+func (m *Mockclient) ExportTemplate(arg0 context.Context, arg1 string, arg2 []string) (resources.GroupExportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTemplate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(resources.GroupExportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportTemplate indicates an expected call of ExportTemplate.
+func (mr *MockclientMockRecorder) ExportTemplate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTemplate", reflect.TypeOf((*Mockclient)(nil).ExportTemplate), arg0, arg1, arg2)
+}
+
+// ListByResourceGroup mocks base method.
+func (m *Mockclient) ListByResourceGroup(arg0 context.Context, arg1 string) ([]resources.GenericResourceExpanded, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByResourceGroup", arg0, arg1)
+	ret0, _ := ret[0].([]resources.GenericResourceExpanded)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByResourceGroup indicates an expected call of ListByResourceGroup.
+func (mr *MockclientMockRecorder) ListByResourceGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByResourceGroup", reflect.TypeOf((*Mockclient)(nil).ListByResourceGroup), arg0, arg1)
+}
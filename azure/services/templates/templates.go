@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// TemplateScope defines the scope interface for a template export service.
+type TemplateScope interface {
+	azure.ClusterDescriber
+	TemplateExportRequested() bool
+	SetExportedTemplate(context.Context, []byte) error
+}
+
+// Service exports the Azure resources owned by a cluster as an ARM template on request, so that users can review,
+// audit or reproduce the infrastructure outside of capz.
+type Service struct {
+	Scope TemplateScope
+	client
+}
+
+// New creates a new template export service.
+func New(scope TemplateScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile exports the resources owned by the cluster in its resource group when an export was requested.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "templates.Service.Reconcile")
+	defer done()
+
+	if !s.Scope.TemplateExportRequested() {
+		return nil
+	}
+
+	existing, err := s.client.ListByResourceGroup(ctx, s.Scope.ResourceGroup())
+	if err != nil {
+		return errors.Wrapf(err, "failed to list resources in resource group %s", s.Scope.ResourceGroup())
+	}
+	var resourceIDs []string
+	for _, resource := range existing {
+		if converters.MapToTags(resource.Tags).HasOwned(s.Scope.ClusterName()) {
+			resourceIDs = append(resourceIDs, to.String(resource.ID))
+		}
+	}
+	if len(resourceIDs) == 0 {
+		log.V(2).Info("no resources owned by the cluster to export", "resource group", s.Scope.ResourceGroup())
+		return nil
+	}
+	sort.Strings(resourceIDs)
+
+	log.V(2).Info("exporting template", "resource group", s.Scope.ResourceGroup(), "resources", len(resourceIDs))
+	result, err := s.client.ExportTemplate(ctx, s.Scope.ResourceGroup(), resourceIDs)
+	if err != nil {
+		return errors.Wrapf(err, "failed to export template of resource group %s", s.Scope.ResourceGroup())
+	}
+	if result.Error != nil {
+		// the resources which can't be exported are left out of the template, which is still worth reviewing.
+		log.Info("some resources could not be exported", "code", to.String(result.Error.Code), "message", to.String(result.Error.Message))
+	}
+
+	template, err := json.MarshalIndent(result.Template, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal exported template")
+	}
+	if err := s.Scope.SetExportedTemplate(ctx, template); err != nil {
+		return errors.Wrap(err, "failed to store exported template")
+	}
+	log.V(2).Info("successfully exported template", "resource group", s.Scope.ResourceGroup())
+	return nil
+}
+
+// Delete is a no-op, the exported template is deleted with the AzureCluster owning it.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/templates/mock_templates"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	ownedVnet = resources.GenericResourceExpanded{
+		ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"),
+		Tags: map[string]*string{
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+		},
+	}
+	ownedLB = resources.GenericResourceExpanded{
+		ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb"),
+		Tags: map[string]*string{
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+		},
+	}
+	sharedNSG = resources.GenericResourceExpanded{
+		ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-nsg"),
+		Tags: map[string]*string{
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+		},
+	}
+	internalErr = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func TestReconcileTemplates(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_templates.MockTemplateScopeMockRecorder, m *mock_templates.MockclientMockRecorder)
+	}{
+		{
+			name:          "no export requested",
+			expectedError: "",
+			expect: func(s *mock_templates.MockTemplateScopeMockRecorder, m *mock_templates.MockclientMockRecorder) {
+				s.TemplateExportRequested().Return(false)
+			},
+		},
+		{
+			name:          "export the resources owned by the cluster",
+			expectedError: "",
+			expect: func(s *mock_templates.MockTemplateScopeMockRecorder, m *mock_templates.MockclientMockRecorder) {
+				s.TemplateExportRequested().Return(true)
+				m.ListByResourceGroup(gomockinternal.AContext(), "my-rg").Return([]resources.GenericResourceExpanded{ownedVnet, sharedNSG, ownedLB}, nil)
+				m.ExportTemplate(gomockinternal.AContext(), "my-rg", []string{to.String(ownedLB.ID), to.String(ownedVnet.ID)}).Return(resources.GroupExportResult{
+					Template: map[string]interface{}{"contentVersion": "1.0.0.0"},
+				}, nil)
+				s.SetExportedTemplate(gomockinternal.AContext(), []byte("{\n  \"contentVersion\": \"1.0.0.0\"\n}"))
+			},
+		},
+		{
+			name:          "store the template when some resources could not be exported",
+			expectedError: "",
+			expect: func(s *mock_templates.MockTemplateScopeMockRecorder, m *mock_templates.MockclientMockRecorder) {
+				s.TemplateExportRequested().Return(true)
+				m.ListByResourceGroup(gomockinternal.AContext(), "my-rg").Return([]resources.GenericResourceExpanded{ownedVnet}, nil)
+				m.ExportTemplate(gomockinternal.AContext(), "my-rg", []string{to.String(ownedVnet.ID)}).Return(resources.GroupExportResult{
+					Template: map[string]interface{}{},
+					Error:    &resources.ErrorResponse{Code: to.StringPtr("ExportTemplateCompletedWithErrors")},
+				}, nil)
+				s.SetExportedTemplate(gomockinternal.AContext(), []byte("{}"))
+			},
+		},
+		{
+			name:          "nothing to export",
+			expectedError: "",
+			expect: func(s *mock_templates.MockTemplateScopeMockRecorder, m *mock_templates.MockclientMockRecorder) {
+				s.TemplateExportRequested().Return(true)
+				m.ListByResourceGroup(gomockinternal.AContext(), "my-rg").Return([]resources.GenericResourceExpanded{sharedNSG}, nil)
+			},
+		},
+		{
+			name:          "fail to list resources",
+			expectedError: "failed to list resources in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_templates.MockTemplateScopeMockRecorder, m *mock_templates.MockclientMockRecorder) {
+				s.TemplateExportRequested().Return(true)
+				m.ListByResourceGroup(gomockinternal.AContext(), "my-rg").Return(nil, internalErr)
+			},
+		},
+		{
+			name:          "fail to export template",
+			expectedError: "failed to export template of resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_templates.MockTemplateScopeMockRecorder, m *mock_templates.MockclientMockRecorder) {
+				s.TemplateExportRequested().Return(true)
+				m.ListByResourceGroup(gomockinternal.AContext(), "my-rg").Return([]resources.GenericResourceExpanded{ownedVnet}, nil)
+				m.ExportTemplate(gomockinternal.AContext(), "my-rg", []string{to.String(ownedVnet.ID)}).Return(resources.GroupExportResult{}, internalErr)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_templates.NewMockTemplateScope(mockCtrl)
+			clientMock := mock_templates.NewMockclient(mockCtrl)
+
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
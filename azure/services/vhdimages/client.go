@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vhdimages
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, string, string) (compute.Image, error)
+	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter) (interface{}, azureautorest.FutureAPI, error)
+	DeleteAsync(context.Context, azure.ResourceSpecGetter) (azureautorest.FutureAPI, error)
+	Result(context.Context, azureautorest.FutureAPI, string) (interface{}, error)
+	IsDone(context.Context, azureautorest.FutureAPI) (bool, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	images compute.ImagesClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new images client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newImagesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c}
+}
+
+// newImagesClient creates a new managed images client from subscription ID.
+func newImagesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.ImagesClient {
+	imagesClient := compute.NewImagesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&imagesClient.Client, authorizer, serviceName)
+	return imagesClient
+}
+
+// Get gets the specified managed image by the image name and resource group.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, imageName string) (_ compute.Image, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vhdimages.AzureClient.Get")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.images.Get(ctx, resourceGroupName, imageName, "")
+}
+
+// CreateOrUpdateAsync creates a managed image asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ interface{}, _ azureautorest.FutureAPI, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vhdimages.AzureClient.CreateOrUpdateAsync")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	var existingImage interface{}
+
+	if existing, err := ac.Get(ctx, spec.ResourceGroupName(), spec.ResourceName()); err != nil && !azure.ResourceNotFound(err) {
+		return nil, nil, errors.Wrapf(err, "failed to get image %s in %s", spec.ResourceName(), spec.ResourceGroupName())
+	} else if err == nil {
+		existingImage = existing
+	}
+
+	params, err := spec.Parameters(existingImage)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get desired parameters for image %s", spec.ResourceName())
+	}
+
+	image, ok := params.(compute.Image)
+	if !ok {
+		if params == nil {
+			// nothing to do here.
+			return existingImage, nil, nil
+		}
+		return nil, nil, errors.Errorf("%T is not a compute.Image", params)
+	}
+
+	future, err := ac.images.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), image)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = future.WaitForCompletionRef(ctx, ac.images.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, &future, err
+	}
+
+	result, err := future.Result(ac.images)
+	// if the operation completed, return a nil future
+	return result, nil, err
+}
+
+// DeleteAsync deletes a managed image asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ azureautorest.FutureAPI, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vhdimages.AzureClient.DeleteAsync")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.images.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = future.WaitForCompletionRef(ctx, ac.images.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return &future, err
+	}
+	_, err = future.Result(ac.images)
+	// if the operation completed, return a nil future.
+	return nil, err
+}
+
+// Result fetches the result of a long-running operation future.
+func (ac *azureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (_ interface{}, err error) {
+	defer azureerrors.Classify(&err)
+
+	if futureData == nil {
+		return nil, errors.Errorf("cannot get result from nil future")
+	}
+
+	switch futureType {
+	case infrav1.PutFuture:
+		var future *compute.ImagesCreateOrUpdateFuture
+		jsonData, err := futureData.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal future")
+		}
+		if err := json.Unmarshal(jsonData, &future); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal future data")
+		}
+		return future.Result(ac.images)
+
+	case infrav1.DeleteFuture:
+		// Delete does not return a result image
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("unknown future type %q", futureType)
+	}
+}
+
+// IsDone returns true if the long-running operation has completed.
+func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (_ bool, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vhdimages.AzureClient.IsDone")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	isDone, err := future.DoneWithContext(ctx, ac.images)
+	if err != nil {
+		return false, errors.Wrap(err, "failed checking if the operation was complete")
+	}
+
+	return isDone, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vhdimages
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// VHDImageSpec defines the specification for a managed image created from a generalized VHD blob.
+type VHDImageSpec struct {
+	Name             string
+	ResourceGroup    string
+	Location         string
+	BlobURI          string
+	OSType           string
+	HyperVGeneration string
+	ClusterName      string
+	AdditionalTags   infrav1.Tags
+}
+
+// ResourceName returns the name of the managed image.
+func (s *VHDImageSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *VHDImageSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for managed images.
+func (s *VHDImageSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the managed image. Managed images can't be updated, so it is a no-op for an
+// existing image.
+func (s *VHDImageSpec) Parameters(existing interface{}) (interface{}, error) {
+	if existing != nil {
+		if _, ok := existing.(compute.Image); !ok {
+			return nil, errors.Errorf("%T is not a compute.Image", existing)
+		}
+		return nil, nil
+	}
+
+	osType := compute.OperatingSystemTypesLinux
+	if s.OSType == azure.WindowsOS {
+		osType = compute.OperatingSystemTypesWindows
+	}
+	image := compute.Image{
+		Location: to.StringPtr(s.Location),
+		ImageProperties: &compute.ImageProperties{
+			StorageProfile: &compute.ImageStorageProfile{
+				OsDisk: &compute.ImageOSDisk{
+					OsType:  osType,
+					OsState: compute.OperatingSystemStateTypesGeneralized,
+					BlobURI: to.StringPtr(s.BlobURI),
+				},
+			},
+		},
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+	}
+	if s.HyperVGeneration != "" {
+		image.HyperVGeneration = compute.HyperVGenerationTypes(s.HyperVGeneration)
+	}
+	return image, nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vhdimages
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/golden"
+)
+
+func TestVHDImageSpec_Parameters(t *testing.T) {
+	windowsSpec := fakeVHDImageSpec
+	windowsSpec.OSType = "Windows"
+	windowsSpec.HyperVGeneration = "V2"
+
+	testcases := []struct {
+		name          string
+		spec          *VHDImageSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name:     "image already exists",
+			spec:     &fakeVHDImageSpec,
+			existing: compute.Image{Name: to.StringPtr("my-cluster-vhd-0123456789")},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:          "existing is not an image",
+			spec:          &fakeVHDImageSpec,
+			existing:      "foo",
+			expectedError: "string is not a compute.Image",
+		},
+		{
+			name: "linux image does not exist yet",
+			spec: &fakeVHDImageSpec,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.Image{}))
+				image := result.(compute.Image)
+				g.Expect(image.Location).To(Equal(to.StringPtr("westus")))
+				g.Expect(image.StorageProfile.OsDisk).To(Equal(&compute.ImageOSDisk{
+					OsType:  compute.OperatingSystemTypesLinux,
+					OsState: compute.OperatingSystemStateTypesGeneralized,
+					BlobURI: to.StringPtr("https://myaccount.blob.core.windows.net/vhds/image.vhd"),
+				}))
+				g.Expect(image.HyperVGeneration).To(BeEmpty())
+				g.Expect(image.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", to.StringPtr("owned")))
+			},
+		},
+		{
+			name: "windows image of generation 2 does not exist yet",
+			spec: &windowsSpec,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.Image{}))
+				image := result.(compute.Image)
+				g.Expect(image.StorageProfile.OsDisk.OsType).To(Equal(compute.OperatingSystemTypesWindows))
+				g.Expect(image.HyperVGeneration).To(Equal(compute.HyperVGenerationTypesV2))
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				tc.expect(g, result)
+			}
+		})
+	}
+}
+
+func TestVHDImagePayload(t *testing.T) {
+	g := NewWithT(t)
+
+	result, err := fakeVHDImageSpec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	golden.Assert(t, "vhdimage", result)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vhdimages
+
+import (
+	"context"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "vhdimages"
+
+// VHDImageScope defines the scope interface for a VHD images service.
+type VHDImageScope interface {
+	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
+	VHDImageSpec() azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope VHDImageScope
+	client
+}
+
+// New creates a new VHD images service.
+func New(scope VHDImageScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile creates the managed image the VM of the machine is created from when its image is a VHD blob.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vhdimages.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.VHDImageSpec()
+	if spec == nil {
+		return nil
+	}
+	_, err := async.CreateResource(ctx, s.Scope, s.client, spec, serviceName)
+	return err
+}
+
+// Delete is a no-op as the managed image is shared by the machines of the cluster using the same VHD. It is deleted
+// with the resource group of the cluster.
+func (s *Service) Delete(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vhdimages
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vhdimages/mock_vhdimages"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeVHDImageSpec = VHDImageSpec{
+		Name:          "my-cluster-vhd-0123456789",
+		ResourceGroup: "my-rg",
+		Location:      "westus",
+		BlobURI:       "https://myaccount.blob.core.windows.net/vhds/image.vhd",
+		OSType:        "Linux",
+		ClusterName:   "my-cluster",
+	}
+
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func TestReconcileVHDImage(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_vhdimages.MockVHDImageScopeMockRecorder, m *mock_vhdimages.MockclientMockRecorder)
+	}{
+		{
+			name:          "noop if the image is not a VHD",
+			expectedError: "",
+			expect: func(s *mock_vhdimages.MockVHDImageScopeMockRecorder, m *mock_vhdimages.MockclientMockRecorder) {
+				s.VHDImageSpec().Return(nil)
+			},
+		},
+		{
+			name:          "create the managed image",
+			expectedError: "",
+			expect: func(s *mock_vhdimages.MockVHDImageScopeMockRecorder, m *mock_vhdimages.MockclientMockRecorder) {
+				s.VHDImageSpec().Return(&fakeVHDImageSpec)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("my-cluster-vhd-0123456789", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeVHDImageSpec).Return(nil, nil, nil),
+				)
+			},
+		},
+		{
+			name:          "error while trying to create the managed image",
+			expectedError: "failed to create resource my-rg/my-cluster-vhd-0123456789 (service: vhdimages): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_vhdimages.MockVHDImageScopeMockRecorder, m *mock_vhdimages.MockclientMockRecorder) {
+				s.VHDImageSpec().Return(&fakeVHDImageSpec)
+				gomock.InOrder(
+					s.GetLongRunningOperationState("my-cluster-vhd-0123456789", serviceName),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), &fakeVHDImageSpec).Return(nil, nil, internalError),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_vhdimages.NewMockVHDImageScope(mockCtrl)
+			clientMock := mock_vhdimages.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
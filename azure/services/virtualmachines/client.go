@@ -19,6 +19,7 @@ package virtualmachines
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
@@ -28,10 +29,16 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// communityGalleryAPIVersion is a compute API version exposing community gallery images, which are not part of the
+// compute SDK package used by the rest of the provider.
+const communityGalleryAPIVersion = "2022-08-01"
+
 // Client wraps go-sdk.
 type (
 	Client interface {
@@ -59,24 +66,26 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newVirtualMachinesClient creates a new VM client from subscription ID.
 func newVirtualMachinesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachinesClient {
 	vmClient := compute.NewVirtualMachinesClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&vmClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&vmClient.Client, authorizer, "virtualmachines")
 	return vmClient
 }
 
 // Get retrieves information about the model view or the instance view of a virtual machine.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vmName string) (compute.VirtualMachine, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vmName string) (_ compute.VirtualMachine, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
-	return ac.virtualmachines.Get(ctx, resourceGroupName, vmName, "")
+	return ac.virtualmachines.Get(ctx, resourceGroupName, vmName, compute.InstanceViewTypesUserData)
 }
 
 // CreateOrUpdateAsync creates or updates a virtual machine asynchronously.
 // It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
 // progress of the operation.
-func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (interface{}, azureautorest.FutureAPI, error) {
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ interface{}, _ azureautorest.FutureAPI, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	var existingVM interface{}
 
@@ -100,7 +109,12 @@ func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 		return nil, nil, errors.Errorf("%T is not a compute.VirtualMachine", params)
 	}
 
-	future, err := ac.virtualmachines.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), vm)
+	var future compute.VirtualMachinesCreateOrUpdateFuture
+	if vmSpec, ok := spec.(*VMSpec); ok && vmSpec.Image != nil && vmSpec.Image.CommunityGallery != nil {
+		future, err = ac.createOrUpdateWithCommunityGalleryImage(ctx, spec.ResourceGroupName(), spec.ResourceName(), vm, converters.CommunityGalleryImageID(vmSpec.Image))
+	} else {
+		future, err = ac.virtualmachines.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), vm)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -119,16 +133,67 @@ func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 	return result, nil, err
 }
 
+// createOrUpdateWithCommunityGalleryImage sends the PUT request of a virtual machine created from a community gallery
+// image with the community gallery API version, as its image reference can't be expressed with the compute SDK.
+func (ac *AzureClient) createOrUpdateWithCommunityGalleryImage(ctx context.Context, resourceGroupName, vmName string, vm compute.VirtualMachine, imageID string) (future compute.VirtualMachinesCreateOrUpdateFuture, err error) {
+	data, err := json.Marshal(vm)
+	if err != nil {
+		return future, errors.Wrap(err, "failed to marshal virtual machine")
+	}
+	parameters := map[string]interface{}{}
+	if err := json.Unmarshal(data, &parameters); err != nil {
+		return future, errors.Wrap(err, "failed to unmarshal virtual machine")
+	}
+	properties, _ := parameters["properties"].(map[string]interface{})
+	storageProfile, ok := properties["storageProfile"].(map[string]interface{})
+	if !ok {
+		return future, errors.New("virtual machine has no storage profile")
+	}
+	storageProfile["imageReference"] = map[string]interface{}{"communityGalleryImageId": imageID}
+
+	pathParameters := map[string]interface{}{
+		"resourceGroupName": autorest.Encode("path", resourceGroupName),
+		"subscriptionId":    autorest.Encode("path", ac.virtualmachines.SubscriptionID),
+		"vmName":            autorest.Encode("path", vmName),
+	}
+	queryParameters := map[string]interface{}{
+		"api-version": communityGalleryAPIVersion,
+	}
+	req, err := autorest.CreatePreparer(
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.AsPut(),
+		autorest.WithBaseURL(ac.virtualmachines.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Compute/virtualMachines/{vmName}", pathParameters),
+		autorest.WithJSON(parameters),
+		autorest.WithQueryParameters(queryParameters),
+	).Prepare((&http.Request{}).WithContext(ctx))
+	if err != nil {
+		return future, autorest.NewErrorWithError(err, "virtualmachines.AzureClient", "CreateOrUpdate", nil, "Failure preparing request")
+	}
+	future, err = ac.virtualmachines.CreateOrUpdateSender(req)
+	if err != nil {
+		return future, autorest.NewErrorWithError(err, "virtualmachines.AzureClient", "CreateOrUpdate", future.Response(), "Failure sending request")
+	}
+	return future, nil
+}
+
 // DeleteAsync deletes a virtual machine asynchronously. DeleteAsync sends a DELETE
 // request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
 // progress of the operation.
-func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (azureautorest.FutureAPI, error) {
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ azureautorest.FutureAPI, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
-	// TODO: pass variable to force the deletion or not
-	// now we are not forcing.
-	future, err := ac.virtualmachines.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName(), to.BoolPtr(false))
+	forceDelete := false
+	if vmSpec, ok := spec.(*VMSpec); ok {
+		forceDelete = vmSpec.ForceDelete
+	}
+	future, err := ac.virtualmachines.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName(), to.BoolPtr(forceDelete))
+	if err != nil && forceDelete && azure.BadRequest(err) {
+		// force deletion is not available in all the regions, so fall back to a normal deletion.
+		future, err = ac.virtualmachines.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName(), to.BoolPtr(false))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -148,9 +213,10 @@ func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 }
 
 // IsDone returns true if the long-running operation has completed.
-func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (bool, error) {
+func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (_ bool, err error) {
 	ctx, span := tele.Tracer().Start(ctx, "virtualmachines.AzureClient.IsDone")
 	defer span.End()
+	defer azureerrors.Classify(&err)
 
 	done, err := future.DoneWithContext(ctx, ac.virtualmachines)
 	if err != nil {
@@ -161,7 +227,9 @@ func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAP
 }
 
 // Result fetches the result of a long-running operation future.
-func (ac *AzureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (interface{}, error) {
+func (ac *AzureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (_ interface{}, err error) {
+	defer azureerrors.Classify(&err)
+
 	if futureData == nil {
 		return nil, errors.Errorf("cannot get result from nil future")
 	}
@@ -19,6 +19,7 @@ package virtualmachines
 import (
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
@@ -34,27 +35,37 @@ import (
 
 // VMSpec defines the specification for a Virtual Machine.
 type VMSpec struct {
-	Name                   string
-	ResourceGroup          string
-	Location               string
-	ClusterName            string
-	Role                   string
-	NICIDs                 []string
-	SSHKeyData             string
-	Size                   string
-	AvailabilitySetID      string
-	Zone                   string
-	Identity               infrav1.VMIdentity
-	OSDisk                 infrav1.OSDisk
-	DataDisks              []infrav1.DataDisk
-	UserAssignedIdentities []infrav1.UserAssignedIdentity
-	SpotVMOptions          *infrav1.SpotVMOptions
-	SecurityProfile        *infrav1.SecurityProfile
-	AdditionalTags         infrav1.Tags
-	SKU                    resourceskus.SKU
-	Image                  *infrav1.Image
-	BootstrapData          string
-	ProviderID             string
+	Name                       string
+	ResourceGroup              string
+	SubscriptionID             string
+	Location                   string
+	ClusterName                string
+	Role                       string
+	NICIDs                     []string
+	SSHKeyData                 string
+	Size                       string
+	AvailabilitySetID          string
+	ProximityPlacementGroupID  string
+	CapacityReservationGroupID string
+	LicenseType                string
+	ComputerName               string
+	Zone                       string
+	Identity                   infrav1.VMIdentity
+	OSDisk                     infrav1.OSDisk
+	DataDisks                  []infrav1.DataDisk
+	UserAssignedIdentities     []infrav1.UserAssignedIdentity
+	SpotVMOptions              *infrav1.SpotVMOptions
+	SecurityProfile            *infrav1.SecurityProfile
+	AdditionalTags             infrav1.Tags
+	SKU                        resourceskus.SKU
+	Image                      *infrav1.Image
+	BootstrapData              string
+	UserData                   string
+	ProviderID                 string
+	// ForceDelete force deletes the VM, falling back to a normal deletion where force deletion is not available.
+	ForceDelete bool
+	// LocationZones are the availability zones of the location, which zone redundant disks are replicated across.
+	LocationZones []string
 }
 
 // ResourceName returns the name of the virtual machine.
@@ -75,11 +86,33 @@ func (s *VMSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the virtual machine.
 func (s *VMSpec) Parameters(existing interface{}) (interface{}, error) {
 	if existing != nil {
-		if _, ok := existing.(compute.VirtualMachine); !ok {
+		existingVM, ok := existing.(compute.VirtualMachine)
+		if !ok {
 			return nil, errors.Errorf("%T is not a compute.VirtualMachine", existing)
 		}
-		// vm already exists
-		return nil, nil
+		// vm already exists, only its user data and data disks can be updated in place.
+		if existingVM.VirtualMachineProperties == nil {
+			return nil, nil
+		}
+		changed := false
+		if to.String(existingVM.UserData) != s.UserData {
+			existingVM.UserData = to.StringPtr(s.UserData)
+			changed = true
+		}
+		if existingVM.StorageProfile != nil {
+			dataDisks, disksChanged, err := s.updateDataDisks(existingVM.StorageProfile.DataDisks)
+			if err != nil {
+				return nil, err
+			}
+			if disksChanged {
+				existingVM.StorageProfile.DataDisks = &dataDisks
+				changed = true
+			}
+		}
+		if !changed {
+			return nil, nil
+		}
+		return existingVM, nil
 	}
 
 	// VM got deleted outside of capz, do not recreate it as Machines are immutable.
@@ -123,8 +156,10 @@ func (s *VMSpec) Parameters(existing interface{}) (interface{}, error) {
 			Additional:  s.AdditionalTags,
 		})),
 		VirtualMachineProperties: &compute.VirtualMachineProperties{
-			AdditionalCapabilities: s.generateAdditionalCapabilities(),
-			AvailabilitySet:        s.getAvailabilitySet(),
+			AdditionalCapabilities:  s.generateAdditionalCapabilities(),
+			AvailabilitySet:         s.getAvailabilitySet(),
+			ProximityPlacementGroup: s.getProximityPlacementGroup(),
+			CapacityReservation:     s.getCapacityReservation(),
 			HardwareProfile: &compute.HardwareProfile{
 				VMSize: compute.VirtualMachineSizeTypes(s.Size),
 			},
@@ -142,6 +177,8 @@ func (s *VMSpec) Parameters(existing interface{}) (interface{}, error) {
 					Enabled: to.BoolPtr(true),
 				},
 			},
+			UserData:    s.getUserData(),
+			LicenseType: s.getLicenseType(),
 		},
 		Identity: identity,
 		Zones:    s.getZones(),
@@ -199,30 +236,21 @@ func (s *VMSpec) generateStorageProfile() (*compute.StorageProfile, error) {
 		}
 	}
 
-	dataDisks := make([]compute.DataDisk, len(s.DataDisks))
-	for i, disk := range s.DataDisks {
-		dataDisks[i] = compute.DataDisk{
-			CreateOption: compute.DiskCreateOptionTypesEmpty,
-			DiskSizeGB:   to.Int32Ptr(disk.DiskSizeGB),
-			Lun:          disk.Lun,
-			Name:         to.StringPtr(azure.GenerateDataDiskName(s.Name, disk.NameSuffix)),
-			Caching:      compute.CachingTypes(disk.CachingType),
-		}
-
-		if disk.ManagedDisk != nil {
-			dataDisks[i].ManagedDisk = &compute.ManagedDiskParameters{
-				StorageAccountType: compute.StorageAccountTypes(disk.ManagedDisk.StorageAccountType),
-			}
+	if err := s.validateWriteAccelerator(); err != nil {
+		return nil, err
+	}
 
-			if disk.ManagedDisk.DiskEncryptionSet != nil {
-				dataDisks[i].ManagedDisk.DiskEncryptionSet = &compute.DiskEncryptionSetParameters{ID: to.StringPtr(disk.ManagedDisk.DiskEncryptionSet.ID)}
-			}
+	if err := s.validateZoneRedundantDisks(); err != nil {
+		return nil, err
+	}
 
-			// check the support for ultra disks based on location and vm size
-			if disk.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) && !s.SKU.HasLocationCapability(resourceskus.UltraSSDAvailable, s.Location, s.Zone) {
-				return nil, azure.WithTerminalError(fmt.Errorf("vm size %s does not support ultra disks in location %s. select a different vm size or disable ultra disks", s.Size, s.Location))
-			}
+	dataDisks := make([]compute.DataDisk, len(s.DataDisks))
+	for i, disk := range s.DataDisks {
+		dataDisk, err := s.generateDataDisk(disk)
+		if err != nil {
+			return nil, err
 		}
+		dataDisks[i] = dataDisk
 	}
 	storageProfile.DataDisks = &dataDisks
 
@@ -236,14 +264,160 @@ func (s *VMSpec) generateStorageProfile() (*compute.StorageProfile, error) {
 	return storageProfile, nil
 }
 
+// dataDiskName returns the name of a data disk of the machine spec, shared disks are named after the cluster.
+func (s *VMSpec) dataDiskName(disk infrav1.DataDisk) string {
+	if disk.IsShared() {
+		return azure.GenerateSharedDataDiskName(s.ClusterName, disk.NameSuffix)
+	}
+	return azure.GenerateDataDiskName(s.Name, disk.NameSuffix)
+}
+
+// generateDataDisk converts a data disk of the machine spec into a data disk to create with the VM.
+// Shared disks are created by the disks service and attached to the VM.
+func (s *VMSpec) generateDataDisk(disk infrav1.DataDisk) (compute.DataDisk, error) {
+	if disk.IsShared() {
+		return compute.DataDisk{
+			CreateOption: compute.DiskCreateOptionTypesAttach,
+			Lun:          disk.Lun,
+			Name:         to.StringPtr(s.dataDiskName(disk)),
+			Caching:      compute.CachingTypes(disk.CachingType),
+			DeleteOption: compute.DiskDeleteOptionTypesDetach,
+			ManagedDisk: &compute.ManagedDiskParameters{
+				ID: to.StringPtr(azure.DiskID(s.SubscriptionID, s.ResourceGroup, s.dataDiskName(disk))),
+			},
+		}, nil
+	}
+
+	dataDisk := compute.DataDisk{
+		CreateOption:            compute.DiskCreateOptionTypesEmpty,
+		DiskSizeGB:              to.Int32Ptr(disk.DiskSizeGB),
+		Lun:                     disk.Lun,
+		Name:                    to.StringPtr(azure.GenerateDataDiskName(s.Name, disk.NameSuffix)),
+		Caching:                 compute.CachingTypes(disk.CachingType),
+		DeleteOption:            compute.DiskDeleteOptionTypesDelete,
+		WriteAcceleratorEnabled: disk.WriteAcceleratorEnabled,
+	}
+	if disk.DeleteOption == infrav1.DiskDeleteOptionDetach {
+		dataDisk.DeleteOption = compute.DiskDeleteOptionTypesDetach
+	}
+
+	if disk.ManagedDisk != nil {
+		dataDisk.ManagedDisk = &compute.ManagedDiskParameters{
+			StorageAccountType: compute.StorageAccountTypes(disk.ManagedDisk.StorageAccountType),
+		}
+
+		if disk.ManagedDisk.DiskEncryptionSet != nil {
+			dataDisk.ManagedDisk.DiskEncryptionSet = &compute.DiskEncryptionSetParameters{ID: to.StringPtr(disk.ManagedDisk.DiskEncryptionSet.ID)}
+		}
+
+		// check the support for ultra disks based on location and vm size
+		if disk.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) && !s.SKU.HasLocationCapability(resourceskus.UltraSSDAvailable, s.Location, s.Zone) {
+			return compute.DataDisk{}, azure.WithTerminalError(fmt.Errorf("vm size %s does not support ultra disks in location %s. select a different vm size or disable ultra disks", s.Size, s.Location))
+		}
+	}
+
+	return dataDisk, nil
+}
+
+// validateWriteAccelerator checks that the VM size supports Write Accelerator on as many data disks as it is enabled on.
+func (s *VMSpec) validateWriteAccelerator() error {
+	var count int64
+	for _, disk := range s.DataDisks {
+		if to.Bool(disk.WriteAcceleratorEnabled) {
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	supported, err := s.SKU.HasCapabilityWithCapacity(resourceskus.MaxWriteAcceleratorDisksAllowed, count)
+	if err != nil {
+		return azure.WithTerminalError(errors.Wrap(err, "failed to validate the write accelerator capability"))
+	}
+	if !supported {
+		return azure.WithTerminalError(fmt.Errorf("vm size %s does not support write accelerator on %d data disks. select a different vm size or disable write accelerator", s.Size, count))
+	}
+	return nil
+}
+
+// validateZoneRedundantDisks checks that the location has availability zones to replicate zone redundant disks across.
+func (s *VMSpec) validateZoneRedundantDisks() error {
+	if len(s.LocationZones) > 0 {
+		return nil
+	}
+	managedDisks := []*infrav1.ManagedDiskParameters{s.OSDisk.ManagedDisk}
+	for _, disk := range s.DataDisks {
+		managedDisks = append(managedDisks, disk.ManagedDisk)
+	}
+	for _, managedDisk := range managedDisks {
+		if managedDisk.IsZoneRedundant() {
+			return azure.WithTerminalError(fmt.Errorf("location %s does not have availability zones to replicate disks with storage account type %s across. select a locally redundant storage account type", s.Location, managedDisk.StorageAccountType))
+		}
+	}
+	return nil
+}
+
+// updateDataDisks reconciles the data disks attached to an existing VM with the ones of the machine spec.
+// Data disks missing from the VM are created and attached, and data disks of the machine or shared disks of the cluster
+// which are no longer in the spec are detached. Other disks are left untouched.
+// It returns the resulting data disks and whether they differ from the existing ones.
+func (s *VMSpec) updateDataDisks(existing *[]compute.DataDisk) ([]compute.DataDisk, bool, error) {
+	if err := s.validateWriteAccelerator(); err != nil {
+		return nil, false, err
+	}
+
+	if err := s.validateZoneRedundantDisks(); err != nil {
+		return nil, false, err
+	}
+
+	desired := make(map[string]infrav1.DataDisk, len(s.DataDisks))
+	for _, disk := range s.DataDisks {
+		desired[s.dataDiskName(disk)] = disk
+	}
+
+	// an empty list rather than nil, so that detaching the last data disks is sent to Azure.
+	dataDisks := []compute.DataDisk{}
+	attached := make(map[string]struct{})
+	changed := false
+	if existing != nil {
+		for _, disk := range *existing {
+			name := to.String(disk.Name)
+			if _, ok := desired[name]; !ok && (strings.HasPrefix(name, s.Name+"_") || strings.HasPrefix(name, s.ClusterName+"_")) {
+				changed = true
+				continue
+			}
+			attached[name] = struct{}{}
+			dataDisks = append(dataDisks, disk)
+		}
+	}
+
+	for _, disk := range s.DataDisks {
+		if _, ok := attached[s.dataDiskName(disk)]; ok {
+			continue
+		}
+		dataDisk, err := s.generateDataDisk(disk)
+		if err != nil {
+			return nil, false, err
+		}
+		dataDisks = append(dataDisks, dataDisk)
+		changed = true
+	}
+
+	return dataDisks, changed, nil
+}
+
 func (s *VMSpec) generateOSProfile() (*compute.OSProfile, error) {
 	sshKey, err := base64.StdEncoding.DecodeString(s.SSHKeyData)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to decode ssh public key")
 	}
 
+	computerName := s.Name
+	if s.ComputerName != "" {
+		computerName = s.ComputerName
+	}
 	osProfile := &compute.OSProfile{
-		ComputerName:  to.StringPtr(s.Name),
+		ComputerName:  to.StringPtr(computerName),
 		AdminUsername: to.StringPtr(azure.DefaultUserName),
 		CustomData:    to.StringPtr(s.BootstrapData),
 	}
@@ -283,13 +457,15 @@ func (s *VMSpec) generateSecurityProfile() (*compute.SecurityProfile, error) {
 		return nil, nil
 	}
 
-	if !s.SKU.HasCapability(resourceskus.EncryptionAtHost) {
+	if to.Bool(s.SecurityProfile.EncryptionAtHost) && !s.SKU.HasCapability(resourceskus.EncryptionAtHost) {
 		return nil, azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM type %s", s.Size))
 	}
 
-	return &compute.SecurityProfile{
-		EncryptionAtHost: s.SecurityProfile.EncryptionAtHost,
-	}, nil
+	if s.SecurityProfile.SecurityType == infrav1.SecurityTypesTrustedLaunch && !s.SKU.SupportsTrustedLaunch() {
+		return nil, azure.WithTerminalError(errors.Errorf("trusted launch is not supported for VM type %s", s.Size))
+	}
+
+	return converters.SecurityProfileToSDK(s.SecurityProfile), nil
 }
 
 func (s *VMSpec) generateNICRefs() *[]compute.NetworkInterfaceReference {
@@ -327,6 +503,31 @@ func (s *VMSpec) getAvailabilitySet() *compute.SubResource {
 	return as
 }
 
+func (s *VMSpec) getProximityPlacementGroup() *compute.SubResource {
+	var ppg *compute.SubResource
+	if s.ProximityPlacementGroupID != "" {
+		ppg = &compute.SubResource{ID: &s.ProximityPlacementGroupID}
+	}
+	return ppg
+}
+
+func (s *VMSpec) getCapacityReservation() *compute.CapacityReservationProfile {
+	var capacityReservation *compute.CapacityReservationProfile
+	if s.CapacityReservationGroupID != "" {
+		capacityReservation = &compute.CapacityReservationProfile{
+			CapacityReservationGroup: &compute.SubResource{ID: &s.CapacityReservationGroupID},
+		}
+	}
+	return capacityReservation
+}
+
+func (s *VMSpec) getLicenseType() *string {
+	if s.LicenseType == "" {
+		return nil
+	}
+	return to.StringPtr(s.LicenseType)
+}
+
 func (s *VMSpec) getZones() *[]string {
 	var zones *[]string
 	if s.Zone != "" {
@@ -334,3 +535,10 @@ func (s *VMSpec) getZones() *[]string {
 	}
 	return zones
 }
+
+func (s *VMSpec) getUserData() *string {
+	if s.UserData == "" {
+		return nil
+	}
+	return to.StringPtr(s.UserData)
+}
@@ -24,9 +24,11 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/golden"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
@@ -71,6 +73,28 @@ var (
 		},
 	}
 
+	validSKUWithTrustedLaunch = resourceskus.SKU{
+		Name: to.StringPtr("Standard_D2v3"),
+		Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
+		Locations: &[]string{
+			"test-location",
+		},
+		Capabilities: &[]compute.ResourceSkuCapabilities{
+			{
+				Name:  to.StringPtr(resourceskus.VCPUs),
+				Value: to.StringPtr("2"),
+			},
+			{
+				Name:  to.StringPtr(resourceskus.MemoryGB),
+				Value: to.StringPtr("4"),
+			},
+			{
+				Name:  to.StringPtr(resourceskus.HyperVGenerations),
+				Value: to.StringPtr("V1,V2"),
+			},
+		},
+	}
+
 	validSKUWithEphemeralOS = resourceskus.SKU{
 		Name: to.StringPtr("Standard_D2v3"),
 		Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
@@ -128,6 +152,28 @@ var (
 		},
 	}
 
+	validSKUWithWriteAccelerator = resourceskus.SKU{
+		Name: to.StringPtr("Standard_M32ms"),
+		Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
+		Locations: &[]string{
+			"test-location",
+		},
+		Capabilities: &[]compute.ResourceSkuCapabilities{
+			{
+				Name:  to.StringPtr(resourceskus.VCPUs),
+				Value: to.StringPtr("32"),
+			},
+			{
+				Name:  to.StringPtr(resourceskus.MemoryGB),
+				Value: to.StringPtr("875"),
+			},
+			{
+				Name:  to.StringPtr(resourceskus.MaxWriteAcceleratorDisksAllowed),
+				Value: to.StringPtr("1"),
+			},
+		},
+	}
+
 	invalidCPUSKU = resourceskus.SKU{
 		Name: to.StringPtr("Standard_D2v3"),
 		Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
@@ -166,6 +212,8 @@ var (
 )
 
 func TestParameters(t *testing.T) {
+	deleteEvictionPolicy := infrav1.SpotEvictionPolicyDelete
+
 	testcases := []struct {
 		name          string
 		spec          *VMSpec
@@ -191,6 +239,208 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "returns nil if the user data of the existing vm is up to date",
+			spec: &VMSpec{
+				UserData: "ZmFrZS11c2VyLWRhdGE=",
+			},
+			existing: compute.VirtualMachine{
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					UserData: to.StringPtr("ZmFrZS11c2VyLWRhdGE="),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name: "updates the user data of an existing vm in place",
+			spec: &VMSpec{
+				UserData: "ZmFrZS11c2VyLWRhdGE=",
+			},
+			existing: compute.VirtualMachine{
+				Location: to.StringPtr("test-location"),
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					HardwareProfile: &compute.HardwareProfile{VMSize: "Standard_D2v3"},
+					UserData:        to.StringPtr("b2xkLXVzZXItZGF0YQ=="),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(compute.VirtualMachine{
+					Location: to.StringPtr("test-location"),
+					VirtualMachineProperties: &compute.VirtualMachineProperties{
+						HardwareProfile: &compute.HardwareProfile{VMSize: "Standard_D2v3"},
+						UserData:        to.StringPtr("ZmFrZS11c2VyLWRhdGE="),
+					},
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "clears the user data of an existing vm",
+			spec: &VMSpec{},
+			existing: compute.VirtualMachine{
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					UserData: to.StringPtr("b2xkLXVzZXItZGF0YQ=="),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).UserData).To(Equal(to.StringPtr("")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "returns nil if the data disks of the existing vm are up to date",
+			spec: &VMSpec{
+				Name: "my-vm",
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "mydisk",
+						DiskSizeGB: 64,
+						Lun:        to.Int32Ptr(0),
+					},
+				},
+			},
+			existing: compute.VirtualMachine{
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					UserData: to.StringPtr(""),
+					StorageProfile: &compute.StorageProfile{
+						DataDisks: &[]compute.DataDisk{
+							{
+								Lun:          to.Int32Ptr(0),
+								Name:         to.StringPtr("my-vm_mydisk"),
+								CreateOption: "Empty",
+								DiskSizeGB:   to.Int32Ptr(64),
+							},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name: "attaches data disks added to an existing vm",
+			spec: &VMSpec{
+				Name: "my-vm",
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "mydisk",
+						DiskSizeGB: 64,
+						Lun:        to.Int32Ptr(0),
+					},
+					{
+						NameSuffix:   "newdisk",
+						DiskSizeGB:   128,
+						Lun:          to.Int32Ptr(1),
+						CachingType:  "ReadOnly",
+						DeleteOption: infrav1.DiskDeleteOptionDetach,
+					},
+				},
+			},
+			existing: compute.VirtualMachine{
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					UserData: to.StringPtr(""),
+					StorageProfile: &compute.StorageProfile{
+						DataDisks: &[]compute.DataDisk{
+							{
+								Lun:          to.Int32Ptr(0),
+								Name:         to.StringPtr("my-vm_mydisk"),
+								CreateOption: "Empty",
+								DiskSizeGB:   to.Int32Ptr(64),
+							},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).StorageProfile.DataDisks).To(Equal(&[]compute.DataDisk{
+					{
+						Lun:          to.Int32Ptr(0),
+						Name:         to.StringPtr("my-vm_mydisk"),
+						CreateOption: "Empty",
+						DiskSizeGB:   to.Int32Ptr(64),
+					},
+					{
+						Lun:          to.Int32Ptr(1),
+						Name:         to.StringPtr("my-vm_newdisk"),
+						CreateOption: "Empty",
+						DiskSizeGB:   to.Int32Ptr(128),
+						Caching:      "ReadOnly",
+						DeleteOption: "Detach",
+					},
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "detaches data disks removed from an existing vm",
+			spec: &VMSpec{
+				Name: "my-vm",
+			},
+			existing: compute.VirtualMachine{
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					UserData: to.StringPtr(""),
+					StorageProfile: &compute.StorageProfile{
+						DataDisks: &[]compute.DataDisk{
+							{
+								Lun:          to.Int32Ptr(0),
+								Name:         to.StringPtr("my-vm_mydisk"),
+								CreateOption: "Empty",
+								DiskSizeGB:   to.Int32Ptr(64),
+							},
+							{
+								Lun:          to.Int32Ptr(1),
+								Name:         to.StringPtr("attached-out-of-band"),
+								CreateOption: "Attach",
+							},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).StorageProfile.DataDisks).To(Equal(&[]compute.DataDisk{
+					{
+						Lun:          to.Int32Ptr(1),
+						Name:         to.StringPtr("attached-out-of-band"),
+						CreateOption: "Attach",
+					},
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "detaches shared disks removed from an existing vm",
+			spec: &VMSpec{
+				Name:        "my-vm",
+				ClusterName: "my-cluster",
+			},
+			existing: compute.VirtualMachine{
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					UserData: to.StringPtr(""),
+					StorageProfile: &compute.StorageProfile{
+						DataDisks: &[]compute.DataDisk{
+							{
+								Lun:          to.Int32Ptr(0),
+								Name:         to.StringPtr("my-cluster_quorum"),
+								CreateOption: "Attach",
+							},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).StorageProfile.DataDisks).To(Equal(&[]compute.DataDisk{}))
+			},
+			expectedError: "",
+		},
 		{
 			name: "fails if vm deleted out of band, should not recreate",
 			spec: &VMSpec{
@@ -223,6 +473,26 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm with user data",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				SKU:        validSKU,
+				UserData:   "ZmFrZS11c2VyLWRhdGE=",
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).UserData).To(Equal(to.StringPtr("ZmFrZS11c2VyLWRhdGE=")))
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a vm with user assigned identity ",
 			spec: &VMSpec{
@@ -267,6 +537,31 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a spot vm with a maximum price which is deleted on eviction",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				SpotVMOptions: &infrav1.SpotVMOptions{
+					MaxPrice:       resource.NewMilliQuantity(25, resource.DecimalSI),
+					EvictionPolicy: &deleteEvictionPolicy,
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).Priority).To(Equal(compute.VirtualMachinePriorityTypesSpot))
+				g.Expect(result.(compute.VirtualMachine).EvictionPolicy).To(Equal(compute.VirtualMachineEvictionPolicyTypesDelete))
+				g.Expect(result.(compute.VirtualMachine).BillingProfile.MaxPrice).To(Equal(to.Float64Ptr(0.025)))
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a windows vm",
 			spec: &VMSpec{
@@ -293,6 +588,34 @@ func TestParameters(t *testing.T) {
 				g.Expect(*result.(compute.VirtualMachine).VirtualMachineProperties.OsProfile.AdminPassword).Should(HaveLen(123))
 				g.Expect(*result.(compute.VirtualMachine).VirtualMachineProperties.OsProfile.AdminUsername).Should(Equal("capi"))
 				g.Expect(*result.(compute.VirtualMachine).VirtualMachineProperties.OsProfile.WindowsConfiguration.EnableAutomaticUpdates).Should(Equal(false))
+				g.Expect(result.(compute.VirtualMachine).VirtualMachineProperties.LicenseType).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a windows vm with azure hybrid benefit",
+			spec: &VMSpec{
+				Name:        "my-vm",
+				Role:        infrav1.Node,
+				NICIDs:      []string{"my-nic"},
+				SSHKeyData:  "fakesshpublickey",
+				Size:        "Standard_D2v3",
+				Zone:        "1",
+				Image:       &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				LicenseType: "Windows_Server",
+				OSDisk: infrav1.OSDisk{
+					OSType:     "Windows",
+					DiskSizeGB: to.Int32Ptr(128),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).VirtualMachineProperties.LicenseType).To(Equal(to.StringPtr("Windows_Server")))
 			},
 			expectedError: "",
 		},
@@ -343,6 +666,52 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a trusted launch vm",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				SecurityProfile: &infrav1.SecurityProfile{
+					SecurityType: infrav1.SecurityTypesTrustedLaunch,
+					UefiSettings: &infrav1.UefiSettings{SecureBootEnabled: to.BoolPtr(true), VTpmEnabled: to.BoolPtr(true)},
+				},
+				SKU: validSKUWithTrustedLaunch,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				securityProfile := result.(compute.VirtualMachine).VirtualMachineProperties.SecurityProfile
+				g.Expect(securityProfile.SecurityType).To(Equal(compute.SecurityTypesTrustedLaunch))
+				g.Expect(securityProfile.UefiSettings).To(Equal(&compute.UefiSettings{SecureBootEnabled: to.BoolPtr(true), VTpmEnabled: to.BoolPtr(true)}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "creating a trusted launch vm for unsupported VM type fails",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				SecurityProfile: &infrav1.SecurityProfile{
+					SecurityType: infrav1.SecurityTypesTrustedLaunch,
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: trusted launch is not supported for VM type Standard_D2v3. Object will not be requeued",
+		},
 		{
 			name: "can create a vm and assign it to an availability set",
 			spec: &VMSpec{
@@ -364,6 +733,66 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm in a proximity placement group",
+			spec: &VMSpec{
+				Name:                      "my-vm",
+				Role:                      infrav1.Node,
+				NICIDs:                    []string{"my-nic"},
+				SSHKeyData:                "fakesshpublickey",
+				Size:                      "Standard_D2v3",
+				ProximityPlacementGroupID: "fake-proximity-placement-group-id",
+				Zone:                      "1",
+				Image:                     &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				SKU:                       validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).ProximityPlacementGroup.ID).To(Equal(to.StringPtr("fake-proximity-placement-group-id")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm with a computer name",
+			spec: &VMSpec{
+				Name:         "my-vm",
+				ComputerName: "web-1-abcde",
+				Role:         infrav1.Node,
+				NICIDs:       []string{"my-nic"},
+				SSHKeyData:   "fakesshpublickey",
+				Size:         "Standard_D2v3",
+				Zone:         "1",
+				Image:        &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				SKU:          validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).OsProfile.ComputerName).To(Equal(to.StringPtr("web-1-abcde")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm from a capacity reservation group",
+			spec: &VMSpec{
+				Name:                       "my-vm",
+				Role:                       infrav1.Node,
+				NICIDs:                     []string{"my-nic"},
+				SSHKeyData:                 "fakesshpublickey",
+				Size:                       "Standard_D2v3",
+				CapacityReservationGroupID: "fake-capacity-reservation-group-id",
+				Zone:                       "1",
+				Image:                      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				SKU:                        validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).CapacityReservation.CapacityReservationGroup.ID).To(Equal(to.StringPtr("fake-capacity-reservation-group-id")))
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a vm with EphemeralOSDisk",
 			spec: &VMSpec{
@@ -412,6 +841,27 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "reconcile error that cannot be recovered occurred: encryption at host is not supported for VM type Standard_D2v3. Object will not be requeued",
 		},
+		{
+			name: "can create a vm with encryption at host disabled for unsupported VM type",
+			spec: &VMSpec{
+				Name:              "my-vm",
+				Role:              infrav1.Node,
+				NICIDs:            []string{"my-nic"},
+				SSHKeyData:        "fakesshpublickey",
+				Size:              "Standard_D2v3",
+				AvailabilitySetID: "fake-availability-set-id",
+				Zone:              "",
+				Image:             &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				SecurityProfile:   &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(false)},
+				SKU:               validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).SecurityProfile.EncryptionAtHost).To(Equal(to.BoolPtr(false)))
+			},
+			expectedError: "",
+		},
 		{
 			name: "cannot create vm with EphemeralOSDisk if does not support ephemeral os",
 			spec: &VMSpec{
@@ -593,12 +1043,14 @@ func TestParameters(t *testing.T) {
 						Lun:          to.Int32Ptr(0),
 						Name:         to.StringPtr("my-ultra-ssd-vm_mydisk"),
 						CreateOption: "Empty",
+						DeleteOption: "Delete",
 						DiskSizeGB:   to.Int32Ptr(64),
 					},
 					{
 						Lun:          to.Int32Ptr(1),
 						Name:         to.StringPtr("my-ultra-ssd-vm_myDiskWithUltraDisk"),
 						CreateOption: "Empty",
+						DeleteOption: "Delete",
 						DiskSizeGB:   to.Int32Ptr(128),
 						ManagedDisk: &compute.ManagedDiskParameters{
 							StorageAccountType: "UltraSSD_LRS",
@@ -608,6 +1060,7 @@ func TestParameters(t *testing.T) {
 						Lun:          to.Int32Ptr(2),
 						Name:         to.StringPtr("my-ultra-ssd-vm_myDiskWithManagedDisk"),
 						CreateOption: "Empty",
+						DeleteOption: "Delete",
 						DiskSizeGB:   to.Int32Ptr(128),
 						ManagedDisk: &compute.ManagedDiskParameters{
 							StorageAccountType: "Premium_LRS",
@@ -617,6 +1070,7 @@ func TestParameters(t *testing.T) {
 						Lun:          to.Int32Ptr(3),
 						Name:         to.StringPtr("my-ultra-ssd-vm_managedDiskWithEncryption"),
 						CreateOption: "Empty",
+						DeleteOption: "Delete",
 						DiskSizeGB:   to.Int32Ptr(128),
 						ManagedDisk: &compute.ManagedDiskParameters{
 							StorageAccountType: "Premium_LRS",
@@ -659,6 +1113,210 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "reconcile error that cannot be recovered occurred: vm size Standard_D2v3 does not support ultra disks in location test-location. select a different vm size or disable ultra disks. Object will not be requeued",
 		},
+		{
+			name: "can create a vm with write accelerator enabled",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_M32ms",
+				Location:   "test-location",
+				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "myDiskWithWriteAccelerator",
+						DiskSizeGB: 128,
+						Lun:        to.Int32Ptr(0),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+						},
+						CachingType:             "None",
+						WriteAcceleratorEnabled: to.BoolPtr(true),
+					},
+				},
+				SKU: validSKUWithWriteAccelerator,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				dataDisks := *result.(compute.VirtualMachine).StorageProfile.DataDisks
+				g.Expect(dataDisks[0].WriteAcceleratorEnabled).To(Equal(to.BoolPtr(true)))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm with a shared data disk",
+			spec: &VMSpec{
+				Name:           "my-vm",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				ClusterName:    "my-cluster",
+				Role:           infrav1.Node,
+				NICIDs:         []string{"my-nic"},
+				SSHKeyData:     "fakesshpublickey",
+				Size:           "Standard_D2v3",
+				Location:       "test-location",
+				Image:          &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "quorum",
+						DiskSizeGB: 32,
+						Lun:        to.Int32Ptr(0),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+						},
+						CachingType:  "None",
+						DeleteOption: infrav1.DiskDeleteOptionDetach,
+						MaxShares:    to.Int32Ptr(2),
+					},
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).StorageProfile.DataDisks).To(Equal(&[]compute.DataDisk{
+					{
+						Lun:          to.Int32Ptr(0),
+						Name:         to.StringPtr("my-cluster_quorum"),
+						CreateOption: "Attach",
+						Caching:      "None",
+						DeleteOption: "Detach",
+						ManagedDisk: &compute.ManagedDiskParameters{
+							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-cluster_quorum"),
+						},
+					},
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "creating a vm with write accelerator on more disks than supported fails",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_M32ms",
+				Location:   "test-location",
+				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix:              "myDisk1",
+						DiskSizeGB:              128,
+						Lun:                     to.Int32Ptr(0),
+						WriteAcceleratorEnabled: to.BoolPtr(true),
+					},
+					{
+						NameSuffix:              "myDisk2",
+						DiskSizeGB:              128,
+						Lun:                     to.Int32Ptr(1),
+						WriteAcceleratorEnabled: to.BoolPtr(true),
+					},
+				},
+				SKU: validSKUWithWriteAccelerator,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: vm size Standard_M32ms does not support write accelerator on 2 data disks. select a different vm size or disable write accelerator. Object will not be requeued",
+		},
+		{
+			name: "creating a vm with write accelerator for unsupported VM type fails",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Location:   "test-location",
+				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix:              "myDisk",
+						DiskSizeGB:              128,
+						Lun:                     to.Int32Ptr(0),
+						WriteAcceleratorEnabled: to.BoolPtr(true),
+					},
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: vm size Standard_D2v3 does not support write accelerator on 1 data disks. select a different vm size or disable write accelerator. Object will not be requeued",
+		},
+		{
+			name: "can create a vm with zone redundant disks",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Location:   "test-location",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				OSDisk: infrav1.OSDisk{
+					OSType:     "Linux",
+					DiskSizeGB: to.Int32Ptr(128),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "StandardSSD_ZRS",
+					},
+				},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "myDisk",
+						DiskSizeGB: 128,
+						Lun:        to.Int32Ptr(0),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_ZRS",
+						},
+					},
+				},
+				SKU:           validSKU,
+				LocationZones: []string{"1", "2", "3"},
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				vm := result.(compute.VirtualMachine)
+				g.Expect(vm.StorageProfile.OsDisk.ManagedDisk.StorageAccountType).To(Equal(compute.StorageAccountTypesStandardSSDZRS))
+				g.Expect((*vm.StorageProfile.DataDisks)[0].ManagedDisk.StorageAccountType).To(Equal(compute.StorageAccountTypesPremiumZRS))
+			},
+			expectedError: "",
+		},
+		{
+			name: "creating a vm with zone redundant disks in a location without availability zones fails",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Location:   "test-location",
+				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "myDisk",
+						DiskSizeGB: 128,
+						Lun:        to.Int32Ptr(0),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_ZRS",
+						},
+					},
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: location test-location does not have availability zones to replicate disks with storage account type Premium_ZRS across. select a locally redundant storage account type. Object will not be requeued",
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -677,3 +1335,15 @@ func TestParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestVMPayload(t *testing.T) {
+	g := NewWithT(t)
+
+	golden.Deterministic(t)
+	spec := fakeVMSpec
+	spec.SSHKeyData = "c3NoLXJzYSBmYWtlLXNzaC1wdWJsaWMta2V5"
+	spec.SKU = validSKU
+	result, err := spec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	golden.Assert(t, "vm", result)
+}
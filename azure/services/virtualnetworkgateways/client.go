@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworkgateways
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, string, string) (network.VirtualNetworkGateway, error)
+	CreateOrUpdate(context.Context, string, string, network.VirtualNetworkGateway) error
+	Delete(context.Context, string, string) error
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	virtualnetworkgateways network.VirtualNetworkGatewaysClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new virtual network gateway client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newVirtualNetworkGatewaysClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c}
+}
+
+// newVirtualNetworkGatewaysClient creates a new virtual network gateways client from subscription ID.
+func newVirtualNetworkGatewaysClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.VirtualNetworkGatewaysClient {
+	gatewaysClient := network.NewVirtualNetworkGatewaysClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&gatewaysClient.Client, authorizer, "virtualnetworkgateways")
+	return gatewaysClient
+}
+
+// Get gets the specified virtual network gateway.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, gatewayName string) (_ network.VirtualNetworkGateway, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworkgateways.AzureClient.Get")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.virtualnetworkgateways.Get(ctx, resourceGroupName, gatewayName)
+}
+
+// CreateOrUpdate creates or updates a virtual network gateway in the specified resource group.
+func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, gatewayName string, gateway network.VirtualNetworkGateway) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworkgateways.AzureClient.CreateOrUpdate")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.virtualnetworkgateways.CreateOrUpdate(ctx, resourceGroupName, gatewayName, gateway)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.virtualnetworkgateways.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.virtualnetworkgateways)
+	return err
+}
+
+// Delete deletes the specified virtual network gateway.
+func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, gatewayName string) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworkgateways.AzureClient.Delete")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.virtualnetworkgateways.Delete(ctx, resourceGroupName, gatewayName)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.virtualnetworkgateways.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.virtualnetworkgateways)
+	return err
+}
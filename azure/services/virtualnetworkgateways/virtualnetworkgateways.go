@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworkgateways
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// VirtualNetworkGatewayScope defines the scope interface for a virtual network gateway service.
+type VirtualNetworkGatewayScope interface {
+	azure.ClusterDescriber
+	ExpressRouteGatewaySpec() *azure.ExpressRouteGatewaySpec
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope VirtualNetworkGatewayScope
+	client
+	subnetsClient   subnets.Client
+	publicIPsClient publicips.Client
+}
+
+// New creates a new service.
+func New(scope VirtualNetworkGatewayScope) *Service {
+	return &Service{
+		Scope:           scope,
+		client:          newClient(scope),
+		subnetsClient:   subnets.NewClient(scope),
+		publicIPsClient: publicips.NewClient(scope),
+	}
+}
+
+// Reconcile creates the ExpressRoute gateway of the virtual network if it doesn't exist.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualnetworkgateways.Service.Reconcile")
+	defer done()
+
+	gatewaySpec := s.Scope.ExpressRouteGatewaySpec()
+	if gatewaySpec == nil {
+		return nil
+	}
+
+	_, err := s.client.Get(ctx, gatewaySpec.ResourceGroup, gatewaySpec.Name)
+	switch {
+	case err == nil:
+		// the SKU and the IP configuration of a gateway are not updated, as it may require recreating it
+		return nil
+	case !azure.ResourceNotFound(err):
+		return errors.Wrapf(err, "failed to get ExpressRoute gateway %s", gatewaySpec.Name)
+	}
+
+	publicIP, err := s.publicIPsClient.Get(ctx, s.Scope.ResourceGroup(), gatewaySpec.PublicIPName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get public IP for ExpressRoute gateway")
+	}
+
+	subnet, err := s.subnetsClient.Get(ctx, gatewaySpec.ResourceGroup, gatewaySpec.VNetName, gatewaySpec.SubnetName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get subnet for ExpressRoute gateway")
+	}
+
+	log.V(2).Info("creating ExpressRoute gateway", "gateway", gatewaySpec.Name)
+	err = s.client.CreateOrUpdate(ctx, gatewaySpec.ResourceGroup, gatewaySpec.Name, network.VirtualNetworkGateway{
+		Location: to.StringPtr(s.Scope.Location()),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.Scope.ClusterName(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(gatewaySpec.Name),
+			Role:        to.StringPtr(infrav1.CommonRole),
+			Additional:  s.Scope.AdditionalTags(),
+		})),
+		VirtualNetworkGatewayPropertiesFormat: &network.VirtualNetworkGatewayPropertiesFormat{
+			GatewayType: network.VirtualNetworkGatewayTypeExpressRoute,
+			Sku: &network.VirtualNetworkGatewaySku{
+				Name: network.VirtualNetworkGatewaySkuName(gatewaySpec.Sku),
+				Tier: network.VirtualNetworkGatewaySkuTier(gatewaySpec.Sku),
+			},
+			IPConfigurations: &[]network.VirtualNetworkGatewayIPConfiguration{
+				{
+					Name: to.StringPtr(fmt.Sprintf("%s-ipconfig", gatewaySpec.Name)),
+					VirtualNetworkGatewayIPConfigurationPropertiesFormat: &network.VirtualNetworkGatewayIPConfigurationPropertiesFormat{
+						PrivateIPAllocationMethod: network.IPAllocationMethodDynamic,
+						Subnet:                    &network.SubResource{ID: subnet.ID},
+						PublicIPAddress:           &network.SubResource{ID: publicIP.ID},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create ExpressRoute gateway %s in resource group %s", gatewaySpec.Name, gatewaySpec.ResourceGroup)
+	}
+
+	log.V(2).Info("successfully created ExpressRoute gateway", "gateway", gatewaySpec.Name)
+	return nil
+}
+
+// Delete deletes the ExpressRoute gateway of the virtual network if it is owned by the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualnetworkgateways.Service.Delete")
+	defer done()
+
+	gatewaySpec := s.Scope.ExpressRouteGatewaySpec()
+	if gatewaySpec == nil {
+		return nil
+	}
+
+	gateway, err := s.client.Get(ctx, gatewaySpec.ResourceGroup, gatewaySpec.Name)
+	if azure.ResourceGroupNotFound(err) || azure.ResourceNotFound(err) {
+		// gateway does not exist, there is nothing to delete
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get ExpressRoute gateway %s", gatewaySpec.Name)
+	}
+
+	if !converters.MapToTags(gateway.Tags).HasOwned(s.Scope.ClusterName()) {
+		log.V(4).Info("Skipping deletion of unmanaged ExpressRoute gateway", "gateway", gatewaySpec.Name)
+		return nil
+	}
+
+	log.V(2).Info("deleting ExpressRoute gateway", "gateway", gatewaySpec.Name)
+	err = s.client.Delete(ctx, gatewaySpec.ResourceGroup, gatewaySpec.Name)
+	if err != nil && !azure.ResourceGroupNotFound(err) && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete ExpressRoute gateway %s in resource group %s", gatewaySpec.Name, gatewaySpec.ResourceGroup)
+	}
+
+	log.V(2).Info("successfully deleted ExpressRoute gateway", "gateway", gatewaySpec.Name)
+	return nil
+}
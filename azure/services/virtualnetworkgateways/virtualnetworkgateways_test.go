@@ -0,0 +1,297 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworkgateways
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips/mock_publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets/mock_subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworkgateways/mock_virtualnetworkgateways"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeGatewaySpec = azure.ExpressRouteGatewaySpec{
+		Name:          "my-ergw",
+		ResourceGroup: "my-vnet-rg",
+		Sku:           infrav1.ExpressRouteGatewaySkuErGw1AZ,
+		VNetName:      "my-vnet",
+		SubnetName:    "GatewaySubnet",
+		PublicIPName:  "my-ergw-pip",
+	}
+	notFoundError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found")
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+)
+
+func TestReconcileVirtualNetworkGateways(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder,
+			m *mock_virtualnetworkgateways.MockclientMockRecorder,
+			mSubnet *mock_subnets.MockClientMockRecorder,
+			mPublicIP *mock_publicips.MockClientMockRecorder)
+	}{
+		{
+			name:          "no gateway to create",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder,
+				m *mock_virtualnetworkgateways.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(nil)
+			},
+		},
+		{
+			name:          "gateway already exists",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder,
+				m *mock_virtualnetworkgateways.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(&fakeGatewaySpec)
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-ergw").Return(network.VirtualNetworkGateway{Name: to.StringPtr("my-ergw")}, nil)
+			},
+		},
+		{
+			name:          "create gateway",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder,
+				m *mock_virtualnetworkgateways.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(&fakeGatewaySpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().Return("westus")
+				s.ClusterName().Return("my-cluster")
+				s.AdditionalTags().Return(infrav1.Tags{})
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-ergw").Return(network.VirtualNetworkGateway{}, notFoundError)
+				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-ergw-pip").Return(network.PublicIPAddress{ID: to.StringPtr("my-ergw-pip-id")}, nil)
+				mSubnet.Get(gomockinternal.AContext(), "my-vnet-rg", "my-vnet", "GatewaySubnet").Return(network.Subnet{ID: to.StringPtr("my-gateway-subnet-id")}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-vnet-rg", "my-ergw", gomockinternal.DiffEq(network.VirtualNetworkGateway{
+					Location: to.StringPtr("westus"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-ergw"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr("common"),
+					},
+					VirtualNetworkGatewayPropertiesFormat: &network.VirtualNetworkGatewayPropertiesFormat{
+						GatewayType: network.VirtualNetworkGatewayTypeExpressRoute,
+						Sku: &network.VirtualNetworkGatewaySku{
+							Name: network.VirtualNetworkGatewaySkuNameErGw1AZ,
+							Tier: network.VirtualNetworkGatewaySkuTierErGw1AZ,
+						},
+						IPConfigurations: &[]network.VirtualNetworkGatewayIPConfiguration{
+							{
+								Name: to.StringPtr("my-ergw-ipconfig"),
+								VirtualNetworkGatewayIPConfigurationPropertiesFormat: &network.VirtualNetworkGatewayIPConfigurationPropertiesFormat{
+									PrivateIPAllocationMethod: network.IPAllocationMethodDynamic,
+									Subnet:                    &network.SubResource{ID: to.StringPtr("my-gateway-subnet-id")},
+									PublicIPAddress:           &network.SubResource{ID: to.StringPtr("my-ergw-pip-id")},
+								},
+							},
+						},
+					},
+				}))
+			},
+		},
+		{
+			name:          "fail to get gateway",
+			expectedError: "failed to get ExpressRoute gateway my-ergw: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder,
+				m *mock_virtualnetworkgateways.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(&fakeGatewaySpec)
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-ergw").Return(network.VirtualNetworkGateway{}, internalError)
+			},
+		},
+		{
+			name:          "fail to get public IP",
+			expectedError: "failed to get public IP for ExpressRoute gateway: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder,
+				m *mock_virtualnetworkgateways.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(&fakeGatewaySpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-ergw").Return(network.VirtualNetworkGateway{}, notFoundError)
+				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-ergw-pip").Return(network.PublicIPAddress{}, internalError)
+			},
+		},
+		{
+			name:          "fail to get subnet",
+			expectedError: "failed to get subnet for ExpressRoute gateway: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder,
+				m *mock_virtualnetworkgateways.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(&fakeGatewaySpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-ergw").Return(network.VirtualNetworkGateway{}, notFoundError)
+				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-ergw-pip").Return(network.PublicIPAddress{ID: to.StringPtr("my-ergw-pip-id")}, nil)
+				mSubnet.Get(gomockinternal.AContext(), "my-vnet-rg", "my-vnet", "GatewaySubnet").Return(network.Subnet{}, internalError)
+			},
+		},
+		{
+			name:          "fail to create gateway",
+			expectedError: "failed to create ExpressRoute gateway my-ergw in resource group my-vnet-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder,
+				m *mock_virtualnetworkgateways.MockclientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(&fakeGatewaySpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().Return("westus")
+				s.ClusterName().Return("my-cluster")
+				s.AdditionalTags().Return(infrav1.Tags{})
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-ergw").Return(network.VirtualNetworkGateway{}, notFoundError)
+				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-ergw-pip").Return(network.PublicIPAddress{ID: to.StringPtr("my-ergw-pip-id")}, nil)
+				mSubnet.Get(gomockinternal.AContext(), "my-vnet-rg", "my-vnet", "GatewaySubnet").Return(network.Subnet{ID: to.StringPtr("my-gateway-subnet-id")}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-vnet-rg", "my-ergw", gomock.AssignableToTypeOf(network.VirtualNetworkGateway{})).Return(internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_virtualnetworkgateways.NewMockVirtualNetworkGatewayScope(mockCtrl)
+			clientMock := mock_virtualnetworkgateways.NewMockclient(mockCtrl)
+			subnetMock := mock_subnets.NewMockClient(mockCtrl)
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(),
+				subnetMock.EXPECT(), publicIPsMock.EXPECT())
+
+			s := &Service{
+				Scope:           scopeMock,
+				client:          clientMock,
+				subnetsClient:   subnetMock,
+				publicIPsClient: publicIPsMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteVirtualNetworkGateways(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder, m *mock_virtualnetworkgateways.MockclientMockRecorder)
+	}{
+		{
+			name:          "no gateway to delete",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder, m *mock_virtualnetworkgateways.MockclientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(nil)
+			},
+		},
+		{
+			name:          "gateway already deleted",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder, m *mock_virtualnetworkgateways.MockclientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(&fakeGatewaySpec)
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-ergw").Return(network.VirtualNetworkGateway{}, notFoundError)
+			},
+		},
+		{
+			name:          "skip unmanaged gateway",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder, m *mock_virtualnetworkgateways.MockclientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(&fakeGatewaySpec)
+				s.ClusterName().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-ergw").Return(network.VirtualNetworkGateway{Name: to.StringPtr("my-ergw")}, nil)
+			},
+		},
+		{
+			name:          "delete managed gateway",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder, m *mock_virtualnetworkgateways.MockclientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(&fakeGatewaySpec)
+				s.ClusterName().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-ergw").Return(network.VirtualNetworkGateway{
+					Name: to.StringPtr("my-ergw"),
+					Tags: map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned")},
+				}, nil)
+				m.Delete(gomockinternal.AContext(), "my-vnet-rg", "my-ergw")
+			},
+		},
+		{
+			name:          "fail to delete gateway",
+			expectedError: "failed to delete ExpressRoute gateway my-ergw in resource group my-vnet-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualnetworkgateways.MockVirtualNetworkGatewayScopeMockRecorder, m *mock_virtualnetworkgateways.MockclientMockRecorder) {
+				s.ExpressRouteGatewaySpec().Return(&fakeGatewaySpec)
+				s.ClusterName().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-vnet-rg", "my-ergw").Return(network.VirtualNetworkGateway{
+					Name: to.StringPtr("my-ergw"),
+					Tags: map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned")},
+				}, nil)
+				m.Delete(gomockinternal.AContext(), "my-vnet-rg", "my-ergw").Return(internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_virtualnetworkgateways.NewMockVirtualNetworkGatewayScope(mockCtrl)
+			clientMock := mock_virtualnetworkgateways.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -32,11 +33,15 @@ type Client interface {
 	CreateOrUpdate(context.Context, string, string, network.VirtualNetwork) error
 	Delete(context.Context, string, string) error
 	CheckIPAddressAvailability(context.Context, string, string, string) (network.IPAddressAvailabilityResult, error)
+	GetDDoSProtectionPlan(context.Context, string, string) (network.DdosProtectionPlan, error)
+	CreateOrUpdateDDoSProtectionPlan(context.Context, string, string, network.DdosProtectionPlan) error
+	DeleteDDoSProtectionPlan(context.Context, string, string) error
 }
 
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
-	virtualnetworks network.VirtualNetworksClient
+	virtualnetworks     network.VirtualNetworksClient
+	ddosprotectionplans network.DdosProtectionPlansClient
 }
 
 var _ Client = &AzureClient{}
@@ -44,30 +49,41 @@ var _ Client = &AzureClient{}
 // NewClient creates a new VM client from subscription ID.
 func NewClient(auth azure.Authorizer) *AzureClient {
 	c := newVirtualNetworksClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	d := newDDoSProtectionPlansClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
 	return &AzureClient{
-		virtualnetworks: c,
+		virtualnetworks:     c,
+		ddosprotectionplans: d,
 	}
 }
 
 // newVirtualNetworksClient creates a new vnet client from subscription ID.
 func newVirtualNetworksClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.VirtualNetworksClient {
 	vnetsClient := network.NewVirtualNetworksClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&vnetsClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&vnetsClient.Client, authorizer, "virtualnetworks")
 	return vnetsClient
 }
 
+// newDDoSProtectionPlansClient creates a new DDoS Protection Plan client from subscription ID.
+func newDDoSProtectionPlansClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.DdosProtectionPlansClient {
+	plansClient := network.NewDdosProtectionPlansClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&plansClient.Client, authorizer, "virtualnetworks")
+	return plansClient
+}
+
 // Get gets the specified virtual network by resource group.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vnetName string) (network.VirtualNetwork, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vnetName string) (_ network.VirtualNetwork, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.virtualnetworks.Get(ctx, resourceGroupName, vnetName, "")
 }
 
 // CreateOrUpdate creates or updates a virtual network in the specified resource group.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vnetName string, vn network.VirtualNetwork) error {
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vnetName string, vn network.VirtualNetwork) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.virtualnetworks.CreateOrUpdate(ctx, resourceGroupName, vnetName, vn)
 	if err != nil {
@@ -82,9 +98,10 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vn
 }
 
 // Delete deletes the specified virtual network.
-func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vnetName string) error {
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vnetName string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.virtualnetworks.Delete(ctx, resourceGroupName, vnetName)
 	if err != nil {
@@ -99,9 +116,55 @@ func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vnetName s
 }
 
 // CheckIPAddressAvailability checks whether a private IP address is available for use.
-func (ac *AzureClient) CheckIPAddressAvailability(ctx context.Context, resourceGroupName, vnetName, ip string) (network.IPAddressAvailabilityResult, error) {
+func (ac *AzureClient) CheckIPAddressAvailability(ctx context.Context, resourceGroupName, vnetName, ip string) (_ network.IPAddressAvailabilityResult, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.AzureClient.CheckIPAddressAvailability")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.virtualnetworks.CheckIPAddressAvailability(ctx, resourceGroupName, vnetName, ip)
 }
+
+// GetDDoSProtectionPlan gets the specified DDoS Protection Plan by resource group.
+func (ac *AzureClient) GetDDoSProtectionPlan(ctx context.Context, resourceGroupName, planName string) (_ network.DdosProtectionPlan, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.AzureClient.GetDDoSProtectionPlan")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.ddosprotectionplans.Get(ctx, resourceGroupName, planName)
+}
+
+// CreateOrUpdateDDoSProtectionPlan creates or updates a DDoS Protection Plan in the specified resource group.
+func (ac *AzureClient) CreateOrUpdateDDoSProtectionPlan(ctx context.Context, resourceGroupName, planName string, plan network.DdosProtectionPlan) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.AzureClient.CreateOrUpdateDDoSProtectionPlan")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.ddosprotectionplans.CreateOrUpdate(ctx, resourceGroupName, planName, plan)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.ddosprotectionplans.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.ddosprotectionplans)
+	return err
+}
+
+// DeleteDDoSProtectionPlan deletes the specified DDoS Protection Plan.
+func (ac *AzureClient) DeleteDDoSProtectionPlan(ctx context.Context, resourceGroupName, planName string) (err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.AzureClient.DeleteDDoSProtectionPlan")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	future, err := ac.ddosprotectionplans.Delete(ctx, resourceGroupName, planName)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.ddosprotectionplans.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.ddosprotectionplans)
+	return err
+}
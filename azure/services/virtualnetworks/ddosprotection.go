@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworks
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// reconcileDDoSProtection makes sure the DDoS protection of an existing managed virtual network matches the spec.
+func (s *Service) reconcileDDoSProtection(ctx context.Context, vnetSpec azure.VNetSpec, vnet network.VirtualNetwork) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.Service.reconcileDDoSProtection")
+	defer done()
+
+	if err := s.reconcileDDoSProtectionPlan(ctx, vnetSpec); err != nil {
+		return err
+	}
+
+	if hasDDoSProtection(vnet, vnetSpec.DDoSProtectionPlanID) {
+		return nil
+	}
+
+	log.V(2).Info("updating DDoS protection of VNet", "VNet", vnetSpec.Name, "DDoS protection plan", vnetSpec.DDoSProtectionPlanID)
+	setDDoSProtection(&vnet, vnetSpec.DDoSProtectionPlanID)
+	if err := s.Client.CreateOrUpdate(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, vnet); err != nil {
+		return errors.Wrapf(err, "failed to update DDoS protection of virtual network %s", vnetSpec.Name)
+	}
+	log.V(2).Info("successfully updated DDoS protection of VNet", "VNet", vnetSpec.Name)
+	return nil
+}
+
+// reconcileDDoSProtectionPlan creates the DDoS Protection Plan of the virtual network if it is managed and does not exist.
+func (s *Service) reconcileDDoSProtectionPlan(ctx context.Context, vnetSpec azure.VNetSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.Service.reconcileDDoSProtectionPlan")
+	defer done()
+
+	if vnetSpec.DDoSProtectionPlanName == "" {
+		return nil
+	}
+
+	_, err := s.Client.GetDDoSProtectionPlan(ctx, vnetSpec.ResourceGroup, vnetSpec.DDoSProtectionPlanName)
+	switch {
+	case err == nil:
+		return nil
+	case !azure.ResourceNotFound(err):
+		return errors.Wrapf(err, "failed to get DDoS protection plan %s", vnetSpec.DDoSProtectionPlanName)
+	}
+
+	log.V(2).Info("creating DDoS protection plan", "DDoS protection plan", vnetSpec.DDoSProtectionPlanName)
+	plan := network.DdosProtectionPlan{
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.Scope.ClusterName(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(vnetSpec.DDoSProtectionPlanName),
+			Role:        to.StringPtr(infrav1.CommonRole),
+			Additional:  s.Scope.AdditionalTags(),
+		})),
+		Location: to.StringPtr(s.Scope.Location()),
+	}
+	if err := s.Client.CreateOrUpdateDDoSProtectionPlan(ctx, vnetSpec.ResourceGroup, vnetSpec.DDoSProtectionPlanName, plan); err != nil {
+		return errors.Wrapf(err, "failed to create DDoS protection plan %s", vnetSpec.DDoSProtectionPlanName)
+	}
+	log.V(2).Info("successfully created DDoS protection plan", "DDoS protection plan", vnetSpec.DDoSProtectionPlanName)
+	return nil
+}
+
+// deleteDDoSProtectionPlan deletes the DDoS Protection Plan of the virtual network if it is owned by the cluster.
+func (s *Service) deleteDDoSProtectionPlan(ctx context.Context, vnetSpec azure.VNetSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.Service.deleteDDoSProtectionPlan")
+	defer done()
+
+	if vnetSpec.DDoSProtectionPlanName == "" {
+		return nil
+	}
+
+	plan, err := s.Client.GetDDoSProtectionPlan(ctx, vnetSpec.ResourceGroup, vnetSpec.DDoSProtectionPlanName)
+	if azure.ResourceGroupNotFound(err) || azure.ResourceNotFound(err) {
+		// plan does not exist, there is nothing to delete
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get DDoS protection plan %s", vnetSpec.DDoSProtectionPlanName)
+	}
+
+	if !converters.MapToTags(plan.Tags).HasOwned(s.Scope.ClusterName()) {
+		log.V(4).Info("Skipping deletion of unmanaged DDoS protection plan", "DDoS protection plan", vnetSpec.DDoSProtectionPlanName)
+		return nil
+	}
+
+	log.V(2).Info("deleting DDoS protection plan", "DDoS protection plan", vnetSpec.DDoSProtectionPlanName)
+	err = s.Client.DeleteDDoSProtectionPlan(ctx, vnetSpec.ResourceGroup, vnetSpec.DDoSProtectionPlanName)
+	if err != nil && !azure.ResourceGroupNotFound(err) && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete DDoS protection plan %s in resource group %s", vnetSpec.DDoSProtectionPlanName, vnetSpec.ResourceGroup)
+	}
+
+	log.V(2).Info("successfully deleted DDoS protection plan", "DDoS protection plan", vnetSpec.DDoSProtectionPlanName)
+	return nil
+}
+
+// hasDDoSProtection returns true if the virtual network is protected by the given DDoS Protection Plan, or is not
+// protected at all if the plan ID is empty.
+func hasDDoSProtection(vnet network.VirtualNetwork, planID string) bool {
+	var enabled bool
+	var existingID string
+	if vnet.VirtualNetworkPropertiesFormat != nil {
+		enabled = to.Bool(vnet.EnableDdosProtection)
+		if vnet.DdosProtectionPlan != nil {
+			existingID = to.String(vnet.DdosProtectionPlan.ID)
+		}
+	}
+	return enabled == (planID != "") && strings.EqualFold(existingID, planID)
+}
+
+// setDDoSProtection enables DDoS protection on the virtual network with the given DDoS Protection Plan, or disables it
+// if the plan ID is empty.
+func setDDoSProtection(vnet *network.VirtualNetwork, planID string) {
+	if vnet.VirtualNetworkPropertiesFormat == nil {
+		vnet.VirtualNetworkPropertiesFormat = &network.VirtualNetworkPropertiesFormat{}
+	}
+	vnet.EnableDdosProtection = to.BoolPtr(planID != "")
+	vnet.DdosProtectionPlan = nil
+	if planID != "" {
+		vnet.DdosProtectionPlan = &network.SubResource{ID: to.StringPtr(planID)}
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworks
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks/mock_virtualnetworks"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const fakePlanID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/ddosProtectionPlans/my-plan"
+
+func managedVnet(planID string) network.VirtualNetwork {
+	vnet := network.VirtualNetwork{
+		ID:   to.StringPtr("azure/fake/id"),
+		Name: to.StringPtr("vnet-exists"),
+		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+			AddressSpace: &network.AddressSpace{
+				AddressPrefixes: to.StringSlicePtr([]string{"10.0.0.0/8"}),
+			},
+		},
+		Tags: map[string]*string{
+			"Name": to.StringPtr("vnet-exists"),
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": to.StringPtr("owned"),
+			"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("common"),
+		},
+	}
+	if planID != "" {
+		vnet.EnableDdosProtection = to.BoolPtr(true)
+		vnet.DdosProtectionPlan = &network.SubResource{ID: to.StringPtr(planID)}
+	}
+	return vnet
+}
+
+func TestReconcileVnetDDoSProtection(t *testing.T) {
+	notFound := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder)
+	}{
+		{
+			name:          "vnet created with a new DDoS protection plan",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup:          "my-rg",
+					Name:                   "vnet-new",
+					CIDRs:                  []string{"10.0.0.0/8"},
+					DDoSProtectionPlanID:   fakePlanID,
+					DDoSProtectionPlanName: "my-plan",
+				})
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-new").Return(network.VirtualNetwork{}, notFound)
+				m.GetDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan").Return(network.DdosProtectionPlan{}, notFound)
+				m.CreateOrUpdateDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan", gomock.AssignableToTypeOf(network.DdosProtectionPlan{}))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "vnet-new", gomock.AssignableToTypeOf(network.VirtualNetwork{})).
+					Do(func(_ context.Context, _, _ string, vnet network.VirtualNetwork) {
+						if !hasDDoSProtection(vnet, fakePlanID) {
+							t.Errorf("virtual network should be created with DDoS protection")
+						}
+					})
+			},
+		},
+		{
+			name:          "managed vnet without DDoS protection is updated",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup:        "my-rg",
+					Name:                 "vnet-exists",
+					CIDRs:                []string{"10.0.0.0/8"},
+					DDoSProtectionPlanID: fakePlanID,
+				})
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-exists").Return(managedVnet(""), nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "vnet-exists", gomock.AssignableToTypeOf(network.VirtualNetwork{})).
+					Do(func(_ context.Context, _, _ string, vnet network.VirtualNetwork) {
+						if !hasDDoSProtection(vnet, fakePlanID) {
+							t.Errorf("virtual network should be updated with DDoS protection")
+						}
+					})
+			},
+		},
+		{
+			name:          "DDoS protection plan changed out of band is restored",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup:          "my-rg",
+					Name:                   "vnet-exists",
+					CIDRs:                  []string{"10.0.0.0/8"},
+					DDoSProtectionPlanID:   fakePlanID,
+					DDoSProtectionPlanName: "my-plan",
+				})
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-exists").Return(managedVnet("/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/ddosProtectionPlans/other-plan"), nil)
+				m.GetDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan")
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "vnet-exists", gomock.AssignableToTypeOf(network.VirtualNetwork{}))
+			},
+		},
+		{
+			name:          "managed vnet with expected DDoS protection is not updated",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup:        "my-rg",
+					Name:                 "vnet-exists",
+					CIDRs:                []string{"10.0.0.0/8"},
+					DDoSProtectionPlanID: fakePlanID,
+				})
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-exists").Return(managedVnet(fakePlanID), nil)
+			},
+		},
+		{
+			name:          "DDoS protection is removed from managed vnet",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup: "my-rg",
+					Name:          "vnet-exists",
+					CIDRs:         []string{"10.0.0.0/8"},
+				})
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-exists").Return(managedVnet(fakePlanID), nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "vnet-exists", gomock.AssignableToTypeOf(network.VirtualNetwork{})).
+					Do(func(_ context.Context, _, _ string, vnet network.VirtualNetwork) {
+						if !hasDDoSProtection(vnet, "") {
+							t.Errorf("virtual network should be updated without DDoS protection")
+						}
+					})
+			},
+		},
+		{
+			name:          "fail to create DDoS protection plan",
+			expectedError: "failed to create DDoS protection plan my-plan: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup:          "my-rg",
+					Name:                   "vnet-new",
+					CIDRs:                  []string{"10.0.0.0/8"},
+					DDoSProtectionPlanID:   fakePlanID,
+					DDoSProtectionPlanName: "my-plan",
+				})
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-new").Return(network.VirtualNetwork{}, notFound)
+				m.GetDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan").Return(network.DdosProtectionPlan{}, notFound)
+				m.CreateOrUpdateDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan", gomock.AssignableToTypeOf(network.DdosProtectionPlan{})).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_virtualnetworks.NewMockVNetScope(mockCtrl)
+			clientMock := mock_virtualnetworks.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("fake-cluster")
+			scopeMock.EXPECT().Location().AnyTimes().Return("fake-location")
+			scopeMock.EXPECT().AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			scopeMock.EXPECT().Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "vnet-exists"})
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteVnetDDoSProtectionPlan(t *testing.T) {
+	notFound := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+	vnetSpec := azure.VNetSpec{
+		ResourceGroup:          "my-rg",
+		Name:                   "vnet-exists",
+		CIDRs:                  []string{"10.0.0.0/8"},
+		DDoSProtectionPlanID:   fakePlanID,
+		DDoSProtectionPlanName: "my-plan",
+	}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder)
+	}{
+		{
+			name:          "managed DDoS protection plan is deleted after the vnet",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(vnetSpec)
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "vnet-exists").Return(managedVnet(fakePlanID), nil),
+					m.Delete(gomockinternal.AContext(), "my-rg", "vnet-exists"),
+					m.GetDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan").Return(network.DdosProtectionPlan{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": to.StringPtr("owned"),
+						},
+					}, nil),
+					m.DeleteDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan"),
+				)
+			},
+		},
+		{
+			name:          "DDoS protection plan is deleted if the vnet is already deleted",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(vnetSpec)
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-exists").Return(network.VirtualNetwork{}, notFound)
+				m.GetDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan").Return(network.DdosProtectionPlan{
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": to.StringPtr("owned"),
+					},
+				}, nil)
+				m.DeleteDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan")
+			},
+		},
+		{
+			name:          "unmanaged DDoS protection plan is not deleted",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(vnetSpec)
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-exists").Return(network.VirtualNetwork{}, notFound)
+				m.GetDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan").Return(network.DdosProtectionPlan{}, nil)
+			},
+		},
+		{
+			name:          "fail to delete DDoS protection plan",
+			expectedError: "failed to delete DDoS protection plan my-plan in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(vnetSpec)
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-exists").Return(network.VirtualNetwork{}, notFound)
+				m.GetDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan").Return(network.DdosProtectionPlan{
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": to.StringPtr("owned"),
+					},
+				}, nil)
+				m.DeleteDDoSProtectionPlan(gomockinternal.AContext(), "my-rg", "my-plan").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_virtualnetworks.NewMockVNetScope(mockCtrl)
+			clientMock := mock_virtualnetworks.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("fake-cluster")
+			scopeMock.EXPECT().Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "vnet-exists"})
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -80,6 +80,20 @@ func (mr *MockClientMockRecorder) CreateOrUpdate(arg0, arg1, arg2, arg3 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdate", reflect.TypeOf((*MockClient)(nil).CreateOrUpdate), arg0, arg1, arg2, arg3)
 }
 
+// CreateOrUpdateDDoSProtectionPlan mocks base method.
+func (m *MockClient) CreateOrUpdateDDoSProtectionPlan(arg0 context.Context, arg1, arg2 string, arg3 network.DdosProtectionPlan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateDDoSProtectionPlan", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrUpdateDDoSProtectionPlan indicates an expected call of CreateOrUpdateDDoSProtectionPlan.
+func (mr *MockClientMockRecorder) CreateOrUpdateDDoSProtectionPlan(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateDDoSProtectionPlan", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateDDoSProtectionPlan), arg0, arg1, arg2, arg3)
+}
+
 // Delete mocks base method.
 func (m *MockClient) Delete(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -94,6 +108,20 @@ func (mr *MockClientMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), arg0, arg1, arg2)
 }
 
+// DeleteDDoSProtectionPlan mocks base method.
+func (m *MockClient) DeleteDDoSProtectionPlan(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDDoSProtectionPlan", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDDoSProtectionPlan indicates an expected call of DeleteDDoSProtectionPlan.
+func (mr *MockClientMockRecorder) DeleteDDoSProtectionPlan(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDDoSProtectionPlan", reflect.TypeOf((*MockClient)(nil).DeleteDDoSProtectionPlan), arg0, arg1, arg2)
+}
+
 // Get mocks base method.
 func (m *MockClient) Get(arg0 context.Context, arg1, arg2 string) (network.VirtualNetwork, error) {
 	m.ctrl.T.Helper()
@@ -108,3 +136,18 @@ func (mr *MockClientMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), arg0, arg1, arg2)
 }
+
+// GetDDoSProtectionPlan mocks base method.
+func (m *MockClient) GetDDoSProtectionPlan(arg0 context.Context, arg1, arg2 string) (network.DdosProtectionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDDoSProtectionPlan", arg0, arg1, arg2)
+	ret0, _ := ret[0].(network.DdosProtectionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDDoSProtectionPlan indicates an expected call of GetDDoSProtectionPlan.
+func (mr *MockClientMockRecorder) GetDDoSProtectionPlan(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDDoSProtectionPlan", reflect.TypeOf((*MockClient)(nil).GetDDoSProtectionPlan), arg0, arg1, arg2)
+}
@@ -64,38 +64,30 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	//    * Node NSG
 	//    * Node Route Table
 	vnetSpec := s.Scope.VNetSpec()
-	existingVnet, err := s.getExisting(ctx, vnetSpec)
+	vnet, err := s.getExisting(ctx, vnetSpec)
 
 	switch {
 	case err != nil && !azure.ResourceNotFound(err):
 		return errors.Wrapf(err, "failed to get VNet %s", vnetSpec.Name)
 
 	case err == nil:
-		// vnet already exists, cannot update since it's immutable
-		if !existingVnet.IsManaged(s.Scope.ClusterName()) {
+		// vnet already exists, only its DDoS protection is kept in sync with the spec
+		existingVnet := converters.SDKToVNetSummary(vnet, vnetSpec.ResourceGroup)
+		if !existingVnet.Tags.HasOwned(s.Scope.ClusterName()) {
 			log.V(2).Info("Working on custom VNet", "vnet-id", existingVnet.ID)
+		} else if err := s.reconcileDDoSProtection(ctx, vnetSpec, vnet); err != nil {
+			return err
 		}
-		existingVnet.DeepCopyInto(s.Scope.Vnet())
+		s.updateVnet(existingVnet)
 
 	default:
-		log.V(2).Info("creating VNet", "VNet", vnetSpec.Name)
-
-		vnetProperties := network.VirtualNetwork{
-			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
-				ClusterName: s.Scope.ClusterName(),
-				Lifecycle:   infrav1.ResourceLifecycleOwned,
-				Name:        to.StringPtr(vnetSpec.Name),
-				Role:        to.StringPtr(infrav1.CommonRole),
-				Additional:  s.Scope.AdditionalTags(),
-			})),
-			Location: to.StringPtr(s.Scope.Location()),
-			VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
-				AddressSpace: &network.AddressSpace{
-					AddressPrefixes: &vnetSpec.CIDRs,
-				},
-			},
+		if err := s.reconcileDDoSProtectionPlan(ctx, vnetSpec); err != nil {
+			return err
 		}
 
+		log.V(2).Info("creating VNet", "VNet", vnetSpec.Name)
+
+		vnetProperties := Parameters(vnetSpec, s.Scope.ClusterName(), s.Scope.Location(), s.Scope.AdditionalTags())
 		err = s.Client.CreateOrUpdate(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, vnetProperties)
 
 		if err != nil {
@@ -107,19 +99,45 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// Parameters returns the parameters of a new virtual network owned by the cluster.
+func Parameters(vnetSpec azure.VNetSpec, clusterName, location string, additionalTags infrav1.Tags) network.VirtualNetwork {
+	vnet := network.VirtualNetwork{
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: clusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(vnetSpec.Name),
+			Role:        to.StringPtr(infrav1.CommonRole),
+			Additional:  additionalTags,
+		})),
+		Location: to.StringPtr(location),
+		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+			AddressSpace: &network.AddressSpace{
+				AddressPrefixes: &vnetSpec.CIDRs,
+			},
+		},
+	}
+	if vnetSpec.DDoSProtectionPlanID != "" {
+		setDDoSProtection(&vnet, vnetSpec.DDoSProtectionPlanID)
+	}
+	return vnet
+}
+
 // Delete deletes the virtual network with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.Service.Delete")
 	defer done()
 
 	vnetSpec := s.Scope.VNetSpec()
-	existingVnet, err := s.getExisting(ctx, vnetSpec)
+	vnet, err := s.getExisting(ctx, vnetSpec)
 	if azure.ResourceNotFound(err) {
-		// vnet does not exist, there is nothing to delete
-		return nil
+		// vnet does not exist, only its DDoS Protection Plan may be left
+		return s.deleteDDoSProtectionPlan(ctx, vnetSpec)
+	}
+	if err != nil {
+		return err
 	}
 
-	if !existingVnet.IsManaged(s.Scope.ClusterName()) {
+	if !converters.MapToTags(vnet.Tags).HasOwned(s.Scope.ClusterName()) {
 		log.V(4).Info("Skipping VNet deletion in custom vnet mode")
 		return nil
 	}
@@ -136,11 +154,11 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 
 	log.V(2).Info("successfully deleted VNet", "VNet", vnetSpec.Name)
-	return nil
+	return s.deleteDDoSProtectionPlan(ctx, vnetSpec)
 }
 
-// getExisting provides information about an existing virtual network.
-func (s *Service) getExisting(ctx context.Context, spec azure.VNetSpec) (*infrav1.VnetSpec, error) {
+// getExisting gets an existing virtual network.
+func (s *Service) getExisting(ctx context.Context, spec azure.VNetSpec) (network.VirtualNetwork, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.Service.getExisting")
 	defer done()
 
@@ -148,21 +166,33 @@ func (s *Service) getExisting(ctx context.Context, spec azure.VNetSpec) (*infrav
 	if err != nil {
 		if azure.ResourceNotFound(err) {
 			log.V(2).Info(fmt.Sprintf("Resource not found for VNet %q from resource group %q", spec.Name, spec.ResourceGroup))
-			return nil, err
+			return vnet, err
 		}
-		return nil, errors.Wrapf(err, "failed to get VNet %s", spec.Name)
+		return vnet, errors.Wrapf(err, "failed to get VNet %s", spec.Name)
 	}
-	var prefixes []string
-	if vnet.VirtualNetworkPropertiesFormat != nil && vnet.VirtualNetworkPropertiesFormat.AddressSpace != nil {
-		prefixes = to.StringSlice(vnet.VirtualNetworkPropertiesFormat.AddressSpace.AddressPrefixes)
+	return vnet, nil
+}
+
+// Describe returns a summary of the existing virtual network.
+func (s *Service) Describe(ctx context.Context) (*azure.VNetSummary, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.Service.Describe")
+	defer done()
+
+	vnetSpec := s.Scope.VNetSpec()
+	vnet, err := s.getExisting(ctx, vnetSpec)
+	if err != nil {
+		return nil, err
 	}
+	summary := converters.SDKToVNetSummary(vnet, vnetSpec.ResourceGroup)
+	return &summary, nil
+}
 
-	return &infrav1.VnetSpec{
-		ResourceGroup: spec.ResourceGroup,
-		ID:            to.String(vnet.ID),
-		Name:          to.String(vnet.Name),
-		CIDRBlocks:    prefixes,
-		Peerings:      s.Scope.Vnet().Peerings,
-		Tags:          converters.MapToTags(vnet.Tags),
-	}, nil
+// updateVnet updates the virtual network of the scope with the state of the existing virtual network.
+func (s *Service) updateVnet(existing azure.VNetSummary) {
+	vnet := s.Scope.Vnet()
+	vnet.ResourceGroup = existing.ResourceGroup
+	vnet.ID = existing.ID
+	vnet.Name = existing.Name
+	vnet.CIDRBlocks = existing.CIDRs
+	vnet.Tags = existing.Tags
 }
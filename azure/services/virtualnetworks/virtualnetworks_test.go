@@ -415,3 +415,102 @@ func TestDeleteVnet(t *testing.T) {
 		})
 	}
 }
+
+func TestDescribeVnet(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expected      *azure.VNetSummary
+		expect        func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder)
+	}{
+		{
+			name:          "vnet exists",
+			expectedError: "",
+			expected: &azure.VNetSummary{
+				ID:            "azure/fake/id",
+				Name:          "vnet-exists",
+				ResourceGroup: "my-rg",
+				CIDRs:         []string{"10.0.0.0/8"},
+				Tags: infrav1.Tags{
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+				},
+				ProvisioningState: infrav1.Succeeded,
+			},
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup: "my-rg",
+					Name:          "vnet-exists",
+					CIDRs:         []string{"10.0.0.0/8"},
+				})
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-exists").
+					Return(network.VirtualNetwork{
+						ID:   to.StringPtr("azure/fake/id"),
+						Name: to.StringPtr("vnet-exists"),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							ProvisioningState: network.ProvisioningStateSucceeded,
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: to.StringSlicePtr([]string{"10.0.0.0/8"}),
+							},
+						},
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": to.StringPtr("owned"),
+						},
+					}, nil)
+			},
+		},
+		{
+			name:          "vnet not found",
+			expectedError: "#: Not found: StatusCode=404",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup: "my-rg",
+					Name:          "vnet-new",
+					CIDRs:         []string{"10.0.0.0/8"},
+				})
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-new").
+					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "failed to get vnet",
+			expectedError: "failed to get VNet vnet-exists: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup: "my-rg",
+					Name:          "vnet-exists",
+					CIDRs:         []string{"10.0.0.0/8"},
+				})
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-exists").
+					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_virtualnetworks.NewMockVNetScope(mockCtrl)
+			clientMock := mock_virtualnetworks.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			summary, err := s.Describe(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(summary).To(Equal(tc.expected))
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -48,31 +49,34 @@ func newClient(auth azure.Authorizer) *azureClient {
 // newVirtualMachineExtensionsClient creates a new vm extension client from subscription ID.
 func newVirtualMachineExtensionsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineExtensionsClient {
 	vmextensionsClient := compute.NewVirtualMachineExtensionsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&vmextensionsClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&vmextensionsClient.Client, authorizer, "vmextensions")
 	return vmextensionsClient
 }
 
 // Get the virtual machine extension.
-func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmName, name string) (compute.VirtualMachineExtension, error) {
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmName, name string) (_ compute.VirtualMachineExtension, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmextensions.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.vmextensions.Get(ctx, resourceGroupName, vmName, name, "")
 }
 
 // CreateOrUpdateAsync creates or updates the virtual machine extension.
-func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vmName, name string, parameters compute.VirtualMachineExtension) error {
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vmName, name string, parameters compute.VirtualMachineExtension) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmextensions.AzureClient.CreateOrUpdate")
 	defer done()
+	defer azureerrors.Classify(&err)
 
-	_, err := ac.vmextensions.CreateOrUpdate(ctx, resourceGroupName, vmName, name, parameters)
+	_, err = ac.vmextensions.CreateOrUpdate(ctx, resourceGroupName, vmName, name, parameters)
 	return err
 }
 
 // Delete removes the virtual machine extension.
-func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, vmName, name string) error {
+func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, vmName, name string) (err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmextensions.AzureClient.Delete")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.vmextensions.Delete(ctx, resourceGroupName, vmName, name)
 	if err != nil {
@@ -37,6 +37,9 @@ type VMExtensionScope interface {
 type Service struct {
 	Scope VMExtensionScope
 	client
+	// provisioningStates are the provisioning states of the existing extensions fetched by Reconcile, by name, so that
+	// VerifyBootstrap doesn't fetch them again.
+	provisioningStates map[string]string
 }
 
 // New creates a new vm extension service.
@@ -47,18 +50,16 @@ func New(scope VMExtensionScope) *Service {
 	}
 }
 
-// Reconcile creates or updates the VM extension.
+// Reconcile creates the VM extensions which don't exist yet, and records the provisioning state of the existing ones.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "vmextensions.Service.Reconcile")
 	defer done()
 
+	s.provisioningStates = map[string]string{}
 	for _, extensionSpec := range s.Scope.VMExtensionSpecs() {
 		if existing, err := s.client.Get(ctx, s.Scope.ResourceGroup(), extensionSpec.VMName, extensionSpec.Name); err == nil {
-			// check the extension status and set the associated conditions.
-			if retErr := s.Scope.SetBootstrapConditions(ctx, to.String(existing.ProvisioningState), extensionSpec.Name); retErr != nil {
-				return retErr
-			}
 			// if the extension already exists, do not update it.
+			s.provisioningStates[extensionSpec.Name] = to.String(existing.ProvisioningState)
 			continue
 		} else if !azure.ResourceNotFound(err) {
 			return errors.Wrapf(err, "failed to get vm extension %s on vm %s", extensionSpec.Name, extensionSpec.VMName)
@@ -89,6 +90,29 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// VerifyBootstrap sets the bootstrap conditions from the provisioning states of the VM extensions, returning an error
+// until the bootstrap data ran. The states fetched by Reconcile are used, and the other extensions are fetched.
+func (s *Service) VerifyBootstrap(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmextensions.Service.VerifyBootstrap")
+	defer done()
+
+	for _, extensionSpec := range s.Scope.VMExtensionSpecs() {
+		provisioningState, ok := s.provisioningStates[extensionSpec.Name]
+		if !ok {
+			existing, err := s.client.Get(ctx, s.Scope.ResourceGroup(), extensionSpec.VMName, extensionSpec.Name)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get vm extension %s on vm %s", extensionSpec.Name, extensionSpec.VMName)
+			}
+			provisioningState = to.String(existing.ProvisioningState)
+		}
+		// check the extension status and set the associated conditions.
+		if err := s.Scope.SetBootstrapConditions(ctx, provisioningState, extensionSpec.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Delete is a no-op. Extensions will be deleted as part of VM deletion.
 func (s *Service) Delete(_ context.Context) error {
 	return nil
@@ -18,6 +18,7 @@ package vmextensions
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -60,7 +61,6 @@ func TestReconcileVMExtension(t *testing.T) {
 					ID:   to.StringPtr("fake/id"),
 					Name: to.StringPtr("my-extension-1"),
 				}, nil)
-				s.SetBootstrapConditions(gomockinternal.AContext(), string(compute.ProvisioningStateSucceeded), "my-extension-1")
 			},
 		},
 		{
@@ -86,7 +86,6 @@ func TestReconcileVMExtension(t *testing.T) {
 					ID:   to.StringPtr("fake/id"),
 					Name: to.StringPtr("my-extension-1"),
 				}, nil)
-				s.SetBootstrapConditions(gomockinternal.AContext(), string(compute.ProvisioningStateFailed), "my-extension-1")
 			},
 		},
 		{
@@ -112,7 +111,6 @@ func TestReconcileVMExtension(t *testing.T) {
 					ID:   to.StringPtr("fake/id"),
 					Name: to.StringPtr("my-extension-1"),
 				}, nil)
-				s.SetBootstrapConditions(gomockinternal.AContext(), string(compute.ProvisioningStateCreating), "my-extension-1")
 			},
 		},
 		{
@@ -222,3 +220,105 @@ func TestReconcileVMExtension(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyBootstrap(t *testing.T) {
+	extensionSpecs := []azure.ExtensionSpec{
+		{
+			Name:      "my-extension-1",
+			VMName:    "my-vm",
+			Publisher: "some-publisher",
+			Version:   "1.0",
+		},
+	}
+	existingExtension := func(provisioningState compute.ProvisioningState) compute.VirtualMachineExtension {
+		return compute.VirtualMachineExtension{
+			VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+				Publisher:         to.StringPtr("some-publisher"),
+				Type:              to.StringPtr("my-extension-1"),
+				ProvisioningState: to.StringPtr(string(provisioningState)),
+			},
+			ID:   to.StringPtr("fake/id"),
+			Name: to.StringPtr("my-extension-1"),
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		reconcile     bool
+		expectedError string
+		expect        func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder)
+	}{
+		{
+			name:          "provisioning state fetched by reconcile is not fetched again",
+			reconcile:     true,
+			expectedError: "",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.VMExtensionSpecs().AnyTimes().Return(extensionSpecs)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").Times(1).Return(existingExtension(compute.ProvisioningStateSucceeded), nil)
+				s.SetBootstrapConditions(gomockinternal.AContext(), string(compute.ProvisioningStateSucceeded), "my-extension-1")
+			},
+		},
+		{
+			name:          "provisioning state is fetched when the extensions were not reconciled",
+			expectedError: "",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.VMExtensionSpecs().AnyTimes().Return(extensionSpecs)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").Return(existingExtension(compute.ProvisioningStateSucceeded), nil)
+				s.SetBootstrapConditions(gomockinternal.AContext(), string(compute.ProvisioningStateSucceeded), "my-extension-1")
+			},
+		},
+		{
+			name:          "bootstrap still running",
+			expectedError: "extension is still in provisioning state",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.VMExtensionSpecs().AnyTimes().Return(extensionSpecs)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").Return(existingExtension(compute.ProvisioningStateCreating), nil)
+				s.SetBootstrapConditions(gomockinternal.AContext(), string(compute.ProvisioningStateCreating), "my-extension-1").Return(errors.New("extension is still in provisioning state"))
+			},
+		},
+		{
+			name:          "error getting the extension",
+			expectedError: "failed to get vm extension my-extension-1 on vm my-vm: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.VMExtensionSpecs().AnyTimes().Return(extensionSpecs)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").
+					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_vmextensions.NewMockVMExtensionScope(mockCtrl)
+			clientMock := mock_vmextensions.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			if tc.reconcile {
+				g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+			}
+			err := s.VerifyBootstrap(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -46,14 +47,15 @@ func newClient(auth azure.Authorizer) *azureClient {
 // newVirtualMachineScaleSetExtensionsClient creates a new vmss extension client from subscription ID.
 func newVirtualMachineScaleSetExtensionsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineScaleSetExtensionsClient {
 	vmssextensionsClient := compute.NewVirtualMachineScaleSetExtensionsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&vmssextensionsClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&vmssextensionsClient.Client, authorizer, "vmssextensions")
 	return vmssextensionsClient
 }
 
 // Get creates or updates the virtual machine scale set extension.
-func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmssName, name string) (compute.VirtualMachineScaleSetExtension, error) {
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmssName, name string) (_ compute.VirtualMachineScaleSetExtension, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmssextensions.AzureClient.Get")
 	defer done()
+	defer azureerrors.Classify(&err)
 
 	return ac.vmssextensions.Get(ctx, resourceGroupName, vmssName, name, "")
 }
@@ -27,6 +27,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -56,14 +57,15 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newPeeringsClient creates a new virtual network peerings client from subscription ID.
 func newPeeringsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.VirtualNetworkPeeringsClient {
 	peeringsClient := network.NewVirtualNetworkPeeringsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&peeringsClient.Client, authorizer)
+	azure.SetAutoRestClientDefaults(&peeringsClient.Client, authorizer, serviceName)
 	return peeringsClient
 }
 
 // Get gets the specified virtual network peering by the peering name, virtual network, and resource group.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vnetName, peeringName string) (network.VirtualNetworkPeering, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vnetName, peeringName string) (_ network.VirtualNetworkPeering, err error) {
 	ctx, span := tele.Tracer().Start(ctx, "vnetpeerings.AzureClient.Get")
 	defer span.End()
+	defer azureerrors.Classify(&err)
 
 	return ac.peerings.Get(ctx, resourceGroupName, vnetName, peeringName)
 }
@@ -71,9 +73,10 @@ func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vnetName, pee
 // CreateOrUpdateAsync creates or updates a virtual network peering asynchronously.
 // It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
 // progress of the operation.
-func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (interface{}, azureautorest.FutureAPI, error) {
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ interface{}, _ azureautorest.FutureAPI, err error) {
 	ctx, span := tele.Tracer().Start(ctx, "vnetpeerings.AzureClient.CreateOrUpdateAsync")
 	defer span.End()
+	defer azureerrors.Classify(&err)
 
 	var existingPeering interface{}
 
@@ -120,9 +123,10 @@ func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 // DeleteAsync deletes a virtual network peering asynchronously. DeleteAsync sends a DELETE
 // request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
 // progress of the operation.
-func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (azureautorest.FutureAPI, error) {
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (_ azureautorest.FutureAPI, err error) {
 	ctx, span := tele.Tracer().Start(ctx, "vnetpeerings.AzureClient.Delete")
 	defer span.End()
+	defer azureerrors.Classify(&err)
 
 	future, err := ac.peerings.Delete(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName())
 	if err != nil {
@@ -144,9 +148,10 @@ func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 }
 
 // IsDone returns true if the long-running operation has completed.
-func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (bool, error) {
+func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (_ bool, err error) {
 	ctx, span := tele.Tracer().Start(ctx, "vnetpeerings.AzureClient.IsDone")
 	defer span.End()
+	defer azureerrors.Classify(&err)
 
 	done, err := future.DoneWithContext(ctx, ac.peerings)
 	if err != nil {
@@ -157,7 +162,9 @@ func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAP
 }
 
 // Result fetches the result of a long-running operation future.
-func (ac *AzureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (interface{}, error) {
+func (ac *AzureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (_ interface{}, err error) {
+	defer azureerrors.Classify(&err)
+
 	if futureData == nil {
 		return nil, errors.Errorf("cannot get result from nil future")
 	}
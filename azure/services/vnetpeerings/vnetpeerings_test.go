@@ -29,6 +29,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings/mock_vnetpeerings"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/golden"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
@@ -380,3 +381,11 @@ func TestDeleteVnetPeerings(t *testing.T) {
 		})
 	}
 }
+
+func TestVnetPeeringPayload(t *testing.T) {
+	g := NewWithT(t)
+
+	result, err := fakePeering1To2.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	golden.Assert(t, "vnetpeering", result)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zoneoutages
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/resourcehealth/mgmt/2017-07-01/resourcehealth"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	ListAvailabilityStatuses(context.Context, string) ([]resourcehealth.AvailabilityStatus, error)
+	ListVirtualMachineZones(context.Context, string) (map[string]string, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	availabilityStatuses resourcehealth.AvailabilityStatusesClient
+	virtualmachines      compute.VirtualMachinesClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new zone outages client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	return &azureClient{
+		availabilityStatuses: newAvailabilityStatusesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		virtualmachines:      newVirtualMachinesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newAvailabilityStatusesClient creates a new Resource Health availability statuses client from subscription ID.
+func newAvailabilityStatusesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resourcehealth.AvailabilityStatusesClient {
+	c := resourcehealth.NewAvailabilityStatusesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer, "zoneoutages")
+	return c
+}
+
+// newVirtualMachinesClient creates a new VM client from subscription ID.
+func newVirtualMachinesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachinesClient {
+	c := compute.NewVirtualMachinesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer, "zoneoutages")
+	return c
+}
+
+// ListAvailabilityStatuses returns the current availability statuses of the resources of a resource group, with the
+// service health events impacting them.
+func (ac *azureClient) ListAvailabilityStatuses(ctx context.Context, resourceGroupName string) (_ []resourcehealth.AvailabilityStatus, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "zoneoutages.AzureClient.ListAvailabilityStatuses")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	iter, err := ac.availabilityStatuses.ListByResourceGroupComplete(ctx, resourceGroupName, "", "")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list availability statuses")
+	}
+
+	var statuses []resourcehealth.AvailabilityStatus
+	for iter.NotDone() {
+		statuses = append(statuses, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return statuses, errors.Wrap(err, "could not iterate availability statuses")
+		}
+	}
+
+	return statuses, nil
+}
+
+// ListVirtualMachineZones returns the availability zones of the zonal VMs of a resource group, keyed by the lower case
+// ID of the VM.
+func (ac *azureClient) ListVirtualMachineZones(ctx context.Context, resourceGroupName string) (_ map[string]string, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "zoneoutages.AzureClient.ListVirtualMachineZones")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	iter, err := ac.virtualmachines.ListComplete(ctx, resourceGroupName)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list virtual machines")
+	}
+
+	zones := make(map[string]string)
+	for iter.NotDone() {
+		vm := iter.Value()
+		if vm.ID != nil && vm.Zones != nil && len(*vm.Zones) > 0 {
+			zones[strings.ToLower(*vm.ID)] = (*vm.Zones)[0]
+		}
+		if err := iter.NextWithContext(ctx); err != nil {
+			return zones, errors.Wrap(err, "could not iterate virtual machines")
+		}
+	}
+
+	return zones, nil
+}
@@ -0,0 +1,248 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../zoneoutages.go
+
+// Package mock_zoneoutages is a generated GoMock package.
+package mock_zoneoutages
+
+import (
+	context "context"
+	reflect "reflect"
+
+	autorest "github.com/Azure/go-autorest/autorest"
+	gomock "github.com/golang/mock/gomock"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// MockZoneOutageScope is a mock of ZoneOutageScope interface.
+type MockZoneOutageScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockZoneOutageScopeMockRecorder
+}
+
+// MockZoneOutageScopeMockRecorder is the mock recorder for MockZoneOutageScope.
+type MockZoneOutageScopeMockRecorder struct {
+	mock *MockZoneOutageScope
+}
+
+// NewMockZoneOutageScope creates a new mock instance.
+func NewMockZoneOutageScope(ctrl *gomock.Controller) *MockZoneOutageScope {
+	mock := &MockZoneOutageScope{ctrl: ctrl}
+	mock.recorder = &MockZoneOutageScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockZoneOutageScope) EXPECT() *MockZoneOutageScopeMockRecorder {
+	return m.recorder
+}
+
+// Authorizer mocks base method.
+func (m *MockZoneOutageScope) Authorizer() autorest.Authorizer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorizer")
+	ret0, _ := ret[0].(autorest.Authorizer)
+	return ret0
+}
+
+// Authorizer indicates an expected call of Authorizer.
+func (mr *MockZoneOutageScopeMockRecorder) Authorizer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockZoneOutageScope)(nil).Authorizer))
+}
+
+// BaseURI mocks base method.
+func (m *MockZoneOutageScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockZoneOutageScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockZoneOutageScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockZoneOutageScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockZoneOutageScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockZoneOutageScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockZoneOutageScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockZoneOutageScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockZoneOutageScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockZoneOutageScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockZoneOutageScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockZoneOutageScope)(nil).CloudEnvironment))
+}
+
+// CordonImpactedZones mocks base method.
+func (m *MockZoneOutageScope) CordonImpactedZones() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CordonImpactedZones")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CordonImpactedZones indicates an expected call of CordonImpactedZones.
+func (mr *MockZoneOutageScopeMockRecorder) CordonImpactedZones() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CordonImpactedZones", reflect.TypeOf((*MockZoneOutageScope)(nil).CordonImpactedZones))
+}
+
+// CordonedZones mocks base method.
+func (m *MockZoneOutageScope) CordonedZones() []v1beta1.CordonedZone {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CordonedZones")
+	ret0, _ := ret[0].([]v1beta1.CordonedZone)
+	return ret0
+}
+
+// CordonedZones indicates an expected call of CordonedZones.
+func (mr *MockZoneOutageScopeMockRecorder) CordonedZones() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CordonedZones", reflect.TypeOf((*MockZoneOutageScope)(nil).CordonedZones))
+}
+
+// FailureDomains mocks base method.
+func (m *MockZoneOutageScope) FailureDomains() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailureDomains")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// FailureDomains indicates an expected call of FailureDomains.
+func (mr *MockZoneOutageScopeMockRecorder) FailureDomains() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockZoneOutageScope)(nil).FailureDomains))
+}
+
+// HashKey mocks base method.
+func (m *MockZoneOutageScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockZoneOutageScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockZoneOutageScope)(nil).HashKey))
+}
+
+// ResourceGroup mocks base method.
+func (m *MockZoneOutageScope) ResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroup indicates an expected call of ResourceGroup.
+func (mr *MockZoneOutageScopeMockRecorder) ResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockZoneOutageScope)(nil).ResourceGroup))
+}
+
+// SetCordonedZones mocks base method.
+func (m *MockZoneOutageScope) SetCordonedZones(arg0 []v1beta1.CordonedZone) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetCordonedZones", arg0)
+}
+
+// SetCordonedZones indicates an expected call of SetCordonedZones.
+func (mr *MockZoneOutageScopeMockRecorder) SetCordonedZones(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCordonedZones", reflect.TypeOf((*MockZoneOutageScope)(nil).SetCordonedZones), arg0)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockZoneOutageScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockZoneOutageScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockZoneOutageScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockZoneOutageScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockZoneOutageScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockZoneOutageScope)(nil).TenantID))
+}
+
+// ZonalMachineDeployments mocks base method.
+func (m *MockZoneOutageScope) ZonalMachineDeployments(arg0 context.Context) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZonalMachineDeployments", arg0)
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZonalMachineDeployments indicates an expected call of ZonalMachineDeployments.
+func (mr *MockZoneOutageScopeMockRecorder) ZonalMachineDeployments(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZonalMachineDeployments", reflect.TypeOf((*MockZoneOutageScope)(nil).ZonalMachineDeployments), arg0)
+}
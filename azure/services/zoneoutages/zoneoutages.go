@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zoneoutages
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/resourcehealth/mgmt/2017-07-01/resourcehealth"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// availabilityStatusSuffix is the suffix of the ID of the current availability status of a resource, appended to the
+// ID of the resource.
+const availabilityStatusSuffix = "/providers/microsoft.resourcehealth/availabilitystatuses/current"
+
+// ZoneOutageScope defines the scope interface for a zone outages service.
+type ZoneOutageScope interface {
+	azure.Authorizer
+	ResourceGroup() string
+	FailureDomains() []string
+	CordonImpactedZones() bool
+	CordonedZones() []infrav1.CordonedZone
+	SetCordonedZones([]infrav1.CordonedZone)
+	ZonalMachineDeployments(context.Context) (map[string][]string, error)
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope ZoneOutageScope
+	client
+}
+
+// New creates a new zone outages service.
+func New(scope ZoneOutageScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile cordons the availability zones of the cluster whose VMs are unavailable because of an active Azure service
+// health event, and uncordons them once none of their VMs is impacted anymore. An event impacting every zone of the
+// cluster is a regional outage: no zone is cordoned, as new machines couldn't be placed anywhere.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "zoneoutages.Service.Reconcile")
+	defer done()
+
+	if !s.Scope.CordonImpactedZones() {
+		s.Scope.SetCordonedZones(nil)
+		return nil
+	}
+
+	statuses, err := s.client.ListAvailabilityStatuses(ctx, s.Scope.ResourceGroup())
+	if azure.ResourceNotFound(err) {
+		s.Scope.SetCordonedZones(nil)
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to list availability statuses in resource group %s", s.Scope.ResourceGroup())
+	}
+	vmZones, err := s.client.ListVirtualMachineZones(ctx, s.Scope.ResourceGroup())
+	if err != nil {
+		return errors.Wrapf(err, "failed to list virtual machines in resource group %s", s.Scope.ResourceGroup())
+	}
+
+	impacted := impactedZones(statuses, vmZones)
+	if len(impacted) > 0 && len(impacted) >= len(s.Scope.FailureDomains()) {
+		log.Info("Service health event impacts every availability zone, not cordoning them", "zones", len(impacted))
+		impacted = nil
+	}
+
+	previous := make(map[string]infrav1.CordonedZone)
+	for _, zone := range s.Scope.CordonedZones() {
+		previous[zone.Zone] = zone
+	}
+	var cordoned []infrav1.CordonedZone
+	for zone, event := range impacted {
+		cordonedZone := infrav1.CordonedZone{
+			Zone:    zone,
+			EventID: to.String(event.CorrelationID),
+			Since:   metav1.Now(),
+		}
+		if event.IncidentProperties != nil {
+			cordonedZone.Title = to.String(event.IncidentProperties.Title)
+		}
+		if existing, ok := previous[zone]; ok {
+			cordonedZone.Since = existing.Since
+		} else {
+			log.Info("Cordoning availability zone impacted by a service health event", "zone", zone, "event", cordonedZone.EventID)
+		}
+		cordoned = append(cordoned, cordonedZone)
+	}
+	for zone := range previous {
+		if _, ok := impacted[zone]; !ok {
+			log.Info("Uncordoning availability zone no longer impacted by a service health event", "zone", zone)
+		}
+	}
+	sort.Slice(cordoned, func(i, j int) bool { return cordoned[i].Zone < cordoned[j].Zone })
+
+	// The MachineDeployments pinned to a cordoned zone keep placing their machines there, so they are reported for
+	// the user to move them to another zone.
+	if len(cordoned) > 0 {
+		zonal, err := s.Scope.ZonalMachineDeployments(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to list the MachineDeployments placed in cordoned zones")
+		}
+		for i := range cordoned {
+			cordoned[i].MachineDeployments = zonal[cordoned[i].Zone]
+			sort.Strings(cordoned[i].MachineDeployments)
+		}
+	}
+
+	s.Scope.SetCordonedZones(cordoned)
+	return nil
+}
+
+// Delete is a no-op as no Azure resource is created.
+func (s *Service) Delete(_ context.Context) error {
+	return nil
+}
+
+// impactedZones returns the availability zones of the zonal VMs which are unavailable because of an active service
+// health event, with the event.
+func impactedZones(statuses []resourcehealth.AvailabilityStatus, vmZones map[string]string) map[string]resourcehealth.ServiceImpactingEvent {
+	impacted := make(map[string]resourcehealth.ServiceImpactingEvent)
+	for _, status := range statuses {
+		if status.ID == nil || status.Properties == nil || status.Properties.AvailabilityState != resourcehealth.Unavailable {
+			continue
+		}
+		zone, ok := vmZones[strings.TrimSuffix(strings.ToLower(*status.ID), availabilityStatusSuffix)]
+		if !ok {
+			continue
+		}
+		if event, ok := activeEvent(status.Properties.ServiceImpactingEvents); ok {
+			if _, seen := impacted[zone]; !seen {
+				impacted[zone] = event
+			}
+		}
+	}
+	return impacted
+}
+
+// activeEvent returns the first active service health event of an availability status.
+func activeEvent(events *[]resourcehealth.ServiceImpactingEvent) (resourcehealth.ServiceImpactingEvent, bool) {
+	if events == nil {
+		return resourcehealth.ServiceImpactingEvent{}, false
+	}
+	for _, event := range *events {
+		if event.Status != nil && strings.EqualFold(to.String(event.Status.Value), "Active") {
+			return event, true
+		}
+	}
+	return resourcehealth.ServiceImpactingEvent{}, false
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zoneoutages
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/resourcehealth/mgmt/2017-07-01/resourcehealth"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/zoneoutages/mock_zoneoutages"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const vmID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/"
+
+// availabilityStatus returns the availability status of a VM, impacted by a service health event with the status if
+// it's not empty.
+func availabilityStatus(vm string, state resourcehealth.AvailabilityStateValues, eventStatus string) resourcehealth.AvailabilityStatus {
+	status := resourcehealth.AvailabilityStatus{
+		ID: to.StringPtr(vmID + vm + "/providers/Microsoft.ResourceHealth/availabilityStatuses/current"),
+		Properties: &resourcehealth.AvailabilityStatusProperties{
+			AvailabilityState: state,
+		},
+	}
+	if eventStatus != "" {
+		status.Properties.ServiceImpactingEvents = &[]resourcehealth.ServiceImpactingEvent{
+			{
+				CorrelationID:      to.StringPtr("event-1"),
+				Status:             &resourcehealth.ServiceImpactingEventStatus{Value: to.StringPtr(eventStatus)},
+				IncidentProperties: &resourcehealth.ServiceImpactingEventIncidentProperties{Title: to.StringPtr("Compute outage")},
+			},
+		}
+	}
+	return status
+}
+
+func TestReconcileZoneOutages(t *testing.T) {
+	vmZones := map[string]string{
+		"/subscriptions/123/resourcegroups/my-rg/providers/microsoft.compute/virtualmachines/cp-1": "1",
+		"/subscriptions/123/resourcegroups/my-rg/providers/microsoft.compute/virtualmachines/cp-2": "2",
+		"/subscriptions/123/resourcegroups/my-rg/providers/microsoft.compute/virtualmachines/cp-3": "3",
+	}
+	since := metav1.NewTime(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC))
+
+	testcases := []struct {
+		name          string
+		enabled       bool
+		cordoned      []infrav1.CordonedZone
+		expect        func(m *mock_zoneoutages.MockclientMockRecorder)
+		want          []infrav1.CordonedZone
+		expectedError string
+	}{
+		{
+			name:    "cordoned zones are cleared when disabled",
+			enabled: false,
+			want:    nil,
+		},
+		{
+			name:    "zone of a VM impacted by an active event is cordoned",
+			enabled: true,
+			expect: func(m *mock_zoneoutages.MockclientMockRecorder) {
+				m.ListAvailabilityStatuses(gomockinternal.AContext(), "my-rg").Return([]resourcehealth.AvailabilityStatus{
+					availabilityStatus("cp-1", resourcehealth.Unavailable, "Active"),
+					availabilityStatus("cp-2", resourcehealth.Available, ""),
+					availabilityStatus("cp-3", resourcehealth.Unavailable, ""),
+				}, nil)
+				m.ListVirtualMachineZones(gomockinternal.AContext(), "my-rg").Return(vmZones, nil)
+			},
+			want: []infrav1.CordonedZone{{Zone: "1", EventID: "event-1", Title: "Compute outage"}},
+		},
+		{
+			name:    "MachineDeployments placed in a cordoned zone are reported",
+			enabled: true,
+			expect: func(m *mock_zoneoutages.MockclientMockRecorder) {
+				m.ListAvailabilityStatuses(gomockinternal.AContext(), "my-rg").Return([]resourcehealth.AvailabilityStatus{
+					availabilityStatus("cp-2", resourcehealth.Unavailable, "Active"),
+				}, nil)
+				m.ListVirtualMachineZones(gomockinternal.AContext(), "my-rg").Return(vmZones, nil)
+			},
+			want: []infrav1.CordonedZone{{Zone: "2", EventID: "event-1", Title: "Compute outage", MachineDeployments: []string{"md-a", "md-b"}}},
+		},
+		{
+			name:     "zone stays cordoned since the event started impacting it",
+			enabled:  true,
+			cordoned: []infrav1.CordonedZone{{Zone: "1", EventID: "event-1", Title: "Compute outage", Since: since}},
+			expect: func(m *mock_zoneoutages.MockclientMockRecorder) {
+				m.ListAvailabilityStatuses(gomockinternal.AContext(), "my-rg").Return([]resourcehealth.AvailabilityStatus{
+					availabilityStatus("cp-1", resourcehealth.Unavailable, "Active"),
+				}, nil)
+				m.ListVirtualMachineZones(gomockinternal.AContext(), "my-rg").Return(vmZones, nil)
+			},
+			want: []infrav1.CordonedZone{{Zone: "1", EventID: "event-1", Title: "Compute outage", Since: since}},
+		},
+		{
+			name:     "zone is uncordoned once the event is resolved",
+			enabled:  true,
+			cordoned: []infrav1.CordonedZone{{Zone: "1", EventID: "event-1", Title: "Compute outage", Since: since}},
+			expect: func(m *mock_zoneoutages.MockclientMockRecorder) {
+				m.ListAvailabilityStatuses(gomockinternal.AContext(), "my-rg").Return([]resourcehealth.AvailabilityStatus{
+					availabilityStatus("cp-1", resourcehealth.Unavailable, "Resolved"),
+				}, nil)
+				m.ListVirtualMachineZones(gomockinternal.AContext(), "my-rg").Return(vmZones, nil)
+			},
+			want: nil,
+		},
+		{
+			name:    "event impacting every zone cordons none",
+			enabled: true,
+			expect: func(m *mock_zoneoutages.MockclientMockRecorder) {
+				m.ListAvailabilityStatuses(gomockinternal.AContext(), "my-rg").Return([]resourcehealth.AvailabilityStatus{
+					availabilityStatus("cp-1", resourcehealth.Unavailable, "Active"),
+					availabilityStatus("cp-2", resourcehealth.Unavailable, "Active"),
+					availabilityStatus("cp-3", resourcehealth.Unavailable, "Active"),
+				}, nil)
+				m.ListVirtualMachineZones(gomockinternal.AContext(), "my-rg").Return(vmZones, nil)
+			},
+			want: nil,
+		},
+		{
+			name:    "no zone is cordoned before the resource group exists",
+			enabled: true,
+			expect: func(m *mock_zoneoutages.MockclientMockRecorder) {
+				m.ListAvailabilityStatuses(gomockinternal.AContext(), "my-rg").Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "ResourceGroupNotFound"))
+			},
+			want: nil,
+		},
+		{
+			name:    "fail to list availability statuses",
+			enabled: true,
+			expect: func(m *mock_zoneoutages.MockclientMockRecorder) {
+				m.ListAvailabilityStatuses(gomockinternal.AContext(), "my-rg").Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error"))
+			},
+			expectedError: "failed to list availability statuses in resource group my-rg",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_zoneoutages.NewMockZoneOutageScope(mockCtrl)
+			clientMock := mock_zoneoutages.NewMockclient(mockCtrl)
+
+			scopeMock.EXPECT().CordonImpactedZones().Return(tc.enabled)
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().FailureDomains().AnyTimes().Return([]string{"1", "2", "3"})
+			scopeMock.EXPECT().CordonedZones().AnyTimes().Return(tc.cordoned)
+			scopeMock.EXPECT().ZonalMachineDeployments(gomockinternal.AContext()).AnyTimes().Return(map[string][]string{
+				"2": {"md-b", "md-a"},
+				"3": {"md-c"},
+			}, nil)
+			var got []infrav1.CordonedZone
+			scopeMock.EXPECT().SetCordonedZones(gomock.Any()).MaxTimes(1).Do(func(zones []infrav1.CordonedZone) {
+				got = zones
+			})
+			if tc.expect != nil {
+				tc.expect(clientMock.EXPECT())
+			}
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(HaveLen(len(tc.want)))
+			for i := range tc.want {
+				g.Expect(got[i].Zone).To(Equal(tc.want[i].Zone))
+				g.Expect(got[i].EventID).To(Equal(tc.want[i].EventID))
+				g.Expect(got[i].Title).To(Equal(tc.want[i].Title))
+				g.Expect(got[i].MachineDeployments).To(Equal(tc.want[i].MachineDeployments))
+				if !tc.want[i].Since.IsZero() {
+					g.Expect(got[i].Since).To(Equal(tc.want[i].Since))
+				} else {
+					g.Expect(got[i].Since.IsZero()).To(BeFalse())
+				}
+			}
+		})
+	}
+}
@@ -29,6 +29,8 @@ type PublicIPSpec struct {
 	Name    string
 	DNSName string
 	IsIPv6  bool
+	// RetainOnDelete keeps the public IP in place when it is deleted.
+	RetainOnDelete bool
 }
 
 // NICSpec defines the specification for a Network Interface.
@@ -49,6 +51,8 @@ type NICSpec struct {
 	AcceleratedNetworking     *bool
 	IPv6Enabled               bool
 	EnableIPForwarding        bool
+	PoolName                  string
+	PoolSize                  int
 }
 
 // LBSpec defines the specification for a Load Balancer.
@@ -62,6 +66,15 @@ type LBSpec struct {
 	FrontendIPConfigs    []infrav1.FrontendIP
 	APIServerPort        int32
 	IdleTimeoutInMinutes *int32
+	// ForwardedPorts are additional ports the API server load balancer forwards to the control plane nodes.
+	ForwardedPorts []LBPort
+}
+
+// LBPort defines a port forwarded by a load balancer to the same port of its backend pool.
+type LBPort struct {
+	Name     string
+	Port     int32
+	Protocol string
 }
 
 // RouteTableRole defines the unique role of a route table.
@@ -69,8 +82,9 @@ type RouteTableRole string
 
 // RouteTableSpec defines the specification for a Route Table.
 type RouteTableSpec struct {
-	Name   string
-	Subnet infrav1.SubnetSpec
+	Name                       string
+	Subnet                     infrav1.SubnetSpec
+	DisableBGPRoutePropagation bool
 }
 
 // NatGatewaySpec defines the specification for a Nat Gateway.
@@ -88,28 +102,48 @@ type InboundNatSpec struct {
 
 // SubnetSpec defines the specification for a Subnet.
 type SubnetSpec struct {
-	Name              string
-	CIDRs             []string
-	VNetName          string
-	RouteTableName    string
-	SecurityGroupName string
-	Role              infrav1.SubnetRole
-	NatGatewayName    string
+	Name               string
+	CIDRs              []string
+	VNetName           string
+	RouteTableName     string
+	SecurityGroupName  string
+	Role               infrav1.SubnetRole
+	NatGatewayName     string
+	PrivateEndpoints   bool
+	PrivateLinkService bool
 }
 
 // VNetSpec defines the specification for a Virtual Network.
 type VNetSpec struct {
-	ResourceGroup string
-	Name          string
-	CIDRs         []string
-	Peerings      []infrav1.VnetPeeringSpec
+	ResourceGroup          string
+	Name                   string
+	CIDRs                  []string
+	Peerings               []infrav1.VnetPeeringSpec
+	DDoSProtectionPlanID   string
+	DDoSProtectionPlanName string
+}
+
+// NetworkDeploymentSpec defines the specification for the ARM deployment provisioning the initial network of a cluster.
+type NetworkDeploymentSpec struct {
+	Name           string
+	ResourceGroup  string
+	VNet           VNetSpec
+	SecurityGroups []NSGSpec
+	RouteTables    []RouteTableSpec
+	Subnets        []SubnetSpec
 }
 
 // RoleAssignmentSpec defines the specification for a Role Assignment.
 type RoleAssignmentSpec struct {
+	// MachineName is the name of the VM, scale set or managed cluster whose system-assigned identity the role is
+	// assigned to.
 	MachineName  string
 	Name         string
 	ResourceType string
+	// Scope is the scope the role is assigned at. Defaults to the subscription.
+	Scope string
+	// RoleDefinitionID is the ID of the Azure built-in role assigned. Defaults to Contributor.
+	RoleDefinitionID string
 }
 
 // ResourceType defines the type azure resource being reconciled.
@@ -123,6 +157,9 @@ const (
 
 	// VirtualMachineScaleSet ...
 	VirtualMachineScaleSet = "VirtualMachineScaleSet"
+
+	// ManagedCluster is the resource type of AKS clusters, whose system-assigned identity roles are assigned to.
+	ManagedCluster = "ManagedCluster"
 )
 
 // NSGSpec defines the specification for a Security Group.
@@ -138,10 +175,39 @@ type BastionSpec struct {
 
 // AzureBastionSpec defines the specification for azure bastion feature.
 type AzureBastionSpec struct { // nolint
-	Name         string
-	SubnetSpec   infrav1.SubnetSpec
-	PublicIPName string
+	Name            string
+	SubnetSpec      infrav1.SubnetSpec
+	PublicIPName    string
+	VNetName        string
+	Sku             infrav1.BastionHostSkuName
+	ScaleUnits      *int32
+	EnableTunneling bool
+	EnableIPConnect bool
+}
+
+// ExpressRouteGatewaySpec defines the specification for an ExpressRoute virtual network gateway.
+type ExpressRouteGatewaySpec struct {
+	Name          string
+	ResourceGroup string
+	Sku           infrav1.ExpressRouteGatewaySku
+	VNetName      string
+	SubnetName    string
+	PublicIPName  string
+}
+
+// AzureFirewallSpec defines the specification for the Azure Firewall the egress of the nodes is routed through.
+type AzureFirewallSpec struct {
+	Name          string
+	ResourceGroup string
+	// Managed is whether the firewall is created and deleted with the cluster, rather than an existing one.
+	Managed      bool
+	SkuTier      infrav1.AzureFirewallSkuTier
+	PolicyID     string
 	VNetName     string
+	SubnetName   string
+	PublicIPName string
+	// RouteTableNames are the route tables of the node subnets, whose default route points to the firewall.
+	RouteTableNames []string
 }
 
 // ScaleSetSpec defines the specification for a Scale Set.
@@ -159,11 +225,129 @@ type ScaleSetSpec struct {
 	PublicLBAddressPoolName      string
 	AcceleratedNetworking        *bool
 	TerminateNotificationTimeout *int
+	Diagnostics                  *infrav1.Diagnostics
 	Identity                     infrav1.VMIdentity
 	UserAssignedIdentities       []infrav1.UserAssignedIdentity
 	SecurityProfile              *infrav1.SecurityProfile
 	SpotVMOptions                *infrav1.SpotVMOptions
 	FailureDomains               []string
+	// AllZones spreads the scale set across all the availability zones of its location which offer its VM size,
+	// ignoring FailureDomains.
+	AllZones bool
+	// ExcludedZones are the availability zones AllZones doesn't spread a new scale set to, e.g. the cordoned zones of
+	// the cluster. The zones of an existing scale set can't be changed.
+	ExcludedZones []string
+	// ZoneBalance strictly balances the instances across the zones of the scale set, or nil to leave it to Azure.
+	ZoneBalance *bool
+	// PlatformFaultDomainCount is the number of fault domains of the scale set, or nil to leave it to Azure.
+	PlatformFaultDomainCount   *int32
+	ProximityPlacementGroupID  string
+	CapacityReservationGroupID string
+	ComputerNamePrefix         string
+	UpgradePolicy              *ScaleSetUpgradePolicy
+	AutomaticRepairsPolicy     *ScaleSetAutomaticRepairsPolicy
+	// PriorityMixPolicy blends regular and Spot VMs in a Flexible scale set, or nil for no mix.
+	PriorityMixPolicy *ScaleSetPriorityMixPolicy
+	// OrchestrationMode is the orchestration mode of the scale set, Uniform or Flexible.
+	OrchestrationMode string
+	// ScaleInRule is the rule Azure follows to choose the instances removed when the scale set is scaled in, or "" to
+	// leave the choice to Azure.
+	ScaleInRule string
+	// ScaleUpBatchSize is the maximum number of instances added to the scale set at once, or 0 for no limit.
+	ScaleUpBatchSize int64
+	// ForceDelete force deletes the scale set, falling back to a normal deletion where force deletion is not available.
+	ForceDelete bool
+}
+
+// ScaleSetUpgradePolicy defines the upgrade policy of a virtual machine scale set.
+type ScaleSetUpgradePolicy struct {
+	Mode                                string
+	AutomaticOSUpgrade                  bool
+	MaxBatchInstancePercent             *int32
+	MaxUnhealthyInstancePercent         *int32
+	MaxUnhealthyUpgradedInstancePercent *int32
+	// PauseTimeBetweenBatches is in ISO 8601 format.
+	PauseTimeBetweenBatches *string
+	HealthProbe             *ScaleSetHealthProbe
+}
+
+// ScaleSetAutomaticRepairsPolicy defines the automatic repairs policy of a virtual machine scale set.
+type ScaleSetAutomaticRepairsPolicy struct {
+	Enabled bool
+	// GracePeriod is in ISO 8601 format.
+	GracePeriod *string
+}
+
+// ScaleSetPriorityMixPolicy defines the mix of regular and Spot VMs of a virtual machine scale set.
+type ScaleSetPriorityMixPolicy struct {
+	BaseRegularPriorityCount           *int32
+	RegularPriorityPercentageAboveBase *int32
+}
+
+// ScaleSetHealthProbe defines the endpoint probed by the Application Health extension of a virtual machine scale set.
+type ScaleSetHealthProbe struct {
+	Protocol    string
+	Port        int32
+	RequestPath string
+}
+
+// SpotPlacementScoreSpec defines the specification for a Spot Placement Score lookup.
+type SpotPlacementScoreSpec struct {
+	Location  string
+	Size      string
+	Zones     []string
+	Count     int32
+	Threshold string
+}
+
+// ImageReplicationMode is how the replication of the gallery image version of a new machine into its location is
+// handled before its VM is created.
+type ImageReplicationMode string
+
+const (
+	// ImageReplicationDisabled doesn't check the replication of gallery image versions.
+	ImageReplicationDisabled ImageReplicationMode = ""
+	// ImageReplicationVerify waits for the gallery image version to be replicated into the location of the machine.
+	ImageReplicationVerify ImageReplicationMode = "Verify"
+	// ImageReplicationReplicate adds the location of the machine to the target regions of the gallery image version
+	// if it's missing, then waits for the replication.
+	ImageReplicationReplicate ImageReplicationMode = "Replicate"
+)
+
+// ImageReplicationSpec defines the specification for the replication of a gallery image version into a location.
+type ImageReplicationSpec struct {
+	SubscriptionID string
+	ResourceGroup  string
+	Gallery        string
+	Image          string
+	Version        string
+	Location       string
+	Replicate      bool
+}
+
+// DefaultImageSource is where the images of the VMs of machines which don't specify one are resolved from.
+type DefaultImageSource string
+
+const (
+	// DefaultImageSourceMarketplace resolves the reference images published in the Azure Marketplace.
+	DefaultImageSourceMarketplace DefaultImageSource = "Marketplace"
+	// DefaultImageSourceCommunityGallery resolves the reference images published in the community gallery of
+	// Cluster API.
+	DefaultImageSourceCommunityGallery DefaultImageSource = "CommunityGallery"
+)
+
+// MarketplaceTermsSpec defines the specification for the marketplace terms of the purchase plan of an image.
+type MarketplaceTermsSpec struct {
+	Publisher string
+	Offer     string
+	Plan      string
+}
+
+// VMPowerStateSpec defines the specification for the power state of a VM.
+type VMPowerStateSpec struct {
+	Name          string
+	ResourceGroup string
+	PowerState    infrav1.VMPowerState
 }
 
 // TagsSpec defines the specification for a set of tags.
@@ -176,11 +360,54 @@ type TagsSpec struct {
 	Annotation string
 }
 
+// OwnershipSpec defines the specification for verifying the ownership tag of a resource.
+type OwnershipSpec struct {
+	// Name is the name of the resource.
+	Name string
+	// Scope is the ID of the resource.
+	Scope string
+	// Owned is true when the cluster has recorded the resource as owned, in which case a missing ownership tag is
+	// restored.
+	Owned bool
+}
+
+// SNATMetricsSpec defines the specification for collecting the SNAT metrics of an outbound load balancer or NAT gateway.
+type SNATMetricsSpec struct {
+	Name       string
+	ResourceID string
+	// NATGateway is true when the resource is a NAT gateway rather than a load balancer.
+	NATGateway bool
+}
+
+// AlertRuleSpec defines the specification for a metric alert rule on the resources of a cluster.
+type AlertRuleSpec struct {
+	Name        string
+	Description string
+	Severity    int32
+	// Scopes are the IDs of the resources monitored by the rule, or of their resource group.
+	Scopes []string
+	// TargetResourceType is the type of the monitored resources, which is also the namespace of the metric.
+	TargetResourceType string
+	MetricName         string
+	Aggregation        string
+	Operator           string
+	Threshold          float64
+	// DimensionName and DimensionValues filter the metric on the values of a dimension, if set.
+	DimensionName   string
+	DimensionValues []string
+	ActionGroupID   string
+}
+
 // PrivateDNSSpec defines the specification for a private DNS zone.
 type PrivateDNSSpec struct {
-	ZoneName string
-	Links    []PrivateDNSLinkSpec
-	Records  []infrav1.AddressRecord
+	ZoneName       string
+	ResourceGroup  string
+	SubscriptionID string
+	// ExistingZone is true when the zone is not owned by the cluster, in which case only its links and records are managed.
+	ExistingZone bool
+	Links        []PrivateDNSLinkSpec
+	Records      []infrav1.AddressRecord
+	CNAMERecords []PrivateDNSCNAMERecordSpec
 }
 
 // PrivateDNSLinkSpec defines the specification for a virtual network link in a private DNS zone.
@@ -190,6 +417,26 @@ type PrivateDNSLinkSpec struct {
 	LinkName          string
 }
 
+// PrivateDNSCNAMERecordSpec defines the specification for a CNAME record in a private DNS zone.
+type PrivateDNSCNAMERecordSpec struct {
+	Hostname string
+	CNAME    string
+}
+
+// PublicDNSSpec defines the specification for the record of a public API server in an existing DNS zone.
+type PublicDNSSpec struct {
+	ZoneName       string
+	ResourceGroup  string
+	SubscriptionID string
+	RecordName     string
+	RecordType     infrav1.PublicDNSRecordType
+	TTL            int64
+	// CNAME is the FQDN of the public IP of the API server, the target of a CNAME record.
+	CNAME string
+	// PublicIPID is the ID of the public IP of the API server, the target of an A alias record.
+	PublicIPID string
+}
+
 // AvailabilitySetSpec defines the specification for an availability set.
 type AvailabilitySetSpec struct {
 	Name string
@@ -213,6 +460,9 @@ type (
 		Name             string                    `json:"name,omitempty"`
 		AvailabilityZone string                    `json:"availabilityZone,omitempty"`
 		State            infrav1.ProvisioningState `json:"vmState,omitempty"`
+		// ProtectFromScaleIn and ProtectFromScaleSetActions are the protection policy of the instance.
+		ProtectFromScaleIn         bool `json:"protectFromScaleIn,omitempty"`
+		ProtectFromScaleSetActions bool `json:"protectFromScaleSetActions,omitempty"`
 	}
 
 	// VMSS defines a virtual machine scale set.
@@ -227,6 +477,37 @@ type (
 		Identity  infrav1.VMIdentity        `json:"identity,omitempty"`
 		Tags      infrav1.Tags              `json:"tags,omitempty"`
 		Instances []VMSSVM                  `json:"instances,omitempty"`
+		// OrchestrationMode is the orchestration mode of the VMSS, Uniform or Flexible.
+		OrchestrationMode string `json:"orchestrationMode,omitempty"`
+		// ScaleInRule is the rule Azure follows to choose the instances removed when the VMSS is scaled in.
+		ScaleInRule string `json:"scaleInRule,omitempty"`
+		// UpgradeMode and AutomaticOSUpgrade are the upgrade settings of the VMSS.
+		UpgradeMode        string `json:"upgradeMode,omitempty"`
+		AutomaticOSUpgrade bool   `json:"automaticOSUpgrade,omitempty"`
+		// AutomaticRepairs and AutomaticRepairsGracePeriod are the automatic repairs settings of the VMSS.
+		AutomaticRepairs            bool   `json:"automaticRepairs,omitempty"`
+		AutomaticRepairsGracePeriod string `json:"automaticRepairsGracePeriod,omitempty"`
+		// UserData is the base64 encoded user data of the VMSS model, which is kept out of the logs.
+		UserData string `json:"-"`
+		// DataDisks are the data disks attached to the instances of the VMSS model.
+		DataDisks []VMSSDataDisk `json:"dataDisks,omitempty"`
+		// AcceleratedNetworking is whether accelerated networking is enabled on the network interfaces of the VMSS model.
+		AcceleratedNetworking bool `json:"acceleratedNetworking,omitempty"`
+		// BootDiagnostics is whether boot diagnostics are enabled on the VMSS model.
+		BootDiagnostics bool `json:"bootDiagnostics,omitempty"`
+		// BootDiagnosticsStorageURI is the blob endpoint of the storage account the boot diagnostics are stored in, or ""
+		// for a storage account managed by Azure.
+		BootDiagnosticsStorageURI string `json:"bootDiagnosticsStorageURI,omitempty"`
+	}
+
+	// VMSSDataDisk defines a data disk of the model of a virtual machine scale set.
+	VMSSDataDisk struct {
+		Name                    string `json:"name,omitempty"`
+		Lun                     int32  `json:"lun"`
+		DiskSizeGB              int32  `json:"diskSizeGB,omitempty"`
+		StorageAccountType      string `json:"storageAccountType,omitempty"`
+		CachingType             string `json:"cachingType,omitempty"`
+		WriteAcceleratorEnabled bool   `json:"writeAcceleratorEnabled,omitempty"`
 	}
 )
 
@@ -236,10 +517,42 @@ func (vmss VMSS) HasModelChanges(other VMSS) bool {
 		cmp.Equal(vmss.Identity, other.Identity) &&
 		cmp.Equal(vmss.Zones, other.Zones) &&
 		cmp.Equal(vmss.Tags, other.Tags) &&
-		cmp.Equal(vmss.Sku, other.Sku)
+		cmp.Equal(vmss.Sku, other.Sku) &&
+		vmss.UpgradeMode == other.UpgradeMode &&
+		vmss.AutomaticOSUpgrade == other.AutomaticOSUpgrade &&
+		vmss.AcceleratedNetworking == other.AcceleratedNetworking &&
+		vmss.BootDiagnostics == other.BootDiagnostics &&
+		vmss.BootDiagnosticsStorageURI == other.BootDiagnosticsStorageURI &&
+		!hasDataDiskChanges(vmss.DataDisks, other.DataDisks)
 	return !equal
 }
 
+// hasDataDiskChanges returns true if the desired data disks differ from the current ones. The storage account and
+// caching types left unspecified are chosen by Azure, so they are not compared.
+func hasDataDiskChanges(current, desired []VMSSDataDisk) bool {
+	if len(current) != len(desired) {
+		return true
+	}
+
+	currentByLun := make(map[int32]VMSSDataDisk, len(current))
+	for _, disk := range current {
+		currentByLun[disk.Lun] = disk
+	}
+	for _, disk := range desired {
+		existing, ok := currentByLun[disk.Lun]
+		switch {
+		case !ok,
+			existing.Name != disk.Name,
+			existing.DiskSizeGB != disk.DiskSizeGB,
+			existing.WriteAcceleratorEnabled != disk.WriteAcceleratorEnabled,
+			disk.StorageAccountType != "" && existing.StorageAccountType != disk.StorageAccountType,
+			disk.CachingType != "" && existing.CachingType != disk.CachingType:
+			return true
+		}
+	}
+	return false
+}
+
 // InstancesByProviderID returns VMSSVMs by ID.
 func (vmss VMSS) InstancesByProviderID() map[string]VMSSVM {
 	instancesByProviderID := make(map[string]VMSSVM, len(vmss.Instances))
@@ -317,9 +630,15 @@ type ManagedClusterSpec struct {
 	// NetworkPlugin used for building Kubernetes network. Possible values include: 'azure', 'kubenet'. Defaults to azure.
 	NetworkPlugin string
 
-	// NetworkPolicy used for building Kubernetes network. Possible values include: 'calico', 'azure'. Defaults to azure.
+	// NetworkPluginMode is the mode of the network plugin. Possible values include: 'overlay'.
+	NetworkPluginMode string
+
+	// NetworkPolicy used for building Kubernetes network. Possible values include: 'calico', 'azure', 'cilium'. Defaults to azure.
 	NetworkPolicy string
 
+	// NetworkDataplane is the dataplane of the network of the cluster. Possible values include: 'azure', 'cilium'.
+	NetworkDataplane string
+
 	// SSHPublicKey is a string literal containing an ssh public key. Will autogenerate and discard if not provided.
 	SSHPublicKey string
 
@@ -346,6 +665,51 @@ type ManagedClusterSpec struct {
 
 	// APIServerAccessProfile is the access profile for AKS API server.
 	APIServerAccessProfile *APIServerAccessProfile
+
+	// HTTPProxyConfig is the HTTP proxy configuration for the cluster's nodes.
+	HTTPProxyConfig *HTTPProxyConfig
+
+	// AutoScalerProfile is the profile of the cluster autoscaler.
+	AutoScalerProfile *AutoScalerProfile
+
+	// OIDCIssuerEnabled is whether the OIDC issuer of the cluster is enabled, or nil to leave it unmanaged.
+	OIDCIssuerEnabled *bool
+
+	// WorkloadIdentityEnabled is whether workload identity is enabled, or nil to leave it unmanaged.
+	WorkloadIdentityEnabled *bool
+
+	// AddonProfiles are the profiles of the add-ons of the cluster.
+	AddonProfiles []AddonProfile
+
+	// Stopped is whether the cluster is stopped.
+	Stopped bool
+
+	// DisableLocalAccounts is whether the local accounts of the cluster are disabled.
+	DisableLocalAccounts *bool
+}
+
+// AddonProfile is the profile of a managed cluster add-on.
+type AddonProfile struct {
+	// Name is the name of the add-on.
+	Name string
+
+	// Enabled is whether the add-on is enabled.
+	Enabled bool
+
+	// Config is the settings of the add-on.
+	Config map[string]string
+}
+
+// WorkloadIdentityProfile is the OIDC issuer and workload identity configuration of a managed cluster.
+type WorkloadIdentityProfile struct {
+	// OIDCIssuerEnabled is whether the OIDC issuer of the cluster is enabled.
+	OIDCIssuerEnabled bool
+
+	// OIDCIssuerURL is the URL of the OIDC issuer of the cluster, when it is enabled.
+	OIDCIssuerURL string
+
+	// WorkloadIdentityEnabled is whether workload identity is enabled.
+	WorkloadIdentityEnabled bool
 }
 
 // AADProfile is Azure Active Directory configuration to integrate with AKS, for aad authentication.
@@ -401,6 +765,39 @@ type APIServerAccessProfile struct {
 	EnablePrivateClusterPublicFQDN *bool
 }
 
+// HTTPProxyConfig - Configuration for provisioning the cluster with HTTP proxy servers.
+type HTTPProxyConfig struct {
+	// HTTPProxy - HTTP proxy server endpoint to use.
+	HTTPProxy string
+	// HTTPSProxy - HTTPS proxy server endpoint to use.
+	HTTPSProxy string
+	// NoProxy - Endpoints that should not go through the proxy.
+	NoProxy []string
+	// TrustedCA - PEM-encoded CA bundle to use for connecting to the proxy servers.
+	TrustedCA string
+}
+
+// AutoScalerProfile - Parameters of the cluster autoscaler. Unset parameters keep their AKS defaults.
+type AutoScalerProfile struct {
+	BalanceSimilarNodeGroups      *string
+	Expander                      *string
+	MaxEmptyBulkDelete            *string
+	MaxGracefulTerminationSec     *string
+	MaxNodeProvisionTime          *string
+	MaxTotalUnreadyPercentage     *string
+	NewPodScaleUpDelay            *string
+	OkTotalUnreadyCount           *string
+	ScanInterval                  *string
+	ScaleDownDelayAfterAdd        *string
+	ScaleDownDelayAfterDelete     *string
+	ScaleDownDelayAfterFailure    *string
+	ScaleDownUnneededTime         *string
+	ScaleDownUnreadyTime          *string
+	ScaleDownUtilizationThreshold *string
+	SkipNodesWithLocalStorage     *string
+	SkipNodesWithSystemPods       *string
+}
+
 // AgentPoolSpec contains agent pool specification details.
 type AgentPoolSpec struct {
 	// Name is the name of agent pool.
@@ -441,4 +838,74 @@ type AgentPoolSpec struct {
 
 	// AvailabilityZones represents the Availability zones for nodes in the AgentPool.
 	AvailabilityZones []string
+
+	// EnableArtifactStreaming is whether artifact streaming is enabled, or nil to leave it unmanaged.
+	EnableArtifactStreaming *bool
+
+	// NodeLabels are the Kubernetes labels of the nodes of the agent pool.
+	NodeLabels map[string]*string
+
+	// NodeTaints are the Kubernetes taints of the nodes of the agent pool, as key=value:effect.
+	NodeTaints []string
+
+	// ScaleSetPriority is the priority of the VMs of the agent pool. Possible values include: 'Regular', 'Spot'.
+	ScaleSetPriority string
+
+	// ScaleSetEvictionPolicy is the eviction policy of the VMs of a Spot agent pool. Possible values include: 'Delete', 'Deallocate'.
+	ScaleSetEvictionPolicy string
+
+	// SpotMaxPrice is the maximum price of the VMs of a Spot agent pool, in US dollars per hour, or -1 for the on-demand price.
+	SpotMaxPrice *float64
 }
+
+// Summaries of existing Azure resources, returned by the Describe methods of the services.
+// They only hold the state reported to users, and are cheaper to build than the infrav1 spec types.
+type (
+	// VNetSummary describes an existing virtual network.
+	VNetSummary struct {
+		ID                string                    `json:"id,omitempty"`
+		Name              string                    `json:"name,omitempty"`
+		ResourceGroup     string                    `json:"resourceGroup,omitempty"`
+		CIDRs             []string                  `json:"cidrs,omitempty"`
+		Subnets           []SubnetSummary           `json:"subnets,omitempty"`
+		Tags              infrav1.Tags              `json:"tags,omitempty"`
+		ProvisioningState infrav1.ProvisioningState `json:"provisioningState,omitempty"`
+	}
+
+	// SubnetSummary describes an existing subnet.
+	SubnetSummary struct {
+		ID                string                    `json:"id,omitempty"`
+		Name              string                    `json:"name,omitempty"`
+		CIDRs             []string                  `json:"cidrs,omitempty"`
+		SecurityGroupID   string                    `json:"securityGroupID,omitempty"`
+		RouteTableID      string                    `json:"routeTableID,omitempty"`
+		NatGatewayID      string                    `json:"natGatewayID,omitempty"`
+		ProvisioningState infrav1.ProvisioningState `json:"provisioningState,omitempty"`
+	}
+
+	// LoadBalancerSummary describes an existing load balancer.
+	LoadBalancerSummary struct {
+		ID                string                    `json:"id,omitempty"`
+		Name              string                    `json:"name,omitempty"`
+		SKU               string                    `json:"sku,omitempty"`
+		FrontendIPs       []FrontendIPSummary       `json:"frontendIPs,omitempty"`
+		BackendPools      []string                  `json:"backendPools,omitempty"`
+		ProvisioningState infrav1.ProvisioningState `json:"provisioningState,omitempty"`
+	}
+
+	// FrontendIPSummary describes a frontend IP configuration of an existing load balancer.
+	FrontendIPSummary struct {
+		Name             string `json:"name,omitempty"`
+		PrivateIPAddress string `json:"privateIPAddress,omitempty"`
+		PublicIPID       string `json:"publicIPID,omitempty"`
+	}
+
+	// PublicIPSummary describes an existing public IP address.
+	PublicIPSummary struct {
+		ID                string                    `json:"id,omitempty"`
+		Name              string                    `json:"name,omitempty"`
+		IPAddress         string                    `json:"ipAddress,omitempty"`
+		FQDN              string                    `json:"fqdn,omitempty"`
+		ProvisioningState infrav1.ProvisioningState `json:"provisioningState,omitempty"`
+	}
+)
@@ -136,6 +136,70 @@ func TestVMSS_HasModelChanges(t *testing.T) {
 			},
 			HasModelChanges: true,
 		},
+		{
+			Name: "with an additional data disk",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.DataDisks = append(l.DataDisks, VMSSDataDisk{Name: "vmss_logs", Lun: 1, DiskSizeGB: 64})
+				r := getDefaultVMSSForModelTesting()
+				return r, l
+			},
+			HasModelChanges: true,
+		},
+		{
+			Name: "with a resized data disk",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.DataDisks[0].DiskSizeGB = 512
+				r := getDefaultVMSSForModelTesting()
+				return r, l
+			},
+			HasModelChanges: true,
+		},
+		{
+			Name: "with a different data disk storage account type",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.DataDisks[0].StorageAccountType = "Premium_LRS"
+				r := getDefaultVMSSForModelTesting()
+				return r, l
+			},
+			HasModelChanges: true,
+		},
+		{
+			Name: "with data disk settings left to Azure",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.DataDisks[0].StorageAccountType = ""
+				l.DataDisks[0].CachingType = ""
+				r := getDefaultVMSSForModelTesting()
+				return r, l
+			},
+			HasModelChanges: false,
+		},
+		{
+			Name: "with accelerated networking disabled",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.AcceleratedNetworking = false
+				r := getDefaultVMSSForModelTesting()
+				r.AcceleratedNetworking = true
+				return r, l
+			},
+			HasModelChanges: true,
+		},
+		{
+			Name: "with boot diagnostics moved to a user managed storage account",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.BootDiagnostics = true
+				r := getDefaultVMSSForModelTesting()
+				r.BootDiagnostics = true
+				r.BootDiagnosticsStorageURI = "https://fake.blob.core.windows.net/"
+				return r, l
+			},
+			HasModelChanges: true,
+		},
 	}
 
 	for _, c := range cases {
@@ -161,5 +225,14 @@ func getDefaultVMSSForModelTesting() VMSS {
 		Tags: infrav1.Tags{
 			"foo": "baz",
 		},
+		DataDisks: []VMSSDataDisk{
+			{
+				Name:               "vmss_etcd",
+				Lun:                0,
+				DiskSizeGB:         256,
+				StorageAccountType: "StandardSSD_LRS",
+				CachingType:        "ReadWrite",
+			},
+		},
 	}
 }
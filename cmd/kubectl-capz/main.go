@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-capz is a kubectl plugin to inspect the Azure infrastructure of the workload clusters managed by CAPZ.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inspect"
+)
+
+const usage = `Inspect the Azure infrastructure of a workload cluster managed by CAPZ.
+
+Prints the Azure resources of the AzureCluster, its conditions, the Azure long-running operations in progress
+and the recent errors of its reconciliation. The Azure resources are read with the client secret of the service
+principal of the AzureClusterIdentity the AzureCluster references, or with the AZURE_* environment variables.
+
+Usage:
+  kubectl capz describe cluster NAME [flags]
+
+Flags:
+`
+
+var (
+	scheme = runtime.NewScheme()
+
+	kubeconfig string
+	overrides  clientcmd.ConfigOverrides
+	output     string
+	maxErrors  int
+	skipAzure  bool
+	timeout    time.Duration
+)
+
+func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+}
+
+// InitFlags initializes all command-line flags.
+func InitFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file of the management cluster.")
+	clientcmd.BindOverrideFlags(&overrides, fs, clientcmd.ConfigOverrideFlags{
+		CurrentContext: clientcmd.FlagInfo{LongName: "context", Default: "", Description: "The name of the kubeconfig context to use."},
+		ContextOverrideFlags: clientcmd.ContextOverrideFlags{
+			Namespace: clientcmd.FlagInfo{LongName: "namespace", ShortName: "n", Default: "", Description: "The namespace of the AzureCluster."},
+		},
+	})
+	fs.StringVarP(&output, "output", "o", inspect.OutputText, fmt.Sprintf("Output format, one of %s, %s or %s.", inspect.OutputText, inspect.OutputJSON, inspect.OutputYAML))
+	fs.IntVar(&maxErrors, "max-errors", inspect.DefaultMaxErrors, "Maximum number of recent errors to print.")
+	fs.BoolVar(&skipAzure, "skip-azure", false, "Only print the state recorded in the management cluster, without reading the Azure resources.")
+	fs.DurationVar(&timeout, "timeout", 2*time.Minute, "Timeout of the inspection.")
+}
+
+func main() {
+	klog.InitFlags(nil)
+	InitFlags(pflag.CommandLine)
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	pflag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		pflag.PrintDefaults()
+	}
+	pflag.Parse()
+
+	ctrl.SetLogger(klogr.New())
+
+	if err := run(pflag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) != 3 || args[0] != "describe" || (args[1] != "cluster" && args[1] != "azurecluster") {
+		pflag.Usage()
+		return fmt.Errorf("expected arguments \"describe cluster NAME\", got %q", args)
+	}
+	name := args[2]
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &overrides)
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return fmt.Errorf("failed to get the namespace from the kubeconfig: %w", err)
+	}
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load the kubeconfig: %w", err)
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create the management cluster client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	report, err := inspect.Inspect(ctx, c, client.ObjectKey{Namespace: namespace, Name: name}, inspect.Options{
+		MaxErrors: maxErrors,
+		SkipAzure: skipAzure,
+	})
+	if err != nil {
+		return err
+	}
+	return report.Print(os.Stdout, output)
+}
@@ -42,6 +42,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/runtimehooks"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -52,18 +53,23 @@ type AzureClusterReconciler struct {
 	Recorder                  record.EventRecorder
 	ReconcileTimeout          time.Duration
 	WatchFilterValue          string
+	ProvisioningSLO           time.Duration
+	resyncPeriods             reconciler.ResyncPeriods
+	runtimeHooks              runtimehooks.Caller
+	cordonImpactedZones       bool
 	createAzureClusterService azureClusterServiceCreator
 }
 
 type azureClusterServiceCreator func(clusterScope *scope.ClusterScope) (*azureClusterService, error)
 
 // NewAzureClusterReconciler returns a new AzureClusterReconciler instance.
-func NewAzureClusterReconciler(client client.Client, recorder record.EventRecorder, reconcileTimeout time.Duration, watchFilterValue string) *AzureClusterReconciler {
+func NewAzureClusterReconciler(client client.Client, recorder record.EventRecorder, reconcileTimeout time.Duration, watchFilterValue string, provisioningSLO time.Duration) *AzureClusterReconciler {
 	acr := &AzureClusterReconciler{
 		Client:           client,
 		Recorder:         recorder,
 		ReconcileTimeout: reconcileTimeout,
 		WatchFilterValue: watchFilterValue,
+		ProvisioningSLO:  provisioningSLO,
 	}
 
 	acr.createAzureClusterService = newAzureClusterService
@@ -79,6 +85,9 @@ func (acr *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 	)
 	defer done()
 
+	acr.resyncPeriods = options.ResyncPeriods
+	acr.runtimeHooks = options.RuntimeHooks
+	acr.cordonImpactedZones = options.CordonImpactedZones
 	var r reconcile.Reconciler = acr
 	if options.Cache != nil {
 		r = coalescing.NewReconciler(acr, options.Cache, log)
@@ -110,8 +119,10 @@ func (acr *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinetemplates;azuremachinetemplates/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusteridentities;azureclusteridentities/status,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
 
 // Reconcile idempotently gets, creates, and updates a cluster.
 func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -175,9 +186,12 @@ func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	// Create the scope.
 	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
-		Client:       acr.Client,
-		Cluster:      cluster,
-		AzureCluster: azureCluster,
+		Client:              acr.Client,
+		Cluster:             cluster,
+		AzureCluster:        azureCluster,
+		ProvisioningSLO:     acr.ProvisioningSLO,
+		RuntimeHooks:        acr.runtimeHooks,
+		CordonImpactedZones: acr.cordonImpactedZones,
 	})
 	if err != nil {
 		err = errors.Errorf("failed to create scope: %+v", err)
@@ -249,7 +263,12 @@ func (acr *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 	azureCluster.Status.Ready = true
 	conditions.MarkTrue(azureCluster, infrav1.NetworkInfrastructureReadyCondition)
 
-	return reconcile.Result{}, nil
+	if err := clusterScope.UpdateProvisioningDurations(ctx); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to update provisioning durations")
+	}
+
+	provisioning := !clusterScope.Cluster.Status.ControlPlaneReady
+	return reconcile.Result{RequeueAfter: acr.resyncPeriods.RequeueAfter(azureCluster, provisioning)}, nil
 }
 
 func (acr *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
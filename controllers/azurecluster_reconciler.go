@@ -22,39 +22,70 @@ import (
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/alerts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/azurefirewalls"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/deployments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/diskencryptionsets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/flowlogs"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/ownership"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatedns"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatelinkservices"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/proximityplacementgroups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicdns"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/snatmetrics"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/templates"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworkgateways"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/zoneoutages"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/runtimehooks"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // azureClusterService is the reconciler called by the AzureCluster controller.
 type azureClusterService struct {
-	scope            *scope.ClusterScope
-	groupsSvc        azure.Reconciler
-	vnetSvc          azure.Reconciler
-	securityGroupSvc azure.Reconciler
-	routeTableSvc    azure.Reconciler
-	subnetsSvc       azure.Reconciler
-	publicIPSvc      azure.Reconciler
-	loadBalancerSvc  azure.Reconciler
-	privateDNSSvc    azure.Reconciler
-	bastionSvc       azure.Reconciler
-	skuCache         *resourceskus.Cache
-	natGatewaySvc    azure.Reconciler
-	peeringsSvc      azure.Reconciler
-	tagsSvc          azure.Reconciler
+	scope                       *scope.ClusterScope
+	groupsSvc                   azure.Reconciler
+	ownershipSvc                azure.Reconciler
+	networkDeploymentSvc        azure.Reconciler
+	vnetSvc                     azure.Reconciler
+	securityGroupSvc            azure.Reconciler
+	flowLogsSvc                 azure.Reconciler
+	routeTableSvc               azure.Reconciler
+	subnetsSvc                  azure.Reconciler
+	publicIPSvc                 azure.Reconciler
+	loadBalancerSvc             azure.Reconciler
+	privateDNSSvc               azure.Reconciler
+	publicDNSSvc                azure.Reconciler
+	bastionSvc                  azure.Reconciler
+	skuCache                    *resourceskus.Cache
+	natGatewaySvc               azure.Reconciler
+	peeringsSvc                 azure.Reconciler
+	privateEndpointsSvc         azure.Reconciler
+	privateLinkSvc              azure.Reconciler
+	tagsSvc                     azure.Reconciler
+	expressRouteGatewaySvc      azure.Reconciler
+	firewallSvc                 azure.Reconciler
+	snatMetricsSvc              azure.Reconciler
+	alertsSvc                   azure.Reconciler
+	diskEncryptionSetsSvc       azure.Reconciler
+	proximityPlacementGroupsSvc azure.Reconciler
+	templatesSvc                azure.Reconciler
+	zoneOutagesSvc              azure.Reconciler
+	nicPoolsSvc                 azure.Reconciler
 }
 
 // newAzureClusterService populates all the services based on input scope.
@@ -65,20 +96,35 @@ func newAzureClusterService(scope *scope.ClusterScope) (*azureClusterService, er
 	}
 
 	return &azureClusterService{
-		scope:            scope,
-		groupsSvc:        groups.New(scope),
-		vnetSvc:          virtualnetworks.New(scope),
-		securityGroupSvc: securitygroups.New(scope),
-		routeTableSvc:    routetables.New(scope),
-		natGatewaySvc:    natgateways.New(scope),
-		subnetsSvc:       subnets.New(scope),
-		publicIPSvc:      publicips.New(scope),
-		loadBalancerSvc:  loadbalancers.New(scope),
-		privateDNSSvc:    privatedns.New(scope),
-		bastionSvc:       bastionhosts.New(scope),
-		skuCache:         skuCache,
-		peeringsSvc:      vnetpeerings.New(scope),
-		tagsSvc:          tags.New(scope),
+		scope:                       scope,
+		groupsSvc:                   groups.New(scope),
+		ownershipSvc:                ownership.New(scope),
+		networkDeploymentSvc:        deployments.New(scope),
+		vnetSvc:                     virtualnetworks.New(scope),
+		securityGroupSvc:            securitygroups.New(scope),
+		flowLogsSvc:                 flowlogs.New(scope),
+		routeTableSvc:               routetables.New(scope),
+		natGatewaySvc:               natgateways.New(scope),
+		subnetsSvc:                  subnets.New(scope),
+		publicIPSvc:                 publicips.New(scope),
+		loadBalancerSvc:             loadbalancers.New(scope),
+		privateDNSSvc:               privatedns.New(scope),
+		publicDNSSvc:                publicdns.New(scope),
+		bastionSvc:                  bastionhosts.New(scope),
+		skuCache:                    skuCache,
+		peeringsSvc:                 vnetpeerings.New(scope),
+		privateEndpointsSvc:         privateendpoints.New(scope),
+		privateLinkSvc:              privatelinkservices.New(scope),
+		tagsSvc:                     tags.New(scope),
+		expressRouteGatewaySvc:      virtualnetworkgateways.New(scope),
+		firewallSvc:                 azurefirewalls.New(scope),
+		snatMetricsSvc:              snatmetrics.New(scope),
+		alertsSvc:                   alerts.New(scope),
+		diskEncryptionSetsSvc:       diskencryptionsets.New(scope),
+		proximityPlacementGroupsSvc: proximityplacementgroups.New(scope),
+		templatesSvc:                templates.New(scope),
+		zoneOutagesSvc:              zoneoutages.New(scope),
+		nicPoolsSvc:                 networkinterfaces.NewPoolService(scope),
 	}, nil
 }
 
@@ -89,61 +135,153 @@ func (s *azureClusterService) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Reconcile")
 	defer done()
 
+	// Zones impacted by an outage are cordoned before the failure domains are set, so that they are not selected.
+	if err := s.zoneOutagesSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "failed to check availability zone outages")
+	}
+
 	if err := s.setFailureDomainsForLocation(ctx); err != nil {
 		return errors.Wrap(err, "failed to get availability zones")
 	}
 
 	s.scope.SetDNSName()
-	s.scope.SetControlPlaneSecurityRules()
+	s.scope.SetAPIVersionProfileCondition()
 
-	if err := s.groupsSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, "groups", s.groupsSvc); err != nil {
 		return errors.Wrap(err, "failed to reconcile resource group")
 	}
 
-	if err := s.vnetSvc.Reconcile(ctx); err != nil {
+	// Ownership tags are verified before the virtual network is reconciled, as it records the tags of the existing
+	// virtual network.
+	if err := s.reconcileService(ctx, "ownership", s.ownershipSvc); err != nil {
+		return errors.Wrap(err, "failed to reconcile resource ownership")
+	}
+
+	// The runtime extension is called before the network is reconciled for the first time, e.g. to allocate its address
+	// space in an IP address management system.
+	if !s.scope.ServiceProvisioned("virtualnetworks") {
+		if err := s.scope.CallRuntimeHook(ctx, runtimehooks.BeforeClusterNetworkReconcile); err != nil {
+			return errors.Wrap(err, "failed to call runtime extension before reconciling network")
+		}
+	}
+
+	// The initial network is deployed at once before the network services reconcile its resources one by one.
+	if err := s.reconcileService(ctx, "deployments", s.networkDeploymentSvc); err != nil {
+		return errors.Wrap(err, "failed to deploy network")
+	}
+
+	if err := s.reconcileService(ctx, "virtualnetworks", s.vnetSvc); err != nil {
 		return errors.Wrap(err, "failed to reconcile virtual network")
 	}
 
-	if err := s.securityGroupSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, "securitygroups", s.securityGroupSvc); err != nil {
 		return errors.Wrap(err, "failed to reconcile network security group")
 	}
 
-	if err := s.routeTableSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, "flowlogs", s.flowLogsSvc); err != nil {
+		return errors.Wrap(err, "failed to reconcile flow logs")
+	}
+
+	if err := s.reconcileService(ctx, "routetables", s.routeTableSvc); err != nil {
 		return errors.Wrap(err, "failed to reconcile route table")
 	}
 
-	if err := s.publicIPSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, "publicips", s.publicIPSvc); err != nil {
 		return errors.Wrap(err, "failed to reconcile public IP")
 	}
 
-	if err := s.natGatewaySvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, "natgateways", s.natGatewaySvc); err != nil {
 		return errors.Wrapf(err, "failed to reconcile nat gateway")
 	}
 
-	if err := s.subnetsSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, "subnets", s.subnetsSvc); err != nil {
 		return errors.Wrapf(err, "failed to reconcile subnet")
 	}
 
-	if err := s.peeringsSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, "privateendpoints", s.privateEndpointsSvc); err != nil {
+		return errors.Wrap(err, "failed to reconcile private endpoints")
+	}
+
+	if err := s.reconcileService(ctx, "vnetpeerings", s.peeringsSvc); err != nil {
 		return errors.Wrap(err, "failed to reconcile peerings")
 	}
 
+	// The runtime extension is notified once, when the load balancers are reconciled successfully for the first time.
 	if err := s.loadBalancerSvc.Reconcile(ctx); err != nil {
 		return errors.Wrap(err, "failed to reconcile load balancer")
 	}
+	if !s.scope.ServiceProvisioned("loadbalancers") {
+		if err := s.scope.CallRuntimeHook(ctx, runtimehooks.AfterControlPlaneLBReady); err != nil {
+			return errors.Wrap(err, "failed to call runtime extension after reconciling load balancer")
+		}
+	}
+	s.scope.SetServiceProvisioned("loadbalancers")
+
+	if err := s.reconcileService(ctx, "privatelinkservices", s.privateLinkSvc); err != nil {
+		return errors.Wrap(err, "failed to reconcile private link service")
+	}
 
-	if err := s.privateDNSSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, "privatedns", s.privateDNSSvc); err != nil {
 		return errors.Wrap(err, "failed to reconcile private dns")
 	}
 
-	if err := s.bastionSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, "publicdns", s.publicDNSSvc); err != nil {
+		return errors.Wrap(err, "failed to reconcile public dns")
+	}
+
+	if err := s.reconcileService(ctx, "bastionhosts", s.bastionSvc); err != nil {
 		return errors.Wrap(err, "failed to reconcile bastion")
 	}
 
-	if err := s.tagsSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, "virtualnetworkgateways", s.expressRouteGatewaySvc); err != nil {
+		return errors.Wrap(err, "failed to reconcile ExpressRoute gateway")
+	}
+
+	if err := s.reconcileService(ctx, "azurefirewalls", s.firewallSvc); err != nil {
+		return errors.Wrap(err, "failed to reconcile Azure Firewall")
+	}
+
+	if err := s.reconcileService(ctx, "snatmetrics", s.snatMetricsSvc); err != nil {
+		return errors.Wrap(err, "failed to reconcile SNAT metrics")
+	}
+
+	if err := s.reconcileService(ctx, "diskencryptionsets", s.diskEncryptionSetsSvc); err != nil {
+		return errors.Wrap(err, "failed to reconcile disk encryption sets")
+	}
+
+	if err := s.reconcileService(ctx, "proximityplacementgroups", s.proximityPlacementGroupsSvc); err != nil {
+		return errors.Wrap(err, "failed to reconcile proximity placement group")
+	}
+
+	// Spare network interfaces are only claimed by the machines of their MachineDeployment, so the pools of deleted
+	// MachineDeployments are deleted by the cluster.
+	if err := s.nicPoolsSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "failed to delete unused network interface pools")
+	}
+
+	if err := s.reconcileService(ctx, "alerts", s.alertsSvc); err != nil {
+		return errors.Wrap(err, "failed to reconcile alert rules")
+	}
+
+	if err := s.reconcileService(ctx, "tags", s.tagsSvc); err != nil {
 		return errors.Wrap(err, "unable to update tags")
 	}
 
+	// The template is exported last, so that it reflects the tags and resources of the current reconcile.
+	if err := s.templatesSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "failed to export template")
+	}
+
+	return nil
+}
+
+// reconcileService reconciles a service, recording how long it took to be reconciled successfully the first time.
+func (s *azureClusterService) reconcileService(ctx context.Context, name string, svc azure.Reconciler) error {
+	if err := svc.Reconcile(ctx); err != nil {
+		return err
+	}
+
+	s.scope.SetServiceProvisioned(name)
 	return nil
 }
 
@@ -152,16 +290,71 @@ func (s *azureClusterService) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Delete")
 	defer done()
 
-	if err := s.groupsSvc.Delete(ctx); err != nil {
+	// Resources which lost their ownership tag are not deleted, so the tag is restored first when possible.
+	if err := s.ownershipSvc.Delete(ctx); err != nil {
+		return errors.Wrap(err, "failed to verify resource ownership")
+	}
+
+	if err := s.snatMetricsSvc.Delete(ctx); err != nil {
+		return errors.Wrap(err, "failed to delete SNAT metrics")
+	}
+
+	// Flow logs live in the resource group of the Network Watcher, so they are not deleted with the resource group.
+	if err := s.flowLogsSvc.Delete(ctx); err != nil {
+		return errors.Wrap(err, "failed to delete flow logs")
+	}
+
+	// The record of the API server lives in a DNS zone outside of the cluster, so it is not deleted with the resource group.
+	if err := s.publicDNSSvc.Delete(ctx); err != nil {
+		return errors.Wrap(err, "failed to delete public dns")
+	}
+
+	// Disk encryption sets are granted access to key vaults outside of the cluster, which must be revoked explicitly.
+	if err := s.diskEncryptionSetsSvc.Delete(ctx); err != nil {
+		return errors.Wrap(err, "failed to delete disk encryption sets")
+	}
+
+	// Retained public IPs live in the resource group, so it is kept and its other resources are deleted one by one.
+	var err error
+	if s.scope.RetainPublicIPsOnDelete() {
+		err = azure.ErrNotOwned
+	} else {
+		err = s.groupsSvc.Delete(ctx)
+	}
+	if err != nil {
 		if errors.Is(err, azure.ErrNotOwned) {
+			if err := s.alertsSvc.Delete(ctx); err != nil {
+				return errors.Wrap(err, "failed to delete alert rules")
+			}
+
+			if err := s.proximityPlacementGroupsSvc.Delete(ctx); err != nil {
+				return errors.Wrap(err, "failed to delete proximity placement group")
+			}
+
 			if err := s.bastionSvc.Delete(ctx); err != nil {
 				return errors.Wrap(err, "failed to delete bastion")
 			}
 
+			if err := s.expressRouteGatewaySvc.Delete(ctx); err != nil {
+				return errors.Wrap(err, "failed to delete ExpressRoute gateway")
+			}
+
+			if err := s.firewallSvc.Delete(ctx); err != nil {
+				return errors.Wrap(err, "failed to delete Azure Firewall")
+			}
+
 			if err := s.privateDNSSvc.Delete(ctx); err != nil {
 				return errors.Wrap(err, "failed to delete private dns")
 			}
 
+			if err := s.privateLinkSvc.Delete(ctx); err != nil {
+				return errors.Wrap(err, "failed to delete private link service")
+			}
+
+			if err := s.nicPoolsSvc.Delete(ctx); err != nil {
+				return errors.Wrap(err, "failed to delete network interface pools")
+			}
+
 			if err := s.loadBalancerSvc.Delete(ctx); err != nil {
 				return errors.Wrap(err, "failed to delete load balancer")
 			}
@@ -170,6 +363,10 @@ func (s *azureClusterService) Delete(ctx context.Context) error {
 				return errors.Wrap(err, "failed to delete peerings")
 			}
 
+			if err := s.privateEndpointsSvc.Delete(ctx); err != nil {
+				return errors.Wrap(err, "failed to delete private endpoints")
+			}
+
 			if err := s.subnetsSvc.Delete(ctx); err != nil {
 				return errors.Wrap(err, "failed to delete subnet")
 			}
@@ -209,9 +406,12 @@ func (s *azureClusterService) setFailureDomainsForLocation(ctx context.Context)
 		return errors.Wrapf(err, "failed to get zones for location %s", s.scope.Location())
 	}
 
+	// A cordoned zone stays a failure domain, as the machines of MachineDeployments and MachinePools pinned to it stay
+	// there. It is excluded from the placement of control plane machines here, and from the zones CAPZ picks for new
+	// machines without a failure domain and new scale sets spread across all zones by their scopes.
 	for _, zone := range zones {
 		s.scope.SetFailureDomain(zone, clusterv1.FailureDomainSpec{
-			ControlPlane: true,
+			ControlPlane: !s.scope.IsZoneCordoned(zone),
 		})
 	}
 
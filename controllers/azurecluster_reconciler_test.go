@@ -32,36 +32,89 @@ import (
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
-type expect func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder)
+type expect func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder)
 
 func TestAzureClusterReconcilerDelete(t *testing.T) {
 	cases := map[string]struct {
-		expectedError string
-		expect        expect
+		expectedError   string
+		retainPublicIPs bool
+		expect          expect
 	}{
 		"Resource Group is deleted successfully": {
 			expectedError: "",
-			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder) {
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
 				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()),
+					des.Delete(gomockinternal.AContext()),
 					grp.Delete(gomockinternal.AContext()).Return(nil))
 			},
 		},
 		"Resource Group delete fails": {
 			expectedError: "failed to delete resource group: internal error",
-			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder) {
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
 				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()),
+					des.Delete(gomockinternal.AContext()),
 					grp.Delete(gomockinternal.AContext()).Return(errors.New("internal error")))
 			},
 		},
 		"Resource Group not owned by cluster": {
 			expectedError: "",
-			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder) {
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
 				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()),
+					des.Delete(gomockinternal.AContext()),
 					grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned),
+					alerts.Delete(gomockinternal.AContext()),
+					ppg.Delete(gomockinternal.AContext()),
 					bastion.Delete(gomockinternal.AContext()),
+					ergw.Delete(gomockinternal.AContext()),
+					azfw.Delete(gomockinternal.AContext()),
 					dns.Delete(gomockinternal.AContext()),
+					pls.Delete(gomockinternal.AContext()),
+					nicpools.Delete(gomockinternal.AContext()),
 					lb.Delete(gomockinternal.AContext()),
 					peer.Delete(gomockinternal.AContext()),
+					pe.Delete(gomockinternal.AContext()),
+					sn.Delete(gomockinternal.AContext()),
+					natg.Delete(gomockinternal.AContext()),
+					pip.Delete(gomockinternal.AContext()),
+					rt.Delete(gomockinternal.AContext()),
+					sg.Delete(gomockinternal.AContext()),
+					vnet.Delete(gomockinternal.AContext()),
+				)
+			},
+		},
+		"Resource Group is kept when public IPs are retained": {
+			expectedError:   "",
+			retainPublicIPs: true,
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
+				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()),
+					des.Delete(gomockinternal.AContext()),
+					alerts.Delete(gomockinternal.AContext()),
+					ppg.Delete(gomockinternal.AContext()),
+					bastion.Delete(gomockinternal.AContext()),
+					ergw.Delete(gomockinternal.AContext()),
+					azfw.Delete(gomockinternal.AContext()),
+					dns.Delete(gomockinternal.AContext()),
+					pls.Delete(gomockinternal.AContext()),
+					nicpools.Delete(gomockinternal.AContext()),
+					lb.Delete(gomockinternal.AContext()),
+					peer.Delete(gomockinternal.AContext()),
+					pe.Delete(gomockinternal.AContext()),
 					sn.Delete(gomockinternal.AContext()),
 					natg.Delete(gomockinternal.AContext()),
 					pip.Delete(gomockinternal.AContext()),
@@ -73,24 +126,47 @@ func TestAzureClusterReconcilerDelete(t *testing.T) {
 		},
 		"Load Balancer delete fails": {
 			expectedError: "failed to delete load balancer: some error happened",
-			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder) {
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
 				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()),
+					des.Delete(gomockinternal.AContext()),
 					grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned),
+					alerts.Delete(gomockinternal.AContext()),
+					ppg.Delete(gomockinternal.AContext()),
 					bastion.Delete(gomockinternal.AContext()),
+					ergw.Delete(gomockinternal.AContext()),
+					azfw.Delete(gomockinternal.AContext()),
 					dns.Delete(gomockinternal.AContext()),
+					pls.Delete(gomockinternal.AContext()),
+					nicpools.Delete(gomockinternal.AContext()),
 					lb.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
 				)
 			},
 		},
 		"Route table delete fails": {
 			expectedError: "failed to delete route table: some error happened",
-			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder) {
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
 				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()),
+					des.Delete(gomockinternal.AContext()),
 					grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned),
+					alerts.Delete(gomockinternal.AContext()),
+					ppg.Delete(gomockinternal.AContext()),
 					bastion.Delete(gomockinternal.AContext()),
+					ergw.Delete(gomockinternal.AContext()),
+					azfw.Delete(gomockinternal.AContext()),
 					dns.Delete(gomockinternal.AContext()),
+					pls.Delete(gomockinternal.AContext()),
+					nicpools.Delete(gomockinternal.AContext()),
 					lb.Delete(gomockinternal.AContext()),
 					peer.Delete(gomockinternal.AContext()),
+					pe.Delete(gomockinternal.AContext()),
 					sn.Delete(gomockinternal.AContext()),
 					pip.Delete(gomockinternal.AContext()),
 					natg.Delete(gomockinternal.AContext()),
@@ -98,6 +174,129 @@ func TestAzureClusterReconcilerDelete(t *testing.T) {
 				)
 			},
 		},
+		"Private endpoints delete fails": {
+			expectedError: "failed to delete private endpoints: some error happened",
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
+				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()),
+					des.Delete(gomockinternal.AContext()),
+					grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned),
+					alerts.Delete(gomockinternal.AContext()),
+					ppg.Delete(gomockinternal.AContext()),
+					bastion.Delete(gomockinternal.AContext()),
+					ergw.Delete(gomockinternal.AContext()),
+					azfw.Delete(gomockinternal.AContext()),
+					dns.Delete(gomockinternal.AContext()),
+					pls.Delete(gomockinternal.AContext()),
+					nicpools.Delete(gomockinternal.AContext()),
+					lb.Delete(gomockinternal.AContext()),
+					peer.Delete(gomockinternal.AContext()),
+					pe.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
+				)
+			},
+		},
+		"Private link service delete fails": {
+			expectedError: "failed to delete private link service: some error happened",
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
+				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()),
+					des.Delete(gomockinternal.AContext()),
+					grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned),
+					alerts.Delete(gomockinternal.AContext()),
+					ppg.Delete(gomockinternal.AContext()),
+					bastion.Delete(gomockinternal.AContext()),
+					ergw.Delete(gomockinternal.AContext()),
+					azfw.Delete(gomockinternal.AContext()),
+					dns.Delete(gomockinternal.AContext()),
+					pls.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
+				)
+			},
+		},
+		"Alert rules delete fails": {
+			expectedError: "failed to delete alert rules: some error happened",
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
+				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()),
+					des.Delete(gomockinternal.AContext()),
+					grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned),
+					alerts.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
+				)
+			},
+		},
+		"Proximity placement group delete fails": {
+			expectedError: "failed to delete proximity placement group: some error happened",
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
+				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()),
+					des.Delete(gomockinternal.AContext()),
+					grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned),
+					alerts.Delete(gomockinternal.AContext()),
+					ppg.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
+				)
+			},
+		},
+		"Resource ownership verification fails": {
+			expectedError: "failed to verify resource ownership: some error happened",
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
+				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
+				)
+			},
+		},
+		"Disk encryption sets delete fails": {
+			expectedError: "failed to delete disk encryption sets: some error happened",
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
+				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()),
+					des.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
+				)
+			},
+		},
+		"SNAT metrics delete fails": {
+			expectedError: "failed to delete SNAT metrics: some error happened",
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
+				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
+				)
+			},
+		},
+		"Flow logs delete fails": {
+			expectedError: "failed to delete flow logs: some error happened",
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
+				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
+				)
+			},
+		},
+		"Public DNS delete fails": {
+			expectedError: "failed to delete public dns: some error happened",
+			expect: func(grp *mock_azure.MockReconcilerMockRecorder, vnet *mock_azure.MockReconcilerMockRecorder, sg *mock_azure.MockReconcilerMockRecorder, rt *mock_azure.MockReconcilerMockRecorder, sn *mock_azure.MockReconcilerMockRecorder, natg *mock_azure.MockReconcilerMockRecorder, pip *mock_azure.MockReconcilerMockRecorder, lb *mock_azure.MockReconcilerMockRecorder, dns *mock_azure.MockReconcilerMockRecorder, bastion *mock_azure.MockReconcilerMockRecorder, peer *mock_azure.MockReconcilerMockRecorder, flowlogs *mock_azure.MockReconcilerMockRecorder, pe *mock_azure.MockReconcilerMockRecorder, pls *mock_azure.MockReconcilerMockRecorder, ergw *mock_azure.MockReconcilerMockRecorder, azfw *mock_azure.MockReconcilerMockRecorder, publicdns *mock_azure.MockReconcilerMockRecorder, ownership *mock_azure.MockReconcilerMockRecorder, snatmetrics *mock_azure.MockReconcilerMockRecorder, alerts *mock_azure.MockReconcilerMockRecorder, des *mock_azure.MockReconcilerMockRecorder, ppg *mock_azure.MockReconcilerMockRecorder, nicpools *mock_azure.MockReconcilerMockRecorder) {
+				gomock.InOrder(
+					ownership.Delete(gomockinternal.AContext()),
+					snatmetrics.Delete(gomockinternal.AContext()),
+					flowlogs.Delete(gomockinternal.AContext()),
+					publicdns.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
+				)
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -119,25 +318,55 @@ func TestAzureClusterReconcilerDelete(t *testing.T) {
 			dnsMock := mock_azure.NewMockReconciler(mockCtrl)
 			bastionMock := mock_azure.NewMockReconciler(mockCtrl)
 			peeringsMock := mock_azure.NewMockReconciler(mockCtrl)
+			flowLogsMock := mock_azure.NewMockReconciler(mockCtrl)
+			privateEndpointsMock := mock_azure.NewMockReconciler(mockCtrl)
+			privateLinkMock := mock_azure.NewMockReconciler(mockCtrl)
+			expressRouteGatewayMock := mock_azure.NewMockReconciler(mockCtrl)
+			firewallMock := mock_azure.NewMockReconciler(mockCtrl)
+			publicDNSMock := mock_azure.NewMockReconciler(mockCtrl)
+			ownershipMock := mock_azure.NewMockReconciler(mockCtrl)
+			snatMetricsMock := mock_azure.NewMockReconciler(mockCtrl)
+			alertsMock := mock_azure.NewMockReconciler(mockCtrl)
+			desMock := mock_azure.NewMockReconciler(mockCtrl)
+			ppgMock := mock_azure.NewMockReconciler(mockCtrl)
+			nicPoolsMock := mock_azure.NewMockReconciler(mockCtrl)
 
-			tc.expect(groupsMock.EXPECT(), vnetMock.EXPECT(), sgMock.EXPECT(), rtMock.EXPECT(), subnetsMock.EXPECT(), natGatewaysMock.EXPECT(), publicIPMock.EXPECT(), lbMock.EXPECT(), dnsMock.EXPECT(), bastionMock.EXPECT(), peeringsMock.EXPECT())
+			tc.expect(groupsMock.EXPECT(), vnetMock.EXPECT(), sgMock.EXPECT(), rtMock.EXPECT(), subnetsMock.EXPECT(), natGatewaysMock.EXPECT(), publicIPMock.EXPECT(), lbMock.EXPECT(), dnsMock.EXPECT(), bastionMock.EXPECT(), peeringsMock.EXPECT(), flowLogsMock.EXPECT(), privateEndpointsMock.EXPECT(), privateLinkMock.EXPECT(), expressRouteGatewayMock.EXPECT(), firewallMock.EXPECT(), publicDNSMock.EXPECT(), ownershipMock.EXPECT(), snatMetricsMock.EXPECT(), alertsMock.EXPECT(), desMock.EXPECT(), ppgMock.EXPECT(), nicPoolsMock.EXPECT())
 
 			s := &azureClusterService{
 				scope: &scope.ClusterScope{
-					AzureCluster: &infrav1.AzureCluster{},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							NetworkSpec: infrav1.NetworkSpec{
+								RetainPublicIPsOnDelete: tc.retainPublicIPs,
+							},
+						},
+					},
 				},
-				groupsSvc:        groupsMock,
-				vnetSvc:          vnetMock,
-				securityGroupSvc: sgMock,
-				routeTableSvc:    rtMock,
-				natGatewaySvc:    natGatewaysMock,
-				subnetsSvc:       subnetsMock,
-				publicIPSvc:      publicIPMock,
-				loadBalancerSvc:  lbMock,
-				privateDNSSvc:    dnsMock,
-				bastionSvc:       bastionMock,
-				peeringsSvc:      peeringsMock,
-				skuCache:         resourceskus.NewStaticCache([]compute.ResourceSku{}, ""),
+				groupsSvc:                   groupsMock,
+				ownershipSvc:                ownershipMock,
+				vnetSvc:                     vnetMock,
+				securityGroupSvc:            sgMock,
+				routeTableSvc:               rtMock,
+				natGatewaySvc:               natGatewaysMock,
+				subnetsSvc:                  subnetsMock,
+				publicIPSvc:                 publicIPMock,
+				loadBalancerSvc:             lbMock,
+				privateDNSSvc:               dnsMock,
+				publicDNSSvc:                publicDNSMock,
+				bastionSvc:                  bastionMock,
+				peeringsSvc:                 peeringsMock,
+				flowLogsSvc:                 flowLogsMock,
+				privateEndpointsSvc:         privateEndpointsMock,
+				privateLinkSvc:              privateLinkMock,
+				expressRouteGatewaySvc:      expressRouteGatewayMock,
+				firewallSvc:                 firewallMock,
+				snatMetricsSvc:              snatMetricsMock,
+				alertsSvc:                   alertsMock,
+				diskEncryptionSetsSvc:       desMock,
+				proximityPlacementGroupsSvc: ppgMock,
+				nicPoolsSvc:                 nicPoolsMock,
+				skuCache:                    resourceskus.NewStaticCache([]compute.ResourceSku{}, ""),
 			}
 
 			err := s.Delete(context.TODO())
@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/runtimehooks"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -51,6 +52,11 @@ type AzureMachineReconciler struct {
 	Recorder                  record.EventRecorder
 	ReconcileTimeout          time.Duration
 	WatchFilterValue          string
+	resyncPeriods             reconciler.ResyncPeriods
+	imageReplication          azure.ImageReplicationMode
+	acceptMarketplaceTerms    bool
+	imageResolver             azure.ImageResolver
+	runtimeHooks              runtimehooks.Caller
 	createAzureMachineService azureMachineServiceCreator
 }
 
@@ -78,6 +84,11 @@ func (amr *AzureMachineReconciler) SetupWithManager(ctx context.Context, mgr ctr
 	)
 	defer done()
 
+	amr.resyncPeriods = options.ResyncPeriods
+	amr.imageReplication = options.ImageReplication
+	amr.acceptMarketplaceTerms = options.AcceptMarketplaceTerms
+	amr.imageResolver = options.ImageResolver
+	amr.runtimeHooks = options.RuntimeHooks
 	var r reconcile.Reconciler = amr
 	if options.Cache != nil {
 		r = coalescing.NewReconciler(amr, options.Cache, log)
@@ -210,10 +221,17 @@ func (amr *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	// Create the machine scope
 	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
-		Client:       amr.Client,
-		Machine:      machine,
-		AzureMachine: azureMachine,
-		ClusterScope: clusterScope,
+		Client:                    amr.Client,
+		Machine:                   machine,
+		AzureMachine:              azureMachine,
+		ClusterScope:              clusterScope,
+		ForceDelete:               clusterScope.ForceDeleteVirtualMachines(),
+		ImageReplication:          amr.imageReplication,
+		AcceptMarketplaceTerms:    amr.acceptMarketplaceTerms,
+		ImageResolver:             amr.imageResolver,
+		RuntimeHooks:              amr.runtimeHooks,
+		CordonedZones:             clusterScope.CordonedZoneNames(),
+		ProximityPlacementGroupID: clusterScope.ProximityPlacementGroupID(),
 	})
 	if err != nil {
 		amr.Recorder.Eventf(azureMachine, corev1.EventTypeWarning, "Error creating the machine scope", err.Error())
@@ -274,6 +292,16 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 		return reconcile.Result{}, errors.Wrap(err, "failed to init machine scope cache")
 	}
 
+	// Make sure a new VM has time to join the cluster before its bootstrap token expires. The bootstrap provider keeps
+	// refreshing the token until the infrastructure is ready, so wait for it instead of creating a VM which would fail to
+	// join silently.
+	if machineScope.ProviderID() == "" && amr.bootstrapTokenExpiring(ctx, machineScope, clusterScope) {
+		log.Info("Bootstrap token is about to expire, waiting for it to be refreshed")
+		conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.BootstrapTokenExpiringReason, clusterv1.ConditionSeverityWarning,
+			"bootstrap token expires in less than %s, waiting for the bootstrap provider to refresh it", BootstrapTokenExpiryMargin)
+		return reconcile.Result{RequeueAfter: BootstrapTokenRequeueAfter}, nil
+	}
+
 	ams, err := amr.createAzureMachineService(machineScope)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create azure machine service")
@@ -319,7 +347,27 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 
 	machineScope.SetReady()
 
-	return reconcile.Result{}, nil
+	provisioning := !clusterScope.Cluster.Status.ControlPlaneReady || machineScope.Machine.Status.NodeRef == nil
+	return reconcile.Result{RequeueAfter: amr.resyncPeriods.RequeueAfter(machineScope.AzureMachine, provisioning)}, nil
+}
+
+// bootstrapTokenExpiring reports whether the bootstrap token of the machine expires too soon to create its VM. Failures
+// to check the token are logged and don't block the VM creation.
+func (amr *AzureMachineReconciler) bootstrapTokenExpiring(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) bool {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachineReconciler.bootstrapTokenExpiring")
+	defer done()
+
+	bootstrapData, err := machineScope.GetBootstrapData(ctx)
+	if err != nil {
+		log.V(2).Info("failed to get bootstrap data to check its bootstrap token", "error", err.Error())
+		return false
+	}
+	expiring, err := BootstrapTokenExpiring(ctx, amr.Client, util.ObjectKey(clusterScope.Cluster), bootstrapData)
+	if err != nil {
+		log.V(2).Info("failed to check the bootstrap token expiration", "error", err.Error())
+		return false
+	}
+	return expiring
 }
 
 func (amr *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
@@ -332,6 +380,20 @@ func (amr *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineS
 		return reconcile.Result{}, err
 	}
 
+	// The runtime extension can delay the deletion, e.g. to deregister the machine from systems outside of Azure. Once
+	// it allowed the deletion, it is not called again while the resources of the machine are deleted.
+	if _, ok := machineScope.AzureMachine.Annotations[runtimehooks.OkToDeleteAnnotation]; !ok {
+		if err := machineScope.CallRuntimeHook(ctx, runtimehooks.BeforeMachineDelete); err != nil {
+			var reconcileError azure.ReconcileError
+			if errors.As(err, &reconcileError) && reconcileError.IsTransient() {
+				log.V(2).Info("runtime extension is delaying the deletion of the AzureMachine", "reason", reconcileError.Error())
+				return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+			}
+			return reconcile.Result{}, errors.Wrapf(err, "failed to call runtime extension before deleting AzureMachine %s/%s", machineScope.Namespace(), machineScope.Name())
+		}
+		machineScope.SetAnnotation(runtimehooks.OkToDeleteAnnotation, "")
+	}
+
 	if ShouldDeleteIndividualResources(ctx, clusterScope) {
 		log.Info("Deleting AzureMachine")
 		ams, err := amr.createAzureMachineService(machineScope)
@@ -20,17 +20,23 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/availabilitysets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagereplications"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/marketplaceterms"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/powerstates"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vhdimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -44,10 +50,15 @@ type azureMachineService struct {
 	virtualMachinesSvc   azure.Reconciler
 	roleAssignmentsSvc   azure.Reconciler
 	disksSvc             azure.Reconciler
+	imageReplicationsSvc azure.Reconciler
+	marketplaceTermsSvc  azure.Reconciler
+	vhdImagesSvc         azure.Reconciler
 	publicIPsSvc         azure.Reconciler
 	tagsSvc              azure.Reconciler
 	vmExtensionsSvc      azure.Reconciler
+	bootstrapVerifier    azure.BootstrapVerifier
 	availabilitySetsSvc  azure.Reconciler
+	powerStatesSvc       azure.Reconciler
 	skuCache             *resourceskus.Cache
 }
 
@@ -60,6 +71,7 @@ func newAzureMachineService(machineScope *scope.MachineScope) (*azureMachineServ
 		return nil, errors.Wrap(err, "failed creating a NewCache")
 	}
 
+	vmExtensionsSvc := vmextensions.New(machineScope)
 	return &azureMachineService{
 		scope:                machineScope,
 		inboundNatRulesSvc:   inboundnatrules.New(machineScope),
@@ -67,15 +79,96 @@ func newAzureMachineService(machineScope *scope.MachineScope) (*azureMachineServ
 		virtualMachinesSvc:   virtualmachines.New(machineScope),
 		roleAssignmentsSvc:   roleassignments.New(machineScope),
 		disksSvc:             disks.New(machineScope),
+		imageReplicationsSvc: imagereplications.New(machineScope),
+		marketplaceTermsSvc:  marketplaceterms.New(machineScope),
+		vhdImagesSvc:         vhdimages.New(machineScope),
 		publicIPsSvc:         publicips.New(machineScope),
 		tagsSvc:              tags.New(machineScope),
-		vmExtensionsSvc:      vmextensions.New(machineScope),
+		vmExtensionsSvc:      vmExtensionsSvc,
+		bootstrapVerifier:    vmExtensionsSvc,
 		availabilitySetsSvc:  availabilitysets.New(machineScope, cache),
+		powerStatesSvc:       powerstates.New(machineScope),
 		skuCache:             cache,
 	}, nil
 }
 
-// Reconcile reconciles all the services in a predetermined order.
+// azureMachineStep is a step of a provisioning phase, with the message wrapping its errors.
+type azureMachineStep struct {
+	reconcile func(context.Context) error
+	failure   string
+}
+
+// azureMachinePhase is a provisioning phase of an AzureMachine, completed once all its steps are reconciled.
+type azureMachinePhase struct {
+	name infrav1.AzureMachinePhase
+	// condition reports on the phase, it is left empty when the services of the phase set their own condition.
+	condition clusterv1.ConditionType
+	steps     []azureMachineStep
+	// drift are the steps reconciled on every loop once the phase is completed, to repair the resources changed or
+	// deleted outside of capz. Only the one-shot steps, which verify or prepare the image of the machine, are left out.
+	drift []azureMachineStep
+}
+
+// phases returns the provisioning phases of the machine in order.
+func (s *azureMachineService) phases() []azureMachinePhase {
+	networkSteps := []azureMachineStep{
+		{reconcile: s.publicIPsSvc.Reconcile, failure: "failed to create public IP"},
+		{reconcile: s.inboundNatRulesSvc.Reconcile, failure: "failed to create inbound NAT rule"},
+		{reconcile: s.networkInterfacesSvc.Reconcile, failure: "failed to create network interface"},
+	}
+	return []azureMachinePhase{
+		{
+			name:      infrav1.AzureMachinePhaseNetworkInterfaces,
+			condition: infrav1.NetworkInterfacesReadyCondition,
+			steps:     networkSteps,
+			drift:     networkSteps,
+		},
+		{
+			// The image replications service sets the ImageReplicated condition, the virtual machine service sets the
+			// VMRunning condition.
+			name: infrav1.AzureMachinePhaseVirtualMachine,
+			steps: []azureMachineStep{
+				{reconcile: s.marketplaceTermsSvc.Reconcile, failure: "failed to accept marketplace terms"},
+				{reconcile: s.imageReplicationsSvc.Reconcile, failure: "failed to verify image replication"},
+				{reconcile: s.vhdImagesSvc.Reconcile, failure: "failed to create image from VHD"},
+				{reconcile: s.availabilitySetsSvc.Reconcile, failure: "failed to create availability set"},
+				{reconcile: s.disksSvc.Reconcile, failure: "failed to create shared data disks"},
+				{reconcile: s.virtualMachinesSvc.Reconcile, failure: "failed to create virtual machine"},
+				{reconcile: s.roleAssignmentsSvc.Reconcile, failure: "unable to create role assignment"},
+			},
+			// The virtual machine is reconciled on every loop to keep its state and addresses up to date, and to detect
+			// that it was deleted outside of capz, along with the resources it depends on and its role assignments. The
+			// marketplace terms and the image are only needed to create it.
+			drift: []azureMachineStep{
+				{reconcile: s.availabilitySetsSvc.Reconcile, failure: "failed to create availability set"},
+				{reconcile: s.disksSvc.Reconcile, failure: "failed to create shared data disks"},
+				{reconcile: s.virtualMachinesSvc.Reconcile, failure: "failed to reconcile virtual machine"},
+				{reconcile: s.roleAssignmentsSvc.Reconcile, failure: "unable to create role assignment"},
+			},
+		},
+		{
+			name:      infrav1.AzureMachinePhaseExtensions,
+			condition: infrav1.VMExtensionsReadyCondition,
+			steps: []azureMachineStep{
+				{reconcile: s.vmExtensionsSvc.Reconcile, failure: "unable to create vm extension"},
+			},
+			drift: []azureMachineStep{
+				{reconcile: s.vmExtensionsSvc.Reconcile, failure: "unable to create vm extension"},
+			},
+		},
+		{
+			// The bootstrap verifier reads the state of the bootstrap extension fetched by the VM extensions service
+			// and sets the BootstrapSucceeded condition, returning a transient error until the bootstrap data ran.
+			name: infrav1.AzureMachinePhaseBootstrapVerified,
+			steps: []azureMachineStep{
+				{reconcile: s.bootstrapVerifier.VerifyBootstrap, failure: "unable to verify bootstrap"},
+			},
+		},
+	}
+}
+
+// Reconcile reconciles the drift of the completed provisioning phases of the machine, then the next phases in order,
+// then the services which are reconciled on every loop.
 func (s *azureMachineService) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachineService.Reconcile")
 	defer done()
@@ -84,38 +177,57 @@ func (s *azureMachineService) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed defaulting subnet name")
 	}
 
-	if err := s.publicIPsSvc.Reconcile(ctx); err != nil {
-		return errors.Wrap(err, "failed to create public IP")
-	}
-
-	if err := s.inboundNatRulesSvc.Reconcile(ctx); err != nil {
-		return errors.Wrap(err, "failed to create inbound NAT rule")
+	phases := s.phases()
+	next := 0
+	for i, phase := range phases {
+		if phase.name == s.scope.CompletedPhase() {
+			next = i + 1
+		}
 	}
 
-	if err := s.networkInterfacesSvc.Reconcile(ctx); err != nil {
-		return errors.Wrap(err, "failed to create network interface")
+	for _, phase := range phases[:next] {
+		if len(phase.drift) == 0 {
+			continue
+		}
+		err := reconcileSteps(ctx, phase.drift)
+		if phase.condition != "" {
+			s.scope.UpdatePutStatus(phase.condition, string(phase.name), err)
+		}
+		if err != nil {
+			return err
+		}
 	}
 
-	if err := s.availabilitySetsSvc.Reconcile(ctx); err != nil {
-		return errors.Wrap(err, "failed to create availability set")
+	for _, phase := range phases[next:] {
+		err := reconcileSteps(ctx, phase.steps)
+		if phase.condition != "" {
+			s.scope.UpdatePutStatus(phase.condition, string(phase.name), err)
+		}
+		if err != nil {
+			return err
+		}
+		s.scope.SetCompletedPhase(phase.name)
 	}
 
-	if err := s.virtualMachinesSvc.Reconcile(ctx); err != nil {
-		return errors.Wrap(err, "failed to create virtual machine")
+	if err := s.tagsSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "unable to update tags")
 	}
 
-	if err := s.roleAssignmentsSvc.Reconcile(ctx); err != nil {
-		return errors.Wrap(err, "unable to create role assignment")
+	// The power state of the VM is only changed once the machine is provisioned.
+	if err := s.powerStatesSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "failed to reconcile VM power state")
 	}
 
-	if err := s.vmExtensionsSvc.Reconcile(ctx); err != nil {
-		return errors.Wrap(err, "unable to create vm extension")
-	}
+	return nil
+}
 
-	if err := s.tagsSvc.Reconcile(ctx); err != nil {
-		return errors.Wrap(err, "unable to update tags")
+// reconcileSteps reconciles the steps of a provisioning phase in order.
+func reconcileSteps(ctx context.Context, steps []azureMachineStep) error {
+	for _, step := range steps {
+		if err := step.reconcile(ctx); err != nil {
+			return errors.Wrap(err, step.failure)
+		}
 	}
-
 	return nil
 }
 
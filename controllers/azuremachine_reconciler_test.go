@@ -0,0 +1,356 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+type azureMachineServiceMocks struct {
+	pip, nat, nic, avset, terms, image, vhd, disks, vm, role, ext, tags, power *mock_azure.MockReconcilerMockRecorder
+	bootstrap                                                                  *mock_azure.MockBootstrapVerifierMockRecorder
+}
+
+func TestAzureMachineServiceReconcile(t *testing.T) {
+	cases := map[string]struct {
+		completedPhase         infrav1.AzureMachinePhase
+		expect                 func(m azureMachineServiceMocks)
+		expectedError          string
+		expectedCompletedPhase infrav1.AzureMachinePhase
+		expectedConditions     map[clusterv1.ConditionType]corev1.ConditionStatus
+	}{
+		"all the phases are reconciled in order for a new machine": {
+			expect: func(m azureMachineServiceMocks) {
+				gomock.InOrder(
+					m.pip.Reconcile(gomockinternal.AContext()),
+					m.nat.Reconcile(gomockinternal.AContext()),
+					m.nic.Reconcile(gomockinternal.AContext()),
+					m.terms.Reconcile(gomockinternal.AContext()),
+					m.image.Reconcile(gomockinternal.AContext()),
+					m.vhd.Reconcile(gomockinternal.AContext()),
+					m.avset.Reconcile(gomockinternal.AContext()),
+					m.disks.Reconcile(gomockinternal.AContext()),
+					m.vm.Reconcile(gomockinternal.AContext()),
+					m.role.Reconcile(gomockinternal.AContext()),
+					m.ext.Reconcile(gomockinternal.AContext()),
+					m.bootstrap.VerifyBootstrap(gomockinternal.AContext()),
+					m.tags.Reconcile(gomockinternal.AContext()),
+					m.power.Reconcile(gomockinternal.AContext()),
+				)
+			},
+			expectedCompletedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionTrue,
+				infrav1.VMExtensionsReadyCondition:      corev1.ConditionTrue,
+			},
+		},
+		"provisioning resumes after the last completed phase": {
+			completedPhase: infrav1.AzureMachinePhaseVirtualMachine,
+			expect: func(m azureMachineServiceMocks) {
+				gomock.InOrder(
+					m.pip.Reconcile(gomockinternal.AContext()),
+					m.nat.Reconcile(gomockinternal.AContext()),
+					m.nic.Reconcile(gomockinternal.AContext()),
+					m.avset.Reconcile(gomockinternal.AContext()),
+					m.disks.Reconcile(gomockinternal.AContext()),
+					m.vm.Reconcile(gomockinternal.AContext()),
+					m.role.Reconcile(gomockinternal.AContext()),
+					m.ext.Reconcile(gomockinternal.AContext()),
+					m.bootstrap.VerifyBootstrap(gomockinternal.AContext()),
+					m.tags.Reconcile(gomockinternal.AContext()),
+					m.power.Reconcile(gomockinternal.AContext()),
+				)
+			},
+			expectedCompletedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionTrue,
+				infrav1.VMExtensionsReadyCondition:      corev1.ConditionTrue,
+			},
+		},
+		"the drift of the completed phases and the tags are reconciled for a provisioned machine": {
+			completedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expect: func(m azureMachineServiceMocks) {
+				gomock.InOrder(
+					m.pip.Reconcile(gomockinternal.AContext()),
+					m.nat.Reconcile(gomockinternal.AContext()),
+					m.nic.Reconcile(gomockinternal.AContext()),
+					m.avset.Reconcile(gomockinternal.AContext()),
+					m.disks.Reconcile(gomockinternal.AContext()),
+					m.vm.Reconcile(gomockinternal.AContext()),
+					m.role.Reconcile(gomockinternal.AContext()),
+					m.ext.Reconcile(gomockinternal.AContext()),
+					m.tags.Reconcile(gomockinternal.AContext()),
+					m.power.Reconcile(gomockinternal.AContext()),
+				)
+			},
+			expectedCompletedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionTrue,
+				infrav1.VMExtensionsReadyCondition:      corev1.ConditionTrue,
+			},
+		},
+		"extensions and role assignments are repaired for a provisioned machine": {
+			completedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expect: func(m azureMachineServiceMocks) {
+				gomock.InOrder(
+					m.pip.Reconcile(gomockinternal.AContext()),
+					m.nat.Reconcile(gomockinternal.AContext()),
+					m.nic.Reconcile(gomockinternal.AContext()),
+					m.avset.Reconcile(gomockinternal.AContext()),
+					m.disks.Reconcile(gomockinternal.AContext()),
+					m.vm.Reconcile(gomockinternal.AContext()),
+					m.role.Reconcile(gomockinternal.AContext()),
+					m.ext.Reconcile(gomockinternal.AContext()).Return(errors.New("extension failed")),
+				)
+			},
+			expectedError:          "unable to create vm extension: extension failed",
+			expectedCompletedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionTrue,
+				infrav1.VMExtensionsReadyCondition:      corev1.ConditionFalse,
+			},
+		},
+		"power state of a provisioned machine is changing": {
+			completedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expect: func(m azureMachineServiceMocks) {
+				gomock.InOrder(
+					m.pip.Reconcile(gomockinternal.AContext()),
+					m.nat.Reconcile(gomockinternal.AContext()),
+					m.nic.Reconcile(gomockinternal.AContext()),
+					m.avset.Reconcile(gomockinternal.AContext()),
+					m.disks.Reconcile(gomockinternal.AContext()),
+					m.vm.Reconcile(gomockinternal.AContext()),
+					m.role.Reconcile(gomockinternal.AContext()),
+					m.ext.Reconcile(gomockinternal.AContext()),
+					m.tags.Reconcile(gomockinternal.AContext()),
+					m.power.Reconcile(gomockinternal.AContext()).Return(azure.WithTransientError(errors.New("changing power state"), 30*time.Second)),
+				)
+			},
+			expectedError:          "failed to reconcile VM power state: changing power state",
+			expectedCompletedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionTrue,
+				infrav1.VMExtensionsReadyCondition:      corev1.ConditionTrue,
+			},
+		},
+		"virtual machine deleted outside of capz is reported for a provisioned machine": {
+			completedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expect: func(m azureMachineServiceMocks) {
+				m.pip.Reconcile(gomockinternal.AContext())
+				m.nat.Reconcile(gomockinternal.AContext())
+				m.nic.Reconcile(gomockinternal.AContext())
+				m.avset.Reconcile(gomockinternal.AContext())
+				m.disks.Reconcile(gomockinternal.AContext())
+				m.vm.Reconcile(gomockinternal.AContext()).Return(azure.VMDeletedError{ProviderID: "azure:///vm"})
+			},
+			expectedError:          "failed to reconcile virtual machine: VM with provider id \"azure:///vm\" has been deleted",
+			expectedCompletedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionTrue,
+			},
+		},
+		"failed phase is not completed and reports its condition": {
+			expect: func(m azureMachineServiceMocks) {
+				gomock.InOrder(
+					m.pip.Reconcile(gomockinternal.AContext()),
+					m.nat.Reconcile(gomockinternal.AContext()),
+					m.nic.Reconcile(gomockinternal.AContext()).Return(errors.New("internal error")),
+				)
+			},
+			expectedError: "failed to create network interface: internal error",
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionFalse,
+			},
+		},
+		"virtual machine is not created until its image is replicated": {
+			completedPhase: infrav1.AzureMachinePhaseNetworkInterfaces,
+			expect: func(m azureMachineServiceMocks) {
+				m.pip.Reconcile(gomockinternal.AContext())
+				m.nat.Reconcile(gomockinternal.AContext())
+				m.nic.Reconcile(gomockinternal.AContext())
+				m.terms.Reconcile(gomockinternal.AContext())
+				m.image.Reconcile(gomockinternal.AContext()).Return(azure.WithTransientError(errors.New("replicating image version"), time.Minute))
+			},
+			expectedError:          "failed to verify image replication: replicating image version",
+			expectedCompletedPhase: infrav1.AzureMachinePhaseNetworkInterfaces,
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionTrue,
+			},
+		},
+		"virtual machine is not created if the marketplace terms of its image cannot be accepted": {
+			completedPhase: infrav1.AzureMachinePhaseNetworkInterfaces,
+			expect: func(m azureMachineServiceMocks) {
+				m.pip.Reconcile(gomockinternal.AContext())
+				m.nat.Reconcile(gomockinternal.AContext())
+				m.nic.Reconcile(gomockinternal.AContext())
+				m.terms.Reconcile(gomockinternal.AContext()).Return(errors.New("internal error"))
+			},
+			expectedError:          "failed to accept marketplace terms: internal error",
+			expectedCompletedPhase: infrav1.AzureMachinePhaseNetworkInterfaces,
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionTrue,
+			},
+		},
+		"extensions are completed while the bootstrap data is still running": {
+			completedPhase: infrav1.AzureMachinePhaseVirtualMachine,
+			expect: func(m azureMachineServiceMocks) {
+				gomock.InOrder(
+					m.pip.Reconcile(gomockinternal.AContext()),
+					m.nat.Reconcile(gomockinternal.AContext()),
+					m.nic.Reconcile(gomockinternal.AContext()),
+					m.avset.Reconcile(gomockinternal.AContext()),
+					m.disks.Reconcile(gomockinternal.AContext()),
+					m.vm.Reconcile(gomockinternal.AContext()),
+					m.role.Reconcile(gomockinternal.AContext()),
+					m.ext.Reconcile(gomockinternal.AContext()),
+					m.bootstrap.VerifyBootstrap(gomockinternal.AContext()).Return(azure.WithTransientError(errors.New("extension is still in provisioning state"), 30*time.Second)),
+				)
+			},
+			expectedError:          "unable to verify bootstrap: extension is still in provisioning state",
+			expectedCompletedPhase: infrav1.AzureMachinePhaseExtensions,
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionTrue,
+				infrav1.VMExtensionsReadyCondition:      corev1.ConditionTrue,
+			},
+		},
+		"network interface deleted outside of capz is recreated for a provisioned machine": {
+			completedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expect: func(m azureMachineServiceMocks) {
+				m.pip.Reconcile(gomockinternal.AContext())
+				m.nat.Reconcile(gomockinternal.AContext())
+				m.nic.Reconcile(gomockinternal.AContext()).Return(errors.New("internal error"))
+			},
+			expectedError:          "failed to create network interface: internal error",
+			expectedCompletedPhase: infrav1.AzureMachinePhaseBootstrapVerified,
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionFalse,
+			},
+		},
+		"phase is completed before the failure of the next one": {
+			expect: func(m azureMachineServiceMocks) {
+				gomock.InOrder(
+					m.pip.Reconcile(gomockinternal.AContext()),
+					m.nat.Reconcile(gomockinternal.AContext()),
+					m.nic.Reconcile(gomockinternal.AContext()),
+					m.terms.Reconcile(gomockinternal.AContext()),
+					m.image.Reconcile(gomockinternal.AContext()),
+					m.vhd.Reconcile(gomockinternal.AContext()),
+					m.avset.Reconcile(gomockinternal.AContext()),
+					m.disks.Reconcile(gomockinternal.AContext()),
+					m.vm.Reconcile(gomockinternal.AContext()).Return(azure.WithTransientError(azure.NewOperationNotDoneError(&infrav1.Future{}), 15*time.Second)),
+				)
+			},
+			expectedError:          "failed to create virtual machine",
+			expectedCompletedPhase: infrav1.AzureMachinePhaseNetworkInterfaces,
+			expectedConditions: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				infrav1.NetworkInterfacesReadyCondition: corev1.ConditionTrue,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			pipMock := mock_azure.NewMockReconciler(mockCtrl)
+			natMock := mock_azure.NewMockReconciler(mockCtrl)
+			nicMock := mock_azure.NewMockReconciler(mockCtrl)
+			avsetMock := mock_azure.NewMockReconciler(mockCtrl)
+			termsMock := mock_azure.NewMockReconciler(mockCtrl)
+			imageMock := mock_azure.NewMockReconciler(mockCtrl)
+			vhdMock := mock_azure.NewMockReconciler(mockCtrl)
+			disksMock := mock_azure.NewMockReconciler(mockCtrl)
+			vmMock := mock_azure.NewMockReconciler(mockCtrl)
+			roleMock := mock_azure.NewMockReconciler(mockCtrl)
+			extMock := mock_azure.NewMockReconciler(mockCtrl)
+			tagsMock := mock_azure.NewMockReconciler(mockCtrl)
+			powerMock := mock_azure.NewMockReconciler(mockCtrl)
+			bootstrapMock := mock_azure.NewMockBootstrapVerifier(mockCtrl)
+
+			tc.expect(azureMachineServiceMocks{
+				pip:       pipMock.EXPECT(),
+				nat:       natMock.EXPECT(),
+				nic:       nicMock.EXPECT(),
+				avset:     avsetMock.EXPECT(),
+				terms:     termsMock.EXPECT(),
+				image:     imageMock.EXPECT(),
+				vhd:       vhdMock.EXPECT(),
+				disks:     disksMock.EXPECT(),
+				vm:        vmMock.EXPECT(),
+				role:      roleMock.EXPECT(),
+				ext:       extMock.EXPECT(),
+				tags:      tagsMock.EXPECT(),
+				power:     powerMock.EXPECT(),
+				bootstrap: bootstrapMock.EXPECT(),
+			})
+
+			azureMachine := &infrav1.AzureMachine{
+				Spec:   infrav1.AzureMachineSpec{SubnetName: "node-subnet"},
+				Status: infrav1.AzureMachineStatus{CompletedPhase: tc.completedPhase},
+			}
+			s := &azureMachineService{
+				scope:                &scope.MachineScope{AzureMachine: azureMachine},
+				publicIPsSvc:         pipMock,
+				inboundNatRulesSvc:   natMock,
+				networkInterfacesSvc: nicMock,
+				availabilitySetsSvc:  avsetMock,
+				marketplaceTermsSvc:  termsMock,
+				imageReplicationsSvc: imageMock,
+				vhdImagesSvc:         vhdMock,
+				disksSvc:             disksMock,
+				virtualMachinesSvc:   vmMock,
+				roleAssignmentsSvc:   roleMock,
+				vmExtensionsSvc:      extMock,
+				bootstrapVerifier:    bootstrapMock,
+				tagsSvc:              tagsMock,
+				powerStatesSvc:       powerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(HavePrefix(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(azureMachine.Status.CompletedPhase).To(Equal(tc.expectedCompletedPhase))
+			g.Expect(azureMachine.Status.Conditions).To(HaveLen(len(tc.expectedConditions)))
+			for conditionType, status := range tc.expectedConditions {
+				g.Expect(conditions.Get(azureMachine, conditionType).Status).To(Equal(status))
+			}
+		})
+	}
+}
@@ -18,8 +18,10 @@ package controllers
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -37,9 +39,12 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/runtimehooks"
+	"sigs.k8s.io/cluster-api-provider-azure/util/bootstraptoken"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -50,6 +55,12 @@ import (
 )
 
 const (
+	// BootstrapTokenExpiryMargin is the minimum time the kubeadm bootstrap token of the bootstrap data must stay valid
+	// for a new VM to be created with it, so the VM has time to boot and join the cluster.
+	BootstrapTokenExpiryMargin = 5 * time.Minute
+	// BootstrapTokenRequeueAfter is how long to wait for the bootstrap provider to refresh an expiring bootstrap token.
+	BootstrapTokenRequeueAfter = 30 * time.Second
+
 	spIdentityWarning = "You are using Service Principal authentication for Cloud Provider Azure which is less secure than Managed Identity. " +
 		"Your Service Principal credentials will be written to a file on the disk of each VM in order to be accessible by Cloud Provider. " +
 		"To learn more, see https://capz.sigs.k8s.io/topics/identities-use-cases.html#azure-host-identity "
@@ -62,7 +73,18 @@ type (
 	// Options are controller options extended.
 	Options struct {
 		controller.Options
-		Cache *coalescing.ReconcileCache
+		Cache         *coalescing.ReconcileCache
+		ResyncPeriods reconciler.ResyncPeriods
+		// ImageReplication is how the replication of the gallery image versions of new machines is handled.
+		ImageReplication azure.ImageReplicationMode
+		// AcceptMarketplaceTerms accepts the marketplace terms of the purchase plan of the images of the machines.
+		AcceptMarketplaceTerms bool
+		// ImageResolver resolves the images of the AzureMachines which don't specify one.
+		ImageResolver azure.ImageResolver
+		// RuntimeHooks calls the runtime extension at the provisioning milestones of clusters and machines.
+		RuntimeHooks runtimehooks.Caller
+		// CordonImpactedZones cordons the availability zones of clusters impacted by an Azure service health event.
+		CordonImpactedZones bool
 	}
 )
 
@@ -597,3 +619,26 @@ func GetClusterIdentityFromRef(ctx context.Context, c client.Client, azureCluste
 	}
 	return nil, nil
 }
+
+// BootstrapTokenExpiring reports whether the kubeadm bootstrap token of the base64 encoded bootstrap data expires before
+// a new VM would have time to join the workload cluster. Bootstrap data without a join token, like the one of the first
+// control plane machine, never expires.
+func BootstrapTokenExpiring(ctx context.Context, c client.Client, cluster client.ObjectKey, bootstrapData string) (bool, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.BootstrapTokenExpiring")
+	defer done()
+
+	data, err := base64.StdEncoding.DecodeString(bootstrapData)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to decode bootstrap data")
+	}
+	id, ok := bootstraptoken.IDFromBootstrapData(data)
+	if !ok {
+		return false, nil
+	}
+
+	workloadClient, err := remote.NewClusterClient(ctx, "azure-bootstrap-token", c, cluster)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create the workload cluster client")
+	}
+	return bootstraptoken.ExpiresBefore(ctx, workloadClient, id, time.Now().Add(BootstrapTokenExpiryMargin))
+}
@@ -48,6 +48,38 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	dst.Spec.Template.SubnetName = restored.Spec.Template.SubnetName
+	dst.Spec.Template.UserData = restored.Spec.Template.UserData
+	dst.Spec.Template.ProximityPlacementGroupID = restored.Spec.Template.ProximityPlacementGroupID
+	dst.Spec.Template.CapacityReservationGroupID = restored.Spec.Template.CapacityReservationGroupID
+	dst.Spec.Template.ComputerNamePrefix = restored.Spec.Template.ComputerNamePrefix
+	dst.Spec.Template.Diagnostics = restored.Spec.Template.Diagnostics
+
+	if restored.Spec.Template.SpotVMOptions != nil && dst.Spec.Template.SpotVMOptions != nil {
+		dst.Spec.Template.SpotVMOptions.EvictionPolicy = restored.Spec.Template.SpotVMOptions.EvictionPolicy
+	}
+
+	dst.Spec.ScaleUpBatchSize = restored.Spec.ScaleUpBatchSize
+	dst.Spec.OrchestrationMode = restored.Spec.OrchestrationMode
+	dst.Spec.ScaleInPolicy = restored.Spec.ScaleInPolicy
+	dst.Spec.AutomaticRepairsPolicy = restored.Spec.AutomaticRepairsPolicy
+	dst.Spec.PriorityMixPolicy = restored.Spec.PriorityMixPolicy
+	dst.Spec.ScheduledEventsPolicy = restored.Spec.ScheduledEventsPolicy
+	dst.Spec.ZonePolicy = restored.Spec.ZonePolicy
+	dst.Spec.NodeVolumeDetachTimeout = restored.Spec.NodeVolumeDetachTimeout
+	dst.Spec.RemediationStrategy = restored.Spec.RemediationStrategy
+
+	restoreSecurityProfile(dst.Spec.Template.SecurityProfile, restored.Spec.Template.SecurityProfile)
+	restoreManagedDiskParameters(dst.Spec.Template.OSDisk.ManagedDisk, restored.Spec.Template.OSDisk.ManagedDisk)
+	for i := range dst.Spec.Template.DataDisks {
+		for _, disk := range restored.Spec.Template.DataDisks {
+			if disk.NameSuffix == dst.Spec.Template.DataDisks[i].NameSuffix {
+				dst.Spec.Template.DataDisks[i].DeleteOption = disk.DeleteOption
+				dst.Spec.Template.DataDisks[i].WriteAcceleratorEnabled = disk.WriteAcceleratorEnabled
+				dst.Spec.Template.DataDisks[i].MaxShares = disk.MaxShares
+				restoreManagedDiskParameters(dst.Spec.Template.DataDisks[i].ManagedDisk, disk.ManagedDisk)
+			}
+		}
+	}
 
 	dst.Spec.Strategy.Type = restored.Spec.Strategy.Type
 	if restored.Spec.Strategy.RollingUpdate != nil {
@@ -57,12 +89,16 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 		}
 
 		dst.Spec.Strategy.RollingUpdate.DeletePolicy = restored.Spec.Strategy.RollingUpdate.DeletePolicy
+		dst.Spec.Strategy.RollingUpdate.BatchSettleTime = restored.Spec.Strategy.RollingUpdate.BatchSettleTime
 	}
 
 	if restored.Spec.NodeDrainTimeout != nil {
 		dst.Spec.NodeDrainTimeout = restored.Spec.NodeDrainTimeout
 	}
 
+	dst.Spec.SpotPlacementScoreThreshold = restored.Spec.SpotPlacementScoreThreshold
+	dst.Spec.UpgradePolicy = restored.Spec.UpgradePolicy
+
 	if restored.Status.Image != nil {
 		dst.Status.Image = restored.Status.Image
 	}
@@ -73,6 +109,11 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 		dst.Spec.Template.Image.SharedGallery.SKU = restored.Spec.Template.Image.SharedGallery.SKU
 	}
 
+	if restored.Spec.Template.Image != nil && dst.Spec.Template.Image != nil {
+		dst.Spec.Template.Image.CommunityGallery = restored.Spec.Template.Image.CommunityGallery
+		dst.Spec.Template.Image.VHD = restored.Spec.Template.Image.VHD
+	}
+
 	if len(dst.Annotations) == 0 {
 		dst.Annotations = nil
 	}
@@ -41,8 +41,21 @@ func (src *AzureManagedControlPlane) ConvertTo(dstRaw conversion.Hub) error { //
 	dst.Spec.SKU = restored.Spec.SKU
 	dst.Spec.LoadBalancerProfile = restored.Spec.LoadBalancerProfile
 	dst.Spec.APIServerAccessProfile = restored.Spec.APIServerAccessProfile
+	dst.Spec.ProxyConfig = restored.Spec.ProxyConfig
+	dst.Spec.AutoScalerProfile = restored.Spec.AutoScalerProfile
+	dst.Spec.VirtualNetwork.ResourceGroup = restored.Spec.VirtualNetwork.ResourceGroup
+	dst.Spec.OIDCIssuerProfile = restored.Spec.OIDCIssuerProfile
+	dst.Spec.SecurityProfile = restored.Spec.SecurityProfile
+	dst.Spec.AddonProfiles = restored.Spec.AddonProfiles
+	dst.Spec.NetworkPluginMode = restored.Spec.NetworkPluginMode
+	dst.Spec.NetworkDataplane = restored.Spec.NetworkDataplane
+	dst.Spec.Stopped = restored.Spec.Stopped
+	dst.Spec.DisableLocalAccounts = restored.Spec.DisableLocalAccounts
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
+	dst.Status.SubnetUtilization = restored.Status.SubnetUtilization
+	dst.Status.OIDCIssuerURL = restored.Status.OIDCIssuerURL
+	dst.Status.PowerState = restored.Status.PowerState
 
 	return nil
 }
@@ -72,3 +85,8 @@ func Convert_v1beta1_AzureManagedControlPlaneSpec_To_v1alpha3_AzureManagedContro
 func Convert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha3_AzureManagedControlPlaneStatus(in *expv1beta1.AzureManagedControlPlaneStatus, out *AzureManagedControlPlaneStatus, s apiconversion.Scope) error {
 	return autoConvert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha3_AzureManagedControlPlaneStatus(in, out, s)
 }
+
+// Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork converts a v1beta1 ManagedControlPlaneVirtualNetwork to a v1alpha3 ManagedControlPlaneVirtualNetwork.
+func Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork(in *expv1beta1.ManagedControlPlaneVirtualNetwork, out *ManagedControlPlaneVirtualNetwork, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork(in, out, s)
+}
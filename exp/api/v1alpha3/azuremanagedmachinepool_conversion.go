@@ -40,6 +40,13 @@ func (src *AzureManagedMachinePool) ConvertTo(dstRaw conversion.Hub) error { //
 	dst.Spec.Name = restored.Spec.Name
 	dst.Spec.Scaling = restored.Spec.Scaling
 	dst.Spec.AvailabilityZones = restored.Spec.AvailabilityZones
+	dst.Spec.EnableArtifactStreaming = restored.Spec.EnableArtifactStreaming
+	dst.Spec.NodeLabels = restored.Spec.NodeLabels
+	dst.Spec.Taints = restored.Spec.Taints
+	dst.Spec.ScaleSetPriority = restored.Spec.ScaleSetPriority
+	dst.Spec.ScaleSetEvictionPolicy = restored.Spec.ScaleSetEvictionPolicy
+	dst.Spec.SpotMaxPrice = restored.Spec.SpotMaxPrice
+	dst.Spec.SubnetName = restored.Spec.SubnetName
 
 	return nil
 }
@@ -44,6 +44,36 @@ func Convert_v1beta1_Image_To_v1alpha3_Image(in *v1beta1.Image, out *v1alpha3.Im
 	return v1alpha3.Convert_v1beta1_Image_To_v1alpha3_Image(in, out, s)
 }
 
+// Convert_v1alpha3_SpotVMOptions_To_v1beta1_SpotVMOptions is a conversion function.
+func Convert_v1alpha3_SpotVMOptions_To_v1beta1_SpotVMOptions(in *v1alpha3.SpotVMOptions, out *v1beta1.SpotVMOptions, s conversion.Scope) error {
+	return v1alpha3.Convert_v1alpha3_SpotVMOptions_To_v1beta1_SpotVMOptions(in, out, s)
+}
+
+// Convert_v1beta1_SpotVMOptions_To_v1alpha3_SpotVMOptions is a conversion function.
+func Convert_v1beta1_SpotVMOptions_To_v1alpha3_SpotVMOptions(in *v1beta1.SpotVMOptions, out *v1alpha3.SpotVMOptions, s conversion.Scope) error {
+	return v1alpha3.Convert_v1beta1_SpotVMOptions_To_v1alpha3_SpotVMOptions(in, out, s)
+}
+
+// Convert_v1alpha3_SecurityProfile_To_v1beta1_SecurityProfile is a conversion function.
+func Convert_v1alpha3_SecurityProfile_To_v1beta1_SecurityProfile(in *v1alpha3.SecurityProfile, out *v1beta1.SecurityProfile, s conversion.Scope) error {
+	return v1alpha3.Convert_v1alpha3_SecurityProfile_To_v1beta1_SecurityProfile(in, out, s)
+}
+
+// Convert_v1beta1_SecurityProfile_To_v1alpha3_SecurityProfile is a conversion function.
+func Convert_v1beta1_SecurityProfile_To_v1alpha3_SecurityProfile(in *v1beta1.SecurityProfile, out *v1alpha3.SecurityProfile, s conversion.Scope) error {
+	return v1alpha3.Convert_v1beta1_SecurityProfile_To_v1alpha3_SecurityProfile(in, out, s)
+}
+
+// Convert_v1alpha3_DataDisk_To_v1beta1_DataDisk is a conversion function.
+func Convert_v1alpha3_DataDisk_To_v1beta1_DataDisk(in *v1alpha3.DataDisk, out *v1beta1.DataDisk, s conversion.Scope) error {
+	return v1alpha3.Convert_v1alpha3_DataDisk_To_v1beta1_DataDisk(in, out, s)
+}
+
+// Convert_v1beta1_DataDisk_To_v1alpha3_DataDisk is a conversion function.
+func Convert_v1beta1_DataDisk_To_v1alpha3_DataDisk(in *v1beta1.DataDisk, out *v1alpha3.DataDisk, s conversion.Scope) error {
+	return v1alpha3.Convert_v1beta1_DataDisk_To_v1alpha3_DataDisk(in, out, s)
+}
+
 // Convert_v1alpha3_APIEndpoint_To_v1beta1_APIEndpoint is an autogenerated conversion function.
 func Convert_v1alpha3_APIEndpoint_To_v1beta1_APIEndpoint(in *clusterapiapiv1alpha3.APIEndpoint, out *clusterapiapiv1beta1.APIEndpoint, s conversion.Scope) error {
 	return clusterapiapiv1alpha3.Convert_v1alpha3_APIEndpoint_To_v1beta1_APIEndpoint(in, out, s)
@@ -53,3 +83,28 @@ func Convert_v1alpha3_APIEndpoint_To_v1beta1_APIEndpoint(in *clusterapiapiv1alph
 func Convert_v1beta1_APIEndpoint_To_v1alpha3_APIEndpoint(in *clusterapiapiv1beta1.APIEndpoint, out *clusterapiapiv1alpha3.APIEndpoint, s conversion.Scope) error {
 	return clusterapiapiv1alpha3.Convert_v1beta1_APIEndpoint_To_v1alpha3_APIEndpoint(in, out, s)
 }
+
+// restoreManagedDiskParameters restores the performance tier and the name of the disk encryption set of a managed disk,
+// which are not part of this version.
+func restoreManagedDiskParameters(dst, restored *v1beta1.ManagedDiskParameters) {
+	if dst == nil || restored == nil {
+		return
+	}
+	dst.PerformanceTier = restored.PerformanceTier
+	if restored.DiskEncryptionSet == nil || restored.DiskEncryptionSet.Name == "" {
+		return
+	}
+	if dst.DiskEncryptionSet == nil {
+		dst.DiskEncryptionSet = &v1beta1.DiskEncryptionSetParameters{}
+	}
+	dst.DiskEncryptionSet.Name = restored.DiskEncryptionSet.Name
+}
+
+// restoreSecurityProfile restores the trusted launch settings of a security profile, which are not part of this version.
+func restoreSecurityProfile(dst, restored *v1beta1.SecurityProfile) {
+	if dst == nil || restored == nil {
+		return
+	}
+	dst.SecurityType = restored.SecurityType
+	dst.UefiSettings = restored.UefiSettings
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	expv1beta1 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
@@ -25,12 +27,94 @@ import (
 func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	dst := dstRaw.(*expv1beta1.AzureMachinePool)
 
-	return Convert_v1alpha4_AzureMachinePool_To_v1beta1_AzureMachinePool(src, dst, nil)
+	if err := Convert_v1alpha4_AzureMachinePool_To_v1beta1_AzureMachinePool(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &expv1beta1.AzureMachinePool{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.SpotPlacementScoreThreshold = restored.Spec.SpotPlacementScoreThreshold
+	dst.Spec.UpgradePolicy = restored.Spec.UpgradePolicy
+	dst.Spec.Template.UserData = restored.Spec.Template.UserData
+	dst.Spec.Template.ProximityPlacementGroupID = restored.Spec.Template.ProximityPlacementGroupID
+	dst.Spec.Template.CapacityReservationGroupID = restored.Spec.Template.CapacityReservationGroupID
+	dst.Spec.Template.ComputerNamePrefix = restored.Spec.Template.ComputerNamePrefix
+	dst.Spec.Template.Diagnostics = restored.Spec.Template.Diagnostics
+
+	if restored.Spec.Template.SpotVMOptions != nil && dst.Spec.Template.SpotVMOptions != nil {
+		dst.Spec.Template.SpotVMOptions.EvictionPolicy = restored.Spec.Template.SpotVMOptions.EvictionPolicy
+	}
+
+	if restored.Spec.Template.Image != nil && dst.Spec.Template.Image != nil {
+		dst.Spec.Template.Image.CommunityGallery = restored.Spec.Template.Image.CommunityGallery
+		dst.Spec.Template.Image.VHD = restored.Spec.Template.Image.VHD
+	}
+	if restored.Status.Image != nil && dst.Status.Image != nil {
+		dst.Status.Image.CommunityGallery = restored.Status.Image.CommunityGallery
+		dst.Status.Image.VHD = restored.Status.Image.VHD
+	}
+
+	dst.Spec.ScaleUpBatchSize = restored.Spec.ScaleUpBatchSize
+	dst.Spec.OrchestrationMode = restored.Spec.OrchestrationMode
+	dst.Spec.ScaleInPolicy = restored.Spec.ScaleInPolicy
+	dst.Spec.AutomaticRepairsPolicy = restored.Spec.AutomaticRepairsPolicy
+	dst.Spec.PriorityMixPolicy = restored.Spec.PriorityMixPolicy
+	dst.Spec.ScheduledEventsPolicy = restored.Spec.ScheduledEventsPolicy
+	dst.Spec.ZonePolicy = restored.Spec.ZonePolicy
+	dst.Spec.NodeVolumeDetachTimeout = restored.Spec.NodeVolumeDetachTimeout
+	dst.Spec.RemediationStrategy = restored.Spec.RemediationStrategy
+
+	if restored.Spec.Strategy.RollingUpdate != nil && dst.Spec.Strategy.RollingUpdate != nil {
+		dst.Spec.Strategy.RollingUpdate.BatchSettleTime = restored.Spec.Strategy.RollingUpdate.BatchSettleTime
+	}
+
+	restoreSecurityProfile(dst.Spec.Template.SecurityProfile, restored.Spec.Template.SecurityProfile)
+	restoreManagedDiskParameters(dst.Spec.Template.OSDisk.ManagedDisk, restored.Spec.Template.OSDisk.ManagedDisk)
+	for i := range dst.Spec.Template.DataDisks {
+		for _, disk := range restored.Spec.Template.DataDisks {
+			if disk.NameSuffix == dst.Spec.Template.DataDisks[i].NameSuffix {
+				dst.Spec.Template.DataDisks[i].DeleteOption = disk.DeleteOption
+				dst.Spec.Template.DataDisks[i].WriteAcceleratorEnabled = disk.WriteAcceleratorEnabled
+				dst.Spec.Template.DataDisks[i].MaxShares = disk.MaxShares
+				restoreManagedDiskParameters(dst.Spec.Template.DataDisks[i].ManagedDisk, disk.ManagedDisk)
+			}
+		}
+	}
+
+	return nil
 }
 
 // ConvertFrom converts from the Hub version (v1beta1) to this version.
 func (dst *AzureMachinePool) ConvertFrom(srcRaw conversion.Hub) error { // nolint
 	src := srcRaw.(*expv1beta1.AzureMachinePool)
 
-	return Convert_v1beta1_AzureMachinePool_To_v1alpha4_AzureMachinePool(src, dst, nil)
+	if err := Convert_v1beta1_AzureMachinePool_To_v1alpha4_AzureMachinePool(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion.
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Convert_v1beta1_AzureMachinePoolSpec_To_v1alpha4_AzureMachinePoolSpec is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachinePoolSpec_To_v1alpha4_AzureMachinePoolSpec(in *expv1beta1.AzureMachinePoolSpec, out *AzureMachinePoolSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachinePoolSpec_To_v1alpha4_AzureMachinePoolSpec(in, out, s)
+}
+
+// Convert_v1beta1_MachineRollingUpdateDeployment_To_v1alpha4_MachineRollingUpdateDeployment is an autogenerated conversion function.
+func Convert_v1beta1_MachineRollingUpdateDeployment_To_v1alpha4_MachineRollingUpdateDeployment(in *expv1beta1.MachineRollingUpdateDeployment, out *MachineRollingUpdateDeployment, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_MachineRollingUpdateDeployment_To_v1alpha4_MachineRollingUpdateDeployment(in, out, s)
+}
+
+// Convert_v1beta1_AzureMachinePoolMachineTemplate_To_v1alpha4_AzureMachinePoolMachineTemplate is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachinePoolMachineTemplate_To_v1alpha4_AzureMachinePoolMachineTemplate(in *expv1beta1.AzureMachinePoolMachineTemplate, out *AzureMachinePoolMachineTemplate, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1beta1_AzureMachinePoolMachineTemplate_To_v1alpha4_AzureMachinePoolMachineTemplate(in, out, s)
 }
@@ -19,8 +19,11 @@ package v1beta1
 import (
 	"encoding/base64"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/utils/pointer"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	utilSSH "sigs.k8s.io/cluster-api-provider-azure/util/ssh"
@@ -49,3 +52,97 @@ func (amp *AzureMachinePool) SetIdentityDefaults() {
 		}
 	}
 }
+
+// SetUpgradePolicyDefaults sets the defaults for the VMSS upgrade policy.
+func (amp *AzureMachinePool) SetUpgradePolicyDefaults() {
+	repairs := amp.Spec.AutomaticRepairsPolicy != nil && amp.Spec.AutomaticRepairsPolicy.Enabled
+	if amp.Spec.UpgradePolicy == nil && repairs {
+		amp.Spec.UpgradePolicy = &UpgradePolicy{}
+	}
+	policy := amp.Spec.UpgradePolicy
+	if policy == nil {
+		return
+	}
+	if policy.Mode == "" {
+		policy.Mode = UpgradeModeManual
+	}
+	// Rolling and automatic OS upgrades, as well as automatic repairs, rely on the health of the instances, probe the
+	// kubelet unless told otherwise.
+	if policy.HealthProbe == nil && (policy.Mode == UpgradeModeRolling || policy.AutomaticOSUpgrade || repairs) {
+		policy.HealthProbe = &HealthProbe{}
+	}
+	if probe := policy.HealthProbe; probe != nil {
+		if probe.Protocol == "" {
+			probe.Protocol = HealthProbeProtocolHTTP
+		}
+		if probe.Port == 0 {
+			probe.Port = DefaultHealthProbePort
+		}
+		if probe.RequestPath == "" && probe.Protocol != HealthProbeProtocolTCP {
+			probe.RequestPath = DefaultHealthProbeRequestPath
+		}
+	}
+}
+
+// SetScheduledEventsPolicyDefaults sets the defaults for the handling of scheduled events. Azure only reports the
+// deletion of an instance ahead of time when the terminate notifications of the scale set are enabled.
+func (amp *AzureMachinePool) SetScheduledEventsPolicyDefaults() {
+	policy := amp.Spec.ScheduledEventsPolicy
+	if policy == nil || !policy.Enabled {
+		return
+	}
+	if policy.PollInterval == nil {
+		policy.PollInterval = &metav1.Duration{Duration: DefaultScheduledEventsPollInterval}
+	}
+	if policy.NodeConditionType == "" {
+		policy.NodeConditionType = DefaultScheduledEventsNodeConditionType
+	}
+	if amp.Spec.Template.TerminateNotificationTimeout == nil {
+		amp.Spec.Template.TerminateNotificationTimeout = pointer.IntPtr(DefaultTerminateNotificationTimeout)
+	}
+}
+
+// SetDataDisksDefaults sets the defaults for the data disks of the VMSS instances: unassigned LUNs are filled with the
+// lowest free ones, and host caching is enabled unless the disk doesn't support it.
+func (amp *AzureMachinePool) SetDataDisksDefaults() {
+	dataDisks := amp.Spec.Template.DataDisks
+	set := make(map[int32]struct{})
+	for _, disk := range dataDisks {
+		if disk.Lun != nil {
+			set[*disk.Lun] = struct{}{}
+		}
+	}
+	for i, disk := range dataDisks {
+		if disk.Lun == nil {
+			for l := range dataDisks {
+				lun := int32(l)
+				if _, ok := set[lun]; !ok {
+					dataDisks[i].Lun = &lun
+					set[lun] = struct{}{}
+					break
+				}
+			}
+		}
+		if disk.CachingType == "" {
+			switch {
+			case pointer.BoolDeref(disk.WriteAcceleratorEnabled, false):
+				// Write Accelerator doesn't support caching writes.
+				dataDisks[i].CachingType = string(compute.CachingTypesNone)
+			case disk.ManagedDisk != nil && disk.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS):
+				// Host caching isn't supported on ultra disks.
+				dataDisks[i].CachingType = string(compute.CachingTypesNone)
+			default:
+				dataDisks[i].CachingType = string(compute.CachingTypesReadWrite)
+			}
+		}
+	}
+}
+
+// SetOSDiskDefaults sets the defaults for the OS disk of the VMSS instances. Ephemeral OS disks only support read-only
+// host caching.
+func (amp *AzureMachinePool) SetOSDiskDefaults() {
+	osDisk := &amp.Spec.Template.OSDisk
+	if osDisk.DiffDiskSettings != nil && osDisk.CachingType == "" {
+		osDisk.CachingType = string(compute.CachingTypesReadOnly)
+	}
+}
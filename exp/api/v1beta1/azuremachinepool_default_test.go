@@ -18,9 +18,12 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/uuid"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
@@ -77,6 +80,205 @@ func TestAzureMachinePool_SetIdentityDefaults(t *testing.T) {
 	g.Expect(notSystemAssignedTest.machinePool.Spec.RoleAssignmentName).To(BeEmpty())
 }
 
+func TestAzureMachinePool_SetUpgradePolicyDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *UpgradePolicy
+		repairs *AutomaticRepairsPolicy
+		want    *UpgradePolicy
+	}{
+		{
+			name:   "no upgrade policy",
+			policy: nil,
+			want:   nil,
+		},
+		{
+			name:   "manual mode has no health probe",
+			policy: &UpgradePolicy{},
+			want:   &UpgradePolicy{Mode: UpgradeModeManual},
+		},
+		{
+			name:   "rolling mode probes the kubelet",
+			policy: &UpgradePolicy{Mode: UpgradeModeRolling},
+			want: &UpgradePolicy{
+				Mode:        UpgradeModeRolling,
+				HealthProbe: &HealthProbe{Protocol: HealthProbeProtocolHTTP, Port: DefaultHealthProbePort, RequestPath: DefaultHealthProbeRequestPath},
+			},
+		},
+		{
+			name:   "automatic OS upgrade probes the kubelet",
+			policy: &UpgradePolicy{Mode: UpgradeModeAutomatic, AutomaticOSUpgrade: true},
+			want: &UpgradePolicy{
+				Mode:               UpgradeModeAutomatic,
+				AutomaticOSUpgrade: true,
+				HealthProbe:        &HealthProbe{Protocol: HealthProbeProtocolHTTP, Port: DefaultHealthProbePort, RequestPath: DefaultHealthProbeRequestPath},
+			},
+		},
+		{
+			name:   "tcp health probe has no request path",
+			policy: &UpgradePolicy{Mode: UpgradeModeRolling, HealthProbe: &HealthProbe{Protocol: HealthProbeProtocolTCP, Port: 10250}},
+			want:   &UpgradePolicy{Mode: UpgradeModeRolling, HealthProbe: &HealthProbe{Protocol: HealthProbeProtocolTCP, Port: 10250}},
+		},
+		{
+			name:    "automatic repairs probe the kubelet",
+			policy:  nil,
+			repairs: &AutomaticRepairsPolicy{Enabled: true},
+			want: &UpgradePolicy{
+				Mode:        UpgradeModeManual,
+				HealthProbe: &HealthProbe{Protocol: HealthProbeProtocolHTTP, Port: DefaultHealthProbePort, RequestPath: DefaultHealthProbeRequestPath},
+			},
+		},
+		{
+			name:    "disabled automatic repairs don't need a health probe",
+			policy:  nil,
+			repairs: &AutomaticRepairsPolicy{},
+			want:    nil,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{Spec: AzureMachinePoolSpec{UpgradePolicy: tc.policy, AutomaticRepairsPolicy: tc.repairs}}
+			amp.SetUpgradePolicyDefaults()
+			g.Expect(amp.Spec.UpgradePolicy).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestAzureMachinePool_SetScheduledEventsPolicyDefaults(t *testing.T) {
+	tests := []struct {
+		name                string
+		policy              *ScheduledEventsPolicy
+		notificationTimeout *int
+		want                *ScheduledEventsPolicy
+		wantTimeout         *int
+	}{
+		{
+			name:   "no scheduled events policy",
+			policy: nil,
+			want:   nil,
+		},
+		{
+			name:   "disabled policy is left untouched",
+			policy: &ScheduledEventsPolicy{},
+			want:   &ScheduledEventsPolicy{},
+		},
+		{
+			name:        "enabled policy polls the default node condition every minute and enables terminate notifications",
+			policy:      &ScheduledEventsPolicy{Enabled: true},
+			want:        &ScheduledEventsPolicy{Enabled: true, PollInterval: &metav1.Duration{Duration: time.Minute}, NodeConditionType: DefaultScheduledEventsNodeConditionType},
+			wantTimeout: to.IntPtr(DefaultTerminateNotificationTimeout),
+		},
+		{
+			name:                "enabled policy keeps its node condition and the terminate notification timeout",
+			policy:              &ScheduledEventsPolicy{Enabled: true, PollInterval: &metav1.Duration{Duration: 30 * time.Second}, NodeConditionType: "ScheduledEvent"},
+			notificationTimeout: to.IntPtr(10),
+			want:                &ScheduledEventsPolicy{Enabled: true, PollInterval: &metav1.Duration{Duration: 30 * time.Second}, NodeConditionType: "ScheduledEvent"},
+			wantTimeout:         to.IntPtr(10),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{Spec: AzureMachinePoolSpec{
+				ScheduledEventsPolicy: tc.policy,
+				Template:              AzureMachinePoolMachineTemplate{TerminateNotificationTimeout: tc.notificationTimeout},
+			}}
+			amp.SetScheduledEventsPolicyDefaults()
+			g.Expect(amp.Spec.ScheduledEventsPolicy).To(Equal(tc.want))
+			g.Expect(amp.Spec.Template.TerminateNotificationTimeout).To(Equal(tc.wantTimeout))
+		})
+	}
+}
+
+func TestAzureMachinePool_SetDataDisksDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		dataDisks []infrav1.DataDisk
+		want      []infrav1.DataDisk
+	}{
+		{
+			name:      "no data disks",
+			dataDisks: nil,
+			want:      nil,
+		},
+		{
+			name: "unassigned LUNs take the lowest free ones",
+			dataDisks: []infrav1.DataDisk{
+				{NameSuffix: "etcd", DiskSizeGB: 256},
+				{NameSuffix: "logs", DiskSizeGB: 64, Lun: to.Int32Ptr(0)},
+				{NameSuffix: "data", DiskSizeGB: 128},
+			},
+			want: []infrav1.DataDisk{
+				{NameSuffix: "etcd", DiskSizeGB: 256, Lun: to.Int32Ptr(1), CachingType: "ReadWrite"},
+				{NameSuffix: "logs", DiskSizeGB: 64, Lun: to.Int32Ptr(0), CachingType: "ReadWrite"},
+				{NameSuffix: "data", DiskSizeGB: 128, Lun: to.Int32Ptr(2), CachingType: "ReadWrite"},
+			},
+		},
+		{
+			name: "host caching is disabled on write accelerated and ultra disks",
+			dataDisks: []infrav1.DataDisk{
+				{NameSuffix: "log", DiskSizeGB: 256, Lun: to.Int32Ptr(0), WriteAcceleratorEnabled: to.BoolPtr(true)},
+				{NameSuffix: "ultra", DiskSizeGB: 1024, Lun: to.Int32Ptr(1), ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "UltraSSD_LRS"}},
+				{NameSuffix: "cached", DiskSizeGB: 128, Lun: to.Int32Ptr(2), CachingType: "ReadOnly"},
+			},
+			want: []infrav1.DataDisk{
+				{NameSuffix: "log", DiskSizeGB: 256, Lun: to.Int32Ptr(0), WriteAcceleratorEnabled: to.BoolPtr(true), CachingType: "None"},
+				{NameSuffix: "ultra", DiskSizeGB: 1024, Lun: to.Int32Ptr(1), ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "UltraSSD_LRS"}, CachingType: "None"},
+				{NameSuffix: "cached", DiskSizeGB: 128, Lun: to.Int32Ptr(2), CachingType: "ReadOnly"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{Spec: AzureMachinePoolSpec{
+				Template: AzureMachinePoolMachineTemplate{DataDisks: tc.dataDisks},
+			}}
+			amp.SetDataDisksDefaults()
+			g.Expect(amp.Spec.Template.DataDisks).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestAzureMachinePool_SetOSDiskDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		osDisk infrav1.OSDisk
+		want   infrav1.OSDisk
+	}{
+		{
+			name:   "managed OS disk is left untouched",
+			osDisk: infrav1.OSDisk{OSType: "Linux"},
+			want:   infrav1.OSDisk{OSType: "Linux"},
+		},
+		{
+			name:   "ephemeral OS disk defaults to read-only caching",
+			osDisk: infrav1.OSDisk{OSType: "Linux", DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"}},
+			want:   infrav1.OSDisk{OSType: "Linux", CachingType: "ReadOnly", DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"}},
+		},
+		{
+			name:   "ephemeral OS disk keeps its caching type",
+			osDisk: infrav1.OSDisk{OSType: "Linux", CachingType: "None", DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"}},
+			want:   infrav1.OSDisk{OSType: "Linux", CachingType: "None", DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"}},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{Spec: AzureMachinePoolSpec{
+				Template: AzureMachinePoolMachineTemplate{OSDisk: tc.osDisk},
+			}}
+			amp.SetOSDiskDefaults()
+			g.Expect(amp.Spec.Template.OSDisk).To(Equal(tc.want))
+		})
+	}
+}
+
 func createMachinePoolWithSSHPublicKey(sshPublicKey string) *AzureMachinePool {
 	return hardcodedAzureMachinePoolWithSSHKey(sshPublicKey)
 }
@@ -77,7 +77,49 @@ func TestAzureMachinePool_Validate(t *testing.T) {
 			},
 			Expect: func(g *gomega.GomegaWithT, actual error) {
 				g.Expect(actual).To(gomega.HaveOccurred())
-				g.Expect(actual.Error()).To(gomega.ContainSubstring("You must supply a ID, Marketplace or SharedGallery image details"))
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("You must supply a ID, Marketplace, SharedGallery, CommunityGallery or VHD image details"))
+			},
+		},
+		{
+			Name: "HasCommunityGalleryImage",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						Template: exp.AzureMachinePoolMachineTemplate{
+							Image: &infrav1.Image{
+								CommunityGallery: &infrav1.AzureCommunityGalleryImage{
+									Gallery: "ClusterAPI-f72ceb4f-5159-4c26-a0fe-2ea738f0d019",
+									Name:    "capi-ubun2-2004",
+									Version: "1.23.9",
+								},
+							},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("CommunityGallery images are not supported by machine pools"))
+			},
+		},
+		{
+			Name: "HasVHDImage",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						Template: exp.AzureMachinePoolMachineTemplate{
+							Image: &infrav1.Image{
+								VHD: &infrav1.AzureVHDImage{
+									URI: "https://myaccount.blob.core.windows.net/vhds/image.vhd",
+								},
+							},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("VHD images are not supported by machine pools"))
 			},
 		},
 		{
@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -40,6 +42,64 @@ const (
 	NewestDeletePolicyType AzureMachinePoolDeletePolicyType = "Newest"
 	// RandomDeletePolicyType will delete machines in random order.
 	RandomDeletePolicyType AzureMachinePoolDeletePolicyType = "Random"
+
+	// SpotPlacementScoreHigh means a Spot allocation request is highly likely to succeed.
+	SpotPlacementScoreHigh SpotPlacementScore = "High"
+	// SpotPlacementScoreMedium means a Spot allocation request is moderately likely to succeed.
+	SpotPlacementScoreMedium SpotPlacementScore = "Medium"
+	// SpotPlacementScoreLow means a Spot allocation request is unlikely to succeed.
+	SpotPlacementScoreLow SpotPlacementScore = "Low"
+
+	// UpgradeModeManual leaves the instances of the scale set on their model until they are replaced by CAPZ following
+	// the deployment strategy of the AzureMachinePool.
+	UpgradeModeManual UpgradeMode = "Manual"
+	// UpgradeModeAutomatic lets Azure upgrade all the instances of the scale set at the same time when its model changes.
+	UpgradeModeAutomatic UpgradeMode = "Automatic"
+	// UpgradeModeRolling lets Azure upgrade the instances of the scale set in batches when its model changes.
+	UpgradeModeRolling UpgradeMode = "Rolling"
+
+	// UniformOrchestrationMode manages the instances of the scale set as identical scale set VMs.
+	UniformOrchestrationMode OrchestrationModeType = "Uniform"
+	// FlexibleOrchestrationMode manages the instances of the scale set as standard VMs.
+	FlexibleOrchestrationMode OrchestrationModeType = "Flexible"
+
+	// ScaleInRuleDefault balances the scale set across availability zones and fault domains, then removes the newest
+	// instances.
+	ScaleInRuleDefault ScaleInRule = "Default"
+	// ScaleInRuleNewestVM balances the scale set across availability zones, then removes the newest instances.
+	ScaleInRuleNewestVM ScaleInRule = "NewestVM"
+	// ScaleInRuleOldestVM balances the scale set across availability zones, then removes the oldest instances.
+	ScaleInRuleOldestVM ScaleInRule = "OldestVM"
+
+	// HealthProbeProtocolHTTP probes the health of an instance with an HTTP request.
+	HealthProbeProtocolHTTP HealthProbeProtocol = "http"
+	// HealthProbeProtocolHTTPS probes the health of an instance with an HTTPS request.
+	HealthProbeProtocolHTTPS HealthProbeProtocol = "https"
+	// HealthProbeProtocolTCP probes the health of an instance by opening a TCP connection.
+	HealthProbeProtocolTCP HealthProbeProtocol = "tcp"
+
+	// DeleteRemediationStrategyType deletes failed instances, which are replaced following the deployment strategy.
+	DeleteRemediationStrategyType RemediationStrategyType = "Delete"
+	// ReimageRemediationStrategyType reimages failed instances in place.
+	ReimageRemediationStrategyType RemediationStrategyType = "Reimage"
+	// RedeployRemediationStrategyType redeploys failed instances in place to another host.
+	RedeployRemediationStrategyType RemediationStrategyType = "Redeploy"
+
+	// DefaultHealthProbePort is the port of the kubelet health endpoint probed by default.
+	DefaultHealthProbePort = 10248
+	// DefaultHealthProbeRequestPath is the path of the kubelet health endpoint probed by default.
+	DefaultHealthProbeRequestPath = "/healthz"
+
+	// DefaultScaleUpBatchSize is the default maximum number of instances added to a scale set at once.
+	DefaultScaleUpBatchSize = 100
+
+	// DefaultScheduledEventsPollInterval is how often the nodes of a pool are checked for scheduled events by default.
+	DefaultScheduledEventsPollInterval = time.Minute
+	// DefaultScheduledEventsNodeConditionType is the type of the node condition reporting scheduled events by default.
+	DefaultScheduledEventsNodeConditionType = "VMEventScheduled"
+	// DefaultTerminateNotificationTimeout is the terminate notification timeout, in minutes, of the scale sets which
+	// handle scheduled events without setting one.
+	DefaultTerminateNotificationTimeout = 5
 )
 
 type (
@@ -88,6 +148,32 @@ type (
 		// SubnetName selects the Subnet where the VMSS will be placed
 		// +optional
 		SubnetName string `json:"subnetName,omitempty"`
+
+		// ProximityPlacementGroupID is the Azure resource ID of an existing proximity placement group the VMSS is placed
+		// in. The VMSS is placed in the proximity placement group of the cluster if it is not set and the cluster has one.
+		// +optional
+		ProximityPlacementGroupID *string `json:"proximityPlacementGroupID,omitempty"`
+
+		// ComputerNamePrefix is the prefix of the computer names, i.e. the hostnames, of the VMSS instances, to which
+		// Azure appends the 6 characters of the base-36 index of each instance. The prefix can be up to 9 characters long
+		// on Windows and 58 on Linux. The computer names are prefixed with the VMSS name if it is not set.
+		// +optional
+		ComputerNamePrefix string `json:"computerNamePrefix,omitempty"`
+
+		// CapacityReservationGroupID is the Azure resource ID of a capacity reservation group the VMSS instances are
+		// allocated from, so that they consume capacity reserved in advance. It can't be used with Spot VMs.
+		// +optional
+		CapacityReservationGroupID *string `json:"capacityReservationGroupID,omitempty"`
+
+		// UserData references a Secret holding the user data of the VMSS instances. The user data is available to the
+		// instances from the instance metadata service and, unlike the bootstrap data, can be changed without reimaging them.
+		// +optional
+		UserData *infrav1.UserDataSource `json:"userData,omitempty"`
+
+		// Diagnostics configures the boot diagnostics of the VMSS instances, which the serial console requires. Boot
+		// diagnostics are enabled with a storage account managed by Azure if it is not set.
+		// +optional
+		Diagnostics *infrav1.Diagnostics `json:"diagnostics,omitempty"`
 	}
 
 	// AzureMachinePoolSpec defines the desired state of AzureMachinePool.
@@ -144,8 +230,269 @@ type (
 		// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
 		// +optional
 		NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+		// NodeVolumeDetachTimeout is the total amount of time that the controller will spend on waiting for all volumes
+		// to be detached from a drained node before deleting its instance.
+		// The default value is 0, meaning that the volumes can be detached without any time limitations.
+		// +optional
+		NodeVolumeDetachTimeout *metav1.Duration `json:"nodeVolumeDetachTimeout,omitempty"`
+
+		// SpotPlacementScoreThreshold is the minimum Spot Placement Score required to create a scale set using Spot VMs.
+		// The score is looked up before the scale set is created and reported in the SpotPlacementScoreReady condition.
+		// If the score reported by Azure is below the threshold, creation of the scale set is held back until the score
+		// improves. When omitted, the score is reported but never blocks creation.
+		// +kubebuilder:validation:Enum=High;Medium;Low
+		// +optional
+		SpotPlacementScoreThreshold *SpotPlacementScore `json:"spotPlacementScoreThreshold,omitempty"`
+
+		// UpgradePolicy defines how Azure brings the instances of the Virtual Machine Scale Set up to date with its latest
+		// model. When omitted, the instances are only replaced by CAPZ following the deployment strategy.
+		// +optional
+		UpgradePolicy *UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+		// ScaleUpBatchSize is the maximum number of instances added to the Virtual Machine Scale Set at once. Larger scale
+		// ups are split into batches, each created with the bootstrap data current when it starts, so that the instances
+		// of the last batches don't join the cluster with a bootstrap token which expired while the first ones were being
+		// created. Defaults to 100.
+		// +kubebuilder:validation:Minimum=1
+		// +optional
+		ScaleUpBatchSize *int32 `json:"scaleUpBatchSize,omitempty"`
+
+		// OrchestrationMode is the orchestration mode of the Virtual Machine Scale Set. With Uniform, the instances are
+		// identical scale set VMs. With Flexible, the instances are standard VMs spread across fault domains, which are
+		// managed individually and can't be upgraded in place by Azure. Immutable.
+		// +kubebuilder:validation:Enum=Uniform;Flexible
+		// +kubebuilder:default=Uniform
+		// +optional
+		OrchestrationMode OrchestrationModeType `json:"orchestrationMode,omitempty"`
+
+		// ScaleInPolicy defines which instances Azure removes when the capacity of the Virtual Machine Scale Set is
+		// lowered, and whether the instances removed from the scale set are force deleted.
+		// +optional
+		ScaleInPolicy *ScaleInPolicy `json:"scaleInPolicy,omitempty"`
+
+		// AutomaticRepairsPolicy lets Azure replace the instances of the Virtual Machine Scale Set reported unhealthy by the
+		// health probe of the upgrade policy, without waiting for a MachineHealthCheck to remediate their machines.
+		// +optional
+		AutomaticRepairsPolicy *AutomaticRepairsPolicy `json:"automaticRepairsPolicy,omitempty"`
+
+		// PriorityMixPolicy blends regular and Spot VMs in the Virtual Machine Scale Set. It requires the Flexible
+		// orchestration mode and Spot VM options in the template, which apply to the Spot VMs of the mix. Immutable.
+		// +optional
+		PriorityMixPolicy *PriorityMixPolicy `json:"priorityMixPolicy,omitempty"`
+
+		// ScheduledEventsPolicy cordons and drains the nodes of the instances Azure is about to evict or to perform
+		// maintenance on.
+		// +optional
+		ScheduledEventsPolicy *ScheduledEventsPolicy `json:"scheduledEventsPolicy,omitempty"`
+
+		// ZonePolicy defines the availability zones of the Virtual Machine Scale Set and how its instances are spread
+		// across zones and fault domains. Immutable.
+		// +optional
+		ZonePolicy *ZonePolicy `json:"zonePolicy,omitempty"`
+
+		// RemediationStrategy defines how the AzureMachinePoolMachines whose instance failed are remediated. When
+		// omitted, they are deleted and replaced following the deployment strategy.
+		// +optional
+		RemediationStrategy *RemediationStrategy `json:"remediationStrategy,omitempty"`
+	}
+
+	// RemediationStrategy describes how the failed instances of a Virtual Machine Scale Set are remediated.
+	RemediationStrategy struct {
+		// Type is how a failed instance is remediated. Delete replaces it with a new instance. Reimage restores its OS
+		// disk from the image of the scale set model, and Redeploy moves it to another host keeping its disks; both are
+		// much faster than creating a new instance from a large image. An instance is remediated in place once, until it
+		// is ready again, and deleted if it fails again in the meantime. Reimage requires the Uniform orchestration mode.
+		// +kubebuilder:validation:Enum=Delete;Reimage;Redeploy
+		// +kubebuilder:default=Delete
+		// +optional
+		Type RemediationStrategyType `json:"type,omitempty"`
+	}
+
+	// RemediationStrategyType is the way the failed instances of a Virtual Machine Scale Set are remediated.
+	RemediationStrategyType string
+
+	// ZonePolicy describes the placement of the instances of a Virtual Machine Scale Set across availability zones and
+	// fault domains.
+	ZonePolicy struct {
+		// Zones pins the scale set to these availability zones, taking precedence over the failure domains of the
+		// MachinePool. Mutually exclusive with AllZones.
+		// +optional
+		Zones []string `json:"zones,omitempty"`
+
+		// AllZones spreads the scale set across all the availability zones of its location which offer its VM size, and
+		// balances its instances evenly across them unless ZoneBalance is false. Mutually exclusive with Zones.
+		// +optional
+		AllZones bool `json:"allZones,omitempty"`
+
+		// ZoneBalance strictly balances the instances across the zones of the scale set: Azure fails a scale out rather
+		// than leaving the zones unbalanced. It requires at least 2 zones.
+		// +optional
+		ZoneBalance *bool `json:"zoneBalance,omitempty"`
+
+		// PlatformFaultDomainCount is the number of fault domains the instances of each zone, or of the scale set if it
+		// isn't zonal, are spread across. Flexible scale sets spread across availability zones require 1.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=5
+		// +optional
+		PlatformFaultDomainCount *int32 `json:"platformFaultDomainCount,omitempty"`
+	}
+
+	// ScheduledEventsPolicy describes how the instances of a Virtual Machine Scale Set react to the events Azure schedules
+	// for them. The events are only served to the instances themselves, by the Scheduled Events endpoint of the Instance
+	// Metadata Service, so they must be reported by an agent running on the nodes as a node condition.
+	ScheduledEventsPolicy struct {
+		// Enabled cordons and drains the node of an instance when its node condition reports an event scheduled by Azure,
+		// such as a Spot eviction or a planned maintenance, and uncordons the node once the event is over. It enables the
+		// terminate notifications of the scale set when the template doesn't set a terminate notification timeout.
+		// +optional
+		Enabled bool `json:"enabled,omitempty"`
+
+		// PollInterval is how often the nodes of the pool are checked for events. It must be at least 10 seconds.
+		// Defaults to 1 minute.
+		// +optional
+		PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+
+		// NodeConditionType is the type of the node condition reporting the events scheduled for the instance of the
+		// node. The condition is True while an event is scheduled, and its reason is the type of the event, e.g.
+		// Preempt, Terminate, Reboot or Redeploy. Freeze events are ignored. Defaults to VMEventScheduled.
+		// +optional
+		NodeConditionType string `json:"nodeConditionType,omitempty"`
+	}
+
+	// PriorityMixPolicy describes the mix of regular and Spot VMs of a Virtual Machine Scale Set.
+	PriorityMixPolicy struct {
+		// BaseRegularPriorityCount is the number of regular VMs created before any Spot VM. Defaults to 0 in Azure.
+		// +kubebuilder:validation:Minimum=0
+		// +optional
+		BaseRegularPriorityCount *int32 `json:"baseRegularPriorityCount,omitempty"`
+
+		// RegularPriorityPercentageAboveBase is the percentage of regular VMs among the VMs created above the base
+		// regular count, the others being Spot VMs. Defaults to 50 in Azure.
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		RegularPriorityPercentageAboveBase *int32 `json:"regularPriorityPercentageAboveBase,omitempty"`
+	}
+
+	// AutomaticRepairsPolicy describes the automatic repairs of the unhealthy instances of a Virtual Machine Scale Set.
+	AutomaticRepairsPolicy struct {
+		// Enabled enables automatic repairs. The health of the instances is reported by the Application Health extension,
+		// which probes the kubelet health endpoint unless the upgrade policy specifies another health probe.
+		// +optional
+		Enabled bool `json:"enabled,omitempty"`
+
+		// GracePeriod is the time automatic repairs are suspended after the state of an instance changed, e.g. after it
+		// was created or restarted, giving it time to become healthy. It must be between 30 and 90 minutes, and is
+		// rounded down to the minute. Defaults to 30 minutes in Azure.
+		// +optional
+		GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
 	}
 
+	// ScaleInRule is the rule Azure follows to choose the instances removed when a Virtual Machine Scale Set is scaled in.
+	ScaleInRule string
+
+	// ScaleInPolicy describes how the instances of a Virtual Machine Scale Set are removed when it is scaled in.
+	ScaleInPolicy struct {
+		// Rule is the rule Azure follows to choose the instances to remove. With Default, the scale set is balanced
+		// across availability zones and fault domains, then the newest instances are removed. With NewestVM and OldestVM,
+		// the scale set is balanced across availability zones, then respectively the newest or the oldest instances are
+		// removed.
+		// +kubebuilder:validation:Enum=Default;NewestVM;OldestVM
+		// +kubebuilder:default=Default
+		// +optional
+		Rule ScaleInRule `json:"rule,omitempty"`
+
+		// ForceDeletion force deletes the instances removed from the scale set, which releases their resources faster but
+		// doesn't let them shut down gracefully. Force deletion falls back to a normal deletion where it isn't available.
+		// +optional
+		ForceDeletion bool `json:"forceDeletion,omitempty"`
+	}
+
+	// OrchestrationModeType is the orchestration mode of a Virtual Machine Scale Set.
+	OrchestrationModeType string
+
+	// UpgradeMode is the upgrade mode of a Virtual Machine Scale Set.
+	UpgradeMode string
+
+	// HealthProbeProtocol is the protocol used to probe the health of the instances of a Virtual Machine Scale Set.
+	HealthProbeProtocol string
+
+	// UpgradePolicy describes how the instances of a Virtual Machine Scale Set are upgraded.
+	UpgradePolicy struct {
+		// Mode is the upgrade mode of the scale set. With Manual, the instances are replaced by CAPZ following the
+		// deployment strategy of the AzureMachinePool. With Automatic and Rolling, Azure upgrades the instances in place,
+		// respectively all at once or in batches, and CAPZ no longer replaces the instances running an older model.
+		// +kubebuilder:validation:Enum=Manual;Automatic;Rolling
+		// +kubebuilder:default=Manual
+		// +optional
+		Mode UpgradeMode `json:"mode,omitempty"`
+
+		// RollingUpgrade configures the batches of a Rolling upgrade. It can only be set with the Rolling mode.
+		// +optional
+		RollingUpgrade *RollingUpgradePolicy `json:"rollingUpgrade,omitempty"`
+
+		// AutomaticOSUpgrade enables automatic OS image upgrades: Azure upgrades the instances in batches when a new
+		// version of their image is published. The image must reference its latest version.
+		// +optional
+		AutomaticOSUpgrade bool `json:"automaticOSUpgrade,omitempty"`
+
+		// HealthProbe configures the Application Health extension Azure uses to check the health of the instances
+		// during Rolling and automatic OS upgrades. Defaults to the kubelet health endpoint with these upgrades.
+		// +optional
+		HealthProbe *HealthProbe `json:"healthProbe,omitempty"`
+	}
+
+	// RollingUpgradePolicy describes the batches of a Rolling upgrade of a Virtual Machine Scale Set.
+	RollingUpgradePolicy struct {
+		// MaxBatchInstancePercent is the maximum percentage of the instances upgraded at the same time.
+		// Defaults to 20 in Azure.
+		// +kubebuilder:validation:Minimum=5
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		MaxBatchInstancePercent *int32 `json:"maxBatchInstancePercent,omitempty"`
+
+		// MaxUnhealthyInstancePercent is the maximum percentage of unhealthy instances, upgraded or not, before the
+		// upgrade is aborted. Defaults to 20 in Azure.
+		// +kubebuilder:validation:Minimum=5
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		MaxUnhealthyInstancePercent *int32 `json:"maxUnhealthyInstancePercent,omitempty"`
+
+		// MaxUnhealthyUpgradedInstancePercent is the maximum percentage of upgraded instances found unhealthy before the
+		// upgrade is aborted. Defaults to 20 in Azure.
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		MaxUnhealthyUpgradedInstancePercent *int32 `json:"maxUnhealthyUpgradedInstancePercent,omitempty"`
+
+		// PauseTimeBetweenBatches is the time to wait between two batches. Defaults to 0 in Azure.
+		// +optional
+		PauseTimeBetweenBatches *metav1.Duration `json:"pauseTimeBetweenBatches,omitempty"`
+	}
+
+	// HealthProbe describes the endpoint probed on each instance to know whether it is healthy.
+	HealthProbe struct {
+		// Protocol used to probe the endpoint.
+		// +kubebuilder:validation:Enum=http;https;tcp
+		// +kubebuilder:default=http
+		// +optional
+		Protocol HealthProbeProtocol `json:"protocol,omitempty"`
+
+		// Port of the endpoint. Defaults to 10248, the port of the kubelet health endpoint.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=65535
+		// +optional
+		Port int32 `json:"port,omitempty"`
+
+		// RequestPath is the path of the endpoint probed with http and https. Defaults to /healthz.
+		// +optional
+		RequestPath string `json:"requestPath,omitempty"`
+	}
+
+	// SpotPlacementScore is the likelihood reported by Azure that a Spot VM allocation request will succeed.
+	SpotPlacementScore string
+
 	// AzureMachinePoolDeploymentStrategyType is the type of deployment strategy employed to rollout a new version of
 	// the AzureMachinePool.
 	AzureMachinePoolDeploymentStrategyType string
@@ -211,6 +558,14 @@ type (
 		// +kubebuilder:validation:Enum=Random;Newest;Oldest
 		// +kubebuilder:default:=Oldest
 		DeletePolicy AzureMachinePoolDeletePolicyType `json:"deletePolicy,omitempty"`
+
+		// BatchSettleTime is the time to wait after a batch of machines running the latest model was created before
+		// deleting the next batch of machines running an outdated model. It gives workloads time to settle on the new
+		// machines during large rollouts. Unlike the PauseTimeBetweenBatches of the rolling upgrade policy, which Azure
+		// applies when it upgrades the instances in place, it paces the deletion of the machines by CAPZ.
+		// Defaults to 0.
+		// +optional
+		BatchSettleTime *metav1.Duration `json:"batchSettleTime,omitempty"`
 	}
 
 	// AzureMachinePoolStatus defines the observed state of AzureMachinePool.
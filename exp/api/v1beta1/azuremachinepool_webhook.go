@@ -20,7 +20,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -48,9 +51,16 @@ func (amp *AzureMachinePool) Default() {
 		ctrl.Log.WithName("AzureMachinePoolLogger").Error(err, "SetDefaultSshPublicKey failed")
 	}
 	amp.SetIdentityDefaults()
+	amp.SetUpgradePolicyDefaults()
+	amp.SetScheduledEventsPolicyDefaults()
+	amp.SetDataDisksDefaults()
+	amp.SetOSDiskDefaults()
+	amp.Spec.Template.SecurityProfile.SetDefaults()
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachinepool,mutating=false,failurePolicy=fail,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepools,versions=v1beta1,name=validation.azuremachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// The support of encryption at host by the VM size is validated by another webhook with access to Azure, see the encryptionathost package.
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachinepool-encryptionathost,mutating=false,failurePolicy=fail,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepools,versions=v1beta1,name=encryptionathost.azuremachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 var _ webhook.Validator = &AzureMachinePool{}
 
@@ -78,6 +88,24 @@ func (amp *AzureMachinePool) Validate(old runtime.Object) error {
 		amp.ValidateUserAssignedIdentity,
 		amp.ValidateStrategy(),
 		amp.ValidateSystemAssignedIdentity(old),
+		amp.ValidateUpgradePolicy,
+		amp.ValidateSpotVMOptions,
+		amp.ValidateSecurityProfile,
+		amp.ValidateProximityPlacementGroupID(old),
+		amp.ValidateCapacityReservationGroupID(old),
+		amp.ValidateComputerNamePrefix(old),
+		amp.ValidateEphemeralOSDisk(old),
+		amp.ValidateDataDisks,
+		amp.ValidateWriteAccelerator,
+		amp.ValidateSharedDataDisks,
+		amp.ValidatePerformanceTiers,
+		amp.ValidateOrchestrationMode(old),
+		amp.ValidateAutomaticRepairsPolicy,
+		amp.ValidatePriorityMixPolicy(old),
+		amp.ValidateScheduledEventsPolicy,
+		amp.ValidateZonePolicy(old),
+		amp.ValidateRemediationStrategy,
+		amp.ValidateDiagnostics,
 	}
 
 	var errs []error
@@ -98,6 +126,12 @@ func (amp *AzureMachinePool) ValidateImage() error {
 			agg := kerrors.NewAggregate(errs.ToAggregate().Errors())
 			return agg
 		}
+		if image.CommunityGallery != nil {
+			return field.Forbidden(field.NewPath("image", "CommunityGallery"), "CommunityGallery images are not supported by machine pools")
+		}
+		if image.VHD != nil {
+			return field.Forbidden(field.NewPath("image", "VHD"), "VHD images are not supported by machine pools")
+		}
 	}
 
 	return nil
@@ -142,6 +176,294 @@ func (amp *AzureMachinePool) ValidateUserAssignedIdentity() error {
 	return nil
 }
 
+// ValidateSpotVMOptions validates the Spot VM options.
+func (amp *AzureMachinePool) ValidateSpotVMOptions() error {
+	fldPath := field.NewPath("spec", "template", "spotVMOptions")
+	if errs := infrav1.ValidateSpotVMOptions(amp.Spec.Template.SpotVMOptions, amp.Spec.Template.OSDisk, fldPath); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateEphemeralOSDisk validates the ephemeral OS disk settings, which can't be changed once the VMSS is created.
+func (amp *AzureMachinePool) ValidateEphemeralOSDisk(old runtime.Object) func() error {
+	return func() error {
+		fldPath := field.NewPath("spec", "template", "osDisk")
+		osDisk := amp.Spec.Template.OSDisk
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			if !reflect.DeepEqual(osDisk.DiffDiskSettings, oldMachinePool.Spec.Template.OSDisk.DiffDiskSettings) {
+				return field.Invalid(fldPath.Child("diffDiskSettings"), osDisk.DiffDiskSettings, "field is immutable")
+			}
+		}
+
+		if osDisk.DiffDiskSettings == nil {
+			return nil
+		}
+		errs := infrav1.ValidateOSDisk(osDisk, fldPath)
+		if osDisk.CachingType != "" && osDisk.CachingType != string(compute.CachingTypesReadOnly) {
+			errs = append(errs, field.Invalid(fldPath.Child("cachingType"), osDisk.CachingType, "ephemeral OS disks only support the ReadOnly caching type"))
+		}
+		if len(errs) > 0 {
+			return kerrors.NewAggregate(errs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
+// ValidateDataDisks validates the data disks of the VMSS instances.
+func (amp *AzureMachinePool) ValidateDataDisks() error {
+	fldPath := field.NewPath("spec", "template", "dataDisks")
+	errs := infrav1.ValidateDataDisks(amp.Spec.Template.DataDisks, fldPath)
+	for i, disk := range amp.Spec.Template.DataDisks {
+		if disk.ManagedDisk != nil && disk.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) &&
+			disk.CachingType != "" && disk.CachingType != string(compute.CachingTypesNone) {
+			errs = append(errs, field.Invalid(fldPath.Index(i).Child("cachingType"), disk.CachingType, "host caching is not supported on ultra disks"))
+		}
+	}
+	if len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateWriteAccelerator validates the data disks with Write Accelerator enabled.
+func (amp *AzureMachinePool) ValidateWriteAccelerator() error {
+	fldPath := field.NewPath("spec", "template", "dataDisks")
+	if errs := infrav1.ValidateWriteAccelerator(amp.Spec.Template.VMSize, amp.Spec.Template.DataDisks, fldPath); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateSharedDataDisks validates that no data disk is shared, as scale set instances can't attach existing disks.
+func (amp *AzureMachinePool) ValidateSharedDataDisks() error {
+	fldPath := field.NewPath("spec", "template", "dataDisks")
+	for i, disk := range amp.Spec.Template.DataDisks {
+		if disk.IsShared() {
+			return field.Invalid(fldPath.Index(i).Child("maxShares"), *disk.MaxShares, "shared data disks are not supported on AzureMachinePools")
+		}
+	}
+
+	return nil
+}
+
+// ValidatePerformanceTiers validates that no disk sets a performance tier, as the disks of scale set instances can't
+// be tiered.
+func (amp *AzureMachinePool) ValidatePerformanceTiers() error {
+	if managedDisk := amp.Spec.Template.OSDisk.ManagedDisk; managedDisk != nil && managedDisk.PerformanceTier != "" {
+		return field.Invalid(field.NewPath("spec", "template", "osDisk", "managedDisk", "performanceTier"), managedDisk.PerformanceTier, "performance tiers are not supported on AzureMachinePools")
+	}
+	fldPath := field.NewPath("spec", "template", "dataDisks")
+	for i, disk := range amp.Spec.Template.DataDisks {
+		if disk.ManagedDisk != nil && disk.ManagedDisk.PerformanceTier != "" {
+			return field.Invalid(fldPath.Index(i).Child("managedDisk", "performanceTier"), disk.ManagedDisk.PerformanceTier, "performance tiers are not supported on AzureMachinePools")
+		}
+	}
+
+	return nil
+}
+
+// ValidateSecurityProfile validates the security profile.
+func (amp *AzureMachinePool) ValidateSecurityProfile() error {
+	fldPath := field.NewPath("spec", "template", "securityProfile")
+	if errs := infrav1.ValidateSecurityProfile(amp.Spec.Template.SecurityProfile, amp.Spec.Template.Image, fldPath); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateDiagnostics validates the boot diagnostics of the VMSS instances.
+func (amp *AzureMachinePool) ValidateDiagnostics() error {
+	fldPath := field.NewPath("spec", "template", "diagnostics")
+	if errs := infrav1.ValidateDiagnostics(amp.Spec.Template.Diagnostics, fldPath); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateProximityPlacementGroupID validates the proximity placement group, which can't be changed after creation.
+func (amp *AzureMachinePool) ValidateProximityPlacementGroupID(old runtime.Object) func() error {
+	return func() error {
+		fldPath := field.NewPath("spec", "template", "proximityPlacementGroupID")
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			if !reflect.DeepEqual(amp.Spec.Template.ProximityPlacementGroupID, oldMachinePool.Spec.Template.ProximityPlacementGroupID) {
+				return field.Invalid(fldPath, amp.Spec.Template.ProximityPlacementGroupID, "field is immutable")
+			}
+		}
+
+		if errs := infrav1.ValidateProximityPlacementGroupID(amp.Spec.Template.ProximityPlacementGroupID, fldPath); len(errs) > 0 {
+			return kerrors.NewAggregate(errs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
+// ValidateCapacityReservationGroupID validates the capacity reservation group, which can't be changed after creation.
+func (amp *AzureMachinePool) ValidateCapacityReservationGroupID(old runtime.Object) func() error {
+	return func() error {
+		fldPath := field.NewPath("spec", "template", "capacityReservationGroupID")
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			if !reflect.DeepEqual(amp.Spec.Template.CapacityReservationGroupID, oldMachinePool.Spec.Template.CapacityReservationGroupID) {
+				return field.Invalid(fldPath, amp.Spec.Template.CapacityReservationGroupID, "field is immutable")
+			}
+		}
+
+		if errs := infrav1.ValidateCapacityReservationGroupID(amp.Spec.Template.CapacityReservationGroupID, amp.Spec.Template.SpotVMOptions, fldPath); len(errs) > 0 {
+			return kerrors.NewAggregate(errs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
+// ValidateComputerNamePrefix validates the prefix of the computer names, which can't be changed after creation.
+func (amp *AzureMachinePool) ValidateComputerNamePrefix(old runtime.Object) func() error {
+	return func() error {
+		fldPath := field.NewPath("spec", "template", "computerNamePrefix")
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			if amp.Spec.Template.ComputerNamePrefix != oldMachinePool.Spec.Template.ComputerNamePrefix {
+				return field.Invalid(fldPath, amp.Spec.Template.ComputerNamePrefix, "field is immutable")
+			}
+		}
+
+		// Azure appends the 6 characters of the index of the instances to the prefix.
+		maxLength := 58
+		if amp.Spec.Template.OSDisk.OSType == string(compute.OperatingSystemTypesWindows) {
+			maxLength = 9
+		}
+		if errs := infrav1.ValidateComputerNamePrefix(amp.Spec.Template.ComputerNamePrefix, maxLength, fldPath); len(errs) > 0 {
+			return kerrors.NewAggregate(errs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
+// ValidateOrchestrationMode validates the orchestration mode, which can't be changed after creation. Azure can't
+// upgrade the instances of a Flexible scale set in place, so they can only be replaced following the deployment
+// strategy.
+func (amp *AzureMachinePool) ValidateOrchestrationMode(old runtime.Object) func() error {
+	return func() error {
+		fldPath := field.NewPath("spec", "orchestrationMode")
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			if amp.Spec.OrchestrationMode != oldMachinePool.Spec.OrchestrationMode {
+				return field.Invalid(fldPath, amp.Spec.OrchestrationMode, "field is immutable")
+			}
+		}
+
+		if amp.Spec.OrchestrationMode != FlexibleOrchestrationMode {
+			return nil
+		}
+		if policy := amp.Spec.UpgradePolicy; policy != nil && (policy.Mode == UpgradeModeAutomatic || policy.Mode == UpgradeModeRolling || policy.AutomaticOSUpgrade) {
+			return field.Forbidden(field.NewPath("spec", "upgradePolicy"), "only the Manual upgrade mode without automatic OS upgrades is supported with the Flexible orchestration mode")
+		}
+
+		return nil
+	}
+}
+
+// ValidatePriorityMixPolicy validates the priority mix policy, which can't be changed after creation. Azure only mixes
+// regular and Spot VMs in Flexible scale sets whose VMs are Spot VMs.
+func (amp *AzureMachinePool) ValidatePriorityMixPolicy(old runtime.Object) func() error {
+	return func() error {
+		fldPath := field.NewPath("spec", "priorityMixPolicy")
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			if !reflect.DeepEqual(amp.Spec.PriorityMixPolicy, oldMachinePool.Spec.PriorityMixPolicy) {
+				return field.Invalid(fldPath, amp.Spec.PriorityMixPolicy, "field is immutable")
+			}
+		}
+
+		if amp.Spec.PriorityMixPolicy == nil {
+			return nil
+		}
+		if amp.Spec.OrchestrationMode != FlexibleOrchestrationMode {
+			return field.Forbidden(fldPath, "a priority mix policy is only supported with the Flexible orchestration mode")
+		}
+		if amp.Spec.Template.SpotVMOptions == nil {
+			return field.Required(field.NewPath("spec", "template", "spotVMOptions"), "a priority mix policy requires Spot VM options")
+		}
+
+		return nil
+	}
+}
+
+// ValidateZonePolicy validates the zone policy, which can't be changed after creation as the zones and fault domains
+// of a scale set are set when it is created.
+func (amp *AzureMachinePool) ValidateZonePolicy(old runtime.Object) func() error {
+	return func() error {
+		fldPath := field.NewPath("spec", "zonePolicy")
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			if !reflect.DeepEqual(amp.Spec.ZonePolicy, oldMachinePool.Spec.ZonePolicy) {
+				return field.Invalid(fldPath, amp.Spec.ZonePolicy, "field is immutable")
+			}
+		}
+
+		policy := amp.Spec.ZonePolicy
+		if policy == nil {
+			return nil
+		}
+
+		var allErrs field.ErrorList
+		if policy.AllZones && len(policy.Zones) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("zones"), "zones can't be set when the scale set is spread across all zones"))
+		}
+		if policy.ZoneBalance != nil && *policy.ZoneBalance && len(policy.Zones) == 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("zoneBalance"), true, "zone balance requires at least 2 zones"))
+		}
+		zonal := policy.AllZones || len(policy.Zones) > 0
+		if count := policy.PlatformFaultDomainCount; count != nil && *count != 1 && zonal && amp.Spec.OrchestrationMode == FlexibleOrchestrationMode {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("platformFaultDomainCount"), *count, "Flexible scale sets spread across availability zones require a single fault domain"))
+		}
+		if len(allErrs) > 0 {
+			return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
 // ValidateStrategy validates the strategy.
 func (amp *AzureMachinePool) ValidateStrategy() func() error {
 	return func() error {
@@ -153,6 +475,9 @@ func (amp *AzureMachinePool) ValidateStrategy() func() error {
 				maxUnavailable.Type == intstr.Int && maxUnavailable.IntVal == 0 {
 				return errors.New("rolling update strategy MaxUnavailable must not be 0 if MaxSurge is 0")
 			}
+			if settle := rollingUpdateStrategy.BatchSettleTime; settle != nil && settle.Duration < 0 {
+				return errors.New("rolling update strategy BatchSettleTime must not be negative")
+			}
 		}
 
 		return nil
@@ -180,3 +505,88 @@ func (amp *AzureMachinePool) ValidateSystemAssignedIdentity(old runtime.Object)
 		return nil
 	}
 }
+
+// ValidateUpgradePolicy validates the VMSS upgrade policy.
+func (amp *AzureMachinePool) ValidateUpgradePolicy() error {
+	policy := amp.Spec.UpgradePolicy
+	if policy == nil {
+		return nil
+	}
+
+	fldPath := field.NewPath("upgradePolicy")
+	var allErrs field.ErrorList
+	if policy.RollingUpgrade != nil && policy.Mode != UpgradeModeRolling {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("rollingUpgrade"), "rollingUpgrade can only be set with the Rolling mode"))
+	}
+	if policy.HealthProbe == nil && (policy.Mode == UpgradeModeRolling || policy.AutomaticOSUpgrade) {
+		allErrs = append(allErrs, field.Required(fldPath.Child("healthProbe"), "a health probe is required by Rolling and automatic OS upgrades"))
+	}
+	if probe := policy.HealthProbe; probe != nil {
+		switch probe.Protocol {
+		case HealthProbeProtocolTCP:
+			if probe.RequestPath != "" {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("healthProbe", "requestPath"), "requestPath can not be set with the tcp protocol"))
+			}
+		default:
+			if !strings.HasPrefix(probe.RequestPath, "/") {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("healthProbe", "requestPath"), probe.RequestPath, "requestPath must start with /"))
+			}
+		}
+	}
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateAutomaticRepairsPolicy validates the VMSS automatic repairs policy.
+func (amp *AzureMachinePool) ValidateAutomaticRepairsPolicy() error {
+	policy := amp.Spec.AutomaticRepairsPolicy
+	if policy == nil {
+		return nil
+	}
+
+	fldPath := field.NewPath("automaticRepairsPolicy")
+	var allErrs field.ErrorList
+	if policy.Enabled && (amp.Spec.UpgradePolicy == nil || amp.Spec.UpgradePolicy.HealthProbe == nil) {
+		allErrs = append(allErrs, field.Required(field.NewPath("upgradePolicy", "healthProbe"), "a health probe is required by automatic repairs"))
+	}
+	if period := policy.GracePeriod; period != nil && (period.Duration < 30*time.Minute || period.Duration > 90*time.Minute) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("gracePeriod"), period.Duration.String(), "gracePeriod must be between 30 and 90 minutes"))
+	}
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateScheduledEventsPolicy validates the handling of scheduled events.
+func (amp *AzureMachinePool) ValidateScheduledEventsPolicy() error {
+	policy := amp.Spec.ScheduledEventsPolicy
+	if policy == nil || policy.PollInterval == nil {
+		return nil
+	}
+
+	if policy.PollInterval.Duration < 10*time.Second {
+		return field.Invalid(field.NewPath("scheduledEventsPolicy", "pollInterval"), policy.PollInterval.Duration.String(), "pollInterval must be at least 10 seconds")
+	}
+
+	return nil
+}
+
+// ValidateRemediationStrategy validates the remediation of the failed instances. The instances of a Flexible scale set
+// are standard VMs, which Azure only reimages when their OS disk is ephemeral.
+func (amp *AzureMachinePool) ValidateRemediationStrategy() error {
+	strategy := amp.Spec.RemediationStrategy
+	if strategy == nil || strategy.Type != ReimageRemediationStrategyType {
+		return nil
+	}
+
+	if amp.Spec.OrchestrationMode == FlexibleOrchestrationMode {
+		return field.Forbidden(field.NewPath("spec", "remediationStrategy", "type"), "the Reimage remediation strategy is only supported with the Uniform orchestration mode")
+	}
+
+	return nil
+}
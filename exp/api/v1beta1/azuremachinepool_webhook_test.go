@@ -21,7 +21,10 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"testing"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
 
@@ -42,8 +45,9 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 	g := NewWithT(t)
 
 	var (
-		zero = intstr.FromInt(0)
-		one  = intstr.FromInt(1)
+		zero                 = intstr.FromInt(0)
+		one                  = intstr.FromInt(1)
+		deleteEvictionPolicy = infrav1.SpotEvictionPolicyDelete
 	)
 
 	tests := []struct {
@@ -138,6 +142,330 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 			}),
 			wantErr: false,
 		},
+		{
+			name: "azuremachinepool with a pause between rolling update batches",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:        &one,
+					MaxUnavailable:  &zero,
+					BatchSettleTime: &metav1.Duration{Duration: 5 * time.Minute},
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with a negative pause between rolling update batches",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:        &one,
+					MaxUnavailable:  &zero,
+					BatchSettleTime: &metav1.Duration{Duration: -time.Minute},
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic repairs",
+			amp: createMachinePoolWithAutomaticRepairsPolicy(AutomaticRepairsPolicy{Enabled: true, GracePeriod: &metav1.Duration{Duration: 45 * time.Minute}},
+				&HealthProbe{Protocol: HealthProbeProtocolHTTP, Port: 10248, RequestPath: "/healthz"}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with automatic repairs without health probe",
+			amp:     createMachinePoolWithAutomaticRepairsPolicy(AutomaticRepairsPolicy{Enabled: true}, nil),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic repairs with a too short grace period",
+			amp: createMachinePoolWithAutomaticRepairsPolicy(AutomaticRepairsPolicy{Enabled: true, GracePeriod: &metav1.Duration{Duration: 10 * time.Minute}},
+				&HealthProbe{Protocol: HealthProbeProtocolHTTP, Port: 10248, RequestPath: "/healthz"}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic repairs with a too long grace period",
+			amp: createMachinePoolWithAutomaticRepairsPolicy(AutomaticRepairsPolicy{Enabled: true, GracePeriod: &metav1.Duration{Duration: 2 * time.Hour}},
+				&HealthProbe{Protocol: HealthProbeProtocolHTTP, Port: 10248, RequestPath: "/healthz"}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool handling scheduled events",
+			amp:     createMachinePoolWithScheduledEventsPolicy(ScheduledEventsPolicy{Enabled: true, PollInterval: &metav1.Duration{Duration: 30 * time.Second}}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool handling scheduled events with a too short poll interval",
+			amp:     createMachinePoolWithScheduledEventsPolicy(ScheduledEventsPolicy{Enabled: true, PollInterval: &metav1.Duration{Duration: time.Second}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool reimaging failed instances",
+			amp:     createMachinePoolWithRemediationStrategy(ReimageRemediationStrategyType, UniformOrchestrationMode),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool redeploying failed instances in the Flexible orchestration mode",
+			amp:     createMachinePoolWithRemediationStrategy(RedeployRemediationStrategyType, FlexibleOrchestrationMode),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool reimaging failed instances in the Flexible orchestration mode",
+			amp:     createMachinePoolWithRemediationStrategy(ReimageRemediationStrategyType, FlexibleOrchestrationMode),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with boot diagnostics in a user managed storage account",
+			amp:     createMachinePoolWithDiagnostics(infrav1.UserManagedDiagnosticsStorage, &infrav1.UserManagedBootDiagnostics{StorageAccountURI: "https://fake.blob.core.windows.net/"}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with boot diagnostics in a user managed storage account without URI",
+			amp:     createMachinePoolWithDiagnostics(infrav1.UserManagedDiagnosticsStorage, nil),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with disabled boot diagnostics referencing a storage account",
+			amp:     createMachinePoolWithDiagnostics(infrav1.DisabledDiagnosticsStorage, &infrav1.UserManagedBootDiagnostics{StorageAccountURI: "https://fake.blob.core.windows.net/"}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with Rolling upgrade policy",
+			amp: createMachinePoolWithUpgradePolicy(UpgradePolicy{
+				Mode:           UpgradeModeRolling,
+				RollingUpgrade: &RollingUpgradePolicy{MaxBatchInstancePercent: to.Int32Ptr(50)},
+				HealthProbe:    &HealthProbe{Protocol: HealthProbeProtocolHTTP, Port: 10248, RequestPath: "/healthz"},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with rolling upgrade settings in Manual mode",
+			amp: createMachinePoolWithUpgradePolicy(UpgradePolicy{
+				Mode:           UpgradeModeManual,
+				RollingUpgrade: &RollingUpgradePolicy{MaxBatchInstancePercent: to.Int32Ptr(50)},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic OS upgrade without health probe",
+			amp: createMachinePoolWithUpgradePolicy(UpgradePolicy{
+				Mode:               UpgradeModeAutomatic,
+				AutomaticOSUpgrade: true,
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with tcp health probe and request path",
+			amp: createMachinePoolWithUpgradePolicy(UpgradePolicy{
+				Mode:        UpgradeModeRolling,
+				HealthProbe: &HealthProbe{Protocol: HealthProbeProtocolTCP, Port: 10250, RequestPath: "/healthz"},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with http health probe without request path",
+			amp: createMachinePoolWithUpgradePolicy(UpgradePolicy{
+				Mode:        UpgradeModeRolling,
+				HealthProbe: &HealthProbe{Protocol: HealthProbeProtocolHTTP, Port: 10248},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with spot VMs deleted on eviction and an ephemeral OS disk",
+			amp: createMachinePoolWithSpotVMOptions(infrav1.SpotVMOptions{
+				EvictionPolicy: &deleteEvictionPolicy,
+			}, &infrav1.DiffDiskSettings{Option: "Local"}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with spot VMs deallocated on eviction and an ephemeral OS disk",
+			amp:     createMachinePoolWithSpotVMOptions(infrav1.SpotVMOptions{}, &infrav1.DiffDiskSettings{Option: "Local"}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with an ephemeral OS disk",
+			amp:     createMachinePoolWithEphemeralOSDisk("ReadOnly", nil),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with an ephemeral OS disk with write caching",
+			amp:     createMachinePoolWithEphemeralOSDisk("ReadWrite", nil),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with an encrypted ephemeral OS disk",
+			amp: createMachinePoolWithEphemeralOSDisk("ReadOnly", &infrav1.ManagedDiskParameters{
+				DiskEncryptionSet: &infrav1.DiskEncryptionSetParameters{ID: "encryption_id"},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with spot VMs and a negative maximum price",
+			amp: createMachinePoolWithSpotVMOptions(infrav1.SpotVMOptions{
+				MaxPrice: resource.NewQuantity(-2, resource.DecimalSI),
+			}, nil),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a proximity placement group",
+			amp:     createMachinePoolWithProximityPlacementGroupID(to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg")),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with an invalid proximity placement group ID",
+			amp:     createMachinePoolWithProximityPlacementGroupID(to.StringPtr("my-ppg")),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a capacity reservation group",
+			amp:     createMachinePoolWithCapacityReservationGroupID(to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"), nil),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with an invalid capacity reservation group ID",
+			amp:     createMachinePoolWithCapacityReservationGroupID(to.StringPtr("my-crg"), nil),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a capacity reservation group and Spot VMs",
+			amp:     createMachinePoolWithCapacityReservationGroupID(to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"), &infrav1.SpotVMOptions{}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a computer name prefix",
+			amp:     createMachinePoolWithComputerNamePrefix("webserver", "Windows"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with a computer name prefix too long for Windows",
+			amp:     createMachinePoolWithComputerNamePrefix("webservers", "Windows"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a long computer name prefix on Linux",
+			amp:     createMachinePoolWithComputerNamePrefix("webservers", "Linux"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with write accelerator on an M-series VM size",
+			amp:     createMachinePoolWithWriteAccelerator("Standard_M32ms", "Premium_LRS"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with write accelerator on a non M-series VM size",
+			amp:     createMachinePoolWithWriteAccelerator("Standard_D4s_v3", "Premium_LRS"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with write accelerator on a standard disk",
+			amp:     createMachinePoolWithWriteAccelerator("Standard_M32ms", "Standard_LRS"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a shared data disk",
+			amp:     createMachinePoolWithMaxShares(2),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a data disk with maxShares of 1",
+			amp:     createMachinePoolWithMaxShares(1),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with a performance tier on a data disk",
+			amp:     createMachinePoolWithDataDiskPerformanceTier("P30"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool without a performance tier on a data disk",
+			amp:     createMachinePoolWithDataDiskPerformanceTier(""),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with data disks",
+			amp:     createMachinePoolWithDataDisks(0, 1, "Premium_LRS", "ReadWrite"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with data disks sharing a LUN",
+			amp:     createMachinePoolWithDataDisks(1, 1, "Premium_LRS", "ReadWrite"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with an ultra data disk without host caching",
+			amp:     createMachinePoolWithDataDisks(0, 1, "UltraSSD_LRS", "None"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with an ultra data disk with host caching",
+			amp:     createMachinePoolWithDataDisks(0, 1, "UltraSSD_LRS", "ReadOnly"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with the Flexible orchestration mode",
+			amp:     createMachinePoolWithOrchestrationMode(FlexibleOrchestrationMode, nil),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with the Flexible orchestration mode and the Manual upgrade mode",
+			amp:     createMachinePoolWithOrchestrationMode(FlexibleOrchestrationMode, &UpgradePolicy{Mode: UpgradeModeManual}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with the Flexible orchestration mode and the Rolling upgrade mode",
+			amp:     createMachinePoolWithOrchestrationMode(FlexibleOrchestrationMode, &UpgradePolicy{Mode: UpgradeModeRolling, HealthProbe: &HealthProbe{Protocol: HealthProbeProtocolTCP}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with the Flexible orchestration mode and automatic OS upgrades",
+			amp:     createMachinePoolWithOrchestrationMode(FlexibleOrchestrationMode, &UpgradePolicy{Mode: UpgradeModeManual, AutomaticOSUpgrade: true, HealthProbe: &HealthProbe{Protocol: HealthProbeProtocolTCP}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with the Uniform orchestration mode and the Rolling upgrade mode",
+			amp:     createMachinePoolWithOrchestrationMode(UniformOrchestrationMode, &UpgradePolicy{Mode: UpgradeModeRolling, HealthProbe: &HealthProbe{Protocol: HealthProbeProtocolTCP}}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with a priority mix policy",
+			amp:     createMachinePoolWithPriorityMixPolicy(FlexibleOrchestrationMode, &infrav1.SpotVMOptions{}, &PriorityMixPolicy{BaseRegularPriorityCount: to.Int32Ptr(2), RegularPriorityPercentageAboveBase: to.Int32Ptr(25)}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with a priority mix policy and the Uniform orchestration mode",
+			amp:     createMachinePoolWithPriorityMixPolicy(UniformOrchestrationMode, &infrav1.SpotVMOptions{}, &PriorityMixPolicy{BaseRegularPriorityCount: to.Int32Ptr(2)}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a priority mix policy without Spot VM options",
+			amp:     createMachinePoolWithPriorityMixPolicy(FlexibleOrchestrationMode, nil, &PriorityMixPolicy{BaseRegularPriorityCount: to.Int32Ptr(2)}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool pinned to zones",
+			amp:     createMachinePoolWithZonePolicy(UniformOrchestrationMode, &ZonePolicy{Zones: []string{"1", "2"}, ZoneBalance: to.BoolPtr(true), PlatformFaultDomainCount: to.Int32Ptr(2)}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool spread across all zones",
+			amp:     createMachinePoolWithZonePolicy(FlexibleOrchestrationMode, &ZonePolicy{AllZones: true, PlatformFaultDomainCount: to.Int32Ptr(1)}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool spread across all zones and pinned to zones",
+			amp:     createMachinePoolWithZonePolicy(UniformOrchestrationMode, &ZonePolicy{AllZones: true, Zones: []string{"1"}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with zone balance in a single zone",
+			amp:     createMachinePoolWithZonePolicy(UniformOrchestrationMode, &ZonePolicy{Zones: []string{"1"}, ZoneBalance: to.BoolPtr(true)}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with several fault domains in a zonal Flexible scale set",
+			amp:     createMachinePoolWithZonePolicy(FlexibleOrchestrationMode, &ZonePolicy{Zones: []string{"1", "2"}, PlatformFaultDomainCount: to.Int32Ptr(2)}),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -213,6 +541,66 @@ func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
 			}),
 			wantErr: false,
 		},
+		{
+			name:    "azuremachinepool with proximity placement group changed",
+			oldAMP:  createMachinePoolWithProximityPlacementGroupID(nil),
+			amp:     createMachinePoolWithProximityPlacementGroupID(to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/proximityPlacementGroups/my-ppg")),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with capacity reservation group changed",
+			oldAMP:  createMachinePoolWithCapacityReservationGroupID(to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/capacityReservationGroups/my-crg"), nil),
+			amp:     createMachinePoolWithCapacityReservationGroupID(nil, nil),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with computer name prefix changed",
+			oldAMP:  createMachinePoolWithComputerNamePrefix("web", "Linux"),
+			amp:     createMachinePoolWithComputerNamePrefix("api", "Linux"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with orchestration mode changed",
+			oldAMP:  createMachinePoolWithOrchestrationMode(UniformOrchestrationMode, nil),
+			amp:     createMachinePoolWithOrchestrationMode(FlexibleOrchestrationMode, nil),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with priority mix policy changed",
+			oldAMP:  createMachinePoolWithPriorityMixPolicy(FlexibleOrchestrationMode, &infrav1.SpotVMOptions{}, &PriorityMixPolicy{RegularPriorityPercentageAboveBase: to.Int32Ptr(25)}),
+			amp:     createMachinePoolWithPriorityMixPolicy(FlexibleOrchestrationMode, &infrav1.SpotVMOptions{}, &PriorityMixPolicy{RegularPriorityPercentageAboveBase: to.Int32Ptr(50)}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with priority mix policy unchanged",
+			oldAMP:  createMachinePoolWithPriorityMixPolicy(FlexibleOrchestrationMode, &infrav1.SpotVMOptions{}, &PriorityMixPolicy{RegularPriorityPercentageAboveBase: to.Int32Ptr(25)}),
+			amp:     createMachinePoolWithPriorityMixPolicy(FlexibleOrchestrationMode, &infrav1.SpotVMOptions{}, &PriorityMixPolicy{RegularPriorityPercentageAboveBase: to.Int32Ptr(25)}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with zone policy changed",
+			oldAMP:  createMachinePoolWithZonePolicy(UniformOrchestrationMode, &ZonePolicy{Zones: []string{"1"}}),
+			amp:     createMachinePoolWithZonePolicy(UniformOrchestrationMode, &ZonePolicy{Zones: []string{"1", "2"}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with zone policy unchanged",
+			oldAMP:  createMachinePoolWithZonePolicy(UniformOrchestrationMode, &ZonePolicy{AllZones: true}),
+			amp:     createMachinePoolWithZonePolicy(UniformOrchestrationMode, &ZonePolicy{AllZones: true}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with ephemeral OS disk enabled",
+			oldAMP:  &AzureMachinePool{},
+			amp:     createMachinePoolWithEphemeralOSDisk("ReadOnly", nil),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with ephemeral OS disk unchanged",
+			oldAMP:  createMachinePoolWithEphemeralOSDisk("ReadOnly", nil),
+			amp:     createMachinePoolWithEphemeralOSDisk("ReadOnly", nil),
+			wantErr: false,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -364,3 +752,240 @@ func createMachinePoolWithStrategy(strategy AzureMachinePoolDeploymentStrategy)
 		},
 	}
 }
+
+func createMachinePoolWithUpgradePolicy(policy UpgradePolicy) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			UpgradePolicy: &policy,
+		},
+	}
+}
+
+func createMachinePoolWithAutomaticRepairsPolicy(policy AutomaticRepairsPolicy, probe *HealthProbe) *AzureMachinePool {
+	amp := &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			AutomaticRepairsPolicy: &policy,
+		},
+	}
+	if probe != nil {
+		amp.Spec.UpgradePolicy = &UpgradePolicy{Mode: UpgradeModeManual, HealthProbe: probe}
+	}
+	return amp
+}
+
+func createMachinePoolWithZonePolicy(mode OrchestrationModeType, policy *ZonePolicy) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			OrchestrationMode: mode,
+			ZonePolicy:        policy,
+		},
+	}
+}
+
+func createMachinePoolWithScheduledEventsPolicy(policy ScheduledEventsPolicy) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			ScheduledEventsPolicy: &policy,
+		},
+	}
+}
+
+func createMachinePoolWithRemediationStrategy(strategy RemediationStrategyType, mode OrchestrationModeType) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			OrchestrationMode:   mode,
+			RemediationStrategy: &RemediationStrategy{Type: strategy},
+		},
+	}
+}
+
+func createMachinePoolWithDiagnostics(storageAccountType infrav1.BootDiagnosticsStorageAccountType, userManaged *infrav1.UserManagedBootDiagnostics) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				Diagnostics: &infrav1.Diagnostics{
+					Boot: &infrav1.BootDiagnostics{
+						StorageAccountType: storageAccountType,
+						UserManaged:        userManaged,
+					},
+				},
+			},
+		},
+	}
+}
+
+func createMachinePoolWithSpotVMOptions(spotVMOptions infrav1.SpotVMOptions, diffDiskSettings *infrav1.DiffDiskSettings) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				SpotVMOptions: &spotVMOptions,
+				OSDisk: infrav1.OSDisk{
+					OSType:           "Linux",
+					CachingType:      "ReadOnly",
+					DiffDiskSettings: diffDiskSettings,
+				},
+			},
+		},
+	}
+}
+
+func createMachinePoolWithEphemeralOSDisk(cachingType string, managedDisk *infrav1.ManagedDiskParameters) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				OSDisk: infrav1.OSDisk{
+					OSType:           "Linux",
+					DiskSizeGB:       to.Int32Ptr(30),
+					CachingType:      cachingType,
+					ManagedDisk:      managedDisk,
+					DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"},
+				},
+			},
+		},
+	}
+}
+
+func createMachinePoolWithProximityPlacementGroupID(proximityPlacementGroupID *string) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				ProximityPlacementGroupID: proximityPlacementGroupID,
+			},
+		},
+	}
+}
+
+func createMachinePoolWithCapacityReservationGroupID(capacityReservationGroupID *string, spotVMOptions *infrav1.SpotVMOptions) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				CapacityReservationGroupID: capacityReservationGroupID,
+				SpotVMOptions:              spotVMOptions,
+			},
+		},
+	}
+}
+
+func createMachinePoolWithComputerNamePrefix(computerNamePrefix, osType string) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				ComputerNamePrefix: computerNamePrefix,
+				OSDisk: infrav1.OSDisk{
+					OSType: osType,
+				},
+			},
+		},
+	}
+}
+
+func createMachinePoolWithWriteAccelerator(vmSize, storageAccountType string) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				VMSize: vmSize,
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "data",
+						DiskSizeGB: 128,
+						Lun:        to.Int32Ptr(0),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: storageAccountType,
+						},
+						CachingType:             "None",
+						WriteAcceleratorEnabled: to.BoolPtr(true),
+					},
+				},
+			},
+		},
+	}
+}
+
+func createMachinePoolWithMaxShares(maxShares int32) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "data",
+						DiskSizeGB: 128,
+						Lun:        to.Int32Ptr(0),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+						},
+						CachingType: "None",
+						MaxShares:   to.Int32Ptr(maxShares),
+					},
+				},
+			},
+		},
+	}
+}
+
+func createMachinePoolWithDataDisks(firstLun, secondLun int32, storageAccountType, cachingType string) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix:  "etcd",
+						DiskSizeGB:  256,
+						Lun:         to.Int32Ptr(firstLun),
+						CachingType: "ReadWrite",
+					},
+					{
+						NameSuffix: "data",
+						DiskSizeGB: 1024,
+						Lun:        to.Int32Ptr(secondLun),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: storageAccountType,
+						},
+						CachingType: cachingType,
+					},
+				},
+			},
+		},
+	}
+}
+
+func createMachinePoolWithDataDiskPerformanceTier(performanceTier string) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "data",
+						DiskSizeGB: 128,
+						Lun:        to.Int32Ptr(0),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+							PerformanceTier:    performanceTier,
+						},
+						CachingType: "ReadWrite",
+					},
+				},
+			},
+		},
+	}
+}
+
+func createMachinePoolWithPriorityMixPolicy(mode OrchestrationModeType, spotVMOptions *infrav1.SpotVMOptions, policy *PriorityMixPolicy) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			OrchestrationMode: mode,
+			Template: AzureMachinePoolMachineTemplate{
+				SpotVMOptions: spotVMOptions,
+			},
+			PriorityMixPolicy: policy,
+		},
+	}
+}
+
+func createMachinePoolWithOrchestrationMode(mode OrchestrationModeType, policy *UpgradePolicy) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			OrchestrationMode: mode,
+			UpgradePolicy:     policy,
+		},
+	}
+}
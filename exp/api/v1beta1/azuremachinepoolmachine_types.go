@@ -28,6 +28,26 @@ import (
 const (
 	// AzureMachinePoolMachineFinalizer is used to ensure deletion of dependencies (nodes, infra).
 	AzureMachinePoolMachineFinalizer = "azuremachinepoolmachine.infrastructure.cluster.x-k8s.io"
+
+	// InstanceProtectionAnnotation protects the instance of an AzureMachinePoolMachine until it is removed. It is read
+	// from the AzureMachinePoolMachine or, if it doesn't have it, from its node. Only the instances of a Uniform scale set
+	// can be protected.
+	InstanceProtectionAnnotation = "azuremachinepoolmachine.infrastructure.cluster.x-k8s.io/instance-protection"
+	// InstanceProtectionScaleIn protects the instance from being removed when the scale set is scaled in.
+	InstanceProtectionScaleIn = "ScaleIn"
+	// InstanceProtectionScaleSetActions protects the instance from being removed when the scale set is scaled in, and
+	// from the actions performed on the scale set, such as upgrades.
+	InstanceProtectionScaleSetActions = "ScaleSetActions"
+
+	// ScheduledEventAnnotation is set by the AzureMachinePool controller on the AzureMachinePoolMachines whose node
+	// reports an event Azure scheduled for its instance, to the type of the event, e.g. Preempt or Redeploy. It is
+	// removed once the node doesn't report the event anymore.
+	ScheduledEventAnnotation = "azuremachinepoolmachine.infrastructure.cluster.x-k8s.io/scheduled-event"
+	// ScheduledEventPreempt is the type of the event scheduled for a Spot instance about to be evicted.
+	ScheduledEventPreempt = "Preempt"
+	// ScheduledEventFreeze is the type of the event scheduled for an instance about to be paused for a few seconds,
+	// for which its node is not drained.
+	ScheduledEventFreeze = "Freeze"
 )
 
 type (
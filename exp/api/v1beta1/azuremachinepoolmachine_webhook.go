@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -36,7 +38,7 @@ var _ webhook.Validator = &AzureMachinePoolMachine{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (ampm *AzureMachinePoolMachine) ValidateCreate() error {
-	return nil
+	return ampm.validateInstanceProtection()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -50,10 +52,19 @@ func (ampm *AzureMachinePoolMachine) ValidateUpdate(old runtime.Object) error {
 		return errors.New("providerID is immutable")
 	}
 
-	return nil
+	return ampm.validateInstanceProtection()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (ampm *AzureMachinePoolMachine) ValidateDelete() error {
 	return nil
 }
+
+// validateInstanceProtection validates the value of the InstanceProtectionAnnotation.
+func (ampm *AzureMachinePoolMachine) validateInstanceProtection() error {
+	value, ok := ampm.Annotations[InstanceProtectionAnnotation]
+	if !ok || value == InstanceProtectionScaleIn || value == InstanceProtectionScaleSetActions {
+		return nil
+	}
+	return fmt.Errorf("annotation %s must be %s or %s, got %q", InstanceProtectionAnnotation, InstanceProtectionScaleIn, InstanceProtectionScaleSetActions, value)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"math"
+	"net"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+)
+
+const (
+	// NetworkPluginAzure is the Azure CNI network plugin, which assigns the IPs of the pods from the subnet of the nodes.
+	NetworkPluginAzure = "azure"
+	// NetworkPluginKubenet is the kubenet network plugin, which assigns the IPs of the pods from a separate pod CIDR.
+	NetworkPluginKubenet = "kubenet"
+	// NetworkPluginModeOverlay is the overlay mode of Azure CNI, which assigns the IPs of the pods from a separate pod
+	// CIDR.
+	NetworkPluginModeOverlay = "overlay"
+	// NetworkDataplaneCilium is the network dataplane of AKS powered by Cilium.
+	NetworkDataplaneCilium = "cilium"
+	// NetworkPolicyCilium is the network policy enforced by the Cilium dataplane.
+	NetworkPolicyCilium = "cilium"
+
+	// AzureCNIMaxPodsPerNode is the maximum number of pods AKS schedules on a node with Azure CNI by default.
+	AzureCNIMaxPodsPerNode = 30
+	// KubenetMaxNodes is the maximum number of nodes of an AKS cluster with kubenet, limited by its route table.
+	KubenetMaxNodes = 400
+
+	// azureReservedSubnetIPs is the number of IPs Azure reserves in each subnet.
+	azureReservedSubnetIPs = 5
+)
+
+// SubnetCapacity returns the maximum number of nodes the subnet of the cluster can hold. With Azure CNI not in overlay
+// mode, each node takes the IPs of its pods from the subnet in addition to its own, and the maximum number of pods is
+// returned too.
+// It returns an error if the CIDR of the subnet is invalid or isn't an IPv4 CIDR.
+func (m *AzureManagedControlPlane) SubnetCapacity() (maxNodes int32, maxPods *int32, err error) {
+	cidr := m.Spec.VirtualNetwork.Subnet.CIDRBlock
+	_, subnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "invalid subnet CIDR %s", cidr)
+	}
+	ones, bits := subnet.Mask.Size()
+	if bits != net.IPv4len*8 {
+		return 0, nil, errors.Errorf("subnet CIDR %s is not an IPv4 CIDR", cidr)
+	}
+
+	usableIPs := int64(1)<<(bits-ones) - azureReservedSubnetIPs
+	if usableIPs < 0 {
+		usableIPs = 0
+	}
+
+	if m.Spec.NetworkPlugin != nil && *m.Spec.NetworkPlugin == NetworkPluginKubenet {
+		nodes := usableIPs
+		if nodes > KubenetMaxNodes {
+			nodes = KubenetMaxNodes
+		}
+		return int32(nodes), nil, nil
+	}
+
+	if m.Spec.NetworkPluginMode != nil && *m.Spec.NetworkPluginMode == NetworkPluginModeOverlay {
+		return int32(usableIPs), nil, nil
+	}
+
+	nodes := usableIPs / (1 + AzureCNIMaxPodsPerNode)
+	pods := nodes * AzureCNIMaxPodsPerNode
+	if pods > math.MaxInt32 {
+		pods = math.MaxInt32
+	}
+	return int32(nodes), to.Int32Ptr(int32(pods)), nil
+}
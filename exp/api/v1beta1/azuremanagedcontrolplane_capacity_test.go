@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+)
+
+func TestAzureManagedControlPlane_SubnetCapacity(t *testing.T) {
+	tests := []struct {
+		name              string
+		cidr              string
+		networkPlugin     *string
+		networkPluginMode *string
+		wantMaxNodes      int32
+		wantMaxPods       *int32
+		wantErr           bool
+	}{
+		{
+			name:          "azure cni",
+			cidr:          "10.240.0.0/24",
+			networkPlugin: to.StringPtr(NetworkPluginAzure),
+			wantMaxNodes:  8,
+			wantMaxPods:   to.Int32Ptr(240),
+		},
+		{
+			name:          "default network plugin",
+			cidr:          "10.240.0.0/16",
+			networkPlugin: nil,
+			wantMaxNodes:  2113,
+			wantMaxPods:   to.Int32Ptr(63390),
+		},
+		{
+			name:          "kubenet",
+			cidr:          "10.240.0.0/24",
+			networkPlugin: to.StringPtr(NetworkPluginKubenet),
+			wantMaxNodes:  251,
+			wantMaxPods:   nil,
+		},
+		{
+			name:          "kubenet is limited by its route table",
+			cidr:          "10.240.0.0/16",
+			networkPlugin: to.StringPtr(NetworkPluginKubenet),
+			wantMaxNodes:  KubenetMaxNodes,
+			wantMaxPods:   nil,
+		},
+		{
+			name:              "azure cni overlay",
+			cidr:              "10.240.0.0/24",
+			networkPlugin:     to.StringPtr(NetworkPluginAzure),
+			networkPluginMode: to.StringPtr(NetworkPluginModeOverlay),
+			wantMaxNodes:      251,
+			wantMaxPods:       nil,
+		},
+		{
+			name:          "subnet smaller than the reserved IPs",
+			cidr:          "10.240.0.0/30",
+			networkPlugin: to.StringPtr(NetworkPluginKubenet),
+			wantMaxNodes:  0,
+			wantMaxPods:   nil,
+		},
+		{
+			name:    "invalid CIDR",
+			cidr:    "10.240.0.0",
+			wantErr: true,
+		},
+		{
+			name:    "IPv6 CIDR",
+			cidr:    "2001:1234:5678:9abc::/64",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					NetworkPlugin:     tc.networkPlugin,
+					NetworkPluginMode: tc.networkPluginMode,
+					VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+						Subnet: ManagedControlPlaneSubnet{CIDRBlock: tc.cidr},
+					},
+				},
+			}
+			maxNodes, maxPods, err := m.SubnetCapacity()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(maxNodes).To(Equal(tc.wantMaxNodes))
+			g.Expect(maxPods).To(Equal(tc.wantMaxPods))
+		})
+	}
+}
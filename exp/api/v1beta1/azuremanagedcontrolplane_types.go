@@ -32,6 +32,23 @@ const (
 	PrivateDNSZoneModeNone string = "None"
 )
 
+const (
+	// PowerStateRunning is the power state of a running AKS cluster.
+	PowerStateRunning = "Running"
+
+	// PowerStateStopped is the power state of a stopped AKS cluster, whose control plane and nodes are deallocated.
+	PowerStateStopped = "Stopped"
+)
+
+const (
+	// AddonOMSAgent is the name of the AKS add-on monitoring the cluster with Azure Monitor.
+	AddonOMSAgent string = "omsagent"
+
+	// AddonOMSAgentWorkspaceConfig is the setting of the omsagent add-on holding the resource ID of its Log Analytics
+	// workspace.
+	AddonOMSAgentWorkspaceConfig string = "logAnalyticsWorkspaceResourceID"
+)
+
 // AzureManagedControlPlaneSpec defines the desired state of AzureManagedControlPlane.
 type AzureManagedControlPlaneSpec struct {
 	// Version defines the desired Kubernetes version.
@@ -72,11 +89,25 @@ type AzureManagedControlPlaneSpec struct {
 	// +optional
 	NetworkPlugin *string `json:"networkPlugin,omitempty"`
 
-	// NetworkPolicy used for building Kubernetes network.
-	// +kubebuilder:validation:Enum=azure;calico
+	// NetworkPluginMode is the mode of the network plugin. With overlay, Azure CNI assigns the IPs of the pods from the
+	// pod CIDR of the cluster network instead of the subnet of the nodes. It requires the azure network plugin and can't
+	// be changed after creation.
+	// +kubebuilder:validation:Enum=overlay
+	// +optional
+	NetworkPluginMode *string `json:"networkPluginMode,omitempty"`
+
+	// NetworkPolicy used for building Kubernetes network. The cilium network policy requires the cilium network
+	// dataplane, which defaults it.
+	// +kubebuilder:validation:Enum=azure;calico;cilium
 	// +optional
 	NetworkPolicy *string `json:"networkPolicy,omitempty"`
 
+	// NetworkDataplane is the dataplane of the network of the cluster. The cilium dataplane requires the azure network
+	// plugin and the cilium network policy. It can't be changed after creation.
+	// +kubebuilder:validation:Enum=azure;cilium
+	// +optional
+	NetworkDataplane *string `json:"networkDataplane,omitempty"`
+
 	// SSHPublicKey is a string literal containing an ssh public key base64 encoded.
 	SSHPublicKey string `json:"sshPublicKey"`
 
@@ -109,6 +140,41 @@ type AzureManagedControlPlaneSpec struct {
 	// APIServerAccessProfile is the access profile for AKS API server.
 	// +optional
 	APIServerAccessProfile *APIServerAccessProfile `json:"apiServerAccessProfile,omitempty"`
+
+	// ProxyConfig is the HTTP proxy the nodes of the cluster reach the Internet through, set as the HTTP proxy
+	// configuration of the AKS cluster. It can't be changed after creation.
+	// +optional
+	ProxyConfig *infrav1.ProxyConfig `json:"proxyConfig,omitempty"`
+
+	// AutoScalerProfile is the profile of the cluster autoscaler of the node pools with autoscaling enabled.
+	// Settings left unset keep the AKS defaults, and settings removed from the spec are not reset on the cluster.
+	// +optional
+	AutoScalerProfile *AutoScalerProfile `json:"autoScalerProfile,omitempty"`
+
+	// OIDCIssuerProfile is the profile of the OIDC issuer of the cluster, whose URL is reported in the status so that
+	// federated identity credentials can be created for it. The OIDC issuer can't be disabled once enabled.
+	// +optional
+	OIDCIssuerProfile *OIDCIssuerProfile `json:"oidcIssuerProfile,omitempty"`
+
+	// SecurityProfile is the security profile of the cluster.
+	// +optional
+	SecurityProfile *ManagedControlPlaneSecurityProfile `json:"securityProfile,omitempty"`
+
+	// AddonProfiles are the profiles of the AKS add-ons of the cluster, such as omsagent, azurepolicy or
+	// azureKeyvaultSecretsProvider. Add-ons removed from the spec are left as they are on the cluster.
+	// +optional
+	AddonProfiles []AddonProfile `json:"addonProfiles,omitempty"`
+
+	// Stopped is whether the cluster is stopped. Stopping a cluster deallocates its control plane and nodes to save
+	// their cost while keeping its configuration, and unsetting the field starts it again. The cluster and its node
+	// pools are not updated while it is stopped.
+	// +optional
+	Stopped bool `json:"stopped,omitempty"`
+
+	// DisableLocalAccounts is whether the local accounts of the cluster are disabled, so that it can only be accessed
+	// with AAD credentials. It requires a managed AADProfile and can't be changed after creation.
+	// +optional
+	DisableLocalAccounts *bool `json:"disableLocalAccounts,omitempty"`
 }
 
 // AADProfile - AAD integration managed by AKS.
@@ -123,19 +189,23 @@ type AADProfile struct {
 }
 
 // AzureManagedControlPlaneSkuTier - Tier of a managed cluster SKU.
-// +kubebuilder:validation:Enum=Free;Paid
+// +kubebuilder:validation:Enum=Free;Paid;Standard
 type AzureManagedControlPlaneSkuTier string
 
 const (
 	// FreeManagedControlPlaneTier is the free tier of AKS without corresponding SLAs.
 	FreeManagedControlPlaneTier AzureManagedControlPlaneSkuTier = "Free"
-	// PaidManagedControlPlaneTier is the paid tier of AKS with corresponding SLAs.
+	// PaidManagedControlPlaneTier is the paid tier of AKS with corresponding SLAs, also known as Uptime SLA.
+	// It is the former name of the Standard tier.
 	PaidManagedControlPlaneTier AzureManagedControlPlaneSkuTier = "Paid"
+	// StandardManagedControlPlaneTier is the standard tier of AKS with a financially backed SLA for the uptime of the
+	// API server.
+	StandardManagedControlPlaneTier AzureManagedControlPlaneSkuTier = "Standard"
 )
 
 // SKU - AKS SKU.
 type SKU struct {
-	// Tier - Tier of a managed cluster SKU.
+	// Tier - Tier of a managed cluster SKU. It can be changed on existing clusters.
 	Tier AzureManagedControlPlaneSkuTier `json:"tier"`
 }
 
@@ -184,10 +254,152 @@ type APIServerAccessProfile struct {
 	EnablePrivateClusterPublicFQDN *bool `json:"enablePrivateClusterPublicFQDN,omitempty"`
 }
 
+// Expander - The expander the cluster autoscaler uses to select the node pool to scale up.
+// +kubebuilder:validation:Enum=least-waste;most-pods;priority;random
+type Expander string
+
+const (
+	// ExpanderLeastWaste selects the node pool that will have the least idle CPU, then memory, after scale up.
+	ExpanderLeastWaste Expander = "least-waste"
+	// ExpanderMostPods selects the node pool that would be able to schedule the most pods when scaling up.
+	ExpanderMostPods Expander = "most-pods"
+	// ExpanderPriority selects the node pool with the highest priority assigned by the user.
+	ExpanderPriority Expander = "priority"
+	// ExpanderRandom selects a node pool randomly.
+	ExpanderRandom Expander = "random"
+)
+
+// AutoScalerProfile - Parameters of the cluster autoscaler of an AKS cluster.
+// See https://docs.microsoft.com/en-us/azure/aks/cluster-autoscaler#using-the-autoscaler-profile
+type AutoScalerProfile struct {
+	// BalanceSimilarNodeGroups - Whether to balance the size of similar node pools. Valid values are 'true' and 'false'.
+	// +kubebuilder:validation:Enum="true";"false"
+	// +optional
+	BalanceSimilarNodeGroups *string `json:"balanceSimilarNodeGroups,omitempty"`
+
+	// Expander - The expander used to select the node pool to scale up. The default is 'random'.
+	// +optional
+	Expander *Expander `json:"expander,omitempty"`
+
+	// MaxEmptyBulkDelete - The maximum number of empty nodes that can be deleted at the same time. The default is '10'.
+	// +kubebuilder:validation:Pattern=`^(\d+)$`
+	// +optional
+	MaxEmptyBulkDelete *string `json:"maxEmptyBulkDelete,omitempty"`
+
+	// MaxGracefulTerminationSec - The maximum number of seconds the autoscaler waits for pod termination when trying to scale down a node. The default is '600'.
+	// +kubebuilder:validation:Pattern=`^(\d+)$`
+	// +optional
+	MaxGracefulTerminationSec *string `json:"maxGracefulTerminationSec,omitempty"`
+
+	// MaxNodeProvisionTime - The maximum time the autoscaler waits for a node to be provisioned, in minutes. The default is '15m'.
+	// +kubebuilder:validation:Pattern=`^(\d+)m$`
+	// +optional
+	MaxNodeProvisionTime *string `json:"maxNodeProvisionTime,omitempty"`
+
+	// MaxTotalUnreadyPercentage - The maximum percentage of unready nodes in the cluster, between 0 and 100. Once exceeded, the autoscaler halts operations. The default is '45'.
+	// +kubebuilder:validation:Pattern=`^(\d+)$`
+	// +optional
+	MaxTotalUnreadyPercentage *string `json:"maxTotalUnreadyPercentage,omitempty"`
+
+	// NewPodScaleUpDelay - The time to ignore new pods for before they are considered for scale up, in seconds. The default is '0s'.
+	// +kubebuilder:validation:Pattern=`^(\d+)s$`
+	// +optional
+	NewPodScaleUpDelay *string `json:"newPodScaleUpDelay,omitempty"`
+
+	// OkTotalUnreadyCount - The number of unready nodes allowed, irrespective of MaxTotalUnreadyPercentage. The default is '3'.
+	// +kubebuilder:validation:Pattern=`^(\d+)$`
+	// +optional
+	OkTotalUnreadyCount *string `json:"okTotalUnreadyCount,omitempty"`
+
+	// ScanInterval - How often the cluster is reevaluated for scale up or down, in seconds. The default is '10s'.
+	// +kubebuilder:validation:Pattern=`^(\d+)s$`
+	// +optional
+	ScanInterval *string `json:"scanInterval,omitempty"`
+
+	// ScaleDownDelayAfterAdd - How long after scale up that scale down evaluation resumes, in minutes. The default is '10m'.
+	// +kubebuilder:validation:Pattern=`^(\d+)m$`
+	// +optional
+	ScaleDownDelayAfterAdd *string `json:"scaleDownDelayAfterAdd,omitempty"`
+
+	// ScaleDownDelayAfterDelete - How long after node deletion that scale down evaluation resumes, in seconds. The default is the scan interval.
+	// +kubebuilder:validation:Pattern=`^(\d+)s$`
+	// +optional
+	ScaleDownDelayAfterDelete *string `json:"scaleDownDelayAfterDelete,omitempty"`
+
+	// ScaleDownDelayAfterFailure - How long after scale down failure that scale down evaluation resumes, in minutes. The default is '3m'.
+	// +kubebuilder:validation:Pattern=`^(\d+)m$`
+	// +optional
+	ScaleDownDelayAfterFailure *string `json:"scaleDownDelayAfterFailure,omitempty"`
+
+	// ScaleDownUnneededTime - How long a node should be unneeded before it is eligible for scale down, in minutes. The default is '10m'.
+	// +kubebuilder:validation:Pattern=`^(\d+)m$`
+	// +optional
+	ScaleDownUnneededTime *string `json:"scaleDownUnneededTime,omitempty"`
+
+	// ScaleDownUnreadyTime - How long an unready node should be unneeded before it is eligible for scale down, in minutes. The default is '20m'.
+	// +kubebuilder:validation:Pattern=`^(\d+)m$`
+	// +optional
+	ScaleDownUnreadyTime *string `json:"scaleDownUnreadyTime,omitempty"`
+
+	// ScaleDownUtilizationThreshold - The ratio of requested resources to capacity, between 0 and 1, under which a node is considered for scale down. The default is '0.5'.
+	// +kubebuilder:validation:Pattern=`^(0|1|0?\.\d+|1\.0+)$`
+	// +optional
+	ScaleDownUtilizationThreshold *string `json:"scaleDownUtilizationThreshold,omitempty"`
+
+	// SkipNodesWithLocalStorage - Whether the autoscaler skips deleting nodes with pods with local storage, such as EmptyDir or HostPath. Valid values are 'true' and 'false'.
+	// +kubebuilder:validation:Enum="true";"false"
+	// +optional
+	SkipNodesWithLocalStorage *string `json:"skipNodesWithLocalStorage,omitempty"`
+
+	// SkipNodesWithSystemPods - Whether the autoscaler skips deleting nodes with pods from kube-system, except DaemonSet and mirror pods. Valid values are 'true' and 'false'.
+	// +kubebuilder:validation:Enum="true";"false"
+	// +optional
+	SkipNodesWithSystemPods *string `json:"skipNodesWithSystemPods,omitempty"`
+}
+
+// OIDCIssuerProfile is the profile of the OIDC issuer of an AKS cluster.
+type OIDCIssuerProfile struct {
+	// Enabled is whether the OIDC issuer of the cluster is enabled.
+	Enabled bool `json:"enabled"`
+}
+
+// ManagedControlPlaneSecurityProfile is the security profile of an AKS cluster.
+type ManagedControlPlaneSecurityProfile struct {
+	// WorkloadIdentity is the workload identity settings of the cluster.
+	// +optional
+	WorkloadIdentity *ManagedControlPlaneWorkloadIdentity `json:"workloadIdentity,omitempty"`
+}
+
+// ManagedControlPlaneWorkloadIdentity is the workload identity settings of an AKS cluster.
+type ManagedControlPlaneWorkloadIdentity struct {
+	// Enabled is whether workload identity is enabled, which lets pods use Azure AD workload identities through
+	// federated identity credentials. It requires the OIDC issuer of the cluster to be enabled.
+	Enabled bool `json:"enabled"`
+}
+
+// AddonProfile is the profile of an AKS add-on.
+type AddonProfile struct {
+	// Name is the name of the add-on, e.g. omsagent, azurepolicy or azureKeyvaultSecretsProvider.
+	Name string `json:"name"`
+
+	// Enabled is whether the add-on is enabled.
+	Enabled bool `json:"enabled"`
+
+	// Config is the settings of the add-on, e.g. logAnalyticsWorkspaceResourceID for omsagent or
+	// enableSecretRotation for azureKeyvaultSecretsProvider.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+}
+
 // ManagedControlPlaneVirtualNetwork describes a virtual network required to provision AKS clusters.
 type ManagedControlPlaneVirtualNetwork struct {
 	Name      string `json:"name"`
 	CIDRBlock string `json:"cidrBlock"`
+	// ResourceGroup is the resource group of the virtual network. Defaults to the resource group of the cluster.
+	// An existing virtual network can be used by setting its name and resource group; it is then neither updated
+	// nor deleted, and its subnet must already exist.
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
 	// +optional
 	Subnet ManagedControlPlaneSubnet `json:"subnet,omitempty"`
 }
@@ -214,6 +426,33 @@ type AzureManagedControlPlaneStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
+
+	// SubnetUtilization reports how many nodes the subnet of the cluster holds, and how many nodes and pods it can hold
+	// with the network plugin of the cluster.
+	// +optional
+	SubnetUtilization *SubnetUtilization `json:"subnetUtilization,omitempty"`
+
+	// OIDCIssuerURL is the URL of the OIDC issuer of the cluster, when it is enabled.
+	// +optional
+	OIDCIssuerURL string `json:"oidcIssuerURL,omitempty"`
+
+	// PowerState is the power state of the cluster, Running or Stopped.
+	// +optional
+	PowerState string `json:"powerState,omitempty"`
+}
+
+// SubnetUtilization reports the utilization of the subnet of an AKS cluster.
+type SubnetUtilization struct {
+	// Nodes is the number of nodes of the node pools of the cluster.
+	Nodes int32 `json:"nodes"`
+
+	// MaxNodes is the maximum number of nodes the subnet can hold.
+	MaxNodes int32 `json:"maxNodes"`
+
+	// MaxPods is the maximum number of pods the subnet can hold. It is only reported with Azure CNI not in overlay mode,
+	// as kubenet and overlay don't assign the IPs of the pods from the subnet.
+	// +optional
+	MaxPods *int32 `json:"maxPods,omitempty"`
 }
 
 // +kubebuilder:object:root=true
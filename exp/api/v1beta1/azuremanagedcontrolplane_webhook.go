@@ -21,8 +21,10 @@ import (
 	"net"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -57,6 +59,9 @@ func (r *AzureManagedControlPlane) Default() {
 	}
 	if r.Spec.NetworkPolicy == nil {
 		NetworkPolicy := "calico"
+		if r.Spec.NetworkDataplane != nil && *r.Spec.NetworkDataplane == NetworkDataplaneCilium {
+			NetworkPolicy = NetworkPolicyCilium
+		}
 		r.Spec.NetworkPolicy = &NetworkPolicy
 	}
 
@@ -190,6 +195,31 @@ func (r *AzureManagedControlPlane) ValidateUpdate(oldRaw runtime.Object) error {
 		}
 	}
 
+	if !reflect.DeepEqual(r.Spec.NetworkPluginMode, old.Spec.NetworkPluginMode) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "NetworkPluginMode"),
+				r.Spec.NetworkPluginMode,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.NetworkDataplane, old.Spec.NetworkDataplane) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "NetworkDataplane"),
+				r.Spec.NetworkDataplane,
+				"field is immutable"))
+	}
+
+	// Unset and false both keep the local accounts enabled.
+	if to.Bool(r.Spec.DisableLocalAccounts) != to.Bool(old.Spec.DisableLocalAccounts) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "DisableLocalAccounts"),
+				r.Spec.DisableLocalAccounts,
+				"field is immutable"))
+	}
+
 	if old.Spec.LoadBalancerSKU != nil {
 		// Prevent LoadBalancerSKU modification if it was already set to some value
 		if r.Spec.LoadBalancerSKU == nil {
@@ -238,6 +268,22 @@ func (r *AzureManagedControlPlane) ValidateUpdate(oldRaw runtime.Object) error {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if !reflect.DeepEqual(r.Spec.ProxyConfig, old.Spec.ProxyConfig) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "ProxyConfig"),
+				r.Spec.ProxyConfig,
+				"field is immutable"))
+	}
+
+	if old.Spec.OIDCIssuerProfile != nil && old.Spec.OIDCIssuerProfile.Enabled && !r.isOIDCIssuerEnabled() {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "OIDCIssuerProfile", "Enabled"),
+				r.Spec.OIDCIssuerProfile,
+				"the OIDC issuer can't be disabled once enabled"))
+	}
+
 	if len(allErrs) == 0 {
 		return r.Validate()
 	}
@@ -258,6 +304,12 @@ func (r *AzureManagedControlPlane) Validate() error {
 		r.validateSSHKey,
 		r.validateLoadBalancerProfile,
 		r.validateAPIServerAccessProfile,
+		r.validateProxyConfig,
+		r.validateAutoScalerProfile,
+		r.validateWorkloadIdentity,
+		r.validateAddonProfiles,
+		r.validateNetworkMode,
+		r.validateDisableLocalAccounts,
 	}
 
 	var errs []error
@@ -359,6 +411,9 @@ func (r *AzureManagedControlPlane) validateAPIServerAccessProfile() error {
 				allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "APIServerAccessProfile", "AuthorizedIPRanges"), ipRange, "invalid CIDR format"))
 			}
 		}
+		if len(r.Spec.APIServerAccessProfile.AuthorizedIPRanges) > 0 && r.Spec.APIServerAccessProfile.EnablePrivateCluster != nil && *r.Spec.APIServerAccessProfile.EnablePrivateCluster {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "APIServerAccessProfile", "AuthorizedIPRanges"), r.Spec.APIServerAccessProfile.AuthorizedIPRanges, "authorized IP ranges are not supported by private clusters"))
+		}
 		if len(allErrs) > 0 {
 			return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
 		}
@@ -366,6 +421,118 @@ func (r *AzureManagedControlPlane) validateAPIServerAccessProfile() error {
 	return nil
 }
 
+// validateProxyConfig validates a ProxyConfig.
+func (r *AzureManagedControlPlane) validateProxyConfig() error {
+	if errs := infrav1.ValidateProxyConfig(r.Spec.ProxyConfig, field.NewPath("Spec", "ProxyConfig")); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+	return nil
+}
+
+// validateAutoScalerProfile validates the ranges of the numeric settings of the AutoScalerProfile, which can't be
+// expressed by the patterns of the CRD.
+func (r *AzureManagedControlPlane) validateAutoScalerProfile() error {
+	if r.Spec.AutoScalerProfile == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+
+	if percentage := r.Spec.AutoScalerProfile.MaxTotalUnreadyPercentage; percentage != nil {
+		if value, err := strconv.Atoi(*percentage); err != nil || value < 0 || value > 100 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "AutoScalerProfile", "MaxTotalUnreadyPercentage"), *percentage, "value should be an integer in between 0 and 100"))
+		}
+	}
+
+	if threshold := r.Spec.AutoScalerProfile.ScaleDownUtilizationThreshold; threshold != nil {
+		if value, err := strconv.ParseFloat(*threshold, 64); err != nil || value < 0 || value > 1 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "AutoScalerProfile", "ScaleDownUtilizationThreshold"), *threshold, "value should be a number in between 0 and 1"))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+	return nil
+}
+
+// validateWorkloadIdentity validates that the OIDC issuer the federated identity credentials of workload identity
+// rely on is enabled.
+func (r *AzureManagedControlPlane) validateWorkloadIdentity() error {
+	if r.Spec.SecurityProfile == nil || r.Spec.SecurityProfile.WorkloadIdentity == nil || !r.Spec.SecurityProfile.WorkloadIdentity.Enabled {
+		return nil
+	}
+	if !r.isOIDCIssuerEnabled() {
+		return field.Invalid(field.NewPath("Spec", "SecurityProfile", "WorkloadIdentity", "Enabled"), true, "workload identity requires the OIDC issuer to be enabled")
+	}
+	return nil
+}
+
+// validateAddonProfiles validates that each add-on is set once, and that the settings the add-ons require are set.
+func (r *AzureManagedControlPlane) validateAddonProfiles() error {
+	var allErrs field.ErrorList
+
+	names := map[string]bool{}
+	for i, profile := range r.Spec.AddonProfiles {
+		if names[profile.Name] {
+			allErrs = append(allErrs, field.Duplicate(field.NewPath("Spec", "AddonProfiles").Index(i).Child("Name"), profile.Name))
+		}
+		names[profile.Name] = true
+
+		if profile.Name == AddonOMSAgent && profile.Enabled && profile.Config[AddonOMSAgentWorkspaceConfig] == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath("Spec", "AddonProfiles").Index(i).Child("Config", AddonOMSAgentWorkspaceConfig), "the omsagent add-on requires a Log Analytics workspace"))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+	return nil
+}
+
+// validateNetworkMode validates that the overlay mode and the cilium dataplane are used with Azure CNI, and that the
+// cilium dataplane and network policy are used together.
+func (r *AzureManagedControlPlane) validateNetworkMode() error {
+	var allErrs field.ErrorList
+
+	isAzureCNI := r.Spec.NetworkPlugin == nil || *r.Spec.NetworkPlugin == NetworkPluginAzure
+	if r.Spec.NetworkPluginMode != nil && !isAzureCNI {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "NetworkPluginMode"), *r.Spec.NetworkPluginMode, "the network plugin mode requires the azure network plugin"))
+	}
+
+	isCiliumDataplane := r.Spec.NetworkDataplane != nil && *r.Spec.NetworkDataplane == NetworkDataplaneCilium
+	if isCiliumDataplane && !isAzureCNI {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "NetworkDataplane"), *r.Spec.NetworkDataplane, "the cilium network dataplane requires the azure network plugin"))
+	}
+	isCiliumPolicy := r.Spec.NetworkPolicy != nil && *r.Spec.NetworkPolicy == NetworkPolicyCilium
+	if isCiliumDataplane && r.Spec.NetworkPolicy != nil && !isCiliumPolicy {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "NetworkPolicy"), *r.Spec.NetworkPolicy, "the cilium network dataplane requires the cilium network policy"))
+	}
+	if isCiliumPolicy && !isCiliumDataplane {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "NetworkPolicy"), *r.Spec.NetworkPolicy, "the cilium network policy requires the cilium network dataplane"))
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+	return nil
+}
+
+// validateDisableLocalAccounts validates that the local accounts are only disabled on clusters with managed AAD.
+func (r *AzureManagedControlPlane) validateDisableLocalAccounts() error {
+	if r.Spec.DisableLocalAccounts == nil || !*r.Spec.DisableLocalAccounts {
+		return nil
+	}
+	if r.Spec.AADProfile == nil || !r.Spec.AADProfile.Managed {
+		return field.Invalid(field.NewPath("Spec", "DisableLocalAccounts"), true, "disabling the local accounts requires a managed AADProfile")
+	}
+	return nil
+}
+
+// isOIDCIssuerEnabled returns true if the OIDC issuer of the cluster is enabled.
+func (r *AzureManagedControlPlane) isOIDCIssuerEnabled() bool {
+	return r.Spec.OIDCIssuerProfile != nil && r.Spec.OIDCIssuerProfile.Enabled
+}
+
 // validateAPIServerAccessProfileUpdate validates update to APIServerAccessProfile.
 func (r *AzureManagedControlPlane) validateAPIServerAccessProfileUpdate(old *AzureManagedControlPlane) field.ErrorList {
 	var allErrs field.ErrorList
@@ -24,6 +24,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
 func TestDefaultingWebhook(t *testing.T) {
@@ -74,6 +75,14 @@ func TestDefaultingWebhook(t *testing.T) {
 	g.Expect(amcp.Spec.VirtualNetwork.Name).To(Equal("fooVnetName"))
 	g.Expect(amcp.Spec.VirtualNetwork.Subnet.Name).To(Equal("fooSubnetName"))
 	g.Expect(amcp.Spec.SKU.Tier).To(Equal(PaidManagedControlPlaneTier))
+
+	t.Logf("Testing amcp defaulting webhook with the cilium network dataplane")
+	amcp.Spec.NetworkPlugin = nil
+	amcp.Spec.NetworkPolicy = nil
+	amcp.Spec.NetworkDataplane = to.StringPtr(NetworkDataplaneCilium)
+	amcp.Default()
+	g.Expect(*amcp.Spec.NetworkPlugin).To(Equal("azure"))
+	g.Expect(*amcp.Spec.NetworkPolicy).To(Equal(NetworkPolicyCilium))
 }
 
 func TestValidatingWebhook(t *testing.T) {
@@ -234,6 +243,227 @@ func TestValidatingWebhook(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "Testing AuthorizedIPRanges on a private cluster",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					APIServerAccessProfile: &APIServerAccessProfile{
+						AuthorizedIPRanges:   []string{"12.34.56.78/32"},
+						EnablePrivateCluster: to.BoolPtr(true),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing valid ProxyConfig",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					ProxyConfig: &infrav1.ProxyConfig{
+						HTTPSProxy: "http://proxy.example.com:3128",
+						NoProxy:    []string{"localhost"},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Testing invalid ProxyConfig",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					ProxyConfig: &infrav1.ProxyConfig{
+						HTTPSProxy: "proxy.example.com:3128",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing valid AutoScalerProfile",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						Expander:                      (*Expander)(pointer.StringPtr(string(ExpanderLeastWaste))),
+						MaxTotalUnreadyPercentage:     pointer.StringPtr("100"),
+						ScaleDownUtilizationThreshold: pointer.StringPtr("0.5"),
+						ScanInterval:                  pointer.StringPtr("20s"),
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Testing invalid AutoScalerProfile MaxTotalUnreadyPercentage",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						MaxTotalUnreadyPercentage: pointer.StringPtr("101"),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing invalid AutoScalerProfile ScaleDownUtilizationThreshold",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						ScaleDownUtilizationThreshold: pointer.StringPtr("1.5"),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing workload identity with the OIDC issuer",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:           "v1.21.2",
+					OIDCIssuerProfile: &OIDCIssuerProfile{Enabled: true},
+					SecurityProfile: &ManagedControlPlaneSecurityProfile{
+						WorkloadIdentity: &ManagedControlPlaneWorkloadIdentity{Enabled: true},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Testing workload identity without the OIDC issuer",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					SecurityProfile: &ManagedControlPlaneSecurityProfile{
+						WorkloadIdentity: &ManagedControlPlaneWorkloadIdentity{Enabled: true},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing azure cni overlay with the cilium network dataplane",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:           "v1.21.2",
+					NetworkPlugin:     to.StringPtr("azure"),
+					NetworkPluginMode: to.StringPtr("overlay"),
+					NetworkPolicy:     to.StringPtr("cilium"),
+					NetworkDataplane:  to.StringPtr("cilium"),
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Testing overlay network plugin mode with kubenet",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:           "v1.21.2",
+					NetworkPlugin:     to.StringPtr("kubenet"),
+					NetworkPluginMode: to.StringPtr("overlay"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing cilium network dataplane with the calico network policy",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:          "v1.21.2",
+					NetworkPlugin:    to.StringPtr("azure"),
+					NetworkPolicy:    to.StringPtr("calico"),
+					NetworkDataplane: to.StringPtr("cilium"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing cilium network policy without the cilium network dataplane",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:       "v1.21.2",
+					NetworkPlugin: to.StringPtr("azure"),
+					NetworkPolicy: to.StringPtr("cilium"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing addon profiles",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AddonProfiles: []AddonProfile{
+						{
+							Name:    "omsagent",
+							Enabled: true,
+							Config: map[string]string{
+								"logAnalyticsWorkspaceResourceID": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-workspace",
+							},
+						},
+						{
+							Name:    "azureKeyvaultSecretsProvider",
+							Enabled: true,
+							Config:  map[string]string{"enableSecretRotation": "true"},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Testing duplicate addon profiles",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AddonProfiles: []AddonProfile{
+						{Name: "azurepolicy", Enabled: true},
+						{Name: "azurepolicy", Enabled: false},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing omsagent addon profile without a Log Analytics workspace",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AddonProfiles: []AddonProfile{
+						{Name: "omsagent", Enabled: true},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing disabled local accounts with managed AAD",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AADProfile: &AADProfile{
+						Managed:             true,
+						AdminGroupObjectIDs: []string{"00000000-0000-0000-0000-000000000000"},
+					},
+					DisableLocalAccounts: to.BoolPtr(true),
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Testing disabled local accounts without AAD",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:              "v1.21.2",
+					DisableLocalAccounts: to.BoolPtr(true),
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -724,6 +954,159 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "AzureManagedControlPlane ProxyConfig is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					ProxyConfig: &infrav1.ProxyConfig{
+						HTTPProxy: "http://proxy.example.com:3128",
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					ProxyConfig: &infrav1.ProxyConfig{
+						HTTPProxy: "http://other-proxy.example.com:3128",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane OIDC issuer can be enabled",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP:      to.StringPtr("192.168.0.0"),
+					Version:           "v1.18.0",
+					OIDCIssuerProfile: &OIDCIssuerProfile{Enabled: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AzureManagedControlPlane OIDC issuer can't be disabled",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP:      to.StringPtr("192.168.0.0"),
+					Version:           "v1.18.0",
+					OIDCIssuerProfile: &OIDCIssuerProfile{Enabled: true},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane NetworkPluginMode is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP:      to.StringPtr("192.168.0.0"),
+					Version:           "v1.18.0",
+					NetworkPluginMode: to.StringPtr("overlay"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane NetworkDataplane is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP:     to.StringPtr("192.168.0.0"),
+					Version:          "v1.18.0",
+					NetworkDataplane: to.StringPtr("cilium"),
+					NetworkPolicy:    to.StringPtr("cilium"),
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP:     to.StringPtr("192.168.0.0"),
+					Version:          "v1.18.0",
+					NetworkDataplane: to.StringPtr("azure"),
+					NetworkPolicy:    to.StringPtr("cilium"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane SKU tier can be changed",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					SKU:          &SKU{Tier: FreeManagedControlPlaneTier},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					SKU:          &SKU{Tier: StandardManagedControlPlaneTier},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AzureManagedControlPlane DisableLocalAccounts is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					AADProfile: &AADProfile{
+						Managed:             true,
+						AdminGroupObjectIDs: []string{"00000000-0000-0000-0000-000000000000"},
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					AADProfile: &AADProfile{
+						Managed:             true,
+						AdminGroupObjectIDs: []string{"00000000-0000-0000-0000-000000000000"},
+					},
+					DisableLocalAccounts: to.BoolPtr(true),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane DisableLocalAccounts can be set to false when unset",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP:         to.StringPtr("192.168.0.0"),
+					Version:              "v1.18.0",
+					DisableLocalAccounts: to.BoolPtr(false),
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -17,8 +17,11 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
 const (
@@ -65,6 +68,83 @@ type AzureManagedMachinePoolSpec struct {
 	// Scaling specifies the autoscaling parameters for the node pool.
 	// +optional
 	Scaling *ManagedMachinePoolScaling `json:"scaling,omitempty"`
+
+	// EnableArtifactStreaming enables or disables artifact streaming on the node pool, which lazily pulls the images
+	// hosted in a premium Azure Container Registry so that containers with large images start faster. It is enabled or
+	// disabled in place on existing node pools, and left as AKS configured it if it is not set.
+	// +optional
+	EnableArtifactStreaming *bool `json:"enableArtifactStreaming,omitempty"`
+
+	// NodeLabels are the Kubernetes labels set on the nodes of the node pool. They are updated in place on existing
+	// node pools.
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+	// Taints are the Kubernetes taints set on the nodes of the node pool. They can't be changed after creation.
+	// +optional
+	Taints []Taint `json:"taints,omitempty"`
+
+	// ScaleSetPriority is the priority of the VMs of the node pool. Spot node pools run on spare Azure capacity at a
+	// discount, and their nodes can be evicted whenever Azure needs the capacity back. AKS adds the
+	// kubernetes.azure.com/scalesetpriority=spot:NoSchedule taint to their nodes. Defaults to Regular.
+	// It can't be changed after creation.
+	// +kubebuilder:validation:Enum=Regular;Spot
+	// +optional
+	ScaleSetPriority *ScaleSetPriority `json:"scaleSetPriority,omitempty"`
+
+	// ScaleSetEvictionPolicy defines what happens to the VMs of a Spot node pool when they are evicted. Defaults to
+	// Delete. It can only be set on Spot node pools and can't be changed after creation.
+	// +kubebuilder:validation:Enum=Deallocate;Delete
+	// +optional
+	ScaleSetEvictionPolicy *infrav1.SpotEvictionPolicy `json:"scaleSetEvictionPolicy,omitempty"`
+
+	// SpotMaxPrice is the maximum price the user is willing to pay for the VMs of a Spot node pool, in US dollars per
+	// hour. -1 caps the price at the on-demand price, which is also the behavior when it is not set. It can only be set
+	// on Spot node pools and can't be changed after creation.
+	// +optional
+	SpotMaxPrice *resource.Quantity `json:"spotMaxPrice,omitempty"`
+
+	// SubnetName is the name of the subnet of the virtual network of the cluster the nodes of the node pool are
+	// attached to. The subnet must already exist. Defaults to the subnet of the AzureManagedControlPlane.
+	// It can't be changed after creation.
+	// +optional
+	SubnetName *string `json:"subnetName,omitempty"`
+}
+
+// ScaleSetPriority is the priority of the VMs of a node pool.
+type ScaleSetPriority string
+
+const (
+	// ScaleSetPriorityRegular is the priority of node pools of regular VMs.
+	ScaleSetPriorityRegular ScaleSetPriority = "Regular"
+	// ScaleSetPrioritySpot is the priority of node pools of Spot VMs.
+	ScaleSetPrioritySpot ScaleSetPriority = "Spot"
+)
+
+// TaintEffect is the effect of a taint on the pods that don't tolerate it.
+// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+type TaintEffect string
+
+const (
+	// TaintEffectNoSchedule prevents the pods that don't tolerate the taint from being scheduled on the node.
+	TaintEffectNoSchedule TaintEffect = "NoSchedule"
+	// TaintEffectPreferNoSchedule avoids scheduling the pods that don't tolerate the taint on the node.
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+	// TaintEffectNoExecute evicts the pods that don't tolerate the taint from the node.
+	TaintEffectNoExecute TaintEffect = "NoExecute"
+)
+
+// Taint is a Kubernetes taint of the nodes of a node pool.
+type Taint struct {
+	// Key is the key of the taint.
+	Key string `json:"key"`
+
+	// Value is the value of the taint.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Effect is the effect of the taint on the pods that don't tolerate it.
+	Effect TaintEffect `json:"effect"`
 }
 
 // ManagedMachinePoolScaling specifies scaling options.
@@ -18,16 +18,24 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// aksReservedLabelPrefix is the prefix of the node labels set by AKS, which can't be set on node pools.
+const aksReservedLabelPrefix = "kubernetes.azure.com/"
+
 //+kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-azuremanagedmachinepool,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedmachinepools,verbs=create;update,versions=v1beta1,name=default.azuremanagedmachinepools.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
@@ -42,10 +50,21 @@ func (r *AzureManagedMachinePool) Default(client client.Client) {
 	}
 }
 
-//+kubebuilder:webhook:verbs=update;delete,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremanagedmachinepool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedmachinepools,versions=v1beta1,name=validation.azuremanagedmachinepools.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+//+kubebuilder:webhook:verbs=create;update;delete,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremanagedmachinepool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedmachinepools,versions=v1beta1,name=validation.azuremanagedmachinepools.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (r *AzureManagedMachinePool) ValidateCreate(client client.Client) error {
+	var allErrs field.ErrorList
+	if err := r.validateMaxSize(client); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	allErrs = append(allErrs, r.validateNodeLabels()...)
+	allErrs = append(allErrs, r.validateTaints()...)
+	allErrs = append(allErrs, r.validateSpot()...)
+
+	if len(allErrs) != 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("AzureManagedMachinePool").GroupKind(), r.Name, allErrs)
+	}
 	return nil
 }
 
@@ -89,6 +108,55 @@ func (r *AzureManagedMachinePool) ValidateUpdate(oldRaw runtime.Object, client c
 				"field is immutable"))
 	}
 
+	if !reflect.DeepEqual(r.Spec.Taints, old.Spec.Taints) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Taints"),
+				r.Spec.Taints,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.ScaleSetPriority, old.Spec.ScaleSetPriority) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "ScaleSetPriority"),
+				r.Spec.ScaleSetPriority,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.ScaleSetEvictionPolicy, old.Spec.ScaleSetEvictionPolicy) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "ScaleSetEvictionPolicy"),
+				r.Spec.ScaleSetEvictionPolicy,
+				"field is immutable"))
+	}
+
+	if (r.Spec.SpotMaxPrice == nil) != (old.Spec.SpotMaxPrice == nil) ||
+		(r.Spec.SpotMaxPrice != nil && r.Spec.SpotMaxPrice.Cmp(*old.Spec.SpotMaxPrice) != 0) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "SpotMaxPrice"),
+				r.Spec.SpotMaxPrice,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.SubnetName, old.Spec.SubnetName) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "SubnetName"),
+				r.Spec.SubnetName,
+				"field is immutable"))
+	}
+
+	if r.Spec.Mode == string(NodePoolModeSystem) && r.isSpot() {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Mode"),
+				r.Spec.Mode,
+				"Spot node pools can't be system node pools"))
+	}
+
 	if r.Spec.Mode != string(NodePoolModeSystem) && old.Spec.Mode == string(NodePoolModeSystem) {
 		// validate for last system node pool
 		if err := r.validateLastSystemNodePool(client); err != nil {
@@ -99,6 +167,11 @@ func (r *AzureManagedMachinePool) ValidateUpdate(oldRaw runtime.Object, client c
 		}
 	}
 
+	if err := r.validateMaxSize(client); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	allErrs = append(allErrs, r.validateNodeLabels()...)
+
 	if len(allErrs) != 0 {
 		return apierrors.NewInvalid(GroupVersion.WithKind("AzureManagedMachinePool").GroupKind(), r.Name, allErrs)
 	}
@@ -160,6 +233,145 @@ func (r *AzureManagedMachinePool) validateLastSystemNodePool(cli client.Client)
 	return nil
 }
 
+// validateMaxSize checks that the maximum size of the node pool doesn't exceed the number of nodes the subnet of its
+// cluster can hold with its network plugin, as all the node pools of an AKS cluster share its subnet.
+func (r *AzureManagedMachinePool) validateMaxSize(cli client.Client) *field.Error {
+	if r.Spec.Scaling == nil || r.Spec.Scaling.MaxSize == nil {
+		return nil
+	}
+
+	controlPlane, err := r.getControlPlane(cli)
+	if err != nil {
+		return field.InternalError(field.NewPath("Spec", "Scaling", "MaxSize"), err)
+	}
+	if controlPlane == nil {
+		return nil
+	}
+	if r.Spec.SubnetName != nil && *r.Spec.SubnetName != controlPlane.Spec.VirtualNetwork.Subnet.Name {
+		// the CIDR of the subnet of the node pool is not known.
+		return nil
+	}
+
+	maxNodes, _, err := controlPlane.SubnetCapacity()
+	if err != nil {
+		// the subnet is validated with the control plane.
+		return nil
+	}
+	if *r.Spec.Scaling.MaxSize > maxNodes {
+		networkPlugin := NetworkPluginAzure
+		if controlPlane.Spec.NetworkPlugin != nil {
+			networkPlugin = *controlPlane.Spec.NetworkPlugin
+		}
+		return field.Invalid(
+			field.NewPath("Spec", "Scaling", "MaxSize"),
+			*r.Spec.Scaling.MaxSize,
+			fmt.Sprintf("the subnet %s of the cluster can hold at most %d nodes with the %s network plugin", controlPlane.Spec.VirtualNetwork.Subnet.CIDRBlock, maxNodes, networkPlugin))
+	}
+	return nil
+}
+
+// validateNodeLabels checks that the node labels are valid Kubernetes labels that don't use the prefix AKS reserves
+// for its own labels.
+func (r *AzureManagedMachinePool) validateNodeLabels() field.ErrorList {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "NodeLabels")
+	for key, value := range r.Spec.NodeLabels {
+		for _, msg := range validation.IsQualifiedName(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath, key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(value) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), value, msg))
+		}
+		if strings.HasPrefix(key, aksReservedLabelPrefix) {
+			allErrs = append(allErrs, field.Invalid(fldPath, key, fmt.Sprintf("the prefix %s is reserved by AKS", aksReservedLabelPrefix)))
+		}
+	}
+	return allErrs
+}
+
+// validateTaints checks that the keys and values of the taints are valid.
+func (r *AzureManagedMachinePool) validateTaints() field.ErrorList {
+	var allErrs field.ErrorList
+	for i, taint := range r.Spec.Taints {
+		fldPath := field.NewPath("Spec", "Taints").Index(i)
+		for _, msg := range validation.IsQualifiedName(taint.Key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Key"), taint.Key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(taint.Value) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Value"), taint.Value, msg))
+		}
+	}
+	return allErrs
+}
+
+// isSpot returns true if the node pool is a Spot node pool.
+func (r *AzureManagedMachinePool) isSpot() bool {
+	return r.Spec.ScaleSetPriority != nil && *r.Spec.ScaleSetPriority == ScaleSetPrioritySpot
+}
+
+// validateSpot checks that the eviction policy and the maximum price are only set on Spot node pools, that the maximum
+// price is either -1 or positive, and that Spot node pools aren't system node pools, which AKS doesn't support.
+func (r *AzureManagedMachinePool) validateSpot() field.ErrorList {
+	var allErrs field.ErrorList
+	if !r.isSpot() {
+		if r.Spec.ScaleSetEvictionPolicy != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "ScaleSetEvictionPolicy"), *r.Spec.ScaleSetEvictionPolicy, "can only be set on Spot node pools"))
+		}
+		if r.Spec.SpotMaxPrice != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "SpotMaxPrice"), r.Spec.SpotMaxPrice.String(), "can only be set on Spot node pools"))
+		}
+		return allErrs
+	}
+
+	if r.Spec.Mode == string(NodePoolModeSystem) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "Mode"), r.Spec.Mode, "Spot node pools can't be system node pools"))
+	}
+	if r.Spec.SpotMaxPrice != nil && r.Spec.SpotMaxPrice.Sign() <= 0 && r.Spec.SpotMaxPrice.Cmp(resource.MustParse("-1")) != 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "SpotMaxPrice"), r.Spec.SpotMaxPrice.String(), "must be -1 or greater than 0"))
+	}
+	return allErrs
+}
+
+// getControlPlane returns the AzureManagedControlPlane of the cluster of the node pool, or nil if it doesn't exist yet.
+func (r *AzureManagedMachinePool) getControlPlane(cli client.Client) (*AzureManagedControlPlane, error) {
+	ctx := context.Background()
+
+	clusterName, ok := r.Labels[clusterv1.ClusterLabelName]
+	if !ok {
+		return nil, nil
+	}
+
+	ownerCluster := &clusterv1.Cluster{}
+	key := client.ObjectKey{
+		Namespace: r.Namespace,
+		Name:      clusterName,
+	}
+	if err := cli.Get(ctx, key, ownerCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ref := ownerCluster.Spec.ControlPlaneRef
+	if ref == nil || ref.Kind != "AzureManagedControlPlane" {
+		return nil, nil
+	}
+
+	controlPlane := &AzureManagedControlPlane{}
+	key = client.ObjectKey{
+		Namespace: r.Namespace,
+		Name:      ref.Name,
+	}
+	if err := cli.Get(ctx, key, controlPlane); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return controlPlane, nil
+}
+
 func ensureStringSlicesAreEqual(a []string, b []string) bool {
 	if len(a) != len(b) {
 		return false
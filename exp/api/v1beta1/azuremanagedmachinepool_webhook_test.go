@@ -21,8 +21,15 @@ import (
 
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
 func TestAzureManagedMachinePoolDefaultingWebhook(t *testing.T) {
@@ -185,6 +192,131 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Cannot change Taints of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:   "User",
+					SKU:    "StandardD2S_V3",
+					Taints: []Taint{{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoExecute}},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:   "User",
+					SKU:    "StandardD2S_V3",
+					Taints: []Taint{{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Can change NodeLabels and Mode of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "System",
+					SKU:        "StandardD2S_V3",
+					NodeLabels: map[string]string{"workload": "batch"},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "User",
+					SKU:        "StandardD2S_V3",
+					NodeLabels: map[string]string{"workload": "web"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Cannot set NodeLabels with the prefix reserved by AKS",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "User",
+					SKU:        "StandardD2S_V3",
+					NodeLabels: map[string]string{"kubernetes.azure.com/mode": "user"},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot change ScaleSetPriority of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:             "User",
+					SKU:              "StandardD2S_V3",
+					ScaleSetPriority: scaleSetPriorityPtr(ScaleSetPrioritySpot),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot change SpotMaxPrice of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:             "User",
+					SKU:              "StandardD2S_V3",
+					ScaleSetPriority: scaleSetPriorityPtr(ScaleSetPrioritySpot),
+					SpotMaxPrice:     resource.NewMilliQuantity(200, resource.DecimalSI),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:             "User",
+					SKU:              "StandardD2S_V3",
+					ScaleSetPriority: scaleSetPriorityPtr(ScaleSetPrioritySpot),
+					SpotMaxPrice:     resource.NewMilliQuantity(100, resource.DecimalSI),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot change a Spot agentpool to a system agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:             "System",
+					SKU:              "StandardD2S_V3",
+					ScaleSetPriority: scaleSetPriorityPtr(ScaleSetPrioritySpot),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:             "User",
+					SKU:              "StandardD2S_V3",
+					ScaleSetPriority: scaleSetPriorityPtr(ScaleSetPrioritySpot),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot change SubnetName of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "User",
+					SKU:        "StandardD2S_V3",
+					SubnetName: to.StringPtr("pool1"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: true,
+		},
 	}
 	var client client.Client
 	for _, tc := range tests {
@@ -198,3 +330,236 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureManagedMachinePoolMaxSizeWebhook(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = AddToScheme(scheme)
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{Kind: "AzureManagedControlPlane", Name: "my-control-plane", Namespace: "default"},
+		},
+	}
+	controlPlane := &AzureManagedControlPlane{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-control-plane", Namespace: "default"},
+		Spec: AzureManagedControlPlaneSpec{
+			NetworkPlugin: to.StringPtr(NetworkPluginAzure),
+			VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+				Subnet: ManagedControlPlaneSubnet{CIDRBlock: "10.240.0.0/24"},
+			},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, controlPlane).Build()
+
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		scaling    *ManagedMachinePoolScaling
+		subnetName *string
+		wantErr    bool
+	}{
+		{
+			name:    "node pool without autoscaling",
+			labels:  map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+			scaling: nil,
+			wantErr: false,
+		},
+		{
+			name:    "maximum size the subnet can hold",
+			labels:  map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+			scaling: &ManagedMachinePoolScaling{MinSize: to.Int32Ptr(1), MaxSize: to.Int32Ptr(8)},
+			wantErr: false,
+		},
+		{
+			name:    "maximum size exceeding what the subnet can hold",
+			labels:  map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+			scaling: &ManagedMachinePoolScaling{MinSize: to.Int32Ptr(1), MaxSize: to.Int32Ptr(9)},
+			wantErr: true,
+		},
+		{
+			name:       "node pool in its own subnet",
+			labels:     map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+			scaling:    &ManagedMachinePoolScaling{MinSize: to.Int32Ptr(1), MaxSize: to.Int32Ptr(100)},
+			subnetName: to.StringPtr("pool0-subnet"),
+			wantErr:    false,
+		},
+		{
+			name:    "cluster doesn't exist yet",
+			labels:  map[string]string{clusterv1.ClusterLabelName: "other-cluster"},
+			scaling: &ManagedMachinePoolScaling{MinSize: to.Int32Ptr(1), MaxSize: to.Int32Ptr(1000)},
+			wantErr: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ammp := &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool0", Namespace: "default", Labels: tc.labels},
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "User",
+					SKU:        "Standard_D2s_v3",
+					Scaling:    tc.scaling,
+					SubnetName: tc.subnetName,
+				},
+			}
+			err := ammp.ValidateCreate(client)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			err = ammp.ValidateUpdate(ammp.DeepCopy(), client)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureManagedMachinePoolLabelsAndTaintsWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		nodeLabels map[string]string
+		taints     []Taint
+		wantErr    bool
+	}{
+		{
+			name:       "valid labels and taints",
+			nodeLabels: map[string]string{"example.com/workload": "batch"},
+			taints:     []Taint{{Key: "dedicated", Value: "batch", Effect: TaintEffectNoSchedule}, {Key: "example.com/spot", Effect: TaintEffectPreferNoSchedule}},
+			wantErr:    false,
+		},
+		{
+			name:       "invalid label key",
+			nodeLabels: map[string]string{"-workload": "batch"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid label value",
+			nodeLabels: map[string]string{"workload": "batch jobs"},
+			wantErr:    true,
+		},
+		{
+			name:       "label with the prefix reserved by AKS",
+			nodeLabels: map[string]string{"kubernetes.azure.com/scalesetpriority": "spot"},
+			wantErr:    true,
+		},
+		{
+			name:    "invalid taint key",
+			taints:  []Taint{{Key: "dedicated=", Value: "batch", Effect: TaintEffectNoSchedule}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid taint value",
+			taints:  []Taint{{Key: "dedicated", Value: "batch:jobs", Effect: TaintEffectNoSchedule}},
+			wantErr: true,
+		},
+	}
+	var client client.Client
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ammp := &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool0", Namespace: "default"},
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "User",
+					SKU:        "Standard_D2s_v3",
+					NodeLabels: tc.nodeLabels,
+					Taints:     tc.taints,
+				},
+			}
+			err := ammp.ValidateCreate(client)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureManagedMachinePoolSpotWebhook(t *testing.T) {
+	evictionPolicy := infrav1.SpotEvictionPolicyDeallocate
+	tests := []struct {
+		name           string
+		mode           string
+		priority       *ScaleSetPriority
+		evictionPolicy *infrav1.SpotEvictionPolicy
+		maxPrice       *resource.Quantity
+		wantErr        bool
+	}{
+		{
+			name:           "Spot node pool with eviction policy and maximum price",
+			mode:           "User",
+			priority:       scaleSetPriorityPtr(ScaleSetPrioritySpot),
+			evictionPolicy: &evictionPolicy,
+			maxPrice:       resource.NewMilliQuantity(150, resource.DecimalSI),
+			wantErr:        false,
+		},
+		{
+			name:     "Spot node pool capped at the on-demand price",
+			mode:     "User",
+			priority: scaleSetPriorityPtr(ScaleSetPrioritySpot),
+			maxPrice: resource.NewQuantity(-1, resource.DecimalSI),
+			wantErr:  false,
+		},
+		{
+			name:     "Spot node pool with a maximum price of 0",
+			mode:     "User",
+			priority: scaleSetPriorityPtr(ScaleSetPrioritySpot),
+			maxPrice: resource.NewQuantity(0, resource.DecimalSI),
+			wantErr:  true,
+		},
+		{
+			name:     "Spot system node pool",
+			mode:     "System",
+			priority: scaleSetPriorityPtr(ScaleSetPrioritySpot),
+			wantErr:  true,
+		},
+		{
+			name:           "eviction policy on a regular node pool",
+			mode:           "User",
+			priority:       scaleSetPriorityPtr(ScaleSetPriorityRegular),
+			evictionPolicy: &evictionPolicy,
+			wantErr:        true,
+		},
+		{
+			name:     "maximum price without priority",
+			mode:     "User",
+			maxPrice: resource.NewMilliQuantity(150, resource.DecimalSI),
+			wantErr:  true,
+		},
+	}
+	var client client.Client
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ammp := &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool0", Namespace: "default"},
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:                   tc.mode,
+					SKU:                    "Standard_D2s_v3",
+					ScaleSetPriority:       tc.priority,
+					ScaleSetEvictionPolicy: tc.evictionPolicy,
+					SpotMaxPrice:           tc.maxPrice,
+				},
+			}
+			err := ammp.ValidateCreate(client)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func scaleSetPriorityPtr(priority ScaleSetPriority) *ScaleSetPriority {
+	return &priority
+}
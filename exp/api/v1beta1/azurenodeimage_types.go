@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+const (
+	// AzureNodeImageFinalizer allows the reconciler to delete the image template of an AzureNodeImage before removing it
+	// from the apiserver.
+	AzureNodeImageFinalizer = "azurenodeimage.infrastructure.cluster.x-k8s.io"
+
+	// ImageBuiltCondition reports whether the image of an AzureNodeImage was built by Azure Image Builder and published
+	// to its gallery.
+	ImageBuiltCondition clusterv1.ConditionType = "ImageBuilt"
+	// ImageBuildingReason means Azure Image Builder is building the image.
+	ImageBuildingReason = "Building"
+)
+
+// AzureNodeImageSpec defines the desired state of AzureNodeImage.
+type AzureNodeImageSpec struct {
+	// SubscriptionID is the GUID of the Azure subscription holding the image template and the gallery.
+	// +optional
+	SubscriptionID string `json:"subscriptionID,omitempty"`
+
+	// ResourceGroup is the name of the resource group the image template is created in. It must exist.
+	// +kubebuilder:validation:MinLength=1
+	ResourceGroup string `json:"resourceGroup"`
+
+	// Location is the region the image is built in. Examples: "westus2", "eastus".
+	// +kubebuilder:validation:MinLength=1
+	Location string `json:"location"`
+
+	// IdentityRef is a reference to a AzureClusterIdentity to be used when reconciling this node image.
+	// +optional
+	IdentityRef *corev1.ObjectReference `json:"identityRef,omitempty"`
+
+	// BuildIdentityID is the resource ID of the user-assigned identity Azure Image Builder uses to build the image. It
+	// must be able to read the source image and to write image versions to the gallery.
+	// +kubebuilder:validation:MinLength=1
+	BuildIdentityID string `json:"buildIdentityID"`
+
+	// Source is the base image the node image is built from.
+	Source NodeImageSource `json:"source"`
+
+	// Customizations are the steps run in order on the base image to bake the node image.
+	// +optional
+	Customizations []NodeImageCustomization `json:"customizations,omitempty"`
+
+	// Distribution is the gallery image definition the node image is published to as a new image version.
+	Distribution NodeImageDistribution `json:"distribution"`
+
+	// VMSize is the size of the VM used to build the image. Defaults to Azure Image Builder's default size.
+	// +optional
+	VMSize string `json:"vmSize,omitempty"`
+
+	// OSDiskSizeGB is the size of the OS disk of the VM used to build the image. Defaults to the size of the base image.
+	// +optional
+	OSDiskSizeGB *int32 `json:"osDiskSizeGB,omitempty"`
+
+	// BuildTimeoutInMinutes is the maximum duration of the build. Defaults to 4 hours.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=960
+	// +optional
+	BuildTimeoutInMinutes *int32 `json:"buildTimeoutInMinutes,omitempty"`
+
+	// AdditionalTags is an optional set of tags to add to the image template and to the published image version.
+	// +optional
+	AdditionalTags infrav1.Tags `json:"additionalTags,omitempty"`
+}
+
+// NodeImageSource defines the base image of a node image. Exactly one of the fields must be set.
+type NodeImageSource struct {
+	// Marketplace is an Azure Marketplace image to build the node image from.
+	// +optional
+	Marketplace *infrav1.AzureMarketplaceImage `json:"marketplace,omitempty"`
+
+	// SharedGallery is a shared gallery image version to build the node image from.
+	// +optional
+	SharedGallery *infrav1.AzureSharedGalleryImage `json:"sharedGallery,omitempty"`
+}
+
+// NodeImageCustomization defines a step customizing the base image of a node image. Exactly one of Shell, PowerShell
+// and File must be set.
+type NodeImageCustomization struct {
+	// Name describes what the step does.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Shell runs a shell script on a Linux image.
+	// +optional
+	Shell *ScriptCustomization `json:"shell,omitempty"`
+
+	// PowerShell runs a PowerShell script on a Windows image.
+	// +optional
+	PowerShell *ScriptCustomization `json:"powerShell,omitempty"`
+
+	// File downloads a file onto the image.
+	// +optional
+	File *FileCustomization `json:"file,omitempty"`
+}
+
+// ScriptCustomization defines a script run on the base image. Exactly one of Inline and ScriptURI must be set.
+type ScriptCustomization struct {
+	// Inline are the commands of the script.
+	// +optional
+	Inline []string `json:"inline,omitempty"`
+
+	// ScriptURI is the URI the script is downloaded from, e.g. a GitHub link or a storage blob SAS URI.
+	// +optional
+	ScriptURI string `json:"scriptURI,omitempty"`
+
+	// SHA256Checksum is the checksum of the script downloaded from ScriptURI.
+	// +optional
+	SHA256Checksum string `json:"sha256Checksum,omitempty"`
+}
+
+// FileCustomization defines a file downloaded onto the base image.
+type FileCustomization struct {
+	// SourceURI is the URI the file is downloaded from.
+	// +kubebuilder:validation:MinLength=1
+	SourceURI string `json:"sourceURI"`
+
+	// Destination is the absolute path of the file on the image.
+	// +kubebuilder:validation:MinLength=1
+	Destination string `json:"destination"`
+
+	// SHA256Checksum is the checksum of the file.
+	// +optional
+	SHA256Checksum string `json:"sha256Checksum,omitempty"`
+}
+
+// NodeImageDistribution defines the gallery image definition a node image is published to. The image definition must
+// exist in a gallery of the subscription of the AzureNodeImage.
+type NodeImageDistribution struct {
+	// ResourceGroup is the resource group of the gallery.
+	// +kubebuilder:validation:MinLength=1
+	ResourceGroup string `json:"resourceGroup"`
+
+	// Gallery is the name of the gallery.
+	// +kubebuilder:validation:MinLength=1
+	Gallery string `json:"gallery"`
+
+	// Name is the name of the image definition.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ReplicationRegions are the regions the image version is replicated to. Defaults to the location of the
+	// AzureNodeImage.
+	// +optional
+	ReplicationRegions []string `json:"replicationRegions,omitempty"`
+
+	// ExcludeFromLatest excludes the image version from the latest version of the image definition.
+	// +optional
+	ExcludeFromLatest bool `json:"excludeFromLatest,omitempty"`
+}
+
+// AzureNodeImageStatus defines the observed state of AzureNodeImage.
+type AzureNodeImageStatus struct {
+	// Ready is true when the node image is published to its gallery.
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// Image is the gallery image version the node image was published as. It can be used as the image of
+	// AzureMachineTemplates.
+	// +optional
+	Image *infrav1.Image `json:"image,omitempty"`
+
+	// RunState is the state of the build of the image, as reported by Azure Image Builder.
+	// +optional
+	RunState string `json:"runState,omitempty"`
+
+	// RunMessage is the message of the last state of the build of the image, as reported by Azure Image Builder.
+	// +optional
+	RunMessage string `json:"runMessage,omitempty"`
+
+	// Conditions defines current service state of the AzureNodeImage.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// LongRunningOperationStates saves the states for Azure long-running operations so they can be continued on the
+	// next reconciliation loop.
+	// +optional
+	LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=azurenodeimages,scope=Namespaced,categories=cluster-api,shortName=ani
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Node image is published to its gallery"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.runState",description="State of the build of the image"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of AzureNodeImage"
+
+// AzureNodeImage is the Schema for the azurenodeimages API. It bakes a node image with Azure Image Builder and
+// publishes it to a gallery.
+type AzureNodeImage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AzureNodeImageSpec   `json:"spec,omitempty"`
+	Status AzureNodeImageStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AzureNodeImageList contains a list of AzureNodeImages.
+type AzureNodeImageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AzureNodeImage `json:"items"`
+}
+
+// GetConditions returns the list of conditions for an AzureNodeImage API object.
+func (ani *AzureNodeImage) GetConditions() clusterv1.Conditions {
+	return ani.Status.Conditions
+}
+
+// SetConditions will set the given conditions on an AzureNodeImage object.
+func (ani *AzureNodeImage) SetConditions(conditions clusterv1.Conditions) {
+	ani.Status.Conditions = conditions
+}
+
+// GetFutures returns the list of long running operation states for an AzureNodeImage API object.
+func (ani *AzureNodeImage) GetFutures() infrav1.Futures {
+	return ani.Status.LongRunningOperationStates
+}
+
+// SetFutures will set the given long running operation states on an AzureNodeImage object.
+func (ani *AzureNodeImage) SetFutures(futures infrav1.Futures) {
+	ani.Status.LongRunningOperationStates = futures
+}
+
+func init() {
+	SchemeBuilder.Register(&AzureNodeImage{}, &AzureNodeImageList{})
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// SetupWebhookWithManager sets up and registers the webhook with the manager.
+func (ani *AzureNodeImage) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(ani).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azurenodeimage,mutating=false,failurePolicy=fail,groups=infrastructure.cluster.x-k8s.io,resources=azurenodeimages,versions=v1beta1,name=validation.azurenodeimage.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+var _ webhook.Validator = &AzureNodeImage{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (ani *AzureNodeImage) ValidateCreate() error {
+	if allErrs := ani.validateSpec(); len(allErrs) > 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("AzureNodeImage").GroupKind(), ani.Name, allErrs)
+	}
+	return nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+// Azure Image Builder templates can't be updated, so the spec is immutable: a new image is built by a new AzureNodeImage.
+func (ani *AzureNodeImage) ValidateUpdate(oldRaw runtime.Object) error {
+	old := oldRaw.(*AzureNodeImage)
+	if !reflect.DeepEqual(ani.Spec, old.Spec) {
+		return apierrors.NewInvalid(GroupVersion.WithKind("AzureNodeImage").GroupKind(), ani.Name, field.ErrorList{
+			field.Forbidden(field.NewPath("Spec"), "field is immutable, create a new AzureNodeImage to build a new image"),
+		})
+	}
+	return nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (ani *AzureNodeImage) ValidateDelete() error {
+	return nil
+}
+
+// validateSpec validates the spec of an AzureNodeImage.
+func (ani *AzureNodeImage) validateSpec() field.ErrorList {
+	var allErrs field.ErrorList
+
+	source := &infrav1.Image{
+		Marketplace:   ani.Spec.Source.Marketplace,
+		SharedGallery: ani.Spec.Source.SharedGallery,
+	}
+	allErrs = append(allErrs, infrav1.ValidateImage(source, field.NewPath("Spec", "Source"))...)
+
+	for i, customization := range ani.Spec.Customizations {
+		allErrs = append(allErrs, validateNodeImageCustomization(customization, field.NewPath("Spec", "Customizations").Index(i))...)
+	}
+
+	return allErrs
+}
+
+// validateNodeImageCustomization validates that a customization step defines exactly one kind of step.
+func validateNodeImageCustomization(customization NodeImageCustomization, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	steps := 0
+	if customization.Shell != nil {
+		steps++
+		allErrs = append(allErrs, validateScriptCustomization(customization.Shell, fldPath.Child("Shell"))...)
+	}
+	if customization.PowerShell != nil {
+		steps++
+		allErrs = append(allErrs, validateScriptCustomization(customization.PowerShell, fldPath.Child("PowerShell"))...)
+	}
+	if customization.File != nil {
+		steps++
+	}
+	if steps != 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath, customization.Name, "exactly one of Shell, PowerShell and File must be set"))
+	}
+
+	return allErrs
+}
+
+// validateScriptCustomization validates that a script is either inline or downloaded.
+func validateScriptCustomization(script *ScriptCustomization, fldPath *field.Path) field.ErrorList {
+	if (len(script.Inline) == 0) == (script.ScriptURI == "") {
+		return field.ErrorList{field.Required(fldPath, "exactly one of Inline and ScriptURI must be set")}
+	}
+	if script.SHA256Checksum != "" && script.ScriptURI == "" {
+		return field.ErrorList{field.Forbidden(fldPath.Child("SHA256Checksum"), "SHA256Checksum can only be set with ScriptURI")}
+	}
+	return nil
+}
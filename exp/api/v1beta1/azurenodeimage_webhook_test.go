@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func createNodeImage() *AzureNodeImage {
+	return &AzureNodeImage{
+		Spec: AzureNodeImageSpec{
+			ResourceGroup:   "my-rg",
+			Location:        "westus2",
+			BuildIdentityID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/aib",
+			Source: NodeImageSource{
+				Marketplace: &infrav1.AzureMarketplaceImage{
+					Publisher: "cncf-upstream",
+					Offer:     "capi",
+					SKU:       "ubuntu-2004-gen1",
+					Version:   "latest",
+				},
+			},
+			Customizations: []NodeImageCustomization{
+				{
+					Name:  "install-packages",
+					Shell: &ScriptCustomization{Inline: []string{"sudo apt-get install -y jq"}},
+				},
+			},
+			Distribution: NodeImageDistribution{
+				ResourceGroup: "my-rg",
+				Gallery:       "my_gallery",
+				Name:          "capi-ubuntu-2004",
+			},
+		},
+	}
+}
+
+func TestAzureNodeImage_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*AzureNodeImage)
+		wantErr bool
+	}{
+		{
+			name:    "valid node image",
+			mutate:  func(*AzureNodeImage) {},
+			wantErr: false,
+		},
+		{
+			name: "valid node image from a shared gallery image",
+			mutate: func(ani *AzureNodeImage) {
+				ani.Spec.Source = NodeImageSource{
+					SharedGallery: &infrav1.AzureSharedGalleryImage{
+						SubscriptionID: "123",
+						ResourceGroup:  "my-rg",
+						Gallery:        "my_gallery",
+						Name:           "capi-ubuntu-2004",
+						Version:        "1.0.0",
+					},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "no source image",
+			mutate: func(ani *AzureNodeImage) {
+				ani.Spec.Source = NodeImageSource{}
+			},
+			wantErr: true,
+		},
+		{
+			name: "both marketplace and shared gallery source images",
+			mutate: func(ani *AzureNodeImage) {
+				ani.Spec.Source.SharedGallery = &infrav1.AzureSharedGalleryImage{
+					SubscriptionID: "123",
+					ResourceGroup:  "my-rg",
+					Gallery:        "my_gallery",
+					Name:           "capi-ubuntu-2004",
+					Version:        "1.0.0",
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "customization without step",
+			mutate: func(ani *AzureNodeImage) {
+				ani.Spec.Customizations = []NodeImageCustomization{{Name: "nothing"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "customization with two steps",
+			mutate: func(ani *AzureNodeImage) {
+				ani.Spec.Customizations[0].File = &FileCustomization{SourceURI: "https://example.com/file", Destination: "/tmp/file"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "script both inline and downloaded",
+			mutate: func(ani *AzureNodeImage) {
+				ani.Spec.Customizations[0].Shell.ScriptURI = "https://example.com/script.sh"
+			},
+			wantErr: true,
+		},
+		{
+			name: "checksum of an inline script",
+			mutate: func(ani *AzureNodeImage) {
+				ani.Spec.Customizations[0].Shell.SHA256Checksum = "abc"
+			},
+			wantErr: true,
+		},
+		{
+			name: "downloaded PowerShell script with checksum",
+			mutate: func(ani *AzureNodeImage) {
+				ani.Spec.Customizations = []NodeImageCustomization{
+					{
+						Name:       "configure",
+						PowerShell: &ScriptCustomization{ScriptURI: "https://example.com/script.ps1", SHA256Checksum: "abc"},
+					},
+				}
+			},
+			wantErr: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ani := createNodeImage()
+			tc.mutate(ani)
+			err := ani.ValidateCreate()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureNodeImage_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	old := createNodeImage()
+	ani := createNodeImage()
+	ani.Status.Ready = true
+	g.Expect(ani.ValidateUpdate(old)).To(Succeed())
+
+	ani.Spec.Customizations[0].Shell.Inline = []string{"sudo apt-get install -y curl"}
+	g.Expect(ani.ValidateUpdate(old)).NotTo(Succeed())
+}
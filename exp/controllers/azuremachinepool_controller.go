@@ -37,6 +37,7 @@ import (
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -54,6 +55,8 @@ type (
 		Recorder                      record.EventRecorder
 		ReconcileTimeout              time.Duration
 		WatchFilterValue              string
+		resyncPeriods                 reconciler.ResyncPeriods
+		acceptMarketplaceTerms        bool
 		createAzureMachinePoolService azureMachinePoolServiceCreator
 	}
 
@@ -88,6 +91,8 @@ func (ampr *AzureMachinePoolReconciler) SetupWithManager(ctx context.Context, mg
 	)
 	defer done()
 
+	ampr.resyncPeriods = options.ResyncPeriods
+	ampr.acceptMarketplaceTerms = options.AcceptMarketplaceTerms
 	var r reconcile.Reconciler = ampr
 	if options.Cache != nil {
 		r = coalescing.NewReconciler(ampr, options.Cache, log)
@@ -149,13 +154,14 @@ func (ampr *AzureMachinePoolReconciler) SetupWithManager(ctx context.Context, mg
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepools/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepoolmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepoolmachines/status,verbs=get
-// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=bootstrap.cluster.x-k8s.io,resources=kubeadmconfigs,verbs=get
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 
 // Reconcile idempotently gets, creates, and updates a machine pool.
-func (ampr *AzureMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+func (ampr *AzureMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, reterr error) {
 	ctx, logger, done := tele.StartSpanWithLogger(
 		ctx,
 		"controllers.AzureMachinePoolReconciler.Reconcile",
@@ -228,10 +234,14 @@ func (ampr *AzureMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	// Create the machine pool scope
 	machinePoolScope, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
-		Client:           ampr.Client,
-		MachinePool:      machinePool,
-		AzureMachinePool: azMachinePool,
-		ClusterScope:     clusterScope,
+		Client:                    ampr.Client,
+		MachinePool:               machinePool,
+		AzureMachinePool:          azMachinePool,
+		ClusterScope:              clusterScope,
+		ForceDelete:               clusterScope.ForceDeleteVirtualMachines(),
+		AcceptMarketplaceTerms:    ampr.acceptMarketplaceTerms,
+		CordonedZones:             clusterScope.CordonedZoneNames(),
+		ProximityPlacementGroupID: clusterScope.ProximityPlacementGroupID(),
 	})
 	if err != nil {
 		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
@@ -239,9 +249,20 @@ func (ampr *AzureMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	// Always close the scope when exiting this function so we can persist any AzureMachine changes.
 	defer func() {
-		if err := machinePoolScope.Close(ctx); err != nil && reterr == nil {
-			reterr = err
+		err := machinePoolScope.Close(ctx)
+		if err == nil || reterr != nil {
+			return
 		}
+		// requeue no later than requested when the deletion of AzureMachinePoolMachines must wait
+		var reconcileError azure.ReconcileError
+		if errors.As(err, &reconcileError) && reconcileError.IsTransient() {
+			logger.V(2).Info("requeuing AzureMachinePool", "reason", err.Error())
+			if result.RequeueAfter == 0 || reconcileError.RequeueAfter() < result.RequeueAfter {
+				result.RequeueAfter = reconcileError.RequeueAfter()
+			}
+			return
+		}
+		reterr = err
 	}()
 
 	// Handle deleted machine pools
@@ -282,6 +303,16 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 		return reconcile.Result{}, nil
 	}
 
+	// Make sure new instances have time to join the cluster before their bootstrap token expires. The bootstrap provider
+	// rotates the token of machine pools, so wait for it instead of scaling up with a token about to expire.
+	if machinePoolScope.MachinePool.Spec.Replicas != nil && *machinePoolScope.MachinePool.Spec.Replicas > machinePoolScope.AzureMachinePool.Status.Replicas &&
+		ampr.bootstrapTokenExpiring(ctx, machinePoolScope, clusterScope) {
+		log.Info("Bootstrap token is about to expire, waiting for it to be rotated before scaling up")
+		conditions.MarkFalse(machinePoolScope.AzureMachinePool, infrav1.ScaleSetDesiredReplicasCondition, infrav1.BootstrapTokenExpiringReason, clusterv1.ConditionSeverityWarning,
+			"bootstrap token expires in less than %s, waiting for the bootstrap provider to rotate it", infracontroller.BootstrapTokenExpiryMargin)
+		return reconcile.Result{RequeueAfter: infracontroller.BootstrapTokenRequeueAfter}, nil
+	}
+
 	ams, err := ampr.createAzureMachinePoolService(machinePoolScope)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed creating a newAzureMachinePoolService")
@@ -322,13 +353,42 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 		return reconcile.Result{}, errors.Wrap(err, "Scale set deleted, retry creating in next reconcile")
 	}
 
+	if err := machinePoolScope.AnnotateScheduledEvents(ctx); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to check the nodes of the AzureMachinePool for scheduled events")
+	}
+
 	if machinePoolScope.NeedsRequeue() {
 		return reconcile.Result{
 			RequeueAfter: 30 * time.Second,
 		}, nil
 	}
 
-	return reconcile.Result{}, nil
+	provisioning := !clusterScope.Cluster.Status.ControlPlaneReady || !machinePoolScope.AzureMachinePool.Status.Ready
+	requeueAfter := ampr.resyncPeriods.RequeueAfter(machinePoolScope.AzureMachinePool, provisioning)
+	// keep checking the nodes of the pool for the events Azure schedules for their instances
+	if interval := machinePoolScope.ScheduledEventsPollInterval(); interval > 0 && (requeueAfter == 0 || interval < requeueAfter) {
+		requeueAfter = interval
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// bootstrapTokenExpiring reports whether the bootstrap token of the machine pool expires too soon to create new
+// instances. Failures to check the token are logged and don't block the scale up.
+func (ampr *AzureMachinePoolReconciler) bootstrapTokenExpiring(ctx context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) bool {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachinePoolReconciler.bootstrapTokenExpiring")
+	defer done()
+
+	bootstrapData, err := machinePoolScope.GetBootstrapData(ctx)
+	if err != nil {
+		log.V(2).Info("failed to get bootstrap data to check its bootstrap token", "error", err.Error())
+		return false
+	}
+	expiring, err := infracontroller.BootstrapTokenExpiring(ctx, ampr.Client, util.ObjectKey(clusterScope.Cluster), bootstrapData)
+	if err != nil {
+		log.V(2).Info("failed to check the bootstrap token expiration", "error", err.Error())
+		return false
+	}
+	return expiring
 }
 
 func (ampr *AzureMachinePoolReconciler) reconcileDelete(ctx context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
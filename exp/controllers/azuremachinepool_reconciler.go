@@ -23,9 +23,11 @@ import (
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/marketplaceterms"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/spotplacementscores"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmssextensions"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -33,6 +35,8 @@ import (
 // azureMachinePoolService is the group of services called by the AzureMachinePool controller.
 type azureMachinePoolService struct {
 	scope                      *scope.MachinePoolScope
+	spotPlacementScoresSvc     azure.Reconciler
+	marketplaceTermsSvc        azure.Reconciler
 	virtualMachinesScaleSetSvc azure.Reconciler
 	skuCache                   *resourceskus.Cache
 	roleAssignmentsSvc         azure.Reconciler
@@ -50,6 +54,8 @@ func newAzureMachinePoolService(machinePoolScope *scope.MachinePoolScope) (*azur
 
 	return &azureMachinePoolService{
 		scope:                      machinePoolScope,
+		spotPlacementScoresSvc:     spotplacementscores.New(machinePoolScope),
+		marketplaceTermsSvc:        marketplaceterms.New(machinePoolScope),
 		virtualMachinesScaleSetSvc: scalesets.NewService(machinePoolScope, cache),
 		skuCache:                   cache,
 		roleAssignmentsSvc:         roleassignments.New(machinePoolScope),
@@ -66,10 +72,26 @@ func (s *azureMachinePoolService) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed defaulting subnet name")
 	}
 
+	if err := s.spotPlacementScoresSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "failed to check spot placement score")
+	}
+
+	if err := s.marketplaceTermsSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "failed to accept marketplace terms")
+	}
+
 	if err := s.virtualMachinesScaleSetSvc.Reconcile(ctx); err != nil {
 		return errors.Wrap(err, "failed to create scale set")
 	}
 
+	sku, err := s.skuCache.Get(ctx, s.scope.AzureMachinePool.Spec.Template.VMSize, resourceskus.VirtualMachines)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the SKU of the VM size")
+	}
+	if err := s.scope.UpdateAutoscalerCapacity(ctx, sku); err != nil {
+		return errors.Wrap(err, "failed to update the cluster-autoscaler capacity annotations")
+	}
+
 	if err := s.roleAssignmentsSvc.Reconcile(ctx); err != nil {
 		return errors.Wrap(err, "unable to create role assignment")
 	}
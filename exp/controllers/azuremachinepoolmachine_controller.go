@@ -355,10 +355,18 @@ func (r *azureMachinePoolMachineReconciler) Reconcile(ctx context.Context) error
 		return errors.Wrap(err, "failed to reconcile scalesetVMs")
 	}
 
+	if err := r.scalesetVMsService.Remediate(ctx); err != nil {
+		return errors.Wrap(err, "failed to remediate scalesetVMs")
+	}
+
 	if err := r.Scope.UpdateStatus(ctx); err != nil {
 		return errors.Wrap(err, "failed to update vmss vm status")
 	}
 
+	if err := r.Scope.HandleScheduledEvents(ctx); err != nil {
+		return errors.Wrap(err, "failed to handle scheduled events")
+	}
+
 	return nil
 }
 
@@ -378,6 +386,10 @@ func (r *azureMachinePoolMachineReconciler) Delete(ctx context.Context) error {
 		return errors.Wrap(err, "failed to cordon and drain the scalesetVMs")
 	}
 
+	if err := r.Scope.WaitForVolumeDetach(ctx); err != nil {
+		return errors.Wrap(err, "failed to wait for the volumes of the scalesetVMs to be detached")
+	}
+
 	if err := r.scalesetVMsService.Delete(ctx); err != nil {
 		return errors.Wrap(err, "failed to reconcile scalesetVMs")
 	}
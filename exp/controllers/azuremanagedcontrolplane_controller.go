@@ -232,11 +232,16 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcileNormal(ctx context.Con
 		return reconcile.Result{}, errors.Wrapf(err, "error creating AzureManagedControlPlane %s/%s", scope.ControlPlane.Namespace, scope.ControlPlane.Name)
 	}
 
+	if err := scope.SetSubnetUtilization(ctx); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to report subnet utilization")
+	}
+
 	// No errors, so mark us ready so the Cluster API Cluster Controller can pull it
 	scope.ControlPlane.Status.Ready = true
 	scope.ControlPlane.Status.Initialized = true
 	amcpr.Recorder.Event(scope.ControlPlane, corev1.EventTypeNormal, "AzureManagedControlPlane available", "successfully reconciled")
-	return reconcile.Result{}, nil
+	// refresh the kubeconfig before its token expires
+	return reconcile.Result{RequeueAfter: scope.KubeConfigRefreshAfter()}, nil
 }
 
 func (amcpr *AzureManagedControlPlaneReconciler) reconcileDelete(ctx context.Context, scope *scope.ManagedControlPlaneScope) (reconcile.Result, error) {
@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
@@ -42,6 +43,7 @@ type azureManagedControlPlaneService struct {
 	groupsSvc          azure.Reconciler
 	vnetSvc            azure.Reconciler
 	subnetsSvc         azure.Reconciler
+	roleAssignmentsSvc azure.Reconciler
 	tagsSvc            azure.Reconciler
 }
 
@@ -54,6 +56,7 @@ func newAzureManagedControlPlaneReconciler(scope *scope.ManagedControlPlaneScope
 		groupsSvc:          groups.New(scope),
 		vnetSvc:            virtualnetworks.New(scope),
 		subnetsSvc:         subnets.New(scope),
+		roleAssignmentsSvc: roleassignments.New(scope),
 		tagsSvc:            tags.New(scope),
 	}
 }
@@ -80,6 +83,10 @@ func (r *azureManagedControlPlaneService) Reconcile(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to reconcile managed cluster")
 	}
 
+	if err := r.roleAssignmentsSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "unable to create role assignment")
+	}
+
 	if err := r.reconcileKubeconfig(ctx); err != nil {
 		return errors.Wrap(err, "failed to reconcile kubeconfig secret")
 	}
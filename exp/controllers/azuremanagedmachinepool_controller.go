@@ -195,6 +195,13 @@ func (ammpr *AzureManagedMachinePoolReconciler) Reconcile(ctx context.Context, r
 		return reconcile.Result{}, nil
 	}
 
+	// The agent pools of a stopped cluster can't be updated. They are reconciled again once the control plane is
+	// started.
+	if controlPlane.Status.PowerState == infrav1exp.PowerStateStopped && infraPool.DeletionTimestamp.IsZero() {
+		log.Info("AzureManagedControlPlane is stopped")
+		return reconcile.Result{}, nil
+	}
+
 	// Create the scope.
 	mcpScope, err := scope.NewManagedControlPlaneScope(ctx, scope.ManagedControlPlaneScopeParams{
 		Client:           ammpr.Client,
@@ -0,0 +1,197 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagebuilder"
+	infracontroller "sigs.k8s.io/cluster-api-provider-azure/controllers"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// AzureNodeImageReconciler reconciles an AzureNodeImage object.
+type AzureNodeImageReconciler struct {
+	client.Client
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+}
+
+// SetupWithManager initializes this controller with a manager.
+func (anir *AzureNodeImageReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options infracontroller.Options) error {
+	_, log, done := tele.StartSpanWithLogger(ctx,
+		"controllers.AzureNodeImageReconciler.SetupWithManager",
+		tele.KVP("controller", "AzureNodeImage"),
+	)
+	defer done()
+
+	var r reconcile.Reconciler = anir
+	if options.Cache != nil {
+		r = coalescing.NewReconciler(anir, options.Cache, log)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options.Options).
+		For(&infrav1exp.AzureNodeImage{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, anir.WatchFilterValue)).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurenodeimages,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurenodeimages/status,verbs=get;update;patch
+
+// Reconcile builds the image of an AzureNodeImage with Azure Image Builder and publishes it to its gallery.
+func (anir *AzureNodeImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(anir.ReconcileTimeout))
+	defer cancel()
+
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.AzureNodeImageReconciler.Reconcile",
+		tele.KVP("namespace", req.Namespace),
+		tele.KVP("name", req.Name),
+		tele.KVP("kind", "AzureNodeImage"),
+	)
+	defer done()
+
+	// Fetch the AzureNodeImage instance
+	nodeImage := &infrav1exp.AzureNodeImage{}
+	err := anir.Get(ctx, req.NamespacedName, nodeImage)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// check if the node image's namespace is allowed for this identity.
+	if nodeImage.Spec.IdentityRef != nil {
+		identity, err := infracontroller.GetClusterIdentityFromRef(ctx, anir.Client, nodeImage.Namespace, nodeImage.Spec.IdentityRef)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !scope.IsClusterNamespaceAllowed(ctx, anir.Client, identity.Spec.AllowedNamespaces, nodeImage.Namespace) {
+			return reconcile.Result{}, errors.New("AzureClusterIdentity list of allowed namespaces doesn't include current azure node image namespace")
+		}
+	}
+
+	// Create the scope.
+	nodeImageScope, err := scope.NewNodeImageScope(ctx, scope.NodeImageScopeParams{
+		Client:    anir.Client,
+		NodeImage: nodeImage,
+	})
+	if err != nil {
+		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
+	}
+
+	// Always patch when exiting so we can persist changes to finalizers and status
+	defer func() {
+		if err := nodeImageScope.Close(ctx); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	// Handle deleted node images
+	if !nodeImage.DeletionTimestamp.IsZero() {
+		return anir.reconcileDelete(ctx, nodeImageScope)
+	}
+	// Handle non-deleted node images
+	return anir.reconcileNormal(ctx, nodeImageScope)
+}
+
+func (anir *AzureNodeImageReconciler) reconcileNormal(ctx context.Context, nodeImageScope *scope.NodeImageScope) (reconcile.Result, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureNodeImageReconciler.reconcileNormal")
+	defer done()
+
+	log.Info("Reconciling AzureNodeImage")
+	nodeImage := nodeImageScope.NodeImage
+
+	// The image is built once: a new AzureNodeImage builds a new image.
+	if nodeImage.Status.Ready && nodeImage.Status.Image != nil {
+		return reconcile.Result{}, nil
+	}
+
+	// If the AzureNodeImage doesn't have our finalizer, add it.
+	controllerutil.AddFinalizer(nodeImage, infrav1exp.AzureNodeImageFinalizer)
+	// Register the finalizer immediately to avoid orphaning the image template on delete
+	if err := nodeImageScope.PatchObject(ctx); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := imagebuilder.New(nodeImageScope).Reconcile(ctx); err != nil {
+		// Handle transient and terminal errors
+		var reconcileError azure.ReconcileError
+		if errors.As(err, &reconcileError) {
+			if reconcileError.IsTerminal() {
+				log.Error(err, "failed to build AzureNodeImage")
+				anir.Recorder.Eventf(nodeImage, corev1.EventTypeWarning, "ImageBuildFailed", err.Error())
+				return reconcile.Result{}, nil
+			}
+
+			if reconcileError.IsTransient() {
+				log.V(4).Info("requeuing due to transient failure", "error", err)
+				return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+			}
+
+			return reconcile.Result{}, errors.Wrap(err, "failed to reconcile AzureNodeImage")
+		}
+
+		return reconcile.Result{}, errors.Wrapf(err, "error building AzureNodeImage %s/%s", nodeImage.Namespace, nodeImage.Name)
+	}
+
+	nodeImage.Status.Ready = true
+	anir.Recorder.Event(nodeImage, corev1.EventTypeNormal, "ImageBuilt", fmt.Sprintf("image published as version %s", nodeImage.Status.Image.SharedGallery.Version))
+	return reconcile.Result{}, nil
+}
+
+func (anir *AzureNodeImageReconciler) reconcileDelete(ctx context.Context, nodeImageScope *scope.NodeImageScope) (reconcile.Result, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureNodeImageReconciler.reconcileDelete")
+	defer done()
+
+	log.Info("Reconciling AzureNodeImage delete")
+	nodeImage := nodeImageScope.NodeImage
+
+	if err := imagebuilder.New(nodeImageScope).Delete(ctx); err != nil {
+		var reconcileError azure.ReconcileError
+		if errors.As(err, &reconcileError) && reconcileError.IsTransient() {
+			log.V(4).Info("requeuing due to transient failure", "error", err)
+			return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+		}
+		return reconcile.Result{}, errors.Wrapf(err, "error deleting AzureNodeImage %s/%s", nodeImage.Namespace, nodeImage.Name)
+	}
+
+	// The image template is deleted so remove the finalizer.
+	controllerutil.RemoveFinalizer(nodeImage, infrav1exp.AzureNodeImageFinalizer)
+
+	return reconcile.Result{}, nil
+}
@@ -34,6 +34,33 @@ const (
 	// owner: @alexeldeib
 	// alpha: v0.4
 	AKS featuregate.Feature = "AKS"
+
+	// NICPool is the feature gate for pre-creating network interfaces for the machines of a MachineDeployment.
+	// owner: @nick5616
+	// alpha: v1.1
+	NICPool featuregate.Feature = "NICPool"
+
+	// ResourceGroupScoped is the feature gate for running capz with permissions scoped to pre-created resource groups.
+	// owner: @nick5616
+	// alpha: v1.1
+	ResourceGroupScoped featuregate.Feature = "ResourceGroupScoped"
+
+	// SNATMetrics is the feature gate for collecting the SNAT metrics of the outbound load balancers and NAT gateways
+	// of the clusters.
+	// owner: @nick5616
+	// alpha: v1.1
+	SNATMetrics featuregate.Feature = "SNATMetrics"
+
+	// BulkProvisioning is the feature gate for provisioning the initial network of the clusters with a single ARM
+	// deployment instead of one request per resource.
+	// owner: @nick5616
+	// alpha: v1.1
+	BulkProvisioning featuregate.Feature = "BulkProvisioning"
+
+	// NodeImages is the feature gate for building node images with Azure Image Builder and publishing them to galleries.
+	// owner: @nick5616
+	// alpha: v1.1
+	NodeImages featuregate.Feature = "NodeImages"
 )
 
 func init() {
@@ -44,5 +71,10 @@ func init() {
 // To add a new feature, define a key for it above and add it here.
 var defaultCAPZFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	// Every feature should be initiated here:
-	AKS: {Default: false, PreRelease: featuregate.Alpha},
+	AKS:                 {Default: false, PreRelease: featuregate.Alpha},
+	NICPool:             {Default: false, PreRelease: featuregate.Alpha},
+	ResourceGroupScoped: {Default: false, PreRelease: featuregate.Alpha},
+	SNATMetrics:         {Default: false, PreRelease: featuregate.Alpha},
+	BulkProvisioning:    {Default: false, PreRelease: featuregate.Alpha},
+	NodeImages:          {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package golden compares the payloads sent to Azure with golden files, to catch unintended changes of the generated
+// ARM payloads. Payloads are only reproducible when they don't contain random values, see generators.SetDeterministic.
+package golden
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/cluster-api-provider-azure/util/generators"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the package instead of comparing payloads with them")
+
+// Deterministic makes the generators return reproducible values for the duration of the test.
+// Tests calling it must not run in parallel with tests generating random values.
+func Deterministic(t *testing.T) {
+	t.Helper()
+
+	previous := generators.Deterministic()
+	generators.SetDeterministic(true)
+	t.Cleanup(func() { generators.SetDeterministic(previous) })
+}
+
+// Assert compares the JSON encoding of the payload, as sent to Azure, with the golden file testdata/<name>.golden.json
+// of the package under test. Run the tests of the package with -update to write the golden files.
+func Assert(t *testing.T, name string, payload interface{}) {
+	t.Helper()
+
+	actual, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal payload %s: %v", name, err)
+	}
+	actual = append(actual, '\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory of golden file %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, actual, 0o600); err != nil {
+			t.Fatalf("failed to write golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s, run the tests with -update to create it: %v", path, err)
+	}
+	if diff := cmp.Diff(string(expected), string(actual)); diff != "" {
+		t.Errorf("payload %s differs from golden file %s (-want +got):\n%s", name, path, diff)
+	}
+}
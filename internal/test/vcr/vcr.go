@@ -0,0 +1,348 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vcr records the HTTP interactions of the Azure service clients with ARM into cassettes, and replays them in
+// unit tests, to catch behavior changes of the clients without Azure credentials.
+package vcr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/google/go-cmp/cmp"
+)
+
+var record = flag.Bool("record", false, "record the interactions of the package with Azure into its cassettes instead of replaying them, using the AZURE_* credentials of the environment")
+
+// ReplaySubscriptionID is the subscription ID of the replayed interactions. The subscription ID of the recorded
+// interactions is replaced with it.
+const ReplaySubscriptionID = "00000000-0000-0000-0000-000000000000"
+
+// recordedHeaders are the response headers kept in the cassettes, which the clients need to poll long-running operations.
+var recordedHeaders = []string{"Content-Type", "Azure-AsyncOperation", "Location"}
+
+// Cassette is the sequence of HTTP interactions of a test with ARM.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is an HTTP request sent to ARM and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	StatusCode int               `json:"statusCode"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+}
+
+// Recorder is an autorest.Sender recording or replaying the interactions of a test with ARM, depending on the -record
+// flag. It also implements azure.Authorizer, so that the clients under test can be created with it.
+type Recorder struct {
+	t        *testing.T
+	path     string
+	settings auth.EnvironmentSettings
+	sender   autorest.Sender
+
+	mu       sync.Mutex
+	cassette Cassette
+	next     int
+}
+
+var _ autorest.Sender = (*Recorder)(nil)
+
+// New creates a Recorder for the cassette testdata/<name>.cassette.json of the package under test. Run the tests of
+// the package with -record to record the cassettes against Azure.
+func New(t *testing.T, name string) *Recorder {
+	t.Helper()
+
+	r := &Recorder{
+		t:    t,
+		path: filepath.Join("testdata", name+".cassette.json"),
+	}
+
+	if *record {
+		settings, err := auth.GetSettingsFromEnvironment()
+		if err != nil {
+			t.Fatalf("failed to get the Azure settings of the environment: %v", err)
+		}
+		if settings.GetSubscriptionID() == "" {
+			t.Fatalf("AZURE_SUBSCRIPTION_ID must be set to record cassette %s", r.path)
+		}
+		r.settings = settings
+		t.Cleanup(r.save)
+		return r
+	}
+
+	r.settings = auth.EnvironmentSettings{
+		Values:      map[string]string{auth.SubscriptionID: ReplaySubscriptionID},
+		Environment: azure.PublicCloud,
+	}
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		t.Fatalf("failed to read cassette %s, run the tests with -record to create it: %v", r.path, err)
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		t.Fatalf("failed to unmarshal cassette %s: %v", r.path, err)
+	}
+	t.Cleanup(r.verify)
+	return r
+}
+
+// Apply makes the client send its requests through the Recorder. When replaying, the client doesn't wait between
+// polls of long-running operations and isn't authorized.
+func (r *Recorder) Apply(c *autorest.Client) {
+	if *record {
+		r.sender = c.Sender
+		c.Sender = r
+		return
+	}
+
+	c.Sender = r
+	c.Authorizer = autorest.NullAuthorizer{}
+	c.PollingDelay = 0
+	c.RetryDuration = 0
+}
+
+// Do records or replays the request.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if *record {
+		return r.recordInteraction(req, body)
+	}
+	return r.replayInteraction(req, body)
+}
+
+func (r *Recorder) recordInteraction(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.sender.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    r.scrub(req.URL.String()),
+			Body:   r.scrubBody(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    map[string]string{},
+			Body:       r.scrubBody(respBody),
+		},
+	}
+	for _, header := range recordedHeaders {
+		if v := resp.Header.Get(header); v != "" {
+			interaction.Response.Headers[header] = r.scrub(v)
+		}
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	return resp, nil
+}
+
+func (r *Recorder) replayInteraction(req *http.Request, body []byte) (*http.Response, error) {
+	if r.next >= len(r.cassette.Interactions) {
+		r.t.Errorf("unexpected request %s %s, all %d interactions of cassette %s were replayed", req.Method, req.URL, len(r.cassette.Interactions), r.path)
+		return nil, fmt.Errorf("no interaction left in cassette %s for request %s %s", r.path, req.Method, req.URL)
+	}
+	interaction := r.cassette.Interactions[r.next]
+	r.next++
+
+	actual := Request{Method: req.Method, URL: req.URL.String(), Body: compact(body)}
+	expected := interaction.Request
+	expected.Body = compact(expected.Body)
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		r.t.Errorf("request %d differs from cassette %s (-want +got):\n%s", r.next, r.path, diff)
+		return nil, fmt.Errorf("request %s %s doesn't match interaction %d of cassette %s", req.Method, req.URL, r.next, r.path)
+	}
+
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          io.NopCloser(bytes.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}
+	for header, v := range interaction.Response.Headers {
+		resp.Header.Set(header, v)
+	}
+	return resp, nil
+}
+
+// save writes the recorded interactions into the cassette, unless the test failed.
+func (r *Recorder) save() {
+	if r.t.Failed() {
+		r.t.Logf("not writing cassette %s of failed test", r.path)
+		return
+	}
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		r.t.Fatalf("failed to marshal cassette %s: %v", r.path, err)
+	}
+	data = append(data, '\n')
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		r.t.Fatalf("failed to create directory of cassette %s: %v", r.path, err)
+	}
+	if err := os.WriteFile(r.path, data, 0o600); err != nil {
+		r.t.Fatalf("failed to write cassette %s: %v", r.path, err)
+	}
+}
+
+// verify checks that all the interactions of the cassette were replayed.
+func (r *Recorder) verify() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next < len(r.cassette.Interactions) {
+		next := r.cassette.Interactions[r.next].Request
+		r.t.Errorf("%d interactions of cassette %s were not replayed, starting with %s %s", len(r.cassette.Interactions)-r.next, r.path, next.Method, next.URL)
+	}
+}
+
+// scrub replaces the subscription ID and the resource manager endpoint of the recording environment with the ones
+// used for replaying.
+func (r *Recorder) scrub(s string) string {
+	s = strings.ReplaceAll(s, r.settings.Environment.ResourceManagerEndpoint, azure.PublicCloud.ResourceManagerEndpoint)
+	return strings.ReplaceAll(s, r.settings.GetSubscriptionID(), ReplaySubscriptionID)
+}
+
+func (r *Recorder) scrubBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if !json.Valid(body) {
+		r.t.Errorf("body %q is not JSON and can't be recorded into cassette %s", body, r.path)
+		return nil
+	}
+	return json.RawMessage(r.scrub(string(body)))
+}
+
+// readBody reads the body and replaces it with a reader of the same content.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, err
+	}
+	if err := (*body).Close(); err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// compact removes the insignificant space of a JSON body, so that bodies compare regardless of their indentation.
+func compact(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, body); err != nil {
+		return body
+	}
+	return buf.Bytes()
+}
+
+// SubscriptionID returns the subscription ID of the environment when recording, or ReplaySubscriptionID.
+func (r *Recorder) SubscriptionID() string {
+	return r.settings.GetSubscriptionID()
+}
+
+// ClientID returns the client ID of the environment when recording.
+func (r *Recorder) ClientID() string {
+	return r.settings.Values[auth.ClientID]
+}
+
+// ClientSecret returns the client secret of the environment when recording.
+func (r *Recorder) ClientSecret() string {
+	return r.settings.Values[auth.ClientSecret]
+}
+
+// CloudEnvironment returns the name of the Azure environment.
+func (r *Recorder) CloudEnvironment() string {
+	return r.settings.Environment.Name
+}
+
+// TenantID returns the tenant ID of the environment when recording.
+func (r *Recorder) TenantID() string {
+	return r.settings.Values[auth.TenantID]
+}
+
+// BaseURI returns the resource manager endpoint of the Azure environment.
+func (r *Recorder) BaseURI() string {
+	return r.settings.Environment.ResourceManagerEndpoint
+}
+
+// Authorizer returns the authorizer of the environment when recording, which Apply removes from the clients when
+// replaying.
+func (r *Recorder) Authorizer() autorest.Authorizer {
+	if !*record {
+		return autorest.NullAuthorizer{}
+	}
+	authorizer, err := r.settings.GetAuthorizer()
+	if err != nil {
+		r.t.Fatalf("failed to create the authorizer of the environment: %v", err)
+	}
+	return authorizer
+}
+
+// HashKey returns a base64 url encoded sha256 hash of the identity of the Recorder.
+func (r *Recorder) HashKey() string {
+	hasher := sha256.New()
+	_, _ = hasher.Write([]byte(r.TenantID() + r.CloudEnvironment() + r.SubscriptionID() + r.ClientID()))
+	return base64.URLEncoding.EncodeToString(hasher.Sum(nil))
+}
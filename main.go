@@ -50,6 +50,10 @@ import (
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	infrav1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1alpha3exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
 	infrav1alpha4exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha4"
@@ -57,7 +61,12 @@ import (
 	infrav1controllersexp "sigs.k8s.io/cluster-api-provider-azure/exp/controllers"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/encryptionathost"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/imagepolicy"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/ot"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/runtimehooks"
+	"sigs.k8s.io/cluster-api-provider-azure/util/generators"
+	"sigs.k8s.io/cluster-api-provider-azure/util/payloadlog"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	"sigs.k8s.io/cluster-api-provider-azure/version"
@@ -113,10 +122,25 @@ var (
 	azureMachinePoolMachineConcurrency int
 	debouncingTimer                    time.Duration
 	syncPeriod                         time.Duration
+	resyncPeriods                      reconciler.ResyncPeriods
 	healthAddr                         string
 	webhookPort                        int
 	reconcileTimeout                   time.Duration
 	enableTracing                      bool
+	clusterProvisioningSLO             time.Duration
+	resourceSKUsFile                   string
+	imageMaxAge                        time.Duration
+	imageRequiredTags                  string
+	imageReplication                   string
+	deterministicNames                 bool
+	runtimeExtensionURL                string
+	runtimeExtensionTimeout            time.Duration
+	acceptMarketplaceTerms             bool
+	defaultImageSource                 string
+	retryableAzureErrors               []string
+	terminalAzureErrors                []string
+	cordonImpactedZones                bool
+	azurePayloadLogLevels              []string
 )
 
 // InitFlags initializes all command-line flags.
@@ -181,7 +205,7 @@ func InitFlags(fs *pflag.FlagSet) {
 		&profilerAddress,
 		"profiler-address",
 		"",
-		"Bind address to expose the pprof profiler (e.g. localhost:6060)",
+		"Bind address to expose the pprof profiler and the /debug/azure-payload-log endpoint (e.g. localhost:6060)",
 	)
 
 	fs.IntVar(&azureClusterConcurrency,
@@ -218,6 +242,24 @@ func InitFlags(fs *pflag.FlagSet) {
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)",
 	)
 
+	fs.DurationVar(&resyncPeriods.Provisioning,
+		"resync-period-provisioning",
+		0,
+		"The interval at which AzureClusters, AzureMachines and AzureMachinePools are reconciled again while their cluster or themselves are being provisioned (e.g. 1m). Disabled if zero, in which case the sync period applies.",
+	)
+
+	fs.DurationVar(&resyncPeriods.Degraded,
+		"resync-period-degraded",
+		0,
+		"The interval at which AzureClusters, AzureMachines and AzureMachinePools with a false condition of warning or error severity are reconciled again (e.g. 2m). Disabled if zero, in which case the sync period applies.",
+	)
+
+	fs.DurationVar(&resyncPeriods.Steady,
+		"resync-period-steady",
+		0,
+		"The interval at which provisioned, healthy AzureClusters, AzureMachines and AzureMachinePools are reconciled again (e.g. 30m). Disabled if zero, in which case the sync period applies. Resources are reconciled at least every sync period, so raise the sync period above this interval to reduce the Azure API calls.",
+	)
+
 	fs.StringVar(&healthAddr,
 		"health-addr",
 		":9440",
@@ -236,6 +278,90 @@ func InitFlags(fs *pflag.FlagSet) {
 		"The maximum duration a reconcile loop can run (e.g. 90m)",
 	)
 
+	fs.DurationVar(&clusterProvisioningSLO,
+		"cluster-provisioning-slo",
+		0,
+		"The maximum duration the first node of a cluster should take to become ready, reported by the ProvisioningSLOMet condition of the AzureClusters (e.g. 20m). Disabled if zero.",
+	)
+
+	fs.StringVar(&resourceSKUsFile,
+		"resource-skus-file",
+		"",
+		"Path to a JSON file of the resource SKUs of the locations of the clusters, e.g. the output of \"az vm list-skus --output json\", used instead of listing them from the subscription. Required by the ResourceGroupScoped feature gate.",
+	)
+
+	fs.DurationVar(&imageMaxAge,
+		"image-max-age",
+		0,
+		"The maximum age of the shared image gallery image versions of new AzureMachines since they were published (e.g. 720h). Disabled if zero.",
+	)
+
+	fs.StringVar(&imageRequiredTags,
+		"image-required-tags",
+		"",
+		"Comma separated list of key=value tags which the shared image gallery image versions of new AzureMachines must have (e.g. scanned=true).",
+	)
+
+	fs.StringVar(&imageReplication,
+		"gallery-image-replication",
+		"",
+		"How the replication of the shared image gallery image versions of new AzureMachines into their location is handled before their VM is created: \"Verify\" waits for the image version to be replicated, \"Replicate\" also adds the location to its target regions. Disabled if empty.",
+	)
+
+	fs.BoolVar(&acceptMarketplaceTerms,
+		"accept-marketplace-terms",
+		false,
+		"Accept the marketplace terms of the purchase plan of the images of new AzureMachines and of AzureMachinePools in the subscription of their cluster, as `az vm image terms accept` does.",
+	)
+
+	fs.StringVar(&defaultImageSource,
+		"default-image-source",
+		string(azure.DefaultImageSourceMarketplace),
+		"Where the images of AzureMachines which don't specify one are resolved from for their Kubernetes version: \"Marketplace\" or \"CommunityGallery\".",
+	)
+
+	fs.StringSliceVar(&retryableAzureErrors,
+		"retryable-azure-errors",
+		nil,
+		"Comma separated list of Azure error codes or HTTP status codes of errors which are retried after the default requeue period, e.g. non-standard transient errors of sovereign clouds or Azure Stack Hub (e.g. SubscriptionNotReady,409).",
+	)
+
+	fs.StringSliceVar(&terminalAzureErrors,
+		"terminal-azure-errors",
+		nil,
+		"Comma separated list of Azure error codes or HTTP status codes of errors which cannot succeed when retried, e.g. non-standard errors of sovereign clouds or Azure Stack Hub. They fail AzureMachines and AzureMachinePools instead of being retried (e.g. QuotaExceeded).",
+	)
+
+	fs.BoolVar(&cordonImpactedZones,
+		"cordon-impacted-zones",
+		false,
+		"Cordon the availability zones of clusters whose VMs are unavailable because of an Azure service health event, so that new control plane machines are not placed in them until the event is resolved. The zones of worker machines are not affected.",
+	)
+
+	fs.StringVar(&runtimeExtensionURL,
+		"runtime-extension-url",
+		"",
+		"Base URL of a runtime extension called at the provisioning milestones of the clusters and machines (e.g. https://capz-extension.default.svc/hooks). Disabled if empty.",
+	)
+
+	fs.DurationVar(&runtimeExtensionTimeout,
+		"runtime-extension-timeout",
+		10*time.Second,
+		"The maximum duration of a call to the runtime extension (e.g. 10s).",
+	)
+
+	fs.StringSliceVar(&azurePayloadLogLevels,
+		"azure-payload-log-levels",
+		nil,
+		"Comma separated list of the payload logging verbosity of Azure services, as service=level (e.g. natgateways=2). Level 1 logs the requests sent to Azure by the service and level 2 also logs their redacted payloads. It can be changed at runtime at the /debug/azure-payload-log endpoint of the profiler server, if --profiler-address is set.",
+	)
+
+	fs.BoolVar(&deterministicNames,
+		"deterministic-names",
+		false,
+		"Generate reproducible resource names and payloads instead of random suffixes and passwords, for record/replay and golden file testing. Must not be used for production clusters.",
+	)
+
 	fs.BoolVar(
 		&enableTracing,
 		"enable-tracing",
@@ -253,12 +379,58 @@ func main() {
 
 	ctrl.SetLogger(klogr.New())
 
+	if resourceSKUsFile != "" {
+		if err := resourceskus.LoadStaticData(resourceSKUsFile); err != nil {
+			setupLog.Error(err, "unable to load resource SKUs")
+			os.Exit(1)
+		}
+	} else if feature.Gates.Enabled(feature.ResourceGroupScoped) {
+		setupLog.Error(nil, "--resource-skus-file is required by the ResourceGroupScoped feature gate")
+		os.Exit(1)
+	}
+
+	switch azure.ImageReplicationMode(imageReplication) {
+	case azure.ImageReplicationDisabled, azure.ImageReplicationVerify, azure.ImageReplicationReplicate:
+	default:
+		setupLog.Error(nil, "--gallery-image-replication must be empty, \"Verify\" or \"Replicate\"")
+		os.Exit(1)
+	}
+
+	switch azure.DefaultImageSource(defaultImageSource) {
+	case azure.DefaultImageSourceMarketplace, azure.DefaultImageSourceCommunityGallery:
+	default:
+		setupLog.Error(nil, "--default-image-source must be \"Marketplace\" or \"CommunityGallery\"")
+		os.Exit(1)
+	}
+
+	azureerrors.SetClassification(azureerrors.Classification{
+		Retryable: retryableAzureErrors,
+		Terminal:  terminalAzureErrors,
+	})
+
+	payloadLogLevels, err := payloadlog.ParseLevels(azurePayloadLogLevels)
+	if err != nil {
+		setupLog.Error(err, "invalid --azure-payload-log-levels")
+		os.Exit(1)
+	}
+	for service, level := range payloadLogLevels {
+		payloadlog.SetLevel(service, level)
+	}
+
+	if deterministicNames {
+		setupLog.Info("Generating deterministic resource names and payloads, this mode must not be used for production clusters")
+		generators.SetDeterministic(true)
+	}
+
 	if watchNamespace != "" {
 		setupLog.Info("Watching cluster-api objects only in namespace for reconciliation", "namespace", watchNamespace)
 	}
 
 	if profilerAddress != "" {
 		setupLog.Info("Profiler listening for requests", "profiler-address", profilerAddress)
+		// The payload logging verbosity can only be changed at runtime on the opt-in profiler server, since the metrics
+		// server is not authenticated.
+		http.Handle("/debug/azure-payload-log", payloadlog.Handler())
 		go func() {
 			setupLog.Error(http.ListenAndServe(profilerAddress, nil), "listen and serve error")
 		}()
@@ -311,6 +483,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := mgr.Add(manager.RunnableFunc(scope.RefreshIdentityTokens)); err != nil {
+		setupLog.Error(err, "unable to start identity token refresh")
+		os.Exit(1)
+	}
+
 	registerControllers(ctx, mgr)
 
 	registerWebhooks(ctx, mgr)
@@ -324,6 +501,13 @@ func main() {
 }
 
 func registerControllers(ctx context.Context, mgr manager.Manager) {
+	runtimeHooks := runtimehooks.NewClient(runtimeExtensionURL, runtimeExtensionTimeout)
+	imageResolver, err := azure.NewImageResolver(azure.DefaultImageSource(defaultImageSource))
+	if err != nil {
+		setupLog.Error(err, "unable to create image resolver")
+		os.Exit(1)
+	}
+
 	machineCache, err := coalescing.NewRequestCache(debouncingTimer)
 	if err != nil {
 		setupLog.Error(err, "failed to build machineCache ReconcileCache")
@@ -332,7 +516,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		mgr.GetEventRecorderFor("azuremachine-reconciler"),
 		reconcileTimeout,
 		watchFilterValue,
-	).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}, Cache: machineCache}); err != nil {
+	).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}, Cache: machineCache, ResyncPeriods: resyncPeriods, ImageReplication: azure.ImageReplicationMode(imageReplication), AcceptMarketplaceTerms: acceptMarketplaceTerms, ImageResolver: imageResolver, RuntimeHooks: runtimeHooks}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureMachine")
 		os.Exit(1)
 	}
@@ -346,7 +530,8 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		mgr.GetEventRecorderFor("azurecluster-reconciler"),
 		reconcileTimeout,
 		watchFilterValue,
-	).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}, Cache: clusterCache}); err != nil {
+		clusterProvisioningSLO,
+	).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}, Cache: clusterCache, ResyncPeriods: resyncPeriods, RuntimeHooks: runtimeHooks, CordonImpactedZones: cordonImpactedZones}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureCluster")
 		os.Exit(1)
 	}
@@ -394,7 +579,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			mgr.GetEventRecorderFor("azuremachinepool-reconciler"),
 			reconcileTimeout,
 			watchFilterValue,
-		).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachinePoolConcurrency}, Cache: mpCache}); err != nil {
+		).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachinePoolConcurrency}, Cache: mpCache, ResyncPeriods: resyncPeriods, AcceptMarketplaceTerms: acceptMarketplaceTerms}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AzureMachinePool")
 			os.Exit(1)
 		}
@@ -471,6 +656,18 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			}
 		}
 	}
+
+	if feature.Gates.Enabled(feature.NodeImages) {
+		if err := (&infrav1controllersexp.AzureNodeImageReconciler{
+			Client:           mgr.GetClient(),
+			Recorder:         mgr.GetEventRecorderFor("azurenodeimage-reconciler"),
+			ReconcileTimeout: reconcileTimeout,
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AzureNodeImage")
+			os.Exit(1)
+		}
+	}
 }
 
 func registerWebhooks(ctx context.Context, mgr manager.Manager) {
@@ -484,6 +681,17 @@ func registerWebhooks(ctx context.Context, mgr manager.Manager) {
 		os.Exit(1)
 	}
 
+	requiredTags, err := imagepolicy.ParseRequiredTags(imageRequiredTags)
+	if err != nil {
+		setupLog.Error(err, "invalid --image-required-tags")
+		os.Exit(1)
+	}
+	mgr.GetWebhookServer().Register(imagepolicy.WebhookPath, imagepolicy.NewWebhook(mgr.GetClient(), imagepolicy.Policy{
+		MaxAge:       imageMaxAge,
+		RequiredTags: requiredTags,
+	}))
+	mgr.GetWebhookServer().Register(encryptionathost.MachineWebhookPath, encryptionathost.NewWebhook(mgr.GetClient()))
+
 	if err := (&infrav1beta1.AzureMachineTemplate{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachineTemplate")
 		os.Exit(1)
@@ -499,6 +707,7 @@ func registerWebhooks(ctx context.Context, mgr manager.Manager) {
 			setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachinePool")
 			os.Exit(1)
 		}
+		mgr.GetWebhookServer().Register(encryptionathost.MachinePoolWebhookPath, encryptionathost.NewWebhook(mgr.GetClient()))
 
 		if err := (&infrav1beta1exp.AzureMachinePoolMachine{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachinePoolMachine")
@@ -523,6 +732,13 @@ func registerWebhooks(ctx context.Context, mgr manager.Manager) {
 		))
 	}
 
+	if feature.Gates.Enabled(feature.NodeImages) {
+		if err := (&infrav1beta1exp.AzureNodeImage{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AzureNodeImage")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
 		setupLog.Error(err, "unable to create ready check")
 		os.Exit(1)
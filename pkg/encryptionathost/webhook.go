@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encryptionathost implements an admission webhook rejecting AzureMachines and AzureMachinePools which
+// enable encryption at host on a VM size that doesn't support it.
+package encryptionathost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// MachineWebhookPath is the path of the webhook declared by the AzureMachine type.
+	MachineWebhookPath = "/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachine-encryptionathost"
+	// MachinePoolWebhookPath is the path of the webhook declared by the AzureMachinePool type.
+	MachinePoolWebhookPath = "/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachinepool-encryptionathost"
+)
+
+// skuGetter gets the resource SKUs of a location.
+type skuGetter interface {
+	Get(ctx context.Context, name string, kind resourceskus.ResourceType) (resourceskus.SKU, error)
+}
+
+// Validator rejects AzureMachines and AzureMachinePools enabling encryption at host on a VM size which doesn't
+// support it. As the VM size capabilities are only known to Azure, it relies on the resource SKUs of the location of
+// the cluster.
+type Validator struct {
+	Client  ctrlclient.Client
+	decoder *admission.Decoder
+	// newSKUGetter gets the resource SKUs of a location, or of the location of the cluster if empty, with the
+	// credentials of the cluster of the object.
+	newSKUGetter func(ctx context.Context, obj metav1.ObjectMeta, location string) (skuGetter, error)
+}
+
+var _ admission.DecoderInjector = &Validator{}
+
+// NewWebhook creates a webhook validating the encryption at host settings of AzureMachines and AzureMachinePools.
+func NewWebhook(c ctrlclient.Client) *admission.Webhook {
+	v := &Validator{
+		Client: c,
+	}
+	v.newSKUGetter = v.clusterSKUs
+	return &admission.Webhook{Handler: v}
+}
+
+// InjectDecoder injects the decoder into the Validator.
+func (v *Validator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle handles admission requests.
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "encryptionathost.Validator.Handle")
+	defer done()
+
+	var (
+		obj             metav1.ObjectMeta
+		vmSize          string
+		location        string
+		securityProfile *infrav1.SecurityProfile
+	)
+	switch req.Kind.Kind {
+	case "AzureMachinePool":
+		amp := &infrav1exp.AzureMachinePool{}
+		if err := v.decoder.Decode(req, amp); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		obj, vmSize, location, securityProfile = amp.ObjectMeta, amp.Spec.Template.VMSize, amp.Spec.Location, amp.Spec.Template.SecurityProfile
+	default:
+		machine := &infrav1.AzureMachine{}
+		if err := v.decoder.Decode(req, machine); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		obj, vmSize, securityProfile = machine.ObjectMeta, machine.Spec.VMSize, machine.Spec.SecurityProfile
+	}
+
+	if securityProfile == nil || !to.Bool(securityProfile.EncryptionAtHost) {
+		return admission.Allowed("")
+	}
+
+	// The VM size is verified again when creating the VMs, so failing to get its capabilities here must not prevent
+	// creating objects e.g. before the credentials of their cluster are available.
+	skus, err := v.newSKUGetter(ctx, obj, location)
+	if err != nil {
+		log.Error(err, "failed to get resource SKUs, skipping encryption at host validation", "name", obj.Name)
+		return admission.Allowed("")
+	}
+	sku, err := skus.Get(ctx, vmSize, resourceskus.VirtualMachines)
+	if err != nil {
+		log.Error(err, "failed to get VM size, skipping encryption at host validation", "name", obj.Name, "vmSize", vmSize)
+		return admission.Allowed("")
+	}
+	if !sku.HasCapability(resourceskus.EncryptionAtHost) {
+		return admission.Denied(fmt.Sprintf("encryption at host is not supported for VM size %s. Select a different VM size or disable spec.securityProfile.encryptionAtHost", vmSize))
+	}
+	return admission.Allowed("")
+}
+
+// clusterSKUs gets the resource SKUs of a location with the credentials of the AzureCluster of an object.
+func (v *Validator) clusterSKUs(ctx context.Context, obj metav1.ObjectMeta, location string) (skuGetter, error) {
+	cluster, err := util.GetClusterFromMetadata(ctx, v.Client, obj)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Spec.InfrastructureRef == nil {
+		return nil, errors.Errorf("cluster %s has no infrastructure reference", cluster.Name)
+	}
+
+	azureCluster := &infrav1.AzureCluster{}
+	key := ctrlclient.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := v.Client.Get(ctx, key, azureCluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to get AzureCluster %s", key.Name)
+	}
+
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:       v.Client,
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cluster scope")
+	}
+	if location == "" {
+		location = clusterScope.Location()
+	}
+	return resourceskus.GetCache(clusterScope, location)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionathost
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+)
+
+func TestHandle(t *testing.T) {
+	skus := resourceskus.NewStaticCache([]compute.ResourceSku{
+		{
+			Name:         to.StringPtr("Standard_D2s_v3"),
+			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(resourceskus.EncryptionAtHost), Value: to.StringPtr(string(resourceskus.CapabilitySupported))},
+			},
+		},
+		{
+			Name:         to.StringPtr("Standard_A2_v2"),
+			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
+			Capabilities: &[]compute.ResourceSkuCapabilities{},
+		},
+	}, "eastus")
+
+	tests := []struct {
+		name             string
+		obj              runtime.Object
+		skuErr           error
+		wantLocation     string
+		wantAllowed      bool
+		wantMessage      string
+		wantSKUsRequired bool
+	}{
+		{
+			name:        "encryption at host not set",
+			obj:         azureMachine("Standard_A2_v2", nil),
+			wantAllowed: true,
+		},
+		{
+			name:        "encryption at host disabled",
+			obj:         azureMachine("Standard_A2_v2", &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(false)}),
+			wantAllowed: true,
+		},
+		{
+			name:             "machine with a VM size supporting encryption at host",
+			obj:              azureMachine("Standard_D2s_v3", &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(true)}),
+			wantSKUsRequired: true,
+			wantAllowed:      true,
+		},
+		{
+			name:             "machine with a VM size not supporting encryption at host",
+			obj:              azureMachine("Standard_A2_v2", &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(true)}),
+			wantSKUsRequired: true,
+			wantAllowed:      false,
+			wantMessage:      "encryption at host is not supported for VM size Standard_A2_v2. Select a different VM size or disable spec.securityProfile.encryptionAtHost",
+		},
+		{
+			name:             "machine with an unknown VM size",
+			obj:              azureMachine("Standard_Unknown", &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(true)}),
+			wantSKUsRequired: true,
+			wantAllowed:      true,
+		},
+		{
+			name:             "resource SKUs can't be listed",
+			obj:              azureMachine("Standard_A2_v2", &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(true)}),
+			skuErr:           errors.New("cluster not found"),
+			wantSKUsRequired: true,
+			wantAllowed:      true,
+		},
+		{
+			name:             "machine pool with a VM size not supporting encryption at host",
+			obj:              azureMachinePool("Standard_A2_v2", "eastus", &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(true)}),
+			wantSKUsRequired: true,
+			wantLocation:     "eastus",
+			wantAllowed:      false,
+			wantMessage:      "encryption at host is not supported for VM size Standard_A2_v2. Select a different VM size or disable spec.securityProfile.encryptionAtHost",
+		},
+		{
+			name:             "machine pool with a VM size supporting encryption at host",
+			obj:              azureMachinePool("Standard_D2s_v3", "eastus", &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(true)}),
+			wantSKUsRequired: true,
+			wantLocation:     "eastus",
+			wantAllowed:      true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(infrav1exp.AddToScheme(scheme)).To(Succeed())
+			decoder, err := admission.NewDecoder(scheme)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			skusRequired := false
+			v := &Validator{
+				decoder: decoder,
+				newSKUGetter: func(_ context.Context, obj metav1.ObjectMeta, location string) (skuGetter, error) {
+					skusRequired = true
+					g.Expect(obj.Name).To(Equal("my-machine"))
+					g.Expect(location).To(Equal(tc.wantLocation))
+					if tc.skuErr != nil {
+						return nil, tc.skuErr
+					}
+					return skus, nil
+				},
+			}
+
+			raw, err := json.Marshal(tc.obj)
+			g.Expect(err).NotTo(HaveOccurred())
+			resp := v.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      metav1.GroupVersionKind{Kind: tc.obj.GetObjectKind().GroupVersionKind().Kind},
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			g.Expect(resp.Allowed).To(Equal(tc.wantAllowed))
+			g.Expect(skusRequired).To(Equal(tc.wantSKUsRequired))
+			if tc.wantMessage != "" {
+				g.Expect(string(resp.Result.Reason)).To(Equal(tc.wantMessage))
+			}
+		})
+	}
+}
+
+func azureMachine(vmSize string, securityProfile *infrav1.SecurityProfile) *infrav1.AzureMachine {
+	return &infrav1.AzureMachine{
+		TypeMeta:   metav1.TypeMeta{APIVersion: infrav1.GroupVersion.String(), Kind: "AzureMachine"},
+		ObjectMeta: metav1.ObjectMeta{Name: "my-machine", Namespace: "default"},
+		Spec: infrav1.AzureMachineSpec{
+			VMSize:          vmSize,
+			SecurityProfile: securityProfile,
+		},
+	}
+}
+
+func azureMachinePool(vmSize, location string, securityProfile *infrav1.SecurityProfile) *infrav1exp.AzureMachinePool {
+	return &infrav1exp.AzureMachinePool{
+		TypeMeta:   metav1.TypeMeta{APIVersion: infrav1exp.GroupVersion.String(), Kind: "AzureMachinePool"},
+		ObjectMeta: metav1.ObjectMeta{Name: "my-machine", Namespace: "default"},
+		Spec: infrav1exp.AzureMachinePoolSpec{
+			Location: location,
+			Template: infrav1exp.AzureMachinePoolMachineTemplate{
+				VMSize:          vmSize,
+				SecurityProfile: securityProfile,
+			},
+		},
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepolicy
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	azureerrors "sigs.k8s.io/cluster-api-provider-azure/azure/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	GetImageVersion(ctx context.Context, resourceGroupName, galleryName, imageName, versionName string) (compute.GalleryImageVersion, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	versions compute.GalleryImageVersionsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new gallery image versions client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newGalleryImageVersionsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{c}
+}
+
+// newGalleryImageVersionsClient creates a new gallery image versions client from subscription ID.
+func newGalleryImageVersionsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.GalleryImageVersionsClient {
+	versionsClient := compute.NewGalleryImageVersionsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&versionsClient.Client, authorizer, "imagepolicy")
+	return versionsClient
+}
+
+// GetImageVersion gets a version of an image of a shared image gallery.
+func (ac *azureClient) GetImageVersion(ctx context.Context, resourceGroupName, galleryName, imageName, versionName string) (_ compute.GalleryImageVersion, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagepolicy.AzureClient.GetImageVersion")
+	defer done()
+	defer azureerrors.Classify(&err)
+
+	return ac.versions.Get(ctx, resourceGroupName, galleryName, imageName, versionName, "")
+}
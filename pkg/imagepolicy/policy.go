@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package imagepolicy rejects the creation of machines from gallery image versions which don't satisfy a policy.
+package imagepolicy
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+)
+
+// Policy defines the requirements of the gallery image versions of new machines.
+type Policy struct {
+	// MaxAge is the maximum age of an image version since it was published. Disabled if zero.
+	MaxAge time.Duration
+	// RequiredTags are the tags an image version must have, with these values.
+	RequiredTags map[string]string
+}
+
+// Enabled returns true if the policy has any requirement.
+func (p Policy) Enabled() bool {
+	return p.MaxAge > 0 || len(p.RequiredTags) > 0
+}
+
+// ParseRequiredTags parses a comma separated list of key=value tags, e.g. "scanned=true,team=platform".
+func ParseRequiredTags(s string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("invalid tag %q, must be formatted as key=value", tag)
+		}
+		tags[kv[0]] = kv[1]
+	}
+	return tags, nil
+}
+
+// Check returns an error describing why the image version doesn't satisfy the policy, if it doesn't.
+func (p Policy) Check(version compute.GalleryImageVersion, now time.Time) error {
+	var violations []string
+
+	if p.MaxAge > 0 {
+		var published *time.Time
+		if props := version.GalleryImageVersionProperties; props != nil && props.PublishingProfile != nil && props.PublishingProfile.PublishedDate != nil {
+			published = &props.PublishingProfile.PublishedDate.Time
+		}
+		switch {
+		case published == nil:
+			violations = append(violations, "it has no published date")
+		case now.Sub(*published) > p.MaxAge:
+			violations = append(violations, "it was published on "+published.UTC().Format(time.RFC3339)+", more than "+p.MaxAge.String()+" ago")
+		}
+	}
+
+	keys := make([]string, 0, len(p.RequiredTags))
+	for key := range p.RequiredTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := version.Tags[key]
+		if !ok || to.String(value) != p.RequiredTags[key] {
+			violations = append(violations, "it is not tagged "+key+"="+p.RequiredTags[key])
+		}
+	}
+
+	if len(violations) > 0 {
+		return errors.New(strings.Join(violations, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+)
+
+var now = time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+// imageVersion returns a gallery image version published at the given date, with the given tags.
+func imageVersion(published *time.Time, tags map[string]*string) compute.GalleryImageVersion {
+	version := compute.GalleryImageVersion{
+		Tags:                          tags,
+		GalleryImageVersionProperties: &compute.GalleryImageVersionProperties{},
+	}
+	if published != nil {
+		version.PublishingProfile = &compute.GalleryImageVersionPublishingProfile{PublishedDate: &date.Time{Time: *published}}
+	}
+	return version
+}
+
+func TestCheck(t *testing.T) {
+	recent := now.Add(-24 * time.Hour)
+	old := now.Add(-60 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		policy  Policy
+		version compute.GalleryImageVersion
+		wantErr string
+	}{
+		{
+			name:    "empty policy",
+			policy:  Policy{},
+			version: imageVersion(nil, nil),
+			wantErr: "",
+		},
+		{
+			name:    "recent image version",
+			policy:  Policy{MaxAge: 30 * 24 * time.Hour},
+			version: imageVersion(&recent, nil),
+			wantErr: "",
+		},
+		{
+			name:    "old image version",
+			policy:  Policy{MaxAge: 30 * 24 * time.Hour},
+			version: imageVersion(&old, nil),
+			wantErr: "it was published on 2021-12-31T00:00:00Z, more than 720h0m0s ago",
+		},
+		{
+			name:    "image version without published date",
+			policy:  Policy{MaxAge: 30 * 24 * time.Hour},
+			version: imageVersion(nil, nil),
+			wantErr: "it has no published date",
+		},
+		{
+			name:    "required tags",
+			policy:  Policy{RequiredTags: map[string]string{"scanned": "true"}},
+			version: imageVersion(nil, map[string]*string{"scanned": to.StringPtr("true"), "team": to.StringPtr("platform")}),
+			wantErr: "",
+		},
+		{
+			name:    "missing and mismatching tags",
+			policy:  Policy{MaxAge: 30 * 24 * time.Hour, RequiredTags: map[string]string{"scanned": "true", "approved": "yes"}},
+			version: imageVersion(&old, map[string]*string{"scanned": to.StringPtr("false")}),
+			wantErr: "it was published on 2021-12-31T00:00:00Z, more than 720h0m0s ago, it is not tagged approved=yes, it is not tagged scanned=true",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := tc.policy.Check(tc.version, now)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestParseRequiredTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "no tags",
+			tags: "",
+			want: map[string]string{},
+		},
+		{
+			name: "several tags",
+			tags: "scanned=true, team=platform,empty=",
+			want: map[string]string{"scanned": "true", "team": "platform", "empty": ""},
+		},
+		{
+			name:    "tag without value",
+			tags:    "scanned",
+			wantErr: true,
+		},
+		{
+			name:    "tag without key",
+			tags:    "=true",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			tags, err := ParseRequiredTags(tc.tags)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(tags).To(Equal(tc.want))
+			}
+		})
+	}
+}
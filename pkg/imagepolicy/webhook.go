@@ -0,0 +1,197 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepolicy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api/util"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// WebhookPath is the path of the webhook declared by the AzureMachine type.
+const WebhookPath = "/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachine-image"
+
+var galleryImageVersionIDRegex = regexp.MustCompile(`(?i)^/subscriptions/([^/]+)/resourceGroups/([^/]+)/providers/Microsoft\.Compute/galleries/([^/]+)/images/([^/]+)/versions/([^/]+)$`)
+
+// imageVersionRef references a version of an image of a shared image gallery.
+type imageVersionRef struct {
+	SubscriptionID string
+	ResourceGroup  string
+	Gallery        string
+	Name           string
+	Version        string
+}
+
+func (r imageVersionRef) String() string {
+	return fmt.Sprintf("%s/%s/%s", r.Gallery, r.Name, r.Version)
+}
+
+// Validator rejects the creation of AzureMachines whose gallery image version doesn't satisfy the policy.
+type Validator struct {
+	Client  ctrlclient.Client
+	Policy  Policy
+	decoder *admission.Decoder
+	// newClient creates a client for the gallery image versions of a subscription, with the credentials of the cluster
+	// of the machine.
+	newClient func(ctx context.Context, machine *infrav1.AzureMachine, subscriptionID string) (client, error)
+	now       func() time.Time
+}
+
+var _ admission.DecoderInjector = &Validator{}
+
+// NewWebhook creates a webhook enforcing the policy on new AzureMachines.
+func NewWebhook(c ctrlclient.Client, policy Policy) *admission.Webhook {
+	v := &Validator{
+		Client: c,
+		Policy: policy,
+		now:    time.Now,
+	}
+	v.newClient = v.clusterClient
+	return &admission.Webhook{Handler: v}
+}
+
+// InjectDecoder injects the decoder into the Validator.
+func (v *Validator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle handles admission requests.
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "imagepolicy.Validator.Handle")
+	defer done()
+
+	if !v.Policy.Enabled() {
+		return admission.Allowed("")
+	}
+
+	machine := &infrav1.AzureMachine{}
+	if err := v.decoder.Decode(req, machine); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if machine.Annotations[infrav1.SkipImagePolicyAnnotation] == "true" {
+		log.Info("skipping image policy", "azureMachine", machine.Name)
+		return admission.Allowed("image policy skipped")
+	}
+	ref := galleryImageVersion(machine.Spec.Image)
+	if ref == nil {
+		// The policy only applies to the images of shared image galleries, which carry the metadata it relies on.
+		return admission.Allowed("")
+	}
+
+	imageVersion, err := v.getImageVersion(ctx, machine, ref)
+	if err != nil {
+		return admission.Denied(fmt.Sprintf("failed to verify image version %s against the image policy: %v. Set the annotation %s=true to skip the image policy",
+			ref, err, infrav1.SkipImagePolicyAnnotation))
+	}
+	if err := v.Policy.Check(imageVersion, v.now()); err != nil {
+		return admission.Denied(fmt.Sprintf("image version %s doesn't satisfy the image policy: %v. Set the annotation %s=true to skip the image policy",
+			ref, err, infrav1.SkipImagePolicyAnnotation))
+	}
+	return admission.Allowed("")
+}
+
+// getImageVersion gets the gallery image version of the machine.
+func (v *Validator) getImageVersion(ctx context.Context, machine *infrav1.AzureMachine, ref *imageVersionRef) (compute.GalleryImageVersion, error) {
+	c, err := v.newClient(ctx, machine, ref.SubscriptionID)
+	if err != nil {
+		return compute.GalleryImageVersion{}, err
+	}
+	return c.GetImageVersion(ctx, ref.ResourceGroup, ref.Gallery, ref.Name, ref.Version)
+}
+
+// clusterClient creates a client with the credentials of the AzureCluster of the machine.
+func (v *Validator) clusterClient(ctx context.Context, machine *infrav1.AzureMachine, subscriptionID string) (client, error) {
+	cluster, err := util.GetClusterFromMetadata(ctx, v.Client, machine.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Spec.InfrastructureRef == nil {
+		return nil, errors.Errorf("cluster %s has no infrastructure reference", cluster.Name)
+	}
+
+	azureCluster := &infrav1.AzureCluster{}
+	key := ctrlclient.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := v.Client.Get(ctx, key, azureCluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to get AzureCluster %s", key.Name)
+	}
+
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:       v.Client,
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cluster scope")
+	}
+	return newClient(subscriptionAuthorizer{ClusterScope: clusterScope, subscriptionID: subscriptionID}), nil
+}
+
+// subscriptionAuthorizer overrides the subscription of the cluster authorizer, as the gallery can be in another one.
+type subscriptionAuthorizer struct {
+	*scope.ClusterScope
+	subscriptionID string
+}
+
+// SubscriptionID returns the overridden subscription ID.
+func (a subscriptionAuthorizer) SubscriptionID() string {
+	if a.subscriptionID == "" {
+		return a.ClusterScope.SubscriptionID()
+	}
+	return a.subscriptionID
+}
+
+// galleryImageVersion returns the gallery image version referenced by an image, or nil if it's not one.
+func galleryImageVersion(image *infrav1.Image) *imageVersionRef {
+	switch {
+	case image == nil:
+		return nil
+	case image.SharedGallery != nil:
+		return &imageVersionRef{
+			SubscriptionID: image.SharedGallery.SubscriptionID,
+			ResourceGroup:  image.SharedGallery.ResourceGroup,
+			Gallery:        image.SharedGallery.Gallery,
+			Name:           image.SharedGallery.Name,
+			Version:        image.SharedGallery.Version,
+		}
+	case image.ID != nil:
+		match := galleryImageVersionIDRegex.FindStringSubmatch(*image.ID)
+		if match == nil {
+			return nil
+		}
+		return &imageVersionRef{
+			SubscriptionID: match[1],
+			ResourceGroup:  match[2],
+			Gallery:        match[3],
+			Name:           match[4],
+			Version:        match[5],
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/imagepolicy/mock_imagepolicy"
+)
+
+func TestHandle(t *testing.T) {
+	recent := now.Add(-24 * time.Hour)
+	policy := Policy{MaxAge: 30 * 24 * time.Hour, RequiredTags: map[string]string{"scanned": "true"}}
+	galleryImage := &infrav1.Image{
+		SharedGallery: &infrav1.AzureSharedGalleryImage{
+			SubscriptionID: "456",
+			ResourceGroup:  "images",
+			Gallery:        "gallery",
+			Name:           "ubuntu",
+			Version:        "1.0.0",
+		},
+	}
+	galleryImageID := &infrav1.Image{
+		ID: to.StringPtr("/subscriptions/456/resourceGroups/images/providers/Microsoft.Compute/galleries/gallery/images/ubuntu/versions/1.0.0"),
+	}
+
+	tests := []struct {
+		name        string
+		policy      Policy
+		image       *infrav1.Image
+		annotations map[string]string
+		expect      func(m *mock_imagepolicy.MockclientMockRecorder)
+		wantAllowed bool
+		wantMessage string
+	}{
+		{
+			name:        "policy disabled",
+			policy:      Policy{},
+			image:       galleryImage,
+			expect:      func(m *mock_imagepolicy.MockclientMockRecorder) {},
+			wantAllowed: true,
+		},
+		{
+			name:        "marketplace image",
+			policy:      policy,
+			image:       &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{Publisher: "cncf-upstream", Offer: "capi", SKU: "k8s", Version: "latest"}},
+			expect:      func(m *mock_imagepolicy.MockclientMockRecorder) {},
+			wantAllowed: true,
+		},
+		{
+			name:        "policy skipped by annotation",
+			policy:      policy,
+			image:       galleryImage,
+			annotations: map[string]string{infrav1.SkipImagePolicyAnnotation: "true"},
+			expect:      func(m *mock_imagepolicy.MockclientMockRecorder) {},
+			wantAllowed: true,
+		},
+		{
+			name:   "shared gallery image satisfying the policy",
+			policy: policy,
+			image:  galleryImage,
+			expect: func(m *mock_imagepolicy.MockclientMockRecorder) {
+				m.GetImageVersion(gomockinternal.AContext(), "images", "gallery", "ubuntu", "1.0.0").
+					Return(imageVersion(&recent, map[string]*string{"scanned": to.StringPtr("true")}), nil)
+			},
+			wantAllowed: true,
+		},
+		{
+			name:   "gallery image ID not satisfying the policy",
+			policy: policy,
+			image:  galleryImageID,
+			expect: func(m *mock_imagepolicy.MockclientMockRecorder) {
+				m.GetImageVersion(gomockinternal.AContext(), "images", "gallery", "ubuntu", "1.0.0").
+					Return(imageVersion(&recent, nil), nil)
+			},
+			wantAllowed: false,
+			wantMessage: "image version gallery/ubuntu/1.0.0 doesn't satisfy the image policy: it is not tagged scanned=true. " +
+				"Set the annotation azuremachine.infrastructure.cluster.x-k8s.io/skip-image-policy=true to skip the image policy",
+		},
+		{
+			name:   "image version can't be read",
+			policy: policy,
+			image:  galleryImage,
+			expect: func(m *mock_imagepolicy.MockclientMockRecorder) {
+				m.GetImageVersion(gomockinternal.AContext(), "images", "gallery", "ubuntu", "1.0.0").
+					Return(compute.GalleryImageVersion{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusForbidden}, "Forbidden"))
+			},
+			wantAllowed: false,
+			wantMessage: "failed to verify image version gallery/ubuntu/1.0.0 against the image policy: #: Forbidden: StatusCode=403. " +
+				"Set the annotation azuremachine.infrastructure.cluster.x-k8s.io/skip-image-policy=true to skip the image policy",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_imagepolicy.NewMockclient(mockCtrl)
+			tc.expect(clientMock.EXPECT())
+
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			decoder, err := admission.NewDecoder(scheme)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			v := &Validator{
+				Policy:  tc.policy,
+				decoder: decoder,
+				newClient: func(_ context.Context, _ *infrav1.AzureMachine, subscriptionID string) (client, error) {
+					g.Expect(subscriptionID).To(Equal("456"))
+					return clientMock, nil
+				},
+				now: func() time.Time { return now },
+			}
+
+			machine := &infrav1.AzureMachine{
+				TypeMeta:   metav1.TypeMeta{APIVersion: infrav1.GroupVersion.String(), Kind: "AzureMachine"},
+				ObjectMeta: metav1.ObjectMeta{Name: "my-machine", Namespace: "default", Annotations: tc.annotations},
+				Spec:       infrav1.AzureMachineSpec{Image: tc.image},
+			}
+			raw, err := json.Marshal(machine)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			resp := v.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			g.Expect(resp.Allowed).To(Equal(tc.wantAllowed))
+			if tc.wantMessage != "" {
+				g.Expect(string(resp.Result.Reason)).To(Equal(tc.wantMessage))
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inspect reports the live state of the Azure infrastructure of a workload cluster.
+package inspect
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
+)
+
+// DefaultMaxErrors is the default number of recent errors in a cluster report.
+const DefaultMaxErrors = 10
+
+// ClusterReport describes the live state of the Azure infrastructure of a workload cluster.
+type ClusterReport struct {
+	Name           string `json:"name"`
+	Namespace      string `json:"namespace"`
+	SubscriptionID string `json:"subscriptionID,omitempty"`
+	ResourceGroup  string `json:"resourceGroup,omitempty"`
+	Location       string `json:"location,omitempty"`
+	Ready          bool   `json:"ready"`
+
+	VNet          *azure.VNetSummary          `json:"vnet,omitempty"`
+	LoadBalancers []azure.LoadBalancerSummary `json:"loadBalancers,omitempty"`
+	PublicIPs     []azure.PublicIPSummary     `json:"publicIPs,omitempty"`
+	// DescribeErrors are the errors which occurred while getting the Azure resources of the cluster.
+	DescribeErrors []string `json:"describeErrors,omitempty"`
+
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+	Operations []Operation          `json:"operations,omitempty"`
+	Errors     []Error              `json:"errors,omitempty"`
+}
+
+// Operation is an Azure long-running operation which has not completed yet.
+type Operation struct {
+	Type          string `json:"type"`
+	ServiceName   string `json:"serviceName"`
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+	Name          string `json:"name"`
+}
+
+// Error is a recent error reported by the controllers for the cluster, which includes the errors returned by Azure Resource Manager.
+type Error struct {
+	Time    metav1.Time `json:"time"`
+	Reason  string      `json:"reason"`
+	Message string      `json:"message"`
+	Count   int32       `json:"count,omitempty"`
+}
+
+// Options are the options of a cluster inspection.
+type Options struct {
+	// MaxErrors is the maximum number of recent errors in the report. Defaults to DefaultMaxErrors.
+	MaxErrors int
+	// SkipAzure only reports the state recorded in the management cluster, without getting the Azure resources.
+	SkipAzure bool
+}
+
+// Inspect returns the report of the AzureCluster with the given key. The Azure resources are described with the same
+// scope and services as the AzureCluster controller, using the credentials of the AzureCluster.
+func Inspect(ctx context.Context, c client.Client, key client.ObjectKey, opts Options) (*ClusterReport, error) {
+	azureCluster := &infrav1.AzureCluster{}
+	if err := c.Get(ctx, key, azureCluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to get AzureCluster %s", key)
+	}
+
+	report := newClusterReport(azureCluster)
+
+	recentErrors, err := getRecentErrors(ctx, c, azureCluster, opts.MaxErrors)
+	if err != nil {
+		return nil, err
+	}
+	report.Errors = recentErrors
+
+	if opts.SkipAzure {
+		return report, nil
+	}
+
+	cluster, err := util.GetOwnerCluster(ctx, c, azureCluster.ObjectMeta)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get owner Cluster of AzureCluster %s", key)
+	}
+	if cluster == nil {
+		return nil, errors.Errorf("AzureCluster %s has no owner Cluster yet", key)
+	}
+
+	clusterScope, err := newClusterScope(ctx, c, cluster, azureCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create scope")
+	}
+
+	report.describeResources(ctx, clusterScope)
+	return report, nil
+}
+
+// newClusterReport returns the report of the state of the AzureCluster recorded in the management cluster.
+func newClusterReport(azureCluster *infrav1.AzureCluster) *ClusterReport {
+	report := &ClusterReport{
+		Name:           azureCluster.Name,
+		Namespace:      azureCluster.Namespace,
+		SubscriptionID: azureCluster.Spec.SubscriptionID,
+		ResourceGroup:  azureCluster.Spec.ResourceGroup,
+		Location:       azureCluster.Spec.Location,
+		Ready:          azureCluster.Status.Ready,
+		Conditions:     azureCluster.Status.Conditions,
+	}
+	for _, future := range azureCluster.Status.LongRunningOperationStates {
+		report.Operations = append(report.Operations, Operation{
+			Type:          future.Type,
+			ServiceName:   future.ServiceName,
+			ResourceGroup: future.ResourceGroup,
+			Name:          future.Name,
+		})
+	}
+	return report
+}
+
+// describeResources adds the Azure resources of the cluster to the report. An error describing a type of resources
+// doesn't prevent describing the others.
+func (r *ClusterReport) describeResources(ctx context.Context, clusterScope *scope.ClusterScope) {
+	vnet, err := virtualnetworks.New(clusterScope).Describe(ctx)
+	switch {
+	case azure.ResourceNotFound(err):
+		r.DescribeErrors = append(r.DescribeErrors, errors.Wrapf(err, "virtual network %s not found", clusterScope.Vnet().Name).Error())
+	case err != nil:
+		r.DescribeErrors = append(r.DescribeErrors, err.Error())
+	default:
+		r.VNet = vnet
+	}
+
+	if r.LoadBalancers, err = loadbalancers.New(clusterScope).Describe(ctx); err != nil {
+		r.DescribeErrors = append(r.DescribeErrors, err.Error())
+	}
+
+	if r.PublicIPs, err = publicips.New(clusterScope).Describe(ctx); err != nil {
+		r.DescribeErrors = append(r.DescribeErrors, err.Error())
+	}
+}
+
+// getRecentErrors returns the most recent warning events of the AzureCluster, which hold the errors of its reconciliation.
+func getRecentErrors(ctx context.Context, c client.Client, azureCluster *infrav1.AzureCluster, maxErrors int) ([]Error, error) {
+	if maxErrors <= 0 {
+		maxErrors = DefaultMaxErrors
+	}
+
+	events := &corev1.EventList{}
+	if err := c.List(ctx, events, client.InNamespace(azureCluster.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list events in namespace %s", azureCluster.Namespace)
+	}
+
+	var recentErrors []Error
+	for _, event := range events.Items {
+		if event.Type != corev1.EventTypeWarning || event.InvolvedObject.UID != azureCluster.UID {
+			continue
+		}
+		eventTime := event.LastTimestamp
+		if eventTime.IsZero() {
+			eventTime = metav1.NewTime(event.EventTime.Time)
+		}
+		recentErrors = append(recentErrors, Error{
+			Time:    eventTime,
+			Reason:  event.Reason,
+			Message: event.Message,
+			Count:   event.Count,
+		})
+	}
+
+	sort.SliceStable(recentErrors, func(i, j int) bool {
+		return recentErrors[j].Time.Before(&recentErrors[i].Time)
+	})
+	if len(recentErrors) > maxErrors {
+		recentErrors = recentErrors[:maxErrors]
+	}
+	return recentErrors, nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+var fakeAzureCluster = &infrav1.AzureCluster{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "my-cluster",
+		Namespace: "default",
+		UID:       types.UID("my-cluster-uid"),
+	},
+	Spec: infrav1.AzureClusterSpec{
+		SubscriptionID: "123",
+		Location:       "westus2",
+		ResourceGroup:  "my-rg",
+	},
+	Status: infrav1.AzureClusterStatus{
+		Conditions: clusterv1.Conditions{
+			{
+				Type:     infrav1.NetworkInfrastructureReadyCondition,
+				Status:   corev1.ConditionFalse,
+				Severity: clusterv1.ConditionSeverityError,
+				Reason:   infrav1.FailedReason,
+				Message:  "failed to create resource my-rg/my-vnet (service: virtualnetworks):\n  StatusCode=403",
+			},
+		},
+		LongRunningOperationStates: infrav1.Futures{
+			{
+				Type:          infrav1.PutFuture,
+				ServiceName:   "privateendpoints",
+				ResourceGroup: "my-rg",
+				Name:          "my-registry-pe",
+				Data:          "future-data",
+			},
+		},
+	},
+}
+
+func newWarningEvent(name string, involvedObject types.UID, lastTimestamp time.Time, reason string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{UID: involvedObject},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        "some error happened",
+		Count:          2,
+		LastTimestamp:  metav1.NewTime(lastTimestamp),
+	}
+}
+
+func TestInspect(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	initObjects := []client.Object{
+		fakeAzureCluster.DeepCopy(),
+		newWarningEvent("old-error", fakeAzureCluster.UID, now.Add(-time.Hour), "ClusterReconcilerNormalFailed"),
+		newWarningEvent("recent-error", fakeAzureCluster.UID, now, "ClusterReconcilerDeleteFailed"),
+		newWarningEvent("other-object", types.UID("other-uid"), now, "ClusterReconcilerNormalFailed"),
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "normal", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{UID: fakeAzureCluster.UID},
+			Type:           corev1.EventTypeNormal,
+			Reason:         "ClusterPaused",
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
+
+	report, err := Inspect(context.TODO(), c, client.ObjectKey{Namespace: "default", Name: "my-cluster"}, Options{
+		MaxErrors: 1,
+		SkipAzure: true,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(report.Name).To(Equal("my-cluster"))
+	g.Expect(report.SubscriptionID).To(Equal("123"))
+	g.Expect(report.ResourceGroup).To(Equal("my-rg"))
+	g.Expect(report.Conditions).To(Equal(fakeAzureCluster.Status.Conditions))
+	g.Expect(report.Operations).To(Equal([]Operation{
+		{Type: infrav1.PutFuture, ServiceName: "privateendpoints", ResourceGroup: "my-rg", Name: "my-registry-pe"},
+	}))
+	g.Expect(report.Errors).To(HaveLen(1))
+	g.Expect(report.Errors[0].Reason).To(Equal("ClusterReconcilerDeleteFailed"))
+
+	_, err = Inspect(context.TODO(), c, client.ObjectKey{Namespace: "default", Name: "missing"}, Options{})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestPrint(t *testing.T) {
+	report := newClusterReport(fakeAzureCluster)
+	report.VNet = &azure.VNetSummary{
+		Name:              "my-vnet",
+		CIDRs:             []string{"10.0.0.0/8"},
+		ProvisioningState: infrav1.Succeeded,
+		Subnets: []azure.SubnetSummary{
+			{
+				Name:            "my-subnet",
+				CIDRs:           []string{"10.0.0.0/16"},
+				SecurityGroupID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-nsg",
+			},
+		},
+	}
+	report.LoadBalancers = []azure.LoadBalancerSummary{
+		{
+			Name:        "my-lb",
+			SKU:         "Standard",
+			FrontendIPs: []azure.FrontendIPSummary{{Name: "my-frontend", PublicIPID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-publicip"}},
+		},
+	}
+	report.DescribeErrors = []string{"failed to get public IP my-publicip"}
+	report.Errors = []Error{{Time: metav1.NewTime(time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)), Reason: "ClusterReconcilerNormalFailed", Message: "failed\nto reconcile", Count: 3}}
+
+	t.Run("text", func(t *testing.T) {
+		g := NewWithT(t)
+		var buf bytes.Buffer
+		g.Expect(report.Print(&buf, OutputText)).To(Succeed())
+		out := buf.String()
+		g.Expect(out).To(ContainSubstring("default/my-cluster"))
+		g.Expect(out).To(MatchRegexp(`VirtualNetwork my-vnet\s+Succeeded\s+10.0.0.0/8`))
+		g.Expect(out).To(ContainSubstring("NetworkSecurityGroup my-nsg"))
+		g.Expect(out).To(MatchRegexp(`FrontendIP my-frontend\s+my-publicip`))
+		g.Expect(out).To(ContainSubstring("Error: failed to get public IP my-publicip"))
+		g.Expect(out).To(MatchRegexp(`NetworkInfrastructureReady\s+False\s+Error\s+Failed\s+failed to create resource my-rg/my-vnet \(service: virtualnetworks\): StatusCode=403`))
+		g.Expect(out).To(MatchRegexp(`privateendpoints\s+my-rg/my-registry-pe\s+PUT`))
+		g.Expect(out).To(MatchRegexp(`2022-01-01T12:00:00Z\s+ClusterReconcilerNormalFailed\s+3\s+failed to reconcile`))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		g := NewWithT(t)
+		var buf bytes.Buffer
+		g.Expect(report.Print(&buf, OutputJSON)).To(Succeed())
+		decoded := &ClusterReport{}
+		g.Expect(json.Unmarshal(buf.Bytes(), decoded)).To(Succeed())
+		g.Expect(decoded.VNet).To(Equal(report.VNet))
+		g.Expect(decoded.Operations).To(Equal(report.Operations))
+		g.Expect(buf.String()).NotTo(ContainSubstring("future-data"))
+	})
+
+	t.Run("unknown output", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(report.Print(&bytes.Buffer{}, "table")).To(MatchError(`unknown output format "table", must be one of text, json or yaml`))
+	})
+}
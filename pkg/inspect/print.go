@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+const (
+	// OutputText prints a cluster report as human-readable text.
+	OutputText = "text"
+	// OutputJSON prints a cluster report as JSON.
+	OutputJSON = "json"
+	// OutputYAML prints a cluster report as YAML.
+	OutputYAML = "yaml"
+)
+
+// Print writes the cluster report to w in the given output format.
+func (r *ClusterReport) Print(w io.Writer, output string) error {
+	switch output {
+	case OutputText, "":
+		return r.printText(w)
+	case OutputJSON:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal cluster report")
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case OutputYAML:
+		data, err := yaml.Marshal(r)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal cluster report")
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		return errors.Errorf("unknown output format %q, must be one of %s, %s or %s", output, OutputText, OutputJSON, OutputYAML)
+	}
+}
+
+func (r *ClusterReport) printText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "AzureCluster:\t%s/%s\n", r.Namespace, r.Name)
+	fmt.Fprintf(tw, "Ready:\t%t\n", r.Ready)
+	fmt.Fprintf(tw, "Subscription:\t%s\n", r.SubscriptionID)
+	fmt.Fprintf(tw, "Resource group:\t%s\n", r.ResourceGroup)
+	fmt.Fprintf(tw, "Location:\t%s\n", r.Location)
+
+	fmt.Fprintln(tw, "\nResources:")
+	if r.VNet != nil {
+		printVNet(tw, r.VNet)
+	}
+	for i := range r.LoadBalancers {
+		printLoadBalancer(tw, &r.LoadBalancers[i])
+	}
+	for _, ip := range r.PublicIPs {
+		address := ip.IPAddress
+		if ip.FQDN != "" {
+			address = fmt.Sprintf("%s (%s)", ip.IPAddress, ip.FQDN)
+		}
+		fmt.Fprintf(tw, "  PublicIP %s\t%s\t%s\n", ip.Name, ip.ProvisioningState, address)
+	}
+	for _, err := range r.DescribeErrors {
+		fmt.Fprintf(tw, "  Error: %s\n", err)
+	}
+
+	fmt.Fprintln(tw, "\nConditions:")
+	if len(r.Conditions) > 0 {
+		fmt.Fprintln(tw, "  TYPE\tSTATUS\tSEVERITY\tREASON\tMESSAGE")
+	}
+	for _, condition := range r.Conditions {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Severity, condition.Reason, singleLine(condition.Message))
+	}
+
+	fmt.Fprintln(tw, "\nOperations in progress:")
+	if len(r.Operations) > 0 {
+		fmt.Fprintln(tw, "  SERVICE\tRESOURCE\tTYPE")
+	}
+	for _, operation := range r.Operations {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", operation.ServiceName, path.Join(operation.ResourceGroup, operation.Name), operation.Type)
+	}
+
+	fmt.Fprintln(tw, "\nRecent errors:")
+	if len(r.Errors) > 0 {
+		fmt.Fprintln(tw, "  TIME\tREASON\tCOUNT\tMESSAGE")
+	}
+	for _, e := range r.Errors {
+		fmt.Fprintf(tw, "  %s\t%s\t%d\t%s\n", e.Time.UTC().Format(time.RFC3339), e.Reason, e.Count, singleLine(e.Message))
+	}
+
+	return tw.Flush()
+}
+
+func printVNet(w io.Writer, vnet *azure.VNetSummary) {
+	fmt.Fprintf(w, "  VirtualNetwork %s\t%s\t%s\n", vnet.Name, vnet.ProvisioningState, strings.Join(vnet.CIDRs, ","))
+	for _, subnet := range vnet.Subnets {
+		fmt.Fprintf(w, "    Subnet %s\t%s\t%s\n", subnet.Name, subnet.ProvisioningState, strings.Join(subnet.CIDRs, ","))
+		if subnet.SecurityGroupID != "" {
+			fmt.Fprintf(w, "      NetworkSecurityGroup %s\t\t\n", path.Base(subnet.SecurityGroupID))
+		}
+		if subnet.RouteTableID != "" {
+			fmt.Fprintf(w, "      RouteTable %s\t\t\n", path.Base(subnet.RouteTableID))
+		}
+		if subnet.NatGatewayID != "" {
+			fmt.Fprintf(w, "      NatGateway %s\t\t\n", path.Base(subnet.NatGatewayID))
+		}
+	}
+}
+
+func printLoadBalancer(w io.Writer, lb *azure.LoadBalancerSummary) {
+	fmt.Fprintf(w, "  LoadBalancer %s\t%s\t%s\n", lb.Name, lb.ProvisioningState, lb.SKU)
+	for _, frontend := range lb.FrontendIPs {
+		address := frontend.PrivateIPAddress
+		if frontend.PublicIPID != "" {
+			address = path.Base(frontend.PublicIPID)
+		}
+		fmt.Fprintf(w, "    FrontendIP %s\t\t%s\n", frontend.Name, address)
+	}
+	for _, pool := range lb.BackendPools {
+		fmt.Fprintf(w, "    BackendPool %s\t\t\n", pool)
+	}
+}
+
+// singleLine keeps the messages of conditions and errors, which often hold a multi-line Azure error, on a single line of the table.
+func singleLine(message string) string {
+	return strings.Join(strings.Fields(message), " ")
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspect
+
+import (
+	"context"
+	"os"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+)
+
+// newClusterScope creates the scope of the AzureCluster. The controller authenticates the clusters which reference an
+// AzureClusterIdentity with AAD pod identity, which is only available in the management cluster, so these clusters are
+// authenticated with the client secret of the service principal of the identity instead.
+func newClusterScope(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, azureCluster *infrav1.AzureCluster) (*scope.ClusterScope, error) {
+	if azureCluster.Spec.IdentityRef == nil {
+		return scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+			Client:       c,
+			Cluster:      cluster,
+			AzureCluster: azureCluster,
+		})
+	}
+
+	credentialsProvider, err := scope.NewAzureClusterCredentialsProvider(ctx, c, azureCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init credentials provider")
+	}
+
+	environment := azureautorest.PublicCloud
+	if azureCluster.Spec.AzureEnvironment != "" {
+		if environment, err = azureautorest.EnvironmentFromName(azureCluster.Spec.AzureEnvironment); err != nil {
+			return nil, err
+		}
+	}
+
+	subscriptionID := azureCluster.Spec.SubscriptionID
+	if subscriptionID == "" {
+		subscriptionID = os.Getenv(auth.SubscriptionID)
+		if subscriptionID == "" {
+			return nil, errors.New("subscriptionID is not set in cluster or AZURE_SUBSCRIPTION_ID env var")
+		}
+	}
+
+	clientSecret, err := credentialsProvider.GetClientSecret(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client secret")
+	}
+	oauthConfig, err := adal.NewOAuthConfig(environment.ActiveDirectoryEndpoint, credentialsProvider.GetTenantID())
+	if err != nil {
+		return nil, err
+	}
+	token, err := adal.NewServicePrincipalToken(*oauthConfig, credentialsProvider.GetClientID(), clientSecret, environment.ResourceManagerEndpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get token from service principal identity")
+	}
+
+	return &scope.ClusterScope{
+		Client: c,
+		AzureClients: scope.AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Environment: environment,
+				Values: map[string]string{
+					auth.SubscriptionID: subscriptionID,
+					auth.TenantID:       credentialsProvider.GetTenantID(),
+					auth.ClientID:       credentialsProvider.GetClientID(),
+				},
+			},
+			Authorizer:                 autorest.NewBearerAuthorizer(token),
+			ResourceManagerEndpoint:    environment.ResourceManagerEndpoint,
+			ResourceManagerVMDNSSuffix: environment.ResourceManagerVMDNSSuffix,
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	}, nil
+}
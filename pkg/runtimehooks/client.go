@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtimehooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client calls a runtime extension over HTTP. Each hook is sent as a POST request of a JSON Request to the path of the
+// hook under the URL of the extension, e.g. https://extension.example.com/hooks/BeforeMachineDelete, which must answer
+// with a JSON Response.
+type Client struct {
+	// URL is the base URL of the runtime extension.
+	URL        string
+	httpClient *http.Client
+}
+
+var _ Caller = &Client{}
+
+// NewClient creates a client of the runtime extension served at the given URL, whose calls time out after the given
+// duration. It returns nil if the URL is empty, i.e. no runtime extension is configured.
+func NewClient(url string, timeout time.Duration) *Client {
+	if url == "" {
+		return nil
+	}
+	return &Client{
+		URL:        strings.TrimSuffix(url, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Call calls a hook of the runtime extension. It is a no-op on a nil client.
+func (c *Client) Call(ctx context.Context, req Request) error {
+	if c == nil {
+		return nil
+	}
+
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "runtimehooks.Client.Call", tele.KVP("hook", string(req.Hook)))
+	defer done()
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal request of hook %s", req.Hook)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL+"/"+string(req.Hook), bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to create request of hook %s", req.Hook)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	log.V(4).Info("calling runtime extension", "hook", req.Hook, "cluster", req.Cluster.Name)
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return errors.Wrapf(err, "failed to call hook %s", req.Hook)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to call hook %s: runtime extension answered with status code %d", req.Hook, httpResp.StatusCode)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return errors.Wrapf(err, "failed to decode response of hook %s", req.Hook)
+	}
+	switch {
+	case resp.Status != ResponseStatusSuccess:
+		return errors.Errorf("hook %s failed: %s", req.Hook, resp.Message)
+	case req.Hook.Blocking() && resp.RetryAfterSeconds > 0:
+		return azure.WithTransientError(errors.Errorf("hook %s is blocking: %s", req.Hook, resp.Message), time.Duration(resp.RetryAfterSeconds)*time.Second)
+	default:
+		return nil
+	}
+}
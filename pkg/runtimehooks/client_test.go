@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtimehooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+func TestCall(t *testing.T) {
+	tests := []struct {
+		name          string
+		hook          Hook
+		statusCode    int
+		response      Response
+		expectedError string
+		transient     bool
+	}{
+		{
+			name:       "hook succeeded",
+			hook:       BeforeClusterNetworkReconcile,
+			statusCode: http.StatusOK,
+			response:   Response{Status: ResponseStatusSuccess},
+		},
+		{
+			name:          "blocking hook asks to retry",
+			hook:          BeforeMachineDelete,
+			statusCode:    http.StatusOK,
+			response:      Response{Status: ResponseStatusSuccess, Message: "draining", RetryAfterSeconds: 20},
+			expectedError: "hook BeforeMachineDelete is blocking: draining. Object will be requeued after 20s",
+			transient:     true,
+		},
+		{
+			name:       "non-blocking hook asks to retry",
+			hook:       AfterControlPlaneLBReady,
+			statusCode: http.StatusOK,
+			response:   Response{Status: ResponseStatusSuccess, RetryAfterSeconds: 20},
+		},
+		{
+			name:          "hook failed",
+			hook:          BeforeClusterNetworkReconcile,
+			statusCode:    http.StatusOK,
+			response:      Response{Status: ResponseStatusFailure, Message: "no IP address range left"},
+			expectedError: "hook BeforeClusterNetworkReconcile failed: no IP address range left",
+		},
+		{
+			name:          "runtime extension error",
+			hook:          AfterControlPlaneLBReady,
+			statusCode:    http.StatusInternalServerError,
+			expectedError: "failed to call hook AfterControlPlaneLBReady: runtime extension answered with status code 500",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var received Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.Method).To(Equal(http.MethodPost))
+				g.Expect(r.URL.Path).To(Equal("/hooks/" + string(tc.hook)))
+				g.Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+				w.WriteHeader(tc.statusCode)
+				g.Expect(json.NewEncoder(w).Encode(tc.response)).To(Succeed())
+			}))
+			defer server.Close()
+
+			c := NewClient(server.URL+"/hooks/", 5*time.Second)
+			err := c.Call(context.TODO(), Request{
+				Hook:    tc.hook,
+				Cluster: ObjectReference{Namespace: "default", Name: "my-cluster"},
+			})
+			g.Expect(received.Cluster.Name).To(Equal("my-cluster"))
+			if tc.expectedError == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(tc.expectedError))
+			var reconcileError azure.ReconcileError
+			g.Expect(errors.As(err, &reconcileError) && reconcileError.IsTransient()).To(Equal(tc.transient))
+		})
+	}
+}
+
+func TestCallWithoutRuntimeExtension(t *testing.T) {
+	g := NewWithT(t)
+	c := NewClient("", 5*time.Second)
+	g.Expect(c).To(BeNil())
+	g.Expect(c.Call(context.TODO(), Request{Hook: BeforeMachineDelete})).To(Succeed())
+}
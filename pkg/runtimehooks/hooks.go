@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package runtimehooks calls a runtime extension at the provisioning milestones of clusters and machines, so that
+// custom automation can run at these milestones without changing the provider.
+package runtimehooks
+
+import (
+	"context"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// Hook is a provisioning milestone at which the runtime extension is called.
+type Hook string
+
+const (
+	// BeforeClusterNetworkReconcile is called before the network of a cluster is reconciled for the first time. It can
+	// delay the creation of the network.
+	BeforeClusterNetworkReconcile Hook = "BeforeClusterNetworkReconcile"
+	// AfterControlPlaneLBReady is called once the load balancers of a cluster are reconciled for the first time, with the
+	// endpoint of its API server.
+	AfterControlPlaneLBReady Hook = "AfterControlPlaneLBReady"
+	// BeforeMachineDelete is called before the resources of a machine are deleted. It can delay the deletion.
+	BeforeMachineDelete Hook = "BeforeMachineDelete"
+)
+
+// OkToDeleteAnnotation is set on the AzureMachines whose deletion was allowed by the BeforeMachineDelete hook, so
+// that the hook is not called again while their resources are deleted.
+const OkToDeleteAnnotation = "runtimehooks.infrastructure.cluster.x-k8s.io/ok-to-delete"
+
+// Blocking returns true if the runtime extension can delay the provisioning step following the hook.
+func (h Hook) Blocking() bool {
+	return h == BeforeClusterNetworkReconcile || h == BeforeMachineDelete
+}
+
+// ObjectReference references an object by namespace and name.
+type ObjectReference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Request is the body of the request sent to the runtime extension.
+type Request struct {
+	// Hook is the hook being called.
+	Hook Hook `json:"hook"`
+	// Cluster is the Cluster the hook is called for.
+	Cluster ObjectReference `json:"cluster"`
+	// ControlPlaneEndpoint is the endpoint of the API server of the cluster, sent with AfterControlPlaneLBReady.
+	ControlPlaneEndpoint *clusterv1.APIEndpoint `json:"controlPlaneEndpoint,omitempty"`
+	// Machine is the AzureMachine the hook is called for, sent with machine hooks.
+	Machine *ObjectReference `json:"machine,omitempty"`
+	// ProviderID is the provider ID of the machine, if its VM was created.
+	ProviderID string `json:"providerID,omitempty"`
+}
+
+// ResponseStatus is the status of a hook call.
+type ResponseStatus string
+
+const (
+	// ResponseStatusSuccess means the runtime extension handled the hook.
+	ResponseStatusSuccess ResponseStatus = "Success"
+	// ResponseStatusFailure means the runtime extension failed to handle the hook.
+	ResponseStatusFailure ResponseStatus = "Failure"
+)
+
+// Response is the body of the response of the runtime extension.
+type Response struct {
+	// Status is the status of the hook call.
+	Status ResponseStatus `json:"status"`
+	// Message explains the status, e.g. why the hook failed or is blocking.
+	Message string `json:"message,omitempty"`
+	// RetryAfterSeconds delays the provisioning step following a blocking hook, which is called again after this
+	// duration. Ignored for non-blocking hooks.
+	RetryAfterSeconds int32 `json:"retryAfterSeconds,omitempty"`
+}
+
+// Caller calls the runtime extension.
+type Caller interface {
+	// Call calls a hook, returning a transient error if a blocking hook delays the following provisioning step.
+	Call(ctx context.Context, req Request) error
+}
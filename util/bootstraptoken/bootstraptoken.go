@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bootstraptoken inspects the kubeadm bootstrap tokens used by the bootstrap data of machines.
+package bootstraptoken
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// secretPrefix is the prefix of the name of the secrets holding bootstrap tokens in the workload cluster.
+	secretPrefix = "bootstrap-token-"
+	// expirationKey is the key of the expiration time of a bootstrap token in its secret.
+	expirationKey = "expiration"
+)
+
+// tokenRegexp matches the kubeadm bootstrap token of a join configuration, capturing the token ID.
+var tokenRegexp = regexp.MustCompile(`token:\s*["']?([a-z0-9]{6})\.[a-z0-9]{16}\b`)
+
+// IDFromBootstrapData returns the ID of the kubeadm bootstrap token used by the bootstrap data, if it has one.
+func IDFromBootstrapData(data []byte) (string, bool) {
+	match := tokenRegexp.FindSubmatch(data)
+	if match == nil {
+		return "", false
+	}
+	return string(match[1]), true
+}
+
+// ExpiresBefore reports whether the bootstrap token with the given ID expires before the deadline. Tokens which are
+// missing from the workload cluster, typically because the token cleaner removed them after they expired, are reported
+// as expiring.
+func ExpiresBefore(ctx context.Context, c client.Client, id string, deadline time.Time) (bool, error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: secretPrefix + id}
+	if err := c.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, errors.Wrapf(err, "failed to get bootstrap token %s", id)
+	}
+
+	value, ok := secret.Data[expirationKey]
+	if !ok || len(value) == 0 {
+		// The token never expires.
+		return false, nil
+	}
+	expiration, err := time.Parse(time.RFC3339, string(value))
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse the expiration of bootstrap token %s", id)
+	}
+	return expiration.Before(deadline), nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstraptoken
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIDFromBootstrapData(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		wantID string
+		wantOK bool
+	}{
+		{
+			name:   "cloud-init join configuration",
+			data:   "#cloud-config\nwrite_files:\n- content: |\n    discovery:\n      bootstrapToken:\n        apiServerEndpoint: my-cluster.eastus.cloudapp.azure.com:6443\n        token: abcdef.0123456789abcdef\n",
+			wantID: "abcdef",
+			wantOK: true,
+		},
+		{
+			name:   "quoted token",
+			data:   `token: "x1y2z3.0123456789abcdef"`,
+			wantID: "x1y2z3",
+			wantOK: true,
+		},
+		{
+			name:   "init configuration without a token",
+			data:   "#cloud-config\nwrite_files:\n- content: |\n    kind: InitConfiguration\n",
+			wantOK: false,
+		},
+		{
+			name:   "malformed token",
+			data:   "token: ABCDEF.0123456789abcdef",
+			wantOK: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			id, ok := IDFromBootstrapData([]byte(tc.data))
+			g.Expect(ok).To(Equal(tc.wantOK))
+			g.Expect(id).To(Equal(tc.wantID))
+		})
+	}
+}
+
+func TestExpiresBefore(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		objects []client.Object
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:    "token expires after the deadline",
+			objects: []client.Object{tokenSecret("abcdef", now.Add(10*time.Minute).Format(time.RFC3339))},
+			want:    false,
+		},
+		{
+			name:    "token expires before the deadline",
+			objects: []client.Object{tokenSecret("abcdef", now.Add(2*time.Minute).Format(time.RFC3339))},
+			want:    true,
+		},
+		{
+			name:    "token never expires",
+			objects: []client.Object{tokenSecret("abcdef", "")},
+			want:    false,
+		},
+		{
+			name: "token was removed",
+			want: true,
+		},
+		{
+			name:    "invalid expiration",
+			objects: []client.Object{tokenSecret("abcdef", "tomorrow")},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := fake.NewClientBuilder().WithObjects(tc.objects...).Build()
+			got, err := ExpiresBefore(context.Background(), c, "abcdef", now.Add(5*time.Minute))
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
+
+func tokenSecret(id, expiration string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceSystem,
+			Name:      secretPrefix + id,
+		},
+		Data: map[string][]byte{
+			"token-id": []byte(id),
+		},
+	}
+	if expiration != "" {
+		secret.Data[expirationKey] = []byte(expiration)
+	}
+	return secret
+}
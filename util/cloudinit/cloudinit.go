@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cloudinit contains helpers for amending cloud-init bootstrap data.
+package cloudinit
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const cloudConfigHeader = "#cloud-config"
+
+// amendCloudConfig appends files to the write_files of cloud-config bootstrap data and prepends commands to its runcmd.
+// Data which is not a cloud-config document is returned unchanged.
+func amendCloudConfig(data []byte, files, commands []interface{}) ([]byte, error) {
+	header, body, ok := splitCloudConfig(data)
+	if !ok {
+		return data, nil
+	}
+
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal(body, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse cloud-config bootstrap data")
+	}
+
+	existingFiles, err := listValue(config, "write_files")
+	if err != nil {
+		return nil, err
+	}
+	config["write_files"] = append(existingFiles, files...)
+
+	existingCommands, err := listValue(config, "runcmd")
+	if err != nil {
+		return nil, err
+	}
+	config["runcmd"] = append(commands, existingCommands...)
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal cloud-config bootstrap data")
+	}
+	return append(header, out...), nil
+}
+
+// splitCloudConfig separates the leading comment lines of a cloud-config document, such as "## template: jinja" and
+// "#cloud-config", from its body. It returns false if the data is not a cloud-config document.
+func splitCloudConfig(data []byte) (header, body []byte, ok bool) {
+	offset := 0
+	for offset < len(data) && data[offset] == '#' {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end < 0 {
+			end = len(data) - offset
+		} else {
+			end++
+		}
+		if strings.TrimSpace(string(data[offset:offset+end])) == cloudConfigHeader {
+			ok = true
+		}
+		offset += end
+	}
+	if !ok {
+		return nil, nil, false
+	}
+	header = append([]byte{}, data[:offset]...)
+	if header[len(header)-1] != '\n' {
+		header = append(header, '\n')
+	}
+	return header, data[offset:], true
+}
+
+// listValue returns the list stored under key in a cloud-config document, or an empty list if the key is absent.
+func listValue(config map[string]interface{}, key string) ([]interface{}, error) {
+	value, ok := config[key]
+	if !ok || value == nil {
+		return []interface{}{}, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected cloud-config key %q to be a list, got %T", key, value)
+	}
+	return list, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"fmt"
+	"strings"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+const (
+	// EnvironmentPath is the file the proxy environment variables are appended to.
+	EnvironmentPath = "/etc/environment"
+	// ContainerdProxyDropInPath is the systemd drop-in configuring the proxy for containerd.
+	ContainerdProxyDropInPath = "/etc/systemd/system/containerd.service.d/http-proxy.conf"
+	// KubeletProxyDropInPath is the systemd drop-in configuring the proxy for the kubelet.
+	KubeletProxyDropInPath = "/etc/systemd/system/kubelet.service.d/http-proxy.conf"
+	// TrustedCAPath is the file the proxy's trusted CA bundle is written to.
+	TrustedCAPath = "/usr/local/share/ca-certificates/proxy-ca.crt"
+)
+
+// InjectProxyConfig adds the given proxy configuration to cloud-config bootstrap data. The proxy environment is written
+// to /etc/environment and to systemd drop-ins for containerd and the kubelet, the trusted CA bundle (if any) is
+// installed, and commands to apply the configuration are prepended to runcmd so that they run before the node joins
+// the cluster. Data which is not a cloud-config document is returned unchanged.
+func InjectProxyConfig(data []byte, proxy *infrav1.ProxyConfig) ([]byte, error) {
+	if proxy == nil {
+		return data, nil
+	}
+
+	env := proxyEnvironment(proxy)
+	files := []interface{}{
+		map[string]interface{}{
+			"path":        EnvironmentPath,
+			"owner":       "root:root",
+			"permissions": "0644",
+			"append":      true,
+			"content":     environmentFileContent(env),
+		},
+		map[string]interface{}{
+			"path":        ContainerdProxyDropInPath,
+			"owner":       "root:root",
+			"permissions": "0644",
+			"content":     systemdDropInContent(env),
+		},
+		map[string]interface{}{
+			"path":        KubeletProxyDropInPath,
+			"owner":       "root:root",
+			"permissions": "0644",
+			"content":     systemdDropInContent(env),
+		},
+	}
+	commands := []interface{}{}
+	if proxy.TrustedCA != "" {
+		files = append(files, map[string]interface{}{
+			"path":        TrustedCAPath,
+			"owner":       "root:root",
+			"permissions": "0644",
+			"content":     proxy.TrustedCA,
+		})
+		commands = append(commands, "update-ca-certificates")
+	}
+	commands = append(commands, "systemctl daemon-reload", "systemctl restart containerd")
+
+	return amendCloudConfig(data, files, commands)
+}
+
+// proxyEnvironment returns the proxy environment variables, in both upper and lower case since tools differ in which
+// they honor.
+func proxyEnvironment(proxy *infrav1.ProxyConfig) []string {
+	var env []string
+	add := func(name, value string) {
+		if value == "" {
+			return
+		}
+		env = append(env, fmt.Sprintf("%s=%s", strings.ToUpper(name), value), fmt.Sprintf("%s=%s", name, value))
+	}
+	add("http_proxy", proxy.HTTPProxy)
+	add("https_proxy", proxy.HTTPSProxy)
+	add("no_proxy", strings.Join(proxy.NoProxy, ","))
+	return env
+}
+
+func environmentFileContent(env []string) string {
+	var b strings.Builder
+	for _, e := range env {
+		b.WriteString(e)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func systemdDropInContent(env []string) string {
+	var b strings.Builder
+	b.WriteString("[Service]\n")
+	for _, e := range env {
+		fmt.Fprintf(&b, "Environment=%q\n", e)
+	}
+	return b.String()
+}
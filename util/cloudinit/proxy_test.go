@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+const kubeadmCloudConfig = `## template: jinja
+#cloud-config
+
+write_files:
+-   path: /etc/kubernetes/azure.json
+    owner: root:root
+    permissions: '0644'
+    content: |
+      {}
+runcmd:
+  - 'kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml'
+`
+
+func TestInjectProxyConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		proxy  *infrav1.ProxyConfig
+		expect func(g *WithT, out string)
+	}{
+		{
+			name:  "nil proxy config leaves data unchanged",
+			data:  kubeadmCloudConfig,
+			proxy: nil,
+			expect: func(g *WithT, out string) {
+				g.Expect(out).To(Equal(kubeadmCloudConfig))
+			},
+		},
+		{
+			name: "non cloud-config data is left unchanged",
+			data: "#!/bin/bash\necho hello\n",
+			proxy: &infrav1.ProxyConfig{
+				HTTPProxy: "http://proxy.example.com:3128",
+			},
+			expect: func(g *WithT, out string) {
+				g.Expect(out).To(Equal("#!/bin/bash\necho hello\n"))
+			},
+		},
+		{
+			name: "proxy settings are written and applied before existing commands",
+			data: kubeadmCloudConfig,
+			proxy: &infrav1.ProxyConfig{
+				HTTPProxy:  "http://proxy.example.com:3128",
+				HTTPSProxy: "http://proxy.example.com:3129",
+				NoProxy:    []string{"localhost", "10.0.0.0/8"},
+			},
+			expect: func(g *WithT, out string) {
+				g.Expect(out).To(HavePrefix("## template: jinja\n#cloud-config\n"))
+				config := parse(g, out)
+
+				files := config["write_files"].([]interface{})
+				g.Expect(files).To(HaveLen(4))
+				g.Expect(paths(files)).To(Equal([]string{
+					"/etc/kubernetes/azure.json",
+					EnvironmentPath,
+					ContainerdProxyDropInPath,
+					KubeletProxyDropInPath,
+				}))
+				env := files[1].(map[string]interface{})
+				g.Expect(env["append"]).To(BeTrue())
+				g.Expect(env["content"]).To(Equal("HTTP_PROXY=http://proxy.example.com:3128\nhttp_proxy=http://proxy.example.com:3128\n" +
+					"HTTPS_PROXY=http://proxy.example.com:3129\nhttps_proxy=http://proxy.example.com:3129\n" +
+					"NO_PROXY=localhost,10.0.0.0/8\nno_proxy=localhost,10.0.0.0/8\n"))
+				dropIn := files[2].(map[string]interface{})["content"].(string)
+				g.Expect(dropIn).To(HavePrefix("[Service]\n"))
+				g.Expect(dropIn).To(ContainSubstring(`Environment="HTTPS_PROXY=http://proxy.example.com:3129"`))
+
+				g.Expect(config["runcmd"]).To(Equal([]interface{}{
+					"systemctl daemon-reload",
+					"systemctl restart containerd",
+					"kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml",
+				}))
+			},
+		},
+		{
+			name: "trusted CA is installed",
+			data: "#cloud-config\n",
+			proxy: &infrav1.ProxyConfig{
+				HTTPSProxy: "https://proxy.example.com",
+				TrustedCA:  "-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----\n",
+			},
+			expect: func(g *WithT, out string) {
+				config := parse(g, out)
+				files := config["write_files"].([]interface{})
+				g.Expect(paths(files)).To(ContainElement(TrustedCAPath))
+				g.Expect(files[3].(map[string]interface{})["content"]).To(HavePrefix("-----BEGIN CERTIFICATE-----"))
+				g.Expect(config["runcmd"]).To(Equal([]interface{}{
+					"update-ca-certificates",
+					"systemctl daemon-reload",
+					"systemctl restart containerd",
+				}))
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			out, err := InjectProxyConfig([]byte(tc.data), tc.proxy)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, string(out))
+		})
+	}
+}
+
+func TestInjectProxyConfigInvalidCloudConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := InjectProxyConfig([]byte("#cloud-config\nruncmd: echo\n"), &infrav1.ProxyConfig{HTTPProxy: "http://proxy:3128"})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func parse(g *WithT, out string) map[string]interface{} {
+	config := map[string]interface{}{}
+	body := out[strings.Index(out, "#cloud-config\n")+len("#cloud-config\n"):]
+	g.Expect(yaml.Unmarshal([]byte(body), &config)).To(Succeed())
+	return config
+}
+
+func paths(files []interface{}) []string {
+	var result []string
+	for _, f := range files {
+		result = append(result, f.(map[string]interface{})["path"].(string))
+	}
+	return result
+}
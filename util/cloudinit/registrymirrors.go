@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// ContainerdConfigPath is the containerd configuration file the credentials of the registry mirrors are appended to.
+const ContainerdConfigPath = "/etc/containerd/config.toml"
+
+// RegistryCredentials are the credentials used to pull images from the mirrors of a registry.
+type RegistryCredentials struct {
+	Username string
+	Password string
+}
+
+// InjectRegistryMirrors adds the given registry mirrors to cloud-config bootstrap data. A containerd registry host
+// configuration is written for each mirrored registry, the credentials of its mirrors (keyed by mirrored registry in
+// credentials) are appended to the containerd configuration, and a command restarting containerd is prepended to
+// runcmd so that the mirrors are used when the node joins the cluster. Data which is not a cloud-config document is
+// returned unchanged.
+func InjectRegistryMirrors(data []byte, mirrors []infrav1.RegistryMirror, credentials map[string]RegistryCredentials) ([]byte, error) {
+	if len(mirrors) == 0 {
+		return data, nil
+	}
+
+	files := []interface{}{}
+	var auths strings.Builder
+	authHosts := map[string]bool{}
+	for _, mirror := range mirrors {
+		files = append(files, map[string]interface{}{
+			"path":        path.Join(ContainerdCertsDir, mirror.Registry, "hosts.toml"),
+			"owner":       "root:root",
+			"permissions": "0644",
+			"content":     mirrorHostsContent(mirror),
+		})
+
+		creds, ok := credentials[mirror.Registry]
+		if !ok {
+			continue
+		}
+		for _, endpoint := range mirror.Endpoints {
+			u, err := url.Parse(endpoint)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse endpoint %q of the mirrors of registry %s", endpoint, mirror.Registry)
+			}
+			// containerd refuses a configuration defining the same table twice.
+			if authHosts[u.Host] {
+				continue
+			}
+			authHosts[u.Host] = true
+			fmt.Fprintf(&auths, "\n[plugins.\"io.containerd.grpc.v1.cri\".registry.configs.%q.auth]\n  username = %q\n  password = %q\n", u.Host, creds.Username, creds.Password)
+		}
+	}
+	if auths.Len() > 0 {
+		files = append(files, map[string]interface{}{
+			"path":        ContainerdConfigPath,
+			"owner":       "root:root",
+			"permissions": "0600",
+			"append":      true,
+			"content":     auths.String(),
+		})
+	}
+	commands := []interface{}{"systemctl restart containerd"}
+
+	return amendCloudConfig(data, files, commands)
+}
+
+// mirrorHostsContent returns the containerd host configuration of a registry which pulls images from its mirrors, in
+// order, before falling back to the registry itself.
+func mirrorHostsContent(mirror infrav1.RegistryMirror) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "server = %q\n", registryServer(mirror.Registry))
+	for _, endpoint := range mirror.Endpoints {
+		fmt.Fprintf(&b, "\n[host.%q]\n  capabilities = [\"pull\", \"resolve\"]\n", endpoint)
+	}
+	return b.String()
+}
+
+// registryServer returns the URL of the API of a registry, which for Docker Hub isn't served on its registry host.
+func registryServer(registry string) string {
+	if registry == "docker.io" {
+		return "https://registry-1.docker.io"
+	}
+	return "https://" + registry
+}
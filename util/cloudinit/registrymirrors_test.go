@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestInjectRegistryMirrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		mirrors     []infrav1.RegistryMirror
+		credentials map[string]RegistryCredentials
+		expect      func(g *WithT, out string)
+	}{
+		{
+			name:    "no mirrors leaves data unchanged",
+			data:    kubeadmCloudConfig,
+			mirrors: nil,
+			expect: func(g *WithT, out string) {
+				g.Expect(out).To(Equal(kubeadmCloudConfig))
+			},
+		},
+		{
+			name: "non cloud-config data is left unchanged",
+			data: "#!/bin/bash\necho hello\n",
+			mirrors: []infrav1.RegistryMirror{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}},
+			},
+			expect: func(g *WithT, out string) {
+				g.Expect(out).To(Equal("#!/bin/bash\necho hello\n"))
+			},
+		},
+		{
+			name: "mirrors are configured before existing commands",
+			data: kubeadmCloudConfig,
+			mirrors: []infrav1.RegistryMirror{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com:5000", "http://10.0.0.4:5000"}},
+				{Registry: "registry.k8s.io", Endpoints: []string{"https://mirror.example.com:5000"}},
+			},
+			expect: func(g *WithT, out string) {
+				g.Expect(out).To(HavePrefix("## template: jinja\n#cloud-config\n"))
+				config := parse(g, out)
+
+				files := config["write_files"].([]interface{})
+				g.Expect(paths(files)).To(Equal([]string{
+					"/etc/kubernetes/azure.json",
+					"/etc/containerd/certs.d/docker.io/hosts.toml",
+					"/etc/containerd/certs.d/registry.k8s.io/hosts.toml",
+				}))
+				g.Expect(files[1].(map[string]interface{})["content"]).To(Equal("server = \"https://registry-1.docker.io\"\n\n" +
+					"[host.\"https://mirror.example.com:5000\"]\n  capabilities = [\"pull\", \"resolve\"]\n\n" +
+					"[host.\"http://10.0.0.4:5000\"]\n  capabilities = [\"pull\", \"resolve\"]\n"))
+				g.Expect(files[2].(map[string]interface{})["content"]).To(Equal("server = \"https://registry.k8s.io\"\n\n" +
+					"[host.\"https://mirror.example.com:5000\"]\n  capabilities = [\"pull\", \"resolve\"]\n"))
+
+				g.Expect(config["runcmd"]).To(Equal([]interface{}{
+					"systemctl restart containerd",
+					"kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml",
+				}))
+			},
+		},
+		{
+			name: "credentials are appended to the containerd configuration once per mirror host",
+			data: "#cloud-config\n",
+			mirrors: []infrav1.RegistryMirror{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com:5000"}, CredentialsSecretName: "mirror-credentials"},
+				{Registry: "registry.k8s.io", Endpoints: []string{"https://mirror.example.com:5000"}, CredentialsSecretName: "mirror-credentials"},
+				{Registry: "ghcr.io", Endpoints: []string{"https://public-mirror.example.com"}},
+			},
+			credentials: map[string]RegistryCredentials{
+				"docker.io":       {Username: "puller", Password: "s3cr3t"},
+				"registry.k8s.io": {Username: "puller", Password: "s3cr3t"},
+			},
+			expect: func(g *WithT, out string) {
+				config := parse(g, out)
+
+				files := config["write_files"].([]interface{})
+				g.Expect(paths(files)).To(Equal([]string{
+					"/etc/containerd/certs.d/docker.io/hosts.toml",
+					"/etc/containerd/certs.d/registry.k8s.io/hosts.toml",
+					"/etc/containerd/certs.d/ghcr.io/hosts.toml",
+					ContainerdConfigPath,
+				}))
+				auth := files[3].(map[string]interface{})
+				g.Expect(auth["append"]).To(BeTrue())
+				g.Expect(auth["permissions"]).To(Equal("0600"))
+				g.Expect(auth["content"]).To(Equal("\n[plugins.\"io.containerd.grpc.v1.cri\".registry.configs.\"mirror.example.com:5000\".auth]\n" +
+					"  username = \"puller\"\n  password = \"s3cr3t\"\n"))
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			out, err := InjectRegistryMirrors([]byte(tc.data), tc.mirrors, tc.credentials)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, string(out))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"fmt"
+	"path"
+)
+
+const (
+	// TrustedCAsPath is the file the additional trusted CA certificates are written to.
+	TrustedCAsPath = "/usr/local/share/ca-certificates/trusted-cas.crt"
+	// ContainerdCertsDir is the directory of the containerd registry host configurations.
+	ContainerdCertsDir = "/etc/containerd/certs.d"
+)
+
+// InjectTrustedCAs adds the given PEM encoded CA certificates to cloud-config bootstrap data. The certificates are
+// installed in the trust store of the OS, and containerd is configured to verify the certificates of the given
+// registries against them. Commands to apply the configuration are prepended to runcmd so that they run before the node
+// joins the cluster. Data which is not a cloud-config document is returned unchanged.
+func InjectTrustedCAs(data []byte, caCerts []byte, registries []string) ([]byte, error) {
+	if len(caCerts) == 0 {
+		return data, nil
+	}
+
+	files := []interface{}{
+		map[string]interface{}{
+			"path":        TrustedCAsPath,
+			"owner":       "root:root",
+			"permissions": "0644",
+			"content":     string(caCerts),
+		},
+	}
+	for _, registry := range registries {
+		files = append(files, map[string]interface{}{
+			"path":        path.Join(ContainerdCertsDir, registry, "hosts.toml"),
+			"owner":       "root:root",
+			"permissions": "0644",
+			"content":     registryHostsContent(registry),
+		})
+	}
+	commands := []interface{}{"update-ca-certificates", "systemctl restart containerd"}
+
+	return amendCloudConfig(data, files, commands)
+}
+
+// registryHostsContent returns the containerd host configuration of a registry which trusts the CA certificates.
+func registryHostsContent(registry string) string {
+	server := "https://" + registry
+	return fmt.Sprintf("server = %q\n\n[host.%q]\n  ca = %q\n", server, server, TrustedCAsPath)
+}
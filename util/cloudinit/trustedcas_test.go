@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const testCACerts = "-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----\n-----BEGIN CERTIFICATE-----\ndef\n-----END CERTIFICATE-----\n"
+
+func TestInjectTrustedCAs(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		caCerts    string
+		registries []string
+		expect     func(g *WithT, out string)
+	}{
+		{
+			name:    "no CA certificates leaves data unchanged",
+			data:    kubeadmCloudConfig,
+			caCerts: "",
+			expect: func(g *WithT, out string) {
+				g.Expect(out).To(Equal(kubeadmCloudConfig))
+			},
+		},
+		{
+			name:    "non cloud-config data is left unchanged",
+			data:    "#!/bin/bash\necho hello\n",
+			caCerts: testCACerts,
+			expect: func(g *WithT, out string) {
+				g.Expect(out).To(Equal("#!/bin/bash\necho hello\n"))
+			},
+		},
+		{
+			name:    "CA certificates are installed before existing commands",
+			data:    kubeadmCloudConfig,
+			caCerts: testCACerts,
+			expect: func(g *WithT, out string) {
+				g.Expect(out).To(HavePrefix("## template: jinja\n#cloud-config\n"))
+				config := parse(g, out)
+
+				files := config["write_files"].([]interface{})
+				g.Expect(paths(files)).To(Equal([]string{
+					"/etc/kubernetes/azure.json",
+					TrustedCAsPath,
+				}))
+				g.Expect(files[1].(map[string]interface{})["content"]).To(Equal(testCACerts))
+
+				g.Expect(config["runcmd"]).To(Equal([]interface{}{
+					"update-ca-certificates",
+					"systemctl restart containerd",
+					"kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml",
+				}))
+			},
+		},
+		{
+			name:       "containerd trusts the CA certificates for the registries",
+			data:       "#cloud-config\n",
+			caCerts:    testCACerts,
+			registries: []string{"registry.example.com", "registry.example.com:5000"},
+			expect: func(g *WithT, out string) {
+				config := parse(g, out)
+
+				files := config["write_files"].([]interface{})
+				g.Expect(paths(files)).To(Equal([]string{
+					TrustedCAsPath,
+					"/etc/containerd/certs.d/registry.example.com/hosts.toml",
+					"/etc/containerd/certs.d/registry.example.com:5000/hosts.toml",
+				}))
+				g.Expect(files[2].(map[string]interface{})["content"]).To(Equal("server = \"https://registry.example.com:5000\"\n\n" +
+					"[host.\"https://registry.example.com:5000\"]\n  ca = \"/usr/local/share/ca-certificates/trusted-cas.crt\"\n"))
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			out, err := InjectTrustedCAs([]byte(tc.data), []byte(tc.caCerts), tc.registries)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, string(out))
+		})
+	}
+}
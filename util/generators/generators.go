@@ -18,12 +18,48 @@ package generators
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
 	"math/big"
+
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
 )
 
+// passwordSeed is the seed of the passwords generated in deterministic mode.
+const passwordSeed = "admin-password"
+
+// deterministic is true when the generators return values derived from their seed instead of random values.
+var deterministic bool
+
+// SetDeterministic makes the generators return values derived from their seed instead of random values, so that the
+// names of the resources and the payloads sent to Azure are reproducible, e.g. to record and replay tests or to compare
+// payloads to golden files. It must not be enabled for production clusters, since the generated passwords become
+// predictable. It is meant to be called once at startup, before any value is generated.
+func SetDeterministic(enabled bool) {
+	deterministic = enabled
+}
+
+// Deterministic returns true if the generators return values derived from their seed.
+func Deterministic() bool {
+	return deterministic
+}
+
+// RandomString returns a random string of n characters which can be used in resource names. In deterministic mode,
+// the same seed always returns the same string.
+func RandomString(n int, seed string) string {
+	if !deterministic {
+		return utilrand.String(n)
+	}
+	// same alphabet as utilrand.String, which avoids vowels and confusing characters
+	return seededString(n, seed, "bcdfghjklmnpqrstvwxz2456789")
+}
+
 // SudoRandomPassword returns a sudo random password. It will be discarded in Windows at provisioning time and replaced.
 func SudoRandomPassword(size int) string {
 	const pwCharSet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789!@#$%^&*()[]"
+	if deterministic {
+		return seededString(size, passwordSeed, pwCharSet)
+	}
 	result := make([]byte, size)
 	for i := range result {
 		num, _ := rand.Int(rand.Reader, big.NewInt(int64(len(pwCharSet))))
@@ -32,3 +68,18 @@ func SudoRandomPassword(size int) string {
 
 	return string(result)
 }
+
+// seededString returns a string of n characters of the charset derived from the SHA-256 hashes of the seed.
+func seededString(n int, seed, charset string) string {
+	result := make([]byte, 0, n)
+	for block := 0; len(result) < n; block++ {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", seed, block)))
+		for _, b := range sum {
+			if len(result) == n {
+				break
+			}
+			result = append(result, charset[int(b)%len(charset)])
+		}
+	}
+	return string(result)
+}
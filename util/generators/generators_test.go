@@ -0,0 +1,41 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDeterministic(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(RandomString(5, "seed")).To(HaveLen(5))
+	g.Expect(SudoRandomPassword(123)).NotTo(Equal(SudoRandomPassword(123)))
+
+	SetDeterministic(true)
+	defer SetDeterministic(false)
+
+	g.Expect(RandomString(5, "seed")).To(HaveLen(5))
+	g.Expect(RandomString(5, "seed")).To(Equal(RandomString(5, "seed")))
+	g.Expect(RandomString(5, "seed")).NotTo(Equal(RandomString(5, "other-seed")))
+	// longer strings span several hashes of the seed
+	g.Expect(RandomString(40, "seed")).To(HavePrefix(RandomString(5, "seed")))
+	g.Expect(SudoRandomPassword(123)).To(HaveLen(123))
+	g.Expect(SudoRandomPassword(123)).To(Equal(SudoRandomPassword(123)))
+}
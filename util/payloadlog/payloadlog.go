@@ -0,0 +1,273 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package payloadlog logs the requests sent to Azure by the services of the controllers, and their responses, at a
+// verbosity set per service, so that a single service can be debugged without raising the log level of the whole
+// controller manager. Secrets such as custom data, passwords, keys and tokens are redacted from the logged payloads.
+package payloadlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// LevelOff disables the payload logging of a service.
+	LevelOff = 0
+	// LevelRequests logs the method, URL, status code and duration of the requests of a service.
+	LevelRequests = 1
+	// LevelPayloads also logs the redacted bodies of the requests of a service and of their responses.
+	LevelPayloads = 2
+
+	// redacted replaces the values of sensitive fields in logged payloads.
+	redacted = "REDACTED"
+)
+
+// sensitiveFields are the lower-cased names of the JSON fields whose values are redacted from logged payloads.
+var sensitiveFields = map[string]bool{
+	"accesstoken":                   true,
+	"adminpassword":                 true,
+	"clientsecret":                  true,
+	"connectionstring":              true,
+	"customdata":                    true,
+	"keydata":                       true,
+	"password":                      true,
+	"primarykey":                    true,
+	"privatekey":                    true,
+	"protectedsettings":             true,
+	"protectedsettingsfromkeyvault": true,
+	"secondarykey":                  true,
+	"secret":                        true,
+	"sharedkey":                     true,
+	"token":                         true,
+	"userdata":                      true,
+}
+
+// sensitiveElementFields are the lower-cased names of the JSON fields whose values are redacted from the elements of
+// the lists of the given lower-cased names only, since they are too generic to be redacted everywhere, e.g. the value
+// of the kubeconfigs returned by the credential requests of AKS clusters.
+var sensitiveElementFields = map[string]map[string]bool{
+	"kubeconfigs": {"value": true},
+}
+
+var (
+	mu     sync.RWMutex
+	levels = map[string]int{}
+)
+
+// SetLevel sets the payload logging verbosity of a service, e.g. "natgateways". LevelOff disables it.
+func SetLevel(service string, level int) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if level <= LevelOff {
+		delete(levels, service)
+		return
+	}
+	levels[service] = level
+}
+
+// Level returns the payload logging verbosity of a service.
+func Level(service string) int {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return levels[service]
+}
+
+// Levels returns the payload logging verbosity of the services it is enabled for.
+func Levels() map[string]int {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make(map[string]int, len(levels))
+	for service, level := range levels {
+		result[service] = level
+	}
+	return result
+}
+
+// ParseLevels parses verbosities of the form "service=level", e.g. "natgateways=2".
+func ParseLevels(specs []string) (map[string]int, error) {
+	result := make(map[string]int, len(specs))
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("invalid payload log level %q, expected service=level", spec)
+		}
+		service, value := parts[0], parts[1]
+		level, err := strconv.Atoi(value)
+		if err != nil || level < LevelOff {
+			return nil, errors.Errorf("invalid payload log level %q, expected a non-negative level", spec)
+		}
+		result[service] = level
+	}
+	return result, nil
+}
+
+// SendDecorator returns an autorest.SendDecorator logging the requests of a service according to its verbosity. The
+// verbosity is read for every request, so it can be changed at runtime.
+func SendDecorator(service string) autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			level := Level(service)
+			if level <= LevelOff {
+				return s.Do(r)
+			}
+
+			logger := log.FromContext(r.Context()).WithName("payloadlog").WithValues("service", service, "method", r.Method, "url", r.URL.String())
+			if corrID, ok := tele.CorrIDFromCtx(r.Context()); ok {
+				logger = logger.WithValues(string(tele.CorrIDKeyVal), string(corrID))
+			}
+			if level >= LevelPayloads && r.Body != nil {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to read request body")
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				logger.Info("sending request to Azure", "body", redact(body))
+			}
+
+			start := time.Now()
+			resp, err := s.Do(r)
+			keysAndValues := []interface{}{"duration", time.Since(start).String()}
+			if resp != nil {
+				keysAndValues = append(keysAndValues, "status", resp.StatusCode)
+				if level >= LevelPayloads && resp.Body != nil {
+					body, readErr := io.ReadAll(resp.Body)
+					if readErr != nil {
+						return resp, errors.Wrap(readErr, "failed to read response body")
+					}
+					resp.Body = io.NopCloser(bytes.NewReader(body))
+					keysAndValues = append(keysAndValues, "body", redact(body))
+				}
+			}
+			if err != nil {
+				logger.Error(err, "request to Azure failed", keysAndValues...)
+				return resp, err
+			}
+			logger.Info("received response from Azure", keysAndValues...)
+			return resp, nil
+		})
+	}
+}
+
+// redact returns a payload with the values of its sensitive fields redacted. Payloads which aren't JSON are replaced by
+// their size, as their content can't be inspected.
+func redact(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return strconv.Itoa(len(body)) + " bytes"
+	}
+	out, err := json.Marshal(redactValue(payload))
+	if err != nil {
+		return strconv.Itoa(len(body)) + " bytes"
+	}
+	return string(out)
+}
+
+// redactValue redacts the sensitive fields of a decoded JSON value, recursively.
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			if fields, ok := sensitiveElementFields[strings.ToLower(key)]; ok {
+				redactElements(field, fields)
+			}
+			v[key] = redactValue(field)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// redactElements redacts the given fields of the objects in a decoded JSON list.
+func redactElements(value interface{}, fields map[string]bool) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return
+	}
+	for _, element := range list {
+		object, ok := element.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range object {
+			if fields[strings.ToLower(key)] {
+				object[key] = redacted
+			}
+		}
+	}
+}
+
+// Handler returns an http.Handler to read and change the payload logging verbosity of the services at runtime. GET
+// returns the verbosity of the services it is enabled for, and PUT sets the verbosity of the service in the service
+// query parameter to the level query parameter, e.g. PUT /?service=natgateways&level=2.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			service := r.URL.Query().Get("service")
+			if service == "" {
+				http.Error(w, "missing service query parameter", http.StatusBadRequest)
+				return
+			}
+			parsed, err := ParseLevels([]string{service + "=" + r.URL.Query().Get("level")})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			SetLevel(service, parsed[service])
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeLevels(w)
+	})
+}
+
+// writeLevels writes the payload logging verbosity of the services as a JSON object.
+func writeLevels(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(Levels())
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payloadlog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+)
+
+func TestParseLevels(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		want    map[string]int
+		wantErr bool
+	}{
+		{
+			name:  "no levels",
+			specs: nil,
+			want:  map[string]int{},
+		},
+		{
+			name:  "levels of several services",
+			specs: []string{"natgateways=2", "virtualmachines=1"},
+			want:  map[string]int{"natgateways": 2, "virtualmachines": 1},
+		},
+		{
+			name:    "missing level",
+			specs:   []string{"natgateways"},
+			wantErr: true,
+		},
+		{
+			name:    "missing service",
+			specs:   []string{"=2"},
+			wantErr: true,
+		},
+		{
+			name:    "negative level",
+			specs:   []string{"natgateways=-1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := ParseLevels(tt.specs)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "empty body",
+			body: "",
+			want: "",
+		},
+		{
+			name: "sensitive fields are redacted at any depth",
+			body: `{"name":"my-vm","properties":{"osProfile":{"adminPassword":"hunter2","customData":"I2Nsb3VkLWNvbmZpZw=="},"extensions":[{"protectedSettings":{"commandToExecute":"secret"}}]}}`,
+			want: `{"name":"my-vm","properties":{"extensions":[{"protectedSettings":"REDACTED"}],"osProfile":{"adminPassword":"REDACTED","customData":"REDACTED"}}}`,
+		},
+		{
+			name: "field names are matched case-insensitively",
+			body: `{"UserData":"c2VjcmV0"}`,
+			want: `{"UserData":"REDACTED"}`,
+		},
+		{
+			name: "tokens are redacted",
+			body: `{"accessToken":"eyJ0eXAi","properties":{"token":"c2VjcmV0","tokenType":"Bearer"}}`,
+			want: `{"accessToken":"REDACTED","properties":{"token":"REDACTED","tokenType":"Bearer"}}`,
+		},
+		{
+			name: "kubeconfigs of a credential list are redacted",
+			body: `{"kubeconfigs":[{"name":"clusterAdmin","value":"YXBpVmVyc2lvbjogdjE="},{"name":"clusterUser","value":"YXBpVmVyc2lvbjogdjE="}]}`,
+			want: `{"kubeconfigs":[{"name":"clusterAdmin","value":"REDACTED"},{"name":"clusterUser","value":"REDACTED"}]}`,
+		},
+		{
+			name: "values of other lists are kept",
+			body: `{"value":[{"name":"my-natgw"}]}`,
+			want: `{"value":[{"name":"my-natgw"}]}`,
+		},
+		{
+			name: "payloads which aren't JSON are replaced by their size",
+			body: "password=hunter2",
+			want: "16 bytes",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(redact([]byte(tt.body))).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestSendDecorator(t *testing.T) {
+	g := NewWithT(t)
+	defer SetLevel("natgateways", LevelOff)
+
+	var sentBody string
+	sender := autorest.DecorateSender(autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		sentBody = string(body)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"name":"my-natgw"}`))}, nil
+	}), SendDecorator("natgateways"))
+
+	for _, level := range []int{LevelOff, LevelPayloads} {
+		SetLevel("natgateways", level)
+		req := httptest.NewRequest(http.MethodPut, "https://management.azure.com/natGateways/my-natgw", strings.NewReader(`{"location":"eastus"}`))
+		resp, err := sender.Do(req)
+		g.Expect(err).NotTo(HaveOccurred())
+		// the bodies are still readable after being logged
+		g.Expect(sentBody).To(Equal(`{"location":"eastus"}`))
+		body, err := io.ReadAll(resp.Body)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(string(body)).To(Equal(`{"name":"my-natgw"}`))
+	}
+}
+
+func TestHandler(t *testing.T) {
+	g := NewWithT(t)
+	defer SetLevel("natgateways", LevelOff)
+	handler := Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/?service=natgateways&level=2", nil))
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(Level("natgateways")).To(Equal(LevelPayloads))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(rec.Body.String()).To(Equal("{\"natgateways\":2}\n"))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/?service=natgateways&level=0", nil))
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(Level("natgateways")).To(Equal(LevelOff))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/?level=2", nil))
+	g.Expect(rec.Code).To(Equal(http.StatusBadRequest))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+	g.Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// resyncJitterFactor spreads the resyncs of the objects reconciled at the same time, e.g. after a restart of the manager.
+const resyncJitterFactor = 0.1
+
+// ResyncPeriods are the intervals after which a successfully reconciled object is reconciled again, depending on its
+// phase. A zero interval leaves the object to the sync period of the manager.
+type ResyncPeriods struct {
+	// Provisioning is the interval for objects which are still being provisioned.
+	Provisioning time.Duration
+	// Degraded is the interval for objects with a false condition of warning or error severity.
+	Degraded time.Duration
+	// Steady is the interval for provisioned, healthy objects.
+	Steady time.Duration
+}
+
+// RequeueAfter returns how long to wait before reconciling obj again, with some jitter, or zero to not requeue it.
+// Degraded objects take precedence over provisioning ones.
+func (p ResyncPeriods) RequeueAfter(obj conditions.Getter, provisioning bool) time.Duration {
+	var period time.Duration
+	switch {
+	case isDegraded(obj):
+		period = p.Degraded
+	case provisioning:
+		period = p.Provisioning
+	default:
+		period = p.Steady
+	}
+	if period <= 0 {
+		return 0
+	}
+	return wait.Jitter(period, resyncJitterFactor)
+}
+
+// isDegraded returns true if obj has a false condition of warning or error severity.
+func isDegraded(obj conditions.Getter) bool {
+	for _, condition := range obj.GetConditions() {
+		if condition.Status == corev1.ConditionFalse && (condition.Severity == clusterv1.ConditionSeverityWarning || condition.Severity == clusterv1.ConditionSeverityError) {
+			return true
+		}
+	}
+	return false
+}
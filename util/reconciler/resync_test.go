@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+)
+
+func TestResyncPeriodsRequeueAfter(t *testing.T) {
+	periods := reconciler.ResyncPeriods{
+		Provisioning: time.Minute,
+		Degraded:     2 * time.Minute,
+		Steady:       30 * time.Minute,
+	}
+	cases := []struct {
+		Name         string
+		Periods      reconciler.ResyncPeriods
+		Conditions   clusterv1.Conditions
+		Provisioning bool
+		Expected     time.Duration
+	}{
+		{
+			Name:     "Steady",
+			Periods:  periods,
+			Expected: 30 * time.Minute,
+		},
+		{
+			Name:         "Provisioning",
+			Periods:      periods,
+			Provisioning: true,
+			Expected:     time.Minute,
+		},
+		{
+			Name:    "DegradedWithWarning",
+			Periods: periods,
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.ReadyCondition, Status: corev1.ConditionFalse, Severity: clusterv1.ConditionSeverityWarning},
+			},
+			Provisioning: true,
+			Expected:     2 * time.Minute,
+		},
+		{
+			Name:    "DegradedWithError",
+			Periods: periods,
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.ReadyCondition, Status: corev1.ConditionFalse, Severity: clusterv1.ConditionSeverityError},
+			},
+			Expected: 2 * time.Minute,
+		},
+		{
+			Name:    "FalseConditionWithInfo",
+			Periods: periods,
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.ReadyCondition, Status: corev1.ConditionFalse, Severity: clusterv1.ConditionSeverityInfo},
+			},
+			Expected: 30 * time.Minute,
+		},
+		{
+			Name:     "Disabled",
+			Periods:  reconciler.ResyncPeriods{Provisioning: time.Minute},
+			Expected: 0,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewWithT(t)
+			cluster := &clusterv1.Cluster{Status: clusterv1.ClusterStatus{Conditions: c.Conditions}}
+			requeueAfter := c.Periods.RequeueAfter(cluster, c.Provisioning)
+			g.Expect(requeueAfter).To(gomega.BeNumerically(">=", c.Expected))
+			g.Expect(requeueAfter).To(gomega.BeNumerically("<=", c.Expected+c.Expected/10))
+		})
+	}
+}